@@ -0,0 +1,99 @@
+package bolt12
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ltcsuite/ltcd/ltcutil/bech32"
+)
+
+const (
+	// charset is the bech32 character set.
+	charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// maxEncodedLength is the maximum length of an encoded offer, invoice
+	// request or invoice that we'll attempt to decode. This is done as an
+	// anti-DoS measure.
+	maxEncodedLength = 65535
+)
+
+var (
+	// ErrEncodingTooLarge is returned when an encoded string exceeds the
+	// maximum length that we'll decode.
+	ErrEncodingTooLarge = errors.New("encoded string too large")
+)
+
+// encodeBech32 encodes the given data with the given human-readable part.
+// Unlike BIP 173, the encoding of BOLT 12 strings has no checksum, as they are
+// signed or meant to be scanned rather than typed.
+func encodeBech32(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(converted))
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, c := range converted {
+		b.WriteByte(charset[c])
+	}
+
+	return b.String(), nil
+}
+
+// decodeBech32 decodes a BOLT 12 string into its human-readable part and
+// data. Strings may be split into several parts that are joined by a '+'
+// followed by optional whitespace.
+func decodeBech32(s string) (string, []byte, error) {
+	if len(s) > maxEncodedLength {
+		return "", nil, ErrEncodingTooLarge
+	}
+
+	// Join the parts of the string, making sure that a '+' is always
+	// surrounded by bech32 characters.
+	parts := strings.Split(s, "+")
+	for i, part := range parts {
+		if i > 0 {
+			part = strings.TrimLeftFunc(part, unicode.IsSpace)
+		}
+
+		if part == "" {
+			return "", nil, errors.New("invalid use of '+'")
+		}
+		parts[i] = part
+	}
+	s = strings.Join(parts, "")
+
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return "", nil, errors.New("string uses mixed case")
+	}
+	s = lower
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+1 == len(s) {
+		return "", nil, errors.New("invalid separator position")
+	}
+
+	hrp := s[:sep]
+	data := make([]byte, 0, len(s)-sep-1)
+	for _, c := range s[sep+1:] {
+		idx := strings.IndexRune(charset, c)
+		if idx == -1 {
+			return "", nil, fmt.Errorf("invalid character %q", c)
+		}
+
+		data = append(data, byte(idx))
+	}
+
+	converted, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, converted, nil
+}
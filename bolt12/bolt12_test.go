@@ -0,0 +1,262 @@
+package bolt12
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/zpay32"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+func newPrivKey(t *testing.T) *btcec.PrivateKey {
+	t.Helper()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return privKey
+}
+
+func newBlindedPath(t *testing.T) *sphinx.BlindedPath {
+	t.Helper()
+
+	return &sphinx.BlindedPath{
+		IntroductionPoint: newPrivKey(t).PubKey(),
+		BlindingPoint:     newPrivKey(t).PubKey(),
+		BlindedHops: []*sphinx.BlindedHopInfo{
+			{
+				BlindedNodePub: newPrivKey(t).PubKey(),
+				CipherText:     []byte{1, 2, 3},
+			},
+		},
+	}
+}
+
+func newTestOffer(t *testing.T, issuer *btcec.PrivateKey) *Offer {
+	t.Helper()
+
+	quantityMax := uint64(5)
+	chain := *chaincfg.MainNetParams.GenesisHash
+
+	return &Offer{
+		Chains:         []chainhash.Hash{chain},
+		Metadata:       []byte{1, 2, 3, 4},
+		Amount:         1000,
+		Description:    "coffee",
+		AbsoluteExpiry: time.Unix(2000000000, 0),
+		Paths:          []*sphinx.BlindedPath{newBlindedPath(t)},
+		Issuer:         "coffee shop",
+		QuantityMax:    &quantityMax,
+		IssuerID:       issuer.PubKey(),
+		ExtraRecords:   map[uint64][]byte{1000000001: {9}},
+	}
+}
+
+// TestMerkleRoot tests the merkle root computation against the test vector of
+// the specification.
+func TestMerkleRoot(t *testing.T) {
+	t.Parallel()
+
+	root, err := merkleRoot(recordSet{1: {0x03, 0xe8}})
+	require.NoError(t, err)
+	require.Equal(t,
+		"b013756c8fee86503a0b4abdab4cddeb1af5d344ca6fc2fa8b6c08938c"+
+			"aa6f93", hex.EncodeToString(root),
+	)
+}
+
+// TestOfferEncoding tests that offers round trip through their encoding, and
+// that the encoding may be split into several parts.
+func TestOfferEncoding(t *testing.T) {
+	t.Parallel()
+
+	offer := newTestOffer(t, newPrivKey(t))
+
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "lno1"))
+
+	decoded, err := DecodeOffer(encoded)
+	require.NoError(t, err)
+	require.Equal(t, offer, decoded)
+	require.True(t, decoded.SupportsChain(
+		*chaincfg.MainNetParams.GenesisHash,
+	))
+	require.False(t, decoded.SupportsChain(bitcoinGenesisHash))
+
+	split := encoded[:20] + "+\n  " + encoded[20:]
+	decoded, err = DecodeOffer(strings.ToUpper(split))
+	require.NoError(t, err)
+	require.Equal(t, offer, decoded)
+
+	_, err = DecodeOffer(encoded + "+")
+	require.Error(t, err)
+
+	// Offers with an amount must have a description, and must say how
+	// to reach the issuer.
+	encoded, err = (&Offer{Amount: 1}).Encode()
+	require.NoError(t, err)
+	_, err = DecodeOffer(encoded)
+	require.Error(t, err)
+
+	encoded, err = (&Offer{Description: "tip"}).Encode()
+	require.NoError(t, err)
+	_, err = DecodeOffer(encoded)
+	require.Error(t, err)
+
+	// Unknown even records are rejected.
+	offer.ExtraRecords = map[uint64][]byte{1000000002: {9}}
+	encoded, err = offer.Encode()
+	require.NoError(t, err)
+	_, err = DecodeOffer(encoded)
+	require.Error(t, err)
+}
+
+// TestInvoiceRequest tests the signing, encoding and validation of invoice
+// requests.
+func TestInvoiceRequest(t *testing.T) {
+	t.Parallel()
+
+	payerKey := newPrivKey(t)
+	request := &InvoiceRequest{
+		Offer:     newTestOffer(t, newPrivKey(t)),
+		Metadata:  []byte{5, 6, 7},
+		Chain:     chaincfg.MainNetParams.GenesisHash,
+		Quantity:  2,
+		PayerID:   payerKey.PubKey(),
+		PayerNote: "thanks",
+	}
+	require.NoError(t, request.Validate())
+	require.EqualValues(t, 2000, request.AmountToPay())
+
+	_, err := request.Serialize()
+	require.Error(t, err)
+
+	require.NoError(t, request.Sign(PrivKeySigner(payerKey)))
+	require.NoError(t, request.Verify())
+
+	encoded, err := request.Serialize()
+	require.NoError(t, err)
+
+	decoded, err := DeserializeInvoiceRequest(encoded)
+	require.NoError(t, err)
+	require.Equal(t, request, decoded)
+	require.NoError(t, decoded.Verify())
+
+	// Tampering with any field invalidates the signature.
+	decoded.Quantity = 3
+	require.ErrorIs(t, decoded.Verify(), ErrInvalidSignature)
+
+	err = request.Sign(PrivKeySigner(newPrivKey(t)))
+	require.NoError(t, err)
+	require.ErrorIs(t, request.Verify(), ErrInvalidSignature)
+
+	// The quantity and amount must match the offer.
+	request.Quantity = 6
+	require.Error(t, request.Validate())
+
+	request.Quantity = 0
+	require.Error(t, request.Validate())
+
+	request.Quantity = 1
+	request.Amount = 999
+	require.Error(t, request.Validate())
+
+	request.Amount = 1500
+	require.NoError(t, request.Validate())
+	require.EqualValues(t, 1500, request.AmountToPay())
+}
+
+// TestInvoice tests the signing and encoding of invoices, and that they must
+// mirror the invoice request they answer.
+func TestInvoice(t *testing.T) {
+	t.Parallel()
+
+	issuerKey := newPrivKey(t)
+	request := &InvoiceRequest{
+		Offer:    newTestOffer(t, issuerKey),
+		Metadata: []byte{5, 6, 7},
+		Quantity: 1,
+		PayerID:  newPrivKey(t).PubKey(),
+	}
+	require.NoError(t, request.Sign(PrivKeySigner(newPrivKey(t))))
+
+	path := newBlindedPath(t)
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.TLVOnionPayloadOptional),
+		lnwire.Features,
+	)
+	invoice := &Invoice{
+		Request: request,
+		Paths: []*zpay32.BlindedPaymentPath{{
+			FeeBaseMsat:                 1,
+			FeeRate:                     2,
+			CltvExpiryDelta:             3,
+			HTLCMinMsat:                 4,
+			HTLCMaxMsat:                 5,
+			Features:                    features,
+			FirstEphemeralBlindingPoint: path.BlindingPoint,
+			IntroductionNode:            path.IntroductionPoint,
+			Hops:                        path.BlindedHops,
+		}},
+		CreatedAt:      time.Unix(1700000000, 0),
+		RelativeExpiry: time.Hour,
+		PaymentHash:    lntypes.Hash{1},
+		Amount:         request.AmountToPay(),
+		NodeID:         issuerKey.PubKey(),
+		ExtraRecords:   map[uint64][]byte{invoiceFallbacksType: {1}},
+	}
+	require.NoError(t, invoice.Validate())
+	require.NoError(t, invoice.Sign(PrivKeySigner(issuerKey)))
+
+	encoded, err := invoice.Encode()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "lni1"))
+
+	decoded, err := DecodeInvoice(encoded)
+	require.NoError(t, err)
+
+	// The request's signature is not part of the invoice.
+	request.Signature = nil
+	require.Equal(t, invoice, decoded)
+	require.NoError(t, decoded.Verify())
+	require.NoError(t, decoded.MatchesRequest(request))
+
+	require.False(t, decoded.IsExpired(invoice.CreatedAt))
+	require.True(t, decoded.IsExpired(invoice.CreatedAt.Add(2*time.Hour)))
+
+	other := *request
+	other.PayerNote = "hi"
+	require.Error(t, decoded.MatchesRequest(&other))
+
+	decoded.Amount++
+	require.ErrorIs(t, decoded.Verify(), ErrInvalidSignature)
+}
+
+// TestInvoiceError tests that invoice errors round trip through their
+// encoding.
+func TestInvoiceError(t *testing.T) {
+	t.Parallel()
+
+	field := uint64(82)
+	invoiceError := &InvoiceError{
+		ErroneousField: &field,
+		SuggestedValue: []byte{1},
+		Message:        "amount too low",
+	}
+
+	encoded, err := invoiceError.Serialize()
+	require.NoError(t, err)
+
+	decoded, err := DeserializeInvoiceError(encoded)
+	require.NoError(t, err)
+	require.Equal(t, invoiceError, decoded)
+}
@@ -0,0 +1,506 @@
+package bolt12
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/onionmessage"
+	"github.com/ltcsuite/lnd/zpay32"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
+)
+
+const (
+	// invoiceHRP is the human-readable part of an encoded invoice.
+	invoiceHRP = "lni"
+
+	// invoiceMessageName is the name of invoices that is used for their
+	// signatures.
+	invoiceMessageName = "invoice"
+
+	// DefaultRelativeExpiry is the time after its creation that an
+	// invoice expires if it doesn't specify its expiry.
+	DefaultRelativeExpiry = 2 * time.Hour
+
+	invoicePathsType          = 160
+	invoiceBlindedPayType     = 162
+	invoiceCreatedAtType      = 164
+	invoiceRelativeExpiryType = 166
+	invoicePaymentHashType    = 168
+	invoiceAmountType         = 170
+	invoiceFallbacksType      = 172
+	invoiceFeaturesType       = 174
+	invoiceNodeIDType         = 176
+)
+
+var (
+	// ErrInvoiceExpired is returned when an invoice is used after its
+	// expiry.
+	ErrInvoiceExpired = errors.New("invoice expired")
+)
+
+// Invoice is an invoice that the issuer of an offer sends in response to an
+// invoice request.
+type Invoice struct {
+	// Request is the invoice request that the invoice is for, as mirrored
+	// in the invoice. The request's signature is not part of the invoice.
+	Request *InvoiceRequest
+
+	// Paths is the list of blinded paths that the invoice may be paid
+	// over, along with their payment parameters.
+	Paths []*zpay32.BlindedPaymentPath
+
+	// CreatedAt is the time at which the invoice was created.
+	CreatedAt time.Time
+
+	// RelativeExpiry is the time after its creation that the invoice
+	// expires. If it is zero, DefaultRelativeExpiry applies.
+	RelativeExpiry time.Duration
+
+	// PaymentHash is the hash of the preimage that the payment releases.
+	PaymentHash lntypes.Hash
+
+	// Amount is the amount to pay.
+	Amount lnwire.MilliSatoshi
+
+	// Features is the set of features that the recipient supports for the
+	// payment.
+	Features *lnwire.RawFeatureVector
+
+	// NodeID is the public key that signs the invoice.
+	NodeID *btcec.PublicKey
+
+	// ExtraRecords holds the invoice's records of types that we don't
+	// understand.
+	ExtraRecords map[uint64][]byte
+
+	// Signature is the signature of the invoice by the NodeID.
+	Signature *schnorr.Signature
+}
+
+// Sign signs the invoice with the given signer, which must hold the private
+// key of the NodeID.
+func (i *Invoice) Sign(signer MessageSigner) error {
+	records, err := i.records()
+	if err != nil {
+		return err
+	}
+
+	i.Signature, err = sign(invoiceMessageName, records, signer)
+
+	return err
+}
+
+// Verify checks that the invoice is signed by its NodeID.
+func (i *Invoice) Verify() error {
+	if i.Signature == nil {
+		return errors.New("invoice is not signed")
+	}
+
+	records, err := i.records()
+	if err != nil {
+		return err
+	}
+
+	return verify(invoiceMessageName, records, i.Signature, i.NodeID)
+}
+
+// Validate checks that the invoice is well formed.
+func (i *Invoice) Validate() error {
+	switch {
+	case len(i.Paths) == 0:
+		return errors.New("invoice has no blinded paths")
+
+	case i.CreatedAt.IsZero():
+		return errors.New("invoice has no creation time")
+
+	case i.PaymentHash == lntypes.ZeroHash:
+		return errors.New("invoice has no payment hash")
+
+	case i.Amount == 0:
+		return errors.New("invoice has no amount")
+
+	case i.NodeID == nil:
+		return errors.New("invoice has no node id")
+	}
+
+	return nil
+}
+
+// Expiry returns the time after its creation that the invoice expires.
+func (i *Invoice) Expiry() time.Duration {
+	if i.RelativeExpiry == 0 {
+		return DefaultRelativeExpiry
+	}
+
+	return i.RelativeExpiry
+}
+
+// IsExpired returns true if the invoice has expired at the given time.
+func (i *Invoice) IsExpired(now time.Time) bool {
+	return now.After(i.CreatedAt.Add(i.Expiry()))
+}
+
+// MatchesRequest checks that the invoice mirrors the given invoice request.
+func (i *Invoice) MatchesRequest(request *InvoiceRequest) error {
+	invoiceRecords, err := i.Request.records()
+	if err != nil {
+		return err
+	}
+
+	requestRecords, err := request.records()
+	if err != nil {
+		return err
+	}
+
+	if !invoiceRecords.equal(requestRecords) {
+		return errors.New("invoice doesn't match invoice request")
+	}
+
+	return nil
+}
+
+// Serialize serializes the signed invoice as a TLV stream.
+func (i *Invoice) Serialize() ([]byte, error) {
+	if i.Signature == nil {
+		return nil, errors.New("invoice is not signed")
+	}
+
+	records, err := i.records()
+	if err != nil {
+		return nil, err
+	}
+	records[signatureType] = i.Signature.Serialize()
+
+	return records.encode()
+}
+
+// Encode returns the signed invoice as a bech32 string.
+func (i *Invoice) Encode() (string, error) {
+	data, err := i.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	return encodeBech32(invoiceHRP, data)
+}
+
+// DeserializeInvoice parses a signed invoice from its TLV stream. The invoice
+// is neither validated nor its signature verified.
+func DeserializeInvoice(data []byte) (*Invoice, error) {
+	records, err := decodeRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := parseSignature(records.split([]typeRange{signatureRange}))
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := invoiceFromRecords(records)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Signature = sig
+
+	return invoice, nil
+}
+
+// DecodeInvoice parses a signed invoice from its bech32 string. The invoice
+// is neither validated nor its signature verified.
+func DecodeInvoice(s string) (*Invoice, error) {
+	hrp, data, err := decodeBech32(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if hrp != invoiceHRP {
+		return nil, fmt.Errorf("invalid invoice prefix %q", hrp)
+	}
+
+	return DeserializeInvoice(data)
+}
+
+// records returns the TLV records of the invoice, without its signature.
+func (i *Invoice) records() (recordSet, error) {
+	records, err := i.Request.records()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, payInfo, err := encodePaymentPaths(i.Paths)
+	if err != nil {
+		return nil, err
+	}
+	records[invoicePathsType] = paths
+	records[invoiceBlindedPayType] = payInfo
+
+	records[invoiceCreatedAtType] = encodeTU64(uint64(i.CreatedAt.Unix()))
+
+	if i.RelativeExpiry != 0 {
+		records[invoiceRelativeExpiryType] = encodeTU64(
+			uint64(i.RelativeExpiry / time.Second),
+		)
+	}
+
+	records[invoicePaymentHashType] = i.PaymentHash[:]
+	records[invoiceAmountType] = encodeTU64(uint64(i.Amount))
+
+	if i.Features != nil && i.Features.SerializeSize() > 0 {
+		records[invoiceFeaturesType] = encodeFeatures(i.Features)
+	}
+
+	if i.NodeID != nil {
+		records[invoiceNodeIDType] = i.NodeID.SerializeCompressed()
+	}
+
+	if err := records.addExtra(i.ExtraRecords, invoiceRanges); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// invoiceFromRecords parses an invoice from its TLV records, without its
+// signature.
+func invoiceFromRecords(records recordSet) (*Invoice, error) {
+	var (
+		invoice  Invoice
+		original = records.copy()
+		err      error
+	)
+
+	invoiceRecords := records.split(invoiceRanges)
+	invoice.Request, err = invoiceRequestFromRecords(records)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		paths   []byte
+		payInfo []byte
+	)
+	for typ, value := range invoiceRecords {
+		switch typ {
+		case invoicePathsType:
+			paths = value
+
+		case invoiceBlindedPayType:
+			payInfo = value
+
+		case invoiceCreatedAtType:
+			var createdAt uint64
+			createdAt, err = decodeTU64(value)
+			if createdAt > math.MaxInt64 {
+				err = errors.New("invalid creation time")
+			}
+			invoice.CreatedAt = time.Unix(int64(createdAt), 0)
+
+		case invoiceRelativeExpiryType:
+			var expiry uint64
+			expiry, err = decodeTU64(value)
+			if expiry > math.MaxUint32 {
+				err = errors.New("invalid relative expiry")
+			}
+			invoice.RelativeExpiry = time.Duration(expiry) *
+				time.Second
+
+		case invoicePaymentHashType:
+			invoice.PaymentHash, err = lntypes.MakeHash(value)
+
+		case invoiceAmountType:
+			var amt uint64
+			amt, err = decodeTU64(value)
+			invoice.Amount = lnwire.MilliSatoshi(amt)
+
+		case invoiceFeaturesType:
+			invoice.Features, err = decodeFeatures(value)
+
+		case invoiceNodeIDType:
+			invoice.NodeID, err = decodePubKey(value)
+
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice record %d: %w",
+				typ, err)
+		}
+
+		delete(invoiceRecords, typ)
+	}
+
+	invoice.Paths, err = decodePaymentPaths(paths, payInfo)
+	if err != nil {
+		return nil, err
+	}
+	delete(invoiceRecords, invoicePathsType)
+	delete(invoiceRecords, invoiceBlindedPayType)
+
+	// We don't pay to fallback addresses, but keep them with the unknown
+	// records so that the signature can be verified.
+	fallbacks, hasFallbacks := invoiceRecords[invoiceFallbacksType]
+	delete(invoiceRecords, invoiceFallbacksType)
+
+	invoice.ExtraRecords, err = invoiceRecords.unknown()
+	if err != nil {
+		return nil, err
+	}
+
+	if hasFallbacks {
+		if invoice.ExtraRecords == nil {
+			invoice.ExtraRecords = make(map[uint64][]byte)
+		}
+		invoice.ExtraRecords[invoiceFallbacksType] = fallbacks
+	}
+
+	encoded, err := invoice.records()
+	if err != nil {
+		return nil, err
+	}
+
+	if !encoded.equal(original) {
+		return nil, errors.New("invoice is not canonically encoded")
+	}
+
+	return &invoice, nil
+}
+
+// encodePaymentPaths serializes the blinded paths of an invoice and their
+// payment parameters.
+func encodePaymentPaths(paths []*zpay32.BlindedPaymentPath) ([]byte, []byte,
+	error) {
+
+	if len(paths) == 0 {
+		return nil, nil, errors.New("invoice has no blinded paths")
+	}
+
+	var (
+		pathsBuf   bytes.Buffer
+		payInfoBuf bytes.Buffer
+	)
+	for _, path := range paths {
+		err := onionmessage.WriteBlindedPath(
+			&pathsBuf, path.SphinxPath(),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var features []byte
+		if path.Features != nil {
+			features = encodeFeatures(path.Features.RawFeatureVector)
+		}
+
+		if len(features) > math.MaxUint16 {
+			return nil, nil, errors.New("blinded path features " +
+				"too large")
+		}
+
+		err = writeElements(&payInfoBuf,
+			path.FeeBaseMsat, path.FeeRate, path.CltvExpiryDelta,
+			path.HTLCMinMsat, path.HTLCMaxMsat,
+			uint16(len(features)),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		payInfoBuf.Write(features)
+	}
+
+	return pathsBuf.Bytes(), payInfoBuf.Bytes(), nil
+}
+
+// decodePaymentPaths parses the blinded paths of an invoice and their payment
+// parameters, of which there must be one per path.
+func decodePaymentPaths(paths, payInfo []byte) ([]*zpay32.BlindedPaymentPath,
+	error) {
+
+	if paths == nil || payInfo == nil {
+		return nil, errors.New("invoice has no blinded paths")
+	}
+
+	sphinxPaths, err := decodeBlindedPaths(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(payInfo)
+	paymentPaths := make([]*zpay32.BlindedPaymentPath, len(sphinxPaths))
+	for i, sphinxPath := range sphinxPaths {
+		path := &zpay32.BlindedPaymentPath{
+			IntroductionNode:            sphinxPath.IntroductionPoint,
+			FirstEphemeralBlindingPoint: sphinxPath.BlindingPoint,
+			Hops:                        sphinxPath.BlindedHops,
+		}
+
+		var featuresLen uint16
+		err := readElements(r,
+			&path.FeeBaseMsat, &path.FeeRate, &path.CltvExpiryDelta,
+			&path.HTLCMinMsat, &path.HTLCMaxMsat, &featuresLen,
+		)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid blinded payinfo: %w",
+				err)
+		}
+
+		features := make([]byte, featuresLen)
+		if _, err := io.ReadFull(r, features); err != nil {
+			return nil, fmt.Errorf("invalid blinded payinfo: %w",
+				err)
+		}
+
+		rawFeatures, err := decodeFeatures(features)
+		if err != nil {
+			return nil, err
+		}
+		path.Features = lnwire.NewFeatureVector(
+			rawFeatures, lnwire.Features,
+		)
+
+		paymentPaths[i] = path
+	}
+
+	if r.Len() > 0 {
+		return nil, errors.New("blinded payinfo doesn't match blinded " +
+			"paths")
+	}
+
+	return paymentPaths, nil
+}
+
+// writeElements writes the given fixed-size elements in big endian byte
+// order.
+func writeElements(w io.Writer, elements ...interface{}) error {
+	for _, element := range elements {
+		err := binary.Write(w, binary.BigEndian, element)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readElements reads the given fixed-size elements in big endian byte order.
+func readElements(r io.Reader, elements ...interface{}) error {
+	for _, element := range elements {
+		err := binary.Read(r, binary.BigEndian, element)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package bolt12
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+const (
+	// invoiceRequestMessageName is the name of invoice requests that is
+	// used for their signatures.
+	invoiceRequestMessageName = "invoice_request"
+
+	invreqMetadataType  = 0
+	invreqChainType     = 80
+	invreqAmountType    = 82
+	invreqFeaturesType  = 84
+	invreqQuantityType  = 86
+	invreqPayerIDType   = 88
+	invreqPayerNoteType = 89
+)
+
+// InvoiceRequest is a request for an invoice for an offer, which is sent to
+// the offer's issuer by the payer.
+type InvoiceRequest struct {
+	// Offer is the offer that the invoice is requested for, as mirrored
+	// in the request.
+	Offer *Offer
+
+	// Metadata is opaque data chosen by the payer, which makes every
+	// request unique.
+	Metadata []byte
+
+	// Chain is the chain that the payer wants to pay on. If it is nil,
+	// the payer wants to pay on bitcoin.
+	Chain *chainhash.Hash
+
+	// Amount is the amount that the payer wants to pay. It is required if
+	// the offer has no amount, and may exceed the offer's amount
+	// otherwise. A value of zero means that the amount isn't set.
+	Amount lnwire.MilliSatoshi
+
+	// Features is the set of features that the payer supports for the
+	// request.
+	Features *lnwire.RawFeatureVector
+
+	// Quantity is the number of items that the payer requests, which must
+	// be set if and only if the offer has a QuantityMax. A value of zero
+	// means that the quantity isn't set.
+	Quantity uint64
+
+	// PayerID is the transient public key of the payer, which signs the
+	// request.
+	PayerID *btcec.PublicKey
+
+	// PayerNote is an optional note from the payer to the issuer.
+	PayerNote string
+
+	// ExtraRecords holds the request's records of types that we don't
+	// understand, which must be mirrored in the invoice.
+	ExtraRecords map[uint64][]byte
+
+	// Signature is the signature of the request by the PayerID.
+	Signature *schnorr.Signature
+}
+
+// Sign signs the invoice request with the given signer, which must hold the
+// private key of the PayerID.
+func (r *InvoiceRequest) Sign(signer MessageSigner) error {
+	records, err := r.records()
+	if err != nil {
+		return err
+	}
+
+	r.Signature, err = sign(invoiceRequestMessageName, records, signer)
+
+	return err
+}
+
+// Verify checks that the invoice request is signed by its PayerID.
+func (r *InvoiceRequest) Verify() error {
+	if r.Signature == nil {
+		return errors.New("invoice request is not signed")
+	}
+
+	records, err := r.records()
+	if err != nil {
+		return err
+	}
+
+	return verify(
+		invoiceRequestMessageName, records, r.Signature, r.PayerID,
+	)
+}
+
+// Validate checks that the invoice request is well formed and requests a
+// valid quantity and amount for its offer.
+func (r *InvoiceRequest) Validate() error {
+	if err := r.Offer.Validate(); err != nil {
+		return err
+	}
+
+	switch {
+	case len(r.Metadata) == 0:
+		return errors.New("invoice request has no metadata")
+
+	case r.PayerID == nil:
+		return errors.New("invoice request has no payer id")
+
+	case r.Offer.QuantityMax == nil && r.Quantity != 0:
+		return errors.New("offer doesn't support a quantity")
+
+	case r.Offer.QuantityMax != nil && r.Quantity == 0:
+		return errors.New("offer requires a quantity")
+
+	case r.Offer.QuantityMax != nil && *r.Offer.QuantityMax != 0 &&
+		r.Quantity > *r.Offer.QuantityMax:
+
+		return fmt.Errorf("quantity %d exceeds maximum of %d",
+			r.Quantity, *r.Offer.QuantityMax)
+
+	case r.Offer.Amount == 0 && r.Amount == 0:
+		return errors.New("amount required for offer without amount")
+	}
+
+	if r.Offer.Amount != 0 {
+		quantity := r.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+
+		if uint64(r.Offer.Amount) > math.MaxUint64/quantity {
+			return errors.New("offer amount overflows")
+		}
+
+		minAmt := r.Offer.Amount * lnwire.MilliSatoshi(quantity)
+		if r.Amount != 0 && r.Amount < minAmt {
+			return fmt.Errorf("amount %v is below the offer amount "+
+				"of %v", r.Amount, minAmt)
+		}
+	}
+
+	return nil
+}
+
+// ChainHash returns the chain that the payer wants to pay on.
+func (r *InvoiceRequest) ChainHash() chainhash.Hash {
+	if r.Chain == nil {
+		return bitcoinGenesisHash
+	}
+
+	return *r.Chain
+}
+
+// AmountToPay returns the amount that the invoice for the request must be
+// for. The request must have been validated.
+func (r *InvoiceRequest) AmountToPay() lnwire.MilliSatoshi {
+	if r.Amount != 0 {
+		return r.Amount
+	}
+
+	quantity := r.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	return r.Offer.Amount * lnwire.MilliSatoshi(quantity)
+}
+
+// Serialize serializes the signed invoice request as a TLV stream.
+func (r *InvoiceRequest) Serialize() ([]byte, error) {
+	if r.Signature == nil {
+		return nil, errors.New("invoice request is not signed")
+	}
+
+	records, err := r.records()
+	if err != nil {
+		return nil, err
+	}
+	records[signatureType] = r.Signature.Serialize()
+
+	return records.encode()
+}
+
+// DeserializeInvoiceRequest parses a signed invoice request from its TLV
+// stream. The request is neither validated nor its signature verified.
+func DeserializeInvoiceRequest(data []byte) (*InvoiceRequest, error) {
+	records, err := decodeRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sigRecords := records.split([]typeRange{signatureRange})
+	sig, err := parseSignature(sigRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := invoiceRequestFromRecords(records)
+	if err != nil {
+		return nil, err
+	}
+	request.Signature = sig
+
+	return request, nil
+}
+
+// records returns the TLV records of the invoice request, without its
+// signature.
+func (r *InvoiceRequest) records() (recordSet, error) {
+	records, err := r.Offer.records()
+	if err != nil {
+		return nil, err
+	}
+
+	records[invreqMetadataType] = r.Metadata
+
+	if r.Chain != nil {
+		records[invreqChainType] = r.Chain[:]
+	}
+
+	if r.Amount != 0 {
+		records[invreqAmountType] = encodeTU64(uint64(r.Amount))
+	}
+
+	if r.Features != nil && r.Features.SerializeSize() > 0 {
+		records[invreqFeaturesType] = encodeFeatures(r.Features)
+	}
+
+	if r.Quantity != 0 {
+		records[invreqQuantityType] = encodeTU64(r.Quantity)
+	}
+
+	if r.PayerID != nil {
+		records[invreqPayerIDType] = r.PayerID.SerializeCompressed()
+	}
+
+	if r.PayerNote != "" {
+		records[invreqPayerNoteType] = []byte(r.PayerNote)
+	}
+
+	err = records.addExtra(r.ExtraRecords, invoiceRequestRanges)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// invoiceRequestFromRecords parses an invoice request from its TLV records,
+// without its signature. All records must belong to the request.
+func invoiceRequestFromRecords(records recordSet) (*InvoiceRequest, error) {
+	var (
+		request  InvoiceRequest
+		original = records.copy()
+		err      error
+	)
+
+	request.Offer, err = offerFromRecords(records.split(offerRanges))
+	if err != nil {
+		return nil, err
+	}
+
+	requestRecords := records.split(invoiceRequestRanges)
+	if len(records) > 0 {
+		return nil, errors.New("invoice request contains records of " +
+			"other messages")
+	}
+
+	for typ, value := range requestRecords {
+		switch typ {
+		case invreqMetadataType:
+			request.Metadata = value
+
+		case invreqChainType:
+			if len(value) != chainhash.HashSize {
+				err = errors.New("invalid chain hash")
+				break
+			}
+
+			var chain chainhash.Hash
+			copy(chain[:], value)
+			request.Chain = &chain
+
+		case invreqAmountType:
+			var amt uint64
+			amt, err = decodeTU64(value)
+			request.Amount = lnwire.MilliSatoshi(amt)
+
+		case invreqFeaturesType:
+			request.Features, err = decodeFeatures(value)
+
+		case invreqQuantityType:
+			request.Quantity, err = decodeTU64(value)
+
+		case invreqPayerIDType:
+			request.PayerID, err = decodePubKey(value)
+
+		case invreqPayerNoteType:
+			request.PayerNote, err = decodeString(value)
+
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice request record "+
+				"%d: %w", typ, err)
+		}
+
+		delete(requestRecords, typ)
+	}
+
+	request.ExtraRecords, err = requestRecords.unknown()
+	if err != nil {
+		return nil, err
+	}
+
+	// The records are mirrored into the invoice, so they must survive a
+	// round trip unchanged.
+	encoded, err := request.records()
+	if err != nil {
+		return nil, err
+	}
+
+	if !encoded.equal(original) {
+		return nil, errors.New("invoice request is not canonically " +
+			"encoded")
+	}
+
+	return &request, nil
+}
+
+// parseSignature parses the signature from the signature records of a
+// message.
+func parseSignature(records recordSet) (*schnorr.Signature, error) {
+	value, ok := records[signatureType]
+	if !ok {
+		return nil, errors.New("missing signature")
+	}
+	delete(records, signatureType)
+
+	if _, err := records.unknown(); err != nil {
+		return nil, err
+	}
+
+	return schnorr.ParseSignature(value)
+}
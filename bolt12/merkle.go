@@ -0,0 +1,164 @@
+package bolt12
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+const (
+	// signatureType is the TLV type of the signature of an invoice
+	// request or invoice.
+	signatureType = 240
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of an invoice
+	// request or invoice doesn't match its signing key.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	leafTag   = []byte("LnLeaf")
+	nonceTag  = []byte("LnNonce")
+	branchTag = []byte("LnBranch")
+)
+
+// MessageSigner signs the messages of invoice requests and invoices with a
+// BIP 340 signature. The signer must hash the message with a single SHA256
+// before signing it, as is done by keychain.MessageSignerRing's
+// SignMessageSchnorr.
+type MessageSigner func(msg []byte) (*schnorr.Signature, error)
+
+// PrivKeySigner returns a MessageSigner that signs with the given private
+// key.
+func PrivKeySigner(privKey *btcec.PrivateKey) MessageSigner {
+	return func(msg []byte) (*schnorr.Signature, error) {
+		return schnorr.Sign(privKey, chainhash.HashB(msg))
+	}
+}
+
+// merkleRoot computes the root of the merkle tree of the given records, as
+// specified by BOLT 12. Signature records are not part of the tree.
+func merkleRoot(records recordSet) ([]byte, error) {
+	types := make([]uint64, 0, len(records))
+	for typ := range records {
+		if !signatureRange.contains(typ) {
+			types = append(types, typ)
+		}
+	}
+
+	if len(types) == 0 {
+		return nil, errors.New("no records to sign")
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	var scratch [8]byte
+	encodeRecord := func(typ uint64) ([]byte, []byte, error) {
+		var b bytes.Buffer
+		if err := tlv.WriteVarInt(&b, typ, &scratch); err != nil {
+			return nil, nil, err
+		}
+		typeBytes := append([]byte(nil), b.Bytes()...)
+
+		value := records[typ]
+		err := tlv.WriteVarInt(&b, uint64(len(value)), &scratch)
+		if err != nil {
+			return nil, nil, err
+		}
+		b.Write(value)
+
+		return typeBytes, b.Bytes(), nil
+	}
+
+	// Each record contributes a leaf that commits to it and a nonce leaf
+	// that commits to its type, keyed by the first record so that the
+	// leaves can't be guessed.
+	_, firstRecord, err := encodeRecord(types[0])
+	if err != nil {
+		return nil, err
+	}
+	recordNonceTag := append(append([]byte(nil), nonceTag...),
+		firstRecord...)
+
+	hashes := make([]*chainhash.Hash, len(types))
+	for i, typ := range types {
+		typeBytes, record, err := encodeRecord(typ)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes[i] = branchHash(
+			chainhash.TaggedHash(leafTag, record),
+			chainhash.TaggedHash(recordNonceTag, typeBytes),
+		)
+	}
+
+	// Combine the branches pairwise, so that the tree is deepest at the
+	// records of the lowest types if their number isn't a power of two.
+	for step := 1; step < len(hashes); step *= 2 {
+		for i := 0; i+step < len(hashes); i += 2 * step {
+			hashes[i] = branchHash(hashes[i], hashes[i+step])
+		}
+	}
+
+	return hashes[0][:], nil
+}
+
+// branchHash computes the inner node of the merkle tree from its two children,
+// which are sorted so that proofs don't need to specify their order.
+func branchHash(a, b *chainhash.Hash) *chainhash.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+
+	return chainhash.TaggedHash(branchTag, a[:], b[:])
+}
+
+// signatureMessage returns the message that is signed to sign the merkle root
+// of a message. It is the BIP 340 tagged hash of the root, with its final
+// SHA256 left to the signer.
+func signatureMessage(messageName string, root []byte) []byte {
+	tag := chainhash.HashB([]byte("lightning" + messageName + "signature"))
+
+	msg := make([]byte, 0, 2*len(tag)+len(root))
+	msg = append(msg, tag...)
+	msg = append(msg, tag...)
+
+	return append(msg, root...)
+}
+
+// sign signs the records of a message.
+func sign(messageName string, records recordSet,
+	signer MessageSigner) (*schnorr.Signature, error) {
+
+	root, err := merkleRoot(records)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer(signatureMessage(messageName, root))
+}
+
+// verify checks the signature of the records of a message.
+func verify(messageName string, records recordSet, sig *schnorr.Signature,
+	pubKey *btcec.PublicKey) error {
+
+	root, err := merkleRoot(records)
+	if err != nil {
+		return err
+	}
+
+	digest := chainhash.HashB(signatureMessage(messageName, root))
+	if !sig.Verify(digest, pubKey) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
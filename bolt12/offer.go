@@ -0,0 +1,328 @@
+package bolt12
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+const (
+	// offerHRP is the human-readable part of an encoded offer.
+	offerHRP = "lno"
+
+	offerChainsType         = 2
+	offerMetadataType       = 4
+	offerAmountType         = 8
+	offerDescriptionType    = 10
+	offerFeaturesType       = 12
+	offerAbsoluteExpiryType = 14
+	offerPathsType          = 16
+	offerIssuerType         = 18
+	offerQuantityMaxType    = 20
+	offerIssuerIDType       = 22
+)
+
+var (
+	// bitcoinGenesisHash is the chain that an offer is for if it doesn't
+	// list any chains.
+	bitcoinGenesisHash = chainhash.Hash{
+		0x6f, 0xe2, 0x8c, 0x0a, 0xb6, 0xf1, 0xb3, 0x72,
+		0xc1, 0xa6, 0xa2, 0x46, 0xae, 0x63, 0xf7, 0x4f,
+		0x93, 0x1e, 0x83, 0x65, 0xe1, 0x5a, 0x08, 0x9c,
+		0x68, 0xd6, 0x19, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+
+	// ErrOfferExpired is returned when an offer is used after its expiry.
+	ErrOfferExpired = errors.New("offer expired")
+)
+
+// Offer is a BOLT 12 offer, a reusable payment code that payers use to
+// request invoices from the offer's issuer.
+type Offer struct {
+	// Chains is the list of chains that the offer may be paid on. If it
+	// is empty, the offer is for bitcoin.
+	Chains []chainhash.Hash
+
+	// Metadata is opaque data that the issuer uses to recognize the offer
+	// when it receives an invoice request for it.
+	Metadata []byte
+
+	// Amount is the amount per item that is requested by the offer. If it
+	// is zero, the payer chooses the amount.
+	Amount lnwire.MilliSatoshi
+
+	// Description is a description of the purpose of the payment, which
+	// is required if the offer has an amount.
+	Description string
+
+	// Features is the set of features that the issuer supports for the
+	// offer.
+	Features *lnwire.RawFeatureVector
+
+	// AbsoluteExpiry is the time after which invoice requests for the
+	// offer are no longer accepted. The zero value means that the offer
+	// doesn't expire.
+	AbsoluteExpiry time.Time
+
+	// Paths is a list of blinded paths to the issuer, which invoice
+	// requests are sent over. If it is empty, they're sent to the
+	// IssuerID directly.
+	Paths []*sphinx.BlindedPath
+
+	// Issuer is a human-readable description of the issuer.
+	Issuer string
+
+	// QuantityMax is the maximum number of items that may be requested
+	// at once. A value of zero means that any number of items may be
+	// requested. If it is nil, the offer is for a single item.
+	QuantityMax *uint64
+
+	// IssuerID is the public key of the issuer, which signs the invoices
+	// for the offer unless the offer has blinded paths.
+	IssuerID *btcec.PublicKey
+
+	// ExtraRecords holds the records of types that we don't understand,
+	// which must be mirrored in invoice requests for the offer.
+	ExtraRecords map[uint64][]byte
+}
+
+// Encode returns the offer as a bech32 string, which can be shared with
+// payers.
+func (o *Offer) Encode() (string, error) {
+	records, err := o.records()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := records.encode()
+	if err != nil {
+		return "", err
+	}
+
+	return encodeBech32(offerHRP, data)
+}
+
+// DecodeOffer parses and validates an offer from its bech32 string.
+func DecodeOffer(s string) (*Offer, error) {
+	hrp, data, err := decodeBech32(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if hrp != offerHRP {
+		return nil, fmt.Errorf("invalid offer prefix %q", hrp)
+	}
+
+	records, err := decodeRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	offerRecords := records.split(offerRanges)
+	if len(records) > 0 {
+		return nil, errors.New("offer contains records of other " +
+			"messages")
+	}
+
+	offer, err := offerFromRecords(offerRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := offer.Validate(); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// Validate checks that the offer is well formed.
+func (o *Offer) Validate() error {
+	switch {
+	case o.Amount != 0 && o.Description == "":
+		return errors.New("offer with an amount must have a " +
+			"description")
+
+	case o.IssuerID == nil && len(o.Paths) == 0:
+		return errors.New("offer must have an issuer id or blinded " +
+			"paths")
+	}
+
+	return nil
+}
+
+// SupportsChain returns true if the offer may be paid on the chain with the
+// given genesis hash.
+func (o *Offer) SupportsChain(genesisHash chainhash.Hash) bool {
+	if len(o.Chains) == 0 {
+		return genesisHash == bitcoinGenesisHash
+	}
+
+	for _, chain := range o.Chains {
+		if chain == genesisHash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsExpired returns true if the offer has expired at the given time.
+func (o *Offer) IsExpired(now time.Time) bool {
+	return !o.AbsoluteExpiry.IsZero() && now.After(o.AbsoluteExpiry)
+}
+
+// records returns the TLV records of the offer.
+func (o *Offer) records() (recordSet, error) {
+	records := make(recordSet)
+
+	if len(o.Chains) > 0 {
+		chains := make([]byte, 0, len(o.Chains)*chainhash.HashSize)
+		for _, chain := range o.Chains {
+			chains = append(chains, chain[:]...)
+		}
+		records[offerChainsType] = chains
+	}
+
+	if len(o.Metadata) > 0 {
+		records[offerMetadataType] = o.Metadata
+	}
+
+	if o.Amount != 0 {
+		records[offerAmountType] = encodeTU64(uint64(o.Amount))
+	}
+
+	if o.Description != "" {
+		records[offerDescriptionType] = []byte(o.Description)
+	}
+
+	if o.Features != nil && o.Features.SerializeSize() > 0 {
+		records[offerFeaturesType] = encodeFeatures(o.Features)
+	}
+
+	if !o.AbsoluteExpiry.IsZero() {
+		records[offerAbsoluteExpiryType] = encodeTU64(
+			uint64(o.AbsoluteExpiry.Unix()),
+		)
+	}
+
+	if len(o.Paths) > 0 {
+		paths, err := encodeBlindedPaths(o.Paths)
+		if err != nil {
+			return nil, err
+		}
+		records[offerPathsType] = paths
+	}
+
+	if o.Issuer != "" {
+		records[offerIssuerType] = []byte(o.Issuer)
+	}
+
+	if o.QuantityMax != nil {
+		records[offerQuantityMaxType] = encodeTU64(*o.QuantityMax)
+	}
+
+	if o.IssuerID != nil {
+		records[offerIssuerIDType] = o.IssuerID.SerializeCompressed()
+	}
+
+	if err := records.addExtra(o.ExtraRecords, offerRanges); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// offerFromRecords parses an offer from its TLV records. The records must be
+// in their canonical encoding, so that they can be mirrored.
+func offerFromRecords(records recordSet) (*Offer, error) {
+	var (
+		offer    Offer
+		original = records.copy()
+		err      error
+	)
+	for typ, value := range records {
+		switch typ {
+		case offerChainsType:
+			if len(value) == 0 || len(value)%chainhash.HashSize != 0 {
+				return nil, errors.New("invalid offer chains")
+			}
+
+			for len(value) > 0 {
+				var chain chainhash.Hash
+				copy(chain[:], value)
+				offer.Chains = append(offer.Chains, chain)
+				value = value[chainhash.HashSize:]
+			}
+
+		case offerMetadataType:
+			offer.Metadata = value
+
+		case offerAmountType:
+			var amt uint64
+			amt, err = decodeTU64(value)
+			offer.Amount = lnwire.MilliSatoshi(amt)
+
+		case offerDescriptionType:
+			offer.Description, err = decodeString(value)
+
+		case offerFeaturesType:
+			offer.Features, err = decodeFeatures(value)
+
+		case offerAbsoluteExpiryType:
+			var expiry uint64
+			expiry, err = decodeTU64(value)
+			if expiry > math.MaxInt64 {
+				err = errors.New("invalid offer expiry")
+			}
+			offer.AbsoluteExpiry = time.Unix(int64(expiry), 0)
+
+		case offerPathsType:
+			offer.Paths, err = decodeBlindedPaths(value)
+
+		case offerIssuerType:
+			offer.Issuer, err = decodeString(value)
+
+		case offerQuantityMaxType:
+			var quantityMax uint64
+			quantityMax, err = decodeTU64(value)
+			offer.QuantityMax = &quantityMax
+
+		case offerIssuerIDType:
+			offer.IssuerID, err = decodePubKey(value)
+
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid offer record %d: %w",
+				typ, err)
+		}
+
+		delete(records, typ)
+	}
+
+	offer.ExtraRecords, err = records.unknown()
+	if err != nil {
+		return nil, err
+	}
+
+	// The records are mirrored from the offer into invoice requests and
+	// invoices, so they must survive a round trip unchanged.
+	encoded, err := offer.records()
+	if err != nil {
+		return nil, err
+	}
+
+	if !encoded.equal(original) {
+		return nil, errors.New("offer is not canonically encoded")
+	}
+
+	return &offer, nil
+}
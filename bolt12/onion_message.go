@@ -0,0 +1,109 @@
+package bolt12
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// InvoiceRequestRecordType is the type of the onion message record
+	// that carries an invoice request.
+	InvoiceRequestRecordType = 64
+
+	// InvoiceRecordType is the type of the onion message record that
+	// carries an invoice.
+	InvoiceRecordType = 66
+
+	// InvoiceErrorRecordType is the type of the onion message record that
+	// carries an invoice error.
+	InvoiceErrorRecordType = 68
+
+	invoiceErrorFieldType          = 1
+	invoiceErrorSuggestedValueType = 3
+	invoiceErrorMessageType        = 5
+)
+
+// InvoiceError is sent in reply to an invoice request or invoice that can't
+// be handled.
+type InvoiceError struct {
+	// ErroneousField is the TLV type of the field that caused the error,
+	// if any.
+	ErroneousField *uint64
+
+	// SuggestedValue is a value for the erroneous field that would be
+	// accepted.
+	SuggestedValue []byte
+
+	// Message is a human-readable explanation of the error.
+	Message string
+}
+
+// Error returns a human-readable description of the error.
+func (e *InvoiceError) Error() string {
+	if e.ErroneousField != nil {
+		return fmt.Sprintf("invoice error in field %d: %s",
+			*e.ErroneousField, e.Message)
+	}
+
+	return fmt.Sprintf("invoice error: %s", e.Message)
+}
+
+// Serialize serializes the invoice error as a TLV stream.
+func (e *InvoiceError) Serialize() ([]byte, error) {
+	records := recordSet{
+		invoiceErrorMessageType: []byte(e.Message),
+	}
+
+	if e.ErroneousField != nil {
+		records[invoiceErrorFieldType] = encodeTU64(*e.ErroneousField)
+	}
+
+	if len(e.SuggestedValue) > 0 {
+		records[invoiceErrorSuggestedValueType] = e.SuggestedValue
+	}
+
+	return records.encode()
+}
+
+// DeserializeInvoiceError parses an invoice error from its TLV stream.
+func DeserializeInvoiceError(data []byte) (*InvoiceError, error) {
+	records, err := decodeRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := records[invoiceErrorMessageType]; !ok {
+		return nil, errors.New("invoice error has no message")
+	}
+
+	var invoiceError InvoiceError
+	for typ, value := range records {
+		switch typ {
+		case invoiceErrorFieldType:
+			var field uint64
+			field, err = decodeTU64(value)
+			invoiceError.ErroneousField = &field
+
+		case invoiceErrorSuggestedValueType:
+			invoiceError.SuggestedValue = value
+
+		case invoiceErrorMessageType:
+			invoiceError.Message, err = decodeString(value)
+
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice error record "+
+				"%d: %w", typ, err)
+		}
+
+		delete(records, typ)
+	}
+
+	if _, err := records.unknown(); err != nil {
+		return nil, err
+	}
+
+	return &invoiceError, nil
+}
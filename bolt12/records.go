@@ -0,0 +1,287 @@
+package bolt12
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/onionmessage"
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+)
+
+// typeRange is an inclusive range of TLV types.
+type typeRange struct {
+	min uint64
+	max uint64
+}
+
+// contains returns true if the type lies within the range.
+func (r typeRange) contains(typ uint64) bool {
+	return typ >= r.min && typ <= r.max
+}
+
+var (
+	// offerRanges are the TLV types that belong to an offer.
+	offerRanges = []typeRange{
+		{1, 79},
+		{1000000000, 1999999999},
+	}
+
+	// invoiceRequestRanges are the TLV types that an invoice request adds
+	// to the offer it mirrors.
+	invoiceRequestRanges = []typeRange{
+		{0, 0},
+		{80, 159},
+		{2000000000, 2999999999},
+	}
+
+	// invoiceRanges are the TLV types that an invoice adds to the invoice
+	// request it mirrors.
+	invoiceRanges = []typeRange{
+		{160, 239},
+		{3000000000, 3999999999},
+	}
+
+	// signatureRange are the TLV types that hold signatures, which are
+	// not part of the merkle tree of a message.
+	signatureRange = typeRange{240, 1000}
+)
+
+// inRanges returns true if the type lies within one of the ranges.
+func inRanges(typ uint64, ranges []typeRange) bool {
+	for _, r := range ranges {
+		if r.contains(typ) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordSet is a set of raw TLV records, keyed by their type.
+type recordSet map[uint64][]byte
+
+// split removes the records with a type in one of the given ranges from the
+// set and returns them.
+func (s recordSet) split(ranges []typeRange) recordSet {
+	out := make(recordSet)
+	for typ, value := range s {
+		if inRanges(typ, ranges) {
+			out[typ] = value
+			delete(s, typ)
+		}
+	}
+
+	return out
+}
+
+// unknown checks that the records left in the set after all known types have
+// been removed from it are optional. The remaining records are returned so
+// that they can be mirrored.
+func (s recordSet) unknown() (map[uint64][]byte, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	for typ := range s {
+		if typ%2 == 0 {
+			return nil, fmt.Errorf("unknown required type %d", typ)
+		}
+	}
+
+	return s, nil
+}
+
+// addExtra adds the given records of unknown types to the set, failing if any
+// of them are already present or not within the given ranges.
+func (s recordSet) addExtra(records map[uint64][]byte,
+	ranges []typeRange) error {
+
+	for typ, value := range records {
+		if !inRanges(typ, ranges) {
+			return fmt.Errorf("invalid extra record type %d", typ)
+		}
+
+		if _, ok := s[typ]; ok {
+			return fmt.Errorf("duplicate type %d", typ)
+		}
+
+		s[typ] = value
+	}
+
+	return nil
+}
+
+// copy returns a shallow copy of the set.
+func (s recordSet) copy() recordSet {
+	c := make(recordSet, len(s))
+	for typ, value := range s {
+		c[typ] = value
+	}
+
+	return c
+}
+
+// equal returns true if both sets contain the same records.
+func (s recordSet) equal(other recordSet) bool {
+	if len(s) != len(other) {
+		return false
+	}
+
+	for typ, value := range s {
+		otherValue, ok := other[typ]
+		if !ok || !bytes.Equal(value, otherValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// encode serializes the records as a canonical TLV stream.
+func (s recordSet) encode() ([]byte, error) {
+	stream, err := tlv.NewStream(tlv.MapToRecords(s)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeRecords parses a TLV stream into its raw records.
+func decodeRecords(data []byte) (recordSet, error) {
+	stream, err := tlv.NewStream()
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(recordSet, len(parsedTypes))
+	for typ, value := range parsedTypes {
+		records[uint64(typ)] = value
+	}
+
+	return records, nil
+}
+
+// encodeTU64 serializes a truncated uint64.
+func encodeTU64(v uint64) []byte {
+	var (
+		b       bytes.Buffer
+		scratch [8]byte
+	)
+	_ = tlv.ETUint64T(&b, v, &scratch)
+
+	return b.Bytes()
+}
+
+// decodeTU64 parses a truncated uint64.
+func decodeTU64(value []byte) (uint64, error) {
+	var (
+		v       uint64
+		scratch [8]byte
+	)
+	err := tlv.DTUint64(
+		bytes.NewReader(value), &v, &scratch, uint64(len(value)),
+	)
+
+	return v, err
+}
+
+// decodeString parses a UTF-8 string.
+func decodeString(value []byte) (string, error) {
+	if !utf8.Valid(value) {
+		return "", errors.New("invalid utf-8 string")
+	}
+
+	return string(value), nil
+}
+
+// encodeFeatures serializes a feature vector without a length prefix.
+func encodeFeatures(features *lnwire.RawFeatureVector) []byte {
+	var b bytes.Buffer
+	_ = features.EncodeBase256(&b)
+
+	return b.Bytes()
+}
+
+// decodeFeatures parses a feature vector that is serialized without a length
+// prefix, and checks that we understand all of its required features.
+func decodeFeatures(value []byte) (*lnwire.RawFeatureVector, error) {
+	features := lnwire.NewRawFeatureVector()
+	err := features.DecodeBase256(bytes.NewReader(value), len(value))
+	if err != nil {
+		return nil, err
+	}
+
+	fv := lnwire.NewFeatureVector(features, lnwire.Features)
+	if unknown := fv.UnknownRequiredFeatures(); len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown required features: %v",
+			unknown)
+	}
+
+	return features, nil
+}
+
+// decodePubKey parses a compressed public key.
+func decodePubKey(value []byte) (*btcec.PublicKey, error) {
+	if len(value) != btcec.PubKeyBytesLenCompressed {
+		return nil, fmt.Errorf("invalid public key length: %d",
+			len(value))
+	}
+
+	return btcec.ParsePubKey(value)
+}
+
+// encodeBlindedPaths serializes a list of blinded paths.
+func encodeBlindedPaths(paths []*sphinx.BlindedPath) ([]byte, error) {
+	var b bytes.Buffer
+	for _, path := range paths {
+		if err := onionmessage.WriteBlindedPath(&b, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeBlindedPaths parses a non-empty list of blinded paths.
+func decodeBlindedPaths(value []byte) ([]*sphinx.BlindedPath, error) {
+	var (
+		r     = bytes.NewReader(value)
+		paths []*sphinx.BlindedPath
+	)
+	for r.Len() > 0 {
+		path, err := onionmessage.ReadBlindedPath(r)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("empty list of blinded paths")
+	}
+
+	return paths, nil
+}
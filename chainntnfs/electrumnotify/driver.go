@@ -13,9 +13,9 @@ import (
 // createNewNotifier creates a new instance of the ChainNotifier interface
 // implemented by ElectrumNotifier.
 func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
-	if len(args) != 6 {
+	if len(args) != 7 {
 		return nil, fmt.Errorf("incorrect number of arguments to "+
-			".New(...), expected 6, instead passed %v", len(args))
+			".New(...), expected 7, instead passed %v", len(args))
 	}
 
 	client, ok := args[0].(*electrum.Client)
@@ -48,14 +48,26 @@ func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
 			"is incorrect, expected a *blockcache.BlockCache")
 	}
 
-	restURL, ok := args[5].(string)
+	// The block source is optional, so a nil argument is accepted.
+	var blockSource BlockSource
+	if args[5] != nil {
+		blockSource, ok = args[5].(BlockSource)
+		if !ok {
+			return nil, errors.New("sixth argument to " +
+				"electrumnotify.New is incorrect, expected " +
+				"a BlockSource")
+		}
+	}
+
+	headerStore, ok := args[6].(*electrum.HeaderStore)
 	if !ok {
-		return nil, errors.New("sixth argument to electrumnotify.New " +
-			"is incorrect, expected a string (REST URL)")
+		return nil, errors.New("seventh argument to " +
+			"electrumnotify.New is incorrect, expected a " +
+			"*electrum.HeaderStore")
 	}
 
 	return New(client, chainParams, spendHintCache,
-		confirmHintCache, blockCache, restURL), nil
+		confirmHintCache, blockCache, blockSource, headerStore), nil
 }
 
 // init registers a driver for the ElectrumNotifier concrete implementation of
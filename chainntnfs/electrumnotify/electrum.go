@@ -51,16 +51,31 @@ type ElectrumNotifier struct {
 	bestBlockMtx sync.RWMutex
 	bestBlock    chainntnfs.BlockEpoch
 
+	// recentBlocks holds the blocks we've connected within the last
+	// ReorgSafetyLimit heights, keyed by height. It is used to find the
+	// fork point when the server switches to a different branch.
+	//
+	// NOTE: This is only accessed by the blockSubscriptionHandler.
+	recentBlocks map[int32]chainntnfs.BlockEpoch
+
 	// client is the Electrum client used to communicate with the server.
 	client *electrum.Client
 
-	// restClient is an optional REST API client for mempool/electrs.
-	// Used to fetch TxIndex for channel validation.
-	restClient *electrum.RESTClient
+	// blockSource is an optional source of full blocks, which Electrum
+	// servers can't serve themselves.
+	blockSource BlockSource
 
 	// chainParams are the parameters of the chain we're connected to.
 	chainParams *chaincfg.Params
 
+	// verifier checks the proof-of-work, linkage and difficulty of the
+	// headers served by the Electrum server before we act on them.
+	verifier *electrum.HeaderVerifier
+
+	// headerStore is an optional persistent store of validated headers,
+	// used to serve historical header lookups without asking the server.
+	headerStore *electrum.HeaderStore
+
 	notificationCancels  chan interface{}
 	notificationRegistry chan interface{}
 
@@ -83,36 +98,41 @@ type ElectrumNotifier struct {
 	quit chan struct{}
 }
 
+// BlockSource fetches full blocks by hash. The blocks it returns must have
+// been checked against the validated header chain.
+type BlockSource interface {
+	// GetBlock returns the block with the given hash.
+	GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error)
+}
+
 // Ensure ElectrumNotifier implements the ChainNotifier interface at compile
 // time.
 var _ chainntnfs.ChainNotifier = (*ElectrumNotifier)(nil)
 
 // New creates a new instance of the ElectrumNotifier. The Electrum client
 // should already be started and connected before being passed to this
-// function. If restURL is provided, the notifier will use the mempool/electrs
-// REST API to fetch TxIndex for proper channel validation.
+// function. If blockSource is non-nil, GetBlock fetches full blocks from it.
+// If headerStore is non-nil, headers it covers are read from it instead of
+// the server.
 func New(client *electrum.Client, chainParams *chaincfg.Params,
 	spendHintCache chainntnfs.SpendHintCache,
 	confirmHintCache chainntnfs.ConfirmHintCache,
-	blockCache *blockcache.BlockCache,
-	restURL string) *ElectrumNotifier {
+	blockCache *blockcache.BlockCache, blockSource BlockSource,
+	headerStore *electrum.HeaderStore) *ElectrumNotifier {
 
-	var restClient *electrum.RESTClient
-	if restURL != "" {
-		restClient = electrum.NewRESTClient(restURL)
-		log.Infof("Electrum notifier REST API enabled: %s", restURL)
-	}
-
-	return &ElectrumNotifier{
+	notifier := &ElectrumNotifier{
 		client:      client,
-		restClient:  restClient,
+		blockSource: blockSource,
 		chainParams: chainParams,
+		headerStore: headerStore,
 
 		notificationCancels:  make(chan interface{}),
 		notificationRegistry: make(chan interface{}),
 
 		blockEpochClients: make(map[uint64]*blockEpochRegistration),
 
+		recentBlocks: make(map[int32]chainntnfs.BlockEpoch),
+
 		spendHintCache:   spendHintCache,
 		confirmHintCache: confirmHintCache,
 
@@ -120,6 +140,11 @@ func New(client *electrum.Client, chainParams *chaincfg.Params,
 
 		quit: make(chan struct{}),
 	}
+	notifier.verifier = electrum.NewHeaderVerifier(
+		chainParams, notifier.fetchHeader,
+	)
+
+	return notifier
 }
 
 // Start establishes the connection to the Electrum server and begins
@@ -164,6 +189,12 @@ func (e *ElectrumNotifier) startNotifier() error {
 				err)
 		}
 
+		err = electrum.CheckHeaderPoW(blockHeader, e.chainParams)
+		if err != nil {
+			return fmt.Errorf("invalid initial block header: %w",
+				err)
+		}
+
 		blockHash := blockHeader.BlockHash()
 
 		e.bestBlockMtx.Lock()
@@ -172,6 +203,7 @@ func (e *ElectrumNotifier) startNotifier() error {
 			Hash:        &blockHash,
 			BlockHeader: blockHeader,
 		}
+		e.recentBlocks[e.bestBlock.Height] = e.bestBlock
 		e.bestBlockMtx.Unlock()
 
 		log.Infof("Electrum notifier started at height %d, hash %s",
@@ -273,30 +305,24 @@ func (e *ElectrumNotifier) blockSubscriptionHandler(
 			blockHash := blockHeader.BlockHash()
 			newHeight := int32(headerResult.Height)
 
-			// Check if this is a new block or a reorg.
-			e.bestBlockMtx.RLock()
-			prevHeight := e.bestBlock.Height
-			prevHash := e.bestBlock.Hash
-			e.bestBlockMtx.RUnlock()
-
-			// Handle the new block.
-			if newHeight > prevHeight {
-				// New block connected.
-				e.handleBlockConnected(
-					newHeight, &blockHash, blockHeader,
-				)
-			} else if newHeight <= prevHeight &&
-				!blockHash.IsEqual(prevHash) {
-
-				// Potential reorg detected.
-				log.Warnf("Potential reorg detected: "+
-					"prev_height=%d, new_height=%d",
-					prevHeight, newHeight)
-
-				e.handleReorg(prevHeight, newHeight, &blockHash,
-					blockHeader)
+			// Refuse to act on a tip that doesn't carry valid
+			// proof-of-work or doesn't follow the difficulty rules.
+			err = e.verifier.VerifyHeader(
+				blockHeader, newHeight, nil,
+			)
+			if err != nil {
+				log.Errorf("Rejecting block header %v at "+
+					"height %d: %v", blockHash, newHeight,
+					err)
+				continue
 			}
 
+			e.handleNewTip(chainntnfs.BlockEpoch{
+				Height:      newHeight,
+				Hash:        &blockHash,
+				BlockHeader: blockHeader,
+			})
+
 		case <-e.quit:
 			return
 		}
@@ -309,13 +335,16 @@ func (e *ElectrumNotifier) handleBlockConnected(height int32,
 
 	log.Debugf("New block connected: height=%d, hash=%s", height, hash)
 
-	// Update the best block.
+	// Update the best block and remember it in case it's reorged out
+	// later on.
 	e.bestBlockMtx.Lock()
 	e.bestBlock = chainntnfs.BlockEpoch{
 		Height:      height,
 		Hash:        hash,
 		BlockHeader: header,
 	}
+	e.recentBlocks[height] = e.bestBlock
+	delete(e.recentBlocks, height-chainntnfs.ReorgSafetyLimit)
 	e.bestBlockMtx.Unlock()
 
 	// Notify all block epoch clients about the new block.
@@ -429,26 +458,191 @@ func (e *ElectrumNotifier) checkPendingSpends(currentHeight uint32) {
 	}
 }
 
-// handleReorg handles a chain reorganization.
-func (e *ElectrumNotifier) handleReorg(prevHeight, newHeight int32,
-	newHash *chainhash.Hash, newHeader *wire.BlockHeader) {
+// handleNewTip reconciles our view of the chain with a new tip announced by
+// the server. The server only tells us about its latest tip, so a single
+// notification may cover several missed blocks, a reorg of the same height or
+// a reorg that replaces many blocks at once. We find the fork point between
+// the server's branch and the blocks we've connected, disconnect exactly the
+// stale blocks and then connect the new branch block by block.
+func (e *ElectrumNotifier) handleNewTip(tip chainntnfs.BlockEpoch) {
+	e.bestBlockMtx.RLock()
+	prevBest := e.bestBlock
+	e.bestBlockMtx.RUnlock()
 
-	// For reorgs, we need to disconnect blocks and reconnect at the new
-	// height. Since we don't have full block data, we do our best by
-	// updating the txNotifier.
-	if e.txNotifier != nil {
-		// Disconnect blocks from prevHeight down to newHeight.
-		for h := uint32(prevHeight); h > uint32(newHeight); h-- {
-			err := e.txNotifier.DisconnectTip(h)
+	if prevBest.Hash != nil && tip.Hash.IsEqual(prevBest.Hash) {
+		return
+	}
+
+	forkHeight, branch, err := findForkPoint(
+		tip, prevBest.Height, e.serverHeaders, e.recentBlockHash,
+	)
+	if err != nil {
+		log.Errorf("Unable to reconcile chain with tip %v at height "+
+			"%d: %v", tip.Hash, tip.Height, err)
+		return
+	}
+
+	for _, block := range branch {
+		err := electrum.CheckHeaderPoW(block.BlockHeader, e.chainParams)
+		if err != nil {
+			log.Errorf("Rejecting branch to tip %v: invalid "+
+				"header at height %d: %v", tip.Hash,
+				block.Height, err)
+			return
+		}
+	}
+
+	if forkHeight < prevBest.Height {
+		log.Warnf("Chain reorganization detected: fork at height %d, "+
+			"disconnecting %d block(s), new tip %v at height %d",
+			forkHeight, prevBest.Height-forkHeight, tip.Hash,
+			tip.Height)
+
+		e.disconnectBlocks(forkHeight)
+	}
+
+	for _, block := range branch {
+		e.handleBlockConnected(
+			block.Height, block.Hash, block.BlockHeader,
+		)
+	}
+}
+
+// disconnectBlocks rewinds our best block down to the given fork height,
+// disconnecting every block above it from the txNotifier.
+func (e *ElectrumNotifier) disconnectBlocks(forkHeight int32) {
+	e.bestBlockMtx.Lock()
+	defer e.bestBlockMtx.Unlock()
+
+	for height := e.bestBlock.Height; height > forkHeight; height-- {
+		log.Infof("Block disconnected from main chain: height=%d, "+
+			"hash=%v", height, e.bestBlock.Hash)
+
+		if e.txNotifier != nil {
+			err := e.txNotifier.DisconnectTip(uint32(height))
 			if err != nil {
 				log.Errorf("Failed to disconnect tip at "+
-					"height %d: %v", h, err)
+					"height %d: %v", height, err)
+			}
+		}
+
+		delete(e.recentBlocks, height)
+
+		// Step back to the previous block. If it's outside the window
+		// of blocks we remember, only its height is known.
+		prev, ok := e.recentBlocks[height-1]
+		if !ok {
+			prev = chainntnfs.BlockEpoch{Height: height - 1}
+			if e.bestBlock.BlockHeader != nil {
+				prev.Hash = &e.bestBlock.BlockHeader.PrevBlock
+			}
+		}
+		e.bestBlock = prev
+	}
+}
+
+// recentBlockHash returns the hash of the block we connected at the given
+// height, if it's within the window of blocks we remember.
+func (e *ElectrumNotifier) recentBlockHash(height int32) (*chainhash.Hash,
+	bool) {
+
+	e.bestBlockMtx.RLock()
+	defer e.bestBlockMtx.RUnlock()
+
+	block, ok := e.recentBlocks[height]
+	if !ok {
+		return nil, false
+	}
+
+	return block.Hash, true
+}
+
+// serverHeaders fetches count headers starting at the given height from the
+// Electrum server in a single request. Unlike fetchHeader it never consults
+// the header store, as the store may still hold blocks of a branch that is
+// being reorged out.
+func (e *ElectrumNotifier) serverHeaders(startHeight int32,
+	count uint32) ([]*wire.BlockHeader, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return e.client.GetBlockHeaders(ctx, uint32(startHeight), count)
+}
+
+// findForkPoint walks the server's chain back from the new tip until it finds
+// a block we've connected ourselves, whose hash is looked up by localHash.
+// It returns the height of that common ancestor along with the server's
+// blocks above it, ordered by height and ending with the tip. The server's
+// headers are fetched in batches, and every one of them must link to the one
+// above it so that the branch is consistent.
+//
+// If the walk goes below the window of blocks we remember, the block just
+// above the window is assumed to be shared, as there's nothing left to
+// compare against.
+func findForkPoint(tip chainntnfs.BlockEpoch, bestHeight int32,
+	fetchHeaders electrum.HeaderRangeFetcher,
+	localHash func(int32) (*chainhash.Hash, bool)) (int32,
+	[]chainntnfs.BlockEpoch, error) {
+
+	// The server may have fallen back to a block we've already
+	// connected, in which case everything above it is stale.
+	if tip.Height <= bestHeight {
+		ours, ok := localHash(tip.Height)
+		if ok && ours.IsEqual(tip.Hash) {
+			return tip.Height, nil, nil
+		}
+	}
+
+	branch := []chainntnfs.BlockEpoch{tip}
+	next := tip.BlockHeader
+	walker := electrum.NewHeaderWalker(fetchHeaders, bestHeight)
+
+	for height := tip.Height - 1; height >= 0; height-- {
+		if height <= bestHeight {
+			ours, ok := localHash(height)
+			if !ok {
+				log.Warnf("Reorg deeper than the %d blocks we "+
+					"track, assuming common ancestor at "+
+					"height %d", chainntnfs.ReorgSafetyLimit,
+					height)
+
+				break
 			}
+
+			if next.PrevBlock.IsEqual(ours) {
+				break
+			}
+		}
+
+		header, err := walker.Header(height)
+		if err != nil {
+			return 0, nil, fmt.Errorf("unable to fetch header at "+
+				"height %d: %w", height, err)
 		}
+
+		hash := header.BlockHash()
+		if !next.PrevBlock.IsEqual(&hash) {
+			return 0, nil, fmt.Errorf("%w: expected %v at height "+
+				"%d, got %v", electrum.ErrHeaderNotConnected,
+				next.PrevBlock, height, hash)
+		}
+
+		branch = append(branch, chainntnfs.BlockEpoch{
+			Height:      height,
+			Hash:        &hash,
+			BlockHeader: header,
+		})
+		next = header
+	}
+
+	// The branch was collected from the tip downwards, so reverse it to
+	// connect the blocks in order.
+	for i, j := 0, len(branch)-1; i < j; i, j = i+1, j-1 {
+		branch[i], branch[j] = branch[j], branch[i]
 	}
 
-	// Now handle the new block at the reorg height.
-	e.handleBlockConnected(newHeight, newHash, newHeader)
+	return branch[0].Height - 1, branch, nil
 }
 
 // notificationDispatcher is the primary goroutine which handles client
@@ -615,20 +809,16 @@ func (e *ElectrumNotifier) historicalConfDetails(
 				}
 			}
 
-			// Try to get the actual TxIndex via REST API if available.
-			var txIndex uint32
-			if e.restClient != nil {
-				txIdx, _, err := e.restClient.GetTxIndexByHeight(
-					ctx, int64(blockHeight),
-					confRequest.TxID.String(),
-				)
-				if err != nil {
-					log.Debugf("Failed to get TxIndex via REST: %v", err)
-				} else {
-					txIndex = txIdx
-					log.Debugf("Got TxIndex %d for tx %s via REST",
-						txIndex, confRequest.TxID)
-				}
+			// Before dispatching the confirmation, make sure the
+			// server can prove the transaction is in the block it
+			// claims. The proof also gives us the TxIndex.
+			txIndex, err := e.verifyTxInclusion(
+				ctx, &confRequest.TxID, blockHeight, blockHash,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to verify "+
+					"confirmation of %v: %w",
+					confRequest.TxID, err)
 			}
 
 			return &chainntnfs.TxConfirmation{
@@ -687,13 +877,20 @@ func (e *ElectrumNotifier) historicalConfDetails(
 			continue
 		}
 
-		// Get the block header for this height.
-		header, err := e.client.GetBlockHeader(
-			ctx, uint32(tx.Height),
+		txHash, err := chainhash.NewHashFromStr(tx.Hash)
+		if err != nil {
+			continue
+		}
+
+		// Make sure the server can prove the transaction is in the
+		// block at the height it claims. The proof also gives us the
+		// TxIndex.
+		header, txIndex, err := e.verifiedTxHeader(
+			ctx, txHash, uint32(tx.Height),
 		)
 		if err != nil {
-			log.Debugf("Failed to get block header at height %d: %v",
-				tx.Height, err)
+			log.Warnf("Unable to verify confirmation of tx %s at "+
+				"height %d: %v", tx.Hash, tx.Height, err)
 			continue
 		}
 
@@ -723,22 +920,6 @@ func (e *ElectrumNotifier) historicalConfDetails(
 				tx.Hash, txErr)
 		}
 
-		// Try to get the actual TxIndex via REST API if available.
-		var txIndex uint32
-		if e.restClient != nil {
-			blockHashStr := blockHash.String()
-			txIdx, err := e.restClient.GetTxIndex(
-				ctx, blockHashStr, tx.Hash,
-			)
-			if err != nil {
-				log.Debugf("Failed to get TxIndex via REST: %v", err)
-			} else {
-				txIndex = txIdx
-				log.Debugf("Got TxIndex %d for tx %s via REST",
-					txIndex, tx.Hash)
-			}
-		}
-
 		return &chainntnfs.TxConfirmation{
 			BlockHash:   &blockHash,
 			BlockHeight: uint32(tx.Height),
@@ -825,6 +1006,21 @@ func (e *ElectrumNotifier) historicalSpendDetails(
 			if txIn.PreviousOutPoint == spendRequest.OutPoint {
 				spenderHash := tx.TxHash()
 
+				// Only report the spend once the server has
+				// proven the spender is in the block it
+				// claims.
+				_, _, err := e.verifiedTxHeader(
+					ctx, &spenderHash,
+					uint32(histTx.Height),
+				)
+				if err != nil {
+					log.Warnf("Unable to verify spend of "+
+						"%v by %v: %v",
+						spendRequest.OutPoint,
+						spenderHash, err)
+					break
+				}
+
 				return &chainntnfs.SpendDetail{
 					SpentOutPoint:     &spendRequest.OutPoint,
 					SpenderTxHash:     &spenderHash,
@@ -839,6 +1035,73 @@ func (e *ElectrumNotifier) historicalSpendDetails(
 	return nil, nil
 }
 
+// fetchHeader retrieves the block header at the given height from the header
+// store, or from the Electrum server if the store doesn't have it.
+func (e *ElectrumNotifier) fetchHeader(height int32) (*wire.BlockHeader,
+	error) {
+
+	if header, ok := e.storedHeader(uint32(height)); ok {
+		return header, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return e.client.GetBlockHeader(ctx, uint32(height))
+}
+
+// verifiedTxHeader fetches and validates the block header at the given height
+// and checks the server's merkle proof that the transaction is committed to by
+// it. It returns the header along with the transaction's index in the block.
+func (e *ElectrumNotifier) verifiedTxHeader(ctx context.Context,
+	txid *chainhash.Hash, height uint32) (*wire.BlockHeader, uint32,
+	error) {
+
+	// Headers from the store were validated when they were written.
+	header, ok := e.storedHeader(height)
+	if !ok {
+		var err error
+		header, err = e.client.GetBlockHeader(ctx, height)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to fetch header at "+
+				"height %d: %w", height, err)
+		}
+
+		err = e.verifier.VerifyHeader(header, int32(height), nil)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	txIndex, err := e.client.VerifyTxInclusion(ctx, txid, height, header)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return header, txIndex, nil
+}
+
+// verifyTxInclusion checks that the transaction is committed to by the block
+// with the given hash at the given height, returning its index in the block.
+func (e *ElectrumNotifier) verifyTxInclusion(ctx context.Context,
+	txid *chainhash.Hash, height uint32,
+	blockHash *chainhash.Hash) (uint32, error) {
+
+	header, txIndex, err := e.verifiedTxHeader(ctx, txid, height)
+	if err != nil {
+		return 0, err
+	}
+
+	headerHash := header.BlockHash()
+	if !headerHash.IsEqual(blockHash) {
+		return 0, fmt.Errorf("%w: expected %v at height %d, got %v",
+			electrum.ErrHeaderMismatch, blockHash, height,
+			headerHash)
+	}
+
+	return txIndex, nil
+}
+
 // dispatchMissedBlocks sends block epoch notifications for any blocks that
 // the client may have missed.
 func (e *ElectrumNotifier) dispatchMissedBlocks(
@@ -851,13 +1114,7 @@ func (e *ElectrumNotifier) dispatchMissedBlocks(
 	startHeight := registration.bestBlock.Height + 1
 
 	for height := startHeight; height <= currentHeight; height++ {
-		ctx, cancel := context.WithTimeout(
-			context.Background(), 30*time.Second,
-		)
-
-		header, err := e.client.GetBlockHeader(ctx, uint32(height))
-		cancel()
-
+		header, err := e.fetchHeader(height)
 		if err != nil {
 			log.Errorf("Failed to get block header at height %d: %v",
 				height, err)
@@ -869,6 +1126,23 @@ func (e *ElectrumNotifier) dispatchMissedBlocks(
 	}
 }
 
+// storedHeader returns the header at the given height from the header store,
+// if one is configured and covers that height.
+func (e *ElectrumNotifier) storedHeader(height uint32) (*wire.BlockHeader,
+	bool) {
+
+	if e.headerStore == nil {
+		return nil, false
+	}
+
+	header, err := e.headerStore.FetchHeaderByHeight(height)
+	if err != nil {
+		return nil, false
+	}
+
+	return header, true
+}
+
 // notifyBlockEpochClient sends a block epoch notification to a specific client.
 func (e *ElectrumNotifier) notifyBlockEpochClient(
 	registration *blockEpochRegistration, height int32,
@@ -994,14 +1268,25 @@ func (e *ElectrumNotifier) RegisterBlockEpochNtfn(
 	}
 }
 
-// GetBlock attempts to retrieve a block from the cache or the Electrum server.
-// NOTE: Electrum servers do not serve full blocks, so this will return an
-// error. This method is provided for interface compatibility.
+// GetBlock attempts to retrieve a block from the cache or the notifier's
+// block source.
+//
+// NOTE: Electrum servers do not serve full blocks, so an error is returned if
+// the notifier wasn't given a block source.
 func (e *ElectrumNotifier) GetBlock(hash chainhash.Hash) (*ltcutil.Block,
 	error) {
 
-	return nil, errors.New("electrum backend does not support full block " +
-		"retrieval")
+	if e.blockSource == nil {
+		return nil, errors.New("electrum backend does not support " +
+			"full block retrieval")
+	}
+
+	block, err := e.blockCache.GetBlock(&hash, e.blockSource.GetBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	return ltcutil.NewBlock(block), nil
 }
 
 // filteredBlock represents a block with optional transaction data.
@@ -0,0 +1,249 @@
+package electrumnotify
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/lnd/chainntnfs"
+	"github.com/ltcsuite/lnd/electrum"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// testChain is a simple height-indexed view of a chain of headers.
+type testChain map[int32]*wire.BlockHeader
+
+// extend returns a copy of the chain that keeps the blocks up to and
+// including forkHeight, followed by new blocks up to tipHeight. The branch
+// byte makes the new blocks differ from those of other branches.
+func (c testChain) extend(forkHeight, tipHeight int32, branch byte) testChain {
+	chain := make(testChain, len(c))
+	for height, header := range c {
+		if height <= forkHeight {
+			chain[height] = header
+		}
+	}
+
+	for height := forkHeight + 1; height <= tipHeight; height++ {
+		var prevHash chainhash.Hash
+		if prev, ok := chain[height-1]; ok {
+			prevHash = prev.BlockHash()
+		}
+
+		chain[height] = &wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  prevHash,
+			MerkleRoot: chainhash.Hash{branch},
+			Timestamp:  time.Unix(int64(height)*150, 0),
+		}
+	}
+
+	return chain
+}
+
+// epoch returns the block epoch of the block at the given height.
+func (c testChain) epoch(height int32) chainntnfs.BlockEpoch {
+	hash := c[height].BlockHash()
+
+	return chainntnfs.BlockEpoch{
+		Height:      height,
+		Hash:        &hash,
+		BlockHeader: c[height],
+	}
+}
+
+// fetcher returns a function that looks up a range of headers, like the
+// Electrum server would. Every request is counted in the passed counter.
+func (c testChain) fetcher(requests *int) electrum.HeaderRangeFetcher {
+	return func(startHeight int32,
+		count uint32) ([]*wire.BlockHeader, error) {
+
+		*requests++
+
+		headers := make([]*wire.BlockHeader, 0, count)
+		for i := int32(0); i < int32(count); i++ {
+			header, ok := c[startHeight+i]
+			if !ok {
+				return nil, errors.New("unknown height")
+			}
+
+			headers = append(headers, header)
+		}
+
+		return headers, nil
+	}
+}
+
+// localHashes returns a lookup of the chain's hashes between the given
+// heights, modelling the window of blocks the notifier remembers.
+func (c testChain) localHashes(from,
+	to int32) func(int32) (*chainhash.Hash, bool) {
+
+	return func(height int32) (*chainhash.Hash, bool) {
+		if height < from || height > to {
+			return nil, false
+		}
+
+		hash := c[height].BlockHash()
+
+		return &hash, true
+	}
+}
+
+// TestFindForkPoint tests that the fork point between the server's branch and
+// our own blocks is found for extensions, missed blocks and reorgs of various
+// depths.
+func TestFindForkPoint(t *testing.T) {
+	t.Parallel()
+
+	ours := testChain{}.extend(-1, 110, 0)
+
+	testCases := []struct {
+		name        string
+		server      testChain
+		tipHeight   int32
+		windowStart int32
+		forkHeight  int32
+		branchLen   int
+		requests    int
+	}{
+		{
+			name:       "extends tip",
+			server:     ours.extend(110, 111, 0),
+			tipHeight:  111,
+			forkHeight: 110,
+			branchLen:  1,
+		},
+		{
+			name:       "missed blocks",
+			server:     ours.extend(110, 115, 0),
+			tipHeight:  115,
+			forkHeight: 110,
+			branchLen:  5,
+			requests:   1,
+		},
+		{
+			name:       "same height reorg",
+			server:     ours.extend(109, 110, 1),
+			tipHeight:  110,
+			forkHeight: 109,
+			branchLen:  1,
+		},
+		{
+			name:       "deep reorg to longer chain",
+			server:     ours.extend(100, 112, 1),
+			tipHeight:  112,
+			forkHeight: 100,
+			branchLen:  12,
+			requests:   1,
+		},
+		{
+			name:       "deep reorg to shorter chain",
+			server:     ours.extend(95, 105, 1),
+			tipHeight:  105,
+			forkHeight: 95,
+			branchLen:  10,
+			requests:   1,
+		},
+		{
+			name:       "server falls back to our block",
+			server:     ours.extend(107, 107, 0),
+			tipHeight:  107,
+			forkHeight: 107,
+			branchLen:  0,
+		},
+		{
+			name:        "reorg beyond tracked window",
+			server:      ours.extend(90, 111, 1),
+			tipHeight:   111,
+			windowStart: 100,
+			forkHeight:  99,
+			branchLen:   12,
+			requests:    1,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int
+			forkHeight, branch, err := findForkPoint(
+				tc.server.epoch(tc.tipHeight), 110,
+				tc.server.fetcher(&requests),
+				ours.localHashes(tc.windowStart, 110),
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.forkHeight, forkHeight)
+			require.Len(t, branch, tc.branchLen)
+
+			// The headers are fetched in batches rather than one
+			// at a time.
+			require.Equal(t, tc.requests, requests)
+
+			// The branch must be the server's blocks above the
+			// fork point, in order.
+			for i, block := range branch {
+				height := forkHeight + int32(i) + 1
+				require.Equal(t, height, block.Height)
+
+				hash := tc.server[height].BlockHash()
+				require.Equal(t, hash, *block.Hash)
+			}
+		})
+	}
+}
+
+// TestFindForkPointBrokenBranch tests that a branch whose headers don't link
+// up is rejected.
+func TestFindForkPointBrokenBranch(t *testing.T) {
+	t.Parallel()
+
+	ours := testChain{}.extend(-1, 110, 0)
+	server := ours.extend(100, 112, 1)
+
+	// Swap out a header in the middle of the branch for one that doesn't
+	// link to its successor.
+	server[105] = ours[105]
+
+	var requests int
+	_, _, err := findForkPoint(
+		server.epoch(112), 110, server.fetcher(&requests),
+		ours.localHashes(0, 110),
+	)
+	require.ErrorIs(t, err, electrum.ErrHeaderNotConnected)
+}
+
+// TestDisconnectBlocks tests that disconnecting blocks rewinds the best block
+// to the fork point and forgets the stale blocks.
+func TestDisconnectBlocks(t *testing.T) {
+	t.Parallel()
+
+	chain := testChain{}.extend(-1, 110, 0)
+
+	notifier := &ElectrumNotifier{
+		recentBlocks: make(map[int32]chainntnfs.BlockEpoch),
+	}
+	for height := int32(100); height <= 110; height++ {
+		notifier.recentBlocks[height] = chain.epoch(height)
+	}
+	notifier.bestBlock = chain.epoch(110)
+
+	notifier.disconnectBlocks(104)
+
+	require.Equal(t, chain.epoch(104), notifier.bestBlock)
+	for height := int32(105); height <= 110; height++ {
+		_, ok := notifier.recentBlockHash(height)
+		require.False(t, ok)
+	}
+
+	// Rewinding to just below the remembered window, which is as deep as
+	// a fork point can be, still tracks the hash of the new best block.
+	notifier.disconnectBlocks(99)
+	require.EqualValues(t, 99, notifier.bestBlock.Height)
+	require.Equal(t, chain[100].PrevBlock, *notifier.bestBlock.Hash)
+}
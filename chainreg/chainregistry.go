@@ -277,6 +277,10 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			"cache: %v", err)
 	}
 
+	// electrumHeaders is the Electrum backend's persistent header store,
+	// which must be closed on cleanup if it was opened.
+	var electrumHeaders *electrum.HeaderStore
+
 	// If spv mode is active, then we'll be using a distinct set of
 	// chainControl interfaces that interface directly with the p2p network
 	// of the selected chain.
@@ -675,8 +679,8 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 	case "electrum":
 		electrumMode := cfg.ElectrumMode
 
-		log.Infof("Initializing Electrum backend, server=%s",
-			electrumMode.Server)
+		log.Infof("Initializing Electrum backend, servers=%v",
+			electrumMode.Servers)
 
 		// Create the Electrum client configuration.
 		electrumClientCfg := electrum.NewClientConfigFromLncfg(
@@ -695,19 +699,50 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		}
 		log.Info("Electrum client started successfully")
 
-		// Create the chain notifier.
+		// Open the persistent header store that is shared by the
+		// chain client and notifier.
+		dataDir := filepath.Join(
+			homeChainConfig.ChainDir,
+			lncfg.NormalizeNetwork(
+				cfg.ActiveNetParams.Name,
+			),
+		)
+		electrumHeaders, err = electrum.NewHeaderStore(
+			filepath.Join(dataDir, "electrum"),
+			cfg.ActiveNetParams.Params,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open electrum "+
+				"header store: %v", err)
+		}
+
+		// Create the chain client for wallet integration.
+		log.Debug("Creating Electrum chain client")
+		chainClient := electrum.NewChainClient(
+			electrumClient, cfg.ActiveNetParams.Params,
+			electrumMode.RESTURL, dataDir,
+			electrumMode.MwebP2PPeers, electrumHeaders,
+		)
+		cc.ChainSource = chainClient
+		log.Debug("Electrum chain client created")
+
+		// Create the chain notifier. Full blocks are fetched through
+		// the chain client, which gets them from P2P peers or the
+		// REST API.
 		log.Debug("Creating Electrum chain notifier")
 		chainNotifier := electrumnotify.New(
 			electrumClient, cfg.ActiveNetParams.Params,
-			hintCache, hintCache, cfg.BlockCache,
-			electrumMode.RESTURL,
+			hintCache, hintCache, cfg.BlockCache, chainClient,
+			electrumHeaders,
 		)
 		cc.ChainNotifier = chainNotifier
 		log.Debug("Electrum chain notifier created")
 
 		// Create the filtered chain view using the adapter.
 		log.Debug("Creating Electrum filtered chain view")
-		chainViewAdapter := electrum.NewChainViewAdapter(electrumClient)
+		chainViewAdapter := electrum.NewChainViewAdapter(
+			electrumClient, cfg.ActiveNetParams.Params,
+		)
 		cc.ChainView, err = chainview.NewElectrumFilteredChainView(
 			chainViewAdapter,
 		)
@@ -725,28 +760,13 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		)
 		log.Debug("Electrum fee estimator created")
 
-		// Create the chain client for wallet integration.
-		log.Debug("Creating Electrum chain client")
-		dataDir := filepath.Join(
-			homeChainConfig.ChainDir,
-			lncfg.NormalizeNetwork(
-				cfg.ActiveNetParams.Name,
-			),
-		)
-		chainClient := electrum.NewChainClient(
-			electrumClient, cfg.ActiveNetParams.Params,
-			electrumMode.RESTURL, dataDir,
-			electrumMode.MwebP2PPeers,
-		)
-		cc.ChainSource = chainClient
-		log.Debug("Electrum chain client created")
-
-		// Health check verifies we can connect to the Electrum server.
+		// Health check verifies we can connect to an Electrum server
+		// and, in quorum mode, that the servers agree with each other.
 		cc.HealthCheck = func() error {
 			if !electrumClient.IsConnected() {
 				return fmt.Errorf("electrum client not connected")
 			}
-			return nil
+			return electrumClient.QuorumErr()
 		}
 
 	case "nochainbackend":
@@ -803,6 +823,13 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 					err)
 			}
 		}
+
+		if electrumHeaders != nil {
+			if err := electrumHeaders.Close(); err != nil {
+				log.Errorf("Failed to close electrum header "+
+					"store: %v", err)
+			}
+		}
 	}
 
 	// Start fee estimator.
@@ -24,8 +24,13 @@ type ChannelAcceptRequest struct {
 	Node *btcec.PublicKey
 
 	// OpenChanMsg is the actual OpenChannel protocol message that the peer
-	// sent to us.
+	// sent to us. For dual funded channels, it is derived from the
+	// OpenChannel2 message of the peer.
 	OpenChanMsg *lnwire.OpenChannel
+
+	// DualFunded is true if the channel is opened with the dual funding
+	// protocol, which allows us to contribute funds to the channel.
+	DualFunded bool
 }
 
 // ChannelAcceptResponse is a struct containing the response to a request to
@@ -66,6 +71,10 @@ type ChannelAcceptResponse struct {
 	// ZeroConf indicates that the fundee wishes to send min_depth = 0 and
 	// request a zero-conf channel with the counter-party.
 	ZeroConf bool
+
+	// FundingContribution is the amount we contribute to a dual funded
+	// channel.
+	FundingContribution ltcutil.Amount
 }
 
 // NewChannelAcceptResponse is a constructor for a channel accept response,
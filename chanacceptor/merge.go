@@ -18,6 +18,7 @@ const (
 	fieldMinIn           = "min htlc in"
 	fieldInFlightTotal   = "in flight total"
 	fieldUpfrontShutdown = "upfront shutdown"
+	fieldContribution    = "funding contribution"
 )
 
 var (
@@ -166,5 +167,14 @@ func mergeResponse(current,
 		return current, err
 	}
 
+	contribution, err := mergeInt64(
+		fieldContribution, int64(current.FundingContribution),
+		int64(newValue.FundingContribution),
+	)
+	if err != nil {
+		return current, err
+	}
+	current.FundingContribution = ltcutil.Amount(contribution)
+
 	return current, nil
 }
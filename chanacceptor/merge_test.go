@@ -114,6 +114,16 @@ func TestMergeResponse(t *testing.T) {
 			},
 			err: fieldMismatchError(fieldMinDep, 1, 2),
 		},
+		{
+			name: "different contribution",
+			current: ChannelAcceptResponse{
+				FundingContribution: 1,
+			},
+			new: ChannelAcceptResponse{
+				FundingContribution: 2,
+			},
+			err: fieldMismatchError(fieldContribution, 1, 2),
+		},
 		{
 			name: "merge all values",
 			current: ChannelAcceptResponse{
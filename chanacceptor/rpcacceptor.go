@@ -38,6 +38,11 @@ var (
 	errAcceptWithError = errors.New("channel acceptor response accepts " +
 		"channel, but also includes custom error")
 
+	// errContributionNotDualFunded is returned when the response sets a
+	// funding contribution for a channel that isn't dual funded.
+	errContributionNotDualFunded = errors.New("funding contribution " +
+		"set for channel that isn't dual funded")
+
 	// errMaxHtlcTooHigh is returned if our htlc count exceeds the number
 	// hard-set by BOLT 2.
 	errMaxHtlcTooHigh = fmt.Errorf("htlc limit exceeds spec limit of: %v",
@@ -405,6 +410,7 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				CommitmentType:   commitmentType,
 				WantsZeroConf:    wantsZeroConf,
 				WantsScidAlias:   wantsScidAlias,
+				DualFunded:       req.DualFunded,
 			}
 
 			if err := r.send(chanAcceptReq); err != nil {
@@ -429,13 +435,14 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 			// valid, we log our error and proceed to deliver the
 			// rejection.
 			accept, acceptErr, shutdown, err := r.validateAcceptorResponse(
-				requestInfo.request.OpenChanMsg.DustLimit, resp,
+				requestInfo.request.OpenChanMsg.DustLimit,
+				requestInfo.request.DualFunded, resp,
 			)
 			if err != nil {
 				log.Errorf("Invalid acceptor response: %v", err)
 			}
 
-			requestInfo.response <- NewChannelAcceptResponse(
+			acceptResp := NewChannelAcceptResponse(
 				accept, acceptErr, shutdown,
 				uint16(resp.CsvDelay),
 				uint16(resp.MaxHtlcCount),
@@ -445,6 +452,12 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				lnwire.MilliSatoshi(resp.MinHtlcIn),
 				resp.ZeroConf,
 			)
+			if accept {
+				acceptResp.FundingContribution = ltcutil.Amount(
+					resp.FundingContributionSat,
+				)
+			}
+			requestInfo.response <- acceptResp
 
 			// Delete the channel from the acceptRequests map.
 			delete(acceptRequests, pendingID)
@@ -465,8 +478,8 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 // acceptor, returning a boolean indicating whether to accept the channel, an
 // error to send to the peer, and any validation errors that occurred.
 func (r *RPCAcceptor) validateAcceptorResponse(dustLimit ltcutil.Amount,
-	req *lnrpc.ChannelAcceptResponse) (bool, error, lnwire.DeliveryAddress,
-	error) {
+	dualFunded bool, req *lnrpc.ChannelAcceptResponse) (bool, error,
+	lnwire.DeliveryAddress, error) {
 
 	channelStr := hex.EncodeToString(req.PendingChanId)
 
@@ -493,6 +506,16 @@ func (r *RPCAcceptor) validateAcceptorResponse(dustLimit ltcutil.Amount,
 		return false, errChannelRejected, nil, errInsufficientReserve
 	}
 
+	// Only dual funded channels allow us to contribute funds.
+	if req.FundingContributionSat != 0 && !dualFunded {
+		log.Errorf("Funding contribution: %v sat for channel: %v "+
+			"set, but channel isn't dual funded",
+			req.FundingContributionSat, channelStr)
+
+		return false, errChannelRejected, nil,
+			errContributionNotDualFunded
+	}
+
 	// Attempt to parse the upfront shutdown address provided.
 	upfront, err := chancloser.ParseUpfrontShutdownAddress(
 		req.UpfrontShutdown, r.params,
@@ -0,0 +1,267 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/ltcsuite/lnd/kvdb"
+)
+
+var (
+	// chanTypeUpgradesKey can be accessed within the bucket for a channel.
+	// It stores the list of upgrades of the channel type of the channel,
+	// if any.
+	chanTypeUpgradesKey = []byte("chan-type-upgrades-key")
+
+	// proposedChanTypeKey can be accessed within the bucket for a channel.
+	// It stores the channel type we proposed to upgrade the channel to, as
+	// long as the remote peer hasn't accepted or rejected the proposal.
+	proposedChanTypeKey = []byte("proposed-chan-type-key")
+)
+
+// ChanTypeUpgrade records an upgrade of the channel type of a live channel.
+// Commitments below the recorded heights still use the previous channel type,
+// while all commitments from the recorded heights onwards use the new one.
+type ChanTypeUpgrade struct {
+	// PrevType is the channel type of the channel before the upgrade.
+	PrevType ChannelType
+
+	// LocalHeight is the height of the first local commitment that uses
+	// the new channel type.
+	LocalHeight uint64
+
+	// RemoteHeight is the height of the first remote commitment that uses
+	// the new channel type.
+	RemoteHeight uint64
+}
+
+// serializeChanTypeUpgrades writes the list of channel type upgrades to the
+// given writer.
+func serializeChanTypeUpgrades(w io.Writer,
+	upgrades []ChanTypeUpgrade) error {
+
+	if err := WriteElement(w, uint16(len(upgrades))); err != nil {
+		return err
+	}
+
+	for _, upgrade := range upgrades {
+		err := WriteElements(w,
+			upgrade.PrevType, upgrade.LocalHeight,
+			upgrade.RemoteHeight,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeChanTypeUpgrades reads a list of channel type upgrades that was
+// written with serializeChanTypeUpgrades.
+func deserializeChanTypeUpgrades(r io.Reader) ([]ChanTypeUpgrade, error) {
+	var numUpgrades uint16
+	if err := ReadElement(r, &numUpgrades); err != nil {
+		return nil, err
+	}
+
+	upgrades := make([]ChanTypeUpgrade, numUpgrades)
+	for i := range upgrades {
+		err := ReadElements(r,
+			&upgrades[i].PrevType, &upgrades[i].LocalHeight,
+			&upgrades[i].RemoteHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return upgrades, nil
+}
+
+// putChanTypeUpgrades stores the list of channel type upgrades within the
+// channel bucket.
+func putChanTypeUpgrades(chanBucket kvdb.RwBucket,
+	upgrades []ChanTypeUpgrade) error {
+
+	var b bytes.Buffer
+	if err := serializeChanTypeUpgrades(&b, upgrades); err != nil {
+		return err
+	}
+
+	return chanBucket.Put(chanTypeUpgradesKey, b.Bytes())
+}
+
+// fetchChanTypeUpgrades reads the list of channel type upgrades from the
+// channel bucket. If the channel type of the channel was never upgraded, nil
+// is returned.
+func fetchChanTypeUpgrades(chanBucket kvdb.RBucket) ([]ChanTypeUpgrade,
+	error) {
+
+	upgradeBytes := chanBucket.Get(chanTypeUpgradesKey)
+	if upgradeBytes == nil {
+		return nil, nil
+	}
+
+	return deserializeChanTypeUpgrades(bytes.NewReader(upgradeBytes))
+}
+
+// UpgradeChanType switches the channel over to the passed channel type. The
+// new type applies to all commitments following the current ones, and the
+// previous type is recorded so that older commitments can still be
+// reconstructed. The caller must ensure that the channel has no pending
+// updates, so that the next commitment of each side is the first one to use
+// the new type.
+func (c *OpenChannel) UpgradeChanType(newType ChannelType) error {
+	c.Lock()
+	defer c.Unlock()
+
+	upgrade := ChanTypeUpgrade{
+		PrevType:     c.ChanType,
+		LocalHeight:  c.LocalCommitment.CommitHeight + 1,
+		RemoteHeight: c.RemoteCommitment.CommitHeight + 1,
+	}
+
+	// Copy the existing upgrades, so that the in-memory state is only
+	// modified once the upgrade has been written to disk.
+	upgrades := make([]ChanTypeUpgrade, 0, len(c.ChanTypeUpgrades)+1)
+	upgrades = append(upgrades, c.ChanTypeUpgrades...)
+	upgrades = append(upgrades, upgrade)
+
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.ChanType = newType
+		if err := putChanInfo(chanBucket, channel); err != nil {
+			return err
+		}
+
+		if err := putChanTypeUpgrades(chanBucket, upgrades); err != nil {
+			return err
+		}
+
+		// Any proposal of ours is settled by the upgrade.
+		return chanBucket.Delete(proposedChanTypeKey)
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	c.ChanType = newType
+	c.ChanTypeUpgrades = upgrades
+	c.ProposedChanType = fn.None[ChannelType]()
+
+	return nil
+}
+
+// fetchProposedChanType reads the channel type we proposed to upgrade the
+// channel to from the channel bucket, if any.
+func fetchProposedChanType(chanBucket kvdb.RBucket) (fn.Option[ChannelType],
+	error) {
+
+	typeBytes := chanBucket.Get(proposedChanTypeKey)
+	if typeBytes == nil {
+		return fn.None[ChannelType](), nil
+	}
+
+	var chanType ChannelType
+	err := ReadElement(bytes.NewReader(typeBytes), &chanType)
+	if err != nil {
+		return fn.None[ChannelType](), err
+	}
+
+	return fn.Some(chanType), nil
+}
+
+// ProposeChanType records that we proposed to upgrade the channel to the
+// passed channel type. The proposal is kept until the channel is upgraded or
+// the proposal is withdrawn, so that a proposal that was accepted right before
+// a disconnect can still be applied once the remote peer reconnects.
+func (c *OpenChannel) ProposeChanType(newType ChannelType) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var b bytes.Buffer
+	if err := WriteElement(&b, newType); err != nil {
+		return err
+	}
+
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(proposedChanTypeKey, b.Bytes())
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	c.ProposedChanType = fn.Some(newType)
+
+	return nil
+}
+
+// WithdrawChanTypeProposal removes the proposal to upgrade the channel type,
+// as the remote peer rejected it.
+func (c *OpenChannel) WithdrawChanTypeProposal() error {
+	c.Lock()
+	defer c.Unlock()
+
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Delete(proposedChanTypeKey)
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	c.ProposedChanType = fn.None[ChannelType]()
+
+	return nil
+}
+
+// ChanTypeAtHeight returns the channel type of the local or remote commitment
+// at the given height, taking all upgrades of the channel type into account.
+func (c *OpenChannel) ChanTypeAtHeight(local bool,
+	height uint64) ChannelType {
+
+	c.RLock()
+	defer c.RUnlock()
+
+	// The upgrades are ordered from oldest to newest, so the first upgrade
+	// that took effect after the given height tells us the type that was
+	// in use at that height.
+	for _, upgrade := range c.ChanTypeUpgrades {
+		upgradeHeight := upgrade.RemoteHeight
+		if local {
+			upgradeHeight = upgrade.LocalHeight
+		}
+
+		if height < upgradeHeight {
+			return upgrade.PrevType
+		}
+	}
+
+	return c.ChanType
+}
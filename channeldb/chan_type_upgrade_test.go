@@ -0,0 +1,106 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpgradeChanType asserts that upgrades of the channel type of a channel
+// are persisted, and that the channel type of older commitments can still be
+// determined after an upgrade.
+func TestUpgradeChanType(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+	origType := channel.ChanType
+
+	// Without any upgrades, all commitments use the current type.
+	require.Equal(t, origType, channel.ChanTypeAtHeight(true, 0))
+	require.Equal(t, origType, channel.ChanTypeAtHeight(false, 10))
+
+	// Upgrade the channel twice, advancing the commitment heights in
+	// between.
+	tweaklessType := origType | SingleFunderTweaklessBit
+	require.NoError(t, channel.UpgradeChanType(tweaklessType))
+
+	channel.LocalCommitment.CommitHeight = 5
+	channel.RemoteCommitment.CommitHeight = 7
+
+	anchorType := tweaklessType | AnchorOutputsBit | ZeroHtlcTxFeeBit
+	require.NoError(t, channel.UpgradeChanType(anchorType))
+
+	assertUpgrades := func(c *OpenChannel) {
+		t.Helper()
+
+		require.Equal(t, anchorType, c.ChanType)
+		require.Equal(t, []ChanTypeUpgrade{{
+			PrevType:     origType,
+			LocalHeight:  1,
+			RemoteHeight: 1,
+		}, {
+			PrevType:     tweaklessType,
+			LocalHeight:  6,
+			RemoteHeight: 8,
+		}}, c.ChanTypeUpgrades)
+
+		require.Equal(t, origType, c.ChanTypeAtHeight(true, 0))
+		require.Equal(t, origType, c.ChanTypeAtHeight(false, 0))
+		require.Equal(t, tweaklessType, c.ChanTypeAtHeight(true, 5))
+		require.Equal(t, tweaklessType, c.ChanTypeAtHeight(false, 7))
+		require.Equal(t, anchorType, c.ChanTypeAtHeight(true, 6))
+		require.Equal(t, anchorType, c.ChanTypeAtHeight(false, 8))
+	}
+	assertUpgrades(channel)
+
+	// The upgrades must also be restored when the channel is read from
+	// disk.
+	channels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assertUpgrades(channels[0])
+}
+
+// TestProposeChanType asserts that a proposal to upgrade the channel type is
+// persisted until it is either withdrawn or settled by an upgrade.
+func TestProposeChanType(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+	anchorType := channel.ChanType | SingleFunderTweaklessBit |
+		AnchorOutputsBit | ZeroHtlcTxFeeBit
+
+	assertProposal := func(proposal fn.Option[ChannelType]) {
+		t.Helper()
+
+		require.Equal(t, proposal, channel.ProposedChanType)
+
+		channels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+		require.NoError(t, err)
+		require.Len(t, channels, 1)
+		require.Equal(t, proposal, channels[0].ProposedChanType)
+	}
+	assertProposal(fn.None[ChannelType]())
+
+	require.NoError(t, channel.ProposeChanType(anchorType))
+	assertProposal(fn.Some(anchorType))
+
+	require.NoError(t, channel.WithdrawChanTypeProposal())
+	assertProposal(fn.None[ChannelType]())
+
+	// Applying the proposed upgrade settles the proposal.
+	require.NoError(t, channel.ProposeChanType(anchorType))
+	require.NoError(t, channel.UpgradeChanType(anchorType))
+	assertProposal(fn.None[ChannelType]())
+}
@@ -14,6 +14,7 @@ import (
 	"sync"
 
 	"github.com/ltcsuite/lnd/channeldb/models"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/htlcswitch/hop"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/keychain"
@@ -309,6 +310,12 @@ const (
 	// SimpleTaprootFeatureBit indicates that the simple-taproot-chans
 	// feature bit was negotiated during the lifetime of the channel.
 	SimpleTaprootFeatureBit ChannelType = 1 << 10
+
+	// InteractiveFundingBit indicates that the channel was opened with the
+	// dual funding protocol, where both parties may contribute funds and
+	// the funding transaction is constructed interactively. Both parties
+	// know the funding transaction of such a channel.
+	InteractiveFundingBit ChannelType = 1 << 11
 )
 
 // IsSingleFunder returns true if the channel type if one of the known single
@@ -379,6 +386,12 @@ func (c ChannelType) IsTaproot() bool {
 	return c&SimpleTaprootFeatureBit == SimpleTaprootFeatureBit
 }
 
+// HasInteractiveFunding returns true if the funding transaction of the
+// channel was constructed interactively with the dual funding protocol.
+func (c ChannelType) HasInteractiveFunding() bool {
+	return c&InteractiveFundingBit == InteractiveFundingBit
+}
+
 // ChannelConstraints represents a set of constraints meant to allow a node to
 // limit their exposure, enact flow control and ensure that all HTLCs are
 // economically relevant. This struct will be mirrored for both sides of the
@@ -597,6 +610,11 @@ var (
 	// ChanStatusRemoteCloseInitiator indicates that the remote node
 	// initiated closing the channel.
 	ChanStatusRemoteCloseInitiator ChannelStatus = 1 << 6
+
+	// ChanStatusSplicing indicates that both peers signed the commitments
+	// spending the funding output of a splice of the channel. The channel
+	// can't be updated until the splice has completed.
+	ChanStatusSplicing ChannelStatus = 1 << 7
 )
 
 // chanStatusStrings maps a ChannelStatus to a human friendly string that
@@ -610,6 +628,7 @@ var chanStatusStrings = map[ChannelStatus]string{
 	ChanStatusCoopBroadcasted:      "ChanStatusCoopBroadcasted",
 	ChanStatusLocalCloseInitiator:  "ChanStatusLocalCloseInitiator",
 	ChanStatusRemoteCloseInitiator: "ChanStatusRemoteCloseInitiator",
+	ChanStatusSplicing:             "ChanStatusSplicing",
 }
 
 // orderedChanStatusFlags is an in-order list of all that channel status flags.
@@ -621,6 +640,7 @@ var orderedChanStatusFlags = []ChannelStatus{
 	ChanStatusCoopBroadcasted,
 	ChanStatusLocalCloseInitiator,
 	ChanStatusRemoteCloseInitiator,
+	ChanStatusSplicing,
 }
 
 // String returns a human-readable representation of the ChannelStatus.
@@ -679,6 +699,15 @@ type OpenChannel struct {
 	// ChanType denotes which type of channel this is.
 	ChanType ChannelType
 
+	// ChanTypeUpgrades is the list of upgrades of the channel type that
+	// were applied to the live channel, ordered from oldest to newest. It
+	// is used to determine the channel type of older commitments.
+	ChanTypeUpgrades []ChanTypeUpgrade
+
+	// ProposedChanType is the channel type we proposed to upgrade the
+	// channel to, if the remote peer hasn't answered the proposal yet.
+	ProposedChanType fn.Option[ChannelType]
+
 	// ChainHash is a hash which represents the blockchain that this
 	// channel will be opened within. This value is typically the genesis
 	// hash. In the case that the original chain went through a contentious
@@ -1293,6 +1322,117 @@ func (c *OpenChannel) MarkRealScid(realScid lnwire.ShortChannelID) error {
 	return nil
 }
 
+// MarkFundingTxSigned replaces the funding transaction of a pending dual
+// funded channel with its fully signed version, once both parties exchanged
+// the signatures for their inputs. The funding transaction is persisted
+// unsigned when the channel is created, as the signatures are only exchanged
+// after both parties committed to the initial commitment transactions.
+func (c *OpenChannel) MarkFundingTxSigned(fundingTx *wire.MsgTx) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.ChanType.HasInteractiveFunding() {
+		return fmt.Errorf("funding tx of ChannelPoint(%v) wasn't "+
+			"constructed interactively", c.FundingOutpoint)
+	}
+
+	if fundingTx.TxHash() != c.FundingOutpoint.Hash {
+		return fmt.Errorf("funding tx %v doesn't create "+
+			"ChannelPoint(%v)", fundingTx.TxHash(),
+			c.FundingOutpoint)
+	}
+
+	if err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel.FundingTxn = fundingTx
+
+		return putOpenChannel(chanBucket, channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.FundingTxn = fundingTx
+
+	return nil
+}
+
+// SyncFundingReplacement persists a new pending channel that replaces the
+// funding transaction of this pending dual funded channel with one paying a
+// higher fee. The new channel shares all parameters with this one, except for
+// the funding outpoint and the initial commitments spending it. Both channels
+// remain pending until one of the funding transactions confirms.
+func (c *OpenChannel) SyncFundingReplacement(fundingTx *wire.MsgTx,
+	fundingPoint wire.OutPoint, localCommit,
+	remoteCommit *ChannelCommitment) (*OpenChannel, error) {
+
+	c.RLock()
+	defer c.RUnlock()
+
+	if !c.ChanType.HasInteractiveFunding() || !c.IsPending {
+		return nil, fmt.Errorf("funding tx of ChannelPoint(%v) can't "+
+			"be replaced", c.FundingOutpoint)
+	}
+
+	if fundingTx.TxHash() != fundingPoint.Hash {
+		return nil, fmt.Errorf("funding tx %v doesn't create "+
+			"ChannelPoint(%v)", fundingTx.TxHash(), fundingPoint)
+	}
+
+	var replacement *OpenChannel
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		// We read the channel from disk instead of copying the
+		// in-memory state, so the replacement is a fully independent
+		// instance.
+		channel, err := fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel.Db = c.Db
+		channel.FundingOutpoint = fundingPoint
+		channel.FundingTxn = fundingTx
+		channel.LocalCommitment = *localCommit
+		channel.RemoteCommitment = *remoteCommit
+
+		if err := channel.fullSync(tx); err != nil {
+			return err
+		}
+
+		replacement = channel
+
+		return nil
+	}, func() {
+		replacement = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replacement, nil
+}
+
 // MarkScidAliasNegotiated adds ScidAliasFeatureBit to ChanType in-memory and
 // in the database.
 func (c *OpenChannel) MarkScidAliasNegotiated() error {
@@ -1805,6 +1945,16 @@ func putOpenChannel(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 		}
 	}
 
+	// If the channel type was upgraded, we'll also store the previous
+	// types of the channel.
+	if len(channel.ChanTypeUpgrades) > 0 {
+		err := putChanTypeUpgrades(chanBucket, channel.ChanTypeUpgrades)
+		if err != nil {
+			return fmt.Errorf("unable to store chan type "+
+				"upgrades: %v", err)
+		}
+	}
+
 	// Finally, we'll write out the revocation state for both parties
 	// within a distinct key space.
 	if err := putChanRevocationState(chanBucket, channel); err != nil {
@@ -1847,6 +1997,20 @@ func fetchOpenChannel(chanBucket kvdb.RBucket,
 		channel.ThawHeight = thawHeight
 	}
 
+	upgrades, err := fetchChanTypeUpgrades(chanBucket)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch chan type upgrades: "+
+			"%v", err)
+	}
+	channel.ChanTypeUpgrades = upgrades
+
+	proposedType, err := fetchProposedChanType(chanBucket)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch proposed chan type: "+
+			"%v", err)
+	}
+	channel.ProposedChanType = proposedType
+
 	// Finally, we'll retrieve the current revocation state so we can
 	// properly
 	if err := fetchChanRevocationState(chanBucket, channel); err != nil {
@@ -2224,6 +2388,46 @@ type HTLC struct {
 	ExtraData []byte
 }
 
+// BlindingPoint returns the blinding point that was transmitted with the HTLC
+// in its extra data, or nil if the HTLC isn't part of a blinded route.
+func (h *HTLC) BlindingPoint() (*btcec.PublicKey, error) {
+	if len(h.ExtraData) == 0 {
+		return nil, nil
+	}
+
+	var (
+		blindingPoint lnwire.BlindingPoint
+		tlvData       = lnwire.ExtraOpaqueData(h.ExtraData)
+	)
+	typeMap, err := tlvData.ExtractRecords(&blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := typeMap[lnwire.BlindingPointRecordType]
+	if !ok || val != nil {
+		return nil, nil
+	}
+
+	return (*btcec.PublicKey)(&blindingPoint), nil
+}
+
+// CustomRecords returns the custom records that were transmitted with the
+// HTLC in its extra data, or nil if there are none.
+func (h *HTLC) CustomRecords() (lnwire.CustomRecords, error) {
+	if len(h.ExtraData) == 0 {
+		return nil, nil
+	}
+
+	tlvData := lnwire.ExtraOpaqueData(h.ExtraData)
+	typeMap, err := tlvData.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	return lnwire.ParseCustomRecords(typeMap), nil
+}
+
 // SerializeHtlcs writes out the passed set of HTLC's into the passed writer
 // using the current default on-disk serialization format.
 //
@@ -3804,12 +4008,14 @@ func writeChanConfig(b io.Writer, c *ChannelConfig) error {
 }
 
 // fundingTxPresent returns true if expect the funding transcation to be found
-// on disk or already populated within the passed open channel struct.
+// on disk or already populated within the passed open channel struct. Besides
+// the initiator, both parties of a dual funded channel know the funding
+// transaction.
 func fundingTxPresent(channel *OpenChannel) bool {
 	chanType := channel.ChanType
 
 	return chanType.IsSingleFunder() && chanType.HasFundingTx() &&
-		channel.IsInitiator &&
+		(channel.IsInitiator || chanType.HasInteractiveFunding()) &&
 		!channel.hasChanStatus(ChanStatusRestored)
 }
 
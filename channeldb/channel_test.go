@@ -969,6 +969,84 @@ func TestFetchPendingChannels(t *testing.T) {
 	}
 }
 
+// TestInteractiveFundingTx tests that the funding transaction of a pending
+// dual funded channel can be marked as signed, and replaced by a new
+// transaction that is persisted as a separate pending channel.
+func TestInteractiveFundingTx(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	fundingTx := channels.TestFundingTx.Copy()
+	fundingPoint := wire.OutPoint{Hash: fundingTx.TxHash()}
+	channel := createTestChannel(
+		t, cdb, fundingPointOption(fundingPoint),
+		func(params *testChannelParams) {
+			params.channel.ChanType = SingleFunderBit |
+				InteractiveFundingBit
+			params.channel.FundingTxn = fundingTx
+		},
+	)
+
+	// A transaction that doesn't create the channel point can't be used
+	// as the signed funding transaction.
+	otherTx := fundingTx.Copy()
+	otherTx.LockTime++
+	require.Error(t, channel.MarkFundingTxSigned(otherTx))
+
+	// Adding the witnesses doesn't change the txid, so the signed funding
+	// transaction is persisted.
+	signedTx := fundingTx.Copy()
+	signedTx.TxIn[0].Witness = wire.TxWitness{{1, 2, 3}}
+	require.NoError(t, channel.MarkFundingTxSigned(signedTx))
+
+	pendingChannels, err := cdb.FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, pendingChannels, 1)
+	require.Equal(t, signedTx, pendingChannels[0].FundingTxn)
+
+	// Replace the funding transaction, which creates a second pending
+	// channel spending the new funding output.
+	replacementPoint := wire.OutPoint{Hash: otherTx.TxHash(), Index: 1}
+	localCommit := channel.LocalCommitment
+	localCommit.CommitSig = bytes.Repeat([]byte{2}, 71)
+	remoteCommit := channel.RemoteCommitment
+	remoteCommit.CommitSig = bytes.Repeat([]byte{3}, 71)
+
+	_, err = channel.SyncFundingReplacement(
+		otherTx, fundingPoint, &localCommit, &remoteCommit,
+	)
+	require.Error(t, err)
+
+	replacement, err := channel.SyncFundingReplacement(
+		otherTx, replacementPoint, &localCommit, &remoteCommit,
+	)
+	require.NoError(t, err)
+	require.Equal(t, replacementPoint, replacement.FundingOutpoint)
+
+	pendingChannels, err = cdb.FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, pendingChannels, 2)
+
+	for _, pending := range pendingChannels {
+		if pending.FundingOutpoint != replacementPoint {
+			require.Equal(t, fundingPoint, pending.FundingOutpoint)
+			continue
+		}
+
+		require.Equal(t, otherTx, pending.FundingTxn)
+		require.Equal(t, localCommit, pending.LocalCommitment)
+		require.Equal(t, remoteCommit, pending.RemoteCommitment)
+		require.Equal(t, channel.Capacity, pending.Capacity)
+		require.Equal(t, channel.ChanType, pending.ChanType)
+		require.Equal(t, channel.LocalChanCfg, pending.LocalChanCfg)
+		require.Equal(t, channel.RemoteChanCfg, pending.RemoteChanCfg)
+	}
+}
+
 func TestFetchClosedChannels(t *testing.T) {
 	t.Parallel()
 
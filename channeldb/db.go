@@ -397,11 +397,18 @@ func CreateWithBackend(backend kvdb.Backend,
 	// Set the parent pointer (only used in tests).
 	chanDB.channelStateDB.parent = chanDB
 
+	// A dry run of the migrations must not copy the graph over to the
+	// native SQL graph store, as that can't be rolled back.
+	sqlGraphStore := opts.sqlGraphStore
+	if opts.dryRun {
+		sqlGraphStore = nil
+	}
+
 	var err error
 	chanDB.graph, err = NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		opts.UseGraphCache, opts.NoMigration,
+		opts.UseGraphCache, opts.NoMigration, sqlGraphStore,
 	)
 	if err != nil {
 		return nil, err
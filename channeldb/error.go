@@ -100,10 +100,20 @@ var (
 	ErrEdgePolicyOptionalFieldNotFound = fmt.Errorf("optional field not " +
 		"present")
 
+	// ErrParsingExtraTLVBytes is returned if the extra opaque data of a
+	// graph message isn't a valid TLV stream. The SQL graph store only
+	// stores extra opaque data as individual TLV records.
+	ErrParsingExtraTLVBytes = fmt.Errorf("unable to parse extra opaque " +
+		"data as TLV stream")
+
 	// ErrChanAlreadyExists is return when the caller attempts to create a
 	// channel with a channel point that is already present in the
 	// database.
 	ErrChanAlreadyExists = fmt.Errorf("channel already exists")
+
+	// ErrNoSplice is returned when the splice of a channel is requested,
+	// but the channel isn't being spliced.
+	ErrNoSplice = fmt.Errorf("no splice in progress for channel")
 )
 
 // ErrTooManyExtraOpaqueBytes creates an error which should be returned if the
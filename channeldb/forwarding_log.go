@@ -2,12 +2,16 @@ package channeldb
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"sort"
 	"time"
 
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/kvdb"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/lnd/tlv"
 	"github.com/ltcsuite/ltcwallet/walletdb"
 )
 
@@ -21,14 +25,16 @@ var (
 )
 
 const (
-	// forwardingEventSize is the size of a forwarding event. The breakdown
-	// is as follows:
+	// forwardingEventSize is the size of the fixed part of a forwarding
+	// event. The breakdown is as follows:
 	//
 	//  * 8 byte incoming chan ID || 8 byte outgoing chan ID || 8 byte value in
 	//    || 8 byte value out
 	//
 	// From the value in and value out, callers can easily compute the
-	// total fee extract from a forwarding event.
+	// total fee extract from a forwarding event. The fixed part is followed
+	// by an optional TLV stream which carries the remaining details of the
+	// event.
 	forwardingEventSize = 32
 
 	// MaxResponseEvents is the max number of forwarding events that will
@@ -39,6 +45,113 @@ const (
 	MaxResponseEvents = 50000
 )
 
+const (
+	// fwdEventIncomingHtlcIDType is the TLV type of the incoming HTLC ID.
+	fwdEventIncomingHtlcIDType tlv.Type = 0
+
+	// fwdEventOutgoingHtlcIDType is the TLV type of the outgoing HTLC ID.
+	fwdEventOutgoingHtlcIDType tlv.Type = 1
+
+	// fwdEventAddedTimeType is the TLV type of the time the incoming HTLC
+	// was added, in nano seconds since the unix epoch.
+	fwdEventAddedTimeType tlv.Type = 2
+
+	// fwdEventOutcomeType is the TLV type of the outcome of the forward.
+	fwdEventOutcomeType tlv.Type = 3
+
+	// fwdEventFailureSourceType is the TLV type of the failure source.
+	fwdEventFailureSourceType tlv.Type = 4
+
+	// fwdEventFailureCodeType is the TLV type of the wire failure code.
+	fwdEventFailureCodeType tlv.Type = 5
+
+	// fwdEventFailureReasonType is the TLV type of the failure reason.
+	fwdEventFailureReasonType tlv.Type = 6
+
+	// fwdEventIncomingPeerType is the TLV type of the incoming peer.
+	fwdEventIncomingPeerType tlv.Type = 7
+
+	// fwdEventOutgoingPeerType is the TLV type of the outgoing peer.
+	fwdEventOutgoingPeerType tlv.Type = 8
+
+	// fwdEventIncomingAliasType is the TLV type of the alias of the
+	// incoming peer.
+	fwdEventIncomingAliasType tlv.Type = 9
+
+	// fwdEventOutgoingAliasType is the TLV type of the alias of the
+	// outgoing peer.
+	fwdEventOutgoingAliasType tlv.Type = 10
+)
+
+// ForwardingEventOutcome describes how a forwarded HTLC was resolved.
+type ForwardingEventOutcome uint8
+
+const (
+	// ForwardingEventSettled is the outcome of a forward that was settled
+	// by the downstream peer. All events written by older versions of the
+	// forwarding log have this outcome.
+	ForwardingEventSettled ForwardingEventOutcome = 0
+
+	// ForwardingEventFailed is the outcome of a forward that was sent to
+	// the outgoing channel, but failed back afterwards.
+	ForwardingEventFailed ForwardingEventOutcome = 1
+
+	// ForwardingEventRejected is the outcome of a forward that we rejected
+	// locally before it was sent to the outgoing channel.
+	ForwardingEventRejected ForwardingEventOutcome = 2
+)
+
+// String returns a human readable version of the outcome.
+func (o ForwardingEventOutcome) String() string {
+	switch o {
+	case ForwardingEventSettled:
+		return "settled"
+
+	case ForwardingEventFailed:
+		return "failed"
+
+	case ForwardingEventRejected:
+		return "rejected"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(o))
+	}
+}
+
+// ForwardingFailureSource describes where the failure of a forward originated.
+type ForwardingFailureSource uint8
+
+const (
+	// ForwardingFailureSourceNone is used for forwards that didn't fail.
+	ForwardingFailureSourceNone ForwardingFailureSource = 0
+
+	// ForwardingFailureSourceLocal is used for failures that were created
+	// by our own node, so their reason is known.
+	ForwardingFailureSourceLocal ForwardingFailureSource = 1
+
+	// ForwardingFailureSourceRemote is used for failures that were
+	// returned by a downstream node. As the failure is encrypted for the
+	// sender of the HTLC, its reason isn't known to us.
+	ForwardingFailureSourceRemote ForwardingFailureSource = 2
+)
+
+// String returns a human readable version of the failure source.
+func (f ForwardingFailureSource) String() string {
+	switch f {
+	case ForwardingFailureSourceNone:
+		return "none"
+
+	case ForwardingFailureSourceLocal:
+		return "local"
+
+	case ForwardingFailureSourceRemote:
+		return "remote"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(f))
+	}
+}
+
 // ForwardingLog returns an instance of the ForwardingLog object backed by the
 // target database instance.
 func (d *DB) ForwardingLog() *ForwardingLog {
@@ -60,9 +173,11 @@ type ForwardingLog struct {
 // ForwardingEvent is an event in the forwarding log's time series. Each
 // forwarding event logs the creation and tear-down of a payment circuit. A
 // circuit is created once an incoming HTLC has been fully forwarded, and
-// destroyed once the payment has been settled.
+// destroyed once the payment has been settled or failed. Forwards that were
+// rejected by our node before reaching the outgoing channel are logged as
+// well.
 type ForwardingEvent struct {
-	// Timestamp is the settlement time of this payment circuit.
+	// Timestamp is the resolution time of this payment circuit.
 	Timestamp time.Time
 
 	// IncomingChanID is the incoming channel ID of the payment circuit.
@@ -78,15 +193,151 @@ type ForwardingEvent struct {
 	// AmtOut is the amount of the outgoing HTLC. Subtracting the incoming
 	// amount from this gives the total fees for this payment circuit.
 	AmtOut lnwire.MilliSatoshi
+
+	// IncomingHtlcID is the ID of the incoming HTLC. It is unknown for
+	// events written by older versions of the forwarding log.
+	IncomingHtlcID fn.Option[uint64]
+
+	// OutgoingHtlcID is the ID of the outgoing HTLC. It is unknown for
+	// events written by older versions of the forwarding log, and for
+	// forwards that never made it to the outgoing channel.
+	OutgoingHtlcID fn.Option[uint64]
+
+	// AddedTime is the time the incoming HTLC was handed to the switch for
+	// forwarding. It is the zero time if unknown, which is the case for
+	// HTLCs that were added before a restart.
+	AddedTime time.Time
+
+	// Outcome describes how the forward was resolved.
+	Outcome ForwardingEventOutcome
+
+	// FailureSource describes where the failure of a failed or rejected
+	// forward originated.
+	FailureSource ForwardingFailureSource
+
+	// FailureCode is the wire failure code of a failure that was created
+	// by our node. It is zero if unknown.
+	FailureCode lnwire.FailCode
+
+	// FailureReason describes the failure of a forward that was failed or
+	// rejected by our node in more detail than its failure code. It is
+	// taken from the fixed set of failure details of the switch rather
+	// than being a free-form error, and is empty if unknown.
+	FailureReason string
+
+	// IncomingPeer is the public key of the peer of the incoming channel,
+	// if known.
+	IncomingPeer fn.Option[route.Vertex]
+
+	// OutgoingPeer is the public key of the peer of the outgoing channel,
+	// if known.
+	OutgoingPeer fn.Option[route.Vertex]
+
+	// IncomingPeerAlias is the alias of the peer of the incoming channel
+	// at the time the event was logged.
+	IncomingPeerAlias string
+
+	// OutgoingPeerAlias is the alias of the peer of the outgoing channel
+	// at the time the event was logged.
+	OutgoingPeerAlias string
+}
+
+// ResolutionLatency returns the time it took to resolve the forward after the
+// incoming HTLC was added. Zero is returned if the add time is unknown.
+func (f *ForwardingEvent) ResolutionLatency() time.Duration {
+	if f.AddedTime.IsZero() {
+		return 0
+	}
+
+	return f.Timestamp.Sub(f.AddedTime)
 }
 
 // encodeForwardingEvent writes out the target forwarding event to the passed
 // io.Writer, using the expected DB format. Note that the timestamp isn't
 // serialized as this will be the key value within the bucket.
 func encodeForwardingEvent(w io.Writer, f *ForwardingEvent) error {
-	return WriteElements(
+	err := WriteElements(
 		w, f.IncomingChanID, f.OutgoingChanID, f.AmtIn, f.AmtOut,
 	)
+	if err != nil {
+		return err
+	}
+
+	// The remaining details are written as TLV records that are only
+	// present if set, so that an event without any details is encoded in
+	// the same way as by older versions of the forwarding log.
+	var records []tlv.Record
+	f.IncomingHtlcID.WhenSome(func(id uint64) {
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventIncomingHtlcIDType, &id,
+		))
+	})
+	f.OutgoingHtlcID.WhenSome(func(id uint64) {
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventOutgoingHtlcIDType, &id,
+		))
+	})
+	if !f.AddedTime.IsZero() {
+		addedTime := uint64(f.AddedTime.UnixNano())
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventAddedTimeType, &addedTime,
+		))
+	}
+	if f.Outcome != ForwardingEventSettled {
+		outcome := uint8(f.Outcome)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventOutcomeType, &outcome,
+		))
+	}
+	if f.FailureSource != ForwardingFailureSourceNone {
+		source := uint8(f.FailureSource)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventFailureSourceType, &source,
+		))
+	}
+	if f.FailureCode != 0 {
+		code := uint16(f.FailureCode)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventFailureCodeType, &code,
+		))
+	}
+	if f.FailureReason != "" {
+		reason := []byte(f.FailureReason)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventFailureReasonType, &reason,
+		))
+	}
+	f.IncomingPeer.WhenSome(func(peer route.Vertex) {
+		pubKey := [33]byte(peer)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventIncomingPeerType, &pubKey,
+		))
+	})
+	f.OutgoingPeer.WhenSome(func(peer route.Vertex) {
+		pubKey := [33]byte(peer)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventOutgoingPeerType, &pubKey,
+		))
+	})
+	if f.IncomingPeerAlias != "" {
+		alias := []byte(f.IncomingPeerAlias)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventIncomingAliasType, &alias,
+		))
+	}
+	if f.OutgoingPeerAlias != "" {
+		alias := []byte(f.OutgoingPeerAlias)
+		records = append(records, tlv.MakePrimitiveRecord(
+			fwdEventOutgoingAliasType, &alias,
+		))
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
 }
 
 // decodeForwardingEvent attempts to decode the raw bytes of a serialized
@@ -94,9 +345,85 @@ func encodeForwardingEvent(w io.Writer, f *ForwardingEvent) error {
 // won't be decoded, as the caller is expected to set this due to the bucket
 // structure of the forwarding log.
 func decodeForwardingEvent(r io.Reader, f *ForwardingEvent) error {
-	return ReadElements(
+	err := ReadElements(
 		r, &f.IncomingChanID, &f.OutgoingChanID, &f.AmtIn, &f.AmtOut,
 	)
+	if err != nil {
+		return err
+	}
+
+	var (
+		incomingHtlcID, outgoingHtlcID, addedTime uint64
+		outcome, failureSource                    uint8
+		failureCode                               uint16
+		failureReason                             []byte
+		incomingPeer, outgoingPeer                [33]byte
+		incomingAlias, outgoingAlias              []byte
+	)
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(
+			fwdEventIncomingHtlcIDType, &incomingHtlcID,
+		),
+		tlv.MakePrimitiveRecord(
+			fwdEventOutgoingHtlcIDType, &outgoingHtlcID,
+		),
+		tlv.MakePrimitiveRecord(fwdEventAddedTimeType, &addedTime),
+		tlv.MakePrimitiveRecord(fwdEventOutcomeType, &outcome),
+		tlv.MakePrimitiveRecord(
+			fwdEventFailureSourceType, &failureSource,
+		),
+		tlv.MakePrimitiveRecord(fwdEventFailureCodeType, &failureCode),
+		tlv.MakePrimitiveRecord(
+			fwdEventFailureReasonType, &failureReason,
+		),
+		tlv.MakePrimitiveRecord(
+			fwdEventIncomingPeerType, &incomingPeer,
+		),
+		tlv.MakePrimitiveRecord(
+			fwdEventOutgoingPeerType, &outgoingPeer,
+		),
+		tlv.MakePrimitiveRecord(
+			fwdEventIncomingAliasType, &incomingAlias,
+		),
+		tlv.MakePrimitiveRecord(
+			fwdEventOutgoingAliasType, &outgoingAlias,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Events written by older versions of the forwarding log don't have
+	// a TLV stream at all, which decodes to an empty set of types.
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := parsedTypes[fwdEventIncomingHtlcIDType]; ok {
+		f.IncomingHtlcID = fn.Some(incomingHtlcID)
+	}
+	if _, ok := parsedTypes[fwdEventOutgoingHtlcIDType]; ok {
+		f.OutgoingHtlcID = fn.Some(outgoingHtlcID)
+	}
+	if _, ok := parsedTypes[fwdEventAddedTimeType]; ok {
+		f.AddedTime = time.Unix(0, int64(addedTime))
+	}
+	if _, ok := parsedTypes[fwdEventIncomingPeerType]; ok {
+		f.IncomingPeer = fn.Some(route.Vertex(incomingPeer))
+	}
+	if _, ok := parsedTypes[fwdEventOutgoingPeerType]; ok {
+		f.OutgoingPeer = fn.Some(route.Vertex(outgoingPeer))
+	}
+
+	f.Outcome = ForwardingEventOutcome(outcome)
+	f.FailureSource = ForwardingFailureSource(failureSource)
+	f.FailureCode = lnwire.FailCode(failureCode)
+	f.FailureReason = string(failureReason)
+	f.IncomingPeerAlias = string(incomingAlias)
+	f.OutgoingPeerAlias = string(outgoingAlias)
+
+	return nil
 }
 
 // AddForwardingEvents adds a series of forwarding events to the database.
@@ -200,6 +527,57 @@ type ForwardingEventQuery struct {
 
 	// NumMaxEvents is the max number of events to return.
 	NumMaxEvents uint32
+
+	// IncomingChanIDs restricts the query to events with one of the given
+	// incoming channels. All incoming channels match if empty.
+	IncomingChanIDs fn.Set[lnwire.ShortChannelID]
+
+	// OutgoingChanIDs restricts the query to events with one of the given
+	// outgoing channels. All outgoing channels match if empty.
+	OutgoingChanIDs fn.Set[lnwire.ShortChannelID]
+
+	// Peers restricts the query to events of which either the incoming or
+	// the outgoing peer is one of the given peers. All peers match if
+	// empty.
+	Peers fn.Set[route.Vertex]
+
+	// Outcomes restricts the query to events with one of the given
+	// outcomes. As the forwarding log used to only contain settled
+	// forwards, only those are returned if empty.
+	Outcomes fn.Set[ForwardingEventOutcome]
+}
+
+// matches returns true if the given event satisfies the filters of the query.
+// The time slice of the query isn't checked.
+func (q *ForwardingEventQuery) matches(event *ForwardingEvent) bool {
+	if len(q.IncomingChanIDs) > 0 &&
+		!q.IncomingChanIDs.Contains(event.IncomingChanID) {
+
+		return false
+	}
+
+	if len(q.OutgoingChanIDs) > 0 &&
+		!q.OutgoingChanIDs.Contains(event.OutgoingChanID) {
+
+		return false
+	}
+
+	if len(q.Peers) > 0 {
+		peerMatches := func(peer route.Vertex) bool {
+			return q.Peers.Contains(peer)
+		}
+		if !fn.MapOptionZ(event.IncomingPeer, peerMatches) &&
+			!fn.MapOptionZ(event.OutgoingPeer, peerMatches) {
+
+			return false
+		}
+	}
+
+	if len(q.Outcomes) == 0 {
+		return event.Outcome == ForwardingEventSettled
+	}
+
+	return q.Outcomes.Contains(event.Outcome)
 }
 
 // ForwardingLogTimeSlice is the response to a forwarding query. It includes
@@ -264,6 +642,26 @@ func (f *ForwardingLog) Query(q ForwardingEventQuery) (ForwardingLogTimeSlice, e
 				return nil
 			}
 
+			currentTime := time.Unix(
+				0, int64(byteOrder.Uint64(timestamp)),
+			)
+
+			// Each key holds a single event which we'll need to
+			// decode to check whether it matches the filters of
+			// the query. Only matching events count towards the
+			// index offset.
+			var event ForwardingEvent
+			err := decodeForwardingEvent(
+				bytes.NewReader(events), &event,
+			)
+			if err != nil {
+				return err
+			}
+
+			if !q.matches(&event) {
+				continue
+			}
+
 			// If we're not yet past the user defined offset, then
 			// we'll continue to seek forward.
 			if recordsToSkip > 0 {
@@ -271,27 +669,14 @@ func (f *ForwardingLog) Query(q ForwardingEventQuery) (ForwardingLogTimeSlice, e
 				continue
 			}
 
-			currentTime := time.Unix(
-				0, int64(byteOrder.Uint64(timestamp)),
-			)
-
 			// At this point, we've skipped enough records to start
 			// to collate our query. For each record, we'll
 			// increment the final record offset so the querier can
 			// utilize pagination to seek further.
-			readBuf := bytes.NewReader(events)
-			for readBuf.Len() != 0 {
-				var event ForwardingEvent
-				err := decodeForwardingEvent(readBuf, &event)
-				if err != nil {
-					return err
-				}
-
-				event.Timestamp = currentTime
-				resp.ForwardingEvents = append(resp.ForwardingEvents, event)
-
-				recordOffset++
-			}
+			event.Timestamp = currentTime
+			resp.ForwardingEvents = append(resp.ForwardingEvents, event)
+
+			recordOffset++
 		}
 
 		return nil
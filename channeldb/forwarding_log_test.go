@@ -1,13 +1,16 @@
 package channeldb
 
 import (
+	"bytes"
 	"math/rand"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -360,3 +363,260 @@ func TestForwardingLogStoreEvent(t *testing.T) {
 		}
 	}
 }
+
+// TestForwardingLogExtendedEvents tests that the details of settled, failed
+// and rejected forwards survive a round trip through the forwarding log, and
+// that events without any details are still encoded in the legacy format.
+func TestForwardingLogExtendedEvents(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test db")
+
+	log := ForwardingLog{
+		db: db,
+	}
+
+	// An event without any of the extended details must be encoded in
+	// exactly the same way as by older versions of the forwarding log.
+	legacyEvent := ForwardingEvent{
+		IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+		OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+		AmtIn:          2000,
+		AmtOut:         1000,
+	}
+	var b bytes.Buffer
+	require.NoError(t, encodeForwardingEvent(&b, &legacyEvent))
+	require.Len(t, b.Bytes(), forwardingEventSize)
+
+	peerIn := route.Vertex{1}
+	peerOut := route.Vertex{2}
+	timestamp := time.Unix(0, 1_000_000)
+	events := []ForwardingEvent{
+		{
+			Timestamp:         timestamp,
+			IncomingChanID:    lnwire.NewShortChanIDFromInt(1),
+			OutgoingChanID:    lnwire.NewShortChanIDFromInt(2),
+			AmtIn:             2000,
+			AmtOut:            1000,
+			IncomingHtlcID:    fn.Some(uint64(0)),
+			OutgoingHtlcID:    fn.Some(uint64(7)),
+			AddedTime:         timestamp.Add(-time.Second),
+			IncomingPeer:      fn.Some(peerIn),
+			OutgoingPeer:      fn.Some(peerOut),
+			IncomingPeerAlias: "alice",
+			OutgoingPeerAlias: "bob",
+		},
+		{
+			Timestamp:      timestamp.Add(time.Second),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+			AmtIn:          3000,
+			AmtOut:         2000,
+			IncomingHtlcID: fn.Some(uint64(1)),
+			OutgoingHtlcID: fn.Some(uint64(8)),
+			Outcome:        ForwardingEventFailed,
+			FailureSource:  ForwardingFailureSourceRemote,
+			IncomingPeer:   fn.Some(peerIn),
+			OutgoingPeer:   fn.Some(peerOut),
+		},
+		{
+			Timestamp:      timestamp.Add(2 * time.Second),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(3),
+			AmtIn:          4000,
+			AmtOut:         3000,
+			IncomingHtlcID: fn.Some(uint64(2)),
+			AddedTime:      timestamp,
+			Outcome:        ForwardingEventRejected,
+			FailureSource:  ForwardingFailureSourceLocal,
+			FailureCode:    lnwire.CodeTemporaryChannelFailure,
+			FailureReason:  "insufficient bandwidth to route htlc",
+			IncomingPeer:   fn.Some(peerIn),
+		},
+	}
+	require.NoError(t, log.AddForwardingEvents(events))
+
+	require.Equal(t, time.Second, events[0].ResolutionLatency())
+	require.Zero(t, events[1].ResolutionLatency())
+
+	query := ForwardingEventQuery{
+		StartTime:    time.Unix(0, 0),
+		EndTime:      timestamp.Add(time.Hour),
+		NumMaxEvents: 10,
+		Outcomes: fn.NewSet(
+			ForwardingEventSettled, ForwardingEventFailed,
+			ForwardingEventRejected,
+		),
+	}
+	timeSlice, err := log.Query(query)
+	require.NoError(t, err)
+	require.Equal(t, events, timeSlice.ForwardingEvents)
+	require.EqualValues(t, len(events), timeSlice.LastIndexOffset)
+}
+
+// TestForwardingLogQueryFilters tests that events can be filtered by channel,
+// peer and outcome, and that the index offset only counts matching events.
+func TestForwardingLogQueryFilters(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test db")
+
+	log := ForwardingLog{
+		db: db,
+	}
+
+	var (
+		chanA = lnwire.NewShortChanIDFromInt(1)
+		chanB = lnwire.NewShortChanIDFromInt(2)
+		chanC = lnwire.NewShortChanIDFromInt(3)
+		peerA = route.Vertex{1}
+		peerB = route.Vertex{2}
+		peerC = route.Vertex{3}
+	)
+	outcomes := []ForwardingEventOutcome{
+		ForwardingEventSettled, ForwardingEventFailed,
+		ForwardingEventRejected,
+	}
+
+	// We'll create events going from channel A to B and from channel B to
+	// C, alternating between all outcomes.
+	timestamp := time.Unix(1234, 0)
+	var events []ForwardingEvent
+	for i := 0; i < 12; i++ {
+		offset := time.Duration(i) * time.Minute
+		event := ForwardingEvent{
+			Timestamp:      timestamp.Add(offset),
+			IncomingChanID: chanA,
+			OutgoingChanID: chanB,
+			AmtIn:          2000,
+			AmtOut:         1000,
+			Outcome:        outcomes[i%len(outcomes)],
+			IncomingPeer:   fn.Some(peerA),
+			OutgoingPeer:   fn.Some(peerB),
+		}
+		if i%2 == 1 {
+			event.IncomingChanID = chanB
+			event.OutgoingChanID = chanC
+			event.IncomingPeer = fn.Some(peerB)
+			event.OutgoingPeer = fn.Some(peerC)
+		}
+
+		events = append(events, event)
+	}
+	require.NoError(t, log.AddForwardingEvents(events))
+
+	// filterEvents returns the subset of events the predicate holds for.
+	filterEvents := func(
+		pred func(e ForwardingEvent) bool) []ForwardingEvent {
+
+		var filtered []ForwardingEvent
+		for _, e := range events {
+			if pred(e) {
+				filtered = append(filtered, e)
+			}
+		}
+
+		return filtered
+	}
+
+	testCases := []struct {
+		name     string
+		query    ForwardingEventQuery
+		expected []ForwardingEvent
+	}{
+		{
+			name: "settled only by default",
+			expected: filterEvents(func(e ForwardingEvent) bool {
+				return e.Outcome == ForwardingEventSettled
+			}),
+		},
+		{
+			name: "failed and rejected",
+			query: ForwardingEventQuery{
+				Outcomes: fn.NewSet(
+					ForwardingEventFailed,
+					ForwardingEventRejected,
+				),
+			},
+			expected: filterEvents(func(e ForwardingEvent) bool {
+				return e.Outcome != ForwardingEventSettled
+			}),
+		},
+		{
+			name: "incoming channel",
+			query: ForwardingEventQuery{
+				IncomingChanIDs: fn.NewSet(chanB),
+				Outcomes:        fn.NewSet(outcomes...),
+			},
+			expected: filterEvents(func(e ForwardingEvent) bool {
+				return e.IncomingChanID == chanB
+			}),
+		},
+		{
+			name: "outgoing channel",
+			query: ForwardingEventQuery{
+				OutgoingChanIDs: fn.NewSet(chanB),
+				Outcomes: fn.NewSet(
+					ForwardingEventFailed,
+				),
+			},
+			expected: filterEvents(func(e ForwardingEvent) bool {
+				return e.OutgoingChanID == chanB &&
+					e.Outcome == ForwardingEventFailed
+			}),
+		},
+		{
+			name: "peer on either side",
+			query: ForwardingEventQuery{
+				Peers:    fn.NewSet(peerB),
+				Outcomes: fn.NewSet(outcomes...),
+			},
+			expected: events,
+		},
+		{
+			name: "peer on one side",
+			query: ForwardingEventQuery{
+				Peers:    fn.NewSet(peerC),
+				Outcomes: fn.NewSet(outcomes...),
+			},
+			expected: filterEvents(func(e ForwardingEvent) bool {
+				return e.OutgoingChanID == chanC
+			}),
+		},
+		{
+			name: "index offset counts matching events",
+			query: ForwardingEventQuery{
+				IncomingChanIDs: fn.NewSet(chanA),
+				Outcomes:        fn.NewSet(outcomes...),
+				IndexOffset:     2,
+			},
+			expected: filterEvents(func(e ForwardingEvent) bool {
+				return e.IncomingChanID == chanA
+			})[2:],
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			tc.query.StartTime = timestamp
+			tc.query.EndTime = timestamp.Add(time.Hour)
+			tc.query.NumMaxEvents = 100
+
+			timeSlice, err := log.Query(tc.query)
+			require.NoError(t, err)
+			require.Equal(
+				t, tc.expected, timeSlice.ForwardingEvents,
+			)
+
+			numEvents := uint32(len(tc.expected))
+			require.EqualValues(
+				t, tc.query.IndexOffset+numEvents,
+				timeSlice.LastIndexOffset,
+			)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package channeldb
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
 	"errors"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/ltcsuite/lnd/aliasmgr"
 	"github.com/ltcsuite/lnd/batch"
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/kvdb"
 	"github.com/ltcsuite/lnd/lnwire"
@@ -176,9 +178,16 @@ const (
 // Nodes, edges, and edge information can all be added to the graph
 // independently. Edge removal results in the deletion of all edge information
 // for that edge.
+//
+// If the graph is backed by a native SQL graph store, then the graph is read
+// from and written to the SQL store instead of the key-value store.
 type ChannelGraph struct {
 	db kvdb.Backend
 
+	// sqlStore is the native SQL graph store that backs the graph. If nil,
+	// the graph is kept in the key-value store.
+	sqlStore *SQLGraphStore
+
 	cacheMu     sync.RWMutex
 	rejectCache *rejectCache
 	chanCache   *channelCache
@@ -189,10 +198,13 @@ type ChannelGraph struct {
 }
 
 // NewChannelGraph allocates a new ChannelGraph backed by a DB instance. The
-// returned instance has its own unique reject cache and channel cache.
+// returned instance has its own unique reject cache and channel cache. If a
+// native SQL graph store is passed, the graph is kept in the SQL store instead,
+// and the graph of the key-value store is copied over to it the first time.
 func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
 	batchCommitInterval time.Duration, preAllocCacheNumNodes int,
-	useGraphCache, noMigrations bool) (*ChannelGraph, error) {
+	useGraphCache, noMigrations bool,
+	sqlStore *SQLGraphStore) (*ChannelGraph, error) {
 
 	if !noMigrations {
 		if err := initChannelGraph(db); err != nil {
@@ -212,10 +224,25 @@ func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
 		db, nil, batchCommitInterval,
 	)
 
+	if sqlStore != nil {
+		if !noMigrations {
+			err := MigrateGraphToSQL(context.TODO(), g, sqlStore)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		g.sqlStore = sqlStore
+	}
+
 	// The graph cache can be turned off (e.g. for mobile users) for a
 	// speed/memory usage tradeoff.
 	if useGraphCache {
 		g.graphCache = NewGraphCache(preAllocCacheNumNodes)
+		if g.sqlStore != nil {
+			g.sqlStore.graphCache = g.graphCache
+		}
+
 		startTime := time.Now()
 		log.Debugf("Populating in-memory channel graph, this might " +
 			"take a while...")
@@ -323,8 +350,15 @@ var graphTopLevelBuckets = [][]byte{
 
 // Wipe completely deletes all saved state within all used buckets within the
 // database. The deletion is done in a single transaction, therefore this
-// operation is fully atomic.
+// operation is fully atomic. If the graph is backed by a native SQL graph
+// store, its tables are cleared first, in a transaction of their own.
 func (c *ChannelGraph) Wipe() error {
+	if c.sqlStore != nil {
+		if err := c.sqlStore.Wipe(); err != nil {
+			return err
+		}
+	}
+
 	err := kvdb.Update(c.db, func(tx kvdb.RwTx) error {
 		for _, tlb := range graphTopLevelBuckets {
 			err := tx.DeleteTopLevelBucket(tlb)
@@ -393,9 +427,11 @@ func initChannelGraph(db kvdb.Backend) error {
 }
 
 // NewPathFindTx returns a new read transaction that can be used for a single
-// path finding session. Will return nil if the graph cache is enabled.
+// path finding session. Will return nil if the graph cache is enabled or the
+// graph is kept in a native SQL graph store.
 func (c *ChannelGraph) NewPathFindTx() (kvdb.RTx, error) {
-	if c.graphCache != nil {
+	// The SQL graph store doesn't use key-value transactions.
+	if c.graphCache != nil || c.sqlStore != nil {
 		return nil, nil
 	}
 
@@ -414,6 +450,10 @@ func (c *ChannelGraph) NewPathFindTx() (kvdb.RTx, error) {
 func (c *ChannelGraph) ForEachChannel(cb func(*ChannelEdgeInfo,
 	*ChannelEdgePolicy, *ChannelEdgePolicy) error) error {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.ForEachChannel(cb)
+	}
+
 	return c.db.View(func(tx kvdb.RTx) error {
 		edges := tx.ReadBucket(edgeBucket)
 		if edges == nil {
@@ -486,11 +526,14 @@ func (c *ChannelGraph) ForEachNodeChannel(tx kvdb.RTx, node route.Vertex,
 	dbCallback := func(tx kvdb.RTx, e *ChannelEdgeInfo, p1,
 		p2 *ChannelEdgePolicy) error {
 
+		inboundFee := inboundFeeOrZero(p1)
+
 		var cachedInPolicy *CachedEdgePolicy
 		if p2 != nil {
 			cachedInPolicy = NewCachedPolicy(p2)
 			cachedInPolicy.ToNodePubKey = toNodeCallback
 			cachedInPolicy.ToNodeFeatures = toNodeFeatures
+			cachedInPolicy.InboundFee = inboundFee
 		}
 
 		directedChannel := &DirectedChannel{
@@ -500,6 +543,7 @@ func (c *ChannelGraph) ForEachNodeChannel(tx kvdb.RTx, node route.Vertex,
 			Capacity:     e.Capacity,
 			OutPolicySet: p1 != nil,
 			InPolicy:     cachedInPolicy,
+			InboundFee:   inboundFee,
 		}
 
 		if node == e.NodeKey2Bytes {
@@ -508,6 +552,11 @@ func (c *ChannelGraph) ForEachNodeChannel(tx kvdb.RTx, node route.Vertex,
 
 		return cb(directedChannel)
 	}
+
+	if c.sqlStore != nil {
+		return c.sqlStore.forEachNodeChannelTx(node, dbCallback)
+	}
+
 	return nodeTraversal(tx, node[:], c.db, dbCallback)
 }
 
@@ -585,6 +634,7 @@ func (c *ChannelGraph) ForEachNodeCached(cb func(node route.Vertex,
 				Capacity:     e.Capacity,
 				OutPolicySet: p1 != nil,
 				InPolicy:     cachedInPolicy,
+				InboundFee:   inboundFeeOrZero(p1),
 			}
 
 			if node.PubKeyBytes == e.NodeKey2Bytes {
@@ -607,6 +657,10 @@ func (c *ChannelGraph) ForEachNodeCached(cb func(node route.Vertex,
 // A channel is disabled when two of the associated ChanelEdgePolicies
 // have their disabled bit on.
 func (c *ChannelGraph) DisabledChannelIDs() ([]uint64, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.DisabledChannelIDs()
+	}
+
 	var disabledChanIDs []uint64
 	var chanEdgeFound map[uint64]struct{}
 
@@ -658,6 +712,14 @@ func (c *ChannelGraph) DisabledChannelIDs() ([]uint64, error) {
 func (c *ChannelGraph) ForEachNode(
 	cb func(kvdb.RTx, *LightningNode) error) error {
 
+	// As the SQL graph store doesn't use key-value transactions, a nil
+	// transaction is passed into the callback.
+	if c.sqlStore != nil {
+		return c.sqlStore.ForEachNode(func(node *LightningNode) error {
+			return cb(nil, node)
+		})
+	}
+
 	traversal := func(tx kvdb.RTx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
@@ -697,6 +759,17 @@ func (c *ChannelGraph) ForEachNode(
 func (c *ChannelGraph) ForEachNodeCacheable(cb func(kvdb.RTx,
 	GraphCacheNode) error) error {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.ForEachNode(func(node *LightningNode) error {
+			cacheableNode := newGraphCacheNode(
+				node.PubKeyBytes, node.Features,
+			)
+			cacheableNode.sqlStore = c.sqlStore
+
+			return cb(nil, cacheableNode)
+		})
+	}
+
 	traversal := func(tx kvdb.RTx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
@@ -735,6 +808,10 @@ func (c *ChannelGraph) ForEachNodeCacheable(cb func(kvdb.RTx,
 // a path finding algorithm in order to explore the reachability of another
 // node based off the source node.
 func (c *ChannelGraph) SourceNode() (*LightningNode, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.SourceNode()
+	}
+
 	var source *LightningNode
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
 		// First grab the nodes bucket which stores the mapping from
@@ -786,6 +863,10 @@ func (c *ChannelGraph) sourceNode(nodes kvdb.RBucket) (*LightningNode, error) {
 // node is to be used as the center of a star-graph within path finding
 // algorithms.
 func (c *ChannelGraph) SetSourceNode(node *LightningNode) error {
+	if c.sqlStore != nil {
+		return c.sqlStore.SetSourceNode(node)
+	}
+
 	nodePubBytes := node.PubKeyBytes[:]
 
 	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
@@ -819,6 +900,10 @@ func (c *ChannelGraph) SetSourceNode(node *LightningNode) error {
 func (c *ChannelGraph) AddLightningNode(node *LightningNode,
 	op ...batch.SchedulerOption) error {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.AddLightningNode(node, op...)
+	}
+
 	r := &batch.Request{
 		Update: func(tx kvdb.RwTx) error {
 			if c.graphCache != nil {
@@ -866,6 +951,10 @@ func addLightningNode(tx kvdb.RwTx, node *LightningNode) error {
 // LookupAlias attempts to return the alias as advertised by the target node.
 // TODO(roasbeef): currently assumes that aliases are unique...
 func (c *ChannelGraph) LookupAlias(pub *btcec.PublicKey) (string, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.LookupAlias(pub)
+	}
+
 	var alias string
 
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
@@ -902,6 +991,10 @@ func (c *ChannelGraph) LookupAlias(pub *btcec.PublicKey) (string, error) {
 // DeleteLightningNode starts a new database transaction to remove a vertex/node
 // from the database according to the node's public key.
 func (c *ChannelGraph) DeleteLightningNode(nodePub route.Vertex) error {
+	if c.sqlStore != nil {
+		return c.sqlStore.DeleteLightningNode(nodePub)
+	}
+
 	// TODO(roasbeef): ensure dangling edges are removed...
 	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
 		nodes := tx.ReadWriteBucket(nodeBucket)
@@ -970,6 +1063,10 @@ func (c *ChannelGraph) deleteLightningNode(nodes kvdb.RwBucket,
 func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo,
 	op ...batch.SchedulerOption) error {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.AddChannelEdge(edge, op...)
+	}
+
 	var alreadyExists bool
 	r := &batch.Request{
 		Reset: func() {
@@ -1116,6 +1213,10 @@ func (c *ChannelGraph) addChannelEdge(tx kvdb.RwTx, edge *ChannelEdgeInfo) error
 func (c *ChannelGraph) HasChannelEdge(
 	chanID uint64) (time.Time, time.Time, bool, bool, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.HasChannelEdge(chanID)
+	}
+
 	var (
 		upd1Time time.Time
 		upd2Time time.Time
@@ -1221,6 +1322,10 @@ func (c *ChannelGraph) HasChannelEdge(
 // that an edge info hasn't yet been created yet, but someone attempts to update
 // it.
 func (c *ChannelGraph) UpdateChannelEdge(edge *ChannelEdgeInfo) error {
+	if c.sqlStore != nil {
+		return c.sqlStore.UpdateChannelEdge(edge)
+	}
+
 	// Construct the channel's primary key which is the 8-byte channel ID.
 	var chanKey [8]byte
 	binary.BigEndian.PutUint64(chanKey[:], edge.ChannelID)
@@ -1267,6 +1372,12 @@ const (
 func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 	blockHash *chainhash.Hash, blockHeight uint32) ([]*ChannelEdgeInfo, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.PruneGraph(
+			spentOutputs, blockHash, blockHeight,
+		)
+	}
+
 	c.cacheMu.Lock()
 	defer c.cacheMu.Unlock()
 
@@ -1393,6 +1504,10 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 // that we only maintain a graph of reachable nodes. In the event that a pruned
 // node gains more channels, it will be re-added back to the graph.
 func (c *ChannelGraph) PruneGraphNodes() error {
+	if c.sqlStore != nil {
+		return c.sqlStore.PruneGraphNodes()
+	}
+
 	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
 		nodes := tx.ReadWriteBucket(nodeBucket)
 		if nodes == nil {
@@ -1521,6 +1636,10 @@ func (c *ChannelGraph) pruneGraphNodes(nodes kvdb.RwBucket,
 func (c *ChannelGraph) DisconnectBlockAtHeight(height uint32) ([]*ChannelEdgeInfo,
 	error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.DisconnectBlockAtHeight(height)
+	}
+
 	// Every channel having a ShortChannelID starting at 'height'
 	// will no longer be confirmed.
 	startShortChanID := lnwire.ShortChannelID{
@@ -1651,6 +1770,10 @@ func (c *ChannelGraph) DisconnectBlockAtHeight(height uint32) ([]*ChannelEdgeInf
 // to tell if the graph is currently in sync with the current best known UTXO
 // state.
 func (c *ChannelGraph) PruneTip() (*chainhash.Hash, uint32, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.PruneTip()
+	}
+
 	var (
 		tipHash   chainhash.Hash
 		tipHeight uint32
@@ -1700,6 +1823,12 @@ func (c *ChannelGraph) PruneTip() (*chainhash.Hash, uint32, error) {
 func (c *ChannelGraph) DeleteChannelEdges(strictZombiePruning, markZombie bool,
 	chanIDs ...uint64) error {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.DeleteChannelEdges(
+			strictZombiePruning, markZombie, chanIDs...,
+		)
+	}
+
 	// TODO(roasbeef): possibly delete from node bucket if node has no more
 	// channels
 	// TODO(roasbeef): don't delete both edges?
@@ -1759,6 +1888,10 @@ func (c *ChannelGraph) DeleteChannelEdges(strictZombiePruning, markZombie bool,
 // passed channel point (outpoint). If the passed channel doesn't exist within
 // the database, then ErrEdgeNotFound is returned.
 func (c *ChannelGraph) ChannelID(chanPoint *wire.OutPoint) (uint64, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.ChannelID(chanPoint)
+	}
+
 	var chanID uint64
 	if err := kvdb.View(c.db, func(tx kvdb.RTx) error {
 		var err error
@@ -1805,6 +1938,10 @@ func getChanID(tx kvdb.RTx, chanPoint *wire.OutPoint) (uint64, error) {
 // This represents the "newest" channel from the PoV of the chain. This method
 // can be used by peers to quickly determine if they're graphs are in sync.
 func (c *ChannelGraph) HighestChanID() (uint64, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.HighestChanID()
+	}
+
 	var cid uint64
 
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
@@ -1864,6 +2001,10 @@ type ChannelEdge struct {
 func (c *ChannelGraph) ChanUpdatesInHorizon(startTime,
 	endTime time.Time) ([]ChannelEdge, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.ChanUpdatesInHorizon(startTime, endTime)
+	}
+
 	// To ensure we don't return duplicate ChannelEdges, we'll use an
 	// additional map to keep track of the edges already seen to prevent
 	// re-adding it.
@@ -1999,6 +2140,10 @@ func (c *ChannelGraph) ChanUpdatesInHorizon(startTime,
 func (c *ChannelGraph) NodeUpdatesInHorizon(startTime,
 	endTime time.Time) ([]LightningNode, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.NodeUpdatesInHorizon(startTime, endTime)
+	}
+
 	var nodesInHorizon []LightningNode
 
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
@@ -2063,6 +2208,10 @@ func (c *ChannelGraph) NodeUpdatesInHorizon(startTime,
 // passed in. This method can be used by callers to determine the set of
 // channels another peer knows of that we don't.
 func (c *ChannelGraph) FilterKnownChanIDs(chanIDs []uint64) ([]uint64, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.FilterKnownChanIDs(chanIDs)
+	}
+
 	var newChanIDs []uint64
 
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
@@ -2139,6 +2288,10 @@ type BlockChannelRange struct {
 func (c *ChannelGraph) FilterChannelRange(startHeight,
 	endHeight uint32) ([]BlockChannelRange, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.FilterChannelRange(startHeight, endHeight)
+	}
+
 	startChanID := &lnwire.ShortChannelID{
 		BlockHeight: startHeight,
 	}
@@ -2234,6 +2387,10 @@ func (c *ChannelGraph) FilterChannelRange(startHeight,
 // of the query. This can be used to respond to peer queries that are seeking to
 // fill in gaps in their view of the channel graph.
 func (c *ChannelGraph) FetchChanInfos(chanIDs []uint64) ([]ChannelEdge, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.FetchChanInfos(chanIDs)
+	}
+
 	// TODO(roasbeef): sort cids?
 
 	var (
@@ -2472,6 +2629,10 @@ func makeZombiePubkeys(info *ChannelEdgeInfo,
 func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy,
 	op ...batch.SchedulerOption) error {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.UpdateEdgePolicy(edge, op...)
+	}
+
 	var (
 		isUpdate1    bool
 		edgeNotFound bool
@@ -2659,6 +2820,10 @@ type LightningNode struct {
 
 	db kvdb.Backend
 
+	// sqlStore is the native SQL graph store the node was fetched from, if
+	// any. If set, it's used instead of db to traverse the node's channels.
+	sqlStore *SQLGraphStore
+
 	// TODO(roasbeef): discovery will need storage to keep it's last IP
 	// address and re-announce if interface changes?
 
@@ -2784,6 +2949,10 @@ func (l *LightningNode) isPublic(tx kvdb.RTx, sourcePubKey []byte) (bool, error)
 func (c *ChannelGraph) FetchLightningNode(nodePub route.Vertex) (
 	*LightningNode, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.FetchLightningNode(nodePub)
+	}
+
 	var node *LightningNode
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
 		// First grab the nodes bucket which stores the mapping from
@@ -2827,6 +2996,10 @@ func (c *ChannelGraph) FetchLightningNode(nodePub route.Vertex) (
 type graphCacheNode struct {
 	pubKeyBytes route.Vertex
 	features    *lnwire.FeatureVector
+
+	// sqlStore is the native SQL graph store the node was fetched from, if
+	// any. If set, it's used to traverse the node's channels.
+	sqlStore *SQLGraphStore
 }
 
 // newGraphCacheNode returns a new cache optimized node.
@@ -2861,6 +3034,10 @@ func (n *graphCacheNode) ForEachChannel(tx kvdb.RTx,
 	cb func(kvdb.RTx, *ChannelEdgeInfo, *ChannelEdgePolicy,
 		*ChannelEdgePolicy) error) error {
 
+	if n.sqlStore != nil {
+		return n.sqlStore.forEachNodeChannelTx(n.pubKeyBytes, cb)
+	}
+
 	return nodeTraversal(tx, n.pubKeyBytes[:], nil, cb)
 }
 
@@ -2872,6 +3049,10 @@ var _ GraphCacheNode = (*graphCacheNode)(nil)
 // with a true boolean. Otherwise, an empty time.Time is returned with a false
 // boolean.
 func (c *ChannelGraph) HasLightningNode(nodePub [33]byte) (time.Time, bool, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.HasLightningNode(nodePub)
+	}
+
 	var (
 		updateTime time.Time
 		exists     bool
@@ -3020,6 +3201,10 @@ func (l *LightningNode) ForEachChannel(tx kvdb.RTx,
 	cb func(kvdb.RTx, *ChannelEdgeInfo, *ChannelEdgePolicy,
 		*ChannelEdgePolicy) error) error {
 
+	if l.sqlStore != nil {
+		return l.sqlStore.forEachNodeChannelTx(l.PubKeyBytes, cb)
+	}
+
 	nodePub := l.PubKeyBytes[:]
 	db := l.db
 
@@ -3087,6 +3272,10 @@ type ChannelEdgeInfo struct {
 	ExtraOpaqueData []byte
 
 	db kvdb.Backend
+
+	// sqlStore is the native SQL graph store the edge was fetched from, if
+	// any. If set, it's used instead of db to fetch the channel's nodes.
+	sqlStore *SQLGraphStore
 }
 
 // AddNodeKeys is a setter-like method that can be used to replace the set of
@@ -3223,6 +3412,10 @@ func (c *ChannelEdgeInfo) FetchOtherNode(tx kvdb.RTx,
 		return nil, fmt.Errorf("node not participating in this channel")
 	}
 
+	if c.sqlStore != nil {
+		return c.sqlStore.FetchLightningNode(targetNodeBytes)
+	}
+
 	var targetNode *LightningNode
 	fetchNodeFunc := func(tx kvdb.RTx) error {
 		// First grab the nodes bucket which stores the mapping from
@@ -3492,6 +3685,40 @@ func (c *ChannelEdgePolicy) ComputeFee(
 	return c.FeeBaseMSat + (amt*c.FeeProportionalMillionths)/feeRateParts
 }
 
+// InboundFee returns the inbound fee that the node which published this policy
+// charges for HTLCs it receives over the channel. The fee is carried in the
+// extra opaque data of the channel update. A zero fee is returned if the
+// policy doesn't carry one.
+func (c *ChannelEdgePolicy) InboundFee() (models.InboundFee, error) {
+	var (
+		fee        lnwire.Fee
+		extraBytes = lnwire.ExtraOpaqueData(c.ExtraOpaqueData)
+	)
+	if _, err := extraBytes.ExtractRecords(&fee); err != nil {
+		return models.InboundFee{}, err
+	}
+
+	return models.NewInboundFeeFromWire(fee), nil
+}
+
+// inboundFeeOrZero returns the inbound fee of the given policy, or a zero fee
+// if the policy is unknown or its inbound fee can't be parsed.
+func inboundFeeOrZero(policy *ChannelEdgePolicy) models.InboundFee {
+	if policy == nil {
+		return models.InboundFee{}
+	}
+
+	fee, err := policy.InboundFee()
+	if err != nil {
+		log.Debugf("Unable to parse inbound fee of channel %v: %v",
+			policy.ChannelID, err)
+
+		return models.InboundFee{}
+	}
+
+	return fee
+}
+
 // divideCeil divides dividend by factor and rounds the result up.
 func divideCeil(dividend, factor lnwire.MilliSatoshi) lnwire.MilliSatoshi {
 	return (dividend + factor - 1) / factor
@@ -3516,6 +3743,10 @@ func (c *ChannelEdgePolicy) ComputeFeeFromIncoming(
 func (c *ChannelGraph) FetchChannelEdgesByOutpoint(op *wire.OutPoint,
 ) (*ChannelEdgeInfo, *ChannelEdgePolicy, *ChannelEdgePolicy, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.FetchChannelEdgesByOutpoint(op)
+	}
+
 	var (
 		edgeInfo *ChannelEdgeInfo
 		policy1  *ChannelEdgePolicy
@@ -3603,6 +3834,10 @@ func (c *ChannelGraph) FetchChannelEdgesByOutpoint(op *wire.OutPoint,
 func (c *ChannelGraph) FetchChannelEdgesByID(chanID uint64,
 ) (*ChannelEdgeInfo, *ChannelEdgePolicy, *ChannelEdgePolicy, error) {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.FetchChannelEdgesByID(chanID)
+	}
+
 	var (
 		edgeInfo  *ChannelEdgeInfo
 		policy1   *ChannelEdgePolicy
@@ -3703,6 +3938,10 @@ func (c *ChannelGraph) FetchChannelEdgesByID(chanID uint64,
 // given public key is seen as a public node in the graph from the graph's
 // source node's point of view.
 func (c *ChannelGraph) IsPublicNode(pubKey [33]byte) (bool, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.IsPublicNode(pubKey)
+	}
+
 	var nodeIsPublic bool
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
 		nodes := tx.ReadBucket(nodeBucket)
@@ -3773,6 +4012,10 @@ func (e *EdgePoint) String() string {
 // returned are the ones that need to be watched on chain to detect channel
 // closes on the resident blockchain.
 func (c *ChannelGraph) ChannelView() ([]EdgePoint, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.ChannelView()
+	}
+
 	var edgePoints []EdgePoint
 	if err := kvdb.View(c.db, func(tx kvdb.RTx) error {
 		// We're going to iterate over the entire channel index, so
@@ -3845,6 +4088,10 @@ func (c *ChannelGraph) NewChannelEdgePolicy() *ChannelEdgePolicy {
 func (c *ChannelGraph) MarkEdgeZombie(chanID uint64,
 	pubKey1, pubKey2 [33]byte) error {
 
+	if c.sqlStore != nil {
+		return c.sqlStore.MarkEdgeZombie(chanID, pubKey1, pubKey2)
+	}
+
 	c.cacheMu.Lock()
 	defer c.cacheMu.Unlock()
 
@@ -3893,6 +4140,10 @@ func markEdgeZombie(zombieIndex kvdb.RwBucket, chanID uint64, pubKey1,
 
 // MarkEdgeLive clears an edge from our zombie index, deeming it as live.
 func (c *ChannelGraph) MarkEdgeLive(chanID uint64) error {
+	if c.sqlStore != nil {
+		return c.sqlStore.MarkEdgeLive(chanID)
+	}
+
 	c.cacheMu.Lock()
 	defer c.cacheMu.Unlock()
 
@@ -3939,6 +4190,10 @@ func (c *ChannelGraph) MarkEdgeLive(chanID uint64) error {
 // zombie, then the two node public keys corresponding to this edge are also
 // returned.
 func (c *ChannelGraph) IsZombieEdge(chanID uint64) (bool, [33]byte, [33]byte) {
+	if c.sqlStore != nil {
+		return c.sqlStore.IsZombieEdge(chanID)
+	}
+
 	var (
 		isZombie         bool
 		pubKey1, pubKey2 [33]byte
@@ -3991,6 +4246,10 @@ func isZombieEdge(zombieIndex kvdb.RBucket,
 
 // NumZombies returns the current number of zombie channels in the graph.
 func (c *ChannelGraph) NumZombies() (uint64, error) {
+	if c.sqlStore != nil {
+		return c.sqlStore.NumZombies()
+	}
+
 	var numZombies uint64
 	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
 		edges := tx.ReadBucket(edgeBucket)
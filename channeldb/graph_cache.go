@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/kvdb"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/routing/route"
@@ -81,6 +82,12 @@ type CachedEdgePolicy struct {
 	// the edge is in the cache, only on the copy that is returned in
 	// ForEachChannel().
 	ToNodeFeatures *lnwire.FeatureVector
+
+	// InboundFee is the fee that the to node charges for HTLCs it receives
+	// over this channel, taken from the to node's own policy of the
+	// channel. Like the other to node fields, it is only set on the copy
+	// that is returned in ForEachChannel().
+	InboundFee models.InboundFee
 }
 
 // ComputeFee computes the fee to forward an HTLC of `amt` milli-satoshis over
@@ -143,6 +150,10 @@ type DirectedChannel struct {
 	// source, so we're always interested in the edge that arrives to us
 	// from the other node.
 	InPolicy *CachedEdgePolicy
+
+	// InboundFee is the fee that this node charges for HTLCs it receives
+	// over the channel. It is taken from the outgoing policy of the node.
+	InboundFee models.InboundFee
 }
 
 // DeepCopy creates a deep copy of the channel, including the incoming policy.
@@ -324,11 +335,13 @@ func (c *GraphCache) UpdatePolicy(policy *ChannelEdgePolicy, fromNode,
 		// policy for node 1.
 		case channel.IsNode1 && edge1:
 			channel.OutPolicySet = true
+			channel.InboundFee = inboundFeeOrZero(policy)
 
 		// This is node 2, and it is edge 2, so this is the outgoing
 		// policy for node 2.
 		case !channel.IsNode1 && !edge1:
 			channel.OutPolicySet = true
+			channel.InboundFee = inboundFeeOrZero(policy)
 
 		// The other two cases left mean it's the inbound policy for the
 		// node.
@@ -442,6 +455,7 @@ func (c *GraphCache) getChannels(node route.Vertex) []*DirectedChannel {
 		if channelCopy.InPolicy != nil {
 			channelCopy.InPolicy.ToNodePubKey = toNodeCallback
 			channelCopy.InPolicy.ToNodeFeatures = features
+			channelCopy.InPolicy.InboundFee = channelCopy.InboundFee
 		}
 
 		channelsCopy[i] = channelCopy
@@ -0,0 +1,27 @@
+//go:build test_native_sql
+
+package channeldb
+
+import "testing"
+
+// testNativeSQLGraph is true if the graph tests run against the native SQL
+// graph store.
+const testNativeSQLGraph = true
+
+// testEdgeFeatures is the feature vector of the channels created by the graph
+// tests. The SQL store always returns the encoded features, which is just the
+// two byte length prefix for an empty feature vector.
+var testEdgeFeatures = []byte{0x00, 0x00}
+
+// testGraphSQLStore returns the native SQL graph store the graph tests run
+// against.
+func testGraphSQLStore(t testing.TB) *SQLGraphStore {
+	return newTestSQLGraphStore(t)
+}
+
+// testExtraOpaqueData returns the extra opaque data used by the graph tests.
+// The SQL store only accepts extra data that is a TLV stream, so the data is
+// wrapped in a single record of an odd type.
+func testExtraOpaqueData(data string) []byte {
+	return append([]byte{0x01, byte(len(data))}, data...)
+}
@@ -0,0 +1,25 @@
+//go:build !test_native_sql
+
+package channeldb
+
+import "testing"
+
+// testNativeSQLGraph is true if the graph tests run against the native SQL
+// graph store.
+const testNativeSQLGraph = false
+
+// testEdgeFeatures is the feature vector of the channels created by the graph
+// tests. The key-value store keeps channels without any features as they are.
+var testEdgeFeatures []byte
+
+// testGraphSQLStore returns nil so that the graph tests run against the
+// key-value graph store.
+func testGraphSQLStore(_ testing.TB) *SQLGraphStore {
+	return nil
+}
+
+// testExtraOpaqueData returns the extra opaque data used by the graph tests.
+// The key-value store accepts any extra data, so it is used as is.
+func testExtraOpaqueData(data string) []byte {
+	return []byte(data)
+}
@@ -0,0 +1,2783 @@
+package channeldb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ltcsuite/lnd/aliasmgr"
+	"github.com/ltcsuite/lnd/batch"
+	"github.com/ltcsuite/lnd/kvdb"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/lnd/sqldb"
+	"github.com/ltcsuite/lnd/sqldb/sqlc"
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/lnd/tor"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// graphQueryPageSize is the number of nodes or channels that are
+	// loaded at once when iterating over all the nodes or channels of the
+	// SQL graph store.
+	graphQueryPageSize = 1000
+)
+
+// SQLGraphQueries is an interface that defines the set of operations that can
+// be executed against the graph tables of the SQL database.
+type SQLGraphQueries interface {
+	UpsertNode(ctx context.Context,
+		arg sqlc.UpsertNodeParams) (int32, error)
+
+	InsertShellNode(ctx context.Context, pubKey []byte) (int32, error)
+
+	GetNodeByPubKey(ctx context.Context,
+		pubKey []byte) (sqlc.GraphNode, error)
+
+	GetNodeByID(ctx context.Context, id int32) (sqlc.GraphNode, error)
+
+	GetNodesByLastUpdateRange(ctx context.Context,
+		arg sqlc.GetNodesByLastUpdateRangeParams) ([]sqlc.GraphNode,
+		error)
+
+	ListNodesPaginated(ctx context.Context,
+		arg sqlc.ListNodesPaginatedParams) ([]sqlc.GraphNode, error)
+
+	CountNodes(ctx context.Context) (int64, error)
+
+	DeleteNodeByPubKey(ctx context.Context, pubKey []byte) (int64, error)
+
+	DeleteAllNodes(ctx context.Context) error
+
+	DeleteUnconnectedNodes(ctx context.Context) ([][]byte, error)
+
+	InsertNodeFeature(ctx context.Context,
+		arg sqlc.InsertNodeFeatureParams) error
+
+	GetNodeFeatures(ctx context.Context, nodeID int32) ([]int32, error)
+
+	DeleteNodeFeatures(ctx context.Context, nodeID int32) error
+
+	InsertNodeAddress(ctx context.Context,
+		arg sqlc.InsertNodeAddressParams) error
+
+	GetNodeAddresses(ctx context.Context,
+		nodeID int32) ([]sqlc.GetNodeAddressesRow, error)
+
+	DeleteNodeAddresses(ctx context.Context, nodeID int32) error
+
+	InsertNodeExtraType(ctx context.Context,
+		arg sqlc.InsertNodeExtraTypeParams) error
+
+	GetNodeExtraTypes(ctx context.Context,
+		nodeID int32) ([]sqlc.GraphNodeExtraType, error)
+
+	DeleteNodeExtraTypes(ctx context.Context, nodeID int32) error
+
+	InsertSourceNode(ctx context.Context, nodeID int32) error
+
+	GetSourceNodeID(ctx context.Context) (int32, error)
+
+	DeleteSourceNodes(ctx context.Context) error
+
+	InsertChannel(ctx context.Context,
+		arg sqlc.InsertChannelParams) (int32, error)
+
+	UpdateChannel(ctx context.Context, arg sqlc.UpdateChannelParams) error
+
+	GetChannelBySCID(ctx context.Context,
+		scid []byte) (sqlc.GraphChannel, error)
+
+	GetChannelByOutpoint(ctx context.Context,
+		outpoint string) (sqlc.GraphChannel, error)
+
+	GetChannelsBySCIDRange(ctx context.Context,
+		arg sqlc.GetChannelsBySCIDRangeParams) ([]sqlc.GraphChannel,
+		error)
+
+	GetChannelsByNodeID(ctx context.Context,
+		nodeID1 int32) ([]sqlc.GraphChannel, error)
+
+	ListChannelsPaginated(ctx context.Context,
+		arg sqlc.ListChannelsPaginatedParams) ([]sqlc.GraphChannel,
+		error)
+
+	GetHighestSCID(ctx context.Context) ([]byte, error)
+
+	DeleteChannel(ctx context.Context, id int32) error
+
+	DeleteAllChannels(ctx context.Context) error
+
+	InsertChannelFeature(ctx context.Context,
+		arg sqlc.InsertChannelFeatureParams) error
+
+	GetChannelFeatures(ctx context.Context,
+		channelID int32) ([]int32, error)
+
+	DeleteChannelFeatures(ctx context.Context, channelID int32) error
+
+	InsertChannelExtraType(ctx context.Context,
+		arg sqlc.InsertChannelExtraTypeParams) error
+
+	GetChannelExtraTypes(ctx context.Context,
+		channelID int32) ([]sqlc.GraphChannelExtraType, error)
+
+	DeleteChannelExtraTypes(ctx context.Context, channelID int32) error
+
+	UpsertChannelPolicy(ctx context.Context,
+		arg sqlc.UpsertChannelPolicyParams) (int32, error)
+
+	GetChannelPolicy(ctx context.Context,
+		arg sqlc.GetChannelPolicyParams) (sqlc.GraphChannelPolicy,
+		error)
+
+	GetSCIDsByPolicyLastUpdateRange(ctx context.Context,
+		arg sqlc.GetSCIDsByPolicyLastUpdateRangeParams) ([][]byte,
+		error)
+
+	GetDisabledSCIDs(ctx context.Context) ([][]byte, error)
+
+	InsertChannelPolicyExtraType(ctx context.Context,
+		arg sqlc.InsertChannelPolicyExtraTypeParams) error
+
+	GetChannelPolicyExtraTypes(ctx context.Context,
+		channelPolicyID int32) ([]sqlc.GraphChannelPolicyExtraType,
+		error)
+
+	DeleteChannelPolicyExtraTypes(ctx context.Context,
+		channelPolicyID int32) error
+
+	UpsertZombieChannel(ctx context.Context,
+		arg sqlc.UpsertZombieChannelParams) error
+
+	GetZombieChannel(ctx context.Context,
+		scid []byte) (sqlc.GraphZombieChannel, error)
+
+	DeleteZombieChannel(ctx context.Context, scid []byte) error
+
+	DeleteAllZombieChannels(ctx context.Context) error
+
+	CountZombieChannels(ctx context.Context) (int64, error)
+
+	UpsertPruneLogEntry(ctx context.Context,
+		arg sqlc.UpsertPruneLogEntryParams) error
+
+	GetPruneTip(ctx context.Context) (sqlc.GraphPruneLog, error)
+
+	DeletePruneLogEntriesFrom(ctx context.Context, blockHeight int64) error
+}
+
+// GraphQueriesTxOptions defines the set of db txn options the SQLGraphQueries
+// understands.
+type GraphQueriesTxOptions struct {
+	// readOnly governs if a read only transaction is needed or not.
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+//
+// NOTE: This implements the sqldb.TxOptions interface.
+func (g *GraphQueriesTxOptions) ReadOnly() bool {
+	return g.readOnly
+}
+
+// NewGraphQueryReadTx creates a new read transaction option set.
+func NewGraphQueryReadTx() GraphQueriesTxOptions {
+	return GraphQueriesTxOptions{
+		readOnly: true,
+	}
+}
+
+// BatchedSQLGraphQueries is a version of the SQLGraphQueries that's capable
+// of batched database operations.
+type BatchedSQLGraphQueries interface {
+	SQLGraphQueries
+
+	sqldb.BatchedTx[SQLGraphQueries]
+}
+
+// SQLGraphStore is a GraphStore implementation that keeps the channel graph
+// in native SQL tables. It doesn't keep any in-memory caches of its own, so
+// several instances can safely share the same database. If it backs a
+// ChannelGraph, the graph cache of the ChannelGraph is kept up to date with
+// all the changes made through the store.
+//
+// NOTE: The extra opaque data of the stored announcements and updates is kept
+// as individual TLV records, so extra opaque data that isn't a valid TLV
+// stream is rejected with ErrParsingExtraTLVBytes.
+type SQLGraphStore struct {
+	db BatchedSQLGraphQueries
+
+	// graphCache is the in-memory graph cache of the ChannelGraph that is
+	// backed by this store. It is nil if the cache is disabled or the
+	// store is used on its own.
+	graphCache *GraphCache
+}
+
+// NewSQLGraphStore creates a new SQL backed graph store.
+func NewSQLGraphStore(db BatchedSQLGraphQueries) *SQLGraphStore {
+	return &SQLGraphStore{
+		db: db,
+	}
+}
+
+// Compile-time constraint to ensure SQLGraphStore implements the GraphStore
+// interface.
+var _ GraphStore = (*SQLGraphStore)(nil)
+
+// SourceNode returns the source node of the graph.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) SourceNode() (*LightningNode, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx = NewGraphQueryReadTx()
+		node   *LightningNode
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		nodeID, err := db.GetSourceNodeID(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrSourceNodeNotSet
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch source node: %w",
+				err)
+		}
+
+		node, err = s.fetchSQLNodeByID(ctx, db, nodeID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// SetSourceNode sets the source node within the graph database.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) SetSourceNode(node *LightningNode) error {
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		nodeID, err := upsertSQLNode(ctx, db, node)
+		if err != nil {
+			return err
+		}
+
+		if err := db.DeleteSourceNodes(ctx); err != nil {
+			return fmt.Errorf("unable to delete source node: %w",
+				err)
+		}
+
+		return db.InsertSourceNode(ctx, nodeID)
+	})
+}
+
+// AddLightningNode adds a vertex/node to the graph database. If the node is
+// already present, its information is updated.
+//
+// NOTE: The scheduler options are ignored as the SQL store doesn't batch
+// writes.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) AddLightningNode(node *LightningNode,
+	_ ...batch.SchedulerOption) error {
+
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		_, err := upsertSQLNode(ctx, db, node)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		cNode := newGraphCacheNode(node.PubKeyBytes, node.Features)
+		cNode.sqlStore = s
+
+		return s.graphCache.AddNode(nil, cNode)
+	}
+
+	return nil
+}
+
+// FetchLightningNode attempts to look up a target node by its identity public
+// key. If the node isn't found in the database, then ErrGraphNodeNotFound is
+// returned.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) FetchLightningNode(nodePub route.Vertex) (
+	*LightningNode, error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx = NewGraphQueryReadTx()
+		node   *LightningNode
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		dbNode, err := db.GetNodeByPubKey(ctx, nodePub[:])
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrGraphNodeNotFound
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch node: %w", err)
+		}
+
+		node, err = s.buildSQLNode(ctx, db, dbNode)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// HasLightningNode determines if the graph has a vertex identified by the
+// target node identity public key. If the node exists in the database, a
+// timestamp of when the data for the node was lasted updated is returned along
+// with a true boolean. Otherwise, an empty time.Time is returned with a false
+// boolean.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) HasLightningNode(nodePub [33]byte) (time.Time, bool,
+	error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx     = NewGraphQueryReadTx()
+		updateTime time.Time
+		exists     bool
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		dbNode, err := db.GetNodeByPubKey(ctx, nodePub[:])
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			updateTime, exists = time.Time{}, false
+			return nil
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch node: %w", err)
+		}
+
+		updateTime, exists = time.Unix(dbNode.LastUpdate, 0), true
+
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return updateTime, exists, nil
+}
+
+// LookupAlias attempts to return the alias as advertised by the target node.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) LookupAlias(pub *btcec.PublicKey) (string, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx = NewGraphQueryReadTx()
+		alias  string
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		dbNode, err := db.GetNodeByPubKey(
+			ctx, pub.SerializeCompressed(),
+		)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNodeAliasNotFound
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch node: %w", err)
+		}
+
+		// Shell nodes never advertised an alias.
+		if !dbNode.Alias.Valid {
+			return ErrNodeAliasNotFound
+		}
+		alias = dbNode.Alias.String
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return alias, nil
+}
+
+// DeleteLightningNode removes a vertex/node from the database according to the
+// node's public key.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) DeleteLightningNode(nodePub route.Vertex) error {
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		numDeleted, err := db.DeleteNodeByPubKey(ctx, nodePub[:])
+		if err != nil {
+			return fmt.Errorf("unable to delete node: %w", err)
+		}
+
+		if numDeleted == 0 {
+			return ErrGraphNodeNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.RemoveNode(nodePub)
+	}
+
+	return nil
+}
+
+// IsPublicNode is a helper method that determines whether the node with the
+// given public key is seen as a public node in the graph from the graph's
+// source node's point of view.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) IsPublicNode(pubKey [33]byte) (bool, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx       = NewGraphQueryReadTx()
+		nodeIsPublic bool
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		nodeIsPublic = false
+
+		sourceID, err := db.GetSourceNodeID(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrSourceNodeNotSet
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch source node: %w",
+				err)
+		}
+
+		dbNode, err := db.GetNodeByPubKey(ctx, pubKey[:])
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrGraphNodeNotFound
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch node: %w", err)
+		}
+
+		channels, err := db.GetChannelsByNodeID(ctx, dbNode.ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch node channels: %w",
+				err)
+		}
+
+		// The node is public if it has a channel that doesn't extend
+		// to the source node, or if any of its channels with the
+		// source node was announced.
+		for _, dbChan := range channels {
+			if dbChan.NodeID1 != sourceID &&
+				dbChan.NodeID2 != sourceID {
+
+				nodeIsPublic = true
+				return nil
+			}
+
+			if sqlChanAuthProof(dbChan) != nil {
+				nodeIsPublic = true
+				return nil
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return nodeIsPublic, nil
+}
+
+// NodeUpdatesInHorizon returns all the known lightning node which have an
+// update timestamp within the passed range.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) NodeUpdatesInHorizon(startTime,
+	endTime time.Time) ([]LightningNode, error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx         = NewGraphQueryReadTx()
+		nodesInHorizon []LightningNode
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		nodesInHorizon = nil
+
+		dbNodes, err := db.GetNodesByLastUpdateRange(
+			ctx, sqlc.GetNodesByLastUpdateRangeParams{
+				StartTime: startTime.Unix(),
+				EndTime:   endTime.Unix(),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch nodes: %w", err)
+		}
+
+		for _, dbNode := range dbNodes {
+			node, err := s.buildSQLNode(ctx, db, dbNode)
+			if err != nil {
+				return err
+			}
+
+			nodesInHorizon = append(nodesInHorizon, *node)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodesInHorizon, nil
+}
+
+// PruneGraphNodes is a garbage collection method which attempts to prune out
+// any nodes from the channel graph that are currently unconnected. The source
+// node is never pruned.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) PruneGraphNodes() error {
+	ctx := context.TODO()
+
+	var (
+		writeTxOpts GraphQueriesTxOptions
+		prunedNodes []route.Vertex
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		var err error
+		prunedNodes, err = pruneSQLGraphNodes(ctx, db)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeCachedNodes(prunedNodes)
+
+	return nil
+}
+
+// AddChannelEdge adds a new (undirected, blank) edge to the graph database.
+// Shell nodes are created for the two nodes of the channel if they aren't
+// known yet. ErrEdgeAlreadyExist is returned if the edge is already known.
+//
+// NOTE: The scheduler options are ignored as the SQL store doesn't batch
+// writes.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) AddChannelEdge(edge *ChannelEdgeInfo,
+	_ ...batch.SchedulerOption) error {
+
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		_, err := db.GetChannelBySCID(ctx, chanIDToSCID(edge.ChannelID))
+		switch {
+		case err == nil:
+			return ErrEdgeAlreadyExist
+
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("unable to fetch channel: %w", err)
+		}
+
+		return insertSQLChannel(ctx, db, edge)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.AddChannel(edge, nil, nil)
+	}
+
+	return nil
+}
+
+// UpdateChannelEdge updates the info of an already existing edge. If the edge
+// isn't known yet, ErrEdgeNotFound is returned.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) UpdateChannelEdge(edge *ChannelEdgeInfo) error {
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetChannelBySCID(
+			ctx, chanIDToSCID(edge.ChannelID),
+		)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEdgeNotFound
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch channel: %w", err)
+		}
+
+		features, extraTypes, err := parseSQLChannelDetails(edge)
+		if err != nil {
+			return err
+		}
+
+		node1ID, err := fetchOrInsertSQLShellNode(
+			ctx, db, edge.NodeKey1Bytes,
+		)
+		if err != nil {
+			return err
+		}
+		node2ID, err := fetchOrInsertSQLShellNode(
+			ctx, db, edge.NodeKey2Bytes,
+		)
+		if err != nil {
+			return err
+		}
+
+		proof := edge.AuthProof
+		if proof == nil {
+			proof = &ChannelAuthProof{}
+		}
+
+		err = db.UpdateChannel(ctx, sqlc.UpdateChannelParams{
+			ID:                dbChan.ID,
+			ChainHash:         edge.ChainHash[:],
+			Outpoint:          edge.ChannelPoint.String(),
+			Capacity:          int64(edge.Capacity),
+			NodeID1:           node1ID,
+			NodeID2:           node2ID,
+			BitcoinKey1:       edge.BitcoinKey1Bytes[:],
+			BitcoinKey2:       edge.BitcoinKey2Bytes[:],
+			Node1Signature:    proof.NodeSig1Bytes,
+			Node2Signature:    proof.NodeSig2Bytes,
+			Bitcoin1Signature: proof.BitcoinSig1Bytes,
+			Bitcoin2Signature: proof.BitcoinSig2Bytes,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to update channel: %w", err)
+		}
+
+		if err := db.DeleteChannelFeatures(ctx, dbChan.ID); err != nil {
+			return fmt.Errorf("unable to delete channel features: "+
+				"%w", err)
+		}
+
+		err = db.DeleteChannelExtraTypes(ctx, dbChan.ID)
+		if err != nil {
+			return fmt.Errorf("unable to delete channel extra "+
+				"types: %w", err)
+		}
+
+		return insertSQLChannelDetails(
+			ctx, db, dbChan.ID, features, extraTypes,
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.UpdateChannel(edge)
+	}
+
+	return nil
+}
+
+// UpdateEdgePolicy updates the edge routing policy for a single directed edge
+// within the database for the referenced channel. The direction bit of the
+// channel flags determines which of the directed edges is updated.
+//
+// NOTE: The scheduler options are ignored as the SQL store doesn't batch
+// writes.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) UpdateEdgePolicy(edge *ChannelEdgePolicy,
+	_ ...batch.SchedulerOption) error {
+
+	ctx := context.TODO()
+
+	var (
+		writeTxOpts      GraphQueriesTxOptions
+		fromNode, toNode route.Vertex
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		dbChan, err := upsertSQLPolicy(ctx, db, edge)
+		if err != nil {
+			return err
+		}
+
+		// The graph cache needs to know the direction of the policy
+		// by the public keys of the nodes.
+		if s.graphCache == nil {
+			return nil
+		}
+
+		fromNodeID, toNodeID := dbChan.NodeID1, dbChan.NodeID2
+		if edge.ChannelFlags&lnwire.ChanUpdateDirection != 0 {
+			fromNodeID, toNodeID = toNodeID, fromNodeID
+		}
+
+		dbFromNode, err := db.GetNodeByID(ctx, fromNodeID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch node: %w", err)
+		}
+		dbToNode, err := db.GetNodeByID(ctx, toNodeID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch node: %w", err)
+		}
+
+		copy(fromNode[:], dbFromNode.PubKey)
+		copy(toNode[:], dbToNode.PubKey)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		isUpdate1 := edge.ChannelFlags&lnwire.ChanUpdateDirection == 0
+		s.graphCache.UpdatePolicy(edge, fromNode, toNode, isUpdate1)
+	}
+
+	return nil
+}
+
+// HasChannelEdge returns true if the database knows of a channel edge with the
+// passed channel ID, and false otherwise. If an edge with that ID is found
+// within the graph, then two time stamps representing the last time the edge
+// was updated for both directed edges are returned along with the boolean. If
+// it is not found, then the zombie index is checked and its result is returned
+// as the second boolean.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) HasChannelEdge(chanID uint64) (time.Time, time.Time,
+	bool, bool, error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx             = NewGraphQueryReadTx()
+		upd1Time, upd2Time time.Time
+		exists, isZombie   bool
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		upd1Time, upd2Time = time.Time{}, time.Time{}
+		exists, isZombie = false, false
+
+		scid := chanIDToSCID(chanID)
+		dbChan, err := db.GetChannelBySCID(ctx, scid)
+		switch {
+		// If the edge doesn't exist, then we'll also check our zombie
+		// index.
+		case errors.Is(err, sql.ErrNoRows):
+			isZombie, _, _, err = fetchSQLZombie(ctx, db, scid)
+			return err
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch channel: %w", err)
+		}
+
+		exists = true
+
+		// As we may have only one of the policies, only set the update
+		// time if the policy was found in the database.
+		updateTime := func(nodeID int32) (time.Time, error) {
+			dbPolicy, err := db.GetChannelPolicy(
+				ctx, sqlc.GetChannelPolicyParams{
+					ChannelID: dbChan.ID,
+					NodeID:    nodeID,
+				},
+			)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return time.Time{}, nil
+
+			case err != nil:
+				return time.Time{}, fmt.Errorf("unable to "+
+					"fetch policy: %w", err)
+			}
+
+			return time.Unix(dbPolicy.LastUpdate, 0), nil
+		}
+
+		upd1Time, err = updateTime(dbChan.NodeID1)
+		if err != nil {
+			return err
+		}
+		upd2Time, err = updateTime(dbChan.NodeID2)
+
+		return err
+	})
+	if err != nil {
+		return time.Time{}, time.Time{}, exists, isZombie, err
+	}
+
+	return upd1Time, upd2Time, exists, isZombie, nil
+}
+
+// FetchChannelEdgesByOutpoint attempts to lookup the two directed edges for
+// the channel identified by the funding outpoint. If the channel can't be
+// found, then ErrEdgeNotFound is returned.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) FetchChannelEdgesByOutpoint(op *wire.OutPoint) (
+	*ChannelEdgeInfo, *ChannelEdgePolicy, *ChannelEdgePolicy, error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx = NewGraphQueryReadTx()
+		edge   *ChannelEdge
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetChannelByOutpoint(ctx, op.String())
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEdgeNotFound
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch channel: %w", err)
+		}
+
+		edge, err = s.buildSQLChannelEdge(ctx, db, dbChan)
+
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return edge.Info, edge.Policy1, edge.Policy2, nil
+}
+
+// FetchChannelEdgesByID attempts to lookup the two directed edges for the
+// channel identified by the channel ID. If the channel can't be found, then
+// ErrEdgeNotFound is returned.
+//
+// ErrZombieEdge is returned if the edge is currently marked as a zombie. In
+// this case, the ChannelEdgePolicy's will be nil, and the ChannelEdgeInfo will
+// only include the public keys of each node.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) FetchChannelEdgesByID(chanID uint64) (
+	*ChannelEdgeInfo, *ChannelEdgePolicy, *ChannelEdgePolicy, error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx = NewGraphQueryReadTx()
+		edge   *ChannelEdge
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		scid := chanIDToSCID(chanID)
+		dbChan, err := db.GetChannelBySCID(ctx, scid)
+		switch {
+		// If it doesn't exist, we'll check whether it was marked as a
+		// zombie instead, in which case only the public keys of the
+		// nodes are known.
+		case errors.Is(err, sql.ErrNoRows):
+			isZombie, pubKey1, pubKey2, err := fetchSQLZombie(
+				ctx, db, scid,
+			)
+			if err != nil {
+				return err
+			}
+
+			if !isZombie {
+				return ErrEdgeNotFound
+			}
+
+			edge = &ChannelEdge{
+				Info: &ChannelEdgeInfo{
+					NodeKey1Bytes: pubKey1,
+					NodeKey2Bytes: pubKey2,
+				},
+			}
+
+			return ErrZombieEdge
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch channel: %w", err)
+		}
+
+		edge, err = s.buildSQLChannelEdge(ctx, db, dbChan)
+
+		return err
+	})
+	switch {
+	case errors.Is(err, ErrZombieEdge):
+		return edge.Info, nil, nil, err
+
+	case err != nil:
+		return nil, nil, nil, err
+	}
+
+	return edge.Info, edge.Policy1, edge.Policy2, nil
+}
+
+// ChannelID attempt to lookup the 8-byte compact channel ID which maps to the
+// passed channel point (outpoint). If the passed channel doesn't exist within
+// the database, then ErrEdgeNotFound is returned.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) ChannelID(chanPoint *wire.OutPoint) (uint64, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx = NewGraphQueryReadTx()
+		chanID uint64
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		dbChan, err := db.GetChannelByOutpoint(ctx, chanPoint.String())
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEdgeNotFound
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch channel: %w", err)
+		}
+
+		chanID = byteOrder.Uint64(dbChan.Scid)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return chanID, nil
+}
+
+// HighestChanID returns the "highest" known channel ID in the channel graph.
+// Zero is returned if the graph doesn't contain any channels.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) HighestChanID() (uint64, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx = NewGraphQueryReadTx()
+		chanID uint64
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		scid, err := db.GetHighestSCID(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			chanID = 0
+			return nil
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch highest channel "+
+				"ID: %w", err)
+		}
+
+		chanID = byteOrder.Uint64(scid)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return chanID, nil
+}
+
+// ForEachChannel iterates through all the channel edges stored within the
+// graph and invokes the passed callback for each edge. If the callback returns
+// an error, then the iteration stops early.
+//
+// NOTE: If an edge can't be found, or wasn't advertised, then a nil pointer
+// for that particular channel edge routing policy will be passed into the
+// callback.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) ForEachChannel(cb func(*ChannelEdgeInfo,
+	*ChannelEdgePolicy, *ChannelEdgePolicy) error) error {
+
+	ctx := context.TODO()
+
+	readTx := NewGraphQueryReadTx()
+	return s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		return forEachSQLChannel(
+			ctx, db, func(dbChan sqlc.GraphChannel) error {
+				edge, err := s.buildSQLChannelEdge(
+					ctx, db, dbChan,
+				)
+				if err != nil {
+					return err
+				}
+
+				return cb(edge.Info, edge.Policy1, edge.Policy2)
+			},
+		)
+	})
+}
+
+// ForEachNode iterates through all the stored nodes in the graph, executing
+// the passed callback with each node encountered. If the callback returns an
+// error, then the iteration stops early.
+func (s *SQLGraphStore) ForEachNode(cb func(*LightningNode) error) error {
+	ctx := context.TODO()
+
+	readTx := NewGraphQueryReadTx()
+	return s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		var lastID int32
+		for {
+			dbNodes, err := db.ListNodesPaginated(
+				ctx, sqlc.ListNodesPaginatedParams{
+					IDAfter:  lastID,
+					NumLimit: graphQueryPageSize,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to fetch nodes: %w",
+					err)
+			}
+
+			for _, dbNode := range dbNodes {
+				node, err := s.buildSQLNode(ctx, db, dbNode)
+				if err != nil {
+					return err
+				}
+
+				if err := cb(node); err != nil {
+					return err
+				}
+			}
+
+			if len(dbNodes) < graphQueryPageSize {
+				return nil
+			}
+
+			lastID = dbNodes[len(dbNodes)-1].ID
+		}
+	})
+}
+
+// ForEachNodeChannel iterates through all channels of the given node,
+// executing the passed callback with an edge info structure and the policies
+// of each end of the channel. The first edge policy is the outgoing edge *to*
+// the connecting node, while the second is the incoming edge *from* the
+// connecting node. If the callback returns an error, then the iteration is
+// halted with the error propagated back up to the caller.
+//
+// Unknown policies are passed into the callback as nil values.
+func (s *SQLGraphStore) ForEachNodeChannel(nodePub route.Vertex,
+	cb func(*ChannelEdgeInfo, *ChannelEdgePolicy,
+		*ChannelEdgePolicy) error) error {
+
+	ctx := context.TODO()
+
+	readTx := NewGraphQueryReadTx()
+	return s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		dbNode, err := db.GetNodeByPubKey(ctx, nodePub[:])
+		switch {
+		// Like the key-value store, an unknown node simply doesn't
+		// have any channels.
+		case errors.Is(err, sql.ErrNoRows):
+			return nil
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch node: %w", err)
+		}
+
+		channels, err := db.GetChannelsByNodeID(ctx, dbNode.ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch node channels: %w",
+				err)
+		}
+
+		for _, dbChan := range channels {
+			info, err := s.buildSQLEdgeInfo(ctx, db, dbChan)
+			if err != nil {
+				return err
+			}
+
+			otherID := dbChan.NodeID2
+			if otherID == dbNode.ID {
+				otherID = dbChan.NodeID1
+			}
+
+			outPolicy, err := s.fetchSQLPolicy(
+				ctx, db, dbChan, dbNode.ID, otherID,
+			)
+			if err != nil {
+				return err
+			}
+			inPolicy, err := s.fetchSQLPolicy(
+				ctx, db, dbChan, otherID, dbNode.ID,
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := cb(info, outPolicy, inPolicy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// forEachNodeChannelTx is a version of ForEachNodeChannel that uses the
+// callback signature of the key-value store's node traversal. As the SQL
+// store doesn't use key-value transactions, a nil transaction is passed into
+// the callback.
+func (s *SQLGraphStore) forEachNodeChannelTx(nodePub route.Vertex,
+	cb func(kvdb.RTx, *ChannelEdgeInfo, *ChannelEdgePolicy,
+		*ChannelEdgePolicy) error) error {
+
+	return s.ForEachNodeChannel(nodePub, func(info *ChannelEdgeInfo,
+		outPolicy, inPolicy *ChannelEdgePolicy) error {
+
+		return cb(nil, info, outPolicy, inPolicy)
+	})
+}
+
+// ChannelView returns the verifiable edge information for each active channel
+// within the known channel graph.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) ChannelView() ([]EdgePoint, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx     = NewGraphQueryReadTx()
+		edgePoints []EdgePoint
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		edgePoints = nil
+
+		return forEachSQLChannel(
+			ctx, db, func(dbChan sqlc.GraphChannel) error {
+				chanPoint, err := wire.NewOutPointFromString(
+					dbChan.Outpoint,
+				)
+				if err != nil {
+					return err
+				}
+
+				pkScript, err := genMultiSigP2WSH(
+					dbChan.BitcoinKey1, dbChan.BitcoinKey2,
+				)
+				if err != nil {
+					return err
+				}
+
+				edgePoints = append(edgePoints, EdgePoint{
+					FundingPkScript: pkScript,
+					OutPoint:        *chanPoint,
+				})
+
+				return nil
+			},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edgePoints, nil
+}
+
+// DisabledChannelIDs returns the channel ids of disabled channels. A channel
+// is disabled when both of its policies have their disabled bit set.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) DisabledChannelIDs() ([]uint64, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx          = NewGraphQueryReadTx()
+		disabledChanIDs []uint64
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		disabledChanIDs = nil
+
+		scids, err := db.GetDisabledSCIDs(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to fetch disabled channels: "+
+				"%w", err)
+		}
+
+		for _, scid := range scids {
+			disabledChanIDs = append(
+				disabledChanIDs, byteOrder.Uint64(scid),
+			)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return disabledChanIDs, nil
+}
+
+// ChanUpdatesInHorizon returns all the known channel edges which have at least
+// one edge that has an update timestamp within the specified horizon.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) ChanUpdatesInHorizon(startTime,
+	endTime time.Time) ([]ChannelEdge, error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx         = NewGraphQueryReadTx()
+		edgesInHorizon []ChannelEdge
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		edgesInHorizon = nil
+
+		scids, err := db.GetSCIDsByPolicyLastUpdateRange(
+			ctx, sqlc.GetSCIDsByPolicyLastUpdateRangeParams{
+				StartTime: startTime.Unix(),
+				EndTime:   endTime.Unix(),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch channels: %w", err)
+		}
+
+		for _, scid := range scids {
+			dbChan, err := db.GetChannelBySCID(ctx, scid)
+			if err != nil {
+				return fmt.Errorf("unable to fetch channel: %w",
+					err)
+			}
+
+			edge, err := s.buildSQLChannelEdge(ctx, db, dbChan)
+			if err != nil {
+				return err
+			}
+
+			edgesInHorizon = append(edgesInHorizon, *edge)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edgesInHorizon, nil
+}
+
+// FilterKnownChanIDs takes a set of channel IDs and return the subset of chan
+// ID's that we don't know and are not known zombies of the passed set.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) FilterKnownChanIDs(chanIDs []uint64) ([]uint64,
+	error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx     = NewGraphQueryReadTx()
+		newChanIDs []uint64
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		newChanIDs = nil
+
+		for _, chanID := range chanIDs {
+			scid := chanIDToSCID(chanID)
+
+			// If the edge is already known, skip it.
+			_, err := db.GetChannelBySCID(ctx, scid)
+			switch {
+			case err == nil:
+				continue
+
+			case !errors.Is(err, sql.ErrNoRows):
+				return fmt.Errorf("unable to fetch channel: %w",
+					err)
+			}
+
+			// If the edge is a known zombie, skip it.
+			isZombie, _, _, err := fetchSQLZombie(ctx, db, scid)
+			if err != nil {
+				return err
+			}
+			if isZombie {
+				continue
+			}
+
+			newChanIDs = append(newChanIDs, chanID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newChanIDs, nil
+}
+
+// FilterChannelRange returns the channel ID's of all known channels which were
+// mined in a block height within the passed range. The channel IDs are grouped
+// by their common block height.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) FilterChannelRange(startHeight,
+	endHeight uint32) ([]BlockChannelRange, error) {
+
+	ctx := context.TODO()
+
+	startChanID := lnwire.ShortChannelID{
+		BlockHeight: startHeight,
+	}
+	endChanID := lnwire.ShortChannelID{
+		BlockHeight: endHeight,
+		TxIndex:     math.MaxUint32 & 0x00ffffff,
+		TxPosition:  math.MaxUint16,
+	}
+
+	var (
+		readTx        = NewGraphQueryReadTx()
+		channelRanges []BlockChannelRange
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		channelRanges = nil
+
+		channels, err := db.GetChannelsBySCIDRange(
+			ctx, sqlc.GetChannelsBySCIDRangeParams{
+				StartScid: chanIDToSCID(startChanID.ToUint64()),
+				EndScid:   chanIDToSCID(endChanID.ToUint64()),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch channels: %w", err)
+		}
+
+		// The channels are returned in ascending order, so we can
+		// group them by block height as we go.
+		for _, dbChan := range channels {
+			// Don't send alias SCIDs during gossip sync.
+			if sqlChanAuthProof(dbChan) == nil {
+				continue
+			}
+
+			cid := lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(dbChan.Scid),
+			)
+
+			var lastRange *BlockChannelRange
+			if len(channelRanges) > 0 {
+				lastRange = &channelRanges[len(channelRanges)-1]
+			}
+			height := cid.BlockHeight
+			if lastRange == nil || lastRange.Height != height {
+				channelRanges = append(
+					channelRanges, BlockChannelRange{
+						Height: height,
+					},
+				)
+				lastRange = &channelRanges[len(channelRanges)-1]
+			}
+
+			lastRange.Channels = append(lastRange.Channels, cid)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return channelRanges, nil
+}
+
+// FetchChanInfos returns the set of channel edges that correspond to the passed
+// channel ID's. If an edge is the query is unknown to the database, it will
+// skipped and the result will contain only those edges that exist at the time
+// of the query.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) FetchChanInfos(chanIDs []uint64) ([]ChannelEdge,
+	error) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx    = NewGraphQueryReadTx()
+		chanEdges []ChannelEdge
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		chanEdges = nil
+
+		for _, chanID := range chanIDs {
+			dbChan, err := db.GetChannelBySCID(
+				ctx, chanIDToSCID(chanID),
+			)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				continue
+
+			case err != nil:
+				return fmt.Errorf("unable to fetch channel: %w",
+					err)
+			}
+
+			edge, err := s.buildSQLChannelEdge(ctx, db, dbChan)
+			if err != nil {
+				return err
+			}
+
+			chanEdges = append(chanEdges, *edge)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanEdges, nil
+}
+
+// DeleteChannelEdges removes edges with the given channel IDs from the
+// database and optionally marks them as zombies. If an edge does not exist
+// within the database, then ErrEdgeNotFound will be returned. If
+// strictZombiePruning is true, then only the node that failed to send a fresh
+// update will be able to resurrect the channel from its zombie state.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) DeleteChannelEdges(strictZombiePruning,
+	markZombie bool, chanIDs ...uint64) error {
+
+	ctx := context.TODO()
+
+	var (
+		writeTxOpts  GraphQueriesTxOptions
+		deletedChans []*ChannelEdgeInfo
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		deletedChans = nil
+
+		for _, chanID := range chanIDs {
+			scid := chanIDToSCID(chanID)
+			dbChan, err := db.GetChannelBySCID(ctx, scid)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEdgeNotFound
+
+			case err != nil:
+				return fmt.Errorf("unable to fetch channel: %w",
+					err)
+			}
+
+			edge, err := s.buildSQLChannelEdge(ctx, db, dbChan)
+			if err != nil {
+				return err
+			}
+
+			err = db.DeleteChannel(ctx, dbChan.ID)
+			if err != nil {
+				return fmt.Errorf("unable to delete "+
+					"channel: %w", err)
+			}
+
+			deletedChans = append(deletedChans, edge.Info)
+
+			if !markZombie {
+				continue
+			}
+
+			info := edge.Info
+			nodeKey1, nodeKey2 := info.NodeKey1Bytes,
+				info.NodeKey2Bytes
+			if strictZombiePruning {
+				nodeKey1, nodeKey2 = makeZombiePubkeys(
+					info, edge.Policy1, edge.Policy2,
+				)
+			}
+
+			err = db.UpsertZombieChannel(
+				ctx, sqlc.UpsertZombieChannelParams{
+					Scid:     scid,
+					NodeKey1: nodeKey1[:],
+					NodeKey2: nodeKey2[:],
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to mark channel as "+
+					"zombie: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removeCachedChannels(deletedChans)
+
+	return nil
+}
+
+// PruneGraph prunes newly closed channels from the channel graph in response
+// to a new block being solved on the network. Any transactions which spend the
+// funding output of any known channels within the graph will be deleted, and
+// the block is added to the prune log. A slice of channels that have been
+// closed by the target block are returned if the function succeeds without
+// error.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) PruneGraph(spentOutputs []*wire.OutPoint,
+	blockHash *chainhash.Hash, blockHeight uint32) ([]*ChannelEdgeInfo,
+	error) {
+
+	ctx := context.TODO()
+
+	var (
+		writeTxOpts GraphQueriesTxOptions
+		chansClosed []*ChannelEdgeInfo
+		prunedNodes []route.Vertex
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		chansClosed = nil
+
+		// For each of the outpoints that have been spent within the
+		// block, we attempt to delete them from the graph as if that
+		// outpoint was a channel, then it has now been closed.
+		for _, chanPoint := range spentOutputs {
+			dbChan, err := db.GetChannelByOutpoint(
+				ctx, chanPoint.String(),
+			)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				continue
+
+			case err != nil:
+				return fmt.Errorf("unable to fetch channel: %w",
+					err)
+			}
+
+			info, err := s.buildSQLEdgeInfo(ctx, db, dbChan)
+			if err != nil {
+				return err
+			}
+
+			err = db.DeleteChannel(ctx, dbChan.ID)
+			if err != nil {
+				return fmt.Errorf("unable to delete "+
+					"channel: %w", err)
+			}
+
+			chansClosed = append(chansClosed, info)
+		}
+
+		// With the graph pruned, add a new entry to the prune log,
+		// which can be used to check if the graph is fully synced with
+		// the current UTXO state.
+		pruneEntry := sqlc.UpsertPruneLogEntryParams{
+			BlockHeight: int64(blockHeight),
+			BlockHash:   blockHash[:],
+		}
+		err := db.UpsertPruneLogEntry(ctx, pruneEntry)
+		if err != nil {
+			return fmt.Errorf("unable to add prune log entry: %w",
+				err)
+		}
+
+		// Now that the graph has been pruned, we'll also attempt to
+		// prune any nodes that have had a channel closed within the
+		// latest block.
+		prunedNodes, err = pruneSQLGraphNodes(ctx, db)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeCachedChannels(chansClosed)
+	s.removeCachedNodes(prunedNodes)
+
+	return chansClosed, nil
+}
+
+// PruneTip returns the block height and hash of the latest block that has been
+// used to prune channels in the graph.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) PruneTip() (*chainhash.Hash, uint32, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx    = NewGraphQueryReadTx()
+		tipHash   chainhash.Hash
+		tipHeight uint32
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		pruneTip, err := db.GetPruneTip(ctx)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrGraphNeverPruned
+
+		case err != nil:
+			return fmt.Errorf("unable to fetch prune tip: %w", err)
+		}
+
+		copy(tipHash[:], pruneTip.BlockHash)
+		tipHeight = uint32(pruneTip.BlockHeight)
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &tipHash, tipHeight, nil
+}
+
+// DisconnectBlockAtHeight is used to indicate that the block specified by the
+// passed height has been disconnected from the main chain. This will "rewind"
+// the graph back to the height below, deleting channels that are no longer
+// confirmed from the graph. The prune log will be set to the last prune height
+// valid for the remaining chain. Channels that were removed from the graph
+// resulting from the disconnected block are returned.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) DisconnectBlockAtHeight(height uint32) (
+	[]*ChannelEdgeInfo, error) {
+
+	ctx := context.TODO()
+
+	// Every channel having a ShortChannelID starting at 'height' will no
+	// longer be confirmed. We delete everything up until the SCID alias
+	// range, which itself isn't deleted.
+	startShortChanID := lnwire.ShortChannelID{
+		BlockHeight: height,
+	}
+	endChanID := aliasmgr.StartingAlias.ToUint64() - 1
+
+	var (
+		writeTxOpts  GraphQueriesTxOptions
+		removedChans []*ChannelEdgeInfo
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		removedChans = nil
+
+		channels, err := db.GetChannelsBySCIDRange(
+			ctx, sqlc.GetChannelsBySCIDRangeParams{
+				StartScid: chanIDToSCID(
+					startShortChanID.ToUint64(),
+				),
+				EndScid: chanIDToSCID(endChanID),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch channels: %w", err)
+		}
+
+		for _, dbChan := range channels {
+			info, err := s.buildSQLEdgeInfo(ctx, db, dbChan)
+			if err != nil {
+				return err
+			}
+
+			err = db.DeleteChannel(ctx, dbChan.ID)
+			if err != nil {
+				return fmt.Errorf("unable to delete "+
+					"channel: %w", err)
+			}
+
+			removedChans = append(removedChans, info)
+		}
+
+		// Delete all the entries in the prune log having a height
+		// greater or equal to the block disconnected.
+		err = db.DeletePruneLogEntriesFrom(ctx, int64(height))
+		if err != nil {
+			return fmt.Errorf("unable to delete prune log "+
+				"entries: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeCachedChannels(removedChans)
+
+	return removedChans, nil
+}
+
+// MarkEdgeZombie attempts to mark a channel identified by its channel ID as a
+// zombie.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) MarkEdgeZombie(chanID uint64,
+	pubKey1, pubKey2 [33]byte) error {
+
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		zombie := sqlc.UpsertZombieChannelParams{
+			Scid:     chanIDToSCID(chanID),
+			NodeKey1: pubKey1[:],
+			NodeKey2: pubKey2[:],
+		}
+
+		return db.UpsertZombieChannel(ctx, zombie)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache != nil {
+		s.graphCache.RemoveChannel(pubKey1, pubKey2, chanID)
+	}
+
+	return nil
+}
+
+// MarkEdgeLive clears an edge from our zombie index, deeming it as live.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) MarkEdgeLive(chanID uint64) error {
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		return db.DeleteZombieChannel(ctx, chanIDToSCID(chanID))
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.graphCache == nil {
+		return nil
+	}
+
+	// We need to add the channel back into the graph cache, otherwise it
+	// won't be used for path finding.
+	edges, err := s.FetchChanInfos([]uint64{chanID})
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		s.graphCache.AddChannel(edge.Info, edge.Policy1, edge.Policy2)
+	}
+
+	return nil
+}
+
+// IsZombieEdge returns whether the edge is considered zombie. If it is a
+// zombie, then the two node public keys corresponding to this edge are also
+// returned.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) IsZombieEdge(chanID uint64) (bool, [33]byte,
+	[33]byte) {
+
+	ctx := context.TODO()
+
+	var (
+		readTx           = NewGraphQueryReadTx()
+		isZombie         bool
+		pubKey1, pubKey2 [33]byte
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		var err error
+		isZombie, pubKey1, pubKey2, err = fetchSQLZombie(
+			ctx, db, chanIDToSCID(chanID),
+		)
+
+		return err
+	})
+	if err != nil {
+		return false, [33]byte{}, [33]byte{}
+	}
+
+	return isZombie, pubKey1, pubKey2
+}
+
+// NumZombies returns the current number of zombie channels in the graph.
+//
+// NOTE: This is part of the GraphStore interface.
+func (s *SQLGraphStore) NumZombies() (uint64, error) {
+	ctx := context.TODO()
+
+	var (
+		readTx     = NewGraphQueryReadTx()
+		numZombies uint64
+	)
+	err := s.db.ExecTx(ctx, &readTx, func(db SQLGraphQueries) error {
+		count, err := db.CountZombieChannels(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to count zombies: %w", err)
+		}
+
+		numZombies = uint64(count)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numZombies, nil
+}
+
+// Wipe deletes the entire graph from the SQL tables, including the zombie
+// channels and the prune log. The deletion is done in a single transaction,
+// therefore this operation is fully atomic.
+func (s *SQLGraphStore) Wipe() error {
+	ctx := context.TODO()
+
+	var writeTxOpts GraphQueriesTxOptions
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLGraphQueries) error {
+		// The channels go first, as their policies reference the nodes
+		// without cascading. All details of the channels and nodes are
+		// removed along with them.
+		if err := db.DeleteAllChannels(ctx); err != nil {
+			return fmt.Errorf("unable to delete channels: %w", err)
+		}
+
+		if err := db.DeleteAllNodes(ctx); err != nil {
+			return fmt.Errorf("unable to delete nodes: %w", err)
+		}
+
+		if err := db.DeleteAllZombieChannels(ctx); err != nil {
+			return fmt.Errorf("unable to delete zombies: %w", err)
+		}
+
+		err := db.DeletePruneLogEntriesFrom(ctx, 0)
+		if err != nil {
+			return fmt.Errorf("unable to delete prune log: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// chanIDToSCID encodes the given channel ID as the 8 byte big endian short
+// channel ID used to key channels in the SQL graph store.
+func chanIDToSCID(chanID uint64) []byte {
+	var scid [8]byte
+	byteOrder.PutUint64(scid[:], chanID)
+
+	return scid[:]
+}
+
+// upsertSQLNode inserts the given node or updates it if it already exists,
+// replacing all of its announcement details. The id of the node is returned.
+func upsertSQLNode(ctx context.Context, db SQLGraphQueries,
+	node *LightningNode) (int32, error) {
+
+	params := sqlc.UpsertNodeParams{
+		PubKey:           node.PubKeyBytes[:],
+		HaveAnnouncement: node.HaveNodeAnnouncement,
+	}
+
+	// Like the key-value store, we record a zero update time if the node
+	// doesn't have one.
+	if node.LastUpdate.Unix() > 0 {
+		params.LastUpdate = node.LastUpdate.Unix()
+	}
+
+	// The remaining data is only known if we got a node announcement for
+	// this node.
+	var extraTypes tlv.TypeMap
+	if node.HaveNodeAnnouncement {
+		sigLen := len(node.AuthSigBytes)
+		if sigLen > 80 {
+			return 0, fmt.Errorf("max sig len allowed is 80, had "+
+				"%v", sigLen)
+		}
+
+		var err error
+		extraTypes, err = parseExtraTLVTypes(node.ExtraOpaqueData)
+		if err != nil {
+			return 0, err
+		}
+
+		params.Color = sql.NullString{
+			String: encodeSQLColor(node.Color),
+			Valid:  true,
+		}
+		params.Alias = sql.NullString{
+			String: node.Alias,
+			Valid:  true,
+		}
+		params.Signature = node.AuthSigBytes
+	}
+
+	nodeID, err := db.UpsertNode(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("unable to upsert node %x: %w",
+			node.PubKeyBytes, err)
+	}
+
+	// Remove the details of a previous announcement before adding the
+	// current ones.
+	if err := db.DeleteNodeFeatures(ctx, nodeID); err != nil {
+		return 0, fmt.Errorf("unable to delete node features: %w", err)
+	}
+	if err := db.DeleteNodeAddresses(ctx, nodeID); err != nil {
+		return 0, fmt.Errorf("unable to delete node addresses: %w", err)
+	}
+	if err := db.DeleteNodeExtraTypes(ctx, nodeID); err != nil {
+		return 0, fmt.Errorf("unable to delete node extra types: %w",
+			err)
+	}
+
+	if !node.HaveNodeAnnouncement {
+		return nodeID, nil
+	}
+
+	if node.Features != nil {
+		for bit := range node.Features.Features() {
+			err := db.InsertNodeFeature(
+				ctx, sqlc.InsertNodeFeatureParams{
+					NodeID:     nodeID,
+					FeatureBit: int32(bit),
+				},
+			)
+			if err != nil {
+				return 0, fmt.Errorf("unable to insert node "+
+					"feature %v: %w", bit, err)
+			}
+		}
+	}
+
+	for i, address := range node.Addresses {
+		addrType, addr, err := encodeSQLAddr(address)
+		if err != nil {
+			return 0, err
+		}
+
+		err = db.InsertNodeAddress(ctx, sqlc.InsertNodeAddressParams{
+			NodeID:   nodeID,
+			Position: int32(i),
+			Type:     int16(addrType),
+			Address:  addr,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert node address "+
+				"%v: %w", address, err)
+		}
+	}
+
+	for typ, value := range extraTypes {
+		extraType := sqlc.InsertNodeExtraTypeParams{
+			NodeID: nodeID,
+			Type:   int64(typ),
+			Value:  value,
+		}
+		err := db.InsertNodeExtraType(ctx, extraType)
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert node extra "+
+				"type %v: %w", typ, err)
+		}
+	}
+
+	return nodeID, nil
+}
+
+// fetchOrInsertSQLShellNode returns the id of the node with the given public
+// key, inserting a shell node that only includes its public key if it isn't
+// known yet.
+func fetchOrInsertSQLShellNode(ctx context.Context, db SQLGraphQueries,
+	pubKey [33]byte) (int32, error) {
+
+	dbNode, err := db.GetNodeByPubKey(ctx, pubKey[:])
+	switch {
+	case err == nil:
+		return dbNode.ID, nil
+
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, fmt.Errorf("unable to fetch node %x: %w", pubKey, err)
+	}
+
+	nodeID, err := db.InsertShellNode(ctx, pubKey[:])
+	if err != nil {
+		return 0, fmt.Errorf("unable to create shell node for: %x: %w",
+			pubKey, err)
+	}
+
+	return nodeID, nil
+}
+
+// fetchSQLNodeByID fetches the node with the given id along with all of its
+// announcement details.
+func (s *SQLGraphStore) fetchSQLNodeByID(ctx context.Context,
+	db SQLGraphQueries, nodeID int32) (*LightningNode, error) {
+
+	dbNode, err := db.GetNodeByID(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch node %v: %w", nodeID,
+			err)
+	}
+
+	return s.buildSQLNode(ctx, db, dbNode)
+}
+
+// buildSQLNode reconstructs a LightningNode from the given node row and its
+// announcement details.
+func (s *SQLGraphStore) buildSQLNode(ctx context.Context, db SQLGraphQueries,
+	dbNode sqlc.GraphNode) (*LightningNode, error) {
+
+	// Always populate a feature vector, even if we don't have a node
+	// announcement and short circuit below.
+	node := &LightningNode{
+		HaveNodeAnnouncement: dbNode.HaveAnnouncement,
+		LastUpdate:           time.Unix(dbNode.LastUpdate, 0),
+		Features:             lnwire.EmptyFeatureVector(),
+		sqlStore:             s,
+	}
+	copy(node.PubKeyBytes[:], dbNode.PubKey)
+
+	if !node.HaveNodeAnnouncement {
+		return node, nil
+	}
+
+	nodeColor, err := decodeSQLColor(dbNode.Color.String)
+	if err != nil {
+		return nil, err
+	}
+	node.Color = nodeColor
+	node.Alias = dbNode.Alias.String
+	node.AuthSigBytes = dbNode.Signature
+
+	bits, err := db.GetNodeFeatures(ctx, dbNode.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch node features: %w", err)
+	}
+	for _, bit := range bits {
+		node.Features.Set(lnwire.FeatureBit(bit))
+	}
+
+	addresses, err := db.GetNodeAddresses(ctx, dbNode.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch node addresses: %w",
+			err)
+	}
+	for _, address := range addresses {
+		addr, err := decodeSQLAddr(address.Type, address.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		node.Addresses = append(node.Addresses, addr)
+	}
+
+	dbExtraTypes, err := db.GetNodeExtraTypes(ctx, dbNode.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch node extra types: %w",
+			err)
+	}
+
+	extraTypes := make(tlv.TypeMap, len(dbExtraTypes))
+	for _, extraType := range dbExtraTypes {
+		extraTypes[tlv.Type(extraType.Type)] = extraType.Value
+	}
+
+	node.ExtraOpaqueData, err = encodeExtraTLVTypes(extraTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// pruneSQLGraphNodes removes all the nodes that no longer have any channels,
+// except for the source node. The public keys of the pruned nodes are
+// returned.
+func pruneSQLGraphNodes(ctx context.Context,
+	db SQLGraphQueries) ([]route.Vertex, error) {
+
+	log.Trace("Pruning nodes from graph with no open channels")
+
+	// Like the key-value store, we refuse to prune the graph without a
+	// source node.
+	_, err := db.GetSourceNodeID(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrSourceNodeNotSet
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to fetch source node: %w", err)
+	}
+
+	dbPrunedNodes, err := db.DeleteUnconnectedNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to prune nodes: %w", err)
+	}
+
+	prunedNodes := make([]route.Vertex, 0, len(dbPrunedNodes))
+	for _, nodePub := range dbPrunedNodes {
+		log.Infof("Pruned unconnected node %x from channel graph",
+			nodePub)
+
+		var prunedNode route.Vertex
+		copy(prunedNode[:], nodePub)
+		prunedNodes = append(prunedNodes, prunedNode)
+	}
+
+	if len(prunedNodes) > 0 {
+		log.Infof("Pruned %v unconnected nodes from the channel graph",
+			len(prunedNodes))
+	}
+
+	return prunedNodes, nil
+}
+
+// removeCachedChannels removes the given channels from the graph cache, if
+// the store has one.
+func (s *SQLGraphStore) removeCachedChannels(infos []*ChannelEdgeInfo) {
+	if s.graphCache == nil {
+		return
+	}
+
+	for _, info := range infos {
+		s.graphCache.RemoveChannel(
+			info.NodeKey1Bytes, info.NodeKey2Bytes, info.ChannelID,
+		)
+	}
+}
+
+// removeCachedNodes removes the given nodes from the graph cache, if the store
+// has one.
+func (s *SQLGraphStore) removeCachedNodes(nodes []route.Vertex) {
+	if s.graphCache == nil {
+		return
+	}
+
+	for _, node := range nodes {
+		s.graphCache.RemoveNode(node)
+	}
+}
+
+// parseSQLChannelDetails parses the feature vector and the extra opaque data
+// of the given channel into the form they are stored in the SQL graph store.
+func parseSQLChannelDetails(edge *ChannelEdgeInfo) (*lnwire.FeatureVector,
+	tlv.TypeMap, error) {
+
+	features := lnwire.EmptyFeatureVector()
+	if len(edge.Features) > 0 {
+		err := features.Decode(bytes.NewReader(edge.Features))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to decode channel "+
+				"features: %w", err)
+		}
+	}
+
+	extraTypes, err := parseExtraTLVTypes(edge.ExtraOpaqueData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return features, extraTypes, nil
+}
+
+// insertSQLChannel inserts the given channel, creating shell nodes for its two
+// nodes if they aren't known yet.
+func insertSQLChannel(ctx context.Context, db SQLGraphQueries,
+	edge *ChannelEdgeInfo) error {
+
+	features, extraTypes, err := parseSQLChannelDetails(edge)
+	if err != nil {
+		return err
+	}
+
+	// Before we insert the channel into the database, we'll ensure that
+	// both nodes already exist in the channel graph.
+	node1ID, err := fetchOrInsertSQLShellNode(ctx, db, edge.NodeKey1Bytes)
+	if err != nil {
+		return err
+	}
+	node2ID, err := fetchOrInsertSQLShellNode(ctx, db, edge.NodeKey2Bytes)
+	if err != nil {
+		return err
+	}
+
+	proof := edge.AuthProof
+	if proof == nil {
+		proof = &ChannelAuthProof{}
+	}
+
+	chanID, err := db.InsertChannel(ctx, sqlc.InsertChannelParams{
+		Scid:              chanIDToSCID(edge.ChannelID),
+		ChainHash:         edge.ChainHash[:],
+		Outpoint:          edge.ChannelPoint.String(),
+		Capacity:          int64(edge.Capacity),
+		NodeID1:           node1ID,
+		NodeID2:           node2ID,
+		BitcoinKey1:       edge.BitcoinKey1Bytes[:],
+		BitcoinKey2:       edge.BitcoinKey2Bytes[:],
+		Node1Signature:    proof.NodeSig1Bytes,
+		Node2Signature:    proof.NodeSig2Bytes,
+		Bitcoin1Signature: proof.BitcoinSig1Bytes,
+		Bitcoin2Signature: proof.BitcoinSig2Bytes,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to insert channel %v: %w",
+			edge.ChannelID, err)
+	}
+
+	return insertSQLChannelDetails(ctx, db, chanID, features, extraTypes)
+}
+
+// insertSQLChannelDetails inserts the feature bits and extra TLV records of
+// the channel with the given id.
+func insertSQLChannelDetails(ctx context.Context, db SQLGraphQueries,
+	chanID int32, features *lnwire.FeatureVector,
+	extraTypes tlv.TypeMap) error {
+
+	for bit := range features.Features() {
+		err := db.InsertChannelFeature(
+			ctx, sqlc.InsertChannelFeatureParams{
+				ChannelID:  chanID,
+				FeatureBit: int32(bit),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert channel feature "+
+				"%v: %w", bit, err)
+		}
+	}
+
+	for typ, value := range extraTypes {
+		err := db.InsertChannelExtraType(
+			ctx, sqlc.InsertChannelExtraTypeParams{
+				ChannelID: chanID,
+				Type:      int64(typ),
+				Value:     value,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert channel extra "+
+				"type %v: %w", typ, err)
+		}
+	}
+
+	return nil
+}
+
+// sqlChanAuthProof returns the announcement proof of the given channel, or nil
+// if the channel wasn't announced.
+func sqlChanAuthProof(dbChan sqlc.GraphChannel) *ChannelAuthProof {
+	proof := &ChannelAuthProof{
+		NodeSig1Bytes:    dbChan.Node1Signature,
+		NodeSig2Bytes:    dbChan.Node2Signature,
+		BitcoinSig1Bytes: dbChan.Bitcoin1Signature,
+		BitcoinSig2Bytes: dbChan.Bitcoin2Signature,
+	}
+	if proof.IsEmpty() {
+		return nil
+	}
+
+	return proof
+}
+
+// buildSQLEdgeInfo reconstructs a ChannelEdgeInfo from the given channel row
+// and its feature bits and extra TLV records.
+func (s *SQLGraphStore) buildSQLEdgeInfo(ctx context.Context,
+	db SQLGraphQueries, dbChan sqlc.GraphChannel) (*ChannelEdgeInfo,
+	error) {
+
+	node1, err := db.GetNodeByID(ctx, dbChan.NodeID1)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch node: %w", err)
+	}
+	node2, err := db.GetNodeByID(ctx, dbChan.NodeID2)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch node: %w", err)
+	}
+
+	chanPoint, err := wire.NewOutPointFromString(dbChan.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	edge := &ChannelEdgeInfo{
+		ChannelID:    byteOrder.Uint64(dbChan.Scid),
+		AuthProof:    sqlChanAuthProof(dbChan),
+		ChannelPoint: *chanPoint,
+		Capacity:     ltcutil.Amount(dbChan.Capacity),
+		sqlStore:     s,
+	}
+	copy(edge.ChainHash[:], dbChan.ChainHash)
+	copy(edge.NodeKey1Bytes[:], node1.PubKey)
+	copy(edge.NodeKey2Bytes[:], node2.PubKey)
+	copy(edge.BitcoinKey1Bytes[:], dbChan.BitcoinKey1)
+	copy(edge.BitcoinKey2Bytes[:], dbChan.BitcoinKey2)
+
+	// The features are stored as individual bits, so they are always
+	// returned in their canonical encoding.
+	bits, err := db.GetChannelFeatures(ctx, dbChan.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channel features: %w",
+			err)
+	}
+
+	features := lnwire.NewRawFeatureVector()
+	for _, bit := range bits {
+		features.Set(lnwire.FeatureBit(bit))
+	}
+
+	var b bytes.Buffer
+	if err := features.Encode(&b); err != nil {
+		return nil, err
+	}
+	edge.Features = b.Bytes()
+
+	dbExtraTypes, err := db.GetChannelExtraTypes(ctx, dbChan.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channel extra types: "+
+			"%w", err)
+	}
+
+	extraTypes := make(tlv.TypeMap, len(dbExtraTypes))
+	for _, extraType := range dbExtraTypes {
+		extraTypes[tlv.Type(extraType.Type)] = extraType.Value
+	}
+
+	edge.ExtraOpaqueData, err = encodeExtraTLVTypes(extraTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return edge, nil
+}
+
+// buildSQLChannelEdge reconstructs the info and the two policies of the given
+// channel row. Unknown policies are returned as nil.
+func (s *SQLGraphStore) buildSQLChannelEdge(ctx context.Context,
+	db SQLGraphQueries, dbChan sqlc.GraphChannel) (*ChannelEdge, error) {
+
+	info, err := s.buildSQLEdgeInfo(ctx, db, dbChan)
+	if err != nil {
+		return nil, err
+	}
+
+	policy1, err := s.fetchSQLPolicy(
+		ctx, db, dbChan, dbChan.NodeID1, dbChan.NodeID2,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	policy2, err := s.fetchSQLPolicy(
+		ctx, db, dbChan, dbChan.NodeID2, dbChan.NodeID1,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChannelEdge{
+		Info:    info,
+		Policy1: policy1,
+		Policy2: policy2,
+	}, nil
+}
+
+// forEachSQLChannel calls the given callback for each channel row in the
+// database, loading the channels page by page.
+func forEachSQLChannel(ctx context.Context, db SQLGraphQueries,
+	cb func(sqlc.GraphChannel) error) error {
+
+	var lastID int32
+	for {
+		channels, err := db.ListChannelsPaginated(
+			ctx, sqlc.ListChannelsPaginatedParams{
+				IDAfter:  lastID,
+				NumLimit: graphQueryPageSize,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch channels: %w", err)
+		}
+
+		for _, dbChan := range channels {
+			if err := cb(dbChan); err != nil {
+				return err
+			}
+		}
+
+		if len(channels) < graphQueryPageSize {
+			return nil
+		}
+
+		lastID = channels[len(channels)-1].ID
+	}
+}
+
+// upsertSQLPolicy inserts or replaces the policy of the given channel in the
+// direction signaled by its channel flags, returning the channel the policy
+// belongs to. ErrEdgeNotFound is returned if the channel isn't known.
+func upsertSQLPolicy(ctx context.Context, db SQLGraphQueries,
+	edge *ChannelEdgePolicy) (sqlc.GraphChannel, error) {
+
+	var dbChan sqlc.GraphChannel
+
+	extraTypes, err := parseExtraTLVTypes(edge.ExtraOpaqueData)
+	if err != nil {
+		return dbChan, err
+	}
+
+	dbChan, err = db.GetChannelBySCID(ctx, chanIDToSCID(edge.ChannelID))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return dbChan, ErrEdgeNotFound
+
+	case err != nil:
+		return dbChan, fmt.Errorf("unable to fetch channel: %w", err)
+	}
+
+	// Depending on the flags value passed above, either the first or
+	// second edge policy is being updated.
+	nodeID := dbChan.NodeID1
+	if edge.ChannelFlags&lnwire.ChanUpdateDirection != 0 {
+		nodeID = dbChan.NodeID2
+	}
+
+	var maxHtlc sql.NullInt64
+	if edge.MessageFlags.HasMaxHtlc() {
+		maxHtlc = sql.NullInt64{
+			Int64: int64(edge.MaxHTLC),
+			Valid: true,
+		}
+	}
+
+	policyID, err := db.UpsertChannelPolicy(
+		ctx, sqlc.UpsertChannelPolicyParams{
+			ChannelID:    dbChan.ID,
+			NodeID:       nodeID,
+			LastUpdate:   edge.LastUpdate.Unix(),
+			MessageFlags: int16(edge.MessageFlags),
+			ChannelFlags: int16(edge.ChannelFlags),
+			Timelock:     int32(edge.TimeLockDelta),
+			MinHtlcMsat:  int64(edge.MinHTLC),
+			MaxHtlcMsat:  maxHtlc,
+			BaseFeeMsat:  int64(edge.FeeBaseMSat),
+			FeePpm:       int64(edge.FeeProportionalMillionths),
+			Signature:    edge.SigBytes,
+		},
+	)
+	if err != nil {
+		return dbChan, fmt.Errorf("unable to upsert policy: %w", err)
+	}
+
+	err = db.DeleteChannelPolicyExtraTypes(ctx, policyID)
+	if err != nil {
+		return dbChan, fmt.Errorf("unable to delete policy extra "+
+			"types: %w", err)
+	}
+
+	for typ, value := range extraTypes {
+		err := db.InsertChannelPolicyExtraType(
+			ctx, sqlc.InsertChannelPolicyExtraTypeParams{
+				ChannelPolicyID: policyID,
+				Type:            int64(typ),
+				Value:           value,
+			},
+		)
+		if err != nil {
+			return dbChan, fmt.Errorf("unable to insert policy "+
+				"extra type %v: %w", typ, err)
+		}
+	}
+
+	return dbChan, nil
+}
+
+// fetchSQLPolicy fetches the policy advertised by the node with the given id
+// for the given channel. The policy's Node is set to the node on the other end
+// of the channel. Nil is returned if the policy isn't known.
+func (s *SQLGraphStore) fetchSQLPolicy(ctx context.Context,
+	db SQLGraphQueries, dbChan sqlc.GraphChannel, fromNodeID,
+	toNodeID int32) (*ChannelEdgePolicy, error) {
+
+	dbPolicy, err := db.GetChannelPolicy(ctx, sqlc.GetChannelPolicyParams{
+		ChannelID: dbChan.ID,
+		NodeID:    fromNodeID,
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to fetch policy: %w", err)
+	}
+
+	// A policy that signals a max HTLC value without having one is
+	// handled as unknown, like the key-value store does.
+	msgFlags := lnwire.ChanUpdateMsgFlags(dbPolicy.MessageFlags)
+	if msgFlags.HasMaxHtlc() && !dbPolicy.MaxHtlcMsat.Valid {
+		return nil, nil
+	}
+
+	toNode, err := s.fetchSQLNodeByID(ctx, db, toNodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	chanFlags := lnwire.ChanUpdateChanFlags(dbPolicy.ChannelFlags)
+
+	policy := &ChannelEdgePolicy{
+		SigBytes:      dbPolicy.Signature,
+		ChannelID:     byteOrder.Uint64(dbChan.Scid),
+		LastUpdate:    time.Unix(dbPolicy.LastUpdate, 0),
+		MessageFlags:  msgFlags,
+		ChannelFlags:  chanFlags,
+		TimeLockDelta: uint16(dbPolicy.Timelock),
+		MinHTLC:       lnwire.MilliSatoshi(dbPolicy.MinHtlcMsat),
+		MaxHTLC:       lnwire.MilliSatoshi(dbPolicy.MaxHtlcMsat.Int64),
+		FeeBaseMSat:   lnwire.MilliSatoshi(dbPolicy.BaseFeeMsat),
+		FeeProportionalMillionths: lnwire.MilliSatoshi(
+			dbPolicy.FeePpm,
+		),
+		Node: toNode,
+	}
+
+	dbExtraTypes, err := db.GetChannelPolicyExtraTypes(ctx, dbPolicy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch policy extra types: %w",
+			err)
+	}
+
+	extraTypes := make(tlv.TypeMap, len(dbExtraTypes))
+	for _, extraType := range dbExtraTypes {
+		extraTypes[tlv.Type(extraType.Type)] = extraType.Value
+	}
+
+	policy.ExtraOpaqueData, err = encodeExtraTLVTypes(extraTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// fetchSQLZombie returns whether the channel with the given SCID is a zombie,
+// along with the public keys of the nodes that may resurrect it.
+func fetchSQLZombie(ctx context.Context, db SQLGraphQueries,
+	scid []byte) (bool, [33]byte, [33]byte, error) {
+
+	var pubKey1, pubKey2 [33]byte
+
+	zombie, err := db.GetZombieChannel(ctx, scid)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, pubKey1, pubKey2, nil
+
+	case err != nil:
+		return false, pubKey1, pubKey2, fmt.Errorf("unable to fetch "+
+			"zombie channel: %w", err)
+	}
+
+	copy(pubKey1[:], zombie.NodeKey1)
+	copy(pubKey2[:], zombie.NodeKey2)
+
+	return true, pubKey1, pubKey2, nil
+}
+
+// parseExtraTLVTypes parses the given extra opaque data as a TLV stream and
+// returns its records. ErrParsingExtraTLVBytes is returned if the data isn't a
+// valid TLV stream.
+func parseExtraTLVTypes(extraData []byte) (tlv.TypeMap, error) {
+	if len(extraData) > MaxAllowedExtraOpaqueBytes {
+		return nil, ErrTooManyExtraOpaqueBytes(len(extraData))
+	}
+
+	if len(extraData) == 0 {
+		return nil, nil
+	}
+
+	stream, err := tlv.NewStream()
+	if err != nil {
+		return nil, err
+	}
+
+	extraTypes, err := stream.DecodeWithParsedTypes(
+		bytes.NewReader(extraData),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingExtraTLVBytes, err)
+	}
+
+	return extraTypes, nil
+}
+
+// encodeExtraTLVTypes serializes the given TLV records in ascending type order,
+// recreating the extra opaque data they were parsed from.
+func encodeExtraTLVTypes(extraTypes tlv.TypeMap) ([]byte, error) {
+	if len(extraTypes) == 0 {
+		return nil, nil
+	}
+
+	types := make([]tlv.Type, 0, len(extraTypes))
+	for typ := range extraTypes {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	var (
+		b       bytes.Buffer
+		scratch [8]byte
+	)
+	for _, typ := range types {
+		value := extraTypes[typ]
+
+		err := tlv.WriteVarInt(&b, uint64(typ), &scratch)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tlv.WriteVarInt(&b, uint64(len(value)), &scratch)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := b.Write(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// encodeSQLColor encodes the given color in the #rrggbb format.
+func encodeSQLColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// decodeSQLColor decodes a color in the #rrggbb format.
+func decodeSQLColor(c string) (color.RGBA, error) {
+	rgb, err := hex.DecodeString(strings.TrimPrefix(c, "#"))
+	if err != nil || len(rgb) != 3 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", c)
+	}
+
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2]}, nil
+}
+
+// encodeSQLAddr returns the address type and the host:port representation of
+// the given address.
+func encodeSQLAddr(address net.Addr) (addressType, string, error) {
+	switch addr := address.(type) {
+	case *net.TCPAddr:
+		addrType := tcp6Addr
+		if addr.IP.To4() != nil {
+			addrType = tcp4Addr
+		}
+
+		return addrType, net.JoinHostPort(
+			addr.IP.String(), strconv.Itoa(addr.Port),
+		), nil
+
+	case *tor.OnionAddr:
+		switch len(addr.OnionService) {
+		case tor.V2Len:
+			return v2OnionAddr, addr.String(), nil
+
+		case tor.V3Len:
+			return v3OnionAddr, addr.String(), nil
+
+		default:
+			return 0, "", errors.New("unknown onion service length")
+		}
+
+	default:
+		return 0, "", ErrUnknownAddressType
+	}
+}
+
+// decodeSQLAddr parses an address of the given type in the host:port format.
+func decodeSQLAddr(addrType int16, address string) (net.Addr, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch addressType(addrType) {
+	case tcp4Addr, tcp6Addr:
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %v", host)
+		}
+
+		// Like the key-value store, IPv4 addresses are returned in
+		// their 4 byte representation.
+		if addressType(addrType) == tcp4Addr {
+			ip = ip.To4()
+		}
+
+		return &net.TCPAddr{
+			IP:   ip,
+			Port: port,
+		}, nil
+
+	case v2OnionAddr, v3OnionAddr:
+		return &tor.OnionAddr{
+			OnionService: host,
+			Port:         port,
+		}, nil
+
+	default:
+		return nil, ErrUnknownAddressType
+	}
+}
@@ -0,0 +1,330 @@
+package channeldb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/kvdb"
+	"github.com/ltcsuite/lnd/sqldb/sqlc"
+)
+
+// graphMigrationStats keeps track of the number of graph entries that were
+// copied over to the SQL graph store and of those whose extra opaque data had
+// to be dropped.
+type graphMigrationStats struct {
+	numNodes       int
+	numChannels    int
+	numPolicies    int
+	numZombies     int
+	numPruneLogs   int
+	numNoExtraData int
+}
+
+// MigrateGraphToSQL copies the channel graph from the key-value store of the
+// given graph into the native SQL graph store. This includes all nodes, the
+// source node, all channels and their policies, the zombie index and the prune
+// log. The migration is applied in a single SQL transaction and is skipped if
+// the SQL store already contains nodes, so it is safe to call on every start
+// up. The graph is left untouched in the key-value store.
+//
+// NOTE: Extra opaque data that isn't a valid TLV stream can't be represented in
+// the SQL schema. Nodes, channels and policies carrying such data are migrated
+// without it, and the number of affected entries is reported. Gossip will
+// replace them once new announcements are received.
+func MigrateGraphToSQL(ctx context.Context, graph *ChannelGraph,
+	sqlStore *SQLGraphStore) error {
+
+	var (
+		writeTxOpts GraphQueriesTxOptions
+		skipped     bool
+		stats       graphMigrationStats
+	)
+	err := sqlStore.db.ExecTx(ctx, &writeTxOpts,
+		func(db SQLGraphQueries) error {
+			skipped, stats = false, graphMigrationStats{}
+
+			count, err := db.CountNodes(ctx)
+			if err != nil {
+				return fmt.Errorf("unable to count nodes: %w",
+					err)
+			}
+
+			// The SQL store is only ever populated after the
+			// migration, so there's nothing left to do if it
+			// already contains nodes.
+			if count > 0 {
+				skipped = true
+				return nil
+			}
+
+			return kvdb.View(graph.db, func(tx kvdb.RTx) error {
+				stats = graphMigrationStats{}
+
+				return migrateGraphToSQL(ctx, tx, db, &stats)
+			}, func() {})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to migrate graph to SQL: %w", err)
+	}
+
+	if skipped {
+		log.Debugf("Channel graph already migrated to SQL store")
+		return nil
+	}
+
+	log.Infof("Migrated %d nodes, %d channels, %d policies, %d zombies "+
+		"and %d prune log entries to SQL store", stats.numNodes,
+		stats.numChannels, stats.numPolicies, stats.numZombies,
+		stats.numPruneLogs)
+
+	if stats.numNoExtraData > 0 {
+		log.Warnf("Migrated %d graph entries without their extra "+
+			"opaque data as it isn't a valid TLV stream",
+			stats.numNoExtraData)
+	}
+
+	return nil
+}
+
+// migrateGraphToSQL inserts all the graph entries found in the key-value
+// graph buckets into the SQL store.
+func migrateGraphToSQL(ctx context.Context, tx kvdb.RTx, db SQLGraphQueries,
+	stats *graphMigrationStats) error {
+
+	// Nodes must be migrated first, so that the channels can reference
+	// them.
+	if err := migrateSQLGraphNodes(ctx, tx, db, stats); err != nil {
+		return err
+	}
+
+	if err := migrateSQLGraphChannels(ctx, tx, db, stats); err != nil {
+		return err
+	}
+
+	if err := migrateSQLGraphZombies(ctx, tx, db, stats); err != nil {
+		return err
+	}
+
+	return migrateSQLGraphPruneLog(ctx, tx, db, stats)
+}
+
+// migrateSQLGraphNodes inserts all nodes of the key-value node bucket into the
+// SQL store and marks the source node.
+func migrateSQLGraphNodes(ctx context.Context, tx kvdb.RTx,
+	db SQLGraphQueries, stats *graphMigrationStats) error {
+
+	nodes := tx.ReadBucket(nodeBucket)
+	if nodes == nil {
+		return nil
+	}
+
+	err := nodes.ForEach(func(pubKey, nodeBytes []byte) error {
+		// The source key maps to the public key of the source node
+		// rather than to the node information, so we skip it here.
+		if bytes.Equal(pubKey, sourceKey) || len(pubKey) != 33 {
+			return nil
+		}
+
+		node, err := deserializeLightningNode(
+			bytes.NewReader(nodeBytes),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to deserialize node %x: %w",
+				pubKey, err)
+		}
+
+		_, err = upsertSQLNode(ctx, db, &node)
+		if errors.Is(err, ErrParsingExtraTLVBytes) {
+			log.Warnf("Migrating node %x without its extra "+
+				"opaque data: %v", pubKey, err)
+			stats.numNoExtraData++
+
+			node.ExtraOpaqueData = nil
+			_, err = upsertSQLNode(ctx, db, &node)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to migrate node %x: %w",
+				pubKey, err)
+		}
+
+		stats.numNodes++
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sourcePub := nodes.Get(sourceKey)
+	if sourcePub == nil {
+		return nil
+	}
+
+	// The source node was migrated along with all other nodes above, so
+	// it only needs to be marked. Should its entry be missing, it is added
+	// as a shell node which will be replaced once we announce ourselves
+	// again.
+	var pubKey [33]byte
+	copy(pubKey[:], sourcePub)
+	sourceID, err := fetchOrInsertSQLShellNode(ctx, db, pubKey)
+	if err != nil {
+		return err
+	}
+
+	return db.InsertSourceNode(ctx, sourceID)
+}
+
+// migrateSQLGraphChannels inserts all channels of the key-value edge index,
+// along with their policies, into the SQL store.
+func migrateSQLGraphChannels(ctx context.Context, tx kvdb.RTx,
+	db SQLGraphQueries, stats *graphMigrationStats) error {
+
+	edges := tx.ReadBucket(edgeBucket)
+	if edges == nil {
+		return nil
+	}
+	edgeIndex := edges.NestedReadBucket(edgeIndexBucket)
+	if edgeIndex == nil {
+		return nil
+	}
+	nodes := tx.ReadBucket(nodeBucket)
+	if nodes == nil {
+		return ErrGraphNodesNotFound
+	}
+
+	return edgeIndex.ForEach(func(chanID, edgeInfoBytes []byte) error {
+		info, err := deserializeChanEdgeInfo(
+			bytes.NewReader(edgeInfoBytes),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to deserialize channel "+
+				"%x: %w", chanID, err)
+		}
+
+		err = insertSQLChannel(ctx, db, &info)
+		if errors.Is(err, ErrParsingExtraTLVBytes) {
+			log.Warnf("Migrating channel %v without its extra "+
+				"opaque data: %v", info.ChannelID, err)
+			stats.numNoExtraData++
+
+			info.ExtraOpaqueData = nil
+			err = insertSQLChannel(ctx, db, &info)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to migrate channel %v: %w",
+				info.ChannelID, err)
+		}
+
+		stats.numChannels++
+
+		policy1, policy2, err := fetchChanEdgePolicies(
+			edgeIndex, edges, nodes, chanID, nil,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch policies of "+
+				"channel %v: %w", info.ChannelID, err)
+		}
+
+		for _, policy := range []*ChannelEdgePolicy{policy1, policy2} {
+			if policy == nil {
+				continue
+			}
+
+			_, err := upsertSQLPolicy(ctx, db, policy)
+			if errors.Is(err, ErrParsingExtraTLVBytes) {
+				log.Warnf("Migrating policy of channel %v "+
+					"without its extra opaque data: %v",
+					info.ChannelID, err)
+				stats.numNoExtraData++
+
+				policy.ExtraOpaqueData = nil
+				_, err = upsertSQLPolicy(ctx, db, policy)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to migrate policy "+
+					"of channel %v: %w", info.ChannelID,
+					err)
+			}
+
+			stats.numPolicies++
+		}
+
+		return nil
+	})
+}
+
+// migrateSQLGraphZombies inserts all entries of the key-value zombie index
+// into the SQL store.
+func migrateSQLGraphZombies(ctx context.Context, tx kvdb.RTx,
+	db SQLGraphQueries, stats *graphMigrationStats) error {
+
+	edges := tx.ReadBucket(edgeBucket)
+	if edges == nil {
+		return nil
+	}
+	zombieIndex := edges.NestedReadBucket(zombieBucket)
+	if zombieIndex == nil {
+		return nil
+	}
+
+	return zombieIndex.ForEach(func(chanID, pubKeys []byte) error {
+		if len(chanID) != 8 || len(pubKeys) != 66 {
+			return fmt.Errorf("invalid zombie index entry %x",
+				chanID)
+		}
+
+		zombie := sqlc.UpsertZombieChannelParams{
+			Scid:     chanID,
+			NodeKey1: pubKeys[:33],
+			NodeKey2: pubKeys[33:],
+		}
+		err := db.UpsertZombieChannel(ctx, zombie)
+		if err != nil {
+			return fmt.Errorf("unable to migrate zombie %x: %w",
+				chanID, err)
+		}
+
+		stats.numZombies++
+
+		return nil
+	})
+}
+
+// migrateSQLGraphPruneLog inserts all entries of the key-value prune log into
+// the SQL store.
+func migrateSQLGraphPruneLog(ctx context.Context, tx kvdb.RTx,
+	db SQLGraphQueries, stats *graphMigrationStats) error {
+
+	graphMeta := tx.ReadBucket(graphMetaBucket)
+	if graphMeta == nil {
+		return nil
+	}
+	pruneBucket := graphMeta.NestedReadBucket(pruneLogBucket)
+	if pruneBucket == nil {
+		return nil
+	}
+
+	return pruneBucket.ForEach(func(height, blockHash []byte) error {
+		if len(height) != 4 {
+			return fmt.Errorf("invalid prune log entry %x", height)
+		}
+
+		blockHeight := byteOrder.Uint32(height)
+		pruneEntry := sqlc.UpsertPruneLogEntryParams{
+			BlockHeight: int64(blockHeight),
+			BlockHash:   blockHash,
+		}
+		err := db.UpsertPruneLogEntry(ctx, pruneEntry)
+		if err != nil {
+			return fmt.Errorf("unable to migrate prune log entry "+
+				"%d: %w", blockHeight, err)
+		}
+
+		stats.numPruneLogs++
+
+		return nil
+	})
+}
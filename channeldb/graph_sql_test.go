@@ -0,0 +1,361 @@
+package channeldb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/sqldb"
+	"github.com/ltcsuite/lnd/sqldb/sqlc"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// testExtraTLVData is a valid TLV stream that is used as extra opaque
+	// data in the graph store tests, as the SQL graph store only accepts
+	// TLV encoded extra data.
+	testExtraTLVData = []byte{0x01, 0x02, 0xab, 0xcd, 0xfd, 0x01, 0x00,
+		0x01, 0xef}
+)
+
+// newTestSQLGraphStore creates a new SQL graph store backed by a fresh sqlite
+// database.
+func newTestSQLGraphStore(t testing.TB) *SQLGraphStore {
+	t.Helper()
+
+	dbFileName := filepath.Join(t.TempDir(), "graph.db")
+	sqlDB, err := sqldb.NewSqliteStore(&sqldb.SqliteConfig{
+		DatabaseFileName: dbFileName,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, sqlDB.DB.Close())
+	})
+
+	db := sqlDB.BaseDB
+	executor := sqldb.NewTransactionExecutor(
+		db, func(tx *sql.Tx) SQLGraphQueries {
+			return db.WithTx(tx)
+		},
+	)
+
+	return NewSQLGraphStore(executor)
+}
+
+// createSQLTestEdge creates a channel between the two given nodes at the
+// given block height, using data that can be represented by all graph stores.
+func createSQLTestEdge(t *testing.T, height uint32, outPointIndex uint32,
+	node1, node2 *LightningNode) *ChannelEdgeInfo {
+
+	t.Helper()
+
+	edge, _ := createEdge(height, 0, 0, outPointIndex, node1, node2)
+
+	var features bytes.Buffer
+	require.NoError(t, testFeatures.Encode(&features))
+	edge.Features = features.Bytes()
+	edge.ExtraOpaqueData = testExtraTLVData
+
+	return &edge
+}
+
+// createSQLTestPolicy creates a policy for the given channel announced by the
+// first or the second node of the channel.
+func createSQLTestPolicy(edge *ChannelEdgeInfo, toNode *LightningNode,
+	direction lnwire.ChanUpdateChanFlags,
+	updateTime int64) *ChannelEdgePolicy {
+
+	policy := newEdgePolicy(edge.ChannelID, nil, updateTime)
+	policy.SigBytes = testSig.Serialize()
+	policy.ChannelFlags = direction
+	policy.TimeLockDelta = 40
+	policy.MinHTLC = 1000
+	policy.MaxHTLC = 1_000_000
+	policy.FeeBaseMSat = 1000
+	policy.FeeProportionalMillionths = 10
+	policy.ExtraOpaqueData = testExtraTLVData
+	policy.Node = toNode
+
+	return policy
+}
+
+// addSQLTestNodes adds the given number of nodes to the store.
+func addSQLTestNodes(t *testing.T, store GraphStore,
+	numNodes int) []*LightningNode {
+
+	t.Helper()
+
+	nodes := make([]*LightningNode, 0, numNodes)
+	for i := 0; i < numNodes; i++ {
+		node, err := createTestVertex(nil)
+		require.NoError(t, err)
+		node.ExtraOpaqueData = testExtraTLVData
+
+		require.NoError(t, store.AddLightningNode(node))
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// TestSQLGraphStoreInvalidExtraData asserts that the SQL graph store rejects
+// extra opaque data that isn't a valid TLV stream.
+func TestSQLGraphStoreInvalidExtraData(t *testing.T) {
+	t.Parallel()
+
+	store := newTestSQLGraphStore(t)
+
+	node, err := createTestVertex(nil)
+	require.NoError(t, err)
+	node.ExtraOpaqueData = []byte("new unknown feature")
+
+	err = store.AddLightningNode(node)
+	require.ErrorIs(t, err, ErrParsingExtraTLVBytes)
+
+	node.ExtraOpaqueData = nil
+	require.NoError(t, store.AddLightningNode(node))
+
+	edge := createSQLTestEdge(t, 100, 0, node, node)
+	edge.ExtraOpaqueData = []byte("new unknown feature")
+	err = store.AddChannelEdge(edge)
+	require.ErrorIs(t, err, ErrParsingExtraTLVBytes)
+}
+
+// TestMigrateGraphToSQL tests that the channel graph is copied over from the
+// key-value store to the SQL graph store.
+func TestMigrateGraphToSQL(t *testing.T) {
+	t.Parallel()
+
+	graph, err := makeTestGraph(t, nil)
+	require.NoError(t, err)
+
+	nodes := addSQLTestNodes(t, graph, 3)
+	require.NoError(t, graph.SetSourceNode(nodes[0]))
+
+	// Extra data that isn't a TLV stream can't be stored in the SQL
+	// store, so the node, channel and policy carrying it are migrated
+	// without it.
+	invalidNode, err := createTestVertex(nil)
+	require.NoError(t, err)
+	invalidNode.ExtraOpaqueData = []byte("new unknown feature")
+	require.NoError(t, graph.AddLightningNode(invalidNode))
+
+	edge1 := createSQLTestEdge(t, 100, 0, nodes[0], nodes[1])
+	edge2 := createSQLTestEdge(t, 101, 1, nodes[1], invalidNode)
+	edge2.ExtraOpaqueData = []byte("new unknown feature")
+	edge3 := createSQLTestEdge(t, 102, 2, nodes[1], nodes[2])
+	for _, edge := range []*ChannelEdgeInfo{edge1, edge2, edge3} {
+		require.NoError(t, graph.AddChannelEdge(edge))
+	}
+
+	policy1 := createSQLTestPolicy(edge1, nodes[1], 0, 1000)
+	policy2 := createSQLTestPolicy(
+		edge1, nodes[0], lnwire.ChanUpdateDirection, 2000,
+	)
+	policy3 := createSQLTestPolicy(edge2, invalidNode, 0, 3000)
+	policy3.ExtraOpaqueData = []byte("new unknown feature")
+	require.NoError(t, graph.UpdateEdgePolicy(policy1))
+	require.NoError(t, graph.UpdateEdgePolicy(policy2))
+	require.NoError(t, graph.UpdateEdgePolicy(policy3))
+
+	zombieChanID := edge3.ChannelID + 1
+	require.NoError(t, graph.MarkEdgeZombie(
+		zombieChanID, nodes[1].PubKeyBytes, nodes[2].PubKeyBytes,
+	))
+	_, err = graph.PruneGraph(nil, &chainhash.Hash{1}, 103)
+	require.NoError(t, err)
+
+	store := newTestSQLGraphStore(t)
+	require.NoError(t, MigrateGraphToSQL(
+		context.Background(), graph, store,
+	))
+
+	sourceNode, err := store.SourceNode()
+	require.NoError(t, err)
+	require.NoError(t, compareNodes(nodes[0], sourceNode))
+
+	for _, node := range nodes {
+		dbNode, err := store.FetchLightningNode(node.PubKeyBytes)
+		require.NoError(t, err)
+		require.NoError(t, compareNodes(node, dbNode))
+	}
+
+	dbNode, err := store.FetchLightningNode(invalidNode.PubKeyBytes)
+	require.NoError(t, err)
+	invalidNode.ExtraOpaqueData = nil
+	require.NoError(t, compareNodes(invalidNode, dbNode))
+
+	dbEdge, dbPolicy1, dbPolicy2, err := store.FetchChannelEdgesByID(
+		edge1.ChannelID,
+	)
+	require.NoError(t, err)
+	assertEdgeInfoEqual(t, edge1, dbEdge)
+	require.NoError(t, compareEdgePolicies(policy1, dbPolicy1))
+	require.NoError(t, compareEdgePolicies(policy2, dbPolicy2))
+
+	dbEdge, dbPolicy1, _, err = store.FetchChannelEdgesByID(
+		edge2.ChannelID,
+	)
+	require.NoError(t, err)
+	edge2.ExtraOpaqueData = nil
+	assertEdgeInfoEqual(t, edge2, dbEdge)
+	policy3.ExtraOpaqueData = nil
+	require.NoError(t, compareEdgePolicies(policy3, dbPolicy1))
+
+	_, _, _, err = store.FetchChannelEdgesByID(edge3.ChannelID)
+	require.NoError(t, err)
+
+	isZombie, pub1, pub2 := store.IsZombieEdge(zombieChanID)
+	require.True(t, isZombie)
+	require.Equal(t, nodes[1].PubKeyBytes, pub1)
+	require.Equal(t, nodes[2].PubKeyBytes, pub2)
+
+	tipHash, tipHeight, err := store.PruneTip()
+	require.NoError(t, err)
+	require.Equal(t, chainhash.Hash{1}, *tipHash)
+	require.EqualValues(t, 103, tipHeight)
+
+	// Running the migration again is a no-op, even if the key-value
+	// graph has changed in the mean time.
+	require.NoError(t, graph.DeleteLightningNode(nodes[2].PubKeyBytes))
+	require.NoError(t, MigrateGraphToSQL(
+		context.Background(), graph, store,
+	))
+
+	_, err = store.FetchLightningNode(nodes[2].PubKeyBytes)
+	require.NoError(t, err)
+}
+
+// TestChannelGraphWipeSQL tests that wiping a channel graph that is backed by
+// the native SQL graph store clears the SQL tables.
+func TestChannelGraphWipeSQL(t *testing.T) {
+	t.Parallel()
+
+	store := newTestSQLGraphStore(t)
+	graph, err := makeTestGraph(t, store)
+	require.NoError(t, err)
+
+	nodes := addSQLTestNodes(t, graph, 2)
+	require.NoError(t, graph.SetSourceNode(nodes[0]))
+
+	edge := createSQLTestEdge(t, 100, 0, nodes[0], nodes[1])
+	require.NoError(t, graph.AddChannelEdge(edge))
+	require.NoError(t, graph.UpdateEdgePolicy(
+		createSQLTestPolicy(edge, nodes[1], 0, 1000),
+	))
+
+	zombieChanID := edge.ChannelID + 1
+	require.NoError(t, graph.MarkEdgeZombie(
+		zombieChanID, nodes[0].PubKeyBytes, nodes[1].PubKeyBytes,
+	))
+	_, err = graph.PruneGraph(nil, &chainhash.Hash{1}, 101)
+	require.NoError(t, err)
+
+	require.NoError(t, graph.Wipe())
+
+	_, err = store.SourceNode()
+	require.ErrorIs(t, err, ErrSourceNodeNotSet)
+
+	for _, node := range nodes {
+		_, err := store.FetchLightningNode(node.PubKeyBytes)
+		require.ErrorIs(t, err, ErrGraphNodeNotFound)
+	}
+
+	_, _, _, err = store.FetchChannelEdgesByID(edge.ChannelID)
+	require.ErrorIs(t, err, ErrEdgeNotFound)
+
+	numZombies, err := store.NumZombies()
+	require.NoError(t, err)
+	require.Zero(t, numZombies)
+
+	_, _, err = store.PruneTip()
+	require.ErrorIs(t, err, ErrGraphNeverPruned)
+}
+
+// checkSQLUpdateTimestamps asserts that the channel updates of the given graph
+// that is backed by the native SQL graph store have exactly the given
+// timestamps, and are found in the order of their earliest policy update.
+func checkSQLUpdateTimestamps(t *testing.T, graph *ChannelGraph,
+	timestamps map[uint64]struct{}) {
+
+	t.Helper()
+
+	edges, err := graph.ChanUpdatesInHorizon(
+		time.Unix(0, 0), time.Unix(math.MaxInt64, 0),
+	)
+	require.NoError(t, err)
+
+	found := make(map[uint64]struct{})
+	var prevEarliest int64
+	for _, edge := range edges {
+		earliest := int64(math.MaxInt64)
+		for _, policy := range []*ChannelEdgePolicy{
+			edge.Policy1, edge.Policy2,
+		} {
+			if policy == nil {
+				continue
+			}
+
+			update := policy.LastUpdate.Unix()
+			found[uint64(update)] = struct{}{}
+			if update < earliest {
+				earliest = update
+			}
+		}
+
+		require.GreaterOrEqual(t, earliest, prevEarliest)
+		prevEarliest = earliest
+	}
+
+	require.Equal(t, timestamps, found)
+}
+
+// putSQLPolicyWithoutMaxHtlc stores the given policy in the native SQL graph
+// store with the max HTLC flag set, but without the max HTLC value.
+func putSQLPolicyWithoutMaxHtlc(t *testing.T, store *SQLGraphStore,
+	policy *ChannelEdgePolicy) {
+
+	t.Helper()
+
+	ctx := context.Background()
+	params := sqlc.UpsertChannelPolicyParams{
+		LastUpdate:   policy.LastUpdate.Unix(),
+		MessageFlags: int16(lnwire.ChanUpdateRequiredMaxHtlc),
+		ChannelFlags: int16(policy.ChannelFlags),
+		Timelock:     int32(policy.TimeLockDelta),
+		MinHtlcMsat:  int64(policy.MinHTLC),
+		BaseFeeMsat:  int64(policy.FeeBaseMSat),
+		FeePpm:       int64(policy.FeeProportionalMillionths),
+		Signature:    policy.SigBytes,
+	}
+
+	var writeTxOpts GraphQueriesTxOptions
+	err := store.db.ExecTx(ctx, &writeTxOpts,
+		func(db SQLGraphQueries) error {
+			dbChan, err := db.GetChannelBySCID(
+				ctx, chanIDToSCID(policy.ChannelID),
+			)
+			if err != nil {
+				return err
+			}
+
+			params.ChannelID = dbChan.ID
+			params.NodeID = dbChan.NodeID1
+			if policy.ChannelFlags&lnwire.ChanUpdateDirection != 0 {
+				params.NodeID = dbChan.NodeID2
+			}
+
+			_, err = db.UpsertChannelPolicy(ctx, params)
+
+			return err
+		},
+	)
+	require.NoError(t, err)
+}
@@ -0,0 +1,167 @@
+package channeldb
+
+import (
+	"time"
+
+	"github.com/ltcsuite/lnd/batch"
+	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// GraphStore is an interface that describes the persistence of the channel
+// graph. It covers the part of the graph API that doesn't require a database
+// transaction to be passed in, and is implemented both by the key-value store
+// backed ChannelGraph and by the native SQL backed SQLGraphStore.
+type GraphStore interface {
+	// SourceNode returns the source node of the graph.
+	SourceNode() (*LightningNode, error)
+
+	// SetSourceNode sets the source node within the graph database.
+	SetSourceNode(node *LightningNode) error
+
+	// AddLightningNode adds a vertex/node to the graph database. If the
+	// node is already present, its information is updated.
+	AddLightningNode(node *LightningNode,
+		op ...batch.SchedulerOption) error
+
+	// FetchLightningNode attempts to look up a target node by its
+	// identity public key. ErrGraphNodeNotFound is returned if the node
+	// doesn't exist.
+	FetchLightningNode(nodePub route.Vertex) (*LightningNode, error)
+
+	// HasLightningNode determines if the graph has a vertex identified by
+	// the target node identity public key, returning the time of its last
+	// update if so.
+	HasLightningNode(nodePub [33]byte) (time.Time, bool, error)
+
+	// LookupAlias attempts to return the alias as advertised by the target
+	// node.
+	LookupAlias(pub *btcec.PublicKey) (string, error)
+
+	// DeleteLightningNode removes a vertex/node from the database
+	// according to the node's public key.
+	DeleteLightningNode(nodePub route.Vertex) error
+
+	// IsPublicNode determines whether the node with the given public key
+	// is seen as a public node in the graph from the graph's source node's
+	// point of view.
+	IsPublicNode(pubKey [33]byte) (bool, error)
+
+	// NodeUpdatesInHorizon returns all the known lightning nodes which
+	// have an update timestamp within the passed range.
+	NodeUpdatesInHorizon(startTime, endTime time.Time) ([]LightningNode,
+		error)
+
+	// PruneGraphNodes removes any nodes from the graph that no longer
+	// have any channels.
+	PruneGraphNodes() error
+
+	// AddChannelEdge adds a new (undirected, blank) edge to the graph
+	// database. ErrEdgeAlreadyExist is returned if the edge is known.
+	AddChannelEdge(edge *ChannelEdgeInfo,
+		op ...batch.SchedulerOption) error
+
+	// UpdateChannelEdge updates the info of an already existing edge.
+	UpdateChannelEdge(edge *ChannelEdgeInfo) error
+
+	// UpdateEdgePolicy updates the edge routing policy for a single
+	// directed edge within the database for the referenced channel.
+	UpdateEdgePolicy(edge *ChannelEdgePolicy,
+		op ...batch.SchedulerOption) error
+
+	// HasChannelEdge returns the last update times of both directed edges
+	// of the channel with the passed channel ID, whether the channel
+	// exists and whether it is a known zombie.
+	HasChannelEdge(chanID uint64) (time.Time, time.Time, bool, bool,
+		error)
+
+	// FetchChannelEdgesByOutpoint attempts to lookup the edge info and the
+	// two directed edges for the channel identified by the funding
+	// outpoint.
+	FetchChannelEdgesByOutpoint(op *wire.OutPoint) (*ChannelEdgeInfo,
+		*ChannelEdgePolicy, *ChannelEdgePolicy, error)
+
+	// FetchChannelEdgesByID attempts to lookup the edge info and the two
+	// directed edges for the channel identified by the channel ID.
+	FetchChannelEdgesByID(chanID uint64) (*ChannelEdgeInfo,
+		*ChannelEdgePolicy, *ChannelEdgePolicy, error)
+
+	// ChannelID attempts to lookup the channel ID which maps to the passed
+	// channel point.
+	ChannelID(chanPoint *wire.OutPoint) (uint64, error)
+
+	// HighestChanID returns the "highest" known channel ID in the channel
+	// graph.
+	HighestChanID() (uint64, error)
+
+	// ForEachChannel iterates through all the channel edges stored within
+	// the graph and invokes the passed callback for each edge.
+	ForEachChannel(cb func(*ChannelEdgeInfo, *ChannelEdgePolicy,
+		*ChannelEdgePolicy) error) error
+
+	// ChannelView returns the funding outpoints and scripts of all the
+	// channels within the graph.
+	ChannelView() ([]EdgePoint, error)
+
+	// DisabledChannelIDs returns the channel ids of disabled channels.
+	DisabledChannelIDs() ([]uint64, error)
+
+	// ChanUpdatesInHorizon returns all the known channel edges which have
+	// at least one edge that has an update timestamp within the specified
+	// horizon.
+	ChanUpdatesInHorizon(startTime, endTime time.Time) ([]ChannelEdge,
+		error)
+
+	// FilterKnownChanIDs returns the subset of the passed channel IDs that
+	// we don't know of and which aren't known zombies.
+	FilterKnownChanIDs(chanIDs []uint64) ([]uint64, error)
+
+	// FilterChannelRange returns the channel ID's of all known channels
+	// which were mined in a block height within the passed range.
+	FilterChannelRange(startHeight, endHeight uint32) ([]BlockChannelRange,
+		error)
+
+	// FetchChanInfos returns the set of channel edges that correspond to
+	// the passed channel ID's. Unknown channels are skipped.
+	FetchChanInfos(chanIDs []uint64) ([]ChannelEdge, error)
+
+	// DeleteChannelEdges removes edges with the given channel IDs from the
+	// database, optionally marking them as zombies.
+	DeleteChannelEdges(strictZombiePruning, markZombie bool,
+		chanIDs ...uint64) error
+
+	// PruneGraph prunes newly closed channels from the channel graph in
+	// response to a new block being solved on the network.
+	PruneGraph(spentOutputs []*wire.OutPoint, blockHash *chainhash.Hash,
+		blockHeight uint32) ([]*ChannelEdgeInfo, error)
+
+	// PruneTip returns the block height and hash of the latest block that
+	// has been used to prune channels in the graph.
+	PruneTip() (*chainhash.Hash, uint32, error)
+
+	// DisconnectBlockAtHeight removes all channels that were confirmed at
+	// or above the given height, along with the matching prune log
+	// entries.
+	DisconnectBlockAtHeight(height uint32) ([]*ChannelEdgeInfo, error)
+
+	// MarkEdgeZombie marks a channel identified by its channel ID as a
+	// zombie.
+	MarkEdgeZombie(chanID uint64, pubKey1, pubKey2 [33]byte) error
+
+	// MarkEdgeLive clears an edge from the zombie index.
+	MarkEdgeLive(chanID uint64) error
+
+	// IsZombieEdge returns whether the edge is considered zombie along
+	// with the public keys of the nodes that may resurrect it.
+	IsZombieEdge(chanID uint64) (bool, [33]byte, [33]byte)
+
+	// NumZombies returns the current number of zombie channels in the
+	// graph.
+	NumZombies() (uint64, error)
+}
+
+// Compile-time constraint to ensure ChannelGraph implements the GraphStore
+// interface.
+var _ GraphStore = (*ChannelGraph)(nil)
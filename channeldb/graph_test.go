@@ -53,6 +53,15 @@ var (
 
 // MakeTestGraph creates a new instance of the ChannelGraph for testing purposes.
 func MakeTestGraph(t testing.TB, modifiers ...OptionModifier) (*ChannelGraph, error) {
+	return makeTestGraph(t, testGraphSQLStore(t), modifiers...)
+}
+
+// makeTestGraph creates a new instance of the ChannelGraph for testing
+// purposes that is backed by the given native SQL graph store, or by the
+// key-value store only if it is nil.
+func makeTestGraph(t testing.TB, sqlStore *SQLGraphStore,
+	modifiers ...OptionModifier) (*ChannelGraph, error) {
+
 	opts := DefaultOptions()
 	for _, modifier := range modifiers {
 		modifier(&opts)
@@ -68,7 +77,7 @@ func MakeTestGraph(t testing.TB, modifiers ...OptionModifier) (*ChannelGraph, er
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		true, false, sqlStore,
 	)
 	if err != nil {
 		backendCleanup()
@@ -127,7 +136,7 @@ func TestNodeInsertionAndDeletion(t *testing.T) {
 		Alias:                "kek",
 		Features:             testFeatures,
 		Addresses:            testAddrs,
-		ExtraOpaqueData:      []byte("extra new data"),
+		ExtraOpaqueData:      testExtraOpaqueData("extra new data"),
 		PubKeyBytes:          testPub,
 		db:                   graph.db,
 	}
@@ -338,6 +347,7 @@ func TestEdgeInsertionDeletion(t *testing.T) {
 		},
 		ChannelPoint: outpoint,
 		Capacity:     9000,
+		Features:     testEdgeFeatures,
 	}
 	copy(edgeInfo.NodeKey1Bytes[:], node1Pub.SerializeCompressed())
 	copy(edgeInfo.NodeKey2Bytes[:], node2Pub.SerializeCompressed())
@@ -412,6 +422,7 @@ func createEdge(height, txIndex uint32, txPosition uint16, outPointIndex uint32,
 		},
 		ChannelPoint: outpoint,
 		Capacity:     9000,
+		Features:     testEdgeFeatures,
 	}
 
 	copy(edgeInfo.NodeKey1Bytes[:], node1Pub.SerializeCompressed())
@@ -654,7 +665,8 @@ func createChannelEdge(db kvdb.Backend, node1, node2 *LightningNode) (*ChannelEd
 		},
 		ChannelPoint:    outpoint,
 		Capacity:        1000,
-		ExtraOpaqueData: []byte("new unknown feature"),
+		Features:        testEdgeFeatures,
+		ExtraOpaqueData: testExtraOpaqueData("new unknown feature"),
 	}
 	copy(edgeInfo.NodeKey1Bytes[:], firstNode.PubKeyBytes[:])
 	copy(edgeInfo.NodeKey2Bytes[:], secondNode.PubKeyBytes[:])
@@ -673,8 +685,10 @@ func createChannelEdge(db kvdb.Backend, node1, node2 *LightningNode) (*ChannelEd
 		FeeBaseMSat:               4352345,
 		FeeProportionalMillionths: 3452352,
 		Node:                      secondNode,
-		ExtraOpaqueData:           []byte("new unknown feature2"),
 		db:                        db,
+		ExtraOpaqueData: testExtraOpaqueData(
+			"new unknown feature2",
+		),
 	}
 	edge2 := &ChannelEdgePolicy{
 		SigBytes:                  testSig.Serialize(),
@@ -688,8 +702,10 @@ func createChannelEdge(db kvdb.Backend, node1, node2 *LightningNode) (*ChannelEd
 		FeeBaseMSat:               4352345,
 		FeeProportionalMillionths: 90392423,
 		Node:                      firstNode,
-		ExtraOpaqueData:           []byte("new unknown feature1"),
 		db:                        db,
+		ExtraOpaqueData: testExtraOpaqueData(
+			"new unknown feature1",
+		),
 	}
 
 	return edgeInfo, edge1, edge2
@@ -1055,7 +1071,9 @@ func TestGraphTraversal(t *testing.T) {
 	// Finally, we want to test the ability to iterate over all the
 	// outgoing channels for a particular node.
 	numNodeChans := 0
-	firstNode, secondNode := nodeList[0], nodeList[1]
+	firstNode, err := graph.FetchLightningNode(nodeList[0].PubKeyBytes)
+	require.NoError(t, err)
+	secondNode := nodeList[1]
 	err = firstNode.ForEachChannel(nil, func(_ kvdb.RTx, _ *ChannelEdgeInfo,
 		outEdge, inEdge *ChannelEdgePolicy) error {
 
@@ -2279,8 +2297,11 @@ func TestIncompleteChannelPolicies(t *testing.T) {
 
 	// Ensure that channel is reported with unknown policies.
 	checkPolicies := func(node *LightningNode, expectedIn, expectedOut bool) {
+		node, err := graph.FetchLightningNode(node.PubKeyBytes)
+		require.NoError(t, err)
+
 		calls := 0
-		err := node.ForEachChannel(nil, func(_ kvdb.RTx, _ *ChannelEdgeInfo,
+		err = node.ForEachChannel(nil, func(_ kvdb.RTx, _ *ChannelEdgeInfo,
 			outEdge, inEdge *ChannelEdgePolicy) error {
 
 			if !expectedOut && outEdge != nil {
@@ -2406,6 +2427,13 @@ func TestChannelEdgePruningUpdateIndexDeletion(t *testing.T) {
 			timestampSet[t] = struct{}{}
 		}
 
+		// The SQL store has no edge update index, as it looks up the
+		// channel updates by the last update of their policies.
+		if testNativeSQLGraph {
+			checkSQLUpdateTimestamps(t, graph, timestampSet)
+			return
+		}
+
 		err := kvdb.View(graph.db, func(tx kvdb.RTx) error {
 			edges := tx.ReadBucket(edgeBucket)
 			if edges == nil {
@@ -2911,13 +2939,69 @@ func TestEdgePolicyMissingMaxHtcl(t *testing.T) {
 	from := edge2.Node.PubKeyBytes[:]
 	to := edge1.Node.PubKeyBytes[:]
 
+	// We'll store the first edge policy with the max_htlc flag set, but
+	// without the max_htlc field.
+	if testNativeSQLGraph {
+		edge1.MessageFlags = lnwire.ChanUpdateRequiredMaxHtlc
+		edge1.MaxHTLC = 13928598
+		edge1.ExtraOpaqueData = nil
+		putSQLPolicyWithoutMaxHtlc(t, graph.sqlStore, edge1)
+	} else {
+		putKVPolicyWithoutMaxHtlc(t, graph, edge1, from, to)
+	}
+
+	// And add the second, unmodified edge.
+	if err := graph.UpdateEdgePolicy(edge2); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+
+	// Attempt to fetch the edge and policies from the DB. Since the policy
+	// we added is invalid according to the new format, it should be as we
+	// are not aware of the policy (indicated by the policy returned being
+	// nil)
+	dbEdgeInfo, dbEdge1, dbEdge2, err := graph.FetchChannelEdgesByID(chanID)
+	require.NoError(t, err, "unable to fetch channel by ID")
+
+	// The first edge should have a nil-policy returned
+	if dbEdge1 != nil {
+		t.Fatalf("expected db edge to be nil")
+	}
+	if err := compareEdgePolicies(dbEdge2, edge2); err != nil {
+		t.Fatalf("edge doesn't match: %v", err)
+	}
+	assertEdgeInfoEqual(t, dbEdgeInfo, edgeInfo)
+
+	// Now add the original, unmodified edge policy, and make sure the edge
+	// policies then become fully populated.
+	if err := graph.UpdateEdgePolicy(edge1); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+
+	dbEdgeInfo, dbEdge1, dbEdge2, err = graph.FetchChannelEdgesByID(chanID)
+	require.NoError(t, err, "unable to fetch channel by ID")
+	if err := compareEdgePolicies(dbEdge1, edge1); err != nil {
+		t.Fatalf("edge doesn't match: %v", err)
+	}
+	if err := compareEdgePolicies(dbEdge2, edge2); err != nil {
+		t.Fatalf("edge doesn't match: %v", err)
+	}
+	assertEdgeInfoEqual(t, dbEdgeInfo, edgeInfo)
+}
+
+// putKVPolicyWithoutMaxHtlc stores the given policy in the key-value store
+// with the max HTLC flag set, but without the max HTLC value.
+func putKVPolicyWithoutMaxHtlc(t *testing.T, graph *ChannelGraph,
+	edge1 *ChannelEdgePolicy, from, to []byte) {
+
+	t.Helper()
+
 	// We'll remove the no max_htlc field from the first edge policy, and
 	// all other opaque data, and serialize it.
 	edge1.MessageFlags = 0
 	edge1.ExtraOpaqueData = nil
 
 	var b bytes.Buffer
-	err = serializeChanEdgePolicy(&b, edge1, to)
+	err := serializeChanEdgePolicy(&b, edge1, to)
 	if err != nil {
 		t.Fatalf("unable to serialize policy")
 	}
@@ -2989,43 +3073,6 @@ func TestEdgePolicyMissingMaxHtcl(t *testing.T) {
 		return edges.Put(edgeKey[:], stripped)
 	}, func() {})
 	require.NoError(t, err, "error writing db")
-
-	// And add the second, unmodified edge.
-	if err := graph.UpdateEdgePolicy(edge2); err != nil {
-		t.Fatalf("unable to update edge: %v", err)
-	}
-
-	// Attempt to fetch the edge and policies from the DB. Since the policy
-	// we added is invalid according to the new format, it should be as we
-	// are not aware of the policy (indicated by the policy returned being
-	// nil)
-	dbEdgeInfo, dbEdge1, dbEdge2, err := graph.FetchChannelEdgesByID(chanID)
-	require.NoError(t, err, "unable to fetch channel by ID")
-
-	// The first edge should have a nil-policy returned
-	if dbEdge1 != nil {
-		t.Fatalf("expected db edge to be nil")
-	}
-	if err := compareEdgePolicies(dbEdge2, edge2); err != nil {
-		t.Fatalf("edge doesn't match: %v", err)
-	}
-	assertEdgeInfoEqual(t, dbEdgeInfo, edgeInfo)
-
-	// Now add the original, unmodified edge policy, and make sure the edge
-	// policies then become fully populated.
-	if err := graph.UpdateEdgePolicy(edge1); err != nil {
-		t.Fatalf("unable to update edge: %v", err)
-	}
-
-	dbEdgeInfo, dbEdge1, dbEdge2, err = graph.FetchChannelEdgesByID(chanID)
-	require.NoError(t, err, "unable to fetch channel by ID")
-	if err := compareEdgePolicies(dbEdge1, edge1); err != nil {
-		t.Fatalf("edge doesn't match: %v", err)
-	}
-	if err := compareEdgePolicies(dbEdge2, edge2); err != nil {
-		t.Fatalf("edge doesn't match: %v", err)
-	}
-	assertEdgeInfoEqual(t, dbEdgeInfo, edgeInfo)
 }
 
 // assertNumZombies queries the provided ChannelGraph for NumZombies, and
@@ -3140,9 +3187,9 @@ func compareNodes(a, b *LightningNode) error {
 		return fmt.Errorf("Alias doesn't match: expected %#v, \n "+
 			"got %#v", a.Alias, b.Alias)
 	}
-	if !reflect.DeepEqual(a.db, b.db) {
-		return fmt.Errorf("db doesn't match: expected %#v, \n "+
-			"got %#v", a.db, b.db)
+	err := compareGraphStores(a.db, b.db, a.sqlStore, b.sqlStore)
+	if err != nil {
+		return err
 	}
 	if !reflect.DeepEqual(a.HaveNodeAnnouncement, b.HaveNodeAnnouncement) {
 		return fmt.Errorf("HaveNodeAnnouncement doesn't match: expected %#v, \n "+
@@ -3203,10 +3250,30 @@ func compareEdgePolicies(a, b *ChannelEdgePolicy) error {
 	if err := compareNodes(a.Node, b.Node); err != nil {
 		return err
 	}
-	if !reflect.DeepEqual(a.db, b.db) {
-		return fmt.Errorf("db doesn't match: expected %#v, \n "+
-			"got %#v", a.db, b.db)
+	if err := compareGraphStores(a.db, b.db, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// compareGraphStores checks that two graph objects are tied to the same graph
+// store. Objects read from the native SQL graph store are tied to the SQL
+// store instead of the key-value backend the test objects are created with, so
+// the key-value backends are only compared if both objects are tied to one.
+func compareGraphStores(aDB, bDB kvdb.Backend, aSQL,
+	bSQL *SQLGraphStore) error {
+
+	if !testNativeSQLGraph || (aDB != nil && bDB != nil) {
+		if !reflect.DeepEqual(aDB, bDB) {
+			return fmt.Errorf("db doesn't match: expected %#v, \n "+
+				"got %#v", aDB, bDB)
+		}
+	}
+	if aSQL != nil && bSQL != nil && aSQL != bSQL {
+		return fmt.Errorf("SQL store doesn't match: expected %p, "+
+			"got %p", aSQL, bSQL)
 	}
+
 	return nil
 }
 
@@ -3522,10 +3589,11 @@ func TestGraphLoading(t *testing.T) {
 	defer backendCleanup()
 
 	opts := DefaultOptions()
+	sqlStore := testGraphSQLStore(t)
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		true, false, sqlStore,
 	)
 	require.NoError(t, err)
 
@@ -3539,7 +3607,7 @@ func TestGraphLoading(t *testing.T) {
 	graphReloaded, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		true, false, sqlStore,
 	)
 	require.NoError(t, err)
 
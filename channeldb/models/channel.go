@@ -126,5 +126,9 @@ type ForwardingPolicy struct {
 	// per-hop payload of the incoming HTLC's onion packet.
 	TimeLockDelta uint32
 
+	// InboundFee is the fee that must be paid for HTLCs that are received
+	// over the channel, in addition to the fee of the outgoing channel.
+	InboundFee InboundFee
+
 	// TODO(roasbeef): add fee module inside of switch
 }
@@ -0,0 +1,43 @@
+package models
+
+import "github.com/ltcsuite/lnd/lnwire"
+
+// InboundFee is the fee that a node charges for htlcs that it receives over a
+// particular channel, in addition to the regular fee of the outgoing channel.
+// Both components may be negative to express a discount.
+type InboundFee struct {
+	// Base is the fixed fee in millisatoshi.
+	Base int32
+
+	// Rate is the proportional fee in parts per million.
+	Rate int32
+}
+
+// NewInboundFeeFromWire constructs an inbound fee from its wire
+// representation.
+func NewInboundFeeFromWire(fee lnwire.Fee) InboundFee {
+	return InboundFee{
+		Base: fee.BaseFee,
+		Rate: fee.FeeRate,
+	}
+}
+
+// ToWire converts the inbound fee to its wire representation.
+func (i *InboundFee) ToWire() lnwire.Fee {
+	return lnwire.Fee{
+		BaseFee: i.Base,
+		FeeRate: i.Rate,
+	}
+}
+
+// CalcFee calculates the inbound fee for the given amount. The result may be
+// negative.
+func (i *InboundFee) CalcFee(amt lnwire.MilliSatoshi) int64 {
+	fee := int64(i.Base)
+
+	// To keep the integer math simple, positive fees are rounded down
+	// while negative fees are rounded up.
+	fee += int64(i.Rate) * int64(amt) / 1_000_000
+
+	return fee
+}
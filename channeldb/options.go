@@ -82,6 +82,10 @@ type Options struct {
 	// storeFinalHtlcResolutions determines whether to persistently store
 	// the final resolution of incoming htlcs.
 	storeFinalHtlcResolutions bool
+
+	// sqlGraphStore is the native SQL graph store the channel graph is
+	// kept in. If nil, the graph is kept in the key-value store.
+	sqlGraphStore *SQLGraphStore
 }
 
 // DefaultOptions returns an Options populated with default values.
@@ -213,6 +217,15 @@ func OptionStoreFinalHtlcResolutions(
 	}
 }
 
+// OptionSetSQLGraphStore sets the native SQL graph store the channel graph is
+// kept in. The graph of the key-value store is copied over to it the first time
+// the database is opened with it.
+func OptionSetSQLGraphStore(store *SQLGraphStore) OptionModifier {
+	return func(o *Options) {
+		o.sqlGraphStore = store
+	}
+}
+
 // OptionPruneRevocationLog specifies whether the migration for pruning
 // revocation logs needs to be applied or not.
 func OptionPruneRevocationLog(prune bool) OptionModifier {
@@ -145,29 +145,10 @@ func (p *PaymentControl) InitPayment(paymentHash lntypes.Hash,
 			return err
 		}
 
-		switch paymentStatus {
-
-		// We allow retrying failed payments.
-		case StatusFailed:
-
-		// This is a new payment that is being initialized for the
-		// first time.
-		case StatusUnknown:
-
-		// We already have an InFlight payment on the network. We will
-		// disallow any new payments.
-		case StatusInFlight:
-			updateErr = ErrPaymentInFlight
-			return nil
-
-		// We've already succeeded a payment to this payment hash,
-		// forbid the switch from sending another.
-		case StatusSucceeded:
-			updateErr = ErrAlreadyPaid
-			return nil
-
-		default:
-			updateErr = ErrUnknownPaymentStatus
+		// Make sure the payment can be (re-)initialized in its current
+		// state.
+		updateErr = verifyInitPayment(paymentStatus)
+		if updateErr != nil {
 			return nil
 		}
 
@@ -225,6 +206,34 @@ func (p *PaymentControl) InitPayment(paymentHash lntypes.Hash,
 	return updateErr
 }
 
+// verifyInitPayment checks whether a payment with the given status may be
+// initialized, which is only the case for new payments and payments that failed
+// before.
+func verifyInitPayment(paymentStatus PaymentStatus) error {
+	switch paymentStatus {
+	// We allow retrying failed payments.
+	case StatusFailed:
+		return nil
+
+	// This is a new payment that is being initialized for the first time.
+	case StatusUnknown:
+		return nil
+
+	// We already have an InFlight payment on the network. We will disallow
+	// any new payments.
+	case StatusInFlight:
+		return ErrPaymentInFlight
+
+	// We've already succeeded a payment to this payment hash, forbid the
+	// switch from sending another.
+	case StatusSucceeded:
+		return ErrAlreadyPaid
+
+	default:
+		return ErrUnknownPaymentStatus
+	}
+}
+
 // DeleteFailedAttempts deletes all failed htlcs for a payment if configured
 // by the PaymentControl db.
 func (p *PaymentControl) DeleteFailedAttempts(hash lntypes.Hash) error {
@@ -319,64 +328,10 @@ func (p *PaymentControl) RegisterAttempt(paymentHash lntypes.Hash,
 			return err
 		}
 
-		// We cannot register a new attempt if the payment already has
-		// reached a terminal condition. We check this before
-		// ensureInFlight because it is a more general check.
-		settle, fail := p.TerminalInfo()
-		if settle != nil || fail != nil {
-			return ErrPaymentTerminal
-		}
-
-		// Ensure the payment is in-flight.
-		if err := ensureInFlight(p); err != nil {
+		if err := verifyAttempt(p, attempt); err != nil {
 			return err
 		}
 
-		// Make sure any existing shards match the new one with regards
-		// to MPP options.
-		mpp := attempt.Route.FinalHop().MPP
-		for _, h := range p.InFlightHTLCs() {
-			hMpp := h.Route.FinalHop().MPP
-
-			switch {
-			// We tried to register a non-MPP attempt for a MPP
-			// payment.
-			case mpp == nil && hMpp != nil:
-				return ErrMPPayment
-
-			// We tried to register a MPP shard for a non-MPP
-			// payment.
-			case mpp != nil && hMpp == nil:
-				return ErrNonMPPayment
-
-			// Non-MPP payment, nothing more to validate.
-			case mpp == nil:
-				continue
-			}
-
-			// Check that MPP options match.
-			if mpp.PaymentAddr() != hMpp.PaymentAddr() {
-				return ErrMPPPaymentAddrMismatch
-			}
-
-			if mpp.TotalMsat() != hMpp.TotalMsat() {
-				return ErrMPPTotalAmountMismatch
-			}
-		}
-
-		// If this is a non-MPP attempt, it must match the total amount
-		// exactly.
-		amt := attempt.Route.ReceiverAmt()
-		if mpp == nil && amt != p.Info.Value {
-			return ErrValueMismatch
-		}
-
-		// Ensure we aren't sending more than the total payment amount.
-		sentAmt, _ := p.SentAmt()
-		if sentAmt+amt > p.Info.Value {
-			return ErrValueExceedsAmt
-		}
-
 		htlcsBucket, err := bucket.CreateBucketIfNotExists(
 			paymentHtlcsBucket,
 		)
@@ -403,6 +358,71 @@ func (p *PaymentControl) RegisterAttempt(paymentHash lntypes.Hash,
 	return payment, err
 }
 
+// verifyAttempt validates that the given HTLC attempt can be registered for
+// the payment, making sure the payment is still in flight and that the attempt
+// is consistent with any other in-flight shards.
+func verifyAttempt(payment *MPPayment, attempt *HTLCAttemptInfo) error {
+	// We cannot register a new attempt if the payment already has
+	// reached a terminal condition. We check this before
+	// ensureInFlight because it is a more general check.
+	settle, fail := payment.TerminalInfo()
+	if settle != nil || fail != nil {
+		return ErrPaymentTerminal
+	}
+
+	// Ensure the payment is in-flight.
+	if err := ensureInFlight(payment); err != nil {
+		return err
+	}
+
+	// Make sure any existing shards match the new one with regards
+	// to MPP options.
+	mpp := attempt.Route.FinalHop().MPP
+	for _, h := range payment.InFlightHTLCs() {
+		hMpp := h.Route.FinalHop().MPP
+
+		switch {
+		// We tried to register a non-MPP attempt for a MPP
+		// payment.
+		case mpp == nil && hMpp != nil:
+			return ErrMPPayment
+
+		// We tried to register a MPP shard for a non-MPP
+		// payment.
+		case mpp != nil && hMpp == nil:
+			return ErrNonMPPayment
+
+		// Non-MPP payment, nothing more to validate.
+		case mpp == nil:
+			continue
+		}
+
+		// Check that MPP options match.
+		if mpp.PaymentAddr() != hMpp.PaymentAddr() {
+			return ErrMPPPaymentAddrMismatch
+		}
+
+		if mpp.TotalMsat() != hMpp.TotalMsat() {
+			return ErrMPPTotalAmountMismatch
+		}
+	}
+
+	// If this is a non-MPP attempt, it must match the total amount
+	// exactly.
+	amt := attempt.Route.ReceiverAmt()
+	if mpp == nil && amt != payment.Info.Value {
+		return ErrValueMismatch
+	}
+
+	// Ensure we aren't sending more than the total payment amount.
+	sentAmt, _ := payment.SentAmt()
+	if sentAmt+amt > payment.Info.Value {
+		return ErrValueExceedsAmt
+	}
+
+	return nil
+}
+
 // SettleAttempt marks the given attempt settled with the preimage. If this is
 // a multi shard payment, this might implicitly mean that the full payment
 // succeeded.
@@ -790,3 +810,31 @@ func (p *PaymentControl) FetchInFlightPayments() ([]*MPPayment, error) {
 
 	return inFlights, nil
 }
+
+// QueryPayments is a query to the payments database which is restricted to a
+// subset of payments by the payments query, containing an offset index and a
+// maximum number of returned payments.
+func (p *PaymentControl) QueryPayments(query PaymentsQuery) (PaymentsResponse,
+	error) {
+
+	return p.db.QueryPayments(query)
+}
+
+// DeletePayment deletes a payment from the DB given its payment hash. If
+// failedHtlcsOnly is set, only failed HTLC attempts of the payment will be
+// deleted.
+func (p *PaymentControl) DeletePayment(paymentHash lntypes.Hash,
+	failedHtlcsOnly bool) error {
+
+	return p.db.DeletePayment(paymentHash, failedHtlcsOnly)
+}
+
+// DeletePayments deletes all completed and failed payments from the DB. If
+// failedOnly is set, only failed payments will be considered for deletion. If
+// failedHtlcsOnly is set, the payment itself won't be deleted, only failed HTLC
+// attempts.
+func (p *PaymentControl) DeletePayments(failedOnly,
+	failedHtlcsOnly bool) error {
+
+	return p.db.DeletePayments(failedOnly, failedHtlcsOnly)
+}
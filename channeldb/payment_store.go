@@ -0,0 +1,61 @@
+package channeldb
+
+import (
+	"github.com/ltcsuite/lnd/lntypes"
+)
+
+// PaymentStore is an interface that describes the persistence of the payments
+// we send and their HTLC attempts. It is implemented both by the key-value
+// store backed PaymentControl and by the native SQL backed SQLPaymentStore.
+type PaymentStore interface {
+	// InitPayment checks or records the given PaymentCreationInfo with the
+	// DB, making sure it does not already exist as an in-flight payment.
+	// When this method returns successfully, the payment is guaranteed to
+	// be in the InFlight state.
+	InitPayment(lntypes.Hash, *PaymentCreationInfo) error
+
+	// DeleteFailedAttempts deletes all failed htlcs for a payment if the
+	// store isn't configured to keep them.
+	DeleteFailedAttempts(lntypes.Hash) error
+
+	// RegisterAttempt atomically records the provided HTLCAttemptInfo.
+	RegisterAttempt(lntypes.Hash, *HTLCAttemptInfo) (*MPPayment, error)
+
+	// SettleAttempt marks the given attempt settled with the preimage. If
+	// this is a multi shard payment, this might implicitly mean the full
+	// payment succeeded.
+	SettleAttempt(lntypes.Hash, uint64, *HTLCSettleInfo) (*MPPayment,
+		error)
+
+	// FailAttempt marks the given payment attempt failed.
+	FailAttempt(lntypes.Hash, uint64, *HTLCFailInfo) (*MPPayment, error)
+
+	// Fail transitions a payment into the Failed state, and records the
+	// ultimate reason the payment failed.
+	Fail(lntypes.Hash, FailureReason) (*MPPayment, error)
+
+	// FetchPayment returns information about a payment.
+	FetchPayment(lntypes.Hash) (*MPPayment, error)
+
+	// FetchInFlightPayments returns all payments with status InFlight.
+	FetchInFlightPayments() ([]*MPPayment, error)
+
+	// QueryPayments returns the subset of payments selected by the given
+	// payments query.
+	QueryPayments(PaymentsQuery) (PaymentsResponse, error)
+
+	// DeletePayment deletes a payment given its payment hash. If
+	// failedHtlcsOnly is set, only the failed HTLC attempts of the payment
+	// will be deleted.
+	DeletePayment(paymentHash lntypes.Hash, failedHtlcsOnly bool) error
+
+	// DeletePayments deletes all completed and failed payments. If
+	// failedOnly is set, only failed payments will be considered for
+	// deletion. If failedHtlcsOnly is set, the payments themselves won't be
+	// deleted, only their failed HTLC attempts.
+	DeletePayments(failedOnly, failedHtlcsOnly bool) error
+}
+
+// Compile-time constraint to ensure PaymentControl implements the PaymentStore
+// interface.
+var _ PaymentStore = (*PaymentControl)(nil)
@@ -15,6 +15,7 @@ import (
 	"github.com/ltcsuite/lnd/record"
 	"github.com/ltcsuite/lnd/routing/route"
 	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/wire"
 )
 
@@ -298,6 +299,23 @@ func fetchPayment(bucket kvdb.RBucket) (*MPPayment, error) {
 		failureReason = &reason
 	}
 
+	// Use the DB state to determine the status of the payment.
+	paymentStatus := decidePaymentStatus(htlcs, failureReason)
+
+	return &MPPayment{
+		SequenceNum:   sequenceNum,
+		Info:          creationInfo,
+		HTLCs:         htlcs,
+		FailureReason: failureReason,
+		Status:        paymentStatus,
+	}, nil
+}
+
+// decidePaymentStatus determines the status of a payment from the state of its
+// HTLC attempts and the payment level failure reason, if any.
+func decidePaymentStatus(htlcs []HTLCAttempt,
+	failureReason *FailureReason) PaymentStatus {
+
 	// Go through all HTLCs for this payment, noting whether we have any
 	// settled HTLC, and any still in-flight.
 	var inflight, settled bool
@@ -316,32 +334,21 @@ func fetchPayment(bucket kvdb.RBucket) (*MPPayment, error) {
 		inflight = true
 	}
 
-	// Use the DB state to determine the status of the payment.
-	var paymentStatus PaymentStatus
-
 	switch {
 	// If any of the the HTLCs did succeed and there are no HTLCs in
 	// flight, the payment succeeded.
 	case !inflight && settled:
-		paymentStatus = StatusSucceeded
+		return StatusSucceeded
 
 	// If we have no in-flight HTLCs, and the payment failure is set, the
 	// payment is considered failed.
 	case !inflight && failureReason != nil:
-		paymentStatus = StatusFailed
+		return StatusFailed
 
 	// Otherwise it is still in flight.
 	default:
-		paymentStatus = StatusInFlight
+		return StatusInFlight
 	}
-
-	return &MPPayment{
-		SequenceNum:   sequenceNum,
-		Info:          creationInfo,
-		HTLCs:         htlcs,
-		FailureReason: failureReason,
-		Status:        paymentStatus,
-	}, nil
 }
 
 // fetchHtlcAttempts retrieves all htlc attempts made for the payment found in
@@ -1176,6 +1183,25 @@ func serializeHop(w io.Writer, h *route.Hop) error {
 		records = append(records, record.NewMetadataRecord(&h.Metadata))
 	}
 
+	if h.EncryptedData != nil {
+		records = append(records,
+			record.NewEncryptedDataRecord(&h.EncryptedData),
+		)
+	}
+
+	if h.BlindingPoint != nil {
+		records = append(records,
+			record.NewBlindingPointRecord(&h.BlindingPoint),
+		)
+	}
+
+	if h.TotalAmtMsat != 0 {
+		totalMsatInt := uint64(h.TotalAmtMsat)
+		records = append(records,
+			record.NewTotalAmtMsatBlinded(&totalMsatInt),
+		)
+	}
+
 	// Final sanity check to absolutely rule out custom records that are not
 	// custom and write into the standard range.
 	if err := h.CustomRecords.Validate(); err != nil {
@@ -1297,6 +1323,42 @@ func deserializeHop(r io.Reader) (*route.Hop, error) {
 		h.Metadata = metadata
 	}
 
+	encryptedDataType := uint64(record.EncryptedDataOnionType)
+	if data, ok := tlvMap[encryptedDataType]; ok {
+		delete(tlvMap, encryptedDataType)
+		h.EncryptedData = data
+	}
+
+	blindingType := uint64(record.BlindingPointOnionType)
+	if blindingPoint, ok := tlvMap[blindingType]; ok {
+		delete(tlvMap, blindingType)
+
+		h.BlindingPoint, err = btcec.ParsePubKey(blindingPoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blinding point: %w",
+				err)
+		}
+	}
+
+	totalAmtMsatType := uint64(record.TotalAmtMsatBlindedType)
+	if totalAmtMsat, ok := tlvMap[totalAmtMsatType]; ok {
+		delete(tlvMap, totalAmtMsatType)
+
+		var (
+			totalAmtMsatInt uint64
+			buf             [8]byte
+		)
+		err := tlv.DTUint64(
+			bytes.NewReader(totalAmtMsat), &totalAmtMsatInt, &buf,
+			uint64(len(totalAmtMsat)),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		h.TotalAmtMsat = lnwire.MilliSatoshi(totalAmtMsatInt)
+	}
+
 	h.CustomRecords = tlvMap
 
 	return h, nil
@@ -0,0 +1,922 @@
+package channeldb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/sqldb"
+	"github.com/ltcsuite/lnd/sqldb/sqlc"
+)
+
+// SQLPaymentQueries is an interface that defines the set of operations that
+// can be executed against the payments tables of the SQL database.
+type SQLPaymentQueries interface {
+	InsertPayment(ctx context.Context,
+		arg sqlc.InsertPaymentParams) (int32, error)
+
+	GetPayment(ctx context.Context, hash []byte) (sqlc.Payment, error)
+
+	GetMaxPaymentSequenceNum(ctx context.Context) (int64, error)
+
+	CountPayments(ctx context.Context) (int64, error)
+
+	FetchPaymentsByStatus(ctx context.Context,
+		status int16) ([]sqlc.Payment, error)
+
+	FilterPayments(ctx context.Context,
+		arg sqlc.FilterPaymentsParams) ([]sqlc.Payment, error)
+
+	UpdatePaymentStatus(ctx context.Context,
+		arg sqlc.UpdatePaymentStatusParams) error
+
+	DeletePayment(ctx context.Context, id int32) error
+
+	DeletePaymentsByStatus(ctx context.Context, status int16) error
+
+	InsertPaymentHTLCAttempt(ctx context.Context,
+		arg sqlc.InsertPaymentHTLCAttemptParams) error
+
+	GetPaymentHTLCAttempts(ctx context.Context,
+		paymentID int32) ([]sqlc.PaymentHtlcAttempt, error)
+
+	SettlePaymentHTLCAttempt(ctx context.Context,
+		arg sqlc.SettlePaymentHTLCAttemptParams) error
+
+	FailPaymentHTLCAttempt(ctx context.Context,
+		arg sqlc.FailPaymentHTLCAttemptParams) error
+
+	DeleteFailedPaymentHTLCAttempts(ctx context.Context,
+		paymentID int32) error
+
+	DeleteFailedHTLCAttemptsByPaymentStatus(ctx context.Context,
+		status int16) error
+}
+
+// PaymentQueriesTxOptions defines the set of db txn options the
+// SQLPaymentQueries understands.
+type PaymentQueriesTxOptions struct {
+	// readOnly governs if a read only transaction is needed or not.
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+//
+// NOTE: This implements the sqldb.TxOptions interface.
+func (p *PaymentQueriesTxOptions) ReadOnly() bool {
+	return p.readOnly
+}
+
+// NewPaymentQueryReadTx creates a new read transaction option set.
+func NewPaymentQueryReadTx() PaymentQueriesTxOptions {
+	return PaymentQueriesTxOptions{
+		readOnly: true,
+	}
+}
+
+// BatchedSQLPaymentQueries is a version of the SQLPaymentQueries that's
+// capable of batched database operations.
+type BatchedSQLPaymentQueries interface {
+	SQLPaymentQueries
+
+	sqldb.BatchedTx[SQLPaymentQueries]
+}
+
+// SQLPaymentStore is a PaymentStore implementation that keeps the payments
+// and their HTLC attempts in native SQL tables.
+type SQLPaymentStore struct {
+	db BatchedSQLPaymentQueries
+
+	// keepFailedPaymentAttempts determines whether failed HTLC attempts
+	// are kept once the payment has reached a terminal state.
+	keepFailedPaymentAttempts bool
+
+	// seqMtx guards the payment sequence number counter below.
+	seqMtx sync.Mutex
+
+	// lastSeqNum is the last payment sequence number that was handed out.
+	// It is lazily initialized from the database on first use.
+	lastSeqNum uint64
+
+	// seqLoaded indicates whether lastSeqNum was read from the database.
+	seqLoaded bool
+}
+
+// NewSQLPaymentStore creates a new SQL backed payment store.
+func NewSQLPaymentStore(db BatchedSQLPaymentQueries,
+	keepFailedPaymentAttempts bool) *SQLPaymentStore {
+
+	return &SQLPaymentStore{
+		db:                        db,
+		keepFailedPaymentAttempts: keepFailedPaymentAttempts,
+	}
+}
+
+// Compile-time constraint to ensure SQLPaymentStore implements the
+// PaymentStore interface.
+var _ PaymentStore = (*SQLPaymentStore)(nil)
+
+// InitPayment checks or records the given PaymentCreationInfo with the DB,
+// making sure it does not already exist as an in-flight payment. When this
+// method returns successfully, the payment is guaranteed to be in the InFlight
+// state.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) InitPayment(paymentHash lntypes.Hash,
+	info *PaymentCreationInfo) error {
+
+	ctx := context.TODO()
+
+	// Obtain a new sequence number for this payment. This is used to sort
+	// the payments in order of creation.
+	sequenceNum, err := s.nextSequenceNum(ctx)
+	if err != nil {
+		return err
+	}
+
+	var writeTxOpts PaymentQueriesTxOptions
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLPaymentQueries) error {
+		row, payment, err := fetchSQLPayment(ctx, db, paymentHash)
+		switch {
+		// This is a new payment that is being initialized for the first
+		// time.
+		case errors.Is(err, ErrPaymentNotInitiated):
+
+		case err != nil:
+			return err
+
+		// We have a previous attempt of this payment, which we'll
+		// replace along with all its HTLC attempts if it may be
+		// retried.
+		default:
+			err := verifyInitPayment(payment.Status)
+			if err != nil {
+				return err
+			}
+
+			if err := db.DeletePayment(ctx, row.ID); err != nil {
+				return fmt.Errorf("unable to delete previous "+
+					"payment: %w", err)
+			}
+		}
+
+		_, err = db.InsertPayment(ctx, sqlc.InsertPaymentParams{
+			SequenceNum:    int64(sequenceNum),
+			Hash:           paymentHash[:],
+			AmountMsat:     int64(info.Value),
+			PaymentRequest: info.PaymentRequest,
+			Status:         int16(StatusInFlight),
+			CreatedAt:      info.CreationTime.UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to insert payment: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteFailedAttempts deletes all failed htlcs for a payment if configured
+// to do so.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) DeleteFailedAttempts(hash lntypes.Hash) error {
+	if s.keepFailedPaymentAttempts {
+		return nil
+	}
+
+	const failedHtlcsOnly = true
+	return s.DeletePayment(hash, failedHtlcsOnly)
+}
+
+// RegisterAttempt atomically records the provided HTLCAttemptInfo.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) RegisterAttempt(paymentHash lntypes.Hash,
+	attempt *HTLCAttemptInfo) (*MPPayment, error) {
+
+	ctx := context.TODO()
+
+	var (
+		writeTxOpts PaymentQueriesTxOptions
+		payment     *MPPayment
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLPaymentQueries) error {
+		row, p, err := fetchSQLPayment(ctx, db, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		if err := verifyAttempt(p, attempt); err != nil {
+			return err
+		}
+
+		err = insertSQLHTLCAttempt(ctx, db, row.ID, attempt)
+		if err != nil {
+			return err
+		}
+
+		payment, err = syncSQLPaymentStatus(ctx, db, paymentHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// SettleAttempt marks the given attempt settled with the preimage. If this is
+// a multi shard payment, this might implicitly mean that the full payment
+// succeeded.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) SettleAttempt(hash lntypes.Hash,
+	attemptID uint64, settleInfo *HTLCSettleInfo) (*MPPayment, error) {
+
+	return s.updateAttempt(hash, attemptID,
+		func(db SQLPaymentQueries, paymentID int32) error {
+			return settleSQLHTLCAttempt(
+				context.TODO(), db, paymentID, attemptID,
+				settleInfo,
+			)
+		},
+	)
+}
+
+// FailAttempt marks the given payment attempt failed.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) FailAttempt(hash lntypes.Hash,
+	attemptID uint64, failInfo *HTLCFailInfo) (*MPPayment, error) {
+
+	return s.updateAttempt(hash, attemptID,
+		func(db SQLPaymentQueries, paymentID int32) error {
+			return failSQLHTLCAttempt(
+				context.TODO(), db, paymentID, attemptID,
+				failInfo,
+			)
+		},
+	)
+}
+
+// updateAttempt applies the given update to a registered and unresolved HTLC
+// attempt of an in-flight payment.
+func (s *SQLPaymentStore) updateAttempt(paymentHash lntypes.Hash,
+	attemptID uint64,
+	update func(SQLPaymentQueries, int32) error) (*MPPayment, error) {
+
+	ctx := context.TODO()
+
+	var (
+		writeTxOpts PaymentQueriesTxOptions
+		payment     *MPPayment
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLPaymentQueries) error {
+		row, p, err := fetchSQLPayment(ctx, db, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		// We can only update attempts of in-flight payments. We allow
+		// updating them even if the payment has reached a terminal
+		// condition, since the HTLC outcomes must still be recorded.
+		if err := ensureInFlight(p); err != nil {
+			return err
+		}
+
+		var htlc *HTLCAttempt
+		for i := range p.HTLCs {
+			if p.HTLCs[i].AttemptID == attemptID {
+				htlc = &p.HTLCs[i]
+				break
+			}
+		}
+
+		// Make sure the shard exists and is not already failed or
+		// settled.
+		switch {
+		case htlc == nil:
+			return fmt.Errorf("HTLC with ID %v not registered",
+				attemptID)
+
+		case htlc.Failure != nil:
+			return ErrAttemptAlreadyFailed
+
+		case htlc.Settle != nil:
+			return ErrAttemptAlreadySettled
+		}
+
+		if err := update(db, row.ID); err != nil {
+			return err
+		}
+
+		payment, err = syncSQLPaymentStatus(ctx, db, paymentHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// Fail transitions a payment into the Failed state, and records the reason the
+// payment failed. After invoking this method, InitPayment should return nil on
+// its next call for this payment hash, allowing the switch to make a
+// subsequent payment.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) Fail(paymentHash lntypes.Hash,
+	reason FailureReason) (*MPPayment, error) {
+
+	ctx := context.TODO()
+
+	var (
+		writeTxOpts PaymentQueriesTxOptions
+		payment     *MPPayment
+	)
+	err := s.db.ExecTx(ctx, &writeTxOpts, func(db SQLPaymentQueries) error {
+		row, p, err := fetchSQLPayment(ctx, db, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		// We mark the payment as failed as long as it is known. This
+		// lets the last attempt to fail with a terminal write its
+		// failure without synchronizing with other attempts.
+		p.FailureReason = &reason
+		p.Status = decidePaymentStatus(p.HTLCs, p.FailureReason)
+
+		params := sqlc.UpdatePaymentStatusParams{
+			ID:     row.ID,
+			Status: int16(p.Status),
+			FailReason: sql.NullInt16{
+				Int16: int16(reason),
+				Valid: true,
+			},
+		}
+		if err := db.UpdatePaymentStatus(ctx, params); err != nil {
+			return fmt.Errorf("unable to update payment: %w", err)
+		}
+
+		payment = p
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// FetchPayment returns information about a payment from the database.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) FetchPayment(paymentHash lntypes.Hash) (*MPPayment,
+	error) {
+
+	ctx := context.TODO()
+
+	var payment *MPPayment
+	readTxOpt := NewPaymentQueryReadTx()
+	err := s.db.ExecTx(ctx, &readTxOpt, func(db SQLPaymentQueries) error {
+		var err error
+		_, payment, err = fetchSQLPayment(ctx, db, paymentHash)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// FetchInFlightPayments returns all payments with status InFlight.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) FetchInFlightPayments() ([]*MPPayment, error) {
+	ctx := context.TODO()
+
+	var inFlights []*MPPayment
+	readTxOpt := NewPaymentQueryReadTx()
+	err := s.db.ExecTx(ctx, &readTxOpt, func(db SQLPaymentQueries) error {
+		rows, err := db.FetchPaymentsByStatus(
+			ctx, int16(StatusInFlight),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch in-flight "+
+				"payments: %w", err)
+		}
+
+		inFlights, err = unmarshalSQLPayments(ctx, db, rows)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inFlights, nil
+}
+
+// QueryPayments is a query to the payments database which is restricted to a
+// subset of payments by the payments query, containing an offset index and a
+// maximum number of returned payments.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) QueryPayments(query PaymentsQuery) (PaymentsResponse,
+	error) {
+
+	ctx := context.TODO()
+
+	params := sqlc.FilterPaymentsParams{
+		Reverse:  query.Reversed,
+		NumLimit: int32(min(query.MaxPayments, math.MaxInt32)),
+	}
+
+	// The index offset is exclusive and, depending on the direction of the
+	// query, either marks the start or the end of the queried range.
+	if query.IndexOffset != 0 {
+		offset := sql.NullInt64{
+			Int64: int64(min(query.IndexOffset, math.MaxInt64)),
+			Valid: true,
+		}
+		if query.Reversed {
+			params.SequenceNumBefore = offset
+		} else {
+			params.SequenceNumAfter = offset
+		}
+	}
+
+	// To keep compatibility with the old API, we only return non-succeeded
+	// payments if requested.
+	if !query.IncludeIncomplete {
+		params.Status = sql.NullInt16{
+			Int16: int16(StatusSucceeded),
+			Valid: true,
+		}
+	}
+
+	if !query.CreationDateStart.IsZero() {
+		params.CreatedAfter = sqlTime(query.CreationDateStart)
+	}
+
+	if !query.CreationDateEnd.IsZero() {
+		params.CreatedBefore = sqlTime(query.CreationDateEnd)
+	}
+
+	var resp PaymentsResponse
+	readTxOpt := NewPaymentQueryReadTx()
+	err := s.db.ExecTx(ctx, &readTxOpt, func(db SQLPaymentQueries) error {
+		resp = PaymentsResponse{}
+
+		rows, err := db.FilterPayments(ctx, params)
+		if err != nil {
+			return fmt.Errorf("unable to query payments: %w", err)
+		}
+
+		resp.Payments, err = unmarshalSQLPayments(ctx, db, rows)
+		if err != nil {
+			return err
+		}
+
+		if !query.CountTotal {
+			return nil
+		}
+
+		totalPayments, err := db.CountPayments(ctx)
+		if err != nil {
+			return fmt.Errorf("error counting payments: %w", err)
+		}
+		resp.TotalCount = uint64(totalPayments)
+
+		return nil
+	})
+	if err != nil {
+		return PaymentsResponse{}, err
+	}
+
+	// Reversed queries are returned in descending order by the database,
+	// but callers expect the payments in order of creation.
+	if query.Reversed {
+		for l, r := 0, len(resp.Payments)-1; l < r; l, r = l+1, r-1 {
+			resp.Payments[l], resp.Payments[r] =
+				resp.Payments[r], resp.Payments[l]
+		}
+	}
+
+	// Set the first and last index of the returned payments so that the
+	// caller can resume from this point later on.
+	if len(resp.Payments) > 0 {
+		resp.FirstIndexOffset = resp.Payments[0].SequenceNum
+		resp.LastIndexOffset =
+			resp.Payments[len(resp.Payments)-1].SequenceNum
+	}
+
+	return resp, nil
+}
+
+// DeletePayment deletes a payment from the DB given its payment hash. If
+// failedHtlcsOnly is set, only failed HTLC attempts of the payment will be
+// deleted.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) DeletePayment(paymentHash lntypes.Hash,
+	failedHtlcsOnly bool) error {
+
+	ctx := context.TODO()
+
+	var writeTxOpts PaymentQueriesTxOptions
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLPaymentQueries) error {
+		row, payment, err := fetchSQLPayment(ctx, db, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		// If the status is InFlight, we cannot safely delete the
+		// payment information, so we return an error.
+		if payment.Status == StatusInFlight {
+			return fmt.Errorf("payment '%v' has status InFlight "+
+				"and therefore cannot be deleted",
+				paymentHash.String())
+		}
+
+		if failedHtlcsOnly {
+			return db.DeleteFailedPaymentHTLCAttempts(ctx, row.ID)
+		}
+
+		return db.DeletePayment(ctx, row.ID)
+	})
+}
+
+// DeletePayments deletes all completed and failed payments from the DB. If
+// failedOnly is set, only failed payments will be considered for deletion. If
+// failedHtlcsOnly is set, the payment itself won't be deleted, only failed HTLC
+// attempts.
+//
+// NOTE: This is part of the PaymentStore interface.
+func (s *SQLPaymentStore) DeletePayments(failedOnly,
+	failedHtlcsOnly bool) error {
+
+	ctx := context.TODO()
+
+	// In-flight payments can never be deleted safely.
+	statuses := []PaymentStatus{StatusFailed}
+	if !failedOnly {
+		statuses = append(statuses, StatusSucceeded)
+	}
+
+	var writeTxOpts PaymentQueriesTxOptions
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLPaymentQueries) error {
+		for _, status := range statuses {
+			var err error
+			if failedHtlcsOnly {
+				//nolint:lll
+				err = db.DeleteFailedHTLCAttemptsByPaymentStatus(
+					ctx, int16(status),
+				)
+			} else {
+				err = db.DeletePaymentsByStatus(
+					ctx, int16(status),
+				)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to delete %v "+
+					"payments: %w", status, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// nextSequenceNum returns the next sequence number to store for a new payment.
+func (s *SQLPaymentStore) nextSequenceNum(ctx context.Context) (uint64,
+	error) {
+
+	s.seqMtx.Lock()
+	defer s.seqMtx.Unlock()
+
+	// We lazily initialize the counter from the highest sequence number
+	// in the database, which also covers any migrated payments.
+	if !s.seqLoaded {
+		lastSeqNum, err := s.db.GetMaxPaymentSequenceNum(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("unable to fetch payment "+
+				"sequence number: %w", err)
+		}
+
+		s.lastSeqNum = uint64(lastSeqNum)
+		s.seqLoaded = true
+	}
+
+	s.lastSeqNum++
+
+	return s.lastSeqNum, nil
+}
+
+// resetSequenceNum makes sure the sequence number counter is re-initialized
+// from the database the next time a sequence number is needed.
+func (s *SQLPaymentStore) resetSequenceNum() {
+	s.seqMtx.Lock()
+	defer s.seqMtx.Unlock()
+
+	s.seqLoaded = false
+}
+
+// fetchSQLPayment fetches the payment with the given hash along with all its
+// HTLC attempts. ErrPaymentNotInitiated is returned if the payment is unknown.
+func fetchSQLPayment(ctx context.Context, db SQLPaymentQueries,
+	paymentHash lntypes.Hash) (sqlc.Payment, *MPPayment, error) {
+
+	row, err := db.GetPayment(ctx, paymentHash[:])
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return row, nil, ErrPaymentNotInitiated
+
+	case err != nil:
+		return row, nil, fmt.Errorf("unable to fetch payment: %w", err)
+	}
+
+	payment, err := unmarshalSQLPayment(ctx, db, row)
+	if err != nil {
+		return row, nil, err
+	}
+
+	return row, payment, nil
+}
+
+// syncSQLPaymentStatus re-derives the status of the payment with the given
+// hash from its HTLC attempts and persists it if it changed. The up to date
+// payment is returned.
+func syncSQLPaymentStatus(ctx context.Context, db SQLPaymentQueries,
+	paymentHash lntypes.Hash) (*MPPayment, error) {
+
+	row, payment, err := fetchSQLPayment(ctx, db, paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if PaymentStatus(row.Status) == payment.Status {
+		return payment, nil
+	}
+
+	err = db.UpdatePaymentStatus(ctx, sqlc.UpdatePaymentStatusParams{
+		ID:         row.ID,
+		Status:     int16(payment.Status),
+		FailReason: row.FailReason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to update payment status: %w",
+			err)
+	}
+
+	return payment, nil
+}
+
+// insertSQLHTLCAttempt inserts a new HTLC attempt for the payment with the
+// given id.
+func insertSQLHTLCAttempt(ctx context.Context, db SQLPaymentQueries,
+	paymentID int32, attempt *HTLCAttemptInfo) error {
+
+	var b bytes.Buffer
+	if err := SerializeRoute(&b, attempt.Route); err != nil {
+		return err
+	}
+
+	var firstHopChanID uint64
+	if len(attempt.Route.Hops) > 0 {
+		firstHopChanID = attempt.Route.Hops[0].ChannelID
+	}
+
+	var hash []byte
+	if attempt.Hash != nil {
+		hash = attempt.Hash[:]
+	}
+
+	err := db.InsertPaymentHTLCAttempt(
+		ctx, sqlc.InsertPaymentHTLCAttemptParams{
+			AttemptID:      int64(attempt.AttemptID),
+			SessionKey:     attempt.sessionKey[:],
+			Route:          b.Bytes(),
+			FirstHopChanID: strconv.FormatUint(firstHopChanID, 10),
+			Hash:           hash,
+			AttemptTime:    attempt.AttemptTime.UTC(),
+			PaymentID:      paymentID,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to insert htlc attempt: %w", err)
+	}
+
+	return nil
+}
+
+// settleSQLHTLCAttempt records the settle info of an HTLC attempt.
+func settleSQLHTLCAttempt(ctx context.Context, db SQLPaymentQueries,
+	paymentID int32, attemptID uint64, settleInfo *HTLCSettleInfo) error {
+
+	err := db.SettlePaymentHTLCAttempt(
+		ctx, sqlc.SettlePaymentHTLCAttemptParams{
+			PaymentID:      paymentID,
+			AttemptID:      int64(attemptID),
+			SettlePreimage: settleInfo.Preimage[:],
+			SettleTime:     sqlTime(settleInfo.SettleTime),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to settle htlc attempt: %w", err)
+	}
+
+	return nil
+}
+
+// failSQLHTLCAttempt records the failure info of an HTLC attempt.
+func failSQLHTLCAttempt(ctx context.Context, db SQLPaymentQueries,
+	paymentID int32, attemptID uint64, failInfo *HTLCFailInfo) error {
+
+	var message bytes.Buffer
+	if failInfo.Message != nil {
+		err := lnwire.EncodeFailureMessage(
+			&message, failInfo.Message, 0,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := db.FailPaymentHTLCAttempt(ctx, sqlc.FailPaymentHTLCAttemptParams{
+		PaymentID: paymentID,
+		AttemptID: int64(attemptID),
+		FailReason: sql.NullInt16{
+			Int16: int16(failInfo.Reason),
+			Valid: true,
+		},
+		FailMessage: message.Bytes(),
+		FailSourceIndex: sql.NullInt32{
+			Int32: int32(failInfo.FailureSourceIndex),
+			Valid: true,
+		},
+		FailTime: sqlTime(failInfo.FailTime),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to fail htlc attempt: %w", err)
+	}
+
+	return nil
+}
+
+// unmarshalSQLPayments converts the given payment rows to MPPayments, fetching
+// the HTLC attempts of each of them.
+func unmarshalSQLPayments(ctx context.Context, db SQLPaymentQueries,
+	rows []sqlc.Payment) ([]*MPPayment, error) {
+
+	payments := make([]*MPPayment, 0, len(rows))
+	for _, row := range rows {
+		payment, err := unmarshalSQLPayment(ctx, db, row)
+		if err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+// unmarshalSQLPayment converts a payment row to an MPPayment, fetching its
+// HTLC attempts.
+func unmarshalSQLPayment(ctx context.Context, db SQLPaymentQueries,
+	row sqlc.Payment) (*MPPayment, error) {
+
+	hash, err := lntypes.MakeHash(row.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := db.GetPaymentHTLCAttempts(ctx, row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch htlc attempts: %w", err)
+	}
+
+	var htlcs []HTLCAttempt
+	for _, attempt := range attempts {
+		htlc, err := unmarshalSQLHTLCAttempt(attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		htlcs = append(htlcs, *htlc)
+	}
+
+	var failureReason *FailureReason
+	if row.FailReason.Valid {
+		reason := FailureReason(row.FailReason.Int16)
+		failureReason = &reason
+	}
+
+	return &MPPayment{
+		SequenceNum: uint64(row.SequenceNum),
+		Info: &PaymentCreationInfo{
+			PaymentIdentifier: hash,
+			Value:             lnwire.MilliSatoshi(row.AmountMsat),
+			CreationTime:      unmarshalSQLTime(row.CreatedAt),
+			PaymentRequest:    row.PaymentRequest,
+		},
+		HTLCs:         htlcs,
+		FailureReason: failureReason,
+		Status:        decidePaymentStatus(htlcs, failureReason),
+	}, nil
+}
+
+// unmarshalSQLHTLCAttempt converts an HTLC attempt row to an HTLCAttempt.
+func unmarshalSQLHTLCAttempt(row sqlc.PaymentHtlcAttempt) (*HTLCAttempt,
+	error) {
+
+	rt, err := DeserializeRoute(bytes.NewReader(row.Route))
+	if err != nil {
+		return nil, err
+	}
+
+	htlc := &HTLCAttempt{
+		HTLCAttemptInfo: HTLCAttemptInfo{
+			AttemptID:   uint64(row.AttemptID),
+			Route:       rt,
+			AttemptTime: unmarshalSQLTime(row.AttemptTime),
+		},
+	}
+	copy(htlc.sessionKey[:], row.SessionKey)
+
+	if len(row.Hash) > 0 {
+		hash, err := lntypes.MakeHash(row.Hash)
+		if err != nil {
+			return nil, err
+		}
+		htlc.Hash = &hash
+	}
+
+	if row.SettleTime.Valid {
+		preimage, err := lntypes.MakePreimage(row.SettlePreimage)
+		if err != nil {
+			return nil, err
+		}
+
+		htlc.Settle = &HTLCSettleInfo{
+			Preimage:   preimage,
+			SettleTime: unmarshalSQLTime(row.SettleTime.Time),
+		}
+	}
+
+	if row.FailTime.Valid {
+		htlc.Failure = &HTLCFailInfo{
+			FailTime: unmarshalSQLTime(row.FailTime.Time),
+			Reason:   HTLCFailReason(row.FailReason.Int16),
+			FailureSourceIndex: uint32(
+				row.FailSourceIndex.Int32,
+			),
+		}
+
+		if len(row.FailMessage) > 0 {
+			htlc.Failure.Message, err = lnwire.DecodeFailureMessage(
+				bytes.NewReader(row.FailMessage), 0,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return htlc, nil
+}
+
+// sqlTime turns a time.Time into the NullTime that sql/sqlc uses when a time
+// field can be permitted to be NULL. Times are always stored in UTC.
+func sqlTime(t time.Time) sql.NullTime {
+	return sql.NullTime{
+		Time:  t.UTC(),
+		Valid: true,
+	}
+}
+
+// unmarshalSQLTime converts a time read from the database to the same
+// representation the key-value store uses, which is a local time without a
+// monotonic clock reading. A zero time is kept as is, since calling UnixNano()
+// on it yields an undefined result.
+func unmarshalSQLTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+
+	return time.Unix(0, t.UnixNano())
+}
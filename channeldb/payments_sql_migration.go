@@ -0,0 +1,176 @@
+package channeldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/kvdb"
+	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/sqldb/sqlc"
+)
+
+// MigratePaymentsToSQL copies all payments and their HTLC attempts from the
+// key-value store of the given DB into the native SQL payment store. The
+// migration is applied in a single SQL transaction and is skipped if the SQL
+// store already contains payments, so it is safe to call on every start up.
+// The payments are left untouched in the key-value store.
+//
+// NOTE: Duplicate payments created by old versions of lnd can't be represented
+// in the SQL schema and are therefore not migrated.
+func MigratePaymentsToSQL(ctx context.Context, kvDB *DB,
+	sqlStore *SQLPaymentStore) error {
+
+	var (
+		writeTxOpts PaymentQueriesTxOptions
+		skipped     bool
+		numPayments int
+	)
+	err := sqlStore.db.ExecTx(ctx, &writeTxOpts,
+		func(db SQLPaymentQueries) error {
+			skipped, numPayments = false, 0
+
+			count, err := db.CountPayments(ctx)
+			if err != nil {
+				return fmt.Errorf("unable to count payments: "+
+					"%w", err)
+			}
+
+			// The SQL store is only ever populated after the
+			// migration, so there's nothing left to do if it
+			// already contains payments.
+			if count > 0 {
+				skipped = true
+				return nil
+			}
+
+			return kvdb.View(kvDB, func(tx kvdb.RTx) error {
+				numPayments = 0
+
+				return migratePaymentsToSQL(ctx, tx, db,
+					&numPayments)
+			}, func() {})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to migrate payments to SQL: %w", err)
+	}
+
+	if skipped {
+		log.Debugf("Payments already migrated to SQL store")
+		return nil
+	}
+
+	// New payments must continue after the sequence numbers of the
+	// migrated ones.
+	sqlStore.resetSequenceNum()
+
+	log.Infof("Migrated %d payments to SQL store", numPayments)
+
+	return nil
+}
+
+// migratePaymentsToSQL inserts all payments found in the key-value payments
+// bucket into the SQL store, counting the number of migrated payments.
+func migratePaymentsToSQL(ctx context.Context, tx kvdb.RTx,
+	db SQLPaymentQueries, numPayments *int) error {
+
+	payments := tx.ReadBucket(paymentsRootBucket)
+	if payments == nil {
+		return nil
+	}
+
+	return payments.ForEach(func(k, _ []byte) error {
+		bucket := payments.NestedReadBucket(k)
+		if bucket == nil {
+			// We only expect sub-buckets to be found in this
+			// top-level bucket.
+			return fmt.Errorf("non bucket element in payments " +
+				"bucket")
+		}
+
+		// Payments without creation info were never initiated, so
+		// there's nothing to migrate.
+		if bucket.Get(paymentCreationInfoKey) == nil {
+			return nil
+		}
+
+		paymentHash, err := lntypes.MakeHash(k)
+		if err != nil {
+			return err
+		}
+
+		payment, err := fetchPayment(bucket)
+		if err != nil {
+			return fmt.Errorf("unable to fetch payment %v: %w",
+				paymentHash, err)
+		}
+
+		err = insertSQLPayment(ctx, db, paymentHash, payment)
+		if err != nil {
+			return fmt.Errorf("unable to migrate payment %v: %w",
+				paymentHash, err)
+		}
+
+		*numPayments++
+
+		return nil
+	})
+}
+
+// insertSQLPayment inserts the given payment along with all its HTLC attempts,
+// keeping its sequence number and status.
+func insertSQLPayment(ctx context.Context, db SQLPaymentQueries,
+	paymentHash lntypes.Hash, payment *MPPayment) error {
+
+	var failReason sql.NullInt16
+	if payment.FailureReason != nil {
+		failReason = sql.NullInt16{
+			Int16: int16(*payment.FailureReason),
+			Valid: true,
+		}
+	}
+
+	paymentID, err := db.InsertPayment(ctx, sqlc.InsertPaymentParams{
+		SequenceNum:    int64(payment.SequenceNum),
+		Hash:           paymentHash[:],
+		AmountMsat:     int64(payment.Info.Value),
+		PaymentRequest: payment.Info.PaymentRequest,
+		Status:         int16(payment.Status),
+		FailReason:     failReason,
+		CreatedAt:      payment.Info.CreationTime.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to insert payment: %w", err)
+	}
+
+	for _, htlc := range payment.HTLCs {
+		err := insertSQLHTLCAttempt(
+			ctx, db, paymentID, &htlc.HTLCAttemptInfo,
+		)
+		if err != nil {
+			return err
+		}
+
+		if htlc.Settle != nil {
+			err := settleSQLHTLCAttempt(
+				ctx, db, paymentID, htlc.AttemptID, htlc.Settle,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if htlc.Failure != nil {
+			err := failSQLHTLCAttempt(
+				ctx, db, paymentID, htlc.AttemptID,
+				htlc.Failure,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,429 @@
+package channeldb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/sqldb"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSQLPaymentStore creates a new SQL payment store backed by a fresh
+// sqlite database.
+func newTestSQLPaymentStore(t *testing.T,
+	keepFailedPaymentAttempts bool) *SQLPaymentStore {
+
+	t.Helper()
+
+	db := sqldb.NewTestSqliteDB(t).BaseDB
+	executor := sqldb.NewTransactionExecutor(
+		db, func(tx *sql.Tx) SQLPaymentQueries {
+			return db.WithTx(tx)
+		},
+	)
+
+	return NewSQLPaymentStore(executor, keepFailedPaymentAttempts)
+}
+
+// TestSQLPaymentStoreLifecycle tests that a payment and its HTLC attempts go
+// through the expected states when stored in the SQL payment store.
+func TestSQLPaymentStoreLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := newTestSQLPaymentStore(t, true)
+
+	info, attempt, preimg, err := genInfo()
+	require.NoError(t, err)
+	hash := info.PaymentIdentifier
+
+	// Unknown payments can't be fetched.
+	_, err = store.FetchPayment(hash)
+	require.ErrorIs(t, err, ErrPaymentNotInitiated)
+
+	require.NoError(t, store.InitPayment(hash, info))
+
+	// A second init of an in-flight payment must fail.
+	err = store.InitPayment(hash, info)
+	require.ErrorIs(t, err, ErrPaymentInFlight)
+
+	payment, err := store.FetchPayment(hash)
+	require.NoError(t, err)
+	require.Equal(t, StatusInFlight, payment.Status)
+	require.Equal(t, info, payment.Info)
+	require.EqualValues(t, 1, payment.SequenceNum)
+	require.Empty(t, payment.HTLCs)
+
+	// Register an attempt and fail it with a wire message.
+	payment, err = store.RegisterAttempt(hash, attempt)
+	require.NoError(t, err)
+	require.Len(t, payment.HTLCs, 1)
+	require.Equal(t, attempt.Route, payment.HTLCs[0].Route)
+	require.Equal(
+		t, attempt.SessionKey().Serialize(),
+		payment.HTLCs[0].SessionKey().Serialize(),
+	)
+
+	failInfo := &HTLCFailInfo{
+		FailTime:           time.Unix(100, 500),
+		Message:            lnwire.NewTemporaryChannelFailure(nil),
+		Reason:             HTLCFailMessage,
+		FailureSourceIndex: 1,
+	}
+	payment, err = store.FailAttempt(hash, attempt.AttemptID, failInfo)
+	require.NoError(t, err)
+	require.Equal(t, StatusInFlight, payment.Status)
+	require.Equal(t, failInfo, payment.HTLCs[0].Failure)
+
+	// The attempt can't be resolved twice.
+	_, err = store.FailAttempt(hash, attempt.AttemptID, failInfo)
+	require.ErrorIs(t, err, ErrAttemptAlreadyFailed)
+
+	// Register a second attempt and settle it, which should complete the
+	// payment.
+	attempt.AttemptID = 1
+	_, err = store.RegisterAttempt(hash, attempt)
+	require.NoError(t, err)
+
+	settleInfo := &HTLCSettleInfo{
+		Preimage:   preimg,
+		SettleTime: time.Unix(200, 0),
+	}
+	payment, err = store.SettleAttempt(hash, attempt.AttemptID, settleInfo)
+	require.NoError(t, err)
+	require.Equal(t, StatusSucceeded, payment.Status)
+	require.Len(t, payment.HTLCs, 2)
+	require.Equal(t, settleInfo, payment.HTLCs[1].Settle)
+
+	// No more attempts can be registered and the payment can't be
+	// initiated again.
+	attempt.AttemptID = 2
+	_, err = store.RegisterAttempt(hash, attempt)
+	require.ErrorIs(t, err, ErrPaymentTerminal)
+
+	err = store.InitPayment(hash, info)
+	require.ErrorIs(t, err, ErrAlreadyPaid)
+
+	inFlight, err := store.FetchInFlightPayments()
+	require.NoError(t, err)
+	require.Empty(t, inFlight)
+}
+
+// TestSQLPaymentStoreFailRetry tests that a failed payment can be initiated
+// again, which replaces the previous payment and its HTLC attempts.
+func TestSQLPaymentStoreFailRetry(t *testing.T) {
+	t.Parallel()
+
+	store := newTestSQLPaymentStore(t, true)
+
+	info, attempt, _, err := genInfo()
+	require.NoError(t, err)
+	hash := info.PaymentIdentifier
+
+	require.NoError(t, store.InitPayment(hash, info))
+
+	_, err = store.RegisterAttempt(hash, attempt)
+	require.NoError(t, err)
+
+	inFlight, err := store.FetchInFlightPayments()
+	require.NoError(t, err)
+	require.Len(t, inFlight, 1)
+
+	// Failing the payment while an attempt is in flight keeps the payment
+	// in flight.
+	payment, err := store.Fail(hash, FailureReasonNoRoute)
+	require.NoError(t, err)
+	require.Equal(t, StatusInFlight, payment.Status)
+	require.Equal(t, FailureReasonNoRoute, *payment.FailureReason)
+
+	// Once the last attempt failed, the payment is failed as well.
+	payment, err = store.FailAttempt(hash, attempt.AttemptID, &HTLCFailInfo{
+		Reason: HTLCFailUnreadable,
+	})
+	require.NoError(t, err)
+	require.Equal(t, StatusFailed, payment.Status)
+
+	inFlight, err = store.FetchInFlightPayments()
+	require.NoError(t, err)
+	require.Empty(t, inFlight)
+
+	// The payment may now be retried, which starts it from scratch.
+	require.NoError(t, store.InitPayment(hash, info))
+
+	payment, err = store.FetchPayment(hash)
+	require.NoError(t, err)
+	require.Equal(t, StatusInFlight, payment.Status)
+	require.Nil(t, payment.FailureReason)
+	require.Empty(t, payment.HTLCs)
+	require.EqualValues(t, 2, payment.SequenceNum)
+}
+
+// TestSQLPaymentStoreQueryPayments tests that payments are correctly filtered
+// and paginated by the SQL payment store.
+func TestSQLPaymentStoreQueryPayments(t *testing.T) {
+	t.Parallel()
+
+	store := newTestSQLPaymentStore(t, true)
+
+	// Create five payments one hour apart, where all but the third one
+	// succeed.
+	startTime := time.Unix(1_700_000_000, 0)
+	hashes := make([]lntypes.Hash, 5)
+	for i := range hashes {
+		info, attempt, preimg, err := genInfo()
+		require.NoError(t, err)
+
+		hashes[i] = info.PaymentIdentifier
+		info.CreationTime = startTime.Add(time.Duration(i) * time.Hour)
+
+		require.NoError(t, store.InitPayment(hashes[i], info))
+
+		_, err = store.RegisterAttempt(hashes[i], attempt)
+		require.NoError(t, err)
+
+		if i == 2 {
+			continue
+		}
+
+		_, err = store.SettleAttempt(
+			hashes[i], attempt.AttemptID,
+			&HTLCSettleInfo{Preimage: preimg},
+		)
+		require.NoError(t, err)
+	}
+
+	queryHashes := func(query PaymentsQuery) ([]lntypes.Hash, uint64,
+		uint64) {
+
+		resp, err := store.QueryPayments(query)
+		require.NoError(t, err)
+
+		var result []lntypes.Hash
+		for _, p := range resp.Payments {
+			result = append(result, p.Info.PaymentIdentifier)
+		}
+
+		return result, resp.FirstIndexOffset, resp.LastIndexOffset
+	}
+
+	testCases := []struct {
+		name        string
+		query       PaymentsQuery
+		expected    []lntypes.Hash
+		first, last uint64
+	}{
+		{
+			name: "all succeeded",
+			query: PaymentsQuery{
+				MaxPayments: 10,
+			},
+			expected: []lntypes.Hash{
+				hashes[0], hashes[1], hashes[3], hashes[4],
+			},
+			first: 1,
+			last:  5,
+		},
+		{
+			name: "include incomplete with offset",
+			query: PaymentsQuery{
+				IndexOffset:       1,
+				MaxPayments:       2,
+				IncludeIncomplete: true,
+			},
+			expected: []lntypes.Hash{hashes[1], hashes[2]},
+			first:    2,
+			last:     3,
+		},
+		{
+			name: "reversed with offset",
+			query: PaymentsQuery{
+				IndexOffset:       5,
+				MaxPayments:       2,
+				Reversed:          true,
+				IncludeIncomplete: true,
+			},
+			expected: []lntypes.Hash{hashes[2], hashes[3]},
+			first:    3,
+			last:     4,
+		},
+		{
+			name: "creation date range",
+			query: PaymentsQuery{
+				MaxPayments:       10,
+				IncludeIncomplete: true,
+				CreationDateStart: startTime.Add(time.Hour),
+				CreationDateEnd:   startTime.Add(3 * time.Hour),
+			},
+			expected: []lntypes.Hash{
+				hashes[1], hashes[2], hashes[3],
+			},
+			first: 2,
+			last:  4,
+		},
+		{
+			name: "no results",
+			query: PaymentsQuery{
+				IndexOffset: 5,
+				MaxPayments: 10,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			result, first, last := queryHashes(tc.query)
+			require.Equal(t, tc.expected, result)
+			require.Equal(t, tc.first, first)
+			require.Equal(t, tc.last, last)
+		})
+	}
+
+	resp, err := store.QueryPayments(PaymentsQuery{
+		MaxPayments: 1,
+		CountTotal:  true,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 5, resp.TotalCount)
+}
+
+// TestSQLPaymentStoreDeletePayments tests that only payments and HTLC attempts
+// in a terminal state are deleted from the SQL payment store.
+func TestSQLPaymentStoreDeletePayments(t *testing.T) {
+	t.Parallel()
+
+	store := newTestSQLPaymentStore(t, true)
+
+	statuses := []PaymentStatus{
+		StatusFailed, StatusSucceeded, StatusInFlight,
+	}
+	hashes := make([]lntypes.Hash, len(statuses))
+	for i, status := range statuses {
+		info, attempt, preimg, err := genInfo()
+		require.NoError(t, err)
+		hashes[i] = info.PaymentIdentifier
+
+		require.NoError(t, store.InitPayment(hashes[i], info))
+
+		// Every payment gets one failed attempt.
+		_, err = store.RegisterAttempt(hashes[i], attempt)
+		require.NoError(t, err)
+		_, err = store.FailAttempt(
+			hashes[i], attempt.AttemptID,
+			&HTLCFailInfo{Reason: HTLCFailUnreadable},
+		)
+		require.NoError(t, err)
+
+		switch status {
+		case StatusFailed:
+			_, err = store.Fail(hashes[i], FailureReasonNoRoute)
+			require.NoError(t, err)
+
+		case StatusSucceeded:
+			attempt.AttemptID = 1
+			_, err = store.RegisterAttempt(hashes[i], attempt)
+			require.NoError(t, err)
+			_, err = store.SettleAttempt(
+				hashes[i], attempt.AttemptID,
+				&HTLCSettleInfo{Preimage: preimg},
+			)
+			require.NoError(t, err)
+
+		case StatusInFlight:
+			attempt.AttemptID = 1
+			_, err = store.RegisterAttempt(hashes[i], attempt)
+			require.NoError(t, err)
+		}
+	}
+
+	assertNumHTLCs := func(hash lntypes.Hash, num int) {
+		t.Helper()
+
+		payment, err := store.FetchPayment(hash)
+		require.NoError(t, err)
+		require.Len(t, payment.HTLCs, num)
+	}
+
+	// In-flight payments can't be deleted.
+	require.Error(t, store.DeletePayment(hashes[2], false))
+
+	// Deleting the failed HTLCs of the succeeded payment only removes the
+	// failed attempt.
+	require.NoError(t, store.DeletePayment(hashes[1], true))
+	assertNumHTLCs(hashes[1], 1)
+
+	// Deleting all failed HTLCs leaves the in-flight payment untouched.
+	require.NoError(t, store.DeletePayments(false, true))
+	assertNumHTLCs(hashes[0], 0)
+	assertNumHTLCs(hashes[2], 2)
+
+	// Deleting only failed payments keeps the succeeded one.
+	require.NoError(t, store.DeletePayments(true, false))
+	_, err := store.FetchPayment(hashes[0])
+	require.ErrorIs(t, err, ErrPaymentNotInitiated)
+	assertNumHTLCs(hashes[1], 1)
+
+	// Finally, delete all completed payments.
+	require.NoError(t, store.DeletePayments(false, false))
+	_, err = store.FetchPayment(hashes[1])
+	require.ErrorIs(t, err, ErrPaymentNotInitiated)
+	assertNumHTLCs(hashes[2], 2)
+}
+
+// TestMigratePaymentsToSQL tests that the payments of the key-value store are
+// migrated to the SQL payment store without any loss of information.
+func TestMigratePaymentsToSQL(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	pControl := NewPaymentControl(db)
+	payments := []*payment{
+		{status: StatusFailed},
+		{status: StatusSucceeded},
+		{status: StatusInFlight},
+		{status: StatusSucceeded},
+	}
+	createTestPayments(t, pControl, payments)
+
+	store := newTestSQLPaymentStore(t, false)
+	ctx := context.Background()
+	require.NoError(t, MigratePaymentsToSQL(ctx, db, store))
+
+	// Every payment must be identical in both stores.
+	for _, p := range payments {
+		kvPayment, err := pControl.FetchPayment(p.id)
+		require.NoError(t, err)
+
+		sqlPayment, err := store.FetchPayment(p.id)
+		require.NoError(t, err)
+
+		require.Equal(t, kvPayment, sqlPayment)
+		require.Equal(t, p.status, sqlPayment.Status)
+	}
+
+	// Running the migration again is a no-op.
+	require.NoError(t, MigratePaymentsToSQL(ctx, db, store))
+
+	resp, err := store.QueryPayments(PaymentsQuery{
+		MaxPayments:       10,
+		IncludeIncomplete: true,
+		CountTotal:        true,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, len(payments), resp.TotalCount)
+
+	// New payments continue after the migrated sequence numbers.
+	info, _, _, err := genInfo()
+	require.NoError(t, err)
+	require.NoError(t, store.InitPayment(info.PaymentIdentifier, info))
+
+	payment, err := store.FetchPayment(info.PaymentIdentifier)
+	require.NoError(t, err)
+	require.Equal(t, resp.LastIndexOffset+1, payment.SequenceNum)
+}
@@ -0,0 +1,430 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ltcsuite/lnd/kvdb"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+var (
+	// spliceKey can be accessed within the bucket for a channel. It
+	// stores the splice of the channel that is in progress, if any.
+	spliceKey = []byte("splice-key")
+)
+
+// ChannelSplice describes a splice of a channel that both peers have signed
+// the commitments for. The splice replaces the funding output of the channel
+// with a new one once the splice transaction has confirmed, and both peers
+// sent splice_locked.
+type ChannelSplice struct {
+	// SpliceTx is the splice transaction. Until both peers exchanged
+	// their tx_signatures, only the witnesses of our own inputs are
+	// populated.
+	SpliceTx *wire.MsgTx
+
+	// PrevOutpoint is the funding outpoint that is spent by the splice
+	// transaction.
+	PrevOutpoint wire.OutPoint
+
+	// FundingOutputIndex is the index of the new funding output within
+	// the splice transaction.
+	FundingOutputIndex uint32
+
+	// Capacity is the capacity of the channel after the splice.
+	Capacity ltcutil.Amount
+
+	// IsInitiator is true if we initiated the splice.
+	IsInitiator bool
+
+	// LocalContribution is the amount that our balance changes by. It is
+	// negative if funds were spliced out of our side of the channel.
+	LocalContribution ltcutil.Amount
+
+	// RemoteContribution is the amount that the balance of the remote
+	// peer changes by.
+	RemoteContribution ltcutil.Amount
+
+	// LocalCommitment is our commitment that spends the new funding
+	// output, including the signature of the remote peer.
+	LocalCommitment ChannelCommitment
+
+	// RemoteCommitment is the commitment of the remote peer that spends
+	// the new funding output.
+	RemoteCommitment ChannelCommitment
+
+	// LocalSharedSig is our signature for the current funding output,
+	// which is spent by the splice transaction.
+	LocalSharedSig []byte
+
+	// Signed is true once the splice transaction is fully signed and can
+	// be broadcast.
+	Signed bool
+
+	// BroadcastHeight is the height at which the splice transaction was
+	// first broadcast.
+	BroadcastHeight uint32
+
+	// ShortChannelID is the location of the new funding output in the
+	// chain. It is only set once the splice transaction has confirmed.
+	ShortChannelID lnwire.ShortChannelID
+
+	// LocalLocked is true once we sent splice_locked.
+	LocalLocked bool
+
+	// RemoteLocked is true once the remote peer sent splice_locked.
+	RemoteLocked bool
+}
+
+// FundingOutpoint returns the new funding outpoint of the channel.
+func (s *ChannelSplice) FundingOutpoint() wire.OutPoint {
+	return wire.OutPoint{
+		Hash:  s.SpliceTx.TxHash(),
+		Index: s.FundingOutputIndex,
+	}
+}
+
+// serializeChannelSplice writes the splice to the given writer.
+func serializeChannelSplice(w io.Writer, s *ChannelSplice) error {
+	err := WriteElements(w,
+		s.SpliceTx, s.PrevOutpoint, s.FundingOutputIndex, s.Capacity,
+		s.IsInitiator,
+		uint64(int64(s.LocalContribution)),
+		uint64(int64(s.RemoteContribution)), s.LocalSharedSig,
+		s.Signed, s.BroadcastHeight, s.ShortChannelID, s.LocalLocked,
+		s.RemoteLocked,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := serializeChanCommit(w, &s.LocalCommitment); err != nil {
+		return err
+	}
+
+	return serializeChanCommit(w, &s.RemoteCommitment)
+}
+
+// deserializeChannelSplice reads a splice that was written with
+// serializeChannelSplice.
+func deserializeChannelSplice(r io.Reader) (*ChannelSplice, error) {
+	var (
+		s                       ChannelSplice
+		localContr, remoteContr uint64
+		err                     error
+	)
+	err = ReadElements(r,
+		&s.SpliceTx, &s.PrevOutpoint, &s.FundingOutputIndex, &s.Capacity,
+		&s.IsInitiator, &localContr, &remoteContr, &s.LocalSharedSig,
+		&s.Signed, &s.BroadcastHeight, &s.ShortChannelID,
+		&s.LocalLocked, &s.RemoteLocked,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.LocalContribution = ltcutil.Amount(int64(localContr))
+	s.RemoteContribution = ltcutil.Amount(int64(remoteContr))
+
+	s.LocalCommitment, err = deserializeChanCommit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RemoteCommitment, err = deserializeChanCommit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// putSplice stores the splice within the channel bucket.
+func putSplice(chanBucket kvdb.RwBucket, splice *ChannelSplice) error {
+	var b bytes.Buffer
+	if err := serializeChannelSplice(&b, splice); err != nil {
+		return err
+	}
+
+	return chanBucket.Put(spliceKey, b.Bytes())
+}
+
+// fetchSplice reads the splice from the channel bucket.
+func fetchSplice(chanBucket kvdb.RBucket) (*ChannelSplice, error) {
+	spliceBytes := chanBucket.Get(spliceKey)
+	if spliceBytes == nil {
+		return nil, ErrNoSplice
+	}
+
+	return deserializeChannelSplice(bytes.NewReader(spliceBytes))
+}
+
+// MarkSplicing stores the splice of the channel and sets the
+// ChanStatusSplicing status. From this point on, the channel must not be
+// updated until the splice either completes, or the current funding output
+// is spent otherwise.
+func (c *OpenChannel) MarkSplicing(splice *ChannelSplice) error {
+	c.Lock()
+	defer c.Unlock()
+
+	putSpliceInfo := func(chanBucket kvdb.RwBucket) error {
+		return putSplice(chanBucket, splice)
+	}
+
+	return c.putChanStatus(ChanStatusSplicing, putSpliceInfo)
+}
+
+// updateSplice applies the passed modifier to the stored splice of the
+// channel.
+func (c *OpenChannel) updateSplice(modify func(*ChannelSplice)) error {
+	return kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		splice, err := fetchSplice(chanBucket)
+		if err != nil {
+			return err
+		}
+
+		modify(splice)
+
+		return putSplice(chanBucket, splice)
+	}, func() {})
+}
+
+// MarkSpliceSigned stores the fully signed splice transaction of the
+// channel, which can be broadcast from now on.
+func (c *OpenChannel) MarkSpliceSigned(spliceTx *wire.MsgTx) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.updateSplice(func(splice *ChannelSplice) {
+		splice.SpliceTx = spliceTx
+		splice.Signed = true
+	})
+}
+
+// MarkSpliceRemoteLocked records that the remote peer sent splice_locked for
+// the splice of the channel.
+func (c *OpenChannel) MarkSpliceRemoteLocked() error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.updateSplice(func(splice *ChannelSplice) {
+		splice.RemoteLocked = true
+	})
+}
+
+// FetchSplice returns the splice of the channel that is in progress. If there
+// is none, ErrNoSplice is returned.
+func (c *OpenChannel) FetchSplice() (*ChannelSplice, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var splice *ChannelSplice
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		splice, err = fetchSplice(chanBucket)
+		return err
+	}, func() {
+		splice = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return splice, nil
+}
+
+// CompleteSplice moves the channel over to the new funding output of its
+// splice once the splice transaction has confirmed at the passed location.
+// All channel data is moved to the new outpoint, and the funding parameters
+// and commitments of the channel are replaced with those of the splice. The
+// channel is removed from the outpoint index under its old outpoint. The
+// splice record is kept, marked as locked by us, until the remote peer sent
+// splice_locked as well. ErrNoSplice is returned if the channel isn't being
+// spliced.
+func (c *OpenChannel) CompleteSplice(scid lnwire.ShortChannelID) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var (
+		oldPoint = c.FundingOutpoint
+		channel  *OpenChannel
+	)
+	err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		oldBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &oldPoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		splice, err := fetchSplice(oldBucket)
+		if err != nil {
+			return err
+		}
+
+		channel, err = fetchOpenChannel(oldBucket, &oldPoint)
+		if err != nil {
+			return err
+		}
+		if !channel.HasChanStatus(ChanStatusSplicing) {
+			return ErrNoSplice
+		}
+
+		splice.ShortChannelID = scid
+		splice.LocalLocked = true
+
+		// Forwarding packages are keyed by the short channel ID, which
+		// changes with the splice. As the channel is clean, the
+		// packages of the old funding output can be removed.
+		if err := channel.Packager.Wipe(tx); err != nil {
+			return err
+		}
+
+		newPoint := splice.FundingOutpoint()
+		channel.FundingOutpoint = newPoint
+		channel.Capacity = splice.Capacity
+		channel.ShortChannelID = splice.ShortChannelID
+		channel.FundingTxn = splice.SpliceTx
+		channel.FundingBroadcastHeight = splice.BroadcastHeight
+		channel.LocalCommitment = splice.LocalCommitment
+		channel.RemoteCommitment = splice.RemoteCommitment
+		channel.chanStatus &^= ChanStatusSplicing
+		channel.Packager = NewChannelPackager(splice.ShortChannelID)
+
+		// Move all data of the channel, including its revocation log,
+		// over to a bucket for the new funding outpoint.
+		chainBucket := tx.ReadWriteBucket(openChannelBucket).
+			NestedReadWriteBucket(c.IdentityPub.SerializeCompressed()).
+			NestedReadWriteBucket(c.ChainHash[:])
+
+		var oldKey, newKey bytes.Buffer
+		if err := writeOutpoint(&oldKey, &oldPoint); err != nil {
+			return err
+		}
+		if err := writeOutpoint(&newKey, &newPoint); err != nil {
+			return err
+		}
+
+		newBucket, err := chainBucket.CreateBucket(newKey.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := copyBucket(newBucket, oldBucket); err != nil {
+			return err
+		}
+		err = chainBucket.DeleteNestedBucket(oldKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		if err := putSplice(newBucket, splice); err != nil {
+			return err
+		}
+		if err := putOpenChannel(newBucket, channel); err != nil {
+			return err
+		}
+
+		return spliceChannelIndex(tx, oldPoint, newPoint)
+	}, func() {
+		channel = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.FundingOutpoint = channel.FundingOutpoint
+	c.Capacity = channel.Capacity
+	c.ShortChannelID = channel.ShortChannelID
+	c.FundingTxn = channel.FundingTxn
+	c.FundingBroadcastHeight = channel.FundingBroadcastHeight
+	c.LocalCommitment = channel.LocalCommitment
+	c.RemoteCommitment = channel.RemoteCommitment
+	c.chanStatus = channel.chanStatus
+	c.Packager = channel.Packager
+
+	return nil
+}
+
+// spliceChannelIndex replaces the old funding outpoint of a spliced channel
+// with the new one in the outpoint and channel ID indexes.
+func spliceChannelIndex(tx kvdb.RwTx, oldPoint, newPoint wire.OutPoint) error {
+	opBucket := tx.ReadWriteBucket(outpointBucket)
+	cidBucket := tx.ReadWriteBucket(chanIDBucket)
+
+	putStatus := func(op wire.OutPoint, status indexStatus) error {
+		var opBuf bytes.Buffer
+		if err := writeOutpoint(&opBuf, &op); err != nil {
+			return err
+		}
+
+		statusByte := uint8(status)
+		statusRecord := tlv.MakePrimitiveRecord(
+			indexStatusType, &statusByte,
+		)
+		opStream, err := tlv.NewStream(statusRecord)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := opStream.Encode(&b); err != nil {
+			return err
+		}
+
+		return opBucket.Put(opBuf.Bytes(), b.Bytes())
+	}
+
+	if err := putStatus(oldPoint, outpointClosed); err != nil {
+		return err
+	}
+	if err := putStatus(newPoint, outpointOpen); err != nil {
+		return err
+	}
+
+	oldCid := lnwire.NewChanIDFromOutPoint(&oldPoint)
+	if err := cidBucket.Delete(oldCid[:]); err != nil {
+		return err
+	}
+
+	newCid := lnwire.NewChanIDFromOutPoint(&newPoint)
+
+	return cidBucket.Put(newCid[:], []byte{})
+}
+
+// copyBucket recursively copies all keys and nested buckets of src into dst.
+func copyBucket(dst kvdb.RwBucket, src kvdb.RwBucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+
+		srcNested := src.NestedReadWriteBucket(k)
+		if srcNested == nil {
+			return fmt.Errorf("nested bucket %x not found", k)
+		}
+
+		dstNested, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+
+		return copyBucket(dstNested, srcNested)
+	})
+}
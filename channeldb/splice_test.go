@@ -0,0 +1,123 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelSplice asserts that a splice of a channel can be stored,
+// updated and completed, moving the channel to the new funding outpoint.
+func TestChannelSplice(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	channel := createTestChannel(t, cdb, openChannelOption())
+	oldPoint := channel.FundingOutpoint
+
+	// Without a splice, there is nothing to fetch or complete.
+	_, err = channel.FetchSplice()
+	require.ErrorIs(t, err, ErrNoSplice)
+	require.ErrorIs(t, channel.MarkSpliceRemoteLocked(), ErrNoSplice)
+	require.ErrorIs(
+		t, channel.CompleteSplice(lnwire.ShortChannelID{}), ErrNoSplice,
+	)
+
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: oldPoint,
+		SignatureScript:  []byte{},
+	})
+	spliceTx.AddTxOut(&wire.TxOut{Value: 20_000, PkScript: []byte{0x01}})
+	spliceTx.AddTxOut(&wire.TxOut{Value: 1_000, PkScript: []byte{0x02}})
+
+	localCommit := channel.LocalCommitment
+	localCommit.LocalBalance += lnwire.NewMSatFromSatoshis(1_000)
+	remoteCommit := channel.RemoteCommitment
+	remoteCommit.LocalBalance += lnwire.NewMSatFromSatoshis(1_000)
+
+	splice := &ChannelSplice{
+		SpliceTx:           spliceTx,
+		PrevOutpoint:       oldPoint,
+		FundingOutputIndex: 0,
+		Capacity:           20_000,
+		IsInitiator:        true,
+		LocalContribution:  1_000,
+		RemoteContribution: 0,
+		LocalCommitment:    localCommit,
+		RemoteCommitment:   remoteCommit,
+		LocalSharedSig:     testSig.Serialize(),
+		BroadcastHeight:    200,
+	}
+	require.NoError(t, channel.MarkSplicing(splice))
+	require.True(t, channel.HasChanStatus(ChanStatusSplicing))
+
+	// The splice is returned as stored.
+	dbSplice, err := channel.FetchSplice()
+	require.NoError(t, err)
+	require.Equal(t, splice, dbSplice)
+
+	// Record the progress of the splice.
+	spliceTx.TxIn[0].Witness = wire.TxWitness{{0x01}}
+	require.NoError(t, channel.MarkSpliceSigned(spliceTx))
+	require.NoError(t, channel.MarkSpliceRemoteLocked())
+
+	splice.Signed = true
+	splice.RemoteLocked = true
+	dbSplice, err = channel.FetchSplice()
+	require.NoError(t, err)
+	require.Equal(t, splice, dbSplice)
+
+	// Completing the splice moves the channel over to the new funding
+	// output.
+	scid := lnwire.ShortChannelID{
+		BlockHeight: 210,
+		TxIndex:     3,
+	}
+	require.NoError(t, channel.CompleteSplice(scid))
+	splice.ShortChannelID = scid
+	splice.LocalLocked = true
+
+	newPoint := splice.FundingOutpoint()
+	require.Equal(t, newPoint, channel.FundingOutpoint)
+	require.Equal(t, ltcutil.Amount(20_000), channel.Capacity)
+	require.Equal(t, scid, channel.ShortChannelID)
+	require.False(t, channel.HasChanStatus(ChanStatusSplicing))
+
+	// The splice can't be completed twice.
+	require.ErrorIs(t, channel.CompleteSplice(scid), ErrNoSplice)
+
+	_, err = cdb.FetchChannel(nil, oldPoint)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+
+	dbChannel, err := cdb.FetchChannel(nil, newPoint)
+	require.NoError(t, err)
+	require.Equal(t, ltcutil.Amount(20_000), dbChannel.Capacity)
+	require.Equal(t, scid, dbChannel.ShortChannelID)
+	require.Equal(t, localCommit, dbChannel.LocalCommitment)
+	require.Equal(t, remoteCommit, dbChannel.RemoteCommitment)
+	require.False(t, dbChannel.HasChanStatus(ChanStatusSplicing))
+
+	// The channel can also be found by its new channel ID, and the splice
+	// record was moved along with it.
+	_, err = cdb.FetchChannelByID(
+		nil, lnwire.NewChanIDFromOutPoint(&newPoint),
+	)
+	require.NoError(t, err)
+
+	dbSplice, err = dbChannel.FetchSplice()
+	require.NoError(t, err)
+	require.Equal(t, splice, dbSplice)
+
+	channels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.Equal(t, newPoint, channels[0].FundingOutpoint)
+}
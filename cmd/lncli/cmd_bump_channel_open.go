@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var bumpChannelOpenCommand = cli.Command{
+	Name:     "bumpchannelopen",
+	Category: "Channels",
+	Usage:    "Bump the fee of the funding tx of a pending channel.",
+	Description: `
+	Replace the funding transaction of a pending dual funded channel that
+	we opened with one paying a higher fee rate. The replacement is
+	constructed with the remote node again, and both nodes keep their
+	contributions to the channel. Once either funding transaction confirms,
+	the channel continues under its funding outpoint.`,
+	ArgsUsage: "--chan_point=<txid:output_index> " +
+		"[--conf_target=N | --sat_per_vbyte=N]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the pending channel whose funding " +
+				"transaction to replace. Takes the form of: " +
+				"txid:output_index",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"replacement transaction should confirm in, " +
+				"will be used for fee estimation",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte that should be used when crafting " +
+				"the replacement transaction",
+		},
+	},
+	Action: actionDecorator(bumpChannelOpen),
+}
+
+func bumpChannelOpen(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "bumpchannelopen")
+		return nil
+	}
+
+	if !ctx.IsSet("chan_point") {
+		return fmt.Errorf("chan_point argument missing")
+	}
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte") {
+		return fmt.Errorf("either conf_target or sat_per_vbyte " +
+			"should be set, but not both")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.String("chan_point"))
+	if err != nil {
+		return fmt.Errorf("unable to parse chan_point: %w", err)
+	}
+
+	req := &lnrpc.BumpChannelOpenRequest{
+		ChannelPoint: chanPoint,
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	}
+
+	resp, err := client.BumpChannelOpen(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -74,6 +74,12 @@ var addInvoiceCommand = cli.Command{
 			Usage: "creates an AMP invoice. If true, preimage " +
 				"should not be set.",
 		},
+		cli.BoolFlag{
+			Name: "blind",
+			Usage: "include blinded paths to the node in the " +
+				"invoice instead of revealing its channels. " +
+				"Can't be combined with --private or --amp.",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -128,6 +134,7 @@ func addInvoice(ctx *cli.Context) error {
 		Expiry:          ctx.Int64("expiry"),
 		Private:         ctx.Bool("private"),
 		IsAmp:           ctx.Bool("amp"),
+		IsBlinded:       ctx.Bool("blind"),
 	}
 
 	resp, err := client.AddInvoice(ctxc, invoice)
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var addOfferCommand = cli.Command{
+	Name:     "addoffer",
+	Category: "Invoices",
+	Usage:    "Create a BOLT 12 offer that can be paid several times.",
+	Description: `
+	Create a BOLT 12 offer. Unlike an invoice, an offer can be paid
+	several times: for each payment, the payer requests a fresh invoice
+	from this node over onion messages. Onion messages must be enabled.
+
+	If no amount is given, the payer chooses the amount, which suits
+	donations and tips.
+	`,
+	ArgsUsage: "description [amt_msat]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "description",
+			Usage: "a description of the purpose of the payment, " +
+				"required if the offer has an amount",
+		},
+		cli.Int64Flag{
+			Name:  "amt_msat",
+			Usage: "the amount per item in millisatoshis",
+		},
+		cli.StringFlag{
+			Name:  "issuer",
+			Usage: "a human-readable description of the issuer",
+		},
+		cli.BoolFlag{
+			Name:  "allow_quantity",
+			Usage: "allow paying for several items at once",
+		},
+		cli.Uint64Flag{
+			Name: "quantity_max",
+			Usage: "the maximum number of items per payment if " +
+				"--allow_quantity is set, zero means no limit",
+		},
+		cli.DurationFlag{
+			Name: "expiry",
+			Usage: "the duration after which the offer expires, " +
+				"zero means that it doesn't expire",
+		},
+	},
+	Action: actionDecorator(addOffer),
+}
+
+func addOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	req := &lnrpc.AddOfferRequest{
+		Issuer:        ctx.String("issuer"),
+		AllowQuantity: ctx.Bool("allow_quantity"),
+		QuantityMax:   ctx.Uint64("quantity_max"),
+	}
+
+	switch {
+	case ctx.IsSet("description"):
+		req.Description = ctx.String("description")
+	case args.Present():
+		req.Description = args.First()
+		args = args.Tail()
+	}
+
+	switch {
+	case ctx.IsSet("amt_msat"):
+		req.AmountMsat = ctx.Int64("amt_msat")
+	case args.Present():
+		amt, err := strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt_msat: %w", err)
+		}
+		req.AmountMsat = amt
+	}
+
+	if expiry := ctx.Duration("expiry"); expiry != 0 {
+		req.AbsoluteExpiry = time.Now().Add(expiry).Unix()
+	}
+
+	resp, err := client.AddOffer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var decodeOfferCommand = cli.Command{
+	Name:      "decodeoffer",
+	Category:  "Invoices",
+	Usage:     "Decode a BOLT 12 offer.",
+	ArgsUsage: "offer",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the encoded offer",
+		},
+	},
+	Action: actionDecorator(decodeOffer),
+}
+
+func decodeOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var offer string
+	switch {
+	case ctx.IsSet("offer"):
+		offer = ctx.String("offer")
+	case ctx.Args().Present():
+		offer = ctx.Args().First()
+	default:
+		return errors.New("offer argument missing")
+	}
+
+	resp, err := client.DecodeOffer(ctxc, &lnrpc.DecodeOfferRequest{
+		Offer: offer,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var payOfferCommand = cli.Command{
+	Name:     "payoffer",
+	Category: "Payments",
+	Usage:    "Pay a BOLT 12 offer.",
+	Description: `
+	Request an invoice for a BOLT 12 offer from its issuer over onion
+	messages and pay it. The amount must be given if the offer has none,
+	and the quantity must be given if the offer allows paying for several
+	items at once.
+	`,
+	ArgsUsage: "offer",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the encoded offer to pay",
+		},
+		cli.Int64Flag{
+			Name: "amt_msat",
+			Usage: "the amount to pay in millisatoshis, required " +
+				"if the offer has no amount",
+		},
+		cli.Uint64Flag{
+			Name:  "quantity",
+			Usage: "the number of items to pay for",
+		},
+		cli.StringFlag{
+			Name:  "note",
+			Usage: "an optional note to the issuer of the offer",
+		},
+		cli.DurationFlag{
+			Name: "invoice_timeout",
+			Usage: "the maximum amount of time to wait for the " +
+				"invoice from the issuer",
+			Value: routerrpc.DefaultOfferInvoiceTimeout,
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis when " +
+				"sending the payment",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the payment's amount used as " +
+				"the maximum fee allowed when sending the " +
+				"payment",
+		},
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "the maximum amount of time we should spend " +
+				"trying to fulfill the payment, failing " +
+				"after the timeout has elapsed",
+			Value: paymentTimeout,
+		},
+		cltvLimitFlag,
+		cli.Uint64Flag{
+			Name: "outgoing_chan_id",
+			Usage: "short channel id of the outgoing channel to " +
+				"use for the first hop of the payment",
+		},
+		inflightUpdatesFlag, jsonFlag, timePrefFlag,
+	},
+	Action: actionDecorator(payOffer),
+}
+
+func payOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := lnrpc.NewLightningClient(conn)
+	routerClient := routerrpc.NewRouterClient(conn)
+
+	invoiceTimeout := ctx.Duration("invoice_timeout")
+	req := &routerrpc.PayOfferRequest{
+		AmtMsat:               ctx.Int64("amt_msat"),
+		Quantity:              ctx.Uint64("quantity"),
+		PayerNote:             ctx.String("note"),
+		InvoiceTimeoutSeconds: int32(invoiceTimeout.Seconds()),
+		CltvLimit:             int32(ctx.Int(cltvLimitFlag.Name)),
+		TimePref:              ctx.Float64(timePrefFlag.Name),
+	}
+
+	switch {
+	case ctx.IsSet("offer"):
+		req.Offer = ctx.String("offer")
+	case ctx.Args().Present():
+		req.Offer = ctx.Args().First()
+	default:
+		return errors.New("offer argument missing")
+	}
+
+	if outChan := ctx.Uint64("outgoing_chan_id"); outChan != 0 {
+		req.OutgoingChanIds = []uint64{outChan}
+	}
+
+	pmtTimeout := ctx.Duration("timeout")
+	if pmtTimeout <= 0 {
+		return errors.New("payment timeout must be greater than zero")
+	}
+	req.TimeoutSeconds = int32(pmtTimeout.Seconds())
+
+	// The fee limit is derived from the amount we expect to pay, which is
+	// the amount of the offer unless the payer chose a different one.
+	amtMsat := req.AmtMsat
+	if amtMsat == 0 {
+		offer, err := client.DecodeOffer(ctxc, &lnrpc.DecodeOfferRequest{
+			Offer: req.Offer,
+		})
+		if err != nil {
+			return err
+		}
+
+		amtMsat = offer.AmountMsat
+		if req.Quantity > 1 {
+			amtMsat *= int64(req.Quantity)
+		}
+	}
+
+	feeLimit, err := retrieveFeeLimit(ctx, (amtMsat+999)/1000)
+	if err != nil {
+		return err
+	}
+	req.FeeLimitMsat = feeLimit * 1000
+
+	// Always print in-flight updates for the table output.
+	printJSON := ctx.Bool(jsonFlag.Name)
+	req.NoInflightUpdates = !ctx.Bool(inflightUpdatesFlag.Name) && printJSON
+
+	stream, err := routerClient.PayOffer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	finalState, err := printLivePayment(ctxc, stream, client, printJSON)
+	if err != nil {
+		return err
+	}
+
+	if finalState.Status != lnrpc.Payment_SUCCEEDED {
+		return errors.New(finalState.Status.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var sendOnionMessageCommand = cli.Command{
+	Name:     "sendonionmsg",
+	Category: "Peers",
+	Usage:    "Send an onion message to a node or over a blinded path.",
+	Description: `
+	Send an onion message that carries a set of application-defined
+	records to a node, or over a blinded path such as the reply path of
+	a received onion message. The first node of the path must be a
+	connected peer.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest",
+			Usage: "the pubkey of the node to deliver the " +
+				"message to",
+		},
+		cli.StringFlag{
+			Name: "blinded_path",
+			Usage: "the JSON encoded blinded path to deliver the " +
+				"message over, as printed by subscribeonionmsg",
+		},
+		cli.StringSliceFlag{
+			Name: "hop",
+			Usage: "the pubkey of a node to relay the message " +
+				"through before the destination, can be " +
+				"specified multiple times",
+		},
+		cli.BoolFlag{
+			Name: "reply_path",
+			Usage: "include a blinded path back to this node, so " +
+				"that the recipient can reply",
+		},
+		cli.StringFlag{
+			Name: "data",
+			Usage: "the records to deliver to the recipient. The " +
+				"required format is: <record_id>=<hex_value>," +
+				"<record_id>=<hex_value>,.. Record ids start " +
+				"from 64.",
+		},
+	},
+	Action: actionDecorator(sendOnionMessage),
+}
+
+func sendOnionMessage(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.SendOnionMessageRequest{
+		IncludeReplyPath: ctx.Bool("reply_path"),
+		CustomRecords:    make(map[uint64][]byte),
+	}
+
+	if ctx.IsSet("dest") {
+		dest, err := hex.DecodeString(ctx.String("dest"))
+		if err != nil {
+			return fmt.Errorf("invalid dest: %w", err)
+		}
+		req.Destination = dest
+	}
+
+	if ctx.IsSet("blinded_path") {
+		req.BlindedPath = &lnrpc.BlindedPath{}
+		err := lnrpc.ProtoJSONUnmarshalOpts.Unmarshal(
+			[]byte(ctx.String("blinded_path")), req.BlindedPath,
+		)
+		if err != nil {
+			return fmt.Errorf("invalid blinded_path: %w", err)
+		}
+	}
+
+	for _, hopStr := range ctx.StringSlice("hop") {
+		hop, err := hex.DecodeString(hopStr)
+		if err != nil {
+			return fmt.Errorf("invalid hop: %w", err)
+		}
+		req.IntermediateHops = append(req.IntermediateHops, hop)
+	}
+
+	data := ctx.String("data")
+	if data != "" {
+		for _, r := range strings.Split(data, ",") {
+			kv := strings.Split(r, "=")
+			if len(kv) != 2 {
+				return errors.New("invalid data format: " +
+					"multiple equal signs in record")
+			}
+
+			recordID, err := strconv.ParseUint(kv[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid data format: %w",
+					err)
+			}
+
+			hexValue, err := hex.DecodeString(kv[1])
+			if err != nil {
+				return fmt.Errorf("invalid data format: %w",
+					err)
+			}
+
+			req.CustomRecords[recordID] = hexValue
+		}
+	}
+
+	resp, err := client.SendOnionMessage(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var subscribeOnionMessagesCommand = cli.Command{
+	Name:     "subscribeonionmsg",
+	Category: "Peers",
+	Usage:    "Subscribe to the onion messages addressed to this node.",
+	Action:   actionDecorator(subscribeOnionMessages),
+}
+
+func subscribeOnionMessages(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeOnionMessages(
+		ctxc, &lnrpc.SubscribeOnionMessagesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
@@ -283,6 +283,14 @@ var openChannelCommand = cli.Command{
 				has no bearing on the channel's operation. Max
 				allowed length is 500 characters`,
 		},
+		cli.BoolFlag{
+			Name: "dual_funded",
+			Usage: "(optional) whether to open a dual funded " +
+				"channel, which allows the remote node to " +
+				"contribute funds as well. The funding " +
+				"transaction can be fee bumped with " +
+				"bumpchannelopen while the channel is pending",
+		},
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -327,6 +335,7 @@ func openChannel(ctx *cli.Context) error {
 		RemoteChanReserveSat:       ctx.Uint64("remote_reserve_sats"),
 		FundMax:                    ctx.Bool("fundmax"),
 		Memo:                       ctx.String("memo"),
+		DualFunded:                 ctx.Bool("dual_funded"),
 	}
 
 	switch {
@@ -817,6 +826,14 @@ var batchOpenChannelCommand = cli.Command{
 				"transaction when storing it to the local " +
 				"wallet after publishing it",
 		},
+		cli.StringSliceFlag{
+			Name: "utxo",
+			Usage: "(optional) a utxo specified as " +
+				"outpoint(tx:idx) which will be used to fund " +
+				"the batch transaction. This flag can be " +
+				"repeatedly used to fund the channels with a " +
+				"selection of utxos",
+		},
 	},
 	Action: actionDecorator(batchOpenChannel),
 }
@@ -854,6 +871,17 @@ func batchOpenChannel(ctx *cli.Context) error {
 		Label:            ctx.String("label"),
 	}
 
+	if ctx.IsSet("utxo") {
+		utxos := ctx.StringSlice("utxo")
+
+		outpoints, err := UtxosToOutpoints(utxos)
+		if err != nil {
+			return fmt.Errorf("unable to decode utxos: %w", err)
+		}
+
+		req.Outpoints = outpoints
+	}
+
 	// Let's try and parse the JSON part of the CLI now. Fortunately we can
 	// parse it directly into the RPC struct if we use the correct
 	// marshaler that keeps the original snake case.
@@ -1273,6 +1273,14 @@ func listPayments(ctx *cli.Context) error {
 	return nil
 }
 
+// forwardingOutcomes maps the outcomes accepted by the fwdinghistory command to
+// their RPC counterparts.
+var forwardingOutcomes = map[string]lnrpc.ForwardingOutcome{
+	"settled":  lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_SETTLED,
+	"failed":   lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_FAILED,
+	"rejected": lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_REJECTED,
+}
+
 var forwardingHistoryCommand = cli.Command{
 	Name:      "fwdinghistory",
 	Category:  "Payments",
@@ -1295,6 +1303,12 @@ var forwardingHistoryCommand = cli.Command{
 	Finally, callers can skip a series of events using the --index_offset
 	parameter. Each response will contain the offset index of the last
 	entry. Using this callers can manually paginate within a time slice.
+
+	By default only settled forwards are returned. Failed forwards and
+	forwards that were rejected by our node can be included with the
+	--outcome flag. The events can further be filtered by channel and peer
+	using the --incoming_chan_id, --outgoing_chan_id and --peer flags. All
+	of these flags can be specified multiple times.
 	`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -1320,6 +1334,29 @@ var forwardingHistoryCommand = cli.Command{
 			Usage: "skip the peer alias lookup per forwarding " +
 				"event in order to improve performance",
 		},
+		cli.StringSliceFlag{
+			Name: "incoming_chan_id",
+			Usage: "only return events with the given incoming " +
+				"channel id; can be specified multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: "outgoing_chan_id",
+			Usage: "only return events with the given outgoing " +
+				"channel id; can be specified multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: "peer",
+			Usage: "only return events with the given peer on " +
+				"either side, expressed as hex encoded public " +
+				"key; can be specified multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: "outcome",
+			Usage: "only return events with the given outcome, " +
+				"one of 'settled', 'failed' or 'rejected'; " +
+				"can be specified multiple times, defaults " +
+				"to 'settled'",
+		},
 	},
 	Action: actionDecorator(forwardingHistory),
 }
@@ -1400,6 +1437,41 @@ func forwardingHistory(ctx *cli.Context) error {
 		NumMaxEvents:    maxEvents,
 		PeerAliasLookup: lookupPeerAlias,
 	}
+
+	for _, chanID := range ctx.StringSlice("incoming_chan_id") {
+		id, err := strconv.ParseUint(chanID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode incoming_chan_id: "+
+				"%v", err)
+		}
+		req.IncomingChanIds = append(req.IncomingChanIds, id)
+	}
+
+	for _, chanID := range ctx.StringSlice("outgoing_chan_id") {
+		id, err := strconv.ParseUint(chanID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode outgoing_chan_id: "+
+				"%v", err)
+		}
+		req.OutgoingChanIds = append(req.OutgoingChanIds, id)
+	}
+
+	for _, peer := range ctx.StringSlice("peer") {
+		pubKey, err := route.NewVertexFromStr(peer)
+		if err != nil {
+			return fmt.Errorf("unable to decode peer: %v", err)
+		}
+		req.Peers = append(req.Peers, pubKey[:])
+	}
+
+	for _, outcome := range ctx.StringSlice("outcome") {
+		fwdOutcome, ok := forwardingOutcomes[outcome]
+		if !ok {
+			return fmt.Errorf("unknown outcome: %v", outcome)
+		}
+		req.Outcomes = append(req.Outcomes, fwdOutcome)
+	}
+
 	resp, err := client.ForwardingHistory(ctxc, req)
 	if err != nil {
 		return err
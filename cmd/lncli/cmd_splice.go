@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+// spliceFlags are the flags shared by the splice commands.
+var spliceFlags = []cli.Flag{
+	cli.StringFlag{
+		Name: "chan_point",
+		Usage: "the channel to splice. Takes the form of: " +
+			"txid:output_index",
+	},
+	cli.Int64Flag{
+		Name:  "amt",
+		Usage: "the amount of funds to splice, in satoshis",
+	},
+	cli.Int64Flag{
+		Name: "conf_target",
+		Usage: "(optional) the number of blocks that the splice " +
+			"transaction should confirm in, will be used for " +
+			"fee estimation",
+	},
+	cli.Uint64Flag{
+		Name: "sat_per_vbyte",
+		Usage: "(optional) a manual fee expressed in sat/vbyte that " +
+			"should be used when crafting the splice transaction",
+	},
+}
+
+var spliceInCommand = cli.Command{
+	Name:     "splicein",
+	Category: "Channels",
+	Usage:    "Add funds from the wallet to an existing channel.",
+	Description: `
+	Splice funds of the wallet into an active channel, without closing it.
+
+	The channel is offline while the splice is pending: no HTLCs can be
+	sent, received or forwarded over it, as commitments aren't signed for
+	both the old and the new funding output. Once the splice transaction
+	has confirmed and both peers locked the splice, the channel continues
+	under the funding outpoint of the splice transaction.`,
+	ArgsUsage: "--chan_point=<txid:output_index> --amt=<sats>",
+	Flags:     spliceFlags,
+	Action:    actionDecorator(spliceIn),
+}
+
+func spliceIn(ctx *cli.Context) error {
+	if ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "splicein")
+		return nil
+	}
+
+	req, err := parseSpliceRequest(ctx)
+	if err != nil {
+		return err
+	}
+	req.SpliceInSat = ctx.Int64("amt")
+
+	return spliceChannel(ctx, req)
+}
+
+var spliceOutCommand = cli.Command{
+	Name:     "spliceout",
+	Category: "Channels",
+	Usage:    "Remove funds from an existing channel.",
+	Description: `
+	Splice funds out of our balance of an active channel, without closing
+	it. The funds are sent to the given address, or to a new address of the
+	wallet if none is given.
+
+	The channel is offline while the splice is pending: no HTLCs can be
+	sent, received or forwarded over it, as commitments aren't signed for
+	both the old and the new funding output. Once the splice transaction
+	has confirmed and both peers locked the splice, the channel continues
+	under the funding outpoint of the splice transaction.`,
+	ArgsUsage: "--chan_point=<txid:output_index> --amt=<sats> [--addr=<addr>]",
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name: "addr",
+			Usage: "(optional) the address to send the spliced " +
+				"out funds to",
+		},
+	}, spliceFlags...),
+	Action: actionDecorator(spliceOut),
+}
+
+func spliceOut(ctx *cli.Context) error {
+	if ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "spliceout")
+		return nil
+	}
+
+	req, err := parseSpliceRequest(ctx)
+	if err != nil {
+		return err
+	}
+	req.SpliceOutSat = ctx.Int64("amt")
+	req.SpliceOutAddr = ctx.String("addr")
+
+	return spliceChannel(ctx, req)
+}
+
+// parseSpliceRequest parses the flags shared by the splice commands.
+func parseSpliceRequest(ctx *cli.Context) (*lnrpc.SpliceChannelRequest,
+	error) {
+
+	if !ctx.IsSet("chan_point") {
+		return nil, fmt.Errorf("chan_point argument missing")
+	}
+	if !ctx.IsSet("amt") {
+		return nil, fmt.Errorf("amt argument missing")
+	}
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte") {
+		return nil, fmt.Errorf("either conf_target or sat_per_vbyte " +
+			"should be set, but not both")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.String("chan_point"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse chan_point: %w", err)
+	}
+
+	return &lnrpc.SpliceChannelRequest{
+		ChannelPoint: chanPoint,
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	}, nil
+}
+
+func spliceChannel(ctx *cli.Context, req *lnrpc.SpliceChannelRequest) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SpliceChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
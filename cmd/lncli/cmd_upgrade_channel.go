@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var upgradeChannelCommand = cli.Command{
+	Name:     "upgradechannel",
+	Category: "Channels",
+	Usage:    "Upgrade the commitment type of an active channel.",
+	Description: `
+	Upgrade the commitment type of an active channel in place, without
+	closing it. The channel is quiesced, and both nodes sign new
+	commitments of the upgraded type. The channel must not carry any
+	HTLCs, and the remote node must support dynamic commitments.
+
+	Legacy channels can be upgraded to static_remote_key or anchors, and
+	static_remote_key channels to anchors.`,
+	ArgsUsage: "--chan_point=<txid:output_index> " +
+		"--commitment_type=<static_remote_key|anchors>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel to upgrade. Takes the form of: " +
+				"txid:output_index",
+		},
+		cli.StringFlag{
+			Name: "commitment_type",
+			Usage: "the commitment type to upgrade the channel " +
+				"to, either 'static_remote_key' or 'anchors'",
+		},
+	},
+	Action: actionDecorator(upgradeChannel),
+}
+
+func upgradeChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "upgradechannel")
+		return nil
+	}
+
+	if !ctx.IsSet("chan_point") {
+		return fmt.Errorf("chan_point argument missing")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.String("chan_point"))
+	if err != nil {
+		return fmt.Errorf("unable to parse chan_point: %w", err)
+	}
+
+	var commitType lnrpc.CommitmentType
+	switch ctx.String("commitment_type") {
+	case "static_remote_key":
+		commitType = lnrpc.CommitmentType_STATIC_REMOTE_KEY
+
+	case "anchors":
+		commitType = lnrpc.CommitmentType_ANCHORS
+
+	default:
+		return fmt.Errorf("unsupported commitment type %q",
+			ctx.String("commitment_type"))
+	}
+
+	req := &lnrpc.UpgradeChannelRequest{
+		ChannelPoint:   chanPoint,
+		CommitmentType: commitType,
+	}
+
+	resp, err := client.UpgradeChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
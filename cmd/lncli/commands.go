@@ -1361,6 +1361,15 @@ var walletBalanceCommand = cli.Command{
 			Value: "",
 		},
 	},
+	Description: `
+	Display the wallet's total, confirmed and unconfirmed balance.
+
+	The balance is also split by extension: canonical_balance is held in
+	regular outputs and can be used to open channels, while
+	mweb_confirmed_balance and mweb_unconfirmed_balance are held in the
+	MWEB extension block and need to be pegged out before funding a
+	channel.
+`,
 	Action: actionDecorator(walletBalance),
 }
 
@@ -1884,6 +1893,11 @@ var listChainTxnsCommand = cli.Command{
 			Usage: "(optional) only include transactions " +
 				"relevant to this account",
 		},
+		cli.BoolFlag{
+			Name: "mweb_only",
+			Usage: "(optional) only include transactions that " +
+				"touch the MWEB extension block",
+		},
 	},
 	Description: `
 	List all transactions an address of the wallet was involved in.
@@ -1902,6 +1916,11 @@ var listChainTxnsCommand = cli.Command{
 	(e.g. '--account=default'). When set, amounts reflect only the
 	credits and debits belonging to that account. If not set, all
 	wallet transactions are returned.
+
+	Transactions touching the MWEB extension block are marked with their
+	mweb_type (peg-in, peg-out or internal), the IDs of their MWEB kernels
+	and the fee paid by them. Use '--mweb_only' to only list those. The
+	stealth addresses of MWEB outputs are not shown.
 `,
 	Action: actionDecorator(listChainTxns),
 }
@@ -1927,6 +1946,16 @@ func listChainTxns(ctx *cli.Context) error {
 		return err
 	}
 
+	if ctx.Bool("mweb_only") {
+		var mwebTxns []*lnrpc.Transaction
+		for _, tx := range resp.Transactions {
+			if tx.MwebType != lnrpc.MwebTxType_MWEB_TX_NONE {
+				mwebTxns = append(mwebTxns, tx)
+			}
+		}
+		resp.Transactions = mwebTxns
+	}
+
 	printRespJSON(resp)
 	return nil
 }
@@ -2142,6 +2171,23 @@ var updateChannelPolicyCommand = cli.Command{
 				"applied to all forwarded HTLCs. If unset, " +
 				"the max HTLC is left unchanged",
 		},
+		cli.Int64Flag{
+			Name: "inbound_base_fee_msat",
+			Usage: "the base inbound fee in milli-satoshis that " +
+				"will be charged for each HTLC received over " +
+				"the channel, in addition to the fee of the " +
+				"outgoing channel. Only discounts expressed " +
+				"by negative values are allowed",
+		},
+		cli.Int64Flag{
+			Name: "inbound_fee_rate_ppm",
+			Usage: "the inbound fee rate ppm (parts per million) " +
+				"that will be charged proportionally based " +
+				"on the value of each HTLC received over the " +
+				"channel, in addition to the fee of the " +
+				"outgoing channel. Only discounts expressed " +
+				"by negative values are allowed",
+		},
 		cli.StringFlag{
 			Name: "chan_point",
 			Usage: "the channel which this policy update should " +
@@ -2276,10 +2322,24 @@ func updateChannelPolicy(ctx *cli.Context) error {
 		}
 	}
 
+	inboundBaseFee := ctx.Int64("inbound_base_fee_msat")
+	if inboundBaseFee < math.MinInt32 || inboundBaseFee > math.MaxInt32 {
+		return errors.New("inbound_base_fee_msat out of range")
+	}
+
+	inboundFeeRatePpm := ctx.Int64("inbound_fee_rate_ppm")
+	if inboundFeeRatePpm < math.MinInt32 ||
+		inboundFeeRatePpm > math.MaxInt32 {
+
+		return errors.New("inbound_fee_rate_ppm out of range")
+	}
+
 	req := &lnrpc.PolicyUpdateRequest{
-		BaseFeeMsat:   baseFee,
-		TimeLockDelta: uint32(timeLockDelta),
-		MaxHtlcMsat:   ctx.Uint64("max_htlc_msat"),
+		BaseFeeMsat:        baseFee,
+		TimeLockDelta:      uint32(timeLockDelta),
+		MaxHtlcMsat:        ctx.Uint64("max_htlc_msat"),
+		InboundBaseFeeMsat: int32(inboundBaseFee),
+		InboundFeeRatePpm:  int32(inboundFeeRatePpm),
 	}
 
 	if ctx.IsSet("min_htlc_msat") {
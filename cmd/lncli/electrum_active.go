@@ -0,0 +1,97 @@
+//go:build electrumrpc
+// +build electrumrpc
+
+package main
+
+import (
+	"github.com/ltcsuite/lnd/lnrpc/electrumrpc"
+	"github.com/urfave/cli"
+)
+
+func getElectrumKitClient(ctx *cli.Context) (electrumrpc.ElectrumKitClient, func()) {
+	conn := getClientConn(ctx, false)
+
+	cleanUp := func() {
+		conn.Close()
+	}
+
+	return electrumrpc.NewElectrumKitClient(conn), cleanUp
+}
+
+var getElectrumStatusCommand = cli.Command{
+	Name:     "status",
+	Usage:    "Returns the status of the Electrum backend.",
+	Category: "Electrum",
+	Description: "Returns the status of the Electrum chain backend: the " +
+		"connected servers along with their negotiated protocol, " +
+		"latency and tip height, the number of subscribed " +
+		"scripthashes, the header cache size, whether a REST API is " +
+		"available, the MWEB sync progress and the connected MWEB " +
+		"P2P peers.",
+	Action: actionDecorator(getElectrumStatus),
+}
+
+func getElectrumStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getElectrumKitClient(ctx)
+	defer cleanUp()
+
+	req := &electrumrpc.StatusRequest{}
+
+	resp, err := client.Status(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var subscribeElectrumEventsCommand = cli.Command{
+	Name:     "subscribe",
+	Usage:    "Subscribe to Electrum server connection events.",
+	Category: "Electrum",
+	Description: "Prints an event every time one of the Electrum servers " +
+		"is connected or disconnected, until interrupted.",
+	Action: actionDecorator(subscribeElectrumEvents),
+}
+
+func subscribeElectrumEvents(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getElectrumKitClient(ctx)
+	defer cleanUp()
+
+	req := &electrumrpc.SubscribeConnectionEventsRequest{}
+
+	stream, err := client.SubscribeConnectionEvents(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(event)
+	}
+}
+
+// electrumCommands will return the set of commands to enable for electrumrpc
+// builds.
+func electrumCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:        "electrum",
+			Category:    "Electrum",
+			Usage:       "Interact with the Electrum backend.",
+			Description: "",
+			Subcommands: []cli.Command{
+				getElectrumStatusCommand,
+				subscribeElectrumEventsCommand,
+			},
+		},
+	}
+}
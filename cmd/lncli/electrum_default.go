@@ -0,0 +1,11 @@
+//go:build !electrumrpc
+// +build !electrumrpc
+
+package main
+
+import "github.com/urfave/cli"
+
+// electrumCommands will return nil for non-electrumrpc builds.
+func electrumCommands() []cli.Command {
+	return nil
+}
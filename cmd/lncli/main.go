@@ -464,6 +464,10 @@ func main() {
 		closeChannelCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
+		spliceInCommand,
+		spliceOutCommand,
+		upgradeChannelCommand,
+		bumpChannelOpenCommand,
 		listPeersCommand,
 		walletBalanceCommand,
 		channelBalanceCommand,
@@ -510,12 +514,18 @@ func main() {
 		deletePaymentsCommand,
 		sendCustomCommand,
 		subscribeCustomCommand,
+		sendOnionMessageCommand,
+		subscribeOnionMessagesCommand,
+		addOfferCommand,
+		decodeOfferCommand,
+		payOfferCommand,
 		fishCompletionCommand,
 		listAliasesCommand,
 	}
 
 	// Add any extra commands determined by build flags.
 	app.Commands = append(app.Commands, autopilotCommands()...)
+	app.Commands = append(app.Commands, electrumCommands()...)
 	app.Commands = append(app.Commands, invoicesCommands()...)
 	app.Commands = append(app.Commands, neutrinoCommands()...)
 	app.Commands = append(app.Commands, routerCommands()...)
@@ -82,6 +82,8 @@ func walletCommands() []cli.Command {
 				accountsCommand,
 				requiredReserveCommand,
 				addressesCommand,
+				pegInCommand,
+				pegOutCommand,
 			},
 		},
 	}
@@ -188,7 +190,9 @@ var bumpFeeCommand = cli.Command{
 	fee transaction that is under the control of the wallet.
 
 	A fee preference must be provided, either through the conf_target or
-	sat_per_vbyte parameters.
+	sat_per_vbyte parameters, unless a budget is given. With a budget, the
+	fee rate is raised every block until the budget is spent at the
+	deadline_height, and the fee preference only sets the starting fee rate.
 
 	Note that this command currently doesn't perform any validation checks
 	on the fee preference being provided. For now, the responsibility of
@@ -220,6 +224,16 @@ var bumpFeeCommand = cli.Command{
 			Name:  "force",
 			Usage: "sweep even if the yield is negative",
 		},
+		cli.Uint64Flag{
+			Name: "budget",
+			Usage: "the maximum amount of fees in satoshis that " +
+				"may be spent to sweep the output",
+		},
+		cli.Uint64Flag{
+			Name: "deadline_height",
+			Usage: "the block height by which the output should " +
+				"be swept, used together with budget",
+		},
 	},
 	Action: actionDecorator(bumpFee),
 }
@@ -252,10 +266,12 @@ func bumpFee(ctx *cli.Context) error {
 	defer cleanUp()
 
 	resp, err := client.BumpFee(ctxc, &walletrpc.BumpFeeRequest{
-		Outpoint:    protoOutPoint,
-		TargetConf:  uint32(ctx.Uint64("conf_target")),
-		SatPerVbyte: ctx.Uint64(feeRateFlag),
-		Force:       ctx.Bool("force"),
+		Outpoint:       protoOutPoint,
+		TargetConf:     uint32(ctx.Uint64("conf_target")),
+		SatPerVbyte:    ctx.Uint64(feeRateFlag),
+		Force:          ctx.Bool("force"),
+		Budget:         ctx.Uint64("budget"),
+		DeadlineHeight: uint32(ctx.Uint64("deadline_height")),
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,204 @@
+//go:build walletrpc
+// +build walletrpc
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lnrpc/walletrpc"
+	"github.com/urfave/cli"
+)
+
+// pegFlags are the flags shared by the pegin and pegout commands.
+var pegFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name: "utxo",
+		Usage: "a utxo specified as outpoint(tx:idx) which will be " +
+			"pegged. This flag can be repeatedly used to " +
+			"specify multiple utxos. If not set, all eligible " +
+			"utxos of the default account are pegged",
+	},
+	cli.Uint64Flag{
+		Name: "conf_target",
+		Usage: "the number of blocks that the transaction should be " +
+			"confirmed on-chain within",
+		Value: 6,
+	},
+	cli.Uint64Flag{
+		Name: "sat_per_vbyte",
+		Usage: "a manual fee expressed in sat/vbyte that should be " +
+			"used when creating the transaction",
+	},
+	cli.StringFlag{
+		Name: "account",
+		Usage: "(optional) the name of the account to derive the " +
+			"destination address from",
+	},
+	cli.Uint64Flag{
+		Name: "min_confs",
+		Usage: "(optional) the minimum number of confirmations " +
+			"each pegged utxo must satisfy",
+		Value: defaultUtxoMinConf,
+	},
+	cli.BoolFlag{
+		Name: "dry_run",
+		Usage: "only craft the transaction and report its fees, " +
+			"without publishing it; the returned raw_tx is " +
+			"still signed and valid",
+	},
+	txLabelFlag,
+}
+
+var pegInCommand = cli.Command{
+	Name:  "pegin",
+	Usage: "Move canonical coins into the MWEB extension block.",
+	ArgsUsage: "[--utxo=U...] [--conf_target=C | --sat_per_vbyte=S] " +
+		"[--account=A] [--dry_run]",
+	Description: `
+	The pegin command moves the full value of the selected canonical coins,
+	minus fees, to a fresh MWEB address of the given account.
+
+	The fee is reported split into the part paid on the canonical chain
+	and the part paid by the MWEB kernel. Use '--dry_run' to see the fees
+	without publishing the transaction.
+	`,
+	Flags:  pegFlags,
+	Action: actionDecorator(pegIn),
+}
+
+func pegIn(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	outpoints, targetConf, satPerVbyte, err := parsePegFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	minConfs := int32(ctx.Uint64("min_confs"))
+	req := &walletrpc.PegInRequest{
+		Outpoints:        outpoints,
+		Account:          ctx.String("account"),
+		MinConfs:         minConfs,
+		SpendUnconfirmed: minConfs == 0,
+		DryRun:           ctx.Bool("dry_run"),
+		Label:            ctx.String(txLabelFlag.Name),
+	}
+	if satPerVbyte != 0 {
+		req.Fees = &walletrpc.PegInRequest_SatPerVbyte{
+			SatPerVbyte: satPerVbyte,
+		}
+	} else {
+		req.Fees = &walletrpc.PegInRequest_TargetConf{
+			TargetConf: targetConf,
+		}
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := walletClient.PegIn(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var pegOutCommand = cli.Command{
+	Name:  "pegout",
+	Usage: "Move MWEB coins out of the extension block.",
+	ArgsUsage: "[--utxo=U...] [--conf_target=C | --sat_per_vbyte=S] " +
+		"[--account=A] [--addr_type=T] [--dry_run]",
+	Description: `
+	The pegout command moves the full value of the selected MWEB coins,
+	minus fees, to a fresh canonical address of the given account. Pegged
+	out coins can only be spent once they have matured.
+
+	The fee is reported split into the part paid on the canonical chain
+	and the part paid by the MWEB kernel. Use '--dry_run' to see the fees
+	without publishing the transaction.
+	`,
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name: "addr_type",
+			Usage: "(optional) the type of the destination " +
+				"address, either p2wkh or p2tr",
+			Value: "p2wkh",
+		},
+	}, pegFlags...),
+	Action: actionDecorator(pegOut),
+}
+
+func pegOut(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	outpoints, targetConf, satPerVbyte, err := parsePegFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	addrType, err := parseAddrType(ctx.String("addr_type"))
+	if err != nil {
+		return err
+	}
+
+	minConfs := int32(ctx.Uint64("min_confs"))
+	req := &walletrpc.PegOutRequest{
+		Outpoints:        outpoints,
+		Account:          ctx.String("account"),
+		MinConfs:         minConfs,
+		SpendUnconfirmed: minConfs == 0,
+		DryRun:           ctx.Bool("dry_run"),
+		Label:            ctx.String(txLabelFlag.Name),
+		AddressType:      addrType,
+	}
+	if satPerVbyte != 0 {
+		req.Fees = &walletrpc.PegOutRequest_SatPerVbyte{
+			SatPerVbyte: satPerVbyte,
+		}
+	} else {
+		req.Fees = &walletrpc.PegOutRequest_TargetConf{
+			TargetConf: targetConf,
+		}
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := walletClient.PegOut(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+// parsePegFlags parses the coin selection and fee flags shared by the pegin
+// and pegout commands.
+func parsePegFlags(ctx *cli.Context) ([]*lnrpc.OutPoint, uint32, uint64,
+	error) {
+
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte") {
+		return nil, 0, 0, fmt.Errorf("either conf_target or " +
+			"sat_per_vbyte should be set, but not both")
+	}
+
+	var outpoints []*lnrpc.OutPoint
+	if ctx.IsSet("utxo") {
+		var err error
+		outpoints, err = UtxosToOutpoints(ctx.StringSlice("utxo"))
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("unable to decode "+
+				"utxos: %w", err)
+		}
+	}
+
+	return outpoints, uint32(ctx.Uint64("conf_target")),
+		ctx.Uint64("sat_per_vbyte"), nil
+}
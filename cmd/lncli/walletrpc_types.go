@@ -14,6 +14,8 @@ type PendingSweep struct {
 	RequestedSatPerVByte uint32   `json:"requested_sat_per_vbyte"`
 	RequestedConfTarget  uint32   `json:"requested_conf_target"`
 	Force                bool     `json:"force"`
+	Budget               uint64   `json:"budget"`
+	DeadlineHeight       uint32   `json:"deadline_height"`
 }
 
 // NewPendingSweepFromProto converts the walletrpc.PendingSweep proto type into
@@ -29,5 +31,7 @@ func NewPendingSweepFromProto(pendingSweep *walletrpc.PendingSweep) *PendingSwee
 		RequestedSatPerVByte: uint32(pendingSweep.RequestedSatPerVbyte),
 		RequestedConfTarget:  pendingSweep.RequestedConfTarget,
 		Force:                pendingSweep.Force,
+		Budget:               pendingSweep.Budget,
+		DeadlineHeight:       pendingSweep.DeadlineHeight,
 	}
 }
@@ -287,6 +287,11 @@ var policyCommand = cli.Command{
 			Name:  "anchor",
 			Usage: "Retrieve the anchor tower client's current policy.",
 		},
+		cli.BoolFlag{
+			Name: "taproot",
+			Usage: "Retrieve the taproot tower client's current " +
+				"policy.",
+		},
 	},
 }
 
@@ -303,6 +308,8 @@ func policy(ctx *cli.Context) error {
 	switch {
 	case ctx.Bool("anchor"):
 		policyType = wtclientrpc.PolicyType_ANCHOR
+	case ctx.Bool("taproot"):
+		policyType = wtclientrpc.PolicyType_TAPROOT
 	case ctx.Bool("legacy"):
 		policyType = wtclientrpc.PolicyType_LEGACY
 
@@ -24,6 +24,7 @@ import (
 	"github.com/ltcsuite/lnd/chainreg"
 	"github.com/ltcsuite/lnd/chanbackup"
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/contractcourt"
 	"github.com/ltcsuite/lnd/discovery"
 	"github.com/ltcsuite/lnd/funding"
 	"github.com/ltcsuite/lnd/htlcswitch"
@@ -36,6 +37,7 @@ import (
 	"github.com/ltcsuite/lnd/lnrpc/signrpc"
 	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/onionmessage"
 	"github.com/ltcsuite/lnd/routing"
 	"github.com/ltcsuite/lnd/signal"
 	"github.com/ltcsuite/lnd/sweep"
@@ -167,6 +169,28 @@ const (
 	defaultRSBackoff  = time.Second * 30
 	defaultRSAttempts = 1
 
+	// Set defaults for the health checks of the Electrum backend, which
+	// are only run when it is in use. The chain tip may lag the local
+	// clock by 24 block intervals, an hour on mainnet, before the Electrum
+	// servers are considered stuck.
+	defaultElectrumTipMaxBlocks = 24
+	defaultElectrumTipInterval  = time.Minute * 5
+	defaultElectrumTipTimeout   = time.Second * 5
+	defaultElectrumTipBackoff   = time.Minute * 5
+	defaultElectrumTipAttempts  = 3
+
+	defaultElectrumMaxLatency      = time.Second * 5
+	defaultElectrumLatencyInterval = time.Minute
+	defaultElectrumLatencyTimeout  = time.Second * 5
+	defaultElectrumLatencyBackoff  = time.Minute
+	defaultElectrumLatencyAttempts = 3
+
+	defaultMwebMinPeers      = 1
+	defaultMwebPeersInterval = time.Minute
+	defaultMwebPeersTimeout  = time.Second * 5
+	defaultMwebPeersBackoff  = time.Minute * 2
+	defaultMwebPeersAttempts = 3
+
 	// defaultRemoteMaxHtlcs specifies the default limit for maximum
 	// concurrent HTLCs the remote party may add to commitment transactions.
 	// This value can be overridden with --default-remote-max-htlcs.
@@ -465,6 +489,8 @@ type Config struct {
 
 	Prometheus lncfg.Prometheus `group:"prometheus" namespace:"prometheus"`
 
+	Tracing *lncfg.Tracing `group:"tracing" namespace:"tracing"`
+
 	WtClient *lncfg.WtClient `group:"wtclient" namespace:"wtclient"`
 
 	Watchtower *lncfg.Watchtower `group:"watchtower" namespace:"watchtower"`
@@ -485,6 +511,10 @@ type Config struct {
 
 	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
 
+	MwebFunding *lncfg.MwebFunding `group:"mwebfunding" namespace:"mwebfunding"`
+
+	OnionMessages *lncfg.OnionMessages `group:"onionmsg" namespace:"onionmsg"`
+
 	Htlcswitch *lncfg.Htlcswitch `group:"htlcswitch" namespace:"htlcswitch"`
 
 	GRPC *GRPCConfig `group:"grpc" namespace:"grpc"`
@@ -640,6 +670,7 @@ func DefaultConfig() Config {
 			ChannelCacheSize: channeldb.DefaultChannelCacheSize,
 		},
 		Prometheus: lncfg.DefaultPrometheus(),
+		Tracing:    lncfg.DefaultTracing(),
 		Watchtower: lncfg.DefaultWatchtowerCfg(defaultTowerDir),
 		HealthChecks: &lncfg.HealthCheckConfig{
 			ChainCheck: &lncfg.CheckConfig{
@@ -675,6 +706,33 @@ func DefaultConfig() Config {
 				Attempts: defaultRSAttempts,
 				Backoff:  defaultRSBackoff,
 			},
+			ElectrumTip: &lncfg.ElectrumTipCheckConfig{
+				MaxBlocks: defaultElectrumTipMaxBlocks,
+				CheckConfig: &lncfg.CheckConfig{
+					Interval: defaultElectrumTipInterval,
+					Timeout:  defaultElectrumTipTimeout,
+					Attempts: defaultElectrumTipAttempts,
+					Backoff:  defaultElectrumTipBackoff,
+				},
+			},
+			ElectrumLatency: &lncfg.ElectrumLatencyCheckConfig{
+				MaxLatency: defaultElectrumMaxLatency,
+				CheckConfig: &lncfg.CheckConfig{
+					Interval: defaultElectrumLatencyInterval,
+					Timeout:  defaultElectrumLatencyTimeout,
+					Attempts: defaultElectrumLatencyAttempts,
+					Backoff:  defaultElectrumLatencyBackoff,
+				},
+			},
+			MwebPeers: &lncfg.MwebPeersCheckConfig{
+				MinPeers: defaultMwebMinPeers,
+				CheckConfig: &lncfg.CheckConfig{
+					Interval: defaultMwebPeersInterval,
+					Timeout:  defaultMwebPeersTimeout,
+					Attempts: defaultMwebPeersAttempts,
+					Backoff:  defaultMwebPeersBackoff,
+				},
+			},
 		},
 		Gossip: &lncfg.Gossip{
 			MaxChannelUpdateBurst: discovery.DefaultMaxChannelUpdateBurst,
@@ -704,6 +762,14 @@ func DefaultConfig() Config {
 		},
 		Sweeper: &lncfg.Sweeper{
 			BatchWindowDuration: sweep.DefaultBatchWindowDuration,
+			Budget:              contractcourt.DefaultBudgetConfig(),
+		},
+		MwebFunding: &lncfg.MwebFunding{
+			PegOutConfTarget: lncfg.DefaultMwebPegOutConfTarget,
+		},
+		OnionMessages: &lncfg.OnionMessages{
+			PeerRateLimit: onionmessage.DefaultPeerRateLimit,
+			PeerBurst:     onionmessage.DefaultPeerBurst,
 		},
 		Htlcswitch: &lncfg.Htlcswitch{
 			MailboxDeliveryTimeout: htlcswitch.DefaultMailboxDeliveryTimeout,
@@ -1240,18 +1306,19 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 
 		case electrumBackendName:
 			// Validate that an Electrum server address was provided.
-			if cfg.ElectrumMode.Server == "" {
+			if len(cfg.ElectrumMode.Servers) == 0 {
 				return nil, mkErr("electrum.server must be set when " +
 					"using electrum mode")
 			}
 
-			// Validate that a REST URL is provided. This is required for
-			// proper channel operations (funding tx validation, channel
-			// close detection, etc.).
-			if cfg.ElectrumMode.RESTURL == "" {
-				return nil, mkErr("electrum.resturl must be set when " +
-					"using electrum mode (e.g., " +
-					"http://localhost:3002 for mempool/electrs)")
+			// A quorum can't be larger than the number of servers
+			// that are asked.
+			quorum := cfg.ElectrumMode.Quorum
+			if quorum < 0 || quorum > len(cfg.ElectrumMode.Servers) {
+				return nil, mkErr(fmt.Sprintf("electrum.quorum "+
+					"must be between 0 and the number of "+
+					"electrum.server entries (%d), got %d",
+					len(cfg.ElectrumMode.Servers), quorum))
 			}
 
 		case "nochainbackend":
@@ -1677,7 +1744,10 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.RPCMiddleware,
 		cfg.RemoteSigner,
 		cfg.Sweeper,
+		cfg.MwebFunding,
+		cfg.OnionMessages,
 		cfg.Htlcswitch,
+		cfg.Tracing,
 	)
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package lnd
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -32,6 +33,7 @@ import (
 	"github.com/ltcsuite/lnd/macaroons"
 	"github.com/ltcsuite/lnd/rpcperms"
 	"github.com/ltcsuite/lnd/signal"
+	"github.com/ltcsuite/lnd/sqldb"
 	"github.com/ltcsuite/lnd/walletunlocker"
 	"github.com/ltcsuite/lnd/watchtower"
 	"github.com/ltcsuite/lnd/watchtower/wtclient"
@@ -853,6 +855,10 @@ type DatabaseInstances struct {
 	// InvoiceDB is the database that stores information about invoices.
 	InvoiceDB invoices.InvoiceDB
 
+	// PaymentDB is the database that stores information about the payments
+	// we send.
+	PaymentDB channeldb.PaymentStore
+
 	// MacaroonDB is the database that stores macaroon root keys.
 	MacaroonDB kvdb.Backend
 
@@ -980,6 +986,25 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		)
 	}
 
+	// The channel graph is kept in its native SQL tables if configured to
+	// do so. Any existing graph is copied over from the key-value store
+	// when the graph DB is opened for the first time with native SQL.
+	nativeSQLStore := databaseBackends.NativeSQLStore
+	if nativeSQLStore != nil {
+		createQuery := func(tx *sql.Tx) channeldb.SQLGraphQueries {
+			return nativeSQLStore.WithTx(tx)
+		}
+		executor := sqldb.NewTransactionExecutor(
+			nativeSQLStore, createQuery,
+		)
+
+		dbOptions = append(
+			dbOptions, channeldb.OptionSetSQLGraphStore(
+				channeldb.NewSQLGraphStore(executor),
+			),
+		)
+	}
+
 	// Otherwise, we'll open two instances, one for the state we only need
 	// locally, and the other for things we want to ensure are replicated.
 	dbs.GraphDB, err = channeldb.CreateWithBackend(
@@ -1020,6 +1045,36 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	// interface.
 	dbs.InvoiceDB = dbs.GraphDB
 
+	// Payments are kept in their native SQL tables if configured to do so,
+	// otherwise they live in the channel state DB.
+	dbs.PaymentDB = channeldb.NewPaymentControl(dbs.ChanStateDB)
+	if nativeSQLStore != nil {
+		createQuery := func(tx *sql.Tx) channeldb.SQLPaymentQueries {
+			return nativeSQLStore.WithTx(tx)
+		}
+		executor := sqldb.NewTransactionExecutor(
+			nativeSQLStore, createQuery,
+		)
+		paymentStore := channeldb.NewSQLPaymentStore(
+			executor, cfg.KeepFailedPaymentAttempts,
+		)
+
+		// Copy over any payments from the key-value store the first
+		// time native SQL is used.
+		err := channeldb.MigratePaymentsToSQL(
+			ctx, dbs.ChanStateDB, paymentStore,
+		)
+		if err != nil {
+			cleanUp()
+
+			err := fmt.Errorf("unable to migrate payments: %v", err)
+			d.logger.Error(err)
+			return nil, nil, err
+		}
+
+		dbs.PaymentDB = paymentStore
+	}
+
 	// Wrap the watchtower client DB and make sure we clean up.
 	if cfg.WtClient.Active {
 		dbs.TowerClientDB, err = wtdb.OpenClientDB(
@@ -9,7 +9,9 @@ import (
 
 	"github.com/ltcsuite/lnd/chainntnfs"
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/clock"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/kvdb"
 	"github.com/ltcsuite/lnd/labels"
@@ -182,6 +184,21 @@ type ChainArbitratorConfig struct {
 	// htlcIndex, if it is a forwarded one.
 	IsForwardedHTLC func(chanID lnwire.ShortChannelID, htlcIndex uint64) bool
 
+	// QueryIncomingCircuit returns the circuit key of the incoming htlc of
+	// a forwarded htlc, identified by its outgoing channel id and
+	// htlcIndex. False is returned if the htlc isn't a known forward.
+	QueryIncomingCircuit func(chanID lnwire.ShortChannelID,
+		htlcIndex uint64) (models.CircuitKey, bool)
+
+	// ForwardingTimeLockDelta is the forwarding CLTV delta of the node. It
+	// is used to derive the deadline of an outgoing htlc that is timed out
+	// on-chain if its incoming htlc isn't known.
+	ForwardingTimeLockDelta uint32
+
+	// Budget is the configuration of the fee budgets we give the sweeper
+	// for the outputs of force closed channels.
+	Budget BudgetConfig
+
 	// Clock is the clock implementation that ChannelArbitrator uses.
 	// It is useful for testing.
 	Clock clock.Clock
@@ -381,6 +398,13 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 			chanStateDB := c.chanSource.ChannelStateDB()
 			return chanStateDB.FetchHistoricalChannel(&chanPoint)
 		},
+		FindIncomingHTLCExpiry: func(
+			htlc channeldb.HTLC) fn.Option[int32] {
+
+			return c.FindIncomingHTLCExpiry(
+				channel.ShortChanID(), htlc,
+			)
+		},
 	}
 
 	// The final component needed is an arbitrator log that the arbitrator
@@ -425,6 +449,45 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 	), nil
 }
 
+// FindIncomingHTLCExpiry returns the expiry height of the incoming htlc of
+// the given outgoing htlc of a channel. None is returned if the htlc isn't a
+// forward, or if its incoming htlc can't be found among the htlcs of our
+// active channels.
+func (c *ChainArbitrator) FindIncomingHTLCExpiry(scid lnwire.ShortChannelID,
+	htlc channeldb.HTLC) fn.Option[int32] {
+
+	if c.cfg.QueryIncomingCircuit == nil {
+		return fn.None[int32]()
+	}
+
+	incoming, ok := c.cfg.QueryIncomingCircuit(scid, htlc.HtlcIndex)
+	if !ok {
+		log.Debugf("ChainArbitrator: no incoming circuit found for "+
+			"htlc %v of channel %v", htlc.HtlcIndex, scid)
+
+		return fn.None[int32]()
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, channelArb := range c.activeChannels {
+		if channelArb.cfg.ShortChanID != incoming.ChanID {
+			continue
+		}
+
+		expiry := channelArb.incomingHTLCExpiry(incoming.HtlcID)
+		if expiry.IsSome() {
+			return expiry
+		}
+	}
+
+	log.Warnf("ChainArbitrator: incoming htlc %v not found for htlc %v "+
+		"of channel %v", incoming, htlc.HtlcIndex, scid)
+
+	return fn.None[int32]()
+}
+
 // getArbChannel returns an open channel wrapper for use by channel arbitrators.
 func (c *ChainArbitrator) getArbChannel(
 	channel *channeldb.OpenChannel) *arbChannel {
@@ -491,6 +554,56 @@ func (c *ChainArbitrator) ResolveContract(chanPoint wire.OutPoint) error {
 	return nil
 }
 
+// spliceChannel replaces the channel arbitrator and chain watcher of a
+// channel whose splice has confirmed with new ones watching the new funding
+// output of the channel. The old ones are stopped in a new goroutine, as the
+// chain watcher calls this method itself.
+func (c *ChainArbitrator) spliceChannel(oldPoint wire.OutPoint,
+	spliced *channeldb.OpenChannel) {
+
+	log.Infof("Moving ChannelArbitrator for ChannelPoint(%v) to spliced "+
+		"ChannelPoint(%v)", oldPoint, spliced.FundingOutpoint)
+
+	if err := c.WatchNewChannel(spliced); err != nil {
+		log.Errorf("unable to watch spliced ChannelPoint(%v): %v",
+			spliced.FundingOutpoint, err)
+	}
+
+	c.Lock()
+	chainArb := c.activeChannels[oldPoint]
+	delete(c.activeChannels, oldPoint)
+
+	chainWatcher := c.activeWatchers[oldPoint]
+	delete(c.activeWatchers, oldPoint)
+	c.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		if chainArb != nil {
+			if err := chainArb.Stop(); err != nil {
+				log.Warnf("unable to stop ChannelArbitrator"+
+					"(%v): %v", oldPoint, err)
+			}
+
+			// The arbitrator log is keyed by the old funding
+			// outpoint, and holds no state for a channel that was
+			// clean when it was spliced.
+			if err := chainArb.log.WipeHistory(); err != nil {
+				log.Warnf("unable to wipe arbitrator log of "+
+					"ChannelPoint(%v): %v", oldPoint, err)
+			}
+		}
+		if chainWatcher != nil {
+			if err := chainWatcher.Stop(); err != nil {
+				log.Warnf("unable to stop ChainWatcher(%v): %v",
+					oldPoint, err)
+			}
+		}
+	}()
+}
+
 // Start launches all goroutines that the ChainArbitrator needs to operate.
 func (c *ChainArbitrator) Start() error {
 	if !atomic.CompareAndSwapInt32(&c.started, 0, 1) {
@@ -531,6 +644,11 @@ func (c *ChainArbitrator) Start() error {
 				isOurAddr:           c.cfg.IsOurAddress,
 				contractBreach:      breachClosure,
 				extractStateNumHint: lnwallet.GetStateNumHint,
+				spliceChannel: func(
+					spliced *channeldb.OpenChannel) {
+
+					c.spliceChannel(chanPoint, spliced)
+				},
 			},
 		)
 		if err != nil {
@@ -577,6 +695,7 @@ func (c *ChainArbitrator) Start() error {
 		// We can leave off the CloseContract and ForceCloseChan
 		// methods as the channel is already closed at this point.
 		chanPoint := closeChanInfo.ChanPoint
+		shortChanID := closeChanInfo.ShortChanID
 		arbCfg := ChannelArbitratorConfig{
 			ChanPoint:             chanPoint,
 			ShortChanID:           closeChanInfo.ShortChanID,
@@ -596,6 +715,13 @@ func (c *ChainArbitrator) Start() error {
 				chanStateDB := c.chanSource.ChannelStateDB()
 				return chanStateDB.FetchHistoricalChannel(&chanPoint)
 			},
+			FindIncomingHTLCExpiry: func(
+				htlc channeldb.HTLC) fn.Option[int32] {
+
+				return c.FindIncomingHTLCExpiry(
+					shortChanID, htlc,
+				)
+			},
 		}
 		chanLog, err := newBoltArbitratorLog(
 			c.chanSource.Backend, arbCfg, c.cfg.ChainHash, chanPoint,
@@ -1150,6 +1276,9 @@ func (c *ChainArbitrator) WatchNewChannel(newChan *channeldb.OpenChannel) error
 				)
 			},
 			extractStateNumHint: lnwallet.GetStateNumHint,
+			spliceChannel: func(spliced *channeldb.OpenChannel) {
+				c.spliceChannel(chanPoint, spliced)
+			},
 		},
 	)
 	if err != nil {
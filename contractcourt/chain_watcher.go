@@ -2,6 +2,7 @@ package contractcourt
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -12,6 +13,7 @@ import (
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
@@ -150,6 +152,12 @@ type ChainEventSubscription struct {
 	// material required to bring the cheating channel peer to justice.
 	ContractBreach chan *BreachCloseInfo
 
+	// Spliced is a channel that will be sent upon once a splice of the
+	// channel has confirmed, and the channel was moved over to the new
+	// funding output. The updated channel state is sent across the
+	// channel.
+	Spliced chan *channeldb.OpenChannel
+
 	// Cancel cancels the subscription to the event stream for a particular
 	// channel. This method should be called once the caller no longer needs to
 	// be notified of any on-chain events for a particular channel.
@@ -187,6 +195,13 @@ type chainWatcherConfig struct {
 	// obfuscater. This is used by the chain watcher to identify which
 	// state was broadcast and confirmed on-chain.
 	extractStateNumHint func(*wire.MsgTx, [lnwallet.StateHintSize]byte) uint64
+
+	// spliceChannel is called once a splice of the channel has confirmed
+	// and the channel was moved over to its new funding output, so the
+	// new funding output can be watched instead.
+	//
+	// NOTE: This chain watcher is stopped asynchronously.
+	spliceChannel func(*channeldb.OpenChannel)
 }
 
 // chainWatcher is a system that's assigned to every active channel. The duty
@@ -369,6 +384,7 @@ func (c *chainWatcher) SubscribeChannelEvents() *ChainEventSubscription {
 		LocalUnilateralClosure:  make(chan *LocalUnilateralCloseInfo, 1),
 		CooperativeClosure:      make(chan *CooperativeCloseInfo, 1),
 		ContractBreach:          make(chan *BreachCloseInfo, 1),
+		Spliced:                 make(chan *channeldb.OpenChannel, 1),
 		Cancel: func() {
 			c.Lock()
 			delete(c.clientSubscriptions, clientID)
@@ -409,22 +425,26 @@ func (c *chainWatcher) handleUnknownLocalState(
 	}
 	commitPoint := input.ComputeCommitmentPoint(commitSecret[:])
 
+	// The channel type may have been upgraded since this state was
+	// created, so we'll use the type that was in use at that state.
+	chanType := c.cfg.chanState.ChanTypeAtHeight(true, broadcastStateNum)
+
 	// Now that we have the commit point, we'll derive the tweaked local
 	// and remote keys for this state. We use our point as only we can
 	// revoke our own commitment.
 	commitKeyRing := lnwallet.DeriveCommitmentKeys(
-		commitPoint, true, c.cfg.chanState.ChanType,
+		commitPoint, true, chanType,
 		&c.cfg.chanState.LocalChanCfg, &c.cfg.chanState.RemoteChanCfg,
 	)
 
 	// With the keys derived, we'll construct the remote script that'll be
 	// present if they have a non-dust balance on the commitment.
 	var leaseExpiry uint32
-	if c.cfg.chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = c.cfg.chanState.ThawHeight
 	}
 	remoteScript, _, err := lnwallet.CommitScriptToRemote(
-		c.cfg.chanState.ChanType, c.cfg.chanState.IsInitiator,
+		chanType, c.cfg.chanState.IsInitiator,
 		commitKeyRing.ToRemoteKey, leaseExpiry,
 	)
 	if err != nil {
@@ -435,7 +455,7 @@ func (c *chainWatcher) handleUnknownLocalState(
 	// the remote party allowing them to claim this output before the CSV
 	// delay if we breach.
 	localScript, err := lnwallet.CommitScriptToSelf(
-		c.cfg.chanState.ChanType, c.cfg.chanState.IsInitiator,
+		chanType, c.cfg.chanState.IsInitiator,
 		commitKeyRing.ToLocalKey, commitKeyRing.RevocationKey,
 		uint32(c.cfg.chanState.LocalChanCfg.CsvDelay), leaseExpiry,
 	)
@@ -630,6 +650,20 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 			return
 		}
 
+		// If the funding output was spent by a splice of the channel,
+		// the channel lives on with the new funding output once the
+		// splice has confirmed. If the splice is reorged out and the
+		// funding output is spent by another transaction instead,
+		// we'll go on to handle that spend.
+		commitSpend, err := c.handleSpliceSpend(commitSpend, spendNtfn)
+		if err != nil {
+			log.Errorf("unable to handle splice: %v", err)
+			return
+		}
+		if commitSpend == nil {
+			return
+		}
+
 		// Otherwise, the remote party might have broadcast a prior
 		// revoked state...!!!
 		commitTxBroadcast := commitSpend.SpendingTx
@@ -743,6 +777,152 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 	}
 }
 
+// handleSpliceSpend checks whether the passed spend of the funding output is
+// the splice transaction of the channel, and if so, dispatches the splice. It
+// returns the spend that still needs to be handled as a close of the channel,
+// which is nil if the channel was moved over to the splice.
+func (c *chainWatcher) handleSpliceSpend(spend *chainntnfs.SpendDetail,
+	spendNtfn *chainntnfs.SpendEvent) (*chainntnfs.SpendDetail, error) {
+
+	splice, err := c.cfg.chanState.FetchSplice()
+	switch {
+	case errors.Is(err, channeldb.ErrNoSplice):
+		return spend, nil
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to fetch splice: %w", err)
+
+	case splice.LocalLocked ||
+		splice.SpliceTx.TxHash() != *spend.SpenderTxHash:
+
+		return spend, nil
+	}
+
+	return c.dispatchSplice(splice, spend, spendNtfn)
+}
+
+// dispatchSplice waits for the passed splice of the channel to confirm
+// sufficiently, then moves the channel over to its new funding output and
+// notifies all subscribers. Until then, the splice transaction may still be
+// reorged out, so we'll keep watching the old funding output. If it is spent
+// by another transaction instead, that spend is returned to be handled as a
+// close of the channel.
+func (c *chainWatcher) dispatchSplice(splice *channeldb.ChannelSplice,
+	spend *chainntnfs.SpendDetail,
+	spendNtfn *chainntnfs.SpendEvent) (*chainntnfs.SpendDetail, error) {
+
+	spliceTxid := splice.SpliceTx.TxHash()
+	fundingOut := splice.SpliceTx.TxOut[splice.FundingOutputIndex]
+
+	numConfs := uint32(c.cfg.chanState.NumConfsRequired)
+	if numConfs == 0 {
+		numConfs = 1
+	}
+
+	log.Infof("ChannelPoint(%v) spliced by txid=%v, waiting for %v "+
+		"confirmations", c.cfg.chanState.FundingOutpoint, spliceTxid,
+		numConfs)
+
+	confNtfn, err := c.cfg.notifier.RegisterConfirmationsNtfn(
+		&spliceTxid, fundingOut.PkScript, numConfs,
+		uint32(spend.SpendingHeight),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer confNtfn.Cancel()
+
+	var conf *chainntnfs.TxConfirmation
+	for conf == nil {
+		select {
+		case txConf, ok := <-confNtfn.Confirmed:
+			if !ok {
+				return nil, fmt.Errorf("notifier exiting")
+			}
+			conf = txConf
+
+		// The splice transaction was reorged out, so the old funding
+		// output is unspent again. We'll keep waiting, as either the
+		// splice or a commitment may spend it in the new chain.
+		case _, ok := <-spendNtfn.Reorg:
+			if !ok {
+				return nil, fmt.Errorf("notifier exiting")
+			}
+
+			log.Warnf("Splice txid=%v of ChannelPoint(%v) reorged "+
+				"out, watching funding output", spliceTxid,
+				c.cfg.chanState.FundingOutpoint)
+
+		case newSpend, ok := <-spendNtfn.Spend:
+			if !ok {
+				return nil, fmt.Errorf("notifier exiting")
+			}
+
+			if *newSpend.SpenderTxHash == spliceTxid {
+				log.Infof("Splice txid=%v of ChannelPoint(%v) "+
+					"mined again at height=%v",
+					spliceTxid,
+					c.cfg.chanState.FundingOutpoint,
+					newSpend.SpendingHeight)
+
+				continue
+			}
+
+			log.Warnf("ChannelPoint(%v) spent by txid=%v instead "+
+				"of splice txid=%v",
+				c.cfg.chanState.FundingOutpoint,
+				newSpend.SpenderTxHash, spliceTxid)
+
+			return newSpend, nil
+
+		case <-c.quit:
+			return nil, fmt.Errorf("exiting")
+		}
+	}
+
+	// Fetch the latest state of the channel before moving it over to
+	// the new funding output.
+	chanState, err := c.cfg.chanState.Db.FetchChannel(
+		nil, c.cfg.chanState.FundingOutpoint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scid := lnwire.ShortChannelID{
+		BlockHeight: conf.BlockHeight,
+		TxIndex:     conf.TxIndex,
+		TxPosition:  uint16(splice.FundingOutputIndex),
+	}
+	if err := chanState.CompleteSplice(scid); err != nil {
+		return nil, err
+	}
+
+	log.Infof("ChannelPoint(%v) moved to spliced ChannelPoint(%v), "+
+		"scid=%v", c.cfg.chanState.FundingOutpoint,
+		chanState.FundingOutpoint, scid)
+
+	// Hand the channel off to be watched under its new funding output
+	// before notifying the subscribers, so they're able to subscribe to
+	// the events of the spliced channel right away.
+	if c.cfg.spliceChannel != nil {
+		c.cfg.spliceChannel(chanState)
+	}
+
+	c.Lock()
+	for _, sub := range c.clientSubscriptions {
+		select {
+		case sub.Spliced <- chanState:
+		case <-c.quit:
+			c.Unlock()
+			return nil, fmt.Errorf("exiting")
+		}
+	}
+	c.Unlock()
+
+	return nil, nil
+}
+
 // handleKnownLocalState checks whether the passed spend is a local state that
 // is known to us (the current state). If so we will act on this state using
 // the passed chainSet. If this is not a known local state, false is returned.
@@ -882,8 +1062,9 @@ func (c *chainWatcher) handlePossibleBreach(commitSpend *chainntnfs.SpendDetail,
 
 	// Create an AnchorResolution for the breached state.
 	anchorRes, err := lnwallet.NewAnchorResolution(
-		c.cfg.chanState, commitSpend.SpendingTx, retribution.KeyRing,
-		false,
+		c.cfg.chanState,
+		c.cfg.chanState.ChanTypeAtHeight(false, broadcastStateNum),
+		commitSpend.SpendingTx, retribution.KeyRing, false,
 	)
 	if err != nil {
 		return false, fmt.Errorf("unable to create anchor "+
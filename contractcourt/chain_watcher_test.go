@@ -599,3 +599,261 @@ func TestChainWatcherLocalForceCloseDetect(t *testing.T) {
 		})
 	}
 }
+
+// markTestSplice records a signed splice of the channel that adds funds to it,
+// and returns the splice transaction.
+func markTestSplice(t *testing.T,
+	channel *lnwallet.LightningChannel) *wire.MsgTx {
+
+	oldPoint := *channel.ChannelPoint()
+	fundingOut := channel.FundingTxOut()
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{PreviousOutPoint: oldPoint})
+	spliceTx.AddTxOut(&wire.TxOut{
+		Value:    fundingOut.Value + 100_000,
+		PkScript: fundingOut.PkScript,
+	})
+
+	state := channel.State()
+	err := state.MarkSplicing(&channeldb.ChannelSplice{
+		SpliceTx:          spliceTx,
+		PrevOutpoint:      oldPoint,
+		Capacity:          state.Capacity + 100_000,
+		IsInitiator:       true,
+		LocalContribution: 100_000,
+		LocalCommitment:   state.LocalCommitment,
+		RemoteCommitment:  state.RemoteCommitment,
+		Signed:            true,
+	})
+	require.NoError(t, err)
+
+	return spliceTx
+}
+
+// TestChainWatcherSplice tests that the chain watcher moves a channel over to
+// its new funding output once a spend by its splice transaction confirmed.
+func TestChainWatcherSplice(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, _, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		ReorgChan: make(chan struct{}),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	splicedChans := make(chan *channeldb.OpenChannel, 1)
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           aliceChannel.State(),
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		extractStateNumHint: lnwallet.GetStateNumHint,
+		spliceChannel: func(spliced *channeldb.OpenChannel) {
+			splicedChans <- spliced
+		},
+	})
+	require.NoError(t, err, "unable to create chain watcher")
+	require.NoError(t, aliceChainWatcher.Start())
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	oldPoint := *aliceChannel.ChannelPoint()
+	spliceTx := markTestSplice(t, aliceChannel)
+	spliceTxid := spliceTx.TxHash()
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &oldPoint,
+		SpenderTxHash:  &spliceTxid,
+		SpendingTx:     spliceTx,
+		SpendingHeight: 100,
+	}
+
+	// The channel is only moved once the splice has confirmed.
+	select {
+	case <-chanEvents.Spliced:
+		t.Fatalf("splice dispatched before confirmation")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// A reorg of the splice transaction doesn't affect the channel, as
+	// long as the splice is mined again.
+	select {
+	case aliceNotifier.ReorgChan <- struct{}{}:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("splice reorg not consumed")
+	}
+
+	select {
+	case aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &oldPoint,
+		SpenderTxHash:  &spliceTxid,
+		SpendingTx:     spliceTx,
+		SpendingHeight: 101,
+	}:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("splice spend not consumed")
+	}
+
+	aliceNotifier.ConfChan <- &chainntnfs.TxConfirmation{
+		BlockHeight: 102,
+		TxIndex:     4,
+		Tx:          spliceTx,
+	}
+
+	var spliced *channeldb.OpenChannel
+	select {
+	case spliced = <-chanEvents.Spliced:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive splice event")
+	}
+
+	newPoint := wire.OutPoint{Hash: spliceTxid}
+	require.Equal(t, newPoint, spliced.FundingOutpoint)
+	require.Equal(t, uint32(102), spliced.ShortChannelID.BlockHeight)
+	require.Equal(t, uint32(4), spliced.ShortChannelID.TxIndex)
+
+	select {
+	case handedOff := <-splicedChans:
+		require.Equal(t, newPoint, handedOff.FundingOutpoint)
+	case <-time.After(time.Second * 15):
+		t.Fatalf("spliced channel not handed off")
+	}
+}
+
+// TestChainWatcherSpliceReorg tests that the chain watcher keeps watching the
+// old funding output while the splice of a channel hasn't confirmed
+// sufficiently, so that a commitment spending it after the splice was reorged
+// out is still detected.
+func TestChainWatcherSpliceReorg(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		breach bool
+	}{{
+		name:   "remote commitment",
+		breach: false,
+	}, {
+		name:   "revoked commitment",
+		breach: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testChainWatcherSpliceReorg(t, tc.breach)
+		})
+	}
+}
+
+func testChainWatcherSpliceReorg(t *testing.T, breach bool) {
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	// Advance the channel by one state, so that Bob's initial commitment
+	// is revoked.
+	revokedCommit := bobChannel.State().LocalCommitment.CommitTx
+	htlcAmount := lnwire.NewMSatFromSatoshis(20_000)
+	addFakeHTLC(t, htlcAmount, 0, aliceChannel, bobChannel)
+	require.NoError(t, lnwallet.ForceStateTransition(
+		aliceChannel, bobChannel,
+	))
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		ReorgChan: make(chan struct{}),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	splicedChans := make(chan *channeldb.OpenChannel, 1)
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           aliceChannel.State(),
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		extractStateNumHint: lnwallet.GetStateNumHint,
+		contractBreach: func(*lnwallet.BreachRetribution) error {
+			return nil
+		},
+		spliceChannel: func(spliced *channeldb.OpenChannel) {
+			splicedChans <- spliced
+		},
+	})
+	require.NoError(t, err, "unable to create chain watcher")
+	require.NoError(t, aliceChainWatcher.Start())
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	// The splice transaction spends the funding output, but is reorged
+	// out before it reached the required number of confirmations.
+	oldPoint := *aliceChannel.ChannelPoint()
+	spliceTx := markTestSplice(t, aliceChannel)
+	spliceTxid := spliceTx.TxHash()
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &oldPoint,
+		SpenderTxHash:  &spliceTxid,
+		SpendingTx:     spliceTx,
+		SpendingHeight: 100,
+	}
+
+	select {
+	case aliceNotifier.ReorgChan <- struct{}{}:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("splice reorg not consumed")
+	}
+
+	// Bob broadcasts one of his commitments instead, which must be handled
+	// as a close of the channel.
+	closeTx := bobChannel.State().LocalCommitment.CommitTx
+	if breach {
+		closeTx = revokedCommit
+	}
+	closeTxid := closeTx.TxHash()
+
+	select {
+	case aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &oldPoint,
+		SpenderTxHash:  &closeTxid,
+		SpendingTx:     closeTx,
+		SpendingHeight: 101,
+	}:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("commitment spend not consumed")
+	}
+
+	if breach {
+		select {
+		case breachInfo := <-chanEvents.ContractBreach:
+			require.Equal(t, closeTxid, breachInfo.CommitHash)
+		case <-time.After(time.Second * 15):
+			t.Fatalf("didn't receive contract breach event")
+		}
+	} else {
+		select {
+		case uniClose := <-chanEvents.RemoteUnilateralClosure:
+			require.Equal(
+				t, closeTxid, *uniClose.SpenderTxHash,
+			)
+		case <-time.After(time.Second * 15):
+			t.Fatalf("didn't receive unilateral close event")
+		}
+	}
+
+	// The channel must not have been moved over to the splice.
+	select {
+	case <-splicedChans:
+		t.Fatalf("reorged splice handed off")
+	case <-chanEvents.Spliced:
+		t.Fatalf("reorged splice dispatched")
+	default:
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/channeldb/models"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/htlcswitch/hop"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/invoices"
@@ -169,6 +170,10 @@ type ChannelArbitratorConfig struct {
 	// additional information required for proper contract resolution.
 	FetchHistoricalChannel func() (*channeldb.OpenChannel, error)
 
+	// FindIncomingHTLCExpiry returns the expiry height of the incoming
+	// htlc of a forwarded outgoing htlc of this channel, if known.
+	FindIncomingHTLCExpiry func(htlc channeldb.HTLC) fn.Option[int32]
+
 	ChainArbitratorConfig
 }
 
@@ -756,6 +761,12 @@ func (c *ChannelArbitrator) relaunchResolvers(commitSet *CommitSet,
 		}
 
 		htlcResolver.Supplement(*htlc)
+
+		// The deadline of outgoing htlcs isn't stored with their
+		// resolvers, so we supplement it as well.
+		if r, ok := resolver.(deadlineContractResolver); ok {
+			r.SupplementDeadline(c.outgoingHTLCDeadline(*htlc))
+		}
 	}
 
 	// The anchor resolver is stateless and can always be re-instantiated.
@@ -1288,9 +1299,10 @@ func (c *ChannelArbitrator) stateStep(
 	return nextState, closeTx, nil
 }
 
-// sweepAnchors offers all given anchor resolutions to the sweeper. It requests
-// sweeping at the minimum fee rate. This fee rate can be upped manually by the
-// user via the BumpFee rpc.
+// sweepAnchors offers all given anchor resolutions to the sweeper. Anchors of
+// commitments with HTLCs at stake are given a budget relative to the value of
+// those HTLCs, which the sweeper spends on cpfp as the deadline approaches.
+// The fee rate can also be upped manually by the user via the BumpFee rpc.
 func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 	heightHint uint32) error {
 
@@ -1304,14 +1316,20 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 		htlcs htlcSet, anchorPath string) error {
 
 		// Find the deadline for this specific anchor.
-		deadline, err := c.findCommitmentDeadline(heightHint, htlcs)
+		deadline, valueAtStake, err := c.findCommitmentDeadline(
+			heightHint, htlcs,
+		)
 		if err != nil {
 			return err
 		}
 
 		// Create a force flag that's used to indicate whether we
 		// should force sweeping this anchor.
-		var force bool
+		var (
+			force          bool
+			budget         ltcutil.Amount
+			deadlineHeight fn.Option[int32]
+		)
 
 		// Check the deadline against the default value. If it's less
 		// than the default value of 144, it means there is a deadline
@@ -1321,11 +1339,17 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 			// anchor will be swept even if it isn't economical
 			// purely based on the anchor value.
 			force = true
+
+			// Give the sweeper a budget to spend on getting the
+			// commitment confirmed before the HTLCs expire.
+			budget = c.cfg.Budget.AnchorCPFPBudget(valueAtStake)
+			deadlineHeight = fn.Some(int32(heightHint + deadline))
 		}
 
 		log.Debugf("ChannelArbitrator(%v): pre-confirmation sweep of "+
-			"anchor of %s commit tx %v, force=%v", c.cfg.ChanPoint,
-			anchorPath, anchor.CommitAnchor, force)
+			"anchor of %s commit tx %v, force=%v, budget=%v",
+			c.cfg.ChanPoint, anchorPath, anchor.CommitAnchor, force,
+			budget)
 
 		witnessType := input.CommitmentAnchor
 
@@ -1363,6 +1387,8 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 				},
 				Force:          force,
 				ExclusiveGroup: &exclusiveGroup,
+				Budget:         budget,
+				DeadlineHeight: deadlineHeight,
 			},
 		)
 		if err != nil {
@@ -1415,14 +1441,20 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 //   - the least CLTV from outgoing HTLCs,  or,
 //   - the least CLTV from incoming HTLCs if the preimage is available.
 //
+// The total value of those HTLCs, which is what we stand to lose if the
+// commitment doesn't confirm in time, is returned as well.
+//
 // Note: when the deadline turns out to be 0 blocks, we will replace it with 1
 // block because our fee estimator doesn't allow a 0 conf target. This also
 // means we've left behind and should increase our fee to make the transaction
 // confirmed asap.
 func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
-	htlcs htlcSet) (uint32, error) {
+	htlcs htlcSet) (uint32, ltcutil.Amount, error) {
 
-	deadlineMinHeight := uint32(math.MaxUint32)
+	var (
+		deadlineMinHeight = uint32(math.MaxUint32)
+		valueAtStake      ltcutil.Amount
+	)
 
 	// First, iterate through the outgoingHTLCs to find the lowest CLTV
 	// value.
@@ -1436,6 +1468,8 @@ func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 			continue
 		}
 
+		valueAtStake += htlc.Amt.ToSatoshis()
+
 		if htlc.RefundTimeout < deadlineMinHeight {
 			deadlineMinHeight = htlc.RefundTimeout
 			log.Tracef("ChannelArbitrator(%v): outgoing HTLC has "+
@@ -1460,13 +1494,15 @@ func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 		// this HTLC.
 		preimageAvailable, err := c.isPreimageAvailable(htlc.RHash)
 		if err != nil {
-			return 0, err
+			return 0, 0, err
 		}
 
 		if !preimageAvailable {
 			continue
 		}
 
+		valueAtStake += htlc.Amt.ToSatoshis()
+
 		if htlc.RefundTimeout < deadlineMinHeight {
 			deadlineMinHeight = htlc.RefundTimeout
 			log.Tracef("ChannelArbitrator(%v): incoming HTLC has "+
@@ -1502,7 +1538,7 @@ func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 		"using deadlineMinHeight=%d, heightHint=%d",
 		c.cfg.ChanPoint, deadline, deadlineMinHeight, heightHint)
 
-	return deadline, nil
+	return deadline, valueAtStake, nil
 }
 
 // launchResolvers updates the activeResolvers list and starts the resolvers.
@@ -2348,6 +2384,9 @@ func (c *ChannelArbitrator) prepContractResolutions(
 				if chanState != nil {
 					resolver.SupplementState(chanState)
 				}
+				resolver.SupplementDeadline(
+					c.outgoingHTLCDeadline(htlc),
+				)
 				htlcResolvers = append(htlcResolvers, resolver)
 			}
 
@@ -2440,6 +2479,9 @@ func (c *ChannelArbitrator) prepContractResolutions(
 				if chanState != nil {
 					resolver.SupplementState(chanState)
 				}
+				resolver.SupplementDeadline(
+					c.outgoingHTLCDeadline(htlc),
+				)
 				htlcResolvers = append(htlcResolvers, resolver)
 			}
 		}
@@ -2654,6 +2696,42 @@ func (c *ChannelArbitrator) updateActiveHTLCs() {
 	}
 }
 
+// incomingHTLCExpiry returns the expiry height of the incoming htlc with the
+// given index on any of the commitments of the channel, if it exists.
+func (c *ChannelArbitrator) incomingHTLCExpiry(htlcIndex uint64) fn.Option[int32] {
+	c.unmergedMtx.RLock()
+	defer c.unmergedMtx.RUnlock()
+
+	for _, htlcs := range c.unmergedSet {
+		htlc, ok := htlcs.incomingHTLCs[htlcIndex]
+		if ok {
+			return fn.Some(int32(htlc.RefundTimeout))
+		}
+	}
+
+	return fn.None[int32]()
+}
+
+// outgoingHTLCDeadline returns the height by which the timeout of an outgoing
+// htlc must confirm. This is the expiry of its incoming htlc, after which we
+// can no longer fail the incoming htlc back without losing its amount. If the
+// incoming htlc isn't known, the deadline is derived from the expiry of the
+// outgoing htlc and our forwarding CLTV delta.
+func (c *ChannelArbitrator) outgoingHTLCDeadline(
+	htlc channeldb.HTLC) fn.Option[int32] {
+
+	if c.cfg.FindIncomingHTLCExpiry != nil {
+		expiry := c.cfg.FindIncomingHTLCExpiry(htlc)
+		if expiry.IsSome() {
+			return expiry
+		}
+	}
+
+	return fn.Some(
+		int32(htlc.RefundTimeout + c.cfg.ForwardingTimeLockDelta),
+	)
+}
+
 // channelAttendant is the primary goroutine that acts at the judicial
 // arbitrator between our channel state, the remote channel peer, and the
 // blockchain (Our judge). This goroutine will ensure that we faithfully execute
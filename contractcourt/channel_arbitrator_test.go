@@ -13,12 +13,14 @@ import (
 	"github.com/ltcsuite/lnd/chainntnfs"
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/clock"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/kvdb"
 	"github.com/ltcsuite/lnd/lntest/mock"
 	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/sweep"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/wire"
@@ -2333,7 +2335,7 @@ func TestFindCommitmentDeadline(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			deadline, err := chanArb.findCommitmentDeadline(
+			deadline, _, err := chanArb.findCommitmentDeadline(
 				heightHint, tc.htlcs,
 			)
 
@@ -2343,6 +2345,78 @@ func TestFindCommitmentDeadline(t *testing.T) {
 	}
 }
 
+// TestOutgoingHTLCDeadline checks that the deadline of an outgoing htlc is the
+// expiry of its incoming htlc if known, and is otherwise derived from its own
+// expiry and the forwarding CLTV delta.
+func TestOutgoingHTLCDeadline(t *testing.T) {
+	t.Parallel()
+
+	incoming := channeldb.HTLC{
+		HtlcIndex:     1,
+		RefundTimeout: 1100,
+		Incoming:      true,
+	}
+	outgoing := channeldb.HTLC{
+		HtlcIndex:     2,
+		RefundTimeout: 1000,
+	}
+
+	// The incoming channel knows the expiry of its incoming htlc.
+	incomingArb := NewChannelArbitrator(
+		ChannelArbitratorConfig{}, map[HtlcSetKey]htlcSet{
+			LocalHtlcSet: newHtlcSet(
+				[]channeldb.HTLC{incoming},
+			),
+		}, nil,
+	)
+	require.Equal(
+		t, fn.Some(int32(incoming.RefundTimeout)),
+		incomingArb.incomingHTLCExpiry(incoming.HtlcIndex),
+	)
+	require.Equal(
+		t, fn.None[int32](),
+		incomingArb.incomingHTLCExpiry(outgoing.HtlcIndex),
+	)
+
+	cfg := ChannelArbitratorConfig{
+		ChainArbitratorConfig: ChainArbitratorConfig{
+			ForwardingTimeLockDelta: 40,
+		},
+	}
+	outgoingArb := NewChannelArbitrator(
+		cfg, map[HtlcSetKey]htlcSet{}, nil,
+	)
+
+	// Without a way to find the incoming htlc, the forwarding CLTV delta
+	// is added to the expiry of the outgoing htlc.
+	require.Equal(
+		t, fn.Some(int32(1040)),
+		outgoingArb.outgoingHTLCDeadline(outgoing),
+	)
+
+	// If the incoming htlc is unknown, the same fallback is used.
+	outgoingArb.cfg.FindIncomingHTLCExpiry = func(
+		channeldb.HTLC) fn.Option[int32] {
+
+		return fn.None[int32]()
+	}
+	require.Equal(
+		t, fn.Some(int32(1040)),
+		outgoingArb.outgoingHTLCDeadline(outgoing),
+	)
+
+	// Otherwise, the expiry of the incoming htlc is the deadline.
+	outgoingArb.cfg.FindIncomingHTLCExpiry = func(
+		channeldb.HTLC) fn.Option[int32] {
+
+		return incomingArb.incomingHTLCExpiry(incoming.HtlcIndex)
+	}
+	require.Equal(
+		t, fn.Some(int32(incoming.RefundTimeout)),
+		outgoingArb.outgoingHTLCDeadline(outgoing),
+	)
+}
+
 // TestSweepAnchors checks the sweep transactions are created using the
 // expected deadlines for different anchor resolutions.
 func TestSweepAnchors(t *testing.T) {
@@ -2381,10 +2455,12 @@ func TestSweepAnchors(t *testing.T) {
 		HtlcIndex:     htlcIndexBase + 2,
 		RefundTimeout: htlcExpiryBase + 2,
 		RHash:         rHash,
+		Amt:           100_000_000,
 	}
 	htlcSmallExipry := channeldb.HTLC{
 		HtlcIndex:     htlcIndexBase + 3,
 		RefundTimeout: htlcExpiryBase + 3,
+		Amt:           200_000_000,
 	}
 
 	// Setup our local HTLC set such that we will use the HTLC's CLTV from
@@ -2487,6 +2563,23 @@ func TestSweepAnchors(t *testing.T) {
 		t, expectedRemoteDeadline, deadlines[2],
 		"remote deadline not matched",
 	)
+
+	// Only the anchors of the local and pending remote commitments have
+	// HTLCs at stake, so only those are given a budget.
+	budgets := chanArbCtx.sweeper.budgets
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i] < budgets[j]
+	})
+	require.Equal(t, []ltcutil.Amount{
+		sweep.BudgetFromRatio(
+			htlcWithPreimage.Amt.ToSatoshis(),
+			sweep.DefaultBudgetRatio,
+		),
+		sweep.BudgetFromRatio(
+			htlcSmallExipry.Amt.ToSatoshis(),
+			sweep.DefaultBudgetRatio,
+		),
+	}, budgets)
 }
 
 // TestChannelArbitratorAnchors asserts that the commitment tx anchor is swept.
@@ -352,7 +352,13 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 	c.log.Infof("sweeping commit output")
 
 	feePref := sweep.FeePreference{ConfTarget: commitOutputConfTarget}
-	resultChan, err := c.Sweeper.SweepInput(inp, sweep.Params{Fee: feePref})
+	budget := c.Budget.ToLocalBudget(
+		ltcutil.Amount(inp.SignDesc().Output.Value),
+	)
+	resultChan, err := c.Sweeper.SweepInput(inp, sweep.Params{
+		Fee:    feePref,
+		Budget: budget,
+	})
 	if err != nil {
 		c.log.Errorf("unable to sweep input: %v", err)
 
@@ -110,6 +110,7 @@ type mockSweeper struct {
 	createSweepTxChan chan *wire.MsgTx
 
 	deadlines []int
+	budgets   []ltcutil.Amount
 }
 
 func newMockSweeper() *mockSweeper {
@@ -132,6 +133,11 @@ func (s *mockSweeper) SweepInput(input input.Input, params sweep.Params) (
 		s.deadlines = append(s.deadlines, int(params.Fee.ConfTarget))
 	}
 
+	// Record the budget if it's set.
+	if params.Budget != 0 {
+		s.budgets = append(s.budgets, params.Budget)
+	}
+
 	result := make(chan sweep.Result, 1)
 	result <- sweep.Result{
 		Tx:  s.sweepTx,
@@ -0,0 +1,119 @@
+package contractcourt
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/lnd/sweep"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// BudgetConfig is a struct that holds the configuration of the fee budgets we
+// give the sweeper when offering it the outputs of a force closed channel.
+// The budget of an output is the given ratio of its value, capped at the
+// absolute value if that is set. Ratios that aren't set default to the budget
+// ratio of the sweeper.
+//
+//nolint:lll
+type BudgetConfig struct {
+	ToLocal      ltcutil.Amount `long:"tolocal" description:"The maximum amount in satoshis to spend on fees when sweeping the to_local output. If set, the budget calculated using the ratio is capped at this value."`
+	ToLocalRatio float64        `long:"tolocalratio" description:"The ratio of the value of the to_local output to spend on fees when sweeping it."`
+
+	AnchorCPFP      ltcutil.Amount `long:"anchorcpfp" description:"The maximum amount in satoshis to spend on fees when CPFPing a force close transaction via its anchor output. If set, the budget calculated using the ratio is capped at this value."`
+	AnchorCPFPRatio float64        `long:"anchorcpfpratio" description:"The ratio of the value of the HTLCs at stake to spend on fees when CPFPing a force close transaction via its anchor output."`
+
+	DeadlineHTLC      ltcutil.Amount `long:"deadlinehtlc" description:"The maximum amount in satoshis to spend on fees when sweeping a time-sensitive HTLC output, which must confirm before a deadline. If set, the budget calculated using the ratio is capped at this value."`
+	DeadlineHTLCRatio float64        `long:"deadlinehtlcratio" description:"The ratio of the value of a time-sensitive HTLC output to spend on fees when sweeping it."`
+
+	NoDeadlineHTLC      ltcutil.Amount `long:"nodeadlinehtlc" description:"The maximum amount in satoshis to spend on fees when sweeping a second-level HTLC output, which has no deadline. If set, the budget calculated using the ratio is capped at this value."`
+	NoDeadlineHTLCRatio float64        `long:"nodeadlinehtlcratio" description:"The ratio of the value of a second-level HTLC output to spend on fees when sweeping it."`
+}
+
+// DefaultBudgetConfig returns the default budget config, which spends the
+// default budget ratio of the sweeper on every type of output, without an
+// absolute cap.
+func DefaultBudgetConfig() *BudgetConfig {
+	return &BudgetConfig{
+		ToLocalRatio:        sweep.DefaultBudgetRatio,
+		AnchorCPFPRatio:     sweep.DefaultBudgetRatio,
+		DeadlineHTLCRatio:   sweep.DefaultBudgetRatio,
+		NoDeadlineHTLCRatio: sweep.DefaultBudgetRatio,
+	}
+}
+
+// Validate checks the budget configuration for any invalid values.
+func (b *BudgetConfig) Validate() error {
+	ratios := map[string]float64{
+		"tolocalratio":        b.ToLocalRatio,
+		"anchorcpfpratio":     b.AnchorCPFPRatio,
+		"deadlinehtlcratio":   b.DeadlineHTLCRatio,
+		"nodeadlinehtlcratio": b.NoDeadlineHTLCRatio,
+	}
+	for name, ratio := range ratios {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("%s must be in [0, 1], got %v", name,
+				ratio)
+		}
+	}
+
+	amounts := map[string]ltcutil.Amount{
+		"tolocal":        b.ToLocal,
+		"anchorcpfp":     b.AnchorCPFP,
+		"deadlinehtlc":   b.DeadlineHTLC,
+		"nodeadlinehtlc": b.NoDeadlineHTLC,
+	}
+	for name, amt := range amounts {
+		if amt < 0 {
+			return fmt.Errorf("%s must not be negative, got %v",
+				name, amt)
+		}
+	}
+
+	return nil
+}
+
+// ToLocalBudget returns the budget for sweeping our commitment output of the
+// given value.
+func (b *BudgetConfig) ToLocalBudget(value ltcutil.Amount) ltcutil.Amount {
+	return calculateBudget(value, b.ToLocalRatio, b.ToLocal)
+}
+
+// AnchorCPFPBudget returns the budget for CPFPing a commitment transaction via
+// its anchor, given the value of the HTLCs at stake.
+func (b *BudgetConfig) AnchorCPFPBudget(
+	valueAtStake ltcutil.Amount) ltcutil.Amount {
+
+	return calculateBudget(valueAtStake, b.AnchorCPFPRatio, b.AnchorCPFP)
+}
+
+// DeadlineHTLCBudget returns the budget for sweeping a time-sensitive HTLC
+// output of the given value.
+func (b *BudgetConfig) DeadlineHTLCBudget(
+	value ltcutil.Amount) ltcutil.Amount {
+
+	return calculateBudget(value, b.DeadlineHTLCRatio, b.DeadlineHTLC)
+}
+
+// NoDeadlineHTLCBudget returns the budget for sweeping a second-level HTLC
+// output of the given value.
+func (b *BudgetConfig) NoDeadlineHTLCBudget(
+	value ltcutil.Amount) ltcutil.Amount {
+
+	return calculateBudget(value, b.NoDeadlineHTLCRatio, b.NoDeadlineHTLC)
+}
+
+// calculateBudget returns the given ratio of the value, capped at maxBudget if
+// it is non-zero. If the ratio is zero, the default ratio is used.
+func calculateBudget(value ltcutil.Amount, ratio float64,
+	maxBudget ltcutil.Amount) ltcutil.Amount {
+
+	if ratio == 0 {
+		ratio = sweep.DefaultBudgetRatio
+	}
+
+	budget := sweep.BudgetFromRatio(value, ratio)
+	if maxBudget != 0 && budget > maxBudget {
+		return maxBudget
+	}
+
+	return budget
+}
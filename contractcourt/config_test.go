@@ -0,0 +1,46 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/sweep"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBudgetConfig checks that the budgets of the different types of outputs
+// are calculated from their own ratio and cap.
+func TestBudgetConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BudgetConfig{
+		ToLocalRatio:      0.1,
+		DeadlineHTLC:      3000,
+		DeadlineHTLCRatio: 0.5,
+	}
+	require.NoError(t, cfg.Validate())
+
+	value := ltcutil.Amount(10_000)
+
+	// The to_local budget only uses its ratio.
+	require.EqualValues(t, 1000, cfg.ToLocalBudget(value))
+
+	// The budget of time-sensitive HTLCs is capped at the absolute value.
+	require.EqualValues(t, 3000, cfg.DeadlineHTLCBudget(value))
+	require.EqualValues(t, 1000, cfg.DeadlineHTLCBudget(2000))
+
+	// Ratios that aren't set use the default ratio of the sweeper.
+	require.Equal(
+		t, sweep.BudgetFromRatio(value, sweep.DefaultBudgetRatio),
+		cfg.AnchorCPFPBudget(value),
+	)
+	require.Equal(
+		t, sweep.BudgetFromRatio(value, sweep.DefaultBudgetRatio),
+		cfg.NoDeadlineHTLCBudget(value),
+	)
+
+	// Ratios above one and negative amounts are rejected.
+	require.Error(t, (&BudgetConfig{ToLocalRatio: 1.5}).Validate())
+	require.Error(t, (&BudgetConfig{AnchorCPFP: -1}).Validate())
+	require.NoError(t, DefaultBudgetConfig().Validate())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/btcsuite/btclog"
 	"github.com/ltcsuite/lnd/build"
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/ltcd/wire"
 )
 
@@ -77,6 +78,16 @@ type htlcContractResolver interface {
 	Supplement(htlc channeldb.HTLC)
 }
 
+// deadlineContractResolver is an htlcContractResolver whose sweeps must
+// confirm before a deadline that isn't known to the resolver itself.
+type deadlineContractResolver interface {
+	htlcContractResolver
+
+	// SupplementDeadline sets the height by which the sweeps of the
+	// resolver must confirm.
+	SupplementDeadline(deadline fn.Option[int32])
+}
+
 // reportingContractResolver is a ContractResolver that also exposes a report on
 // the resolution state of the contract.
 type reportingContractResolver interface {
@@ -520,9 +520,20 @@ func (h *htlcIncomingContestResolver) Supplement(htlc channeldb.HTLC) {
 func (h *htlcIncomingContestResolver) decodePayload() (*hop.Payload,
 	[]byte, error) {
 
+	blindingPoint, err := h.htlc.BlindingPoint()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blindingInfo := hop.ReconstructBlindingInfo{
+		BlindingKey:    blindingPoint,
+		IncomingAmt:    h.htlc.Amt,
+		IncomingExpiry: h.htlc.RefundTimeout,
+	}
+
 	onionReader := bytes.NewReader(h.htlc.OnionBlob[:])
 	iterator, err := h.OnionProcessor.ReconstructHopIterator(
-		onionReader, h.htlc.RHash[:],
+		onionReader, h.htlc.RHash[:], blindingInfo,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -9,6 +9,7 @@ import (
 	"github.com/ltcsuite/lnd/chainntnfs"
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/channeldb/models"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/labels"
 	"github.com/ltcsuite/lnd/lnutils"
@@ -260,12 +261,20 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 			)
 		}
 
+		// The second-level tx must confirm before the incoming HTLC
+		// expires, otherwise the remote party can time it out.
 		_, err := h.Sweeper.SweepInput(
 			&secondLevelInput,
 			sweep.Params{
 				Fee: sweep.FeePreference{
 					ConfTarget: secondLevelConfTarget,
 				},
+				Budget: h.Budget.DeadlineHTLCBudget(
+					h.htlc.Amt.ToSatoshis(),
+				),
+				DeadlineHeight: fn.Some(
+					int32(h.htlc.RefundTimeout),
+				),
 			},
 		)
 		if err != nil {
@@ -378,6 +387,9 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 			Fee: sweep.FeePreference{
 				ConfTarget: sweepConfTarget,
 			},
+			Budget: h.Budget.NoDeadlineHTLCBudget(
+				ltcutil.Amount(inp.SignDesc().Output.Value),
+			),
 		},
 	)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ltcsuite/lnd/chainntnfs"
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnutils"
@@ -49,6 +50,12 @@ type htlcTimeoutResolver struct {
 	// htlc contains information on the htlc that we are resolving on-chain.
 	htlc channeldb.HTLC
 
+	// deadlineHeight is the height by which the second-level timeout
+	// transaction must confirm, which is the expiry of the incoming htlc
+	// if the htlc is a forward. It isn't persisted, but supplemented by
+	// the ChannelArbitrator.
+	deadlineHeight fn.Option[int32]
+
 	// currentReport stores the current state of the resolver for reporting
 	// over the rpc interface. This should only be reported in case we have
 	// a non-nil SignDetails on the htlcResolution, otherwise the nursery
@@ -463,6 +470,10 @@ func (h *htlcTimeoutResolver) sweepSecondLevelTx() error {
 				ConfTarget: secondLevelConfTarget,
 			},
 			Force: true,
+			Budget: h.Budget.DeadlineHTLCBudget(
+				h.htlc.Amt.ToSatoshis(),
+			),
+			DeadlineHeight: h.deadlineHeight,
 		},
 	)
 	if err != nil {
@@ -678,6 +689,11 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 				Fee: sweep.FeePreference{
 					ConfTarget: sweepConfTarget,
 				},
+				Budget: h.Budget.NoDeadlineHTLCBudget(
+					ltcutil.Amount(
+						inp.SignDesc().Output.Value,
+					),
+				),
 			},
 		)
 		if err != nil {
@@ -884,6 +900,14 @@ func (h *htlcTimeoutResolver) Supplement(htlc channeldb.HTLC) {
 	h.htlc = htlc
 }
 
+// SupplementDeadline sets the height by which the second-level timeout
+// transaction must confirm.
+//
+// NOTE: Part of the deadlineContractResolver interface.
+func (h *htlcTimeoutResolver) SupplementDeadline(deadline fn.Option[int32]) {
+	h.deadlineHeight = deadline
+}
+
 // HtlcPoint returns the htlc's outpoint on the commitment tx.
 //
 // NOTE: Part of the htlcContractResolver interface.
@@ -893,7 +917,7 @@ func (h *htlcTimeoutResolver) HtlcPoint() wire.OutPoint {
 
 // A compile time assertion to ensure htlcTimeoutResolver meets the
 // ContractResolver interface.
-var _ htlcContractResolver = (*htlcTimeoutResolver)(nil)
+var _ deadlineContractResolver = (*htlcTimeoutResolver)(nil)
 
 // spendResult is used to hold the result of a spend event from either a
 // mempool spend or a block spend.
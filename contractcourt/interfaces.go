@@ -38,8 +38,10 @@ type Registry interface {
 // OnionProcessor is an interface used to decode onion blobs.
 type OnionProcessor interface {
 	// ReconstructHopIterator attempts to decode a valid sphinx packet from
-	// the passed io.Reader instance.
-	ReconstructHopIterator(r io.Reader, rHash []byte) (hop.Iterator, error)
+	// the passed io.Reader instance. The blinding info is required to
+	// process HTLCs that are part of a blinded route.
+	ReconstructHopIterator(r io.Reader, rHash []byte,
+		blindingInfo hop.ReconstructBlindingInfo) (hop.Iterator, error)
 }
 
 // UtxoSweeper defines the sweep functions that contract court requires.
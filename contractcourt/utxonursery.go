@@ -204,6 +204,10 @@ type NurseryConfig struct {
 
 	// Sweep sweeps an input back to the wallet.
 	SweepInput func(input.Input, sweep.Params) (chan sweep.Result, error)
+
+	// Budget is the configuration of the fee budgets we give the sweeper
+	// for the incubated outputs.
+	Budget BudgetConfig
 }
 
 // UtxoNursery is a system dedicated to incubating time-locked outputs created
@@ -828,9 +832,26 @@ func (u *UtxoNursery) sweepMatureOutputs(classHeight uint32,
 		// passed in with disastrous consequences.
 		local := output
 
+		// HTLC outputs on the remote commitment must be swept before
+		// the remote party can claim them with the preimage, while
+		// second-level outputs are ours alone.
+		var budget ltcutil.Amount
+		switch local.WitnessType() {
+		case input.HtlcOfferedRemoteTimeout,
+			input.TaprootHtlcOfferedRemoteTimeout:
+
+			budget = u.cfg.Budget.DeadlineHTLCBudget(local.Amount())
+
+		default:
+			budget = u.cfg.Budget.NoDeadlineHTLCBudget(
+				local.Amount(),
+			)
+		}
+
 		resultChan, err := u.cfg.SweepInput(&local, sweep.Params{
-			Fee:   feePref,
-			Force: true,
+			Fee:    feePref,
+			Force:  true,
+			Budget: budget,
 		})
 		if err != nil {
 			return err
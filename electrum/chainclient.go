@@ -177,7 +177,8 @@ func topoSortBlock(txs []*collectedTx) []*collectedTx {
 
 // ChainClient is an implementation of chain.Interface that uses an Electrum
 // server as its backend. Note that Electrum servers have limitations compared
-// to full nodes - notably they cannot serve full block data.
+// to full nodes - notably they cannot serve full block data, which is fetched
+// from Litecoin P2P peers or an optional REST API instead.
 type ChainClient struct {
 	started int32
 	stopped int32
@@ -185,7 +186,7 @@ type ChainClient struct {
 	client *Client
 
 	// restClient is an optional REST API client for fetching full blocks
-	// from mempool/electrs. If nil, GetBlock will return an error.
+	// from mempool/electrs. If set, it is tried before the P2P peers.
 	restClient *RESTClient
 
 	chainParams *chaincfg.Params
@@ -202,6 +203,19 @@ type ChainClient struct {
 	heightToHashMtx sync.RWMutex
 	heightToHash    map[int32]*chainhash.Hash
 
+	// verifier checks the proof-of-work, linkage and difficulty of the
+	// headers served by the Electrum server.
+	verifier *HeaderVerifier
+
+	// headerStore is an optional persistent store of validated headers.
+	// Heights it covers are served locally instead of being fetched from
+	// the server and kept in the in-memory caches.
+	headerStore *HeaderStore
+
+	// headerStoreSignal wakes the header store syncer when a new tip has
+	// been seen.
+	headerStoreSignal chan struct{}
+
 	// notificationChan is used to send notifications to the wallet.
 	notificationChan chan interface{}
 
@@ -229,6 +243,10 @@ type ChainClient struct {
 	scripthashSub   *ScripthashSubscription
 	scripthashNotif <-chan *SubscribeNotif
 
+	// subscribed is the set of scripthashes added to scripthashSub.
+	subscribedMtx sync.Mutex
+	subscribed    map[string]struct{}
+
 	// scripthashHistory tracks the last known status hash for each
 	// subscribed scripthash. It is seeded at sync start from the wallet's
 	// committed history (see SeedAddressHistory), so an address whose
@@ -243,7 +261,8 @@ type ChainClient struct {
 	// If empty, DNS seeds are used for peer discovery.
 	mwebP2PPeers []string
 
-	// p2pService manages lightweight P2P connections for MWEB queries.
+	// p2pService manages lightweight P2P connections for MWEB queries and
+	// full block downloads.
 	p2pService *mwebp2p.Service
 
 	// workManager dispatches MWEB queries to P2P peers.
@@ -287,12 +306,15 @@ var (
 )
 
 // NewChainClient creates a new Electrum chain client.
-// If restURL is provided, the client will be able to fetch full blocks
-// via the mempool/electrs REST API.
+// If restURL is provided, the client will first try to fetch full blocks
+// via the mempool/electrs REST API before asking P2P peers.
 // dataDir is the directory for storing MWEB data.
 // mwebP2PPeers is an optional list of explicit P2P peers for MWEB sync.
+// headerStore is an optional persistent header store that is kept synced
+// with the server's chain and used to serve header lookups.
 func NewChainClient(client *Client, chainParams *chaincfg.Params,
-	restURL string, dataDir string, mwebP2PPeers []string) *ChainClient {
+	restURL string, dataDir string, mwebP2PPeers []string,
+	headerStore *HeaderStore) *ChainClient {
 
 	var restClient *RESTClient
 	if restURL != "" {
@@ -300,7 +322,7 @@ func NewChainClient(client *Client, chainParams *chaincfg.Params,
 		log.Infof("Electrum REST API enabled: %s", restURL)
 	}
 
-	return &ChainClient{
+	c := &ChainClient{
 		client:            client,
 		restClient:        restClient,
 		chainParams:       chainParams,
@@ -311,10 +333,16 @@ func NewChainClient(client *Client, chainParams *chaincfg.Params,
 		watchedOutpoints:  make(map[wire.OutPoint]ltcutil.Address),
 		historyCache:      make(map[string][]*electrum.GetMempoolResult),
 		scripthashHistory: make(map[string]string),
+		subscribed:        make(map[string]struct{}),
 		dataDir:           dataDir,
 		mwebP2PPeers:      mwebP2PPeers,
+		headerStore:       headerStore,
+		headerStoreSignal: make(chan struct{}, 1),
 		quit:              make(chan struct{}),
 	}
+	c.verifier = NewHeaderVerifier(chainParams, c.headerByHeight)
+
+	return c
 }
 
 // sendNotification sends a notification to the notification channel in a
@@ -371,6 +399,11 @@ func (c *ChainClient) Start() error {
 				err)
 		}
 
+		err = CheckHeaderPoW(blockHeader, c.chainParams)
+		if err != nil {
+			return fmt.Errorf("invalid initial header: %w", err)
+		}
+
 		hash := blockHeader.BlockHash()
 		c.bestBlockMtx.Lock()
 		c.bestBlock = waddrmgr.BlockStamp{
@@ -391,6 +424,13 @@ func (c *ChainClient) Start() error {
 	c.wg.Add(1)
 	go c.notificationHandler(headerChan)
 
+	// Catch the persistent header store up with the server's chain in
+	// the background.
+	if c.headerStore != nil {
+		c.wg.Add(1)
+		go c.headerStoreSyncer()
+	}
+
 	// Initialize scripthash subscription for real-time address monitoring.
 	// This creates a single subscription object that multiplexes all
 	// watched addresses onto one notification channel.
@@ -406,6 +446,18 @@ func (c *ChainClient) Start() error {
 		go c.scripthashHandler()
 	}
 
+	// Connect to Litecoin P2P peers. They serve MWEB data to the syncer
+	// and full blocks to GetBlock.
+	p2pService := mwebp2p.NewService(&mwebp2p.Config{
+		ChainParams:  c.chainParams,
+		ConnectPeers: c.mwebP2PPeers,
+	})
+	if err := p2pService.Start(); err != nil {
+		log.Warnf("Failed to start MWEB P2P service: %v", err)
+	} else {
+		c.p2pService = p2pService
+	}
+
 	// Start MWEB sync in a background goroutine.
 	if c.dataDir != "" {
 		c.wg.Add(1)
@@ -441,6 +493,12 @@ func (c *ChainClient) Stop() {
 	close(c.quit)
 	c.wg.Wait()
 
+	if c.p2pService != nil {
+		if err := c.p2pService.Stop(); err != nil {
+			log.Errorf("Failed to stop MWEB P2P service: %v", err)
+		}
+	}
+
 	close(c.notificationChan)
 }
 
@@ -548,58 +606,103 @@ func (c *ChainClient) GetBestBlock() (*chainhash.Hash, int32, error) {
 	return &hash, c.bestBlock.Height, nil
 }
 
-// GetBlock returns the raw block from the server given its hash.
+// GetBlock returns the block with the given hash.
 //
-// NOTE: Electrum protocol does not support full blocks directly. If a REST
-// API URL was configured (for mempool/electrs), this method will use that
-// to fetch full blocks. Otherwise, it returns an error.
+// NOTE: The Electrum protocol does not support full blocks. If a REST API URL
+// was configured (for mempool/electrs), it is tried first. Otherwise, or if it
+// fails, the block is requested from Litecoin P2P peers. Either way, the block
+// must match a header we know about and the transactions it commits to.
 //
 // NOTE: This is part of the chain.Interface interface.
 func (c *ChainClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
-	// If we have a REST client configured, use it to fetch the block.
-	if c.restClient != nil {
-		ctx, cancel := context.WithTimeout(
-			context.Background(), defaultRequestTimeout,
-		)
-		defer cancel()
+	if c.restClient == nil && c.p2pService == nil {
+		return nil, ErrFullBlocksNotSupported
+	}
+
+	// Only fetch blocks that are part of the header chain, so the served
+	// block can be checked against a header we trust.
+	if _, err := c.GetBlockHeader(hash); err != nil {
+		return nil, fmt.Errorf("unknown block %v: %w", hash, err)
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), defaultRequestTimeout,
+	)
+	defer cancel()
 
+	var restErr error
+	if c.restClient != nil {
 		block, err := c.restClient.GetBlock(ctx, hash)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch block via REST: %w", err)
+		if err == nil {
+			err = mwebp2p.CheckBlock(block, hash, c.chainParams)
+		}
+		if err == nil {
+			return block.MsgBlock(), nil
+		}
+
+		restErr = fmt.Errorf("failed to fetch block via REST: %w", err)
+		if c.p2pService == nil {
+			return nil, restErr
 		}
 
-		return block.MsgBlock(), nil
+		log.Debugf("%v, falling back to P2P peers", restErr)
 	}
 
-	// Electrum servers cannot serve full blocks. This is a fundamental
-	// limitation of the protocol.
-	return nil, ErrFullBlocksNotSupported
+	block, err := c.p2pService.GetBlock(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block via P2P: %w", err)
+	}
+
+	return block.MsgBlock(), nil
 }
 
-// GetTxIndex returns the index of a transaction within a block at the given height.
-// This is needed for constructing proper ShortChannelIDs.
+// GetTxIndex returns the index of a transaction within a block at the given
+// height. This is needed for constructing proper ShortChannelIDs.
 // Returns the TxIndex and the block hash.
-func (c *ChainClient) GetTxIndex(height int64, txid string) (uint32, string, error) {
-	if c.restClient == nil {
-		// Without REST API, we can't determine the TxIndex.
-		// Return 0 as a fallback (will cause validation failures for
-		// channels where the funding tx is not at index 0).
-		return 0, "", nil
+func (c *ChainClient) GetTxIndex(height int64, txid string) (uint32, string,
+	error) {
+
+	if c.restClient != nil {
+		ctx, cancel := context.WithTimeout(
+			context.Background(), defaultRequestTimeout,
+		)
+		defer cancel()
+
+		return c.restClient.GetTxIndexByHeight(ctx, height, txid)
 	}
 
-	ctx, cancel := context.WithTimeout(
-		context.Background(), defaultRequestTimeout,
-	)
-	defer cancel()
+	// Without the REST API, look the transaction up in the full block.
+	blockHash, err := c.GetBlockHash(height)
+	if err != nil {
+		return 0, "", err
+	}
+
+	block, err := c.GetBlock(blockHash)
+	if err != nil {
+		return 0, "", err
+	}
+
+	for i, tx := range block.Transactions {
+		if tx.TxHash().String() == txid {
+			return uint32(i), blockHash.String(), nil
+		}
+	}
 
-	return c.restClient.GetTxIndexByHeight(ctx, height, txid)
+	return 0, "", fmt.Errorf("transaction %s not found in block %v",
+		txid, blockHash)
 }
 
 // GetBlockHash returns the hash of the block at the given height.
 //
 // NOTE: This is part of the chain.Interface interface.
 func (c *ChainClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
-	// Check cache first.
+	// Serve the hash from the header store if it covers this height.
+	if header, ok := c.storedHeader(int32(height)); ok {
+		hash := header.BlockHash()
+		return &hash, nil
+	}
+
+	// Check cache next.
 	c.heightToHashMtx.RLock()
 	if hash, ok := c.heightToHash[int32(height)]; ok {
 		c.heightToHashMtx.RUnlock()
@@ -684,7 +787,14 @@ func (c *ChainClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
 func (c *ChainClient) GetBlockHeader(
 	hash *chainhash.Hash) (*wire.BlockHeader, error) {
 
-	// Check cache first.
+	// Check the header store first, then the cache.
+	if c.headerStore != nil {
+		header, _, err := c.headerStore.FetchHeader(hash)
+		if err == nil {
+			return header, nil
+		}
+	}
+
 	c.headerCacheMtx.RLock()
 	if header, ok := c.headerCache[*hash]; ok {
 		c.headerCacheMtx.RUnlock()
@@ -856,6 +966,18 @@ func (c *ChainClient) FilterBlocks(
 					txHashStr, err)
 				continue
 			}
+
+			// Only accept the match if the server can prove the
+			// transaction is committed to by the block the wallet
+			// is scanning.
+			err = c.verifyTxInclusion(
+				ctx, txHash, blk.Height, &blk.Hash,
+			)
+			if err != nil {
+				log.Warnf("FilterBlocks: rejecting tx %s at "+
+					"height %d: %v", txHashStr, blk.Height, err)
+				continue
+			}
 			uniqueTxs = append(uniqueTxs, tx)
 		}
 
@@ -1211,6 +1333,13 @@ func (c *ChainClient) collectAddressHistory(ctx context.Context,
 			continue
 		}
 
+		err = c.verifyTxInclusion(ctx, txHash, height, blockHash)
+		if err != nil {
+			log.Warnf("Rejecting tx %s at height %d: %v", txHash,
+				height, err)
+			continue
+		}
+
 		log.Debugf("Collected tx %s at height %d for address %s",
 			txHash, height, addr.EncodeAddress())
 
@@ -1591,6 +1720,16 @@ func (c *ChainClient) handleScripthashNotif(notif *SubscribeNotif) {
 				continue
 			}
 
+			err = c.verifyTxInclusion(
+				ctx, txHash, height, blockHash,
+			)
+			if err != nil {
+				log.Warnf("Rejecting tx %s at height %d: %v",
+					txHash, height, err)
+				delivered = false
+				continue
+			}
+
 			rec := &wtxmgr.TxRecord{
 				MsgTx:    *tx,
 				Hash:     *txHash,
@@ -1633,10 +1772,16 @@ func (c *ChainClient) ensureHeadersCached(ctx context.Context, heights []int32)
 		return
 	}
 
-	// Filter out heights that are already cached.
+	// Filter out heights that are already cached or stored.
 	var missingHeights []int32
 	c.heightToHashMtx.RLock()
 	for _, height := range heights {
+		if c.headerStore != nil &&
+			c.headerStore.Contains(uint32(height)) {
+
+			continue
+		}
+
 		if _, ok := c.heightToHash[height]; !ok {
 			missingHeights = append(missingHeights, height)
 		}
@@ -1758,6 +1903,20 @@ func (c *ChainClient) processHeaderBatch(ctx context.Context,
 			}
 		}
 
+		// Check the header's proof-of-work and that its difficulty
+		// follows the retarget rules before we accept it.
+		var prevHeader *wire.BlockHeader
+		if i > 0 {
+			prevHeader = headers[i-1]
+		}
+		err := c.verifier.VerifyHeader(
+			header, currentHeight, prevHeader,
+		)
+		if err != nil {
+			return fmt.Errorf("invalid header at height %d: %w",
+				currentHeight, err)
+		}
+
 		// Cache the header.
 		c.cacheHeader(currentHeight, &currentHash, header)
 
@@ -1821,9 +1980,7 @@ func (c *ChainClient) handleNewHeader(header *SubscribeHeadersResult) {
 
 			// Notify disconnected blocks.
 			for h := prevHeight; h >= newHeight; h-- {
-				c.heightToHashMtx.RLock()
-				oldHash := c.heightToHash[h]
-				c.heightToHashMtx.RUnlock()
+				oldHash := c.knownHashAtHeight(h)
 
 				if oldHash != nil && c.notifyBlocks.Load() {
 					c.sendNotification(chain.BlockDisconnected{
@@ -1910,6 +2067,14 @@ func (c *ChainClient) handleNewHeader(header *SubscribeHeadersResult) {
 		batchEndHeight := height + int32(len(headers)) - 1
 		c.checkAddressesInRange(ctx, height, batchEndHeight)
 	}
+
+	// Let the header store syncer know there's a new tip to catch up to.
+	if c.headerStore != nil {
+		select {
+		case c.headerStoreSignal <- struct{}{}:
+		default:
+		}
+	}
 }
 
 // checkAddressesInRange checks watched addresses for transactions within a
@@ -1973,6 +2138,81 @@ func (c *ChainClient) checkAddressesInRange(ctx context.Context,
 	c.sendFilteredBlocks(sorted)
 }
 
+// headerByHeight returns the header at the given height, consulting the
+// header store and cache before falling back to the Electrum server.
+func (c *ChainClient) headerByHeight(height int32) (*wire.BlockHeader, error) {
+	if header, ok := c.storedHeader(height); ok {
+		return header, nil
+	}
+
+	c.heightToHashMtx.RLock()
+	hash, ok := c.heightToHash[height]
+	c.heightToHashMtx.RUnlock()
+
+	if ok {
+		c.headerCacheMtx.RLock()
+		header, ok := c.headerCache[*hash]
+		c.headerCacheMtx.RUnlock()
+
+		if ok {
+			return header, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), defaultRequestTimeout,
+	)
+	defer cancel()
+
+	header, err := c.client.GetBlockHeader(ctx, uint32(height))
+	if err != nil {
+		return nil, err
+	}
+
+	headerHash := header.BlockHash()
+	c.cacheHeader(height, &headerHash, header)
+
+	return header, nil
+}
+
+// verifyTxInclusion checks that the transaction is committed to by the block
+// at the given height. The header at that height must hash to the expected
+// block hash and pass the proof-of-work, linkage and difficulty checks of the
+// header verifier, unless it comes from the checkpoint-anchored header store.
+// The server's merkle proof for the transaction must then fold into the
+// header's merkle root.
+func (c *ChainClient) verifyTxInclusion(ctx context.Context,
+	txHash *chainhash.Hash, height int32,
+	expectedHash *chainhash.Hash) error {
+
+	// Headers from the store were validated when they were written.
+	header, ok := c.storedHeader(height)
+	if !ok {
+		var err error
+		header, err = c.headerByHeight(height)
+		if err != nil {
+			return fmt.Errorf("unable to fetch header at height "+
+				"%d: %w", height, err)
+		}
+
+		err = c.verifier.VerifyHeader(header, height, nil)
+		if err != nil {
+			return fmt.Errorf("invalid header at height %d: %w",
+				height, err)
+		}
+	}
+
+	headerHash := header.BlockHash()
+	if expectedHash != nil && !headerHash.IsEqual(expectedHash) {
+		return fmt.Errorf("%w: expected %v at height %d, got %v",
+			ErrHeaderMismatch, expectedHash, height, headerHash)
+	}
+
+	_, err := c.client.VerifyTxInclusion(ctx, txHash, uint32(height), header)
+
+	return err
+}
+
 // cacheHeader adds a header to the cache.
 func (c *ChainClient) cacheHeader(height int32, hash *chainhash.Hash,
 	header *wire.BlockHeader) {
@@ -1987,6 +2227,201 @@ func (c *ChainClient) cacheHeader(height int32, hash *chainhash.Hash,
 	c.heightToHashMtx.Unlock()
 }
 
+// storedHeader returns the header at the given height from the header store,
+// if one is configured and covers that height.
+func (c *ChainClient) storedHeader(height int32) (*wire.BlockHeader, bool) {
+	if c.headerStore == nil || height < 0 {
+		return nil, false
+	}
+
+	header, err := c.headerStore.FetchHeaderByHeight(uint32(height))
+	if err != nil {
+		return nil, false
+	}
+
+	return header, true
+}
+
+// knownHashAtHeight returns the hash we know for the given height from the
+// cache or the header store, without asking the server. It returns nil if the
+// height is unknown.
+func (c *ChainClient) knownHashAtHeight(height int32) *chainhash.Hash {
+	c.heightToHashMtx.RLock()
+	hash, ok := c.heightToHash[height]
+	c.heightToHashMtx.RUnlock()
+
+	if ok {
+		return hash
+	}
+
+	if header, ok := c.storedHeader(height); ok {
+		hash := header.BlockHash()
+		return &hash
+	}
+
+	return nil
+}
+
+// headerStoreSyncer keeps the header store in step with the server's chain.
+// It catches up at startup and again whenever handleNewHeader signals a new
+// tip.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *ChainClient) headerStoreSyncer() {
+	defer c.wg.Done()
+
+	for {
+		if err := c.syncHeaderStore(); err != nil {
+			log.Errorf("Unable to sync header store: %v", err)
+		}
+
+		select {
+		case <-c.headerStoreSignal:
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// syncHeaderStore writes the headers between the header store's tip and our
+// best block to the store in batches. If the server's chain no longer builds
+// on the stored tip, the tip is rolled back until it does.
+func (c *ChainClient) syncHeaderStore() error {
+	for {
+		select {
+		case <-c.quit:
+			return nil
+		default:
+		}
+
+		c.bestBlockMtx.RLock()
+		bestHeight := c.bestBlock.Height
+		c.bestBlockMtx.RUnlock()
+
+		next := c.headerStore.BaseHeight()
+		tip, tipHeight, err := c.headerStore.ChainTip()
+		switch {
+		case err == nil:
+			next = tipHeight + 1
+
+		case !errors.Is(err, ErrHeaderNotFound):
+			return err
+		}
+
+		if int32(next) > bestHeight {
+			return nil
+		}
+
+		count := uint32(bestHeight) - next + 1
+		if count > batchSize {
+			count = batchSize
+		}
+
+		// The headers up to the store's anchor checkpoint can only be
+		// written together with it, so they're fetched all at once.
+		anchorHeight := c.headerStore.AnchorHeight()
+		if next <= anchorHeight {
+			if uint32(bestHeight) < anchorHeight {
+				return nil
+			}
+
+			count = anchorHeight - next + 1
+		}
+
+		headers, err := c.fetchHeaderBatch(next, count)
+		if err != nil {
+			return err
+		}
+
+		if len(headers) == 0 {
+			return nil
+		}
+
+		// If the first header doesn't build on our stored tip, the tip
+		// was reorged out. Drop it and try again one height lower.
+		if tip != nil {
+			tipHash := tip.BlockHash()
+			if !headers[0].PrevBlock.IsEqual(&tipHash) {
+				log.Warnf("Header store tip %v at height %d "+
+					"is no longer on the main chain, "+
+					"rolling back", tipHash, tipHeight)
+
+				_, err := c.headerStore.RollbackTo(
+					tipHeight - 1,
+				)
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		err = c.headerStore.WriteHeaders(next, headers)
+		if err != nil {
+			return err
+		}
+
+		endHeight := next + uint32(len(headers)) - 1
+		log.Debugf("Header store synced to height %d", endHeight)
+
+		c.pruneHeaderCache(int32(next), int32(endHeight))
+	}
+}
+
+// fetchHeaderBatch fetches count headers starting at the given height from
+// the server, requesting at most batchSize headers at a time. Fewer headers
+// are returned if the server doesn't have all of them.
+func (c *ChainClient) fetchHeaderBatch(startHeight,
+	count uint32) ([]*wire.BlockHeader, error) {
+
+	headers := make([]*wire.BlockHeader, 0, count)
+	for uint32(len(headers)) < count {
+		height := startHeight + uint32(len(headers))
+		n := count - uint32(len(headers))
+		if n > batchSize {
+			n = batchSize
+		}
+
+		ctx, cancel := context.WithTimeout(
+			context.Background(), defaultRequestTimeout,
+		)
+		batch, err := c.client.GetBlockHeaders(ctx, height, n)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch headers at "+
+				"height %d: %w", height, err)
+		}
+
+		headers = append(headers, batch...)
+		if uint32(len(batch)) < n {
+			break
+		}
+	}
+
+	return headers, nil
+}
+
+// pruneHeaderCache drops the cached headers in the given height range, which
+// are now served by the header store.
+func (c *ChainClient) pruneHeaderCache(startHeight, endHeight int32) {
+	c.heightToHashMtx.Lock()
+	defer c.heightToHashMtx.Unlock()
+
+	c.headerCacheMtx.Lock()
+	defer c.headerCacheMtx.Unlock()
+
+	for height := startHeight; height <= endHeight; height++ {
+		hash, ok := c.heightToHash[height]
+		if !ok {
+			continue
+		}
+
+		delete(c.headerCache, *hash)
+		delete(c.heightToHash, height)
+	}
+}
+
 // SeedAddressHistory derives each watched scripthash's baseline status from the
 // wallet's committed per-address history, so an address with no new activity
 // short-circuits on the next subscription without re-fetching from the server.
@@ -2052,6 +2487,10 @@ func (c *ChainClient) subscribeAddresses(ctx context.Context,
 			continue
 		}
 
+		c.subscribedMtx.Lock()
+		c.subscribed[scripthash] = struct{}{}
+		c.subscribedMtx.Unlock()
+
 		log.Tracef("Subscribed to scripthash for address %s",
 			addr.EncodeAddress())
 	}
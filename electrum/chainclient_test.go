@@ -114,7 +114,7 @@ func TestNewChainClient(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -123,7 +123,9 @@ func TestNewChainClient(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	require.NotNil(t, chainClient)
 	require.NotNil(t, chainClient.client)
@@ -138,7 +140,7 @@ func TestChainClientBackEnd(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -147,7 +149,9 @@ func TestChainClientBackEnd(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	require.Equal(t, "electrum", chainClient.BackEnd())
 }
@@ -157,7 +161,7 @@ func TestChainClientGetBlockNotSupported(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -166,7 +170,9 @@ func TestChainClientGetBlockNotSupported(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	hash := &chainhash.Hash{}
 	block, err := chainClient.GetBlock(hash)
@@ -181,7 +187,7 @@ func TestChainClientNotifications(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -190,7 +196,9 @@ func TestChainClientNotifications(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	notifChan := chainClient.Notifications()
 	require.NotNil(t, notifChan)
@@ -201,7 +209,7 @@ func TestChainClientTestMempoolAccept(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -210,7 +218,9 @@ func TestChainClientTestMempoolAccept(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	tx := wire.NewMsgTx(wire.TxVersion)
 	results, err := chainClient.TestMempoolAccept([]*wire.MsgTx{tx}, 0.0)
@@ -225,7 +235,7 @@ func TestChainClientMapRPCErr(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -234,7 +244,9 @@ func TestChainClientMapRPCErr(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	testErr := ErrNotConnected
 	mappedErr := chainClient.MapRPCErr(testErr)
@@ -247,7 +259,7 @@ func TestChainClientNotifyBlocks(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -256,7 +268,9 @@ func TestChainClientNotifyBlocks(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	err := chainClient.NotifyBlocks()
 	require.NoError(t, err)
@@ -268,7 +282,7 @@ func TestChainClientNotifyReceived(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -277,7 +291,9 @@ func TestChainClientNotifyReceived(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	// NotifyReceived only monitors P2WPKH addresses (other types are
 	// either unsupported by Electrum or handled by mwebsync).
@@ -393,7 +409,7 @@ func TestChainClientIsCurrent(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -402,7 +418,9 @@ func TestChainClientIsCurrent(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	// Without a live connection, IsCurrent() should return false since it
 	// cannot fetch the best block from the network. This matches the
@@ -416,7 +434,7 @@ func TestChainClientCacheHeader(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -425,7 +443,9 @@ func TestChainClientCacheHeader(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	// Create a test header.
 	header := &wire.BlockHeader{
@@ -456,12 +476,55 @@ func TestChainClientCacheHeader(t *testing.T) {
 	require.Equal(t, &hash, cachedHash)
 }
 
+// TestChainClientVerifyTxInclusionHeader tests that the header a merkle proof
+// is checked against must connect to its parent, not only carry valid
+// proof-of-work.
+func TestChainClientVerifyTxInclusionHeader(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ClientConfig{
+		Servers:           []string{"localhost:50001"},
+		UseSSL:            false,
+		ReconnectInterval: 10 * time.Second,
+		RequestTimeout:    30 * time.Second,
+		PingInterval:      60 * time.Second,
+		MaxRetries:        3,
+	}
+	client := NewClient(cfg)
+
+	chainClient := NewChainClient(
+		client, &chaincfg.RegressionNetParams, "", "", nil, nil,
+	)
+
+	headers := testHeaderChain(t, 5)
+	for i, header := range headers {
+		hash := header.BlockHash()
+		chainClient.cacheHeader(int32(i), &hash, header)
+	}
+
+	// A header that satisfies its proof-of-work but doesn't build on
+	// our chain can't be used to prove a transaction.
+	orphan := *headers[5]
+	orphan.PrevBlock = chainhash.Hash{0x01}
+	mineTestHeader(t, &orphan)
+	err := CheckHeaderPoW(&orphan, &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	orphanHash := orphan.BlockHash()
+	chainClient.cacheHeader(6, &orphanHash, &orphan)
+
+	err = chainClient.verifyTxInclusion(
+		context.Background(), &chainhash.Hash{0x02}, 6, &orphanHash,
+	)
+	require.ErrorIs(t, err, ErrHeaderNotConnected)
+}
+
 // TestChainClientGetUtxo tests the GetUtxo method.
 func TestChainClientGetUtxo(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    1 * time.Second,
@@ -470,7 +533,9 @@ func TestChainClientGetUtxo(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	// Create a test outpoint and pkScript.
 	testHash := chainhash.Hash{0x01, 0x02, 0x03}
@@ -494,7 +559,7 @@ func TestElectrumUtxoSourceInterface(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -503,7 +568,9 @@ func TestElectrumUtxoSourceInterface(t *testing.T) {
 	}
 	client := NewClient(cfg)
 
-	chainClient := NewChainClient(client, &chaincfg.MainNetParams, "", "", nil)
+	chainClient := NewChainClient(
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
+	)
 
 	// Define the interface locally to test without importing btcwallet.
 	type UtxoSource interface {
@@ -598,7 +665,7 @@ func TestSendFilteredBlocks(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -608,7 +675,7 @@ func TestSendFilteredBlocks(t *testing.T) {
 	client := NewClient(cfg)
 
 	chainClient := NewChainClient(
-		client, &chaincfg.MainNetParams, "", "", nil,
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
 	)
 
 	// Create transactions in two different blocks.
@@ -670,7 +737,7 @@ func TestSendFilteredBlocksEmpty(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -680,7 +747,7 @@ func TestSendFilteredBlocksEmpty(t *testing.T) {
 	client := NewClient(cfg)
 
 	chainClient := NewChainClient(
-		client, &chaincfg.MainNetParams, "", "", nil,
+		client, &chaincfg.MainNetParams, "", "", nil, nil,
 	)
 
 	chainClient.sendFilteredBlocks(nil)
@@ -766,7 +833,7 @@ var _ mwebSyncer = (*mockMwebSyncer)(nil)
 // newTestChainClient creates a minimal ChainClient for MWEB tests.
 func newTestChainClient() *ChainClient {
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -774,7 +841,7 @@ func newTestChainClient() *ChainClient {
 		MaxRetries:        3,
 	}
 	return NewChainClient(
-		NewClient(cfg), &chaincfg.MainNetParams, "", "", nil,
+		NewClient(cfg), &chaincfg.MainNetParams, "", "", nil, nil,
 	)
 }
 
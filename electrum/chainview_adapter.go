@@ -3,6 +3,7 @@ package electrum
 import (
 	"context"
 
+	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/wire"
 	"github.com/ltcsuite/lnd/routing/chainview"
@@ -13,14 +14,23 @@ import (
 // the electrum package and the chainview package, avoiding import cycles.
 type ChainViewAdapter struct {
 	client *Client
+
+	// verifier checks the headers that merkle proofs are verified
+	// against.
+	verifier *HeaderVerifier
 }
 
 // NewChainViewAdapter creates a new ChainViewAdapter wrapping the given
 // Electrum client.
-func NewChainViewAdapter(client *Client) *ChainViewAdapter {
-	return &ChainViewAdapter{
+func NewChainViewAdapter(client *Client,
+	chainParams *chaincfg.Params) *ChainViewAdapter {
+
+	a := &ChainViewAdapter{
 		client: client,
 	}
+	a.verifier = NewHeaderVerifier(chainParams, a.fetchHeader)
+
+	return a
 }
 
 // Compile time check to ensure ChainViewAdapter implements the
@@ -89,6 +99,16 @@ func (a *ChainViewAdapter) GetBlockHeader(ctx context.Context,
 	return a.client.GetBlockHeader(ctx, height)
 }
 
+// GetBlockHeaders retrieves count consecutive block headers starting at the
+// given height.
+//
+// NOTE: This is part of the chainview.ElectrumClient interface.
+func (a *ChainViewAdapter) GetBlockHeaders(ctx context.Context, startHeight,
+	count uint32) ([]*wire.BlockHeader, error) {
+
+	return a.client.GetBlockHeaders(ctx, startHeight, count)
+}
+
 // GetHistory retrieves the transaction history for a scripthash.
 //
 // NOTE: This is part of the chainview.ElectrumClient interface.
@@ -119,3 +139,38 @@ func (a *ChainViewAdapter) GetTransactionMsgTx(ctx context.Context,
 
 	return a.client.GetTransactionMsgTx(ctx, txHash)
 }
+
+// VerifyTxInclusion checks that the transaction is committed to by a valid
+// block header at the given height, using the server's merkle proof.
+//
+// NOTE: This is part of the chainview.ElectrumClient interface.
+func (a *ChainViewAdapter) VerifyTxInclusion(ctx context.Context,
+	txHash *chainhash.Hash, height uint32) error {
+
+	header, err := a.client.GetBlockHeader(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	err = a.verifier.VerifyHeader(header, int32(height), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.VerifyTxInclusion(ctx, txHash, height, header)
+
+	return err
+}
+
+// fetchHeader retrieves the block header at the given height for use by the
+// header verifier.
+func (a *ChainViewAdapter) fetchHeader(height int32) (*wire.BlockHeader,
+	error) {
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), defaultRequestTimeout,
+	)
+	defer cancel()
+
+	return a.client.GetBlockHeader(ctx, uint32(height))
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/lnd/routing/chainview"
 	"github.com/stretchr/testify/require"
@@ -26,7 +27,7 @@ func TestNewChainViewAdapter(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -35,7 +36,7 @@ func TestNewChainViewAdapter(t *testing.T) {
 	}
 
 	client := NewClient(cfg)
-	adapter := NewChainViewAdapter(client)
+	adapter := NewChainViewAdapter(client, &chaincfg.MainNetParams)
 
 	require.NotNil(t, adapter)
 	require.NotNil(t, adapter.client)
@@ -47,7 +48,7 @@ func TestChainViewAdapterIsConnected(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -56,7 +57,7 @@ func TestChainViewAdapterIsConnected(t *testing.T) {
 	}
 
 	client := NewClient(cfg)
-	adapter := NewChainViewAdapter(client)
+	adapter := NewChainViewAdapter(client, &chaincfg.MainNetParams)
 
 	// Client should not be connected since we haven't started it.
 	require.False(t, adapter.IsConnected())
@@ -68,7 +69,7 @@ func TestChainViewAdapterGetBlockHeaderNotConnected(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    1 * time.Second,
@@ -77,7 +78,7 @@ func TestChainViewAdapterGetBlockHeaderNotConnected(t *testing.T) {
 	}
 
 	client := NewClient(cfg)
-	adapter := NewChainViewAdapter(client)
+	adapter := NewChainViewAdapter(client, &chaincfg.MainNetParams)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -92,7 +93,7 @@ func TestChainViewAdapterGetHistoryNotConnected(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    1 * time.Second,
@@ -101,7 +102,7 @@ func TestChainViewAdapterGetHistoryNotConnected(t *testing.T) {
 	}
 
 	client := NewClient(cfg)
-	adapter := NewChainViewAdapter(client)
+	adapter := NewChainViewAdapter(client, &chaincfg.MainNetParams)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -116,7 +117,7 @@ func TestChainViewAdapterGetTransactionMsgTxNotConnected(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    1 * time.Second,
@@ -125,7 +126,7 @@ func TestChainViewAdapterGetTransactionMsgTxNotConnected(t *testing.T) {
 	}
 
 	client := NewClient(cfg)
-	adapter := NewChainViewAdapter(client)
+	adapter := NewChainViewAdapter(client, &chaincfg.MainNetParams)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -141,7 +142,7 @@ func TestChainViewAdapterSubscribeHeadersNotConnected(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    1 * time.Second,
@@ -150,7 +151,7 @@ func TestChainViewAdapterSubscribeHeadersNotConnected(t *testing.T) {
 	}
 
 	client := NewClient(cfg)
-	adapter := NewChainViewAdapter(client)
+	adapter := NewChainViewAdapter(client, &chaincfg.MainNetParams)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
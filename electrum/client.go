@@ -15,6 +15,7 @@ import (
 
 	"github.com/checksum0/go-electrum/electrum"
 	"github.com/ltcsuite/lnd/lncfg"
+	"github.com/ltcsuite/lnd/subscribe"
 )
 
 var (
@@ -32,8 +33,10 @@ var (
 
 // ClientConfig holds the configuration for the Electrum client.
 type ClientConfig struct {
-	// Server is the host:port of the Electrum server.
-	Server string
+	// Servers is the list of host:port addresses of the Electrum servers
+	// to use. Requests go to the healthiest connected server and fail
+	// over to the others.
+	Servers []string
 
 	// RESTURL is the optional URL for the mempool/electrs REST API.
 	// If provided, this will be used to fetch full blocks and other data
@@ -60,13 +63,19 @@ type ClientConfig struct {
 
 	// MaxRetries is the maximum number of retries for failed requests.
 	MaxRetries int
+
+	// Quorum is the number of servers that must agree on the chain tip
+	// and on the status of recently queried scripthashes. The servers
+	// are cross-checked every PingInterval. Values below two disable the
+	// cross-checking.
+	Quorum int
 }
 
 // NewClientConfigFromLncfg creates a ClientConfig from the lncfg.Electrum
 // configuration.
 func NewClientConfigFromLncfg(cfg *lncfg.Electrum) *ClientConfig {
 	return &ClientConfig{
-		Server:            cfg.Server,
+		Servers:           cfg.Servers,
 		RESTURL:           cfg.RESTURL,
 		UseSSL:            cfg.UseSSL,
 		TLSCertPath:       cfg.TLSCertPath,
@@ -75,30 +84,43 @@ func NewClientConfigFromLncfg(cfg *lncfg.Electrum) *ClientConfig {
 		RequestTimeout:    cfg.RequestTimeout,
 		PingInterval:      cfg.PingInterval,
 		MaxRetries:        cfg.MaxRetries,
+		Quorum:            cfg.Quorum,
 	}
 }
 
 // Client is a wrapper around the go-electrum client that provides connection
 // management, automatic reconnection, and integration with LND's patterns.
+// It keeps connections to a pool of servers and sends each request to the
+// healthiest of them.
 type Client struct {
 	cfg *ClientConfig
 
-	// client is the underlying electrum client from the go-electrum
-	// library. Access must be synchronized via clientMu.
-	client   *electrum.Client
-	clientMu sync.RWMutex
-
-	// connected indicates whether the client is currently connected.
-	connected atomic.Bool
+	// servers is the pool of Electrum servers, in the configured order.
+	servers []*server
 
 	// started indicates whether the client has been started.
 	started atomic.Bool
 
-	// protocolVersion stores the negotiated protocol version.
-	protocolVersion string
+	// watched holds the scripthashes that are cross-checked in quorum
+	// mode, oldest first.
+	watched    []string
+	watchedMtx sync.Mutex
+
+	// quorumErr is the outcome of the latest quorum check.
+	quorumErr error
+	quorumMtx sync.RWMutex
+
+	// connEvents dispatches a ConnectionEvent whenever a server is
+	// connected or disconnected.
+	connEvents *subscribe.Server
 
-	// serverVersion stores the server's software version string.
-	serverVersion string
+	// subscribeHeaders and subscribeScripthash open the header and
+	// scripthash subscriptions on a single server. They are only replaced
+	// in tests.
+	subscribeHeaders func(context.Context,
+		*server) (<-chan *electrum.SubscribeHeadersResult, error)
+	subscribeScripthash func(*server) (scripthashAdder,
+		<-chan *electrum.SubscribeNotif, error)
 
 	wg   sync.WaitGroup
 	quit chan struct{}
@@ -106,30 +128,45 @@ type Client struct {
 
 // NewClient creates a new Electrum client with the given configuration.
 func NewClient(cfg *ClientConfig) *Client {
+	servers := make([]*server, 0, len(cfg.Servers))
+	for _, addr := range cfg.Servers {
+		servers = append(servers, newServer(addr))
+	}
+
 	return &Client{
-		cfg:  cfg,
-		quit: make(chan struct{}),
+		cfg:                 cfg,
+		servers:             servers,
+		connEvents:          subscribe.NewServer(),
+		subscribeHeaders:    subscribeServerHeaders,
+		subscribeScripthash: subscribeServerScripthash,
+		quit:                make(chan struct{}),
 	}
 }
 
-// Start initializes the client and establishes a connection to the Electrum
-// server. It also starts background goroutines for connection management.
+// Start initializes the client and establishes connections to the Electrum
+// servers. It also starts background goroutines for connection management.
 func (c *Client) Start() error {
 	if c.started.Swap(true) {
 		return nil
 	}
 
-	log.Infof("Starting Electrum client, server=%s, ssl=%v",
-		c.cfg.Server, c.cfg.UseSSL)
+	log.Infof("Starting Electrum client, servers=%v, ssl=%v, quorum=%d",
+		c.cfg.Servers, c.cfg.UseSSL, c.cfg.Quorum)
 
-	// Attempt initial connection.
-	if err := c.connect(); err != nil {
-		log.Warnf("Initial connection to Electrum server failed: %v",
-			err)
+	if err := c.connEvents.Start(); err != nil {
+		return err
+	}
+
+	// Attempt initial connections.
+	for _, s := range c.servers {
+		if err := c.connect(s); err != nil {
+			log.Warnf("Initial connection to Electrum server %s "+
+				"failed: %v", s.addr, err)
 
-		// Start reconnection loop in background rather than failing
-		// immediately. This allows LND to start even if the Electrum
-		// server is temporarily unavailable.
+			// Leave it to the reconnection loop rather than
+			// failing immediately. This allows LND to start even
+			// if the Electrum servers are temporarily unavailable.
+		}
 	}
 
 	// Start the connection manager goroutine.
@@ -139,7 +176,8 @@ func (c *Client) Start() error {
 	return nil
 }
 
-// Stop shuts down the client and closes the connection to the Electrum server.
+// Stop shuts down the client and closes the connections to the Electrum
+// servers.
 func (c *Client) Stop() error {
 	if !c.started.Load() {
 		return nil
@@ -150,21 +188,20 @@ func (c *Client) Stop() error {
 	close(c.quit)
 	c.wg.Wait()
 
-	c.disconnect()
+	for _, s := range c.servers {
+		c.disconnect(s)
+	}
 
-	return nil
+	return c.connEvents.Stop()
 }
 
-// connect establishes a connection to the Electrum server.
-func (c *Client) connect() error {
-	c.clientMu.Lock()
-	defer c.clientMu.Unlock()
+// connect establishes a connection to the given Electrum server.
+func (c *Client) connect(s *server) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
 
 	// Close any existing connection.
-	if c.client != nil {
-		c.client.Shutdown()
-		c.client = nil
-	}
+	c.closeConn(s)
 
 	ctx, cancel := context.WithTimeout(
 		context.Background(), c.cfg.RequestTimeout,
@@ -177,9 +214,9 @@ func (c *Client) connect() error {
 	)
 
 	if c.cfg.UseSSL {
-		client, err = c.connectSSL(ctx)
+		client, err = c.connectSSL(ctx, s.addr)
 	} else {
-		client, err = electrum.NewClientTCP(ctx, c.cfg.Server)
+		client, err = electrum.NewClientTCP(ctx, s.addr)
 	}
 
 	if err != nil {
@@ -194,19 +231,36 @@ func (c *Client) connect() error {
 			err)
 	}
 
-	c.client = client
-	c.serverVersion = serverVer
-	c.protocolVersion = protoVer
-	c.connected.Store(true)
+	s.client = client
+	s.connQuit = make(chan struct{})
+	s.serverVersion = serverVer
+	s.protocolVersion = protoVer
+	s.failures = 0
+	c.setConnected(s, true, nil)
 
-	log.Infof("Connected to Electrum server: version=%s, protocol=%s",
-		serverVer, protoVer)
+	log.Infof("Connected to Electrum server %s: version=%s, protocol=%s",
+		s.addr, serverVer, protoVer)
+
+	// Follow the server's chain tip so it can be compared with the
+	// other servers. The initial tip is delivered on the channel too.
+	headers, err := client.SubscribeHeaders(context.Background())
+	if err != nil {
+		log.Warnf("Unable to subscribe to headers of Electrum "+
+			"server %s: %v", s.addr, err)
+
+		return nil
+	}
+
+	c.wg.Add(1)
+	go c.trackTip(s, headers, s.connQuit)
 
 	return nil
 }
 
-// connectSSL establishes an SSL/TLS connection to the Electrum server.
-func (c *Client) connectSSL(ctx context.Context) (*electrum.Client, error) {
+// connectSSL establishes an SSL/TLS connection to the given Electrum server.
+func (c *Client) connectSSL(ctx context.Context,
+	addr string) (*electrum.Client, error) {
+
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: c.cfg.TLSSkipVerify, //nolint:gosec
 	}
@@ -227,23 +281,34 @@ func (c *Client) connectSSL(ctx context.Context) (*electrum.Client, error) {
 		tlsConfig.RootCAs = certPool
 	}
 
-	return electrum.NewClientSSL(ctx, c.cfg.Server, tlsConfig)
+	return electrum.NewClientSSL(ctx, addr, tlsConfig)
 }
 
-// disconnect closes the connection to the Electrum server.
-func (c *Client) disconnect() {
-	c.clientMu.Lock()
-	defer c.clientMu.Unlock()
+// disconnect closes the connection to the given Electrum server.
+func (c *Client) disconnect(s *server) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	c.closeConn(s)
+}
 
-	if c.client != nil {
-		c.client.Shutdown()
-		c.client = nil
+// closeConn tears down the connection to a server. The caller must hold the
+// server's mutex.
+func (c *Client) closeConn(s *server) {
+	if s.client != nil {
+		s.client.Shutdown()
+		s.client = nil
+	}
+	if s.connQuit != nil {
+		close(s.connQuit)
+		s.connQuit = nil
 	}
 
-	c.connected.Store(false)
+	c.setConnected(s, false, nil)
 }
 
-// connectionManager handles automatic reconnection and keep-alive pings.
+// connectionManager handles automatic reconnection and keep-alive pings, and
+// cross-checks the servers in quorum mode.
 func (c *Client) connectionManager() {
 	defer c.wg.Done()
 
@@ -259,35 +324,47 @@ func (c *Client) connectionManager() {
 			return
 
 		case <-reconnectTicker.C:
-			if !c.connected.Load() {
-				log.Debug("Attempting to reconnect to " +
-					"Electrum server")
+			for _, s := range c.servers {
+				if s.connected.Load() {
+					continue
+				}
 
-				if err := c.connect(); err != nil {
-					log.Warnf("Reconnection failed: %v", err)
+				log.Debugf("Attempting to reconnect to "+
+					"Electrum server %s", s.addr)
+
+				if err := c.connect(s); err != nil {
+					log.Warnf("Reconnection to %s failed: "+
+						"%v", s.addr, err)
 				}
 			}
 
 		case <-pingTicker.C:
-			if c.connected.Load() {
-				if err := c.ping(); err != nil {
-					log.Warnf("Ping failed, marking "+
-						"disconnected: %v", err)
-					c.connected.Store(false)
+			for _, s := range c.servers {
+				if !s.connected.Load() {
+					continue
+				}
+
+				if err := c.ping(s); err != nil {
+					log.Warnf("Ping to %s failed, marking "+
+						"disconnected: %v", s.addr, err)
+					s.recordFailure()
+					c.setConnected(s, false, err)
 				}
 			}
+
+			if c.cfg.Quorum > 1 {
+				c.checkQuorum()
+			}
 		}
 	}
 }
 
-// ping sends a ping to the server to keep the connection alive.
-func (c *Client) ping() error {
-	c.clientMu.RLock()
-	client := c.client
-	c.clientMu.RUnlock()
-
-	if client == nil {
-		return ErrNotConnected
+// ping sends a ping to the given server to keep the connection alive and
+// measure its latency.
+func (c *Client) ping(s *server) error {
+	client, err := s.getClient()
+	if err != nil {
+		return err
 	}
 
 	ctx, cancel := context.WithTimeout(
@@ -295,43 +372,73 @@ func (c *Client) ping() error {
 	)
 	defer cancel()
 
-	return client.Ping(ctx)
+	start := time.Now()
+	if err := client.Ping(ctx); err != nil {
+		return err
+	}
+	s.recordSuccess(time.Since(start))
+
+	return nil
 }
 
-// IsConnected returns true if the client is currently connected.
+// IsConnected returns true if the client is currently connected to at least
+// one server.
 func (c *Client) IsConnected() bool {
-	return c.connected.Load()
+	for _, s := range c.servers {
+		if s.connected.Load() {
+			return true
+		}
+	}
+
+	return false
 }
 
-// ServerVersion returns the server's software version string.
+// ServerVersion returns the software version string of the server currently
+// preferred for requests.
 func (c *Client) ServerVersion() string {
-	return c.serverVersion
+	s := pickServer(c.servers, nil)
+	if s == nil {
+		return ""
+	}
+
+	serverVer, _ := s.versions()
+
+	return serverVer
 }
 
-// ProtocolVersion returns the negotiated protocol version.
+// ProtocolVersion returns the protocol version negotiated with the server
+// currently preferred for requests.
 func (c *Client) ProtocolVersion() string {
-	return c.protocolVersion
+	s := pickServer(c.servers, nil)
+	if s == nil {
+		return ""
+	}
+
+	_, protoVer := s.versions()
+
+	return protoVer
 }
 
-// getClient returns the underlying client with proper locking. Returns an
-// error if not connected.
+// getClient returns the connection to the healthiest connected server.
+// Returns an error if no server is connected.
 func (c *Client) getClient() (*electrum.Client, error) {
-	c.clientMu.RLock()
-	defer c.clientMu.RUnlock()
-
-	if c.client == nil || !c.connected.Load() {
+	s := pickServer(c.servers, nil)
+	if s == nil {
 		return nil, ErrNotConnected
 	}
 
-	return c.client, nil
+	return s.getClient()
 }
 
-// withRetry executes the given function with retry logic.
+// withRetry executes the given function with retry logic. Each attempt goes
+// to the healthiest connected server that hasn't been tried yet, so requests
+// fail over to the other servers of the pool.
 func (c *Client) withRetry(ctx context.Context,
 	fn func(context.Context, *electrum.Client) error) error {
 
 	var lastErr error
 
+	tried := make(map[*server]struct{}, len(c.servers))
 	for i := 0; i <= c.cfg.MaxRetries; i++ {
 		select {
 		case <-ctx.Done():
@@ -341,7 +448,21 @@ func (c *Client) withRetry(ctx context.Context,
 		default:
 		}
 
-		client, err := c.getClient()
+		// Once every server has had a go, start over with the
+		// healthiest one.
+		s := pickServer(c.servers, tried)
+		if s == nil && len(tried) > 0 {
+			tried = make(map[*server]struct{}, len(c.servers))
+			s = pickServer(c.servers, tried)
+		}
+
+		var (
+			client *electrum.Client
+			err    = ErrNotConnected
+		)
+		if s != nil {
+			client, err = s.getClient()
+		}
 		if err != nil {
 			lastErr = err
 
@@ -356,22 +477,34 @@ func (c *Client) withRetry(ctx context.Context,
 
 			continue
 		}
+		tried[s] = struct{}{}
 
+		start := time.Now()
 		reqCtx, cancel := context.WithTimeout(ctx, c.cfg.RequestTimeout)
 		err = fn(reqCtx, client)
 		cancel()
 
 		if err == nil {
+			s.recordSuccess(time.Since(start))
 			return nil
 		}
 
 		lastErr = err
-		log.Debugf("Request failed (attempt %d/%d): %v",
-			i+1, c.cfg.MaxRetries+1, err)
+		log.Debugf("Request to %s failed (attempt %d/%d): %v",
+			s.addr, i+1, c.cfg.MaxRetries+1, err)
+
+		// Connection errors and timeouts count against the server's
+		// health, while other errors are just answers we don't like
+		// and are retried elsewhere.
+		switch {
+		case isConnectionError(err):
+			s.recordFailure()
+			c.setConnected(s, false, err)
+
+		case errors.Is(err, context.DeadlineExceeded) &&
+			ctx.Err() == nil:
 
-		// Check if this looks like a connection error.
-		if isConnectionError(err) {
-			c.connected.Store(false)
+			s.recordFailure()
 		}
 	}
 
@@ -88,7 +88,7 @@ func newTestFeeEstimator(cfg *FeeEstimatorConfig) *testFeeEstimator {
 
 	// Create a real client config (won't actually connect).
 	clientCfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -110,7 +110,7 @@ func TestNewFeeEstimator(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -195,7 +195,7 @@ func TestFeeEstimatorRelayFeePerKW(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -216,7 +216,7 @@ func TestFeeEstimatorEstimateFeePerKWFallback(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    1 * time.Second,
@@ -245,7 +245,7 @@ func TestFeeEstimatorCaching(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    30 * time.Second,
@@ -288,7 +288,7 @@ func TestFeeEstimatorStartStop(t *testing.T) {
 	t.Parallel()
 
 	cfg := &ClientConfig{
-		Server:            "localhost:50001",
+		Servers:           []string{"localhost:50001"},
 		UseSSL:            false,
 		ReconnectInterval: 10 * time.Second,
 		RequestTimeout:    1 * time.Second,
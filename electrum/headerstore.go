@@ -0,0 +1,644 @@
+package electrum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/lnd/kvdb"
+)
+
+const (
+	// headerStoreFileName is the name of the database file that holds the
+	// Electrum backend's block headers.
+	headerStoreFileName = "headers.db"
+
+	// medianTimeBlocks is the number of previous headers used to compute
+	// the median time a new header's timestamp must exceed.
+	medianTimeBlocks = 11
+)
+
+var (
+	// headerBucket maps a big-endian height to the serialized header at
+	// that height on the main chain.
+	headerBucket = []byte("electrum-headers")
+
+	// hashIndexBucket maps a block hash to the big-endian height of the
+	// header in headerBucket.
+	hashIndexBucket = []byte("electrum-header-index")
+
+	// headerMetaBucket holds the base and tip heights of the store.
+	headerMetaBucket = []byte("electrum-header-meta")
+
+	// baseHeightKey is the key under which the height of the first stored
+	// header is kept.
+	baseHeightKey = []byte("base-height")
+
+	// tipHeightKey is the key under which the height of the last stored
+	// header is kept.
+	tipHeightKey = []byte("tip-height")
+
+	// ErrHeaderNotFound is returned when a header is not in the store.
+	ErrHeaderNotFound = errors.New("header not found in store")
+
+	// ErrHeaderStoreGap is returned when headers are written that don't
+	// directly extend the store's tip.
+	ErrHeaderStoreGap = errors.New("headers do not extend store tip")
+
+	// ErrCheckpointMismatch is returned when a header at a checkpoint
+	// height doesn't match the checkpoint hash.
+	ErrCheckpointMismatch = errors.New("header does not match checkpoint")
+
+	// ErrUnanchoredHeaders is returned when headers below the anchor
+	// checkpoint are written without the checkpoint they are tied to.
+	ErrUnanchoredHeaders = errors.New("headers not tied to anchor " +
+		"checkpoint")
+
+	// ErrRollbackBelowAnchor is returned when the store is asked to roll
+	// back below its anchor checkpoint.
+	ErrRollbackBelowAnchor = errors.New("cannot roll back below anchor " +
+		"checkpoint")
+)
+
+// HeaderStore is a persistent, checkpointed store of the main chain's block
+// headers. Headers are validated for linkage, proof-of-work and difficulty on
+// insert, so anything read back from the store can be trusted without asking
+// the Electrum server again.
+//
+// On networks with checkpoints the store doesn't start at genesis. Instead it
+// starts one retarget window before the period of the latest checkpoint, and
+// the headers up to that checkpoint are anchored by its hash. They can only be
+// written in a single batch that reaches the checkpoint, and are never rolled
+// back. Every header after the checkpoint is fully validated against locally
+// stored ancestors.
+type HeaderStore struct {
+	db kvdb.Backend
+
+	params *chaincfg.Params
+
+	// baseHeight is the height of the first header in the store.
+	baseHeight uint32
+
+	// anchorHeight is the height of the checkpoint the store is anchored
+	// at. Headers at or below it are trusted through the checkpoint hash,
+	// headers above it are validated contextually. It is zero if the store
+	// starts at genesis.
+	anchorHeight uint32
+
+	verifier *HeaderVerifier
+
+	// mtx guards the fields below and serializes writers.
+	mtx sync.RWMutex
+
+	empty     bool
+	tipHeight uint32
+	tipHash   chainhash.Hash
+
+	// pending holds the headers of a batch that is being validated but
+	// hasn't been persisted yet, so they can serve as ancestors of the
+	// headers that follow them in the same batch.
+	pending map[uint32]*wire.BlockHeader
+}
+
+// NewHeaderStore opens, or creates, the header store in the given directory.
+// A newly created store for a network without checkpoints is initialized with
+// the genesis header.
+func NewHeaderStore(dbPath string,
+	params *chaincfg.Params) (*HeaderStore, error) {
+
+	db, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:     dbPath,
+		DBFileName: headerStoreFileName,
+		DBTimeout:  kvdb.DefaultDBTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open header store: %w", err)
+	}
+
+	store, err := newHeaderStore(db, params)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// newHeaderStore initializes a header store on top of an open database.
+func newHeaderStore(db kvdb.Backend,
+	params *chaincfg.Params) (*HeaderStore, error) {
+
+	anchorHeight, baseHeight := checkpointBase(params)
+	if baseHeight == 0 {
+		anchorHeight = 0
+	}
+
+	s := &HeaderStore{
+		db:           db,
+		params:       params,
+		baseHeight:   baseHeight,
+		anchorHeight: anchorHeight,
+		empty:        true,
+	}
+	s.verifier = NewHeaderVerifier(params, s.lookupHeader)
+
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		for _, bucket := range [][]byte{
+			headerBucket, hashIndexBucket, headerMetaBucket,
+		} {
+			_, err := tx.CreateTopLevelBucket(bucket)
+			if err != nil {
+				return err
+			}
+		}
+
+		meta := tx.ReadWriteBucket(headerMetaBucket)
+
+		// A store that was created before keeps the base it was
+		// created with, even if newer checkpoints were added since.
+		if base := meta.Get(baseHeightKey); base != nil {
+			s.baseHeight = binary.BigEndian.Uint32(base)
+			if s.baseHeight == 0 {
+				s.anchorHeight = 0
+			}
+		} else {
+			var b [4]byte
+			binary.BigEndian.PutUint32(b[:], s.baseHeight)
+			if err := meta.Put(baseHeightKey, b[:]); err != nil {
+				return err
+			}
+		}
+
+		tip := meta.Get(tipHeightKey)
+		if tip == nil {
+			return nil
+		}
+
+		s.tipHeight = binary.BigEndian.Uint32(tip)
+		header, err := fetchHeaderByHeight(tx, s.tipHeight)
+		if err != nil {
+			return err
+		}
+
+		s.tipHash = header.BlockHash()
+		s.empty = false
+
+		return nil
+	}, func() {
+		s.empty = true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize header store: %w",
+			err)
+	}
+
+	// Without checkpoints the store starts at genesis, which we can
+	// insert right away.
+	if s.empty && s.baseHeight == 0 {
+		genesis := params.GenesisBlock.Header
+		err := s.WriteHeaders(0, []*wire.BlockHeader{&genesis})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infof("Opened Electrum header store: base=%d, anchor=%d, tip=%d",
+		s.baseHeight, s.anchorHeight, s.tipHeight)
+
+	return s, nil
+}
+
+// checkpointBase returns the height of the latest checkpoint of the network
+// and the height the header store should start at so that every header after
+// that checkpoint can be validated against stored ancestors.
+func checkpointBase(params *chaincfg.Params) (uint32, uint32) {
+	if len(params.Checkpoints) == 0 {
+		return 0, 0
+	}
+
+	checkpoint := params.Checkpoints[len(params.Checkpoints)-1]
+	anchor := uint32(checkpoint.Height)
+
+	targetTimespan := int64(params.TargetTimespan)
+	targetTimePerBlock := int64(params.TargetTimePerBlock)
+	blocksPerRetarget := uint32(targetTimespan / targetTimePerBlock)
+
+	// Start a full retarget window plus the median time window before the
+	// period that contains the checkpoint, which covers the ancestors
+	// looked up by the difficulty and timestamp rules.
+	periodStart := (anchor / blocksPerRetarget) * blocksPerRetarget
+	window := blocksPerRetarget + medianTimeBlocks
+	if periodStart < window {
+		return anchor, 0
+	}
+
+	return anchor, periodStart - window
+}
+
+// checkpointAt returns the checkpoint at the given height, if any.
+func checkpointAt(params *chaincfg.Params, height uint32) *chaincfg.Checkpoint {
+	for i := range params.Checkpoints {
+		if uint32(params.Checkpoints[i].Height) == height {
+			return &params.Checkpoints[i]
+		}
+	}
+
+	return nil
+}
+
+// AnchorHeight returns the height of the checkpoint the store is anchored at,
+// or zero if the store starts at genesis. The headers from the base height up
+// to the anchor must be written in a single batch.
+func (s *HeaderStore) AnchorHeight() uint32 {
+	return s.anchorHeight
+}
+
+// Close closes the underlying database.
+func (s *HeaderStore) Close() error {
+	return s.db.Close()
+}
+
+// BaseHeight returns the height of the first header the store holds.
+func (s *HeaderStore) BaseHeight() uint32 {
+	return s.baseHeight
+}
+
+// ChainTip returns the last header in the store along with its height. If
+// the store is still empty ErrHeaderNotFound is returned.
+func (s *HeaderStore) ChainTip() (*wire.BlockHeader, uint32, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.empty {
+		return nil, 0, ErrHeaderNotFound
+	}
+
+	header, err := s.readHeader(s.tipHeight)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return header, s.tipHeight, nil
+}
+
+// Contains returns true if the store holds a header at the given height.
+func (s *HeaderStore) Contains(height uint32) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return !s.empty && height >= s.baseHeight && height <= s.tipHeight
+}
+
+// FetchHeaderByHeight returns the main chain header at the given height.
+func (s *HeaderStore) FetchHeaderByHeight(height uint32) (*wire.BlockHeader,
+	error) {
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.empty || height < s.baseHeight || height > s.tipHeight {
+		return nil, ErrHeaderNotFound
+	}
+
+	return s.readHeader(height)
+}
+
+// FetchHeader returns the main chain header with the given hash along with
+// its height.
+func (s *HeaderStore) FetchHeader(hash *chainhash.Hash) (*wire.BlockHeader,
+	uint32, error) {
+
+	var (
+		header *wire.BlockHeader
+		height uint32
+	)
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		var err error
+		height, err = heightFromHash(tx, hash)
+		if err != nil {
+			return err
+		}
+
+		header, err = fetchHeaderByHeight(tx, height)
+
+		return err
+	}, func() {
+		header = nil
+		height = 0
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return header, height, nil
+}
+
+// HeightFromHash returns the height of the main chain header with the given
+// hash.
+func (s *HeaderStore) HeightFromHash(hash *chainhash.Hash) (uint32, error) {
+	var height uint32
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		var err error
+		height, err = heightFromHash(tx, hash)
+
+		return err
+	}, func() {
+		height = 0
+	})
+
+	return height, err
+}
+
+// WriteHeaders validates the passed headers, which start at the given height,
+// and appends them to the store in a single transaction. The first header
+// must directly extend the current tip, or, for an empty store, sit at the
+// store's base height. A batch holding headers below the anchor checkpoint
+// must reach the checkpoint, as that is what ties them to the chain.
+func (s *HeaderStore) WriteHeaders(startHeight uint32,
+	headers []*wire.BlockHeader) error {
+
+	if len(headers) == 0 {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var prev *wire.BlockHeader
+	switch {
+	case s.empty && startHeight != s.baseHeight:
+		return fmt.Errorf("%w: store starts at height %d, got %d",
+			ErrHeaderStoreGap, s.baseHeight, startHeight)
+
+	case !s.empty && startHeight != s.tipHeight+1:
+		return fmt.Errorf("%w: tip is at height %d, got %d",
+			ErrHeaderStoreGap, s.tipHeight, startHeight)
+
+	case !s.empty:
+		var err error
+		prev, err = s.readHeader(s.tipHeight)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Headers below the anchor are only validated by linking up to the
+	// checkpoint hash, so we can't persist them before we've seen it.
+	endHeight := startHeight + uint32(len(headers)) - 1
+	if startHeight <= s.anchorHeight && endHeight < s.anchorHeight {
+		return fmt.Errorf("%w: batch ends at height %d, anchor is at "+
+			"height %d", ErrUnanchoredHeaders, endHeight,
+			s.anchorHeight)
+	}
+
+	s.pending = make(map[uint32]*wire.BlockHeader, len(headers))
+	defer func() {
+		s.pending = nil
+	}()
+
+	for i, header := range headers {
+		height := startHeight + uint32(i)
+		if err := s.checkHeader(header, height, prev); err != nil {
+			return err
+		}
+
+		s.pending[height] = header
+		prev = header
+	}
+
+	err := kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		headersBkt := tx.ReadWriteBucket(headerBucket)
+		indexBkt := tx.ReadWriteBucket(hashIndexBucket)
+
+		for i, header := range headers {
+			height := startHeight + uint32(i)
+			key := heightKey(height)
+
+			// Each value needs its own buffer, as the database
+			// holds on to it until the transaction commits.
+			var buf bytes.Buffer
+			if err := header.Serialize(&buf); err != nil {
+				return err
+			}
+
+			if err := headersBkt.Put(key, buf.Bytes()); err != nil {
+				return err
+			}
+
+			hash := header.BlockHash()
+			if err := indexBkt.Put(hash[:], key); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.ReadWriteBucket(headerMetaBucket)
+
+		return meta.Put(tipHeightKey, heightKey(endHeight))
+	}, func() {})
+	if err != nil {
+		return fmt.Errorf("unable to write headers: %w", err)
+	}
+
+	s.empty = false
+	s.tipHeight = endHeight
+	s.tipHash = headers[len(headers)-1].BlockHash()
+
+	return nil
+}
+
+// RollbackTo removes all headers above the given height from the store, for
+// example when they were reorged out of the main chain. The removed headers
+// are returned ordered from the old tip downwards. The store can't be rolled
+// back below its anchor checkpoint.
+func (s *HeaderStore) RollbackTo(height uint32) ([]*wire.BlockHeader, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.empty || height >= s.tipHeight {
+		return nil, nil
+	}
+
+	if height < s.baseHeight {
+		return nil, fmt.Errorf("cannot roll back below base height "+
+			"%d", s.baseHeight)
+	}
+
+	// The headers up to the anchor are tied to the checkpoint, which can't
+	// be reorged out. Rolling back below it would also leave headers that
+	// can no longer be linked to the checkpoint.
+	if height < s.anchorHeight {
+		return nil, fmt.Errorf("%w: anchor is at height %d, got %d",
+			ErrRollbackBelowAnchor, s.anchorHeight, height)
+	}
+
+	var removed []*wire.BlockHeader
+	err := kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		headersBkt := tx.ReadWriteBucket(headerBucket)
+		indexBkt := tx.ReadWriteBucket(hashIndexBucket)
+
+		for h := s.tipHeight; h > height; h-- {
+			header, err := fetchHeaderByHeight(tx, h)
+			if err != nil {
+				return err
+			}
+
+			hash := header.BlockHash()
+			if err := indexBkt.Delete(hash[:]); err != nil {
+				return err
+			}
+
+			if err := headersBkt.Delete(heightKey(h)); err != nil {
+				return err
+			}
+
+			removed = append(removed, header)
+		}
+
+		meta := tx.ReadWriteBucket(headerMetaBucket)
+
+		return meta.Put(tipHeightKey, heightKey(height))
+	}, func() {
+		removed = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to roll back headers: %w", err)
+	}
+
+	newTip, err := s.readHeader(height)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tipHeight = height
+	s.tipHash = newTip.BlockHash()
+
+	return removed, nil
+}
+
+// checkHeader validates a header that is about to be written at the given
+// height on top of prev.
+//
+// NOTE: The caller must hold the write lock.
+func (s *HeaderStore) checkHeader(header *wire.BlockHeader, height uint32,
+	prev *wire.BlockHeader) error {
+
+	hash := header.BlockHash()
+	if checkpoint := checkpointAt(s.params, height); checkpoint != nil {
+		if !hash.IsEqual(checkpoint.Hash) {
+			return fmt.Errorf("%w: expected %v at height %d, "+
+				"got %v", ErrCheckpointMismatch,
+				checkpoint.Hash, height, hash)
+		}
+	}
+
+	// Headers after the anchor checkpoint, or all of them if the store
+	// starts at genesis, are validated in full.
+	if s.baseHeight == 0 || height > s.anchorHeight {
+		return s.verifier.VerifyHeader(header, int32(height), prev)
+	}
+
+	// Headers leading up to the anchor checkpoint lack the ancestors
+	// needed for the contextual checks. They are trusted because they are
+	// only persisted in a batch that links up to the checkpoint hash.
+	if err := CheckHeaderPoW(header, s.params); err != nil {
+		return err
+	}
+
+	if prev != nil {
+		prevHash := prev.BlockHash()
+		if !header.PrevBlock.IsEqual(&prevHash) {
+			return fmt.Errorf("%w: expected prevBlock %s, got %s "+
+				"at height %d", ErrHeaderNotConnected, prevHash,
+				header.PrevBlock, height)
+		}
+	}
+
+	return nil
+}
+
+// lookupHeader returns the header at the given height from the batch being
+// written or the store. It is used by the verifier to look up ancestors.
+//
+// NOTE: The caller must hold the write lock.
+func (s *HeaderStore) lookupHeader(height int32) (*wire.BlockHeader, error) {
+	if header, ok := s.pending[uint32(height)]; ok {
+		return header, nil
+	}
+
+	if s.empty || uint32(height) < s.baseHeight ||
+		uint32(height) > s.tipHeight {
+
+		return nil, ErrHeaderNotFound
+	}
+
+	return s.readHeader(uint32(height))
+}
+
+// readHeader reads the header at the given height from the database.
+func (s *HeaderStore) readHeader(height uint32) (*wire.BlockHeader, error) {
+	var header *wire.BlockHeader
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		var err error
+		header, err = fetchHeaderByHeight(tx, height)
+
+		return err
+	}, func() {
+		header = nil
+	})
+
+	return header, err
+}
+
+// fetchHeaderByHeight reads the header at the given height within the passed
+// transaction.
+func fetchHeaderByHeight(tx kvdb.RTx, height uint32) (*wire.BlockHeader,
+	error) {
+
+	headersBkt := tx.ReadBucket(headerBucket)
+	if headersBkt == nil {
+		return nil, ErrHeaderNotFound
+	}
+
+	headerBytes := headersBkt.Get(heightKey(height))
+	if headerBytes == nil {
+		return nil, ErrHeaderNotFound
+	}
+
+	header := &wire.BlockHeader{}
+	err := header.Deserialize(bytes.NewReader(headerBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// heightFromHash looks up the height of the header with the given hash
+// within the passed transaction.
+func heightFromHash(tx kvdb.RTx, hash *chainhash.Hash) (uint32, error) {
+	indexBkt := tx.ReadBucket(hashIndexBucket)
+	if indexBkt == nil {
+		return 0, ErrHeaderNotFound
+	}
+
+	heightBytes := indexBkt.Get(hash[:])
+	if heightBytes == nil {
+		return 0, ErrHeaderNotFound
+	}
+
+	return binary.BigEndian.Uint32(heightBytes), nil
+}
+
+// heightKey encodes a height as a big-endian database key so headers are
+// iterated in chain order.
+func heightKey(height uint32) []byte {
+	var key [4]byte
+	binary.BigEndian.PutUint32(key[:], height)
+
+	return key[:]
+}
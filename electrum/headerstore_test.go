@@ -0,0 +1,201 @@
+package electrum
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHeaderStore tests writing, reading and rolling back headers, and that
+// the store's contents survive a restart.
+func TestHeaderStore(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+	headers := testHeaderChain(t, 20)
+	dbPath := t.TempDir()
+
+	store, err := NewHeaderStore(dbPath, params)
+	require.NoError(t, err)
+
+	// Without checkpoints a new store starts out with the genesis header.
+	require.Zero(t, store.BaseHeight())
+	tip, tipHeight, err := store.ChainTip()
+	require.NoError(t, err)
+	require.Zero(t, tipHeight)
+	require.Equal(t, params.GenesisHash, ptrHash(tip.BlockHash()))
+
+	// Headers that don't extend the tip are rejected.
+	err = store.WriteHeaders(2, headers[2:5])
+	require.ErrorIs(t, err, ErrHeaderStoreGap)
+
+	// So is a batch that doesn't link up to the tip.
+	err = store.WriteHeaders(1, headers[2:5])
+	require.ErrorIs(t, err, ErrHeaderNotConnected)
+
+	require.NoError(t, store.WriteHeaders(1, headers[1:11]))
+	require.NoError(t, store.WriteHeaders(11, headers[11:]))
+	require.True(t, store.Contains(20))
+	require.False(t, store.Contains(21))
+
+	// Reopening the store must give us back the same chain.
+	require.NoError(t, store.Close())
+	store, err = NewHeaderStore(dbPath, params)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+
+	tip, tipHeight, err = store.ChainTip()
+	require.NoError(t, err)
+	require.EqualValues(t, 20, tipHeight)
+	require.Equal(t, headers[20].BlockHash(), tip.BlockHash())
+
+	for height, header := range headers {
+		stored, err := store.FetchHeaderByHeight(uint32(height))
+		require.NoError(t, err)
+		require.Equal(t, header.BlockHash(), stored.BlockHash())
+
+		hash := header.BlockHash()
+		stored, storedHeight, err := store.FetchHeader(&hash)
+		require.NoError(t, err)
+		require.EqualValues(t, height, storedHeight)
+		require.Equal(t, hash, stored.BlockHash())
+	}
+
+	_, err = store.FetchHeaderByHeight(21)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+
+	// Rolling back returns the removed headers from the old tip down and
+	// forgets about them.
+	removed, err := store.RollbackTo(15)
+	require.NoError(t, err)
+	require.Len(t, removed, 5)
+	require.Equal(t, headers[20].BlockHash(), removed[0].BlockHash())
+	require.Equal(t, headers[16].BlockHash(), removed[4].BlockHash())
+
+	_, tipHeight, err = store.ChainTip()
+	require.NoError(t, err)
+	require.EqualValues(t, 15, tipHeight)
+
+	hash := headers[16].BlockHash()
+	_, err = store.HeightFromHash(&hash)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+
+	// The removed headers can be written again afterwards.
+	require.NoError(t, store.WriteHeaders(16, headers[16:]))
+}
+
+// TestHeaderStoreCheckpoint tests that headers conflicting with a checkpoint
+// are rejected.
+func TestHeaderStoreCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	headers := testHeaderChain(t, 10)
+
+	params := chaincfg.RegressionNetParams
+	params.Checkpoints = []chaincfg.Checkpoint{
+		{Height: 5, Hash: &chainhash.Hash{0x01}},
+	}
+
+	store, err := NewHeaderStore(t.TempDir(), &params)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+
+	require.NoError(t, store.WriteHeaders(1, headers[1:5]))
+
+	err = store.WriteHeaders(5, headers[5:])
+	require.ErrorIs(t, err, ErrCheckpointMismatch)
+
+	_, tipHeight, err := store.ChainTip()
+	require.NoError(t, err)
+	require.EqualValues(t, 4, tipHeight)
+}
+
+// TestHeaderStoreAnchor tests that the headers below the anchor checkpoint are
+// only written together with the checkpoint, and that they can't be rolled
+// back.
+func TestHeaderStoreAnchor(t *testing.T) {
+	t.Parallel()
+
+	headers := testHeaderChain(t, 30)
+
+	// Shorten the retarget window so that the store starts well after
+	// genesis.
+	const anchorHeight = 24
+	params := chaincfg.RegressionNetParams
+	params.TargetTimespan = 4 * params.TargetTimePerBlock
+	params.Checkpoints = []chaincfg.Checkpoint{{
+		Height: anchorHeight,
+		Hash:   ptrHash(headers[anchorHeight].BlockHash()),
+	}}
+
+	store, err := NewHeaderStore(t.TempDir(), &params)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+
+	base := store.BaseHeight()
+	require.NotZero(t, base)
+	require.EqualValues(t, anchorHeight, store.AnchorHeight())
+
+	// Headers that stop short of the anchor aren't tied to the checkpoint
+	// yet, so nothing is written.
+	err = store.WriteHeaders(base, headers[base:anchorHeight])
+	require.ErrorIs(t, err, ErrUnanchoredHeaders)
+
+	_, _, err = store.ChainTip()
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+	require.False(t, store.Contains(base))
+
+	// Once the batch reaches the anchor, it is written in full.
+	require.NoError(t, store.WriteHeaders(
+		base, headers[base:anchorHeight+1],
+	))
+	require.NoError(t, store.WriteHeaders(
+		anchorHeight+1, headers[anchorHeight+1:],
+	))
+
+	// The headers after the anchor can be rolled back, but not the anchor
+	// itself or anything below it.
+	_, err = store.RollbackTo(anchorHeight - 1)
+	require.ErrorIs(t, err, ErrRollbackBelowAnchor)
+
+	removed, err := store.RollbackTo(anchorHeight)
+	require.NoError(t, err)
+	require.Len(t, removed, len(headers)-anchorHeight-1)
+
+	_, tipHeight, err := store.ChainTip()
+	require.NoError(t, err)
+	require.EqualValues(t, anchorHeight, tipHeight)
+}
+
+// TestCheckpointBase tests that stores on networks with checkpoints start
+// far enough before the latest checkpoint to validate the headers after it.
+func TestCheckpointBase(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.MainNetParams
+	anchor, base := checkpointBase(params)
+
+	lastCheckpoint := params.Checkpoints[len(params.Checkpoints)-1]
+	require.EqualValues(t, lastCheckpoint.Height, anchor)
+
+	const blocksPerRetarget = 2016
+	periodStart := anchor / blocksPerRetarget * blocksPerRetarget
+	require.Equal(t, periodStart-blocksPerRetarget-medianTimeBlocks, base)
+
+	anchor, base = checkpointBase(&chaincfg.RegressionNetParams)
+	require.Zero(t, anchor)
+	require.Zero(t, base)
+}
+
+// ptrHash returns a pointer to a copy of the given hash.
+func ptrHash(hash chainhash.Hash) *chainhash.Hash {
+	return &hash
+}
@@ -0,0 +1,84 @@
+package electrum
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TipStalenessCheck returns a health check function that fails if the chain
+// tip served by the Electrum servers is older, by the local clock, than
+// maxBlocks times the expected block interval. Blocks are found at random
+// intervals, so maxBlocks should be large enough for an honest gap between
+// two blocks to be very unlikely to trip the check.
+func (c *ChainClient) TipStalenessCheck(maxBlocks uint32) func() error {
+	return func() error {
+		c.bestBlockMtx.RLock()
+		tip := c.bestBlock
+		c.bestBlockMtx.RUnlock()
+
+		return checkTipStaleness(
+			tip.Height, tip.Timestamp, time.Now(),
+			c.chainParams.TargetTimePerBlock, maxBlocks,
+		)
+	}
+}
+
+// checkTipStaleness returns an error if the tip with the given height and
+// timestamp is more than maxBlocks block intervals older than now.
+func checkTipStaleness(height int32, tipTime, now time.Time,
+	blockInterval time.Duration, maxBlocks uint32) error {
+
+	if tipTime.IsZero() {
+		return errors.New("no chain tip received yet")
+	}
+
+	maxAge := time.Duration(maxBlocks) * blockInterval
+	if age := now.Sub(tipTime); age > maxAge {
+		return fmt.Errorf("chain tip at height %d is %v old, more "+
+			"than %d block intervals (%v)", height,
+			age.Round(time.Second), maxBlocks, maxAge)
+	}
+
+	return nil
+}
+
+// LatencyCheck returns a health check function that fails if the average
+// response time of the Electrum server requests are sent to exceeds
+// maxLatency.
+func (c *Client) LatencyCheck(maxLatency time.Duration) func() error {
+	return func() error {
+		s := pickServer(c.servers, nil)
+		if s == nil {
+			return ErrNotConnected
+		}
+
+		return checkLatency(s.status(), maxLatency)
+	}
+}
+
+// checkLatency returns an error if the average response time of the given
+// server exceeds maxLatency.
+func checkLatency(status ServerStatus, maxLatency time.Duration) error {
+	if status.Latency > maxLatency {
+		return fmt.Errorf("electrum server %s latency %v exceeds %v",
+			status.Addr, status.Latency, maxLatency)
+	}
+
+	return nil
+}
+
+// MwebPeerCheck returns a health check function that fails if fewer than
+// minPeers Litecoin P2P peers are connected to fetch MWEB data and full
+// blocks from.
+func (c *ChainClient) MwebPeerCheck(minPeers int) func() error {
+	return func() error {
+		peers := len(c.MwebPeers())
+		if peers < minPeers {
+			return fmt.Errorf("%d mweb p2p peers connected, "+
+				"require at least %d", peers, minPeers)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package electrum
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckTipStaleness tests that a chain tip is only reported stale once it
+// is older than the allowed number of block intervals.
+func TestCheckTipStaleness(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	interval := 150 * time.Second
+
+	// A tip that hasn't been received yet is always stale.
+	require.Error(t, checkTipStaleness(0, time.Time{}, now, interval, 24))
+
+	// A recent tip, or one with a timestamp slightly in the future, is
+	// fine.
+	tipTime := now.Add(-10 * interval)
+	require.NoError(t, checkTipStaleness(100, tipTime, now, interval, 24))
+	tipTime = now.Add(time.Minute)
+	require.NoError(t, checkTipStaleness(100, tipTime, now, interval, 24))
+
+	// One that is older than the allowed number of intervals isn't.
+	tipTime = now.Add(-25 * interval)
+	require.Error(t, checkTipStaleness(100, tipTime, now, interval, 24))
+}
+
+// TestLatencyCheck tests that the latency check only fails if the server
+// requests are sent to is too slow to answer.
+func TestLatencyCheck(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(&ClientConfig{
+		Servers: []string{"a", "b"},
+	})
+	check := client.LatencyCheck(time.Second)
+
+	// Without a connected server, the check fails.
+	require.ErrorIs(t, check(), ErrNotConnected)
+
+	a, b := client.servers[0], client.servers[1]
+	a.connected.Store(true)
+	a.recordSuccess(2 * time.Second)
+	require.Error(t, check())
+
+	// Once a faster server is available requests go there instead.
+	b.connected.Store(true)
+	b.recordSuccess(100 * time.Millisecond)
+	require.NoError(t, check())
+}
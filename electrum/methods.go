@@ -38,9 +38,6 @@ type (
 	// ServerFeaturesResult is the result type for server features queries.
 	ServerFeaturesResult = electrum.ServerFeaturesResult
 
-	// ScripthashSubscription is the type for scripthash subscriptions.
-	ScripthashSubscription = electrum.ScripthashSubscription
-
 	// SubscribeNotif is the notification type for scripthash subscriptions.
 	SubscribeNotif = electrum.SubscribeNotif
 )
@@ -51,6 +48,8 @@ type (
 func (c *Client) GetBalance(ctx context.Context,
 	scripthash string) (electrum.GetBalanceResult, error) {
 
+	c.watchScripthash(scripthash)
+
 	var result electrum.GetBalanceResult
 
 	err := c.withRetry(ctx, func(ctx context.Context,
@@ -72,6 +71,8 @@ func (c *Client) GetBalance(ctx context.Context,
 func (c *Client) GetHistory(ctx context.Context,
 	scripthash string) ([]*electrum.GetMempoolResult, error) {
 
+	c.watchScripthash(scripthash)
+
 	var result []*electrum.GetMempoolResult
 
 	err := c.withRetry(ctx, func(ctx context.Context,
@@ -94,6 +95,8 @@ func (c *Client) GetHistory(ctx context.Context,
 func (c *Client) ListUnspent(ctx context.Context,
 	scripthash string) ([]*electrum.ListUnspentResult, error) {
 
+	c.watchScripthash(scripthash)
+
 	var result []*electrum.ListUnspentResult
 
 	err := c.withRetry(ctx, func(ctx context.Context,
@@ -342,34 +345,6 @@ func (c *Client) EstimateFee(ctx context.Context,
 	return result, err
 }
 
-// SubscribeHeaders subscribes to new block header notifications.
-func (c *Client) SubscribeHeaders(
-	ctx context.Context) (<-chan *electrum.SubscribeHeadersResult, error) {
-
-	client, err := c.getClient()
-	if err != nil {
-		return nil, err
-	}
-
-	return client.SubscribeHeaders(ctx)
-}
-
-// InitScripthashSubscription creates a scripthash subscription object and
-// notification channel without subscribing to any specific scripthash yet.
-// Use subscription.Add() to subscribe to individual scripthashes later.
-func (c *Client) InitScripthashSubscription() (
-	*electrum.ScripthashSubscription, <-chan *electrum.SubscribeNotif,
-	error) {
-
-	client, err := c.getClient()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	sub, notifChan := client.SubscribeScripthash()
-	return sub, notifChan, nil
-}
-
 // GetMerkle retrieves the merkle proof for a transaction in a block.
 func (c *Client) GetMerkle(ctx context.Context, txHash string,
 	height uint32) (*electrum.GetMerkleProofResult, error) {
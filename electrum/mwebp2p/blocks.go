@@ -0,0 +1,176 @@
+package mwebp2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/neutrino/banman"
+)
+
+// blockRequestTimeout is how long a single peer is given to serve a block
+// before the next peer is asked.
+const blockRequestTimeout = 30 * time.Second
+
+var (
+	// ErrNoPeers is returned when a block is requested while no peers are
+	// connected.
+	ErrNoPeers = errors.New("no mweb p2p peers connected")
+
+	// ErrBlockNotFound is returned when a peer doesn't have the requested
+	// block.
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrBlockMismatch is returned when a peer serves a different block
+	// than the one requested.
+	ErrBlockMismatch = errors.New("block does not match requested hash")
+)
+
+// GetBlock fetches the block with the given hash from the connected peers,
+// asking one peer at a time until one of them serves it. The hash should be
+// that of a header validated by the caller. The served block is checked with
+// CheckBlock, and peers serving invalid blocks are banned.
+func (s *Service) GetBlock(ctx context.Context,
+	hash *chainhash.Hash) (*ltcutil.Block, error) {
+
+	s.peersMtx.RLock()
+	peers := make([]*MwebPeer, 0, len(s.peers))
+	for _, p := range s.peers {
+		peers = append(peers, p)
+	}
+	s.peersMtx.RUnlock()
+
+	if len(peers) == 0 {
+		return nil, ErrNoPeers
+	}
+
+	getData := wire.NewMsgGetData()
+	err := getData.AddInvVect(
+		wire.NewInvVect(wire.InvTypeWitnessBlock, hash),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, mp := range peers {
+		block, err := s.requestBlock(ctx, mp, getData, hash)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+
+			log.Debugf("Unable to fetch block %v from %s: %v", hash,
+				mp.Addr(), err)
+			lastErr = err
+
+			continue
+		}
+
+		err = CheckBlock(block, hash, s.cfg.ChainParams)
+		if err != nil {
+			log.Warnf("Invalid block %v received from %s: %v", hash,
+				mp.Addr(), err)
+
+			banErr := s.BanPeer(mp.Addr(), banman.InvalidBlock)
+			if banErr != nil {
+				log.Errorf("Unable to ban peer %s: %v",
+					mp.Addr(), banErr)
+			}
+			lastErr = err
+
+			continue
+		}
+
+		return block, nil
+	}
+
+	return nil, fmt.Errorf("unable to fetch block %v from %d peers: %w",
+		hash, len(peers), lastErr)
+}
+
+// requestBlock sends the getdata message to a single peer and waits for it
+// to serve the requested block.
+func (s *Service) requestBlock(ctx context.Context, mp *MwebPeer,
+	getData *wire.MsgGetData, hash *chainhash.Hash) (*ltcutil.Block,
+	error) {
+
+	// Subscribe before sending the request so the response can't be
+	// missed.
+	recvChan, cancel := mp.SubscribeRecvMsg()
+	defer cancel()
+
+	mp.QueueMessageWithEncoding(getData, nil, wire.BaseEncoding)
+
+	timeout := time.NewTimer(blockRequestTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case msg := <-recvChan:
+			switch m := msg.(type) {
+			case *wire.MsgBlock:
+				if m.BlockHash() != *hash {
+					continue
+				}
+
+				return ltcutil.NewBlock(m), nil
+
+			case *wire.MsgNotFound:
+				for _, inv := range m.InvList {
+					if inv.Hash == *hash {
+						return nil, ErrBlockNotFound
+					}
+				}
+			}
+
+		case <-timeout.C:
+			return nil, fmt.Errorf("timeout waiting for block from "+
+				"%s", mp.Addr())
+
+		case <-mp.OnDisconnect():
+			return nil, fmt.Errorf("peer %s disconnected",
+				mp.Addr())
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-s.quit:
+			return nil, errors.New("mweb p2p service shutting down")
+		}
+	}
+}
+
+// CheckBlock checks that a block has the given hash and that its
+// transactions are the ones committed to by its header, both by the merkle
+// root and by the witness commitment. Proof of work isn't checked again here
+// as the hash is expected to come from an already validated header.
+func CheckBlock(block *ltcutil.Block, hash *chainhash.Hash,
+	params *chaincfg.Params) error {
+
+	if *block.Hash() != *hash {
+		return fmt.Errorf("%w: got %v, want %v", ErrBlockMismatch,
+			block.Hash(), hash)
+	}
+
+	err := blockchain.CheckBlockSanity(
+		block, params.PowLimit, blockchain.NewMedianTime(),
+	)
+	if err != nil {
+		return fmt.Errorf("block failed sanity checks: %w", err)
+	}
+
+	if err := blockchain.ValidateWitnessCommitment(block); err != nil {
+		return fmt.Errorf("invalid witness commitment: %w", err)
+	}
+
+	return nil
+}
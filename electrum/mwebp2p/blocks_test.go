@@ -0,0 +1,36 @@
+package mwebp2p
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckBlock tests that served blocks are only accepted if they match the
+// requested hash and the transactions their header commits to.
+func TestCheckBlock(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+	hash := params.GenesisHash
+
+	block := ltcutil.NewBlock(params.GenesisBlock)
+	require.NoError(t, CheckBlock(block, hash, params))
+
+	// A different block than the one requested is rejected.
+	err := CheckBlock(block, &chainhash.Hash{0x01}, params)
+	require.ErrorIs(t, err, ErrBlockMismatch)
+
+	// So is the right header with transactions it doesn't commit to.
+	tampered := *params.GenesisBlock
+	coinbase := tampered.Transactions[0].Copy()
+	coinbase.TxOut[0].Value++
+	tampered.Transactions = []*wire.MsgTx{coinbase}
+
+	err = CheckBlock(ltcutil.NewBlock(&tampered), hash, params)
+	require.Error(t, err)
+}
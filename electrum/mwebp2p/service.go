@@ -3,6 +3,7 @@ package mwebp2p
 import (
 	"fmt"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -213,6 +214,22 @@ func (s *Service) ConnectedPeers() (<-chan query.Peer, func(), error) {
 	}, nil
 }
 
+// Peers returns the currently connected peers, sorted by address.
+func (s *Service) Peers() []*MwebPeer {
+	s.peersMtx.RLock()
+	peers := make([]*MwebPeer, 0, len(s.peers))
+	for _, p := range s.peers {
+		peers = append(peers, p)
+	}
+	s.peersMtx.RUnlock()
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Addr() < peers[j].Addr()
+	})
+
+	return peers
+}
+
 // BanPeer bans a peer address for misbehavior.
 func (s *Service) BanPeer(addr string, _ banman.Reason) error {
 	s.bannedMtx.Lock()
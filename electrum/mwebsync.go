@@ -16,13 +16,11 @@ import (
 	"github.com/ltcsuite/neutrino/banman"
 	"github.com/ltcsuite/neutrino/mwebdb"
 	"github.com/ltcsuite/neutrino/query"
-
-	"github.com/ltcsuite/lnd/electrum/mwebp2p"
 )
 
 // startMwebSync initializes and starts the mwebsync syncer for MWEB support.
-// It creates a lightweight P2P service for MWEB data queries and wires up the
-// mwebsync library to sync MWEB UTXOs.
+// It dispatches MWEB data queries to the chain client's lightweight P2P
+// service and wires up the mwebsync library to sync MWEB UTXOs.
 //
 // This is called as a goroutine from Start() and runs until the client stops.
 func (c *ChainClient) startMwebSync() {
@@ -50,17 +48,13 @@ func (c *ChainClient) startMwebSync() {
 		return
 	}
 
-	// 1. Start the P2P service for MWEB queries.
-	p2pCfg := &mwebp2p.Config{
-		ChainParams:  c.chainParams,
-		ConnectPeers: c.mwebP2PPeers,
-	}
-	p2pService := mwebp2p.NewService(p2pCfg)
-	if err := p2pService.Start(); err != nil {
-		log.Errorf("Failed to start MWEB P2P service: %v", err)
+	// 1. Use the P2P service started by the chain client for MWEB
+	// queries.
+	p2pService := c.p2pService
+	if p2pService == nil {
+		log.Errorf("MWEB P2P service not running, MWEB sync disabled")
 		return
 	}
-	c.p2pService = p2pService
 
 	// 2. Create query.WorkManager using the P2P service's peers.
 	workManager := query.NewWorkManager(&query.Config{
@@ -77,7 +71,6 @@ func (c *ChainClient) startMwebSync() {
 	})
 	if err := workManager.Start(); err != nil {
 		log.Errorf("Failed to start MWEB query work manager: %v", err)
-		p2pService.Stop()
 		return
 	}
 	c.workManager = workManager
@@ -130,7 +123,6 @@ func (c *ChainClient) startMwebSync() {
 	if err != nil {
 		log.Errorf("Failed to create mwebsync: %v", err)
 		workManager.Stop()
-		p2pService.Stop()
 		return
 	}
 	c.mwebMtx.Lock()
@@ -143,7 +135,6 @@ func (c *ChainClient) startMwebSync() {
 		c.mwebSyncer = nil
 		c.mwebMtx.Unlock()
 		workManager.Stop()
-		p2pService.Stop()
 		return
 	}
 
@@ -161,7 +152,6 @@ func (c *ChainClient) startMwebSync() {
 	c.waitForSyncOrShutdown(syncer)
 
 	workManager.Stop()
-	p2pService.Stop()
 }
 
 // waitForSyncOrShutdown blocks until either the client is stopped or the
@@ -0,0 +1,464 @@
+package electrum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/checksum0/go-electrum/electrum"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// failurePenalty is added to a server's score for every consecutive
+	// failed request, so that a failing server is only preferred over a
+	// slow one when the slow one is very slow.
+	failurePenalty = 5 * time.Second
+
+	// latencyWeight is the weight given to a new latency sample in the
+	// moving average of a server's latency.
+	latencyWeight = 0.25
+
+	// maxQuorumScripthashes is the number of recently queried
+	// scripthashes whose statuses are compared across servers in quorum
+	// mode.
+	maxQuorumScripthashes = 20
+)
+
+var (
+	// ErrQuorumDisagreement is returned when the Electrum servers don't
+	// agree on the chain tip or the status of a scripthash.
+	ErrQuorumDisagreement = errors.New("electrum servers disagree")
+
+	// ErrQuorumUnavailable is returned when fewer servers than the
+	// configured quorum are connected, so their answers can't be
+	// cross-checked.
+	ErrQuorumUnavailable = errors.New("not enough electrum servers " +
+		"connected to reach quorum")
+)
+
+// server is a single Electrum server of the client's pool, along with the
+// connection to it and its health.
+type server struct {
+	addr string
+
+	// connected indicates whether the connection to the server is
+	// currently usable.
+	connected atomic.Bool
+
+	// mtx guards the fields below.
+	mtx sync.RWMutex
+
+	// client is the connection to the server.
+	client *electrum.Client
+
+	// connQuit is closed when the current connection is torn down.
+	connQuit chan struct{}
+
+	// serverVersion stores the server's software version string.
+	serverVersion string
+
+	// protocolVersion stores the negotiated protocol version.
+	protocolVersion string
+
+	// latency is the moving average of the server's response time.
+	latency time.Duration
+
+	// failures is the number of consecutive failed requests.
+	failures int
+
+	// tipHeight and tipHeader are the latest chain tip announced by the
+	// server. The header is kept hex encoded as served.
+	tipHeight int32
+	tipHeader string
+}
+
+// newServer returns a new, disconnected server for the given address.
+func newServer(addr string) *server {
+	return &server{
+		addr: addr,
+	}
+}
+
+// getClient returns the connection to the server, or an error if the server
+// isn't connected.
+func (s *server) getClient() (*electrum.Client, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.client == nil || !s.connected.Load() {
+		return nil, ErrNotConnected
+	}
+
+	return s.client, nil
+}
+
+// score returns the server's health score. Lower is better.
+func (s *server) score() time.Duration {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.latency + time.Duration(s.failures)*failurePenalty
+}
+
+// recordSuccess records a request that was answered after the given time.
+func (s *server) recordSuccess(latency time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.failures = 0
+	if s.latency == 0 {
+		s.latency = latency
+		return
+	}
+
+	s.latency = time.Duration(
+		latencyWeight*float64(latency) +
+			(1-latencyWeight)*float64(s.latency),
+	)
+}
+
+// recordFailure records a request that the server failed to answer.
+func (s *server) recordFailure() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.failures++
+}
+
+// setTip records the chain tip announced by the server.
+func (s *server) setTip(height int32, header string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.tipHeight = height
+	s.tipHeader = strings.ToLower(header)
+}
+
+// tip returns the latest chain tip announced by the server. The returned
+// header is empty if the server hasn't announced a tip yet.
+func (s *server) tip() (int32, string) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.tipHeight, s.tipHeader
+}
+
+// versions returns the server's software and protocol versions.
+func (s *server) versions() (string, string) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.serverVersion, s.protocolVersion
+}
+
+// pickServer returns the connected server with the best health score that
+// isn't in the exclude set. Ties go to the server listed first. Nil is
+// returned if no such server exists.
+func pickServer(servers []*server, exclude map[*server]struct{}) *server {
+	var (
+		best      *server
+		bestScore time.Duration
+	)
+	for _, s := range servers {
+		if _, ok := exclude[s]; ok || !s.connected.Load() {
+			continue
+		}
+
+		score := s.score()
+		if best == nil || score < bestScore {
+			best = s
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+// checkAgreement checks that at least quorum of the given answers, keyed by
+// server address, are the same. What describes the compared value in the
+// returned error.
+func checkAgreement(what string, answers map[string]string,
+	quorum int) error {
+
+	if len(answers) < quorum {
+		return fmt.Errorf("%w: %d of %d answered for %s",
+			ErrQuorumUnavailable, len(answers), quorum, what)
+	}
+
+	votes := make(map[string]int, len(answers))
+	for _, answer := range answers {
+		votes[answer]++
+	}
+
+	var best int
+	for _, count := range votes {
+		if count > best {
+			best = count
+		}
+	}
+	if best >= quorum {
+		return nil
+	}
+
+	// List the answers in a stable order so the error is readable.
+	addrs := make([]string, 0, len(answers))
+	for addr := range answers {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	details := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		details = append(details, fmt.Sprintf("%s=%s", addr,
+			answers[addr]))
+	}
+
+	return fmt.Errorf("%w on %s: %s", ErrQuorumDisagreement, what,
+		strings.Join(details, ", "))
+}
+
+// scripthashStatus computes the Electrum status of a scripthash from its
+// history, only taking into account transactions confirmed at or below the
+// given height. Mempool transactions and more recent blocks are left out as
+// servers may legitimately differ on them for a short while.
+func scripthashStatus(history []*electrum.GetMempoolResult,
+	maxHeight int32) string {
+
+	var status strings.Builder
+	for _, item := range history {
+		if item.Height <= 0 || item.Height > maxHeight {
+			continue
+		}
+
+		fmt.Fprintf(&status, "%s:%d:", item.Hash, item.Height)
+	}
+
+	if status.Len() == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(status.String()))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// headerHash returns the hash of a hex encoded block header.
+func headerHash(headerHex string) (chainhash.Hash, error) {
+	headerBytes, err := hex.DecodeString(headerHex)
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("failed to decode "+
+			"header: %w", err)
+	}
+
+	var header wire.BlockHeader
+	err = header.Deserialize(bytes.NewReader(headerBytes))
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("failed to parse "+
+			"header: %w", err)
+	}
+
+	return header.BlockHash(), nil
+}
+
+// watchScripthash remembers a scripthash whose status is cross-checked in
+// quorum mode.
+func (c *Client) watchScripthash(scripthash string) {
+	if c.cfg.Quorum < 2 {
+		return
+	}
+
+	c.watchedMtx.Lock()
+	defer c.watchedMtx.Unlock()
+
+	for _, watched := range c.watched {
+		if watched == scripthash {
+			return
+		}
+	}
+
+	c.watched = append(c.watched, scripthash)
+	if len(c.watched) > maxQuorumScripthashes {
+		c.watched = c.watched[1:]
+	}
+}
+
+// QuorumErr returns the result of the latest cross-check of the servers in
+// quorum mode. It is nil if the servers agreed or quorum mode is disabled.
+func (c *Client) QuorumErr() error {
+	c.quorumMtx.RLock()
+	defer c.quorumMtx.RUnlock()
+
+	return c.quorumErr
+}
+
+// checkQuorum compares the chain tip and the statuses of the watched
+// scripthashes across the connected servers and records the outcome.
+func (c *Client) checkQuorum() {
+	err := c.compareServers()
+	if err != nil {
+		log.Warnf("Electrum quorum check failed: %v", err)
+	}
+
+	c.quorumMtx.Lock()
+	c.quorumErr = err
+	c.quorumMtx.Unlock()
+}
+
+// compareServers cross-checks the connected servers. The comparison is done
+// at the lowest tip height announced by any of them, so servers that are a
+// block behind don't raise false alarms.
+func (c *Client) compareServers() error {
+	var (
+		servers   []*server
+		minHeight int32
+	)
+	for _, s := range c.servers {
+		height, header := s.tip()
+		if !s.connected.Load() || header == "" {
+			continue
+		}
+
+		if len(servers) == 0 || height < minHeight {
+			minHeight = height
+		}
+		servers = append(servers, s)
+	}
+
+	if len(servers) < c.cfg.Quorum {
+		return fmt.Errorf("%w: %d of %d connected",
+			ErrQuorumUnavailable, len(servers), c.cfg.Quorum)
+	}
+
+	headers := make(map[string]string, len(servers))
+	for _, s := range servers {
+		header, err := c.headerAt(s, minHeight)
+		if err != nil {
+			log.Debugf("Unable to fetch header %d from %s for "+
+				"quorum check: %v", minHeight, s.addr, err)
+			continue
+		}
+
+		hash, err := headerHash(header)
+		if err != nil {
+			log.Debugf("Invalid header %d from %s: %v", minHeight,
+				s.addr, err)
+			continue
+		}
+
+		headers[s.addr] = hash.String()
+	}
+
+	what := fmt.Sprintf("block header at height %d", minHeight)
+	if err := checkAgreement(what, headers, c.cfg.Quorum); err != nil {
+		return err
+	}
+
+	c.watchedMtx.Lock()
+	watched := append([]string(nil), c.watched...)
+	c.watchedMtx.Unlock()
+
+	for _, scripthash := range watched {
+		statuses := make(map[string]string, len(servers))
+		for _, s := range servers {
+			history, err := c.historyFrom(s, scripthash)
+			if err != nil {
+				log.Debugf("Unable to fetch history of %s "+
+					"from %s for quorum check: %v",
+					scripthash, s.addr, err)
+				continue
+			}
+
+			statuses[s.addr] = scripthashStatus(history, minHeight)
+		}
+
+		what := fmt.Sprintf("status of scripthash %s", scripthash)
+		err := checkAgreement(what, statuses, c.cfg.Quorum)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// headerAt returns the hex encoded header at the given height as served by
+// the given server.
+func (c *Client) headerAt(s *server, height int32) (string, error) {
+	if tipHeight, tipHeader := s.tip(); tipHeight == height {
+		return tipHeader, nil
+	}
+
+	client, err := s.getClient()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), c.cfg.RequestTimeout,
+	)
+	defer cancel()
+
+	header, err := client.GetBlockHeader(ctx, uint32(height))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(header.Header), nil
+}
+
+// historyFrom returns the history of a scripthash as served by the given
+// server.
+func (c *Client) historyFrom(s *server,
+	scripthash string) ([]*electrum.GetMempoolResult, error) {
+
+	client, err := s.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), c.cfg.RequestTimeout,
+	)
+	defer cancel()
+
+	return client.GetHistory(ctx, scripthash)
+}
+
+// trackTip records the chain tips announced by a server until its
+// connection is torn down or the client shuts down.
+func (c *Client) trackTip(s *server,
+	headers <-chan *electrum.SubscribeHeadersResult,
+	connQuit <-chan struct{}) {
+
+	defer c.wg.Done()
+
+	for {
+		select {
+		case header, ok := <-headers:
+			if !ok {
+				return
+			}
+			if header == nil {
+				continue
+			}
+
+			s.setTip(header.Height, header.Hex)
+
+		case <-connQuit:
+			return
+
+		case <-c.quit:
+			return
+		}
+	}
+}
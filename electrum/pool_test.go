@@ -0,0 +1,161 @@
+package electrum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/checksum0/go-electrum/electrum"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPickServer tests that requests go to the connected server with the
+// best health score.
+func TestPickServer(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := newServer("a"), newServer("b"), newServer("c")
+	servers := []*server{a, b, c}
+
+	// Nothing is connected yet.
+	require.Nil(t, pickServer(servers, nil))
+
+	for _, s := range servers {
+		s.connected.Store(true)
+	}
+
+	// Without any history, the first server listed wins.
+	require.Equal(t, a, pickServer(servers, nil))
+
+	// The fastest server is preferred.
+	a.recordSuccess(300 * time.Millisecond)
+	b.recordSuccess(100 * time.Millisecond)
+	c.recordSuccess(200 * time.Millisecond)
+	require.Equal(t, b, pickServer(servers, nil))
+
+	// A failing server loses its lead, until it answers again.
+	b.recordFailure()
+	require.Equal(t, c, pickServer(servers, nil))
+	b.recordSuccess(100 * time.Millisecond)
+	require.Equal(t, b, pickServer(servers, nil))
+
+	// Servers that were tried already or are disconnected are skipped.
+	exclude := map[*server]struct{}{b: {}}
+	require.Equal(t, c, pickServer(servers, exclude))
+	c.connected.Store(false)
+	require.Equal(t, a, pickServer(servers, exclude))
+}
+
+// TestCheckAgreement tests that answers are only accepted if enough servers
+// agree on them.
+func TestCheckAgreement(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		answers map[string]string
+		quorum  int
+		err     error
+	}{
+		{
+			name: "all agree",
+			answers: map[string]string{
+				"a": "x", "b": "x", "c": "x",
+			},
+			quorum: 3,
+		},
+		{
+			name: "majority agrees",
+			answers: map[string]string{
+				"a": "x", "b": "y", "c": "x",
+			},
+			quorum: 2,
+		},
+		{
+			name: "too few agree",
+			answers: map[string]string{
+				"a": "x", "b": "y", "c": "x",
+			},
+			quorum: 3,
+			err:    ErrQuorumDisagreement,
+		},
+		{
+			name: "too few answers",
+			answers: map[string]string{
+				"a": "x",
+			},
+			quorum: 2,
+			err:    ErrQuorumUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkAgreement("tip", tc.answers, tc.quorum)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+// TestScripthashStatus tests that the status of a scripthash follows the
+// Electrum protocol and ignores unconfirmed and too recent transactions.
+func TestScripthashStatus(t *testing.T) {
+	t.Parallel()
+
+	history := []*electrum.GetMempoolResult{
+		{Hash: "aa", Height: 100},
+		{Hash: "bb", Height: 105},
+		{Hash: "cc", Height: 110},
+		{Hash: "dd", Height: 0},
+		{Hash: "ee", Height: -1},
+	}
+
+	status := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	require.Equal(
+		t, status("aa:100:bb:105:cc:110:"),
+		scripthashStatus(history, 110),
+	)
+	require.Equal(
+		t, status("aa:100:bb:105:"), scripthashStatus(history, 109),
+	)
+	require.Empty(t, scripthashStatus(history, 99))
+	require.Empty(t, scripthashStatus(nil, 110))
+}
+
+// TestWatchScripthash tests that only a bounded number of recently queried
+// scripthashes are cross-checked, and only in quorum mode.
+func TestWatchScripthash(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(&ClientConfig{
+		Servers: []string{"a", "b"},
+	})
+	client.watchScripthash("00")
+	require.Empty(t, client.watched)
+
+	client.cfg.Quorum = 2
+	for i := 0; i < maxQuorumScripthashes+5; i++ {
+		client.watchScripthash(fmt.Sprintf("%02x", i))
+	}
+
+	// Watching a known scripthash again doesn't add a duplicate.
+	client.watchScripthash(fmt.Sprintf("%02x", maxQuorumScripthashes+4))
+
+	require.Len(t, client.watched, maxQuorumScripthashes)
+	require.Equal(t, "05", client.watched[0])
+}
@@ -0,0 +1,432 @@
+package electrum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+var (
+	// ErrInvalidMerkleProof is returned when a merkle proof served by the
+	// Electrum server does not commit the transaction into the block
+	// header's merkle root.
+	ErrInvalidMerkleProof = errors.New("invalid merkle proof")
+
+	// ErrInvalidProofOfWork is returned when a block header served by the
+	// Electrum server does not satisfy the scrypt proof-of-work target it
+	// claims, or claims a target easier than the chain's limit.
+	ErrInvalidProofOfWork = errors.New("header fails proof-of-work check")
+
+	// ErrHeaderNotConnected is returned when a block header does not
+	// reference its expected parent.
+	ErrHeaderNotConnected = errors.New("header does not connect to parent")
+
+	// ErrHeaderMismatch is returned when the header the Electrum server
+	// returns for a height does not hash to the block we expected.
+	ErrHeaderMismatch = errors.New("header does not match expected hash")
+)
+
+// HeaderFetcher returns the block header at the given height. It is used by
+// the HeaderVerifier to look up the ancestors needed for contextual checks.
+type HeaderFetcher func(height int32) (*wire.BlockHeader, error)
+
+// HeaderRangeFetcher returns count consecutive block headers starting at the
+// given height.
+type HeaderRangeFetcher func(startHeight int32,
+	count uint32) ([]*wire.BlockHeader, error)
+
+const (
+	// forkSearchDepth is the number of headers at or below our best
+	// height that a HeaderWalker requests along with the ones above it.
+	// It covers the reorgs seen in practice with a single request.
+	forkSearchDepth = 16
+
+	// maxHeaderBatch is the maximum number of headers an Electrum server
+	// returns for a single blockchain.block.headers request.
+	maxHeaderBatch = 2016
+)
+
+// HeaderWalker serves the server's block headers while walking the chain back
+// from a new tip in search of the fork point with our own chain. Rather than
+// issuing a request per header, the headers are fetched in batches that reach
+// a few blocks below our best height, so that the walk usually completes with
+// a single request.
+type HeaderWalker struct {
+	fetch      HeaderRangeFetcher
+	bestHeight int32
+
+	// start is the height of the first header in headers.
+	start   int32
+	headers []*wire.BlockHeader
+}
+
+// NewHeaderWalker creates a HeaderWalker for a chain whose best block we know
+// to be at the given height.
+func NewHeaderWalker(fetch HeaderRangeFetcher,
+	bestHeight int32) *HeaderWalker {
+
+	return &HeaderWalker{
+		fetch:      fetch,
+		bestHeight: bestHeight,
+	}
+}
+
+// Header returns the server's header at the given height. If it isn't part of
+// the last fetched batch, a new batch ending at that height is fetched.
+func (w *HeaderWalker) Header(height int32) (*wire.BlockHeader, error) {
+	if height < 0 {
+		return nil, fmt.Errorf("invalid height %d", height)
+	}
+
+	end := w.start + int32(len(w.headers))
+	if height >= w.start && height < end {
+		return w.headers[height-w.start], nil
+	}
+
+	// Fetch the headers above our best height along with a few below it,
+	// but never more than the server returns at once.
+	count := int32(forkSearchDepth)
+	if height > w.bestHeight {
+		count += height - w.bestHeight
+	}
+	if count > maxHeaderBatch {
+		count = maxHeaderBatch
+	}
+	if count > height+1 {
+		count = height + 1
+	}
+
+	start := height - count + 1
+	headers, err := w.fetch(start, uint32(count))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(headers) != int(count) {
+		return nil, fmt.Errorf("expected %d headers starting at "+
+			"height %d, got %d", count, start, len(headers))
+	}
+
+	w.start = start
+	w.headers = headers
+
+	return headers[height-start], nil
+}
+
+// HeaderVerifier performs SPV validation of block headers served by an
+// Electrum server. Every header is checked for scrypt proof-of-work, linkage
+// to its parent and the difficulty expected by the retarget rules of the
+// configured chain.
+type HeaderVerifier struct {
+	params *chaincfg.Params
+
+	blocksPerRetarget   int32
+	minRetargetTimespan int64
+	maxRetargetTimespan int64
+
+	fetchHeader HeaderFetcher
+}
+
+// Compile time check to ensure HeaderVerifier implements the
+// blockchain.ChainCtx interface.
+var _ blockchain.ChainCtx = (*HeaderVerifier)(nil)
+
+// NewHeaderVerifier creates a new HeaderVerifier for the given chain. The
+// fetch function is used to retrieve ancestor headers by height.
+func NewHeaderVerifier(params *chaincfg.Params,
+	fetch HeaderFetcher) *HeaderVerifier {
+
+	targetTimespan := int64(params.TargetTimespan / time.Second)
+	targetTimePerBlock := int64(params.TargetTimePerBlock / time.Second)
+	adjustmentFactor := params.RetargetAdjustmentFactor
+
+	return &HeaderVerifier{
+		params:              params,
+		blocksPerRetarget:   int32(targetTimespan / targetTimePerBlock),
+		minRetargetTimespan: targetTimespan / adjustmentFactor,
+		maxRetargetTimespan: targetTimespan * adjustmentFactor,
+		fetchHeader:         fetch,
+	}
+}
+
+// VerifyHeader checks the header at the given height against its parent. The
+// parent may be nil, in which case it is looked up through the fetch
+// function. The genesis header is only checked for proof-of-work.
+func (v *HeaderVerifier) VerifyHeader(header *wire.BlockHeader, height int32,
+	prev *wire.BlockHeader) error {
+
+	if err := CheckHeaderPoW(header, v.params); err != nil {
+		return err
+	}
+
+	if height == 0 {
+		genesisHash := v.params.GenesisHash
+		hash := header.BlockHash()
+		if genesisHash != nil && !hash.IsEqual(genesisHash) {
+			return fmt.Errorf("%w: genesis %s, got %s",
+				ErrHeaderMismatch, genesisHash, hash)
+		}
+
+		return nil
+	}
+
+	if prev == nil {
+		var err error
+		prev, err = v.fetchHeader(height - 1)
+		if err != nil {
+			return fmt.Errorf("unable to fetch parent header at "+
+				"height %d: %w", height-1, err)
+		}
+	}
+
+	prevHash := prev.BlockHash()
+	if !header.PrevBlock.IsEqual(&prevHash) {
+		return fmt.Errorf("%w: expected prevBlock %s, got %s at "+
+			"height %d", ErrHeaderNotConnected, prevHash,
+			header.PrevBlock, height)
+	}
+
+	parentCtx := &spvHeaderCtx{
+		height: height - 1,
+		header: prev,
+		fetch:  v.fetchHeader,
+	}
+
+	err := blockchain.CheckBlockHeaderContext(
+		header, parentCtx, blockchain.BFNone, v, true,
+	)
+	if err != nil {
+		return fmt.Errorf("contextual check failed at height %d: %w",
+			height, err)
+	}
+
+	return nil
+}
+
+// ChainParams returns the configured chain parameters.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (v *HeaderVerifier) ChainParams() *chaincfg.Params {
+	return v.params
+}
+
+// BlocksPerRetarget returns the number of blocks before retargeting occurs.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (v *HeaderVerifier) BlocksPerRetarget() int32 {
+	return v.blocksPerRetarget
+}
+
+// MinRetargetTimespan returns the minimum amount of time used in the
+// difficulty calculation.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (v *HeaderVerifier) MinRetargetTimespan() int64 {
+	return v.minRetargetTimespan
+}
+
+// MaxRetargetTimespan returns the maximum amount of time used in the
+// difficulty calculation.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (v *HeaderVerifier) MaxRetargetTimespan() int64 {
+	return v.maxRetargetTimespan
+}
+
+// VerifyCheckpoint returns false as checkpoints are not enforced by the
+// verifier.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (v *HeaderVerifier) VerifyCheckpoint(int32, *chainhash.Hash) bool {
+	return false
+}
+
+// FindPreviousCheckpoint returns nil values as checkpoints are not enforced
+// by the verifier.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (v *HeaderVerifier) FindPreviousCheckpoint() (blockchain.HeaderCtx,
+	error) {
+
+	return nil, nil
+}
+
+// spvHeaderCtx is an implementation of the blockchain.HeaderCtx interface
+// which resolves ancestors through a HeaderFetcher.
+type spvHeaderCtx struct {
+	height int32
+	header *wire.BlockHeader
+	fetch  HeaderFetcher
+}
+
+// Height returns the height of the underlying header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (s *spvHeaderCtx) Height() int32 {
+	return s.height
+}
+
+// Bits returns the difficulty bits of the underlying header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (s *spvHeaderCtx) Bits() uint32 {
+	return s.header.Bits
+}
+
+// Timestamp returns the timestamp of the underlying header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (s *spvHeaderCtx) Timestamp() int64 {
+	return s.header.Timestamp.Unix()
+}
+
+// Parent returns the parent of the underlying header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (s *spvHeaderCtx) Parent() blockchain.HeaderCtx {
+	return s.RelativeAncestorCtx(1)
+}
+
+// RelativeAncestorCtx returns the ancestor that is distance blocks before the
+// underlying header in the chain.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (s *spvHeaderCtx) RelativeAncestorCtx(
+	distance int32) blockchain.HeaderCtx {
+
+	ancestorHeight := s.height - distance
+	if ancestorHeight < 0 {
+		return nil
+	}
+
+	ancestor, err := s.fetch(ancestorHeight)
+	if err != nil {
+		log.Debugf("Unable to fetch ancestor header at height %d: %v",
+			ancestorHeight, err)
+		return nil
+	}
+
+	return &spvHeaderCtx{
+		height: ancestorHeight,
+		header: ancestor,
+		fetch:  s.fetch,
+	}
+}
+
+// CheckHeaderPoW ensures the target encoded in the header's difficulty bits
+// is within the chain's proof-of-work limit and that the scrypt hash of the
+// header satisfies it.
+func CheckHeaderPoW(header *wire.BlockHeader, params *chaincfg.Params) error {
+	target := blockchain.CompactToBig(header.Bits)
+	if target.Sign() <= 0 {
+		return fmt.Errorf("%w: target difficulty of %064x is too low",
+			ErrInvalidProofOfWork, target)
+	}
+
+	if target.Cmp(params.PowLimit) > 0 {
+		return fmt.Errorf("%w: target difficulty of %064x is higher "+
+			"than max of %064x", ErrInvalidProofOfWork, target,
+			params.PowLimit)
+	}
+
+	powHash := header.PowHash()
+	if blockchain.HashToBig(&powHash).Cmp(target) > 0 {
+		return fmt.Errorf("%w: block hash %v is higher than target "+
+			"%064x", ErrInvalidProofOfWork, header.BlockHash(),
+			target)
+	}
+
+	return nil
+}
+
+// MerkleRootFromProof folds the merkle branch returned by
+// blockchain.transaction.get_merkle into the root it commits to. The branch
+// hashes are given in the server's display (reversed hex) byte order.
+func MerkleRootFromProof(txid *chainhash.Hash, branch []string,
+	pos uint32) (chainhash.Hash, error) {
+
+	// A position that doesn't fit into the depth of the branch can't refer
+	// to a leaf of this tree.
+	if len(branch) < 32 && pos>>uint(len(branch)) != 0 {
+		return chainhash.Hash{}, fmt.Errorf("%w: position %d out of "+
+			"range for branch of depth %d", ErrInvalidMerkleProof,
+			pos, len(branch))
+	}
+
+	current := *txid
+	for i, hexHash := range branch {
+		sibling, err := chainhash.NewHashFromStr(hexHash)
+		if err != nil {
+			return chainhash.Hash{}, fmt.Errorf("%w: invalid "+
+				"branch hash %q: %v", ErrInvalidMerkleProof,
+				hexHash, err)
+		}
+
+		if (pos>>uint(i))&1 == 1 {
+			current = blockchain.HashMerkleBranches(
+				sibling, &current,
+			)
+		} else {
+			current = blockchain.HashMerkleBranches(
+				&current, sibling,
+			)
+		}
+	}
+
+	return current, nil
+}
+
+// VerifyMerkleProof checks that the given merkle proof commits the
+// transaction into the merkle root of the passed block header.
+func VerifyMerkleProof(txid *chainhash.Hash, proof *GetMerkleProofResult,
+	header *wire.BlockHeader) error {
+
+	if proof == nil {
+		return fmt.Errorf("%w: empty proof", ErrInvalidMerkleProof)
+	}
+
+	root, err := MerkleRootFromProof(txid, proof.Merkle, proof.Position)
+	if err != nil {
+		return err
+	}
+
+	if !root.IsEqual(&header.MerkleRoot) {
+		return fmt.Errorf("%w: tx %v computes root %v, header %v "+
+			"commits to %v", ErrInvalidMerkleProof, txid, root,
+			header.BlockHash(), header.MerkleRoot)
+	}
+
+	return nil
+}
+
+// VerifyTxInclusion fetches a merkle proof for the transaction at the given
+// height and checks it against the passed block header, which the caller is
+// expected to have validated already. On success the position of the
+// transaction within the block is returned.
+func (c *Client) VerifyTxInclusion(ctx context.Context, txid *chainhash.Hash,
+	height uint32, header *wire.BlockHeader) (uint32, error) {
+
+	proof, err := c.GetMerkle(ctx, txid.String(), height)
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch merkle proof for tx "+
+			"%v: %w", txid, err)
+	}
+
+	if proof.Height != height {
+		return 0, fmt.Errorf("%w: proof for tx %v is for height %d, "+
+			"expected %d", ErrInvalidMerkleProof, txid,
+			proof.Height, height)
+	}
+
+	if err := VerifyMerkleProof(txid, proof, header); err != nil {
+		return 0, err
+	}
+
+	return proof.Position, nil
+}
@@ -0,0 +1,293 @@
+package electrum
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// testMerkleBranch returns the merkle branch for the leaf at pos in the
+// format served by blockchain.transaction.get_merkle.
+func testMerkleBranch(leaves []chainhash.Hash, pos int) []string {
+	var branch []string
+
+	level := append([]chainhash.Hash(nil), leaves...)
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+
+		branch = append(branch, level[pos^1].String())
+
+		next := make([]chainhash.Hash, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			next = append(next, blockchain.HashMerkleBranches(
+				&level[i], &level[i+1],
+			))
+		}
+
+		level = next
+		pos >>= 1
+	}
+
+	return branch
+}
+
+// testTxs creates a set of distinct transactions.
+func testTxs(n int) []*ltcutil.Tx {
+	txs := make([]*ltcutil.Tx, 0, n)
+	for i := 0; i < n; i++ {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Index: uint32(i)},
+		})
+		tx.AddTxOut(&wire.TxOut{Value: int64(i + 1)})
+		txs = append(txs, ltcutil.NewTx(tx))
+	}
+
+	return txs
+}
+
+// TestVerifyMerkleProof tests that merkle proofs are accepted for every
+// position in blocks of various sizes, and rejected when tampered with.
+func TestVerifyMerkleProof(t *testing.T) {
+	t.Parallel()
+
+	for _, numTxs := range []int{1, 2, 3, 5, 8, 13} {
+		txs := testTxs(numTxs)
+		root := blockchain.CalcMerkleRoot(txs, false)
+		header := &wire.BlockHeader{MerkleRoot: root}
+
+		leaves := make([]chainhash.Hash, 0, numTxs)
+		for _, tx := range txs {
+			leaves = append(leaves, *tx.Hash())
+		}
+
+		for pos, tx := range txs {
+			proof := &GetMerkleProofResult{
+				Merkle:   testMerkleBranch(leaves, pos),
+				Position: uint32(pos),
+			}
+
+			err := VerifyMerkleProof(tx.Hash(), proof, header)
+			require.NoError(t, err, "txs=%d pos=%d", numTxs, pos)
+
+			// The proof must not be valid for any other
+			// transaction.
+			other := txs[(pos+1)%numTxs].Hash()
+			if numTxs > 1 {
+				err = VerifyMerkleProof(other, proof, header)
+				require.ErrorIs(t, err, ErrInvalidMerkleProof)
+			}
+		}
+	}
+
+	// A position outside the range covered by the branch is rejected.
+	txs := testTxs(4)
+	leaves := []chainhash.Hash{
+		*txs[0].Hash(), *txs[1].Hash(), *txs[2].Hash(), *txs[3].Hash(),
+	}
+	_, err := MerkleRootFromProof(
+		txs[0].Hash(), testMerkleBranch(leaves, 0), 4,
+	)
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	// A malformed branch hash is rejected.
+	_, err = MerkleRootFromProof(txs[0].Hash(), []string{"zz"}, 0)
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	// A nil proof is rejected.
+	err = VerifyMerkleProof(txs[0].Hash(), nil, &wire.BlockHeader{})
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+}
+
+// TestCheckHeaderPoW tests the scrypt proof-of-work check.
+func TestCheckHeaderPoW(t *testing.T) {
+	t.Parallel()
+
+	// The mainnet genesis block carries valid proof-of-work.
+	genesis := chaincfg.MainNetParams.GenesisBlock.Header
+	require.NoError(t, CheckHeaderPoW(&genesis, &chaincfg.MainNetParams))
+
+	// Changing the nonce invalidates it.
+	tampered := genesis
+	tampered.Nonce++
+	err := CheckHeaderPoW(&tampered, &chaincfg.MainNetParams)
+	require.ErrorIs(t, err, ErrInvalidProofOfWork)
+
+	// A target easier than the chain's limit is rejected even if the
+	// hash would satisfy it.
+	easy := genesis
+	easy.Bits = chaincfg.RegressionNetParams.PowLimitBits
+	err = CheckHeaderPoW(&easy, &chaincfg.MainNetParams)
+	require.ErrorIs(t, err, ErrInvalidProofOfWork)
+
+	// A zero target is rejected.
+	zero := genesis
+	zero.Bits = 0
+	err = CheckHeaderPoW(&zero, &chaincfg.MainNetParams)
+	require.ErrorIs(t, err, ErrInvalidProofOfWork)
+}
+
+// mineTestHeader grinds the nonce of the header until it satisfies the
+// regtest proof-of-work limit.
+func mineTestHeader(t *testing.T, header *wire.BlockHeader) {
+	t.Helper()
+
+	params := &chaincfg.RegressionNetParams
+	for i := 0; i < 1000; i++ {
+		if CheckHeaderPoW(header, params) == nil {
+			return
+		}
+		header.Nonce++
+	}
+
+	t.Fatalf("unable to mine regtest header")
+}
+
+// testHeaderChain builds a chain of n valid regtest headers on top of the
+// regtest genesis block.
+func testHeaderChain(t *testing.T, n int) []*wire.BlockHeader {
+	t.Helper()
+
+	params := &chaincfg.RegressionNetParams
+	genesis := params.GenesisBlock.Header
+	headers := []*wire.BlockHeader{&genesis}
+
+	for i := 1; i <= n; i++ {
+		prev := headers[i-1]
+		header := &wire.BlockHeader{
+			Version:   0x20000000,
+			PrevBlock: prev.BlockHash(),
+			Timestamp: prev.Timestamp.Add(150 * time.Second),
+			Bits:      params.PowLimitBits,
+		}
+		mineTestHeader(t, header)
+
+		headers = append(headers, header)
+	}
+
+	return headers
+}
+
+// TestHeaderVerifier tests contextual validation of a header chain.
+func TestHeaderVerifier(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+	headers := testHeaderChain(t, 20)
+
+	fetch := func(height int32) (*wire.BlockHeader, error) {
+		if int(height) >= len(headers) || height < 0 {
+			return nil, errors.New("unknown height")
+		}
+
+		return headers[height], nil
+	}
+	verifier := NewHeaderVerifier(params, fetch)
+
+	for i, header := range headers {
+		require.NoError(t, verifier.VerifyHeader(header, int32(i), nil))
+	}
+
+	// A header that doesn't build on its parent is rejected.
+	orphan := *headers[10]
+	orphan.PrevBlock = chainhash.Hash{0x01}
+	mineTestHeader(t, &orphan)
+	err := verifier.VerifyHeader(&orphan, 10, nil)
+	require.ErrorIs(t, err, ErrHeaderNotConnected)
+
+	// A header with unexpected difficulty bits is rejected, even though
+	// its proof-of-work satisfies the target it claims.
+	wrongBits := *headers[10]
+	wrongBits.Bits = 0x1f7fffff
+	for CheckHeaderPoW(&wrongBits, params) != nil {
+		wrongBits.Nonce++
+	}
+	require.Error(t, verifier.VerifyHeader(&wrongBits, 10, nil))
+
+	// A header whose timestamp doesn't move past the median time of
+	// its ancestors is rejected.
+	stale := *headers[10]
+	stale.Timestamp = headers[1].Timestamp
+	mineTestHeader(t, &stale)
+	require.Error(t, verifier.VerifyHeader(&stale, 10, nil))
+
+	// The genesis height only accepts the chain's genesis block.
+	err = verifier.VerifyHeader(headers[1], 0, nil)
+	require.ErrorIs(t, err, ErrHeaderMismatch)
+}
+
+// TestHeaderWalker tests that the header walker fetches the headers in batches
+// that reach below our best height, and serves them from the last batch.
+func TestHeaderWalker(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		start int32
+		count uint32
+	}
+
+	var requests []request
+	fetch := func(start int32, count uint32) ([]*wire.BlockHeader,
+		error) {
+
+		requests = append(requests, request{start, count})
+
+		headers := make([]*wire.BlockHeader, count)
+		for i := range headers {
+			headers[i] = &wire.BlockHeader{
+				Nonce: uint32(start) + uint32(i),
+			}
+		}
+
+		return headers, nil
+	}
+
+	// Walking down from a tip above our best height fetches the missing
+	// headers and a few of ours in a single request.
+	walker := NewHeaderWalker(fetch, 100)
+	for height := int32(105); height >= 100-forkSearchDepth+1; height-- {
+		header, err := walker.Header(height)
+		require.NoError(t, err)
+		require.EqualValues(t, height, header.Nonce)
+	}
+	require.Equal(t, []request{{85, 21}}, requests)
+
+	// Going below the batch fetches the next one.
+	header, err := walker.Header(84)
+	require.NoError(t, err)
+	require.EqualValues(t, 84, header.Nonce)
+	require.Equal(t, request{69, forkSearchDepth}, requests[1])
+
+	// Batches are capped at what the server returns at once.
+	requests = nil
+	walker = NewHeaderWalker(fetch, 0)
+	_, err = walker.Header(5000)
+	require.NoError(t, err)
+	require.Equal(t, []request{{5000 - maxHeaderBatch + 1, maxHeaderBatch}},
+		requests)
+
+	// And never reach below genesis.
+	requests = nil
+	walker = NewHeaderWalker(fetch, 10)
+	_, err = walker.Header(3)
+	require.NoError(t, err)
+	require.Equal(t, []request{{0, 4}}, requests)
+
+	// A server that returns fewer headers than requested is rejected.
+	walker = NewHeaderWalker(func(int32, uint32) ([]*wire.BlockHeader,
+		error) {
+
+		return nil, nil
+	}, 10)
+	_, err = walker.Header(10)
+	require.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package electrum
+
+import (
+	"time"
+
+	"github.com/ltcsuite/lnd/electrum/mwebp2p"
+	"github.com/ltcsuite/lnd/subscribe"
+)
+
+// ServerStatus is a snapshot of the state of one of the Electrum servers of
+// the client's pool.
+type ServerStatus struct {
+	// Addr is the host:port address of the server.
+	Addr string
+
+	// Connected indicates whether the connection to the server is
+	// currently usable.
+	Connected bool
+
+	// Preferred indicates whether the server is the one requests are
+	// currently sent to.
+	Preferred bool
+
+	// ServerVersion is the server's software version string.
+	ServerVersion string
+
+	// ProtocolVersion is the protocol version negotiated with the server.
+	ProtocolVersion string
+
+	// Latency is the moving average of the server's response time.
+	Latency time.Duration
+
+	// Failures is the number of consecutive failed requests.
+	Failures int
+
+	// TipHeight is the height of the latest chain tip announced by the
+	// server.
+	TipHeight int32
+}
+
+// ConnectionEvent is dispatched to the subscribers of
+// SubscribeConnectionEvents when an Electrum server is connected or
+// disconnected.
+type ConnectionEvent struct {
+	// Addr is the host:port address of the server.
+	Addr string
+
+	// Connected is the new state of the connection.
+	Connected bool
+
+	// Err is the error that caused the server to be disconnected, if any.
+	Err error
+
+	// Timestamp is the time at which the state changed.
+	Timestamp time.Time
+}
+
+// status returns a snapshot of the server's state.
+func (s *server) status() ServerStatus {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return ServerStatus{
+		Addr:            s.addr,
+		Connected:       s.connected.Load(),
+		ServerVersion:   s.serverVersion,
+		ProtocolVersion: s.protocolVersion,
+		Latency:         s.latency,
+		Failures:        s.failures,
+		TipHeight:       s.tipHeight,
+	}
+}
+
+// ServerStatuses returns a snapshot of the state of every server of the
+// pool, in the configured order.
+func (c *Client) ServerStatuses() []ServerStatus {
+	preferred := pickServer(c.servers, nil)
+
+	statuses := make([]ServerStatus, 0, len(c.servers))
+	for _, s := range c.servers {
+		status := s.status()
+		status.Preferred = s == preferred
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// SubscribeConnectionEvents returns a subscribe.Client that receives a
+// ConnectionEvent any time one of the servers is connected or disconnected.
+func (c *Client) SubscribeConnectionEvents() (*subscribe.Client, error) {
+	return c.connEvents.Subscribe()
+}
+
+// setConnected updates the connection state of a server and notifies the
+// subscribers if it changed. Err is the reason of a disconnection, if known.
+func (c *Client) setConnected(s *server, connected bool, err error) {
+	if s.connected.Swap(connected) == connected || !c.started.Load() {
+		return
+	}
+
+	event := ConnectionEvent{
+		Addr:      s.addr,
+		Connected: connected,
+		Err:       err,
+		Timestamp: time.Now(),
+	}
+	if err := c.connEvents.SendUpdate(event); err != nil {
+		log.Debugf("Unable to send connection event for %s: %v",
+			s.addr, err)
+	}
+}
+
+// Client returns the Electrum client the chain client is backed by.
+func (c *ChainClient) Client() *Client {
+	return c.client
+}
+
+// SubscribedScripthashes returns the number of scripthashes subscribed to
+// for real-time address monitoring.
+func (c *ChainClient) SubscribedScripthashes() int {
+	c.subscribedMtx.Lock()
+	defer c.subscribedMtx.Unlock()
+
+	return len(c.subscribed)
+}
+
+// HeaderCacheSize returns the number of block headers held in the in-memory
+// header cache.
+func (c *ChainClient) HeaderCacheSize() int {
+	c.headerCacheMtx.RLock()
+	defer c.headerCacheMtx.RUnlock()
+
+	return len(c.headerCache)
+}
+
+// RESTAvailable returns true if a REST API is configured to fetch full
+// blocks from.
+func (c *ChainClient) RESTAvailable() bool {
+	return c.restClient != nil
+}
+
+// MwebSyncHeight returns the height up to which the MWEB UTXO set has been
+// synced. The second return value is false if MWEB sync isn't running.
+func (c *ChainClient) MwebSyncHeight() (uint32, bool) {
+	c.mwebMtx.RLock()
+	coinDB := c.mwebCoinDB
+	c.mwebMtx.RUnlock()
+
+	if coinDB == nil {
+		return 0, false
+	}
+
+	leafset, err := coinDB.GetLeafset()
+	if err != nil {
+		log.Debugf("Unable to fetch MWEB leafset: %v", err)
+		return 0, false
+	}
+
+	return leafset.Height, true
+}
+
+// MwebPeers returns the connected Litecoin P2P peers that serve MWEB data and
+// full blocks.
+func (c *ChainClient) MwebPeers() []*mwebp2p.MwebPeer {
+	if c.p2pService == nil {
+		return nil
+	}
+
+	return c.p2pService.Peers()
+}
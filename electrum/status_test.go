@@ -0,0 +1,91 @@
+package electrum
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestConnectionEvents tests that subscribers are notified of changes to the
+// connection state of the servers, and only of actual changes.
+func TestConnectionEvents(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(&ClientConfig{
+		Servers: []string{"a", "b"},
+	})
+	require.NoError(t, client.connEvents.Start())
+	t.Cleanup(func() {
+		require.NoError(t, client.connEvents.Stop())
+	})
+	client.started.Store(true)
+
+	sub, err := client.SubscribeConnectionEvents()
+	require.NoError(t, err)
+	defer sub.Cancel()
+
+	nextEvent := func() ConnectionEvent {
+		select {
+		case update := <-sub.Updates():
+			event, ok := update.(ConnectionEvent)
+			require.True(t, ok)
+
+			return event
+
+		case <-time.After(time.Second):
+			t.Fatalf("no connection event received")
+			return ConnectionEvent{}
+		}
+	}
+
+	a, b := client.servers[0], client.servers[1]
+	client.setConnected(a, true, nil)
+
+	event := nextEvent()
+	require.Equal(t, "a", event.Addr)
+	require.True(t, event.Connected)
+	require.NoError(t, event.Err)
+
+	// Marking a server with its current state is not an event, so the
+	// next one received is the disconnection.
+	client.setConnected(a, true, nil)
+	client.setConnected(b, false, nil)
+
+	errPing := errors.New("ping failed")
+	client.setConnected(a, false, errPing)
+
+	event = nextEvent()
+	require.Equal(t, "a", event.Addr)
+	require.False(t, event.Connected)
+	require.ErrorIs(t, event.Err, errPing)
+}
+
+// TestServerStatuses tests that the status snapshot lists every server in
+// the configured order and flags the one requests go to.
+func TestServerStatuses(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(&ClientConfig{
+		Servers: []string{"a", "b"},
+	})
+
+	a, b := client.servers[0], client.servers[1]
+	a.connected.Store(true)
+	b.connected.Store(true)
+	a.recordSuccess(200 * time.Millisecond)
+	b.recordSuccess(100 * time.Millisecond)
+	b.setTip(100, "00")
+
+	statuses := client.ServerStatuses()
+	require.Len(t, statuses, 2)
+
+	require.Equal(t, "a", statuses[0].Addr)
+	require.False(t, statuses[0].Preferred)
+	require.Equal(t, 200*time.Millisecond, statuses[0].Latency)
+
+	require.Equal(t, "b", statuses[1].Addr)
+	require.True(t, statuses[1].Preferred)
+	require.EqualValues(t, 100, statuses[1].TipHeight)
+}
@@ -0,0 +1,528 @@
+package electrum
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/checksum0/go-electrum/electrum"
+	"github.com/ltcsuite/lnd/subscribe"
+)
+
+// scripthashAdder subscribes scripthashes on a single server.
+type scripthashAdder interface {
+	// Add subscribes to the given scripthash. The current status of the
+	// scripthash is delivered on the subscription's notification channel.
+	Add(ctx context.Context, scripthash string, address ...string) error
+}
+
+// subscribeServerHeaders subscribes to the block headers announced by the
+// given server.
+func subscribeServerHeaders(ctx context.Context,
+	s *server) (<-chan *electrum.SubscribeHeadersResult, error) {
+
+	client, err := s.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SubscribeHeaders(ctx)
+}
+
+// subscribeServerScripthash creates a scripthash subscription on the given
+// server without subscribing to any scripthash yet.
+func subscribeServerScripthash(s *server) (scripthashAdder,
+	<-chan *electrum.SubscribeNotif, error) {
+
+	client, err := s.getClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sub, notifChan := client.SubscribeScripthash()
+
+	return sub, notifChan, nil
+}
+
+// isConnect returns true if the given connection event reports that a server
+// was connected.
+func isConnect(update interface{}) bool {
+	event, ok := update.(ConnectionEvent)
+
+	return ok && event.Connected
+}
+
+// isDisconnect returns true if the given connection event reports that the
+// server was disconnected.
+func isDisconnect(update interface{}, s *server) bool {
+	event, ok := update.(ConnectionEvent)
+
+	return ok && !event.Connected && event.Addr == s.addr
+}
+
+// SubscribeHeaders subscribes to new block header notifications. The headers
+// are followed on the healthiest server of the pool. If that server drops,
+// the subscription moves on to the next healthy one, which announces its
+// current tip first. The context only bounds the initial subscription
+// request, the returned channel is closed once the client shuts down.
+func (c *Client) SubscribeHeaders(
+	ctx context.Context) (<-chan *electrum.SubscribeHeadersResult, error) {
+
+	s := pickServer(c.servers, nil)
+	if s == nil {
+		return nil, ErrNotConnected
+	}
+
+	events, err := c.connEvents.Subscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := c.subscribeHeaders(ctx, s)
+	if err != nil {
+		events.Cancel()
+		return nil, err
+	}
+
+	headerChan := make(chan *electrum.SubscribeHeadersResult, 1)
+
+	c.wg.Add(1)
+	go c.forwardHeaders(s, headers, headerChan, events)
+
+	return headerChan, nil
+}
+
+// forwardHeaders forwards the headers announced by the server the header
+// subscription is bound to, and subscribes on the next healthy server when
+// that one drops or closes the subscription.
+func (c *Client) forwardHeaders(s *server,
+	headers <-chan *electrum.SubscribeHeadersResult,
+	headerChan chan<- *electrum.SubscribeHeadersResult,
+	events *subscribe.Client) {
+
+	defer c.wg.Done()
+	defer close(headerChan)
+	defer events.Cancel()
+
+	// failover subscribes to the headers of the healthiest server other
+	// than the one that just dropped. It returns false if the client
+	// shut down first.
+	failover := func() bool {
+		exclude := map[*server]struct{}{s: {}}
+		for {
+			next := c.nextServer(exclude, events)
+			if next == nil {
+				return false
+			}
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(), c.cfg.RequestTimeout,
+			)
+			nextHeaders, err := c.subscribeHeaders(ctx, next)
+			cancel()
+			if err != nil {
+				log.Warnf("Unable to subscribe to headers of "+
+					"Electrum server %s: %v", next.addr, err)
+
+				next.recordFailure()
+				exclude[next] = struct{}{}
+
+				continue
+			}
+
+			log.Infof("Header subscription moved from Electrum "+
+				"server %s to %s", s.addr, next.addr)
+
+			s, headers = next, nextHeaders
+
+			return true
+		}
+	}
+
+	for {
+		select {
+		case header, ok := <-headers:
+			if !ok {
+				if !failover() {
+					return
+				}
+				continue
+			}
+
+			select {
+			case headerChan <- header:
+			case <-c.quit:
+				return
+			}
+
+		case update := <-events.Updates():
+			if isDisconnect(update, s) && !failover() {
+				return
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// nextServer returns the healthiest connected server that isn't excluded. If
+// there's none, it waits for a server to connect or for the reconnect
+// interval to pass and then considers every server again. Nil is returned if
+// the client shuts down first.
+func (c *Client) nextServer(exclude map[*server]struct{},
+	events *subscribe.Client) *server {
+
+	for {
+		if s := pickServer(c.servers, exclude); s != nil {
+			return s
+		}
+
+		select {
+		case <-events.Updates():
+		case <-time.After(c.cfg.ReconnectInterval):
+		case <-c.quit:
+			return nil
+		}
+
+		clear(exclude)
+	}
+}
+
+// InitScripthashSubscription creates a scripthash subscription object and
+// notification channel without subscribing to any specific scripthash yet.
+// Use subscription.Add() to subscribe to individual scripthashes later.
+func (c *Client) InitScripthashSubscription() (*ScripthashSubscription,
+	<-chan *electrum.SubscribeNotif, error) {
+
+	s := pickServer(c.servers, nil)
+	if s == nil {
+		return nil, nil, ErrNotConnected
+	}
+
+	events, err := c.connEvents.Subscribe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sub := &ScripthashSubscription{
+		client:       c,
+		events:       events,
+		notifChan:    make(chan *electrum.SubscribeNotif, 1),
+		dropped:      make(chan *serverScripthashSub),
+		scripthashes: make(map[string]string),
+	}
+
+	serverSub, err := sub.subscribe(s)
+	if err != nil {
+		events.Cancel()
+		return nil, nil, err
+	}
+	sub.current = serverSub
+
+	c.wg.Add(1)
+	go sub.failoverHandler()
+
+	return sub, sub.notifChan, nil
+}
+
+// serverScripthashSub is the scripthash subscription on a single server.
+type serverScripthashSub struct {
+	server *server
+	sub    scripthashAdder
+
+	// quit is closed when the subscription is replaced.
+	quit chan struct{}
+}
+
+// ScripthashSubscription multiplexes the scripthash subscriptions of the
+// client onto one notification channel. The scripthashes are subscribed on
+// the healthiest server of the pool. If that server drops, all of them are
+// subscribed again on the next healthy one.
+type ScripthashSubscription struct {
+	client *Client
+	events *subscribe.Client
+
+	notifChan chan *electrum.SubscribeNotif
+
+	// dropped receives the server subscriptions whose notification
+	// channel was closed.
+	dropped chan *serverScripthashSub
+
+	// mtx guards the fields below.
+	mtx sync.Mutex
+
+	// scripthashes maps the subscribed scripthashes to their address, if
+	// one was given.
+	scripthashes map[string]string
+
+	// current is the subscription on the server the scripthashes are
+	// subscribed on. It is nil while no server is connected.
+	current *serverScripthashSub
+}
+
+// Add subscribes to the given scripthash. The current status of the
+// scripthash is delivered on the notification channel.
+func (s *ScripthashSubscription) Add(ctx context.Context, scripthash string,
+	address ...string) error {
+
+	// Track the scripthash before subscribing, so it is subscribed again
+	// if the server drops while it's being added.
+	s.mtx.Lock()
+	s.scripthashes[scripthash] = ""
+	if len(address) > 0 {
+		s.scripthashes[scripthash] = address[0]
+	}
+	current := s.current
+	s.mtx.Unlock()
+
+	// Without a server, the scripthash is subscribed once the next one
+	// connects.
+	if current == nil {
+		return nil
+	}
+
+	if err := current.sub.Add(ctx, scripthash, address...); err != nil {
+		s.forget(scripthash, current)
+		return err
+	}
+
+	return nil
+}
+
+// forget stops tracking a scripthash that couldn't be subscribed, unless the
+// subscription moved to another server in the meantime, which subscribed it
+// already.
+func (s *ScripthashSubscription) forget(scripthash string,
+	attempted *serverScripthashSub) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.current == attempted {
+		delete(s.scripthashes, scripthash)
+	}
+}
+
+// GetAddress returns the address the given scripthash was subscribed for.
+func (s *ScripthashSubscription) GetAddress(scripthash string) (string,
+	error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	address, ok := s.scripthashes[scripthash]
+	if !ok || address == "" {
+		return "", fmt.Errorf("scripthash %s not subscribed",
+			scripthash)
+	}
+
+	return address, nil
+}
+
+// subscribe creates a scripthash subscription on the given server and starts
+// forwarding its notifications.
+func (s *ScripthashSubscription) subscribe(
+	srv *server) (*serverScripthashSub, error) {
+
+	sub, notifChan, err := s.client.subscribeScripthash(srv)
+	if err != nil {
+		return nil, err
+	}
+
+	serverSub := &serverScripthashSub{
+		server: srv,
+		sub:    sub,
+		quit:   make(chan struct{}),
+	}
+
+	s.client.wg.Add(1)
+	go s.forwardNotifs(serverSub, notifChan)
+
+	return serverSub, nil
+}
+
+// forwardNotifs forwards the notifications of a server's subscription until
+// the subscription is replaced or the client shuts down. The failover handler
+// is told if the server closes the notification channel.
+func (s *ScripthashSubscription) forwardNotifs(serverSub *serverScripthashSub,
+	notifChan <-chan *electrum.SubscribeNotif) {
+
+	defer s.client.wg.Done()
+
+	quit := serverSub.quit
+	for {
+		select {
+		case notif, ok := <-notifChan:
+			if !ok {
+				select {
+				case s.dropped <- serverSub:
+				case <-quit:
+				case <-s.client.quit:
+				}
+
+				return
+			}
+
+			select {
+			case s.notifChan <- notif:
+			case <-quit:
+				return
+			case <-s.client.quit:
+				return
+			}
+
+		case <-quit:
+			return
+
+		case <-s.client.quit:
+			return
+		}
+	}
+}
+
+// failoverHandler moves the scripthash subscriptions to the next healthy
+// server whenever the server they're subscribed on drops.
+func (s *ScripthashSubscription) failoverHandler() {
+	defer s.client.wg.Done()
+	defer s.events.Cancel()
+
+	for {
+		select {
+		case dropped := <-s.dropped:
+			s.mtx.Lock()
+			current := s.current
+			s.mtx.Unlock()
+
+			if dropped == current {
+				s.failover(current)
+			}
+
+		case update := <-s.events.Updates():
+			s.mtx.Lock()
+			current := s.current
+			s.mtx.Unlock()
+
+			// Without a server, wait for one to connect. Otherwise
+			// there's nothing to do as long as the server is up.
+			if current == nil && !isConnect(update) {
+				continue
+			}
+			if current != nil && !isDisconnect(update, current.server) {
+				continue
+			}
+
+			s.failover(current)
+
+		case <-s.client.quit:
+			return
+		}
+	}
+}
+
+// failover subscribes all tracked scripthashes on the healthiest connected
+// server other than the dropped one, trying the servers in turn until one
+// accepts them all. If none does, the subscription is left without a server
+// until the next one connects.
+func (s *ScripthashSubscription) failover(dropped *serverScripthashSub) {
+	exclude := make(map[*server]struct{})
+	if dropped != nil {
+		close(dropped.quit)
+		exclude[dropped.server] = struct{}{}
+	}
+
+	s.mtx.Lock()
+	s.current = nil
+	s.mtx.Unlock()
+
+	for {
+		next := pickServer(s.client.servers, exclude)
+		if next == nil {
+			log.Warnf("No Electrum server available to subscribe " +
+				"scripthashes on, waiting for one to connect")
+
+			return
+		}
+		exclude[next] = struct{}{}
+
+		serverSub, err := s.subscribe(next)
+		if err != nil {
+			log.Warnf("Unable to subscribe to scripthashes on "+
+				"Electrum server %s: %v", next.addr, err)
+
+			next.recordFailure()
+			continue
+		}
+
+		if err := s.resubscribe(serverSub); err != nil {
+			log.Warnf("Unable to subscribe to scripthashes on "+
+				"Electrum server %s: %v", next.addr, err)
+
+			close(serverSub.quit)
+			next.recordFailure()
+			continue
+		}
+
+		log.Infof("Scripthash subscriptions moved to Electrum "+
+			"server %s", next.addr)
+
+		return
+	}
+}
+
+// resubscribe subscribes all tracked scripthashes on the given server's
+// subscription and makes it the current one. Scripthashes added while this
+// is in progress are picked up before it returns.
+func (s *ScripthashSubscription) resubscribe(
+	serverSub *serverScripthashSub) error {
+
+	done := make(map[string]struct{})
+	for {
+		s.mtx.Lock()
+		var pending map[string]string
+		for scripthash, address := range s.scripthashes {
+			if _, ok := done[scripthash]; ok {
+				continue
+			}
+			if pending == nil {
+				pending = make(map[string]string)
+			}
+			pending[scripthash] = address
+		}
+
+		// Everything is subscribed, so new scripthashes can be added
+		// to the server's subscription directly.
+		if len(pending) == 0 {
+			s.current = serverSub
+			s.mtx.Unlock()
+
+			return nil
+		}
+		s.mtx.Unlock()
+
+		for scripthash, address := range pending {
+			err := s.add(serverSub, scripthash, address)
+			if err != nil {
+				return err
+			}
+			done[scripthash] = struct{}{}
+		}
+	}
+}
+
+// add subscribes a single scripthash on the given server's subscription.
+func (s *ScripthashSubscription) add(serverSub *serverScripthashSub,
+	scripthash, address string) error {
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), s.client.cfg.RequestTimeout,
+	)
+	defer cancel()
+
+	if address == "" {
+		return serverSub.sub.Add(ctx, scripthash)
+	}
+
+	return serverSub.sub.Add(ctx, scripthash, address)
+}
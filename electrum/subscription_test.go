@@ -0,0 +1,186 @@
+package electrum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/checksum0/go-electrum/electrum"
+	"github.com/stretchr/testify/require"
+)
+
+// mockScripthashSub is a scripthash subscription on a single mock server.
+type mockScripthashSub struct {
+	notifChan chan *electrum.SubscribeNotif
+	added     chan string
+}
+
+func newMockScripthashSub() *mockScripthashSub {
+	return &mockScripthashSub{
+		notifChan: make(chan *electrum.SubscribeNotif, 1),
+		added:     make(chan string, 10),
+	}
+}
+
+func (m *mockScripthashSub) Add(_ context.Context, scripthash string,
+	_ ...string) error {
+
+	m.added <- scripthash
+
+	return nil
+}
+
+// newFailoverTestClient returns a started client with a pool of two
+// connected servers, the first of which is preferred.
+func newFailoverTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	client := NewClient(&ClientConfig{
+		Servers:           []string{"a", "b"},
+		RequestTimeout:    time.Second,
+		ReconnectInterval: time.Second,
+	})
+	require.NoError(t, client.connEvents.Start())
+	client.started.Store(true)
+	t.Cleanup(func() {
+		close(client.quit)
+		client.wg.Wait()
+		require.NoError(t, client.connEvents.Stop())
+	})
+
+	a, b := client.servers[0], client.servers[1]
+	a.connected.Store(true)
+	b.connected.Store(true)
+	a.recordSuccess(100 * time.Millisecond)
+	b.recordSuccess(200 * time.Millisecond)
+
+	return client
+}
+
+// receive returns the next value sent on the given channel.
+func receive[T any](t *testing.T, c <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-c:
+		return v
+
+	case <-time.After(time.Second):
+		t.Fatalf("nothing received")
+
+		var zero T
+		return zero
+	}
+}
+
+// TestSubscribeHeadersFailover tests that the header subscription moves to
+// the next healthy server when the one it's bound to drops, or closes the
+// subscription.
+func TestSubscribeHeadersFailover(t *testing.T) {
+	t.Parallel()
+
+	client := newFailoverTestClient(t)
+	a := client.servers[0]
+
+	headers := map[string]chan *electrum.SubscribeHeadersResult{
+		"a": make(chan *electrum.SubscribeHeadersResult, 1),
+		"b": make(chan *electrum.SubscribeHeadersResult, 1),
+	}
+	client.subscribeHeaders = func(_ context.Context,
+		s *server) (<-chan *electrum.SubscribeHeadersResult, error) {
+
+		return headers[s.addr], nil
+	}
+
+	headerChan, err := client.SubscribeHeaders(context.Background())
+	require.NoError(t, err)
+
+	headers["a"] <- &electrum.SubscribeHeadersResult{Height: 100}
+	require.EqualValues(t, 100, receive(t, headerChan).Height)
+
+	// The first server drops, so the headers are followed on the second
+	// one from now on.
+	client.setConnected(a, false, errors.New("ping failed"))
+	headers["b"] <- &electrum.SubscribeHeadersResult{Height: 101}
+	require.EqualValues(t, 101, receive(t, headerChan).Height)
+
+	// Once the first server is back, it takes over when the second one
+	// closes the subscription.
+	headers["a"] = make(chan *electrum.SubscribeHeadersResult, 1)
+	client.setConnected(a, true, nil)
+	close(headers["b"])
+
+	headers["a"] <- &electrum.SubscribeHeadersResult{Height: 102}
+	require.EqualValues(t, 102, receive(t, headerChan).Height)
+}
+
+// TestScripthashSubscriptionFailover tests that all tracked scripthashes are
+// subscribed again on the next healthy server when the one they're
+// subscribed on drops, or closes the subscription.
+func TestScripthashSubscriptionFailover(t *testing.T) {
+	t.Parallel()
+
+	client := newFailoverTestClient(t)
+	a := client.servers[0]
+
+	subs := map[string]*mockScripthashSub{
+		"a": newMockScripthashSub(),
+		"b": newMockScripthashSub(),
+	}
+	client.subscribeScripthash = func(s *server) (scripthashAdder,
+		<-chan *electrum.SubscribeNotif, error) {
+
+		sub := subs[s.addr]
+
+		return sub, sub.notifChan, nil
+	}
+
+	sub, notifChan, err := client.InitScripthashSubscription()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, sub.Add(ctx, "sh1", "addr1"))
+	require.NoError(t, sub.Add(ctx, "sh2"))
+	require.Equal(t, "sh1", receive(t, subs["a"].added))
+	require.Equal(t, "sh2", receive(t, subs["a"].added))
+
+	notif := &electrum.SubscribeNotif{Params: [2]string{"sh1", "x"}}
+	subs["a"].notifChan <- notif
+	require.Equal(t, notif, receive(t, notifChan))
+
+	// The first server drops, so both scripthashes are subscribed on the
+	// second one, whose notifications are delivered from now on.
+	client.setConnected(a, false, nil)
+	require.ElementsMatch(t, []string{"sh1", "sh2"}, []string{
+		receive(t, subs["b"].added), receive(t, subs["b"].added),
+	})
+
+	notif = &electrum.SubscribeNotif{Params: [2]string{"sh2", "y"}}
+	subs["b"].notifChan <- notif
+	require.Equal(t, notif, receive(t, notifChan))
+
+	require.NoError(t, sub.Add(ctx, "sh3", "addr3"))
+	require.Equal(t, "sh3", receive(t, subs["b"].added))
+
+	// Once the first server is back, it takes over when the second one
+	// closes the subscription.
+	subs["a"] = newMockScripthashSub()
+	client.setConnected(a, true, nil)
+	close(subs["b"].notifChan)
+
+	require.ElementsMatch(t, []string{"sh1", "sh2", "sh3"}, []string{
+		receive(t, subs["a"].added), receive(t, subs["a"].added),
+		receive(t, subs["a"].added),
+	})
+
+	address, err := sub.GetAddress("sh1")
+	require.NoError(t, err)
+	require.Equal(t, "addr1", address)
+	address, err = sub.GetAddress("sh3")
+	require.NoError(t, err)
+	require.Equal(t, "addr3", address)
+
+	_, err = sub.GetAddress("sh2")
+	require.Error(t, err)
+}
@@ -79,6 +79,13 @@ var deps = depDesc{
 		lnwire.AnchorsZeroFeeHtlcTxOptional: {},
 		lnwire.ExplicitChannelTypeOptional:  {},
 	},
+	lnwire.RouteBlindingOptional: {
+		lnwire.TLVOnionPayloadOptional: {},
+	},
+	lnwire.DynamicCommitmentsOptional: {
+		lnwire.QuiescenceOptional:          {},
+		lnwire.ExplicitChannelTypeOptional: {},
+	},
 }
 
 // ValidateDeps asserts that a feature vector sets all features and their
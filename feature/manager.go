@@ -60,6 +60,27 @@ type Config struct {
 	// segwit witness versions for co-op closes.
 	NoAnySegwit bool
 
+	// NoRouteBlinding unsets route blinding feature bits.
+	NoRouteBlinding bool
+
+	// NoOnionMessages unsets onion message feature bits.
+	NoOnionMessages bool
+
+	// NoSplicing unsets any bits signaling support for splicing.
+	NoSplicing bool
+
+	// NoDualFunding unsets any bits signaling support for dual funded
+	// channels.
+	NoDualFunding bool
+
+	// NoQuiescence unsets any bits signaling support for the quiescence
+	// protocol.
+	NoQuiescence bool
+
+	// NoDynamicCommitments unsets any bits signaling support for
+	// upgrading the channel type of live channels.
+	NoDynamicCommitments bool
+
 	// CustomFeatures is a set of custom features to advertise in each
 	// set.
 	CustomFeatures map[Set][]lnwire.FeatureBit
@@ -179,6 +200,30 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.SimpleTaprootChannelsOptionalStaging)
 			raw.Unset(lnwire.SimpleTaprootChannelsRequiredStaging)
 		}
+		if cfg.NoRouteBlinding {
+			raw.Unset(lnwire.RouteBlindingOptional)
+			raw.Unset(lnwire.RouteBlindingRequired)
+		}
+		if cfg.NoOnionMessages {
+			raw.Unset(lnwire.OnionMessagesOptional)
+			raw.Unset(lnwire.OnionMessagesRequired)
+		}
+		if cfg.NoSplicing {
+			raw.Unset(lnwire.SpliceOptional)
+			raw.Unset(lnwire.SpliceRequired)
+		}
+		if cfg.NoDualFunding {
+			raw.Unset(lnwire.DualFundOptional)
+			raw.Unset(lnwire.DualFundRequired)
+		}
+		if cfg.NoQuiescence {
+			raw.Unset(lnwire.QuiescenceOptional)
+			raw.Unset(lnwire.QuiescenceRequired)
+		}
+		if cfg.NoDynamicCommitments {
+			raw.Unset(lnwire.DynamicCommitmentsOptional)
+			raw.Unset(lnwire.DynamicCommitmentsRequired)
+		}
 
 		for _, custom := range cfg.CustomFeatures[set] {
 			if custom > set.Maximum() {
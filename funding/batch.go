@@ -315,8 +315,11 @@ func (b *Batcher) BatchFund(ctx context.Context,
 	}
 
 	// We can now assemble all outputs that we're going to give to the PSBT
-	// funding method of the wallet kit server.
+	// funding method of the wallet kit server. If the coins to fund them
+	// with were selected manually, they are the inputs. Otherwise the
+	// wallet selects them.
 	txTemplate := &walletrpc.TxTemplate{
+		Inputs:  req.Outpoints,
 		Outputs: make(map[string]uint64),
 	}
 	for _, channel := range b.channels {
@@ -347,7 +350,7 @@ func (b *Batcher) BatchFund(ctx context.Context,
 	fundPsbtResp, err := b.cfg.WalletKitServer.FundPsbt(ctx, fundPsbtReq)
 	if err != nil {
 		return nil, fmt.Errorf("error funding PSBT for batch channel "+
-			"open: %v", err)
+			"open: %w", err)
 	}
 
 	// Funding was successful. This means there are some UTXOs that are now
@@ -373,7 +376,7 @@ func (b *Batcher) BatchFund(ctx context.Context,
 			channel.pendingChanID, unsignedPacket, false,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error verifying PSBT: %v", err)
+			return nil, fmt.Errorf("error verifying PSBT: %w", err)
 		}
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/ltcsuite/lnd/lnrpc"
 	"github.com/ltcsuite/lnd/lnrpc/walletrpc"
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
@@ -51,6 +52,9 @@ type testHarness struct {
 	failUpdate2 bool
 	failPublish bool
 
+	// fundErr is the error returned when funding the batch PSBT, if set.
+	fundErr error
+
 	intentsCreated    map[[32]byte]*fundingIntent
 	intentsCanceled   map[[32]byte]struct{}
 	abandonedChannels map[wire.OutPoint]struct{}
@@ -172,6 +176,10 @@ func (h *testHarness) abandonChannel(op *wire.OutPoint) error {
 func (h *testHarness) FundPsbt(context.Context,
 	*walletrpc.FundPsbtRequest) (*walletrpc.FundPsbtResponse, error) {
 
+	if h.fundErr != nil {
+		return nil, h.fundErr
+	}
+
 	// Create PSBTv2 inputs from the transaction inputs
 	var psbtInputs []psbt.PInput
 	for _, txIn := range h.pendingTx.TxIn {
@@ -444,3 +452,48 @@ func TestBatchFund(t *testing.T) {
 		})
 	}
 }
+
+// TestBatchFundMwebFunding asserts that a batch that can only be funded with
+// MWEB coins fails with an error that identifies the coins to peg out, and
+// that the channels of the batch are cleaned up.
+func TestBatchFundMwebFunding(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t, false, false, false)
+	h.fundErr = fmt.Errorf("unable to fund psbt: %w",
+		&chanfunding.ErrMwebFunding{
+			Coins: []chanfunding.Coin{{
+				TxOut: wire.TxOut{
+					Value: ltcutil.SatoshiPerBitcoin,
+				},
+				OutPoint: testOutPoint,
+			}},
+		},
+	)
+
+	req := &lnrpc.BatchOpenChannelRequest{
+		Channels: []*lnrpc.BatchOpenChannel{{
+			NodePubkey:         testPubKey1Bytes,
+			LocalFundingAmount: 1234,
+		}, {
+			NodePubkey:         testPubKey2Bytes,
+			LocalFundingAmount: 4321,
+		}},
+		SatPerVbyte: 5,
+		MinConfs:    1,
+	}
+	_, err := h.batcher.BatchFund(context.Background(), req)
+
+	var mwebErr *chanfunding.ErrMwebFunding
+	require.ErrorAs(t, err, &mwebErr)
+	require.Len(t, mwebErr.Coins, 1)
+	require.Equal(t, testOutPoint, mwebErr.Coins[0].OutPoint)
+
+	// None of the channels got past the funding step, so all of their
+	// intents are canceled.
+	require.Len(t, h.intentsCreated, 2)
+	for pid := range h.intentsCreated {
+		require.Contains(t, h.intentsCanceled, pid)
+	}
+	require.Empty(t, h.releasedUTXOs)
+}
@@ -0,0 +1,1979 @@
+package funding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/chainntnfs"
+	"github.com/ltcsuite/lnd/chanacceptor"
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channeldb/models"
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/keychain"
+	"github.com/ltcsuite/lnd/labels"
+	"github.com/ltcsuite/lnd/lnpeer"
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/lnd/lnwallet/interactivetx"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+var (
+	// ErrFundingReplaced is returned when waiting for the funding
+	// transaction of a dual funded channel is canceled, as a conflicting
+	// funding transaction of the same channel confirmed instead.
+	ErrFundingReplaced = errors.New("funding transaction was replaced")
+
+	// ErrDualFundingUnsupported is returned when a dual funded channel is
+	// opened with a peer that doesn't support dual funding.
+	ErrDualFundingUnsupported = errors.New("dual funding not supported " +
+		"by peer")
+
+	// ErrReplacementFeeRateTooLow is returned when the fee rate of a
+	// transaction replacing a funding transaction isn't at least 25/24 of
+	// the fee rate of the replaced one.
+	ErrReplacementFeeRateTooLow = errors.New("replacement fee rate too " +
+		"low")
+)
+
+const (
+	// dualFundingSequence is the sequence of all inputs of the funding
+	// transaction of a dual funded channel. It signals replaceability, so
+	// the transaction can be fee bumped later on.
+	dualFundingSequence = wire.MaxTxInSequenceNum - 2
+)
+
+// BumpFundingMsg is a request to replace the funding transaction of a pending
+// dual funded channel with one paying a higher fee rate. The transaction is
+// constructed with the remote peer again, and both peers keep their
+// contributions to the channel.
+type BumpFundingMsg struct {
+	// Peer is the peer of the channel.
+	Peer lnpeer.Peer
+
+	// ChanPoint is the channel point of the pending channel.
+	ChanPoint wire.OutPoint
+
+	// FeeRate is the fee rate of the replacement transaction.
+	FeeRate chainfee.SatPerKWeight
+
+	// Txid is sent upon with the txid of the replacement transaction once
+	// it was broadcast.
+	Txid chan chainhash.Hash
+
+	// Err is sent upon if the replacement fails.
+	Err chan error
+}
+
+// fundingCoin is a wallet coin spent by the funding transaction of a dual
+// funded channel.
+type fundingCoin struct {
+	// prevTx is the transaction that created the coin.
+	prevTx *wire.MsgTx
+
+	// index is the output index of the coin within prevTx.
+	index uint32
+}
+
+// interactiveFunding houses the state of the interactive construction and
+// signing of the funding transaction of a dual funded channel. It is used for
+// the initial funding transaction, as well as for any replacement of it.
+type interactiveFunding struct {
+	// chanID is the channel ID used in the messages of the construction.
+	// This is the pending channel ID for the initial funding transaction,
+	// and the channel ID of the replaced funding transaction otherwise.
+	chanID lnwire.ChannelID
+
+	// pendingChanID is the pending channel ID of the channel.
+	pendingChanID [32]byte
+
+	// peer is the remote peer of the channel.
+	peer lnpeer.Peer
+
+	// isInitiator is true if we opened the channel.
+	isInitiator bool
+
+	// localAmt and remoteAmt are the amounts we and the remote peer
+	// contribute to the channel.
+	localAmt  ltcutil.Amount
+	remoteAmt ltcutil.Amount
+
+	// capacity is the capacity of the channel.
+	capacity ltcutil.Amount
+
+	// feeRate is the fee rate of the funding transaction.
+	feeRate chainfee.SatPerKWeight
+
+	// locktime is the locktime of the funding transaction.
+	locktime uint32
+
+	// fundingScript is the pkScript of the funding output.
+	fundingScript []byte
+
+	// intent holds the wallet coins we selected for our contribution. It
+	// is nil for a replacement, which spends the same coins again.
+	intent *chanfunding.FullIntent
+
+	// coins and change are the inputs and change outputs we contribute to
+	// the funding transaction.
+	coins  []fundingCoin
+	change []*wire.TxOut
+
+	// session is the interactive construction of the funding transaction.
+	session *interactivetx.Session
+
+	// pendingMsgs produce the construction messages that we still have
+	// to send, one for each of our turns. The contributions are only
+	// added to the session once they're sent.
+	pendingMsgs []func() (lnwire.Message, error)
+
+	// fundingTx and fundingPoint are the funding transaction and the
+	// funding outpoint once the construction completed.
+	fundingTx    *wire.MsgTx
+	fundingPoint wire.OutPoint
+
+	// resCtx is the reservation of the channel, until the channel was
+	// persisted. It is nil for a replacement.
+	resCtx *reservationWithCtx
+
+	// prevChannel is the pending channel whose funding transaction is
+	// replaced, and lnChannel its state machine used to sign the
+	// commitments spending the new funding output.
+	prevChannel *channeldb.OpenChannel
+	lnChannel   *lnwallet.LightningChannel
+
+	// remoteCommit is the commitment of the remote peer spending the new
+	// funding output of a replacement.
+	remoteCommit *channeldb.ChannelCommitment
+
+	// channel is the pending channel once both commitments are signed.
+	channel *channeldb.OpenChannel
+
+	// sentTxSigs is true once we sent our tx_signatures.
+	sentTxSigs bool
+
+	// updates and errChan are used to notify the opener of the channel.
+	updates chan *lnrpc.OpenStatusUpdate
+	errChan chan error
+
+	// bumpReq is the local request that started a replacement.
+	bumpReq *BumpFundingMsg
+}
+
+// cancel releases the wallet coins we selected for our contribution.
+func (i *interactiveFunding) cancel() {
+	if i.intent != nil {
+		i.intent.Cancel()
+		i.intent = nil
+	}
+}
+
+// notifyErr reports the passed error to the local caller, if any.
+func (i *interactiveFunding) notifyErr(err error) {
+	errChan := i.errChan
+	if i.bumpReq != nil {
+		errChan = i.bumpReq.Err
+	}
+	if errChan == nil {
+		return
+	}
+
+	select {
+	case errChan <- err:
+	default:
+	}
+}
+
+// spliceParams describes the funding output of a replacement transaction.
+// Both balances remain unchanged.
+func (i *interactiveFunding) spliceParams() *lnwallet.SpliceParams {
+	return &lnwallet.SpliceParams{
+		FundingOutpoint: i.fundingPoint,
+		Capacity:        i.capacity,
+	}
+}
+
+// validateDualFundingMsg checks that the request to open a channel only uses
+// options that are supported for dual funded channels.
+func validateDualFundingMsg(msg *InitFundingMsg) error {
+	if !hasFeatures(
+		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	) {
+
+		return ErrDualFundingUnsupported
+	}
+
+	switch {
+	case msg.PushAmt != 0:
+		return fmt.Errorf("dual funded channels can't push funds")
+
+	case msg.SubtractFees, msg.FundUpToMaxAmt != 0:
+		return fmt.Errorf("dual funded channels need a fixed local " +
+			"funding amount")
+
+	case len(msg.Outpoints) != 0, msg.ChanFunder != nil:
+		return fmt.Errorf("dual funded channels are funded by the " +
+			"internal wallet")
+
+	case msg.RemoteChanReserve != 0:
+		return fmt.Errorf("the channel reserve of dual funded " +
+			"channels can't be set")
+	}
+
+	return nil
+}
+
+// checkDualFundedCommitType returns an error if the negotiated channel type
+// isn't supported for dual funded channels.
+func checkDualFundedCommitType(commitType lnwallet.CommitmentType,
+	zeroConf bool) error {
+
+	switch {
+	case commitType.IsTaproot():
+		return fmt.Errorf("dual funded taproot channels are not " +
+			"supported")
+
+	case commitType == lnwallet.CommitmentTypeScriptEnforcedLease:
+		return fmt.Errorf("dual funded channel leases are not " +
+			"supported")
+
+	case zeroConf:
+		return fmt.Errorf("dual funded zero-conf channels are not " +
+			"supported")
+	}
+
+	return nil
+}
+
+// newOpenChannel2 returns the OpenChannel2 message that opens a dual funded
+// channel with the parameters of the passed OpenChannel message.
+func newOpenChannel2(open *lnwire.OpenChannel,
+	fund *interactiveFunding) *lnwire.OpenChannel2 {
+
+	return &lnwire.OpenChannel2{
+		ChainHash:             open.ChainHash,
+		PendingChannelID:      open.PendingChannelID,
+		FundingFeePerKWeight:  uint32(fund.feeRate),
+		CommitFeePerKWeight:   open.FeePerKiloWeight,
+		FundingAmount:         open.FundingAmount,
+		DustLimit:             open.DustLimit,
+		MaxValueInFlight:      open.MaxValueInFlight,
+		HtlcMinimum:           open.HtlcMinimum,
+		CsvDelay:              open.CsvDelay,
+		MaxAcceptedHTLCs:      open.MaxAcceptedHTLCs,
+		Locktime:              fund.locktime,
+		FundingKey:            open.FundingKey,
+		RevocationPoint:       open.RevocationPoint,
+		PaymentPoint:          open.PaymentPoint,
+		DelayedPaymentPoint:   open.DelayedPaymentPoint,
+		HtlcPoint:             open.HtlcPoint,
+		FirstCommitmentPoint:  open.FirstCommitmentPoint,
+		ChannelFlags:          open.ChannelFlags,
+		UpfrontShutdownScript: open.UpfrontShutdownScript,
+		ChannelType:           open.ChannelType,
+	}
+}
+
+// openChannelView returns the OpenChannel message equivalent to the passed
+// OpenChannel2 message, which is presented to the channel acceptor.
+func openChannelView(msg *lnwire.OpenChannel2) *lnwire.OpenChannel {
+	return &lnwire.OpenChannel{
+		ChainHash:             msg.ChainHash,
+		PendingChannelID:      msg.PendingChannelID,
+		FundingAmount:         msg.FundingAmount,
+		DustLimit:             msg.DustLimit,
+		MaxValueInFlight:      msg.MaxValueInFlight,
+		HtlcMinimum:           msg.HtlcMinimum,
+		FeePerKiloWeight:      msg.CommitFeePerKWeight,
+		CsvDelay:              msg.CsvDelay,
+		MaxAcceptedHTLCs:      msg.MaxAcceptedHTLCs,
+		FundingKey:            msg.FundingKey,
+		RevocationPoint:       msg.RevocationPoint,
+		PaymentPoint:          msg.PaymentPoint,
+		DelayedPaymentPoint:   msg.DelayedPaymentPoint,
+		HtlcPoint:             msg.HtlcPoint,
+		FirstCommitmentPoint:  msg.FirstCommitmentPoint,
+		ChannelFlags:          msg.ChannelFlags,
+		UpfrontShutdownScript: msg.UpfrontShutdownScript,
+		ChannelType:           msg.ChannelType,
+	}
+}
+
+// fundingDustLimit returns the minimum value of the outputs of the funding
+// transaction of a dual funded channel.
+func fundingDustLimit() ltcutil.Amount {
+	return lnwallet.DustLimitForSize(input.P2WSHSize)
+}
+
+// isFundingTxSigned returns true if the passed funding transaction of a dual
+// funded channel has been signed by both parties.
+func isFundingTxSigned(tx *wire.MsgTx) bool {
+	if tx == nil || len(tx.TxIn) == 0 {
+		return false
+	}
+
+	for _, txIn := range tx.TxIn {
+		if len(txIn.Witness) == 0 && len(txIn.SignatureScript) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sharesInput returns true if both transactions spend the same outpoint, which
+// means that only one of them can confirm.
+func sharesInput(a, b *wire.MsgTx) bool {
+	spent := make(map[wire.OutPoint]struct{}, len(a.TxIn))
+	for _, txIn := range a.TxIn {
+		spent[txIn.PreviousOutPoint] = struct{}{}
+	}
+
+	for _, txIn := range b.TxIn {
+		if _, ok := spent[txIn.PreviousOutPoint]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newInteractiveFunding selects the wallet coins for our contribution to a
+// new dual funded channel.
+func (f *Manager) newInteractiveFunding(peer lnpeer.Peer,
+	pendingChanID [32]byte, isInitiator bool, amt ltcutil.Amount,
+	feeRate chainfee.SatPerKWeight) (*interactiveFunding, error) {
+
+	_, height, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	fund := &interactiveFunding{
+		chanID:        pendingChanID,
+		pendingChanID: pendingChanID,
+		peer:          peer,
+		isInitiator:   isInitiator,
+		localAmt:      amt,
+		feeRate:       feeRate,
+		locktime:      uint32(height),
+	}
+
+	// The acceptor of a dual funded channel doesn't need to contribute
+	// anything.
+	if amt == 0 {
+		return fund, nil
+	}
+
+	intent, err := f.cfg.Wallet.FundContribution(amt, feeRate)
+	if err != nil {
+		return nil, err
+	}
+	fund.intent = intent
+
+	for _, coin := range intent.InputCoins {
+		prevTx, err := f.cfg.Wallet.FetchTx(coin.OutPoint.Hash)
+		if err != nil {
+			fund.cancel()
+			return nil, fmt.Errorf("unable to fetch tx %v: %w",
+				coin.OutPoint.Hash, err)
+		}
+
+		fund.coins = append(fund.coins, fundingCoin{
+			prevTx: prevTx,
+			index:  coin.OutPoint.Index,
+		})
+	}
+	fund.change = intent.ChangeOutputs
+
+	return fund, nil
+}
+
+// newReplacement prepares the construction of a transaction that replaces the
+// funding transaction of the passed pending dual funded channel. We spend the
+// same wallet coins again, and pay the higher fee from our change.
+func (f *Manager) newReplacement(peer lnpeer.Peer,
+	channel *channeldb.OpenChannel,
+	feeRate chainfee.SatPerKWeight) (*interactiveFunding, error) {
+
+	if !channel.IsPending || !channel.ChanType.HasInteractiveFunding() {
+		return nil, fmt.Errorf("ChannelPoint(%v) is not a pending "+
+			"dual funded channel", channel.FundingOutpoint)
+	}
+
+	if !isFundingTxSigned(channel.FundingTxn) {
+		return nil, fmt.Errorf("funding tx of ChannelPoint(%v) is "+
+			"not signed yet", channel.FundingOutpoint)
+	}
+
+	// The initiator pays the commitment fee from its contribution, so we
+	// derive the contributions from the initial balance of the other
+	// party.
+	var localAmt, remoteAmt ltcutil.Amount
+	if channel.IsInitiator {
+		remoteAmt = channel.InitialRemoteBalance.ToSatoshis()
+		localAmt = channel.Capacity - remoteAmt
+	} else {
+		localAmt = channel.InitialLocalBalance.ToSatoshis()
+		remoteAmt = channel.Capacity - localAmt
+	}
+
+	fundingScript, err := makeFundingScript(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	lnChannel, err := lnwallet.NewLightningChannel(
+		f.cfg.Wallet.Cfg.Signer, channel, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, height, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+	fund := &interactiveFunding{
+		chanID:        chanID,
+		pendingChanID: chanID,
+		peer:          peer,
+		isInitiator:   channel.IsInitiator,
+		localAmt:      localAmt,
+		remoteAmt:     remoteAmt,
+		capacity:      channel.Capacity,
+		feeRate:       feeRate,
+		locktime:      uint32(height),
+		fundingScript: fundingScript,
+		prevChannel:   channel,
+		lnChannel:     lnChannel,
+	}
+
+	if err := f.reuseFundingCoins(fund); err != nil {
+		return nil, err
+	}
+
+	return fund, nil
+}
+
+// checkReplacementFeeRate checks that the fee rate of a transaction replacing
+// a funding transaction is at least 25/24 of the fee rate of the replaced one,
+// as required by BOLT 2.
+func checkReplacementFeeRate(prevFeeRate,
+	feeRate chainfee.SatPerKWeight) error {
+
+	if feeRate*24 < prevFeeRate*25 {
+		return fmt.Errorf("%w: %v is below 25/24 of %v",
+			ErrReplacementFeeRateTooLow, feeRate, prevFeeRate)
+	}
+
+	return nil
+}
+
+// reuseFundingCoins adds the wallet coins spent by the funding transaction
+// that is replaced to our contribution, and recomputes our change at the fee
+// rate of the replacement. The replacement is rejected if its fee rate isn't
+// high enough.
+func (f *Manager) reuseFundingCoins(fund *interactiveFunding) error {
+	prevTx := fund.prevChannel.FundingTxn
+
+	var (
+		weightEstimate input.TxWeightEstimator
+		totalIn        ltcutil.Amount
+		prevChange     ltcutil.Amount
+	)
+	for _, txIn := range prevTx.TxIn {
+		// Inputs that aren't known to our wallet belong to the remote
+		// peer.
+		utxo, err := f.cfg.Wallet.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			continue
+		}
+
+		switch utxo.AddressType {
+		case lnwallet.WitnessPubKey:
+			weightEstimate.AddP2WKHInput()
+
+		case lnwallet.NestedWitnessPubKey:
+			weightEstimate.AddNestedP2WKHInput()
+
+		case lnwallet.TaprootPubkey:
+			weightEstimate.AddTaprootKeySpendInput(
+				txscript.SigHashDefault,
+			)
+
+		default:
+			return fmt.Errorf("unsupported input %v",
+				txIn.PreviousOutPoint)
+		}
+
+		coinTx := utxo.PrevTx
+		if coinTx == nil {
+			coinTx, err = f.cfg.Wallet.FetchTx(
+				txIn.PreviousOutPoint.Hash,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to fetch tx %v: %w",
+					txIn.PreviousOutPoint.Hash, err)
+			}
+		}
+
+		fund.coins = append(fund.coins, fundingCoin{
+			prevTx: coinTx,
+			index:  txIn.PreviousOutPoint.Index,
+		})
+		totalIn += utxo.Value
+	}
+
+	if fund.localAmt > 0 && len(fund.coins) == 0 {
+		return fmt.Errorf("no wallet inputs found in funding tx %v",
+			prevTx.TxHash())
+	}
+
+	// The opener adds the funding output, so it pays for it as well.
+	if fund.isInitiator {
+		weightEstimate.AddP2WSHOutput()
+	}
+
+	// We'll send our change to the same script as before.
+	var changeScript []byte
+	fundingIndex := fund.prevChannel.FundingOutpoint.Index
+	for i, txOut := range prevTx.TxOut {
+		if uint32(i) == fundingIndex {
+			continue
+		}
+
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			txOut.PkScript, &f.cfg.Wallet.Cfg.NetParams,
+		)
+		if err != nil || len(addrs) != 1 {
+			continue
+		}
+
+		if f.cfg.Wallet.IsOurAddress(addrs[0]) {
+			changeScript = txOut.PkScript
+			prevChange = ltcutil.Amount(txOut.Value)
+			weightEstimate.AddTxOutput(txOut)
+
+			break
+		}
+	}
+
+	weight := int64(weightEstimate.Weight())
+
+	// Each party pays the fee for its own inputs and outputs, so the fee
+	// rate of the replaced transaction is the one we paid for ours. If we
+	// didn't contribute any, the remote peer paid the whole fee.
+	if len(fund.coins) > 0 {
+		prevFee := totalIn - fund.localAmt - prevChange
+		prevFeeRate := chainfee.SatPerKWeight(
+			prevFee * 1000 / ltcutil.Amount(weight),
+		)
+
+		err := checkReplacementFeeRate(prevFeeRate, fund.feeRate)
+		if err != nil {
+			return err
+		}
+	}
+
+	fee := fund.feeRate.FeeForWeight(weight)
+	if totalIn < fund.localAmt+fee {
+		return fmt.Errorf("insufficient funds to pay fee %v for "+
+			"contribution %v: inputs=%v", fee, fund.localAmt,
+			totalIn)
+	}
+
+	change := totalIn - fund.localAmt - fee
+	if changeScript != nil && change >= fundingDustLimit() {
+		fund.change = []*wire.TxOut{{
+			Value:    int64(change),
+			PkScript: changeScript,
+		}}
+	}
+
+	return nil
+}
+
+// getInteractiveFunding returns the state of the interactive funding with the
+// passed peer and channel ID.
+func (f *Manager) getInteractiveFunding(peer lnpeer.Peer,
+	chanID lnwire.ChannelID) (*interactiveFunding, bool) {
+
+	f.resMtx.RLock()
+	fund, ok := f.interactiveFundings[chanID]
+	f.resMtx.RUnlock()
+
+	if !ok || fund.peer.PubKey() != peer.PubKey() {
+		return nil, false
+	}
+
+	return fund, true
+}
+
+// startSession starts the interactive construction of the funding
+// transaction, and queues all our contributions to it.
+func (f *Manager) startSession(fund *interactiveFunding) {
+	fund.session = interactivetx.NewSession(interactivetx.Config{
+		ChanID:      fund.chanID,
+		IsInitiator: fund.isInitiator,
+		LockTime:    fund.locktime,
+		DustLimit:   fundingDustLimit(),
+	})
+
+	for _, coin := range fund.coins {
+		coin := coin
+		fund.pendingMsgs = append(fund.pendingMsgs,
+			func() (lnwire.Message, error) {
+				return fund.session.AddInput(
+					coin.prevTx, coin.index,
+					dualFundingSequence,
+				)
+			},
+		)
+	}
+
+	outputs := fund.change
+	if fund.isInitiator {
+		fundingOutput := &wire.TxOut{
+			Value:    int64(fund.capacity),
+			PkScript: fund.fundingScript,
+		}
+		outputs = append([]*wire.TxOut{fundingOutput}, outputs...)
+	}
+	for _, txOut := range outputs {
+		txOut := txOut
+		fund.pendingMsgs = append(fund.pendingMsgs,
+			func() (lnwire.Message, error) {
+				return fund.session.AddOutput(txOut)
+			},
+		)
+	}
+
+	f.resMtx.Lock()
+	f.interactiveFundings[fund.chanID] = fund
+	f.resMtx.Unlock()
+}
+
+// handleFundingOpen2 processes a request of the remote peer to open a dual
+// funded channel. The channel acceptor decides about our contribution, then
+// we respond with an AcceptChannel2 message and wait for the opener to start
+// the construction of the funding transaction.
+func (f *Manager) handleFundingOpen2(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel2) {
+
+	peerKey := peer.IdentityKey()
+	cid := newChanIdentifier(msg.PendingChannelID)
+
+	if !hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.DualFundOptional,
+	) {
+
+		f.failFundingFlow(peer, cid, ErrDualFundingUnsupported)
+		return
+	}
+
+	// Make sure that we're able to accept another channel of the proposed
+	// size from this peer.
+	err := f.validateChannelRequest(peer, msg.FundingAmount)
+	if err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// Query our channel acceptor to determine whether we should accept
+	// the channel, and how much we'd like to contribute to it.
+	acceptorResp := f.cfg.OpenChannelPredicate.Accept(
+		&chanacceptor.ChannelAcceptRequest{
+			Node:        peerKey,
+			OpenChanMsg: openChannelView(msg),
+			DualFunded:  true,
+		},
+	)
+	if acceptorResp.RejectChannel() {
+		f.failFundingFlow(peer, cid, acceptorResp.ChanAcceptError)
+		return
+	}
+
+	contribution := acceptorResp.FundingContribution
+	capacity := msg.FundingAmount + contribution
+
+	log.Infof("Recv'd dual fundingRequest(amt=%v, contribution=%v, "+
+		"delay=%v, pendingId=%x) from peer(%x)", msg.FundingAmount,
+		contribution, msg.CsvDelay, msg.PendingChannelID,
+		peerKey.SerializeCompressed())
+
+	if capacity > f.cfg.MaxChanSize {
+		f.failFundingFlow(
+			peer, cid,
+			lnwallet.ErrChanTooLarge(capacity, f.cfg.MaxChanSize),
+		)
+		return
+	}
+
+	chanType, commitType, err := negotiateCommitmentType(
+		msg.ChannelType, peer.LocalFeatures(), peer.RemoteFeatures(),
+	)
+	if err != nil {
+		log.Errorf("channel type negotiation failed: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	scidFeatureVal := hasFeatures(
+		peer.LocalFeatures(), peer.RemoteFeatures(),
+		lnwire.ScidAliasOptional,
+	)
+
+	var zeroConf, scid bool
+	if chanType != nil {
+		featureVec := lnwire.RawFeatureVector(*chanType)
+		zeroConf = featureVec.IsSet(lnwire.ZeroConfRequired)
+		scid = featureVec.IsSet(lnwire.ScidAliasRequired)
+	}
+
+	err = checkDualFundedCommitType(
+		commitType, zeroConf || acceptorResp.ZeroConf,
+	)
+	if err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// Sending the option-scid-alias channel type for a public channel is
+	// disallowed.
+	public := msg.ChannelFlags&lnwire.FFAnnounceChannel != 0
+	if public && scid {
+		err := fmt.Errorf("option-scid-alias chantype for public " +
+			"channel")
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	fund, err := f.newInteractiveFunding(
+		peer, msg.PendingChannelID, false, contribution,
+		chainfee.SatPerKWeight(msg.FundingFeePerKWeight),
+	)
+	if err != nil {
+		log.Errorf("Unable to fund contribution: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	fund.locktime = msg.Locktime
+	fund.remoteAmt = msg.FundingAmount
+	fund.capacity = capacity
+
+	req := &lnwallet.InitFundingReserveMsg{
+		ChainHash:        &msg.ChainHash,
+		PendingChanID:    msg.PendingChannelID,
+		NodeID:           peerKey,
+		NodeAddr:         peer.Address(),
+		LocalFundingAmt:  contribution,
+		RemoteFundingAmt: msg.FundingAmount,
+		CommitFeePerKw: chainfee.SatPerKWeight(
+			msg.CommitFeePerKWeight,
+		),
+		FundingFeePerKw:  fund.feeRate,
+		Flags:            msg.ChannelFlags,
+		MinConfs:         1,
+		CommitType:       commitType,
+		ChanFunder:       chanfunding.NewInteractiveAssembler(),
+		OptionScidAlias:  scid,
+		ScidAliasFeature: scidFeatureVal,
+		DualFunded:       true,
+	}
+
+	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
+	if err != nil {
+		log.Errorf("Unable to initialize reservation: %v", err)
+		fund.cancel()
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// As we're the responder, we get to specify the number of
+	// confirmations that we require before both of us consider the
+	// channel open.
+	numConfsReq := f.cfg.NumRequiredConfs(capacity, 0)
+	if acceptorResp.MinAcceptDepth != 0 {
+		numConfsReq = acceptorResp.MinAcceptDepth
+	}
+	reservation.SetNumConfsRequired(numConfsReq)
+
+	// There's no way to negotiate the channel reserve of a dual funded
+	// channel, so both parties use the same reserve derived from the
+	// capacity of the channel.
+	maxDustLimit := reservation.OurContribution().DustLimit
+	if msg.DustLimit > maxDustLimit {
+		maxDustLimit = msg.DustLimit
+	}
+	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, maxDustLimit)
+
+	channelConstraints := &channeldb.ChannelConstraints{
+		DustLimit:        msg.DustLimit,
+		ChanReserve:      chanReserve,
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		CsvDelay:         msg.CsvDelay,
+	}
+	err = reservation.CommitConstraints(
+		channelConstraints, f.cfg.MaxLocalCSVDelay, true,
+	)
+	if err != nil {
+		log.Errorf("Unacceptable channel constraints: %v", err)
+		fund.cancel()
+		_ = reservation.Cancel()
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	shutdown, err := getUpfrontShutdownScript(
+		f.cfg.EnableUpfrontShutdown, peer, acceptorResp.UpfrontShutdown,
+		f.selectShutdownScript,
+	)
+	if err != nil {
+		fund.cancel()
+		_ = reservation.Cancel()
+		f.failFundingFlow(
+			peer, cid,
+			fmt.Errorf("getUpfrontShutdownScript error: %v", err),
+		)
+		return
+	}
+	reservation.SetOurUpfrontShutdown(shutdown)
+
+	// Generate our required constraints for the remote party, using the
+	// values provided by the channel acceptor if they are non-zero.
+	remoteCsvDelay := f.cfg.RequiredRemoteDelay(capacity)
+	if acceptorResp.CSVDelay != 0 {
+		remoteCsvDelay = acceptorResp.CSVDelay
+	}
+
+	remoteMaxValue := f.cfg.RequiredRemoteMaxValue(capacity)
+	if acceptorResp.InFlightTotal != 0 {
+		remoteMaxValue = acceptorResp.InFlightTotal
+	}
+
+	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(capacity)
+	if acceptorResp.HtlcLimit != 0 {
+		maxHtlcs = acceptorResp.HtlcLimit
+	}
+
+	minHtlc := f.cfg.DefaultMinHtlcIn
+	if acceptorResp.MinHtlcIn != 0 {
+		minHtlc = acceptorResp.MinHtlcIn
+	}
+
+	ourContribution := reservation.OurContribution()
+	forwardingPolicy := f.defaultForwardingPolicy(
+		ourContribution.ChannelConstraints,
+	)
+
+	// Once the reservation has been created successfully, we add it to
+	// this peer's map of pending reservations to track this particular
+	// reservation until either abort or completion.
+	peerIDKey := newSerializedKey(peerKey)
+	resCtx := &reservationWithCtx{
+		reservation:       reservation,
+		chanAmt:           capacity,
+		forwardingPolicy:  *forwardingPolicy,
+		remoteCsvDelay:    remoteCsvDelay,
+		remoteMinHtlc:     minHtlc,
+		remoteMaxValue:    remoteMaxValue,
+		remoteMaxHtlcs:    maxHtlcs,
+		remoteChanReserve: chanReserve,
+		maxLocalCsv:       f.cfg.MaxLocalCSVDelay,
+		channelType:       chanType,
+		interactive:       fund,
+		err:               make(chan error, 1),
+		peer:              peer,
+	}
+	fund.resCtx = resCtx
+
+	f.resMtx.Lock()
+	if _, ok := f.activeReservations[peerIDKey]; !ok {
+		f.activeReservations[peerIDKey] = make(pendingChannels)
+	}
+	f.activeReservations[peerIDKey][msg.PendingChannelID] = resCtx
+	f.resMtx.Unlock()
+
+	// Update the timestamp once the OpenChannel2 message has been
+	// handled.
+	defer resCtx.updateTimestamp()
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FundingAmount:        msg.FundingAmount,
+		FirstCommitmentPoint: msg.FirstCommitmentPoint,
+		ChannelConfig: &channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit:        msg.DustLimit,
+				MaxPendingAmount: remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          minHtlc,
+				MaxAcceptedHtlcs: maxHtlcs,
+				CsvDelay:         remoteCsvDelay,
+			},
+			MultiSigKey: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.FundingKey),
+			},
+			RevocationBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.RevocationPoint),
+			},
+			PaymentBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.PaymentPoint),
+			},
+			DelayBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.DelayedPaymentPoint),
+			},
+			HtlcBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.HtlcPoint),
+			},
+		},
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+
+	// As both multi-sig keys are known now, the wallet binds them to the
+	// funding output.
+	err = reservation.ProcessContribution(remoteContribution)
+	if err != nil {
+		log.Errorf("unable to add contribution reservation: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	_, fundingOutput, err := input.GenFundingPkScript(
+		ourContribution.MultiSigKey.PubKey.SerializeCompressed(),
+		msg.FundingKey.SerializeCompressed(), int64(capacity),
+	)
+	if err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	fund.fundingScript = fundingOutput.PkScript
+
+	// We'll wait for the opener to start the construction of the funding
+	// transaction.
+	f.startSession(fund)
+
+	log.Infof("Sending dual fundingResp for pending_id(%x)",
+		msg.PendingChannelID)
+
+	fundingAccept := &lnwire.AcceptChannel2{
+		PendingChannelID:      msg.PendingChannelID,
+		FundingAmount:         contribution,
+		DustLimit:             ourContribution.DustLimit,
+		MaxValueInFlight:      remoteMaxValue,
+		HtlcMinimum:           minHtlc,
+		MinAcceptDepth:        uint32(numConfsReq),
+		CsvDelay:              remoteCsvDelay,
+		MaxAcceptedHTLCs:      maxHtlcs,
+		FundingKey:            ourContribution.MultiSigKey.PubKey,
+		RevocationPoint:       ourContribution.RevocationBasePoint.PubKey,
+		PaymentPoint:          ourContribution.PaymentBasePoint.PubKey,
+		DelayedPaymentPoint:   ourContribution.DelayBasePoint.PubKey,
+		HtlcPoint:             ourContribution.HtlcBasePoint.PubKey,
+		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
+		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
+		ChannelType:           chanType,
+	}
+	if err := peer.SendMessage(true, fundingAccept); err != nil {
+		log.Errorf("unable to send funding response to peer: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+}
+
+// handleFundingAccept2 processes the response of the remote peer to our
+// request to open a dual funded channel, and starts the construction of the
+// funding transaction.
+func (f *Manager) handleFundingAccept2(peer lnpeer.Peer,
+	msg *lnwire.AcceptChannel2) {
+
+	pendingChanID := msg.PendingChannelID
+	peerKey := peer.IdentityKey()
+
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err != nil {
+		log.Warnf("Can't find reservation (peerKey:%x, chan_id:%v)",
+			peerKey.SerializeCompressed(), pendingChanID)
+		return
+	}
+
+	// Update the timestamp once the AcceptChannel2 message has been
+	// handled.
+	defer resCtx.updateTimestamp()
+
+	log.Infof("Recv'd dual fundingResponse for pending_id(%x), "+
+		"contribution=%v", pendingChanID[:], msg.FundingAmount)
+
+	cid := newChanIdentifier(pendingChanID)
+
+	fund := resCtx.interactive
+	if fund == nil || fund.session != nil {
+		err := fmt.Errorf("unexpected AcceptChannel2")
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// The channel type must be echoed back, as it's the case for single
+	// funded channels.
+	switch {
+	case resCtx.channelType != nil && msg.ChannelType == nil:
+		err := errors.New("explicit channel type not echoed back")
+		f.failFundingFlow(peer, cid, err)
+		return
+
+	case resCtx.channelType != nil:
+		proposedFeatures := lnwire.RawFeatureVector(*resCtx.channelType)
+		ackedFeatures := lnwire.RawFeatureVector(*msg.ChannelType)
+		if !proposedFeatures.Equals(&ackedFeatures) {
+			err := errors.New("channel type mismatch")
+			f.failFundingFlow(peer, cid, err)
+			return
+		}
+
+	case msg.ChannelType != nil:
+		err := errors.New("received unexpected channel type")
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// The required number of confirmations should not be greater than the
+	// maximum number of confirmations required by the ChainNotifier to
+	// properly dispatch confirmations.
+	if msg.MinAcceptDepth > chainntnfs.MaxNumConfs {
+		err := lnwallet.ErrNumConfsTooLarge(
+			msg.MinAcceptDepth, chainntnfs.MaxNumConfs,
+		)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	if msg.MinAcceptDepth == 0 {
+		err := fmt.Errorf("non-zero-conf channel has min depth zero")
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	resCtx.reservation.SetNumConfsRequired(uint16(msg.MinAcceptDepth))
+
+	// Now that we know the contribution of the remote peer, we know the
+	// capacity of the channel as well.
+	err = resCtx.reservation.AddRemoteFunding(msg.FundingAmount)
+	if err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	capacity := resCtx.reservation.Capacity()
+	if capacity > f.cfg.MaxChanSize {
+		f.failFundingFlow(
+			peer, cid,
+			lnwallet.ErrChanTooLarge(capacity, f.cfg.MaxChanSize),
+		)
+		return
+	}
+	resCtx.chanAmt = capacity
+	fund.remoteAmt = msg.FundingAmount
+	fund.capacity = capacity
+
+	// Both parties use the same reserve derived from the capacity of the
+	// channel.
+	ourContribution := resCtx.reservation.OurContribution()
+	maxDustLimit := ourContribution.DustLimit
+	if msg.DustLimit > maxDustLimit {
+		maxDustLimit = msg.DustLimit
+	}
+	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, maxDustLimit)
+	resCtx.remoteChanReserve = chanReserve
+
+	channelConstraints := &channeldb.ChannelConstraints{
+		DustLimit:        msg.DustLimit,
+		ChanReserve:      chanReserve,
+		MaxPendingAmount: msg.MaxValueInFlight,
+		MinHTLC:          msg.HtlcMinimum,
+		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
+		CsvDelay:         msg.CsvDelay,
+	}
+	err = resCtx.reservation.CommitConstraints(
+		channelConstraints, resCtx.maxLocalCsv, false,
+	)
+	if err != nil {
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	remoteContribution := &lnwallet.ChannelContribution{
+		FundingAmount:        msg.FundingAmount,
+		FirstCommitmentPoint: msg.FirstCommitmentPoint,
+		ChannelConfig: &channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit:        msg.DustLimit,
+				MaxPendingAmount: resCtx.remoteMaxValue,
+				ChanReserve:      chanReserve,
+				MinHTLC:          resCtx.remoteMinHtlc,
+				MaxAcceptedHtlcs: resCtx.remoteMaxHtlcs,
+				CsvDelay:         resCtx.remoteCsvDelay,
+			},
+			MultiSigKey: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.FundingKey),
+			},
+			RevocationBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.RevocationPoint),
+			},
+			PaymentBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.PaymentPoint),
+			},
+			DelayBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.DelayedPaymentPoint),
+			},
+			HtlcBasePoint: keychain.KeyDescriptor{
+				PubKey: copyPubKey(msg.HtlcPoint),
+			},
+		},
+		UpfrontShutdown: msg.UpfrontShutdownScript,
+	}
+
+	err = resCtx.reservation.ProcessContribution(remoteContribution)
+	if err != nil {
+		log.Errorf("Unable to process contribution from %x: %v",
+			peerKey.SerializeCompressed(), err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	_, fundingOutput, err := input.GenFundingPkScript(
+		ourContribution.MultiSigKey.PubKey.SerializeCompressed(),
+		msg.FundingKey.SerializeCompressed(), int64(capacity),
+	)
+	if err != nil {
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+	fund.fundingScript = fundingOutput.PkScript
+
+	// As the opener, we send the first message of the construction.
+	f.startSession(fund)
+	if err := f.sendNextConstructionMsg(fund); err != nil {
+		f.failInteractiveFunding(fund, err, false)
+	}
+}
+
+// sendNextConstructionMsg sends our next contribution to the funding
+// transaction, or tx_complete if there's nothing left to add. If the remote
+// peer already completed the construction, it's complete afterwards.
+func (f *Manager) sendNextConstructionMsg(fund *interactiveFunding) error {
+	if len(fund.pendingMsgs) > 0 {
+		next := fund.pendingMsgs[0]
+		fund.pendingMsgs = fund.pendingMsgs[1:]
+
+		msg, err := next()
+		if err != nil {
+			return err
+		}
+
+		return fund.peer.SendMessage(true, msg)
+	}
+
+	err := fund.peer.SendMessage(true, fund.session.Complete())
+	if err != nil {
+		return err
+	}
+
+	if fund.session.IsComplete() {
+		return f.completeConstruction(fund)
+	}
+
+	return nil
+}
+
+// handleConstructionMsg processes a message of the remote peer that adds to,
+// removes from or completes the funding transaction, then takes our turn.
+func (f *Manager) handleConstructionMsg(peer lnpeer.Peer, msg lnwire.Message) {
+	chanMsg, ok := msg.(interface{ TargetChanID() lnwire.ChannelID })
+	if !ok {
+		return
+	}
+
+	fund, ok := f.getInteractiveFunding(peer, chanMsg.TargetChanID())
+	if !ok {
+		log.Warnf("Received %v for unknown dual funded channel %v",
+			msg.MsgType(), chanMsg.TargetChanID())
+		return
+	}
+
+	if fund.session == nil || fund.fundingTx != nil {
+		err := fmt.Errorf("unexpected %v", msg.MsgType())
+		f.failInteractiveFunding(fund, err, false)
+		return
+	}
+
+	if fund.resCtx != nil {
+		defer fund.resCtx.updateTimestamp()
+	}
+
+	if err := fund.session.ProcessMsg(msg); err != nil {
+		f.failInteractiveFunding(fund, err, false)
+		return
+	}
+
+	if fund.session.IsComplete() {
+		err := f.completeConstruction(fund)
+		if err != nil {
+			f.failInteractiveFunding(fund, err, false)
+		}
+
+		return
+	}
+
+	if err := f.sendNextConstructionMsg(fund); err != nil {
+		f.failInteractiveFunding(fund, err, false)
+	}
+}
+
+// completeConstruction validates the constructed funding transaction, and
+// sends our signature for the initial commitment of the remote peer.
+func (f *Manager) completeConstruction(fund *interactiveFunding) error {
+	tx, err := fund.session.Tx()
+	if err != nil {
+		return err
+	}
+
+	fundingIndex := -1
+	for i, txOut := range tx.TxOut {
+		if !bytes.Equal(txOut.PkScript, fund.fundingScript) {
+			continue
+		}
+
+		if fundingIndex != -1 {
+			return fmt.Errorf("funding tx has multiple funding " +
+				"outputs")
+		}
+		fundingIndex = i
+	}
+	if fundingIndex == -1 {
+		return fmt.Errorf("funding tx has no funding output")
+	}
+
+	fundingValue := ltcutil.Amount(tx.TxOut[fundingIndex].Value)
+	if fundingValue != fund.capacity {
+		return fmt.Errorf("funding output has value %v, expected %v",
+			fundingValue, fund.capacity)
+	}
+
+	// The remote peer must actually pay for its contribution.
+	var remoteIn, remoteOut ltcutil.Amount
+	for _, in := range fund.session.Inputs() {
+		if !fund.session.IsLocal(in.SerialID) {
+			remoteIn += ltcutil.Amount(in.PrevOut.Value)
+		}
+	}
+	for _, out := range fund.session.Outputs() {
+		if fund.session.IsLocal(out.SerialID) ||
+			bytes.Equal(out.TxOut.PkScript, fund.fundingScript) {
+
+			continue
+		}
+		remoteOut += ltcutil.Amount(out.TxOut.Value)
+	}
+	if remoteIn < remoteOut+fund.remoteAmt {
+		return fmt.Errorf("remote inputs %v don't cover contribution "+
+			"%v and outputs %v", remoteIn, fund.remoteAmt,
+			remoteOut)
+	}
+
+	// A replacement must conflict with the transaction it replaces, so
+	// only one of them can confirm.
+	if fund.prevChannel != nil &&
+		!sharesInput(tx, fund.prevChannel.FundingTxn) {
+
+		return fmt.Errorf("replacement doesn't spend any input of " +
+			"the replaced funding tx")
+	}
+
+	fund.fundingTx = tx
+	fund.fundingPoint = wire.OutPoint{
+		Hash:  tx.TxHash(),
+		Index: uint32(fundingIndex),
+	}
+
+	log.Infof("Constructed funding tx %v for dual funded channel %v",
+		fund.fundingPoint, fund.chanID)
+
+	var sig lnwire.Sig
+	if fund.prevChannel == nil {
+		err := fund.resCtx.reservation.ProcessInteractiveTx(tx)
+		if err != nil {
+			return err
+		}
+
+		_, commitSig := fund.resCtx.reservation.OurSignatures()
+		sig, err = lnwire.NewSigFromSignature(commitSig)
+		if err != nil {
+			return err
+		}
+	} else {
+		lnChannel := fund.lnChannel
+		sig, fund.remoteCommit, err = lnChannel.SignSpliceCommitment(
+			fund.spliceParams(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return fund.peer.SendMessage(true, &lnwire.CommitSig{
+		ChanID:    fund.chanID,
+		CommitSig: sig,
+	})
+}
+
+// handleInteractiveCommitSig processes the signature of the remote peer for
+// our initial commitment spending the constructed funding transaction. The
+// channel is persisted afterwards, and we send our tx_signatures if it's our
+// turn to do so.
+func (f *Manager) handleInteractiveCommitSig(peer lnpeer.Peer,
+	msg *lnwire.CommitSig) {
+
+	fund, ok := f.getInteractiveFunding(peer, msg.ChanID)
+	if !ok {
+		log.Warnf("Received CommitSig for unknown dual funded "+
+			"channel %v", msg.ChanID)
+		return
+	}
+
+	if fund.fundingTx == nil || fund.channel != nil {
+		f.failInteractiveFunding(
+			fund, fmt.Errorf("unexpected CommitSig"), false,
+		)
+		return
+	}
+
+	var (
+		channel          *channeldb.OpenChannel
+		forwardingPolicy *models.ForwardingPolicy
+	)
+	if fund.prevChannel == nil {
+		commitSig, err := msg.CommitSig.ToSignature()
+		if err != nil {
+			f.failInteractiveFunding(fund, err, false)
+			return
+		}
+
+		channel, err = fund.resCtx.reservation.CompleteReservation(
+			nil, commitSig,
+		)
+		if err != nil {
+			log.Errorf("Unable to complete reservation: %v", err)
+			f.failInteractiveFunding(fund, err, false)
+			return
+		}
+
+		// The channel is persisted now, so the reservation is no
+		// longer needed.
+		policy := fund.resCtx.forwardingPolicy
+		forwardingPolicy = &policy
+		fund.updates = fund.resCtx.updates
+		f.deleteReservationCtx(peer.IdentityKey(), fund.pendingChanID)
+		fund.resCtx = nil
+	} else {
+		localCommit, err := fund.lnChannel.ReceiveSpliceCommitment(
+			fund.spliceParams(), msg.CommitSig,
+		)
+		if err != nil {
+			f.failInteractiveFunding(fund, err, false)
+			return
+		}
+
+		channel, err = fund.prevChannel.SyncFundingReplacement(
+			fund.fundingTx, fund.fundingPoint, localCommit,
+			fund.remoteCommit,
+		)
+		if err != nil {
+			f.failInteractiveFunding(fund, err, false)
+			return
+		}
+
+		// The replacement is announced with the same policy as the
+		// channel it replaces.
+		forwardingPolicy, err = f.getInitialForwardingPolicy(
+			fund.chanID,
+		)
+		if err != nil {
+			log.Warnf("Unable to fetch forwarding policy of %v: %v",
+				fund.chanID, err)
+
+			forwardingPolicy = f.defaultForwardingPolicy(
+				channel.LocalChanCfg.ChannelConstraints,
+			)
+		}
+	}
+	fund.channel = channel
+
+	// The intent of the wallet isn't needed anymore, as the persisted
+	// funding transaction keeps track of the coins we spend.
+	fund.intent = nil
+
+	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+	f.newChanBarriers.Store(chanID, make(chan struct{}))
+	f.localDiscoverySignals.Store(chanID, make(chan struct{}))
+
+	err := f.saveInitialForwardingPolicy(chanID, forwardingPolicy)
+	if err != nil {
+		log.Errorf("Unable to store the forwarding policy: %v", err)
+	}
+
+	if err := peer.AddPendingChannel(chanID, f.quit); err != nil {
+		log.Errorf("Unable to add pending channel %v with peer %x: %v",
+			chanID, peer.IdentityKey().SerializeCompressed(), err)
+	}
+
+	err = f.cfg.WatchNewChannel(channel, peer.IdentityKey())
+	if err != nil {
+		log.Errorf("Unable to send new ChannelPoint(%v) for "+
+			"arbitration: %v", channel.FundingOutpoint, err)
+	}
+
+	f.cfg.NotifyPendingOpenChannelEvent(channel.FundingOutpoint, channel)
+
+	if !f.signsFirst(fund) {
+		return
+	}
+
+	if err := f.sendTxSignatures(fund); err != nil {
+		f.failInteractiveFunding(fund, err, false)
+	}
+}
+
+// signsFirst returns true if we have to send our tx_signatures first. This is
+// the case if we contributed less to the inputs of the funding transaction,
+// or if both contributed the same and our node key is the lower one.
+func (f *Manager) signsFirst(fund *interactiveFunding) bool {
+	var localIn, remoteIn ltcutil.Amount
+	for _, in := range fund.session.Inputs() {
+		if fund.session.IsLocal(in.SerialID) {
+			localIn += ltcutil.Amount(in.PrevOut.Value)
+		} else {
+			remoteIn += ltcutil.Amount(in.PrevOut.Value)
+		}
+	}
+
+	if localIn != remoteIn {
+		return localIn < remoteIn
+	}
+
+	return bytes.Compare(
+		f.cfg.IDKey.SerializeCompressed(),
+		fund.peer.IdentityKey().SerializeCompressed(),
+	) < 0
+}
+
+// sendTxSignatures signs our inputs of the funding transaction, and sends the
+// witnesses to the remote peer.
+func (f *Manager) sendTxSignatures(fund *interactiveFunding) error {
+	if err := f.signFundingInputs(fund); err != nil {
+		return err
+	}
+	fund.sentTxSigs = true
+
+	return fund.peer.SendMessage(true, &lnwire.TxSignatures{
+		ChanID:    fund.chanID,
+		TxID:      fund.fundingTx.TxHash(),
+		Witnesses: fund.session.LocalWitnesses(fund.fundingTx),
+	})
+}
+
+// signFundingInputs signs the inputs of the funding transaction that spend
+// our wallet coins.
+func (f *Manager) signFundingInputs(fund *interactiveFunding) error {
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for _, in := range fund.session.Inputs() {
+		prevOuts[in.OutPoint] = in.PrevOut
+	}
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	sigHashes := txscript.NewTxSigHashes(fund.fundingTx, prevOutFetcher)
+
+	for i, in := range fund.session.Inputs() {
+		if !fund.session.IsLocal(in.SerialID) {
+			continue
+		}
+
+		hashType := txscript.SigHashAll
+		if txscript.IsPayToTaproot(in.PrevOut.PkScript) {
+			hashType = txscript.SigHashDefault
+		}
+
+		inputScript, err := f.cfg.Wallet.Cfg.Signer.ComputeInputScript(
+			fund.fundingTx, &input.SignDescriptor{
+				Output:            in.PrevOut,
+				HashType:          hashType,
+				SigHashes:         sigHashes,
+				PrevOutputFetcher: prevOutFetcher,
+				InputIndex:        i,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to sign input %v: %w",
+				in.OutPoint, err)
+		}
+
+		fund.fundingTx.TxIn[i].Witness = inputScript.Witness
+		fund.fundingTx.TxIn[i].SignatureScript = inputScript.SigScript
+	}
+
+	return nil
+}
+
+// verifyRemoteInputs checks the witnesses of the remote peer for its inputs
+// of the funding transaction.
+func verifyRemoteInputs(fund *interactiveFunding) error {
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for _, in := range fund.session.Inputs() {
+		prevOuts[in.OutPoint] = in.PrevOut
+	}
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	sigHashes := txscript.NewTxSigHashes(fund.fundingTx, prevOutFetcher)
+
+	for i, in := range fund.session.Inputs() {
+		if fund.session.IsLocal(in.SerialID) {
+			continue
+		}
+
+		vm, err := txscript.NewEngine(
+			in.PrevOut.PkScript, fund.fundingTx, i,
+			txscript.StandardVerifyFlags, nil, sigHashes,
+			in.PrevOut.Value, prevOutFetcher,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := vm.Execute(); err != nil {
+			return fmt.Errorf("invalid witness for input %v: %w",
+				in.OutPoint, err)
+		}
+	}
+
+	return nil
+}
+
+// handleTxSignatures completes the funding transaction with the witnesses of
+// the remote peer, and broadcasts it once we've signed our inputs as well.
+func (f *Manager) handleTxSignatures(peer lnpeer.Peer,
+	msg *lnwire.TxSignatures) {
+
+	fund, ok := f.getInteractiveFunding(peer, msg.ChanID)
+	if !ok {
+		log.Warnf("Received TxSignatures for unknown dual funded "+
+			"channel %v", msg.ChanID)
+		return
+	}
+
+	if fund.channel == nil {
+		f.failInteractiveFunding(
+			fund, fmt.Errorf("unexpected TxSignatures"), false,
+		)
+		return
+	}
+
+	txid := fund.fundingTx.TxHash()
+	if msg.TxID != txid {
+		err := fmt.Errorf("tx_signatures for unknown tx %v, "+
+			"expected %v", msg.TxID, txid)
+		f.failInteractiveFunding(fund, err, false)
+		return
+	}
+
+	err := fund.session.ApplyRemoteWitnesses(fund.fundingTx, msg.Witnesses)
+	if err != nil {
+		f.failInteractiveFunding(fund, err, false)
+		return
+	}
+
+	if err := verifyRemoteInputs(fund); err != nil {
+		f.failInteractiveFunding(fund, err, false)
+		return
+	}
+
+	if !fund.sentTxSigs {
+		if err := f.sendTxSignatures(fund); err != nil {
+			f.failInteractiveFunding(fund, err, false)
+			return
+		}
+	}
+
+	f.publishFundingTx(fund)
+}
+
+// publishFundingTx persists and broadcasts the fully signed funding
+// transaction, then waits for it to confirm.
+func (f *Manager) publishFundingTx(fund *interactiveFunding) {
+	f.resMtx.Lock()
+	delete(f.interactiveFundings, fund.chanID)
+	f.resMtx.Unlock()
+
+	channel := fund.channel
+	if err := channel.MarkFundingTxSigned(fund.fundingTx); err != nil {
+		log.Errorf("Unable to store signed funding tx of "+
+			"ChannelPoint(%v): %v", channel.FundingOutpoint, err)
+	}
+
+	log.Infof("Broadcasting funding tx for ChannelPoint(%v)",
+		channel.FundingOutpoint)
+
+	// Set a nil short channel ID at this stage because we do not know it
+	// until our funding tx confirms.
+	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+	err := f.cfg.PublishTransaction(fund.fundingTx, label)
+	if err != nil {
+		// We'll retry the broadcast at startup.
+		log.Errorf("Unable to broadcast funding tx %v for "+
+			"ChannelPoint(%v): %v", fund.fundingTx.TxHash(),
+			channel.FundingOutpoint, err)
+	}
+
+	fundingPoint := channel.FundingOutpoint
+	switch {
+	case fund.bumpReq != nil:
+		fund.bumpReq.Txid <- fundingPoint.Hash
+
+	case fund.updates != nil:
+		upd := &lnrpc.OpenStatusUpdate{
+			Update: &lnrpc.OpenStatusUpdate_ChanPending{
+				ChanPending: &lnrpc.PendingUpdate{
+					Txid:        fundingPoint.Hash[:],
+					OutputIndex: fundingPoint.Index,
+				},
+			},
+			PendingChanId: fund.pendingChanID[:],
+		}
+
+		select {
+		case fund.updates <- upd:
+		case <-f.quit:
+			return
+		}
+	}
+
+	f.wg.Add(1)
+	go f.advanceFundingState(channel, fund.pendingChanID, fund.updates)
+}
+
+// handleTxAbort processes the request of the remote peer to abort the
+// construction of a funding transaction.
+func (f *Manager) handleTxAbort(peer lnpeer.Peer, msg *lnwire.TxAbort) {
+	fund, ok := f.getInteractiveFunding(peer, msg.ChanID)
+	if !ok {
+		log.Warnf("Received TxAbort for unknown dual funded "+
+			"channel %v", msg.ChanID)
+		return
+	}
+
+	err := fmt.Errorf("funding aborted by %x: %v",
+		peer.IdentityKey().SerializeCompressed(), msg.Error())
+	f.failInteractiveFunding(fund, err, true)
+}
+
+// failReplacement fails the construction of a replacement transaction that
+// the passed error from the remote peer refers to. It returns false if the
+// error isn't tied to a replacement.
+func (f *Manager) failReplacement(peer lnpeer.Peer, chanID lnwire.ChannelID,
+	msg *lnwire.Error) bool {
+
+	fund, ok := f.getInteractiveFunding(peer, chanID)
+	if !ok || fund.resCtx != nil {
+		return false
+	}
+
+	err := fmt.Errorf("received funding error from %x: %v",
+		peer.IdentityKey().SerializeCompressed(), msg.Error())
+	f.failInteractiveFunding(fund, err, true)
+
+	return true
+}
+
+// failInteractiveFunding aborts the interactive construction or signing of a
+// funding transaction. If the channel wasn't persisted yet, its reservation
+// is canceled. A channel that was persisted remains pending, as the remote
+// peer might still broadcast the funding transaction.
+func (f *Manager) failInteractiveFunding(fund *interactiveFunding, err error,
+	byRemote bool) {
+
+	log.Errorf("Dual funding of channel %v failed: %v", fund.chanID, err)
+
+	if fund.resCtx != nil {
+		ctx, cancelErr := f.cancelReservationCtx(
+			fund.peer.IdentityKey(), fund.pendingChanID, byRemote,
+		)
+		if cancelErr != nil {
+			log.Errorf("unable to cancel reservation: %v",
+				cancelErr)
+		}
+
+		if ctx != nil {
+			ctx.err <- err
+		}
+	} else {
+		f.resMtx.Lock()
+		delete(f.interactiveFundings, fund.chanID)
+		f.resMtx.Unlock()
+
+		fund.cancel()
+		fund.notifyErr(err)
+	}
+
+	// A tx_abort of the remote peer is echoed back without a reason.
+	abort := &lnwire.TxAbort{
+		ChanID: fund.chanID,
+	}
+	if !byRemote {
+		abort.Data = fundingErrorData(err)
+	}
+
+	if err := fund.peer.SendMessage(false, abort); err != nil {
+		log.Errorf("Unable to send tx_abort to peer: %v", err)
+	}
+}
+
+// BumpFundingFee sends a request to replace the funding transaction of a
+// pending dual funded channel to the funding manager.
+func (f *Manager) BumpFundingFee(req *BumpFundingMsg) {
+	select {
+	case f.bumpRequests <- req:
+	case <-f.quit:
+		req.Err <- ErrFundingManagerShuttingDown
+	}
+}
+
+// handleBumpFundingMsg starts the construction of a transaction that replaces
+// the funding transaction of a pending dual funded channel we opened.
+func (f *Manager) handleBumpFundingMsg(req *BumpFundingMsg) {
+	channel, err := f.cfg.ChannelDB.FetchChannel(nil, req.ChanPoint)
+	if err != nil {
+		req.Err <- err
+		return
+	}
+
+	if !channel.IsInitiator {
+		req.Err <- fmt.Errorf("only the opener of ChannelPoint(%v) "+
+			"can replace its funding tx", req.ChanPoint)
+		return
+	}
+
+	if !channel.IdentityPub.IsEqual(req.Peer.IdentityKey()) {
+		req.Err <- fmt.Errorf("ChannelPoint(%v) doesn't belong to "+
+			"peer %x", req.ChanPoint,
+			req.Peer.IdentityKey().SerializeCompressed())
+		return
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&req.ChanPoint)
+	if _, ok := f.getInteractiveFunding(req.Peer, chanID); ok {
+		req.Err <- fmt.Errorf("funding tx of ChannelPoint(%v) is "+
+			"already being replaced", req.ChanPoint)
+		return
+	}
+
+	fund, err := f.newReplacement(req.Peer, channel, req.FeeRate)
+	if err != nil {
+		req.Err <- err
+		return
+	}
+	fund.bumpReq = req
+
+	f.resMtx.Lock()
+	f.interactiveFundings[chanID] = fund
+	f.resMtx.Unlock()
+
+	log.Infof("Replacing funding tx of ChannelPoint(%v) at fee rate %v",
+		req.ChanPoint, req.FeeRate)
+
+	err = req.Peer.SendMessage(true, &lnwire.TxInitRbf{
+		ChanID:              chanID,
+		Locktime:            fund.locktime,
+		FeePerKiloWeight:    uint32(req.FeeRate),
+		FundingContribution: fund.localAmt,
+	})
+	if err != nil {
+		f.resMtx.Lock()
+		delete(f.interactiveFundings, chanID)
+		f.resMtx.Unlock()
+
+		req.Err <- err
+	}
+}
+
+// handleTxInitRbf processes the request of the remote peer to replace the
+// funding transaction of a pending dual funded channel.
+func (f *Manager) handleTxInitRbf(peer lnpeer.Peer, msg *lnwire.TxInitRbf) {
+	abort := func(err error) {
+		log.Errorf("Rejecting replacement of funding tx for "+
+			"channel %v: %v", msg.ChanID, err)
+
+		err = peer.SendMessage(false, &lnwire.TxAbort{
+			ChanID: msg.ChanID,
+			Data:   fundingErrorData(err),
+		})
+		if err != nil {
+			log.Errorf("Unable to send tx_abort to peer: %v", err)
+		}
+	}
+
+	if _, ok := f.getInteractiveFunding(peer, msg.ChanID); ok {
+		abort(fmt.Errorf("funding tx is already being replaced"))
+		return
+	}
+
+	channels, err := f.cfg.ChannelDB.FetchOpenChannels(peer.IdentityKey())
+	if err != nil {
+		abort(err)
+		return
+	}
+
+	var channel *channeldb.OpenChannel
+	for _, c := range channels {
+		if lnwire.NewChanIDFromOutPoint(&c.FundingOutpoint) ==
+			msg.ChanID {
+
+			channel = c
+			break
+		}
+	}
+	if channel == nil || channel.IsInitiator {
+		abort(fmt.Errorf("unknown pending channel"))
+		return
+	}
+
+	fund, err := f.newReplacement(
+		peer, channel, chainfee.SatPerKWeight(msg.FeePerKiloWeight),
+	)
+	if err != nil {
+		abort(err)
+		return
+	}
+	fund.locktime = msg.Locktime
+
+	if msg.FundingContribution != fund.remoteAmt {
+		abort(fmt.Errorf("contribution %v doesn't match %v",
+			msg.FundingContribution, fund.remoteAmt))
+		return
+	}
+
+	log.Infof("Replacing funding tx of ChannelPoint(%v) at fee rate %v "+
+		"as requested by peer", channel.FundingOutpoint,
+		chainfee.SatPerKWeight(msg.FeePerKiloWeight))
+
+	f.startSession(fund)
+
+	err = peer.SendMessage(true, &lnwire.TxAckRbf{
+		ChanID:              msg.ChanID,
+		FundingContribution: fund.localAmt,
+	})
+	if err != nil {
+		f.failInteractiveFunding(fund, err, false)
+	}
+}
+
+// handleTxAckRbf starts the construction of the replacement funding
+// transaction once the remote peer accepted it.
+func (f *Manager) handleTxAckRbf(peer lnpeer.Peer, msg *lnwire.TxAckRbf) {
+	fund, ok := f.getInteractiveFunding(peer, msg.ChanID)
+	if !ok {
+		log.Warnf("Received TxAckRbf for unknown dual funded "+
+			"channel %v", msg.ChanID)
+		return
+	}
+
+	if fund.session != nil || fund.bumpReq == nil {
+		f.failInteractiveFunding(
+			fund, fmt.Errorf("unexpected TxAckRbf"), false,
+		)
+		return
+	}
+
+	if msg.FundingContribution != fund.remoteAmt {
+		err := fmt.Errorf("contribution %v doesn't match %v",
+			msg.FundingContribution, fund.remoteAmt)
+		f.failInteractiveFunding(fund, err, false)
+		return
+	}
+
+	f.startSession(fund)
+	if err := f.sendNextConstructionMsg(fund); err != nil {
+		f.failInteractiveFunding(fund, err, false)
+	}
+}
+
+// pruneReplacedFundings forgets about all pending channels whose funding
+// transaction conflicts with the confirmed funding transaction of the passed
+// dual funded channel.
+func (f *Manager) pruneReplacedFundings(confirmed *channeldb.OpenChannel) {
+	if confirmed.FundingTxn == nil {
+		return
+	}
+
+	channels, err := f.cfg.ChannelDB.FetchOpenChannels(
+		confirmed.IdentityPub,
+	)
+	if err != nil {
+		log.Errorf("Unable to fetch channels of peer %x: %v",
+			confirmed.IdentityPub.SerializeCompressed(), err)
+		return
+	}
+
+	for _, c := range channels {
+		if !c.IsPending || !c.ChanType.HasInteractiveFunding() ||
+			c.FundingOutpoint == confirmed.FundingOutpoint ||
+			c.FundingTxn == nil ||
+			!sharesInput(c.FundingTxn, confirmed.FundingTxn) {
+
+			continue
+		}
+
+		log.Infof("Funding tx of ChannelPoint(%v) was replaced by "+
+			"ChannelPoint(%v)", c.FundingOutpoint,
+			confirmed.FundingOutpoint)
+
+		localBalance := c.LocalCommitment.LocalBalance.ToSatoshis()
+		closeInfo := &channeldb.ChannelCloseSummary{
+			ChainHash:               c.ChainHash,
+			ChanPoint:               c.FundingOutpoint,
+			RemotePub:               c.IdentityPub,
+			Capacity:                c.Capacity,
+			SettledBalance:          localBalance,
+			CloseType:               channeldb.FundingCanceled,
+			RemoteCurrentRevocation: c.RemoteCurrentRevocation,
+			RemoteNextRevocation:    c.RemoteNextRevocation,
+			LocalChanConfig:         c.LocalChanCfg,
+		}
+		err := c.CloseChannel(
+			closeInfo, channeldb.ChanStatusLocalCloseInitiator,
+		)
+		if err != nil {
+			log.Errorf("Failed closing channel %v: %v",
+				c.FundingOutpoint, err)
+			continue
+		}
+
+		chanID := lnwire.NewChanIDFromOutPoint(&c.FundingOutpoint)
+		f.newChanBarriers.Delete(chanID)
+		f.localDiscoverySignals.Delete(chanID)
+
+		replaced, ok := f.fundingReplaced.LoadAndDelete(
+			c.FundingOutpoint,
+		)
+		if ok {
+			close(replaced)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package funding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateDualFundingMsg asserts that only the options supported for dual
+// funded channels are accepted.
+func TestValidateDualFundingMsg(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	dualFunding := []lnwire.FeatureBit{lnwire.DualFundOptional}
+
+	testCases := []struct {
+		name     string
+		features []lnwire.FeatureBit
+		modify   func(msg *InitFundingMsg)
+		valid    bool
+	}{{
+		name:     "valid",
+		features: dualFunding,
+		modify:   func(msg *InitFundingMsg) {},
+		valid:    true,
+	}, {
+		name:     "feature not supported",
+		features: nil,
+		modify:   func(msg *InitFundingMsg) {},
+	}, {
+		name:     "push amount",
+		features: dualFunding,
+		modify: func(msg *InitFundingMsg) {
+			msg.PushAmt = 1000
+		},
+	}, {
+		name:     "subtract fees",
+		features: dualFunding,
+		modify: func(msg *InitFundingMsg) {
+			msg.SubtractFees = true
+		},
+	}, {
+		name:     "fund max",
+		features: dualFunding,
+		modify: func(msg *InitFundingMsg) {
+			msg.FundUpToMaxAmt = 100000
+		},
+	}, {
+		name:     "outpoints",
+		features: dualFunding,
+		modify: func(msg *InitFundingMsg) {
+			msg.Outpoints = []wire.OutPoint{{Index: 1}}
+		},
+	}, {
+		name:     "chan funder",
+		features: dualFunding,
+		modify: func(msg *InitFundingMsg) {
+			msg.ChanFunder = chanfunding.NewInteractiveAssembler()
+		},
+	}, {
+		name:     "remote reserve",
+		features: dualFunding,
+		modify: func(msg *InitFundingMsg) {
+			msg.RemoteChanReserve = 1000
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			bob.localFeatures = tc.features
+			bob.remoteFeatures = tc.features
+
+			msg := &InitFundingMsg{
+				Peer:            bob,
+				LocalFundingAmt: 500000,
+				DualFunded:      true,
+			}
+			tc.modify(msg)
+
+			err := validateDualFundingMsg(msg)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestCheckDualFundedCommitType asserts that unsupported channel types are
+// rejected for dual funded channels.
+func TestCheckDualFundedCommitType(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkDualFundedCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx, false,
+	))
+	require.NoError(t, checkDualFundedCommitType(
+		lnwallet.CommitmentTypeTweakless, false,
+	))
+	require.Error(t, checkDualFundedCommitType(
+		lnwallet.CommitmentTypeSimpleTaproot, false,
+	))
+	require.Error(t, checkDualFundedCommitType(
+		lnwallet.CommitmentTypeScriptEnforcedLease, false,
+	))
+	require.Error(t, checkDualFundedCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx, true,
+	))
+}
+
+// TestFundingTxHelpers tests the helpers that inspect the funding transaction
+// of a dual funded channel.
+func TestFundingTxHelpers(t *testing.T) {
+	t.Parallel()
+
+	opA := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	opB := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	opC := wire.OutPoint{Hash: chainhash.Hash{3}, Index: 2}
+
+	tx1 := wire.NewMsgTx(2)
+	tx1.AddTxIn(wire.NewTxIn(&opA, nil, nil))
+	tx1.AddTxIn(wire.NewTxIn(&opB, nil, nil))
+
+	tx2 := wire.NewMsgTx(2)
+	tx2.AddTxIn(wire.NewTxIn(&opB, nil, nil))
+
+	tx3 := wire.NewMsgTx(2)
+	tx3.AddTxIn(wire.NewTxIn(&opC, nil, nil))
+
+	require.True(t, sharesInput(tx1, tx2))
+	require.True(t, sharesInput(tx2, tx1))
+	require.False(t, sharesInput(tx1, tx3))
+
+	// A funding transaction is only signed once all of its inputs carry
+	// a witness.
+	require.False(t, isFundingTxSigned(nil))
+	require.False(t, isFundingTxSigned(wire.NewMsgTx(2)))
+	require.False(t, isFundingTxSigned(tx1))
+
+	tx1.TxIn[0].Witness = wire.TxWitness{{0x01}}
+	require.False(t, isFundingTxSigned(tx1))
+
+	tx1.TxIn[1].Witness = wire.TxWitness{{0x02}}
+	require.True(t, isFundingTxSigned(tx1))
+}
+
+// TestReplacementFeeRate asserts that a funding transaction is only replaced
+// by one paying at least 25/24 of its fee rate.
+func TestReplacementFeeRate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkReplacementFeeRate(2400, 2500))
+	require.NoError(t, checkReplacementFeeRate(2400, 3000))
+	require.ErrorIs(
+		t, checkReplacementFeeRate(2400, 2499),
+		ErrReplacementFeeRateTooLow,
+	)
+	require.ErrorIs(
+		t, checkReplacementFeeRate(2400, 2400),
+		ErrReplacementFeeRateTooLow,
+	)
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	// The funding transaction spends a single coin of the wallet, which
+	// the mock wallet values at 10 LTC, and paid 2400 sat/kw for it.
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(500000, []byte{0x00}))
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddP2WKHInput()
+	prevFee := chainfee.SatPerKWeight(2400).FeeForWeight(
+		int64(weightEstimate.Weight()),
+	)
+
+	replacement := func(
+		feeRate chainfee.SatPerKWeight) *interactiveFunding {
+
+		return &interactiveFunding{
+			localAmt: 10*ltcutil.SatoshiPerBitcoin - prevFee,
+			feeRate:  feeRate,
+			prevChannel: &channeldb.OpenChannel{
+				FundingTxn: prevTx,
+			},
+		}
+	}
+
+	// Paying the same fee rate again doesn't replace the transaction.
+	err := alice.fundingMgr.reuseFundingCoins(replacement(2400))
+	require.ErrorIs(t, err, ErrReplacementFeeRateTooLow)
+
+	// A high enough fee rate passes the check, and only fails as the
+	// funding transaction has no change to pay the higher fee from.
+	err = alice.fundingMgr.reuseFundingCoins(replacement(2500))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrReplacementFeeRateTooLow)
+}
+
+// TestFundingManagerDualFundingUnsupported asserts that a dual funded channel
+// isn't opened with a peer that doesn't support dual funding, and that a
+// request to open one is rejected if we don't support it.
+func TestFundingManagerDualFundingUnsupported(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	// Alice tries to open a dual funded channel with Bob, which isn't
+	// possible as the feature wasn't negotiated.
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		DualFunded:      true,
+		Updates:         updateChan,
+		Err:             errChan,
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-errChan:
+		require.ErrorIs(t, err, ErrDualFundingUnsupported)
+
+	case msg := <-alice.msgChan:
+		t.Fatalf("alice unexpectedly sent %T", msg)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("dual funding request not rejected")
+	}
+	assertNumPendingReservations(t, alice, bobPubKey, 0)
+
+	// Bob rejects a request of Alice to open a dual funded channel as
+	// well.
+	openChannel2 := &lnwire.OpenChannel2{
+		ChainHash:        *fundingNetParams.GenesisHash,
+		PendingChannelID: [32]byte{1},
+		FundingAmount:    500000,
+	}
+	bob.fundingMgr.ProcessFundingMsg(openChannel2, alice)
+
+	assertFundingMsgSent(t, bob.msgChan, "Error")
+	assertNumPendingReservations(t, bob, alicePubKey, 0)
+}
@@ -157,6 +157,10 @@ type reservationWithCtx struct {
 	// the channel.
 	channelType *lnwire.ChannelType
 
+	// interactive is the state of the interactive construction of the
+	// funding transaction. It is only set for dual funded channels.
+	interactive *interactiveFunding
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -290,6 +294,11 @@ type InitFundingMsg struct {
 	// channel that will be useful to our future selves.
 	Memo []byte
 
+	// DualFunded set to true means that the channel is opened with the
+	// dual funding protocol, which allows the remote peer to contribute
+	// funds to the channel as well.
+	DualFunded bool
+
 	// Updates is a channel which updates to the opening status of the
 	// channel are sent on.
 	Updates chan *lnrpc.OpenStatusUpdate
@@ -583,7 +592,13 @@ type Manager struct {
 	// signed by both parties.
 	signedReservations map[lnwire.ChannelID][32]byte
 
-	// resMtx guards both of the maps above to ensure that all access is
+	// interactiveFundings houses the state of all dual funded channels
+	// whose funding transaction is currently constructed or signed with
+	// the remote peer. It is keyed by the channel ID used in the
+	// interactive transaction construction messages.
+	interactiveFundings map[lnwire.ChannelID]*interactiveFunding
+
+	// resMtx guards all of the maps above to ensure that all access is
 	// goroutine safe.
 	resMtx sync.RWMutex
 
@@ -595,6 +610,15 @@ type Manager struct {
 	// requests from a local subsystem within the daemon.
 	fundingRequests chan *InitFundingMsg
 
+	// bumpRequests is a channel used to receive requests to replace the
+	// funding transaction of a pending dual funded channel.
+	bumpRequests chan *BumpFundingMsg
+
+	// fundingReplaced is a map from the funding outpoint of a pending dual
+	// funded channel to a channel that is closed once a conflicting
+	// funding transaction of the same channel confirmed.
+	fundingReplaced *lnutils.SyncMap[wire.OutPoint, chan struct{}]
+
 	// newChanBarriers is a map from a channel ID to a 'barrier' which will
 	// be signalled once the channel is fully open. This barrier acts as a
 	// synchronization point for any incoming/outgoing HTLCs before the
@@ -657,6 +681,9 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		signedReservations: make(
 			map[lnwire.ChannelID][32]byte,
 		),
+		interactiveFundings: make(
+			map[lnwire.ChannelID]*interactiveFunding,
+		),
 		newChanBarriers: &lnutils.SyncMap[
 			lnwire.ChannelID, chan struct{},
 		]{},
@@ -666,6 +693,12 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		fundingRequests: make(
 			chan *InitFundingMsg, msgBufferSize,
 		),
+		bumpRequests: make(
+			chan *BumpFundingMsg, msgBufferSize,
+		),
+		fundingReplaced: &lnutils.SyncMap[
+			wire.OutPoint, chan struct{},
+		]{},
 		localDiscoverySignals: &lnutils.SyncMap[
 			lnwire.ChannelID, chan struct{},
 		]{},
@@ -725,7 +758,17 @@ func (f *Manager) start() error {
 			chanType := channel.ChanType
 			if chanType.IsSingleFunder() &&
 				chanType.HasFundingTx() &&
-				channel.IsInitiator {
+				channel.IsInitiator &&
+				!chanType.HasInteractiveFunding() {
+
+				f.rebroadcastFundingTx(channel)
+			}
+
+			// Both parties of a dual funded channel rebroadcast
+			// its funding transaction, but only once it has been
+			// signed by both of them.
+			if chanType.HasInteractiveFunding() &&
+				isFundingTxSigned(channel.FundingTxn) {
 
 				f.rebroadcastFundingTx(channel)
 			}
@@ -827,6 +870,19 @@ func (f *Manager) CancelPeerReservations(nodePub [33]byte) {
 	f.resMtx.Lock()
 	defer f.resMtx.Unlock()
 
+	// Any funding transaction of a dual funded channel that is still
+	// being constructed or signed with the peer can't be completed
+	// anymore.
+	for chanID, fund := range f.interactiveFundings {
+		if fund.peer.PubKey() != nodePub || fund.resCtx != nil {
+			continue
+		}
+
+		fund.cancel()
+		fund.notifyErr(fmt.Errorf("peer disconnected"))
+		delete(f.interactiveFundings, chanID)
+	}
+
 	// We'll attempt to look up this node in the set of active
 	// reservations.  If they don't have any, then there's no further work
 	// to be done.
@@ -845,6 +901,11 @@ func (f *Manager) CancelPeerReservations(nodePub [33]byte) {
 				"node=%x: %v", nodePub[:], err)
 		}
 
+		if resCtx.interactive != nil {
+			resCtx.interactive.cancel()
+			delete(f.interactiveFundings, resCtx.interactive.chanID)
+		}
+
 		resCtx.err <- fmt.Errorf("peer disconnected")
 		delete(nodeReservations, pendingID)
 	}
@@ -933,33 +994,36 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, cid *chanIdentifier,
 		ctx.err <- fundingErr
 	}
 
+	errMsg := &lnwire.Error{
+		ChanID: cid.tempChanID,
+		Data:   fundingErrorData(fundingErr),
+	}
+
+	log.Debugf("Sending funding error to peer (%x): %v",
+		peer.IdentityKey().SerializeCompressed(), spew.Sdump(errMsg))
+	if err := peer.SendMessage(false, errMsg); err != nil {
+		log.Errorf("unable to send error message to peer %v", err)
+	}
+}
+
+// fundingErrorData returns the data of the error message that is sent to the
+// remote peer when a funding flow fails with the given error.
+func fundingErrorData(fundingErr error) lnwire.ErrorData {
 	// We only send the exact error if it is part of out whitelisted set of
 	// errors (lnwire.FundingError or lnwallet.ReservationError).
-	var msg lnwire.ErrorData
 	switch e := fundingErr.(type) {
 	// Let the actual error message be sent to the remote for the
 	// whitelisted types.
 	case lnwallet.ReservationError:
-		msg = lnwire.ErrorData(e.Error())
+		return lnwire.ErrorData(e.Error())
 	case lnwire.FundingError:
-		msg = lnwire.ErrorData(e.Error())
+		return lnwire.ErrorData(e.Error())
 	case chanacceptor.ChanAcceptError:
-		msg = lnwire.ErrorData(e.Error())
+		return lnwire.ErrorData(e.Error())
 
 	// For all other error types we just send a generic error.
 	default:
-		msg = lnwire.ErrorData("funding failed due to internal error")
-	}
-
-	errMsg := &lnwire.Error{
-		ChanID: cid.tempChanID,
-		Data:   msg,
-	}
-
-	log.Debugf("Sending funding error to peer (%x): %v",
-		peer.IdentityKey().SerializeCompressed(), spew.Sdump(errMsg))
-	if err := peer.SendMessage(false, errMsg); err != nil {
-		log.Errorf("unable to send error message to peer %v", err)
+		return lnwire.ErrorData("funding failed due to internal error")
 	}
 }
 
@@ -983,6 +1047,33 @@ func (f *Manager) reservationCoordinator() {
 			case *lnwire.AcceptChannel:
 				f.handleFundingAccept(fmsg.peer, msg)
 
+			case *lnwire.OpenChannel2:
+				f.handleFundingOpen2(fmsg.peer, msg)
+
+			case *lnwire.AcceptChannel2:
+				f.handleFundingAccept2(fmsg.peer, msg)
+
+			case *lnwire.TxInitRbf:
+				f.handleTxInitRbf(fmsg.peer, msg)
+
+			case *lnwire.TxAckRbf:
+				f.handleTxAckRbf(fmsg.peer, msg)
+
+			case *lnwire.TxAddInput, *lnwire.TxAddOutput,
+				*lnwire.TxRemoveInput, *lnwire.TxRemoveOutput,
+				*lnwire.TxComplete:
+
+				f.handleConstructionMsg(fmsg.peer, msg)
+
+			case *lnwire.CommitSig:
+				f.handleInteractiveCommitSig(fmsg.peer, msg)
+
+			case *lnwire.TxSignatures:
+				f.handleTxSignatures(fmsg.peer, msg)
+
+			case *lnwire.TxAbort:
+				f.handleTxAbort(fmsg.peer, msg)
+
 			case *lnwire.FundingCreated:
 				f.handleFundingCreated(fmsg.peer, msg)
 
@@ -1002,6 +1093,9 @@ func (f *Manager) reservationCoordinator() {
 		case req := <-f.fundingRequests:
 			f.handleInitFundingMsg(req)
 
+		case req := <-f.bumpRequests:
+			f.handleBumpFundingMsg(req)
+
 		case <-zombieSweepTicker.C:
 			f.pruneZombieReservations()
 
@@ -1028,6 +1122,11 @@ func (f *Manager) advanceFundingState(channel *channeldb.OpenChannel,
 	// transaction to confirm.
 	if channel.IsPending {
 		err := f.advancePendingChannelState(channel, pendingChanID)
+		if err == ErrFundingReplaced {
+			log.Infof("Funding tx of ChannelPoint(%v) was replaced",
+				channel.FundingOutpoint)
+			return
+		}
 		if err != nil {
 			log.Errorf("Unable to advance pending state of "+
 				"ChannelPoint(%v): %v",
@@ -1266,12 +1365,21 @@ func (f *Manager) advancePendingChannelState(
 	confChannel, err := f.waitForFundingWithTimeout(channel)
 	if err == ErrConfirmationTimeout {
 		return f.fundingTimeout(channel, pendingChanID)
+	} else if err == ErrFundingReplaced {
+		return err
 	} else if err != nil {
 		return fmt.Errorf("error waiting for funding "+
 			"confirmation for ChannelPoint(%v): %v",
 			channel.FundingOutpoint, err)
 	}
 
+	// The funding transaction of a dual funded channel may have been
+	// replaced with transactions paying a higher fee. Now that one of
+	// them confirmed, the others can be forgotten.
+	if channel.ChanType.HasInteractiveFunding() {
+		f.pruneReplacedFundings(channel)
+	}
+
 	// Success, funding transaction was confirmed.
 	chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
 	log.Debugf("ChannelID(%v) is now fully confirmed! "+
@@ -1306,105 +1414,21 @@ func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
 func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	msg *lnwire.OpenChannel) {
 
-	// Check number of pending channels to be smaller than maximum allowed
-	// number and send ErrorGeneric to remote peer if condition is
-	// violated.
 	peerPubKey := peer.IdentityKey()
 	peerIDKey := newSerializedKey(peerPubKey)
 
 	amt := msg.FundingAmount
 
-	// We get all pending channels for this peer. This is the list of the
-	// active reservations and the channels pending open in the database.
-	f.resMtx.RLock()
-	reservations := f.activeReservations[peerIDKey]
-
-	// We don't count reservations that were created from a canned funding
-	// shim. The user has registered the shim and therefore expects this
-	// channel to arrive.
-	numPending := 0
-	for _, res := range reservations {
-		if !res.reservation.IsCannedShim() {
-			numPending++
-		}
-	}
-	f.resMtx.RUnlock()
-
 	// Create the channel identifier.
 	cid := newChanIdentifier(msg.PendingChannelID)
 
-	// Also count the channels that are already pending. There we don't know
-	// the underlying intent anymore, unfortunately.
-	channels, err := f.cfg.ChannelDB.FetchOpenChannels(peerPubKey)
-	if err != nil {
-		f.failFundingFlow(peer, cid, err)
-		return
-	}
-
-	for _, c := range channels {
-		// Pending channels that have a non-zero thaw height were also
-		// created through a canned funding shim. Those also don't
-		// count towards the DoS protection limit.
-		//
-		// TODO(guggero): Properly store the funding type (wallet, shim,
-		// PSBT) on the channel so we don't need to use the thaw height.
-		if c.IsPending && c.ThawHeight == 0 {
-			numPending++
-		}
-	}
-
-	// TODO(roasbeef): modify to only accept a _single_ pending channel per
-	// block unless white listed
-	if numPending >= f.cfg.MaxPendingChannels {
-		f.failFundingFlow(peer, cid, lnwire.ErrMaxPendingChannels)
-
-		return
-	}
-
-	// Ensure that the pendingChansLimit is respected.
-	pendingChans, err := f.cfg.ChannelDB.FetchPendingChannels()
-	if err != nil {
-		f.failFundingFlow(peer, cid, err)
-		return
-	}
-
-	if len(pendingChans) > pendingChansLimit {
-		f.failFundingFlow(peer, cid, lnwire.ErrMaxPendingChannels)
-		return
-	}
-
-	// We'll also reject any requests to create channels until we're fully
-	// synced to the network as we won't be able to properly validate the
-	// confirmation of the funding transaction.
-	isSynced, _, err := f.cfg.Wallet.IsSynced()
-	if err != nil || !isSynced {
-		if err != nil {
-			log.Errorf("unable to query wallet: %v", err)
-		}
-		err := errors.New("Synchronizing blockchain")
+	// Make sure that we're able to accept another channel of the proposed
+	// size from this peer.
+	if err := f.validateChannelRequest(peer, amt); err != nil {
 		f.failFundingFlow(peer, cid, err)
 		return
 	}
 
-	// Ensure that the remote party respects our maximum channel size.
-	if amt > f.cfg.MaxChanSize {
-		f.failFundingFlow(
-			peer, cid,
-			lnwallet.ErrChanTooLarge(amt, f.cfg.MaxChanSize),
-		)
-		return
-	}
-
-	// We'll, also ensure that the remote party isn't attempting to propose
-	// a channel that's below our current min channel size.
-	if amt < f.cfg.MinChanSize {
-		f.failFundingFlow(
-			peer, cid,
-			lnwallet.ErrChanTooSmall(amt, f.cfg.MinChanSize),
-		)
-		return
-	}
-
 	// If request specifies non-zero push amount and 'rejectpush' is set,
 	// signal an error.
 	if f.cfg.RejectPush && msg.PushAmount > 0 {
@@ -1826,6 +1850,96 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	}
 }
 
+// validateChannelRequest checks whether we're able to accept a new channel of
+// the given size from the peer. The number of pending channels must be below
+// the configured limits, we must be synced to the chain, and the channel size
+// must be within our bounds. The returned error is meant to be sent to the
+// peer.
+func (f *Manager) validateChannelRequest(peer lnpeer.Peer,
+	amt ltcutil.Amount) error {
+
+	// Check number of pending channels to be smaller than maximum allowed
+	// number and send ErrorGeneric to remote peer if condition is
+	// violated.
+	peerPubKey := peer.IdentityKey()
+	peerIDKey := newSerializedKey(peerPubKey)
+
+	// We get all pending channels for this peer. This is the list of the
+	// active reservations and the channels pending open in the database.
+	f.resMtx.RLock()
+	reservations := f.activeReservations[peerIDKey]
+
+	// We don't count reservations that were created from a canned funding
+	// shim. The user has registered the shim and therefore expects this
+	// channel to arrive.
+	numPending := 0
+	for _, res := range reservations {
+		if !res.reservation.IsCannedShim() {
+			numPending++
+		}
+	}
+	f.resMtx.RUnlock()
+
+	// Also count the channels that are already pending. There we don't know
+	// the underlying intent anymore, unfortunately.
+	channels, err := f.cfg.ChannelDB.FetchOpenChannels(peerPubKey)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range channels {
+		// Pending channels that have a non-zero thaw height were also
+		// created through a canned funding shim. Those also don't
+		// count towards the DoS protection limit.
+		//
+		// TODO(guggero): Properly store the funding type (wallet, shim,
+		// PSBT) on the channel so we don't need to use the thaw height.
+		if c.IsPending && c.ThawHeight == 0 {
+			numPending++
+		}
+	}
+
+	// TODO(roasbeef): modify to only accept a _single_ pending channel per
+	// block unless white listed
+	if numPending >= f.cfg.MaxPendingChannels {
+		return lnwire.ErrMaxPendingChannels
+	}
+
+	// Ensure that the pendingChansLimit is respected.
+	pendingChans, err := f.cfg.ChannelDB.FetchPendingChannels()
+	if err != nil {
+		return err
+	}
+
+	if len(pendingChans) > pendingChansLimit {
+		return lnwire.ErrMaxPendingChannels
+	}
+
+	// We'll also reject any requests to create channels until we're fully
+	// synced to the network as we won't be able to properly validate the
+	// confirmation of the funding transaction.
+	isSynced, _, err := f.cfg.Wallet.IsSynced()
+	if err != nil || !isSynced {
+		if err != nil {
+			log.Errorf("unable to query wallet: %v", err)
+		}
+		return errors.New("Synchronizing blockchain")
+	}
+
+	// Ensure that the remote party respects our maximum channel size.
+	if amt > f.cfg.MaxChanSize {
+		return lnwallet.ErrChanTooLarge(amt, f.cfg.MaxChanSize)
+	}
+
+	// We'll, also ensure that the remote party isn't attempting to propose
+	// a channel that's below our current min channel size.
+	if amt < f.cfg.MinChanSize {
+		return lnwallet.ErrChanTooSmall(amt, f.cfg.MinChanSize)
+	}
+
+	return nil
+}
+
 // handleFundingAccept processes a response to the workflow initiation sent by
 // the remote peer. This message then queues a message with the funding
 // outpoint, and a commitment signature to the remote peer.
@@ -2741,14 +2855,34 @@ func (f *Manager) waitForFundingWithTimeout(
 
 	// If we are not the initiator, we have no money at stake and will
 	// timeout waiting for the funding transaction to confirm after a
-	// while.
-	if !ch.IsInitiator && !ch.IsZeroConf() {
+	// while. The same goes for the initiator of a dual funded channel if
+	// the funding transaction was never signed by both parties, as it
+	// can't be broadcast in that case.
+	waitForTimeout := !ch.IsInitiator
+	if ch.ChanType.HasInteractiveFunding() &&
+		!isFundingTxSigned(ch.FundingTxn) {
+
+		waitForTimeout = true
+	}
+	if waitForTimeout && !ch.IsZeroConf() {
 		f.wg.Add(1)
 		go f.waitForTimeout(ch, cancelChan, timeoutChan)
 	}
 	defer close(cancelChan)
 
+	// A conflicting funding transaction of a dual funded channel may
+	// confirm instead of the one of this channel.
+	var replacedChan chan struct{}
+	if ch.ChanType.HasInteractiveFunding() {
+		replacedChan = make(chan struct{})
+		f.fundingReplaced.Store(ch.FundingOutpoint, replacedChan)
+		defer f.fundingReplaced.Delete(ch.FundingOutpoint)
+	}
+
 	select {
+	case <-replacedChan:
+		return nil, ErrFundingReplaced
+
 	case err := <-timeoutChan:
 		if err != nil {
 			return nil, err
@@ -4408,6 +4542,15 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		"min_confs=%v)", localAmt, msg.SubtractFees, msg.PushAmt,
 		msg.ChainHash, peerKey.SerializeCompressed(), msg.MinConfs)
 
+	// Dual funded channels only support a subset of the options of the
+	// single funder workflow.
+	if msg.DualFunded {
+		if err := validateDualFundingMsg(msg); err != nil {
+			msg.Err <- err
+			return
+		}
+	}
+
 	// We set the channel flags to indicate whether we want this channel to
 	// be announced to the network.
 	var channelFlags lnwire.FundingFlag
@@ -4515,6 +4658,32 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		scidFeatureVal = true
 	}
 
+	// For a dual funded channel, we select the coins we contribute to the
+	// funding transaction upfront, as the transaction is only constructed
+	// once the remote peer accepted the channel.
+	var (
+		chanFunder = msg.ChanFunder
+		fund       *interactiveFunding
+	)
+	if msg.DualFunded {
+		err := checkDualFundedCommitType(commitType, zeroConf)
+		if err != nil {
+			msg.Err <- err
+			return
+		}
+
+		fund, err = f.newInteractiveFunding(
+			msg.Peer, chanID, true, localAmt, msg.FundingFeePerKw,
+		)
+		if err != nil {
+			msg.Err <- err
+			return
+		}
+		fund.errChan = msg.Err
+
+		chanFunder = chanfunding.NewInteractiveAssembler()
+	}
+
 	req := &lnwallet.InitFundingReserveMsg{
 		ChainHash:         &msg.ChainHash,
 		PendingChanID:     chanID,
@@ -4533,15 +4702,21 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		Flags:             channelFlags,
 		MinConfs:          msg.MinConfs,
 		CommitType:        commitType,
-		ChanFunder:        msg.ChanFunder,
+		ChanFunder:        chanFunder,
 		ZeroConf:          zeroConf,
 		OptionScidAlias:   scid,
 		ScidAliasFeature:  scidFeatureVal,
+		DualFunded:        msg.DualFunded,
+		Initiator:         msg.DualFunded,
 		Memo:              msg.Memo,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
 	if err != nil {
+		if fund != nil {
+			fund.cancel()
+		}
+
 		msg.Err <- err
 		return
 	}
@@ -4642,6 +4817,7 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		remoteChanReserve: chanReserve,
 		maxLocalCsv:       maxCSV,
 		channelType:       chanType,
+		interactive:       fund,
 		reservation:       reservation,
 		peer:              msg.Peer,
 		updates:           msg.Updates,
@@ -4650,6 +4826,10 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 	f.activeReservations[peerIDKey][chanID] = resCtx
 	f.resMtx.Unlock()
 
+	if fund != nil {
+		fund.resCtx = resCtx
+	}
+
 	// Update the timestamp once the InitFundingMsg has been handled.
 	defer resCtx.updateTimestamp()
 
@@ -4718,7 +4898,15 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		LeaseExpiry:           leaseExpiry,
 		LocalNonce:            localNonce,
 	}
-	if err := msg.Peer.SendMessage(true, &fundingOpen); err != nil {
+
+	// Dual funded channels are opened with the v2 channel establishment
+	// protocol.
+	var openMsg lnwire.Message = &fundingOpen
+	if msg.DualFunded {
+		openMsg = newOpenChannel2(&fundingOpen, fund)
+	}
+
+	if err := msg.Peer.SendMessage(true, openMsg); err != nil {
 		e := fmt.Errorf("unable to send funding request message: %v",
 			err)
 		log.Errorf(e.Error())
@@ -4751,6 +4939,13 @@ func (f *Manager) handleErrorMsg(peer lnpeer.Peer, msg *lnwire.Error) {
 	// First, we'll attempt to retrieve and cancel the funding workflow
 	// that this error was tied to. If we're unable to do so, then we'll
 	// exit early as this was an unwarranted error.
+	// The error might abort the construction of a funding transaction
+	// that replaces the one of a pending dual funded channel, which has no
+	// reservation.
+	if f.failReplacement(peer, chanID, msg) {
+		return
+	}
+
 	resCtx, err := f.cancelReservationCtx(peerKey, chanID, true)
 	if err != nil {
 		log.Warnf("Received error for non-existent funding "+
@@ -4860,6 +5055,13 @@ func (f *Manager) cancelReservationCtx(peerKey *btcec.PublicKey,
 			err)
 	}
 
+	// The coins we contributed to a dual funded channel are no longer
+	// needed either.
+	if ctx.interactive != nil {
+		ctx.interactive.cancel()
+		delete(f.interactiveFundings, ctx.interactive.chanID)
+	}
+
 	delete(nodeReservations, pendingChanID)
 
 	// If this was the last active reservation for this peer, delete the
@@ -4924,8 +5126,16 @@ func (f *Manager) IsPendingChannel(pendingChanID [32]byte,
 	peerIDKey := newSerializedKey(peer.IdentityKey())
 	f.resMtx.RLock()
 	_, ok := f.activeReservations[peerIDKey][pendingChanID]
+	fund, isInteractive := f.interactiveFundings[pendingChanID]
 	f.resMtx.RUnlock()
 
+	// The funding transaction of a dual funded channel is constructed and
+	// signed with the channel ID of the pending channel, or the one of the
+	// funding transaction it replaces.
+	if isInteractive && fund.peer.PubKey() == peer.PubKey() {
+		return true
+	}
+
 	return ok
 }
 
@@ -5072,3 +5282,31 @@ func (f *Manager) waitForPeerOnline(peerPubkey *btcec.PublicKey) (lnpeer.Peer,
 	}
 	return peer, nil
 }
+
+// AnnounceSplicedChannel adds a channel that was moved over to the funding
+// output of a confirmed splice transaction to the router graph, and announces
+// it to the greater network once the splice transaction has six
+// confirmations. Our routing policy of the channel before the splice is
+// carried over to the spliced channel.
+func (f *Manager) AnnounceSplicedChannel(channel *channeldb.OpenChannel,
+	ourPolicy *channeldb.ChannelEdgePolicy) error {
+
+	scid := channel.ShortChannelID
+	err := f.addToRouterGraph(channel, &scid, nil, ourPolicy)
+	if err != nil {
+		return fmt.Errorf("failed adding to router graph: %w", err)
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		if err := f.annAfterSixConfs(channel, &scid); err != nil {
+			log.Errorf("Unable to announce spliced "+
+				"ChannelPoint(%v): %v", channel.FundingOutpoint,
+				err)
+		}
+	}()
+
+	return nil
+}
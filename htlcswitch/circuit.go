@@ -201,6 +201,20 @@ func (c *PaymentCircuit) Decode(r io.Reader) error {
 		// Sphinx encrypter was used as this is a forwarded HTLC.
 		c.ErrorEncrypter = hop.NewSphinxErrorEncrypter()
 
+	case hop.EncrypterTypeIntroduction:
+		// We're the introduction node of the blinded route that this
+		// HTLC was forwarded through.
+		c.ErrorEncrypter = hop.NewIntroductionErrorEncrypter(
+			hop.NewSphinxErrorEncrypter(),
+		)
+
+	case hop.EncrypterTypeRelaying:
+		// This HTLC was forwarded to us by a node inside a blinded
+		// route.
+		c.ErrorEncrypter = hop.NewRelayingErrorEncrypter(
+			hop.NewSphinxErrorEncrypter(),
+		)
+
 	case hop.EncrypterTypeMock:
 		// Test encrypter.
 		c.ErrorEncrypter = NewMockObfuscator()
@@ -25,8 +25,24 @@ const (
 
 	// EncrypterTypeMock is used to identify a mock obfuscator instance.
 	EncrypterTypeMock = 2
+
+	// EncrypterTypeIntroduction is used to identify a sphinx onion error
+	// encrypter of an htlc for which we're the introduction node of a
+	// blinded route.
+	EncrypterTypeIntroduction = 3
+
+	// EncrypterTypeRelaying is used to identify a sphinx onion error
+	// encrypter of an htlc that we received from a node inside a blinded
+	// route.
+	EncrypterTypeRelaying = 4
 )
 
+// IsBlinded returns true if the encrypter type belongs to an htlc that is
+// part of a blinded route.
+func (e EncrypterType) IsBlinded() bool {
+	return e == EncrypterTypeIntroduction || e == EncrypterTypeRelaying
+}
+
 // ErrorEncrypterExtracter defines a function signature that extracts an
 // ErrorEncrypter from an sphinx OnionPacket.
 type ErrorEncrypterExtracter func(*btcec.PublicKey) (ErrorEncrypter,
@@ -203,3 +219,57 @@ func (s *SphinxErrorEncrypter) Reextract(
 // A compile time check to ensure SphinxErrorEncrypter implements the
 // ErrorEncrypter interface.
 var _ ErrorEncrypter = (*SphinxErrorEncrypter)(nil)
+
+// IntroductionErrorEncrypter wraps the error encrypter of an htlc for which
+// we're the introduction node of a blinded route. Failures of such htlcs are
+// replaced by an invalid blinding failure before they're returned to the
+// sender, so that the blinded part of the route can't be probed.
+type IntroductionErrorEncrypter struct {
+	// ErrorEncrypter is the underlying error encrypter, which is embedded
+	// so that the wrapper only needs to override its type.
+	ErrorEncrypter
+}
+
+// NewIntroductionErrorEncrypter wraps the given error encrypter of an htlc for
+// which we're the introduction node of a blinded route.
+func NewIntroductionErrorEncrypter(
+	e ErrorEncrypter) *IntroductionErrorEncrypter {
+
+	return &IntroductionErrorEncrypter{
+		ErrorEncrypter: e,
+	}
+}
+
+// Type returns the identifier for an introduction node error encrypter.
+func (i *IntroductionErrorEncrypter) Type() EncrypterType {
+	return EncrypterTypeIntroduction
+}
+
+// RelayingErrorEncrypter wraps the error encrypter of an htlc that we received
+// from a node inside a blinded route. Such htlcs are always failed back as
+// malformed, so that the blinded part of the route can't be probed.
+type RelayingErrorEncrypter struct {
+	// ErrorEncrypter is the underlying error encrypter, which is embedded
+	// so that the wrapper only needs to override its type.
+	ErrorEncrypter
+}
+
+// NewRelayingErrorEncrypter wraps the given error encrypter of an htlc that we
+// received from a node inside a blinded route.
+func NewRelayingErrorEncrypter(e ErrorEncrypter) *RelayingErrorEncrypter {
+	return &RelayingErrorEncrypter{
+		ErrorEncrypter: e,
+	}
+}
+
+// Type returns the identifier for a relaying node error encrypter.
+func (r *RelayingErrorEncrypter) Type() EncrypterType {
+	return EncrypterTypeRelaying
+}
+
+// A compile time check to ensure the blinded error encrypters implement the
+// ErrorEncrypter interface.
+var (
+	_ ErrorEncrypter = (*IntroductionErrorEncrypter)(nil)
+	_ ErrorEncrypter = (*RelayingErrorEncrypter)(nil)
+)
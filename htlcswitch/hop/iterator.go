@@ -2,15 +2,21 @@ package hop
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
 
 	sphinx "github.com/ltcsuite/lightning-onion"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/record"
 	"github.com/ltcsuite/ltcd/btcec/v2"
 )
 
+// ErrInvalidBlinding is returned when the route blinding data of a hop can't
+// be processed, or the HTLC violates the constraints it sets.
+var ErrInvalidBlinding = errors.New("invalid route blinding data")
+
 // Iterator is an interface that abstracts away the routing information
 // included in HTLC's which includes the entirety of the payment path of an
 // HTLC. This interface provides two basic method which carry out: how to
@@ -47,16 +53,22 @@ type sphinxHopIterator struct {
 	// includes the information required to properly forward the packet to
 	// the next hop.
 	processedPacket *sphinx.ProcessedPacket
+
+	// blindingKit contains the elements required to process hops that
+	// are part of a blinded route.
+	blindingKit BlindingKit
 }
 
 // makeSphinxHopIterator converts a processed packet returned from a sphinx
 // router and converts it into an hop iterator for usage in the link.
 func makeSphinxHopIterator(ogPacket *sphinx.OnionPacket,
-	packet *sphinx.ProcessedPacket) *sphinxHopIterator {
+	packet *sphinx.ProcessedPacket,
+	blindingKit BlindingKit) *sphinxHopIterator {
 
 	return &sphinxHopIterator{
 		ogPacket:        ogPacket,
 		processedPacket: packet,
+		blindingKit:     blindingKit,
 	}
 }
 
@@ -90,9 +102,40 @@ func (r *sphinxHopIterator) HopPayload() (*Payload, error) {
 	// Otherwise, if this is the TLV payload, then we'll make a new stream
 	// to decode only what we need to make routing decisions.
 	case sphinx.PayloadTLV:
-		return NewPayloadFromReader(bytes.NewReader(
-			r.processedPacket.Payload.Payload,
-		))
+		isFinal := r.processedPacket.Action == sphinx.ExitNode
+		payload, err := NewPayloadFromReader(
+			bytes.NewReader(r.processedPacket.Payload.Payload),
+			isFinal,
+		)
+		if err != nil {
+			// Any failure inside a blinded route is reported as
+			// an invalid blinding, so that it can't be used to
+			// probe the route.
+			if r.blindingKit.UpdateAddBlinding != nil {
+				return nil, fmt.Errorf("%w: %v",
+					ErrInvalidBlinding, err)
+			}
+
+			return nil, err
+		}
+
+		// A hop that received a blinding point along with the HTLC
+		// must be part of a blinded route.
+		if payload.encryptedData == nil {
+			if r.blindingKit.UpdateAddBlinding != nil {
+				return nil, fmt.Errorf("%w: missing encrypted "+
+					"data", ErrInvalidBlinding)
+			}
+
+			return payload, nil
+		}
+
+		err = r.blindingKit.DecryptAndValidateFwdInfo(payload, isFinal)
+		if err != nil {
+			return nil, err
+		}
+
+		return payload, nil
 
 	default:
 		return nil, fmt.Errorf("unknown sphinx payload type: %v",
@@ -112,6 +155,201 @@ func (r *sphinxHopIterator) ExtractErrorEncrypter(
 	return extracter(r.ogPacket.EphemeralKey)
 }
 
+// BlindingProcessor is an interface that provides the cryptographic operations
+// required for processing blinded hops.
+//
+// This interface is extracted to allow more granular testing of blinded
+// forwarding calculations.
+type BlindingProcessor interface {
+	// DecryptBlindedHopData decrypts a blinded blob of data using the
+	// ephemeral key provided.
+	DecryptBlindedHopData(ephemPub *btcec.PublicKey,
+		encryptedData []byte) ([]byte, error)
+
+	// NextEphemeral returns the next hop's ephemeral key, calculated
+	// from the current ephemeral key provided.
+	NextEphemeral(*btcec.PublicKey) (*btcec.PublicKey, error)
+}
+
+// BlindingKit contains the components required to extract forwarding
+// information for hops in a blinded route.
+type BlindingKit struct {
+	// Processor provides the low-level cryptographic operations to
+	// handle an encrypted blob of data in a blinded forward.
+	Processor BlindingProcessor
+
+	// UpdateAddBlinding holds a blinding point that was passed to the
+	// node via update_add_htlc's TLVs.
+	UpdateAddBlinding *btcec.PublicKey
+
+	// IncomingCltv is the expiry of the incoming HTLC.
+	IncomingCltv uint32
+
+	// IncomingAmount is the amount of the incoming HTLC.
+	IncomingAmount lnwire.MilliSatoshi
+}
+
+// DecryptAndValidateFwdInfo decrypts the route blinding data of a payload
+// and validates the incoming HTLC against the constraints it sets. For
+// intermediate hops, the forwarding information of the payload is replaced by
+// the one derived from the blinding data. For the final hop, the payment
+// address is taken from the path id the recipient left for itself.
+func (b *BlindingKit) DecryptAndValidateFwdInfo(payload *Payload,
+	isFinalHop bool) error {
+
+	// The blinding point is either provided by the previous hop, or by
+	// the sender if we're the introduction node, but never by both.
+	var ephemeral *btcec.PublicKey
+	switch {
+	case b.UpdateAddBlinding != nil && payload.blindingPoint != nil:
+		return fmt.Errorf("%w: blinding point included in both "+
+			"update_add_htlc and payload", ErrInvalidBlinding)
+
+	case b.UpdateAddBlinding != nil:
+		ephemeral = b.UpdateAddBlinding
+
+	case payload.blindingPoint != nil:
+		ephemeral = payload.blindingPoint
+
+	default:
+		return fmt.Errorf("%w: blinding point missing",
+			ErrInvalidBlinding)
+	}
+
+	decrypted, err := b.Processor.DecryptBlindedHopData(
+		ephemeral, payload.encryptedData,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: could not decrypt data: %v",
+			ErrInvalidBlinding, err)
+	}
+
+	routeData, err := record.DecodeBlindedRouteData(
+		bytes.NewReader(decrypted),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: could not decode data: %v",
+			ErrInvalidBlinding, err)
+	}
+
+	if err := b.validateConstraints(routeData); err != nil {
+		return err
+	}
+
+	// The final hop receives its amount and expiry in the clear, and
+	// recognizes the payment by the path id it chose for itself, which
+	// holds the payment address of the invoice.
+	if isFinalHop {
+		if len(routeData.PathID) != 32 {
+			return fmt.Errorf("%w: invalid path id",
+				ErrInvalidBlinding)
+		}
+
+		var paymentAddr [32]byte
+		copy(paymentAddr[:], routeData.PathID)
+		payload.MPP = record.NewMPP(payload.totalAmtMsat, paymentAddr)
+
+		return nil
+	}
+
+	if routeData.RelayInfo == nil {
+		return fmt.Errorf("%w: relay info missing", ErrInvalidBlinding)
+	}
+
+	if routeData.ShortChannelID == nil {
+		return fmt.Errorf("%w: next channel missing",
+			ErrInvalidBlinding)
+	}
+
+	relayInfo := routeData.RelayInfo
+	if b.IncomingCltv < uint32(relayInfo.CltvExpiryDelta) {
+		return fmt.Errorf("%w: incoming expiry %v below expiry "+
+			"delta %v", ErrInvalidBlinding, b.IncomingCltv,
+			relayInfo.CltvExpiryDelta)
+	}
+
+	fwdAmt, err := calculateForwardingAmount(
+		b.IncomingAmount, lnwire.MilliSatoshi(relayInfo.BaseFee),
+		relayInfo.FeeRate,
+	)
+	if err != nil {
+		return err
+	}
+
+	// The next hop is sent the blinding point it needs to decrypt its
+	// own data, unless the recipient asked us to switch it out.
+	nextBlinding := routeData.NextBlindingOverride
+	if nextBlinding == nil {
+		nextBlinding, err = b.Processor.NextEphemeral(ephemeral)
+		if err != nil {
+			return fmt.Errorf("%w: could not derive next "+
+				"ephemeral: %v", ErrInvalidBlinding, err)
+		}
+	}
+
+	payload.FwdInfo = ForwardingInfo{
+		Network:         LitecoinNetwork,
+		NextHop:         *routeData.ShortChannelID,
+		AmountToForward: fwdAmt,
+		OutgoingCTLV: b.IncomingCltv - uint32(
+			relayInfo.CltvExpiryDelta,
+		),
+		NextBlinding: nextBlinding,
+	}
+
+	return nil
+}
+
+// validateConstraints checks the incoming HTLC against the constraints and
+// features that the recipient set in the route blinding data.
+func (b *BlindingKit) validateConstraints(
+	routeData *record.BlindedRouteData) error {
+
+	if constraints := routeData.Constraints; constraints != nil {
+		if b.IncomingCltv > constraints.MaxCltvExpiry {
+			return fmt.Errorf("%w: expiry %v exceeds maximum %v",
+				ErrInvalidBlinding, b.IncomingCltv,
+				constraints.MaxCltvExpiry)
+		}
+
+		if b.IncomingAmount < constraints.HtlcMinimumMsat {
+			return fmt.Errorf("%w: amount %v below minimum %v",
+				ErrInvalidBlinding, b.IncomingAmount,
+				constraints.HtlcMinimumMsat)
+		}
+	}
+
+	if routeData.Features != nil {
+		unknown := routeData.Features.UnknownRequiredFeatures()
+		if len(unknown) > 0 {
+			return fmt.Errorf("%w: unknown required features %v",
+				ErrInvalidBlinding, unknown)
+		}
+	}
+
+	return nil
+}
+
+// calculateForwardingAmount calculates the amount to forward for a blinded
+// hop based on the incoming amount and forwarding parameters, rounding up so
+// that the fee paid is never less than the policy requires.
+func calculateForwardingAmount(incomingAmount, baseFee lnwire.MilliSatoshi,
+	proportionalFee uint32) (lnwire.MilliSatoshi, error) {
+
+	// Sanity check to prevent overflow.
+	if incomingAmount < baseFee {
+		return 0, fmt.Errorf("%w: incoming amount %v less than base "+
+			"fee %v", ErrInvalidBlinding, incomingAmount, baseFee)
+	}
+
+	numerator := (uint64(incomingAmount) - uint64(baseFee)) * 1e6
+	denominator := 1e6 + uint64(proportionalFee)
+
+	ceiling := (numerator + denominator - 1) / denominator
+
+	return lnwire.MilliSatoshi(ceiling), nil
+}
+
 // OnionProcessor is responsible for keeping all sphinx dependent parts inside
 // and expose only decoding function. With such approach we give freedom for
 // subsystems which wants to decode sphinx path to not be dependable from
@@ -186,40 +424,74 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
 		}
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), lnwire.CodeNone
+	return makeSphinxHopIterator(onionPkt, sphinxPacket, BlindingKit{
+		Processor:    p.router,
+		IncomingCltv: incomingCltv,
+	}), lnwire.CodeNone
+}
+
+// ReconstructBlindingInfo contains the information required to reconstruct a
+// blinded onion.
+type ReconstructBlindingInfo struct {
+	// BlindingKey is the blinding point set in UpdateAddHTLC.
+	BlindingKey *btcec.PublicKey
+
+	// IncomingAmt is the amount for the incoming HTLC.
+	IncomingAmt lnwire.MilliSatoshi
+
+	// IncomingExpiry is the expiry height of the incoming HTLC.
+	IncomingExpiry uint32
 }
 
 // ReconstructHopIterator attempts to decode a valid sphinx packet from the passed io.Reader
 // instance using the rHash as the associated data when checking the relevant
 // MACs during the decoding process.
-func (p *OnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte) (
-	Iterator, error) {
+func (p *OnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte,
+	blindingInfo ReconstructBlindingInfo) (Iterator, error) {
 
 	onionPkt := &sphinx.OnionPacket{}
 	if err := onionPkt.Decode(r); err != nil {
 		return nil, err
 	}
 
+	// If the HTLC was received with a blinding point, it is used to
+	// derive the shared secret of the onion.
+	var opts []sphinx.ProcessOnionOpt
+	if blindingInfo.BlindingKey != nil {
+		opts = append(opts, sphinx.WithBlindingPoint(
+			blindingInfo.BlindingKey,
+		))
+	}
+
 	// Attempt to process the Sphinx packet. We include the payment hash of
 	// the HTLC as it's authenticated within the Sphinx packet itself as
 	// associated data in order to thwart attempts a replay attacks. In the
 	// case of a replay, an attacker is *forced* to use the same payment
 	// hash twice, thereby losing their money entirely.
-	sphinxPacket, err := p.router.ReconstructOnionPacket(onionPkt, rHash)
+	sphinxPacket, err := p.router.ReconstructOnionPacket(
+		onionPkt, rHash, opts...,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), nil
+	return makeSphinxHopIterator(onionPkt, sphinxPacket, BlindingKit{
+		Processor:         p.router,
+		UpdateAddBlinding: blindingInfo.BlindingKey,
+		IncomingAmount:    blindingInfo.IncomingAmt,
+		IncomingCltv:      blindingInfo.IncomingExpiry,
+	}), nil
 }
 
 // DecodeHopIteratorRequest encapsulates all date necessary to process an onion
 // packet, perform sphinx replay detection, and schedule the entry for garbage
 // collection.
 type DecodeHopIteratorRequest struct {
-	OnionReader  io.Reader
-	RHash        []byte
-	IncomingCltv uint32
+	OnionReader    io.Reader
+	RHash          []byte
+	IncomingCltv   uint32
+	IncomingAmount lnwire.MilliSatoshi
+	BlindingPoint  *btcec.PublicKey
 }
 
 // DecodeHopIteratorResponse encapsulates the outcome of a batched sphinx onion
@@ -275,8 +547,15 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 			return lnwire.CodeInvalidOnionKey
 		}
 
+		var opts []sphinx.ProcessOnionOpt
+		if req.BlindingPoint != nil {
+			opts = append(opts, sphinx.WithBlindingPoint(
+				req.BlindingPoint,
+			))
+		}
+
 		err = tx.ProcessOnionPacket(
-			seqNum, onionPkt, req.RHash, req.IncomingCltv,
+			seqNum, onionPkt, req.RHash, req.IncomingCltv, opts...,
 		)
 		switch err {
 		case nil:
@@ -376,7 +655,14 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 
 		// Finally, construct a hop iterator from our processed sphinx
 		// packet, simultaneously caching the original onion packet.
-		resp.HopIterator = makeSphinxHopIterator(&onionPkts[i], &packets[i])
+		resp.HopIterator = makeSphinxHopIterator(
+			&onionPkts[i], &packets[i], BlindingKit{
+				Processor:         p.router,
+				UpdateAddBlinding: reqs[i].BlindingPoint,
+				IncomingAmount:    reqs[i].IncomingAmount,
+				IncomingCltv:      reqs[i].IncomingCltv,
+			},
+		)
 	}
 
 	return resps, nil
@@ -3,6 +3,7 @@ package hop
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -10,6 +11,7 @@ import (
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/record"
 	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -65,14 +67,14 @@ func TestSphinxHopIteratorForwardingInstructions(t *testing.T) {
 			},
 			expectedFwdInfo: expectedFwdInfo,
 		},
-		// A TLV payload, we can leave off the action as we'll always
-		// read the cid encoded.
+		// A TLV payload that signals more hops.
 		{
 			sphinxPacket: &sphinx.ProcessedPacket{
 				Payload: sphinx.HopPayload{
 					Type:    sphinx.PayloadTLV,
 					Payload: b.Bytes(),
 				},
+				Action: sphinx.MoreHops,
 			},
 			expectedFwdInfo: expectedFwdInfo,
 		},
@@ -98,3 +100,206 @@ func TestSphinxHopIteratorForwardingInstructions(t *testing.T) {
 		}
 	}
 }
+
+// mockProcessor is a mocked blinding point processor that just returns the
+// data that it is called with when "decrypting".
+type mockProcessor struct {
+	decryptErr error
+}
+
+// DecryptBlindedHopData mocks blob decryption, returning the same data that
+// it was called with and an optionally configured error.
+func (m *mockProcessor) DecryptBlindedHopData(_ *btcec.PublicKey,
+	data []byte) ([]byte, error) {
+
+	return data, m.decryptErr
+}
+
+// NextEphemeral mocks getting our next ephemeral key.
+func (m *mockProcessor) NextEphemeral(key *btcec.PublicKey) (*btcec.PublicKey,
+	error) {
+
+	return key, nil
+}
+
+// TestDecryptAndValidateFwdInfo tests deriving forwarding information from
+// the route blinding data of a hop.
+func TestDecryptAndValidateFwdInfo(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	blinding := privKey.PubKey()
+
+	scid := lnwire.NewShortChanIDFromInt(1234)
+	forwardData := func(constraints *record.PaymentConstraints) []byte {
+		data, err := record.EncodeBlindedRouteData(
+			&record.BlindedRouteData{
+				ShortChannelID: &scid,
+				RelayInfo: &record.PaymentRelayInfo{
+					CltvExpiryDelta: 40,
+					FeeRate:         1000,
+					BaseFee:         100,
+				},
+				Constraints: constraints,
+			},
+		)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	pathID := [32]byte{1, 2, 3}
+	finalData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			PathID: pathID[:],
+		},
+	)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		kit          BlindingKit
+		payload      *Payload
+		isFinal      bool
+		expErr       error
+		expFwdInfo   *ForwardingInfo
+		expPaymentID *[32]byte
+	}{{
+		name: "no blinding point",
+		kit: BlindingKit{
+			Processor: &mockProcessor{},
+		},
+		payload: &Payload{
+			encryptedData: forwardData(nil),
+		},
+		expErr: ErrInvalidBlinding,
+	}, {
+		name: "both blinding points",
+		kit: BlindingKit{
+			Processor:         &mockProcessor{},
+			UpdateAddBlinding: blinding,
+		},
+		payload: &Payload{
+			encryptedData: forwardData(nil),
+			blindingPoint: blinding,
+		},
+		expErr: ErrInvalidBlinding,
+	}, {
+		name: "decryption failure",
+		kit: BlindingKit{
+			Processor: &mockProcessor{
+				decryptErr: errors.New("bad data"),
+			},
+			UpdateAddBlinding: blinding,
+		},
+		payload: &Payload{
+			encryptedData: forwardData(nil),
+		},
+		expErr: ErrInvalidBlinding,
+	}, {
+		name: "expiry above maximum",
+		kit: BlindingKit{
+			Processor:         &mockProcessor{},
+			UpdateAddBlinding: blinding,
+			IncomingCltv:      1000,
+			IncomingAmount:    10_000,
+		},
+		payload: &Payload{
+			encryptedData: forwardData(
+				&record.PaymentConstraints{
+					MaxCltvExpiry: 999,
+				},
+			),
+		},
+		expErr: ErrInvalidBlinding,
+	}, {
+		name: "introduction node forward",
+		kit: BlindingKit{
+			Processor:      &mockProcessor{},
+			IncomingCltv:   1000,
+			IncomingAmount: 10_100,
+		},
+		payload: &Payload{
+			encryptedData: forwardData(nil),
+			blindingPoint: blinding,
+		},
+		expFwdInfo: &ForwardingInfo{
+			Network:         LitecoinNetwork,
+			NextHop:         scid,
+			AmountToForward: 9_991,
+			OutgoingCTLV:    960,
+			NextBlinding:    blinding,
+		},
+	}, {
+		name: "final hop",
+		kit: BlindingKit{
+			Processor:         &mockProcessor{},
+			UpdateAddBlinding: blinding,
+		},
+		payload: &Payload{
+			encryptedData: finalData,
+			totalAmtMsat:  5000,
+		},
+		isFinal:      true,
+		expPaymentID: &pathID,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.kit.DecryptAndValidateFwdInfo(
+				tc.payload, tc.isFinal,
+			)
+			require.ErrorIs(t, err, tc.expErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if tc.expFwdInfo != nil {
+				require.Equal(
+					t, *tc.expFwdInfo, tc.payload.FwdInfo,
+				)
+			}
+
+			if tc.expPaymentID != nil {
+				require.NotNil(t, tc.payload.MPP)
+				require.Equal(
+					t, *tc.expPaymentID,
+					tc.payload.MPP.PaymentAddr(),
+				)
+				require.EqualValues(
+					t, 5000, tc.payload.MPP.TotalMsat(),
+				)
+			}
+		})
+	}
+}
+
+// TestCalculateForwardingAmount tests that the amount to forward of a blinded
+// hop is rounded up, so that the fee never exceeds the hop's policy.
+func TestCalculateForwardingAmount(t *testing.T) {
+	t.Parallel()
+
+	// A zero fee policy forwards the full amount.
+	amt, err := calculateForwardingAmount(1000, 0, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, amt)
+
+	// The fee is deducted from the incoming amount.
+	amt, err = calculateForwardingAmount(10_100, 100, 1000)
+	require.NoError(t, err)
+	require.EqualValues(t, 9_991, amt)
+
+	// Rounding up keeps the fee paid at or below the policy's fee.
+	amt, err = calculateForwardingAmount(1001, 0, 1000)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, amt)
+
+	// An incoming amount below the base fee can't be forwarded.
+	_, err = calculateForwardingAmount(50, 100, 0)
+	require.ErrorIs(t, err, ErrInvalidBlinding)
+}
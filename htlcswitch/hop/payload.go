@@ -9,6 +9,7 @@ import (
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/record"
 	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
 )
 
 // PayloadViolation is an enum encapsulating the possible invalid payload
@@ -97,6 +98,18 @@ type Payload struct {
 	// metadata is additional data that is sent along with the payment to
 	// the payee.
 	metadata []byte
+
+	// encryptedData is the encrypted data that the recipient of a blinded
+	// payment left for this hop.
+	encryptedData []byte
+
+	// blindingPoint is the ephemeral key that the introduction node of a
+	// blinded route uses to decrypt its encrypted data.
+	blindingPoint *btcec.PublicKey
+
+	// totalAmtMsat is the total amount of a blinded payment, which is
+	// only included in the payload for the final hop.
+	totalAmtMsat lnwire.MilliSatoshi
 }
 
 // NewLegacyPayload builds a Payload from the amount, cltv, and next hop
@@ -116,15 +129,20 @@ func NewLegacyPayload(f *sphinx.HopData) *Payload {
 }
 
 // NewPayloadFromReader builds a new Hop from the passed io.Reader. The reader
-// should correspond to the bytes encapsulated in a TLV onion payload.
-func NewPayloadFromReader(r io.Reader) (*Payload, error) {
+// should correspond to the bytes encapsulated in a TLV onion payload. The
+// finalHop boolean indicates whether the onion marked this hop as the exit
+// hop of the route.
+func NewPayloadFromReader(r io.Reader, finalHop bool) (*Payload, error) {
 	var (
-		cid      uint64
-		amt      uint64
-		cltv     uint32
-		mpp      = &record.MPP{}
-		amp      = &record.AMP{}
-		metadata []byte
+		cid           uint64
+		amt           uint64
+		cltv          uint32
+		mpp           = &record.MPP{}
+		amp           = &record.AMP{}
+		metadata      []byte
+		encryptedData []byte
+		blindingPoint *btcec.PublicKey
+		totalAmtMsat  uint64
 	)
 
 	tlvStream, err := tlv.NewStream(
@@ -132,8 +150,11 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		record.NewLockTimeRecord(&cltv),
 		record.NewNextHopIDRecord(&cid),
 		mpp.Record(),
+		record.NewEncryptedDataRecord(&encryptedData),
+		record.NewBlindingPointRecord(&blindingPoint),
 		amp.Record(),
 		record.NewMetadataRecord(&metadata),
+		record.NewTotalAmtMsatBlinded(&totalAmtMsat),
 	)
 	if err != nil {
 		return nil, err
@@ -148,8 +169,7 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 
 	// Validate whether the sender properly included or omitted tlv records
 	// in accordance with BOLT 04.
-	nextHop := lnwire.NewShortChanIDFromInt(cid)
-	err = ValidateParsedPayloadTypes(parsedTypes, nextHop)
+	err = ValidateParsedPayloadTypes(parsedTypes, finalHop)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +180,7 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		return nil, ErrInvalidPayload{
 			Type:      *violatingType,
 			Violation: RequiredViolation,
-			FinalHop:  nextHop == Exit,
+			FinalHop:  finalHop,
 		}
 	}
 
@@ -182,9 +202,23 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		metadata = nil
 	}
 
+	// If no encrypted data was parsed, set the field on the resulting
+	// payload to nil.
+	if _, ok := parsedTypes[record.EncryptedDataOnionType]; !ok {
+		encryptedData = nil
+	}
+
 	// Filter out the custom records.
 	customRecords := NewCustomRecords(parsedTypes)
 
+	// The exit hop is identified by the onion itself, which for regular
+	// payloads matches the omitted next hop id. Hops inside a blinded
+	// route find their next hop in their encrypted data instead.
+	nextHop := lnwire.NewShortChanIDFromInt(cid)
+	if finalHop {
+		nextHop = Exit
+	}
+
 	return &Payload{
 		FwdInfo: ForwardingInfo{
 			Network:         LitecoinNetwork,
@@ -195,6 +229,9 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		MPP:           mpp,
 		AMP:           amp,
 		metadata:      metadata,
+		encryptedData: encryptedData,
+		blindingPoint: blindingPoint,
+		totalAmtMsat:  lnwire.MilliSatoshi(totalAmtMsat),
 		customRecords: customRecords,
 	}, nil
 }
@@ -223,15 +260,22 @@ func NewCustomRecords(parsedTypes tlv.TypeMap) record.CustomSet {
 // boolean should be true if the payload was parsed for an exit hop. The
 // requirements for this method are described in BOLT 04.
 func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
-	nextHop lnwire.ShortChannelID) error {
-
-	isFinalHop := nextHop == Exit
+	isFinalHop bool) error {
 
 	_, hasAmt := parsedTypes[record.AmtOnionType]
 	_, hasLockTime := parsedTypes[record.LockTimeOnionType]
 	_, hasNextHop := parsedTypes[record.NextHopOnionType]
 	_, hasMPP := parsedTypes[record.MPPOnionType]
 	_, hasAMP := parsedTypes[record.AMPOnionType]
+	_, hasEncryptedData := parsedTypes[record.EncryptedDataOnionType]
+	_, hasBlindingPoint := parsedTypes[record.BlindingPointOnionType]
+	_, hasTotalAmt := parsedTypes[record.TotalAmtMsatBlindedType]
+
+	// Hops inside a blinded route follow a different set of rules, as
+	// most of their forwarding information is provided by the recipient.
+	if hasEncryptedData || hasBlindingPoint {
+		return validateBlindedPayloadTypes(parsedTypes, isFinalHop)
+	}
 
 	switch {
 
@@ -251,9 +295,7 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 			FinalHop:  isFinalHop,
 		}
 
-	// The exit hop should omit the next hop id. If nextHop != Exit, the
-	// sender must have included a record, so we don't need to test for its
-	// inclusion at intermediate hops directly.
+	// The exit hop should omit the next hop id.
 	case isFinalHop && hasNextHop:
 		return ErrInvalidPayload{
 			Type:      record.NextHopOnionType,
@@ -261,6 +303,14 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 			FinalHop:  true,
 		}
 
+	// Intermediate hops must include the next hop id.
+	case !isFinalHop && !hasNextHop:
+		return ErrInvalidPayload{
+			Type:      record.NextHopOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  false,
+		}
+
 	// Intermediate nodes should never receive MPP fields.
 	case !isFinalHop && hasMPP:
 		return ErrInvalidPayload{
@@ -276,6 +326,72 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 			Violation: IncludedViolation,
 			FinalHop:  isFinalHop,
 		}
+
+	// The total amount is only used for blinded payments.
+	case hasTotalAmt:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	return nil
+}
+
+// validateBlindedPayloadTypes checks the types parsed from the payload of a
+// hop inside a blinded route. Intermediate hops only receive encrypted data,
+// since their forwarding information is derived from it, while the final hop
+// also receives the amount, expiry and total amount of the payment.
+func validateBlindedPayloadTypes(parsedTypes tlv.TypeMap,
+	isFinalHop bool) error {
+
+	_, hasEncryptedData := parsedTypes[record.EncryptedDataOnionType]
+	if !hasEncryptedData {
+		return ErrInvalidPayload{
+			Type:      record.EncryptedDataOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	// The final hop must be told what it is being paid.
+	required := []tlv.Type{
+		record.AmtOnionType,
+		record.LockTimeOnionType,
+		record.TotalAmtMsatBlindedType,
+	}
+	if isFinalHop {
+		for _, typ := range required {
+			if _, ok := parsedTypes[typ]; !ok {
+				return ErrInvalidPayload{
+					Type:      typ,
+					Violation: OmittedViolation,
+					FinalHop:  true,
+				}
+			}
+		}
+	}
+
+	// No hop in a blinded route may receive any of the regular
+	// forwarding fields, except for the amount and expiry of the final
+	// hop.
+	disallowed := []tlv.Type{
+		record.NextHopOnionType,
+		record.MPPOnionType,
+		record.AMPOnionType,
+	}
+	if !isFinalHop {
+		disallowed = append(disallowed, required...)
+	}
+	for _, typ := range disallowed {
+		if _, ok := parsedTypes[typ]; ok {
+			return ErrInvalidPayload{
+				Type:      typ,
+				Violation: IncludedViolation,
+				FinalHop:  isFinalHop,
+			}
+		}
 	}
 
 	return nil
@@ -305,6 +421,25 @@ func (h *Payload) Metadata() []byte {
 	return h.metadata
 }
 
+// EncryptedData returns the route blinding data that the recipient of a
+// blinded payment left for this hop, or nil if the hop isn't part of a blinded
+// route.
+func (h *Payload) EncryptedData() []byte {
+	return h.encryptedData
+}
+
+// BlindingPoint returns the blinding point that the sender included for the
+// introduction node of a blinded route.
+func (h *Payload) BlindingPoint() *btcec.PublicKey {
+	return h.blindingPoint
+}
+
+// TotalAmtMsat returns the total amount of a blinded payment, which is only
+// set for the final hop of a blinded route.
+func (h *Payload) TotalAmtMsat() lnwire.MilliSatoshi {
+	return h.totalAmtMsat
+}
+
 // getMinRequiredViolation checks for unrecognized required (even) fields in the
 // standard range and returns the lowest required type. Always returning the
 // lowest required type allows a failure message to be deterministic.
@@ -16,6 +16,7 @@ const testUnknownRequiredType = 0x80
 type decodePayloadTest struct {
 	name               string
 	payload            []byte
+	isFinalHop         bool
 	expErr             error
 	expCustomRecords   map[uint64][]byte
 	shouldHaveMPP      bool
@@ -25,8 +26,9 @@ type decodePayloadTest struct {
 
 var decodePayloadTests = []decodePayloadTest{
 	{
-		name:    "final hop valid",
-		payload: []byte{0x02, 0x00, 0x04, 0x00},
+		name:       "final hop valid",
+		isFinalHop: true,
+		payload:    []byte{0x02, 0x00, 0x04, 0x00},
 	},
 	{
 		name: "intermediate hop valid",
@@ -35,8 +37,9 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name:    "final hop no amount",
-		payload: []byte{0x04, 0x00},
+		name:       "final hop no amount",
+		isFinalHop: true,
+		payload:    []byte{0x04, 0x00},
 		expErr: hop.ErrInvalidPayload{
 			Type:      record.AmtOnionType,
 			Violation: hop.OmittedViolation,
@@ -55,8 +58,9 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name:    "final hop no expiry",
-		payload: []byte{0x02, 0x00},
+		name:       "final hop no expiry",
+		isFinalHop: true,
+		payload:    []byte{0x02, 0x00},
 		expErr: hop.ErrInvalidPayload{
 			Type:      record.LockTimeOnionType,
 			Violation: hop.OmittedViolation,
@@ -75,7 +79,8 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name: "final hop next sid present",
+		name:       "final hop next sid present",
+		isFinalHop: true,
 		payload: []byte{0x02, 0x00, 0x04, 0x00, 0x06, 0x08, 0x00, 0x00,
 			0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		},
@@ -86,7 +91,8 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name: "required type after omitted hop id",
+		name:       "required type after omitted hop id",
+		isFinalHop: true,
 		payload: []byte{
 			0x02, 0x00, 0x04, 0x00,
 			testUnknownRequiredType, 0x00,
@@ -111,8 +117,9 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name:    "required type zero final hop",
-		payload: []byte{0x00, 0x00, 0x02, 0x00, 0x04, 0x00},
+		name:       "required type zero final hop",
+		isFinalHop: true,
+		payload:    []byte{0x00, 0x00, 0x02, 0x00, 0x04, 0x00},
 		expErr: hop.ErrInvalidPayload{
 			Type:      0,
 			Violation: hop.RequiredViolation,
@@ -120,7 +127,8 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name: "required type zero final hop zero sid",
+		name:       "required type zero final hop zero sid",
+		isFinalHop: true,
 		payload: []byte{0x00, 0x00, 0x02, 0x00, 0x04, 0x00, 0x06, 0x08,
 			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		},
@@ -142,7 +150,8 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name: "required type in custom range",
+		name:       "required type in custom range",
+		isFinalHop: true,
 		payload: []byte{0x02, 0x00, 0x04, 0x00,
 			0xfe, 0x00, 0x01, 0x00, 0x00, 0x02, 0x10, 0x11,
 		},
@@ -158,9 +167,10 @@ var decodePayloadTests = []decodePayloadTest{
 		expErr: nil,
 	},
 	{
-		name:    "valid final hop",
-		payload: []byte{0x02, 0x00, 0x04, 0x00},
-		expErr:  nil,
+		name:       "valid final hop",
+		isFinalHop: true,
+		payload:    []byte{0x02, 0x00, 0x04, 0x00},
+		expErr:     nil,
 	},
 	{
 		name: "intermediate hop with mpp",
@@ -218,7 +228,8 @@ var decodePayloadTests = []decodePayloadTest{
 		},
 	},
 	{
-		name: "final hop with mpp",
+		name:       "final hop with mpp",
+		isFinalHop: true,
 		payload: []byte{
 			// amount
 			0x02, 0x00,
@@ -236,7 +247,8 @@ var decodePayloadTests = []decodePayloadTest{
 		shouldHaveMPP: true,
 	},
 	{
-		name: "final hop with amp",
+		name:       "final hop with amp",
+		isFinalHop: true,
 		payload: []byte{
 			// amount
 			0x02, 0x00,
@@ -260,7 +272,8 @@ var decodePayloadTests = []decodePayloadTest{
 		shouldHaveAMP: true,
 	},
 	{
-		name: "final hop with metadata",
+		name:       "final hop with metadata",
+		isFinalHop: true,
 		payload: []byte{
 			// amount
 			0x02, 0x00,
@@ -310,7 +323,9 @@ func testDecodeHopPayloadValidation(t *testing.T, test decodePayloadTest) {
 		testChildIndex = uint32(9)
 	)
 
-	p, err := hop.NewPayloadFromReader(bytes.NewReader(test.payload))
+	p, err := hop.NewPayloadFromReader(
+		bytes.NewReader(test.payload), test.isFinalHop,
+	)
 	if !reflect.DeepEqual(test.expErr, err) {
 		t.Fatalf("expected error mismatch, want: %v, got: %v",
 			test.expErr, err)
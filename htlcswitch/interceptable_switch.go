@@ -22,6 +22,12 @@ var (
 	// code is attempted.
 	ErrUnsupportedFailureCode = errors.New("unsupported failure code")
 
+	// ErrFwdModificationRejected is returned when an interceptor tries to
+	// resume a forward with modifications that can't be applied. The
+	// forward stays held in that case.
+	ErrFwdModificationRejected = errors.New("forward modification " +
+		"rejected")
+
 	errBlockStreamStopped = errors.New("block epoch stream stopped")
 )
 
@@ -30,6 +36,8 @@ var (
 // intercepts forward requests. A reference to the Switch is held in order
 // to communicate back the interception result where the options are:
 // Resume - forwards the original request to the switch as is.
+// ResumeModified - forwards the request to the switch with a modified amount,
+// outgoing channel or custom records.
 // Settle - routes UpdateFulfillHTLC to the originating link.
 // Fail - routes UpdateFailHTLC to the originating link.
 type InterceptableSwitch struct {
@@ -105,6 +113,10 @@ const (
 
 	// FwdActionFail fails the intercepted packet back to the sender.
 	FwdActionFail
+
+	// FwdActionResumeModified forwards the intercepted packet to the
+	// switch with the modifications of the resolution applied.
+	FwdActionResumeModified
 )
 
 // FwdResolution defines the action to be taken on an intercepted packet.
@@ -126,6 +138,10 @@ type FwdResolution struct {
 	// FailureCode is the failure code that is to be passed back to the
 	// sender if action is FwdActionFail.
 	FailureCode lnwire.FailCode
+
+	// Modifications are the changes to apply to the outgoing htlc if
+	// action is FwdActionResumeModified.
+	Modifications FwdModifications
 }
 
 type fwdResolution struct {
@@ -373,6 +389,40 @@ func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
 	case FwdActionResume:
 		return intercepted.Resume()
 
+	case FwdActionResumeModified:
+		err := intercepted.ResumeModified(res.Modifications)
+		if err == nil {
+			return nil
+		}
+
+		// The modified forward was rejected, so we keep holding it and
+		// offer it to the interceptor again along with the reason. This
+		// gives the interceptor the chance to resolve it in a different
+		// way.
+		log.Debugf("Modification of forward %v rejected: %v", res.Key,
+			err)
+
+		err = fmt.Errorf("%w: %v", ErrFwdModificationRejected, err)
+		pushErr := s.heldHtlcSet.push(res.Key, intercepted)
+		if pushErr != nil {
+			log.Errorf("Unable to hold forward %v: %v", res.Key,
+				pushErr)
+
+			return err
+		}
+
+		if s.interceptor != nil {
+			packet := intercepted.Packet()
+			packet.ModificationErr = err
+
+			if err := s.interceptor(packet); err != nil {
+				log.Debugf("Interceptor cannot handle "+
+					"forward: %v", err)
+			}
+		}
+
+		return err
+
 	case FwdActionSettle:
 		return intercepted.Settle(res.Preimage)
 
@@ -615,6 +665,53 @@ func (f *interceptedForward) Resume() error {
 	return f.htlcSwitch.ForwardPackets(nil, f.packet)
 }
 
+// ResumeModified resumes the default behavior with the given modifications
+// applied to the outgoing htlc. The modified htlc must leave through a channel
+// with the same peer and satisfy the policy of that channel, otherwise an
+// error is returned and nothing is forwarded.
+func (f *interceptedForward) ResumeModified(mods FwdModifications) error {
+	if err := mods.CustomRecords.Validate(); err != nil {
+		return err
+	}
+
+	// Apply the modifications to copies of the packet and the htlc, so
+	// that the held forward is left untouched if it can't be resumed.
+	htlc := *f.htlc
+	packet := *f.packet
+	packet.htlc = &htlc
+
+	mods.OutgoingAmount.WhenSome(func(amt lnwire.MilliSatoshi) {
+		htlc.Amount = amt
+		packet.amount = amt
+	})
+	mods.OutgoingChanID.WhenSome(func(chanID lnwire.ShortChannelID) {
+		packet.outgoingChanID = chanID
+		packet.strictOutgoingChan = true
+	})
+
+	// Custom records are merged into the existing set, replacing any
+	// records of the same type.
+	if len(mods.CustomRecords) > 0 {
+		customRecords := htlc.CustomRecords.Copy()
+		if customRecords == nil {
+			customRecords = make(lnwire.CustomRecords)
+		}
+		for typ, value := range mods.CustomRecords {
+			customRecords[typ] = value
+		}
+		htlc.CustomRecords = customRecords
+	}
+
+	err := f.htlcSwitch.checkModifiedForward(f.packet, &packet)
+	if err != nil {
+		return err
+	}
+
+	// Forward to the switch. A link quit channel isn't needed, because we
+	// are on a different thread now.
+	return f.htlcSwitch.ForwardPackets(nil, &packet)
+}
+
 // Fail notifies the intention to Fail an existing hold forward with an
 // encrypted failure reason.
 func (f *interceptedForward) Fail(reason []byte) error {
@@ -3,9 +3,11 @@ package htlcswitch
 import (
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/channeldb/models"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/invoices"
 	"github.com/ltcsuite/lnd/lnpeer"
 	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/record"
@@ -136,6 +138,11 @@ type ChannelUpdateHandler interface {
 	// clean. This can be used with dynamic commitment negotiation or coop
 	// close negotiation which require a clean channel state.
 	ShutdownIfChannelClean() error
+
+	// UpgradeCommitType quiesces the channel and upgrades its commitment
+	// type in place. The returned channel receives nil once the upgrade is
+	// complete, or an error if it failed.
+	UpgradeCommitType(lnwallet.CommitmentType) <-chan error
 }
 
 // ChannelLink is an interface which represents the subsystem for managing the
@@ -199,7 +206,7 @@ type ChannelLink interface {
 	// in order to signal to the source of the HTLC, the policy consistency
 	// issue.
 	CheckHtlcForward(payHash [32]byte, incomingAmt lnwire.MilliSatoshi,
-		amtToForward lnwire.MilliSatoshi,
+		amtToForward lnwire.MilliSatoshi, inboundFee models.InboundFee,
 		incomingTimeout, outgoingTimeout uint32,
 		heightNow uint32, scid lnwire.ShortChannelID) *LinkError
 
@@ -312,13 +319,37 @@ type InterceptedPacket struct {
 	// AutoFailHeight is the block height at which this intercept will be
 	// failed back automatically.
 	AutoFailHeight int32
+
+	// ModificationErr is set if the packet is offered to the interceptor
+	// again because the modifications it requested were rejected. The
+	// htlc is still held and needs to be resolved once more.
+	ModificationErr error
+}
+
+// FwdModifications describes the changes that an interceptor can make to an
+// intercepted htlc when resuming it.
+type FwdModifications struct {
+	// OutgoingAmount is the amount to forward instead of the amount that
+	// was requested in the onion. The amount must still satisfy the
+	// forwarding policy of the outgoing channel.
+	OutgoingAmount fn.Option[lnwire.MilliSatoshi]
+
+	// OutgoingChanID is the channel to forward over instead of the one
+	// that was requested in the onion. It must be a channel with the same
+	// peer.
+	OutgoingChanID fn.Option[lnwire.ShortChannelID]
+
+	// CustomRecords are custom TLV records to attach to the outgoing
+	// UpdateAddHTLC message. Records of the same type that are already
+	// present are replaced.
+	CustomRecords lnwire.CustomRecords
 }
 
 // InterceptedForward is passed to the ForwardInterceptor for every forwarded
 // htlc. It contains all the information about the packet which accordingly
 // the interceptor decides if to hold or not.
 // In addition this interface allows a later resolution by calling either
-// Resume, Settle or Fail.
+// Resume, ResumeModified, Settle or Fail.
 type InterceptedForward interface {
 	// Packet returns the intercepted packet.
 	Packet() InterceptedPacket
@@ -328,6 +359,10 @@ type InterceptedForward interface {
 	// this htlc which usually means forward it.
 	Resume() error
 
+	// ResumeModified notifies the intention to resume an existing hold
+	// forward with the given modifications applied to the outgoing htlc.
+	ResumeModified(mods FwdModifications) error
+
 	// Settle notifies the intention to settle an existing hold
 	// forward with a given preimage.
 	Settle(lntypes.Preimage) error
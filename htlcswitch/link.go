@@ -2,8 +2,10 @@ package htlcswitch
 
 import (
 	"bytes"
+	"context"
 	crand "crypto/rand"
 	"crypto/sha256"
+	goErrors "errors"
 	"fmt"
 	prand "math/rand"
 	"sync"
@@ -29,6 +31,9 @@ import (
 	"github.com/ltcsuite/lnd/ticker"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/wire"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func init() {
@@ -229,6 +234,12 @@ type ChannelLinkConfig struct {
 	// configured set of watchtowers for legacy channels.
 	TowerClient TowerClient
 
+	// GetTowerClient returns the tower client that backs up the states of
+	// channels of the given type. It is used to switch to the tower client
+	// of the new type once the commitment type of the channel has been
+	// upgraded.
+	GetTowerClient func(channeldb.ChannelType) TowerClient
+
 	// MaxOutgoingCltvExpiry is the maximum outgoing timelock that the link
 	// should accept for a forwarded HTLC. The value is relative to the
 	// current block height.
@@ -344,6 +355,15 @@ type channelLink struct {
 	// service shutdown requests from ShutdownIfChannelClean calls.
 	shutdownRequest chan *shutdownReq
 
+	// upgradeRequest is a channel that the channelLink will listen on to
+	// service requests to upgrade the commitment type of the channel.
+	upgradeRequest chan *upgradeReq
+
+	// quiescer tracks the state of the quiescence protocol and the
+	// dynamic commitment negotiation. It must only be accessed from the
+	// htlcManager goroutine.
+	quiescer quiescer
+
 	// updateFeeTimer is the timer responsible for updating the link's
 	// commitment fee every time it fires.
 	updateFeeTimer *time.Timer
@@ -389,6 +409,7 @@ func NewChannelLink(cfg ChannelLinkConfig,
 		channel:         channel,
 		shortChanID:     channel.ShortChanID(),
 		shutdownRequest: make(chan *shutdownReq),
+		upgradeRequest:  make(chan *upgradeReq),
 		hodlMap:         make(map[models.CircuitKey]hodlHtlc),
 		hodlQueue:       queue.NewConcurrentQueue(10),
 		log:             build.NewPrefixLog(logPrefix, log),
@@ -768,6 +789,21 @@ func (l *channelLink) syncChanStates() error {
 			return err
 		}
 
+		// The remote peer may not have received our ack of an upgrade
+		// of the commitment type, which it must process before our
+		// commitment of the new type.
+		if l.shouldResendDynAck(remoteChanSyncMsg) {
+			l.log.Infof("resending dynamic commitment ack")
+
+			ack := &lnwire.DynAck{
+				ChanID: l.ChanID(),
+			}
+			msgsToReSend = append(
+				[]lnwire.Message{ack}, msgsToReSend...,
+			)
+			l.quiescer.ackResent = true
+		}
+
 		if len(msgsToReSend) > 0 {
 			l.log.Infof("sending %v updates to synchronize the "+
 				"state", len(msgsToReSend))
@@ -1109,7 +1145,28 @@ func (l *channelLink) htlcManager() {
 		go l.fwdPkgGarbager()
 	}
 
+	// If we went down while upgrading the commitment type of the channel
+	// or negotiating the upgrade, we'll pick up where we left off before
+	// sending any new updates.
+	if !l.restoreQuiescence() {
+		return
+	}
+
+	// Any pending upgrade request can't be completed once we exit.
+	defer func() {
+		if l.quiescer.upgrade != nil {
+			l.quiescer.upgrade.err <- ErrLinkShuttingDown
+		}
+	}()
+
 	for {
+		// Drive the quiescence protocol forward, as the previous event
+		// may have committed our last pending update or completed an
+		// upgrade of the commitment type.
+		if !l.failed {
+			l.updateQuiescence()
+		}
+
 		// We must always check if we failed at some point processing
 		// the last update before processing the next.
 		if l.failed {
@@ -1132,6 +1189,17 @@ func (l *channelLink) htlcManager() {
 				"PendingLocalUpdateCount")
 		}
 
+		// While the channel is quiescing or being upgraded, we may not
+		// send any new updates. We'll stop reading packets from the
+		// switch and htlc resolutions, which stay queued until the
+		// channel resumes.
+		downstream := l.downstream
+		hodlQueue := l.hodlQueue.ChanOut()
+		if l.quiescer.active() {
+			downstream = nil
+			hodlQueue = nil
+		}
+
 		select {
 		// Our update fee timer has fired, so we'll check the network
 		// fee to see if we should adjust our commitment fee.
@@ -1144,6 +1212,12 @@ func (l *channelLink) htlcManager() {
 				continue
 			}
 
+			// We can't update the fee while the channel is
+			// quiescent, so we'll wait for the next timeout.
+			if l.quiescer.active() {
+				continue
+			}
+
 			// If we are the initiator, then we'll sample the
 			// current fee rate to get into the chain within 3
 			// blocks.
@@ -1221,7 +1295,7 @@ func (l *channelLink) htlcManager() {
 		// A message from the switch was just received. This indicates
 		// that the link is an intermediate hop in a multi-hop HTLC
 		// circuit.
-		case pkt := <-l.downstream:
+		case pkt := <-downstream:
 			l.handleDownstreamPkt(pkt)
 
 		// A message from the connected peer was just received. This
@@ -1232,7 +1306,7 @@ func (l *channelLink) htlcManager() {
 
 		// A htlc resolution is received. This means that we now have a
 		// resolution for a previously accepted htlc.
-		case hodlItem := <-l.hodlQueue.ChanOut():
+		case hodlItem := <-hodlQueue:
 			htlcResolution := hodlItem.(invoices.HtlcResolution)
 			err := l.processHodlQueue(htlcResolution)
 			switch err {
@@ -1258,12 +1332,16 @@ func (l *channelLink) htlcManager() {
 				)
 			}
 
+		case req := <-l.upgradeRequest:
+			l.handleUpgradeRequest(req)
+
 		case req := <-l.shutdownRequest:
 			// If the channel is clean, we send nil on the err chan
 			// and return to prevent the htlcManager goroutine from
 			// processing any more updates. The full link shutdown
-			// will be triggered by RemoveLink in the peer.
-			if l.channel.IsChannelClean() {
+			// will be triggered by RemoveLink in the peer. A
+			// channel that is being upgraded can't be shut down.
+			if !l.quiescer.active() && l.channel.IsChannelClean() {
 				req.err <- nil
 				return
 			}
@@ -1407,6 +1485,24 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 		return errors.New("not an UpdateAddHTLC packet")
 	}
 
+	// If the htlc belongs to a traced local payment, we'll record the
+	// processing of the add as part of the payment's trace.
+	span := trace.SpanFromContext(context.Background())
+	if pkt.spanContext.IsValid() {
+		ctx := trace.ContextWithSpanContext(
+			context.Background(), pkt.spanContext,
+		)
+		_, span = tracer.Start(
+			ctx, "htlcswitch.channelLink.handleDownstreamUpdateAdd",
+			trace.WithAttributes(
+				attribute.String(
+					"chan_id", l.ShortChanID().String(),
+				),
+			),
+		)
+	}
+	defer span.End()
+
 	// If hodl.AddOutgoing mode is active, we exit early to simulate
 	// arbitrary delays between the switch adding an ADD to the
 	// mailbox, and the HTLC being added to the commitment state.
@@ -1439,12 +1535,17 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 		// unacknowledged.
 		l.mailBox.FailAdd(pkt)
 
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
 		return NewDetailedLinkError(
 			lnwire.NewTemporaryChannelFailure(nil),
 			OutgoingFailureDownstreamHtlcAdd,
 		)
 	}
 
+	span.SetAttributes(attribute.Int64("htlc_index", int64(index)))
+
 	l.log.Tracef("received downstream htlc: payment_hash=%x, "+
 		"local_log_index=%v, pend_updates=%v",
 		htlc.PaymentHash[:], index,
@@ -1571,15 +1672,26 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 		}
 
 		// An HTLC cancellation has been triggered somewhere upstream,
-		// we'll remove then HTLC from our local state machine.
-		inKey := pkt.inKey()
-		err := l.channel.FailHTLC(
-			pkt.incomingHTLCID,
-			htlc.Reason,
-			pkt.sourceRef,
-			pkt.destRef,
-			&inKey,
+		// we'll remove then HTLC from our local state machine. If the
+		// HTLC is part of a blinded route, its failure is converted so
+		// that it doesn't reveal where in the route it happened.
+		var (
+			inKey   = pkt.inKey()
+			failMsg lnwire.Message
+			err     error
 		)
+		if pkt.obfuscator != nil && pkt.obfuscator.Type().IsBlinded() {
+			failMsg, err = l.failBlindedHTLC(pkt, htlc)
+		} else {
+			failMsg = htlc
+			err = l.channel.FailHTLC(
+				pkt.incomingHTLCID,
+				htlc.Reason,
+				pkt.sourceRef,
+				pkt.destRef,
+				&inKey,
+			)
+		}
 		if err != nil {
 			l.log.Errorf("unable to cancel incoming HTLC for "+
 				"circuit-key=%v: %v", inKey, err)
@@ -1614,7 +1726,7 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 
 		// We send the HTLC message to the peer which initially created
 		// the HTLC.
-		l.cfg.Peer.SendMessage(false, htlc)
+		l.cfg.Peer.SendMessage(false, failMsg)
 
 		// If the packet does not have a link failure set, it failed
 		// further down the route so we notify a forwarding failure.
@@ -1639,6 +1751,54 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 	}
 }
 
+// failBlindedHTLC removes an incoming HTLC that is part of a blinded route
+// from our local state machine, replacing the failure it's failed with so
+// that the route can't be probed. As the introduction node, we return an
+// invalid blinding failure to the sender within the given fail message. Inside
+// the route, we fail the HTLC as malformed instead. The message that must be
+// sent to the peer is returned.
+func (l *channelLink) failBlindedHTLC(pkt *htlcPacket,
+	htlc *lnwire.UpdateFailHTLC) (lnwire.Message, error) {
+
+	onionBlob, err := l.channel.IncomingOnionBlob(pkt.incomingHTLCID)
+	if err != nil {
+		return nil, err
+	}
+
+	inKey := pkt.inKey()
+
+	if pkt.obfuscator.Type() == hop.EncrypterTypeIntroduction {
+		failure := lnwire.NewInvalidBlinding(onionBlob)
+		htlc.Reason, err = pkt.obfuscator.EncryptFirstHop(failure)
+		if err != nil {
+			return nil, err
+		}
+
+		err = l.channel.FailHTLC(
+			pkt.incomingHTLCID, htlc.Reason, pkt.sourceRef,
+			pkt.destRef, &inKey,
+		)
+
+		return htlc, err
+	}
+
+	shaOnionBlob := sha256.Sum256(onionBlob)
+	err = l.channel.MalformedFailHTLC(
+		pkt.incomingHTLCID, lnwire.CodeInvalidBlinding, shaOnionBlob,
+		pkt.sourceRef, pkt.destRef, &inKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwire.UpdateFailMalformedHTLC{
+		ChanID:       l.ChanID(),
+		ID:           pkt.incomingHTLCID,
+		ShaOnionBlob: shaOnionBlob,
+		FailureCode:  lnwire.CodeInvalidBlinding,
+	}, nil
+}
+
 // tryBatchUpdateCommitTx updates the commitment transaction if the batch is
 // full.
 func (l *channelLink) tryBatchUpdateCommitTx() {
@@ -1717,6 +1877,14 @@ func (l *channelLink) cleanupSpuriousResponse(pkt *htlcPacket) {
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
 func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
+	// Once the remote peer sent stfu, it may not send us any updates
+	// until the channel is resumed.
+	if isUpdateMsg(msg) && l.quiescer.remoteQuiesced() {
+		l.fail(LinkFailureError{code: ErrInvalidUpdate},
+			"received %T while channel is quiescent", msg)
+		return
+	}
+
 	switch msg := msg.(type) {
 
 	case *lnwire.UpdateAddHTLC:
@@ -1804,6 +1972,14 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			failure = &lnwire.FailInvalidOnionKey{
 				OnionSHA256: msg.ShaOnionBlob,
 			}
+
+		// Nodes inside a blinded route fail any HTLC as malformed
+		// with an invalid blinding code, which is passed back as is.
+		case lnwire.CodeInvalidBlinding:
+			failure = &lnwire.FailInvalidBlinding{
+				OnionSHA256: msg.ShaOnionBlob,
+			}
+
 		default:
 			l.log.Warnf("unexpected failure code received in "+
 				"UpdateFailMailformedHTLC: %v", msg.FailureCode)
@@ -2079,7 +2255,12 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		}
 
 		l.processRemoteSettleFails(fwdPkg, settleFails)
-		l.processRemoteAdds(fwdPkg, adds)
+
+		// Settling or failing the adds results in new updates, so
+		// we'll defer them if the channel is quiescent.
+		if !l.deferRemoteAdds(fwdPkg, adds) {
+			l.processRemoteAdds(fwdPkg, adds)
+		}
 
 		// If the link failed during processing the adds, we must
 		// return to ensure we won't attempted to update the state
@@ -2114,6 +2295,11 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// Update the mailbox's feerate as well.
 		l.mailBox.SetFeeRate(fee)
 
+	case *lnwire.Stfu, *lnwire.DynPropose, *lnwire.DynAck,
+		*lnwire.DynReject:
+
+		l.handleQuiescenceMsg(msg)
+
 	// In the case where we receive a warning message from our peer, just
 	// log it and move on. We choose not to disconnect from our peer,
 	// although we "MAY" do so according to the specification.
@@ -2543,7 +2729,7 @@ func (l *channelLink) UpdateForwardingPolicy(
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	incomingHtlcAmt, amtToForward lnwire.MilliSatoshi,
-	incomingTimeout, outgoingTimeout uint32,
+	inboundFee models.InboundFee, incomingTimeout, outgoingTimeout uint32,
 	heightNow uint32, originalScid lnwire.ShortChannelID) *LinkError {
 
 	l.RLock()
@@ -2553,7 +2739,15 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	// Using the amount of the incoming HTLC, we'll calculate the expected
 	// fee this incoming HTLC must carry in order to satisfy the
 	// constraints of the outgoing link.
-	expectedFee := ExpectedFee(policy, amtToForward)
+	outFee := ExpectedFee(policy, amtToForward)
+
+	// The incoming link may additionally charge an inbound fee, which is
+	// based on the outgoing amount plus the outgoing fee. Both components
+	// are calculated separately, so that rounding matches the route
+	// construction of the sender. The inbound fee may be negative, in
+	// which case the expected fee is lowered.
+	inFee := inboundFee.CalcFee(amtToForward + outFee)
+	expectedFee := int64(outFee) + inFee
 
 	// If the actual fee is less than our expected fee, then we'll reject
 	// this HTLC as it didn't provide a sufficient amount of fees, or the
@@ -2561,11 +2755,11 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	// information to construct the forwarding information for this hop. In
 	// any case, we'll cancel this HTLC. We're checking for this case first
 	// to leak as little information as possible.
-	actualFee := incomingHtlcAmt - amtToForward
+	actualFee := int64(incomingHtlcAmt) - int64(amtToForward)
 	if incomingHtlcAmt < amtToForward || actualFee < expectedFee {
 		l.log.Warnf("outgoing htlc(%x) has insufficient fee: "+
-			"expected %v, got %v",
-			payHash[:], int64(expectedFee), int64(actualFee))
+			"expected %v, got %v", payHash[:], expectedFee,
+			actualFee)
 
 		// As part of the returned error, we'll send our latest routing
 		// policy so the sending node obtains the most up to date data.
@@ -2944,9 +3138,11 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			onionReader := bytes.NewReader(pd.OnionBlob)
 
 			req := hop.DecodeHopIteratorRequest{
-				OnionReader:  onionReader,
-				RHash:        pd.RHash[:],
-				IncomingCltv: pd.Timeout,
+				OnionReader:    onionReader,
+				RHash:          pd.RHash[:],
+				IncomingCltv:   pd.Timeout,
+				IncomingAmount: pd.Amount,
+				BlindingPoint:  pd.BlindingPoint,
 			}
 
 			decodeReqs = append(decodeReqs, req)
@@ -2966,6 +3162,12 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 		return
 	}
 
+	// Fetch the inbound fee of this link, which is checked by the switch
+	// together with the policy of the outgoing link.
+	l.RLock()
+	inboundFee := l.cfg.FwrdingPolicy.InboundFee
+	l.RUnlock()
+
 	var switchPackets []*htlcPacket
 
 	for i, pd := range lockedInHtlcs {
@@ -2998,6 +3200,12 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 		// DecodeHopIterator function which process the Sphinx packet.
 		chanIterator, failureCode := decodeResps[i].Result()
 		if failureCode != lnwire.CodeNone {
+			// Nodes inside a blinded route don't reveal the exact
+			// failure, so that the route can't be probed.
+			if pd.BlindingPoint != nil {
+				failureCode = lnwire.CodeInvalidBlinding
+			}
+
 			// If we're unable to process the onion blob than we
 			// should send the malformed htlc error to payment
 			// sender.
@@ -3030,7 +3238,33 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 		heightNow := l.cfg.BestHeight()
 
 		pld, err := chanIterator.HopPayload()
-		if err != nil {
+		switch {
+		// If we received the HTLC from a node inside a blinded route,
+		// any failure is reported as a malformed invalid blinding
+		// error, which the previous hop will convert for the sender.
+		case err != nil && pd.BlindingPoint != nil:
+			l.sendMalformedHTLCError(
+				pd.HtlcIndex, lnwire.CodeInvalidBlinding,
+				onionBlob[:], pd.SourceRef,
+			)
+
+			l.log.Errorf("unable to decode blinded forwarding "+
+				"instructions: %v", err)
+			continue
+
+		// If we're the introduction node of a blinded route, the
+		// failure is returned to the sender as an invalid blinding.
+		case goErrors.Is(err, hop.ErrInvalidBlinding):
+			failure := lnwire.NewInvalidBlinding(onionBlob[:])
+			l.sendHTLCError(
+				pd, NewLinkError(failure), obfuscator, false,
+			)
+
+			l.log.Errorf("unable to decode blinded forwarding "+
+				"instructions: %v", err)
+			continue
+
+		case err != nil:
 			// If we're unable to process the onion payload, or we
 			// received invalid onion payload failure, then we
 			// should send an error back to the caller so the HTLC
@@ -3056,6 +3290,20 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			continue
 		}
 
+		// Failures of HTLCs that are part of a blinded route must not
+		// reveal where in the route they happened, so we mark their
+		// obfuscator for any failure to be converted. If the HTLC was
+		// sent to us with a blinding point we're inside the route,
+		// otherwise the blinding point in the payload makes us the
+		// introduction node.
+		switch {
+		case pd.BlindingPoint != nil:
+			obfuscator = hop.NewRelayingErrorEncrypter(obfuscator)
+
+		case pld.BlindingPoint() != nil:
+			obfuscator = hop.NewIntroductionErrorEncrypter(obfuscator)
+		}
+
 		fwdInfo := pld.ForwardingInfo()
 
 		switch fwdInfo.NextHop {
@@ -3097,9 +3345,10 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 				// Otherwise, it was already processed, we can
 				// can collect it and continue.
 				addMsg := &lnwire.UpdateAddHTLC{
-					Expiry:      fwdInfo.OutgoingCTLV,
-					Amount:      fwdInfo.AmountToForward,
-					PaymentHash: pd.RHash,
+					Expiry:        fwdInfo.OutgoingCTLV,
+					Amount:        fwdInfo.AmountToForward,
+					PaymentHash:   pd.RHash,
+					BlindingPoint: fwdInfo.NextBlinding,
 				}
 
 				// Finally, we'll encode the onion packet for
@@ -3119,6 +3368,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					sourceRef:       pd.SourceRef,
 					incomingAmount:  pd.Amount,
 					amount:          addMsg.Amount,
+					inboundFee:      inboundFee,
 					htlc:            addMsg,
 					obfuscator:      obfuscator,
 					incomingTimeout: pd.Timeout,
@@ -3139,9 +3389,10 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// create the outgoing HTLC using the parameters as
 			// specified in the forwarding info.
 			addMsg := &lnwire.UpdateAddHTLC{
-				Expiry:      fwdInfo.OutgoingCTLV,
-				Amount:      fwdInfo.AmountToForward,
-				PaymentHash: pd.RHash,
+				Expiry:        fwdInfo.OutgoingCTLV,
+				Amount:        fwdInfo.AmountToForward,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: fwdInfo.NextBlinding,
 			}
 
 			// Finally, we'll encode the onion packet for the
@@ -3183,6 +3434,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					sourceRef:       pd.SourceRef,
 					incomingAmount:  pd.Amount,
 					amount:          addMsg.Amount,
+					inboundFee:      inboundFee,
 					htlc:            addMsg,
 					obfuscator:      obfuscator,
 					incomingTimeout: pd.Timeout,
@@ -3380,23 +3632,44 @@ func (l *channelLink) forwardBatch(replay bool, packets ...*htlcPacket) {
 func (l *channelLink) sendHTLCError(pd *lnwallet.PaymentDescriptor,
 	failure *LinkError, e hop.ErrorEncrypter, isReceive bool) {
 
-	reason, err := e.EncryptFirstHop(failure.WireMessage())
-	if err != nil {
-		l.log.Errorf("unable to obfuscate error: %v", err)
-		return
-	}
+	switch e.Type() {
+	// If we received the HTLC from a node inside a blinded route, it is
+	// failed as malformed, which the previous hop will convert for the
+	// sender.
+	case hop.EncrypterTypeRelaying:
+		l.sendMalformedHTLCError(
+			pd.HtlcIndex, lnwire.CodeInvalidBlinding, pd.OnionBlob,
+			pd.SourceRef,
+		)
 
-	err = l.channel.FailHTLC(pd.HtlcIndex, reason, pd.SourceRef, nil, nil)
-	if err != nil {
-		l.log.Errorf("unable cancel htlc: %v", err)
-		return
-	}
+	default:
+		// If we're the introduction node of a blinded route, the
+		// actual failure is replaced by an invalid blinding failure.
+		wireFailure := failure.WireMessage()
+		if e.Type() == hop.EncrypterTypeIntroduction {
+			wireFailure = lnwire.NewInvalidBlinding(pd.OnionBlob)
+		}
 
-	l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
-		ChanID: l.ChanID(),
-		ID:     pd.HtlcIndex,
-		Reason: reason,
-	})
+		reason, err := e.EncryptFirstHop(wireFailure)
+		if err != nil {
+			l.log.Errorf("unable to obfuscate error: %v", err)
+			return
+		}
+
+		err = l.channel.FailHTLC(
+			pd.HtlcIndex, reason, pd.SourceRef, nil, nil,
+		)
+		if err != nil {
+			l.log.Errorf("unable cancel htlc: %v", err)
+			return
+		}
+
+		l.cfg.Peer.SendMessage(false, &lnwire.UpdateFailHTLC{
+			ChanID: l.ChanID(),
+			ID:     pd.HtlcIndex,
+			Reason: reason,
+		})
+	}
 
 	// Notify a link failure on our incoming link. Outgoing htlc information
 	// is not available at this point, because we have not decrypted the
@@ -3431,7 +3704,9 @@ func (l *channelLink) sendMalformedHTLCError(htlcIndex uint64,
 	code lnwire.FailCode, onionBlob []byte, sourceRef *channeldb.AddRef) {
 
 	shaOnionBlob := sha256.Sum256(onionBlob)
-	err := l.channel.MalformedFailHTLC(htlcIndex, code, shaOnionBlob, sourceRef)
+	err := l.channel.MalformedFailHTLC(
+		htlcIndex, code, shaOnionBlob, sourceRef, nil, nil,
+	)
 	if err != nil {
 		l.log.Errorf("unable cancel htlc: %v", err)
 		return
@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
@@ -21,6 +22,7 @@ import (
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/contractcourt"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/htlcswitch/hodl"
 	"github.com/ltcsuite/lnd/htlcswitch/hop"
 	"github.com/ltcsuite/lnd/input"
@@ -1401,7 +1403,8 @@ func TestChannelLinkMultiHopUnknownPaymentHash(t *testing.T) {
 
 	// Send payment and expose err channel.
 	err = n.aliceServer.htlcSwitch.SendHTLC(
-		n.firstBobChannelLink.ShortChanID(), pid, htlc,
+		context.Background(), n.firstBobChannelLink.ShortChanID(), pid,
+		htlc,
 	)
 	require.NoError(t, err, "unable to get send payment")
 
@@ -1448,6 +1451,101 @@ func TestChannelLinkMultiHopUnknownPaymentHash(t *testing.T) {
 	}, 10*time.Second, 100*time.Millisecond)
 }
 
+// sendBlindedPayment sends an htlc through the given hops of the network,
+// which carries a blinding point as if Alice were a node inside a blinded
+// route, and returns the error that the payment failed with.
+func sendBlindedPayment(t *testing.T, n *threeHopNetwork,
+	path ...*channelLink) error {
+
+	amount := lnwire.NewMSatFromSatoshis(ltcutil.SatoshiPerBitcoin)
+	htlcAmt, totalTimelock, hops := generateHops(
+		amount, testStartingHeight, path...,
+	)
+	blob, err := generateRoute(hops...)
+	require.NoError(t, err)
+
+	// The invoice isn't added to the registry of the receiver, so the
+	// payment is failed with an unknown payment hash.
+	_, htlc, pid, err := generatePayment(
+		amount, htlcAmt, totalTimelock, blob,
+	)
+	require.NoError(t, err)
+
+	blindingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	htlc.BlindingPoint = blindingKey.PubKey()
+
+	err = n.aliceServer.htlcSwitch.SendHTLC(
+		context.Background(), n.firstBobChannelLink.ShortChanID(), pid,
+		htlc,
+	)
+	require.NoError(t, err, "unable to send payment")
+
+	resultChan, err := n.aliceServer.htlcSwitch.GetAttemptResult(
+		pid, htlc.PaymentHash, newMockDeobfuscator(),
+	)
+	require.NoError(t, err, "unable to get payment result")
+
+	select {
+	case result, ok := <-resultChan:
+		require.True(t, ok, "unexpected shutdown")
+
+		return result.Error
+
+	case <-time.After(10 * time.Second):
+		t.Fatalf("no result arrive")
+	}
+
+	return nil
+}
+
+// TestChannelLinkBlindedDownstreamFailure checks that a node inside a blinded
+// route fails an htlc that was failed further down the route as malformed
+// with an invalid blinding code, hiding the actual failure.
+func TestChannelLinkBlindedDownstreamFailure(t *testing.T) {
+	t.Parallel()
+
+	channels, _, err := createClusterChannels(
+		t, ltcutil.SatoshiPerBitcoin*5, ltcutil.SatoshiPerBitcoin*5,
+	)
+	require.NoError(t, err, "unable to create channel")
+
+	n := newThreeHopNetwork(t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight)
+	require.NoError(t, n.start())
+	t.Cleanup(n.stop)
+
+	// Carol fails the payment with an unknown payment hash, which Bob
+	// must not pass back to Alice as he received the htlc with a blinding
+	// point.
+	err = sendBlindedPayment(
+		t, n, n.firstBobChannelLink, n.carolChannelLink,
+	)
+	assertFailureCode(t, err, lnwire.CodeInvalidBlinding)
+}
+
+// TestChannelLinkBlindedExitHopFailure checks that the final node of a blinded
+// route fails an htlc as malformed with an invalid blinding code, hiding the
+// actual failure.
+func TestChannelLinkBlindedExitHopFailure(t *testing.T) {
+	t.Parallel()
+
+	channels, _, err := createClusterChannels(
+		t, ltcutil.SatoshiPerBitcoin*5, ltcutil.SatoshiPerBitcoin*5,
+	)
+	require.NoError(t, err, "unable to create channel")
+
+	n := newThreeHopNetwork(t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight)
+	require.NoError(t, n.start())
+	t.Cleanup(n.stop)
+
+	// Bob is the final hop and doesn't know the payment hash, which he
+	// must not reveal as he received the htlc with a blinding point.
+	err = sendBlindedPayment(t, n, n.firstBobChannelLink)
+	assertFailureCode(t, err, lnwire.CodeInvalidBlinding)
+}
+
 // TestChannelLinkMultiHopUnknownNextHop construct the chain of hops
 // Carol<->Bob<->Alice and checks that we receive remote error from Bob if he
 // has no idea about next hop (hop might goes down and routing info not updated
@@ -1845,6 +1943,7 @@ type mockPeer struct {
 	disconnected bool
 	sentMsgs     chan lnwire.Message
 	quit         chan struct{}
+	features     *lnwire.FeatureVector
 }
 
 func (m *mockPeer) QuitSignal() <-chan struct{} {
@@ -1883,10 +1982,10 @@ func (m *mockPeer) Address() net.Addr {
 	return nil
 }
 func (m *mockPeer) LocalFeatures() *lnwire.FeatureVector {
-	return nil
+	return m.features
 }
 func (m *mockPeer) RemoteFeatures() *lnwire.FeatureVector {
-	return nil
+	return m.features
 }
 
 func (m *mockPeer) AddPendingChannel(_ lnwire.ChannelID,
@@ -4104,7 +4203,8 @@ func TestChannelLinkAcceptDuplicatePayment(t *testing.T) {
 	// With the invoice now added to Carol's registry, we'll send the
 	// payment.
 	err = n.aliceServer.htlcSwitch.SendHTLC(
-		n.firstBobChannelLink.ShortChanID(), pid, htlc,
+		context.Background(), n.firstBobChannelLink.ShortChanID(), pid,
+		htlc,
 	)
 	require.NoError(t, err, "unable to send payment to carol")
 
@@ -4116,7 +4216,8 @@ func TestChannelLinkAcceptDuplicatePayment(t *testing.T) {
 	// Now, if we attempt to send the payment *again* it should be rejected
 	// as it's a duplicate request.
 	err = n.aliceServer.htlcSwitch.SendHTLC(
-		n.firstBobChannelLink.ShortChanID(), pid, htlc,
+		context.Background(), n.firstBobChannelLink.ShortChanID(), pid,
+		htlc,
 	)
 	if err != ErrDuplicateAdd {
 		t.Fatalf("ErrDuplicateAdd should have been "+
@@ -4380,6 +4481,7 @@ func (h *persistentLinkHarness) restartLink(
 		alicePeer  = &mockPeer{
 			sentMsgs: make(chan lnwire.Message, 2000),
 			quit:     make(chan struct{}),
+			features: h.coreLink.cfg.Peer.(*mockPeer).features,
 		}
 
 		globalPolicy = models.ForwardingPolicy{
@@ -5749,7 +5851,8 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("satisfied", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 150, 0, lnwire.ShortChannelID{})
+			models.InboundFee{}, 200, 150, 0,
+			lnwire.ShortChannelID{})
 		if result != nil {
 			t.Fatalf("expected policy to be satisfied")
 		}
@@ -5757,7 +5860,8 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("below minhtlc", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 100, 50,
-			200, 150, 0, lnwire.ShortChannelID{})
+			models.InboundFee{}, 200, 150, 0,
+			lnwire.ShortChannelID{})
 		if _, ok := result.WireMessage().(*lnwire.FailAmountBelowMinimum); !ok {
 			t.Fatalf("expected FailAmountBelowMinimum failure code")
 		}
@@ -5765,15 +5869,34 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("above maxhtlc", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1200,
-			200, 150, 0, lnwire.ShortChannelID{})
+			models.InboundFee{}, 200, 150, 0,
+			lnwire.ShortChannelID{})
 		if _, ok := result.WireMessage().(*lnwire.FailTemporaryChannelFailure); !ok {
 			t.Fatalf("expected FailTemporaryChannelFailure failure code")
 		}
 	})
 
+	t.Run("inbound fee satisfied", func(t *testing.T) {
+		// A discount on the incoming channel lowers the fee that needs
+		// to be paid.
+		inboundFee := models.InboundFee{Base: -5}
+		result := link.CheckHtlcForward(hash, 1005, 1000,
+			inboundFee, 200, 150, 0, lnwire.ShortChannelID{})
+		require.Nil(t, result)
+	})
+
+	t.Run("inbound fee insufficient", func(t *testing.T) {
+		inboundFee := models.InboundFee{Base: 10}
+		result := link.CheckHtlcForward(hash, 1015, 1000,
+			inboundFee, 200, 150, 0, lnwire.ShortChannelID{})
+		_, ok := result.WireMessage().(*lnwire.FailFeeInsufficient)
+		require.True(t, ok, "expected FailFeeInsufficient failure code")
+	})
+
 	t.Run("insufficient fee", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1005, 1000,
-			200, 150, 0, lnwire.ShortChannelID{})
+			models.InboundFee{}, 200, 150, 0,
+			lnwire.ShortChannelID{})
 		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
 			t.Fatalf("expected FailFeeInsufficient failure code")
 		}
@@ -5785,7 +5908,7 @@ func TestCheckHtlcForward(t *testing.T) {
 		t.Parallel()
 
 		result := link.CheckHtlcForward(
-			hash, 100005, 100000, 200,
+			hash, 100005, 100000, models.InboundFee{}, 200,
 			150, 0, lnwire.ShortChannelID{},
 		)
 		_, ok := result.WireMessage().(*lnwire.FailFeeInsufficient)
@@ -5794,7 +5917,8 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("expiry too soon", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 150, 190, lnwire.ShortChannelID{})
+			models.InboundFee{}, 200, 150, 190,
+			lnwire.ShortChannelID{})
 		if _, ok := result.WireMessage().(*lnwire.FailExpiryTooSoon); !ok {
 			t.Fatalf("expected FailExpiryTooSoon failure code")
 		}
@@ -5802,7 +5926,8 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("incorrect cltv expiry", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 190, 0, lnwire.ShortChannelID{})
+			models.InboundFee{}, 200, 190, 0,
+			lnwire.ShortChannelID{})
 		if _, ok := result.WireMessage().(*lnwire.FailIncorrectCltvExpiry); !ok {
 			t.Fatalf("expected FailIncorrectCltvExpiry failure code")
 		}
@@ -5812,7 +5937,8 @@ func TestCheckHtlcForward(t *testing.T) {
 	t.Run("cltv expiry too far in the future", func(t *testing.T) {
 		// Check that expiry isn't too far in the future.
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			10200, 10100, 0, lnwire.ShortChannelID{})
+			models.InboundFee{}, 10200, 10100, 0,
+			lnwire.ShortChannelID{})
 		if _, ok := result.WireMessage().(*lnwire.FailExpiryTooFar); !ok {
 			t.Fatalf("expected FailExpiryTooFar failure code")
 		}
@@ -6864,3 +6990,426 @@ func TestChannelLinkShortFailureRelay(t *testing.T) {
 	default:
 	}
 }
+
+// newUpgradeTestContext creates a link test context with a started link of
+// Alice, whose peer signals support for dynamic commitments if enabled. The
+// link config can be adjusted with the given options before the link starts.
+// The returned harness allows to restart Alice's link.
+func newUpgradeTestContext(t *testing.T, dynCommits bool,
+	opts ...func(*ChannelLinkConfig)) (*linkTestContext,
+	*persistentLinkHarness) {
+
+	const chanAmt = ltcutil.SatoshiPerBitcoin * 5
+	const chanReserve = ltcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, batchTicker, start, restore, err :=
+		newSingleLinkTestHarness(t, chanAmt, chanReserve)
+	require.NoError(t, err)
+
+	coreLink := aliceLink.(*channelLink)
+	alicePeer := coreLink.cfg.Peer.(*mockPeer)
+	alicePeer.features = lnwire.EmptyFeatureVector()
+	if dynCommits {
+		alicePeer.features = lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(
+				lnwire.QuiescenceOptional,
+				lnwire.DynamicCommitmentsOptional,
+			), lnwire.Features,
+		)
+	}
+
+	for _, opt := range opts {
+		opt(&coreLink.cfg)
+	}
+
+	require.NoError(t, start())
+
+	return &linkTestContext{
+		t:          t,
+		aliceLink:  aliceLink,
+		bobChannel: bobChannel,
+		aliceMsgs:  alicePeer.sentMsgs,
+	}, newPersistentLinkHarness(t, aliceLink, batchTicker, restore)
+}
+
+// receiveMsgAlice waits for Alice to send a message of the given type.
+func receiveMsgAlice[T lnwire.Message](ctx *linkTestContext) T {
+	ctx.t.Helper()
+
+	var msg lnwire.Message
+	select {
+	case msg = <-ctx.aliceMsgs:
+	case <-time.After(15 * time.Second):
+		ctx.t.Fatalf("did not receive message")
+	}
+
+	typedMsg, ok := msg.(T)
+	require.Truef(ctx.t, ok, "unexpected message %T", msg)
+
+	return typedMsg
+}
+
+// TestChannelLinkUpgradeCommitType tests that a link quiesces the channel
+// and upgrades its commitment type to anchors in place.
+func TestChannelLinkUpgradeCommitType(t *testing.T) {
+	t.Parallel()
+
+	ctx, alice := newUpgradeTestContext(t, true)
+	chanID := ctx.aliceLink.ChanID()
+
+	errChan := ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+
+	// As the channel is clean, Alice sends stfu right away. A second
+	// upgrade request is rejected while the first one is in progress.
+	stfu := receiveMsgAlice[*lnwire.Stfu](ctx)
+	require.True(t, stfu.Initiator)
+
+	err := <-ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+	require.ErrorIs(t, err, ErrUpgradeInProgress)
+
+	// Once Bob replies with stfu, the channel is quiescent and Alice
+	// proposes the new channel type.
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.Stfu{ChanID: chanID})
+
+	propose := receiveMsgAlice[*lnwire.DynPropose](ctx)
+	require.NotNil(t, propose.ChannelType)
+
+	commitType, err := lnwallet.CommitTypeForUpgrade(*propose.ChannelType)
+	require.NoError(t, err)
+	require.EqualValues(
+		t, lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx, commitType,
+	)
+
+	// Bob accepts the proposal, after which both sign a commitment of the
+	// new type and revoke their old ones.
+	require.NoError(t, ctx.bobChannel.UpgradeCommitType(commitType))
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.DynAck{ChanID: chanID})
+
+	// ----sig---->
+	ctx.receiveCommitSigAliceToBob(0)
+	// <---rev-----
+	ctx.sendRevAndAckBobToAlice()
+	// <---sig-----
+	ctx.sendCommitSigBobToAlice(0)
+	// ----rev---->
+	ctx.receiveRevAndAckAliceToBob()
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("upgrade not completed")
+	}
+
+	require.True(t, alice.channel.State().ChanType.HasAnchors())
+	require.True(t, ctx.bobChannel.State().ChanType.HasAnchors())
+
+	// Both commitments now carry the anchor outputs.
+	localCommit := alice.channel.State().LocalCommitment
+	require.Len(t, localCommit.CommitTx.TxOut, 4)
+
+	// With the channel resumed, HTLCs can be sent again.
+	htlc, _ := generateHtlcAndInvoice(t, 0)
+	ctx.sendHtlcAliceToBob(0, htlc)
+	ctx.receiveHtlcAliceToBob()
+}
+
+// TestChannelLinkUpgradeCommitTypeTowerClient tests that a link backs up the
+// states of the old commitment type with the tower client of that type, and
+// switches to the tower client of the new type once the upgrade is complete.
+func TestChannelLinkUpgradeCommitTypeTowerClient(t *testing.T) {
+	t.Parallel()
+
+	legacyClient := newMockTowerClient()
+	anchorClient := newMockTowerClient()
+
+	ctx, alice := newUpgradeTestContext(
+		t, true, func(cfg *ChannelLinkConfig) {
+			cfg.TowerClient = legacyClient
+			cfg.GetTowerClient = func(
+				chanType channeldb.ChannelType) TowerClient {
+
+				if chanType.HasAnchors() {
+					return anchorClient
+				}
+
+				return legacyClient
+			}
+		},
+	)
+	chanID := ctx.aliceLink.ChanID()
+
+	assertBackup := func(client *mockTowerClient, stateNum uint64) {
+		t.Helper()
+
+		select {
+		case backup := <-client.backups:
+			require.Equal(t, stateNum, backup)
+
+		case <-time.After(15 * time.Second):
+			t.Fatalf("state %d not backed up", stateNum)
+		}
+	}
+
+	errChan := ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+
+	receiveMsgAlice[*lnwire.Stfu](ctx)
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.Stfu{ChanID: chanID})
+
+	propose := receiveMsgAlice[*lnwire.DynPropose](ctx)
+	commitType, err := lnwallet.CommitTypeForUpgrade(*propose.ChannelType)
+	require.NoError(t, err)
+
+	require.NoError(t, ctx.bobChannel.UpgradeCommitType(commitType))
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.DynAck{ChanID: chanID})
+
+	// The last legacy commitment of Bob is revoked during the upgrade,
+	// so it is still backed up by the legacy tower client.
+	//
+	// ----sig---->
+	ctx.receiveCommitSigAliceToBob(0)
+	// <---rev-----
+	ctx.sendRevAndAckBobToAlice()
+	assertBackup(legacyClient, 0)
+	// <---sig-----
+	ctx.sendCommitSigBobToAlice(0)
+	// ----rev---->
+	ctx.receiveRevAndAckAliceToBob()
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("upgrade not completed")
+	}
+
+	// Once the channel is used again, the revoked anchor commitment of
+	// Bob is backed up by the anchor tower client.
+	htlc, _ := generateHtlcAndInvoice(t, 0)
+	ctx.sendHtlcAliceToBob(0, htlc)
+	ctx.receiveHtlcAliceToBob()
+
+	select {
+	case alice.batchTicker <- time.Now():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("could not force commit sig")
+	}
+
+	// ----sig---->
+	ctx.receiveCommitSigAliceToBob(1)
+	// <---rev-----
+	ctx.sendRevAndAckBobToAlice()
+	assertBackup(anchorClient, 1)
+
+	select {
+	case backup := <-legacyClient.backups:
+		t.Fatalf("unexpected legacy backup of state %d", backup)
+	default:
+	}
+}
+
+// TestChannelLinkUpgradeCommitTypeRejected tests that an upgrade fails if
+// dynamic commitments aren't supported or the remote peer rejects it.
+func TestChannelLinkUpgradeCommitTypeRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx, _ := newUpgradeTestContext(t, false)
+
+	err := <-ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+	require.ErrorIs(t, err, ErrQuiescenceUnsupported)
+
+	ctx, alice := newUpgradeTestContext(t, true)
+	chanID := ctx.aliceLink.ChanID()
+
+	// An upgrade to the current commitment type is invalid.
+	err = <-ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeTweakless,
+	)
+	require.ErrorIs(t, err, lnwallet.ErrChanTypeUpgradeUnsupported)
+
+	errChan := ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+
+	receiveMsgAlice[*lnwire.Stfu](ctx)
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.Stfu{ChanID: chanID})
+	receiveMsgAlice[*lnwire.DynPropose](ctx)
+
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.DynReject{ChanID: chanID})
+
+	select {
+	case err := <-errChan:
+		require.ErrorIs(t, err, ErrUpgradeRejected)
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("upgrade not rejected")
+	}
+
+	require.False(t, alice.channel.State().ChanType.HasAnchors())
+	require.True(t, alice.channel.ProposedCommitType().IsNone())
+
+	// The channel is resumed, so HTLCs can be sent again.
+	htlc, _ := generateHtlcAndInvoice(t, 0)
+	ctx.sendHtlcAliceToBob(0, htlc)
+	ctx.receiveHtlcAliceToBob()
+}
+
+// restartUpgradeLink restarts Alice's link and reestablishes the channel with
+// Bob's current state.
+func restartUpgradeLink(ctx *linkTestContext, alice *persistentLinkHarness) {
+	ctx.t.Helper()
+
+	alice.restart(false, true)
+	ctx.aliceLink = alice.link
+	ctx.aliceMsgs = alice.msgs
+
+	// --reestablish->
+	receiveMsgAlice[*lnwire.ChannelReestablish](ctx)
+
+	// <-reestablish--
+	bobReest, err := ctx.bobChannel.State().ChanSyncMsg()
+	require.NoError(ctx.t, err)
+	ctx.aliceLink.HandleChannelUpdate(bobReest)
+
+	// As the channel hasn't advanced past its initial state, Alice resends
+	// channel_ready first.
+	receiveMsgAlice[*lnwire.ChannelReady](ctx)
+}
+
+// TestChannelLinkUpgradeCommitTypeLostAck tests that Alice applies an upgrade
+// she proposed if Bob's ack is lost due to a disconnect and only resent once
+// the channel is reestablished.
+func TestChannelLinkUpgradeCommitTypeLostAck(t *testing.T) {
+	t.Parallel()
+
+	ctx, alice := newUpgradeTestContext(t, true)
+	chanID := ctx.aliceLink.ChanID()
+
+	errChan := ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+
+	receiveMsgAlice[*lnwire.Stfu](ctx)
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.Stfu{ChanID: chanID})
+	receiveMsgAlice[*lnwire.DynPropose](ctx)
+
+	// Bob accepts the proposal, but the connection drops before Alice
+	// receives his ack. The proposal is persisted, so that it survives
+	// the disconnect.
+	require.NoError(t, ctx.bobChannel.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	))
+	require.Equal(
+		t, fn.Some[lnwallet.CommitmentType](
+			lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+		),
+		alice.channel.ProposedCommitType(),
+	)
+
+	restartUpgradeLink(ctx, alice)
+
+	select {
+	case err := <-errChan:
+		require.ErrorIs(t, err, ErrLinkShuttingDown)
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("upgrade request not failed")
+	}
+
+	// After reconnecting, Alice quiesces the channel again to resume her
+	// proposal, while Bob resends his ack.
+	stfu := receiveMsgAlice[*lnwire.Stfu](ctx)
+	require.True(t, stfu.Initiator)
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.DynAck{ChanID: chanID})
+
+	// ----sig---->
+	ctx.receiveCommitSigAliceToBob(0)
+	// <---rev-----
+	ctx.sendRevAndAckBobToAlice()
+	// <---sig-----
+	ctx.sendCommitSigBobToAlice(0)
+	// ----rev---->
+	ctx.receiveRevAndAckAliceToBob()
+
+	require.True(t, alice.channel.State().ChanType.HasAnchors())
+	require.True(t, alice.channel.ProposedCommitType().IsNone())
+
+	// With the upgrade complete, the channel is resumed.
+	htlc, _ := generateHtlcAndInvoice(t, 0)
+	ctx.sendHtlcAliceToBob(0, htlc)
+	ctx.receiveHtlcAliceToBob()
+}
+
+// TestChannelLinkUpgradeCommitTypeResendAck tests that Alice resends her ack
+// of an upgrade proposed by Bob when reestablishing the channel, if Bob hasn't
+// received a commitment of the new type yet.
+func TestChannelLinkUpgradeCommitTypeResendAck(t *testing.T) {
+	t.Parallel()
+
+	ctx, alice := newUpgradeTestContext(t, true)
+	chanID := ctx.aliceLink.ChanID()
+
+	chanType, err := lnwallet.ChannelTypeForUpgrade(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+	require.NoError(t, err)
+
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.Stfu{
+		ChanID:    chanID,
+		Initiator: true,
+	})
+	receiveMsgAlice[*lnwire.Stfu](ctx)
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:      chanID,
+		ChannelType: chanType,
+	})
+
+	// Alice accepts the proposal and signs a commitment of the new type,
+	// but neither message reaches Bob before the disconnect.
+	// ----ack----x
+	receiveMsgAlice[*lnwire.DynAck](ctx)
+	// ----sig----x
+	receiveMsgAlice[*lnwire.CommitSig](ctx)
+
+	restartUpgradeLink(ctx, alice)
+
+	// Alice resends her ack ahead of her commitment, so that Bob upgrades
+	// the channel before validating it.
+	// ----ack---->
+	receiveMsgAlice[*lnwire.DynAck](ctx)
+	require.NoError(t, ctx.bobChannel.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+	))
+
+	// Bob quiesced the channel again to resume his proposal before he
+	// received the ack, so Alice ignores his stfu.
+	ctx.aliceLink.HandleChannelUpdate(&lnwire.Stfu{
+		ChanID:    chanID,
+		Initiator: true,
+	})
+
+	// ----sig---->
+	ctx.receiveCommitSigAliceToBob(0)
+	// <---rev-----
+	ctx.sendRevAndAckBobToAlice()
+	// <---sig-----
+	ctx.sendCommitSigBobToAlice(0)
+	// ----rev---->
+	ctx.receiveRevAndAckAliceToBob()
+
+	require.True(t, alice.channel.State().ChanType.HasAnchors())
+
+	// With the upgrade complete, the channel is resumed.
+	htlc, _ := generateHtlcAndInvoice(t, 0)
+	ctx.sendHtlcAliceToBob(0, htlc)
+	ctx.receiveHtlcAliceToBob()
+}
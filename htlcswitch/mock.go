@@ -27,6 +27,7 @@ import (
 	"github.com/ltcsuite/lnd/lnpeer"
 	"github.com/ltcsuite/lnd/lntest/mock"
 	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/ticker"
@@ -843,7 +844,7 @@ func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 func (f *mockChannelLink) UpdateForwardingPolicy(_ models.ForwardingPolicy) {
 }
 func (f *mockChannelLink) CheckHtlcForward([32]byte, lnwire.MilliSatoshi,
-	lnwire.MilliSatoshi, uint32, uint32, uint32,
+	lnwire.MilliSatoshi, models.InboundFee, uint32, uint32, uint32,
 	lnwire.ShortChannelID) *LinkError {
 
 	return f.checkHtlcForwardResult
@@ -909,6 +910,9 @@ func (f *mockChannelLink) Stop()                                        {}
 func (f *mockChannelLink) EligibleToForward() bool                      { return f.eligible }
 func (f *mockChannelLink) MayAddOutgoingHtlc(lnwire.MilliSatoshi) error { return nil }
 func (f *mockChannelLink) ShutdownIfChannelClean() error                { return nil }
+func (f *mockChannelLink) UpgradeCommitType(lnwallet.CommitmentType) <-chan error {
+	return make(chan error)
+}
 func (f *mockChannelLink) setLiveShortChanID(sid lnwire.ShortChannelID) { f.shortChanID = sid }
 func (f *mockChannelLink) IsUnadvertised() bool                         { return f.unadvertised }
 func (f *mockChannelLink) UpdateShortChanID() (lnwire.ShortChannelID, error) {
@@ -918,6 +922,49 @@ func (f *mockChannelLink) UpdateShortChanID() (lnwire.ShortChannelID, error) {
 
 var _ ChannelLink = (*mockChannelLink)(nil)
 
+// mockTowerClient is a mock TowerClient that records the channels that are
+// registered with it and the states that it is asked to back up.
+type mockTowerClient struct {
+	mu         sync.Mutex
+	registered map[lnwire.ChannelID]struct{}
+
+	backups chan uint64
+}
+
+func newMockTowerClient() *mockTowerClient {
+	return &mockTowerClient{
+		registered: make(map[lnwire.ChannelID]struct{}),
+		backups:    make(chan uint64, 10),
+	}
+}
+
+func (m *mockTowerClient) RegisterChannel(chanID lnwire.ChannelID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.registered[chanID] = struct{}{}
+
+	return nil
+}
+
+func (m *mockTowerClient) BackupState(chanID *lnwire.ChannelID,
+	stateNum uint64) error {
+
+	m.mu.Lock()
+	_, ok := m.registered[*chanID]
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("channel %v not registered", chanID)
+	}
+
+	m.backups <- stateNum
+
+	return nil
+}
+
+var _ TowerClient = (*mockTowerClient)(nil)
+
 func newDB() (*channeldb.DB, func(), error) {
 	// First, create a temporary directory to be used for the duration of
 	// this test.
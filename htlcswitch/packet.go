@@ -2,9 +2,11 @@ package htlcswitch
 
 import (
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/htlcswitch/hop"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/record"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // htlcPacket is a wrapper around htlc lnwire update, which adds additional
@@ -43,6 +45,9 @@ type htlcPacket struct {
 	// amount is the value of the HTLC that is being created or modified.
 	amount lnwire.MilliSatoshi
 
+	// inboundFee is the fee schedule of the incoming channel.
+	inboundFee models.InboundFee
+
 	// htlc lnwire message type of which depends on switch request type.
 	htlc lnwire.Message
 
@@ -103,6 +108,18 @@ type htlcPacket struct {
 	// but receives a channel_update with the alias SCID. Instead, the
 	// payer should receive a channel_update with the public SCID.
 	originalOutgoingChanID lnwire.ShortChannelID
+
+	// strictOutgoingChan is set to true if the htlc must be forwarded
+	// over the channel identified by outgoingChanID, rather than over any
+	// channel with the same peer. It is set when an interceptor explicitly
+	// picks the outgoing channel of a forward.
+	strictOutgoingChan bool
+
+	// spanContext is the tracing span of the local payment attempt that
+	// this packet belongs to. It is only set for the adds of local
+	// payments, and allows the outgoing link to record its processing of
+	// the htlc as part of the payment's trace.
+	spanContext trace.SpanContext
 }
 
 // inKey returns the circuit key used to identify the incoming htlc.
@@ -0,0 +1,552 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwire"
+)
+
+var (
+	// ErrQuiescenceUnsupported is returned when the channel is requested to
+	// be upgraded, but either peer doesn't support quiescence or dynamic
+	// commitments.
+	ErrQuiescenceUnsupported = errors.New("quiescence or dynamic " +
+		"commitments not supported by both peers")
+
+	// ErrUpgradeInProgress is returned when an upgrade of the channel is
+	// requested while the channel is already being quiesced or upgraded.
+	ErrUpgradeInProgress = errors.New("channel upgrade already in " +
+		"progress")
+
+	// ErrQuiescenceConflict is returned when both peers initiated
+	// quiescence at the same time and the remote peer won the tie-break.
+	ErrQuiescenceConflict = errors.New("remote peer initiated " +
+		"quiescence concurrently")
+
+	// ErrUpgradeRejected is returned when the remote peer rejects the
+	// proposed upgrade of the channel.
+	ErrUpgradeRejected = errors.New("channel upgrade rejected by remote " +
+		"peer")
+)
+
+// upgradeReq is a request to upgrade the commitment type of the channel
+// managed by the link.
+type upgradeReq struct {
+	commitType lnwallet.CommitmentType
+	err        chan error
+}
+
+// deferredAdds holds a batch of locked-in remote adds whose processing was
+// deferred until the channel is no longer quiescent.
+type deferredAdds struct {
+	fwdPkg *channeldb.FwdPkg
+	adds   []*lnwallet.PaymentDescriptor
+}
+
+// quiescer tracks the state of the quiescence protocol (stfu) of a link, and
+// the dynamic commitment negotiation that is carried out once the channel is
+// quiescent. Once we've sent stfu, we may no longer send any updates to the
+// remote peer, and once we've received stfu, the remote peer may no longer
+// send us any. The channel stays quiescent until the upgrade negotiated on top
+// of it is either rejected or fully signed by both peers.
+type quiescer struct {
+	// localInit is true if we initiated quiescence.
+	localInit bool
+
+	// remoteInit is true if the remote peer initiated quiescence.
+	remoteInit bool
+
+	// sentStfu is true once we've sent stfu to the remote peer.
+	sentStfu bool
+
+	// receivedStfu is true once we've received stfu from the remote peer.
+	receivedStfu bool
+
+	// proposed is true once we've sent a dynamic commitment proposal to
+	// the remote peer.
+	proposed bool
+
+	// upgrading is true once both peers have agreed to upgrade the
+	// commitment type, until both peers have revoked their commitments of
+	// the old type.
+	upgrading bool
+
+	// upgrade is the pending upgrade request that made us initiate
+	// quiescence, if any.
+	upgrade *upgradeReq
+
+	// ackResent is true if we resent our dynamic commitment ack while
+	// reestablishing the channel. The remote peer may then still send the
+	// stfu of the session it restarted before it processed our ack.
+	ackResent bool
+
+	// deferred holds the remote adds that were locked in while quiescing.
+	deferred []deferredAdds
+}
+
+// active returns true if we may not send any new updates to the remote peer,
+// either because quiescence is being negotiated or because the commitment type
+// is being upgraded.
+func (q *quiescer) active() bool {
+	return q.localInit || q.receivedStfu || q.upgrading
+}
+
+// remoteQuiesced returns true if the remote peer may not send us any updates.
+func (q *quiescer) remoteQuiesced() bool {
+	return q.receivedStfu || q.upgrading
+}
+
+// quiescent returns true if both peers have sent stfu.
+func (q *quiescer) quiescent() bool {
+	return q.sentStfu && q.receivedStfu
+}
+
+// isQuiescenceInitiator returns true if we're the initiator of the quiescence
+// session. If both peers initiated it concurrently, the opener of the channel
+// wins.
+func (l *channelLink) isQuiescenceInitiator() bool {
+	q := &l.quiescer
+	if !q.localInit {
+		return false
+	}
+
+	return !q.remoteInit || l.channel.IsInitiator()
+}
+
+// UpgradeCommitType requests the link to upgrade the commitment type of the
+// channel in place. The returned channel receives nil once both peers have
+// signed commitments of the new type, or an error if the upgrade failed.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) UpgradeCommitType(
+	commitType lnwallet.CommitmentType) <-chan error {
+
+	errChan := make(chan error, 1)
+
+	select {
+	case l.upgradeRequest <- &upgradeReq{
+		commitType: commitType,
+		err:        errChan,
+	}:
+	case <-l.quit:
+		errChan <- ErrLinkShuttingDown
+	}
+
+	return errChan
+}
+
+// dynCommitsSupported returns true if both peers signaled support for
+// quiescence and dynamic commitments.
+func (l *channelLink) dynCommitsSupported() bool {
+	local := l.cfg.Peer.LocalFeatures()
+	remote := l.cfg.Peer.RemoteFeatures()
+
+	return local.HasFeature(lnwire.QuiescenceOptional) &&
+		remote.HasFeature(lnwire.QuiescenceOptional) &&
+		local.HasFeature(lnwire.DynamicCommitmentsOptional) &&
+		remote.HasFeature(lnwire.DynamicCommitmentsOptional)
+}
+
+// handleUpgradeRequest validates a request to upgrade the commitment type of
+// the channel and initiates quiescence to carry it out.
+func (l *channelLink) handleUpgradeRequest(req *upgradeReq) {
+	q := &l.quiescer
+
+	switch {
+	case !l.dynCommitsSupported():
+		req.err <- ErrQuiescenceUnsupported
+		return
+
+	case q.active() || q.upgrade != nil:
+		req.err <- ErrUpgradeInProgress
+		return
+	}
+
+	// Check that the upgrade is possible in the current state before
+	// pausing the channel. Both peers check this again once the channel is
+	// quiescent.
+	if err := l.channel.ValidateCommitTypeUpgrade(
+		req.commitType,
+	); err != nil {
+		req.err <- err
+		return
+	}
+
+	l.log.Infof("Initiating quiescence to upgrade commitment type to %v",
+		req.commitType)
+
+	q.localInit = true
+	q.upgrade = req
+}
+
+// updateQuiescence drives the quiescence protocol forward. It sends stfu once
+// all of our updates are committed, proposes the pending upgrade once the
+// channel is quiescent, and resumes the channel once the upgrade is complete.
+func (l *channelLink) updateQuiescence() {
+	q := &l.quiescer
+
+	// Once both peers have revoked their commitments of the old type, the
+	// upgrade is complete and the channel can be resumed.
+	if q.upgrading {
+		if !l.channel.ChanTypeUpgradeComplete() {
+			return
+		}
+
+		l.log.Infof("Commitment type upgrade complete")
+
+		// All states that are revoked from now on are of the new
+		// type, so they must be backed up by its tower client.
+		if err := l.updateTowerClient(); err != nil {
+			l.fail(LinkFailureError{code: ErrInternalError},
+				"unable to update tower client: %v", err)
+			return
+		}
+
+		l.resumeQuiescence(nil)
+		return
+	}
+
+	if !q.localInit && !q.receivedStfu {
+		return
+	}
+
+	// We may only send stfu once none of our updates are pending on
+	// either commitment.
+	if !q.sentStfu {
+		if !l.channel.LocalUpdatesCommitted() {
+			return
+		}
+
+		stfu := &lnwire.Stfu{
+			ChanID:    l.ChanID(),
+			Initiator: q.localInit,
+		}
+		if err := l.cfg.Peer.SendMessage(false, stfu); err != nil {
+			l.log.Errorf("unable to send stfu: %v", err)
+			return
+		}
+
+		q.sentStfu = true
+	}
+
+	// If the channel is quiescent and we're the initiator, we'll propose
+	// the upgrade as soon as no commitments are in flight anymore. This
+	// ensures that both peers validate the proposal against the same
+	// state.
+	if !q.quiescent() || !l.isQuiescenceInitiator() || q.proposed ||
+		!l.channel.CommitmentsSynced() {
+
+		return
+	}
+
+	chanType, err := lnwallet.ChannelTypeForUpgrade(q.upgrade.commitType)
+	if err != nil {
+		l.fail(LinkFailureError{code: ErrInternalError},
+			"unable to create channel type: %v", err)
+		return
+	}
+
+	// We'll persist the proposal before sending it, so that we can still
+	// apply the upgrade if the remote peer's ack is lost due to a
+	// disconnect.
+	err = l.channel.ProposeCommitTypeUpgrade(q.upgrade.commitType)
+	if err != nil {
+		l.fail(LinkFailureError{code: ErrInternalError},
+			"unable to store dynamic commitment proposal: %v", err)
+		return
+	}
+
+	propose := &lnwire.DynPropose{
+		ChanID:      l.ChanID(),
+		ChannelType: chanType,
+	}
+	if err := l.cfg.Peer.SendMessage(false, propose); err != nil {
+		l.log.Errorf("unable to send dynamic commitment proposal: %v",
+			err)
+		return
+	}
+
+	q.proposed = true
+}
+
+// updateTowerClient switches the link to the tower client of the current type
+// of the channel, registering the channel with it.
+func (l *channelLink) updateTowerClient() error {
+	if l.cfg.GetTowerClient == nil {
+		return nil
+	}
+
+	towerClient := l.cfg.GetTowerClient(l.channel.State().ChanType)
+	if towerClient != nil {
+		if err := towerClient.RegisterChannel(l.ChanID()); err != nil {
+			return err
+		}
+	}
+
+	l.cfg.TowerClient = towerClient
+
+	return nil
+}
+
+// resumeQuiescence ends the quiescence session, replies to a pending upgrade
+// request with the given error and processes the remote adds that were locked
+// in while the channel was quiescent.
+func (l *channelLink) resumeQuiescence(err error) {
+	q := &l.quiescer
+
+	if q.upgrade != nil {
+		q.upgrade.err <- err
+	}
+
+	deferred := q.deferred
+	l.quiescer = quiescer{}
+
+	for _, batch := range deferred {
+		l.processRemoteAdds(batch.fwdPkg, batch.adds)
+		if l.failed {
+			return
+		}
+	}
+
+	if l.channel.OweCommitment() {
+		l.updateCommitTxOrFail()
+	}
+}
+
+// handleQuiescenceMsg handles the messages of the quiescence protocol and the
+// dynamic commitment negotiation.
+func (l *channelLink) handleQuiescenceMsg(msg lnwire.Message) {
+	q := &l.quiescer
+
+	switch msg := msg.(type) {
+	case *lnwire.Stfu:
+		// If we resent our ack while reestablishing the channel, the
+		// remote peer may have quiesced the channel again to resume
+		// its proposal before it processed the ack.
+		if q.ackResent && q.upgrading && msg.Initiator {
+			l.log.Debugf("Ignoring stfu sent before our resent " +
+				"dynamic commitment ack")
+
+			q.ackResent = false
+			return
+		}
+
+		if !l.dynCommitsSupported() || q.receivedStfu || q.upgrading {
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"unexpected stfu message")
+			return
+		}
+
+		q.receivedStfu = true
+		q.remoteInit = msg.Initiator
+
+		// If both of us initiated quiescence and we lost the
+		// tie-break, our own request is failed.
+		if q.localInit && !l.isQuiescenceInitiator() {
+			l.log.Infof("Remote peer won quiescence tie-break")
+
+			// A proposal we resumed after reconnecting is
+			// abandoned as well.
+			err := l.channel.WithdrawCommitTypeProposal()
+			if err != nil {
+				l.fail(LinkFailureError{code: ErrInternalError},
+					"unable to withdraw dynamic "+
+						"commitment proposal: %v", err)
+				return
+			}
+
+			q.upgrade.err <- ErrQuiescenceConflict
+			q.upgrade = nil
+			q.localInit = false
+		}
+
+	case *lnwire.DynPropose:
+		if !q.quiescent() || l.isQuiescenceInitiator() {
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"unexpected dynamic commitment proposal")
+			return
+		}
+
+		if err := l.acceptDynPropose(msg); err != nil {
+			l.log.Warnf("Rejecting dynamic commitment proposal: %v",
+				err)
+
+			rejections := lnwire.NewRawFeatureVector(
+				lnwire.FeatureBit(lnwire.ChannelTypeRecordType),
+			)
+			reject := &lnwire.DynReject{
+				ChanID:           l.ChanID(),
+				UpdateRejections: *rejections,
+			}
+			if err := l.cfg.Peer.SendMessage(
+				false, reject,
+			); err != nil {
+				l.log.Errorf("unable to send dynamic "+
+					"commitment rejection: %v", err)
+			}
+
+			l.resumeQuiescence(nil)
+			return
+		}
+
+		ack := &lnwire.DynAck{
+			ChanID: l.ChanID(),
+		}
+		if err := l.cfg.Peer.SendMessage(false, ack); err != nil {
+			l.log.Errorf("unable to send dynamic commitment "+
+				"ack: %v", err)
+		}
+
+		// If the ack gets lost due to a disconnect, we'll resend it
+		// when reestablishing the channel.
+		q.upgrading = true
+		l.updateCommitTxOrFail()
+
+	case *lnwire.DynAck:
+		// The remote peer resends its ack when reestablishing the
+		// channel if we haven't signed a commitment of the new type
+		// yet, so we may have applied the upgrade already.
+		proposed := l.channel.ProposedCommitType()
+		if q.upgrading && proposed.IsNone() {
+			l.log.Debugf("Ignoring resent dynamic commitment ack")
+			return
+		}
+
+		// Once we've proposed an upgrade, we'll accept the ack even
+		// if we haven't proposed it again since reconnecting.
+		if q.upgrade == nil || proposed.IsNone() || q.upgrading {
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"unexpected dynamic commitment ack")
+			return
+		}
+
+		err := l.channel.UpgradeCommitType(q.upgrade.commitType)
+		if err != nil {
+			l.fail(LinkFailureError{code: ErrInternalError},
+				"unable to upgrade commitment type: %v", err)
+			return
+		}
+
+		q.upgrading = true
+		l.updateCommitTxOrFail()
+
+	case *lnwire.DynReject:
+		if !q.proposed || q.upgrading {
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"unexpected dynamic commitment rejection")
+			return
+		}
+
+		if err := l.channel.WithdrawCommitTypeProposal(); err != nil {
+			l.fail(LinkFailureError{code: ErrInternalError},
+				"unable to withdraw dynamic commitment "+
+					"proposal: %v", err)
+			return
+		}
+
+		l.resumeQuiescence(ErrUpgradeRejected)
+	}
+}
+
+// restoreQuiescence restores the state of an upgrade of the commitment type
+// that was interrupted by a disconnect. An upgrade both peers agreed on is
+// continued right away, while a proposal the remote peer hasn't answered yet
+// is made again. It returns false if the link failed.
+func (l *channelLink) restoreQuiescence() bool {
+	q := &l.quiescer
+
+	// If we went down while upgrading the commitment type of the channel,
+	// we'll continue the upgrade before sending any new updates.
+	if !l.channel.ChanTypeUpgradeComplete() {
+		l.log.Infof("Resuming commitment type upgrade")
+
+		q.upgrading = true
+
+		return !l.channel.OweCommitment() || l.updateCommitTxOrFail()
+	}
+
+	l.channel.ProposedCommitType().WhenSome(
+		func(commitType lnwallet.CommitmentType) {
+			l.log.Infof("Resuming proposal to upgrade commitment "+
+				"type to %v", commitType)
+
+			// Nobody waits for the result of the resumed
+			// proposal, so it is only buffered.
+			q.localInit = true
+			q.upgrade = &upgradeReq{
+				commitType: commitType,
+				err:        make(chan error, 1),
+			}
+		},
+	)
+
+	return true
+}
+
+// shouldResendDynAck returns true if we accepted an upgrade of the commitment
+// type that the remote peer hasn't received a commitment of the new type for,
+// according to its channel reestablish message. In that case, our ack may have
+// been lost as well, so we'll resend it ahead of any commitment.
+func (l *channelLink) shouldResendDynAck(
+	remoteSync *lnwire.ChannelReestablish) bool {
+
+	upgrades := l.channel.State().ChanTypeUpgrades
+	if len(upgrades) == 0 {
+		return false
+	}
+	upgrade := upgrades[len(upgrades)-1]
+
+	return remoteSync.NextLocalCommitHeight <= upgrade.RemoteHeight
+}
+
+// acceptDynPropose validates a dynamic commitment proposal of the remote peer
+// and upgrades the commitment type of the channel if it is acceptable.
+func (l *channelLink) acceptDynPropose(msg *lnwire.DynPropose) error {
+	if msg.ChannelType == nil {
+		return fmt.Errorf("%w: no channel type proposed",
+			lnwallet.ErrChanTypeUpgradeUnsupported)
+	}
+
+	commitType, err := lnwallet.CommitTypeForUpgrade(*msg.ChannelType)
+	if err != nil {
+		return err
+	}
+
+	return l.channel.UpgradeCommitType(commitType)
+}
+
+// deferRemoteAdds returns true if the processing of locked-in remote adds
+// needs to be deferred, as it may result in updates we can't send while the
+// channel is quiescent. The adds are processed once the channel resumes.
+func (l *channelLink) deferRemoteAdds(fwdPkg *channeldb.FwdPkg,
+	adds []*lnwallet.PaymentDescriptor) bool {
+
+	q := &l.quiescer
+	if !q.sentStfu && !q.upgrading {
+		return false
+	}
+
+	q.deferred = append(q.deferred, deferredAdds{
+		fwdPkg: fwdPkg,
+		adds:   adds,
+	})
+
+	return true
+}
+
+// isUpdateMsg returns true if the message proposes an update to the
+// commitments of the channel.
+func isUpdateMsg(msg lnwire.Message) bool {
+	switch msg.(type) {
+	case *lnwire.UpdateAddHTLC, *lnwire.UpdateFulfillHTLC,
+		*lnwire.UpdateFailHTLC, *lnwire.UpdateFailMalformedHTLC,
+		*lnwire.UpdateFee:
+
+		return true
+	}
+
+	return false
+}
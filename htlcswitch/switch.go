@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -15,16 +16,22 @@ import (
 	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/clock"
 	"github.com/ltcsuite/lnd/contractcourt"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/htlcswitch/hop"
 	"github.com/ltcsuite/lnd/kvdb"
 	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
 	"github.com/ltcsuite/lnd/ticker"
 	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/wire"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -82,6 +89,10 @@ var (
 	// DefaultDustThreshold is the default threshold after which we'll fail
 	// payments if they are dust. This is currently set to 500m msats.
 	DefaultDustThreshold = lnwire.MilliSatoshi(500_000_000)
+
+	// tracer records the spans of the switch and its links for the htlcs
+	// of local payments. Spans are only exported if tracing is enabled.
+	tracer = otel.Tracer("github.com/ltcsuite/lnd/htlcswitch")
 )
 
 // plexPacket encapsulates switch packet and adds error channel to receive
@@ -129,10 +140,21 @@ type ChanClose struct {
 type Config struct {
 	// FwdingLog is an interface that will be used by the switch to log
 	// forwarding events. A forwarding event happens each time a payment
-	// circuit is successfully completed. So when we forward an HTLC, and a
-	// settle is eventually received.
+	// circuit is completed. So when we forward an HTLC, and a settle or
+	// fail is eventually received, or when we reject an HTLC before
+	// forwarding it.
 	FwdingLog ForwardingLog
 
+	// LogFailedForwards indicates whether forwards that failed or were
+	// rejected are logged to the forwarding log. If false, only settled
+	// forwards are logged.
+	LogFailedForwards bool
+
+	// FetchNodeAlias is an optional function that returns the alias of
+	// the node with the given public key. It is used to record the aliases
+	// of the peers of a forward in the forwarding log.
+	FetchNodeAlias func(route.Vertex) (string, error)
+
 	// LocalChannelClose kicks-off the workflow to execute a cooperative or
 	// forced unilateral closure of the channel initiated by a local
 	// subsystem.
@@ -318,6 +340,13 @@ type Switch struct {
 	fwdEventMtx         sync.Mutex
 	pendingFwdingEvents []channeldb.ForwardingEvent
 
+	// pendingForwards tracks the HTLCs that are currently being forwarded
+	// by the switch, keyed by their incoming circuit key. It is only kept
+	// in memory, so forwards that were added before a restart don't have
+	// an entry.
+	pendingForwardsMtx sync.Mutex
+	pendingForwards    map[CircuitKey]pendingForward
+
 	// blockEpochStream is an active block epoch event stream backed by an
 	// active ChainNotifier instance. This will be used to retrieve the
 	// latest height of the chain.
@@ -378,6 +407,7 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		chanCloseRequests: make(chan *ChanClose),
 		resolutionMsgs:    make(chan *resolutionMsg),
 		resMsgStore:       resStore,
+		pendingForwards:   make(map[CircuitKey]pendingForward),
 		quit:              make(chan struct{}),
 	}
 
@@ -521,8 +551,38 @@ func (s *Switch) CleanStore(keepPids map[uint64]struct{}) error {
 // SendHTLC is used by other subsystems which aren't belong to htlc switch
 // package in order to send the htlc update. The attemptID used MUST be unique
 // for this HTLC, and MUST be used only once, otherwise the switch might reject
-// it.
-func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID, attemptID uint64,
+// it. The handling of the htlc by the switch and the outgoing link is recorded
+// as part of the trace carried by the passed context.
+func (s *Switch) SendHTLC(ctx context.Context, firstHop lnwire.ShortChannelID,
+	attemptID uint64, htlc *lnwire.UpdateAddHTLC) error {
+
+	_, span := tracer.Start(
+		ctx, "htlcswitch.Switch.SendHTLC",
+		trace.WithAttributes(
+			attribute.Int64("attempt_id", int64(attemptID)),
+			attribute.String("first_hop", firstHop.String()),
+			attribute.String(
+				"payment_hash",
+				lntypes.Hash(htlc.PaymentHash).String(),
+			),
+		),
+	)
+	defer span.End()
+
+	err := s.sendHTLC(span.SpanContext(), firstHop, attemptID, htlc)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// sendHTLC hands the htlc of a local payment to the link of its first hop. The
+// span context is passed along with the htlc, so that the link can record its
+// processing as part of the same trace.
+func (s *Switch) sendHTLC(spanContext trace.SpanContext,
+	firstHop lnwire.ShortChannelID, attemptID uint64,
 	htlc *lnwire.UpdateAddHTLC) error {
 
 	// Generate and send new update packet, if error will be received on
@@ -534,6 +594,7 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID, attemptID uint64,
 		outgoingChanID: firstHop,
 		htlc:           htlc,
 		amount:         htlc.Amount,
+		spanContext:    spanContext,
 	}
 
 	// Attempt to fetch the target link before creating a circuit so that
@@ -648,6 +709,23 @@ func (s *Switch) IsForwardedHTLC(chanID lnwire.ShortChannelID,
 	return circuit != nil && circuit.Incoming.ChanID != hop.Source
 }
 
+// QueryIncomingCircuit returns the circuit key of the incoming htlc of a
+// forwarded htlc, identified by its outgoing channel id and htlc index. False
+// is returned if the htlc isn't a known forward.
+func (s *Switch) QueryIncomingCircuit(chanID lnwire.ShortChannelID,
+	htlcIndex uint64) (models.CircuitKey, bool) {
+
+	circuit := s.circuits.LookupOpenCircuit(models.CircuitKey{
+		ChanID: chanID,
+		HtlcID: htlcIndex,
+	})
+	if circuit == nil || circuit.Incoming.ChanID == hop.Source {
+		return models.CircuitKey{}, false
+	}
+
+	return circuit.Incoming, true
+}
+
 // ForwardPackets adds a list of packets to the switch for processing. Fails
 // and settles are added on a first past, simultaneously constructing circuits
 // for any adds. After persisting the circuits, another pass of the adds is
@@ -737,6 +815,7 @@ func (s *Switch) ForwardPackets(linkQuit chan struct{},
 	for _, packet := range addBatch {
 		switch {
 		case len(actions.Adds) > 0 && packet.circuit == actions.Adds[0]:
+			s.trackForward(packet.inKey(), packet.outgoingChanID)
 			addedPackets = append(addedPackets, packet)
 			actions.Adds = actions.Adds[1:]
 
@@ -744,6 +823,7 @@ func (s *Switch) ForwardPackets(linkQuit chan struct{},
 			actions.Drops = actions.Drops[1:]
 
 		case len(actions.Fails) > 0 && packet.circuit == actions.Fails[0]:
+			s.trackForward(packet.inKey(), packet.outgoingChanID)
 			failedPackets = append(failedPackets, packet)
 			actions.Fails = actions.Fails[1:]
 		}
@@ -1148,6 +1228,12 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		interfaceLinks, _ := s.getLinks(targetPeerKey)
 		s.indexMtx.RUnlock()
 
+		// If the outgoing channel was explicitly chosen, we won't
+		// consider any of the other links with the same peer.
+		if packet.strictOutgoingChan {
+			interfaceLinks = []ChannelLink{targetLink}
+		}
+
 		// We'll keep track of any HTLC failures during the link
 		// selection process. This way we can return the error for
 		// precise link that the sender selected, while optimistically
@@ -1174,7 +1260,8 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				currentHeight := atomic.LoadUint32(&s.bestHeight)
 				failure = link.CheckHtlcForward(
 					htlc.PaymentHash, packet.incomingAmount,
-					packet.amount, packet.incomingTimeout,
+					packet.amount, packet.inboundFee,
+					packet.incomingTimeout,
 					packet.outgoingTimeout, currentHeight,
 					packet.originalOutgoingChanID,
 				)
@@ -1199,6 +1286,9 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			// HTLC so we couldn't forward it. So we'll try to look
 			// up the error that came from the source.
 			linkErr, ok := linkErrs[packet.outgoingChanID]
+			if !ok && packet.strictOutgoingChan {
+				linkErr, ok = linkErrs[targetLink.ShortChanID()]
+			}
 			if !ok {
 				// If we can't find the error of the source,
 				// then we'll return an unknown next peer,
@@ -1274,6 +1364,8 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		// Send the packet to the destination channel link which
 		// manages the channel.
 		packet.outgoingChanID = destination.ShortChanID()
+		s.trackForward(packet.inKey(), packet.outgoingChanID)
+
 		return destination.handleSwitchPacket(packet)
 
 	case *lnwire.UpdateFailHTLC, *lnwire.UpdateFulfillHTLC:
@@ -1290,7 +1382,13 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return nil
 		}
 
+		// The incoming link needs the error encrypter of the circuit
+		// to convert the failures of HTLCs in a blinded route.
 		fail, isFail := htlc.(*lnwire.UpdateFailHTLC)
+		if isFail {
+			packet.obfuscator = circuit.ErrorEncrypter
+		}
+
 		if isFail && !packet.hasSource {
 			switch {
 			// No message to encrypt, locally sourced payment.
@@ -1333,33 +1431,24 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 					fail.Reason,
 				)
 			}
-		} else if !isFail && circuit.Outgoing != nil {
-			// If this is an HTLC settle, and it wasn't from a
-			// locally initiated HTLC, then we'll log a forwarding
-			// event so we can flush it to disk later.
-			//
-			// TODO(roasbeef): only do this once link actually
-			// fully settles?
-			localHTLC := packet.incomingChanID == hop.Source
-			if !localHTLC {
-				log.Infof("Forwarded HTLC(%x) of %v (fee: %v) "+
-					"from IncomingChanID(%v) to OutgoingChanID(%v)",
-					circuit.PaymentHash[:], circuit.OutgoingAmount,
-					circuit.IncomingAmount-circuit.OutgoingAmount,
-					circuit.Incoming.ChanID, circuit.Outgoing.ChanID)
-				s.fwdEventMtx.Lock()
-				s.pendingFwdingEvents = append(
-					s.pendingFwdingEvents,
-					channeldb.ForwardingEvent{
-						Timestamp:      time.Now(),
-						IncomingChanID: circuit.Incoming.ChanID,
-						OutgoingChanID: circuit.Outgoing.ChanID,
-						AmtIn:          circuit.IncomingAmount,
-						AmtOut:         circuit.OutgoingAmount,
-					},
-				)
-				s.fwdEventMtx.Unlock()
-			}
+		} else if !isFail && circuit.Outgoing != nil &&
+			packet.incomingChanID != hop.Source {
+
+			log.Infof("Forwarded HTLC(%x) of %v (fee: %v) "+
+				"from IncomingChanID(%v) to OutgoingChanID(%v)",
+				circuit.PaymentHash[:], circuit.OutgoingAmount,
+				circuit.IncomingAmount-circuit.OutgoingAmount,
+				circuit.Incoming.ChanID, circuit.Outgoing.ChanID)
+		}
+
+		// If this HTLC wasn't initiated locally, then we'll log a
+		// forwarding event for its settle or fail so we can flush it to
+		// disk later.
+		//
+		// TODO(roasbeef): only do this once link actually fully
+		// settles?
+		if packet.incomingChanID != hop.Source {
+			s.logResolvedForward(packet, circuit, isFail)
 		}
 
 		// A blank IncomingChanID in a circuit indicates that it is a pending
@@ -1466,6 +1555,29 @@ func (s *Switch) failAddPacket(packet *htlcPacket, failure *LinkError) error {
 
 	log.Error(failure.Error())
 
+	// Unless this is a local payment, we'll log a forwarding event for
+	// the rejected HTLC if failed forwards are logged.
+	switch {
+	// Local payments aren't forwards, so there's nothing to log.
+	case packet.incomingChanID == hop.Source:
+
+	case s.cfg.LogFailedForwards:
+		s.logForwardingEvent(packet.inKey(), channeldb.ForwardingEvent{
+			IncomingChanID: packet.incomingChanID,
+			OutgoingChanID: packet.outgoingChanID,
+			AmtIn:          packet.incomingAmount,
+			AmtOut:         packet.amount,
+			IncomingHtlcID: fn.Some(packet.incomingHTLCID),
+			Outcome:        channeldb.ForwardingEventRejected,
+			FailureSource:  channeldb.ForwardingFailureSourceLocal,
+			FailureCode:    failure.WireMessage().Code(),
+			FailureReason:  failureReason(failure),
+		})
+
+	default:
+		s.untrackForward(packet.inKey())
+	}
+
 	// Create a failure packet for this htlc. The the full set of
 	// information about the htlc failure is included so that they can
 	// be included in link failure notifications.
@@ -1480,6 +1592,7 @@ func (s *Switch) failAddPacket(packet *htlcPacket, failure *LinkError) error {
 		incomingTimeout: packet.incomingTimeout,
 		outgoingTimeout: packet.outgoingTimeout,
 		circuit:         packet.circuit,
+		obfuscator:      packet.obfuscator,
 		linkFailure:     failure,
 		htlc: &lnwire.UpdateFailHTLC{
 			Reason: reason,
@@ -1499,6 +1612,168 @@ func (s *Switch) failAddPacket(packet *htlcPacket, failure *LinkError) error {
 	return failure
 }
 
+// pendingForward holds the details of an HTLC that is being forwarded by the
+// switch which aren't part of its payment circuit.
+type pendingForward struct {
+	// addedTime is the time the HTLC was first handed to the switch.
+	addedTime time.Time
+
+	// outgoingChanID is the outgoing channel selected for the HTLC.
+	outgoingChanID lnwire.ShortChannelID
+}
+
+// trackForward records the outgoing channel of the HTLC with the given
+// incoming circuit key. The add time of the HTLC is recorded the first time it
+// is seen by the switch.
+func (s *Switch) trackForward(inKey CircuitKey,
+	outgoingChanID lnwire.ShortChannelID) {
+
+	s.pendingForwardsMtx.Lock()
+	defer s.pendingForwardsMtx.Unlock()
+
+	fwd, ok := s.pendingForwards[inKey]
+	if !ok {
+		fwd.addedTime = time.Now()
+	}
+	fwd.outgoingChanID = outgoingChanID
+
+	s.pendingForwards[inKey] = fwd
+}
+
+// untrackForward stops tracking the HTLC with the given incoming circuit key
+// without logging a forwarding event for it.
+func (s *Switch) untrackForward(inKey CircuitKey) {
+	s.pendingForwardsMtx.Lock()
+	delete(s.pendingForwards, inKey)
+	s.pendingForwardsMtx.Unlock()
+}
+
+// logResolvedForward logs a forwarding event for an HTLC that was forwarded by
+// us and is now being settled or failed back to the incoming link. Failed
+// forwards are only logged if enabled in the config.
+func (s *Switch) logResolvedForward(packet *htlcPacket,
+	circuit *PaymentCircuit, isFail bool) {
+
+	// A settle is only expected for HTLCs that were added to the outgoing
+	// channel. In any case, we'll stop tracking the HTLC as it is now
+	// resolved.
+	if (!isFail && circuit.Outgoing == nil) ||
+		(isFail && !s.cfg.LogFailedForwards) {
+
+		s.untrackForward(circuit.Incoming)
+
+		return
+	}
+
+	event := channeldb.ForwardingEvent{
+		IncomingChanID: circuit.Incoming.ChanID,
+		AmtIn:          circuit.IncomingAmount,
+		AmtOut:         circuit.OutgoingAmount,
+		IncomingHtlcID: fn.Some(circuit.Incoming.HtlcID),
+	}
+	if circuit.Outgoing != nil {
+		event.OutgoingChanID = circuit.Outgoing.ChanID
+		event.OutgoingHtlcID = fn.Some(circuit.Outgoing.HtlcID)
+	}
+
+	switch {
+	// Settled forwards are logged with the default outcome.
+	case !isFail:
+
+	// If the packet has its source, the HTLC was failed by the outgoing
+	// link before it was added to the outgoing channel.
+	case packet.hasSource:
+		event.Outcome = channeldb.ForwardingEventRejected
+		event.FailureSource = channeldb.ForwardingFailureSourceLocal
+		event.OutgoingHtlcID = fn.None[uint64]()
+
+		if packet.linkFailure != nil {
+			failure := packet.linkFailure
+			event.FailureCode = failure.WireMessage().Code()
+			event.FailureReason = failureReason(failure)
+		}
+
+	// A resolution message means the outgoing HTLC was resolved on chain,
+	// so we fail it back ourselves.
+	case packet.isResolution:
+		event.Outcome = channeldb.ForwardingEventFailed
+		event.FailureSource = channeldb.ForwardingFailureSourceLocal
+		event.FailureCode = lnwire.CodePermanentChannelFailure
+		event.FailureReason = OutgoingFailureOnChainTimeout.
+			FailureString()
+
+	// The remote party failed the HTLC as it couldn't parse the onion.
+	case packet.convertedError:
+		event.Outcome = channeldb.ForwardingEventFailed
+		event.FailureSource = channeldb.ForwardingFailureSourceRemote
+
+	// Otherwise, the failure was created downstream and is encrypted for
+	// the sender, so we don't know its reason.
+	default:
+		event.Outcome = channeldb.ForwardingEventFailed
+		event.FailureSource = channeldb.ForwardingFailureSourceRemote
+	}
+
+	s.logForwardingEvent(circuit.Incoming, event)
+}
+
+// failureReason returns the description of the failure detail of the given
+// link error to record in the forwarding log. Unlike the full error string, it
+// is drawn from a fixed set of descriptions. An empty string is returned if
+// the error has no failure detail.
+func failureReason(failure *LinkError) string {
+	if failure.FailureDetail == nil {
+		return ""
+	}
+
+	return failure.FailureDetail.FailureString()
+}
+
+// logForwardingEvent completes the given forwarding event with the details
+// tracked for the HTLC with the given incoming circuit key and adds it to the
+// set of pending forwarding events that will be flushed to disk later.
+func (s *Switch) logForwardingEvent(inKey CircuitKey,
+	event channeldb.ForwardingEvent) {
+
+	event.Timestamp = time.Now()
+
+	s.pendingForwardsMtx.Lock()
+	fwd, ok := s.pendingForwards[inKey]
+	delete(s.pendingForwards, inKey)
+	s.pendingForwardsMtx.Unlock()
+
+	if ok {
+		event.AddedTime = fwd.addedTime
+
+		// HTLCs that were rejected by the outgoing link don't carry
+		// their outgoing channel, so we'll use the one we selected.
+		if event.OutgoingChanID == (lnwire.ShortChannelID{}) {
+			event.OutgoingChanID = fwd.outgoingChanID
+		}
+	}
+
+	// We'll record the peers of both channels while their links are
+	// still around. The peers of links that are no longer active are
+	// left unknown.
+	peerOf := func(chanID lnwire.ShortChannelID) fn.Option[route.Vertex] {
+		link, err := s.getLinkByShortID(chanID)
+		if err != nil {
+			return fn.None[route.Vertex]()
+		}
+
+		return fn.Some(route.Vertex(link.Peer().PubKey()))
+	}
+
+	s.indexMtx.RLock()
+	event.IncomingPeer = peerOf(event.IncomingChanID)
+	event.OutgoingPeer = peerOf(event.OutgoingChanID)
+	s.indexMtx.RUnlock()
+
+	s.fwdEventMtx.Lock()
+	s.pendingFwdingEvents = append(s.pendingFwdingEvents, event)
+	s.fwdEventMtx.Unlock()
+}
+
 // closeCircuit accepts a settle or fail htlc and the associated htlc packet and
 // attempts to determine the source that forwarded this htlc. This method will
 // set the incoming chan and htlc ID of the given packet if the source was
@@ -2424,6 +2699,48 @@ func (s *Switch) GetLinkByShortID(chanID lnwire.ShortChannelID) (ChannelLink,
 	return link, nil
 }
 
+// checkModifiedForward ensures that a forward which was modified by an
+// interceptor still leaves through a channel with the same peer as the original
+// forward, and that the modified htlc satisfies the policy of that channel.
+func (s *Switch) checkModifiedForward(orig, modified *htlcPacket) error {
+	htlc, ok := modified.htlc.(*lnwire.UpdateAddHTLC)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", modified.htlc)
+	}
+
+	origLink, err := s.GetLinkByShortID(orig.outgoingChanID)
+	if err != nil {
+		return fmt.Errorf("unable to find outgoing channel %v: %w",
+			orig.outgoingChanID, err)
+	}
+
+	link, err := s.GetLinkByShortID(modified.outgoingChanID)
+	if err != nil {
+		return fmt.Errorf("unable to find outgoing channel %v: %w",
+			modified.outgoingChanID, err)
+	}
+
+	if link.Peer().PubKey() != origLink.Peer().PubKey() {
+		return fmt.Errorf("outgoing channel %v is not with the same "+
+			"peer as channel %v", modified.outgoingChanID,
+			orig.outgoingChanID)
+	}
+
+	currentHeight := atomic.LoadUint32(&s.bestHeight)
+	linkErr := link.CheckHtlcForward(
+		htlc.PaymentHash, modified.incomingAmount, modified.amount,
+		modified.inboundFee, modified.incomingTimeout,
+		modified.outgoingTimeout, currentHeight,
+		modified.outgoingChanID,
+	)
+	if linkErr != nil {
+		return fmt.Errorf("modified forward violates policy of "+
+			"channel %v: %w", modified.outgoingChanID, linkErr)
+	}
+
+	return nil
+}
+
 // getLinkByShortID attempts to return the link which possesses the target
 // short channel ID.
 //
@@ -2741,6 +3058,38 @@ func (s *Switch) FlushForwardingEvents() error {
 	s.pendingFwdingEvents = s.pendingFwdingEvents[:0]
 	s.fwdEventMtx.Unlock()
 
+	// Before writing the events, we'll add the current aliases of their
+	// peers, if we're able to look them up.
+	if s.cfg.FetchNodeAlias != nil {
+		aliases := make(map[route.Vertex]string)
+		aliasOf := func(peer route.Vertex) string {
+			if alias, ok := aliases[peer]; ok {
+				return alias
+			}
+
+			// The alias is best effort, so we'll just leave it
+			// empty if the node is unknown.
+			alias, err := s.cfg.FetchNodeAlias(peer)
+			if err != nil {
+				log.Debugf("Unable to fetch alias of peer "+
+					"%v: %v", peer, err)
+			}
+			aliases[peer] = alias
+
+			return alias
+		}
+
+		for i := range events {
+			event := &events[i]
+			event.IncomingPeer.WhenSome(func(peer route.Vertex) {
+				event.IncomingPeerAlias = aliasOf(peer)
+			})
+			event.OutgoingPeer.WhenSome(func(peer route.Vertex) {
+				event.OutgoingPeerAlias = aliasOf(peer)
+			})
+		}
+	}
+
 	// Finally, we'll write out the copied events to the persistent
 	// forwarding log.
 	return s.cfg.FwdingLog.AddForwardingEvents(events)
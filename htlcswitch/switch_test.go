@@ -1,6 +1,7 @@
 package htlcswitch
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
@@ -16,14 +17,19 @@ import (
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/contractcourt"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/htlcswitch/hodl"
 	"github.com/ltcsuite/lnd/htlcswitch/hop"
 	"github.com/ltcsuite/lnd/lntest/mock"
 	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
 	"github.com/ltcsuite/lnd/ticker"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 var zeroCircuit = models.CircuitKey{}
@@ -724,7 +730,7 @@ func testSwitchSendHtlcMapping(t *testing.T, zeroConf, useAlias bool, alias,
 		Amount:      1,
 	}
 
-	err = s.SendHTLC(outgoingSCID, 0, htlc)
+	err = s.SendHTLC(context.Background(), outgoingSCID, 0, htlc)
 	require.NoError(t, err)
 }
 
@@ -2382,7 +2388,9 @@ func testSkipLinkLocalForward(t *testing.T, eligible bool,
 	// We'll attempt to send out a new HTLC that has Alice as the first
 	// outgoing link. This should fail as Alice isn't yet able to forward
 	// any active HTLC's.
-	err = s.SendHTLC(aliceChannelLink.ShortChanID(), 0, addMsg)
+	err = s.SendHTLC(
+		context.Background(), aliceChannelLink.ShortChanID(), 0, addMsg,
+	)
 	if err == nil {
 		t.Fatalf("local forward should fail due to inactive link")
 	}
@@ -2711,7 +2719,8 @@ func TestSwitchSendPayment(t *testing.T) {
 	errChan := make(chan error)
 	go func() {
 		err := s.SendHTLC(
-			aliceChannelLink.ShortChanID(), paymentID, update,
+			context.Background(), aliceChannelLink.ShortChanID(),
+			paymentID, update,
 		)
 		if err != nil {
 			errChan <- err
@@ -3000,6 +3009,180 @@ func TestMultiHopPaymentForwardingEvents(t *testing.T) {
 			t.Fatalf("outgoing amt mismatch: expected %v, got %v",
 				event.AmtOut, finalAmt)
 		}
+
+		// The forwards should be recorded as settled, along with the
+		// details of the HTLCs and the peers they were forwarded
+		// between.
+		require.Equal(t, channeldb.ForwardingEventSettled, event.Outcome)
+		require.True(t, event.IncomingHtlcID.IsSome())
+		require.True(t, event.OutgoingHtlcID.IsSome())
+		require.False(t, event.AddedTime.IsZero())
+		require.Positive(t, event.ResolutionLatency())
+		require.Equal(
+			t, fn.Some(route.Vertex(n.aliceServer.id)),
+			event.IncomingPeer,
+		)
+		require.Equal(
+			t, fn.Some(route.Vertex(n.carolServer.id)),
+			event.OutgoingPeer,
+		)
+	}
+}
+
+// TestMultiHopPaymentRejectedForwardingEvents tests that a forward that is
+// rejected by the switch is logged as a rejected forwarding event, but only if
+// failed forwards are logged.
+func TestMultiHopPaymentRejectedForwardingEvents(t *testing.T) {
+	t.Parallel()
+
+	for _, logFailedForwards := range []bool{true, false} {
+		logFailedForwards := logFailedForwards
+
+		name := fmt.Sprintf("log failed forwards %v", logFailedForwards)
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testRejectedForwardingEvents(t, logFailedForwards)
+		})
+	}
+}
+
+// testRejectedForwardingEvents asserts that a forward rejected by Bob is
+// logged if failed forwards are logged, and forgotten otherwise.
+func testRejectedForwardingEvents(t *testing.T, logFailedForwards bool) {
+	channels, _, err := createClusterChannels(
+		t, ltcutil.SatoshiPerBitcoin*3, ltcutil.SatoshiPerBitcoin*5,
+	)
+	require.NoError(t, err, "unable to create channel")
+
+	n := newThreeHopNetwork(t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight)
+
+	// We'll configure Bob to reject all forwards, so that the payment
+	// from Alice to Carol fails at Bob's switch.
+	n.bobServer.htlcSwitch.cfg.RejectHTLC = true
+	n.bobServer.htlcSwitch.cfg.LogFailedForwards = logFailedForwards
+
+	if err := n.start(); err != nil {
+		t.Fatalf("unable to start three hop network: %v", err)
+	}
+
+	finalAmt := lnwire.NewMSatFromSatoshis(100000)
+	htlcAmt, totalTimelock, hops := generateHops(
+		finalAmt, testStartingHeight, n.firstBobChannelLink,
+		n.carolChannelLink,
+	)
+	firstHop := n.firstBobChannelLink.ShortChanID()
+	_, err = makePayment(
+		n.aliceServer, n.carolServer, firstHop, hops, finalAmt,
+		htlcAmt, totalTimelock,
+	).Wait(30 * time.Second)
+	require.Error(t, err, "payment should have been rejected")
+
+	// Stopping the network flushes the pending forwarding events.
+	n.stop()
+
+	// Bob no longer tracks the rejected HTLC, whether it was logged or
+	// not.
+	bobSwitch := n.bobServer.htlcSwitch
+	bobSwitch.pendingForwardsMtx.Lock()
+	require.Empty(t, bobSwitch.pendingForwards)
+	bobSwitch.pendingForwardsMtx.Unlock()
+
+	bobLog, ok := bobSwitch.cfg.FwdingLog.(*mockForwardingLog)
+	require.True(t, ok, "mockForwardingLog assertion failed")
+
+	bobLog.Lock()
+	defer bobLog.Unlock()
+
+	if !logFailedForwards {
+		require.Empty(t, bobLog.events)
+
+		return
+	}
+	require.Len(t, bobLog.events, 1)
+
+	for _, event := range bobLog.events {
+		require.Equal(
+			t, n.aliceChannelLink.ShortChanID(),
+			event.IncomingChanID,
+		)
+		require.Equal(
+			t, n.carolChannelLink.ShortChanID(),
+			event.OutgoingChanID,
+		)
+		require.Equal(t, htlcAmt, event.AmtIn)
+		require.Equal(t, finalAmt, event.AmtOut)
+		require.Equal(
+			t, channeldb.ForwardingEventRejected, event.Outcome,
+		)
+		require.Equal(
+			t, channeldb.ForwardingFailureSourceLocal,
+			event.FailureSource,
+		)
+		require.Equal(
+			t, lnwire.CodeChannelDisabled, event.FailureCode,
+		)
+		require.Equal(
+			t, OutgoingFailureForwardsDisabled.FailureString(),
+			event.FailureReason,
+		)
+		require.True(t, event.IncomingHtlcID.IsSome())
+		require.True(t, event.OutgoingHtlcID.IsNone())
+		require.False(t, event.AddedTime.IsZero())
+		require.Equal(
+			t, fn.Some(route.Vertex(n.aliceServer.id)),
+			event.IncomingPeer,
+		)
+	}
+}
+
+// TestLogResolvedForwardUntracks asserts that the switch stops tracking a
+// resolved forward, even if no forwarding event is logged for it.
+func TestLogResolvedForwardUntracks(t *testing.T) {
+	t.Parallel()
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err, "unable to init switch")
+
+	inKey := CircuitKey{
+		ChanID: lnwire.NewShortChanIDFromInt(1),
+		HtlcID: 2,
+	}
+	outgoingChanID := lnwire.NewShortChanIDFromInt(3)
+
+	testCases := []struct {
+		name    string
+		circuit *PaymentCircuit
+		isFail  bool
+	}{{
+		name: "settle without outgoing htlc",
+		circuit: &PaymentCircuit{
+			Incoming: inKey,
+		},
+	}, {
+		name: "failed forward not logged",
+		circuit: &PaymentCircuit{
+			Incoming: inKey,
+			Outgoing: &CircuitKey{
+				ChanID: outgoingChanID,
+				HtlcID: 4,
+			},
+		},
+		isFail: true,
+	}}
+
+	for _, tc := range testCases {
+		s.trackForward(inKey, outgoingChanID)
+		s.logResolvedForward(&htlcPacket{}, tc.circuit, tc.isFail)
+
+		s.pendingForwardsMtx.Lock()
+		require.Empty(t, s.pendingForwards, tc.name)
+		s.pendingForwardsMtx.Unlock()
+
+		s.fwdEventMtx.Lock()
+		require.Empty(t, s.pendingFwdingEvents, tc.name)
+		s.fwdEventMtx.Unlock()
 	}
 }
 
@@ -3218,7 +3401,8 @@ func TestInvalidFailure(t *testing.T) {
 
 	// Send the request.
 	err = s.SendHTLC(
-		aliceChannelLink.ShortChanID(), paymentID, update,
+		context.Background(), aliceChannelLink.ShortChanID(),
+		paymentID, update,
 	)
 	require.NoError(t, err, "unable to send payment")
 
@@ -3568,7 +3752,8 @@ func (n *threeHopNetwork) sendThreeHopPayment(t *testing.T) (*lnwire.UpdateAddHT
 	require.NoError(t, err, "unable to add invoice in carol registry")
 
 	if err := n.aliceServer.htlcSwitch.SendHTLC(
-		n.firstBobChannelLink.ShortChanID(), pid, htlc,
+		context.Background(), n.firstBobChannelLink.ShortChanID(), pid,
+		htlc,
 	); err != nil {
 		t.Fatalf("could not send htlc")
 	}
@@ -4194,6 +4379,242 @@ func TestSwitchHoldForward(t *testing.T) {
 	}
 }
 
+// TestSwitchHoldForwardResumeModified tests that an intercepted forward can be
+// resumed with a modified amount, outgoing channel and custom records, and
+// that invalid modifications are rejected without releasing the forward.
+func TestSwitchHoldForwardResumeModified(t *testing.T) {
+	t.Parallel()
+
+	c := newInterceptableSwitchTestContext(t)
+	defer c.finish()
+
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight}
+
+	switchForwardInterceptor, err := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:             c.s,
+			CltvRejectDelta:    c.cltvRejectDelta,
+			CltvInterceptDelta: c.cltvInterceptDelta,
+			Notifier:           notifier,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, switchForwardInterceptor.Start())
+	defer func() {
+		require.NoError(t, switchForwardInterceptor.Stop())
+	}()
+
+	switchForwardInterceptor.SetInterceptor(
+		c.forwardInterceptor.InterceptForwardHtlc,
+	)
+	linkQuit := make(chan struct{})
+
+	createPacket := func() *htlcPacket {
+		packet := c.createTestPacket()
+		packet.incomingAmount = 1000
+		packet.amount = 900
+		packet.htlc.(*lnwire.UpdateAddHTLC).Amount = 900
+
+		return packet
+	}
+
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, createPacket(),
+	))
+	key := c.forwardInterceptor.getIntercepted().IncomingCircuit
+
+	// resolveRejected resumes the forward with the given modifications and
+	// asserts that they are rejected. The forward must be offered to the
+	// interceptor again along with the reason.
+	resolveRejected := func(mods FwdModifications) error {
+		t.Helper()
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- switchForwardInterceptor.Resolve(
+				&FwdResolution{
+					Key:           key,
+					Action:        FwdActionResumeModified,
+					Modifications: mods,
+				},
+			)
+		}()
+
+		packet := c.forwardInterceptor.getIntercepted()
+		require.Equal(t, key, packet.IncomingCircuit)
+		require.ErrorIs(
+			t, packet.ModificationErr, ErrFwdModificationRejected,
+		)
+
+		var err error
+		select {
+		case err = <-errChan:
+		case <-time.After(time.Second):
+			require.Fail(t, "resolve timeout")
+		}
+		require.ErrorIs(t, err, ErrFwdModificationRejected)
+		require.Equal(t, packet.ModificationErr, err)
+
+		return err
+	}
+
+	// Custom records outside of the custom range are rejected.
+	resolveRejected(FwdModifications{
+		CustomRecords: lnwire.CustomRecords{1: {1}},
+	})
+
+	// A channel with a different peer is rejected.
+	err = resolveRejected(FwdModifications{
+		OutgoingChanID: fn.Some(c.aliceChannelLink.ShortChanID()),
+	})
+	require.ErrorContains(t, err, "not with the same peer")
+
+	// An amount that violates the policy of the outgoing channel is
+	// rejected.
+	c.bobChannelLink.checkHtlcForwardResult = NewLinkError(
+		&lnwire.FailFeeInsufficient{},
+	)
+	err = resolveRejected(FwdModifications{
+		OutgoingAmount: fn.Some(lnwire.MilliSatoshi(990)),
+	})
+	require.ErrorContains(t, err, "violates policy")
+	c.bobChannelLink.checkHtlcForwardResult = nil
+
+	// None of the rejected modifications should have been forwarded.
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	assertNumCircuits(t, c.s, 0, 0)
+
+	// The forward is still held, so it can be resumed with a valid set of
+	// modifications.
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:    key,
+		Action: FwdActionResumeModified,
+		Modifications: FwdModifications{
+			OutgoingAmount: fn.Some(lnwire.MilliSatoshi(800)),
+			CustomRecords: lnwire.CustomRecords{
+				lnwire.MinCustomRecordsTlvType: {1, 2, 3},
+			},
+		},
+	}))
+
+	receivedPkt := assertOutgoingLinkReceive(t, c.bobChannelLink, true)
+	require.EqualValues(t, 800, receivedPkt.amount)
+
+	htlc, ok := receivedPkt.htlc.(*lnwire.UpdateAddHTLC)
+	require.True(t, ok)
+	require.EqualValues(t, 800, htlc.Amount)
+	require.Equal(t, lnwire.CustomRecords{
+		lnwire.MinCustomRecordsTlvType: {1, 2, 3},
+	}, htlc.CustomRecords)
+	assertNumCircuits(t, c.s, 1, 1)
+
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false,
+		c.createSettlePacket(receivedPkt.outgoingHTLCID),
+	))
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+	assertNumCircuits(t, c.s, 0, 0)
+
+	// Finally, resume a forward over a second channel with bob. Both
+	// channels can carry the htlc, so the switch must stick to the
+	// requested one rather than pick one at random.
+	chanID, bobChanID2 := genID()
+	bobChannelLink2 := newMockChannelLink(
+		c.s, chanID, bobChanID2, emptyScid, c.bobChannelLink.peer,
+		true, false, false, false,
+	)
+	require.NoError(t, c.s.AddLink(bobChannelLink2))
+
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, createPacket(),
+	))
+	key = c.forwardInterceptor.getIntercepted().IncomingCircuit
+
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:    key,
+		Action: FwdActionResumeModified,
+		Modifications: FwdModifications{
+			OutgoingChanID: fn.Some(bobChanID2),
+		},
+	}))
+
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	receivedPkt = assertOutgoingLinkReceive(t, bobChannelLink2, true)
+	require.Equal(t, bobChanID2, receivedPkt.outgoingChanID)
+	assertNumCircuits(t, c.s, 1, 1)
+}
+
+// TestInterceptableSwitchResumeModifiedStrictChan asserts that a forward that
+// is resumed over an explicitly chosen outgoing channel is failed with the
+// error of that channel if it can't carry the htlc, even if another channel
+// with the same peer could.
+func TestInterceptableSwitchResumeModifiedStrictChan(t *testing.T) {
+	t.Parallel()
+
+	c := newInterceptableSwitchTestContext(t)
+	defer c.finish()
+
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight}
+
+	switchForwardInterceptor, err := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:             c.s,
+			CltvRejectDelta:    c.cltvRejectDelta,
+			CltvInterceptDelta: c.cltvInterceptDelta,
+			Notifier:           notifier,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, switchForwardInterceptor.Start())
+
+	switchForwardInterceptor.SetInterceptor(
+		c.forwardInterceptor.InterceptForwardHtlc,
+	)
+	linkQuit := make(chan struct{})
+
+	// The requested channel isn't eligible to forward while the original
+	// one would accept the htlc.
+	chanID, bobChanID2 := genID()
+	bobChannelLink2 := newMockChannelLink(
+		c.s, chanID, bobChanID2, emptyScid, c.bobChannelLink.peer,
+		false, false, false, false,
+	)
+	require.NoError(t, c.s.AddLink(bobChannelLink2))
+
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, c.createTestPacket(),
+	))
+	key := c.forwardInterceptor.getIntercepted().IncomingCircuit
+
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:    key,
+		Action: FwdActionResumeModified,
+		Modifications: FwdModifications{
+			OutgoingChanID: fn.Some(bobChanID2),
+		},
+	}))
+
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	assertOutgoingLinkReceive(t, bobChannelLink2, false)
+
+	failPkt := assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+	require.NotNil(t, failPkt.linkFailure)
+	require.IsType(
+		t, &lnwire.FailUnknownNextPeer{},
+		failPkt.linkFailure.WireMessage(),
+	)
+	require.Equal(
+		t, OutgoingFailureLinkNotEligible,
+		failPkt.linkFailure.FailureDetail,
+	)
+}
+
 func TestInterceptableSwitchWatchDog(t *testing.T) {
 	t.Parallel()
 
@@ -4355,7 +4776,8 @@ func TestSwitchDustForwarding(t *testing.T) {
 
 	// Assert that the HTLC is failed due to the dust threshold.
 	err = n.bobServer.htlcSwitch.SendHTLC(
-		aliceBobFirstHop, uint64(357), failingHtlc,
+		context.Background(), aliceBobFirstHop, uint64(357),
+		failingHtlc,
 	)
 	require.ErrorIs(t, err, errDustThresholdExceeded)
 
@@ -4382,7 +4804,7 @@ func TestSwitchDustForwarding(t *testing.T) {
 	// Assert that SendHTLC succeeds and evaluateDustThreshold returns
 	// false.
 	err = n.bobServer.htlcSwitch.SendHTLC(
-		aliceBobFirstHop, uint64(358), nondustHtlc,
+		context.Background(), aliceBobFirstHop, uint64(358), nondustHtlc,
 	)
 	require.NoError(t, err)
 
@@ -4410,8 +4832,8 @@ func TestSwitchDustForwarding(t *testing.T) {
 	carolAttemptID := 0
 
 	err = n.carolServer.htlcSwitch.SendHTLC(
-		n.carolChannelLink.ShortChanID(), uint64(carolAttemptID),
-		carolHtlc,
+		context.Background(), n.carolChannelLink.ShortChanID(),
+		uint64(carolAttemptID), carolHtlc,
 	)
 	require.NoError(t, err)
 	carolAttemptID++
@@ -4455,7 +4877,8 @@ func TestSwitchDustForwarding(t *testing.T) {
 	require.True(t, checkAlmostDust(n.aliceChannelLink, aliceMbox, true))
 
 	err = n.aliceServer.htlcSwitch.SendHTLC(
-		n.aliceChannelLink.ShortChanID(), uint64(357),
+		context.Background(), n.aliceChannelLink.ShortChanID(),
+		uint64(357),
 		aliceMultihopHtlc,
 	)
 	require.ErrorIs(t, err, errDustThresholdExceeded)
@@ -4528,7 +4951,9 @@ func sendDustHtlcs(t *testing.T, n *threeHopNetwork, alice bool,
 			// before all 357*2 HTLC's are sent due to double
 			// counting. Get around this by continuing to send
 			// until successful.
-			err = sendingSwitch.SendHTLC(sid, attemptID, htlc)
+			err = sendingSwitch.SendHTLC(
+				context.Background(), sid, attemptID, htlc,
+			)
 			if err == nil {
 				break
 			}
@@ -4633,7 +5058,7 @@ func TestSwitchMailboxDust(t *testing.T) {
 
 	// Sending one more HTLC to Alice should result in the dust threshold
 	// being breached.
-	err = s.SendHTLC(aliceChanID, 0, addMsg)
+	err = s.SendHTLC(context.Background(), aliceChanID, 0, addMsg)
 	require.ErrorIs(t, err, errDustThresholdExceeded)
 
 	// We'll now call ForwardPackets from Bob to ensure that the mailbox
@@ -5535,3 +5960,95 @@ func testSwitchAliasInterceptFail(t *testing.T, zeroConf bool) {
 
 	require.NoError(t, interceptSwitch.Stop())
 }
+
+// TestSwitchSendHTLCTracing asserts that the switch and the outgoing link
+// record their handling of a local payment's htlc as part of the trace of the
+// payment attempt.
+func TestSwitchSendHTLCTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+	)
+
+	// The tracers of the switch and its links record their spans through
+	// the global tracer provider. Restore the previous provider once we're
+	// done, so we don't affect any other tests.
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		require.NoError(t, provider.Shutdown(context.Background()))
+	})
+
+	channels, _, err := createClusterChannels(
+		t, ltcutil.SatoshiPerBitcoin*3, ltcutil.SatoshiPerBitcoin*5,
+	)
+	require.NoError(t, err, "unable to create channel")
+
+	n := newThreeHopNetwork(t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight)
+	require.NoError(t, n.start())
+	t.Cleanup(n.stop)
+
+	amount := lnwire.NewMSatFromSatoshis(ltcutil.SatoshiPerBitcoin)
+	htlcAmt, totalTimelock, hops := generateHops(
+		amount, testStartingHeight, n.firstBobChannelLink,
+		n.carolChannelLink,
+	)
+	blob, err := generateRoute(hops...)
+	require.NoError(t, err)
+
+	invoice, htlc, pid, err := generatePayment(
+		amount, htlcAmt, totalTimelock, blob,
+	)
+	require.NoError(t, err)
+
+	err = n.carolServer.registry.AddInvoice(*invoice, htlc.PaymentHash)
+	require.NoError(t, err, "unable to add invoice in carol registry")
+
+	// Send the htlc as part of the trace of a payment attempt.
+	ctx, attemptSpan := provider.Tracer("test").Start(
+		context.Background(), "attempt",
+	)
+	err = n.aliceServer.htlcSwitch.SendHTLC(
+		ctx, n.firstBobChannelLink.ShortChanID(), pid, htlc,
+	)
+	require.NoError(t, err)
+
+	resultChan, err := n.aliceServer.htlcSwitch.GetAttemptResult(
+		pid, htlc.PaymentHash, newMockDeobfuscator(),
+	)
+	require.NoError(t, err)
+
+	select {
+	case result := <-resultChan:
+		require.NoError(t, result.Error)
+
+	case <-time.After(10 * time.Second):
+		t.Fatalf("no result arrived")
+	}
+	attemptSpan.End()
+
+	// Other tests may be sending htlcs at the same time, so we only look
+	// at the spans of our trace.
+	traceID := attemptSpan.SpanContext().TraceID()
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == traceID {
+			spans[span.Name()] = span
+		}
+	}
+
+	switchSpan, ok := spans["htlcswitch.Switch.SendHTLC"]
+	require.True(t, ok, "switch span not recorded")
+	require.Equal(
+		t, attemptSpan.SpanContext().SpanID(),
+		switchSpan.Parent().SpanID(),
+	)
+
+	linkSpan, ok := spans["htlcswitch.channelLink.handleDownstreamUpdateAdd"]
+	require.True(t, ok, "link span not recorded")
+	require.Equal(
+		t, switchSpan.SpanContext().SpanID(), linkSpan.Parent().SpanID(),
+	)
+}
@@ -51,6 +51,14 @@ func (f *interceptedForward) Resume() error {
 	return ErrCannotResume
 }
 
+// ResumeModified notifies the intention to resume an existing hold forward
+// with a modified htlc.
+func (f *interceptedForward) ResumeModified(
+	_ htlcswitch.FwdModifications) error {
+
+	return ErrCannotResume
+}
+
 // Fail notifies the intention to fail an existing hold forward with an
 // encrypted failure reason.
 func (f *interceptedForward) Fail(_ []byte) error {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btclog"
+	"github.com/jackc/pgconn"
 	"github.com/ltcsuite/lnd/kvdb"
 	"github.com/ltcsuite/lnd/kvdb/etcd"
 	"github.com/ltcsuite/lnd/kvdb/postgres"
@@ -14,6 +15,7 @@ import (
 	"github.com/ltcsuite/lnd/kvdb/sqlite"
 	"github.com/ltcsuite/lnd/lnrpc"
 	"github.com/ltcsuite/lnd/lnwallet/btcwallet"
+	"github.com/ltcsuite/lnd/sqldb"
 )
 
 const (
@@ -28,6 +30,7 @@ const (
 	SqliteChainDBName    = "chain.sqlite"
 	SqliteNeutrinoDBName = "neutrino.sqlite"
 	SqliteTowerDBName    = "watchtower.sqlite"
+	SqliteNativeDBName   = "lnd.sqlite"
 
 	BoltBackend                = "bolt"
 	EtcdBackend                = "etcd"
@@ -64,6 +67,10 @@ const (
 
 	// NSNeutrinoDB is the namespace name that we use for the neutrino DB.
 	NSNeutrinoDB = "neutrinodb"
+
+	// NSNativeSQLDB is the name that we use for the DB that holds the
+	// native SQL tables.
+	NSNativeSQLDB = "nativesqldb"
 )
 
 // DB holds database configuration for LND.
@@ -82,10 +89,12 @@ type DB struct {
 
 	Sqlite *sqlite.Config `group:"sqlite" namespace:"sqlite" description:"Sqlite settings."`
 
-	NoGraphCache bool `long:"no-graph-cache" description:"Don't use the in-memory graph cache for path finding. Much slower but uses less RAM. Can only be used with a bolt database backend."`
+	NoGraphCache bool `long:"no-graph-cache" description:"Don't use the in-memory graph cache for path finding. Much slower but uses less RAM. Can only be used with a bolt database backend or with use-native-sql."`
 
 	PruneRevocation bool `long:"prune-revocation" description:"Run the optional migration that prunes the revocation logs to save disk space."`
 
+	UseNativeSQL bool `long:"use-native-sql" description:"Use native SQL tables for the data that supports it instead of the key-value store. Currently this applies to payments and the channel graph, which are migrated on first start up. Can only be used with the postgres or sqlite database backend."`
+
 	NoRevLogAmtData bool `long:"no-rev-log-amt-data" description:"If set, the to-local and to-remote output amounts of revoked commitment transactions will not be stored in the revocation log. Note that once this data is lost, a watchtower client will not be able to back up the revoked state."`
 }
 
@@ -140,12 +149,23 @@ func (db *DB) Validate() error {
 	// remote database setups (due to not needing to memory-map the bbolt DB
 	// files), we can keep the graph in memory instead. But for mobile
 	// devices the tradeoff between a smaller memory footprint and the
-	// longer time needed for path finding might be a desirable one.
-	if db.NoGraphCache && db.Backend != BoltBackend {
+	// longer time needed for path finding might be a desirable one. The
+	// native SQL graph store doesn't hold such a transaction open, so it
+	// can be used without the cache as well.
+	if db.NoGraphCache && db.Backend != BoltBackend && !db.UseNativeSQL {
 		return fmt.Errorf("cannot use no-graph-cache with database "+
 			"backend '%v'", db.Backend)
 	}
 
+	// The native SQL tables live next to the key-value tables, which is
+	// only possible with one of the SQL backends.
+	if db.UseNativeSQL && db.Backend != PostgresBackend &&
+		db.Backend != SqliteBackend {
+
+		return fmt.Errorf("cannot use native SQL with database "+
+			"backend '%v'", db.Backend)
+	}
+
 	return nil
 }
 
@@ -214,6 +234,10 @@ type DatabaseBackends struct {
 	// the underlying wallet database from.
 	WalletDB btcwallet.LoaderOption
 
+	// NativeSQLStore is the store that holds the native SQL tables. This
+	// might be nil if native SQL is disabled.
+	NativeSQLStore *sqldb.BaseDB
+
 	// Remote indicates whether the database backends are remote, possibly
 	// replicated instances or local bbolt or sqlite backed databases.
 	Remote bool
@@ -402,6 +426,15 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		}
 		closeFuncs[NSWalletDB] = postgresWalletBackend.Close
 
+		nativeSQLStore, err := db.openNativeSQLStore(chanDBPath)
+		if err != nil {
+			return nil, fmt.Errorf("error opening postgres native "+
+				"SQL DB: %v", err)
+		}
+		if nativeSQLStore != nil {
+			closeFuncs[NSNativeSQLDB] = nativeSQLStore.Close
+		}
+
 		// Warn if the user is trying to switch over to a Postgres DB
 		// while there is a wallet or channel bbolt DB still present.
 		warnExistingBoltDBs(
@@ -429,8 +462,9 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 			WalletDB: btcwallet.LoaderWithExternalWalletDB(
 				postgresWalletBackend,
 			),
-			Remote:     true,
-			CloseFuncs: closeFuncs,
+			NativeSQLStore: nativeSQLStore,
+			Remote:         true,
+			CloseFuncs:     closeFuncs,
 		}, nil
 
 	case SqliteBackend:
@@ -504,6 +538,15 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		}
 		closeFuncs[NSWalletDB] = sqliteWalletBackend.Close
 
+		nativeSQLStore, err := db.openNativeSQLStore(chanDBPath)
+		if err != nil {
+			return nil, fmt.Errorf("error opening sqlite native "+
+				"SQL DB: %v", err)
+		}
+		if nativeSQLStore != nil {
+			closeFuncs[NSNativeSQLDB] = nativeSQLStore.Close
+		}
+
 		// Warn if the user is trying to switch over to a sqlite DB
 		// while there is a wallet or channel bbolt DB still present.
 		warnExistingBoltDBs(
@@ -531,7 +574,8 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 			WalletDB: btcwallet.LoaderWithExternalWalletDB(
 				sqliteWalletBackend,
 			),
-			CloseFuncs: closeFuncs,
+			NativeSQLStore: nativeSQLStore,
+			CloseFuncs:     closeFuncs,
 		}, nil
 	}
 
@@ -639,6 +683,62 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	}, nil
 }
 
+// openNativeSQLStore opens the store that holds the native SQL tables on the
+// configured SQL backend. Nil is returned if native SQL is disabled.
+func (db *DB) openNativeSQLStore(chanDBPath string) (*sqldb.BaseDB, error) {
+	if !db.UseNativeSQL {
+		return nil, nil
+	}
+
+	switch db.Backend {
+	case PostgresBackend:
+		cfg, err := nativeSQLPostgresConfig(db.Postgres)
+		if err != nil {
+			return nil, err
+		}
+
+		store, err := sqldb.NewPostgresStore(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return store.BaseDB, nil
+
+	case SqliteBackend:
+		store, err := sqldb.NewSqliteStore(&sqldb.SqliteConfig{
+			DatabaseFileName: filepath.Join(
+				chanDBPath, SqliteNativeDBName,
+			),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return store.BaseDB, nil
+
+	default:
+		return nil, fmt.Errorf("native SQL not supported with "+
+			"backend '%v'", db.Backend)
+	}
+}
+
+// nativeSQLPostgresConfig derives the config of the native SQL postgres store
+// from the config of the key-value postgres backend. The connection string is
+// passed through unchanged, so that all of its connection parameters apply to
+// both stores.
+func nativeSQLPostgresConfig(cfg *postgres.Config) (*sqldb.PostgresConfig,
+	error) {
+
+	if _, err := pgconn.ParseConfig(cfg.Dsn); err != nil {
+		return nil, fmt.Errorf("invalid postgres dsn: %w", err)
+	}
+
+	return &sqldb.PostgresConfig{
+		Dsn:                cfg.Dsn,
+		MaxOpenConnections: cfg.MaxConnections,
+	}, nil
+}
+
 // warnExistingBoltDBs checks if there is an existing bbolt database in the
 // given location and logs a warning if so.
 func warnExistingBoltDBs(log btclog.Logger, dbType, dir, fileName string) {
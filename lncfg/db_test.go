@@ -22,3 +22,19 @@ func TestDBDefaultConfig(t *testing.T) {
 	require.False(t, defaultConfig.Bolt.AutoCompact)
 	require.True(t, defaultConfig.Bolt.NoFreelistSync)
 }
+
+// TestDBValidateNoGraphCache tests that the graph cache can only be disabled
+// with backends that don't keep a long running read transaction open for path
+// finding.
+func TestDBValidateNoGraphCache(t *testing.T) {
+	cfg := lncfg.DefaultDB()
+	cfg.NoGraphCache = true
+	require.NoError(t, cfg.Validate())
+
+	cfg.Backend = lncfg.SqliteBackend
+	require.Error(t, cfg.Validate())
+
+	// The native SQL graph store can be used without the cache.
+	cfg.UseNativeSQL = true
+	require.NoError(t, cfg.Validate())
+}
@@ -35,15 +35,17 @@ const (
 //
 //nolint:ll
 type Electrum struct {
-	// Server is the host:port of the Electrum server to connect to.
-	Server string `long:"server" description:"The host:port of the Electrum server to connect to."`
-
-	// RESTURL is the URL for the mempool/electrs REST API. This is required
-	// for proper channel operations (funding tx validation, channel close
-	// detection, etc.) since the Electrum protocol doesn't support full
-	// block retrieval.
+	// Servers are the host:port addresses of the Electrum servers to
+	// connect to. Requests go to the healthiest server and fail over to
+	// the others.
+	Servers []string `long:"server" description:"The host:port of an Electrum server to connect to. Can be specified multiple times for failover."`
+
+	// RESTURL is the optional URL for the mempool/electrs REST API. Since
+	// the Electrum protocol doesn't support full block retrieval, blocks
+	// are fetched from Litecoin P2P peers. If set, the REST API is tried
+	// first as a faster source.
 	// Example: http://localhost:3002
-	RESTURL string `long:"resturl" description:"(Required) URL for mempool/electrs REST API (e.g., http://localhost:3002)"`
+	RESTURL string `long:"resturl" description:"Optional URL for a mempool/electrs REST API to fetch full blocks from before asking P2P peers (e.g., http://localhost:3002)"`
 
 	// UseSSL specifies whether to use SSL/TLS for the connection to the
 	// Electrum server.
@@ -73,9 +75,15 @@ type Electrum struct {
 	// MaxRetries is the maximum number of times to retry a failed request.
 	MaxRetries int `long:"maxretries" description:"Maximum number of times to retry a failed request."`
 
-	// MwebP2PPeers is an optional list of Litecoin P2P peers for MWEB sync.
-	// If empty, DNS seeds are used for peer discovery.
-	MwebP2PPeers []string `long:"mwebp2ppeer" description:"Litecoin P2P peer for MWEB sync (host:port). Can be specified multiple times."`
+	// Quorum is the number of servers that must agree on the chain tip
+	// and on scripthash statuses. If they don't, the chain backend health
+	// check fails. Values below two disable the cross-checking.
+	Quorum int `long:"quorum" description:"Number of Electrum servers that must agree on the chain tip and scripthash statuses. Disagreement fails the chain backend health check. 0 disables the check."`
+
+	// MwebP2PPeers is an optional list of Litecoin P2P peers for MWEB sync
+	// and full block downloads. If empty, DNS seeds are used for peer
+	// discovery.
+	MwebP2PPeers []string `long:"mwebp2ppeer" description:"Litecoin P2P peer for MWEB sync and full blocks (host:port). Can be specified multiple times."`
 }
 
 // DefaultElectrumConfig returns a new Electrum config with default values
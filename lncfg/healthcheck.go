@@ -34,6 +34,12 @@ type HealthCheckConfig struct {
 	TorConnection *CheckConfig `group:"torconnection" namespace:"torconnection"`
 
 	RemoteSigner *CheckConfig `group:"remotesigner" namespace:"remotesigner"`
+
+	ElectrumTip *ElectrumTipCheckConfig `group:"electrumtip" namespace:"electrumtip"`
+
+	ElectrumLatency *ElectrumLatencyCheckConfig `group:"electrumlatency" namespace:"electrumlatency"`
+
+	MwebPeers *MwebPeersCheckConfig `group:"mwebpeers" namespace:"mwebpeers"`
 }
 
 // Validate checks the values configured for our health checks.
@@ -60,6 +66,33 @@ func (h *HealthCheckConfig) Validate() error {
 		return err
 	}
 
+	if err := h.ElectrumTip.validate("electrum tip"); err != nil {
+		return err
+	}
+
+	if h.ElectrumTip.Attempts != 0 && h.ElectrumTip.MaxBlocks == 0 {
+		return errors.New("electrum tip max blocks must be positive")
+	}
+
+	err := h.ElectrumLatency.validate("electrum latency")
+	if err != nil {
+		return err
+	}
+
+	if h.ElectrumLatency.Attempts != 0 &&
+		h.ElectrumLatency.MaxLatency <= 0 {
+
+		return errors.New("electrum max latency must be positive")
+	}
+
+	if err := h.MwebPeers.validate("mweb peers"); err != nil {
+		return err
+	}
+
+	if h.MwebPeers.MinPeers < 0 {
+		return errors.New("mweb min peers must not be negative")
+	}
+
 	return nil
 }
 
@@ -104,3 +137,28 @@ type DiskCheckConfig struct {
 
 	*CheckConfig
 }
+
+// ElectrumTipCheckConfig contains configuration for ensuring that the chain
+// tip served by the Electrum backend keeps up with the network.
+type ElectrumTipCheckConfig struct {
+	MaxBlocks uint32 `long:"maxblocks" description:"The number of expected block intervals the chain tip may be behind the local clock before the Electrum servers are considered stuck."`
+
+	*CheckConfig
+}
+
+// ElectrumLatencyCheckConfig contains configuration for ensuring that the
+// Electrum server requests are sent to answers in a timely manner.
+type ElectrumLatencyCheckConfig struct {
+	MaxLatency time.Duration `long:"maxlatency" description:"The maximum average response time allowed for the Electrum server requests are sent to."`
+
+	*CheckConfig
+}
+
+// MwebPeersCheckConfig contains configuration for ensuring that the Electrum
+// backend stays connected to Litecoin P2P peers to fetch MWEB data and full
+// blocks from.
+type MwebPeersCheckConfig struct {
+	MinPeers int `long:"minpeers" description:"The minimum number of MWEB P2P peers that must be connected."`
+
+	*CheckConfig
+}
@@ -16,6 +16,8 @@ var (
 //nolint:lll
 type Htlcswitch struct {
 	MailboxDeliveryTimeout time.Duration `long:"mailboxdeliverytimeout" description:"The timeout value when delivering HTLCs to a channel link. Setting this value too small will result in local payment failures if large number of payments are sent over a short period."`
+
+	LogFailedForwards bool `long:"logfailedforwards" description:"If true, forwards that failed or were rejected by this node are recorded in the forwarding log along with their failure code. Settled forwards are always recorded. Enabling this lets peers grow the forwarding log by sending HTLCs that are rejected."`
 }
 
 // Validate checks the values configured for htlcswitch.
@@ -18,3 +18,10 @@ func DefaultPrometheus() Prometheus {
 func (p *Prometheus) Enabled() bool {
 	return false
 }
+
+// NodeMetricsEnabled returns whether or not the metrics about the internals of
+// the node should be exported. Monitoring is currently disabled, so
+// NodeMetricsEnabled will always return false.
+func (p *Prometheus) NodeMetricsEnabled() bool {
+	return false
+}
@@ -21,14 +21,29 @@ type Prometheus struct {
 	// generates additional data, and consume more memory for the
 	// Prometheus server.
 	PerfHistograms bool `long:"perfhistograms" description:"enable additional histogram to track gRPC call processing performance (latency, etc)"`
+
+	// NoNodeMetrics disables the export of the metrics about the internals
+	// of the node, such as its channels, forwards and payments, so that
+	// only the gRPC metrics are exported.
+	NoNodeMetrics bool `long:"nonodemetrics" description:"disable exporting metrics about the node internals (channels, forwards, payments, sweeper, watchtower client, peers and chain backend)"`
+
+	// MaxChannelLabels is the maximum number of distinct channels that
+	// are reported as a label of the per channel metrics. Any further
+	// channels are aggregated under the "other" label.
+	MaxChannelLabels int `long:"maxchannellabels" description:"the maximum number of distinct channels reported as a label of the per channel metrics, further channels are aggregated under the 'other' label"`
 }
 
+// DefaultMaxChannelLabels is the default maximum number of distinct channels
+// that are reported as a label of the per channel metrics.
+const DefaultMaxChannelLabels = 100
+
 // DefaultPrometheus is the default configuration for the Prometheus metrics
 // exporter.
 func DefaultPrometheus() Prometheus {
 	return Prometheus{
-		Listen: "127.0.0.1:8989",
-		Enable: false,
+		Listen:           "127.0.0.1:8989",
+		Enable:           false,
+		MaxChannelLabels: DefaultMaxChannelLabels,
 	}
 }
 
@@ -37,3 +52,10 @@ func DefaultPrometheus() Prometheus {
 func (p *Prometheus) Enabled() bool {
 	return p.Enable
 }
+
+// NodeMetricsEnabled returns whether or not the metrics about the internals of
+// the node should be exported. They're exported by default if monitoring is
+// enabled.
+func (p *Prometheus) NodeMetricsEnabled() bool {
+	return p.Enable && !p.NoNodeMetrics
+}
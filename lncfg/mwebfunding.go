@@ -0,0 +1,28 @@
+package lncfg
+
+import "fmt"
+
+const (
+	// DefaultMwebPegOutConfTarget is the default confirmation target used
+	// to estimate the fee rate of automatic MWEB peg-out transactions.
+	DefaultMwebPegOutConfTarget = 6
+)
+
+// MwebFunding holds the options that govern how channel funding treats the
+// MWEB coins of the wallet, which can't be spent by a funding transaction.
+//
+//nolint:lll
+type MwebFunding struct {
+	PegOut bool `long:"pegout" description:"If the wallet needs MWEB coins to fund a channel, first peg them out to a canonical address of the wallet and open the channel once the peg-out has matured. If false, such channel opens are rejected."`
+
+	PegOutConfTarget uint32 `long:"pegoutconftarget" description:"The confirmation target used to estimate the fee rate of automatic peg-out transactions."`
+}
+
+// Validate checks the values configured for MWEB channel funding.
+func (m *MwebFunding) Validate() error {
+	if m.PegOutConfTarget < 1 {
+		return fmt.Errorf("pegoutconftarget must be at least 1")
+	}
+
+	return nil
+}
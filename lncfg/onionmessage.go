@@ -0,0 +1,26 @@
+package lncfg
+
+import "fmt"
+
+// OnionMessages holds the options that govern how we relay onion messages on
+// behalf of our peers.
+//
+//nolint:lll
+type OnionMessages struct {
+	PeerRateLimit float64 `long:"peer-rate-limit" description:"The average number of onion messages per second that are accepted from a single peer. Messages in excess of the limit are dropped."`
+
+	PeerBurst int `long:"peer-burst" description:"The number of onion messages that a single peer may send in a burst before its rate limit applies."`
+}
+
+// Validate checks the values configured for onion messages.
+func (o *OnionMessages) Validate() error {
+	if o.PeerRateLimit <= 0 {
+		return fmt.Errorf("peer-rate-limit must be positive")
+	}
+
+	if o.PeerBurst < 1 {
+		return fmt.Errorf("peer-burst must be at least 1")
+	}
+
+	return nil
+}
@@ -47,6 +47,29 @@ type ProtocolOptions struct {
 	// NoOptionAnySegwit should be set to true if we don't want to use any
 	// Taproot (and beyond) addresses for co-op closing.
 	NoOptionAnySegwit bool `long:"no-any-segwit" description:"disallow using any segiwt witness version as a co-op close address"`
+
+	// NoRouteBlindingOption disables forwarding of payments in blinded
+	// routes.
+	NoRouteBlindingOption bool `long:"no-route-blinding" description:"do not forward payments that are a part of a blinded route"`
+
+	// NoOnionMessagesOption disables relaying and receiving onion
+	// messages.
+	NoOnionMessagesOption bool `long:"no-onion-messages" description:"do not relay or accept onion messages"`
+
+	// OptionSplicing should be set if we want to signal the splice
+	// feature bit, which allows adding funds to and removing funds from
+	// live channels.
+	OptionSplicing bool `long:"splicing" description:"enable support for splicing funds into and out of channels"`
+
+	// OptionDualFunding should be set if we want to signal the dual
+	// funding feature bit, which allows both peers to contribute funds
+	// when opening a channel.
+	OptionDualFunding bool `long:"dual-funding" description:"enable support for dual funded channels, where both peers may contribute funds"`
+
+	// OptionDynamicCommitments should be set if we want to signal the
+	// quiescence and dynamic commitments feature bits, which allow
+	// upgrading the commitment type of live channels.
+	OptionDynamicCommitments bool `long:"dynamic-commitments" description:"enable support for upgrading the commitment type of live channels"`
 }
 
 // Wumbo returns true if lnd should permit the creation and acceptance of wumbo
@@ -82,3 +105,30 @@ func (l *ProtocolOptions) ZeroConf() bool {
 func (l *ProtocolOptions) NoAnySegwit() bool {
 	return l.NoOptionAnySegwit
 }
+
+// NoRouteBlinding returns true if forwarding of blinded payments is disabled.
+func (l *ProtocolOptions) NoRouteBlinding() bool {
+	return l.NoRouteBlindingOption
+}
+
+// NoOnionMessages returns true if relaying and receiving onion messages is
+// disabled.
+func (l *ProtocolOptions) NoOnionMessages() bool {
+	return l.NoOnionMessagesOption
+}
+
+// Splicing returns true if we have enabled the splice feature bit.
+func (l *ProtocolOptions) Splicing() bool {
+	return l.OptionSplicing
+}
+
+// DualFunding returns true if we have enabled the dual funding feature bit.
+func (l *ProtocolOptions) DualFunding() bool {
+	return l.OptionDualFunding
+}
+
+// DynamicCommitments returns true if we have enabled the quiescence and
+// dynamic commitments feature bits.
+func (l *ProtocolOptions) DynamicCommitments() bool {
+	return l.OptionDynamicCommitments
+}
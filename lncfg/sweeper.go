@@ -3,11 +3,15 @@ package lncfg
 import (
 	"fmt"
 	"time"
+
+	"github.com/ltcsuite/lnd/contractcourt"
 )
 
 //nolint:lll
 type Sweeper struct {
 	BatchWindowDuration time.Duration `long:"batchwindowduration" description:"Duration of the sweep batch window. The sweep is held back during the batch window to allow more inputs to be added and thereby lower the fee per input."`
+
+	Budget *contractcourt.BudgetConfig `group:"sweeper.budget" namespace:"budget" long:"budget" description:"The fee budgets given to the sweeper for the outputs of force closed channels. The budget of an output is a ratio of its value, optionally capped at an absolute amount."`
 }
 
 // Validate checks the values configured for the sweeper.
@@ -16,5 +20,11 @@ func (s *Sweeper) Validate() error {
 		return fmt.Errorf("batchwindowduration must be positive")
 	}
 
+	if s.Budget != nil {
+		if err := s.Budget.Validate(); err != nil {
+			return fmt.Errorf("invalid budget config: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,81 @@
+package lncfg
+
+import (
+	"fmt"
+	"net"
+)
+
+const (
+	// DefaultTracingServiceName is the default service name that is
+	// reported along with all spans exported by lnd.
+	DefaultTracingServiceName = "lnd"
+
+	// DefaultTracingSampleRatio is the default fraction of new traces that
+	// are sampled.
+	DefaultTracingSampleRatio = 1.0
+)
+
+// Tracing holds the configuration options for exporting OpenTelemetry traces
+// of payments and RPC calls to an OTLP collector.
+//
+//nolint:lll
+type Tracing struct {
+	// Active enables exporting traces.
+	Active bool `long:"active" description:"If true, lnd will export OpenTelemetry traces of RPC calls and payments to the configured OTLP collector."`
+
+	// Endpoint is the host:port of the OTLP gRPC collector that traces are
+	// exported to.
+	Endpoint string `long:"endpoint" description:"The host:port of the OTLP gRPC collector that traces are exported to."`
+
+	// Insecure disables TLS for the connection to the collector.
+	Insecure bool `long:"insecure" description:"If true, the connection to the OTLP collector won't be encrypted with TLS."`
+
+	// ServiceName is the service name that is reported along with all
+	// spans.
+	ServiceName string `long:"servicename" description:"The service name that is reported along with all spans."`
+
+	// SampleRatio is the fraction of new traces that are sampled. Spans
+	// of traces that were started by the caller of an RPC follow the
+	// sampling decision of the caller.
+	SampleRatio float64 `long:"sampleratio" description:"The fraction of new traces that are sampled, between 0 and 1. Traces started by the caller of an RPC follow the sampling decision of the caller."`
+}
+
+// DefaultTracing returns the default tracing configuration, which has tracing
+// disabled.
+func DefaultTracing() *Tracing {
+	return &Tracing{
+		ServiceName: DefaultTracingServiceName,
+		SampleRatio: DefaultTracingSampleRatio,
+	}
+}
+
+// Validate checks the values configured for tracing.
+func (t *Tracing) Validate() error {
+	if !t.Active {
+		return nil
+	}
+
+	if t.Endpoint == "" {
+		return fmt.Errorf("tracing.endpoint must be set if tracing " +
+			"is active")
+	}
+
+	if _, _, err := net.SplitHostPort(t.Endpoint); err != nil {
+		return fmt.Errorf("invalid tracing.endpoint %v: %w",
+			t.Endpoint, err)
+	}
+
+	if t.ServiceName == "" {
+		return fmt.Errorf("tracing.servicename must not be empty")
+	}
+
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sampleratio %v must be between 0 "+
+			"and 1", t.SampleRatio)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Tracing implements the Validator interface.
+var _ Validator = (*Tracing)(nil)
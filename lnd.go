@@ -33,6 +33,7 @@ import (
 	"github.com/ltcsuite/lnd/rpcperms"
 	"github.com/ltcsuite/lnd/signal"
 	"github.com/ltcsuite/lnd/tor"
+	"github.com/ltcsuite/lnd/tracing"
 	"github.com/ltcsuite/lnd/walletunlocker"
 	"github.com/ltcsuite/lnd/watchtower"
 	"github.com/ltcsuite/ltcd/ltcutil"
@@ -305,6 +306,22 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		}
 	}
 
+	// Export traces of RPC calls and payments if requested. We start the
+	// exporter before any of the subsystems that record spans.
+	if cfg.Tracing.Active {
+		shutdownTracing, err := tracing.Start(ctx, cfg.Tracing)
+		if err != nil {
+			return mkErr("unable to start tracing: %v", err)
+		}
+		defer func() {
+			err := shutdownTracing(context.Background())
+			if err != nil {
+				ltndLog.Warnf("Unable to shut down tracing: %v",
+					err)
+			}
+		}()
+	}
+
 	// Create a new RPC interceptor that we'll add to the GRPC server. This
 	// will be used to log the API calls invoked on the GRPC server.
 	interceptorChain := rpcperms.NewInterceptorChain(
@@ -334,6 +351,12 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 
 	rpcServerOpts := interceptorChain.CreateServerOpts()
 	serverOpts = append(serverOpts, rpcServerOpts...)
+
+	// If tracing is active, we'll record a span for every RPC call that
+	// the spans of the subsystems handling the call are attached to.
+	if cfg.Tracing.Active {
+		serverOpts = append(serverOpts, tracing.ServerOptions()...)
+	}
 	serverOpts = append(
 		serverOpts, grpc.MaxRecvMsgSize(lnrpc.MaxGrpcMsgSize),
 		grpc.KeepaliveParams(serverKeepalive),
@@ -686,6 +709,19 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	// We transition the server state to Active, as the server is up.
 	interceptorChain.SetServerActive()
 
+	// If Prometheus monitoring is enabled, we'll also export metrics about
+	// the internals of the node, now that all of its subsystems are
+	// running.
+	if cfg.Prometheus.NodeMetricsEnabled() {
+		nodeMetrics := monitoring.NewNodeMetrics(
+			server.nodeMetricsConfig(), cfg.Prometheus,
+		)
+		if err := nodeMetrics.Start(); err != nil {
+			return mkErr("unable to start node metrics: %v", err)
+		}
+		defer nodeMetrics.Stop()
+	}
+
 	// Now that the server has started, if the autopilot mode is currently
 	// active, then we'll start the autopilot agent immediately. It will be
 	// stopped together with the autopilot service.
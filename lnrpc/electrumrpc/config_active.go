@@ -0,0 +1,20 @@
+//go:build electrumrpc
+// +build electrumrpc
+
+package electrumrpc
+
+import (
+	"github.com/ltcsuite/lnd/electrum"
+)
+
+// Config is the primary configuration struct for the Electrum RPC server. It
+// contains all the items required for the rpc server to carry out its
+// duties. The fields with struct tags are meant to be parsed as normal
+// configuration options, while if able to be populated, the latter fields MUST
+// also be specified.
+type Config struct {
+	// ChainClient is the Electrum chain client, which gives access to the
+	// underlying Electrum client and MWEB P2P peers. It is nil if lnd
+	// doesn't run with the Electrum backend.
+	ChainClient *electrum.ChainClient
+}
@@ -0,0 +1,7 @@
+//go:build !electrumrpc
+// +build !electrumrpc
+
+package electrumrpc
+
+// Config is empty for non-electrumrpc builds.
+type Config struct{}
@@ -0,0 +1,307 @@
+//go:build electrumrpc
+// +build electrumrpc
+
+package electrumrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/ltcsuite/lnd/electrum"
+	"github.com/ltcsuite/lnd/lnrpc"
+	"google.golang.org/grpc"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+const (
+	// subServerName is the name of the sub rpc server. We'll use this name
+	// to register ourselves, and we also require that the main
+	// SubServerConfigDispatcher instance recognize it as the name of our
+	// RPC service.
+	subServerName = "ElectrumKitRPC"
+)
+
+var (
+	// macPermissions maps RPC calls to the permissions they require.
+	macPermissions = map[string][]bakery.Op{
+		"/electrumrpc.ElectrumKit/Status": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/electrumrpc.ElectrumKit/SubscribeConnectionEvents": {{
+			Entity: "info",
+			Action: "read",
+		}},
+	}
+
+	// ErrElectrumNotActive is an error returned when lnd doesn't run with
+	// the Electrum chain backend.
+	ErrElectrumNotActive = errors.New("electrum backend not active")
+)
+
+// ServerShell is a shell struct holding a reference to the actual sub-server.
+// It is used to register the gRPC sub-server with the root server before we
+// have the necessary dependencies to populate the actual sub-server.
+type ServerShell struct {
+	ElectrumKitServer
+}
+
+// Server is a sub-server of the main RPC server: the Electrum RPC. This sub
+// RPC server allows external callers to access the status of the Electrum
+// chain backend currently active within lnd.
+type Server struct {
+	// Required by the grpc-gateway/v2 library for forward compatibility.
+	// Must be after the atomically used variables to not break struct
+	// alignment.
+	UnimplementedElectrumKitServer
+
+	cfg *Config
+}
+
+// A compile time check to ensure that ElectrumKit fully implements the
+// ElectrumKitServer gRPC service.
+var _ ElectrumKitServer = (*Server)(nil)
+
+// New returns a new instance of the electrumrpc Electrum sub-server. We also
+// return the set of permissions for the macaroons that we may create within
+// this method. If the macaroons we need aren't found in the filepath, then
+// we'll create them on start up. If we're unable to locate, or create the
+// macaroons we need, then we'll return with an error.
+func New(cfg *Config) (*Server, lnrpc.MacaroonPerms, error) {
+	// We don't create any new macaroons for this subserver, instead reuse
+	// existing info permissions.
+	server := &Server{
+		cfg: cfg,
+	}
+
+	return server, macPermissions, nil
+}
+
+// Start launches any helper goroutines required for the Server to function.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Start() error {
+	return nil
+}
+
+// Stop signals any active goroutines for a graceful closure.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Stop() error {
+	return nil
+}
+
+// Name returns a unique string representation of the sub-server. This can be
+// used to identify the sub-server and also de-duplicate them.
+//
+// NOTE: This is part of the lnrpc.SubServer interface.
+func (s *Server) Name() string {
+	return subServerName
+}
+
+// RegisterWithRootServer will be called by the root gRPC server to direct a
+// sub RPC server to register itself with the main gRPC root server. Until this
+// is called, each sub-server won't be able to have
+// requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) RegisterWithRootServer(grpcServer *grpc.Server) error {
+	// We make sure that we register it with the main gRPC server to ensure
+	// all our methods are routed properly.
+	RegisterElectrumKitServer(grpcServer, r)
+
+	log.Debugf("Electrum RPC server successfully register with root " +
+		"gRPC server")
+
+	return nil
+}
+
+// RegisterWithRestServer will be called by the root REST mux to direct a sub
+// RPC server to register itself with the main REST mux server. Until this is
+// called, each sub-server won't be able to have requests routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) RegisterWithRestServer(ctx context.Context,
+	mux *runtime.ServeMux, dest string, opts []grpc.DialOption) error {
+
+	// We make sure that we register it with the main REST server to ensure
+	// all our methods are routed properly.
+	err := RegisterElectrumKitHandlerFromEndpoint(ctx, mux, dest, opts)
+	if err != nil {
+		log.Errorf("Could not register Electrum REST server "+
+			"with root REST server: %v", err)
+		return err
+	}
+
+	log.Debugf("Electrum REST server successfully registered with " +
+		"root REST server")
+	return nil
+}
+
+// CreateSubServer populates the subserver's dependencies using the passed
+// SubServerConfigDispatcher. This method should fully initialize the
+// sub-server instance, making it ready for action. It returns the macaroon
+// permissions that the sub-server wishes to pass on to the root server for all
+// methods routed towards it.
+//
+// NOTE: This is part of the lnrpc.GrpcHandler interface.
+func (r *ServerShell) CreateSubServer(configRegistry lnrpc.SubServerConfigDispatcher) (
+	lnrpc.SubServer, lnrpc.MacaroonPerms, error) {
+
+	subServer, macPermissions, err := createNewSubServer(configRegistry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.ElectrumKitServer = subServer
+	return subServer, macPermissions, nil
+}
+
+// Status returns the state of the Electrum servers, the chain tip, the MWEB
+// sync progress and the connected MWEB P2P peers.
+//
+// NOTE: Part of the ElectrumKitServer interface.
+func (s *Server) Status(ctx context.Context,
+	in *StatusRequest) (*StatusResponse, error) {
+
+	chainClient := s.cfg.ChainClient
+	if chainClient == nil {
+		return nil, ErrElectrumNotActive
+	}
+	client := chainClient.Client()
+
+	bestHash, bestHeight, err := chainClient.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("could not get best block: %w", err)
+	}
+
+	resp := &StatusResponse{
+		Connected:       client.IsConnected(),
+		ServerVersion:   client.ServerVersion(),
+		ProtocolVersion: client.ProtocolVersion(),
+		BlockHeight:     bestHeight,
+		BlockHash:       bestHash.String(),
+		Synced:          chainClient.IsCurrent(),
+		SubscribedScripthashes: uint32(
+			chainClient.SubscribedScripthashes(),
+		),
+		HeaderCacheSize: uint32(chainClient.HeaderCacheSize()),
+		RestAvailable:   chainClient.RESTAvailable(),
+		MwebSync:        marshalMwebSync(chainClient, bestHeight),
+	}
+
+	for _, status := range client.ServerStatuses() {
+		if status.Preferred {
+			resp.Server = status.Addr
+		}
+
+		resp.Servers = append(resp.Servers, &ElectrumServer{
+			Address:         status.Addr,
+			Connected:       status.Connected,
+			Preferred:       status.Preferred,
+			ServerVersion:   status.ServerVersion,
+			ProtocolVersion: status.ProtocolVersion,
+			LatencyMs:       status.Latency.Milliseconds(),
+			Failures:        uint32(status.Failures),
+			TipHeight:       status.TipHeight,
+		})
+	}
+
+	if err := client.QuorumErr(); err != nil {
+		resp.QuorumError = err.Error()
+	}
+
+	for _, p := range chainClient.MwebPeers() {
+		resp.MwebPeers = append(resp.MwebPeers, &MwebPeer{
+			Address:         p.Addr(),
+			UserAgent:       p.UserAgent(),
+			ProtocolVersion: p.ProtocolVersion(),
+			LastBlock:       p.LastBlock(),
+			PingTime:        p.LastPingMicros(),
+		})
+	}
+
+	return resp, nil
+}
+
+// marshalMwebSync returns the progress of the MWEB UTXO set sync towards the
+// given chain tip.
+func marshalMwebSync(chainClient *electrum.ChainClient,
+	bestHeight int32) *MwebSyncStatus {
+
+	height, active := chainClient.MwebSyncHeight()
+	if !active {
+		return &MwebSyncStatus{}
+	}
+
+	status := &MwebSyncStatus{
+		Active: true,
+		Synced: chainClient.IsMwebSynced(),
+		Height: height,
+	}
+
+	switch {
+	case status.Synced || int64(height) >= int64(bestHeight):
+		status.Progress = 1
+
+	case bestHeight > 0:
+		status.Progress = float64(height) / float64(bestHeight)
+	}
+
+	return status
+}
+
+// SubscribeConnectionEvents sends an event every time one of the Electrum
+// servers is connected or disconnected.
+//
+// NOTE: Part of the ElectrumKitServer interface.
+func (s *Server) SubscribeConnectionEvents(
+	in *SubscribeConnectionEventsRequest,
+	stream ElectrumKit_SubscribeConnectionEventsServer) error {
+
+	if s.cfg.ChainClient == nil {
+		return ErrElectrumNotActive
+	}
+
+	client, err := s.cfg.ChainClient.Client().SubscribeConnectionEvents()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case update := <-client.Updates():
+			event, ok := update.(electrum.ConnectionEvent)
+			if !ok {
+				return fmt.Errorf("unexpected connection "+
+					"event: %v", update)
+			}
+
+			rpcEvent := &ConnectionEvent{
+				Server:    event.Addr,
+				Connected: event.Connected,
+				Timestamp: event.Timestamp.Unix(),
+			}
+			if event.Err != nil {
+				rpcEvent.Error = event.Err.Error()
+			}
+
+			if err := stream.Send(rpcEvent); err != nil {
+				return err
+			}
+
+		// If the stream's context is cancelled, return an error.
+		case <-stream.Context().Done():
+			log.Debugf("electrum connection event stream cancelled")
+			return stream.Context().Err()
+
+		// If the subscribe client terminates, exit with an error.
+		case <-client.Quit():
+			return errors.New("electrum connection event " +
+				"subscription terminated")
+		}
+	}
+}
@@ -83,6 +83,10 @@ type AddInvoiceConfig struct {
 	// GetAlias allows the peer's alias SCID to be retrieved for private
 	// option_scid_alias channels.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// BestHeight returns the current best block height, which is used to
+	// compute the maximum expiry of blinded paths.
+	BestHeight func() (uint32, error)
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -136,6 +140,14 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually
 	// used to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// Blind signals that the invoice should contain blinded paths to our
+	// node rather than route hints.
+	Blind bool
+
+	// MaxBlindedPaths is the maximum number of blinded paths to include
+	// in a blinded invoice. If zero, DefaultMaxBlindedPaths is used.
+	MaxBlindedPaths int
 }
 
 // paymentHashAndPreimage returns the payment hash and preimage for this invoice
@@ -378,6 +390,24 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		options = append(options, zpay32.CLTVExpiry(defaultCLTVExpiry))
 	}
 
+	// Blinded paths replace route hints, and the payment address that
+	// identifies AMP payments can't be carried in the path ID.
+	if invoice.Blind {
+		switch {
+		case invoice.Private || len(invoice.RouteHints) > 0:
+			return nil, nil, errors.New("blinded invoices cannot " +
+				"contain route hints")
+
+		case invoice.Amp:
+			return nil, nil, errors.New("blinded paths are not " +
+				"supported for AMP invoices")
+
+		case invoice.MaxBlindedPaths < 0:
+			return nil, nil, errors.New("maximum number of " +
+				"blinded paths must not be negative")
+		}
+	}
+
 	// We make sure that the given invoice routing hints number is within
 	// the valid range
 	if len(invoice.RouteHints) > maxHopHints {
@@ -447,6 +477,32 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		return nil, nil, err
 	}
 
+	// If requested, add blinded paths to our node that expire along with
+	// the invoice. The final hop of each path uses the payment address as
+	// its path ID, so that we can match payments to the invoice.
+	if invoice.Blind {
+		maxPaths := invoice.MaxBlindedPaths
+		if maxPaths == 0 {
+			maxPaths = DefaultMaxBlindedPaths
+		}
+
+		params, err := newBlindedPathParams(
+			cfg, paymentAddr, payReq.Expiry(),
+			uint16(payReq.MinFinalCLTVExpiry()),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		payReq.BlindedPaymentPaths, err = buildBlindedPaymentPaths(
+			cfg, amtMSat, maxPaths, params,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create blinded "+
+				"paths: %w", err)
+		}
+	}
+
 	payReqString, err := payReq.Encode(zpay32.MessageSigner{
 		SignCompact: func(msg []byte) ([]byte, error) {
 			return cfg.NodeSigner.SignMessageCompact(msg, false)
@@ -497,6 +553,15 @@ func chanCanBeHopHint(channel *HopHintInfo, cfg *SelectHopHintsCfg) (
 		return nil, false
 	}
 
+	return chanRemotePolicy(channel, cfg)
+}
+
+// chanRemotePolicy returns the policy that the remote party of the target
+// channel applies to HTLCs it forwards to us, if the channel is active and the
+// remote party is publicly advertised.
+func chanRemotePolicy(channel *HopHintInfo, cfg *SelectHopHintsCfg) (
+	*channeldb.ChannelEdgePolicy, bool) {
+
 	// Make sure the channel is active.
 	if !channel.IsActive {
 		log.Debugf("Skipping channel %v due to not "+
@@ -708,14 +773,19 @@ func getPotentialHints(cfg *SelectHopHintsCfg) ([]*channeldb.OpenChannel,
 	}
 
 	// Sort the channels in descending remote balance.
+	sortByRemoteBalance(privateChannels)
+
+	return privateChannels, nil
+}
+
+// sortByRemoteBalance sorts the given channels in descending remote balance.
+func sortByRemoteBalance(channels []*channeldb.OpenChannel) {
 	compareRemoteBalance := func(i, j int) bool {
-		iBalance := privateChannels[i].LocalCommitment.RemoteBalance
-		jBalance := privateChannels[j].LocalCommitment.RemoteBalance
+		iBalance := channels[i].LocalCommitment.RemoteBalance
+		jBalance := channels[j].LocalCommitment.RemoteBalance
 		return iBalance > jBalance
 	}
-	sort.Slice(privateChannels, compareRemoteBalance)
-
-	return privateChannels, nil
+	sort.Slice(channels, compareRemoteBalance)
 }
 
 // shouldIncludeChannel returns true if the channel passes all the checks to
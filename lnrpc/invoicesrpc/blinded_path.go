@@ -0,0 +1,289 @@
+package invoicesrpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/record"
+	"github.com/ltcsuite/lnd/zpay32"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+)
+
+const (
+	// DefaultMaxBlindedPaths is the maximum number of blinded paths that
+	// will be included in an invoice if the caller doesn't specify one.
+	DefaultMaxBlindedPaths = 3
+
+	// blindedPathExpiryPadding is the number of blocks that are added on
+	// top of the invoice's expiry when computing the maximum expiry that
+	// the hops of a blinded path will accept, to leave some room for
+	// blocks that are mined while a payment is in flight.
+	blindedPathExpiryPadding = 144
+)
+
+var (
+	// ErrNoBlindedPathCandidates is returned when none of our channels
+	// can be used to construct a blinded path to us.
+	ErrNoBlindedPathCandidates = errors.New("no channels available to " +
+		"construct a blinded path")
+)
+
+// introductionCandidate is a channel whose remote party can act as the
+// introduction node of a blinded path to us.
+type introductionCandidate struct {
+	// info holds the relevant information about the channel.
+	info *HopHintInfo
+
+	// policy is the policy that the remote party applies to HTLCs that
+	// it forwards to us over the channel.
+	policy *channeldb.ChannelEdgePolicy
+}
+
+// selectIntroductionCandidates returns up to maxPaths channels that can be
+// used to construct blinded paths to us, ordered by descending inbound
+// capacity. Unlike hop hints, both public and private channels are eligible
+// as the identity of the introduction node is revealed in either case, but
+// the remote party must be publicly advertised for payers to be able to
+// reach it.
+func selectIntroductionCandidates(cfg *SelectHopHintsCfg,
+	amtMSat lnwire.MilliSatoshi,
+	maxPaths int) ([]*introductionCandidate, error) {
+
+	openChannels, err := cfg.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort the channels in descending remote balance, so that we prefer
+	// the introduction nodes that are most likely to be able to forward
+	// the payment to us.
+	sortByRemoteBalance(openChannels)
+
+	var (
+		candidates = make([]*introductionCandidate, 0, maxPaths)
+		seenPeers  = make(map[[33]byte]struct{})
+	)
+	for _, channel := range openChannels {
+		if len(candidates) >= maxPaths {
+			break
+		}
+
+		// There is no need to include more than one path per peer,
+		// as the introduction node picks the channel to forward over
+		// itself.
+		var remotePub [33]byte
+		copy(remotePub[:], channel.IdentityPub.SerializeCompressed())
+		if _, ok := seenPeers[remotePub]; ok {
+			continue
+		}
+
+		if channel.LocalCommitment.RemoteBalance < amtMSat {
+			continue
+		}
+
+		chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+		info := newHopHintInfo(channel, cfg.IsChannelActive(chanID))
+
+		policy, ok := chanRemotePolicy(info, cfg)
+		if !ok || policy == nil {
+			continue
+		}
+
+		// Private channels that negotiated the scid alias feature are
+		// only known to our peer by their alias.
+		if !info.IsPublic && info.ScidAliasFeature {
+			alias, err := cfg.GetAlias(chanID)
+			if err != nil || alias.IsDefault() {
+				continue
+			}
+
+			info.ShortChannelID = alias.ToUint64()
+		}
+
+		seenPeers[remotePub] = struct{}{}
+		candidates = append(candidates, &introductionCandidate{
+			info:   info,
+			policy: policy,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrNoBlindedPathCandidates
+	}
+
+	return candidates, nil
+}
+
+// blindedPathParams holds the parameters that are shared by all the blinded
+// paths of an invoice.
+type blindedPathParams struct {
+	// nodeKey is our own public key, which is the final hop of the path.
+	nodeKey *btcec.PublicKey
+
+	// paymentAddr is the payment address of the invoice, which is used
+	// as the path ID of the final hop so that we can recognize payments
+	// that are made to the invoice over the path.
+	paymentAddr [32]byte
+
+	// finalCltvDelta is the final expiry delta of the invoice.
+	finalCltvDelta uint16
+
+	// maxCltvExpiry is the maximum expiry height that we will accept for
+	// payments to the invoice.
+	maxCltvExpiry uint32
+}
+
+// newBlindedPathParams derives the shared blinded path parameters for an
+// invoice from its expiry and final CLTV delta.
+func newBlindedPathParams(cfg *AddInvoiceConfig, paymentAddr [32]byte,
+	expiry time.Duration, finalCltvDelta uint16) (*blindedPathParams,
+	error) {
+
+	sourceNode, err := cfg.Graph.SourceNode()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch source node: %w", err)
+	}
+
+	nodeKey, err := sourceNode.PubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	bestHeight, err := cfg.BestHeight()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch best height: %w", err)
+	}
+
+	expiryBlocks := uint32(
+		expiry / cfg.ChainParams.TargetTimePerBlock,
+	)
+
+	return &blindedPathParams{
+		nodeKey:        nodeKey,
+		paymentAddr:    paymentAddr,
+		finalCltvDelta: finalCltvDelta,
+		maxCltvExpiry: bestHeight + expiryBlocks +
+			uint32(finalCltvDelta) + blindedPathExpiryPadding,
+	}, nil
+}
+
+// buildBlindedPaymentPath constructs a blinded payment path to us with the
+// remote party of the given channel as the introduction node.
+func buildBlindedPaymentPath(candidate *introductionCandidate,
+	params *blindedPathParams) (*zpay32.BlindedPaymentPath, error) {
+
+	var (
+		policy  = candidate.policy
+		feeRate = uint32(policy.FeeProportionalMillionths)
+		scid    = lnwire.NewShortChanIDFromInt(
+			candidate.info.ShortChannelID,
+		)
+	)
+
+	// The introduction node needs to know which channel to forward the
+	// payment over and which fees and expiry delta to apply. It will
+	// accept a larger expiry than we do, as its own expiry delta is
+	// added on top of ours.
+	introData := &record.BlindedRouteData{
+		ShortChannelID: &scid,
+		RelayInfo: &record.PaymentRelayInfo{
+			CltvExpiryDelta: policy.TimeLockDelta,
+			FeeRate:         feeRate,
+			BaseFee:         uint32(policy.FeeBaseMSat),
+		},
+		Constraints: &record.PaymentConstraints{
+			MaxCltvExpiry: params.maxCltvExpiry +
+				uint32(policy.TimeLockDelta),
+			HtlcMinimumMsat: policy.MinHTLC,
+		},
+	}
+
+	// The final hop only needs the path ID to identify the invoice that is
+	// being paid.
+	finalData := &record.BlindedRouteData{
+		PathID: params.paymentAddr[:],
+		Constraints: &record.PaymentConstraints{
+			MaxCltvExpiry:   params.maxCltvExpiry,
+			HtlcMinimumMsat: policy.MinHTLC,
+		},
+	}
+
+	introPlainText, err := record.EncodeBlindedRouteData(introData)
+	if err != nil {
+		return nil, err
+	}
+
+	finalPlainText, err := record.EncodeBlindedRouteData(finalData)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := sphinx.BuildBlindedPath(sessionKey, []*sphinx.HopInfo{
+		{
+			NodePub:   candidate.info.RemotePubkey,
+			PlainText: introPlainText,
+		},
+		{
+			NodePub:   params.nodeKey,
+			PlainText: finalPlainText,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to build blinded path: %w", err)
+	}
+
+	var htlcMax uint64
+	if policy.MessageFlags.HasMaxHtlc() {
+		htlcMax = uint64(policy.MaxHTLC)
+	}
+
+	// As we don't charge fees for payments to ourselves, the aggregate
+	// fees of the path are those of the introduction node.
+	return &zpay32.BlindedPaymentPath{
+		FeeBaseMsat: uint32(policy.FeeBaseMSat),
+		FeeRate:     feeRate,
+		CltvExpiryDelta: policy.TimeLockDelta +
+			params.finalCltvDelta,
+		HTLCMinMsat:                 uint64(policy.MinHTLC),
+		HTLCMaxMsat:                 htlcMax,
+		Features:                    lnwire.EmptyFeatureVector(),
+		FirstEphemeralBlindingPoint: path.BlindingPoint,
+		IntroductionNode:            path.IntroductionPoint,
+		Hops:                        path.BlindedHops,
+	}, nil
+}
+
+// buildBlindedPaymentPaths constructs up to maxPaths blinded payment paths to
+// us for an invoice of the given amount.
+func buildBlindedPaymentPaths(cfg *AddInvoiceConfig,
+	amtMSat lnwire.MilliSatoshi, maxPaths int,
+	params *blindedPathParams) ([]*zpay32.BlindedPaymentPath, error) {
+
+	candidates, err := selectIntroductionCandidates(
+		newSelectHopHintsCfg(cfg, maxPaths), amtMSat, maxPaths,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]*zpay32.BlindedPaymentPath, 0, len(candidates))
+	for _, candidate := range candidates {
+		path, err := buildBlindedPaymentPath(candidate, params)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,110 @@
+package invoicesrpc
+
+import (
+	"bytes"
+	"testing"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/record"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildBlindedPaymentPath tests that a blinded payment path to us carries
+// the aggregate policy of the introduction node, and that both the
+// introduction node and we can decrypt our route blinding data.
+func TestBuildBlindedPaymentPath(t *testing.T) {
+	t.Parallel()
+
+	introKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	ourKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	candidate := &introductionCandidate{
+		info: &HopHintInfo{
+			RemotePubkey:   introKey.PubKey(),
+			ShortChannelID: 12345,
+		},
+		policy: &channeldb.ChannelEdgePolicy{
+			MessageFlags:              lnwire.ChanUpdateRequiredMaxHtlc,
+			TimeLockDelta:             40,
+			MinHTLC:                   1000,
+			MaxHTLC:                   1_000_000,
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 100,
+		},
+	}
+
+	params := &blindedPathParams{
+		nodeKey:        ourKey.PubKey(),
+		paymentAddr:    [32]byte{1, 2, 3},
+		finalCltvDelta: 80,
+		maxCltvExpiry:  2000,
+	}
+
+	path, err := buildBlindedPaymentPath(candidate, params)
+	require.NoError(t, err)
+
+	require.Equal(t, introKey.PubKey(), path.IntroductionNode)
+	require.Len(t, path.Hops, 2)
+	require.EqualValues(t, 1000, path.FeeBaseMsat)
+	require.EqualValues(t, 100, path.FeeRate)
+	require.EqualValues(t, 120, path.CltvExpiryDelta)
+	require.EqualValues(t, 1000, path.HTLCMinMsat)
+	require.EqualValues(t, 1_000_000, path.HTLCMaxMsat)
+
+	// decrypt decrypts the route blinding data of a hop of the path with
+	// the given node key.
+	decrypt := func(key *btcec.PrivateKey, blinding *btcec.PublicKey,
+		cipherText []byte) *record.BlindedRouteData {
+
+		router := sphinx.NewRouter(
+			&sphinx.PrivKeyECDH{PrivKey: key},
+			&chaincfg.MainNetParams, sphinx.NewMemoryReplayLog(),
+		)
+
+		plainText, err := router.DecryptBlindedHopData(
+			blinding, cipherText,
+		)
+		require.NoError(t, err)
+
+		data, err := record.DecodeBlindedRouteData(
+			bytes.NewReader(plainText),
+		)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	// The introduction node learns the channel to forward over, along
+	// with the policy that it applies.
+	blinding := path.FirstEphemeralBlindingPoint
+	introData := decrypt(introKey, blinding, path.Hops[0].CipherText)
+	require.NotNil(t, introData.ShortChannelID)
+	require.EqualValues(t, 12345, introData.ShortChannelID.ToUint64())
+	require.Equal(t, &record.PaymentRelayInfo{
+		CltvExpiryDelta: 40,
+		FeeRate:         100,
+		BaseFee:         1000,
+	}, introData.RelayInfo)
+	require.EqualValues(t, 2040, introData.Constraints.MaxCltvExpiry)
+
+	// We find the payment address of the invoice in our data, using the
+	// blinding point that the introduction node hands to us.
+	introRouter := sphinx.NewRouter(
+		&sphinx.PrivKeyECDH{PrivKey: introKey},
+		&chaincfg.MainNetParams, sphinx.NewMemoryReplayLog(),
+	)
+	nextBlinding, err := introRouter.NextEphemeral(blinding)
+	require.NoError(t, err)
+
+	finalData := decrypt(ourKey, nextBlinding, path.Hops[1].CipherText)
+	require.Equal(t, params.paymentAddr[:], finalData.PathID)
+	require.Nil(t, finalData.ShortChannelID)
+	require.EqualValues(t, 2000, finalData.Constraints.MaxCltvExpiry)
+}
@@ -176,6 +176,7 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 		IsKeysend:       invoice.IsKeysend(),
 		PaymentAddr:     invoice.Terms.PaymentAddr[:],
 		IsAmp:           invoice.IsAMP(),
+		IsBlinded:       len(decoded.BlindedPaymentPaths) > 0,
 	}
 
 	rpcInvoice.AmpInvoiceState = make(map[string]*lnrpc.AMPInvoiceState)
@@ -266,6 +267,53 @@ func CreateRPCRouteHints(routeHints [][]zpay32.HopHint) []*lnrpc.RouteHint {
 	return res
 }
 
+// CreateRPCBlindedPayments takes a set of zpay32.BlindedPaymentPath and
+// converts them into a set of lnrpc.BlindedPaymentPath.
+func CreateRPCBlindedPayments(
+	blindedPaths []*zpay32.BlindedPaymentPath) []*lnrpc.BlindedPaymentPath {
+
+	var res []*lnrpc.BlindedPaymentPath
+	for _, path := range blindedPaths {
+		var features []lnrpc.FeatureBit
+		if path.Features != nil {
+			for bit := range path.Features.Features() {
+				features = append(
+					features, lnrpc.FeatureBit(bit),
+				)
+			}
+		}
+
+		hops := make([]*lnrpc.BlindedHop, 0, len(path.Hops))
+		for _, hop := range path.Hops {
+			hops = append(hops, &lnrpc.BlindedHop{
+				BlindedNode: hop.BlindedNodePub.
+					SerializeCompressed(),
+				EncryptedBlob: hop.CipherText,
+			})
+		}
+
+		introNode := path.IntroductionNode.SerializeCompressed()
+		blindingPoint := path.FirstEphemeralBlindingPoint.
+			SerializeCompressed()
+
+		res = append(res, &lnrpc.BlindedPaymentPath{
+			BlindedPath: &lnrpc.BlindedPath{
+				IntroductionNode: introNode,
+				BlindingPoint:    blindingPoint,
+				BlindedHops:      hops,
+			},
+			BaseFeeMsat:         uint64(path.FeeBaseMsat),
+			ProportionalFeeRate: path.FeeRate,
+			TotalCltvDelta:      uint32(path.CltvExpiryDelta),
+			HtlcMinMsat:         path.HTLCMinMsat,
+			HtlcMaxMsat:         path.HTLCMaxMsat,
+			Features:            features,
+		})
+	}
+
+	return res
+}
+
 // CreateZpay32HopHints takes in the lnrpc form of route hints and converts them
 // into an invoice decoded form.
 func CreateZpay32HopHints(routeHints []*lnrpc.RouteHint) ([][]zpay32.HopHint, error) {
@@ -21,7 +21,8 @@ type LightningClient interface {
 	// lncli: `walletbalance`
 	// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
 	// confirmed unspent outputs and all unconfirmed unspent outputs under control
-	// of the wallet.
+	// of the wallet. Funds held in the MWEB extension block are also reported
+	// separately from the canonical funds that can be used to open channels.
 	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
 	// lncli: `channelbalance`
 	// ChannelBalance returns a report on the total funds across all open channels,
@@ -52,7 +53,9 @@ type LightningClient interface {
 	// Deprecated, use walletrpc.ListUnspent instead.
 	//
 	// ListUnspent returns a list of all utxos spendable by the wallet with a
-	// number of confirmations between the specified minimum and maximum.
+	// number of confirmations between the specified minimum and maximum. The
+	// stealth address and raw output of MWEB utxos are omitted, only their
+	// output hash is returned.
 	ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error)
 	// SubscribeTransactions creates a uni-directional stream from the server to
 	// the client in which any newly discovered transactions relevant to the
@@ -157,6 +160,13 @@ type LightningClient interface {
 	// Alternatively, this can be used to interactively drive PSBT signing for
 	// funding for partially complete funding transactions.
 	FundingStateStep(ctx context.Context, in *FundingTransitionMsg, opts ...grpc.CallOption) (*FundingStateStepResp, error)
+	// lncli: `bumpchannelopen`
+	// BumpChannelOpen replaces the funding transaction of a pending dual funded
+	// channel that we opened with one paying a higher fee rate. The replacement
+	// is constructed with the remote peer again, and both peers keep their
+	// contributions to the channel. Once either funding transaction confirms,
+	// the channel continues under its funding outpoint.
+	BumpChannelOpen(ctx context.Context, in *BumpChannelOpenRequest, opts ...grpc.CallOption) (*BumpChannelOpenResponse, error)
 	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	// OpenChannel requests are sent to the client and the client responds with
 	// a boolean that tells LND whether or not to accept the channel. This allows
@@ -172,6 +182,21 @@ type LightningClient interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// lncli: `splicein`, `spliceout`
+	// SpliceChannel splices funds into or out of an active channel identified by
+	// its channel outpoint (ChannelPoint), without closing it. The channel is
+	// offline while the splice is pending: no HTLCs can be sent, received or
+	// forwarded over it, as commitments aren't signed for both the old and the
+	// new funding output. Once the splice transaction has confirmed and both
+	// peers locked it, the channel continues under the new funding outpoint.
+	SpliceChannel(ctx context.Context, in *SpliceChannelRequest, opts ...grpc.CallOption) (*SpliceChannelResponse, error)
+	// lncli: `upgradechannel`
+	// UpgradeChannel upgrades the commitment type of an active channel in place,
+	// without closing it. The channel is quiesced, and both peers sign new
+	// commitments of the upgraded type. The channel must not carry any HTLCs, and
+	// both peers must support dynamic commitments. Upgrades to taproot channels
+	// aren't supported, as they require a new funding output.
+	UpgradeChannel(ctx context.Context, in *UpgradeChannelRequest, opts ...grpc.CallOption) (*UpgradeChannelResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -322,6 +347,9 @@ type LightningClient interface {
 	// As a result each message can only contain 50k entries. Each response has
 	// the index offset of the last entry. The index offset can be provided to the
 	// request to allow the caller to skip a series of records.
+	//
+	// By default only settled forwards are returned. Failed forwards and forwards
+	// that were rejected by our node can be requested using the outcomes field.
 	ForwardingHistory(ctx context.Context, in *ForwardingHistoryRequest, opts ...grpc.CallOption) (*ForwardingHistoryResponse, error)
 	// lncli: `exportchanbackup`
 	// ExportChannelBackup attempts to return an encrypted static channel backup
@@ -399,6 +427,26 @@ type LightningClient interface {
 	// needs to be compiled with  the `dev` build tag, and the message type to
 	// override should be specified in lnd's experimental protocol configuration.
 	SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error)
+	// lncli: `sendonionmsg`
+	// SendOnionMessage sends an onion message to a node or over a blinded path.
+	// Unlike a payment, an onion message doesn't lock up any funds along its
+	// path and is delivered on a best-effort basis, so there is no guarantee or
+	// acknowledgement of its delivery.
+	SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error)
+	// lncli: `subscribeonionmsg`
+	// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+	// that are addressed to this node.
+	SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error)
+	// lncli: `addoffer`
+	// AddOffer creates a BOLT 12 offer, a reusable payment code that payers use
+	// to request invoices from this node over onion messages. Offers aren't
+	// stored, so they can be created freely but can't be listed or revoked.
+	AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*AddOfferResponse, error)
+	// lncli: `decodeoffer`
+	// DecodeOffer takes an encoded BOLT 12 offer string and attempts to decode
+	// it, returning a full description of the conditions encoded within the
+	// offer.
+	DecodeOffer(ctx context.Context, in *DecodeOfferRequest, opts ...grpc.CallOption) (*Offer, error)
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -735,6 +783,15 @@ func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTrans
 	return out, nil
 }
 
+func (c *lightningClient) BumpChannelOpen(ctx context.Context, in *BumpChannelOpenRequest, opts ...grpc.CallOption) (*BumpChannelOpenResponse, error) {
+	out := new(BumpChannelOpenResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BumpChannelOpen", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
 	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/ChannelAcceptor", opts...)
 	if err != nil {
@@ -798,6 +855,24 @@ func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
 	return m, nil
 }
 
+func (c *lightningClient) SpliceChannel(ctx context.Context, in *SpliceChannelRequest, opts ...grpc.CallOption) (*SpliceChannelResponse, error) {
+	out := new(SpliceChannelResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SpliceChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpgradeChannel(ctx context.Context, in *UpgradeChannelRequest, opts ...grpc.CallOption) (*UpgradeChannelResponse, error) {
+	out := new(UpgradeChannelResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpgradeChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
 	out := new(AbandonChannelResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, opts...)
@@ -1300,6 +1375,65 @@ func (x *lightningSubscribeCustomMessagesClient) Recv() (*CustomMessage, error)
 	return m, nil
 }
 
+func (c *lightningClient) SendOnionMessage(ctx context.Context, in *SendOnionMessageRequest, opts ...grpc.CallOption) (*SendOnionMessageResponse, error) {
+	out := new(SendOnionMessageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SendOnionMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeOnionMessages(ctx context.Context, in *SubscribeOnionMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeOnionMessagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/SubscribeOnionMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeOnionMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeOnionMessagesClient interface {
+	Recv() (*OnionMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeOnionMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeOnionMessagesClient) Recv() (*OnionMessage, error) {
+	m := new(OnionMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) AddOffer(ctx context.Context, in *AddOfferRequest, opts ...grpc.CallOption) (*AddOfferResponse, error) {
+	out := new(AddOfferResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AddOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DecodeOffer(ctx context.Context, in *DecodeOfferRequest, opts ...grpc.CallOption) (*Offer, error) {
+	out := new(Offer)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DecodeOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ListAliases(ctx context.Context, in *ListAliasesRequest, opts ...grpc.CallOption) (*ListAliasesResponse, error) {
 	out := new(ListAliasesResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListAliases", in, out, opts...)
@@ -1325,7 +1459,8 @@ type LightningServer interface {
 	// lncli: `walletbalance`
 	// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
 	// confirmed unspent outputs and all unconfirmed unspent outputs under control
-	// of the wallet.
+	// of the wallet. Funds held in the MWEB extension block are also reported
+	// separately from the canonical funds that can be used to open channels.
 	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
 	// lncli: `channelbalance`
 	// ChannelBalance returns a report on the total funds across all open channels,
@@ -1356,7 +1491,9 @@ type LightningServer interface {
 	// Deprecated, use walletrpc.ListUnspent instead.
 	//
 	// ListUnspent returns a list of all utxos spendable by the wallet with a
-	// number of confirmations between the specified minimum and maximum.
+	// number of confirmations between the specified minimum and maximum. The
+	// stealth address and raw output of MWEB utxos are omitted, only their
+	// output hash is returned.
 	ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error)
 	// SubscribeTransactions creates a uni-directional stream from the server to
 	// the client in which any newly discovered transactions relevant to the
@@ -1461,6 +1598,13 @@ type LightningServer interface {
 	// Alternatively, this can be used to interactively drive PSBT signing for
 	// funding for partially complete funding transactions.
 	FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error)
+	// lncli: `bumpchannelopen`
+	// BumpChannelOpen replaces the funding transaction of a pending dual funded
+	// channel that we opened with one paying a higher fee rate. The replacement
+	// is constructed with the remote peer again, and both peers keep their
+	// contributions to the channel. Once either funding transaction confirms,
+	// the channel continues under its funding outpoint.
+	BumpChannelOpen(context.Context, *BumpChannelOpenRequest) (*BumpChannelOpenResponse, error)
 	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	// OpenChannel requests are sent to the client and the client responds with
 	// a boolean that tells LND whether or not to accept the channel. This allows
@@ -1476,6 +1620,21 @@ type LightningServer interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	// lncli: `splicein`, `spliceout`
+	// SpliceChannel splices funds into or out of an active channel identified by
+	// its channel outpoint (ChannelPoint), without closing it. The channel is
+	// offline while the splice is pending: no HTLCs can be sent, received or
+	// forwarded over it, as commitments aren't signed for both the old and the
+	// new funding output. Once the splice transaction has confirmed and both
+	// peers locked it, the channel continues under the new funding outpoint.
+	SpliceChannel(context.Context, *SpliceChannelRequest) (*SpliceChannelResponse, error)
+	// lncli: `upgradechannel`
+	// UpgradeChannel upgrades the commitment type of an active channel in place,
+	// without closing it. The channel is quiesced, and both peers sign new
+	// commitments of the upgraded type. The channel must not carry any HTLCs, and
+	// both peers must support dynamic commitments. Upgrades to taproot channels
+	// aren't supported, as they require a new funding output.
+	UpgradeChannel(context.Context, *UpgradeChannelRequest) (*UpgradeChannelResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -1626,6 +1785,9 @@ type LightningServer interface {
 	// As a result each message can only contain 50k entries. Each response has
 	// the index offset of the last entry. The index offset can be provided to the
 	// request to allow the caller to skip a series of records.
+	//
+	// By default only settled forwards are returned. Failed forwards and forwards
+	// that were rejected by our node can be requested using the outcomes field.
 	ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error)
 	// lncli: `exportchanbackup`
 	// ExportChannelBackup attempts to return an encrypted static channel backup
@@ -1703,6 +1865,26 @@ type LightningServer interface {
 	// needs to be compiled with  the `dev` build tag, and the message type to
 	// override should be specified in lnd's experimental protocol configuration.
 	SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error
+	// lncli: `sendonionmsg`
+	// SendOnionMessage sends an onion message to a node or over a blinded path.
+	// Unlike a payment, an onion message doesn't lock up any funds along its
+	// path and is delivered on a best-effort basis, so there is no guarantee or
+	// acknowledgement of its delivery.
+	SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error)
+	// lncli: `subscribeonionmsg`
+	// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+	// that are addressed to this node.
+	SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error
+	// lncli: `addoffer`
+	// AddOffer creates a BOLT 12 offer, a reusable payment code that payers use
+	// to request invoices from this node over onion messages. Offers aren't
+	// stored, so they can be created freely but can't be listed or revoked.
+	AddOffer(context.Context, *AddOfferRequest) (*AddOfferResponse, error)
+	// lncli: `decodeoffer`
+	// DecodeOffer takes an encoded BOLT 12 offer string and attempts to decode
+	// it, returning a full description of the conditions encoded within the
+	// offer.
+	DecodeOffer(context.Context, *DecodeOfferRequest) (*Offer, error)
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -1794,12 +1976,21 @@ func (UnimplementedLightningServer) BatchOpenChannel(context.Context, *BatchOpen
 func (UnimplementedLightningServer) FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FundingStateStep not implemented")
 }
+func (UnimplementedLightningServer) BumpChannelOpen(context.Context, *BumpChannelOpenRequest) (*BumpChannelOpenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BumpChannelOpen not implemented")
+}
 func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorServer) error {
 	return status.Errorf(codes.Unimplemented, "method ChannelAcceptor not implemented")
 }
 func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
 	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
 }
+func (UnimplementedLightningServer) SpliceChannel(context.Context, *SpliceChannelRequest) (*SpliceChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpliceChannel not implemented")
+}
+func (UnimplementedLightningServer) UpgradeChannel(context.Context, *UpgradeChannelRequest) (*UpgradeChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpgradeChannel not implemented")
+}
 func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
 }
@@ -1914,6 +2105,18 @@ func (UnimplementedLightningServer) SendCustomMessage(context.Context, *SendCust
 func (UnimplementedLightningServer) SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeCustomMessages not implemented")
 }
+func (UnimplementedLightningServer) SendOnionMessage(context.Context, *SendOnionMessageRequest) (*SendOnionMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOnionMessage not implemented")
+}
+func (UnimplementedLightningServer) SubscribeOnionMessages(*SubscribeOnionMessagesRequest, Lightning_SubscribeOnionMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeOnionMessages not implemented")
+}
+func (UnimplementedLightningServer) AddOffer(context.Context, *AddOfferRequest) (*AddOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOffer not implemented")
+}
+func (UnimplementedLightningServer) DecodeOffer(context.Context, *DecodeOfferRequest) (*Offer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodeOffer not implemented")
+}
 func (UnimplementedLightningServer) ListAliases(context.Context, *ListAliasesRequest) (*ListAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAliases not implemented")
 }
@@ -2395,6 +2598,24 @@ func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_BumpChannelOpen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpChannelOpenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BumpChannelOpen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BumpChannelOpen",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BumpChannelOpen(ctx, req.(*BumpChannelOpenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ChannelAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LightningServer).ChannelAcceptor(&lightningChannelAcceptorServer{stream})
 }
@@ -2442,6 +2663,42 @@ func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SpliceChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpliceChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SpliceChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SpliceChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SpliceChannel(ctx, req.(*SpliceChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_UpgradeChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).UpgradeChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UpgradeChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UpgradeChannel(ctx, req.(*UpgradeChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AbandonChannelRequest)
 	if err := dec(in); err != nil {
@@ -3162,6 +3419,81 @@ func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SendOnionMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOnionMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendOnionMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendOnionMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendOnionMessage(ctx, req.(*SendOnionMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeOnionMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeOnionMessagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeOnionMessages(m, &lightningSubscribeOnionMessagesServer{stream})
+}
+
+type Lightning_SubscribeOnionMessagesServer interface {
+	Send(*OnionMessage) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeOnionMessagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeOnionMessagesServer) Send(m *OnionMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_AddOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddOffer(ctx, req.(*AddOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DecodeOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DecodeOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DecodeOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DecodeOffer(ctx, req.(*DecodeOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListAliasesRequest)
 	if err := dec(in); err != nil {
@@ -3289,6 +3621,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FundingStateStep",
 			Handler:    _Lightning_FundingStateStep_Handler,
 		},
+		{
+			MethodName: "BumpChannelOpen",
+			Handler:    _Lightning_BumpChannelOpen_Handler,
+		},
+		{
+			MethodName: "SpliceChannel",
+			Handler:    _Lightning_SpliceChannel_Handler,
+		},
+		{
+			MethodName: "UpgradeChannel",
+			Handler:    _Lightning_UpgradeChannel_Handler,
+		},
 		{
 			MethodName: "AbandonChannel",
 			Handler:    _Lightning_AbandonChannel_Handler,
@@ -3413,6 +3757,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendCustomMessage",
 			Handler:    _Lightning_SendCustomMessage_Handler,
 		},
+		{
+			MethodName: "SendOnionMessage",
+			Handler:    _Lightning_SendOnionMessage_Handler,
+		},
+		{
+			MethodName: "AddOffer",
+			Handler:    _Lightning_AddOffer_Handler,
+		},
+		{
+			MethodName: "DecodeOffer",
+			Handler:    _Lightning_DecodeOffer_Handler,
+		},
 		{
 			MethodName: "ListAliases",
 			Handler:    _Lightning_ListAliases_Handler,
@@ -3492,6 +3848,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeCustomMessages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeOnionMessages",
+			Handler:       _Lightning_SubscribeOnionMessages_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "lightning.proto",
 }
@@ -146,6 +146,26 @@ func MarshalUtxos(utxos []*lnwallet.Utxo, activeNetParams *chaincfg.Params) (
 	return res, nil
 }
 
+// RedactMwebUtxos strips the stealth address and the raw extension block
+// output from the MWEB UTXOs in the given list, leaving only the output ID to
+// identify them by. The address of an MWEB output links it to the wallet's
+// other MWEB outputs, so it's only exposed by the lower level wallet APIs.
+func RedactMwebUtxos(utxos []*Utxo) {
+	for _, utxo := range utxos {
+		if utxo.AddressType != AddressType_MWEB {
+			continue
+		}
+
+		utxo.Address = ""
+		utxo.PkScript = ""
+		if utxo.MwebOutput != nil {
+			utxo.MwebOutput = &MwebOutput{
+				Hash: utxo.MwebOutput.Hash,
+			}
+		}
+	}
+}
+
 // marshalMwebOutputMessage converts a wire.MwebOutputMessage to the RPC format
 func marshalMwebOutputMessage(msg *wire.MwebOutputMessage) *MwebOutputMessage {
 	if msg == nil {
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/ltcsuite/lnd/channeldb/models"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/htlcswitch"
 	"github.com/ltcsuite/lnd/lnrpc"
 	"github.com/ltcsuite/lnd/lntypes"
@@ -59,7 +60,15 @@ func (r *forwardInterceptor) run() error {
 			return err
 		}
 
-		if err := r.resolveFromClient(resp); err != nil {
+		err = r.resolveFromClient(resp)
+		switch {
+		// A rejected modification leaves the htlc held. The switch
+		// offers it to the client again along with the reason, so
+		// there is no need to tear down the stream.
+		case errors.Is(err, htlcswitch.ErrFwdModificationRejected):
+			log.Debugf("Interceptor modification rejected: %v", err)
+
+		case err != nil:
 			return err
 		}
 	}
@@ -92,6 +101,10 @@ func (r *forwardInterceptor) onIntercept(
 		OnionBlob:               htlc.OnionBlob[:],
 		AutoFailHeight:          htlc.AutoFailHeight,
 	}
+	if htlc.ModificationErr != nil {
+		interceptionRequest.ModificationError =
+			htlc.ModificationErr.Error()
+	}
 
 	return r.stream.Send(interceptionRequest)
 }
@@ -119,6 +132,32 @@ func (r *forwardInterceptor) resolveFromClient(
 			Action: htlcswitch.FwdActionResume,
 		})
 
+	case ResolveHoldForwardAction_RESUME_MODIFIED:
+		var mods htlcswitch.FwdModifications
+		if in.OutAmountMsat != 0 {
+			mods.OutgoingAmount = fn.Some(
+				lnwire.MilliSatoshi(in.OutAmountMsat),
+			)
+		}
+		if in.OutChanId != 0 {
+			mods.OutgoingChanID = fn.Some(
+				lnwire.NewShortChanIDFromInt(in.OutChanId),
+			)
+		}
+
+		// The custom records are validated by the switch, which
+		// reports invalid records like any other rejected
+		// modification.
+		mods.CustomRecords = lnwire.CustomRecords(
+			in.OutWireCustomRecords,
+		)
+
+		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
+			Key:           circuitKey,
+			Action:        htlcswitch.FwdActionResumeModified,
+			Modifications: mods,
+		})
+
 	case ResolveHoldForwardAction_FAIL:
 		// Fail with an encrypted reason.
 		if in.FailureMessage != nil {
@@ -0,0 +1,170 @@
+package routerrpc
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/lnd/channeldb/models"
+	"github.com/ltcsuite/lnd/htlcswitch"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// interceptorStreamMock is a mock of the htlc interceptor stream that lets the
+// test act as the interceptor client.
+type interceptorStreamMock struct {
+	grpc.ServerStream
+
+	requests  chan *ForwardHtlcInterceptRequest
+	responses chan *ForwardHtlcInterceptResponse
+}
+
+func (m *interceptorStreamMock) Send(r *ForwardHtlcInterceptRequest) error {
+	m.requests <- r
+	return nil
+}
+
+func (m *interceptorStreamMock) Recv() (*ForwardHtlcInterceptResponse,
+	error) {
+
+	r, ok := <-m.responses
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return r, nil
+}
+
+// rejectingForwarderMock is a mock of the interceptable switch that rejects
+// all modified forwards the way the switch does, by offering the held forward
+// to the interceptor again.
+type rejectingForwarderMock struct {
+	interceptor htlcswitch.ForwardInterceptor
+	resolutions chan *htlcswitch.FwdResolution
+}
+
+func (m *rejectingForwarderMock) SetInterceptor(
+	interceptor htlcswitch.ForwardInterceptor) {
+
+	m.interceptor = interceptor
+}
+
+func (m *rejectingForwarderMock) Resolve(res *htlcswitch.FwdResolution) error {
+	m.resolutions <- res
+
+	if res.Action != htlcswitch.FwdActionResumeModified {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: policy violated",
+		htlcswitch.ErrFwdModificationRejected)
+
+	if m.interceptor != nil {
+		err := m.interceptor(htlcswitch.InterceptedPacket{
+			IncomingCircuit: res.Key,
+			ModificationErr: err,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// TestForwardInterceptorModificationRejected asserts that a rejected
+// modification is reported to the client without tearing down the stream, so
+// that the held htlc isn't released unmodified.
+func TestForwardInterceptorModificationRejected(t *testing.T) {
+	t.Parallel()
+
+	stream := &interceptorStreamMock{
+		requests:  make(chan *ForwardHtlcInterceptRequest, 1),
+		responses: make(chan *ForwardHtlcInterceptResponse),
+	}
+	forwarder := &rejectingForwarderMock{
+		resolutions: make(chan *htlcswitch.FwdResolution, 1),
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- newForwardInterceptor(forwarder, stream).run()
+	}()
+
+	circuitKey := &CircuitKey{
+		ChanId: 1,
+		HtlcId: 2,
+	}
+	key := models.CircuitKey{
+		ChanID: lnwire.NewShortChanIDFromInt(circuitKey.ChanId),
+		HtlcID: circuitKey.HtlcId,
+	}
+
+	sendResponse := func(resp *ForwardHtlcInterceptResponse) {
+		select {
+		case stream.responses <- resp:
+		case err := <-errChan:
+			t.Fatalf("interceptor stopped: %v", err)
+		case <-time.After(time.Second):
+			t.Fatalf("response not received")
+		}
+	}
+
+	assertResolution := func(action htlcswitch.FwdAction) {
+		select {
+		case res := <-forwarder.resolutions:
+			require.Equal(t, key, res.Key)
+			require.Equal(t, action, res.Action)
+
+		case <-time.After(time.Second):
+			t.Fatalf("resolution not received")
+		}
+	}
+
+	// Resume the htlc with modifications that the switch rejects.
+	sendResponse(&ForwardHtlcInterceptResponse{
+		IncomingCircuitKey: circuitKey,
+		Action:             ResolveHoldForwardAction_RESUME_MODIFIED,
+		OutAmountMsat:      1000,
+	})
+	assertResolution(htlcswitch.FwdActionResumeModified)
+
+	// The rejection is reported to the client by offering the htlc again.
+	select {
+	case req := <-stream.requests:
+		require.Equal(
+			t, circuitKey.ChanId, req.IncomingCircuitKey.ChanId,
+		)
+		require.Equal(
+			t, circuitKey.HtlcId, req.IncomingCircuitKey.HtlcId,
+		)
+		require.Contains(t, req.ModificationError, "policy violated")
+
+	case <-time.After(time.Second):
+		t.Fatalf("rejection not reported")
+	}
+
+	// The stream is still open and the interceptor is still registered,
+	// so the htlc can't have been released unmodified. The client can
+	// now resolve the htlc in a different way.
+	require.NotNil(t, forwarder.interceptor)
+	sendResponse(&ForwardHtlcInterceptResponse{
+		IncomingCircuitKey: circuitKey,
+		Action:             ResolveHoldForwardAction_FAIL,
+	})
+	assertResolution(htlcswitch.FwdActionFail)
+
+	// Closing the stream unregisters the interceptor.
+	close(stream.responses)
+	select {
+	case err := <-errChan:
+		require.ErrorIs(t, err, io.EOF)
+
+	case <-time.After(time.Second):
+		t.Fatalf("interceptor not stopped")
+	}
+	require.Nil(t, forwarder.interceptor)
+}
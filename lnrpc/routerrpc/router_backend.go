@@ -9,12 +9,15 @@ import (
 	math "math"
 	"time"
 
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/bolt12"
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/feature"
 	"github.com/ltcsuite/lnd/htlcswitch"
 	"github.com/ltcsuite/lnd/lnrpc"
 	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/offers"
 	"github.com/ltcsuite/lnd/record"
 	"github.com/ltcsuite/lnd/routing"
 	"github.com/ltcsuite/lnd/routing/route"
@@ -33,6 +36,10 @@ const (
 	// TODO(roasbeef): make this value dynamic based on expected number of
 	// attempts for given amount.
 	DefaultMaxParts = 16
+
+	// DefaultOfferInvoiceTimeout is the default amount of time we wait for
+	// the issuer of an offer to reply with an invoice.
+	DefaultOfferInvoiceTimeout = time.Minute
 )
 
 // RouterBackend contains the backend implementation of the router rpc sub
@@ -64,6 +71,14 @@ type RouterBackend struct {
 		routeHints map[route.Vertex][]*channeldb.CachedEdgePolicy,
 		finalExpiry uint16) (*route.Route, float64, error)
 
+	// FindBlindedRoute is a closure that abstracts away how we locate
+	// routes to the recipient of a blinded payment.
+	FindBlindedRoute func(source route.Vertex, amt lnwire.MilliSatoshi,
+		timePref float64, restrictions *routing.RestrictParams,
+		destCustomRecords record.CustomSet,
+		blindedPayment *routing.BlindedPayment) (*route.Route, float64,
+		error)
+
 	MissionControl MissionControl
 
 	// ActiveNetParams are the network parameters of the primary network
@@ -101,6 +116,11 @@ type RouterBackend struct {
 	// SetChannelAuto exposes the ability to restore automatic channel state
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
+
+	// FetchOfferInvoice requests an invoice for a BOLT 12 offer from its
+	// issuer. It is nil if onion messages are disabled.
+	FetchOfferInvoice func(context.Context,
+		*offers.FetchParams) (*bolt12.Invoice, error)
 }
 
 // MissionControl defines the mission control dependencies of routerrpc.
@@ -157,11 +177,47 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 		return route.NewVertexFromBytes(pubKeyBytes)
 	}
 
-	// Parse the hex-encoded source and target public keys into full public
-	// key objects we can properly manipulate.
-	targetPubKey, err := parsePubKey(in.PubKey)
-	if err != nil {
-		return nil, err
+	// If blinded paths are provided, the route with the highest success
+	// probability to any of them is returned and the target is its blinded
+	// recipient. Otherwise, parse the hex-encoded target public key into
+	// a full public key object we can properly manipulate.
+	var (
+		blindedPayments []*routing.BlindedPayment
+		targetPubKey    route.Vertex
+		err             error
+	)
+	if len(in.BlindedPaymentPaths) > 0 {
+		switch {
+		case in.PubKey != "":
+			return nil, errors.New("pub_key and " +
+				"blinded_payment_paths cannot appear together")
+
+		case len(in.RouteHints) > 0:
+			return nil, errors.New("route_hints and " +
+				"blinded_payment_paths cannot appear together")
+
+		case in.FinalCltvDelta != 0:
+			return nil, errors.New("final_cltv_delta and " +
+				"blinded_payment_paths cannot appear together")
+		}
+
+		for _, rpcPayment := range in.BlindedPaymentPaths {
+			blindedPayment, err := unmarshalBlindedPayment(
+				rpcPayment,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			blindedPayments = append(
+				blindedPayments, blindedPayment,
+			)
+		}
+	} else {
+		targetPubKey, err = parsePubKey(in.PubKey)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var sourcePubKey route.Vertex
@@ -239,20 +295,24 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 
 	// We need to subtract the final delta before passing it into path
 	// finding. The optimal path is independent of the final cltv delta and
-	// the path finding algorithm is unaware of this value.
+	// the path finding algorithm is unaware of this value. Blinded paths
+	// come with their own final delta, which is subtracted for each of
+	// them when the route is found below.
 	finalCLTVDelta := r.DefaultFinalCltvDelta
 	if in.FinalCltvDelta != 0 {
 		finalCLTVDelta = uint16(in.FinalCltvDelta)
 	}
 
-	// Do bounds checking without block padding so we don't give routes
-	// that will leave the router in a zombie payment state.
-	err = routing.ValidateCLTVLimit(cltvLimit, finalCLTVDelta, false)
-	if err != nil {
-		return nil, err
-	}
+	if len(blindedPayments) == 0 {
+		// Do bounds checking without block padding so we don't give
+		// routes that will leave the router in a zombie payment state.
+		err = routing.ValidateCLTVLimit(cltvLimit, finalCLTVDelta, false)
+		if err != nil {
+			return nil, err
+		}
 
-	cltvLimit -= uint32(finalCLTVDelta)
+		cltvLimit -= uint32(finalCLTVDelta)
+	}
 
 	// Parse destination feature bits.
 	features, err := UnmarshalFeatures(in.DestFeatures)
@@ -315,25 +375,37 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 		return nil, err
 	}
 
-	// Convert route hints to an edge map.
-	routeHints, err := unmarshallRouteHints(in.RouteHints)
-	if err != nil {
-		return nil, err
-	}
-	routeHintEdges, err := routing.RouteHintsToEdges(
-		routeHints, targetPubKey,
-	)
-	if err != nil {
-		return nil, err
-	}
-
 	// Query the channel router for a possible path to the destination that
 	// can carry `in.Amt` satoshis _including_ the total fee required on
 	// the route.
-	route, successProb, err := r.FindRoute(
-		sourcePubKey, targetPubKey, amt, in.TimePref, restrictions,
-		customRecords, routeHintEdges, finalCLTVDelta,
+	var (
+		route       *route.Route
+		successProb float64
 	)
+	if len(blindedPayments) > 0 {
+		route, successProb, err = r.findBestBlindedRoute(
+			sourcePubKey, amt, in.TimePref, restrictions,
+			customRecords, blindedPayments,
+		)
+	} else {
+		// Convert route hints to an edge map.
+		routeHints, err := unmarshallRouteHints(in.RouteHints)
+		if err != nil {
+			return nil, err
+		}
+		routeHintEdges, err := routing.RouteHintsToEdges(
+			routeHints, targetPubKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		route, successProb, err = r.FindRoute(
+			sourcePubKey, targetPubKey, amt, in.TimePref,
+			restrictions, customRecords, routeHintEdges,
+			finalCLTVDelta,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -409,6 +481,11 @@ func (r *RouterBackend) MarshallRoute(route *route.Route) (*lnrpc.Route, error)
 			}
 		}
 
+		var blindingPoint []byte
+		if hop.BlindingPoint != nil {
+			blindingPoint = hop.BlindingPoint.SerializeCompressed()
+		}
+
 		resp.Hops[i] = &lnrpc.Hop{
 			ChanId:           hop.ChannelID,
 			ChanCapacity:     int64(chanCapacity),
@@ -424,6 +501,9 @@ func (r *RouterBackend) MarshallRoute(route *route.Route) (*lnrpc.Route, error)
 			TlvPayload:    !hop.LegacyPayload,
 			MppRecord:     mpp,
 			Metadata:      hop.Metadata,
+			EncryptedData: hop.EncryptedData,
+			BlindingPoint: blindingPoint,
+			TotalAmtMsat:  uint64(hop.TotalAmtMsat),
 		}
 		incomingAmt = hop.AmtToForward
 	}
@@ -451,6 +531,14 @@ func UnmarshallHopWithPubkey(rpcHop *lnrpc.Hop, pubkey route.Vertex) (*route.Hop
 		return nil, err
 	}
 
+	var blindingPoint *btcec.PublicKey
+	if len(rpcHop.BlindingPoint) > 0 {
+		blindingPoint, err = btcec.ParsePubKey(rpcHop.BlindingPoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blinding point: %w", err)
+		}
+	}
+
 	return &route.Hop{
 		OutgoingTimeLock: rpcHop.Expiry,
 		AmtToForward:     lnwire.MilliSatoshi(rpcHop.AmtToForwardMsat),
@@ -460,6 +548,9 @@ func UnmarshallHopWithPubkey(rpcHop *lnrpc.Hop, pubkey route.Vertex) (*route.Hop
 		LegacyPayload:    false,
 		MPP:              mpp,
 		AMP:              amp,
+		EncryptedData:    rpcHop.EncryptedData,
+		BlindingPoint:    blindingPoint,
+		TotalAmtMsat:     lnwire.MilliSatoshi(rpcHop.TotalAmtMsat),
 	}, nil
 }
 
@@ -747,6 +838,35 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.PaymentAddr = payAddr
 		payIntent.PaymentRequest = []byte(rpcPayReq.PaymentRequest)
 		payIntent.Metadata = payReq.Metadata
+
+		// If the invoice contains blinded paths, path finding considers
+		// all of them. The recipient identifies the payment by the path
+		// ID in its route blinding data, so the payment address is not
+		// sent. The payment can still be split if the invoice allows
+		// it, as the total amount is passed to the recipient in the
+		// payload of the final hop.
+		if len(payReq.BlindedPaymentPaths) > 0 {
+			switch {
+			case len(payIntent.RouteHints) > 0:
+				return nil, errors.New("route_hints cannot " +
+					"be used with a blinded invoice")
+
+			case payReq.Features.HasFeature(lnwire.AMPOptional):
+				return nil, errors.New("AMP payments to " +
+					"blinded paths are not supported")
+			}
+
+			blindedPayments, err := blindedPaymentsFromInvoice(
+				payReq.BlindedPaymentPaths,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			payIntent.BlindedPayments = blindedPayments
+			payIntent.Target = blindedPayments[0].Target()
+			payIntent.PaymentAddr = nil
+		}
 	} else {
 		// Otherwise, If the payment request field was not specified
 		// (and a custom route wasn't specified), construct the payment
@@ -885,6 +1005,319 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	return payIntent, nil
 }
 
+// findBestBlindedRoute finds a route to each of the blinded payments and
+// returns the one with the highest success probability. The cltv limit of the
+// restrictions must not have any final delta subtracted yet, as it differs
+// between the blinded payments.
+func (r *RouterBackend) findBestBlindedRoute(source route.Vertex,
+	amt lnwire.MilliSatoshi, timePref float64,
+	restrictions *routing.RestrictParams, customRecords record.CustomSet,
+	blindedPayments []*routing.BlindedPayment) (*route.Route, float64,
+	error) {
+
+	var (
+		bestRoute *route.Route
+		bestProb  float64
+		lastErr   error
+	)
+	for _, blindedPayment := range blindedPayments {
+		// Do bounds checking without block padding so we don't give
+		// routes that will leave the router in a zombie payment state.
+		finalCLTVDelta := blindedPayment.FinalCltvDelta()
+		err := routing.ValidateCLTVLimit(
+			restrictions.CltvLimit, finalCLTVDelta, false,
+		)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		pathRestrictions := *restrictions
+		pathRestrictions.CltvLimit -= uint32(finalCLTVDelta)
+
+		route, prob, err := r.FindBlindedRoute(
+			source, amt, timePref, &pathRestrictions,
+			customRecords, blindedPayment,
+		)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if bestRoute == nil || prob > bestProb {
+			bestRoute = route
+			bestProb = prob
+		}
+	}
+
+	if bestRoute == nil {
+		return nil, 0, lastErr
+	}
+
+	return bestRoute, bestProb, nil
+}
+
+// unmarshalBlindedPayment unmarshals a blinded payment path from its rpc
+// representation.
+func unmarshalBlindedPayment(rpcPayment *lnrpc.BlindedPaymentPath) (
+	*routing.BlindedPayment, error) {
+
+	if rpcPayment == nil {
+		return nil, errors.New("nil blinded payment")
+	}
+
+	path, err := UnmarshalBlindedPath(rpcPayment.BlindedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	features, err := UnmarshalFeatures(rpcPayment.Features)
+	if err != nil {
+		return nil, err
+	}
+
+	if rpcPayment.BaseFeeMsat > math.MaxUint32 {
+		return nil, fmt.Errorf("base fee %v exceeds maximum of %v",
+			rpcPayment.BaseFeeMsat, uint32(math.MaxUint32))
+	}
+
+	if rpcPayment.TotalCltvDelta > math.MaxUint16 {
+		return nil, fmt.Errorf("total cltv delta %v exceeds maximum "+
+			"of %v", rpcPayment.TotalCltvDelta, math.MaxUint16)
+	}
+
+	blindedPayment := &routing.BlindedPayment{
+		BlindedPath:         path,
+		BaseFee:             uint32(rpcPayment.BaseFeeMsat),
+		ProportionalFeeRate: rpcPayment.ProportionalFeeRate,
+		CltvExpiryDelta:     uint16(rpcPayment.TotalCltvDelta),
+		HtlcMinimum:         rpcPayment.HtlcMinMsat,
+		HtlcMaximum:         rpcPayment.HtlcMaxMsat,
+		Features:            features,
+	}
+
+	if err := blindedPayment.Validate(); err != nil {
+		return nil, err
+	}
+
+	return blindedPayment, nil
+}
+
+// UnmarshalBlindedPath unmarshals a blinded path from its rpc
+// representation.
+func UnmarshalBlindedPath(rpcPath *lnrpc.BlindedPath) (
+	*sphinx.BlindedPath, error) {
+
+	if rpcPath == nil {
+		return nil, errors.New("blinded path required")
+	}
+
+	introduction, err := btcec.ParsePubKey(rpcPath.IntroductionNode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid introduction node: %w", err)
+	}
+
+	blinding, err := btcec.ParsePubKey(rpcPath.BlindingPoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blinding point: %w", err)
+	}
+
+	if len(rpcPath.BlindedHops) < 1 {
+		return nil, errors.New("at least 1 blinded hops required")
+	}
+
+	path := &sphinx.BlindedPath{
+		IntroductionPoint: introduction,
+		BlindingPoint:     blinding,
+		BlindedHops: make(
+			[]*sphinx.BlindedHopInfo, len(rpcPath.BlindedHops),
+		),
+	}
+
+	for i, hop := range rpcPath.BlindedHops {
+		blindedNode, err := btcec.ParsePubKey(hop.BlindedNode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blinded node %v: %w",
+				i, err)
+		}
+
+		if len(hop.EncryptedBlob) == 0 {
+			return nil, fmt.Errorf("blinded hop %v has no "+
+				"encrypted data", i)
+		}
+
+		path.BlindedHops[i] = &sphinx.BlindedHopInfo{
+			BlindedNodePub: blindedNode,
+			CipherText:     hop.EncryptedBlob,
+		}
+	}
+
+	return path, nil
+}
+
+// MarshalBlindedPath converts a blinded path into its rpc representation.
+func MarshalBlindedPath(path *sphinx.BlindedPath) *lnrpc.BlindedPath {
+	hops := make([]*lnrpc.BlindedHop, len(path.BlindedHops))
+	for i, hop := range path.BlindedHops {
+		hops[i] = &lnrpc.BlindedHop{
+			BlindedNode:   hop.BlindedNodePub.SerializeCompressed(),
+			EncryptedBlob: hop.CipherText,
+		}
+	}
+
+	return &lnrpc.BlindedPath{
+		IntroductionNode: path.IntroductionPoint.SerializeCompressed(),
+		BlindingPoint:    path.BlindingPoint.SerializeCompressed(),
+		BlindedHops:      hops,
+	}
+}
+
+// extractIntentFromOffer fetches an invoice for the BOLT 12 offer of the
+// request and creates a payment intent that pays it.
+func (r *RouterBackend) extractIntentFromOffer(ctx context.Context,
+	req *PayOfferRequest) (*routing.LightningPayment, error) {
+
+	if r.FetchOfferInvoice == nil {
+		return nil, errors.New("offers require onion messages, which " +
+			"are disabled")
+	}
+
+	if req.TimePref < -1 || req.TimePref > 1 {
+		return nil, errors.New("time preference out of range")
+	}
+
+	if req.TimeoutSeconds == 0 {
+		return nil, errors.New("timeout_seconds must be specified")
+	}
+
+	if req.AmtMsat < 0 {
+		return nil, errors.New("amount cannot be negative")
+	}
+
+	cltvLimit, err := ValidateCLTVLimit(
+		uint32(req.CltvLimit), r.MaxTotalTimelock,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	feeLimit, err := lnrpc.UnmarshallAmt(0, req.FeeLimitMsat)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := bolt12.DecodeOffer(req.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	invoiceTimeout := DefaultOfferInvoiceTimeout
+	if req.InvoiceTimeoutSeconds != 0 {
+		invoiceTimeout = time.Second *
+			time.Duration(req.InvoiceTimeoutSeconds)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, invoiceTimeout)
+	defer cancel()
+
+	invoice, err := r.FetchOfferInvoice(ctx, &offers.FetchParams{
+		Offer:     offer,
+		Amount:    lnwire.MilliSatoshi(req.AmtMsat),
+		Quantity:  req.Quantity,
+		PayerNote: req.PayerNote,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch invoice: %w", err)
+	}
+
+	encoded, err := invoice.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// The invoice has at least one blinded path, and like for BOLT 11
+	// invoices with blinded paths path finding considers all of them.
+	blindedPayments, err := blindedPaymentsFromInvoice(invoice.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	// The recipient identifies the payment by the path ID of its blinded
+	// paths, which takes the place of the payment address that multi-part
+	// payments otherwise depend on. The payment is split only if the
+	// invoice signals support for multi-part payments.
+	destFeatures := lnwire.NewRawFeatureVector(
+		lnwire.TLVOnionPayloadOptional,
+	)
+	maxParts := uint32(1)
+	if invoice.Features != nil &&
+		(invoice.Features.IsSet(lnwire.MPPOptional) ||
+			invoice.Features.IsSet(lnwire.MPPRequired)) {
+
+		destFeatures.Set(lnwire.PaymentAddrOptional)
+		destFeatures.Set(lnwire.MPPOptional)
+		maxParts = DefaultMaxParts
+	}
+
+	payAttemptTimeout := time.Second * time.Duration(req.TimeoutSeconds)
+	payIntent := &routing.LightningPayment{
+		Target:             blindedPayments[0].Target(),
+		Amount:             invoice.Amount,
+		FeeLimit:           feeLimit,
+		CltvLimit:          cltvLimit,
+		PayAttemptTimeout:  payAttemptTimeout,
+		OutgoingChannelIDs: req.OutgoingChanIds,
+		TimePref:           req.TimePref,
+		DestFeatures: lnwire.NewFeatureVector(
+			destFeatures, lnwire.Features,
+		),
+		BlindedPayments: blindedPayments,
+		PaymentRequest:  []byte(encoded),
+		MaxParts:        maxParts,
+	}
+
+	err = payIntent.SetPaymentHash(invoice.PaymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return payIntent, nil
+}
+
+// blindedPaymentsFromInvoice converts the blinded payment paths that were
+// encoded in an invoice into validated blinded payments.
+func blindedPaymentsFromInvoice(paths []*zpay32.BlindedPaymentPath) (
+	[]*routing.BlindedPayment, error) {
+
+	blindedPayments := make([]*routing.BlindedPayment, 0, len(paths))
+	for _, path := range paths {
+		blindedPayment := blindedPaymentFromInvoice(path)
+		if err := blindedPayment.Validate(); err != nil {
+			return nil, err
+		}
+
+		blindedPayments = append(blindedPayments, blindedPayment)
+	}
+
+	return blindedPayments, nil
+}
+
+// blindedPaymentFromInvoice converts a blinded payment path that was encoded
+// in an invoice into a blinded payment.
+func blindedPaymentFromInvoice(
+	path *zpay32.BlindedPaymentPath) *routing.BlindedPayment {
+
+	return &routing.BlindedPayment{
+		BlindedPath:         path.SphinxPath(),
+		BaseFee:             path.FeeBaseMsat,
+		ProportionalFeeRate: path.FeeRate,
+		CltvExpiryDelta:     path.CltvExpiryDelta,
+		HtlcMinimum:         path.HTLCMinMsat,
+		HtlcMaximum:         path.HTLCMaxMsat,
+		Features:            path.Features,
+	}
+}
+
 // unmarshallRouteHints unmarshalls a list of route hints.
 func unmarshallRouteHints(rpcRouteHints []*lnrpc.RouteHint) (
 	[][]zpay32.HopHint, error) {
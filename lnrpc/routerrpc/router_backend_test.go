@@ -5,13 +5,20 @@ import (
 	"context"
 	"encoding/hex"
 	"testing"
+	"time"
 
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/bolt12"
 	"github.com/ltcsuite/lnd/channeldb"
 	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/offers"
 	"github.com/ltcsuite/lnd/record"
 	"github.com/ltcsuite/lnd/routing"
 	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/lnd/zpay32"
+	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/stretchr/testify/require"
 )
@@ -475,3 +482,139 @@ func testUnmarshalAMP(t *testing.T, test unmarshalAMPTest) {
 		t.Fatalf("test case has non-standard outcome")
 	}
 }
+
+// TestUnmarshalBlindedPayment tests unmarshalling of blinded payment paths
+// from their rpc representation.
+func TestUnmarshalBlindedPayment(t *testing.T) {
+	t.Parallel()
+
+	introNode, err := hex.DecodeString(destKey)
+	require.NoError(t, err)
+
+	blindedNode, err := hex.DecodeString(hintNodeKey)
+	require.NoError(t, err)
+
+	blindingPoint, err := hex.DecodeString(ignoreNodeKey)
+	require.NoError(t, err)
+
+	rpcPayment := &lnrpc.BlindedPaymentPath{
+		BlindedPath: &lnrpc.BlindedPath{
+			IntroductionNode: introNode,
+			BlindingPoint:    blindingPoint,
+			BlindedHops: []*lnrpc.BlindedHop{
+				{
+					BlindedNode:   introNode,
+					EncryptedBlob: []byte{1, 2, 3},
+				},
+				{
+					BlindedNode:   blindedNode,
+					EncryptedBlob: []byte{4, 5, 6},
+				},
+			},
+		},
+		BaseFeeMsat:         1000,
+		ProportionalFeeRate: 10,
+		TotalCltvDelta:      144,
+		HtlcMinMsat:         1,
+		HtlcMaxMsat:         100_000,
+	}
+
+	payment, err := unmarshalBlindedPayment(rpcPayment)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, payment.BaseFee)
+	require.EqualValues(t, 10, payment.ProportionalFeeRate)
+	require.EqualValues(t, 144, payment.CltvExpiryDelta)
+	require.EqualValues(t, 1, payment.HtlcMinimum)
+	require.EqualValues(t, 100_000, payment.HtlcMaximum)
+	require.Len(t, payment.BlindedPath.BlindedHops, 2)
+
+	target, err := route.NewVertexFromBytes(blindedNode)
+	require.NoError(t, err)
+	require.Equal(t, target, payment.Target())
+
+	// A path without any blinded hops is rejected.
+	rpcPayment.BlindedPath.BlindedHops = nil
+	_, err = unmarshalBlindedPayment(rpcPayment)
+	require.Error(t, err)
+
+	// As is a path with an expiry delta that doesn't fit into 16 bits.
+	rpcPayment.BlindedPath.BlindedHops = []*lnrpc.BlindedHop{{
+		BlindedNode:   introNode,
+		EncryptedBlob: []byte{1},
+	}}
+	rpcPayment.TotalCltvDelta = 1 << 16
+	_, err = unmarshalBlindedPayment(rpcPayment)
+	require.Error(t, err)
+}
+
+// TestExtractIntentFromOffer tests that offers are paid through the blinded
+// path of the invoice that is fetched for them.
+func TestExtractIntentFromOffer(t *testing.T) {
+	t.Parallel()
+
+	issuerKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	blindingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	offer := &bolt12.Offer{
+		Description: "tip",
+		IssuerID:    issuerKey.PubKey(),
+	}
+	encodedOffer, err := offer.Encode()
+	require.NoError(t, err)
+
+	req := &PayOfferRequest{
+		Offer:          encodedOffer,
+		AmtMsat:        5000,
+		TimeoutSeconds: 60,
+		FeeLimitMsat:   100,
+	}
+
+	// Without onion messages, offers can't be paid.
+	backend := &RouterBackend{
+		MaxTotalTimelock: 1000,
+	}
+	_, err = backend.extractIntentFromOffer(context.Background(), req)
+	require.Error(t, err)
+
+	backend.FetchOfferInvoice = func(_ context.Context,
+		params *offers.FetchParams) (*bolt12.Invoice, error) {
+
+		invoice := &bolt12.Invoice{
+			Request: &bolt12.InvoiceRequest{
+				Offer:   params.Offer,
+				PayerID: blindingKey.PubKey(),
+			},
+			Paths: []*zpay32.BlindedPaymentPath{{
+				CltvExpiryDelta:             80,
+				HTLCMaxMsat:                 10000,
+				IntroductionNode:            issuerKey.PubKey(),
+				FirstEphemeralBlindingPoint: blindingKey.PubKey(),
+				Hops: []*sphinx.BlindedHopInfo{{
+					BlindedNodePub: blindingKey.PubKey(),
+					CipherText:     []byte{1},
+				}},
+			}},
+			CreatedAt:   time.Now(),
+			PaymentHash: lntypes.Hash{1},
+			Amount:      params.Amount,
+			NodeID:      issuerKey.PubKey(),
+		}
+		err := invoice.Sign(bolt12.PrivKeySigner(issuerKey))
+
+		return invoice, err
+	}
+
+	payment, err := backend.extractIntentFromOffer(
+		context.Background(), req,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 5000, payment.Amount)
+	require.EqualValues(t, 100, payment.FeeLimit)
+	require.EqualValues(t, lntypes.Hash{1}, payment.Identifier())
+	require.Len(t, payment.BlindedPayments, 1)
+	require.Equal(t, payment.BlindedPayments[0].Target(), payment.Target)
+	require.EqualValues(t, 1, payment.MaxParts)
+	require.Nil(t, payment.PaymentAddr)
+}
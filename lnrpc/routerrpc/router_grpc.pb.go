@@ -23,6 +23,9 @@ type RouterClient interface {
 	// PaymentRequest to the final destination. The call returns a stream of
 	// payment updates.
 	SendPaymentV2(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (Router_SendPaymentV2Client, error)
+	// PayOffer requests an invoice for a BOLT 12 offer from its issuer over onion
+	// messages and pays it. The call returns a stream of payment updates.
+	PayOffer(ctx context.Context, in *PayOfferRequest, opts ...grpc.CallOption) (Router_PayOfferClient, error)
 	// TrackPaymentV2 returns an update stream for the payment identified by the
 	// payment hash.
 	TrackPaymentV2(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Router_TrackPaymentV2Client, error)
@@ -142,8 +145,40 @@ func (x *routerSendPaymentV2Client) Recv() (*lnrpc.Payment, error) {
 	return m, nil
 }
 
+func (c *routerClient) PayOffer(ctx context.Context, in *PayOfferRequest, opts ...grpc.CallOption) (Router_PayOfferClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[1], "/routerrpc.Router/PayOffer", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerPayOfferClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_PayOfferClient interface {
+	Recv() (*lnrpc.Payment, error)
+	grpc.ClientStream
+}
+
+type routerPayOfferClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerPayOfferClient) Recv() (*lnrpc.Payment, error) {
+	m := new(lnrpc.Payment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *routerClient) TrackPaymentV2(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Router_TrackPaymentV2Client, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[1], "/routerrpc.Router/TrackPaymentV2", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[2], "/routerrpc.Router/TrackPaymentV2", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -175,7 +210,7 @@ func (x *routerTrackPaymentV2Client) Recv() (*lnrpc.Payment, error) {
 }
 
 func (c *routerClient) TrackPayments(ctx context.Context, in *TrackPaymentsRequest, opts ...grpc.CallOption) (Router_TrackPaymentsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[2], "/routerrpc.Router/TrackPayments", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[3], "/routerrpc.Router/TrackPayments", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +333,7 @@ func (c *routerClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, op
 }
 
 func (c *routerClient) SubscribeHtlcEvents(ctx context.Context, in *SubscribeHtlcEventsRequest, opts ...grpc.CallOption) (Router_SubscribeHtlcEventsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[3], "/routerrpc.Router/SubscribeHtlcEvents", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[4], "/routerrpc.Router/SubscribeHtlcEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -331,7 +366,7 @@ func (x *routerSubscribeHtlcEventsClient) Recv() (*HtlcEvent, error) {
 
 // Deprecated: Do not use.
 func (c *routerClient) SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (Router_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[4], "/routerrpc.Router/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[5], "/routerrpc.Router/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +399,7 @@ func (x *routerSendPaymentClient) Recv() (*PaymentStatus, error) {
 
 // Deprecated: Do not use.
 func (c *routerClient) TrackPayment(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Router_TrackPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[5], "/routerrpc.Router/TrackPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[6], "/routerrpc.Router/TrackPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -396,7 +431,7 @@ func (x *routerTrackPaymentClient) Recv() (*PaymentStatus, error) {
 }
 
 func (c *routerClient) HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[6], "/routerrpc.Router/HtlcInterceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[7], "/routerrpc.Router/HtlcInterceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -443,6 +478,9 @@ type RouterServer interface {
 	// PaymentRequest to the final destination. The call returns a stream of
 	// payment updates.
 	SendPaymentV2(*SendPaymentRequest, Router_SendPaymentV2Server) error
+	// PayOffer requests an invoice for a BOLT 12 offer from its issuer over onion
+	// messages and pays it. The call returns a stream of payment updates.
+	PayOffer(*PayOfferRequest, Router_PayOfferServer) error
 	// TrackPaymentV2 returns an update stream for the payment identified by the
 	// payment hash.
 	TrackPaymentV2(*TrackPaymentRequest, Router_TrackPaymentV2Server) error
@@ -530,6 +568,9 @@ type UnimplementedRouterServer struct {
 func (UnimplementedRouterServer) SendPaymentV2(*SendPaymentRequest, Router_SendPaymentV2Server) error {
 	return status.Errorf(codes.Unimplemented, "method SendPaymentV2 not implemented")
 }
+func (UnimplementedRouterServer) PayOffer(*PayOfferRequest, Router_PayOfferServer) error {
+	return status.Errorf(codes.Unimplemented, "method PayOffer not implemented")
+}
 func (UnimplementedRouterServer) TrackPaymentV2(*TrackPaymentRequest, Router_TrackPaymentV2Server) error {
 	return status.Errorf(codes.Unimplemented, "method TrackPaymentV2 not implemented")
 }
@@ -615,6 +656,27 @@ func (x *routerSendPaymentV2Server) Send(m *lnrpc.Payment) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Router_PayOffer_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PayOfferRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).PayOffer(m, &routerPayOfferServer{stream})
+}
+
+type Router_PayOfferServer interface {
+	Send(*lnrpc.Payment) error
+	grpc.ServerStream
+}
+
+type routerPayOfferServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerPayOfferServer) Send(m *lnrpc.Payment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Router_TrackPaymentV2_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(TrackPaymentRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1002,6 +1064,11 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Router_SendPaymentV2_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "PayOffer",
+			Handler:       _Router_PayOffer_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "TrackPaymentV2",
 			Handler:       _Router_TrackPaymentV2_Handler,
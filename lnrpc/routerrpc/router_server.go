@@ -20,6 +20,7 @@ import (
 	"github.com/ltcsuite/lnd/routing/route"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/wire"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -60,6 +61,10 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/PayOffer": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/SendToRouteV2": {{
 			Entity: "offchain",
 			Action: "write",
@@ -318,9 +323,36 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 		return err
 	}
 
+	return s.sendPayment(payment, stream, req.NoInflightUpdates)
+}
+
+// PayOffer requests an invoice for a BOLT 12 offer from its issuer over onion
+// messages and pays it. The call returns a stream of payment updates.
+func (s *Server) PayOffer(req *PayOfferRequest,
+	stream Router_PayOfferServer) error {
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromOffer(
+		stream.Context(), req,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.sendPayment(payment, stream, req.NoInflightUpdates)
+}
+
+// sendPayment dispatches the given payment and streams its updates to the
+// caller until the payment reaches a final state.
+func (s *Server) sendPayment(payment *routing.LightningPayment,
+	stream Router_TrackPaymentV2Server, noInflightUpdates bool) error {
+
 	// Get the payment hash.
 	payHash := payment.Identifier()
 
+	// Record the spans of the payment as part of the trace of this call,
+	// if tracing is enabled.
+	payment.SpanContext = trace.SpanContextFromContext(stream.Context())
+
 	// Init the payment in db.
 	paySession, shardTracker, err := s.cfg.Router.PreparePayment(payment)
 	if err != nil {
@@ -340,7 +372,7 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 		// If the payment was sent successfully, we can start tracking
 		// the events.
 		return s.trackPayment(
-			sub, payHash, stream, req.NoInflightUpdates,
+			sub, payHash, stream, noInflightUpdates,
 		)
 	}
 
@@ -429,11 +461,17 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 	// the attempt return value and err are non-nil. This can happen when
 	// the attempt was already initiated before the error happened. In that
 	// case, we give precedence to the attempt information as stored in the
-	// db.
+	// db. The spans of the payment are recorded as part of the trace of
+	// this call, if tracing is enabled.
+	spanContext := trace.SpanContextFromContext(ctx)
 	if req.SkipTempErr {
-		attempt, err = s.cfg.Router.SendToRouteSkipTempErr(hash, route)
+		attempt, err = s.cfg.Router.SendToRouteSkipTempErr(
+			spanContext, hash, route,
+		)
 	} else {
-		attempt, err = s.cfg.Router.SendToRoute(hash, route)
+		attempt, err = s.cfg.Router.SendToRoute(
+			spanContext, hash, route,
+		)
 	}
 	if attempt != nil {
 		rpcAttempt, err := s.cfg.RouterBackend.MarshalHTLCAttempt(
@@ -10,6 +10,7 @@ import (
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
 	"github.com/ltcsuite/lnd/sweep"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/txscript"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -63,6 +64,20 @@ func RPCTransaction(tx *lnwallet.TransactionDetail) *Transaction {
 	// Re-package destination output information.
 	var outputDetails []*OutputDetail
 	for _, o := range tx.OutputDetails {
+		// MWEB outputs pay to stealth addresses, which we don't
+		// reveal in the transaction history. The output type and
+		// amount are enough to account for them.
+		if o.OutputType == txscript.MwebTy {
+			outputDetails = append(outputDetails, &OutputDetail{
+				OutputType:   OutputScriptType_SCRIPT_TYPE_MWEB,
+				OutputIndex:  int64(o.OutputIndex),
+				Amount:       int64(o.Value),
+				IsOurAddress: o.IsOurAddress,
+			})
+
+			continue
+		}
+
 		// Note: DestAddresses is deprecated but we keep
 		// populating it with addresses for backwards
 		// compatibility.
@@ -100,6 +115,11 @@ func RPCTransaction(tx *lnwallet.TransactionDetail) *Transaction {
 		blockHash = tx.BlockHash.String()
 	}
 
+	kernelIDs := make([]string, len(tx.Mweb.KernelIDs))
+	for idx, kernelID := range tx.Mweb.KernelIDs {
+		kernelIDs[idx] = kernelID.String()
+	}
+
 	return &Transaction{
 		TxHash:            tx.Hash.String(),
 		Amount:            int64(tx.Value),
@@ -113,6 +133,26 @@ func RPCTransaction(tx *lnwallet.TransactionDetail) *Transaction {
 		RawTxHex:          hex.EncodeToString(tx.RawTx),
 		Label:             tx.Label,
 		PreviousOutpoints: previousOutpoints,
+		MwebType:          MarshalMwebTxType(tx.Mweb.Type),
+		MwebKernelIds:     kernelIDs,
+		MwebFee:           int64(tx.Mweb.Fee),
+	}
+}
+
+// MarshalMwebTxType translates a lnwallet.MwebTxType into a lnrpc.MwebTxType.
+func MarshalMwebTxType(t lnwallet.MwebTxType) MwebTxType {
+	switch t {
+	case lnwallet.MwebTxPegIn:
+		return MwebTxType_MWEB_TX_PEG_IN
+
+	case lnwallet.MwebTxPegOut:
+		return MwebTxType_MWEB_TX_PEG_OUT
+
+	case lnwallet.MwebTxInternal:
+		return MwebTxType_MWEB_TX_INTERNAL
+
+	default:
+		return MwebTxType_MWEB_TX_NONE
 	}
 }
 
@@ -4,6 +4,7 @@
 package walletrpc
 
 import (
+	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/keychain"
 	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
@@ -57,6 +58,10 @@ type Config struct {
 	// keys due to incoming client requests.
 	KeyRing keychain.KeyRing
 
+	// Signer is the signer the WalletKit will use to sign the canonical
+	// inputs of wallet sweeps.
+	Signer input.Signer
+
 	// Sweeper is the central batching engine of lnd. It is responsible for
 	// sweeping inputs in batches back into the wallet.
 	Sweeper *sweep.UtxoSweeper
@@ -0,0 +1,293 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/ltcsuite/lnd/labels"
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/sweep"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// defaultAccountUtxoSource is a sweep.UtxoSource over the UTXOs of the default
+// account of a wallet.
+type defaultAccountUtxoSource struct {
+	lnwallet.WalletController
+}
+
+// ListUnspentWitnessFromDefaultAccount returns all UTXOs from the default
+// wallet account that have between minConfs and maxConfs number of
+// confirmations.
+func (s defaultAccountUtxoSource) ListUnspentWitnessFromDefaultAccount(
+	minConfs, maxConfs int32) ([]*lnwallet.Utxo, error) {
+
+	return s.ListUnspentWitness(
+		minConfs, maxConfs, lnwallet.DefaultAccountName,
+	)
+}
+
+// pegRequest holds the parameters shared by peg-in and peg-out requests.
+type pegRequest struct {
+	outpoints        []*lnrpc.OutPoint
+	targetConf       uint32
+	satPerVbyte      uint64
+	account          string
+	minConfs         int32
+	spendUnconfirmed bool
+	dryRun           bool
+	label            string
+
+	// destType is the type of the destination address, which determines
+	// the direction of the peg. Peg-ins pay to an MWEB address and spend
+	// canonical coins, peg-outs do the opposite.
+	destType lnwallet.AddressType
+}
+
+// PegIn moves canonical coins of the default wallet account into the MWEB
+// extension block, paying to a fresh MWEB address of the destination account.
+func (w *WalletKit) PegIn(_ context.Context,
+	req *PegInRequest) (*PegResponse, error) {
+
+	return w.peg(&pegRequest{
+		outpoints:        req.Outpoints,
+		targetConf:       req.GetTargetConf(),
+		satPerVbyte:      req.GetSatPerVbyte(),
+		account:          req.Account,
+		minConfs:         req.MinConfs,
+		spendUnconfirmed: req.SpendUnconfirmed,
+		dryRun:           req.DryRun,
+		label:            req.Label,
+		destType:         lnwallet.Mweb,
+	})
+}
+
+// PegOut moves MWEB coins of the default wallet account out of the extension
+// block, paying to a fresh canonical address of the destination account.
+func (w *WalletKit) PegOut(_ context.Context,
+	req *PegOutRequest) (*PegResponse, error) {
+
+	var destType lnwallet.AddressType
+	switch req.AddressType {
+	case AddressType_UNKNOWN, AddressType_WITNESS_PUBKEY_HASH:
+		destType = lnwallet.WitnessPubKey
+
+	case AddressType_TAPROOT_PUBKEY:
+		destType = lnwallet.TaprootPubkey
+
+	default:
+		return nil, fmt.Errorf("unsupported peg-out address type %v",
+			req.AddressType)
+	}
+
+	return w.peg(&pegRequest{
+		outpoints:        req.Outpoints,
+		targetConf:       req.GetTargetConf(),
+		satPerVbyte:      req.GetSatPerVbyte(),
+		account:          req.Account,
+		minConfs:         req.MinConfs,
+		spendUnconfirmed: req.SpendUnconfirmed,
+		dryRun:           req.DryRun,
+		label:            req.Label,
+		destType:         destType,
+	})
+}
+
+// peg crafts, and unless it's a dry run publishes, a transaction moving the
+// selected coins between the canonical chain and the MWEB extension block.
+// The MWEB fees are only known once the transaction has been signed, so the
+// returned transaction is signed and valid even for a dry run.
+func (w *WalletKit) peg(req *pegRequest) (*PegResponse, error) {
+	pegOut := req.destType != lnwallet.Mweb
+	direction := "peg-in"
+	if pegOut {
+		direction = "peg-out"
+	}
+
+	var (
+		feeRate chainfee.SatPerKWeight
+		err     error
+	)
+	switch {
+	// Estimate the fee by the target number of blocks to confirmation.
+	case req.targetConf != 0:
+		if req.targetConf < 2 {
+			return nil, fmt.Errorf("confirmation target must be " +
+				"greater than 1")
+		}
+
+		feeRate, err = w.cfg.FeeEstimator.EstimateFeePerKW(
+			req.targetConf,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not estimate fee: %w",
+				err)
+		}
+
+	// Convert the fee to sat/kW from the specified sat/vByte.
+	case req.satPerVbyte != 0:
+		feeRate = chainfee.SatPerKVByte(
+			req.satPerVbyte * 1000,
+		).FeePerKWeight()
+
+	default:
+		return nil, fmt.Errorf("fee definition missing, need to " +
+			"specify either target_conf or sat_per_vbyte")
+	}
+
+	minConfs, err := lnrpc.ExtractMinConfs(
+		req.minConfs, req.spendUnconfirmed,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := labels.ValidateAPI(req.label)
+	if err != nil {
+		return nil, err
+	}
+
+	account := lnwallet.DefaultAccountName
+	if req.account != "" {
+		account = req.account
+	}
+
+	requested := fn.NewSet[wire.OutPoint]()
+	for _, rpcOutpoint := range req.outpoints {
+		op, err := UnmarshallOutPoint(rpcOutpoint)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing outpoint: %w",
+				err)
+		}
+		requested.Add(*op)
+	}
+
+	// Select the coins to peg, which are either the requested ones or,
+	// if none were requested, all coins on the side of the extension
+	// block the peg moves away from.
+	selected := fn.NewSet[wire.OutPoint]()
+	var amount ltcutil.Amount
+	err = w.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
+		utxos, err := w.cfg.Wallet.ListUnspentWitness(
+			minConfs, math.MaxInt32, lnwallet.DefaultAccountName,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, utxo := range utxos {
+			isMweb := utxo.AddressType == lnwallet.Mweb
+			wanted := len(requested) == 0 ||
+				requested.Contains(utxo.OutPoint)
+
+			switch {
+			case !wanted:
+				continue
+
+			case isMweb != pegOut && len(requested) > 0:
+				return fmt.Errorf("outpoint %v can't be used "+
+					"for a %s", utxo.OutPoint, direction)
+
+			case isMweb != pegOut:
+				continue
+			}
+
+			selected.Add(utxo.OutPoint)
+			amount += utxo.Value
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if unknown := requested.Diff(selected); len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %v", sweep.ErrUnknownUTXO,
+			unknown.ToSlice())
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no coins available for %s", direction)
+	}
+
+	// A dry run shouldn't use up an address, so it pays to the last
+	// unused one instead.
+	var destAddr ltcutil.Address
+	if req.dryRun {
+		destAddr, err = w.cfg.Wallet.LastUnusedAddress(
+			req.destType, account,
+		)
+	} else {
+		destAddr, err = w.cfg.Wallet.NewAddress(
+			req.destType, false, account,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, bestHeight, err := w.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The selected coins are swept in their entirety to the destination
+	// address, which is passed as the change address so it receives
+	// everything that is left after fees. Either the inputs or the
+	// change are MWEB, so the transaction is signed by the wallet's MWEB
+	// signing path.
+	sweepTxPkg, err := sweep.CraftSweepAllTx(
+		feeRate, uint32(bestHeight), nil, destAddr,
+		w.cfg.CoinSelectionLocker,
+		defaultAccountUtxoSource{w.cfg.Wallet}, w.cfg.Wallet,
+		w.cfg.FeeEstimator, w.cfg.Signer, minConfs, selected,
+		w.cfg.Wallet,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to craft %s tx: %w", direction,
+			err)
+	}
+
+	var buf bytes.Buffer
+	if err := sweepTxPkg.SweepTx.Serialize(&buf); err != nil {
+		sweepTxPkg.CancelSweepAttempt()
+		return nil, err
+	}
+
+	resp := &PegResponse{
+		Txid:            sweepTxPkg.SweepTx.TxHash().String(),
+		RawTx:           buf.Bytes(),
+		Address:         destAddr.String(),
+		AmountSat:       int64(amount - sweepTxPkg.Fee),
+		CanonicalFeeSat: int64(sweepTxPkg.Fee - sweepTxPkg.MwebFee),
+		MwebFeeSat:      int64(sweepTxPkg.MwebFee),
+	}
+
+	// The coins of a dry run are released again, but the transaction
+	// stays valid until one of them is spent.
+	if req.dryRun {
+		sweepTxPkg.CancelSweepAttempt()
+		return resp, nil
+	}
+
+	err = w.cfg.Wallet.PublishTransaction(sweepTxPkg.SweepTx, label)
+	if err != nil {
+		sweepTxPkg.CancelSweepAttempt()
+		return nil, fmt.Errorf("unable to publish %s tx: %w",
+			direction, err)
+	}
+
+	log.Infof("Published %s tx %v moving %v", direction, resp.Txid,
+		amount-sweepTxPkg.Fee)
+
+	return resp, nil
+}
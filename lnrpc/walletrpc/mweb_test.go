@@ -0,0 +1,445 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lntest/mock"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/sweep"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb/mw"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcwallet/wallet/txauthor"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// pegCanonicalCoin is a canonical coin of the test wallet.
+	pegCanonicalCoin = &lnwallet.Utxo{
+		AddressType:   lnwallet.WitnessPubKey,
+		Value:         ltcutil.SatoshiPerBitcoin,
+		Confirmations: 6,
+		PkScript:      mock.CoinPkScript,
+		OutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{1},
+			Index: 1,
+		},
+	}
+
+	// pegMwebCoin is an MWEB coin of the test wallet.
+	pegMwebCoin = &lnwallet.Utxo{
+		AddressType:   lnwallet.Mweb,
+		Value:         2 * ltcutil.SatoshiPerBitcoin,
+		Confirmations: 6,
+		OutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{2},
+			Index: 2,
+		},
+	}
+)
+
+// pegWallet is a mock wallet controller that holds one canonical and one MWEB
+// coin, and keeps track of the coins locked for a peg.
+type pegWallet struct {
+	*mock.WalletController
+
+	mweb      ltcutil.Address
+	canonical ltcutil.Address
+
+	publishErr error
+
+	mu         sync.Mutex
+	locked     fn.Set[wire.OutPoint]
+	newAddrs   []lnwallet.AddressType
+	lastUnused []lnwallet.AddressType
+	signed     int
+}
+
+// newPegWallet creates a new peg wallet.
+func newPegWallet(t *testing.T) *pegWallet {
+	rootKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	canonical, err := ltcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), &chaincfg.MainNetParams,
+	)
+	require.NoError(t, err)
+
+	// The keys of an MWEB address must be valid public keys for it to
+	// have a script.
+	var scan, spend mw.PublicKey
+	copy(scan[:], rootKey.PubKey().SerializeCompressed())
+	copy(spend[:], rootKey.PubKey().SerializeCompressed())
+	mweb := ltcutil.NewAddressMweb(&mw.StealthAddress{
+		Scan:  &scan,
+		Spend: &spend,
+	}, &chaincfg.MainNetParams)
+
+	return &pegWallet{
+		WalletController: &mock.WalletController{
+			RootKey:               rootKey,
+			PublishedTransactions: make(chan *wire.MsgTx, 1),
+			Utxos: []*lnwallet.Utxo{
+				pegCanonicalCoin, pegMwebCoin,
+			},
+		},
+		mweb:      mweb,
+		canonical: canonical,
+		locked:    fn.NewSet[wire.OutPoint](),
+	}
+}
+
+// address returns the wallet's address of the given type.
+func (w *pegWallet) address(addrType lnwallet.AddressType) ltcutil.Address {
+	if addrType == lnwallet.Mweb {
+		return w.mweb
+	}
+
+	return w.canonical
+}
+
+// NewAddress returns the wallet's address of the given type.
+func (w *pegWallet) NewAddress(addrType lnwallet.AddressType, _ bool,
+	_ string) (ltcutil.Address, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.newAddrs = append(w.newAddrs, addrType)
+
+	return w.address(addrType), nil
+}
+
+// LastUnusedAddress returns the wallet's address of the given type.
+func (w *pegWallet) LastUnusedAddress(addrType lnwallet.AddressType,
+	_ string) (ltcutil.Address, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastUnused = append(w.lastUnused, addrType)
+
+	return w.address(addrType), nil
+}
+
+// LockOutpoint marks the coin as locked.
+func (w *pegWallet) LockOutpoint(o wire.OutPoint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.locked.Add(o)
+}
+
+// UnlockOutpoint releases the coin.
+func (w *pegWallet) UnlockOutpoint(o wire.OutPoint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.locked.Remove(o)
+}
+
+// SignMwebSweepTx counts the signed transactions.
+func (w *pegWallet) SignMwebSweepTx(*txauthor.AuthoredTx,
+	chainfee.SatPerKWeight) error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.signed++
+
+	return nil
+}
+
+// PublishTransaction publishes the transaction, unless publishErr is set.
+func (w *pegWallet) PublishTransaction(tx *wire.MsgTx, label string) error {
+	if w.publishErr != nil {
+		return w.publishErr
+	}
+
+	return w.WalletController.PublishTransaction(tx, label)
+}
+
+// coinSelectionLocker is a sweep.CoinSelectionLocker that runs the closure
+// right away.
+type coinSelectionLocker struct{}
+
+func (coinSelectionLocker) WithCoinSelectLock(f func() error) error {
+	return f()
+}
+
+// newPegWalletKit creates a wallet kit on top of the given peg wallet.
+func newPegWalletKit(wallet *pegWallet) *WalletKit {
+	return &WalletKit{
+		cfg: &Config{
+			FeeEstimator: chainfee.NewStaticEstimator(
+				chainfee.FeePerKwFloor, 0,
+			),
+			Wallet:              wallet,
+			CoinSelectionLocker: coinSelectionLocker{},
+			Signer:              &mock.DummySigner{},
+			Chain:               &mock.ChainIO{BestHeight: 100},
+		},
+	}
+}
+
+// rpcOutpoint converts an outpoint to its RPC representation.
+func rpcOutpoint(o wire.OutPoint) *lnrpc.OutPoint {
+	return &lnrpc.OutPoint{
+		TxidBytes:   o.Hash[:],
+		OutputIndex: o.Index,
+	}
+}
+
+// assertPegTx asserts that the peg response describes a transaction sweeping
+// the given coin to the destination address in its entirety.
+func assertPegTx(t *testing.T, resp *PegResponse, coin *lnwallet.Utxo,
+	dest ltcutil.Address) *wire.MsgTx {
+
+	t.Helper()
+
+	tx := &wire.MsgTx{}
+	require.NoError(t, tx.Deserialize(bytes.NewReader(resp.RawTx)))
+	require.Equal(t, tx.TxHash().String(), resp.Txid)
+
+	require.Len(t, tx.TxIn, 1)
+	require.Equal(t, coin.OutPoint, tx.TxIn[0].PreviousOutPoint)
+
+	destScript, err := txscript.PayToAddrScript(dest)
+	require.NoError(t, err)
+	require.Len(t, tx.TxOut, 1)
+	require.Equal(t, destScript, tx.TxOut[0].PkScript)
+	require.Equal(t, resp.AmountSat, tx.TxOut[0].Value)
+
+	require.Equal(t, dest.String(), resp.Address)
+	require.Positive(t, resp.CanonicalFeeSat+resp.MwebFeeSat)
+	require.Equal(
+		t, int64(coin.Value)-resp.AmountSat,
+		resp.CanonicalFeeSat+resp.MwebFeeSat,
+	)
+
+	return tx
+}
+
+// TestPegIn tests that a peg-in sweeps the canonical coins of the wallet to a
+// fresh MWEB address and publishes the transaction.
+func TestPegIn(t *testing.T) {
+	t.Parallel()
+
+	wallet := newPegWallet(t)
+	w := newPegWalletKit(wallet)
+
+	resp, err := w.PegIn(context.Background(), &PegInRequest{
+		Fees: &PegInRequest_SatPerVbyte{SatPerVbyte: 10},
+	})
+	require.NoError(t, err)
+
+	tx := assertPegTx(t, resp, pegCanonicalCoin, wallet.mweb)
+	require.Equal(t, []lnwallet.AddressType{lnwallet.Mweb}, wallet.newAddrs)
+	require.Equal(t, 1, wallet.signed)
+
+	select {
+	case published := <-wallet.PublishedTransactions:
+		require.Equal(t, tx.TxHash(), published.TxHash())
+
+	default:
+		t.Fatalf("peg-in tx not published")
+	}
+
+	// The pegged in coin stays locked until the transaction confirms.
+	require.True(t, wallet.locked.Contains(pegCanonicalCoin.OutPoint))
+}
+
+// TestPegOut tests that a peg-out sweeps the MWEB coins of the wallet to a
+// fresh canonical address of the requested type.
+func TestPegOut(t *testing.T) {
+	t.Parallel()
+
+	wallet := newPegWallet(t)
+	w := newPegWalletKit(wallet)
+
+	resp, err := w.PegOut(context.Background(), &PegOutRequest{
+		Outpoints: []*lnrpc.OutPoint{
+			rpcOutpoint(pegMwebCoin.OutPoint),
+		},
+		Fees:        &PegOutRequest_TargetConf{TargetConf: 6},
+		AddressType: AddressType_TAPROOT_PUBKEY,
+	})
+	require.NoError(t, err)
+
+	tx := assertPegTx(t, resp, pegMwebCoin, wallet.canonical)
+	require.Equal(
+		t, []lnwallet.AddressType{lnwallet.TaprootPubkey},
+		wallet.newAddrs,
+	)
+
+	select {
+	case published := <-wallet.PublishedTransactions:
+		require.Equal(t, tx.TxHash(), published.TxHash())
+
+	default:
+		t.Fatalf("peg-out tx not published")
+	}
+}
+
+// TestPegDryRun tests that a dry run reports the signed peg transaction
+// without publishing it, deriving a new address or keeping coins locked.
+func TestPegDryRun(t *testing.T) {
+	t.Parallel()
+
+	wallet := newPegWallet(t)
+	w := newPegWalletKit(wallet)
+
+	resp, err := w.PegOut(context.Background(), &PegOutRequest{
+		Fees:   &PegOutRequest_SatPerVbyte{SatPerVbyte: 10},
+		DryRun: true,
+	})
+	require.NoError(t, err)
+
+	assertPegTx(t, resp, pegMwebCoin, wallet.canonical)
+
+	// The last unused address is reused instead of deriving a new one.
+	require.Empty(t, wallet.newAddrs)
+	require.Equal(
+		t, []lnwallet.AddressType{lnwallet.WitnessPubKey},
+		wallet.lastUnused,
+	)
+
+	// The returned transaction is signed all the same, but neither
+	// published nor are its coins kept locked.
+	require.Equal(t, 1, wallet.signed)
+	require.Empty(t, wallet.PublishedTransactions)
+	require.Empty(t, wallet.locked)
+}
+
+// TestPegErrors tests that invalid peg requests are rejected, and that the
+// coins are released if the peg fails.
+func TestPegErrors(t *testing.T) {
+	t.Parallel()
+
+	errPublish := errors.New("publish failed")
+	unknownCoin := wire.OutPoint{Hash: chainhash.Hash{3}}
+
+	testCases := []struct {
+		name       string
+		pegIn      *PegInRequest
+		pegOut     *PegOutRequest
+		publishErr error
+		expectErr  error
+		errString  string
+	}{{
+		name:      "fee missing",
+		pegIn:     &PegInRequest{},
+		errString: "fee definition missing",
+	}, {
+		name: "conf target too low",
+		pegIn: &PegInRequest{
+			Fees: &PegInRequest_TargetConf{TargetConf: 1},
+		},
+		errString: "confirmation target must be greater than 1",
+	}, {
+		name: "mweb coin pegged in",
+		pegIn: &PegInRequest{
+			Outpoints: []*lnrpc.OutPoint{
+				rpcOutpoint(pegMwebCoin.OutPoint),
+			},
+			Fees: &PegInRequest_SatPerVbyte{SatPerVbyte: 10},
+		},
+		errString: "can't be used for a peg-in",
+	}, {
+		name: "canonical coin pegged out",
+		pegOut: &PegOutRequest{
+			Outpoints: []*lnrpc.OutPoint{
+				rpcOutpoint(pegCanonicalCoin.OutPoint),
+			},
+			Fees: &PegOutRequest_SatPerVbyte{SatPerVbyte: 10},
+		},
+		errString: "can't be used for a peg-out",
+	}, {
+		name: "unknown coin",
+		pegOut: &PegOutRequest{
+			Outpoints: []*lnrpc.OutPoint{
+				rpcOutpoint(unknownCoin),
+			},
+			Fees: &PegOutRequest_SatPerVbyte{SatPerVbyte: 10},
+		},
+		expectErr: sweep.ErrUnknownUTXO,
+	}, {
+		name: "unsupported address type",
+		pegOut: &PegOutRequest{
+			Fees: &PegOutRequest_SatPerVbyte{
+				SatPerVbyte: 10,
+			},
+			AddressType: AddressType_NESTED_WITNESS_PUBKEY_HASH,
+		},
+		errString: "unsupported peg-out address type",
+	}, {
+		name: "publish failure",
+		pegIn: &PegInRequest{
+			Fees: &PegInRequest_SatPerVbyte{SatPerVbyte: 10},
+		},
+		publishErr: errPublish,
+		expectErr:  errPublish,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			wallet := newPegWallet(t)
+			wallet.publishErr = tc.publishErr
+			w := newPegWalletKit(wallet)
+
+			var err error
+			if tc.pegIn != nil {
+				_, err = w.PegIn(context.Background(), tc.pegIn)
+			} else {
+				_, err = w.PegOut(
+					context.Background(), tc.pegOut,
+				)
+			}
+
+			require.Error(t, err)
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+			}
+			if tc.errString != "" {
+				require.ErrorContains(t, err, tc.errString)
+			}
+
+			// Nothing is published and no coins stay locked.
+			require.Empty(t, wallet.PublishedTransactions)
+			require.Empty(t, wallet.locked)
+		})
+	}
+}
+
+// TestPegNoCoins tests that a peg fails if the wallet has no coins on the side
+// of the extension block the peg moves away from.
+func TestPegNoCoins(t *testing.T) {
+	t.Parallel()
+
+	wallet := newPegWallet(t)
+	wallet.Utxos = []*lnwallet.Utxo{pegCanonicalCoin}
+	w := newPegWalletKit(wallet)
+
+	_, err := w.PegOut(context.Background(), &PegOutRequest{
+		Fees: &PegOutRequest_SatPerVbyte{SatPerVbyte: 10},
+	})
+	require.ErrorContains(t, err, "no coins available for peg-out")
+}
@@ -124,6 +124,21 @@ type WalletKitClient interface {
 	// amounts and addresses. It is encrypted at rest with the wallet's
 	// public passphrase.
 	ImportMwebScanKey(ctx context.Context, in *ImportMwebScanKeyRequest, opts ...grpc.CallOption) (*ImportMwebScanKeyResponse, error)
+	// lncli: `wallet pegin`
+	// PegIn moves canonical coins of the default wallet account into the MWEB
+	// extension block, paying to a fresh MWEB address of the destination
+	// account. The full value of the selected coins is pegged in, minus the
+	// fees. With dry_run set, the transaction is crafted to report its fees but
+	// not published. The returned transaction is signed in either case.
+	PegIn(ctx context.Context, in *PegInRequest, opts ...grpc.CallOption) (*PegResponse, error)
+	// lncli: `wallet pegout`
+	// PegOut moves MWEB coins of the default wallet account out of the
+	// extension block, paying to a fresh canonical address of the destination
+	// account. The full value of the selected coins is pegged out, minus the
+	// fees. Pegged out coins can only be spent once they have matured. With
+	// dry_run set, the transaction is crafted to report its fees but not
+	// published. The returned transaction is signed in either case.
+	PegOut(ctx context.Context, in *PegOutRequest, opts ...grpc.CallOption) (*PegResponse, error)
 	// ImportPublicKey imports a public key as watch-only into the wallet. The
 	// public key is converted into a simple address of the given type and that
 	// address script is watched on chain. For Taproot keys, this will only watch
@@ -382,6 +397,24 @@ func (c *walletKitClient) ImportMwebScanKey(ctx context.Context, in *ImportMwebS
 	return out, nil
 }
 
+func (c *walletKitClient) PegIn(ctx context.Context, in *PegInRequest, opts ...grpc.CallOption) (*PegResponse, error) {
+	out := new(PegResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/PegIn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) PegOut(ctx context.Context, in *PegOutRequest, opts ...grpc.CallOption) (*PegResponse, error) {
+	out := new(PegResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/PegOut", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) ImportPublicKey(ctx context.Context, in *ImportPublicKeyRequest, opts ...grpc.CallOption) (*ImportPublicKeyResponse, error) {
 	out := new(ImportPublicKeyResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ImportPublicKey", in, out, opts...)
@@ -599,6 +632,21 @@ type WalletKitServer interface {
 	// amounts and addresses. It is encrypted at rest with the wallet's
 	// public passphrase.
 	ImportMwebScanKey(context.Context, *ImportMwebScanKeyRequest) (*ImportMwebScanKeyResponse, error)
+	// lncli: `wallet pegin`
+	// PegIn moves canonical coins of the default wallet account into the MWEB
+	// extension block, paying to a fresh MWEB address of the destination
+	// account. The full value of the selected coins is pegged in, minus the
+	// fees. With dry_run set, the transaction is crafted to report its fees but
+	// not published. The returned transaction is signed in either case.
+	PegIn(context.Context, *PegInRequest) (*PegResponse, error)
+	// lncli: `wallet pegout`
+	// PegOut moves MWEB coins of the default wallet account out of the
+	// extension block, paying to a fresh canonical address of the destination
+	// account. The full value of the selected coins is pegged out, minus the
+	// fees. Pegged out coins can only be spent once they have matured. With
+	// dry_run set, the transaction is crafted to report its fees but not
+	// published. The returned transaction is signed in either case.
+	PegOut(context.Context, *PegOutRequest) (*PegResponse, error)
 	// ImportPublicKey imports a public key as watch-only into the wallet. The
 	// public key is converted into a simple address of the given type and that
 	// address script is watched on chain. For Taproot keys, this will only watch
@@ -770,6 +818,12 @@ func (UnimplementedWalletKitServer) ImportAccount(context.Context, *ImportAccoun
 func (UnimplementedWalletKitServer) ImportMwebScanKey(context.Context, *ImportMwebScanKeyRequest) (*ImportMwebScanKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportMwebScanKey not implemented")
 }
+func (UnimplementedWalletKitServer) PegIn(context.Context, *PegInRequest) (*PegResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PegIn not implemented")
+}
+func (UnimplementedWalletKitServer) PegOut(context.Context, *PegOutRequest) (*PegResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PegOut not implemented")
+}
 func (UnimplementedWalletKitServer) ImportPublicKey(context.Context, *ImportPublicKeyRequest) (*ImportPublicKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportPublicKey not implemented")
 }
@@ -1071,6 +1125,42 @@ func _WalletKit_ImportMwebScanKey_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_PegIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PegInRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).PegIn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/PegIn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).PegIn(ctx, req.(*PegInRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_PegOut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PegOutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).PegOut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/PegOut",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).PegOut(ctx, req.(*PegOutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_ImportPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ImportPublicKeyRequest)
 	if err := dec(in); err != nil {
@@ -1350,6 +1440,14 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ImportMwebScanKey",
 			Handler:    _WalletKit_ImportMwebScanKey_Handler,
 		},
+		{
+			MethodName: "PegIn",
+			Handler:    _WalletKit_PegIn_Handler,
+		},
+		{
+			MethodName: "PegOut",
+			Handler:    _WalletKit_PegOut_Handler,
+		},
 		{
 			MethodName: "ImportPublicKey",
 			Handler:    _WalletKit_ImportPublicKey_Handler,
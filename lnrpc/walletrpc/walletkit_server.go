@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/keychain"
 	"github.com/ltcsuite/lnd/labels"
@@ -164,6 +165,14 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/PegIn": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/PegOut": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 		"/walletrpc.WalletKit/ImportPublicKey": {{
 			Entity: "onchain",
 			Action: "write",
@@ -795,6 +804,8 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 
 		requestedFee := pendingInput.Params.Fee
 		requestedFeeRate := uint64(requestedFee.FeeRate.FeePerKVByte() / 1000)
+		budget := uint64(pendingInput.Params.Budget)
+		deadlineHeight := pendingInput.DeadlineHeight.UnwrapOr(0)
 
 		rpcPendingSweeps = append(rpcPendingSweeps, &PendingSweep{
 			Outpoint:             op,
@@ -806,6 +817,8 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 			RequestedSatPerVbyte: requestedFeeRate,
 			RequestedConfTarget:  requestedFee.ConfTarget,
 			Force:                pendingInput.Params.Force,
+			Budget:               budget,
+			DeadlineHeight:       uint32(deadlineHeight),
 		})
 	}
 
@@ -884,9 +897,16 @@ func (w *WalletKit) BumpFee(ctx context.Context,
 	// bump its fee, which will result in a replacement transaction (RBF)
 	// being broadcast. If it is not aware of the input however,
 	// lnwallet.ErrNotMine is returned.
+	var deadlineHeight fn.Option[int32]
+	if in.DeadlineHeight != 0 {
+		deadlineHeight = fn.Some(int32(in.DeadlineHeight))
+	}
+
 	params := sweep.ParamsUpdate{
-		Fee:   feePreference,
-		Force: in.Force,
+		Fee:            feePreference,
+		Force:          in.Force,
+		Budget:         ltcutil.Amount(in.Budget),
+		DeadlineHeight: deadlineHeight,
 	}
 
 	_, err = w.cfg.Sweeper.UpdateParams(*op, params)
@@ -953,7 +973,11 @@ func (w *WalletKit) BumpFee(ctx context.Context,
 		op, witnessType, signDesc, uint32(currentHeight),
 	)
 
-	sweepParams := sweep.Params{Fee: feePreference}
+	sweepParams := sweep.Params{
+		Fee:            feePreference,
+		Budget:         ltcutil.Amount(in.Budget),
+		DeadlineHeight: deadlineHeight,
+	}
 	if _, err = w.cfg.Sweeper.SweepInput(inp, sweepParams); err != nil {
 		return nil, err
 	}
@@ -215,6 +215,9 @@ func (c *WatchtowerClient) AddTower(ctx context.Context,
 	if err := c.cfg.AnchorClient.AddTower(towerAddr); err != nil {
 		return nil, err
 	}
+	if err := c.cfg.TaprootClient.AddTower(towerAddr); err != nil {
+		return nil, err
+	}
 
 	return &AddTowerResponse{}, nil
 }
@@ -256,6 +259,10 @@ func (c *WatchtowerClient) RemoveTower(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	err = c.cfg.TaprootClient.RemoveTower(pubKey, addr)
+	if err != nil {
+		return nil, err
+	}
 
 	return &RemoveTowerResponse{}, nil
 }
@@ -272,44 +279,33 @@ func (c *WatchtowerClient) ListTowers(ctx context.Context,
 		req.IncludeSessions, req.ExcludeExhaustedSessions,
 	)
 
-	anchorTowers, err := c.cfg.AnchorClient.RegisteredTowers(opts...)
-	if err != nil {
-		return nil, err
-	}
-
-	// Collect all the anchor client towers.
+	// Collect the towers of all clients. If a client has any of the same
+	// towers that a previous client had, then just add the session info
+	// for that client to the existing tower.
 	rpcTowers := make(map[wtdb.TowerID]*Tower)
-	for _, tower := range anchorTowers {
-		rpcTower := marshallTower(
-			tower, PolicyType_ANCHOR, req.IncludeSessions,
-			ackCounts, committedUpdateCounts,
-		)
-
-		rpcTowers[tower.ID] = rpcTower
-	}
-
-	legacyTowers, err := c.cfg.Client.RegisteredTowers(opts...)
-	if err != nil {
-		return nil, err
-	}
-
-	// Collect all the legacy client towers. If it has any of the same
-	// towers that the anchors client has, then just add the session info
-	// for the legacy client to the existing tower.
-	for _, tower := range legacyTowers {
-		rpcTower := marshallTower(
-			tower, PolicyType_LEGACY, req.IncludeSessions,
-			ackCounts, committedUpdateCounts,
-		)
-
-		t, ok := rpcTowers[tower.ID]
-		if !ok {
-			rpcTowers[tower.ID] = rpcTower
-			continue
+	for _, client := range c.policyClients() {
+		clientTowers, err := client.RegisteredTowers(opts...)
+		if err != nil {
+			return nil, err
 		}
 
-		t.SessionInfo = append(t.SessionInfo, rpcTower.SessionInfo...)
-		t.Sessions = append(t.Sessions, rpcTower.Sessions...)
+		for _, tower := range clientTowers {
+			rpcTower := marshallTower(
+				tower, client.policyType, req.IncludeSessions,
+				ackCounts, committedUpdateCounts,
+			)
+
+			t, ok := rpcTowers[tower.ID]
+			if !ok {
+				rpcTowers[tower.ID] = rpcTower
+				continue
+			}
+
+			t.SessionInfo = append(
+				t.SessionInfo, rpcTower.SessionInfo...,
+			)
+			t.Sessions = append(t.Sessions, rpcTower.Sessions...)
+		}
 	}
 
 	towers := make([]*Tower, 0, len(rpcTowers))
@@ -337,42 +333,57 @@ func (c *WatchtowerClient) GetTowerInfo(ctx context.Context,
 		req.IncludeSessions, req.ExcludeExhaustedSessions,
 	)
 
-	// Get the tower and its sessions from anchors client.
-	tower, err := c.cfg.AnchorClient.LookupTower(pubKey, opts...)
-	if err != nil {
-		return nil, err
-	}
-	rpcTower := marshallTower(
-		tower, PolicyType_ANCHOR, req.IncludeSessions, ackCounts,
-		committedUpdateCounts,
-	)
+	// Get the tower and its sessions from each of the clients.
+	var rpcTower *Tower
+	for _, client := range c.policyClients() {
+		tower, err := client.LookupTower(pubKey, opts...)
+		if err != nil {
+			return nil, err
+		}
 
-	// Get the tower and its sessions from legacy client.
-	tower, err = c.cfg.Client.LookupTower(pubKey, opts...)
-	if err != nil {
-		return nil, err
-	}
+		clientTower := marshallTower(
+			tower, client.policyType, req.IncludeSessions,
+			ackCounts, committedUpdateCounts,
+		)
 
-	rpcLegacyTower := marshallTower(
-		tower, PolicyType_LEGACY, req.IncludeSessions, ackCounts,
-		committedUpdateCounts,
-	)
+		if rpcTower == nil {
+			rpcTower = clientTower
+			continue
+		}
 
-	if !bytes.Equal(rpcTower.Pubkey, rpcLegacyTower.Pubkey) {
-		return nil, fmt.Errorf("legacy and anchor clients returned " +
-			"inconsistent results for the given tower")
-	}
+		if !bytes.Equal(rpcTower.Pubkey, clientTower.Pubkey) {
+			return nil, fmt.Errorf("tower clients returned " +
+				"inconsistent results for the given tower")
+		}
 
-	rpcTower.SessionInfo = append(
-		rpcTower.SessionInfo, rpcLegacyTower.SessionInfo...,
-	)
-	rpcTower.Sessions = append(
-		rpcTower.Sessions, rpcLegacyTower.Sessions...,
-	)
+		rpcTower.SessionInfo = append(
+			rpcTower.SessionInfo, clientTower.SessionInfo...,
+		)
+		rpcTower.Sessions = append(
+			rpcTower.Sessions, clientTower.Sessions...,
+		)
+	}
 
 	return rpcTower, nil
 }
 
+// policyClient pairs a tower client with the policy type it serves.
+type policyClient struct {
+	wtclient.Client
+
+	policyType PolicyType
+}
+
+// policyClients returns the tower clients for all supported channel types,
+// anchor channels first, followed by taproot and legacy channels.
+func (c *WatchtowerClient) policyClients() []policyClient {
+	return []policyClient{
+		{Client: c.cfg.AnchorClient, policyType: PolicyType_ANCHOR},
+		{Client: c.cfg.TaprootClient, policyType: PolicyType_TAPROOT},
+		{Client: c.cfg.Client, policyType: PolicyType_LEGACY},
+	}
+}
+
 // constructFunctionalOptions is a helper function that constructs a list of
 // functional options to be used when fetching a tower from the DB. It also
 // returns a map of acked-update counts and one for un-acked-update counts that
@@ -432,6 +443,7 @@ func (c *WatchtowerClient) Stats(ctx context.Context,
 	clientStats := []wtclient.ClientStats{
 		c.cfg.Client.Stats(),
 		c.cfg.AnchorClient.Stats(),
+		c.cfg.TaprootClient.Stats(),
 	}
 
 	var stats wtclient.ClientStats
@@ -470,6 +482,8 @@ func (c *WatchtowerClient) Policy(ctx context.Context,
 		policy = c.cfg.Client.Policy()
 	case PolicyType_ANCHOR:
 		policy = c.cfg.AnchorClient.Policy()
+	case PolicyType_TAPROOT:
+		policy = c.cfg.TaprootClient.Policy()
 	default:
 		return nil, fmt.Errorf("unknown policy type: %v",
 			req.PolicyType)
@@ -9,6 +9,7 @@ import (
 // ChainNotifier is a mock implementation of the ChainNotifier interface.
 type ChainNotifier struct {
 	SpendChan chan *chainntnfs.SpendDetail
+	ReorgChan chan struct{}
 	EpochChan chan *chainntnfs.BlockEpoch
 	ConfChan  chan *chainntnfs.TxConfirmation
 }
@@ -32,6 +33,7 @@ func (c *ChainNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
 
 	return &chainntnfs.SpendEvent{
 		Spend:  c.SpendChan,
+		Reorg:  c.ReorgChan,
 		Cancel: func() {},
 	}, nil
 }
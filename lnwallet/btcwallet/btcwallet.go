@@ -1484,6 +1484,7 @@ func minedTransactionsToDetails(
 			RawTx:             tx.Transaction,
 			Label:             tx.Label,
 			PreviousOutpoints: previousOutpoints,
+			Mweb:              lnwallet.ClassifyMwebTx(wireTx),
 		}
 
 		balanceDelta, err := extractBalanceDelta(tx, wireTx)
@@ -1557,6 +1558,7 @@ func unminedTransactionsToDetail(
 		RawTx:             summary.Transaction,
 		Label:             summary.Label,
 		PreviousOutpoints: previousOutpoints,
+		Mweb:              lnwallet.ClassifyMwebTx(wireTx),
 	}
 
 	balanceDelta, err := extractBalanceDelta(summary, wireTx)
@@ -0,0 +1,294 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+var (
+	// ErrChanTypeUpgradeUnsupported is returned when an upgrade of the
+	// channel type is requested that can't be applied to a live channel.
+	ErrChanTypeUpgradeUnsupported = errors.New("channel type upgrade " +
+		"not supported")
+
+	// ErrChanTypeUpgradeNotClean is returned when the channel type is to
+	// be upgraded while the channel still has HTLCs or unacked updates.
+	ErrChanTypeUpgradeNotClean = errors.New("channel must be clean to " +
+		"upgrade its channel type")
+)
+
+// commitFormatBits are the bits of a channel type that determine the format of
+// its commitment transactions, and are replaced when the channel type of a
+// live channel is upgraded.
+const commitFormatBits = channeldb.SingleFunderTweaklessBit |
+	channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+
+// UpgradedChanType returns the channel type that a channel of the given type
+// has after upgrading it to the passed commitment type. Only upgrades that
+// keep the funding output of the channel are supported, which rules out
+// taproot channels as well as script enforced leases.
+func UpgradedChanType(chanType channeldb.ChannelType,
+	commitType CommitmentType) (channeldb.ChannelType, error) {
+
+	if chanType.IsTaproot() || chanType.HasLeaseExpiration() {
+		return 0, fmt.Errorf("%w: channel type %v can't be upgraded",
+			ErrChanTypeUpgradeUnsupported, chanType)
+	}
+
+	baseType := chanType &^ commitFormatBits
+	switch commitType {
+	case CommitmentTypeTweakless:
+		if chanType.IsTweakless() {
+			return 0, fmt.Errorf("%w: channel already uses static "+
+				"remote keys", ErrChanTypeUpgradeUnsupported)
+		}
+
+		return baseType | channeldb.SingleFunderTweaklessBit, nil
+
+	case CommitmentTypeAnchorsZeroFeeHtlcTx:
+		if chanType.HasAnchors() {
+			return 0, fmt.Errorf("%w: channel already uses "+
+				"anchors", ErrChanTypeUpgradeUnsupported)
+		}
+
+		return baseType | commitFormatBits, nil
+
+	default:
+		return 0, fmt.Errorf("%w: can't upgrade to %v",
+			ErrChanTypeUpgradeUnsupported, commitType)
+	}
+}
+
+// ChannelTypeForUpgrade returns the explicit channel type that is proposed to
+// the remote peer to upgrade a channel to the given commitment type.
+func ChannelTypeForUpgrade(commitType CommitmentType) (*lnwire.ChannelType,
+	error) {
+
+	var features *lnwire.RawFeatureVector
+	switch commitType {
+	case CommitmentTypeTweakless:
+		features = lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyRequired,
+		)
+
+	case CommitmentTypeAnchorsZeroFeeHtlcTx:
+		features = lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired,
+		)
+
+	default:
+		return nil, fmt.Errorf("%w: can't upgrade to %v",
+			ErrChanTypeUpgradeUnsupported, commitType)
+	}
+
+	chanType := lnwire.ChannelType(*features)
+
+	return &chanType, nil
+}
+
+// CommitTypeForUpgrade returns the commitment type a channel is upgraded to
+// when the remote peer proposes the given explicit channel type.
+func CommitTypeForUpgrade(chanType lnwire.ChannelType) (CommitmentType,
+	error) {
+
+	features := lnwire.RawFeatureVector(chanType)
+	for _, commitType := range []CommitmentType{
+		CommitmentTypeTweakless, CommitmentTypeAnchorsZeroFeeHtlcTx,
+	} {
+		// Both types are known to map to a channel type, so we can
+		// ignore the error.
+		upgradeType, _ := ChannelTypeForUpgrade(commitType)
+		upgradeFeatures := lnwire.RawFeatureVector(*upgradeType)
+		if features.Equals(&upgradeFeatures) {
+			return commitType, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: unknown channel type %v",
+		ErrChanTypeUpgradeUnsupported, features)
+}
+
+// ValidateCommitTypeUpgrade checks whether the channel can be switched over
+// to the given commitment type without applying the upgrade. The channel must
+// be clean, and the initiator must be able to pay for the commitment fee and
+// anchor outputs of the new type while staying above its reserve.
+func (lc *LightningChannel) ValidateCommitTypeUpgrade(
+	commitType CommitmentType) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	_, err := lc.validateCommitTypeUpgrade(commitType)
+
+	return err
+}
+
+// validateCommitTypeUpgrade checks whether the channel can be switched over to
+// the given commitment type and returns the resulting channel type. This
+// function expects to be executed with a lock held.
+func (lc *LightningChannel) validateCommitTypeUpgrade(
+	commitType CommitmentType) (channeldb.ChannelType, error) {
+
+	chanState := lc.channelState
+	newType, err := UpgradedChanType(chanState.ChanType, commitType)
+	if err != nil {
+		return 0, err
+	}
+
+	if !lc.isChannelClean() {
+		return 0, ErrChanTypeUpgradeNotClean
+	}
+
+	// The stored balances have the commitment fee subtracted from the
+	// initiator, so we'll add it back in to check that the initiator can
+	// afford the commitment of the new type.
+	commit := chanState.LocalCommitment
+	initiatorBalance := commit.RemoteBalance.ToSatoshis()
+	initiatorReserve := chanState.RemoteChanCfg.ChanReserve
+	if chanState.IsInitiator {
+		initiatorBalance = commit.LocalBalance.ToSatoshis()
+		initiatorReserve = chanState.LocalChanCfg.ChanReserve
+	}
+	initiatorBalance += commit.CommitFee
+
+	feePerKw := chainfee.SatPerKWeight(commit.FeePerKw)
+	required := feePerKw.FeeForWeight(CommitWeight(newType)) +
+		initiatorReserve
+	if !chanState.ChanType.HasAnchors() && newType.HasAnchors() {
+		required += 2 * anchorSize
+	}
+
+	if initiatorBalance < required {
+		return 0, fmt.Errorf("%w: initiator balance %v can't cover "+
+			"%v required by the new commitment type",
+			ErrBelowChanReserve, initiatorBalance, required)
+	}
+
+	return newType, nil
+}
+
+// UpgradeCommitType switches the channel over to the given commitment type.
+// The new type applies to the next commitment of both peers, so a new state
+// must be signed by both of them afterwards to complete the upgrade.
+func (lc *LightningChannel) UpgradeCommitType(commitType CommitmentType) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	newType, err := lc.validateCommitTypeUpgrade(commitType)
+	if err != nil {
+		return err
+	}
+
+	lc.log.Infof("Upgrading channel type from %v to %v",
+		lc.channelState.ChanType, newType)
+
+	return lc.channelState.UpgradeChanType(newType)
+}
+
+// ProposeCommitTypeUpgrade records that we proposed to upgrade the channel to
+// the given commitment type. The proposal is kept until the upgrade is applied
+// with UpgradeCommitType or withdrawn with WithdrawCommitTypeProposal. The
+// state of the channel is only validated once the upgrade is applied.
+func (lc *LightningChannel) ProposeCommitTypeUpgrade(
+	commitType CommitmentType) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	newType, err := UpgradedChanType(lc.channelState.ChanType, commitType)
+	if err != nil {
+		return err
+	}
+
+	return lc.channelState.ProposeChanType(newType)
+}
+
+// ProposedCommitType returns the commitment type we proposed to upgrade the
+// channel to, if the remote peer hasn't answered the proposal yet.
+func (lc *LightningChannel) ProposedCommitType() fn.Option[CommitmentType] {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	// Only the upgrades to static remote keys and anchors can be proposed,
+	// which are told apart by the anchor bit.
+	return fn.MapOption(func(chanType channeldb.ChannelType) CommitmentType {
+		if chanType.HasAnchors() {
+			return CommitmentTypeAnchorsZeroFeeHtlcTx
+		}
+
+		return CommitmentTypeTweakless
+	})(lc.channelState.ProposedChanType)
+}
+
+// WithdrawCommitTypeProposal removes our proposal to upgrade the commitment
+// type of the channel.
+func (lc *LightningChannel) WithdrawCommitTypeProposal() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.channelState.WithdrawChanTypeProposal()
+}
+
+// ChanTypeUpgradeComplete returns true once both peers have revoked all
+// commitments that were created before the latest upgrade of the channel
+// type. It also returns true if the channel type was never upgraded.
+func (lc *LightningChannel) ChanTypeUpgradeComplete() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	upgrades := lc.channelState.ChanTypeUpgrades
+	if len(upgrades) == 0 {
+		return true
+	}
+	upgrade := upgrades[len(upgrades)-1]
+
+	return lc.localCommitChain.tail().height >= upgrade.LocalHeight &&
+		lc.remoteCommitChain.tail().height >= upgrade.RemoteHeight
+}
+
+// chanTypeUpgradePending returns true if the latest upgrade of the channel
+// type hasn't been signed for yet. If local is true, the remote commitment
+// chain is checked, as we owe the remote peer a commitment of the new type.
+// Otherwise, the local commitment chain is checked. This function expects to
+// be executed with a lock held.
+func (lc *LightningChannel) chanTypeUpgradePending(local bool) bool {
+	upgrades := lc.channelState.ChanTypeUpgrades
+	if len(upgrades) == 0 {
+		return false
+	}
+	upgrade := upgrades[len(upgrades)-1]
+
+	if local {
+		return lc.remoteCommitChain.tip().height < upgrade.RemoteHeight
+	}
+
+	return lc.localCommitChain.tip().height < upgrade.LocalHeight
+}
+
+// upgradedAnchorAmt returns the amount the initiator pays for the anchor
+// outputs of the next commitment of the given chain, in addition to the
+// amount already paid on the chain's tip. It is only non-zero if the channel
+// type was upgraded to anchors after the tip was created. This function
+// expects to be executed with a lock held.
+func (lc *LightningChannel) upgradedAnchorAmt(remoteChain bool) ltcutil.Amount {
+	commitChain := lc.localCommitChain
+	if remoteChain {
+		commitChain = lc.remoteCommitChain
+	}
+
+	tipType := lc.channelState.ChanTypeAtHeight(
+		!remoteChain, commitChain.tip().height,
+	)
+	if tipType.HasAnchors() || !lc.channelState.ChanType.HasAnchors() {
+		return 0
+	}
+
+	return 2 * anchorSize
+}
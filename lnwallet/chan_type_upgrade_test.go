@@ -0,0 +1,172 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpgradedChanType asserts that only upgrades of the commitment format
+// that keep the funding output are accepted.
+func TestUpgradedChanType(t *testing.T) {
+	t.Parallel()
+
+	legacy := channeldb.SingleFunderBit
+	tweakless := channeldb.SingleFunderTweaklessBit
+	anchors := tweakless | channeldb.AnchorOutputsBit |
+		channeldb.ZeroHtlcTxFeeBit
+
+	newType, err := UpgradedChanType(legacy, CommitmentTypeTweakless)
+	require.NoError(t, err)
+	require.Equal(t, tweakless, newType)
+
+	newType, err = UpgradedChanType(
+		legacy, CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+	require.NoError(t, err)
+	require.Equal(t, anchors, newType)
+
+	// Unrelated bits of the channel type are kept.
+	newType, err = UpgradedChanType(
+		tweakless|channeldb.ScidAliasFeatureBit,
+		CommitmentTypeAnchorsZeroFeeHtlcTx,
+	)
+	require.NoError(t, err)
+	require.Equal(t, anchors|channeldb.ScidAliasFeatureBit, newType)
+
+	_, err = UpgradedChanType(tweakless, CommitmentTypeTweakless)
+	require.ErrorIs(t, err, ErrChanTypeUpgradeUnsupported)
+
+	_, err = UpgradedChanType(anchors, CommitmentTypeAnchorsZeroFeeHtlcTx)
+	require.ErrorIs(t, err, ErrChanTypeUpgradeUnsupported)
+
+	_, err = UpgradedChanType(tweakless, CommitmentTypeSimpleTaproot)
+	require.ErrorIs(t, err, ErrChanTypeUpgradeUnsupported)
+
+	_, err = UpgradedChanType(
+		anchors|channeldb.LeaseExpirationBit, CommitmentTypeTweakless,
+	)
+	require.ErrorIs(t, err, ErrChanTypeUpgradeUnsupported)
+
+	// The commitment types we can upgrade to must survive the round trip
+	// through their explicit channel type.
+	for _, commitType := range []CommitmentType{
+		CommitmentTypeTweakless, CommitmentTypeAnchorsZeroFeeHtlcTx,
+	} {
+		chanType, err := ChannelTypeForUpgrade(commitType)
+		require.NoError(t, err)
+
+		parsed, err := CommitTypeForUpgrade(*chanType)
+		require.NoError(t, err)
+		require.Equal(t, commitType, parsed)
+	}
+
+	_, err = CommitTypeForUpgrade(lnwire.ChannelType(
+		*lnwire.NewRawFeatureVector(lnwire.ScidAliasRequired),
+	))
+	require.ErrorIs(t, err, ErrChanTypeUpgradeUnsupported)
+}
+
+// TestUpgradeCommitType asserts that a live channel can be upgraded to anchor
+// commitments, and that revoked commitments created before the upgrade can
+// still be punished afterwards.
+func TestUpgradeCommitType(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	// Move the channel forward by one state with an HTLC, so that there
+	// is a revoked commitment of the old type.
+	htlc, preimage := createHTLC(0, lnwire.NewMSatFromSatoshis(50_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// The channel can't be upgraded while the HTLC is active.
+	err = aliceChannel.UpgradeCommitType(CommitmentTypeAnchorsZeroFeeHtlcTx)
+	require.ErrorIs(t, err, ErrChanTypeUpgradeNotClean)
+
+	require.NoError(t, bobChannel.SettleHTLC(preimage, 0, nil, nil, nil))
+	require.NoError(t, aliceChannel.ReceiveHTLCSettle(preimage, 0))
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	require.True(t, aliceChannel.IsChannelClean())
+	require.False(t, aliceChannel.OweCommitment())
+
+	oldRemoteHeight := aliceChannel.channelState.RemoteCommitment.
+		CommitHeight
+	oldRemoteCommit := aliceChannel.channelState.RemoteCommitment.CommitTx
+
+	// Alice proposes the upgrade, which is kept until it is applied.
+	require.True(t, aliceChannel.ProposedCommitType().IsNone())
+	require.NoError(t, aliceChannel.ProposeCommitTypeUpgrade(
+		CommitmentTypeAnchorsZeroFeeHtlcTx,
+	))
+	require.Equal(
+		t, fn.Some[CommitmentType](CommitmentTypeAnchorsZeroFeeHtlcTx),
+		aliceChannel.ProposedCommitType(),
+	)
+
+	// Both peers upgrade the channel, after which each of them owes the
+	// other a commitment of the new type.
+	require.NoError(t, aliceChannel.UpgradeCommitType(
+		CommitmentTypeAnchorsZeroFeeHtlcTx,
+	))
+	require.True(t, aliceChannel.ProposedCommitType().IsNone())
+	require.NoError(t, bobChannel.UpgradeCommitType(
+		CommitmentTypeAnchorsZeroFeeHtlcTx,
+	))
+	require.True(t, aliceChannel.OweCommitment())
+	require.True(t, bobChannel.OweCommitment())
+	require.False(t, aliceChannel.ChanTypeUpgradeComplete())
+	require.False(t, aliceChannel.IsChannelClean())
+
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	require.True(t, aliceChannel.ChanTypeUpgradeComplete())
+	require.True(t, bobChannel.ChanTypeUpgradeComplete())
+	require.False(t, aliceChannel.OweCommitment())
+	require.False(t, bobChannel.OweCommitment())
+	require.True(t, aliceChannel.ChanType().HasAnchors())
+
+	// The new commitments carry both anchor outputs, which are paid for
+	// by Alice as the initiator.
+	aliceCommit := aliceChannel.channelState.LocalCommitment
+	require.Len(t, aliceCommit.CommitTx.TxOut, 4)
+
+	bobCommit := bobChannel.channelState.LocalCommitment
+	require.Len(t, bobCommit.CommitTx.TxOut, 4)
+	require.Equal(t, aliceCommit.LocalBalance, bobCommit.RemoteBalance)
+	require.Equal(t, aliceCommit.RemoteBalance, bobCommit.LocalBalance)
+
+	// The channel keeps operating with the new commitment type.
+	htlc, _ = createHTLC(1, lnwire.NewMSatFromSatoshis(50_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// A breach of Bob's commitment from before the upgrade must still be
+	// recognized using the old commitment type.
+	retribution, err := NewBreachRetribution(
+		aliceChannel.channelState, oldRemoteHeight, 0, oldRemoteCommit,
+	)
+	require.NoError(t, err)
+	require.Equal(t, oldRemoteCommit.TxHash(), retribution.BreachTxHash)
+
+	require.NotNil(t, retribution.LocalOutputSignDesc)
+	localOut := oldRemoteCommit.TxOut[retribution.LocalOutpoint.Index]
+	require.Equal(
+		t, localOut.PkScript,
+		retribution.LocalOutputSignDesc.Output.PkScript,
+	)
+}
@@ -86,6 +86,12 @@ func calculateFees(utxos []Coin, feeRate chainfee.SatPerKWeight) (ltcutil.Amount
 				txscript.SigHashDefault,
 			)
 
+		case txscript.IsMweb(utxo.PkScript):
+			return 0, 0, &ErrMwebFunding{
+				Coins:            []Coin{utxo},
+				ManuallySelected: true,
+			}
+
 		default:
 			return 0, 0, &errUnsupportedInput{utxo.PkScript}
 		}
@@ -0,0 +1,92 @@
+package chanfunding
+
+import (
+	"github.com/ltcsuite/lnd/keychain"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// InteractiveIntent is an intent created by the InteractiveAssembler. It
+// describes the funding output of a dual funded channel, whose funding
+// transaction is constructed interactively by both parties. The inputs and
+// change outputs of both parties aren't managed by the intent, instead the
+// channel point is bound once the construction of the funding transaction is
+// complete.
+type InteractiveIntent struct {
+	ShimIntent
+}
+
+// BindKeys binds both the local and remote multi-sig keys of the channel,
+// which makes the funding output known.
+func (i *InteractiveIntent) BindKeys(localKey *keychain.KeyDescriptor,
+	remoteKey *btcec.PublicKey) {
+
+	i.localKey = localKey
+	i.remoteKey = remoteKey
+}
+
+// AddRemoteFunding records the amount the remote party contributes to the
+// funding output. The opener of a dual funded channel only learns about it
+// once the remote party accepted the channel.
+func (i *InteractiveIntent) AddRemoteFunding(amt ltcutil.Amount) {
+	i.remoteFundingAmt = amt
+}
+
+// SetChanPoint sets the channel point of the interactively constructed
+// funding transaction.
+func (i *InteractiveIntent) SetChanPoint(chanPoint wire.OutPoint) {
+	i.chanPoint = &chanPoint
+}
+
+// Outputs returns all outputs of the final funding transaction that we know
+// about. Before the funding keys are bound, no output is known yet.
+//
+// NOTE: This method satisfies the chanfunding.Intent interface.
+func (i *InteractiveIntent) Outputs() []*wire.TxOut {
+	if i.localKey == nil || i.remoteKey == nil {
+		return nil
+	}
+
+	return i.ShimIntent.Outputs()
+}
+
+// A compile-time check to ensure InteractiveIntent adheres to the Intent
+// interface.
+var _ Intent = (*InteractiveIntent)(nil)
+
+// InteractiveAssembler is a type of chanfunding.Assembler that is used for
+// dual funded channels. Both parties add their inputs and outputs to the
+// funding transaction in an interactive protocol, so the assembler only
+// tracks the funding amounts of both parties and the resulting funding
+// output.
+type InteractiveAssembler struct{}
+
+// NewInteractiveAssembler creates a new InteractiveAssembler.
+func NewInteractiveAssembler() *InteractiveAssembler {
+	return &InteractiveAssembler{}
+}
+
+// ProvisionChannel creates a new InteractiveIntent for the local and remote
+// amounts of the passed funding Request.
+//
+// NOTE: This method satisfies the chanfunding.Assembler interface.
+func (i *InteractiveAssembler) ProvisionChannel(req *Request) (Intent, error) {
+	return &InteractiveIntent{
+		ShimIntent: ShimIntent{
+			localFundingAmt:  req.LocalAmt,
+			remoteFundingAmt: req.RemoteAmt,
+		},
+	}, nil
+}
+
+// FundingTxAvailable is an empty method that an assembler can implement to
+// signal to callers that its able to provide the funding transaction for the
+// channel via the intent it returns.
+//
+// NOTE: This method is a part of the FundingTxAssembler interface.
+func (i *InteractiveAssembler) FundingTxAvailable() {}
+
+// A compile-time assertion to ensure the InteractiveAssembler meets the
+// FundingTxAssembler interface.
+var _ FundingTxAssembler = (*InteractiveAssembler)(nil)
@@ -0,0 +1,70 @@
+package chanfunding
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/keychain"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInteractiveIntent tests that the funding output of an interactive
+// intent commits to the contributions of both parties once the keys are
+// bound.
+func TestInteractiveIntent(t *testing.T) {
+	t.Parallel()
+
+	const (
+		localAmt  ltcutil.Amount = 400_000
+		remoteAmt ltcutil.Amount = 244_000
+	)
+
+	a := NewInteractiveAssembler()
+	intent, err := a.ProvisionChannel(&Request{LocalAmt: localAmt})
+	require.NoError(t, err)
+
+	interactiveIntent, ok := intent.(*InteractiveIntent)
+	require.True(t, ok)
+
+	// Without any keys, the funding output isn't known yet.
+	require.Empty(t, interactiveIntent.Outputs())
+	_, _, err = interactiveIntent.FundingOutput()
+	require.Error(t, err)
+
+	// The opener learns about the remote contribution once the channel is
+	// accepted, along with the remote multi-sig key.
+	_, localPubkey := btcec.PrivKeyFromBytes(localPrivkey)
+	_, remotePubkey := btcec.PrivKeyFromBytes(remotePrivkey)
+	interactiveIntent.AddRemoteFunding(remoteAmt)
+	interactiveIntent.BindKeys(
+		&keychain.KeyDescriptor{PubKey: localPubkey}, remotePubkey,
+	)
+
+	require.Equal(t, localAmt, interactiveIntent.LocalFundingAmt())
+	require.Equal(t, remoteAmt, interactiveIntent.RemoteFundingAmt())
+
+	_, expectedOut, err := input.GenFundingPkScript(
+		localPubkey.SerializeCompressed(),
+		remotePubkey.SerializeCompressed(), int64(localAmt+remoteAmt),
+	)
+	require.NoError(t, err)
+
+	_, fundingOut, err := interactiveIntent.FundingOutput()
+	require.NoError(t, err)
+	require.Equal(t, expectedOut, fundingOut)
+	require.Equal(t, []*wire.TxOut{expectedOut}, interactiveIntent.Outputs())
+
+	// The channel point is only known once the funding transaction has
+	// been constructed.
+	_, err = interactiveIntent.ChanPoint()
+	require.Error(t, err)
+
+	chanPoint := wire.OutPoint{Index: 1}
+	interactiveIntent.SetChanPoint(chanPoint)
+	op, err := interactiveIntent.ChanPoint()
+	require.NoError(t, err)
+	require.Equal(t, chanPoint, *op)
+}
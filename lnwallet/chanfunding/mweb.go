@@ -0,0 +1,164 @@
+package chanfunding
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/psbt"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// ErrMwebFunding is a type matching the error interface which is returned
+// when funding a channel would require spending MWEB coins. MWEB outputs live
+// in the extension block and can't be spent by the canonical transaction that
+// creates the funding output, so they first need to be pegged out to a
+// canonical address.
+type ErrMwebFunding struct {
+	// Coins are the MWEB coins that were selected to fund the channel or,
+	// if coin selection was left to the wallet, the ones that would need
+	// to be pegged out to afford it.
+	Coins []Coin
+
+	// ManuallySelected is true if the MWEB coins were explicitly selected
+	// by the caller, instead of being the wallet's only way to afford the
+	// channel.
+	ManuallySelected bool
+}
+
+// Amount returns the total value of the MWEB coins.
+func (e *ErrMwebFunding) Amount() ltcutil.Amount {
+	var amt ltcutil.Amount
+	for _, coin := range e.Coins {
+		amt += ltcutil.Amount(coin.Value)
+	}
+
+	return amt
+}
+
+// Error returns a human readable string describing the error.
+func (e *ErrMwebFunding) Error() string {
+	if e.ManuallySelected {
+		return fmt.Sprintf("%d of the selected coins (%v) are MWEB "+
+			"outputs, which can't fund a channel directly and "+
+			"need to be pegged out first", len(e.Coins),
+			e.Amount())
+	}
+
+	return fmt.Sprintf("not enough canonical funds to create funding "+
+		"transaction, %d MWEB coins (%v) need to be pegged out first",
+		len(e.Coins), e.Amount())
+}
+
+// splitMwebCoins splits the given coins into canonical and MWEB coins.
+func splitMwebCoins(coins []Coin) ([]Coin, []Coin) {
+	var canonical, mweb []Coin
+	for _, coin := range coins {
+		if txscript.IsMweb(coin.PkScript) {
+			mweb = append(mweb, coin)
+			continue
+		}
+
+		canonical = append(canonical, coin)
+	}
+
+	return canonical, mweb
+}
+
+// selectPegOutCoins selects, largest first, the MWEB coins that need to be
+// pegged out to make up for the given shortfall of a coin selection over
+// canonical coins. Besides the shortfall itself, the pegged out coins must
+// pay for the additional input they add to the funding transaction, and the
+// dust limit is kept as a margin for the fee of the peg-out. If all MWEB coins
+// combined don't suffice, nil is returned.
+func selectPegOutCoins(shortfall ltcutil.Amount,
+	feeRate chainfee.SatPerKWeight, dustLimit ltcutil.Amount,
+	mwebCoins []Coin) []Coin {
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddP2WKHInput()
+	inputFee := feeRate.FeeForWeight(int64(weightEstimate.Weight()))
+
+	coins := make([]Coin, len(mwebCoins))
+	copy(coins, mwebCoins)
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].Value > coins[j].Value
+	})
+
+	_, selected, err := selectInputs(shortfall+inputFee+dustLimit, coins)
+	if err != nil {
+		return nil
+	}
+
+	return selected
+}
+
+// mwebFundingError turns an insufficient funds error of a coin selection over
+// canonical coins into an ErrMwebFunding if pegging out some of the given MWEB
+// coins would make up for the shortfall. Any other error is returned as is.
+func mwebFundingError(err error, feeRate chainfee.SatPerKWeight,
+	dustLimit ltcutil.Amount, mwebCoins []Coin) error {
+
+	var errInsufficientFunds *ErrInsufficientFunds
+	if len(mwebCoins) == 0 || !errors.As(err, &errInsufficientFunds) {
+		return err
+	}
+
+	shortfall := errInsufficientFunds.amountAvailable -
+		errInsufficientFunds.amountSelected
+	pegOutCoins := selectPegOutCoins(
+		shortfall, feeRate, dustLimit, mwebCoins,
+	)
+	if len(pegOutCoins) == 0 {
+		return err
+	}
+
+	return &ErrMwebFunding{
+		Coins: pegOutCoins,
+	}
+}
+
+// verifyNoMwebInputs makes sure none of the inputs of a PSBT spends an MWEB
+// output, returning an ErrMwebFunding listing them otherwise.
+func verifyNoMwebInputs(ins []psbt.PInput) error {
+	var mwebCoins []Coin
+	for _, in := range ins {
+		var coin Coin
+		switch {
+		case in.MwebOutputId != nil:
+			coin.OutPoint = wire.OutPoint{Hash: *in.MwebOutputId}
+			if in.MwebAmount != nil {
+				coin.Value = int64(*in.MwebAmount)
+			}
+
+		case in.WitnessUtxo != nil &&
+			txscript.IsMweb(in.WitnessUtxo.PkScript):
+
+			coin.TxOut = *in.WitnessUtxo
+			if in.PrevoutHash != nil && in.PrevoutIndex != nil {
+				coin.OutPoint = wire.OutPoint{
+					Hash:  *in.PrevoutHash,
+					Index: *in.PrevoutIndex,
+				}
+			}
+
+		default:
+			continue
+		}
+
+		mwebCoins = append(mwebCoins, coin)
+	}
+
+	if len(mwebCoins) == 0 {
+		return nil
+	}
+
+	return &ErrMwebFunding{
+		Coins:            mwebCoins,
+		ManuallySelected: true,
+	}
+}
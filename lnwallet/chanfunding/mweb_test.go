@@ -0,0 +1,129 @@
+package chanfunding
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/psbt"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// mwebScript is the script of an MWEB stealth address, made of its scan and
+// spend public keys.
+var mwebScript, _ = hex.DecodeString(
+	"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+		"02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b9" +
+		"5c709ee5",
+)
+
+// TestSelectPegOutCoins tests that the largest MWEB coins are selected to make
+// up for a shortfall, including the fee of the extra funding input and a
+// margin for the peg-out fee.
+func TestSelectPegOutCoins(t *testing.T) {
+	t.Parallel()
+
+	const (
+		feeRate   = chainfee.SatPerKWeight(1000)
+		dustLimit = ltcutil.Amount(1000)
+	)
+
+	mwebCoin := func(value int64) Coin {
+		return Coin{
+			TxOut: wire.TxOut{
+				Value:    value,
+				PkScript: mwebScript,
+			},
+		}
+	}
+	coins := []Coin{
+		mwebCoin(10_000), mwebCoin(50_000), mwebCoin(20_000),
+	}
+
+	// A shortfall that the largest coin covers only needs that one.
+	selected := selectPegOutCoins(40_000, feeRate, dustLimit, coins)
+	require.Equal(t, []Coin{mwebCoin(50_000)}, selected)
+
+	// If the margin for fees isn't covered, the next largest coin is
+	// added.
+	selected = selectPegOutCoins(50_000, feeRate, dustLimit, coins)
+	require.Equal(t, []Coin{mwebCoin(50_000), mwebCoin(20_000)}, selected)
+
+	// Nothing is selected if all MWEB coins combined don't suffice.
+	selected = selectPegOutCoins(80_000, feeRate, dustLimit, coins)
+	require.Nil(t, selected)
+}
+
+// TestMwebFundingError tests that only insufficient funds errors that pegging
+// out MWEB coins can make up for are turned into an ErrMwebFunding.
+func TestMwebFundingError(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(1000)
+
+	mwebCoins := []Coin{{
+		TxOut: wire.TxOut{
+			Value:    100_000,
+			PkScript: mwebScript,
+		},
+	}}
+	coins := []Coin{{
+		TxOut: wire.TxOut{
+			Value:    50_000,
+			PkScript: p2wkhScript,
+		},
+	}}
+
+	_, _, err := CoinSelect(feeRate, 80_000, 1000, coins)
+	require.IsType(t, &ErrInsufficientFunds{}, err)
+
+	// Without MWEB coins, the original error is returned.
+	require.Equal(t, err, mwebFundingError(err, feeRate, 1000, nil))
+
+	// With them, they need to be pegged out.
+	var mwebErr *ErrMwebFunding
+	require.ErrorAs(t, mwebFundingError(err, feeRate, 1000, mwebCoins),
+		&mwebErr)
+	require.Equal(t, mwebCoins, mwebErr.Coins)
+	require.False(t, mwebErr.ManuallySelected)
+
+	// Unless they are too small to make up for the shortfall.
+	_, _, err = CoinSelect(feeRate, 200_000, 1000, coins)
+	require.Equal(t, err, mwebFundingError(err, feeRate, 1000, mwebCoins))
+
+	// Coin selection over MWEB coins fails with an ErrMwebFunding too.
+	_, _, err = CoinSelect(feeRate, 80_000, 1000, mwebCoins)
+	require.ErrorAs(t, err, &mwebErr)
+	require.True(t, mwebErr.ManuallySelected)
+}
+
+// TestVerifyNoMwebInputs tests that PSBTs spending MWEB outputs are rejected.
+func TestVerifyNoMwebInputs(t *testing.T) {
+	t.Parallel()
+
+	outputID := chainhash.Hash{0x01}
+	amt := ltcutil.Amount(100_000)
+	canonical := psbt.PInput{
+		WitnessUtxo: &wire.TxOut{
+			Value:    50_000,
+			PkScript: p2wkhScript,
+		},
+	}
+	mweb := psbt.PInput{
+		MwebOutputId: &outputID,
+		MwebAmount:   &amt,
+	}
+
+	require.NoError(t, verifyNoMwebInputs([]psbt.PInput{canonical}))
+
+	err := verifyNoMwebInputs([]psbt.PInput{canonical, mweb})
+
+	var mwebErr *ErrMwebFunding
+	require.ErrorAs(t, err, &mwebErr)
+	require.Len(t, mwebErr.Coins, 1)
+	require.Equal(t, outputID, mwebErr.Coins[0].Hash)
+	require.Equal(t, amt, mwebErr.Amount())
+}
@@ -241,6 +241,12 @@ func (i *PsbtIntent) Verify(packet *psbt.Packet, skipFinalize bool) error {
 		return fmt.Errorf("funding output not found in PSBT")
 	}
 
+	// MWEB inputs can't be spent by the canonical transaction that
+	// creates the funding output.
+	if err := verifyNoMwebInputs(packet.Inputs); err != nil {
+		return err
+	}
+
 	// At least one input needs to be specified and it must be large enough
 	// to pay for all outputs. We don't want to dive into fee estimation
 	// here so we just assume that if the input amount exceeds the output
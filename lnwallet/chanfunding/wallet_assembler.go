@@ -303,6 +303,22 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 			}
 		}
 
+		// MWEB coins can't be spent by the funding transaction, so
+		// they need to be pegged out before they can fund a channel.
+		_, mwebSelected := splitMwebCoins(manuallySelectedCoins)
+		if len(mwebSelected) > 0 {
+			return &ErrMwebFunding{
+				Coins:            mwebSelected,
+				ManuallySelected: true,
+			}
+		}
+
+		canonicalCoins, mwebCoins := splitMwebCoins(allCoins)
+		if len(mwebCoins) > 0 {
+			log.Debugf("Excluding %d MWEB coins from funding tx "+
+				"coin selection", len(mwebCoins))
+		}
+
 		var (
 			coins                []Coin
 			selectedCoins        []Coin
@@ -312,10 +328,13 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 
 		// If outputs were specified manually then we'll take the
 		// corresponding coins as basis for coin selection. Otherwise,
-		// all available coins from our wallet are used.
-		coins = allCoins
+		// all available canonical coins from our wallet are used, and
+		// the MWEB ones are only considered for a peg-out if those
+		// don't suffice.
+		coins = canonicalCoins
 		if len(manuallySelectedCoins) > 0 {
 			coins = manuallySelectedCoins
+			mwebCoins = nil
 		}
 
 		// Perform coin selection over our available, unlocked unspent
@@ -364,7 +383,7 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 				}
 
 				sumManual := sumCoins(manuallySelectedCoins)
-				sumAll := sumCoins(allCoins)
+				sumAll := sumCoins(canonicalCoins)
 
 				// If sufficient reserve funds are available we
 				// don't have to provide for it during coin
@@ -420,7 +439,9 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 				r.FeeRate, r.LocalAmt, dustLimit, coins,
 			)
 			if err != nil {
-				return err
+				return mwebFundingError(
+					err, r.FeeRate, dustLimit, mwebCoins,
+				)
 			}
 
 		// Otherwise do a normal coin selection where we target a given
@@ -432,7 +453,9 @@ func (w *WalletAssembler) ProvisionChannel(r *Request) (Intent, error) {
 				r.FeeRate, r.LocalAmt, dustLimit, coins,
 			)
 			if err != nil {
-				return err
+				return mwebFundingError(
+					err, r.FeeRate, dustLimit, mwebCoins,
+				)
 			}
 		}
 
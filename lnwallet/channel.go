@@ -358,6 +358,19 @@ type PaymentDescriptor struct {
 	// NOTE: Populated only in payment descriptor with MalformedFail type.
 	ShaOnionBlob [sha256.Size]byte
 
+	// BlindingPoint is the ephemeral pubkey that is used by the receiving
+	// node to decrypt its route blinding data, if the HTLC is part of a
+	// blinded route.
+	//
+	// NOTE: Populated only on add payment descriptor entry types.
+	BlindingPoint *btcec.PublicKey
+
+	// CustomRecords are the custom TLV records that were attached to the
+	// wire message of the HTLC.
+	//
+	// NOTE: Populated only on add payment descriptor entry types.
+	CustomRecords lnwire.CustomRecords
+
 	// FailReason stores the reason why a particular payment was canceled.
 	//
 	// NOTE: Populate only in fail payment descriptor entry types.
@@ -434,6 +447,8 @@ func PayDescsFromRemoteLogUpdates(chanID lnwire.ShortChannelID, height uint64,
 			}
 			pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 			copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
+			pd.BlindingPoint = wireMsg.BlindingPoint
+			pd.CustomRecords = wireMsg.CustomRecords.Copy()
 
 		case *lnwire.UpdateFulfillHTLC:
 			pd = PaymentDescriptor{
@@ -722,7 +737,9 @@ func (c *commitment) populateHtlcIndexes(chanType channeldb.ChannelType,
 
 // toDiskCommit converts the target commitment into a format suitable to be
 // written to disk after an accepted state transition.
-func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
+func (c *commitment) toDiskCommit(
+	ourCommit bool) (*channeldb.ChannelCommitment, error) {
+
 	numHtlcs := len(c.outgoingHTLCs) + len(c.incomingHTLCs)
 
 	commit := &channeldb.ChannelCommitment{
@@ -757,6 +774,12 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 		}
 		copy(h.OnionBlob[:], htlc.OnionBlob)
 
+		extraData, err := htlcExtraData(&htlc)
+		if err != nil {
+			return nil, err
+		}
+		h.ExtraData = extraData
+
 		if ourCommit && htlc.sig != nil {
 			h.Signature = htlc.sig.Serialize()
 		}
@@ -781,6 +804,12 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 		}
 		copy(h.OnionBlob[:], htlc.OnionBlob)
 
+		extraData, err := htlcExtraData(&htlc)
+		if err != nil {
+			return nil, err
+		}
+		h.ExtraData = extraData
+
 		if ourCommit && htlc.sig != nil {
 			h.Signature = htlc.sig.Serialize()
 		}
@@ -788,7 +817,32 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 		commit.Htlcs = append(commit.Htlcs, h)
 	}
 
-	return commit
+	return commit, nil
+}
+
+// htlcExtraData returns the TLV stream that is persisted along with an add
+// HTLC, which carries its blinding point if it is part of a blinded route and
+// any custom records that were attached to it.
+func htlcExtraData(htlc *PaymentDescriptor) ([]byte, error) {
+	if htlc.BlindingPoint == nil && len(htlc.CustomRecords) == 0 {
+		return nil, nil
+	}
+
+	recordProducers := htlc.CustomRecords.RecordProducers()
+	if htlc.BlindingPoint != nil {
+		recordProducers = append(
+			recordProducers,
+			(*lnwire.BlindingPoint)(htlc.BlindingPoint),
+		)
+	}
+
+	var extraData lnwire.ExtraOpaqueData
+	err := extraData.PackRecords(recordProducers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return extraData, nil
 }
 
 // diskHtlcToPayDesc converts an HTLC previously written to disk within a
@@ -863,6 +917,16 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 	// With the scripts reconstructed (depending on if this is our commit
 	// vs theirs or a pending commit for the remote party), we can now
 	// re-create the original payment descriptor.
+	blindingPoint, err := htlc.BlindingPoint()
+	if err != nil {
+		return pd, err
+	}
+
+	customRecords, err := htlc.CustomRecords()
+	if err != nil {
+		return pd, err
+	}
+
 	pd = PaymentDescriptor{
 		RHash:              htlc.RHash,
 		Timeout:            htlc.RefundTimeout,
@@ -877,6 +941,8 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 		ourWitnessScript:   ourWitnessScript,
 		theirPkScript:      theirP2WSH,
 		theirWitnessScript: theirWitnessScript,
+		BlindingPoint:      blindingPoint,
+		CustomRecords:      customRecords,
 	}
 
 	return pd, nil
@@ -1586,6 +1652,8 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
+		pd.BlindingPoint = wireMsg.BlindingPoint
+		pd.CustomRecords = wireMsg.CustomRecords.Copy()
 
 		isDustRemote := HtlcIsDust(
 			lc.channelState.ChanType, false, false, feeRate,
@@ -1783,6 +1851,8 @@ func (lc *LightningChannel) remoteLogUpdateToPayDesc(logUpdate *channeldb.LogUpd
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob, wireMsg.OnionBlob[:])
+		pd.BlindingPoint = wireMsg.BlindingPoint
+		pd.CustomRecords = wireMsg.CustomRecords.Copy()
 
 		// We don't need to generate an htlc script yet. This will be
 		// done once we sign our remote commitment.
@@ -2462,10 +2532,14 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		revocationPreimage[:],
 	)
 
+	// The channel type may have been upgraded since the breached state
+	// was created, so we'll use the type that was in use at that state.
+	chanType := chanState.ChanTypeAtHeight(false, stateNum)
+
 	// With the commitment point generated, we can now generate the four
 	// keys we'll need to reconstruct the commitment state,
 	keyRing := DeriveCommitmentKeys(
-		commitmentPoint, false, chanState.ChanType,
+		commitmentPoint, false, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -2473,7 +2547,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// number so we can have the proper witness script to sign and include
 	// within the final witness.
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 
@@ -2481,7 +2555,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// going to us will be a to-remote script with our local params.
 	isRemoteInitiator := !chanState.IsInitiator
 	ourScript, ourDelay, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry,
 	)
 	if err != nil {
@@ -2490,7 +2564,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 
 	theirDelay := uint32(chanState.RemoteChanCfg.CsvDelay)
 	theirScript, err := CommitScriptToSelf(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToLocalKey,
+		chanType, isRemoteInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, theirDelay, leaseExpiry,
 	)
 	if err != nil {
@@ -2508,7 +2582,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// we need.
 	if revokedLog != nil {
 		br, ourAmt, theirAmt, err = createBreachRetribution(
-			revokedLog, spendTx, chanState, keyRing,
+			revokedLog, spendTx, chanState, chanType, keyRing,
 			commitmentSecret, leaseExpiry,
 		)
 		if err != nil {
@@ -2523,8 +2597,8 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		// data can still function. This branch can be deleted once we
 		// are confident that no legacy format is in use.
 		br, ourAmt, theirAmt, err = createBreachRetributionLegacy(
-			revokedLogLegacy, chanState, keyRing, commitmentSecret,
-			ourScript, theirScript, leaseExpiry,
+			revokedLogLegacy, chanState, chanType, keyRing,
+			commitmentSecret, ourScript, theirScript, leaseExpiry,
 		)
 		if err != nil {
 			return nil, err
@@ -2555,7 +2629,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 				PkScript: ourScript.PkScript(),
 				Value:    ourAmt,
 			},
-			HashType: sweepSigHash(chanState.ChanType),
+			HashType: sweepSigHash(chanType),
 		}
 
 		// For taproot channels, we'll make sure to set the script path
@@ -2602,7 +2676,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 				PkScript: theirScript.PkScript(),
 				Value:    theirAmt,
 			},
-			HashType: sweepSigHash(chanState.ChanType),
+			HashType: sweepSigHash(chanType),
 		}
 
 		// For taproot channels, the remote output (the revoked output)
@@ -2641,7 +2715,8 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 // createHtlcRetribution is a helper function to construct an HtlcRetribution
 // based on the passed params.
 func createHtlcRetribution(chanState *channeldb.OpenChannel,
-	keyRing *CommitmentKeyRing, commitHash chainhash.Hash,
+	chanType channeldb.ChannelType, keyRing *CommitmentKeyRing,
+	commitHash chainhash.Hash,
 	commitmentSecret *btcec.PrivateKey, leaseExpiry uint32,
 	htlc *channeldb.HTLCEntry) (HtlcRetribution, error) {
 
@@ -2654,7 +2729,7 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 	// we'll need it if we're revoking an HTLC output on the remote
 	// commitment transaction, and *they* go to the second level.
 	secondLevelScript, err := SecondLevelHtlcScript(
-		chanState.ChanType, isRemoteInitiator,
+		chanType, isRemoteInitiator,
 		keyRing.RevocationKey, keyRing.ToLocalKey, theirDelay,
 		leaseExpiry,
 	)
@@ -2668,7 +2743,7 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 	// then from the PoV of the remote commitment state, they're the
 	// receiver of this HTLC.
 	scriptInfo, err := genHtlcScript(
-		chanState.ChanType, htlc.Incoming, false,
+		chanType, htlc.Incoming, false,
 		htlc.RefundTimeout, htlc.RHash, keyRing,
 	)
 	if err != nil {
@@ -2684,7 +2759,7 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 			PkScript: scriptInfo.PkScript(),
 			Value:    int64(htlc.Amt),
 		},
-		HashType: sweepSigHash(chanState.ChanType),
+		HashType: sweepSigHash(chanType),
 	}
 
 	// For taproot HTLC outputs, we need to set the sign method to key
@@ -2732,7 +2807,8 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 // ErrRevLogDataMissing is returned.
 func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 	spendTx *wire.MsgTx, chanState *channeldb.OpenChannel,
-	keyRing *CommitmentKeyRing, commitmentSecret *btcec.PrivateKey,
+	chanType channeldb.ChannelType, keyRing *CommitmentKeyRing,
+	commitmentSecret *btcec.PrivateKey,
 	leaseExpiry uint32) (*BreachRetribution, int64, int64, error) {
 
 	commitHash := revokedLog.CommitTxHash
@@ -2741,7 +2817,7 @@ func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 	htlcRetributions := make([]HtlcRetribution, len(revokedLog.HTLCEntries))
 	for i, htlc := range revokedLog.HTLCEntries {
 		hr, err := createHtlcRetribution(
-			chanState, keyRing, commitHash,
+			chanState, chanType, keyRing, commitHash,
 			commitmentSecret, leaseExpiry, htlc,
 		)
 		if err != nil {
@@ -2839,7 +2915,8 @@ func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 // BreachRetribution using a ChannelCommitment. Returns the constructed
 // retribution, our amount, their amount, and a possible non-nil error.
 func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
-	chanState *channeldb.OpenChannel, keyRing *CommitmentKeyRing,
+	chanState *channeldb.OpenChannel, chanType channeldb.ChannelType,
+	keyRing *CommitmentKeyRing,
 	commitmentSecret *btcec.PrivateKey,
 	ourScript, theirScript input.ScriptDescriptor,
 	leaseExpiry uint32) (*BreachRetribution, int64, int64, error) {
@@ -2871,7 +2948,7 @@ func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
 		// If the HTLC is dust, then we'll skip it as it doesn't have
 		// an output on the commitment transaction.
 		if HtlcIsDust(
-			chanState.ChanType, htlc.Incoming, false,
+			chanType, htlc.Incoming, false,
 			chainfee.SatPerKWeight(revokedLog.FeePerKw),
 			htlc.Amt.ToSatoshis(),
 			chanState.RemoteChanCfg.DustLimit,
@@ -2888,7 +2965,7 @@ func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
 			Amt:           htlc.Amt.ToSatoshis(),
 		}
 		hr, err := createHtlcRetribution(
-			chanState, keyRing, commitHash,
+			chanState, chanType, keyRing, commitHash,
 			commitmentSecret, leaseExpiry, entry,
 		)
 		if err != nil {
@@ -3635,11 +3712,13 @@ func (lc *LightningChannel) createCommitDiff(
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
+				CustomRecords: pd.CustomRecords.Copy(),
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -3709,7 +3788,10 @@ func (lc *LightningChannel) createCommitDiff(
 	// With the set of log updates mapped into wire messages, we'll now
 	// convert the in-memory commit into a format suitable for writing to
 	// disk.
-	diskCommit := newCommit.toDiskCommit(false)
+	diskCommit, err := newCommit.toDiskCommit(false)
+	if err != nil {
+		return nil, err
+	}
 
 	return &channeldb.CommitDiff{
 		Commitment: *diskCommit,
@@ -3772,11 +3854,13 @@ func (lc *LightningChannel) getUnsignedAckedUpdates() []channeldb.LogUpdate {
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
+				CustomRecords: pd.CustomRecords.Copy(),
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -4599,6 +4683,18 @@ func (lc *LightningChannel) computeView(view *htlcView, remoteChain bool,
 		theirBalance += lnwire.NewMSatFromSatoshis(
 			commitChain.tip().fee)
 	}
+
+	// The balances of the tip already have the anchor outputs subtracted.
+	// If the channel was upgraded to anchors after the tip was created,
+	// the initiator pays for them starting with the next commitment.
+	anchorAmt := lnwire.NewMSatFromSatoshis(
+		lc.upgradedAnchorAmt(remoteChain),
+	)
+	if lc.channelState.IsInitiator {
+		ourBalance -= anchorAmt
+	} else {
+		theirBalance -= anchorAmt
+	}
 	nextHeight := commitChain.tip().height + 1
 
 	// Initiate feePerKw to the last committed fee for this chain as we'll
@@ -5222,6 +5318,12 @@ func (lc *LightningChannel) IsChannelClean() bool {
 	lc.RLock()
 	defer lc.RUnlock()
 
+	return lc.isChannelClean()
+}
+
+// isChannelClean is the internal version of IsChannelClean. This function
+// expects to be executed with a lock held.
+func (lc *LightningChannel) isChannelClean() bool {
 	// Check whether we have a pending commitment for our local state.
 	if lc.localCommitChain.hasUnackedCommitment() {
 		return false
@@ -5254,6 +5356,18 @@ func (lc *LightningChannel) IsChannelClean() bool {
 	return true
 }
 
+// CommitmentsSynced returns true if neither party has a pending commitment and
+// both commitments sign the same set of updates. In contrast to
+// IsChannelClean, the channel may still carry HTLCs.
+func (lc *LightningChannel) CommitmentsSynced() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return !lc.localCommitChain.hasUnackedCommitment() &&
+		!lc.remoteCommitChain.hasUnackedCommitment() &&
+		!lc.oweCommitment(true) && !lc.oweCommitment(false)
+}
+
 // OweCommitment returns a boolean value reflecting whether we need to send
 // out a commitment signature because there are outstanding local updates and/or
 // updates in the local commit tx that aren't reflected in the remote commit tx
@@ -5307,13 +5421,19 @@ func (lc *LightningChannel) oweCommitment(local bool) bool {
 			lastLocalCommit.ourMessageIndex
 	}
 
+	// An upgrade of the channel type also requires a new commitment,
+	// even if there are no updates to commit to.
+	upgradePending := lc.chanTypeUpgradePending(local)
+
 	// If any of the conditions above is true, we owe a commitment
 	// signature.
-	oweCommitment := localUpdatesPending || remoteUpdatesPending
+	oweCommitment := localUpdatesPending || remoteUpdatesPending ||
+		upgradePending
 
 	lc.log.Tracef("%v owes commit: %v (local updates: %v, "+
-		"remote updates %v)", perspective, oweCommitment,
-		localUpdatesPending, remoteUpdatesPending)
+		"remote updates %v, chan type upgrade: %v)", perspective,
+		oweCommitment, localUpdatesPending, remoteUpdatesPending,
+		upgradePending)
 
 	return oweCommitment
 }
@@ -5329,6 +5449,18 @@ func (lc *LightningChannel) PendingLocalUpdateCount() uint64 {
 	return lc.localUpdateLog.logIndex - lastRemoteCommit.ourMessageIndex
 }
 
+// LocalUpdatesCommitted returns true if all updates we've sent to the remote
+// peer are included in the current commitments of both parties.
+func (lc *LightningChannel) LocalUpdatesCommitted() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	logIndex := lc.localUpdateLog.logIndex
+
+	return lc.localCommitChain.tail().ourMessageIndex == logIndex &&
+		lc.remoteCommitChain.tail().ourMessageIndex == logIndex
+}
+
 // RevokeCurrentCommitment revokes the next lowest unrevoked commitment
 // transaction in the local commitment chain. As a result the edge of our
 // revocation window is extended by one, and the tail of our local commitment
@@ -5359,7 +5491,10 @@ func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck,
 	// Additionally, generate a channel delta for this state transition for
 	// persistent storage.
 	chainTail := lc.localCommitChain.tail()
-	newCommitment := chainTail.toDiskCommit(true)
+	newCommitment, err := chainTail.toDiskCommit(true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	// Get the unsigned acked remotes updates that are currently in memory.
 	// We need them after a restart to sync our remote commitment with what
@@ -5545,11 +5680,13 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) (
 		switch pd.EntryType {
 		case Add:
 			htlc := &lnwire.UpdateAddHTLC{
-				ChanID:      chanID,
-				ID:          pd.HtlcIndex,
-				Amount:      pd.Amount,
-				Expiry:      pd.Timeout,
-				PaymentHash: pd.RHash,
+				ChanID:        chanID,
+				ID:            pd.HtlcIndex,
+				Amount:        pd.Amount,
+				Expiry:        pd.Timeout,
+				PaymentHash:   pd.RHash,
+				BlindingPoint: pd.BlindingPoint,
+				CustomRecords: pd.CustomRecords.Copy(),
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -5874,6 +6011,8 @@ func (lc *LightningChannel) htlcAddDescriptor(htlc *lnwire.UpdateAddHTLC,
 		HtlcIndex:      lc.localUpdateLog.htlcCounter,
 		OnionBlob:      htlc.OnionBlob[:],
 		OpenCircuitKey: openKey,
+		BlindingPoint:  htlc.BlindingPoint,
+		CustomRecords:  htlc.CustomRecords.Copy(),
 	}
 }
 
@@ -5922,13 +6061,15 @@ func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, err
 	}
 
 	pd := &PaymentDescriptor{
-		EntryType: Add,
-		RHash:     PaymentHash(htlc.PaymentHash),
-		Timeout:   htlc.Expiry,
-		Amount:    htlc.Amount,
-		LogIndex:  lc.remoteUpdateLog.logIndex,
-		HtlcIndex: lc.remoteUpdateLog.htlcCounter,
-		OnionBlob: htlc.OnionBlob[:],
+		EntryType:     Add,
+		RHash:         PaymentHash(htlc.PaymentHash),
+		Timeout:       htlc.Expiry,
+		Amount:        htlc.Amount,
+		LogIndex:      lc.remoteUpdateLog.logIndex,
+		HtlcIndex:     lc.remoteUpdateLog.htlcCounter,
+		OnionBlob:     htlc.OnionBlob[:],
+		BlindingPoint: htlc.BlindingPoint,
+		CustomRecords: htlc.CustomRecords.Copy(),
 	}
 
 	localACKedIndex := lc.remoteCommitChain.tail().ourMessageIndex
@@ -6127,14 +6268,27 @@ func (lc *LightningChannel) FailHTLC(htlcIndex uint64, reason []byte,
 // commitment update. This method is intended to be called in order to cancel
 // in _incoming_ HTLC.
 //
-// The additional sourceRef specifies the location of the Add HTLC within a
-// forwarding package that this HTLC is failing. This value should never be
-// empty.
+// The additional arguments correspond to:
+//
+//   - sourceRef: specifies the location of the Add HTLC within a forwarding
+//     package that this HTLC is failing. This value should never be empty.
+//
+//   - destRef: specifies the location of the Fail HTLC within another
+//     channel's forwarding package. This value can be nil if the HTLC was
+//     failed locally rather than in response to a failure on the outgoing
+//     link, e.g. when the onion could not be decoded.
 //
-// NOTE: It is okay for sourceRef to be nil when unit testing the wallet.
+//   - closeKey: identifies the circuit that should be deleted after this Fail
+//     HTLC is included in a commitment txn. This value should only be nil if
+//     the HTLC was failed locally before committing a circuit to the circuit
+//     map.
+//
+// NOTE: It is okay for sourceRef, destRef, and closeKey to be nil when unit
+// testing the wallet.
 func (lc *LightningChannel) MalformedFailHTLC(htlcIndex uint64,
 	failCode lnwire.FailCode, shaOnionBlob [sha256.Size]byte,
-	sourceRef *channeldb.AddRef) error {
+	sourceRef *channeldb.AddRef, destRef *channeldb.SettleFailRef,
+	closeKey *models.CircuitKey) error {
 
 	lc.Lock()
 	defer lc.Unlock()
@@ -6151,14 +6305,16 @@ func (lc *LightningChannel) MalformedFailHTLC(htlcIndex uint64,
 	}
 
 	pd := &PaymentDescriptor{
-		Amount:       htlc.Amount,
-		RHash:        htlc.RHash,
-		ParentIndex:  htlcIndex,
-		LogIndex:     lc.localUpdateLog.logIndex,
-		EntryType:    MalformedFail,
-		FailCode:     failCode,
-		ShaOnionBlob: shaOnionBlob,
-		SourceRef:    sourceRef,
+		Amount:           htlc.Amount,
+		RHash:            htlc.RHash,
+		ParentIndex:      htlcIndex,
+		LogIndex:         lc.localUpdateLog.logIndex,
+		EntryType:        MalformedFail,
+		FailCode:         failCode,
+		ShaOnionBlob:     shaOnionBlob,
+		SourceRef:        sourceRef,
+		DestRef:          destRef,
+		ClosedCircuitKey: closeKey,
 	}
 
 	lc.localUpdateLog.appendUpdate(pd)
@@ -6171,6 +6327,23 @@ func (lc *LightningChannel) MalformedFailHTLC(htlcIndex uint64,
 	return nil
 }
 
+// IncomingOnionBlob returns the onion blob of the incoming HTLC with the given
+// index. It is used to fail HTLCs that are part of a blinded route, whose
+// failures commit to the onion that they were received with.
+func (lc *LightningChannel) IncomingOnionBlob(htlcIndex uint64) ([]byte,
+	error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	htlc := lc.remoteUpdateLog.lookupHtlc(htlcIndex)
+	if htlc == nil {
+		return nil, ErrUnknownHtlcIndex{lc.ShortChanID(), htlcIndex}
+	}
+
+	return htlc.OnionBlob, nil
+}
+
 // ReceiveFailHTLC attempts to cancel a targeted HTLC by its log index,
 // inserting an entry which will remove the target log entry within the next
 // commitment update. This method should be called in response to the upstream
@@ -6432,18 +6605,28 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	remoteCommit channeldb.ChannelCommitment,
 	commitPoint *btcec.PublicKey) (*UnilateralCloseSummary, error) {
 
+	// The channel type may have been upgraded since the broadcast
+	// commitment was created, so we'll use the type of that commitment if
+	// we know it.
+	chanType := chanState.ChanType
+	if remoteCommit.CommitTx != nil {
+		chanType = chanState.ChanTypeAtHeight(
+			false, remoteCommit.CommitHeight,
+		)
+	}
+
 	// First, we'll generate the commitment point and the revocation point
 	// so we can re-construct the HTLC state and also our payment key.
 	isOurCommit := false
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, isOurCommit, chanState.ChanType,
+		commitPoint, isOurCommit, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
 	// Next, we'll obtain HTLC resolutions for all the outgoing HTLC's we
 	// had on their commitment transaction.
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 	isRemoteInitiator := !chanState.IsInitiator
@@ -6451,7 +6634,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 		chainfee.SatPerKWeight(remoteCommit.FeePerKw), isOurCommit,
 		signer, remoteCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
 		&chanState.RemoteChanCfg, commitSpend.SpendingTx,
-		chanState.ChanType, isRemoteInitiator, leaseExpiry,
+		chanType, isRemoteInitiator, leaseExpiry,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create htlc "+
@@ -6464,7 +6647,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	// locate the output index of our non-delayed output on the commitment
 	// transaction.
 	selfScript, maturityDelay, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry,
 	)
 	if err != nil {
@@ -6514,7 +6697,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 					Value:    localBalance,
 					PkScript: selfScript.PkScript(),
 				},
-				HashType: sweepSigHash(chanState.ChanType),
+				HashType: sweepSigHash(chanType),
 			},
 			MaturityDelay: maturityDelay,
 		}
@@ -6567,7 +6750,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	}
 
 	anchorResolution, err := NewAnchorResolution(
-		chanState, commitTxBroadcast, keyRing, false,
+		chanState, chanType, commitTxBroadcast, keyRing, false,
 	)
 	if err != nil {
 		return nil, err
@@ -7401,17 +7584,21 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 		return nil, err
 	}
 	commitPoint := input.ComputeCommitmentPoint(revocation[:])
+
+	// The channel type may have been upgraded since this state was
+	// created, so we'll use the type that was in use at that state.
+	chanType := chanState.ChanTypeAtHeight(true, stateNum)
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, true, chanState.ChanType,
+		commitPoint, true, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 	toLocalScript, err := CommitScriptToSelf(
-		chanState.ChanType, chanState.IsInitiator, keyRing.ToLocalKey,
+		chanType, chanState.IsInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, csvTimeout, leaseExpiry,
 	)
 	if err != nil {
@@ -7467,7 +7654,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 					PkScript: delayOut.PkScript,
 					Value:    localBalance,
 				},
-				HashType: sweepSigHash(chanState.ChanType),
+				HashType: sweepSigHash(chanType),
 			},
 			MaturityDelay: csvTimeout,
 		}
@@ -7502,7 +7689,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	htlcResolutions, err := extractHtlcResolutions(
 		chainfee.SatPerKWeight(localCommit.FeePerKw), true, signer,
 		localCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
-		&chanState.RemoteChanCfg, commitTx, chanState.ChanType,
+		&chanState.RemoteChanCfg, commitTx, chanType,
 		chanState.IsInitiator, leaseExpiry,
 	)
 	if err != nil {
@@ -7510,7 +7697,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	}
 
 	anchorResolution, err := NewAnchorResolution(
-		chanState, commitTx, keyRing, true,
+		chanState, chanType, commitTx, keyRing, true,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to gen anchor "+
@@ -7803,13 +7990,16 @@ func (lc *LightningChannel) NewAnchorResolutions() (*AnchorResolutions,
 		return nil, err
 	}
 	localCommitPoint := input.ComputeCommitmentPoint(revocation[:])
+	localChanType := lc.channelState.ChanTypeAtHeight(
+		true, lc.currentHeight,
+	)
 	localKeyRing := DeriveCommitmentKeys(
-		localCommitPoint, true, lc.channelState.ChanType,
+		localCommitPoint, true, localChanType,
 		&lc.channelState.LocalChanCfg, &lc.channelState.RemoteChanCfg,
 	)
 	localRes, err := NewAnchorResolution(
-		lc.channelState, lc.channelState.LocalCommitment.CommitTx,
-		localKeyRing, true,
+		lc.channelState, localChanType,
+		lc.channelState.LocalCommitment.CommitTx, localKeyRing, true,
 	)
 	if err != nil {
 		return nil, err
@@ -7817,14 +8007,17 @@ func (lc *LightningChannel) NewAnchorResolutions() (*AnchorResolutions,
 	resolutions.Local = localRes
 
 	// Add anchor for remote commitment tx, if any.
+	remoteChanType := lc.channelState.ChanTypeAtHeight(
+		false, lc.channelState.RemoteCommitment.CommitHeight,
+	)
 	remoteKeyRing := DeriveCommitmentKeys(
 		lc.channelState.RemoteCurrentRevocation, false,
-		lc.channelState.ChanType, &lc.channelState.LocalChanCfg,
+		remoteChanType, &lc.channelState.LocalChanCfg,
 		&lc.channelState.RemoteChanCfg,
 	)
 	remoteRes, err := NewAnchorResolution(
-		lc.channelState, lc.channelState.RemoteCommitment.CommitTx,
-		remoteKeyRing, false,
+		lc.channelState, remoteChanType,
+		lc.channelState.RemoteCommitment.CommitTx, remoteKeyRing, false,
 	)
 	if err != nil {
 		return nil, err
@@ -7838,13 +8031,16 @@ func (lc *LightningChannel) NewAnchorResolutions() (*AnchorResolutions,
 	}
 
 	if remotePendingCommit != nil {
+		pendingChanType := lc.channelState.ChanTypeAtHeight(
+			false, remotePendingCommit.Commitment.CommitHeight,
+		)
 		pendingRemoteKeyRing := DeriveCommitmentKeys(
 			lc.channelState.RemoteNextRevocation, false,
-			lc.channelState.ChanType, &lc.channelState.LocalChanCfg,
+			pendingChanType, &lc.channelState.LocalChanCfg,
 			&lc.channelState.RemoteChanCfg,
 		)
 		remotePendingRes, err := NewAnchorResolution(
-			lc.channelState,
+			lc.channelState, pendingChanType,
 			remotePendingCommit.Commitment.CommitTx,
 			pendingRemoteKeyRing, false,
 		)
@@ -7860,11 +8056,12 @@ func (lc *LightningChannel) NewAnchorResolutions() (*AnchorResolutions,
 // NewAnchorResolution returns the information that is required to sweep the
 // local anchor.
 func NewAnchorResolution(chanState *channeldb.OpenChannel,
-	commitTx *wire.MsgTx, keyRing *CommitmentKeyRing,
+	chanType channeldb.ChannelType, commitTx *wire.MsgTx,
+	keyRing *CommitmentKeyRing,
 	isLocalCommit bool) (*AnchorResolution, error) {
 
 	// Return nil resolution if the channel has no anchors.
-	if !chanState.ChanType.HasAnchors() {
+	if !chanType.HasAnchors() {
 		return nil, nil
 	}
 
@@ -7873,13 +8070,13 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 	// will differ depending on if this is our local or remote
 	// commitment.
 	localAnchor, remoteAnchor, err := CommitScriptAnchors(
-		chanState.ChanType, &chanState.LocalChanCfg,
+		chanType, &chanState.LocalChanCfg,
 		&chanState.RemoteChanCfg, keyRing,
 	)
 	if err != nil {
 		return nil, err
 	}
-	if chanState.ChanType.IsTaproot() && !isLocalCommit {
+	if chanType.IsTaproot() && !isLocalCommit {
 		//nolint:ineffassign
 		localAnchor, remoteAnchor = remoteAnchor, localAnchor
 	}
@@ -7917,7 +8114,7 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 			PkScript: localAnchor.PkScript(),
 			Value:    int64(anchorSize),
 		},
-		HashType: sweepSigHash(chanState.ChanType),
+		HashType: sweepSigHash(chanType),
 	}
 
 	// For taproot outputs, we'll need to ensure that the proper sign
@@ -7957,7 +8154,7 @@ func NewAnchorResolution(chanState *channeldb.OpenChannel,
 	}
 
 	var witnessWeight int64
-	if chanState.ChanType.IsTaproot() {
+	if chanType.IsTaproot() {
 		witnessWeight = input.TaprootKeyPathWitnessSize
 	} else {
 		witnessWeight = input.WitnessCommitmentTxWeight
@@ -9714,8 +9714,8 @@ func TestCreateHtlcRetribution(t *testing.T) {
 
 	// Create the htlc retribution.
 	hr, err := createHtlcRetribution(
-		aliceChannel.channelState, keyRing, commitHash,
-		dummyPrivate, leaseExpiry, htlc,
+		aliceChannel.channelState, aliceChannel.channelState.ChanType,
+		keyRing, commitHash, dummyPrivate, leaseExpiry, htlc,
 	)
 	// Expect no error.
 	require.NoError(t, err)
@@ -9911,7 +9911,8 @@ func TestCreateBreachRetribution(t *testing.T) {
 
 			br, our, their, err := createBreachRetribution(
 				tc.revocationLog, tx,
-				aliceChannel.channelState, keyRing,
+				aliceChannel.channelState,
+				aliceChannel.channelState.ChanType, keyRing,
 				dummyPrivate, leaseExpiry,
 			)
 
@@ -9969,8 +9970,9 @@ func TestCreateBreachRetributionLegacy(t *testing.T) {
 
 	// Create the breach retribution using the legacy format.
 	br, ourAmt, theirAmt, err := createBreachRetributionLegacy(
-		&revokedLog, aliceChannel.channelState, keyRing,
-		dummyPrivate, ourScript, theirScript, leaseExpiry,
+		&revokedLog, aliceChannel.channelState,
+		aliceChannel.channelState.ChanType, keyRing, dummyPrivate,
+		ourScript, theirScript, leaseExpiry,
 	)
 	require.NoError(t, err)
 
@@ -10176,6 +10178,14 @@ func assertPayDescMatchHTLC(t *testing.T, pd PaymentDescriptor,
 	require.Equal(htlc.HtlcIndex, pd.HtlcIndex, "HtlcIndex")
 	require.Equal(htlc.LogIndex, pd.LogIndex, "LogIndex")
 	require.EqualValues(htlc.OnionBlob[:], pd.OnionBlob, "OnionBlob")
+
+	blindingPoint, err := htlc.BlindingPoint()
+	require.NoError(err)
+	require.Equal(blindingPoint, pd.BlindingPoint, "BlindingPoint")
+
+	customRecords, err := htlc.CustomRecords()
+	require.NoError(err)
+	require.Equal(customRecords, pd.CustomRecords, "CustomRecords")
 }
 
 // createRandomHTLC creates an HTLC that has random value in every field except
@@ -10193,8 +10203,18 @@ func createRandomHTLC(t *testing.T, incoming bool) channeldb.HTLC {
 	_, err = rand.Read(sig)
 	require.NoError(t, err)
 
-	extra := make([]byte, 1000)
-	_, err = rand.Read(extra)
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	customRecords := lnwire.CustomRecords{
+		lnwire.MinCustomRecordsTlvType: sig[:8],
+	}
+
+	var extra lnwire.ExtraOpaqueData
+	err = extra.PackRecords(append(
+		customRecords.RecordProducers(),
+		(*lnwire.BlindingPoint)(privKey.PubKey()),
+	)...)
 	require.NoError(t, err)
 
 	return channeldb.HTLC{
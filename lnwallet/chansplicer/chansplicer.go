@@ -0,0 +1,1113 @@
+package chansplicer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/labels"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/lnd/lnwallet/interactivetx"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+var (
+	// ErrSpliceAborted is returned when the remote peer aborted the
+	// splice.
+	ErrSpliceAborted = errors.New("splice aborted by remote peer")
+
+	// ErrInvalidSpliceRequest is returned when a splice request neither
+	// adds nor removes funds, or tries to do both at once.
+	ErrInvalidSpliceRequest = errors.New("a splice must either add or " +
+		"remove funds")
+
+	// ErrUnexpectedMsg is returned when the remote peer sends a message
+	// that isn't expected in the current state of the splice.
+	ErrUnexpectedMsg = errors.New("unexpected splice message")
+
+	// ErrRemoteContribution is returned when the remote peer tries to
+	// contribute to a splice that we initiated.
+	ErrRemoteContribution = errors.New("remote contributions to a " +
+		"splice are not supported")
+
+	// ErrFundingKeyChanged is returned when the remote peer tries to use
+	// a different funding key for the new funding output.
+	ErrFundingKeyChanged = errors.New("splice must keep the funding key")
+)
+
+const (
+	// spliceSequence is the sequence of all inputs of a splice
+	// transaction. It signals replaceability.
+	spliceSequence = wire.MaxTxInSequenceNum - 2
+
+	// sharedInputWeight is the weight of the input of a splice
+	// transaction that spends the current funding output.
+	sharedInputWeight = input.InputSize*blockchain.WitnessScaleFactor +
+		input.MultiSigWitnessSize
+)
+
+// spliceState represents all the possible states the splice state machine can
+// be in.
+type spliceState uint8
+
+const (
+	// spliceIdle is the initial state of the state machine. The acceptor
+	// of a splice waits for splice_init in this state.
+	spliceIdle spliceState = iota
+
+	// spliceAwaitingAck is the state the initiator is in after sending
+	// splice_init.
+	spliceAwaitingAck
+
+	// spliceConstructing is the state both peers are in while the splice
+	// transaction is constructed interactively.
+	spliceConstructing
+
+	// spliceAwaitingCommitSig is the state both peers are in after the
+	// construction completed, until the remote peer signed our
+	// commitment spending the new funding output.
+	spliceAwaitingCommitSig
+
+	// spliceAwaitingTxSigs is the state both peers are in after the
+	// commitments were signed, until the remote peer sent its
+	// tx_signatures. The acceptor has persisted the splice at this
+	// point, so it can no longer be aborted.
+	spliceAwaitingTxSigs
+
+	// spliceAwaitingConf is the state both peers are in once the splice
+	// transaction was broadcast, until it has confirmed.
+	spliceAwaitingConf
+
+	// spliceLocked is the state both peers are in once the splice has
+	// confirmed and they sent splice_locked.
+	spliceLocked
+
+	// spliceAborted is the terminal state of an aborted splice.
+	spliceAborted
+)
+
+// SpliceRequest is a request to splice funds into or out of a channel.
+type SpliceRequest struct {
+	// ChanPoint is the channel point of the channel to splice.
+	ChanPoint *wire.OutPoint
+
+	// SpliceIn is the amount to add to our side of the channel, funded
+	// by the wallet.
+	SpliceIn ltcutil.Amount
+
+	// SpliceOut is the amount to remove from our side of the channel,
+	// and send to SpliceOutScript.
+	SpliceOut ltcutil.Amount
+
+	// SpliceOutScript is the script that the funds spliced out of the
+	// channel are sent to.
+	SpliceOutScript []byte
+
+	// FeeRate is the fee rate of the splice transaction.
+	FeeRate chainfee.SatPerKWeight
+
+	// Txid is sent upon with the txid of the splice transaction once it
+	// was broadcast.
+	Txid chan chainhash.Hash
+
+	// Err is sent upon if the splice fails.
+	Err chan error
+}
+
+// ChanSpliceCfg holds all the items that a ChanSplicer requires to carry out
+// its duties.
+type ChanSpliceCfg struct {
+	// Channel is the channel that should be spliced.
+	Channel Channel
+
+	// FundSplice selects coins of the wallet that cover the passed amount
+	// at the given fee rate. The returned intent holds the selected coins
+	// and the change outputs, and must be canceled if the coins aren't
+	// spent.
+	FundSplice func(ltcutil.Amount,
+		chainfee.SatPerKWeight) (*chanfunding.FullIntent, error)
+
+	// FetchTx returns the wallet transaction with the given txid.
+	FetchTx func(chainhash.Hash) (*wire.MsgTx, error)
+
+	// Signer is used to sign the wallet inputs of the splice
+	// transaction.
+	Signer input.Signer
+
+	// BroadcastTx broadcasts the passed transaction to the network.
+	BroadcastTx func(*wire.MsgTx, string) error
+
+	// Height is the current best height of the chain.
+	Height uint32
+
+	// DustLimit is the dust limit for the outputs of the splice
+	// transaction.
+	DustLimit ltcutil.Amount
+}
+
+// ChanSplicer is a state machine that handles the splice of a channel with
+// the remote peer. The transaction that replaces the funding output of the
+// channel is constructed interactively, then both peers sign their new
+// commitments before signing the splice transaction itself. Once the splice
+// has confirmed, both peers exchange splice_locked before the channel is used
+// again.
+//
+// NOTE: Only the initiator of a splice contributes to it. The channel must be
+// quiesced by both peers for the entire duration of the splice, as new states
+// aren't signed for both the old and the new funding output while the splice
+// is pending. The channel can therefore only be used again once the splice is
+// locked.
+type ChanSplicer struct {
+	// state is the current state of the state machine.
+	state spliceState
+
+	// cfg holds the configuration for this ChanSplicer instance.
+	cfg ChanSpliceCfg
+
+	// cid is the channel ID of the channel before the splice.
+	cid lnwire.ChannelID
+
+	// prevOutpoint is the funding outpoint spent by the splice.
+	prevOutpoint wire.OutPoint
+
+	// req is the request of the local user that initiated the splice. It
+	// is nil if the remote peer initiated the splice.
+	req *SpliceRequest
+
+	// params describes the new funding output of the channel.
+	params *lnwallet.SpliceParams
+
+	// locktime is the locktime of the splice transaction.
+	locktime uint32
+
+	// intent holds the wallet coins that fund a splice-in.
+	intent *chanfunding.FullIntent
+
+	// session is the interactive construction of the splice transaction.
+	session *interactivetx.Session
+
+	// pendingMsgs are the construction messages that the initiator still
+	// has to send, one at a time.
+	pendingMsgs []lnwire.Message
+
+	// spliceTx is the splice transaction once its construction has
+	// completed.
+	spliceTx *wire.MsgTx
+
+	// fundingIndex is the index of the new funding output within the
+	// splice transaction.
+	fundingIndex uint32
+
+	// sharedIndex is the index of the input of the splice transaction
+	// that spends the current funding output.
+	sharedIndex int
+
+	// localCommit and remoteCommit are the signed commitments spending
+	// the new funding output.
+	localCommit  *channeldb.ChannelCommitment
+	remoteCommit *channeldb.ChannelCommitment
+
+	// localSharedSig is our signature for the shared input, which the
+	// acceptor persists before sending it.
+	localSharedSig []byte
+
+	// spliced is the channel state after the splice has confirmed.
+	spliced *channeldb.OpenChannel
+
+	// localLocked and remoteLocked are set once we or the remote peer
+	// sent splice_locked.
+	localLocked  bool
+	remoteLocked bool
+}
+
+// NewChanSplicer creates a new splice state machine for the given channel.
+// If req is nil, the remote peer is expected to initiate the splice.
+func NewChanSplicer(cfg ChanSpliceCfg, req *SpliceRequest) *ChanSplicer {
+	prevOutpoint := *cfg.Channel.ChannelPoint()
+
+	return &ChanSplicer{
+		state:        spliceIdle,
+		cfg:          cfg,
+		cid:          lnwire.NewChanIDFromOutPoint(&prevOutpoint),
+		prevOutpoint: prevOutpoint,
+		req:          req,
+	}
+}
+
+// ResumeChanSplicer recreates the state machine of a splice that was persisted
+// before a restart, and returns the messages that must be retransmitted to
+// the remote peer. A splice that is fully signed is rebroadcast.
+func ResumeChanSplicer(cfg ChanSpliceCfg,
+	splice *channeldb.ChannelSplice) (*ChanSplicer, []lnwire.Message,
+	error) {
+
+	s := &ChanSplicer{
+		cfg:            cfg,
+		cid:            lnwire.NewChanIDFromOutPoint(&splice.PrevOutpoint),
+		prevOutpoint:   splice.PrevOutpoint,
+		spliceTx:       splice.SpliceTx,
+		fundingIndex:   splice.FundingOutputIndex,
+		localSharedSig: splice.LocalSharedSig,
+		localCommit:    &splice.LocalCommitment,
+		remoteCommit:   &splice.RemoteCommitment,
+		localLocked:    splice.LocalLocked,
+		remoteLocked:   splice.RemoteLocked,
+		params: &lnwallet.SpliceParams{
+			FundingOutpoint:    splice.FundingOutpoint(),
+			Capacity:           splice.Capacity,
+			LocalContribution:  splice.LocalContribution,
+			RemoteContribution: splice.RemoteContribution,
+		},
+	}
+
+	spliceTxid := splice.SpliceTx.TxHash()
+	switch {
+	// The splice has confirmed and the channel was moved over to the new
+	// funding output, so we only wait for splice_locked of the remote
+	// peer.
+	case splice.LocalLocked:
+		s.state = spliceLocked
+		s.spliced = cfg.Channel.State()
+
+		return s, []lnwire.Message{&lnwire.SpliceLocked{
+			ChanID:     s.cid,
+			SpliceTxid: spliceTxid,
+		}}, nil
+
+	// The splice transaction is fully signed, so we rebroadcast it and
+	// wait for it to confirm.
+	case splice.Signed:
+		s.state = spliceAwaitingConf
+		err := cfg.BroadcastTx(
+			splice.SpliceTx, labels.MakeLabel(
+				labels.LabelTypeSpliceTransaction,
+				&cfg.Channel.State().ShortChannelID,
+			),
+		)
+		if err != nil {
+			splicerLog.Errorf("ChannelPoint(%v): unable to "+
+				"rebroadcast splice tx %v: %v", s.prevOutpoint,
+				spliceTxid, err)
+		}
+
+		return s, nil, nil
+	}
+
+	// Otherwise, we're the acceptor of the splice and still wait for the
+	// tx_signatures of the initiator, so we retransmit our own.
+	sharedIndex, err := findSharedInput(splice.SpliceTx, s.prevOutpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.sharedIndex = sharedIndex
+	s.state = spliceAwaitingTxSigs
+
+	txSigs, err := s.txSignatures(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, []lnwire.Message{txSigs}, nil
+}
+
+// ChanID returns the channel ID of the channel before the splice, which
+// identifies the splice towards the remote peer.
+func (s *ChanSplicer) ChanID() lnwire.ChannelID {
+	return s.cid
+}
+
+// PrevOutpoint returns the funding outpoint that is spent by the splice.
+func (s *ChanSplicer) PrevOutpoint() wire.OutPoint {
+	return s.prevOutpoint
+}
+
+// Request returns the splice request of the local user, which is nil if the
+// remote peer initiated the splice.
+func (s *ChanSplicer) Request() *SpliceRequest {
+	return s.req
+}
+
+// SpliceTxid returns the txid of the splice transaction, once its
+// construction has completed.
+func (s *ChanSplicer) SpliceTxid() (chainhash.Hash, bool) {
+	if s.spliceTx == nil {
+		return chainhash.Hash{}, false
+	}
+
+	return s.spliceTx.TxHash(), true
+}
+
+// CanAbort returns true as long as the splice hasn't been persisted, and can
+// still be aborted.
+func (s *ChanSplicer) CanAbort() bool {
+	switch s.state {
+	case spliceIdle, spliceAwaitingAck, spliceConstructing,
+		spliceAwaitingCommitSig:
+
+		return true
+
+	case spliceAwaitingTxSigs:
+		return s.isInitiator()
+
+	default:
+		return false
+	}
+}
+
+// Abort aborts the splice and releases the wallet coins that were selected to
+// fund it.
+func (s *ChanSplicer) Abort() {
+	s.state = spliceAborted
+
+	if s.intent != nil {
+		s.intent.Cancel()
+		s.intent = nil
+	}
+}
+
+// IsLocked returns true once both peers sent splice_locked, and the channel
+// can be used again.
+func (s *ChanSplicer) IsLocked() bool {
+	return s.localLocked && s.remoteLocked
+}
+
+// SplicedChannel returns the state of the channel after the splice has
+// confirmed.
+func (s *ChanSplicer) SplicedChannel() *channeldb.OpenChannel {
+	return s.spliced
+}
+
+// isInitiator returns true if we initiated the splice.
+func (s *ChanSplicer) isInitiator() bool {
+	return s.req != nil
+}
+
+// InitSplice selects the coins that fund the splice if needed, and returns
+// the splice_init message that starts the splice.
+func (s *ChanSplicer) InitSplice() (*lnwire.SpliceInit, error) {
+	if s.state != spliceIdle || s.req == nil {
+		return nil, fmt.Errorf("%w: splice already started",
+			ErrUnexpectedMsg)
+	}
+
+	if err := s.cfg.Channel.CanSplice(); err != nil {
+		return nil, err
+	}
+
+	req := s.req
+	var contribution ltcutil.Amount
+	switch {
+	case req.SpliceIn > 0 && req.SpliceOut == 0:
+		// The wallet inputs pay for the shared input of the splice
+		// transaction as well.
+		sharedFee := req.FeeRate.FeeForWeight(sharedInputWeight)
+		intent, err := s.cfg.FundSplice(
+			req.SpliceIn+sharedFee, req.FeeRate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fund splice: %w", err)
+		}
+		s.intent = intent
+		contribution = req.SpliceIn
+
+	case req.SpliceOut > 0 && req.SpliceIn == 0:
+		if !txscript.IsWitnessProgram(req.SpliceOutScript) {
+			return nil, fmt.Errorf("splice out script must be a " +
+				"segwit script")
+		}
+
+		// The fee of the splice transaction is paid from our balance
+		// in the channel.
+		var estimator input.TxWeightEstimator
+		estimator.AddWitnessInput(input.MultiSigWitnessSize)
+		estimator.AddP2WSHOutput()
+		estimator.AddTxOutput(&wire.TxOut{PkScript: req.SpliceOutScript})
+		fee := req.FeeRate.FeeForWeight(int64(estimator.Weight()))
+		contribution = -(req.SpliceOut + fee)
+
+	default:
+		return nil, ErrInvalidSpliceRequest
+	}
+
+	state := s.cfg.Channel.State()
+	s.params = &lnwallet.SpliceParams{
+		Capacity:          state.Capacity + contribution,
+		LocalContribution: contribution,
+	}
+	if err := s.cfg.Channel.ValidateSplice(s.params); err != nil {
+		s.Abort()
+		return nil, err
+	}
+
+	s.locktime = s.cfg.Height
+	s.state = spliceAwaitingAck
+
+	splicerLog.Infof("ChannelPoint(%v): initiating splice with "+
+		"contribution=%v, fee_rate=%v", s.prevOutpoint, contribution,
+		req.FeeRate)
+
+	return &lnwire.SpliceInit{
+		ChanID:              s.cid,
+		FundingContribution: contribution,
+		FeePerKiloWeight:    uint32(req.FeeRate),
+		Locktime:            s.locktime,
+		FundingPubKey:       state.LocalChanCfg.MultiSigKey.PubKey,
+	}, nil
+}
+
+// ProcessSpliceMsg processes a splice message sent by the remote peer, and
+// returns the messages that must be sent in response. If an error is
+// returned while CanAbort is true, the splice must be aborted.
+func (s *ChanSplicer) ProcessSpliceMsg(msg lnwire.Message) ([]lnwire.Message,
+	error) {
+
+	if _, ok := msg.(*lnwire.TxAbort); ok {
+		if !s.CanAbort() {
+			return nil, fmt.Errorf("%w: splice can no longer be "+
+				"aborted", ErrUnexpectedMsg)
+		}
+
+		return nil, ErrSpliceAborted
+	}
+
+	switch s.state {
+	case spliceIdle:
+		spliceInit, ok := msg.(*lnwire.SpliceInit)
+		if !ok || s.isInitiator() {
+			break
+		}
+
+		return s.handleSpliceInit(spliceInit)
+
+	case spliceAwaitingAck:
+		spliceAck, ok := msg.(*lnwire.SpliceAck)
+		if !ok {
+			break
+		}
+
+		return s.handleSpliceAck(spliceAck)
+
+	case spliceConstructing:
+		return s.handleConstructionMsg(msg)
+
+	case spliceAwaitingCommitSig:
+		commitSig, ok := msg.(*lnwire.CommitSig)
+		if !ok {
+			break
+		}
+
+		return s.handleCommitSig(commitSig)
+
+	case spliceAwaitingTxSigs:
+		txSigs, ok := msg.(*lnwire.TxSignatures)
+		if !ok {
+			break
+		}
+
+		return s.handleTxSignatures(txSigs)
+
+	case spliceAwaitingConf, spliceLocked:
+		locked, ok := msg.(*lnwire.SpliceLocked)
+		if !ok {
+			break
+		}
+
+		return nil, s.handleSpliceLocked(locked)
+	}
+
+	return nil, fmt.Errorf("%w: %v in state %v", ErrUnexpectedMsg,
+		msg.MsgType(), s.state)
+}
+
+// handleSpliceInit validates the splice proposed by the remote peer, and
+// accepts it without contributing to it.
+func (s *ChanSplicer) handleSpliceInit(
+	msg *lnwire.SpliceInit) ([]lnwire.Message, error) {
+
+	if err := s.cfg.Channel.CanSplice(); err != nil {
+		return nil, err
+	}
+
+	state := s.cfg.Channel.State()
+	remoteKey := state.RemoteChanCfg.MultiSigKey.PubKey
+	if msg.FundingPubKey == nil || !msg.FundingPubKey.IsEqual(remoteKey) {
+		return nil, ErrFundingKeyChanged
+	}
+
+	feeRate := chainfee.SatPerKWeight(msg.FeePerKiloWeight)
+	if feeRate < chainfee.FeePerKwFloor {
+		return nil, fmt.Errorf("splice fee rate %v below minimum %v",
+			feeRate, chainfee.FeePerKwFloor)
+	}
+
+	s.params = &lnwallet.SpliceParams{
+		Capacity:           state.Capacity + msg.FundingContribution,
+		RemoteContribution: msg.FundingContribution,
+	}
+	if err := s.cfg.Channel.ValidateSplice(s.params); err != nil {
+		return nil, err
+	}
+
+	s.locktime = msg.Locktime
+	s.newSession()
+	s.state = spliceConstructing
+
+	splicerLog.Infof("ChannelPoint(%v): accepting splice with remote "+
+		"contribution=%v", s.prevOutpoint, msg.FundingContribution)
+
+	return []lnwire.Message{&lnwire.SpliceAck{
+		ChanID:        s.cid,
+		FundingPubKey: state.LocalChanCfg.MultiSigKey.PubKey,
+	}}, nil
+}
+
+// handleSpliceAck starts the construction of the splice transaction once the
+// remote peer accepted the splice.
+func (s *ChanSplicer) handleSpliceAck(
+	msg *lnwire.SpliceAck) ([]lnwire.Message, error) {
+
+	if msg.FundingContribution != 0 {
+		return nil, ErrRemoteContribution
+	}
+
+	state := s.cfg.Channel.State()
+	remoteKey := state.RemoteChanCfg.MultiSigKey.PubKey
+	if msg.FundingPubKey == nil || !msg.FundingPubKey.IsEqual(remoteKey) {
+		return nil, ErrFundingKeyChanged
+	}
+
+	s.newSession()
+
+	// Queue all our contributions to the splice transaction: the
+	// current funding output, the wallet coins and their change, the new
+	// funding output and the destination of funds spliced out.
+	sharedInput, err := s.session.AddSharedInput(spliceSequence)
+	if err != nil {
+		return nil, err
+	}
+	s.pendingMsgs = append(s.pendingMsgs, sharedInput)
+
+	if s.intent != nil {
+		for _, coin := range s.intent.InputCoins {
+			prevTx, err := s.cfg.FetchTx(coin.OutPoint.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch tx %v: "+
+					"%w", coin.OutPoint.Hash, err)
+			}
+
+			addInput, err := s.session.AddInput(
+				prevTx, coin.OutPoint.Index, spliceSequence,
+			)
+			if err != nil {
+				return nil, err
+			}
+			s.pendingMsgs = append(s.pendingMsgs, addInput)
+		}
+	}
+
+	outputs := []*wire.TxOut{{
+		Value:    int64(s.params.Capacity),
+		PkScript: s.cfg.Channel.SpliceFundingScript(),
+	}}
+	if s.intent != nil {
+		outputs = append(outputs, s.intent.ChangeOutputs...)
+	}
+	if s.req.SpliceOut > 0 {
+		outputs = append(outputs, &wire.TxOut{
+			Value:    int64(s.req.SpliceOut),
+			PkScript: s.req.SpliceOutScript,
+		})
+	}
+	for _, txOut := range outputs {
+		addOutput, err := s.session.AddOutput(txOut)
+		if err != nil {
+			return nil, err
+		}
+		s.pendingMsgs = append(s.pendingMsgs, addOutput)
+	}
+
+	s.state = spliceConstructing
+
+	return s.nextConstructionMsg()
+}
+
+// newSession starts the interactive construction of the splice transaction.
+func (s *ChanSplicer) newSession() {
+	s.session = interactivetx.NewSession(interactivetx.Config{
+		ChanID:      s.cid,
+		IsInitiator: s.isInitiator(),
+		SharedInput: &interactivetx.SharedInput{
+			OutPoint: s.prevOutpoint,
+			PrevOut:  s.cfg.Channel.FundingTxOut(),
+		},
+		LockTime:  s.locktime,
+		DustLimit: s.cfg.DustLimit,
+	})
+}
+
+// nextConstructionMsg returns the next construction message of the
+// initiator. Once all contributions were sent, the construction is completed
+// and the remote commitment is signed.
+func (s *ChanSplicer) nextConstructionMsg() ([]lnwire.Message, error) {
+	if len(s.pendingMsgs) > 0 {
+		msg := s.pendingMsgs[0]
+		s.pendingMsgs = s.pendingMsgs[1:]
+
+		return []lnwire.Message{msg}, nil
+	}
+
+	txComplete := s.session.Complete()
+	if err := s.completeConstruction(); err != nil {
+		return nil, err
+	}
+
+	commitSig, err := s.signCommitment()
+	if err != nil {
+		return nil, err
+	}
+
+	return []lnwire.Message{txComplete, commitSig}, nil
+}
+
+// handleConstructionMsg processes a construction message of the remote peer.
+// The acceptor confirms each contribution of the initiator with tx_complete,
+// while the initiator continues with its next contribution.
+func (s *ChanSplicer) handleConstructionMsg(
+	msg lnwire.Message) ([]lnwire.Message, error) {
+
+	switch msg.(type) {
+	case *lnwire.TxAddInput, *lnwire.TxAddOutput, *lnwire.TxRemoveInput,
+		*lnwire.TxRemoveOutput, *lnwire.TxComplete:
+
+	default:
+		return nil, fmt.Errorf("%w: %v during construction",
+			ErrUnexpectedMsg, msg.MsgType())
+	}
+
+	_, isComplete := msg.(*lnwire.TxComplete)
+	if s.isInitiator() && !isComplete {
+		return nil, ErrRemoteContribution
+	}
+
+	if err := s.session.ProcessMsg(msg); err != nil {
+		return nil, err
+	}
+
+	if s.isInitiator() {
+		return s.nextConstructionMsg()
+	}
+
+	if !isComplete {
+		return []lnwire.Message{s.session.Complete()}, nil
+	}
+
+	if !s.session.IsComplete() {
+		return nil, fmt.Errorf("%w: tx_complete before any "+
+			"contribution", ErrUnexpectedMsg)
+	}
+
+	return nil, s.completeConstruction()
+}
+
+// completeConstruction validates the constructed splice transaction, and
+// locates the new funding output within it.
+func (s *ChanSplicer) completeConstruction() error {
+	tx, err := s.session.Tx()
+	if err != nil {
+		return err
+	}
+
+	fundingScript := s.cfg.Channel.SpliceFundingScript()
+	fundingIndex := -1
+	for i, txOut := range tx.TxOut {
+		if !bytes.Equal(txOut.PkScript, fundingScript) {
+			continue
+		}
+
+		if fundingIndex != -1 {
+			return fmt.Errorf("splice tx has multiple funding " +
+				"outputs")
+		}
+		fundingIndex = i
+	}
+	if fundingIndex == -1 {
+		return fmt.Errorf("splice tx has no funding output")
+	}
+
+	fundingValue := ltcutil.Amount(tx.TxOut[fundingIndex].Value)
+	if fundingValue != s.params.Capacity {
+		return fmt.Errorf("splice funding output has value %v, "+
+			"expected %v", fundingValue, s.params.Capacity)
+	}
+
+	sharedIndex, err := findSharedInput(tx, s.prevOutpoint)
+	if err != nil {
+		return err
+	}
+
+	s.spliceTx = tx
+	s.fundingIndex = uint32(fundingIndex)
+	s.sharedIndex = sharedIndex
+	s.params.FundingOutpoint = wire.OutPoint{
+		Hash:  tx.TxHash(),
+		Index: uint32(fundingIndex),
+	}
+	s.state = spliceAwaitingCommitSig
+
+	splicerLog.Infof("ChannelPoint(%v): constructed splice tx %v",
+		s.prevOutpoint, s.params.FundingOutpoint.Hash)
+
+	return nil
+}
+
+// signCommitment signs the commitment of the remote peer spending the new
+// funding output.
+func (s *ChanSplicer) signCommitment() (*lnwire.CommitSig, error) {
+	sig, remoteCommit, err := s.cfg.Channel.SignSpliceCommitment(s.params)
+	if err != nil {
+		return nil, err
+	}
+	s.remoteCommit = remoteCommit
+
+	return &lnwire.CommitSig{
+		ChanID:    s.cid,
+		CommitSig: sig,
+	}, nil
+}
+
+// handleCommitSig verifies the signature of the remote peer for our
+// commitment spending the new funding output. The acceptor responds with its
+// own signatures, after persisting the splice.
+func (s *ChanSplicer) handleCommitSig(
+	msg *lnwire.CommitSig) ([]lnwire.Message, error) {
+
+	localCommit, err := s.cfg.Channel.ReceiveSpliceCommitment(
+		s.params, msg.CommitSig,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.localCommit = localCommit
+	s.state = spliceAwaitingTxSigs
+
+	// As the initiator, we already signed the remote commitment and now
+	// wait for the tx_signatures of the acceptor.
+	if s.isInitiator() {
+		return nil, nil
+	}
+
+	commitSig, err := s.signCommitment()
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSig, err := s.cfg.Channel.SignSpliceInput(
+		s.spliceTx, s.sharedIndex,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.localSharedSig = sharedSig.Serialize()
+
+	// Once we send our signature for the splice transaction, the
+	// initiator is able to broadcast it, so the splice must be persisted
+	// first.
+	if err := s.markSplicing(false); err != nil {
+		return nil, err
+	}
+
+	txSigs, err := s.txSignatures(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return []lnwire.Message{commitSig, txSigs}, nil
+}
+
+// txSignatures returns the tx_signatures message carrying our signature for
+// the shared input, and the passed witnesses of our wallet inputs.
+func (s *ChanSplicer) txSignatures(
+	witnesses []wire.TxWitness) (*lnwire.TxSignatures, error) {
+
+	sig, err := lnwire.NewSigFromECDSARawSignature(s.localSharedSig)
+	if err != nil {
+		return nil, err
+	}
+	sharedSig := lnwire.SharedInputSig(sig)
+
+	return &lnwire.TxSignatures{
+		ChanID:         s.cid,
+		TxID:           s.spliceTx.TxHash(),
+		Witnesses:      witnesses,
+		SharedInputSig: &sharedSig,
+	}, nil
+}
+
+// markSplicing persists the splice.
+func (s *ChanSplicer) markSplicing(signed bool) error {
+	return s.cfg.Channel.State().MarkSplicing(&channeldb.ChannelSplice{
+		SpliceTx:           s.spliceTx,
+		PrevOutpoint:       s.prevOutpoint,
+		FundingOutputIndex: s.fundingIndex,
+		Capacity:           s.params.Capacity,
+		IsInitiator:        s.isInitiator(),
+		LocalContribution:  s.params.LocalContribution,
+		RemoteContribution: s.params.RemoteContribution,
+		LocalCommitment:    *s.localCommit,
+		RemoteCommitment:   *s.remoteCommit,
+		LocalSharedSig:     s.localSharedSig,
+		Signed:             signed,
+		BroadcastHeight:    s.cfg.Height,
+	})
+}
+
+// handleTxSignatures completes the splice transaction with the signatures of
+// the remote peer, and broadcasts it. The initiator signs its own inputs and
+// responds with its tx_signatures.
+func (s *ChanSplicer) handleTxSignatures(
+	msg *lnwire.TxSignatures) ([]lnwire.Message, error) {
+
+	spliceTxid := s.spliceTx.TxHash()
+	if msg.TxID != spliceTxid {
+		return nil, fmt.Errorf("tx_signatures for unknown tx %v, "+
+			"expected %v", msg.TxID, spliceTxid)
+	}
+	if msg.SharedInputSig == nil {
+		return nil, fmt.Errorf("tx_signatures without shared input " +
+			"signature")
+	}
+
+	if err := s.applyRemoteWitnesses(msg.Witnesses); err != nil {
+		return nil, err
+	}
+
+	if s.isInitiator() {
+		sharedSig, err := s.cfg.Channel.SignSpliceInput(
+			s.spliceTx, s.sharedIndex,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.localSharedSig = sharedSig.Serialize()
+	}
+
+	err := s.cfg.Channel.CompleteSpliceInput(
+		s.spliceTx, s.sharedIndex, s.localSharedSig,
+		lnwire.Sig(*msg.SharedInputSig),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp []lnwire.Message
+	if s.isInitiator() {
+		if err := s.signWalletInputs(); err != nil {
+			return nil, err
+		}
+
+		if err := s.markSplicing(true); err != nil {
+			return nil, err
+		}
+
+		txSigs, err := s.txSignatures(
+			s.session.LocalWitnesses(s.spliceTx),
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, txSigs)
+
+		// The wallet coins are spent by the splice from now on.
+		s.intent = nil
+	} else {
+		err := s.cfg.Channel.State().MarkSpliceSigned(s.spliceTx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.state = spliceAwaitingConf
+
+	splicerLog.Infof("ChannelPoint(%v): broadcasting splice tx %v",
+		s.prevOutpoint, spliceTxid)
+
+	err = s.cfg.BroadcastTx(s.spliceTx, labels.MakeLabel(
+		labels.LabelTypeSpliceTransaction,
+		&s.cfg.Channel.State().ShortChannelID,
+	))
+	if err != nil {
+		splicerLog.Errorf("ChannelPoint(%v): unable to broadcast "+
+			"splice tx %v: %v", s.prevOutpoint, spliceTxid, err)
+	}
+
+	if s.req != nil {
+		s.req.Txid <- spliceTxid
+	}
+
+	return resp, nil
+}
+
+// applyRemoteWitnesses adds the witnesses of the inputs of the remote peer to
+// the splice transaction.
+func (s *ChanSplicer) applyRemoteWitnesses(witnesses []wire.TxWitness) error {
+	if s.session != nil {
+		return s.session.ApplyRemoteWitnesses(s.spliceTx, witnesses)
+	}
+
+	// Without the construction session, which is the case for a splice
+	// that was resumed by the acceptor, all inputs except for the shared
+	// one belong to the initiator.
+	numRemote := len(s.spliceTx.TxIn) - 1
+	if len(witnesses) != numRemote {
+		return fmt.Errorf("expected %d witnesses, got %d", numRemote,
+			len(witnesses))
+	}
+
+	for i, txIn := range s.spliceTx.TxIn {
+		if i == s.sharedIndex {
+			continue
+		}
+
+		txIn.Witness = witnesses[0]
+		witnesses = witnesses[1:]
+	}
+
+	return nil
+}
+
+// signWalletInputs signs the inputs of the splice transaction that spend
+// wallet coins.
+func (s *ChanSplicer) signWalletInputs() error {
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut)
+	for _, in := range s.session.Inputs() {
+		prevOuts[in.OutPoint] = in.PrevOut
+	}
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	sigHashes := txscript.NewTxSigHashes(s.spliceTx, prevOutFetcher)
+
+	for i, in := range s.session.Inputs() {
+		if in.Shared || !s.session.IsLocal(in.SerialID) {
+			continue
+		}
+
+		hashType := txscript.SigHashAll
+		if txscript.IsPayToTaproot(in.PrevOut.PkScript) {
+			hashType = txscript.SigHashDefault
+		}
+
+		inputScript, err := s.cfg.Signer.ComputeInputScript(
+			s.spliceTx, &input.SignDescriptor{
+				Output:            in.PrevOut,
+				HashType:          hashType,
+				SigHashes:         sigHashes,
+				PrevOutputFetcher: prevOutFetcher,
+				InputIndex:        i,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to sign input %v: %w",
+				in.OutPoint, err)
+		}
+
+		s.spliceTx.TxIn[i].Witness = inputScript.Witness
+		s.spliceTx.TxIn[i].SignatureScript = inputScript.SigScript
+	}
+
+	return nil
+}
+
+// SpliceConfirmed is called once the splice transaction has confirmed and
+// the channel was moved over to the new funding output. It returns the
+// splice_locked message for the remote peer.
+func (s *ChanSplicer) SpliceConfirmed(
+	spliced *channeldb.OpenChannel) (*lnwire.SpliceLocked, error) {
+
+	if s.state != spliceAwaitingConf {
+		return nil, fmt.Errorf("splice of ChannelPoint(%v) confirmed "+
+			"in state %v", s.prevOutpoint, s.state)
+	}
+
+	s.spliced = spliced
+	s.localLocked = true
+	s.state = spliceLocked
+
+	// If the remote peer already sent splice_locked, we persist it now
+	// that the channel was moved over to the new funding output.
+	if s.remoteLocked {
+		if err := spliced.MarkSpliceRemoteLocked(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &lnwire.SpliceLocked{
+		ChanID:     s.cid,
+		SpliceTxid: s.spliceTx.TxHash(),
+	}, nil
+}
+
+// handleSpliceLocked records the splice_locked message of the remote peer.
+func (s *ChanSplicer) handleSpliceLocked(msg *lnwire.SpliceLocked) error {
+	spliceTxid := s.spliceTx.TxHash()
+	if msg.SpliceTxid != spliceTxid {
+		return fmt.Errorf("splice_locked for unknown tx %v, "+
+			"expected %v", msg.SpliceTxid, spliceTxid)
+	}
+
+	s.remoteLocked = true
+
+	// Before the splice confirmed for us, the remote peer locking it is
+	// only kept in memory. It's persisted once the channel was moved over
+	// to the new funding output.
+	if s.spliced == nil {
+		return nil
+	}
+
+	return s.spliced.MarkSpliceRemoteLocked()
+}
+
+// findSharedInput returns the index of the input of the splice transaction
+// that spends the given funding outpoint.
+func findSharedInput(tx *wire.MsgTx, prevOutpoint wire.OutPoint) (int,
+	error) {
+
+	for i, txIn := range tx.TxIn {
+		if txIn.PreviousOutPoint == prevOutpoint {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("splice tx doesn't spend %v", prevOutpoint)
+}
+
+// String returns a human readable form of the splice state.
+func (s spliceState) String() string {
+	switch s {
+	case spliceIdle:
+		return "Idle"
+	case spliceAwaitingAck:
+		return "AwaitingAck"
+	case spliceConstructing:
+		return "Constructing"
+	case spliceAwaitingCommitSig:
+		return "AwaitingCommitSig"
+	case spliceAwaitingTxSigs:
+		return "AwaitingTxSigs"
+	case spliceAwaitingConf:
+		return "AwaitingConf"
+	case spliceLocked:
+		return "Locked"
+	case spliceAborted:
+		return "Aborted"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,257 @@
+package chansplicer
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// testSplicer bundles a splice state machine with the transactions it
+// broadcast.
+type testSplicer struct {
+	*ChanSplicer
+
+	channel   *lnwallet.LightningChannel
+	broadcast []*wire.MsgTx
+}
+
+// newTestSplicer creates a splice state machine for the given channel.
+func newTestSplicer(channel *lnwallet.LightningChannel,
+	req *SpliceRequest) *testSplicer {
+
+	s := &testSplicer{
+		channel: channel,
+	}
+	s.ChanSplicer = NewChanSplicer(s.cfg(), req)
+
+	return s
+}
+
+// cfg returns the configuration of the test splicer.
+func (s *testSplicer) cfg() ChanSpliceCfg {
+	return ChanSpliceCfg{
+		Channel: s.channel,
+		Signer:  s.channel.Signer,
+		BroadcastTx: func(tx *wire.MsgTx, _ string) error {
+			s.broadcast = append(s.broadcast, tx.Copy())
+			return nil
+		},
+		Height:    100,
+		DustLimit: 573,
+	}
+}
+
+// deliver sends the passed messages to the receiving splicer, and delivers
+// all responses back until no more messages are exchanged.
+func deliver(t *testing.T, from, to *testSplicer, msgs ...lnwire.Message) {
+	t.Helper()
+
+	for _, msg := range msgs {
+		resp, err := to.ProcessSpliceMsg(msg)
+		require.NoError(t, err, "unable to process %v", msg.MsgType())
+
+		deliver(t, to, from, resp...)
+	}
+}
+
+// spliceOutRequest returns a request to splice funds out of the channel.
+func spliceOutRequest(amt ltcutil.Amount) *SpliceRequest {
+	return &SpliceRequest{
+		SpliceOut: amt,
+		SpliceOutScript: append(
+			[]byte{txscript.OP_0, txscript.OP_DATA_20},
+			make([]byte, 20)...,
+		),
+		FeeRate: chainfee.SatPerKWeight(1000),
+		Txid:    make(chan chainhash.Hash, 1),
+		Err:     make(chan error, 1),
+	}
+}
+
+// TestChanSplicerSpliceOut tests a splice of funds out of a channel, from its
+// negotiation until both peers locked the splice.
+func TestChanSplicerSpliceOut(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	oldPoint := *aliceChannel.ChannelPoint()
+	fundingOut := aliceChannel.FundingTxOut()
+
+	const spliceAmt = ltcutil.Amount(1_000_000)
+	req := spliceOutRequest(spliceAmt)
+	alice := newTestSplicer(aliceChannel, req)
+	bob := newTestSplicer(bobChannel, nil)
+
+	spliceInit, err := alice.InitSplice()
+	require.NoError(t, err)
+	require.Negative(t, spliceInit.FundingContribution)
+
+	deliver(t, alice, bob, spliceInit)
+
+	// Both peers broadcast the same fully signed splice transaction.
+	require.Len(t, alice.broadcast, 1)
+	require.Len(t, bob.broadcast, 1)
+	spliceTx := alice.broadcast[0]
+	spliceTxid := spliceTx.TxHash()
+	require.Equal(t, spliceTxid, bob.broadcast[0].TxHash())
+	require.Equal(t, spliceTxid, <-req.Txid)
+
+	require.Len(t, spliceTx.TxIn, 1)
+	require.Len(t, spliceTx.TxOut, 2)
+	prevFetcher := txscript.NewCannedPrevOutputFetcher(
+		fundingOut.PkScript, fundingOut.Value,
+	)
+	vm, err := txscript.NewEngine(
+		fundingOut.PkScript, spliceTx, 0, txscript.StandardVerifyFlags,
+		nil, txscript.NewTxSigHashes(spliceTx, prevFetcher),
+		fundingOut.Value, prevFetcher,
+	)
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute())
+
+	// Both peers persisted the splice, and can no longer abort it.
+	require.False(t, alice.CanAbort())
+	require.False(t, bob.CanAbort())
+	for _, channel := range []*lnwallet.LightningChannel{
+		aliceChannel, bobChannel,
+	} {
+		splice, err := channel.State().FetchSplice()
+		require.NoError(t, err)
+		require.True(t, splice.Signed)
+		require.Equal(t, oldPoint, splice.PrevOutpoint)
+		require.Equal(
+			t, ltcutil.Amount(fundingOut.Value)+
+				spliceInit.FundingContribution,
+			splice.Capacity,
+		)
+	}
+
+	// Once the splice confirmed, both peers lock it.
+	scid := lnwire.ShortChannelID{BlockHeight: 103, TxIndex: 1}
+	require.NoError(t, aliceChannel.State().CompleteSplice(scid))
+	aliceLocked, err := alice.SpliceConfirmed(aliceChannel.State())
+	require.NoError(t, err)
+	require.Equal(t, spliceTxid, aliceLocked.SpliceTxid)
+	require.Equal(t, lnwire.NewChanIDFromOutPoint(&oldPoint),
+		aliceLocked.ChanID)
+
+	deliver(t, alice, bob, aliceLocked)
+	require.False(t, alice.IsLocked())
+	require.False(t, bob.IsLocked())
+
+	require.NoError(t, bobChannel.State().CompleteSplice(scid))
+	bobLocked, err := bob.SpliceConfirmed(bobChannel.State())
+	require.NoError(t, err)
+	deliver(t, bob, alice, bobLocked)
+
+	require.True(t, alice.IsLocked())
+	require.True(t, bob.IsLocked())
+
+	splice, err := bob.SplicedChannel().FetchSplice()
+	require.NoError(t, err)
+	require.True(t, splice.LocalLocked)
+	require.True(t, splice.RemoteLocked)
+}
+
+// TestChanSplicerAbort tests that a splice can be aborted until it was
+// persisted, and that the acceptor retransmits its signatures after a
+// restart.
+func TestChanSplicerAbort(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	// A splice that neither adds nor removes funds is rejected.
+	_, err = newTestSplicer(aliceChannel, &SpliceRequest{}).InitSplice()
+	require.ErrorIs(t, err, ErrInvalidSpliceRequest)
+
+	alice := newTestSplicer(aliceChannel, spliceOutRequest(1_000_000))
+	bob := newTestSplicer(bobChannel, nil)
+
+	spliceInit, err := alice.InitSplice()
+	require.NoError(t, err)
+	spliceAck, err := bob.ProcessSpliceMsg(spliceInit)
+	require.NoError(t, err)
+	require.Len(t, spliceAck, 1)
+
+	// The acceptor can abort during the construction.
+	require.True(t, bob.CanAbort())
+	_, err = bob.ProcessSpliceMsg(&lnwire.TxAbort{})
+	require.ErrorIs(t, err, ErrSpliceAborted)
+
+	// Run the construction again, but stop before the initiator
+	// processes the signatures of the acceptor.
+	alice = newTestSplicer(aliceChannel, spliceOutRequest(1_000_000))
+	bob = newTestSplicer(bobChannel, nil)
+	spliceInit, err = alice.InitSplice()
+	require.NoError(t, err)
+
+	msgs := []lnwire.Message{spliceInit}
+	var txSigs *lnwire.TxSignatures
+	for len(msgs) > 0 {
+		var resp []lnwire.Message
+		for _, msg := range msgs {
+			out, err := bob.ProcessSpliceMsg(msg)
+			require.NoError(t, err)
+
+			for _, o := range out {
+				if sigs, ok := o.(*lnwire.TxSignatures); ok {
+					txSigs = sigs
+					continue
+				}
+
+				in, err := alice.ProcessSpliceMsg(o)
+				require.NoError(t, err)
+				resp = append(resp, in...)
+			}
+		}
+		msgs = resp
+	}
+	require.NotNil(t, txSigs)
+
+	// The acceptor persisted the splice and can't abort it anymore.
+	require.False(t, bob.CanAbort())
+	_, err = bob.ProcessSpliceMsg(&lnwire.TxAbort{})
+	require.ErrorIs(t, err, ErrUnexpectedMsg)
+
+	// After a restart, the acceptor retransmits its signatures, and
+	// completes the splice with those of the initiator.
+	splice, err := bobChannel.State().FetchSplice()
+	require.NoError(t, err)
+	require.False(t, splice.Signed)
+
+	resumedBob := &testSplicer{channel: bobChannel}
+	var resend []lnwire.Message
+	resumedBob.ChanSplicer, resend, err = ResumeChanSplicer(
+		resumedBob.cfg(), splice,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []lnwire.Message{txSigs}, resend)
+
+	deliver(t, resumedBob, alice, resend...)
+	require.Len(t, alice.broadcast, 1)
+	require.Len(t, resumedBob.broadcast, 1)
+	require.Equal(
+		t, alice.broadcast[0].TxHash(),
+		resumedBob.broadcast[0].TxHash(),
+	)
+	require.Equal(
+		t, alice.broadcast[0].TxIn[0].Witness,
+		resumedBob.broadcast[0].TxIn[0].Witness,
+	)
+}
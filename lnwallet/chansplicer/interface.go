@@ -0,0 +1,57 @@
+package chansplicer
+
+import (
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// Channel abstracts away from the core channel state machine by exposing an
+// interface that requires only the methods we need to carry out a splice of
+// the channel.
+type Channel interface {
+	// ChannelPoint returns the channel point of the target channel.
+	ChannelPoint() *wire.OutPoint
+
+	// State returns the persistent state of the channel.
+	State() *channeldb.OpenChannel
+
+	// CanSplice returns an error if the channel can't currently be
+	// spliced.
+	CanSplice() error
+
+	// FundingTxOut returns the funding output of the channel.
+	FundingTxOut() *wire.TxOut
+
+	// SpliceFundingScript returns the pkScript of the new funding output
+	// of a splice.
+	SpliceFundingScript() []byte
+
+	// ValidateSplice checks that both peers can afford the passed
+	// contributions.
+	ValidateSplice(params *lnwallet.SpliceParams) error
+
+	// SignSpliceCommitment signs the commitment of the remote peer that
+	// spends the new funding output of the splice.
+	SignSpliceCommitment(params *lnwallet.SpliceParams) (lnwire.Sig,
+		*channeldb.ChannelCommitment, error)
+
+	// ReceiveSpliceCommitment verifies the signature of the remote peer
+	// for our commitment that spends the new funding output of the
+	// splice.
+	ReceiveSpliceCommitment(params *lnwallet.SpliceParams,
+		sig lnwire.Sig) (*channeldb.ChannelCommitment, error)
+
+	// SignSpliceInput signs the input of the splice transaction that
+	// spends the current funding output.
+	SignSpliceInput(spliceTx *wire.MsgTx,
+		inputIndex int) (input.Signature, error)
+
+	// CompleteSpliceInput sets the witness of the input of the splice
+	// transaction that spends the current funding output, after
+	// verifying the signature of the remote peer.
+	CompleteSpliceInput(spliceTx *wire.MsgTx, inputIndex int,
+		localSig []byte, remoteSig lnwire.Sig) error
+}
@@ -0,0 +1,25 @@
+package chansplicer
+
+import (
+	"github.com/btcsuite/btclog"
+	"github.com/ltcsuite/lnd/build"
+)
+
+// splicerLog is a logger that is initialized with the btclog.Disabled
+// logger.
+var splicerLog btclog.Logger
+
+// The default amount of logging is none.
+func init() {
+	UseLogger(build.NewSubLogger("SPLC", nil))
+}
+
+// DisableLog disables all logging output.
+func DisableLog() {
+	UseLogger(btclog.Disabled)
+}
+
+// UseLogger uses a specified Logger to output package logging info.
+func UseLogger(logger btclog.Logger) {
+	splicerLog = logger
+}
@@ -1207,10 +1207,14 @@ func findOutputIndexesFromRemote(revocationPreimage *chainhash.Hash,
 	chanCommit := chanState.RemoteCommitment
 	_, commitmentPoint := btcec.PrivKeyFromBytes(revocationPreimage[:])
 
+	// The channel type may have been upgraded since the commitment was
+	// created, so we'll use the type that was in use at its height.
+	chanType := chanState.ChanTypeAtHeight(false, chanCommit.CommitHeight)
+
 	// With the commitment point generated, we can now derive the king ring
 	// which will be used to generate the output scripts.
 	keyRing := DeriveCommitmentKeys(
-		commitmentPoint, false, chanState.ChanType,
+		commitmentPoint, false, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -1224,7 +1228,7 @@ func findOutputIndexesFromRemote(revocationPreimage *chainhash.Hash,
 	isRemoteInitiator := !chanState.IsInitiator
 
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 
@@ -1236,7 +1240,7 @@ func findOutputIndexesFromRemote(revocationPreimage *chainhash.Hash,
 	// Compute the to local script. From our PoV, when facing a remote
 	// commitment, the to local output belongs to them.
 	theirScript, err := CommitScriptToSelf(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToLocalKey,
+		chanType, isRemoteInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, theirDelay, leaseExpiry,
 	)
 	if err != nil {
@@ -1246,7 +1250,7 @@ func findOutputIndexesFromRemote(revocationPreimage *chainhash.Hash,
 	// Compute the to remote script. From our PoV, when facing a remote
 	// commitment, the to remote output belongs to us.
 	ourScript, _, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry,
 	)
 	if err != nil {
@@ -0,0 +1,49 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// FundContribution selects confirmed coins of the wallet that add the passed
+// amount to a funding transaction that is constructed together with the
+// remote party, as it's the case for dual funded channels and splices. The
+// fee for spending the coins and the change is paid at the given fee rate.
+// The coins are locked until the returned intent is canceled.
+func (l *LightningWallet) FundContribution(amt ltcutil.Amount,
+	feeRate chainfee.SatPerKWeight) (*chanfunding.FullIntent, error) {
+
+	assembler := chanfunding.NewWalletAssembler(chanfunding.WalletConfig{
+		CoinSource:       &CoinSource{l},
+		CoinSelectLocker: l,
+		CoinLocker:       l,
+		Signer:           l.Cfg.Signer,
+		DustLimit:        DustLimitForSize(input.P2WSHSize),
+	})
+
+	intent, err := assembler.ProvisionChannel(&chanfunding.Request{
+		LocalAmt: amt,
+		MinConfs: 1,
+		FeeRate:  feeRate,
+		ChangeAddr: func() (ltcutil.Address, error) {
+			return l.NewAddress(
+				TaprootPubkey, true, DefaultAccountName,
+			)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fullIntent, ok := intent.(*chanfunding.FullIntent)
+	if !ok {
+		intent.Cancel()
+		return nil, fmt.Errorf("unexpected funding intent %T", intent)
+	}
+
+	return fullIntent, nil
+}
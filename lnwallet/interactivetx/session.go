@@ -0,0 +1,653 @@
+package interactivetx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// MaxInputsOutputs is the maximum number of inputs, and separately
+	// outputs, that a transaction may have when constructed
+	// interactively.
+	MaxInputsOutputs = 252
+
+	// MaxMessages is the maximum number of tx_add_* and tx_remove_*
+	// messages that a peer may send during a single construction.
+	MaxMessages = 4096
+
+	// TxVersion is the version of transactions that are constructed
+	// interactively.
+	TxVersion = 2
+)
+
+var (
+	// ErrWrongSerialIDParity is returned when a peer uses a serial ID
+	// that belongs to the other side of the construction.
+	ErrWrongSerialIDParity = errors.New("serial id has the wrong parity")
+
+	// ErrDuplicateSerialID is returned when a serial ID is added twice.
+	ErrDuplicateSerialID = errors.New("duplicate serial id")
+
+	// ErrUnknownSerialID is returned when a peer removes an input or
+	// output that does not exist.
+	ErrUnknownSerialID = errors.New("unknown serial id")
+
+	// ErrDuplicateInput is returned when an outpoint is spent twice.
+	ErrDuplicateInput = errors.New("outpoint is already spent")
+
+	// ErrInvalidSequence is returned when an input doesn't signal
+	// replaceability.
+	ErrInvalidSequence = errors.New("input sequence must be below " +
+		"0xfffffffe")
+
+	// ErrNonSegwitInput is returned when an input spends an output that
+	// isn't a native segwit output, which would make the txid of the
+	// constructed transaction malleable.
+	ErrNonSegwitInput = errors.New("input doesn't spend a segwit output")
+
+	// ErrNonSegwitOutput is returned when an output doesn't pay to a
+	// native segwit script.
+	ErrNonSegwitOutput = errors.New("output isn't a segwit output")
+
+	// ErrOutputBelowDust is returned when an output is below the dust
+	// limit.
+	ErrOutputBelowDust = errors.New("output is below the dust limit")
+
+	// ErrUnexpectedSharedInput is returned when a shared input is added
+	// that doesn't match the one of the session, or when the session
+	// doesn't expect one at all.
+	ErrUnexpectedSharedInput = errors.New("unexpected shared input")
+
+	// ErrMissingSharedInput is returned when the construction completes
+	// without the shared input that the session requires.
+	ErrMissingSharedInput = errors.New("shared input is missing")
+
+	// ErrTooManyInputs is returned when the transaction has too many
+	// inputs.
+	ErrTooManyInputs = errors.New("too many inputs")
+
+	// ErrTooManyOutputs is returned when the transaction has too many
+	// outputs.
+	ErrTooManyOutputs = errors.New("too many outputs")
+
+	// ErrTooManyMessages is returned when a peer sends too many messages
+	// during a single construction.
+	ErrTooManyMessages = errors.New("too many messages")
+
+	// ErrInsufficientInputs is returned when the inputs of the
+	// constructed transaction don't cover its outputs.
+	ErrInsufficientInputs = errors.New("inputs don't cover outputs")
+
+	// ErrNotComplete is returned when the transaction is requested before
+	// both peers have sent tx_complete.
+	ErrNotComplete = errors.New("construction is not complete")
+
+	// ErrSessionComplete is returned when an input or output is added
+	// after the construction has completed.
+	ErrSessionComplete = errors.New("construction is already complete")
+)
+
+// Input is an input of a transaction that is constructed interactively.
+type Input struct {
+	// SerialID identifies the input within the construction.
+	SerialID uint64
+
+	// OutPoint is the outpoint spent by the input.
+	OutPoint wire.OutPoint
+
+	// Sequence is the sequence number of the input.
+	Sequence uint32
+
+	// PrevOut is the output spent by the input.
+	PrevOut *wire.TxOut
+
+	// Shared is true if the input spends the shared funding output of a
+	// channel that is being spliced.
+	Shared bool
+}
+
+// Output is an output of a transaction that is constructed interactively.
+type Output struct {
+	// SerialID identifies the output within the construction.
+	SerialID uint64
+
+	// TxOut is the output itself.
+	TxOut *wire.TxOut
+}
+
+// SharedInput describes a funding output that both peers spend together,
+// such as the current funding output of a channel that is spliced.
+type SharedInput struct {
+	// OutPoint is the outpoint of the funding output.
+	OutPoint wire.OutPoint
+
+	// PrevOut is the funding output.
+	PrevOut *wire.TxOut
+}
+
+// Config holds the parameters of an interactive transaction construction.
+type Config struct {
+	// ChanID is the channel the transaction is constructed for.
+	ChanID lnwire.ChannelID
+
+	// IsInitiator is true if the local node initiated the construction.
+	// The initiator uses even serial IDs, the other peer odd ones.
+	IsInitiator bool
+
+	// SharedInput is the funding output that must be spent by the
+	// transaction, if any.
+	SharedInput *SharedInput
+
+	// LockTime is the locktime of the constructed transaction.
+	LockTime uint32
+
+	// DustLimit is the minimum value of the outputs of the transaction.
+	DustLimit ltcutil.Amount
+}
+
+// Session tracks the state of the interactive construction of a transaction
+// with a single peer, as described in BOLT 2. It produces the messages for
+// our own contributions and validates those of the peer, so that both sides
+// end up with the same transaction once both have sent tx_complete.
+type Session struct {
+	cfg Config
+
+	inputs  map[uint64]*Input
+	outputs map[uint64]*Output
+	spent   map[wire.OutPoint]uint64
+
+	nextSerialID uint64
+
+	localComplete  bool
+	remoteComplete bool
+	numRemoteMsgs  int
+}
+
+// NewSession creates a new construction session.
+func NewSession(cfg Config) *Session {
+	nextSerialID := uint64(1)
+	if cfg.IsInitiator {
+		nextSerialID = 0
+	}
+
+	return &Session{
+		cfg:          cfg,
+		inputs:       make(map[uint64]*Input),
+		outputs:      make(map[uint64]*Output),
+		spent:        make(map[wire.OutPoint]uint64),
+		nextSerialID: nextSerialID,
+	}
+}
+
+// isLocalSerialID returns true if the serial ID belongs to the local node.
+func (s *Session) isLocalSerialID(serialID uint64) bool {
+	isEven := serialID%2 == 0
+	return isEven == s.cfg.IsInitiator
+}
+
+// newSerialID returns a fresh serial ID for one of our own contributions.
+func (s *Session) newSerialID() uint64 {
+	serialID := s.nextSerialID
+	s.nextSerialID += 2
+
+	return serialID
+}
+
+// addInput validates and records an input of either side.
+func (s *Session) addInput(in *Input) error {
+	switch {
+	case s.localComplete && s.remoteComplete:
+		return ErrSessionComplete
+
+	case len(s.inputs) >= MaxInputsOutputs:
+		return ErrTooManyInputs
+
+	case in.Sequence >= wire.MaxTxInSequenceNum-1:
+		return ErrInvalidSequence
+	}
+
+	if _, ok := s.inputs[in.SerialID]; ok {
+		return fmt.Errorf("%w: %d", ErrDuplicateSerialID, in.SerialID)
+	}
+	if _, ok := s.spent[in.OutPoint]; ok {
+		return fmt.Errorf("%w: %v", ErrDuplicateInput, in.OutPoint)
+	}
+
+	// Only native segwit outputs may be spent, as anything else would
+	// allow the txid to change once the witnesses are added. MWEB
+	// outputs live in the extension block and can't be spent here.
+	pkScript := in.PrevOut.PkScript
+	if !txscript.IsWitnessProgram(pkScript) || txscript.IsMweb(pkScript) {
+		return fmt.Errorf("%w: %v", ErrNonSegwitInput, in.OutPoint)
+	}
+
+	s.inputs[in.SerialID] = in
+	s.spent[in.OutPoint] = in.SerialID
+
+	return nil
+}
+
+// addOutput validates and records an output of either side.
+func (s *Session) addOutput(out *Output) error {
+	switch {
+	case s.localComplete && s.remoteComplete:
+		return ErrSessionComplete
+
+	case len(s.outputs) >= MaxInputsOutputs:
+		return ErrTooManyOutputs
+	}
+
+	if _, ok := s.outputs[out.SerialID]; ok {
+		return fmt.Errorf("%w: %d", ErrDuplicateSerialID, out.SerialID)
+	}
+
+	pkScript := out.TxOut.PkScript
+	if !txscript.IsWitnessProgram(pkScript) || txscript.IsMweb(pkScript) {
+		return ErrNonSegwitOutput
+	}
+
+	if ltcutil.Amount(out.TxOut.Value) < s.cfg.DustLimit {
+		return fmt.Errorf("%w: %v", ErrOutputBelowDust,
+			ltcutil.Amount(out.TxOut.Value))
+	}
+
+	s.outputs[out.SerialID] = out
+
+	return nil
+}
+
+// AddInput adds one of our own inputs to the transaction and returns the
+// message that announces it to the peer.
+func (s *Session) AddInput(prevTx *wire.MsgTx, vout uint32,
+	sequence uint32) (*lnwire.TxAddInput, error) {
+
+	if int(vout) >= len(prevTx.TxOut) {
+		return nil, fmt.Errorf("output %d of %v doesn't exist", vout,
+			prevTx.TxHash())
+	}
+
+	var rawTx bytes.Buffer
+	if err := prevTx.Serialize(&rawTx); err != nil {
+		return nil, err
+	}
+
+	in := &Input{
+		SerialID: s.newSerialID(),
+		OutPoint: wire.OutPoint{
+			Hash:  prevTx.TxHash(),
+			Index: vout,
+		},
+		Sequence: sequence,
+		PrevOut:  prevTx.TxOut[vout],
+	}
+	if err := s.addInput(in); err != nil {
+		return nil, err
+	}
+
+	s.remoteComplete = false
+
+	return &lnwire.TxAddInput{
+		ChanID:     s.cfg.ChanID,
+		SerialID:   in.SerialID,
+		PrevTx:     rawTx.Bytes(),
+		PrevTxVout: vout,
+		Sequence:   sequence,
+	}, nil
+}
+
+// AddSharedInput adds the shared input of the session to the transaction and
+// returns the message that announces it to the peer.
+func (s *Session) AddSharedInput(sequence uint32) (*lnwire.TxAddInput,
+	error) {
+
+	shared := s.cfg.SharedInput
+	if shared == nil {
+		return nil, ErrUnexpectedSharedInput
+	}
+
+	in := &Input{
+		SerialID: s.newSerialID(),
+		OutPoint: shared.OutPoint,
+		Sequence: sequence,
+		PrevOut:  shared.PrevOut,
+		Shared:   true,
+	}
+	if err := s.addInput(in); err != nil {
+		return nil, err
+	}
+
+	s.remoteComplete = false
+
+	txid := lnwire.SharedInputTxid(shared.OutPoint.Hash)
+
+	return &lnwire.TxAddInput{
+		ChanID:          s.cfg.ChanID,
+		SerialID:        in.SerialID,
+		PrevTxVout:      shared.OutPoint.Index,
+		Sequence:        sequence,
+		SharedInputTxid: &txid,
+	}, nil
+}
+
+// AddOutput adds one of our own outputs to the transaction and returns the
+// message that announces it to the peer.
+func (s *Session) AddOutput(txOut *wire.TxOut) (*lnwire.TxAddOutput, error) {
+	out := &Output{
+		SerialID: s.newSerialID(),
+		TxOut:    txOut,
+	}
+	if err := s.addOutput(out); err != nil {
+		return nil, err
+	}
+
+	s.remoteComplete = false
+
+	return &lnwire.TxAddOutput{
+		ChanID:   s.cfg.ChanID,
+		SerialID: out.SerialID,
+		Amount:   ltcutil.Amount(txOut.Value),
+		Script:   txOut.PkScript,
+	}, nil
+}
+
+// RemoveInput removes one of our own inputs from the transaction and returns
+// the message that announces the removal to the peer.
+func (s *Session) RemoveInput(serialID uint64) (*lnwire.TxRemoveInput,
+	error) {
+
+	in, ok := s.inputs[serialID]
+	if !ok || !s.isLocalSerialID(serialID) {
+		return nil, fmt.Errorf("%w: input %d", ErrUnknownSerialID,
+			serialID)
+	}
+	delete(s.inputs, serialID)
+	delete(s.spent, in.OutPoint)
+
+	s.remoteComplete = false
+
+	return &lnwire.TxRemoveInput{
+		ChanID:   s.cfg.ChanID,
+		SerialID: serialID,
+	}, nil
+}
+
+// RemoveOutput removes one of our own outputs from the transaction and
+// returns the message that announces the removal to the peer.
+func (s *Session) RemoveOutput(serialID uint64) (*lnwire.TxRemoveOutput,
+	error) {
+
+	if _, ok := s.outputs[serialID]; !ok || !s.isLocalSerialID(serialID) {
+		return nil, fmt.Errorf("%w: output %d", ErrUnknownSerialID,
+			serialID)
+	}
+	delete(s.outputs, serialID)
+
+	s.remoteComplete = false
+
+	return &lnwire.TxRemoveOutput{
+		ChanID:   s.cfg.ChanID,
+		SerialID: serialID,
+	}, nil
+}
+
+// Complete marks our side of the construction as complete and returns the
+// tx_complete message to send to the peer.
+func (s *Session) Complete() *lnwire.TxComplete {
+	s.localComplete = true
+
+	return &lnwire.TxComplete{
+		ChanID: s.cfg.ChanID,
+	}
+}
+
+// ProcessMsg applies a construction message received from the peer. An error
+// means that the construction must be aborted.
+func (s *Session) ProcessMsg(msg lnwire.Message) error {
+	if _, ok := msg.(*lnwire.TxComplete); ok {
+		s.remoteComplete = true
+		return nil
+	}
+
+	s.numRemoteMsgs++
+	if s.numRemoteMsgs > MaxMessages {
+		return ErrTooManyMessages
+	}
+
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		if s.isLocalSerialID(msg.SerialID) {
+			return ErrWrongSerialIDParity
+		}
+
+		in, err := s.remoteInput(msg)
+		if err != nil {
+			return err
+		}
+		if err := s.addInput(in); err != nil {
+			return err
+		}
+
+	case *lnwire.TxAddOutput:
+		if s.isLocalSerialID(msg.SerialID) {
+			return ErrWrongSerialIDParity
+		}
+
+		err := s.addOutput(&Output{
+			SerialID: msg.SerialID,
+			TxOut: &wire.TxOut{
+				Value:    int64(msg.Amount),
+				PkScript: msg.Script,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+	case *lnwire.TxRemoveInput:
+		if s.isLocalSerialID(msg.SerialID) {
+			return ErrWrongSerialIDParity
+		}
+
+		in, ok := s.inputs[msg.SerialID]
+		if !ok {
+			return fmt.Errorf("%w: input %d", ErrUnknownSerialID,
+				msg.SerialID)
+		}
+		delete(s.inputs, msg.SerialID)
+		delete(s.spent, in.OutPoint)
+
+	case *lnwire.TxRemoveOutput:
+		if s.isLocalSerialID(msg.SerialID) {
+			return ErrWrongSerialIDParity
+		}
+
+		if _, ok := s.outputs[msg.SerialID]; !ok {
+			return fmt.Errorf("%w: output %d", ErrUnknownSerialID,
+				msg.SerialID)
+		}
+		delete(s.outputs, msg.SerialID)
+
+	default:
+		return fmt.Errorf("unexpected message %v", msg.MsgType())
+	}
+
+	// Any change by the peer needs to be acknowledged by us again before
+	// the construction can end.
+	s.localComplete = false
+
+	return nil
+}
+
+// remoteInput converts an input announced by the peer.
+func (s *Session) remoteInput(msg *lnwire.TxAddInput) (*Input, error) {
+	if msg.SharedInputTxid != nil {
+		txid := chainhash.Hash(*msg.SharedInputTxid)
+		shared := s.cfg.SharedInput
+		if shared == nil ||
+			shared.OutPoint.Hash != txid ||
+			shared.OutPoint.Index != msg.PrevTxVout {
+
+			return nil, ErrUnexpectedSharedInput
+		}
+
+		return &Input{
+			SerialID: msg.SerialID,
+			OutPoint: shared.OutPoint,
+			Sequence: msg.Sequence,
+			PrevOut:  shared.PrevOut,
+			Shared:   true,
+		}, nil
+	}
+
+	var prevTx wire.MsgTx
+	if err := prevTx.Deserialize(bytes.NewReader(msg.PrevTx)); err != nil {
+		return nil, fmt.Errorf("unable to decode previous tx: %w", err)
+	}
+
+	if int(msg.PrevTxVout) >= len(prevTx.TxOut) {
+		return nil, fmt.Errorf("output %d of %v doesn't exist",
+			msg.PrevTxVout, prevTx.TxHash())
+	}
+
+	return &Input{
+		SerialID: msg.SerialID,
+		OutPoint: wire.OutPoint{
+			Hash:  prevTx.TxHash(),
+			Index: msg.PrevTxVout,
+		},
+		Sequence: msg.Sequence,
+		PrevOut:  prevTx.TxOut[msg.PrevTxVout],
+	}, nil
+}
+
+// IsComplete returns true once both peers have sent tx_complete without any
+// changes in between.
+func (s *Session) IsComplete() bool {
+	return s.localComplete && s.remoteComplete
+}
+
+// RemoteComplete returns true if the peer has sent tx_complete after our last
+// change.
+func (s *Session) RemoteComplete() bool {
+	return s.remoteComplete
+}
+
+// Inputs returns all inputs of the transaction sorted by serial ID, which is
+// the order they have in the final transaction.
+func (s *Session) Inputs() []*Input {
+	inputs := make([]*Input, 0, len(s.inputs))
+	for _, in := range s.inputs {
+		inputs = append(inputs, in)
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].SerialID < inputs[j].SerialID
+	})
+
+	return inputs
+}
+
+// Outputs returns all outputs of the transaction sorted by serial ID, which
+// is the order they have in the final transaction.
+func (s *Session) Outputs() []*Output {
+	outputs := make([]*Output, 0, len(s.outputs))
+	for _, out := range s.outputs {
+		outputs = append(outputs, out)
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].SerialID < outputs[j].SerialID
+	})
+
+	return outputs
+}
+
+// IsLocal returns true if the input or output with the given serial ID was
+// added by the local node.
+func (s *Session) IsLocal(serialID uint64) bool {
+	return s.isLocalSerialID(serialID)
+}
+
+// Tx returns the unsigned transaction once the construction is complete.
+func (s *Session) Tx() (*wire.MsgTx, error) {
+	if !s.IsComplete() {
+		return nil, ErrNotComplete
+	}
+
+	if s.cfg.SharedInput != nil {
+		if _, ok := s.spent[s.cfg.SharedInput.OutPoint]; !ok {
+			return nil, ErrMissingSharedInput
+		}
+	}
+
+	tx := wire.NewMsgTx(TxVersion)
+	tx.LockTime = s.cfg.LockTime
+
+	var totalIn, totalOut ltcutil.Amount
+	for _, in := range s.Inputs() {
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: in.OutPoint,
+			Sequence:         in.Sequence,
+		})
+		totalIn += ltcutil.Amount(in.PrevOut.Value)
+	}
+	for _, out := range s.Outputs() {
+		tx.AddTxOut(out.TxOut)
+		totalOut += ltcutil.Amount(out.TxOut.Value)
+	}
+
+	if totalIn < totalOut {
+		return nil, fmt.Errorf("%w: inputs=%v, outputs=%v",
+			ErrInsufficientInputs, totalIn, totalOut)
+	}
+
+	return tx, nil
+}
+
+// LocalWitnesses collects the witnesses of our own inputs, except for the
+// shared input, from the signed transaction in the order expected by the
+// tx_signatures message.
+func (s *Session) LocalWitnesses(tx *wire.MsgTx) []wire.TxWitness {
+	var witnesses []wire.TxWitness
+	for i, in := range s.Inputs() {
+		if in.Shared || !s.isLocalSerialID(in.SerialID) {
+			continue
+		}
+
+		witnesses = append(witnesses, tx.TxIn[i].Witness)
+	}
+
+	return witnesses
+}
+
+// ApplyRemoteWitnesses adds the witnesses sent by the peer in its
+// tx_signatures message to the peer's inputs of the transaction.
+func (s *Session) ApplyRemoteWitnesses(tx *wire.MsgTx,
+	witnesses []wire.TxWitness) error {
+
+	var remoteIdx []int
+	for i, in := range s.Inputs() {
+		if in.Shared || s.isLocalSerialID(in.SerialID) {
+			continue
+		}
+
+		remoteIdx = append(remoteIdx, i)
+	}
+
+	if len(remoteIdx) != len(witnesses) {
+		return fmt.Errorf("expected %d witnesses, got %d",
+			len(remoteIdx), len(witnesses))
+	}
+
+	for i, idx := range remoteIdx {
+		tx.TxIn[idx].Witness = witnesses[i]
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package interactivetx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testP2WKH = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+	testP2WSH = append([]byte{0x00, 0x20}, make([]byte, 32)...)
+	testP2PKH = append(
+		append([]byte{0x76, 0xa9, 0x14}, make([]byte, 20)...),
+		0x88, 0xac,
+	)
+
+	testSequence = wire.MaxTxInSequenceNum - 2
+)
+
+// newPrevTx returns a transaction with a single output that can be spent by
+// the construction.
+func newPrevTx(value int64, pkScript []byte, nonce uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: nonce},
+	})
+	tx.AddTxOut(&wire.TxOut{Value: value, PkScript: pkScript})
+
+	return tx
+}
+
+// newTestSessions returns an initiator and an acceptor session sharing the
+// given funding output.
+func newTestSessions(shared *SharedInput) (*Session, *Session) {
+	cfg := Config{
+		ChanID:      lnwire.ChannelID{1},
+		SharedInput: shared,
+		LockTime:    100,
+		DustLimit:   546,
+	}
+
+	initiatorCfg := cfg
+	initiatorCfg.IsInitiator = true
+
+	return NewSession(initiatorCfg), NewSession(cfg)
+}
+
+// TestSessionConstruction asserts that both peers end up with the same
+// transaction after a construction that adds and removes inputs and outputs
+// on both sides.
+func TestSessionConstruction(t *testing.T) {
+	t.Parallel()
+
+	shared := &SharedInput{
+		OutPoint: wire.OutPoint{Hash: chainhash.Hash{9}, Index: 1},
+		PrevOut:  &wire.TxOut{Value: 1_000_000, PkScript: testP2WSH},
+	}
+	alice, bob := newTestSessions(shared)
+
+	// Alice starts by adding the shared input, which Bob relays to his
+	// session.
+	msg, err := alice.AddSharedInput(testSequence)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, msg.SerialID)
+	require.Empty(t, msg.PrevTx)
+	require.NoError(t, bob.ProcessMsg(msg))
+
+	// Bob adds an input of his own.
+	bobPrevTx := newPrevTx(200_000, testP2WKH, 1)
+	bobIn, err := bob.AddInput(bobPrevTx, 0, testSequence)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, bobIn.SerialID)
+	require.NoError(t, alice.ProcessMsg(bobIn))
+
+	// Alice adds the new funding output and an output she removes again.
+	fundingOut, err := alice.AddOutput(&wire.TxOut{
+		Value: 1_150_000, PkScript: testP2WSH,
+	})
+	require.NoError(t, err)
+	require.NoError(t, bob.ProcessMsg(fundingOut))
+
+	tempOut, err := alice.AddOutput(&wire.TxOut{
+		Value: 10_000, PkScript: testP2WKH,
+	})
+	require.NoError(t, err)
+	require.NoError(t, bob.ProcessMsg(tempOut))
+	removeOut, err := alice.RemoveOutput(tempOut.SerialID)
+	require.NoError(t, err)
+	require.NoError(t, bob.ProcessMsg(removeOut))
+
+	// Both sides complete the construction, Alice last.
+	require.NoError(t, alice.ProcessMsg(bob.Complete()))
+	require.False(t, alice.IsComplete())
+	require.NoError(t, bob.ProcessMsg(alice.Complete()))
+	require.True(t, alice.IsComplete())
+	require.True(t, bob.IsComplete())
+
+	aliceTx, err := alice.Tx()
+	require.NoError(t, err)
+	bobTx, err := bob.Tx()
+	require.NoError(t, err)
+	require.Equal(t, aliceTx.TxHash(), bobTx.TxHash())
+
+	// The inputs and outputs are sorted by serial ID.
+	require.Len(t, aliceTx.TxIn, 2)
+	require.Equal(t, shared.OutPoint, aliceTx.TxIn[0].PreviousOutPoint)
+	require.Equal(
+		t, bobPrevTx.TxHash(), aliceTx.TxIn[1].PreviousOutPoint.Hash,
+	)
+	require.Len(t, aliceTx.TxOut, 1)
+	require.EqualValues(t, 100, aliceTx.LockTime)
+
+	// Bob's witness ends up at his input on Alice's side, while the shared
+	// input is left alone.
+	witness := wire.TxWitness{{0x01}, {0x02}}
+	bobTx.TxIn[1].Witness = witness
+	bobWitnesses := bob.LocalWitnesses(bobTx)
+	require.Equal(t, []wire.TxWitness{witness}, bobWitnesses)
+	require.Empty(t, alice.LocalWitnesses(aliceTx))
+
+	require.NoError(t, alice.ApplyRemoteWitnesses(aliceTx, bobWitnesses))
+	require.Equal(t, witness, aliceTx.TxIn[1].Witness)
+	require.Nil(t, aliceTx.TxIn[0].Witness)
+}
+
+// TestSessionValidation asserts that invalid messages of the peer are
+// rejected.
+func TestSessionValidation(t *testing.T) {
+	t.Parallel()
+
+	shared := &SharedInput{
+		OutPoint: wire.OutPoint{Hash: chainhash.Hash{9}, Index: 1},
+		PrevOut:  &wire.TxOut{Value: 1_000_000, PkScript: testP2WSH},
+	}
+
+	addInput := func(serialID uint64, prevTx *wire.MsgTx,
+		sequence uint32) *lnwire.TxAddInput {
+
+		var rawTx bytes.Buffer
+		require.NoError(t, prevTx.Serialize(&rawTx))
+
+		return &lnwire.TxAddInput{
+			SerialID: serialID,
+			PrevTx:   rawTx.Bytes(),
+			Sequence: sequence,
+		}
+	}
+
+	sharedTxid := lnwire.SharedInputTxid{8}
+
+	testCases := []struct {
+		name string
+		msgs []lnwire.Message
+		err  error
+	}{{
+		name: "wrong parity",
+		msgs: []lnwire.Message{
+			addInput(1, newPrevTx(1000, testP2WKH, 0), testSequence),
+		},
+		err: ErrWrongSerialIDParity,
+	}, {
+		name: "duplicate serial id",
+		msgs: []lnwire.Message{
+			addInput(2, newPrevTx(1000, testP2WKH, 0), testSequence),
+			addInput(2, newPrevTx(1000, testP2WKH, 1), testSequence),
+		},
+		err: ErrDuplicateSerialID,
+	}, {
+		name: "duplicate outpoint",
+		msgs: []lnwire.Message{
+			addInput(2, newPrevTx(1000, testP2WKH, 0), testSequence),
+			addInput(4, newPrevTx(1000, testP2WKH, 0), testSequence),
+		},
+		err: ErrDuplicateInput,
+	}, {
+		name: "final sequence",
+		msgs: []lnwire.Message{
+			addInput(
+				2, newPrevTx(1000, testP2WKH, 0),
+				wire.MaxTxInSequenceNum,
+			),
+		},
+		err: ErrInvalidSequence,
+	}, {
+		name: "non segwit input",
+		msgs: []lnwire.Message{
+			addInput(2, newPrevTx(1000, testP2PKH, 0), testSequence),
+		},
+		err: ErrNonSegwitInput,
+	}, {
+		name: "wrong shared input",
+		msgs: []lnwire.Message{
+			&lnwire.TxAddInput{
+				SerialID:        2,
+				PrevTxVout:      1,
+				Sequence:        testSequence,
+				SharedInputTxid: &sharedTxid,
+			},
+		},
+		err: ErrUnexpectedSharedInput,
+	}, {
+		name: "dust output",
+		msgs: []lnwire.Message{
+			&lnwire.TxAddOutput{
+				SerialID: 2,
+				Amount:   100,
+				Script:   testP2WKH,
+			},
+		},
+		err: ErrOutputBelowDust,
+	}, {
+		name: "non segwit output",
+		msgs: []lnwire.Message{
+			&lnwire.TxAddOutput{
+				SerialID: 2,
+				Amount:   10_000,
+				Script:   testP2PKH,
+			},
+		},
+		err: ErrNonSegwitOutput,
+	}, {
+		name: "remove unknown input",
+		msgs: []lnwire.Message{
+			&lnwire.TxRemoveInput{SerialID: 2},
+		},
+		err: ErrUnknownSerialID,
+	}, {
+		name: "remove local output",
+		msgs: []lnwire.Message{
+			&lnwire.TxRemoveOutput{SerialID: 1},
+		},
+		err: ErrWrongSerialIDParity,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, bob := newTestSessions(shared)
+
+			var err error
+			for _, msg := range tc.msgs {
+				err = bob.ProcessMsg(msg)
+				if err != nil {
+					break
+				}
+			}
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+// TestSessionTx asserts that the transaction is only returned once the
+// construction is complete and valid.
+func TestSessionTx(t *testing.T) {
+	t.Parallel()
+
+	shared := &SharedInput{
+		OutPoint: wire.OutPoint{Hash: chainhash.Hash{9}, Index: 1},
+		PrevOut:  &wire.TxOut{Value: 1_000_000, PkScript: testP2WSH},
+	}
+
+	// The transaction can't be created before both peers completed.
+	alice, bob := newTestSessions(shared)
+	_, err := alice.Tx()
+	require.ErrorIs(t, err, ErrNotComplete)
+
+	// The shared input must be part of the transaction.
+	require.NoError(t, bob.ProcessMsg(alice.Complete()))
+	require.NoError(t, alice.ProcessMsg(bob.Complete()))
+	_, err = alice.Tx()
+	require.ErrorIs(t, err, ErrMissingSharedInput)
+
+	// The outputs can't exceed the inputs.
+	alice, bob = newTestSessions(shared)
+	msg, err := alice.AddSharedInput(testSequence)
+	require.NoError(t, err)
+	require.NoError(t, bob.ProcessMsg(msg))
+	out, err := alice.AddOutput(&wire.TxOut{
+		Value: 2_000_000, PkScript: testP2WSH,
+	})
+	require.NoError(t, err)
+	require.NoError(t, bob.ProcessMsg(out))
+	require.NoError(t, bob.ProcessMsg(alice.Complete()))
+	require.NoError(t, alice.ProcessMsg(bob.Complete()))
+	_, err = bob.Tx()
+	require.ErrorIs(t, err, ErrInsufficientInputs)
+
+	// Nothing can be added after the construction completed.
+	_, err = alice.AddOutput(&wire.TxOut{Value: 1000, PkScript: testP2WKH})
+	require.ErrorIs(t, err, ErrSessionComplete)
+}
@@ -199,6 +199,10 @@ type TransactionDetail struct {
 
 	// PreviousOutpoints are the inputs for a transaction.
 	PreviousOutpoints []PreviousOutPoint
+
+	// Mweb holds the MWEB details of the transaction, such as whether it
+	// pegs coins in or out of the extension block.
+	Mweb MwebTxInfo
 }
 
 // TransactionSubscription is an interface which describes an object capable of
@@ -0,0 +1,114 @@
+package lnwallet
+
+import (
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// MwebTxType describes how a wallet transaction relates to the MWEB extension
+// block.
+type MwebTxType uint8
+
+const (
+	// MwebTxNone is a transaction that doesn't touch the extension block.
+	MwebTxNone MwebTxType = iota
+
+	// MwebTxPegIn is a transaction that moves canonical coins into the
+	// extension block.
+	MwebTxPegIn
+
+	// MwebTxPegOut is a transaction that moves MWEB coins out of the
+	// extension block to canonical outputs.
+	MwebTxPegOut
+
+	// MwebTxInternal is a transaction that only moves coins within the
+	// extension block.
+	MwebTxInternal
+)
+
+// String returns a human readable name of the MWEB transaction type.
+func (t MwebTxType) String() string {
+	switch t {
+	case MwebTxNone:
+		return "none"
+
+	case MwebTxPegIn:
+		return "peg-in"
+
+	case MwebTxPegOut:
+		return "peg-out"
+
+	case MwebTxInternal:
+		return "mweb-internal"
+
+	default:
+		return "unknown"
+	}
+}
+
+// MwebTxInfo holds the MWEB related details of a wallet transaction.
+type MwebTxInfo struct {
+	// Type is how the transaction relates to the extension block.
+	Type MwebTxType
+
+	// KernelIDs are the IDs of the MWEB kernels of the transaction.
+	KernelIDs []chainhash.Hash
+
+	// Fee is the fee paid by the MWEB kernels of the transaction.
+	Fee ltcutil.Amount
+}
+
+// ClassifyMwebTx determines how the given transaction relates to the MWEB
+// extension block. A transaction pegging coins both in and out is reported
+// as a peg-out, since that is the leg that makes coins visible on the
+// canonical chain again.
+func ClassifyMwebTx(tx *wire.MsgTx) MwebTxInfo {
+	var info MwebTxInfo
+
+	// The integrating transaction of a block carries all peg-outs of
+	// the block to their canonical outputs.
+	if tx.IsHogEx {
+		info.Type = MwebTxPegOut
+		return info
+	}
+
+	var pegIn, pegOut bool
+	for _, txOut := range tx.TxOut {
+		if txscript.GetScriptClass(txOut.PkScript) ==
+			txscript.WitnessMwebPeginTy {
+
+			pegIn = true
+		}
+	}
+
+	if tx.Mweb == nil || tx.Mweb.TxBody == nil {
+		if pegIn {
+			info.Type = MwebTxPegIn
+		}
+
+		return info
+	}
+
+	for _, kernel := range tx.Mweb.TxBody.Kernels {
+		info.KernelIDs = append(info.KernelIDs, *kernel.Hash())
+		info.Fee += ltcutil.Amount(kernel.Fee)
+
+		pegIn = pegIn || kernel.Pegin > 0
+		pegOut = pegOut || len(kernel.Pegouts) > 0
+	}
+
+	switch {
+	case pegOut:
+		info.Type = MwebTxPegOut
+
+	case pegIn:
+		info.Type = MwebTxPegIn
+
+	default:
+		info.Type = MwebTxInternal
+	}
+
+	return info
+}
@@ -0,0 +1,89 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClassifyMwebTx tests that wallet transactions are classified by how they
+// relate to the MWEB extension block.
+func TestClassifyMwebTx(t *testing.T) {
+	t.Parallel()
+
+	mwebTx := func(kernels ...*wire.MwebKernel) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.Mweb = &wire.MwebTx{
+			TxBody: &wire.MwebTxBody{
+				Kernels: kernels,
+			},
+		}
+
+		return tx
+	}
+
+	pegIn := &wire.MwebKernel{Fee: 1000, Pegin: 100_000}
+	pegOut := &wire.MwebKernel{
+		Fee:     2000,
+		Pegouts: []*wire.TxOut{{Value: 50_000}},
+	}
+	internal := &wire.MwebKernel{Fee: 500}
+
+	testCases := []struct {
+		name      string
+		tx        *wire.MsgTx
+		txType    MwebTxType
+		kernelIDs []chainhash.Hash
+		fee       ltcutil.Amount
+	}{{
+		name:   "canonical",
+		tx:     wire.NewMsgTx(2),
+		txType: MwebTxNone,
+	}, {
+		name:      "peg-in",
+		tx:        mwebTx(pegIn),
+		txType:    MwebTxPegIn,
+		kernelIDs: []chainhash.Hash{*pegIn.Hash()},
+		fee:       1000,
+	}, {
+		name:      "peg-out",
+		tx:        mwebTx(pegOut),
+		txType:    MwebTxPegOut,
+		kernelIDs: []chainhash.Hash{*pegOut.Hash()},
+		fee:       2000,
+	}, {
+		name:      "internal",
+		tx:        mwebTx(internal),
+		txType:    MwebTxInternal,
+		kernelIDs: []chainhash.Hash{*internal.Hash()},
+		fee:       500,
+	}, {
+		name:   "peg-in and peg-out",
+		tx:     mwebTx(pegIn, pegOut),
+		txType: MwebTxPegOut,
+		kernelIDs: []chainhash.Hash{
+			*pegIn.Hash(), *pegOut.Hash(),
+		},
+		fee: 3000,
+	}, {
+		name:   "hogex",
+		tx:     &wire.MsgTx{IsHogEx: true},
+		txType: MwebTxPegOut,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := ClassifyMwebTx(tc.tx)
+			require.Equal(t, tc.txType, info.Type)
+			require.Equal(t, tc.kernelIDs, info.KernelIDs)
+			require.Equal(t, tc.fee, info.Fee)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package lnwallet
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 
@@ -264,10 +265,37 @@ func NewChannelReservation(capacity, localFundingAmt ltcutil.Amount,
 	// Used to cut down on verbosity.
 	defaultDust := wallet.Cfg.DefaultConstraints.DustLimit
 
-	// If we're the responder to a single-funder reservation, then we have
-	// no initial balance in the channel unless the remote party is pushing
-	// some funds to us within the first commitment state.
-	if localFundingAmt == 0 {
+	// In a dual funded channel, both parties start out with the amount
+	// they contributed. The opener pays the commitment fee, just like in
+	// the single funder case.
+	if req.DualFunded {
+		remoteFundingMSat := capacityMSat - localFundingMSat
+		initiator = req.Initiator
+
+		if initiator {
+			ourBalance = localFundingMSat - feeMSat
+			theirBalance = remoteFundingMSat
+		} else {
+			ourBalance = localFundingMSat
+			theirBalance = remoteFundingMSat - feeMSat
+		}
+
+		// Make sure the opener is able to pay the fees.
+		funderBalance := ourBalance
+		if !initiator {
+			funderBalance = theirBalance
+		}
+		if int64(funderBalance) < 0 {
+			return nil, ErrFunderBalanceDust(
+				int64(commitFee), int64(funderBalance),
+				int64(2*defaultDust),
+			)
+		}
+	} else if localFundingAmt == 0 {
+		// If we're the responder to a single-funder reservation, then
+		// we have no initial balance in the channel unless the remote
+		// party is pushing some funds to us within the first
+		// commitment state.
 		ourBalance = req.PushMSat
 		theirBalance = capacityMSat - feeMSat - req.PushMSat
 		initiator = false
@@ -340,8 +368,12 @@ func NewChannelReservation(capacity, localFundingAmt ltcutil.Amount,
 
 	// If either of the balances are zero at this point, or we have a
 	// non-zero push amt (there's no pushing for dual funder), then this is
-	// a single-funder channel.
-	if ourBalance == 0 || theirBalance == 0 || req.PushMSat != 0 {
+	// a single-funder channel. Channels opened with the dual funding
+	// protocol keep the single funder semantics, as the opener pays the
+	// fees of the commitment transactions.
+	if req.DualFunded || ourBalance == 0 || theirBalance == 0 ||
+		req.PushMSat != 0 {
+
 		// Both the tweakless type and the anchor type is tweakless,
 		// hence set the bit.
 		if req.CommitType.HasStaticRemoteKey() {
@@ -404,6 +436,10 @@ func NewChannelReservation(capacity, localFundingAmt ltcutil.Amount,
 		chanType |= channeldb.ZeroConfBit
 	}
 
+	if req.DualFunded {
+		chanType |= channeldb.InteractiveFundingBit
+	}
+
 	if req.OptionScidAlias {
 		chanType |= channeldb.ScidAliasChanBit
 	}
@@ -610,6 +646,58 @@ func (r *ChannelReservation) ProcessPsbt() error {
 	return <-errChan
 }
 
+// IsInteractive returns true if the funding transaction of this reservation is
+// constructed interactively with the remote party.
+func (r *ChannelReservation) IsInteractive() bool {
+	_, ok := r.fundingIntent.(*chanfunding.InteractiveIntent)
+	return ok
+}
+
+// AddRemoteFunding adds the amount the remote party contributes to a dual
+// funded channel we're opening. The opener only learns about the amount once
+// the remote party accepted the channel, so the capacity and the balances of
+// the initial commitments are updated accordingly.
+func (r *ChannelReservation) AddRemoteFunding(amt ltcutil.Amount) error {
+	r.Lock()
+	defer r.Unlock()
+
+	intent, ok := r.fundingIntent.(*chanfunding.InteractiveIntent)
+	if !ok {
+		return fmt.Errorf("reservation isn't dual funded")
+	}
+
+	if r.partialState.Capacity != intent.LocalFundingAmt() {
+		return fmt.Errorf("remote funding already added")
+	}
+
+	amtMSat := lnwire.NewMSatFromSatoshis(amt)
+
+	r.partialState.Capacity += amt
+	r.partialState.LocalCommitment.RemoteBalance += amtMSat
+	r.partialState.RemoteCommitment.RemoteBalance += amtMSat
+	r.partialState.InitialRemoteBalance += amtMSat
+	r.theirContribution.FundingAmount += amt
+
+	intent.AddRemoteFunding(amt)
+
+	return nil
+}
+
+// ProcessInteractiveTx continues the funding flow of a dual funded channel
+// once its funding transaction has been constructed interactively. Our
+// signature for the remote commitment transaction is available afterwards.
+func (r *ChannelReservation) ProcessInteractiveTx(fundingTx *wire.MsgTx) error {
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &interactiveTxMsg{
+		pendingFundingID: r.reservationID,
+		fundingTx:        fundingTx,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
 // RemoteCanceled informs the PSBT funding state machine that the remote peer
 // has canceled the pending reservation, likely due to a timeout.
 func (r *ChannelReservation) RemoteCanceled() {
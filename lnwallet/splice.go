@@ -0,0 +1,397 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/input"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+var (
+	// ErrSpliceUnsupported is returned when a splice is attempted for a
+	// channel type that can't be spliced.
+	ErrSpliceUnsupported = errors.New("splicing is not supported for " +
+		"this channel type")
+
+	// ErrSpliceNotClean is returned when a splice is attempted while the
+	// channel still has HTLCs or unacked updates.
+	ErrSpliceNotClean = errors.New("channel must be clean to be spliced")
+
+	// ErrInvalidSpliceSig is returned when the signature of the remote
+	// peer for a splice commitment or the splice transaction is invalid.
+	ErrInvalidSpliceSig = errors.New("invalid splice signature")
+)
+
+// SpliceParams describes the new funding output of a spliced channel, and how
+// the balances of both peers change.
+type SpliceParams struct {
+	// FundingOutpoint is the new funding outpoint of the channel.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel after the splice.
+	Capacity ltcutil.Amount
+
+	// LocalContribution is the amount our balance changes by. It is
+	// negative if we splice funds out of the channel.
+	LocalContribution ltcutil.Amount
+
+	// RemoteContribution is the amount the balance of the remote peer
+	// changes by.
+	RemoteContribution ltcutil.Amount
+}
+
+// CanSplice returns an error if the channel can't currently be spliced.
+// Splicing is only supported for clean, confirmed channels that use a p2wsh
+// funding output and don't rely on an scid alias.
+func (lc *LightningChannel) CanSplice() error {
+	chanType := lc.channelState.ChanType
+	switch {
+	case chanType.IsTaproot(), chanType.HasZeroConf(),
+		chanType.HasScidAliasChan(), chanType.HasScidAliasFeature(),
+		chanType.IsFrozen(), chanType.HasLeaseExpiration():
+
+		return ErrSpliceUnsupported
+
+	case lc.IsPending():
+		return fmt.Errorf("channel is pending: %w",
+			ErrSpliceUnsupported)
+	}
+
+	if !lc.IsChannelClean() {
+		return ErrSpliceNotClean
+	}
+
+	return nil
+}
+
+// SpliceFundingScript returns the pkScript of the funding output. As the
+// multi-sig keys of the channel don't change, the new funding output of a
+// splice uses the same script.
+func (lc *LightningChannel) SpliceFundingScript() []byte {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return append([]byte(nil), lc.fundingOutput.PkScript...)
+}
+
+// ValidateSplice checks that both peers can afford the passed contributions,
+// and that the channel reserves are respected after the splice.
+func (lc *LightningChannel) ValidateSplice(params *SpliceParams) error {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	expected := lc.channelState.Capacity + params.LocalContribution +
+		params.RemoteContribution
+	if params.Capacity != expected {
+		return fmt.Errorf("splice capacity %v doesn't match "+
+			"contributions, expected %v", params.Capacity, expected)
+	}
+
+	ourBalance, theirBalance, err := spliceBalances(
+		&lc.channelState.LocalCommitment, lc.channelState.IsInitiator,
+		params,
+	)
+	if err != nil {
+		return err
+	}
+
+	ourReserve := lc.channelState.LocalChanCfg.ChanReserve
+	theirReserve := lc.channelState.RemoteChanCfg.ChanReserve
+	if params.LocalContribution < 0 &&
+		ourBalance.ToSatoshis() < ourReserve {
+
+		return fmt.Errorf("%w: local balance %v below reserve %v",
+			ErrBelowChanReserve, ourBalance, ourReserve)
+	}
+	if params.RemoteContribution < 0 &&
+		theirBalance.ToSatoshis() < theirReserve {
+
+		return fmt.Errorf("%w: remote balance %v below reserve %v",
+			ErrBelowChanReserve, theirBalance, theirReserve)
+	}
+
+	return nil
+}
+
+// spliceBalances returns the balances of both peers before subtracting the
+// commitment fee, after applying the contributions of the splice to the given
+// commitment.
+func spliceBalances(commit *channeldb.ChannelCommitment, isInitiator bool,
+	params *SpliceParams) (lnwire.MilliSatoshi, lnwire.MilliSatoshi,
+	error) {
+
+	ourBalance := int64(commit.LocalBalance)
+	theirBalance := int64(commit.RemoteBalance)
+
+	// The stored balances already have the commitment fee subtracted from
+	// the initiator, so we'll add it back in.
+	commitFee := int64(lnwire.NewMSatFromSatoshis(commit.CommitFee))
+	if isInitiator {
+		ourBalance += commitFee
+	} else {
+		theirBalance += commitFee
+	}
+
+	ourBalance += int64(lnwire.NewMSatFromSatoshis(
+		params.LocalContribution,
+	))
+	theirBalance += int64(lnwire.NewMSatFromSatoshis(
+		params.RemoteContribution,
+	))
+	if ourBalance < 0 || theirBalance < 0 {
+		return 0, 0, fmt.Errorf("splice contribution exceeds balance")
+	}
+
+	return lnwire.MilliSatoshi(ourBalance),
+		lnwire.MilliSatoshi(theirBalance), nil
+}
+
+// splicedChannel returns a copy of the fields of the channel state that are
+// needed to build commitments, with the funding outpoint and capacity
+// replaced by those of the splice.
+func (lc *LightningChannel) splicedChannel(
+	params *SpliceParams) *channeldb.OpenChannel {
+
+	state := lc.channelState
+
+	return &channeldb.OpenChannel{
+		ChanType:        state.ChanType,
+		ChainHash:       state.ChainHash,
+		FundingOutpoint: params.FundingOutpoint,
+		IsInitiator:     state.IsInitiator,
+		Capacity:        params.Capacity,
+		LocalChanCfg:    state.LocalChanCfg,
+		RemoteChanCfg:   state.RemoteChanCfg,
+		ThawHeight:      state.ThawHeight,
+	}
+}
+
+// spliceCommitment rebuilds the current local or remote commitment so it
+// spends the new funding output of the splice. The commitment keeps its
+// height, so the same revocation secret revokes both versions of it.
+func (lc *LightningChannel) spliceCommitment(params *SpliceParams,
+	remote bool) (*channeldb.ChannelCommitment, error) {
+
+	state := lc.channelState
+
+	current := &state.LocalCommitment
+	if remote {
+		current = &state.RemoteCommitment
+	}
+
+	ourBalance, theirBalance, err := spliceBalances(
+		current, state.IsInitiator, params,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyRing *CommitmentKeyRing
+	if remote {
+		keyRing = DeriveCommitmentKeys(
+			state.RemoteCurrentRevocation, false, state.ChanType,
+			&state.LocalChanCfg, &state.RemoteChanCfg,
+		)
+	} else {
+		commitSecret, err := state.RevocationProducer.AtIndex(
+			current.CommitHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+		commitPoint := input.ComputeCommitmentPoint(commitSecret[:])
+
+		keyRing = DeriveCommitmentKeys(
+			commitPoint, true, state.ChanType,
+			&state.LocalChanCfg, &state.RemoteChanCfg,
+		)
+	}
+
+	cb := NewCommitmentBuilder(lc.splicedChannel(params))
+	commitTx, err := cb.createUnsignedCommitmentTx(
+		ourBalance, theirBalance, !remote,
+		chainfee.SatPerKWeight(current.FeePerKw), current.CommitHeight,
+		&htlcView{}, keyRing,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &channeldb.ChannelCommitment{
+		CommitHeight:    current.CommitHeight,
+		LocalLogIndex:   current.LocalLogIndex,
+		LocalHtlcIndex:  current.LocalHtlcIndex,
+		RemoteLogIndex:  current.RemoteLogIndex,
+		RemoteHtlcIndex: current.RemoteHtlcIndex,
+		LocalBalance:    commitTx.ourBalance,
+		RemoteBalance:   commitTx.theirBalance,
+		CommitFee:       commitTx.fee,
+		FeePerKw:        current.FeePerKw,
+		CommitTx:        commitTx.txn,
+	}, nil
+}
+
+// spliceSignDesc returns a sign descriptor for the funding output of the
+// splice.
+func (lc *LightningChannel) spliceSignDesc(
+	params *SpliceParams) input.SignDescriptor {
+
+	signDesc := *lc.signDesc
+	signDesc.Output = &wire.TxOut{
+		PkScript: lc.fundingOutput.PkScript,
+		Value:    int64(params.Capacity),
+	}
+
+	return signDesc
+}
+
+// SignSpliceCommitment creates the current commitment of the remote peer
+// spending the new funding output of the splice, and signs it. The unsigned
+// commitment is returned along with the signature.
+func (lc *LightningChannel) SignSpliceCommitment(params *SpliceParams) (
+	lnwire.Sig, *channeldb.ChannelCommitment, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	commit, err := lc.spliceCommitment(params, true)
+	if err != nil {
+		return lnwire.Sig{}, nil, err
+	}
+
+	signDesc := lc.spliceSignDesc(params)
+	signDesc.SigHashes = input.NewTxSigHashesV0Only(commit.CommitTx)
+	rawSig, err := lc.Signer.SignOutputRaw(commit.CommitTx, &signDesc)
+	if err != nil {
+		return lnwire.Sig{}, nil, err
+	}
+
+	sig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		return lnwire.Sig{}, nil, err
+	}
+
+	return sig, commit, nil
+}
+
+// ReceiveSpliceCommitment creates our current commitment spending the new
+// funding output of the splice, and verifies the signature of the remote
+// peer for it. The commitment is returned with the signature included.
+func (lc *LightningChannel) ReceiveSpliceCommitment(params *SpliceParams,
+	sig lnwire.Sig) (*channeldb.ChannelCommitment, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	commit, err := lc.spliceCommitment(params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	multiSigScript := lc.signDesc.WitnessScript
+	prevFetcher := txscript.NewCannedPrevOutputFetcher(
+		multiSigScript, int64(params.Capacity),
+	)
+	hashCache := txscript.NewTxSigHashes(commit.CommitTx, prevFetcher)
+	sigHash, err := txscript.CalcWitnessSigHash(
+		multiSigScript, hashCache, txscript.SigHashAll,
+		commit.CommitTx, 0, int64(params.Capacity),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cSig, err := sig.ToSignature()
+	if err != nil {
+		return nil, err
+	}
+	verifyKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey
+	if !cSig.Verify(sigHash, verifyKey) {
+		return nil, fmt.Errorf("%w: commitment at height %v",
+			ErrInvalidSpliceSig, commit.CommitHeight)
+	}
+
+	commit.CommitSig = sig.ToSignatureBytes()
+
+	return commit, nil
+}
+
+// SignSpliceInput signs the input of the splice transaction that spends the
+// current funding output of the channel.
+func (lc *LightningChannel) SignSpliceInput(spliceTx *wire.MsgTx,
+	inputIndex int) (input.Signature, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	signDesc := *lc.signDesc
+	signDesc.InputIndex = inputIndex
+	signDesc.SigHashes = input.NewTxSigHashesV0Only(spliceTx)
+
+	return lc.Signer.SignOutputRaw(spliceTx, &signDesc)
+}
+
+// CompleteSpliceInput verifies the signature of the remote peer for the input
+// of the splice transaction spending the current funding output, and sets
+// the witness of the input using both signatures.
+func (lc *LightningChannel) CompleteSpliceInput(spliceTx *wire.MsgTx,
+	inputIndex int, localSig []byte, remoteSig lnwire.Sig) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	if inputIndex < 0 || inputIndex >= len(spliceTx.TxIn) {
+		return fmt.Errorf("invalid splice input index %v", inputIndex)
+	}
+
+	capacity := int64(lc.channelState.Capacity)
+	multiSigScript := lc.signDesc.WitnessScript
+	hashCache := input.NewTxSigHashesV0Only(spliceTx)
+	sigHash, err := txscript.CalcWitnessSigHash(
+		multiSigScript, hashCache, txscript.SigHashAll, spliceTx,
+		inputIndex, capacity,
+	)
+	if err != nil {
+		return err
+	}
+
+	theirSig, err := remoteSig.ToSignature()
+	if err != nil {
+		return err
+	}
+	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey
+	if !theirSig.Verify(sigHash, theirKey) {
+		return fmt.Errorf("%w: splice input", ErrInvalidSpliceSig)
+	}
+
+	ourSig, err := ecdsa.ParseDERSignature(localSig)
+	if err != nil {
+		return err
+	}
+	ourKey := lc.channelState.LocalChanCfg.MultiSigKey.PubKey
+
+	spliceTx.TxIn[inputIndex].Witness = input.SpendMultiSig(
+		multiSigScript, ourKey.SerializeCompressed(), ourSig,
+		theirKey.SerializeCompressed(), theirSig,
+	)
+
+	return nil
+}
+
+// FundSplice selects confirmed coins of the wallet that add the passed amount
+// to a channel that is spliced. The fee for spending the coins, the new
+// funding output and the change is paid at the given fee rate. The coins are
+// locked until the returned intent is canceled.
+func (l *LightningWallet) FundSplice(amt ltcutil.Amount,
+	feeRate chainfee.SatPerKWeight) (*chanfunding.FullIntent, error) {
+
+	return l.FundContribution(amt, feeRate)
+}
@@ -0,0 +1,181 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSpliceCommitments asserts that both peers create the same commitments
+// spending the funding output of a splice, and accept each other's
+// signatures for them.
+func TestSpliceCommitments(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	require.NoError(t, aliceChannel.CanSplice())
+	require.NoError(t, bobChannel.CanSplice())
+
+	// Alice splices funds into her side of the channel.
+	const spliceAmt = ltcutil.Amount(100_000)
+	capacity := aliceChannel.Capacity + spliceAmt
+	fundingPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 1}
+	aliceParams := &SpliceParams{
+		FundingOutpoint:   fundingPoint,
+		Capacity:          capacity,
+		LocalContribution: spliceAmt,
+	}
+	bobParams := &SpliceParams{
+		FundingOutpoint:    fundingPoint,
+		Capacity:           capacity,
+		RemoteContribution: spliceAmt,
+	}
+	require.NoError(t, aliceChannel.ValidateSplice(aliceParams))
+	require.NoError(t, bobChannel.ValidateSplice(bobParams))
+
+	aliceSig, bobRemote, err := aliceChannel.SignSpliceCommitment(
+		aliceParams,
+	)
+	require.NoError(t, err)
+	bobLocal, err := bobChannel.ReceiveSpliceCommitment(
+		bobParams, aliceSig,
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t, bobRemote.CommitTx.TxHash(), bobLocal.CommitTx.TxHash(),
+	)
+
+	bobSig, aliceRemote, err := bobChannel.SignSpliceCommitment(bobParams)
+	require.NoError(t, err)
+	aliceLocal, err := aliceChannel.ReceiveSpliceCommitment(
+		aliceParams, bobSig,
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t, aliceRemote.CommitTx.TxHash(), aliceLocal.CommitTx.TxHash(),
+	)
+
+	// The commitments spend the new funding output, keep their height
+	// and credit the spliced funds to Alice.
+	oldCommit := aliceChannel.State().LocalCommitment
+	require.Equal(
+		t, fundingPoint, aliceLocal.CommitTx.TxIn[0].PreviousOutPoint,
+	)
+	require.Equal(t, oldCommit.CommitHeight, aliceLocal.CommitHeight)
+	require.Equal(
+		t, oldCommit.LocalBalance+lnwire.NewMSatFromSatoshis(spliceAmt),
+		aliceLocal.LocalBalance,
+	)
+	require.Equal(t, oldCommit.RemoteBalance, aliceLocal.RemoteBalance)
+
+	// A signature for different splice parameters is rejected.
+	otherParams := *bobParams
+	otherParams.Capacity++
+	otherParams.RemoteContribution++
+	_, err = bobChannel.ReceiveSpliceCommitment(&otherParams, aliceSig)
+	require.ErrorIs(t, err, ErrInvalidSpliceSig)
+}
+
+// TestSpliceInput asserts that the input of the splice transaction spending
+// the current funding output can be signed by both peers.
+func TestSpliceInput(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	fundingOut := aliceChannel.FundingTxOut()
+	fundingScript := aliceChannel.SpliceFundingScript()
+	require.Equal(t, fundingOut.PkScript, fundingScript)
+
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{2}},
+	})
+	spliceTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *aliceChannel.ChannelPoint(),
+	})
+	spliceTx.AddTxOut(&wire.TxOut{
+		Value:    fundingOut.Value - 10_000,
+		PkScript: fundingScript,
+	})
+
+	aliceSig, err := aliceChannel.SignSpliceInput(spliceTx, 1)
+	require.NoError(t, err)
+	bobSig, err := bobChannel.SignSpliceInput(spliceTx, 1)
+	require.NoError(t, err)
+
+	bobWireSig, err := lnwire.NewSigFromSignature(bobSig)
+	require.NoError(t, err)
+
+	// The signature of the remote peer must be valid for the input.
+	err = aliceChannel.CompleteSpliceInput(
+		spliceTx, 0, aliceSig.Serialize(), bobWireSig,
+	)
+	require.ErrorIs(t, err, ErrInvalidSpliceSig)
+
+	err = aliceChannel.CompleteSpliceInput(
+		spliceTx, 1, aliceSig.Serialize(), bobWireSig,
+	)
+	require.NoError(t, err)
+
+	prevFetcher := txscript.NewCannedPrevOutputFetcher(
+		fundingOut.PkScript, fundingOut.Value,
+	)
+	hashCache := txscript.NewTxSigHashes(spliceTx, prevFetcher)
+	vm, err := txscript.NewEngine(
+		fundingOut.PkScript, spliceTx, 1, txscript.StandardVerifyFlags,
+		nil, hashCache, fundingOut.Value, prevFetcher,
+	)
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute())
+}
+
+// TestValidateSplice asserts that splices exceeding a balance or the channel
+// reserve are rejected.
+func TestValidateSplice(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, _, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	state := aliceChannel.State()
+	balance := state.LocalCommitment.LocalBalance.ToSatoshis() +
+		state.LocalCommitment.CommitFee
+
+	// The capacity must match the contributions.
+	err = aliceChannel.ValidateSplice(&SpliceParams{
+		Capacity:          state.Capacity,
+		LocalContribution: -1000,
+	})
+	require.Error(t, err)
+
+	// Alice can't splice out more than her balance.
+	err = aliceChannel.ValidateSplice(&SpliceParams{
+		Capacity:          state.Capacity - balance - 1,
+		LocalContribution: -balance - 1,
+	})
+	require.Error(t, err)
+
+	// Alice must keep her reserve.
+	spliceOut := balance - state.LocalChanCfg.ChanReserve + 1
+	err = aliceChannel.ValidateSplice(&SpliceParams{
+		Capacity:          state.Capacity - spliceOut,
+		LocalContribution: -spliceOut,
+	})
+	require.ErrorIs(t, err, ErrBelowChanReserve)
+}
@@ -187,6 +187,15 @@ type InitFundingReserveMsg struct {
 	// negotiated.
 	ScidAliasFeature bool
 
+	// DualFunded is true if the channel is opened with the dual funding
+	// protocol. Both parties may contribute funds to such a channel, and
+	// the funding transaction is constructed interactively.
+	DualFunded bool
+
+	// Initiator denotes whether we're the opener of a dual funded channel.
+	// It is ignored if DualFunded isn't set.
+	Initiator bool
+
 	// Memo is any arbitrary information we wish to store locally about the
 	// channel that will be useful to our future selves.
 	Memo []byte
@@ -243,6 +252,20 @@ type continueContributionMsg struct {
 	err chan error
 }
 
+// interactiveTxMsg represents a message that signals that the funding
+// transaction of a dual funded channel has been constructed interactively, so
+// the funding flow can continue with the commitment transactions.
+type interactiveTxMsg struct {
+	pendingFundingID uint64
+
+	// fundingTx is the unsigned funding transaction both parties agreed
+	// on.
+	fundingTx *wire.MsgTx
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
 // addSingleContributionMsg represents a message executing the second phase of
 // a single funder channel reservation workflow. This messages carries the
 // counterparty's "contribution" to the payment channel. As this message is
@@ -615,6 +638,8 @@ out:
 				l.handleContributionMsg(msg)
 			case *continueContributionMsg:
 				l.handleChanPointReady(msg)
+			case *interactiveTxMsg:
+				l.handleInteractiveTx(msg)
 			case *addSingleFunderSigsMsg:
 				l.handleSingleFunderSigs(msg)
 			case *addCounterPartySigsMsg:
@@ -842,8 +867,16 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 		}
 		req.ChanFunder = chanfunding.NewWalletAssembler(cfg)
 	} else {
-		_, isPsbtFunder := req.ChanFunder.(*chanfunding.PsbtAssembler)
-		enforceNewReservedValue = !isPsbtFunder
+		// The inputs of a dual funded channel are selected by the
+		// caller and only become part of the funding transaction once
+		// it has been constructed interactively, so there's nothing
+		// the intent could tell us about them.
+		switch req.ChanFunder.(type) {
+		case *chanfunding.PsbtAssembler,
+			*chanfunding.InteractiveAssembler:
+
+			enforceNewReservedValue = false
+		}
 	}
 
 	localFundingAmt := req.LocalFundingAmt
@@ -1625,6 +1658,19 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 
 		walletLog.Tracef("Funding tx for ChannelPoint(%v) "+
 			"generated: %v", chanPoint, spew.Sdump(fundingTx))
+
+	// The funding transaction of a dual funded channel is constructed
+	// interactively with the remote party, which requires the funding
+	// output to be known. So we bind the keys now and exit early, the
+	// funding flow continues once the transaction is complete.
+	case *chanfunding.InteractiveIntent:
+		fundingIntent.BindKeys(
+			&ourContribution.MultiSigKey,
+			theirContribution.MultiSigKey.PubKey,
+		)
+
+		req.err <- nil
+		return
 	}
 
 	// If we landed here and didn't exit early, it means we already have
@@ -1823,13 +1869,24 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	// With both commitment transactions constructed, generate the state
 	// obfuscator then use it to encode the current state number within
 	// both commitment transactions.
+	//
+	// NOTE: The responder of a dual funded channel arrives here as well,
+	// so we need to make sure the initiator's key comes first.
 	var stateObfuscator [StateHintSize]byte
-	if chanState.ChanType.IsSingleFunder() {
+	switch {
+	case chanState.ChanType.IsSingleFunder() && chanState.IsInitiator:
 		stateObfuscator = DeriveStateHintObfuscator(
 			ourContribution.PaymentBasePoint.PubKey,
 			theirContribution.PaymentBasePoint.PubKey,
 		)
-	} else {
+
+	case chanState.ChanType.IsSingleFunder():
+		stateObfuscator = DeriveStateHintObfuscator(
+			theirContribution.PaymentBasePoint.PubKey,
+			ourContribution.PaymentBasePoint.PubKey,
+		)
+
+	default:
 		ourSer := ourContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		theirSer := theirContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		switch bytes.Compare(ourSer, theirSer) {
@@ -1894,6 +1951,73 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	req.err <- nil
 }
 
+// handleInteractiveTx records the interactively constructed funding
+// transaction of a dual funded channel, and continues the funding flow by
+// creating both commitment transactions and signing the remote one.
+func (l *LightningWallet) handleInteractiveTx(req *interactiveTxMsg) {
+	l.limboMtx.Lock()
+	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.Unlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		return
+	}
+
+	pendingReservation.Lock()
+	intent, ok := pendingReservation.fundingIntent.(*chanfunding.InteractiveIntent)
+	if !ok {
+		pendingReservation.Unlock()
+		req.err <- fmt.Errorf("reservation has no interactive "+
+			"funding intent: %T", pendingReservation.fundingIntent)
+		return
+	}
+
+	_, fundingOutput, err := intent.FundingOutput()
+	if err != nil {
+		pendingReservation.Unlock()
+		req.err <- fmt.Errorf("unable to obtain funding "+
+			"output: %w", err)
+		return
+	}
+
+	// Locate the funding output within the transaction, which gives us
+	// the channel point.
+	outputIndex := -1
+	for i, txOut := range req.fundingTx.TxOut {
+		if txOut.Value == fundingOutput.Value &&
+			bytes.Equal(txOut.PkScript, fundingOutput.PkScript) {
+
+			outputIndex = i
+			break
+		}
+	}
+	if outputIndex == -1 {
+		pendingReservation.Unlock()
+		req.err <- fmt.Errorf("funding output not found in " +
+			"interactive tx")
+		return
+	}
+
+	chanPoint := wire.OutPoint{
+		Hash:  req.fundingTx.TxHash(),
+		Index: uint32(outputIndex),
+	}
+	intent.SetChanPoint(chanPoint)
+
+	pendingReservation.fundingTx = req.fundingTx
+	pendingReservation.partialState.FundingOutpoint = chanPoint
+	pendingReservation.Unlock()
+
+	walletLog.Tracef("Interactive funding tx for ChannelPoint(%v) "+
+		"constructed: %v", chanPoint, spew.Sdump(req.fundingTx))
+
+	l.handleChanPointReady(&continueContributionMsg{
+		pendingFundingID: req.pendingFundingID,
+		err:              req.err,
+	})
+}
+
 // handleSingleContribution is called as the second step to a single funder
 // workflow to which we are the responder. It simply saves the remote peer's
 // contribution to the channel, as solely the remote peer will contribute any
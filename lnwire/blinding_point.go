@@ -0,0 +1,65 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+)
+
+const (
+	// BlindingPointRecordType is the type used to encode the blinding
+	// point of an HTLC that is part of a blinded route.
+	BlindingPointRecordType tlv.Type = 0
+)
+
+// BlindingPoint is the ephemeral key that a node inside a blinded route uses
+// to decrypt the data that the recipient of the payment left for it.
+type BlindingPoint btcec.PublicKey
+
+// Record returns a TLV record that can be used to encode/decode the blinding
+// point from a given TLV stream.
+func (b *BlindingPoint) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		BlindingPointRecordType, b, btcec.PubKeyBytesLenCompressed,
+		blindingPointEncoder, blindingPointDecoder,
+	)
+}
+
+// blindingPointEncoder is a custom TLV encoder for the BlindingPoint type.
+func blindingPointEncoder(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*BlindingPoint); ok {
+		pubKey := (*btcec.PublicKey)(v)
+		_, err := w.Write(pubKey.SerializeCompressed())
+
+		return err
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.BlindingPoint")
+}
+
+// blindingPointDecoder is a custom TLV decoder for the BlindingPoint record.
+func blindingPointDecoder(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*BlindingPoint); ok &&
+		l == btcec.PubKeyBytesLenCompressed {
+
+		var keyBytes [btcec.PubKeyBytesLenCompressed]byte
+		if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+			return err
+		}
+
+		pubKey, err := btcec.ParsePubKey(keyBytes[:])
+		if err != nil {
+			return err
+		}
+		*v = BlindingPoint(*pubKey)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.BlindingPoint", l, btcec.PubKeyBytesLenCompressed,
+	)
+}
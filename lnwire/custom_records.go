@@ -0,0 +1,100 @@
+package lnwire
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ltcsuite/lnd/tlv"
+)
+
+const (
+	// MinCustomRecordsTlvType is the minimum custom records TLV type as
+	// defined in BOLT 01.
+	MinCustomRecordsTlvType = 65536
+)
+
+// CustomRecords stores a set of custom key/value pairs. Map keys are TLV types
+// which must be greater than or equal to MinCustomRecordsTlvType.
+type CustomRecords map[uint64][]byte
+
+// Validate checks that all custom records are in the custom type range.
+func (c CustomRecords) Validate() error {
+	for key := range c {
+		if key < MinCustomRecordsTlvType {
+			return fmt.Errorf("custom records entry with TLV type "+
+				"below min: %d", MinCustomRecordsTlvType)
+		}
+	}
+
+	return nil
+}
+
+// Copy returns a deep copy of the custom records.
+func (c CustomRecords) Copy() CustomRecords {
+	if c == nil {
+		return nil
+	}
+
+	customRecords := make(CustomRecords, len(c))
+	for key, value := range c {
+		customRecords[key] = append([]byte(nil), value...)
+	}
+
+	return customRecords
+}
+
+// RecordProducers returns a slice of record producers for the custom records,
+// sorted by their TLV type.
+func (c CustomRecords) RecordProducers() []tlv.RecordProducer {
+	keys := make([]uint64, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	producers := make([]tlv.RecordProducer, 0, len(keys))
+	for _, key := range keys {
+		producers = append(producers, &customRecordProducer{
+			typ:   tlv.Type(key),
+			value: c[key],
+		})
+	}
+
+	return producers
+}
+
+// ParseCustomRecords extracts all records in the custom type range from the
+// given type map, which is the result of decoding a TLV stream.
+func ParseCustomRecords(typeMap tlv.TypeMap) CustomRecords {
+	var customRecords CustomRecords
+	for typ, value := range typeMap {
+		// Known records are decoded into their proper types and don't
+		// carry any raw bytes in the type map.
+		if typ < MinCustomRecordsTlvType || value == nil {
+			continue
+		}
+
+		if customRecords == nil {
+			customRecords = make(CustomRecords)
+		}
+		customRecords[uint64(typ)] = value
+	}
+
+	return customRecords
+}
+
+// customRecordProducer is a tlv.RecordProducer for a single opaque custom
+// record.
+type customRecordProducer struct {
+	typ   tlv.Type
+	value []byte
+}
+
+// Record returns the tlv record for the custom record.
+//
+// NOTE: This is part of the tlv.RecordProducer interface.
+func (c *customRecordProducer) Record() tlv.Record {
+	return tlv.MakePrimitiveRecord(c.typ, &c.value)
+}
@@ -0,0 +1,61 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// DynAck is sent by the responder of a dynamic commitment negotiation to
+// accept all of the parameters proposed in the initiator's DynPropose. Once
+// it has been sent, both sides switch to the new parameters for all future
+// commitments.
+type DynAck struct {
+	// ChanID is the channel whose parameters are being changed.
+	ChanID ChannelID
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure DynAck implements the lnwire.Message
+// interface.
+var _ Message = (*DynAck)(nil)
+
+// Decode deserializes a serialized DynAck message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynAck) Decode(r io.Reader, _ uint32) error {
+	return ReadElements(r,
+		&d.ChanID,
+		&d.ExtraData,
+	)
+}
+
+// Encode serializes the target DynAck into the passed io.Writer observing the
+// protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynAck) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, d.ChanID); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, d.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynAck) MsgType() MessageType {
+	return MsgDynAck
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (d *DynAck) TargetChanID() ChannelID {
+	return d.ChanID
+}
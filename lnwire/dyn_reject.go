@@ -0,0 +1,78 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// DynReject is sent by the responder of a dynamic commitment negotiation to
+// reject the parameters proposed in the initiator's DynPropose. The channel
+// continues with its current parameters afterwards.
+type DynReject struct {
+	// ChanID is the channel whose parameters were proposed to be changed.
+	ChanID ChannelID
+
+	// UpdateRejections is a bit vector that marks which of the proposed
+	// parameters were rejected. The bit of a parameter matches the TLV
+	// type it was proposed with in the DynPropose message.
+	UpdateRejections RawFeatureVector
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure DynReject implements the lnwire.Message
+// interface.
+var _ Message = (*DynReject)(nil)
+
+// Decode deserializes a serialized DynReject message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynReject) Decode(r io.Reader, _ uint32) error {
+	var rejections *RawFeatureVector
+	err := ReadElements(r,
+		&d.ChanID,
+		&rejections,
+		&d.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	d.UpdateRejections = *rejections
+
+	return nil
+}
+
+// Encode serializes the target DynReject into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynReject) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, d.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteRawFeatureVector(w, &d.UpdateRejections); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, d.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynReject) MsgType() MessageType {
+	return MsgDynReject
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (d *DynReject) TargetChanID() ChannelID {
+	return d.ChanID
+}
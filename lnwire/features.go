@@ -139,6 +139,14 @@ const (
 	// transactions, which also imply anchor commitments.
 	AnchorsZeroFeeHtlcTxOptional FeatureBit = 23
 
+	// RouteBlindingRequired is a required feature bit that signals that
+	// the node supports blinded payments.
+	RouteBlindingRequired FeatureBit = 24
+
+	// RouteBlindingOptional is an optional feature bit that signals that
+	// the node supports blinded payments.
+	RouteBlindingOptional FeatureBit = 25
+
 	// ShutdownAnySegwitRequired is an required feature bit that signals
 	// that the sender is able to properly handle/parse segwit witness
 	// programs up to version 16. This enables utilization of Taproot
@@ -151,6 +159,26 @@ const (
 	// addresses for cooperative closure addresses.
 	ShutdownAnySegwitOptional FeatureBit = 27
 
+	// DualFundRequired is a required feature bit that signals that the
+	// node requires support for opening channels with the dual funding
+	// protocol, in which both peers may contribute funds.
+	DualFundRequired FeatureBit = 28
+
+	// DualFundOptional is an optional feature bit that signals that the
+	// node supports opening channels with the dual funding protocol, in
+	// which both peers may contribute funds.
+	DualFundOptional FeatureBit = 29
+
+	// QuiescenceRequired is a required feature bit that signals that the
+	// node requires support for the quiescence protocol, which allows
+	// pausing all updates of a channel in a coordinated way.
+	QuiescenceRequired FeatureBit = 34
+
+	// QuiescenceOptional is an optional feature bit that signals that the
+	// node supports the quiescence protocol, which allows pausing all
+	// updates of a channel in a coordinated way.
+	QuiescenceOptional FeatureBit = 35
+
 	// AMPRequired is a required feature bit that signals that the receiver
 	// of a payment supports accepts spontaneous payments, i.e.
 	// sender-generated preimages according to BOLT XX.
@@ -161,6 +189,14 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// OnionMessagesRequired is a required feature bit that signals that
+	// the node is able to forward and receive onion messages.
+	OnionMessagesRequired FeatureBit = 38
+
+	// OnionMessagesOptional is an optional feature bit that signals that
+	// the node is able to forward and receive onion messages.
+	OnionMessagesOptional FeatureBit = 39
+
 	// ExplicitChannelTypeRequired is a required bit that denotes that a
 	// connection established with this node is to use explicit channel
 	// commitment types for negotiation instead of the existing implicit
@@ -231,6 +267,28 @@ const (
 	// TODO: Decide on actual feature bit value.
 	ScriptEnforcedLeaseOptional FeatureBit = 2023
 
+	// DynamicCommitmentsRequired is a required feature bit that signals
+	// that the node requires support for dynamic commitments, which allow
+	// changing the channel type of a live channel.
+	//
+	// TODO: Decide on actual feature bit value.
+	DynamicCommitmentsRequired FeatureBit = 2024
+
+	// DynamicCommitmentsOptional is an optional feature bit that signals
+	// that the node supports dynamic commitments, which allow changing
+	// the channel type of a live channel.
+	//
+	// TODO: Decide on actual feature bit value.
+	DynamicCommitmentsOptional FeatureBit = 2025
+
+	// SpliceRequired is a required feature bit that signals that the node
+	// requires support for splicing funds into and out of live channels.
+	SpliceRequired FeatureBit = 62
+
+	// SpliceOptional is an optional feature bit that signals that the
+	// node supports splicing funds into and out of live channels.
+	SpliceOptional FeatureBit = 63
+
 	// SimpleTaprootChannelsRequredFinal is a required bit that indicates
 	// the node is able to create taproot-native channels. This is the
 	// final feature bit to be used once the channel type is finalized.
@@ -297,10 +355,14 @@ var Features = map[FeatureBit]string{
 	AnchorsOptional:                      "anchor-commitments",
 	AnchorsZeroFeeHtlcTxRequired:         "anchors-zero-fee-htlc-tx",
 	AnchorsZeroFeeHtlcTxOptional:         "anchors-zero-fee-htlc-tx",
+	RouteBlindingRequired:                "route-blinding",
+	RouteBlindingOptional:                "route-blinding",
 	WumboChannelsRequired:                "wumbo-channels",
 	WumboChannelsOptional:                "wumbo-channels",
 	AMPRequired:                          "amp",
 	AMPOptional:                          "amp",
+	OnionMessagesRequired:                "onion-messages",
+	OnionMessagesOptional:                "onion-messages",
 	PaymentMetadataOptional:              "payment-metadata",
 	PaymentMetadataRequired:              "payment-metadata",
 	ExplicitChannelTypeOptional:          "explicit-commitment-type",
@@ -315,6 +377,14 @@ var Features = map[FeatureBit]string{
 	ZeroConfOptional:                     "zero-conf",
 	ShutdownAnySegwitRequired:            "shutdown-any-segwit",
 	ShutdownAnySegwitOptional:            "shutdown-any-segwit",
+	DualFundRequired:                     "dual-fund",
+	DualFundOptional:                     "dual-fund",
+	QuiescenceRequired:                   "quiescence",
+	QuiescenceOptional:                   "quiescence",
+	DynamicCommitmentsRequired:           "dynamic-commitments",
+	DynamicCommitmentsOptional:           "dynamic-commitments",
+	SpliceRequired:                       "splice",
+	SpliceOptional:                       "splice",
 	SimpleTaprootChannelsRequiredFinal:   "simple-taproot-chans",
 	SimpleTaprootChannelsOptionalFinal:   "simple-taproot-chans",
 	SimpleTaprootChannelsRequiredStaging: "simple-taproot-chans-x",
@@ -0,0 +1,28 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeEncodeDecode tests that we're able to properly encode and decode
+// fees, including negative ones, within TLV streams.
+func TestFeeEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	fee := Fee{
+		BaseFee: -1000,
+		FeeRate: -250,
+	}
+
+	var extraData ExtraOpaqueData
+	require.NoError(t, extraData.PackRecords(&fee))
+
+	var fee2 Fee
+	tlvs, err := extraData.ExtractRecords(&fee2)
+	require.NoError(t, err)
+
+	require.Contains(t, tlvs, FeeRecordType)
+	require.Equal(t, fee, fee2)
+}
@@ -708,6 +708,50 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgUpdateAddHTLC: func(v []reflect.Value, r *rand.Rand) {
+			req := &UpdateAddHTLC{
+				ID:        r.Uint64(),
+				Amount:    MilliSatoshi(r.Int63()),
+				Expiry:    r.Uint32(),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.PaymentHash[:]); err != nil {
+				t.Fatalf("unable to generate hash: %v", err)
+				return
+			}
+			if _, err := r.Read(req.OnionBlob[:]); err != nil {
+				t.Fatalf("unable to generate onion: %v", err)
+				return
+			}
+
+			// 50/50 chance to attach a blinding point.
+			if r.Int31()%2 == 0 {
+				var err error
+				req.BlindingPoint, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v",
+						err)
+					return
+				}
+			}
+
+			// 50/50 chance to attach custom records.
+			if r.Int31()%2 == 0 {
+				typ := MinCustomRecordsTlvType +
+					uint64(r.Int31n(10))
+
+				req.CustomRecords = CustomRecords{
+					typ:                          {0x01, 0x02},
+					MinCustomRecordsTlvType + 11: {0x03},
+				}
+			}
+
+			v[0] = reflect.ValueOf(*req)
+		},
 		MsgCommitSig: func(v []reflect.Value, r *rand.Rand) {
 			req := NewCommitSig()
 			if _, err := r.Read(req.ChanID[:]); err != nil {
@@ -1070,6 +1114,416 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgOpenChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := OpenChannel2{
+				FundingFeePerKWeight: r.Uint32(),
+				CommitFeePerKWeight:  r.Uint32(),
+				FundingAmount:        ltcutil.Amount(r.Int63()),
+				DustLimit:            ltcutil.Amount(r.Int63()),
+				MaxValueInFlight:     MilliSatoshi(r.Int63()),
+				HtlcMinimum:          MilliSatoshi(r.Int31()),
+				CsvDelay:             uint16(r.Int31()),
+				MaxAcceptedHTLCs:     uint16(r.Int31()),
+				Locktime:             r.Uint32(),
+				ChannelFlags:         FundingFlag(uint8(r.Int31())),
+			}
+
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v", err)
+				return
+			}
+
+			keys := []**btcec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+			}
+
+			// 1/2 chance empty TLV records.
+			if r.Intn(2) == 0 {
+				var err error
+				req.UpfrontShutdownScript, err = randDeliveryAddress(r)
+				if err != nil {
+					t.Fatalf("unable to generate delivery address: %v", err)
+					return
+				}
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(*randRawFeatureVector(r))
+			} else {
+				req.UpfrontShutdownScript = []byte{}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgAcceptChannel2: func(v []reflect.Value, r *rand.Rand) {
+			req := AcceptChannel2{
+				FundingAmount:    ltcutil.Amount(r.Int63()),
+				DustLimit:        ltcutil.Amount(r.Int63()),
+				MaxValueInFlight: MilliSatoshi(r.Int63()),
+				HtlcMinimum:      MilliSatoshi(r.Int31()),
+				MinAcceptDepth:   uint32(r.Int31()),
+				CsvDelay:         uint16(r.Int31()),
+				MaxAcceptedHTLCs: uint16(r.Int31()),
+			}
+
+			if _, err := r.Read(req.PendingChannelID[:]); err != nil {
+				t.Fatalf("unable to generate pending chan id: %v", err)
+				return
+			}
+
+			keys := []**btcec.PublicKey{
+				&req.FundingKey, &req.RevocationPoint,
+				&req.PaymentPoint, &req.DelayedPaymentPoint,
+				&req.HtlcPoint, &req.FirstCommitmentPoint,
+			}
+			for _, key := range keys {
+				var err error
+				*key, err = randPubKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+			}
+
+			// 1/2 chance empty TLV records.
+			if r.Intn(2) == 0 {
+				var err error
+				req.UpfrontShutdownScript, err = randDeliveryAddress(r)
+				if err != nil {
+					t.Fatalf("unable to generate delivery address: %v", err)
+					return
+				}
+
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(*randRawFeatureVector(r))
+			} else {
+				req.UpfrontShutdownScript = []byte{}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxInitRbf: func(v []reflect.Value, r *rand.Rand) {
+			req := TxInitRbf{
+				Locktime:            r.Uint32(),
+				FeePerKiloWeight:    r.Uint32(),
+				FundingContribution: ltcutil.Amount(r.Int63()),
+				ExtraData:           make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAckRbf: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAckRbf{
+				FundingContribution: ltcutil.Amount(r.Int63()),
+				ExtraData:           make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceInit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceInit{
+				FundingContribution: ltcutil.Amount(
+					r.Int63() - math.MaxInt64/2,
+				),
+				FeePerKiloWeight: r.Uint32(),
+				Locktime:         r.Uint32(),
+				ExtraData:        make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceAck: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceAck{
+				FundingContribution: ltcutil.Amount(
+					r.Int63() - math.MaxInt64/2,
+				),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceLocked: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceLocked{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.SpliceTxid[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgStfu: func(v []reflect.Value, r *rand.Rand) {
+			req := Stfu{
+				Initiator: r.Intn(2) == 0,
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgDynPropose: func(v []reflect.Value, r *rand.Rand) {
+			req := DynPropose{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			// 1/2 chance to propose a channel type.
+			if r.Intn(2) == 0 {
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgDynAck: func(v []reflect.Value, r *rand.Rand) {
+			req := DynAck{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgDynReject: func(v []reflect.Value, r *rand.Rand) {
+			req := DynReject{
+				UpdateRejections: *randRawFeatureVector(r),
+				ExtraData:        make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddInput{
+				SerialID:   r.Uint64(),
+				PrevTxVout: r.Uint32(),
+				Sequence:   r.Uint32(),
+				ExtraData:  make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			// The shared funding input of a splice is sent without
+			// the previous transaction, so we'll either generate
+			// one or the other.
+			if r.Int31()%2 == 0 {
+				var txid SharedInputTxid
+				if _, err := r.Read(txid[:]); err != nil {
+					t.Fatalf("unable to generate txid: %v",
+						err)
+					return
+				}
+				req.SharedInputTxid = &txid
+				req.PrevTx = make([]byte, 0)
+			} else {
+				req.PrevTx = make([]byte, 1+r.Intn(1000))
+				if _, err := r.Read(req.PrevTx); err != nil {
+					t.Fatalf("unable to generate tx: %v",
+						err)
+					return
+				}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddOutput{
+				SerialID:  r.Uint64(),
+				Amount:    ltcutil.Amount(r.Int63()),
+				Script:    make([]byte, 1+r.Intn(100)),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.Script); err != nil {
+				t.Fatalf("unable to generate script: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxRemoveInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxRemoveInput{
+				SerialID:  r.Uint64(),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxRemoveOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxRemoveOutput{
+				SerialID:  r.Uint64(),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxComplete: func(v []reflect.Value, r *rand.Rand) {
+			req := TxComplete{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxSignatures: func(v []reflect.Value, r *rand.Rand) {
+			req := TxSignatures{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.TxID[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+				return
+			}
+
+			// Only create the slice if there will be any witnesses
+			// in it to prevent false positive test failures due to
+			// an empty slice versus a nil slice.
+			numWitnesses := r.Intn(5)
+			for i := 0; i < numWitnesses; i++ {
+				witness := make(wire.TxWitness, 1+r.Intn(4))
+				for j := range witness {
+					witness[j] = make([]byte, 1+r.Intn(80))
+					_, err := r.Read(witness[j])
+					if err != nil {
+						t.Fatalf("unable to generate "+
+							"witness: %v", err)
+						return
+					}
+				}
+				req.Witnesses = append(req.Witnesses, witness)
+			}
+
+			// 50/50 chance to attach a shared input signature.
+			if r.Int31()%2 == 0 {
+				sig, err := NewSigFromSignature(testSig)
+				if err != nil {
+					t.Fatalf("unable to parse sig: %v", err)
+					return
+				}
+				sharedSig := SharedInputSig(sig)
+				req.SharedInputSig = &sharedSig
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAbort: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAbort{
+				Data:      make([]byte, r.Intn(100)),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.Data); err != nil {
+				t.Fatalf("unable to generate data: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			pathKey, err := randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			// Onion message packets may be of variable length, so
+			// we'll generate one that is somewhere between empty
+			// and the largest packet that the spec recommends.
+			onionBlob := make([]byte, r.Intn(32834))
+			if _, err := r.Read(onionBlob); err != nil {
+				t.Fatalf("unable to generate onion: %v", err)
+				return
+			}
+
+			req := NewOnionMessage(pathKey, onionBlob)
+			req.ExtraData = make([]byte, 0)
+
+			v[0] = reflect.ValueOf(*req)
+		},
 	}
 
 	// With the above types defined, we'll now generate a slice of
@@ -1256,6 +1710,120 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgOpenChannel2,
+			scenario: func(m OpenChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgAcceptChannel2,
+			scenario: func(m AcceptChannel2) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxInitRbf,
+			scenario: func(m TxInitRbf) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAckRbf,
+			scenario: func(m TxAckRbf) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceInit,
+			scenario: func(m SpliceInit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceAck,
+			scenario: func(m SpliceAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceLocked,
+			scenario: func(m SpliceLocked) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgStfu,
+			scenario: func(m Stfu) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynPropose,
+			scenario: func(m DynPropose) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynAck,
+			scenario: func(m DynAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynReject,
+			scenario: func(m DynReject) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddInput,
+			scenario: func(m TxAddInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddOutput,
+			scenario: func(m TxAddOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveInput,
+			scenario: func(m TxRemoveInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveOutput,
+			scenario: func(m TxRemoveOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxComplete,
+			scenario: func(m TxComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxSignatures,
+			scenario: func(m TxSignatures) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAbort,
+			scenario: func(m TxAbort) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		var config *quick.Config
@@ -23,6 +23,7 @@ type MessageType uint16
 // Lightning protocol.
 const (
 	MsgWarning                 MessageType = 1
+	MsgStfu                                = 2
 	MsgInit                                = 16
 	MsgError                               = 17
 	MsgPing                                = 18
@@ -34,6 +35,23 @@ const (
 	MsgChannelReady                        = 36
 	MsgShutdown                            = 38
 	MsgClosingSigned                       = 39
+	MsgOpenChannel2                        = 64
+	MsgAcceptChannel2                      = 65
+	MsgTxAddInput                          = 66
+	MsgTxAddOutput                         = 67
+	MsgTxRemoveInput                       = 68
+	MsgTxRemoveOutput                      = 69
+	MsgTxComplete                          = 70
+	MsgTxSignatures                        = 71
+	MsgTxInitRbf                           = 72
+	MsgTxAckRbf                            = 73
+	MsgTxAbort                             = 74
+	MsgSpliceLocked                        = 77
+	MsgSpliceInit                          = 80
+	MsgSpliceAck                           = 81
+	MsgDynPropose                          = 111
+	MsgDynAck                              = 113
+	MsgDynReject                           = 115
 	MsgUpdateAddHTLC                       = 128
 	MsgUpdateFulfillHTLC                   = 130
 	MsgUpdateFailHTLC                      = 131
@@ -51,6 +69,7 @@ const (
 	MsgQueryChannelRange                   = 263
 	MsgReplyChannelRange                   = 264
 	MsgGossipTimestampRange                = 265
+	MsgOnionMessage                        = 513
 )
 
 // ErrorEncodeMessage is used when failed to encode the message payload.
@@ -94,6 +113,42 @@ func (t MessageType) String() string {
 		return "Shutdown"
 	case MsgClosingSigned:
 		return "ClosingSigned"
+	case MsgOpenChannel2:
+		return "MsgOpenChannel2"
+	case MsgAcceptChannel2:
+		return "MsgAcceptChannel2"
+	case MsgTxAddInput:
+		return "TxAddInput"
+	case MsgTxAddOutput:
+		return "TxAddOutput"
+	case MsgTxRemoveInput:
+		return "TxRemoveInput"
+	case MsgTxRemoveOutput:
+		return "TxRemoveOutput"
+	case MsgTxComplete:
+		return "TxComplete"
+	case MsgTxSignatures:
+		return "TxSignatures"
+	case MsgTxInitRbf:
+		return "TxInitRbf"
+	case MsgTxAckRbf:
+		return "TxAckRbf"
+	case MsgTxAbort:
+		return "TxAbort"
+	case MsgSpliceLocked:
+		return "SpliceLocked"
+	case MsgSpliceInit:
+		return "SpliceInit"
+	case MsgSpliceAck:
+		return "SpliceAck"
+	case MsgStfu:
+		return "Stfu"
+	case MsgDynPropose:
+		return "DynPropose"
+	case MsgDynAck:
+		return "DynAck"
+	case MsgDynReject:
+		return "DynReject"
 	case MsgUpdateAddHTLC:
 		return "UpdateAddHTLC"
 	case MsgUpdateFailHTLC:
@@ -134,6 +189,8 @@ func (t MessageType) String() string {
 		return "ReplyChannelRange"
 	case MsgGossipTimestampRange:
 		return "GossipTimestampRange"
+	case MsgOnionMessage:
+		return "OnionMessage"
 	default:
 		return "<unknown>"
 	}
@@ -196,6 +253,42 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &Shutdown{}
 	case MsgClosingSigned:
 		msg = &ClosingSigned{}
+	case MsgOpenChannel2:
+		msg = &OpenChannel2{}
+	case MsgAcceptChannel2:
+		msg = &AcceptChannel2{}
+	case MsgTxAddInput:
+		msg = &TxAddInput{}
+	case MsgTxAddOutput:
+		msg = &TxAddOutput{}
+	case MsgTxRemoveInput:
+		msg = &TxRemoveInput{}
+	case MsgTxRemoveOutput:
+		msg = &TxRemoveOutput{}
+	case MsgTxComplete:
+		msg = &TxComplete{}
+	case MsgTxSignatures:
+		msg = &TxSignatures{}
+	case MsgTxInitRbf:
+		msg = &TxInitRbf{}
+	case MsgTxAckRbf:
+		msg = &TxAckRbf{}
+	case MsgTxAbort:
+		msg = &TxAbort{}
+	case MsgSpliceLocked:
+		msg = &SpliceLocked{}
+	case MsgSpliceInit:
+		msg = &SpliceInit{}
+	case MsgSpliceAck:
+		msg = &SpliceAck{}
+	case MsgStfu:
+		msg = &Stfu{}
+	case MsgDynPropose:
+		msg = &DynPropose{}
+	case MsgDynAck:
+		msg = &DynAck{}
+	case MsgDynReject:
+		msg = &DynReject{}
 	case MsgUpdateAddHTLC:
 		msg = &UpdateAddHTLC{}
 	case MsgUpdateFailHTLC:
@@ -236,6 +329,8 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &ReplyChannelRange{}
 	case MsgGossipTimestampRange:
 		msg = &GossipTimestampRange{}
+	case MsgOnionMessage:
+		msg = &OnionMessage{}
 	default:
 		// If the message is not within our custom range and has not
 		// specifically been overridden, return an unknown message.
@@ -17,6 +17,7 @@ import (
 	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -290,6 +291,16 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgGossipTimestampRange(t, r))
 	msgAll = append(msgAll, newMsgQueryShortChanIDsZlib(t, r))
 	msgAll = append(msgAll, newMsgReplyChannelRangeZlib(t, r))
+	msgAll = append(msgAll, newMsgOnionMessage(t, r))
+	msgAll = append(msgAll, newMsgSpliceInit(t, r))
+	msgAll = append(msgAll, newMsgTxInitRbf(t, r))
+	msgAll = append(msgAll, newMsgSpliceLocked(t, r))
+	msgAll = append(msgAll, newMsgStfu(t, r))
+	msgAll = append(msgAll, newMsgDynPropose(t, r))
+	msgAll = append(msgAll, newMsgDynAck(t, r))
+	msgAll = append(msgAll, newMsgDynReject(t, r))
+	msgAll = append(msgAll, newMsgTxAddInput(t, r))
+	msgAll = append(msgAll, newMsgTxSignatures(t, r))
 
 	return msgAll
 }
@@ -503,10 +514,11 @@ func newMsgUpdateAddHTLC(t testing.TB, r *rand.Rand) *lnwire.UpdateAddHTLC {
 	t.Helper()
 
 	msg := &lnwire.UpdateAddHTLC{
-		ID:        r.Uint64(),
-		Amount:    lnwire.MilliSatoshi(r.Int63()),
-		Expiry:    r.Uint32(),
-		ExtraData: createExtraData(t, r),
+		ID:            r.Uint64(),
+		Amount:        lnwire.MilliSatoshi(r.Int63()),
+		Expiry:        r.Uint32(),
+		BlindingPoint: randPubKey(t),
+		ExtraData:     make([]byte, 0),
 	}
 
 	_, err := r.Read(msg.ChanID[:])
@@ -883,6 +895,168 @@ func newMsgGossipTimestampRange(t testing.TB,
 	return msg
 }
 
+func newMsgOnionMessage(t testing.TB, r io.Reader) *lnwire.OnionMessage {
+	t.Helper()
+
+	onionBlob := make([]byte, 1366)
+	_, err := r.Read(onionBlob)
+	require.NoError(t, err, "unable to generate onion blob")
+
+	msg := lnwire.NewOnionMessage(randPubKey(t), onionBlob)
+	msg.ExtraData = createExtraData(t, r)
+
+	return msg
+}
+
+func newMsgSpliceInit(t testing.TB, r *rand.Rand) *lnwire.SpliceInit {
+	t.Helper()
+
+	msg := &lnwire.SpliceInit{
+		FundingContribution: ltcutil.Amount(r.Int63n(1_000_000)),
+		FeePerKiloWeight:    r.Uint32(),
+		Locktime:            r.Uint32(),
+		FundingPubKey:       randPubKey(t),
+		ExtraData:           createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgTxInitRbf(t testing.TB, r *rand.Rand) *lnwire.TxInitRbf {
+	t.Helper()
+
+	msg := &lnwire.TxInitRbf{
+		Locktime:            r.Uint32(),
+		FeePerKiloWeight:    r.Uint32(),
+		FundingContribution: ltcutil.Amount(r.Int63n(1_000_000)),
+		ExtraData:           createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgSpliceLocked(t testing.TB, r io.Reader) *lnwire.SpliceLocked {
+	t.Helper()
+
+	msg := &lnwire.SpliceLocked{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	_, err = r.Read(msg.SpliceTxid[:])
+	require.NoError(t, err, "unable to generate txid")
+
+	return msg
+}
+
+func newMsgStfu(t testing.TB, r *rand.Rand) *lnwire.Stfu {
+	t.Helper()
+
+	msg := &lnwire.Stfu{
+		Initiator: r.Intn(2) == 0,
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynPropose(t testing.TB, r io.Reader) *lnwire.DynPropose {
+	t.Helper()
+
+	chanType := lnwire.ChannelType(*rawFeatureVector())
+	msg := &lnwire.DynPropose{
+		ChannelType: &chanType,
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynAck(t testing.TB, r io.Reader) *lnwire.DynAck {
+	t.Helper()
+
+	msg := &lnwire.DynAck{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynReject(t testing.TB, r io.Reader) *lnwire.DynReject {
+	t.Helper()
+
+	msg := &lnwire.DynReject{
+		UpdateRejections: *rawFeatureVector(),
+		ExtraData:        createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgTxAddInput(t testing.TB, r *rand.Rand) *lnwire.TxAddInput {
+	t.Helper()
+
+	msg := &lnwire.TxAddInput{
+		SerialID:   r.Uint64(),
+		PrevTx:     make([]byte, 250),
+		PrevTxVout: r.Uint32(),
+		Sequence:   r.Uint32(),
+		ExtraData:  make([]byte, 0),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	_, err = r.Read(msg.PrevTx)
+	require.NoError(t, err, "unable to generate tx")
+
+	return msg
+}
+
+func newMsgTxSignatures(t testing.TB, r io.Reader) *lnwire.TxSignatures {
+	t.Helper()
+
+	sharedSig := lnwire.SharedInputSig(testNodeSig)
+	msg := &lnwire.TxSignatures{
+		SharedInputSig: &sharedSig,
+		ExtraData:      make([]byte, 0),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	_, err = r.Read(msg.TxID[:])
+	require.NoError(t, err, "unable to generate txid")
+
+	witness := wire.TxWitness{make([]byte, 72), make([]byte, 33)}
+	for _, item := range witness {
+		_, err = r.Read(item)
+		require.NoError(t, err, "unable to generate witness")
+	}
+	msg.Witnesses = []wire.TxWitness{witness}
+
+	return msg
+}
+
 func randRawKey(t testing.TB) [33]byte {
 	t.Helper()
 
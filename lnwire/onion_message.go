@@ -0,0 +1,100 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+)
+
+// OnionMessage is a message that carries a sphinx-encrypted onion packet
+// along a (partially) blinded path of nodes, without the use of HTLCs. Each
+// node along the path peels off a layer of the onion and hands the remainder
+// to the next node, with the path key being used to decrypt the route
+// blinding data that tells it who the next node is.
+type OnionMessage struct {
+	// PathKey is the ephemeral public key that the receiving node uses to
+	// decrypt the blinded route data in its onion payload.
+	PathKey *btcec.PublicKey
+
+	// OnionBlob is the raw serialized onion message packet. Unlike the
+	// onion packet of an HTLC, its length is variable.
+	OnionBlob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewOnionMessage creates a new OnionMessage.
+func NewOnionMessage(pathKey *btcec.PublicKey,
+	onionBlob []byte) *OnionMessage {
+
+	return &OnionMessage{
+		PathKey:   pathKey,
+		OnionBlob: onionBlob,
+	}
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Decode deserializes a serialized OnionMessage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElement(r, &o.PathKey); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+	onionLen := binary.BigEndian.Uint16(l[:])
+
+	o.OnionBlob = make([]byte, onionLen)
+	if _, err := io.ReadFull(r, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return ReadElement(r, &o.ExtraData)
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WritePublicKey(w, o.PathKey); err != nil {
+		return err
+	}
+
+	if len(o.OnionBlob) > MaxMsgBody {
+		return fmt.Errorf("onion blob of %d bytes exceeds maximum "+
+			"message size", len(o.OnionBlob))
+	}
+
+	if err := WriteUint16(w, uint16(len(o.OnionBlob))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
@@ -0,0 +1,253 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// OpenChannel2 is the message the opener of a dual funded channel sends to
+// start the v2 channel establishment protocol. Unlike OpenChannel, both peers
+// may contribute funds to the channel, and the funding transaction is
+// constructed interactively once the accepting peer responded with an
+// AcceptChannel2 message.
+type OpenChannel2 struct {
+	// ChainHash is the target chain that the opener wishes to open a
+	// channel within.
+	ChainHash chainhash.Hash
+
+	// PendingChannelID serves to uniquely identify the future channel
+	// until the funding transaction has been constructed.
+	PendingChannelID [32]byte
+
+	// FundingFeePerKWeight is the fee rate in sat/kw that both peers pay
+	// for their contributions to the funding transaction.
+	FundingFeePerKWeight uint32
+
+	// CommitFeePerKWeight is the initial fee rate that the opener
+	// suggests for both commitment transactions.
+	CommitFeePerKWeight uint32
+
+	// FundingAmount is the amount of satoshis that the opener contributes
+	// to the channel.
+	FundingAmount ltcutil.Amount
+
+	// DustLimit is the specific dust limit the sender of this message
+	// would like enforced on their version of the commitment transaction.
+	DustLimit ltcutil.Amount
+
+	// MaxValueInFlight represents the maximum amount of coins that can be
+	// pending within the channel at any given time.
+	MaxValueInFlight MilliSatoshi
+
+	// HtlcMinimum is the smallest HTLC that the sender of this message
+	// will accept.
+	HtlcMinimum MilliSatoshi
+
+	// CsvDelay is the number of blocks to use for the relative time lock
+	// in the pay-to-self output of the commitment transaction of the
+	// receiver.
+	CsvDelay uint16
+
+	// MaxAcceptedHTLCs is the total number of incoming HTLC's that the
+	// sender of this channel will accept.
+	MaxAcceptedHTLCs uint16
+
+	// Locktime is the locktime of the funding transaction.
+	Locktime uint32
+
+	// FundingKey is the key that should be used on behalf of the sender
+	// within the 2-of-2 multi-sig output of the funding transaction.
+	FundingKey *btcec.PublicKey
+
+	// RevocationPoint is the base revocation point for the sending party.
+	RevocationPoint *btcec.PublicKey
+
+	// PaymentPoint is the base payment point for the sending party.
+	PaymentPoint *btcec.PublicKey
+
+	// DelayedPaymentPoint is the delay point for the sending party.
+	DelayedPaymentPoint *btcec.PublicKey
+
+	// HtlcPoint is the base point used to derive the set of keys for this
+	// party that will be used within the HTLC public key scripts.
+	HtlcPoint *btcec.PublicKey
+
+	// FirstCommitmentPoint is the first commitment point for the sending
+	// party.
+	FirstCommitmentPoint *btcec.PublicKey
+
+	// ChannelFlags is a bit-field which allows the opener of the channel
+	// to specify further behavior surrounding the channel.
+	ChannelFlags FundingFlag
+
+	// UpfrontShutdownScript is the script to which the channel funds
+	// should be paid when mutually closing the channel.
+	UpfrontShutdownScript DeliveryAddress
+
+	// ChannelType is the explicit channel type the opener wishes to open.
+	ChannelType *ChannelType
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure OpenChannel2 implements the lnwire.Message
+// interface.
+var _ Message = (*OpenChannel2)(nil)
+
+// Encode serializes the target OpenChannel2 into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) Encode(w *bytes.Buffer, pver uint32) error {
+	recordProducers := []tlv.RecordProducer{&o.UpfrontShutdownScript}
+	if o.ChannelType != nil {
+		recordProducers = append(recordProducers, o.ChannelType)
+	}
+	err := EncodeMessageExtraData(&o.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.ChainHash[:]); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.PendingChannelID[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, o.FundingFeePerKWeight); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, o.CommitFeePerKWeight); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, o.FundingAmount); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, o.DustLimit); err != nil {
+		return err
+	}
+
+	if err := WriteMilliSatoshi(w, o.MaxValueInFlight); err != nil {
+		return err
+	}
+
+	if err := WriteMilliSatoshi(w, o.HtlcMinimum); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, o.CsvDelay); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, o.MaxAcceptedHTLCs); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, o.Locktime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.FundingKey); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.RevocationPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.PaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.DelayedPaymentPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.HtlcPoint); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, o.FirstCommitmentPoint); err != nil {
+		return err
+	}
+
+	if err := WriteFundingFlag(w, o.ChannelFlags); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// Decode deserializes the serialized OpenChannel2 stored in the passed
+// io.Reader into the target OpenChannel2 using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(r,
+		o.ChainHash[:],
+		o.PendingChannelID[:],
+		&o.FundingFeePerKWeight,
+		&o.CommitFeePerKWeight,
+		&o.FundingAmount,
+		&o.DustLimit,
+		&o.MaxValueInFlight,
+		&o.HtlcMinimum,
+		&o.CsvDelay,
+		&o.MaxAcceptedHTLCs,
+		&o.Locktime,
+		&o.FundingKey,
+		&o.RevocationPoint,
+		&o.PaymentPoint,
+		&o.DelayedPaymentPoint,
+		&o.HtlcPoint,
+		&o.FirstCommitmentPoint,
+		&o.ChannelFlags,
+	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var chanType ChannelType
+	typeMap, err := tlvRecords.ExtractRecords(
+		&o.UpfrontShutdownScript, &chanType,
+	)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
+		o.ChannelType = &chanType
+	}
+
+	o.ExtraData = tlvRecords
+
+	return nil
+}
+
+// MsgType returns the MessageType code which uniquely identifies this message
+// as an OpenChannel2 on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OpenChannel2) MsgType() MessageType {
+	return MsgOpenChannel2
+}
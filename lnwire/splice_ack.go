@@ -0,0 +1,90 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// SpliceAck is sent in response to a SpliceInit message by the peer that
+// accepts the splice. Once it has been sent, the initiator starts the
+// interactive construction of the splice transaction.
+type SpliceAck struct {
+	// ChanID is the channel that is to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount that the accepting peer adds to
+	// the channel.
+	FundingContribution ltcutil.Amount
+
+	// FundingPubKey is the key of the accepting peer that is used in the
+	// multi-sig script of the new funding output.
+	FundingPubKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Decode deserializes a serialized SpliceAck message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, _ uint32) error {
+	var contribution uint64
+	err := ReadElements(r,
+		&s.ChanID,
+		&contribution,
+		&s.FundingPubKey,
+		&s.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.FundingContribution = ltcutil.Amount(int64(contribution))
+
+	return nil
+}
+
+// Encode serializes the target SpliceAck into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	err := WriteUint64(w, uint64(int64(s.FundingContribution)))
+	if err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (s *SpliceAck) TargetChanID() ChannelID {
+	return s.ChanID
+}
@@ -0,0 +1,110 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// SpliceInit is sent by the initiator of a splice to propose replacing the
+// funding output of a quiescent channel with a new one. The amount that the
+// initiator adds to or removes from the channel is given by its funding
+// contribution, after which both sides construct the splice transaction
+// using the interactive transaction construction protocol.
+type SpliceInit struct {
+	// ChanID is the channel that is to be spliced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount that the initiator adds to the
+	// channel. A negative amount removes funds from the initiator's side
+	// of the channel.
+	FundingContribution ltcutil.Amount
+
+	// FeePerKiloWeight is the fee rate in sat/kw that the initiator
+	// proposes for the splice transaction.
+	FeePerKiloWeight uint32
+
+	// Locktime is the locktime of the splice transaction.
+	Locktime uint32
+
+	// FundingPubKey is the key of the initiator that is used in the
+	// multi-sig script of the new funding output.
+	FundingPubKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Decode deserializes a serialized SpliceInit message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, _ uint32) error {
+	var contribution uint64
+	err := ReadElements(r,
+		&s.ChanID,
+		&contribution,
+		&s.FeePerKiloWeight,
+		&s.Locktime,
+		&s.FundingPubKey,
+		&s.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.FundingContribution = ltcutil.Amount(int64(contribution))
+
+	return nil
+}
+
+// Encode serializes the target SpliceInit into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	err := WriteUint64(w, uint64(int64(s.FundingContribution)))
+	if err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.FeePerKiloWeight); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.Locktime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (s *SpliceInit) TargetChanID() ChannelID {
+	return s.ChanID
+}
@@ -0,0 +1,70 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// SpliceLocked is sent by each side of a splice once the splice transaction
+// has reached the required number of confirmations. Once both sides have
+// sent it, the channel continues on top of the new funding output.
+type SpliceLocked struct {
+	// ChanID is the channel that was spliced.
+	ChanID ChannelID
+
+	// SpliceTxid is the txid of the splice transaction that was locked.
+	SpliceTxid chainhash.Hash
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceLocked implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceLocked)(nil)
+
+// Decode deserializes a serialized SpliceLocked message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Decode(r io.Reader, _ uint32) error {
+	return ReadElements(r,
+		&s.ChanID,
+		s.SpliceTxid[:],
+		&s.ExtraData,
+	)
+}
+
+// Encode serializes the target SpliceLocked into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, s.SpliceTxid[:]); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) MsgType() MessageType {
+	return MsgSpliceLocked
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (s *SpliceLocked) TargetChanID() ChannelID {
+	return s.ChanID
+}
@@ -0,0 +1,72 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// Stfu is sent by either side of a channel to request that the channel enters
+// a quiescent state. A node only sends it once all of its own pending updates
+// have been irrevocably committed, and won't send any further updates until
+// the channel leaves the quiescent state again. Once both sides have sent it,
+// the channel is quiescent and the initiator may start a protocol that
+// requires a stable channel state, such as a dynamic commitment upgrade.
+type Stfu struct {
+	// ChanID is the channel that is to become quiescent.
+	ChanID ChannelID
+
+	// Initiator is true if the sender of the message is the one who
+	// requested the channel to become quiescent.
+	Initiator bool
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure Stfu implements the lnwire.Message
+// interface.
+var _ Message = (*Stfu)(nil)
+
+// Decode deserializes a serialized Stfu message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Decode(r io.Reader, _ uint32) error {
+	return ReadElements(r,
+		&s.ChanID,
+		&s.Initiator,
+		&s.ExtraData,
+	)
+}
+
+// Encode serializes the target Stfu into the passed io.Writer observing the
+// protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBool(w, s.Initiator); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) MsgType() MessageType {
+	return MsgStfu
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (s *Stfu) TargetChanID() ChannelID {
+	return s.ChanID
+}
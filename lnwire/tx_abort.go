@@ -0,0 +1,94 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// TxAbort is sent by either peer to abort the interactive construction of a
+// transaction, or a splice that has not been signed yet. The receiver echoes
+// the message back unless it sent one itself.
+type TxAbort struct {
+	// ChanID is the channel that the transaction is constructed for.
+	ChanID ChannelID
+
+	// Data is an optional human readable reason for the abort.
+	Data []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAbort implements the lnwire.Message
+// interface.
+var _ Message = (*TxAbort)(nil)
+
+// Decode deserializes a serialized TxAbort message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElement(r, &t.ChanID); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+
+	t.Data = make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, t.Data); err != nil {
+		return err
+	}
+
+	return ReadElement(r, &t.ExtraData)
+}
+
+// Encode serializes the target TxAbort into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if len(t.Data) > MaxMsgBody {
+		return fmt.Errorf("abort data of %d bytes exceeds maximum "+
+			"message size", len(t.Data))
+	}
+
+	if err := WriteUint16(w, uint16(len(t.Data))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, t.Data); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) MsgType() MessageType {
+	return MsgTxAbort
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (t *TxAbort) TargetChanID() ChannelID {
+	return t.ChanID
+}
+
+// Error returns the abort reason as a string.
+func (t *TxAbort) Error() string {
+	return fmt.Sprintf("tx_abort: %s", t.Data)
+}
@@ -0,0 +1,70 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// TxAckRbf is sent in response to a TxInitRbf message by the peer that
+// accepts to replace the funding transaction of a dual funded channel.
+type TxAckRbf struct {
+	// ChanID is the channel whose funding transaction is replaced.
+	ChanID ChannelID
+
+	// FundingContribution is the amount that the sender contributes to
+	// the funding output of the replacement transaction.
+	FundingContribution ltcutil.Amount
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAckRbf implements the lnwire.Message
+// interface.
+var _ Message = (*TxAckRbf)(nil)
+
+// Decode deserializes a serialized TxAckRbf message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAckRbf) Decode(r io.Reader, _ uint32) error {
+	return ReadElements(r,
+		&t.ChanID,
+		&t.FundingContribution,
+		&t.ExtraData,
+	)
+}
+
+// Encode serializes the target TxAckRbf into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAckRbf) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, t.FundingContribution); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAckRbf) MsgType() MessageType {
+	return MsgTxAckRbf
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (t *TxAckRbf) TargetChanID() ChannelID {
+	return t.ChanID
+}
@@ -0,0 +1,173 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+const (
+	// SharedInputTxidRecordType is the TLV type used to identify the
+	// transaction of the shared funding input of a splice.
+	SharedInputTxidRecordType tlv.Type = 0
+)
+
+// SharedInputTxid is the txid of the transaction that created the shared
+// funding output that a splice transaction spends. It is sent in place of
+// the full previous transaction.
+type SharedInputTxid chainhash.Hash
+
+// Record returns a TLV record that can be used to encode/decode the shared
+// input txid from a given TLV stream.
+func (s *SharedInputTxid) Record() tlv.Record {
+	return tlv.MakePrimitiveRecord(
+		SharedInputTxidRecordType, (*[32]byte)(s),
+	)
+}
+
+// TxAddInput is sent during the interactive construction of a transaction to
+// add an input to it. Inputs are identified by a serial ID, which is even for
+// inputs added by the initiator and odd for inputs added by the other peer.
+type TxAddInput struct {
+	// ChanID is the channel that the transaction is constructed for.
+	ChanID ChannelID
+
+	// SerialID identifies the input within the transaction. Inputs are
+	// sorted by their serial ID in the final transaction.
+	SerialID uint64
+
+	// PrevTx is the serialized transaction that contains the output being
+	// spent. It is empty for the shared funding input of a splice, which
+	// is identified by SharedInputTxid instead.
+	PrevTx []byte
+
+	// PrevTxVout is the index of the output being spent.
+	PrevTxVout uint32
+
+	// Sequence is the sequence number of the input.
+	Sequence uint32
+
+	// SharedInputTxid is set if the input spends the current funding
+	// output of the channel being spliced.
+	SharedInputTxid *SharedInputTxid
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddInput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddInput)(nil)
+
+// Decode deserializes a serialized TxAddInput message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElements(r, &t.ChanID, &t.SerialID); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+
+	t.PrevTx = make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, t.PrevTx); err != nil {
+		return err
+	}
+
+	err := ReadElements(r, &t.PrevTxVout, &t.Sequence)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var sharedTxid SharedInputTxid
+	typeMap, err := tlvRecords.ExtractRecords(&sharedTxid)
+	if err != nil {
+		return err
+	}
+
+	// Set the corresponding TLV types if they were included in the stream.
+	if val, ok := typeMap[SharedInputTxidRecordType]; ok && val == nil {
+		t.SharedInputTxid = &sharedTxid
+	}
+
+	if len(tlvRecords) != 0 {
+		t.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// Encode serializes the target TxAddInput into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Encode(w *bytes.Buffer, _ uint32) error {
+	recordProducers := make([]tlv.RecordProducer, 0, 1)
+	if t.SharedInputTxid != nil {
+		recordProducers = append(recordProducers, t.SharedInputTxid)
+	}
+	err := EncodeMessageExtraData(&t.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	if len(t.PrevTx) > MaxMsgBody {
+		return fmt.Errorf("previous tx of %d bytes exceeds maximum "+
+			"message size", len(t.PrevTx))
+	}
+
+	if err := WriteUint16(w, uint16(len(t.PrevTx))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, t.PrevTx); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.PrevTxVout); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.Sequence); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) MsgType() MessageType {
+	return MsgTxAddInput
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (t *TxAddInput) TargetChanID() ChannelID {
+	return t.ChanID
+}
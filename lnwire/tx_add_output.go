@@ -0,0 +1,108 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// TxAddOutput is sent during the interactive construction of a transaction to
+// add an output to it. Outputs are identified by a serial ID, which is even
+// for outputs added by the initiator and odd for outputs added by the other
+// peer.
+type TxAddOutput struct {
+	// ChanID is the channel that the transaction is constructed for.
+	ChanID ChannelID
+
+	// SerialID identifies the output within the transaction. Outputs are
+	// sorted by their serial ID in the final transaction.
+	SerialID uint64
+
+	// Amount is the value of the output.
+	Amount ltcutil.Amount
+
+	// Script is the public key script of the output.
+	Script []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxAddOutput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddOutput)(nil)
+
+// Decode deserializes a serialized TxAddOutput message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Decode(r io.Reader, _ uint32) error {
+	err := ReadElements(r, &t.ChanID, &t.SerialID, &t.Amount)
+	if err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+
+	t.Script = make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, t.Script); err != nil {
+		return err
+	}
+
+	return ReadElement(r, &t.ExtraData)
+}
+
+// Encode serializes the target TxAddOutput into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, t.Amount); err != nil {
+		return err
+	}
+
+	if len(t.Script) > MaxMsgBody {
+		return fmt.Errorf("script of %d bytes exceeds maximum "+
+			"message size", len(t.Script))
+	}
+
+	if err := WriteUint16(w, uint16(len(t.Script))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, t.Script); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) MsgType() MessageType {
+	return MsgTxAddOutput
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (t *TxAddOutput) TargetChanID() ChannelID {
+	return t.ChanID
+}
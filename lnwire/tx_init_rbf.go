@@ -0,0 +1,89 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// TxInitRbf is sent to replace the funding transaction of a dual funded
+// channel that hasn't confirmed yet with one paying a higher fee rate. Once
+// the peer responded with TxAckRbf, both sides construct the replacement
+// transaction using the interactive transaction construction protocol.
+type TxInitRbf struct {
+	// ChanID is the channel whose funding transaction is replaced.
+	ChanID ChannelID
+
+	// Locktime is the locktime of the replacement transaction.
+	Locktime uint32
+
+	// FeePerKiloWeight is the fee rate in sat/kw of the replacement
+	// transaction.
+	FeePerKiloWeight uint32
+
+	// FundingContribution is the amount that the sender contributes to
+	// the funding output of the replacement transaction.
+	FundingContribution ltcutil.Amount
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxInitRbf implements the lnwire.Message
+// interface.
+var _ Message = (*TxInitRbf)(nil)
+
+// Decode deserializes a serialized TxInitRbf message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxInitRbf) Decode(r io.Reader, _ uint32) error {
+	return ReadElements(r,
+		&t.ChanID,
+		&t.Locktime,
+		&t.FeePerKiloWeight,
+		&t.FundingContribution,
+		&t.ExtraData,
+	)
+}
+
+// Encode serializes the target TxInitRbf into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxInitRbf) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.Locktime); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.FeePerKiloWeight); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, t.FundingContribution); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxInitRbf) MsgType() MessageType {
+	return MsgTxInitRbf
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (t *TxInitRbf) TargetChanID() ChannelID {
+	return t.ChanID
+}
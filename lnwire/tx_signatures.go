@@ -0,0 +1,251 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// SharedInputSigRecordType is the TLV type used to carry the
+	// signature of the sender for the shared funding input of a splice.
+	SharedInputSigRecordType tlv.Type = 0
+)
+
+// SharedInputSig is the signature of the sender for the multi-sig funding
+// input that is spent by a splice transaction.
+type SharedInputSig Sig
+
+// Record returns a TLV record that can be used to encode/decode the shared
+// input signature from a given TLV stream.
+func (s *SharedInputSig) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		SharedInputSigRecordType, s, 64, sharedInputSigEncoder,
+		sharedInputSigDecoder,
+	)
+}
+
+// sharedInputSigEncoder is a custom TLV encoder for the SharedInputSig type.
+func sharedInputSigEncoder(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*SharedInputSig); ok {
+		_, err := w.Write(v.bytes[:])
+		return err
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.SharedInputSig")
+}
+
+// sharedInputSigDecoder is a custom TLV decoder for the SharedInputSig
+// record.
+func sharedInputSigDecoder(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*SharedInputSig); ok && l == 64 {
+		_, err := io.ReadFull(r, v.bytes[:])
+		return err
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.SharedInputSig", l, 64)
+}
+
+// TxSignatures is sent by both peers once a transaction has been constructed
+// interactively and all commitment signatures were exchanged. It carries the
+// witnesses for the inputs that the sender added, in the order of their
+// serial IDs.
+type TxSignatures struct {
+	// ChanID is the channel that the transaction is constructed for.
+	ChanID ChannelID
+
+	// TxID is the txid of the constructed transaction.
+	TxID chainhash.Hash
+
+	// Witnesses are the witnesses of the inputs added by the sender,
+	// sorted by serial ID.
+	Witnesses []wire.TxWitness
+
+	// SharedInputSig is set if the transaction is a splice, and carries
+	// the sender's signature for the shared funding input.
+	SharedInputSig *SharedInputSig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure TxSignatures implements the lnwire.Message
+// interface.
+var _ Message = (*TxSignatures)(nil)
+
+// Decode deserializes a serialized TxSignatures message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Decode(r io.Reader, _ uint32) error {
+	var numWitnesses uint16
+	err := ReadElements(r, &t.ChanID, t.TxID[:], &numWitnesses)
+	if err != nil {
+		return err
+	}
+
+	t.Witnesses = nil
+	for i := 0; i < int(numWitnesses); i++ {
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return err
+		}
+
+		rawWitness := make([]byte, binary.BigEndian.Uint16(l[:]))
+		if _, err := io.ReadFull(r, rawWitness); err != nil {
+			return err
+		}
+
+		witness, err := deserializeWitness(rawWitness)
+		if err != nil {
+			return err
+		}
+		t.Witnesses = append(t.Witnesses, witness)
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var sharedSig SharedInputSig
+	typeMap, err := tlvRecords.ExtractRecords(&sharedSig)
+	if err != nil {
+		return err
+	}
+
+	// Set the corresponding TLV types if they were included in the stream.
+	if val, ok := typeMap[SharedInputSigRecordType]; ok && val == nil {
+		t.SharedInputSig = &sharedSig
+	}
+
+	if len(tlvRecords) != 0 {
+		t.ExtraData = tlvRecords
+	}
+
+	return nil
+}
+
+// Encode serializes the target TxSignatures into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Encode(w *bytes.Buffer, _ uint32) error {
+	recordProducers := make([]tlv.RecordProducer, 0, 1)
+	if t.SharedInputSig != nil {
+		recordProducers = append(recordProducers, t.SharedInputSig)
+	}
+	err := EncodeMessageExtraData(&t.ExtraData, recordProducers...)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteChannelID(w, t.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, t.TxID[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, uint16(len(t.Witnesses))); err != nil {
+		return err
+	}
+
+	for _, witness := range t.Witnesses {
+		var b bytes.Buffer
+		if err := serializeWitness(&b, witness); err != nil {
+			return err
+		}
+
+		if b.Len() > MaxMsgBody {
+			return fmt.Errorf("witness of %d bytes exceeds maximum "+
+				"message size", b.Len())
+		}
+
+		if err := WriteUint16(w, uint16(b.Len())); err != nil {
+			return err
+		}
+
+		if err := WriteBytes(w, b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) MsgType() MessageType {
+	return MsgTxSignatures
+}
+
+// TargetChanID returns the channel id of the channel this message is intended
+// for.
+func (t *TxSignatures) TargetChanID() ChannelID {
+	return t.ChanID
+}
+
+// serializeWitness writes the witness in the format used by bitcoin
+// transactions: the number of stack elements followed by each element as
+// variable length bytes.
+func serializeWitness(w io.Writer, witness wire.TxWitness) error {
+	err := wire.WriteVarInt(w, 0, uint64(len(witness)))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(w, 0, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeWitness parses a witness that was serialized with
+// serializeWitness.
+func deserializeWitness(rawWitness []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(rawWitness)
+
+	numItems, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every item takes at least one byte for its length, which bounds the
+	// number of items we need to allocate room for.
+	if numItems > uint64(len(rawWitness)) {
+		return nil, fmt.Errorf("witness with %d items exceeds its "+
+			"length of %d bytes", numItems, len(rawWitness))
+	}
+
+	witness := make(wire.TxWitness, numItems)
+	for i := range witness {
+		witness[i], err = wire.ReadVarBytes(
+			r, 0, uint32(len(rawWitness)), "witness item",
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes in witness", r.Len())
+	}
+
+	return witness, nil
+}
@@ -3,6 +3,8 @@ package lnwire
 import (
 	"bytes"
 	"io"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
 )
 
 // OnionPacketSize is the size of the serialized Sphinx onion packet included
@@ -54,6 +56,17 @@ type UpdateAddHTLC struct {
 	// used in the subsequent UpdateAddHTLC message.
 	OnionBlob [OnionPacketSize]byte
 
+	// BlindingPoint is the ephemeral pubkey that is used by nodes inside
+	// a blinded route to decrypt their route blinding data. It is only
+	// set for HTLCs that are forwarded inside a blinded route, past the
+	// introduction node.
+	BlindingPoint *btcec.PublicKey
+
+	// CustomRecords maps TLV types to byte slices, storing arbitrary data
+	// attached to the HTLC. Only types in the custom range (greater than
+	// or equal to MinCustomRecordsTlvType) are allowed.
+	CustomRecords CustomRecords
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -74,15 +87,42 @@ var _ Message = (*UpdateAddHTLC)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.ID,
 		&c.Amount,
 		c.PaymentHash[:],
 		&c.Expiry,
 		c.OnionBlob[:],
-		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var blindingPoint BlindingPoint
+	typeMap, err := tlvRecords.ExtractRecords(&blindingPoint)
+	if err != nil {
+		return err
+	}
+
+	// We'll only set the blinding point if the corresponding TLV type
+	// was included in the stream.
+	if val, ok := typeMap[BlindingPointRecordType]; ok && val == nil {
+		c.BlindingPoint = (*btcec.PublicKey)(&blindingPoint)
+	}
+
+	// Any remaining records in the custom range are attached to the HTLC
+	// as its custom records.
+	c.CustomRecords = ParseCustomRecords(typeMap)
+
+	c.ExtraData = tlvRecords
+
+	return nil
 }
 
 // Encode serializes the target UpdateAddHTLC into the passed io.Writer
@@ -114,6 +154,28 @@ func (c *UpdateAddHTLC) Encode(w *bytes.Buffer, pver uint32) error {
 		return err
 	}
 
+	// We'll only encode the blinding point and custom records in a TLV
+	// segment if they exist, otherwise the extra data is written out
+	// untouched.
+	if c.BlindingPoint != nil || len(c.CustomRecords) > 0 {
+		if err := c.CustomRecords.Validate(); err != nil {
+			return err
+		}
+
+		recordProducers := c.CustomRecords.RecordProducers()
+		if c.BlindingPoint != nil {
+			recordProducers = append(
+				recordProducers,
+				(*BlindingPoint)(c.BlindingPoint),
+			)
+		}
+
+		err := EncodeMessageExtraData(&c.ExtraData, recordProducers...)
+		if err != nil {
+			return err
+		}
+	}
+
 	return WriteBytes(w, c.ExtraData)
 }
 
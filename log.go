@@ -25,6 +25,7 @@ import (
 	"github.com/ltcsuite/lnd/lnrpc/autopilotrpc"
 	"github.com/ltcsuite/lnd/lnrpc/chainrpc"
 	"github.com/ltcsuite/lnd/lnrpc/devrpc"
+	"github.com/ltcsuite/lnd/lnrpc/electrumrpc"
 	"github.com/ltcsuite/lnd/lnrpc/invoicesrpc"
 	"github.com/ltcsuite/lnd/lnrpc/neutrinorpc"
 	"github.com/ltcsuite/lnd/lnrpc/peersrpc"
@@ -36,9 +37,12 @@ import (
 	"github.com/ltcsuite/lnd/lnwallet/btcwallet"
 	"github.com/ltcsuite/lnd/lnwallet/chancloser"
 	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/lnd/lnwallet/chansplicer"
 	"github.com/ltcsuite/lnd/lnwallet/rpcwallet"
 	"github.com/ltcsuite/lnd/monitoring"
 	"github.com/ltcsuite/lnd/netann"
+	"github.com/ltcsuite/lnd/offers"
+	"github.com/ltcsuite/lnd/onionmessage"
 	"github.com/ltcsuite/lnd/peer"
 	"github.com/ltcsuite/lnd/peernotifier"
 	"github.com/ltcsuite/lnd/routing"
@@ -46,6 +50,7 @@ import (
 	"github.com/ltcsuite/lnd/signal"
 	"github.com/ltcsuite/lnd/sweep"
 	"github.com/ltcsuite/lnd/tor"
+	"github.com/ltcsuite/lnd/tracing"
 	"github.com/ltcsuite/lnd/watchtower"
 	"github.com/ltcsuite/lnd/watchtower/wtclient"
 	"github.com/ltcsuite/ltcd/connmgr"
@@ -153,6 +158,7 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(root, "WLKT", interceptor, walletrpc.UseLogger)
 	AddSubLogger(root, "ARPC", interceptor, autopilotrpc.UseLogger)
 	AddSubLogger(root, "NRPC", interceptor, neutrinorpc.UseLogger)
+	AddSubLogger(root, "ERPC", interceptor, electrumrpc.UseLogger)
 	AddSubLogger(root, "DRPC", interceptor, devrpc.UseLogger)
 	AddSubLogger(root, "INVC", interceptor, invoices.UseLogger)
 	AddSubLogger(root, "NANN", interceptor, netann.UseLogger)
@@ -162,11 +168,13 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(root, "CHNF", interceptor, channelnotifier.UseLogger)
 	AddSubLogger(root, "CHBU", interceptor, chanbackup.UseLogger)
 	AddSubLogger(root, "PROM", interceptor, monitoring.UseLogger)
+	AddSubLogger(root, "TRCE", interceptor, tracing.UseLogger)
 	AddSubLogger(root, "WTCL", interceptor, wtclient.UseLogger)
 	AddSubLogger(root, "PRNF", interceptor, peernotifier.UseLogger)
 	AddSubLogger(root, "CHFD", interceptor, chanfunding.UseLogger)
 	AddSubLogger(root, "PEER", interceptor, peer.UseLogger)
 	AddSubLogger(root, "CHCL", interceptor, chancloser.UseLogger)
+	AddSubLogger(root, "SPLC", interceptor, chansplicer.UseLogger)
 
 	AddSubLogger(root, routing.Subsystem, interceptor, routing.UseLogger)
 	AddSubLogger(root, routerrpc.Subsystem, interceptor, routerrpc.UseLogger)
@@ -185,6 +193,9 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(root, "ELEC", interceptor, electrum.UseLogger)
 	AddSubLogger(root, "ELNF", interceptor, electrumnotify.UseLogger)
 	AddSubLogger(root, "MWBP", interceptor, mwebp2p.UseLogger)
+	AddSubLogger(root, onionmessage.Subsystem, interceptor,
+		onionmessage.UseLogger)
+	AddSubLogger(root, offers.Subsystem, interceptor, offers.UseLogger)
 }
 
 // AddSubLogger is a helper method to conveniently create and register the
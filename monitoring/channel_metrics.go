@@ -0,0 +1,266 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channelnotifier"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// channelCollector exports the number of channels by state and the balances of
+// the open channels, which are queried on every scrape, along with counters of
+// the events of the channel notifier.
+type channelCollector struct {
+	cfg *NodeMetricsConfig
+
+	channels       *prometheus.Desc
+	balance        *prometheus.Desc
+	capacity       *prometheus.Desc
+	pendingBalance *prometheus.Desc
+
+	events *prometheus.CounterVec
+	closes *prometheus.CounterVec
+
+	tracker *eventTracker
+}
+
+// newChannelCollector creates a channel collector for the given subsystems.
+func newChannelCollector(cfg *NodeMetricsConfig) *channelCollector {
+	c := &channelCollector{
+		cfg: cfg,
+		channels: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "channels", "count"),
+			"Number of channels by state.",
+			[]string{"state"}, nil,
+		),
+		balance: prometheus.NewDesc(
+			prometheus.BuildFQName(
+				namespace, "channels", "balance_msat",
+			),
+			"Total local and remote balance of all open channels.",
+			[]string{"side"}, nil,
+		),
+		capacity: prometheus.NewDesc(
+			prometheus.BuildFQName(
+				namespace, "channels", "capacity_sat",
+			),
+			"Total capacity of all open channels.",
+			nil, nil,
+		),
+		pendingBalance: prometheus.NewDesc(
+			prometheus.BuildFQName(
+				namespace, "channels", "pending_balance_msat",
+			),
+			"Total local balance of channels by pending state.",
+			[]string{"state"}, nil,
+		),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "channels",
+			Name:      "events_total",
+			Help:      "Number of channel events by type.",
+		}, []string{"type"}),
+		closes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "channels",
+			Name:      "closed_total",
+			Help:      "Number of closed channels by close type.",
+		}, []string{"close_type"}),
+	}
+
+	if cfg.SubscribeChannelEvents != nil {
+		c.tracker = newEventTracker(
+			cfg.SubscribeChannelEvents, c.handleEvent,
+		)
+	}
+
+	return c
+}
+
+// start starts tracking the events of the channel notifier.
+func (c *channelCollector) start() error {
+	if c.tracker == nil {
+		return nil
+	}
+
+	return c.tracker.start()
+}
+
+// stop stops tracking the events of the channel notifier.
+func (c *channelCollector) stop() {
+	if c.tracker != nil {
+		c.tracker.stop()
+	}
+}
+
+// handleEvent counts a single event of the channel notifier.
+func (c *channelCollector) handleEvent(update interface{}) {
+	var eventType string
+	switch event := update.(type) {
+	case channelnotifier.PendingOpenChannelEvent:
+		eventType = "pending_open"
+
+	case channelnotifier.OpenChannelEvent:
+		eventType = "open"
+
+	case channelnotifier.ActiveChannelEvent:
+		eventType = "active"
+
+	case channelnotifier.InactiveChannelEvent:
+		eventType = "inactive"
+
+	case channelnotifier.ClosedChannelEvent:
+		eventType = "closed"
+
+		if event.CloseSummary != nil {
+			c.closes.WithLabelValues(
+				closeTypeLabel(event.CloseSummary.CloseType),
+			).Inc()
+		}
+
+	case channelnotifier.FullyResolvedChannelEvent:
+		eventType = "fully_resolved"
+
+	// The link events are reported along with the channel events, so we
+	// don't count them separately.
+	default:
+		return
+	}
+
+	c.events.WithLabelValues(eventType).Inc()
+}
+
+// Describe sends the descriptors of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *channelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.channels
+	ch <- c.balance
+	ch <- c.capacity
+	ch <- c.pendingBalance
+	c.events.Describe(ch)
+	c.closes.Describe(ch)
+}
+
+// Collect queries the channels of the node and sends the resulting metrics to
+// the given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *channelCollector) Collect(ch chan<- prometheus.Metric) {
+	c.events.Collect(ch)
+	c.closes.Collect(ch)
+
+	openChannels, err := c.cfg.FetchOpenChannels()
+	if err != nil {
+		log.Errorf("Unable to fetch open channels: %v", err)
+		return
+	}
+
+	var (
+		numActive, numInactive      int
+		localBalance, remoteBalance lnwire.MilliSatoshi
+		capacity                    float64
+	)
+	for _, channel := range openChannels {
+		chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+		if c.cfg.IsChannelActive != nil &&
+			c.cfg.IsChannelActive(chanID) {
+
+			numActive++
+		} else {
+			numInactive++
+		}
+
+		localBalance += channel.LocalCommitment.LocalBalance
+		remoteBalance += channel.LocalCommitment.RemoteBalance
+		capacity += float64(channel.Capacity)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.channels, prometheus.GaugeValue, float64(numActive),
+		"active",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.channels, prometheus.GaugeValue, float64(numInactive),
+		"inactive",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.balance, prometheus.GaugeValue, float64(localBalance),
+		"local",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.balance, prometheus.GaugeValue, float64(remoteBalance),
+		"remote",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.capacity, prometheus.GaugeValue, capacity,
+	)
+
+	c.collectPending(
+		ch, "pending_open", c.cfg.FetchPendingChannels,
+	)
+	c.collectPending(
+		ch, "waiting_close", c.cfg.FetchWaitingCloseChannels,
+	)
+}
+
+// collectPending sends the number and local balance of the channels returned
+// by the given function to the channel, labeled with the given state.
+func (c *channelCollector) collectPending(ch chan<- prometheus.Metric,
+	state string, fetch func() ([]*channeldb.OpenChannel, error)) {
+
+	if fetch == nil {
+		return
+	}
+
+	channels, err := fetch()
+	if err != nil {
+		log.Errorf("Unable to fetch %v channels: %v", state, err)
+		return
+	}
+
+	var localBalance lnwire.MilliSatoshi
+	for _, channel := range channels {
+		localBalance += channel.LocalCommitment.LocalBalance
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.channels, prometheus.GaugeValue, float64(len(channels)),
+		state,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.pendingBalance, prometheus.GaugeValue, float64(localBalance),
+		state,
+	)
+}
+
+// closeTypeLabel returns the label under which channel closes of the given
+// type are reported.
+func closeTypeLabel(closeType channeldb.ClosureType) string {
+	switch closeType {
+	case channeldb.CooperativeClose:
+		return "cooperative"
+
+	case channeldb.LocalForceClose:
+		return "local_force"
+
+	case channeldb.RemoteForceClose:
+		return "remote_force"
+
+	case channeldb.BreachClose:
+		return "breach"
+
+	case channeldb.FundingCanceled:
+		return "funding_canceled"
+
+	case channeldb.Abandoned:
+		return "abandoned"
+
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,248 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"sync"
+
+	"github.com/ltcsuite/lnd/htlcswitch"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// forwardSettled is the outcome of a forward that was settled.
+	forwardSettled = "settled"
+
+	// forwardFailed is the outcome of a forward that failed downstream.
+	forwardFailed = "failed"
+
+	// forwardRejected is the outcome of a forward that failed at our
+	// node.
+	forwardRejected = "rejected"
+)
+
+// htlcCollector exports the forwarding volume, fees and failures of the node,
+// as reported by the htlc notifier.
+type htlcCollector struct {
+	chanLabels    *labelLimiter
+	failureLabels *labelLimiter
+
+	forwards       *prometheus.CounterVec
+	forwardAmounts *prometheus.CounterVec
+	fees           prometheus.Counter
+	failures       *prometheus.CounterVec
+	chanAmounts    *prometheus.CounterVec
+	inFlight       prometheus.GaugeFunc
+
+	// pending holds the details of the forwards that haven't been
+	// resolved yet, as the settle and fail events don't carry them.
+	pendingMtx sync.Mutex
+	pending    map[htlcswitch.HtlcKey]htlcswitch.HtlcInfo
+
+	tracker *eventTracker
+}
+
+// newHtlcCollector creates an htlc collector for the given subsystems. The
+// per channel metrics are labeled using the given label limiter.
+func newHtlcCollector(cfg *NodeMetricsConfig,
+	chanLabels *labelLimiter) *htlcCollector {
+
+	c := &htlcCollector{
+		chanLabels:    chanLabels,
+		failureLabels: newLabelLimiter(maxFailureLabels),
+		forwards: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "forwards",
+			Name:      "total",
+			Help:      "Number of resolved forwards by outcome.",
+		}, []string{"outcome"}),
+		forwardAmounts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "forwards",
+			Name:      "amount_msat_total",
+			Help: "Outgoing amount of resolved forwards by " +
+				"outcome.",
+		}, []string{"outcome"}),
+		fees: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "forwards",
+			Name:      "fees_msat_total",
+			Help:      "Fees earned by settled forwards.",
+		}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "forwards",
+			Name:      "failures_total",
+			Help: "Number of forwards that failed at our node by " +
+				"reason.",
+		}, []string{"reason"}),
+		chanAmounts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "forwards",
+			Name:      "channel_amount_msat_total",
+			Help: "Amount of settled forwards by channel and " +
+				"direction.",
+		}, []string{"chan_id", "direction"}),
+		pending: make(map[htlcswitch.HtlcKey]htlcswitch.HtlcInfo),
+	}
+
+	c.inFlight = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "forwards",
+		Name:      "in_flight",
+		Help:      "Number of forwards that are not yet resolved.",
+	}, func() float64 {
+		c.pendingMtx.Lock()
+		defer c.pendingMtx.Unlock()
+
+		return float64(len(c.pending))
+	})
+
+	c.tracker = newEventTracker(cfg.SubscribeHtlcEvents, c.handleEvent)
+
+	return c
+}
+
+// start starts tracking the events of the htlc notifier.
+func (c *htlcCollector) start() error {
+	return c.tracker.start()
+}
+
+// stop stops tracking the events of the htlc notifier.
+func (c *htlcCollector) stop() {
+	c.tracker.stop()
+}
+
+// handleEvent updates the metrics for a single event of the htlc notifier.
+// Only forwards are tracked, as local payments are covered by the payment
+// collector.
+func (c *htlcCollector) handleEvent(update interface{}) {
+	switch event := update.(type) {
+	case *htlcswitch.ForwardingEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		c.pendingMtx.Lock()
+		c.pending[event.HtlcKey] = event.HtlcInfo
+		c.pendingMtx.Unlock()
+
+	case *htlcswitch.SettleEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		info, ok := c.resolve(event.HtlcKey)
+		c.forwards.WithLabelValues(forwardSettled).Inc()
+		if !ok {
+			return
+		}
+
+		c.forwardAmounts.WithLabelValues(forwardSettled).Add(
+			float64(info.OutgoingAmt),
+		)
+		if info.IncomingAmt > info.OutgoingAmt {
+			c.fees.Add(float64(info.IncomingAmt - info.OutgoingAmt))
+		}
+
+		c.chanAmounts.WithLabelValues(
+			c.chanLabel(event.IncomingCircuit.ChanID), "in",
+		).Add(float64(info.IncomingAmt))
+		c.chanAmounts.WithLabelValues(
+			c.chanLabel(event.OutgoingCircuit.ChanID), "out",
+		).Add(float64(info.OutgoingAmt))
+
+	case *htlcswitch.ForwardingFailEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		info, ok := c.resolve(event.HtlcKey)
+		c.forwards.WithLabelValues(forwardFailed).Inc()
+		if ok {
+			c.forwardAmounts.WithLabelValues(forwardFailed).Add(
+				float64(info.OutgoingAmt),
+			)
+		}
+
+	case *htlcswitch.LinkFailEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		c.resolve(event.HtlcKey)
+		c.forwards.WithLabelValues(forwardRejected).Inc()
+		c.forwardAmounts.WithLabelValues(forwardRejected).Add(
+			float64(event.OutgoingAmt),
+		)
+		reason := linkFailureReason(event.LinkError)
+		c.failures.WithLabelValues(
+			c.failureLabels.label(reason),
+		).Inc()
+	}
+}
+
+// resolve removes the forward with the given key from the set of pending
+// forwards and returns its details, if known.
+func (c *htlcCollector) resolve(
+	key htlcswitch.HtlcKey) (htlcswitch.HtlcInfo, bool) {
+
+	c.pendingMtx.Lock()
+	defer c.pendingMtx.Unlock()
+
+	info, ok := c.pending[key]
+	delete(c.pending, key)
+
+	return info, ok
+}
+
+// chanLabel returns the label under which the given channel is reported.
+func (c *htlcCollector) chanLabel(chanID lnwire.ShortChannelID) string {
+	return c.chanLabels.label(chanID.String())
+}
+
+// linkFailureReason returns a short description of the given link error that
+// is reported as the failure reason.
+func linkFailureReason(linkErr *htlcswitch.LinkError) string {
+	switch {
+	case linkErr == nil:
+		return "unknown"
+
+	case linkErr.FailureDetail != nil:
+		return linkErr.FailureDetail.FailureString()
+
+	case linkErr.WireMessage() != nil:
+		return linkErr.WireMessage().Code().String()
+
+	default:
+		return "unknown"
+	}
+}
+
+// Describe sends the descriptors of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *htlcCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.forwards.Describe(ch)
+	c.forwardAmounts.Describe(ch)
+	c.fees.Describe(ch)
+	c.failures.Describe(ch)
+	c.chanAmounts.Describe(ch)
+	c.inFlight.Describe(ch)
+}
+
+// Collect sends the current values of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *htlcCollector) Collect(ch chan<- prometheus.Metric) {
+	c.forwards.Collect(ch)
+	c.forwardAmounts.Collect(ch)
+	c.fees.Collect(ch)
+	c.failures.Collect(ch)
+	c.chanAmounts.Collect(ch)
+	c.inFlight.Collect(ch)
+}
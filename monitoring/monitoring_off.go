@@ -23,3 +23,26 @@ func ExportPrometheusMetrics(_ *grpc.Server, _ lncfg.Prometheus) error {
 	return fmt.Errorf("lnd must be built with the monitoring tag to " +
 		"enable exporting Prometheus metrics")
 }
+
+// NodeMetrics is required for lnd to compile so that the node metrics can be
+// hidden behind a build tag.
+type NodeMetrics struct{}
+
+// NewNodeMetrics is required for lnd to compile so that the node metrics can
+// be hidden behind a build tag.
+func NewNodeMetrics(_ *NodeMetricsConfig, _ lncfg.Prometheus) *NodeMetrics {
+	return &NodeMetrics{}
+}
+
+// Start is required for lnd to compile so that the node metrics can be hidden
+// behind a build tag.
+func (m *NodeMetrics) Start() error {
+	return fmt.Errorf("lnd must be built with the monitoring tag to " +
+		"enable exporting node metrics")
+}
+
+// Stop is required for lnd to compile so that the node metrics can be hidden
+// behind a build tag.
+func (m *NodeMetrics) Stop() error {
+	return nil
+}
@@ -0,0 +1,233 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ltcsuite/lnd/lncfg"
+	"github.com/ltcsuite/lnd/subscribe"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// namespace is the namespace of all node metrics.
+	namespace = "lnd"
+
+	// otherLabel is the label value that is reported once a label has
+	// reached its maximum number of distinct values.
+	otherLabel = "other"
+
+	// maxFailureLabels is the maximum number of distinct failure reasons
+	// that are reported as a label.
+	maxFailureLabels = 64
+)
+
+// nodeCollector is a Prometheus collector that gathers metrics of a single
+// node subsystem.
+type nodeCollector interface {
+	prometheus.Collector
+
+	// start launches any goroutines the collector needs to track the
+	// events of its subsystem.
+	start() error
+
+	// stop signals all goroutines of the collector to exit.
+	stop()
+}
+
+// NodeMetrics exports metrics about the internals of the node, such as its
+// channels, forwards and payments, to Prometheus. The metrics are gathered
+// from the existing notifiers of the node, or are queried from the node
+// subsystems when Prometheus scrapes them.
+type NodeMetrics struct {
+	started sync.Once
+	stopped sync.Once
+
+	collectors []nodeCollector
+}
+
+// NewNodeMetrics creates the node metrics for the given subsystems.
+func NewNodeMetrics(cfg *NodeMetricsConfig,
+	promCfg lncfg.Prometheus) *NodeMetrics {
+
+	chanLabels := newLabelLimiter(promCfg.MaxChannelLabels)
+
+	var collectors []nodeCollector
+	if cfg.FetchOpenChannels != nil {
+		collectors = append(collectors, newChannelCollector(cfg))
+	}
+	if cfg.SubscribeHtlcEvents != nil {
+		collectors = append(
+			collectors, newHtlcCollector(cfg, chanLabels),
+		)
+	}
+	if cfg.SubscribePayments != nil {
+		collectors = append(collectors, newPaymentCollector(cfg))
+	}
+	if cfg.PendingSweeps != nil {
+		collectors = append(collectors, newSweeperCollector(cfg))
+	}
+	if len(cfg.TowerClients) > 0 {
+		collectors = append(collectors, newTowerCollector(cfg))
+	}
+	if cfg.NumPeers != nil {
+		collectors = append(collectors, newPeerCollector(cfg))
+	}
+	if cfg.ChainIO != nil {
+		collectors = append(collectors, newChainCollector(cfg))
+	}
+
+	return &NodeMetrics{
+		collectors: collectors,
+	}
+}
+
+// Start registers all collectors with the default Prometheus registry, so
+// that they're exported by the Prometheus exporter, and starts tracking the
+// events of the node.
+func (m *NodeMetrics) Start() error {
+	var startErr error
+	m.started.Do(func() {
+		log.Infof("Starting node metrics")
+
+		for _, c := range m.collectors {
+			if err := c.start(); err != nil {
+				startErr = err
+				return
+			}
+
+			if err := prometheus.Register(c); err != nil {
+				startErr = fmt.Errorf("unable to register "+
+					"collector: %w", err)
+				return
+			}
+		}
+	})
+
+	// Make sure we don't leave any of the collectors that were already
+	// started running if we failed to start all of them.
+	if startErr != nil {
+		_ = m.Stop()
+	}
+
+	return startErr
+}
+
+// Stop unregisters all collectors and stops tracking the events of the node.
+func (m *NodeMetrics) Stop() error {
+	m.stopped.Do(func() {
+		log.Infof("Stopping node metrics")
+
+		for _, c := range m.collectors {
+			prometheus.Unregister(c)
+			c.stop()
+		}
+	})
+
+	return nil
+}
+
+// eventTracker runs a goroutine that passes all updates of a notifier
+// subscription to a handler.
+type eventTracker struct {
+	subscribe func() (*subscribe.Client, error)
+	handle    func(interface{})
+
+	client *subscribe.Client
+	wg     sync.WaitGroup
+	quit   chan struct{}
+}
+
+// newEventTracker creates an event tracker that passes the updates of the
+// subscriptions created by the given function to the handler.
+func newEventTracker(subscribe func() (*subscribe.Client, error),
+	handle func(interface{})) *eventTracker {
+
+	return &eventTracker{
+		subscribe: subscribe,
+		handle:    handle,
+		quit:      make(chan struct{}),
+	}
+}
+
+// start subscribes to the notifier and starts handling its updates.
+func (e *eventTracker) start() error {
+	client, err := e.subscribe()
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to events: %w", err)
+	}
+	e.client = client
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		for {
+			select {
+			case update, ok := <-client.Updates():
+				if !ok {
+					return
+				}
+
+				e.handle(update)
+
+			case <-client.Quit():
+				return
+
+			case <-e.quit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// stop cancels the subscription and waits for the handler to exit.
+func (e *eventTracker) stop() {
+	close(e.quit)
+	if e.client != nil {
+		e.client.Cancel()
+	}
+	e.wg.Wait()
+}
+
+// labelLimiter bounds the number of distinct values of a label, so that high
+// cardinality labels such as channel IDs can't blow up the number of time
+// series exported. Once the limit is reached, any new value is reported as
+// otherLabel.
+type labelLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	values map[string]struct{}
+}
+
+// newLabelLimiter creates a label limiter that allows the given number of
+// distinct values. A limit of zero disables the label entirely, so all values
+// are reported as otherLabel.
+func newLabelLimiter(limit int) *labelLimiter {
+	return &labelLimiter{
+		limit:  limit,
+		values: make(map[string]struct{}),
+	}
+}
+
+// label returns the label value to report for the given value.
+func (l *labelLimiter) label(value string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.values[value]; ok {
+		return value
+	}
+
+	if len(l.values) >= l.limit {
+		return otherLabel
+	}
+	l.values[value] = struct{}{}
+
+	return value
+}
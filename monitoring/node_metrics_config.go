@@ -0,0 +1,63 @@
+package monitoring
+
+import (
+	"github.com/ltcsuite/lnd/chainntnfs"
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing"
+	"github.com/ltcsuite/lnd/subscribe"
+	"github.com/ltcsuite/lnd/sweep"
+	"github.com/ltcsuite/lnd/watchtower/wtclient"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// NodeMetricsConfig holds the node subsystems that the Prometheus collectors
+// gather their metrics from. Any subsystem that is left nil is skipped.
+type NodeMetricsConfig struct {
+	// FetchOpenChannels returns all open channels of the node.
+	FetchOpenChannels func() ([]*channeldb.OpenChannel, error)
+
+	// FetchPendingChannels returns all channels that are pending open.
+	FetchPendingChannels func() ([]*channeldb.OpenChannel, error)
+
+	// FetchWaitingCloseChannels returns all channels that are waiting for
+	// their closing transaction to confirm.
+	FetchWaitingCloseChannels func() ([]*channeldb.OpenChannel, error)
+
+	// IsChannelActive returns whether the link of the given channel is
+	// active in the switch.
+	IsChannelActive func(lnwire.ChannelID) bool
+
+	// SubscribeChannelEvents subscribes to the events of the channel
+	// notifier.
+	SubscribeChannelEvents func() (*subscribe.Client, error)
+
+	// SubscribeHtlcEvents subscribes to the events of the htlc notifier.
+	SubscribeHtlcEvents func() (*subscribe.Client, error)
+
+	// SubscribePayments subscribes to the updates of all payments of the
+	// control tower.
+	SubscribePayments func() (routing.ControlTowerSubscriber, error)
+
+	// PendingSweeps returns the inputs that are currently being swept by
+	// the sweeper.
+	PendingSweeps func() (map[wire.OutPoint]*sweep.PendingInput, error)
+
+	// TowerClients holds the active watchtower clients, keyed by the label
+	// that their metrics are reported under.
+	TowerClients map[string]wtclient.Client
+
+	// NumPeers returns the number of peers that we're connected to.
+	NumPeers func() int
+
+	// SubscribePeerEvents subscribes to the events of the peer notifier.
+	SubscribePeerEvents func() (*subscribe.Client, error)
+
+	// ChainIO is used to query the chain backend for its best block.
+	ChainIO lnwallet.BlockChainIO
+
+	// ChainNotifier is used to track the best block that the node has
+	// processed, which is compared to the best block of the chain backend.
+	ChainNotifier chainntnfs.ChainNotifier
+}
@@ -0,0 +1,101 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/htlcswitch"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLabelLimiter asserts that the label limiter reports values that exceed
+// its limit under the other label.
+func TestLabelLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLabelLimiter(2)
+	require.Equal(t, "a", limiter.label("a"))
+	require.Equal(t, "b", limiter.label("b"))
+	require.Equal(t, otherLabel, limiter.label("c"))
+
+	// Values that were already reported keep their own label.
+	require.Equal(t, "a", limiter.label("a"))
+
+	// A limit of zero reports all values under the other label.
+	limiter = newLabelLimiter(0)
+	require.Equal(t, otherLabel, limiter.label("a"))
+}
+
+// TestHtlcCollectorForwards asserts that the htlc collector counts the
+// outcomes, amounts and fees of forwards and ignores local payments.
+func TestHtlcCollectorForwards(t *testing.T) {
+	t.Parallel()
+
+	c := newHtlcCollector(&NodeMetricsConfig{}, newLabelLimiter(1))
+
+	incoming := lnwire.NewShortChanIDFromInt(1)
+	outgoing := lnwire.NewShortChanIDFromInt(2)
+	key := func(htlcID uint64) htlcswitch.HtlcKey {
+		return htlcswitch.HtlcKey{
+			IncomingCircuit: htlcswitch.CircuitKey{
+				ChanID: incoming,
+				HtlcID: htlcID,
+			},
+			OutgoingCircuit: htlcswitch.CircuitKey{
+				ChanID: outgoing,
+				HtlcID: htlcID,
+			},
+		}
+	}
+	info := htlcswitch.HtlcInfo{
+		IncomingAmt: 1_100,
+		OutgoingAmt: 1_000,
+	}
+
+	// Forward two htlcs, of which one settles and one fails downstream.
+	for htlcID := uint64(0); htlcID < 2; htlcID++ {
+		c.handleEvent(&htlcswitch.ForwardingEvent{
+			HtlcKey:       key(htlcID),
+			HtlcInfo:      info,
+			HtlcEventType: htlcswitch.HtlcEventTypeForward,
+		})
+	}
+	require.Equal(t, 2.0, testutil.ToFloat64(c.inFlight))
+
+	c.handleEvent(&htlcswitch.SettleEvent{
+		HtlcKey:       key(0),
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+	})
+	c.handleEvent(&htlcswitch.ForwardingFailEvent{
+		HtlcKey:       key(1),
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+	})
+
+	// Settles of local payments must not be counted as forwards.
+	c.handleEvent(&htlcswitch.SettleEvent{
+		HtlcKey:       key(2),
+		HtlcEventType: htlcswitch.HtlcEventTypeSend,
+	})
+
+	require.Equal(t, 0.0, testutil.ToFloat64(c.inFlight))
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		c.forwards.WithLabelValues(forwardSettled),
+	))
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		c.forwards.WithLabelValues(forwardFailed),
+	))
+	require.Equal(t, 100.0, testutil.ToFloat64(c.fees))
+
+	// Only the first channel fits within the channel label limit, so the
+	// outgoing channel is reported under the other label.
+	require.Equal(t, 1_100.0, testutil.ToFloat64(
+		c.chanAmounts.WithLabelValues(incoming.String(), "in"),
+	))
+	require.Equal(t, 1_000.0, testutil.ToFloat64(
+		c.chanAmounts.WithLabelValues(otherLabel, "out"),
+	))
+}
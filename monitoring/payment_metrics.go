@@ -0,0 +1,181 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/routing"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// paymentCollector exports the outcomes and attempt counts of the payments
+// sent by the node, as reported by the control tower.
+type paymentCollector struct {
+	cfg *NodeMetricsConfig
+
+	payments     *prometheus.CounterVec
+	amounts      *prometheus.CounterVec
+	fees         prometheus.Counter
+	attempts     *prometheus.CounterVec
+	attemptsHist prometheus.Histogram
+
+	subscriber routing.ControlTowerSubscriber
+	wg         sync.WaitGroup
+	quit       chan struct{}
+}
+
+// newPaymentCollector creates a payment collector for the given subsystems.
+func newPaymentCollector(cfg *NodeMetricsConfig) *paymentCollector {
+	return &paymentCollector{
+		cfg: cfg,
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "payments",
+			Name:      "total",
+			Help: "Number of completed payments by status and " +
+				"failure reason.",
+		}, []string{"status", "reason"}),
+		amounts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "payments",
+			Name:      "amount_msat_total",
+			Help:      "Amount of completed payments by status.",
+		}, []string{"status"}),
+		fees: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "payments",
+			Name:      "fees_msat_total",
+			Help:      "Fees paid by succeeded payments.",
+		}),
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "payments",
+			Name:      "attempts_total",
+			Help: "Number of htlc attempts of completed payments " +
+				"by outcome.",
+		}, []string{"outcome"}),
+		attemptsHist: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "payments",
+			Name:      "attempts",
+			Help: "Distribution of the number of htlc attempts " +
+				"of completed payments.",
+			Buckets: []float64{1, 2, 3, 5, 8, 13, 21, 34, 55},
+		}),
+		quit: make(chan struct{}),
+	}
+}
+
+// start subscribes to the updates of all payments.
+func (c *paymentCollector) start() error {
+	subscriber, err := c.cfg.SubscribePayments()
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to payments: %w", err)
+	}
+	c.subscriber = subscriber
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		for {
+			select {
+			case update, ok := <-subscriber.Updates():
+				if !ok {
+					return
+				}
+
+				payment, ok := update.(*channeldb.MPPayment)
+				if ok {
+					c.handlePayment(payment)
+				}
+
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// stop cancels the payment subscription.
+func (c *paymentCollector) stop() {
+	close(c.quit)
+	c.wg.Wait()
+
+	if c.subscriber != nil {
+		c.subscriber.Close()
+	}
+}
+
+// handlePayment updates the metrics for a payment update. Only payments that
+// reached a final state are counted.
+func (c *paymentCollector) handlePayment(payment *channeldb.MPPayment) {
+	var status, reason string
+	switch payment.Status {
+	case channeldb.StatusSucceeded:
+		status, reason = "succeeded", "none"
+
+	case channeldb.StatusFailed:
+		status, reason = "failed", "unknown"
+		if payment.FailureReason != nil {
+			reason = payment.FailureReason.String()
+		}
+
+	default:
+		return
+	}
+
+	c.payments.WithLabelValues(status, reason).Inc()
+	c.attemptsHist.Observe(float64(len(payment.HTLCs)))
+
+	for _, htlc := range payment.HTLCs {
+		switch {
+		case htlc.Settle != nil:
+			c.attempts.WithLabelValues("settled").Inc()
+
+		case htlc.Failure != nil:
+			c.attempts.WithLabelValues("failed").Inc()
+		}
+	}
+
+	if payment.Info != nil {
+		c.amounts.WithLabelValues(status).Add(
+			float64(payment.Info.Value),
+		)
+	}
+
+	if payment.Status == channeldb.StatusSucceeded {
+		_, fees := payment.SentAmt()
+		c.fees.Add(float64(fees))
+	}
+}
+
+// Describe sends the descriptors of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *paymentCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.payments.Describe(ch)
+	c.amounts.Describe(ch)
+	c.fees.Describe(ch)
+	c.attempts.Describe(ch)
+	c.attemptsHist.Describe(ch)
+}
+
+// Collect sends the current values of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *paymentCollector) Collect(ch chan<- prometheus.Metric) {
+	c.payments.Collect(ch)
+	c.amounts.Collect(ch)
+	c.fees.Collect(ch)
+	c.attempts.Collect(ch)
+	c.attemptsHist.Collect(ch)
+}
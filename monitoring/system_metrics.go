@@ -0,0 +1,421 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ltcsuite/lnd/peernotifier"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// sweeperCollector exports the inputs that are pending in the sweeper, which
+// are queried on every scrape.
+type sweeperCollector struct {
+	cfg *NodeMetricsConfig
+
+	inputs *prometheus.Desc
+	amount *prometheus.Desc
+}
+
+// newSweeperCollector creates a sweeper collector for the given subsystems.
+func newSweeperCollector(cfg *NodeMetricsConfig) *sweeperCollector {
+	return &sweeperCollector{
+		cfg: cfg,
+		inputs: prometheus.NewDesc(
+			prometheus.BuildFQName(
+				namespace, "sweeper", "pending_inputs",
+			),
+			"Number of inputs that are pending in the sweeper.",
+			nil, nil,
+		),
+		amount: prometheus.NewDesc(
+			prometheus.BuildFQName(
+				namespace, "sweeper", "pending_amount_sat",
+			),
+			"Total amount of the inputs that are pending in the "+
+				"sweeper.",
+			nil, nil,
+		),
+	}
+}
+
+// start is a no-op, as the sweeper is only queried on scrapes.
+func (c *sweeperCollector) start() error {
+	return nil
+}
+
+// stop is a no-op, as the sweeper is only queried on scrapes.
+func (c *sweeperCollector) stop() {}
+
+// Describe sends the descriptors of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *sweeperCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.inputs
+	ch <- c.amount
+}
+
+// Collect queries the pending inputs of the sweeper and sends the resulting
+// metrics to the given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *sweeperCollector) Collect(ch chan<- prometheus.Metric) {
+	inputs, err := c.cfg.PendingSweeps()
+	if err != nil {
+		log.Errorf("Unable to fetch pending sweeps: %v", err)
+		return
+	}
+
+	var amount float64
+	for _, input := range inputs {
+		amount += float64(input.Amount)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.inputs, prometheus.GaugeValue, float64(len(inputs)),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.amount, prometheus.GaugeValue, amount,
+	)
+}
+
+// towerCollector exports the statistics of the watchtower clients, which are
+// queried on every scrape.
+type towerCollector struct {
+	cfg *NodeMetricsConfig
+
+	tasksPending      *prometheus.Desc
+	tasksAccepted     *prometheus.Desc
+	tasksIneligible   *prometheus.Desc
+	sessionsAcquired  *prometheus.Desc
+	sessionsExhausted *prometheus.Desc
+}
+
+// newTowerCollector creates a watchtower client collector for the given
+// subsystems.
+func newTowerCollector(cfg *NodeMetricsConfig) *towerCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "wtclient", name),
+			help, []string{"client"}, nil,
+		)
+	}
+
+	return &towerCollector{
+		cfg: cfg,
+		tasksPending: desc(
+			"tasks_pending", "Number of backups that are "+
+				"pending to be acknowledged by a tower.",
+		),
+		tasksAccepted: desc(
+			"tasks_accepted_total", "Number of backups that "+
+				"were accepted by a tower.",
+		),
+		tasksIneligible: desc(
+			"tasks_ineligible_total", "Number of backups that "+
+				"no tower session was able to accept.",
+		),
+		sessionsAcquired: desc(
+			"sessions_acquired_total", "Number of sessions "+
+				"that were negotiated with towers.",
+		),
+		sessionsExhausted: desc(
+			"sessions_exhausted_total", "Number of tower "+
+				"sessions that were exhausted.",
+		),
+	}
+}
+
+// start is a no-op, as the tower clients are only queried on scrapes.
+func (c *towerCollector) start() error {
+	return nil
+}
+
+// stop is a no-op, as the tower clients are only queried on scrapes.
+func (c *towerCollector) stop() {}
+
+// Describe sends the descriptors of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *towerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tasksPending
+	ch <- c.tasksAccepted
+	ch <- c.tasksIneligible
+	ch <- c.sessionsAcquired
+	ch <- c.sessionsExhausted
+}
+
+// Collect queries the statistics of the tower clients and sends the resulting
+// metrics to the given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *towerCollector) Collect(ch chan<- prometheus.Metric) {
+	for label, client := range c.cfg.TowerClients {
+		stats := client.Stats()
+
+		ch <- prometheus.MustNewConstMetric(
+			c.tasksPending, prometheus.GaugeValue,
+			float64(stats.NumTasksPending), label,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.tasksAccepted, prometheus.CounterValue,
+			float64(stats.NumTasksAccepted), label,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.tasksIneligible, prometheus.CounterValue,
+			float64(stats.NumTasksIneligible), label,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.sessionsAcquired, prometheus.CounterValue,
+			float64(stats.NumSessionsAcquired), label,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.sessionsExhausted, prometheus.CounterValue,
+			float64(stats.NumSessionsExhausted), label,
+		)
+	}
+}
+
+// peerCollector exports the number of connected peers, which is queried on
+// every scrape, along with the connection churn reported by the peer
+// notifier.
+type peerCollector struct {
+	cfg *NodeMetricsConfig
+
+	peers  *prometheus.Desc
+	events *prometheus.CounterVec
+
+	tracker *eventTracker
+}
+
+// newPeerCollector creates a peer collector for the given subsystems.
+func newPeerCollector(cfg *NodeMetricsConfig) *peerCollector {
+	c := &peerCollector{
+		cfg: cfg,
+		peers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peers", "connected"),
+			"Number of connected peers.",
+			nil, nil,
+		),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "peers",
+			Name:      "events_total",
+			Help:      "Number of peer connection events by type.",
+		}, []string{"type"}),
+	}
+
+	if cfg.SubscribePeerEvents != nil {
+		c.tracker = newEventTracker(
+			cfg.SubscribePeerEvents, c.handleEvent,
+		)
+	}
+
+	return c
+}
+
+// start starts tracking the events of the peer notifier.
+func (c *peerCollector) start() error {
+	if c.tracker == nil {
+		return nil
+	}
+
+	return c.tracker.start()
+}
+
+// stop stops tracking the events of the peer notifier.
+func (c *peerCollector) stop() {
+	if c.tracker != nil {
+		c.tracker.stop()
+	}
+}
+
+// handleEvent counts a single event of the peer notifier.
+func (c *peerCollector) handleEvent(update interface{}) {
+	switch update.(type) {
+	case peernotifier.PeerOnlineEvent:
+		c.events.WithLabelValues("online").Inc()
+
+	case peernotifier.PeerOfflineEvent:
+		c.events.WithLabelValues("offline").Inc()
+	}
+}
+
+// Describe sends the descriptors of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *peerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.peers
+	c.events.Describe(ch)
+}
+
+// Collect sends the current values of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *peerCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(
+		c.peers, prometheus.GaugeValue, float64(c.cfg.NumPeers()),
+	)
+	c.events.Collect(ch)
+}
+
+// chainCollector exports the best block of the chain backend and how far the
+// node lags behind it in processing blocks.
+type chainCollector struct {
+	cfg *NodeMetricsConfig
+
+	backendHeight *prometheus.Desc
+	nodeHeight    *prometheus.Desc
+	lag           *prometheus.Desc
+	blockAge      *prometheus.Desc
+
+	// notifierHeight is the height of the last block epoch received from
+	// the chain notifier.
+	notifierHeight int32
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// newChainCollector creates a chain collector for the given subsystems.
+func newChainCollector(cfg *NodeMetricsConfig) *chainCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "chain", name),
+			help, nil, nil,
+		)
+	}
+
+	return &chainCollector{
+		cfg: cfg,
+		backendHeight: desc(
+			"backend_height", "Height of the best block of the "+
+				"chain backend.",
+		),
+		nodeHeight: desc(
+			"node_height", "Height of the best block processed "+
+				"by the node.",
+		),
+		lag: desc(
+			"lag_blocks", "Number of blocks the node lags behind "+
+				"the chain backend.",
+		),
+		blockAge: desc(
+			"best_block_age_seconds", "Time since the best block "+
+				"of the chain backend was mined.",
+		),
+		quit: make(chan struct{}),
+	}
+}
+
+// start registers for block epochs, so the collector can track the best
+// block processed by the node.
+func (c *chainCollector) start() error {
+	if c.cfg.ChainNotifier == nil {
+		return nil
+	}
+
+	epochs, err := c.cfg.ChainNotifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return fmt.Errorf("unable to register for block epochs: %w",
+			err)
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer epochs.Cancel()
+
+		for {
+			select {
+			case epoch, ok := <-epochs.Epochs:
+				if !ok {
+					return
+				}
+
+				atomic.StoreInt32(
+					&c.notifierHeight, epoch.Height,
+				)
+
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// stop stops tracking block epochs.
+func (c *chainCollector) stop() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// Describe sends the descriptors of all metrics of the collector to the
+// given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *chainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.backendHeight
+	ch <- c.nodeHeight
+	ch <- c.lag
+	ch <- c.blockAge
+}
+
+// Collect queries the best block of the chain backend and sends the
+// resulting metrics to the given channel.
+//
+// NOTE: This is part of the prometheus.Collector interface.
+func (c *chainCollector) Collect(ch chan<- prometheus.Metric) {
+	bestHash, bestHeight, err := c.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		log.Errorf("Unable to fetch best block: %v", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.backendHeight, prometheus.GaugeValue, float64(bestHeight),
+	)
+
+	header, err := c.cfg.ChainIO.GetBlockHeader(bestHash)
+	if err != nil {
+		log.Errorf("Unable to fetch best block header: %v", err)
+	} else {
+		age := time.Since(header.Timestamp).Seconds()
+		ch <- prometheus.MustNewConstMetric(
+			c.blockAge, prometheus.GaugeValue, age,
+		)
+	}
+
+	if c.cfg.ChainNotifier == nil {
+		return
+	}
+
+	// We'll only report the lag once we've received the first epoch.
+	nodeHeight := atomic.LoadInt32(&c.notifierHeight)
+	if nodeHeight == 0 {
+		return
+	}
+
+	lag := bestHeight - nodeHeight
+	if lag < 0 {
+		lag = 0
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.nodeHeight, prometheus.GaugeValue, float64(nodeHeight),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.lag, prometheus.GaugeValue, float64(lag),
+	)
+}
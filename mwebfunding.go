@@ -0,0 +1,202 @@
+package lnd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/ltcsuite/lnd/funding"
+	"github.com/ltcsuite/lnd/labels"
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/lnd/sweep"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// mwebPegOut describes a peg-out of MWEB coins to a canonical address of the
+// wallet, done to be able to fund a channel.
+type mwebPegOut struct {
+	// txid is the hash of the peg-out transaction.
+	txid chainhash.Hash
+
+	// mwebCoins are the MWEB coins that were pegged out.
+	mwebCoins fn.Set[wire.OutPoint]
+
+	// output is the matured canonical output the MWEB coins were pegged
+	// out to.
+	output wire.OutPoint
+}
+
+// applyTo replaces the pegged out MWEB coins among the manually selected
+// outpoints of a funding request with the canonical peg-out output.
+func (p *mwebPegOut) applyTo(req *funding.InitFundingMsg) {
+	if len(req.Outpoints) == 0 {
+		return
+	}
+
+	outpoints := []wire.OutPoint{p.output}
+	for _, outpoint := range req.Outpoints {
+		if !p.mwebCoins.Contains(outpoint) {
+			outpoints = append(outpoints, outpoint)
+		}
+	}
+	req.Outpoints = outpoints
+}
+
+// applyToBatch replaces the pegged out MWEB coins among the manually selected
+// outpoints of a batch funding request with the canonical peg-out output.
+func (p *mwebPegOut) applyToBatch(req *lnrpc.BatchOpenChannelRequest) {
+	// The outpoints were already parsed when the request was checked.
+	wireOutpoints, err := toWireOutpoints(req.Outpoints)
+	if err != nil || len(wireOutpoints) == 0 {
+		return
+	}
+
+	outpoints := []*lnrpc.OutPoint{{
+		TxidBytes:   p.output.Hash[:],
+		TxidStr:     p.output.Hash.String(),
+		OutputIndex: p.output.Index,
+	}}
+	for i, outpoint := range wireOutpoints {
+		if !p.mwebCoins.Contains(outpoint) {
+			outpoints = append(outpoints, req.Outpoints[i])
+		}
+	}
+	req.Outpoints = outpoints
+}
+
+// markPending records in a channel pending update that the channel was funded
+// through the peg-out. A nil peg-out leaves the update on the canonical path.
+func (p *mwebPegOut) markPending(update *lnrpc.PendingUpdate) {
+	if p == nil || update == nil {
+		return
+	}
+
+	update.FundingPath = lnrpc.FundingPath_FUNDING_PATH_MWEB_PEG_OUT
+	update.PegOutTxid = p.txid[:]
+}
+
+// pegOutForFunding pegs out the MWEB coins a channel funding attempt failed on
+// to a fresh canonical address of the wallet, if automatic peg-outs are
+// enabled. It then blocks until the peg-out output has matured and can be
+// spent by a funding transaction, so the funding attempt can be retried. If
+// the funding error isn't caused by MWEB coins or peg-outs are disabled, the
+// error is returned as is.
+func (r *rpcServer) pegOutForFunding(ctx context.Context, fundingErr error,
+	minConfs int32) (*mwebPegOut, error) {
+
+	var mwebErr *chanfunding.ErrMwebFunding
+	if !errors.As(fundingErr, &mwebErr) || !r.cfg.MwebFunding.PegOut {
+		return nil, fundingErr
+	}
+
+	wallet := r.server.cc.Wallet
+	feeRate, err := r.server.cc.FeeEstimator.EstimateFeePerKW(
+		r.cfg.MwebFunding.PegOutConfTarget,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate peg-out fee: %w",
+			err)
+	}
+
+	_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	pegOutAddr, err := wallet.NewAddress(
+		lnwallet.WitnessPubKey, false, lnwallet.DefaultAccountName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pegOutScript, err := txscript.PayToAddrScript(pegOutAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	pegOut := &mwebPegOut{
+		mwebCoins: fn.NewSet[wire.OutPoint](),
+	}
+	for _, coin := range mwebErr.Coins {
+		pegOut.mwebCoins.Add(coin.OutPoint)
+	}
+
+	// The MWEB coins are swept in their entirety to the peg-out address,
+	// which is passed as the change address so it receives everything
+	// that is left after fees.
+	sweepTxPkg, err := sweep.CraftSweepAllTx(
+		feeRate, uint32(bestHeight), nil, pegOutAddr, wallet, wallet,
+		wallet.WalletController, r.server.cc.FeeEstimator,
+		r.server.cc.Signer, minConfs, pegOut.mwebCoins,
+		wallet.WalletController,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to craft peg-out tx: %w", err)
+	}
+
+	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+	err = wallet.PublishTransaction(sweepTxPkg.SweepTx, label)
+	if err != nil {
+		sweepTxPkg.CancelSweepAttempt()
+		return nil, fmt.Errorf("unable to publish peg-out tx: %w", err)
+	}
+	pegOut.txid = sweepTxPkg.SweepTx.TxHash()
+
+	rpcsLog.Infof("Pegged out %d MWEB coins (%v) in tx %v to fund "+
+		"channel, waiting for the peg-out to mature",
+		len(mwebErr.Coins), mwebErr.Amount(), pegOut.txid)
+
+	// Peg-out outputs only become spendable after a maturity period, so
+	// we check for the output every block until it has enough
+	// confirmations. The first epoch is the current tip.
+	maturity := int32(r.cfg.ActiveNetParams.MwebPegoutMaturity)
+	blockEpochs, err := r.server.cc.ChainNotifier.RegisterBlockEpochNtfn(
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case _, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return nil, errors.New("block epoch " +
+					"notifications stopped")
+			}
+
+		case <-ctx.Done():
+			return nil, fmt.Errorf("stopped waiting for peg-out "+
+				"tx %v: %w", pegOut.txid, ctx.Err())
+
+		case <-r.quit:
+			return nil, ErrServerShuttingDown
+		}
+
+		utxos, err := wallet.ListUnspentWitnessFromDefaultAccount(
+			maturity, math.MaxInt32,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, utxo := range utxos {
+			if bytes.Equal(utxo.PkScript, pegOutScript) {
+				pegOut.output = utxo.OutPoint
+
+				rpcsLog.Infof("Peg-out tx %v matured in "+
+					"output %v", pegOut.txid, pegOut.output)
+
+				return pegOut, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/lnd/chainntnfs"
+	"github.com/ltcsuite/lnd/chainreg"
+	"github.com/ltcsuite/lnd/fn"
+	"github.com/ltcsuite/lnd/funding"
+	"github.com/ltcsuite/lnd/lncfg"
+	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lntest/mock"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// mwebCoin is an MWEB coin of the wallet that can't fund a channel.
+	mwebCoin = &lnwallet.Utxo{
+		AddressType:   lnwallet.Mweb,
+		Value:         ltcutil.SatoshiPerBitcoin,
+		Confirmations: 1,
+		OutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{1},
+			Index: 1,
+		},
+	}
+
+	// canonicalCoin is a canonical coin of the wallet.
+	canonicalCoin = wire.OutPoint{
+		Hash:  chainhash.Hash{2},
+		Index: 2,
+	}
+)
+
+// TestMwebPegOutApplyTo asserts that a peg-out replaces the pegged out MWEB
+// coins among the manually selected outpoints of a funding request.
+func TestMwebPegOutApplyTo(t *testing.T) {
+	t.Parallel()
+
+	pegOut := &mwebPegOut{
+		mwebCoins: fn.NewSet(mwebCoin.OutPoint),
+		output: wire.OutPoint{
+			Hash:  chainhash.Hash{3},
+			Index: 0,
+		},
+	}
+
+	// The MWEB coin is replaced by the peg-out output, while the other
+	// coins stay selected.
+	req := &funding.InitFundingMsg{
+		Outpoints: []wire.OutPoint{mwebCoin.OutPoint, canonicalCoin},
+	}
+	pegOut.applyTo(req)
+	require.Equal(
+		t, []wire.OutPoint{pegOut.output, canonicalCoin}, req.Outpoints,
+	)
+
+	// If the coins weren't selected manually, coin selection is left to
+	// the wallet, which will pick up the peg-out output by itself.
+	req = &funding.InitFundingMsg{}
+	pegOut.applyTo(req)
+	require.Empty(t, req.Outpoints)
+}
+
+// TestMwebPegOutApplyToBatch asserts that a peg-out replaces the pegged out
+// MWEB coins among the manually selected outpoints of a batch funding
+// request, which are the inputs of its PSBT template.
+func TestMwebPegOutApplyToBatch(t *testing.T) {
+	t.Parallel()
+
+	pegOut := &mwebPegOut{
+		mwebCoins: fn.NewSet(mwebCoin.OutPoint),
+		output: wire.OutPoint{
+			Hash:  chainhash.Hash{3},
+			Index: 0,
+		},
+	}
+
+	// The MWEB coin is replaced by the peg-out output, while the other
+	// coins stay selected.
+	canonical := &lnrpc.OutPoint{
+		TxidStr:     canonicalCoin.Hash.String(),
+		OutputIndex: canonicalCoin.Index,
+	}
+	req := &lnrpc.BatchOpenChannelRequest{
+		Outpoints: []*lnrpc.OutPoint{{
+			TxidStr:     mwebCoin.OutPoint.Hash.String(),
+			OutputIndex: mwebCoin.OutPoint.Index,
+		}, canonical},
+	}
+	pegOut.applyToBatch(req)
+
+	outpoints, err := toWireOutpoints(req.Outpoints)
+	require.NoError(t, err)
+	require.Equal(
+		t, []wire.OutPoint{pegOut.output, canonicalCoin}, outpoints,
+	)
+	require.Equal(t, canonical, req.Outpoints[1])
+
+	// If the coins weren't selected manually, coin selection is left to
+	// the wallet, which will pick up the peg-out output by itself.
+	req = &lnrpc.BatchOpenChannelRequest{}
+	pegOut.applyToBatch(req)
+	require.Empty(t, req.Outpoints)
+}
+
+// TestMwebPegOutMarkPending asserts that a peg-out is reported in the pending
+// update of the channel it funded, and that channels funded without one are
+// reported on the canonical path.
+func TestMwebPegOutMarkPending(t *testing.T) {
+	t.Parallel()
+
+	pegOut := &mwebPegOut{
+		txid: chainhash.Hash{4},
+	}
+
+	update := &lnrpc.PendingUpdate{}
+	pegOut.markPending(update)
+	require.Equal(
+		t, lnrpc.FundingPath_FUNDING_PATH_MWEB_PEG_OUT,
+		update.FundingPath,
+	)
+	require.Equal(t, pegOut.txid[:], update.PegOutTxid)
+
+	// Without a peg-out, the update is left untouched.
+	var noPegOut *mwebPegOut
+	update = &lnrpc.PendingUpdate{}
+	noPegOut.markPending(update)
+	require.Equal(t, &lnrpc.PendingUpdate{}, update)
+
+	// Updates other than pending ones are passed as nil, which must be
+	// ignored.
+	pegOut.markPending(nil)
+}
+
+// pegOutWallet is a mock wallet controller that only lists the coins that
+// have the requested number of confirmations.
+type pegOutWallet struct {
+	*mock.WalletController
+
+	mu    sync.Mutex
+	utxos []*lnwallet.Utxo
+}
+
+// ListUnspentWitness returns the coins of the wallet that have at least
+// minConfs confirmations.
+func (w *pegOutWallet) ListUnspentWitness(minConfs, _ int32,
+	_ string) ([]*lnwallet.Utxo, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var utxos []*lnwallet.Utxo
+	for _, utxo := range w.utxos {
+		if utxo.Confirmations >= int64(minConfs) {
+			utxos = append(utxos, utxo)
+		}
+	}
+
+	return utxos, nil
+}
+
+// addUtxo adds a coin to the wallet.
+func (w *pegOutWallet) addUtxo(utxo *lnwallet.Utxo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.utxos = append(w.utxos, utxo)
+}
+
+// pegOutHarness bundles an rpc server backed by mock chain and wallet
+// subsystems, used to test MWEB peg-outs for channel funding.
+type pegOutHarness struct {
+	rpcServer *rpcServer
+	wallet    *pegOutWallet
+	notifier  *mock.ChainNotifier
+}
+
+// newPegOutHarness creates a new peg-out harness whose wallet holds a single
+// MWEB coin.
+func newPegOutHarness(t *testing.T, pegOut bool) *pegOutHarness {
+	rootKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	wallet := &pegOutWallet{
+		WalletController: &mock.WalletController{
+			RootKey:               rootKey,
+			PublishedTransactions: make(chan *wire.MsgTx, 1),
+		},
+		utxos: []*lnwallet.Utxo{mwebCoin},
+	}
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+	}
+
+	cc := &chainreg.ChainControl{
+		PartialChainControl: &chainreg.PartialChainControl{
+			FeeEstimator: chainfee.NewStaticEstimator(
+				chainfee.FeePerKwFloor, 0,
+			),
+			ChainNotifier: notifier,
+		},
+		ChainIO: &mock.ChainIO{BestHeight: 100},
+		Signer:  &mock.DummySigner{},
+		Wallet: &lnwallet.LightningWallet{
+			WalletController: wallet,
+		},
+	}
+
+	return &pegOutHarness{
+		rpcServer: &rpcServer{
+			cfg: &Config{
+				ActiveNetParams: chainreg.LitecoinNetParams{
+					Params: &chaincfg.MainNetParams,
+				},
+				MwebFunding: &lncfg.MwebFunding{
+					PegOut:           pegOut,
+					PegOutConfTarget: 6,
+				},
+			},
+			server: &server{
+				cc: cc,
+			},
+			quit: make(chan struct{}),
+		},
+		wallet:   wallet,
+		notifier: notifier,
+	}
+}
+
+// mwebFundingErr returns the funding error for the MWEB coin of the wallet.
+func mwebFundingErr() error {
+	return &chanfunding.ErrMwebFunding{
+		Coins: []chanfunding.Coin{{
+			TxOut: wire.TxOut{
+				Value:    int64(mwebCoin.Value),
+				PkScript: mwebCoin.PkScript,
+			},
+			OutPoint: mwebCoin.OutPoint,
+		}},
+	}
+}
+
+// TestPegOutForFundingPassThrough asserts that funding errors are returned as
+// is if they aren't caused by MWEB coins or peg-outs are disabled.
+func TestPegOutForFundingPassThrough(t *testing.T) {
+	t.Parallel()
+
+	h := newPegOutHarness(t, true)
+	errOther := errors.New("other funding error")
+	pegOut, err := h.rpcServer.pegOutForFunding(
+		context.Background(), errOther, 1,
+	)
+	require.Nil(t, pegOut)
+	require.ErrorIs(t, err, errOther)
+
+	h = newPegOutHarness(t, false)
+	fundingErr := mwebFundingErr()
+	pegOut, err = h.rpcServer.pegOutForFunding(
+		context.Background(), fundingErr, 1,
+	)
+	require.Nil(t, pegOut)
+	require.ErrorIs(t, err, fundingErr)
+
+	// Nothing may be pegged out if the peg-out was refused.
+	require.Empty(t, h.wallet.PublishedTransactions)
+}
+
+// TestPegOutForFunding asserts that the MWEB coins a funding attempt failed on
+// are pegged out to the wallet, and that the peg-out output is only returned
+// once it has matured.
+func TestPegOutForFunding(t *testing.T) {
+	t.Parallel()
+
+	h := newPegOutHarness(t, true)
+
+	type result struct {
+		pegOut *mwebPegOut
+		err    error
+	}
+	resultChan := make(chan result, 1)
+	go func() {
+		pegOut, err := h.rpcServer.pegOutForFunding(
+			context.Background(), mwebFundingErr(), 1,
+		)
+		resultChan <- result{pegOut, err}
+	}()
+
+	// The MWEB coin is swept in its entirety to a canonical address of the
+	// wallet.
+	var pegOutTx *wire.MsgTx
+	select {
+	case pegOutTx = <-h.wallet.PublishedTransactions:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("peg-out tx not published")
+	}
+
+	require.Len(t, pegOutTx.TxIn, 1)
+	require.Equal(t, mwebCoin.OutPoint, pegOutTx.TxIn[0].PreviousOutPoint)
+	require.Len(t, pegOutTx.TxOut, 1)
+
+	pegOutAddr, err := h.wallet.NewAddress(
+		lnwallet.WitnessPubKey, false, lnwallet.DefaultAccountName,
+	)
+	require.NoError(t, err)
+	pegOutScript, err := txscript.PayToAddrScript(pegOutAddr)
+	require.NoError(t, err)
+	require.Equal(t, pegOutScript, pegOutTx.TxOut[0].PkScript)
+
+	// The peg-out output shows up in the wallet, but can't be spent before
+	// it has matured.
+	maturity := int64(chaincfg.MainNetParams.MwebPegoutMaturity)
+	output := &lnwallet.Utxo{
+		AddressType: lnwallet.WitnessPubKey,
+		Value:       ltcutil.Amount(pegOutTx.TxOut[0].Value),
+		PkScript:    pegOutScript,
+		OutPoint: wire.OutPoint{
+			Hash:  pegOutTx.TxHash(),
+			Index: 0,
+		},
+		Confirmations: maturity - 1,
+	}
+	h.wallet.addUtxo(output)
+
+	sendEpoch := func() {
+		select {
+		case h.notifier.EpochChan <- &chainntnfs.BlockEpoch{}:
+		case res := <-resultChan:
+			t.Fatalf("peg-out returned before maturity: %v",
+				res.err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block epoch not consumed")
+		}
+	}
+
+	// Sending the second epoch only succeeds once the first one was
+	// handled without returning the immature output.
+	sendEpoch()
+	sendEpoch()
+
+	h.wallet.mu.Lock()
+	output.Confirmations = maturity
+	h.wallet.mu.Unlock()
+
+	// The peg-out is returned with the next block once it has matured. It
+	// might already be picked up while handling the last epoch.
+	var res result
+	select {
+	case h.notifier.EpochChan <- &chainntnfs.BlockEpoch{}:
+		select {
+		case res = <-resultChan:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("peg-out not returned after maturity")
+		}
+
+	case res = <-resultChan:
+	}
+	require.NoError(t, res.err)
+	require.Equal(t, pegOutTx.TxHash(), res.pegOut.txid)
+	require.Equal(t, output.OutPoint, res.pegOut.output)
+	require.True(t, res.pegOut.mwebCoins.Contains(mwebCoin.OutPoint))
+}
+
+// TestPegOutForFundingShutdown asserts that waiting for a peg-out to mature is
+// aborted once the caller gives up.
+func TestPegOutForFundingShutdown(t *testing.T) {
+	t.Parallel()
+
+	h := newPegOutHarness(t, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := h.rpcServer.pegOutForFunding(
+			ctx, mwebFundingErr(), 1,
+		)
+		errChan <- err
+	}()
+
+	select {
+	case <-h.wallet.PublishedTransactions:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("peg-out tx not published")
+	}
+
+	cancel()
+
+	select {
+	case err := <-errChan:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("peg-out not aborted")
+	}
+}
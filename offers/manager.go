@@ -0,0 +1,629 @@
+package offers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/lnd/bolt12"
+	"github.com/ltcsuite/lnd/clock"
+	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/onionmessage"
+	"github.com/ltcsuite/lnd/subscribe"
+	"github.com/ltcsuite/lnd/zpay32"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+const (
+	// DefaultInvoiceExpiry is the default time after which the invoices
+	// that we create for invoice requests expire.
+	DefaultInvoiceExpiry = bolt12.DefaultRelativeExpiry
+
+	// metadataNonceSize is the size of the random nonce in the metadata
+	// of our offers.
+	metadataNonceSize = 16
+
+	// payerMetadataSize is the size of the random metadata of our invoice
+	// requests.
+	payerMetadataSize = 32
+
+	// replyPathIDSize is the size of the path IDs of the reply paths that
+	// we include in our invoice requests.
+	replyPathIDSize = 32
+)
+
+var (
+	// ErrManagerShuttingDown is returned when the Manager is stopped
+	// while waiting for an invoice.
+	ErrManagerShuttingDown = errors.New("offers manager shutting down")
+
+	// ErrUnsupportedChain is returned when an offer can't be paid on our
+	// chain.
+	ErrUnsupportedChain = errors.New("offer is not for our chain")
+)
+
+// Config holds the dependencies of the offers Manager.
+type Config struct {
+	// ChainParams are the parameters of the chain that we create and pay
+	// offers on.
+	ChainParams *chaincfg.Params
+
+	// NodeKey is our node's public key, which is the issuer ID of our
+	// offers and signs the invoices that we create for them.
+	NodeKey *btcec.PublicKey
+
+	// MetadataKey is the secret that authenticates the metadata of our
+	// offers. It allows us to recognize invoice requests for our offers
+	// without storing them, so it must not change across restarts.
+	MetadataKey [32]byte
+
+	// SignMessage signs a message with our node key.
+	SignMessage bolt12.MessageSigner
+
+	// AddInvoice adds an invoice for the given amount to the invoice
+	// registry and returns its payment hash along with blinded paths to
+	// our node that it can be paid over.
+	AddInvoice func(amt lnwire.MilliSatoshi, memo string,
+		expiry time.Duration) (lntypes.Hash,
+		[]*zpay32.BlindedPaymentPath, error)
+
+	// SendMessage sends an onion message.
+	SendMessage func(*onionmessage.SendRequest) ([]byte, error)
+
+	// SubscribeMessages subscribes to the onion messages that are
+	// addressed to us.
+	SubscribeMessages func() (*subscribe.Client, error)
+
+	// FindPath returns the intermediate hops of an onion message path to
+	// the given node, whose first hop is one of our peers. If we're
+	// connected to the node, or it is our own node, no hops are needed.
+	FindPath func(dest *btcec.PublicKey) ([]*btcec.PublicKey, error)
+
+	// InvoiceExpiry is the time after which the invoices that we create
+	// expire.
+	InvoiceExpiry time.Duration
+
+	// Clock is used to timestamp invoices and check for expiry.
+	Clock clock.Clock
+}
+
+// OfferParams describes an offer that should be created.
+type OfferParams struct {
+	// Amount is the amount per item. If it is zero, the payer chooses the
+	// amount.
+	Amount lnwire.MilliSatoshi
+
+	// Description is a description of the purpose of the payment.
+	Description string
+
+	// Issuer is a human-readable description of ourselves.
+	Issuer string
+
+	// QuantityMax is the maximum number of items per payment, where zero
+	// means no limit. If it is nil, the offer is for a single item.
+	QuantityMax *uint64
+
+	// AbsoluteExpiry is the time after which the offer expires. The zero
+	// value means that it doesn't expire.
+	AbsoluteExpiry time.Time
+}
+
+// FetchParams describes the invoice that should be requested for an offer.
+type FetchParams struct {
+	// Offer is the offer to request an invoice for.
+	Offer *bolt12.Offer
+
+	// Amount is the amount to pay. It is required if the offer has no
+	// amount, and may exceed the offer's amount otherwise.
+	Amount lnwire.MilliSatoshi
+
+	// Quantity is the number of items to pay for, which must be set if
+	// and only if the offer has a maximum quantity.
+	Quantity uint64
+
+	// PayerNote is an optional note to the issuer.
+	PayerNote string
+}
+
+// reply is the reply to one of our invoice requests.
+type reply struct {
+	invoice *bolt12.Invoice
+	err     error
+}
+
+// Manager creates offers, answers the invoice requests for them that we
+// receive over onion messages, and fetches invoices for the offers that we
+// pay. Our offers are stateless: their metadata is authenticated with a
+// secret key, so we recognize invoice requests for them without having to
+// store them.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// pending holds the channels that the replies to our outstanding
+	// invoice requests are delivered on, keyed by the hex encoded path ID
+	// of their reply path.
+	pending    map[string]chan *reply
+	pendingMtx sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a new offers Manager from the given config.
+func NewManager(cfg *Config) *Manager {
+	return &Manager{
+		cfg:     cfg,
+		pending: make(map[string]chan *reply),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start starts handling the onion messages that are addressed to us.
+func (m *Manager) Start() error {
+	var err error
+	m.started.Do(func() {
+		log.Info("Offers manager starting")
+
+		var client *subscribe.Client
+		client, err = m.cfg.SubscribeMessages()
+		if err != nil {
+			return
+		}
+
+		m.wg.Add(1)
+		go m.handleMessages(client)
+	})
+
+	return err
+}
+
+// Stop signals the Manager for a graceful shutdown.
+func (m *Manager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Offers manager shutting down")
+
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// CreateOffer creates a new offer that is paid to our node.
+func (m *Manager) CreateOffer(params *OfferParams) (*bolt12.Offer, error) {
+	offer := &bolt12.Offer{
+		Chains:         []chainhash.Hash{*m.cfg.ChainParams.GenesisHash},
+		Amount:         params.Amount,
+		Description:    params.Description,
+		AbsoluteExpiry: params.AbsoluteExpiry,
+		Issuer:         params.Issuer,
+		QuantityMax:    params.QuantityMax,
+		IssuerID:       m.cfg.NodeKey,
+	}
+
+	if err := offer.Validate(); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, metadataNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	mac, err := m.offerMAC(offer, nonce)
+	if err != nil {
+		return nil, err
+	}
+	offer.Metadata = append(nonce, mac...)
+
+	return offer, nil
+}
+
+// offerMAC computes the authentication code of an offer with the given
+// metadata nonce. It commits to all fields of the offer but its metadata.
+func (m *Manager) offerMAC(offer *bolt12.Offer, nonce []byte) ([]byte,
+	error) {
+
+	unauthenticated := *offer
+	unauthenticated.Metadata = nil
+
+	encoded, err := unauthenticated.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, m.cfg.MetadataKey[:])
+	_, _ = mac.Write(nonce)
+	_, _ = mac.Write([]byte(encoded))
+
+	return mac.Sum(nil), nil
+}
+
+// isOurOffer returns true if the given offer was created by us.
+func (m *Manager) isOurOffer(offer *bolt12.Offer) bool {
+	if offer.IssuerID == nil || !offer.IssuerID.IsEqual(m.cfg.NodeKey) {
+		return false
+	}
+
+	if len(offer.Metadata) != metadataNonceSize+sha256.Size {
+		return false
+	}
+
+	nonce := offer.Metadata[:metadataNonceSize]
+	mac, err := m.offerMAC(offer, nonce)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(mac, offer.Metadata[metadataNonceSize:])
+}
+
+// handleMessages handles the onion messages that are addressed to us.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) handleMessages(client *subscribe.Client) {
+	defer m.wg.Done()
+	defer client.Cancel()
+
+	for {
+		select {
+		case update, ok := <-client.Updates():
+			if !ok {
+				return
+			}
+
+			msg, ok := update.(*onionmessage.ReceivedMessage)
+			if !ok {
+				continue
+			}
+
+			if err := m.handleMessage(msg); err != nil {
+				log.Debugf("Unable to handle onion message from "+
+					"%v: %v", msg.Peer, err)
+			}
+
+		case <-client.Quit():
+			return
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// handleMessage handles a single onion message that is addressed to us.
+func (m *Manager) handleMessage(msg *onionmessage.ReceivedMessage) error {
+	records := msg.CustomRecords
+
+	if data, ok := records[bolt12.InvoiceRequestRecordType]; ok {
+		return m.handleInvoiceRequest(msg, data)
+	}
+
+	// Invoices and invoice errors are only accepted as replies to our
+	// own invoice requests.
+	if data, ok := records[bolt12.InvoiceRecordType]; ok {
+		invoice, err := bolt12.DeserializeInvoice(data)
+		if err != nil {
+			return err
+		}
+
+		return m.deliverReply(msg.PathID, &reply{invoice: invoice})
+	}
+
+	if data, ok := records[bolt12.InvoiceErrorRecordType]; ok {
+		invoiceError, err := bolt12.DeserializeInvoiceError(data)
+		if err != nil {
+			return err
+		}
+
+		return m.deliverReply(msg.PathID, &reply{err: invoiceError})
+	}
+
+	return nil
+}
+
+// deliverReply hands a reply to the invoice request that is waiting for it.
+func (m *Manager) deliverReply(pathID []byte, r *reply) error {
+	m.pendingMtx.Lock()
+	replies, ok := m.pending[hex.EncodeToString(pathID)]
+	m.pendingMtx.Unlock()
+
+	if !ok {
+		return errors.New("unexpected reply to invoice request")
+	}
+
+	// Only the first reply to each request is used.
+	select {
+	case replies <- r:
+	default:
+	}
+
+	return nil
+}
+
+// handleInvoiceRequest answers an invoice request for one of our offers with
+// an invoice, or with an invoice error if the request can't be fulfilled.
+func (m *Manager) handleInvoiceRequest(msg *onionmessage.ReceivedMessage,
+	data []byte) error {
+
+	if msg.ReplyPath == nil {
+		return errors.New("invoice request has no reply path")
+	}
+
+	request, err := bolt12.DeserializeInvoiceRequest(data)
+	if err != nil {
+		return err
+	}
+
+	// Requests for offers that aren't ours are ignored, so that we don't
+	// reveal anything about ourselves.
+	if !m.isOurOffer(request.Offer) {
+		return errors.New("invoice request is not for one of our " +
+			"offers")
+	}
+
+	var records map[uint64][]byte
+	invoice, err := m.createInvoice(request)
+	if err != nil {
+		log.Debugf("Rejecting invoice request: %v", err)
+
+		invoiceError := &bolt12.InvoiceError{Message: err.Error()}
+		encoded, err := invoiceError.Serialize()
+		if err != nil {
+			return err
+		}
+
+		records = map[uint64][]byte{
+			bolt12.InvoiceErrorRecordType: encoded,
+		}
+	} else {
+		encoded, err := invoice.Serialize()
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Sending invoice for %v with payment hash %v",
+			invoice.Amount, invoice.PaymentHash)
+
+		records = map[uint64][]byte{
+			bolt12.InvoiceRecordType: encoded,
+		}
+	}
+
+	hops, err := m.cfg.FindPath(msg.ReplyPath.IntroductionPoint)
+	if err != nil {
+		return fmt.Errorf("unable to find path to reply path: %w", err)
+	}
+
+	_, err = m.cfg.SendMessage(&onionmessage.SendRequest{
+		BlindedDestination: msg.ReplyPath,
+		IntermediateHops:   hops,
+		CustomRecords:      records,
+	})
+
+	return err
+}
+
+// createInvoice validates an invoice request for one of our offers and
+// creates an invoice for it.
+func (m *Manager) createInvoice(
+	request *bolt12.InvoiceRequest) (*bolt12.Invoice, error) {
+
+	if err := request.Verify(); err != nil {
+		return nil, err
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := m.cfg.Clock.Now()
+	if request.Offer.IsExpired(now) {
+		return nil, bolt12.ErrOfferExpired
+	}
+
+	if request.ChainHash() != *m.cfg.ChainParams.GenesisHash {
+		return nil, ErrUnsupportedChain
+	}
+
+	expiry := m.cfg.InvoiceExpiry
+	amt := request.AmountToPay()
+	paymentHash, paths, err := m.cfg.AddInvoice(
+		amt, request.Offer.Description, expiry,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to add invoice: %w", err)
+	}
+
+	mirrored := *request
+	mirrored.Signature = nil
+
+	invoice := &bolt12.Invoice{
+		Request:        &mirrored,
+		Paths:          paths,
+		CreatedAt:      now.Truncate(time.Second),
+		RelativeExpiry: expiry,
+		PaymentHash:    paymentHash,
+		Amount:         amt,
+		NodeID:         m.cfg.NodeKey,
+	}
+	if err := invoice.Sign(m.cfg.SignMessage); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// FetchInvoice sends an invoice request for an offer to its issuer, and waits
+// for the invoice that is sent in reply. The invoice is validated against the
+// request before it is returned.
+func (m *Manager) FetchInvoice(ctx context.Context,
+	params *FetchParams) (*bolt12.Invoice, error) {
+
+	offer := params.Offer
+	if !offer.SupportsChain(*m.cfg.ChainParams.GenesisHash) {
+		return nil, ErrUnsupportedChain
+	}
+
+	if offer.IsExpired(m.cfg.Clock.Now()) {
+		return nil, bolt12.ErrOfferExpired
+	}
+
+	// Every request is signed with a fresh key, so that our payments
+	// can't be linked to each other.
+	payerKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make([]byte, payerMetadataSize)
+	if _, err := rand.Read(metadata); err != nil {
+		return nil, err
+	}
+
+	chain := *m.cfg.ChainParams.GenesisHash
+	request := &bolt12.InvoiceRequest{
+		Offer:     offer,
+		Metadata:  metadata,
+		Chain:     &chain,
+		Amount:    params.Amount,
+		Quantity:  params.Quantity,
+		PayerID:   payerKey.PubKey(),
+		PayerNote: params.PayerNote,
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := request.Sign(bolt12.PrivKeySigner(payerKey)); err != nil {
+		return nil, err
+	}
+
+	encoded, err := request.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	sendReq := &onionmessage.SendRequest{
+		CustomRecords: map[uint64][]byte{
+			bolt12.InvoiceRequestRecordType: encoded,
+		},
+	}
+
+	// If the offer has blinded paths, we send the request over the first
+	// of them, and the invoice must be signed by the key of the path's
+	// final hop. Otherwise the issuer is addressed directly.
+	var (
+		dest       *btcec.PublicKey
+		invoiceKey = offer.IssuerID
+	)
+	if len(offer.Paths) > 0 {
+		path := offer.Paths[0]
+		sendReq.BlindedDestination = path
+		dest = path.IntroductionPoint
+
+		if invoiceKey == nil {
+			lastHop := path.BlindedHops[len(path.BlindedHops)-1]
+			invoiceKey = lastHop.BlindedNodePub
+		}
+	} else {
+		sendReq.Destination = offer.IssuerID
+		dest = offer.IssuerID
+	}
+
+	sendReq.IntermediateHops, err = m.cfg.FindPath(dest)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find path to offer "+
+			"issuer: %w", err)
+	}
+
+	// We register for the reply before sending the request, so that we
+	// can't miss it.
+	sendReq.ReplyPathID = make([]byte, replyPathIDSize)
+	if _, err := rand.Read(sendReq.ReplyPathID); err != nil {
+		return nil, err
+	}
+
+	pathKey := hex.EncodeToString(sendReq.ReplyPathID)
+	replies := make(chan *reply, 1)
+
+	m.pendingMtx.Lock()
+	m.pending[pathKey] = replies
+	m.pendingMtx.Unlock()
+
+	defer func() {
+		m.pendingMtx.Lock()
+		delete(m.pending, pathKey)
+		m.pendingMtx.Unlock()
+	}()
+
+	if _, err := m.cfg.SendMessage(sendReq); err != nil {
+		return nil, fmt.Errorf("unable to send invoice request: %w",
+			err)
+	}
+
+	var r *reply
+	select {
+	case r = <-replies:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-m.quit:
+		return nil, ErrManagerShuttingDown
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	invoice := r.invoice
+	if err := m.validateInvoice(invoice, request, invoiceKey); err != nil {
+		return nil, fmt.Errorf("invalid invoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// validateInvoice checks that an invoice that we received is a valid answer
+// to our invoice request, and is signed by the given key.
+func (m *Manager) validateInvoice(invoice *bolt12.Invoice,
+	request *bolt12.InvoiceRequest, invoiceKey *btcec.PublicKey) error {
+
+	if err := invoice.Validate(); err != nil {
+		return err
+	}
+
+	if !invoice.NodeID.IsEqual(invoiceKey) {
+		return errors.New("invoice is not signed by the offer issuer")
+	}
+
+	if err := invoice.Verify(); err != nil {
+		return err
+	}
+
+	if err := invoice.MatchesRequest(request); err != nil {
+		return err
+	}
+
+	if invoice.Amount != request.AmountToPay() {
+		return fmt.Errorf("invoice amount %v doesn't match requested "+
+			"amount %v", invoice.Amount, request.AmountToPay())
+	}
+
+	if invoice.IsExpired(m.cfg.Clock.Now()) {
+		return bolt12.ErrInvoiceExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package offers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/bolt12"
+	"github.com/ltcsuite/lnd/clock"
+	"github.com/ltcsuite/lnd/lntypes"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/onionmessage"
+	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/lnd/zpay32"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Unix(1700000000, 0)
+
+// testNode is a node with an onion messenger and an offers manager.
+type testNode struct {
+	pubKey    *btcec.PublicKey
+	messenger *onionmessage.Messenger
+	manager   *Manager
+
+	// invoices holds the amounts of the invoices that were added.
+	invoices chan lnwire.MilliSatoshi
+}
+
+// newTestNodes creates two nodes that are connected to each other.
+func newTestNodes(t *testing.T) (*testNode, *testNode) {
+	nodes := make(map[route.Vertex]*testNode)
+
+	newNode := func() *testNode {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		self := route.NewVertex(privKey.PubKey())
+		messenger := onionmessage.New(&onionmessage.Config{
+			NodeKey:     &sphinx.PrivKeyECDH{PrivKey: privKey},
+			ChainParams: &chaincfg.MainNetParams,
+			SendToPeer: func(peer route.Vertex,
+				msg *lnwire.OnionMessage) error {
+
+				return nodes[peer].messenger.HandleMessage(
+					self, msg,
+				)
+			},
+			FetchChannelPeer: func(lnwire.ShortChannelID) (
+				route.Vertex, error) {
+
+				return route.Vertex{}, errors.New("no channels")
+			},
+			PeerRateLimit: onionmessage.DefaultPeerRateLimit,
+			PeerBurst:     onionmessage.DefaultPeerBurst,
+		})
+		require.NoError(t, messenger.Start())
+		t.Cleanup(func() {
+			require.NoError(t, messenger.Stop())
+		})
+
+		node := &testNode{
+			pubKey:    privKey.PubKey(),
+			messenger: messenger,
+			invoices:  make(chan lnwire.MilliSatoshi, 1),
+		}
+
+		node.manager = NewManager(&Config{
+			ChainParams: &chaincfg.MainNetParams,
+			NodeKey:     privKey.PubKey(),
+			MetadataKey: [32]byte{1},
+			SignMessage: bolt12.PrivKeySigner(privKey),
+			AddInvoice: func(amt lnwire.MilliSatoshi, _ string,
+				_ time.Duration) (lntypes.Hash,
+				[]*zpay32.BlindedPaymentPath, error) {
+
+				node.invoices <- amt
+
+				return lntypes.Hash{1}, []*zpay32.BlindedPaymentPath{
+					newPaymentPath(t, privKey.PubKey()),
+				}, nil
+			},
+			SendMessage:       messenger.SendMessage,
+			SubscribeMessages: messenger.SubscribeMessages,
+			FindPath: func(*btcec.PublicKey) ([]*btcec.PublicKey,
+				error) {
+
+				return nil, nil
+			},
+			InvoiceExpiry: DefaultInvoiceExpiry,
+			Clock:         clock.NewTestClock(testTime),
+		})
+		require.NoError(t, node.manager.Start())
+		t.Cleanup(func() {
+			require.NoError(t, node.manager.Stop())
+		})
+
+		nodes[self] = node
+
+		return node
+	}
+
+	return newNode(), newNode()
+}
+
+// newPaymentPath returns a blinded payment path to the given node.
+func newPaymentPath(t *testing.T,
+	dest *btcec.PublicKey) *zpay32.BlindedPaymentPath {
+
+	blindingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &zpay32.BlindedPaymentPath{
+		CltvExpiryDelta:             80,
+		HTLCMaxMsat:                 1000000,
+		IntroductionNode:            dest,
+		FirstEphemeralBlindingPoint: blindingKey.PubKey(),
+		Hops: []*sphinx.BlindedHopInfo{{
+			BlindedNodePub: blindingKey.PubKey(),
+			CipherText:     []byte{1},
+		}},
+	}
+}
+
+// TestFetchInvoice tests that an invoice is fetched for an offer over onion
+// messages.
+func TestFetchInvoice(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newTestNodes(t)
+
+	quantityMax := uint64(0)
+	offer, err := bob.manager.CreateOffer(&OfferParams{
+		Amount:      1000,
+		Description: "donation",
+		QuantityMax: &quantityMax,
+	})
+	require.NoError(t, err)
+	require.True(t, bob.manager.isOurOffer(offer))
+	require.False(t, alice.manager.isOurOffer(offer))
+
+	// The offer survives its encoding.
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+	offer, err = bolt12.DecodeOffer(encoded)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The same offer can be used for several payments.
+	for _, quantity := range []uint64{1, 3} {
+		invoice, err := alice.manager.FetchInvoice(ctx, &FetchParams{
+			Offer:     offer,
+			Quantity:  quantity,
+			PayerNote: "keep it up",
+		})
+		require.NoError(t, err)
+
+		amt := lnwire.MilliSatoshi(1000 * quantity)
+		require.Equal(t, amt, invoice.Amount)
+		require.Equal(t, amt, <-bob.invoices)
+		require.True(t, invoice.NodeID.IsEqual(bob.pubKey))
+		require.Equal(t, "keep it up", invoice.Request.PayerNote)
+	}
+}
+
+// TestFetchInvoiceErrors tests that invalid invoice requests are rejected.
+func TestFetchInvoiceErrors(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newTestNodes(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Requests that don't fit the offer are rejected before they're
+	// sent.
+	offer, err := bob.manager.CreateOffer(&OfferParams{
+		Description: "tip",
+	})
+	require.NoError(t, err)
+
+	_, err = alice.manager.FetchInvoice(ctx, &FetchParams{
+		Offer: offer,
+	})
+	require.Error(t, err)
+
+	// Expired offers can't be paid.
+	offer, err = bob.manager.CreateOffer(&OfferParams{
+		Amount:         1000,
+		Description:    "coffee",
+		AbsoluteExpiry: testTime.Add(-time.Second),
+	})
+	require.NoError(t, err)
+
+	_, err = alice.manager.FetchInvoice(ctx, &FetchParams{
+		Offer: offer,
+	})
+	require.ErrorIs(t, err, bolt12.ErrOfferExpired)
+
+	// If the offer was tampered with, the issuer no longer recognizes it
+	// and doesn't reply.
+	offer, err = bob.manager.CreateOffer(&OfferParams{
+		Amount:      1000,
+		Description: "coffee",
+	})
+	require.NoError(t, err)
+	offer.Amount = 1
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer shortCancel()
+
+	_, err = alice.manager.FetchInvoice(shortCtx, &FetchParams{
+		Offer: offer,
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	select {
+	case <-bob.invoices:
+		t.Fatalf("unexpected invoice")
+	default:
+	}
+}
@@ -0,0 +1,661 @@
+package onionmessage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/record"
+	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/lnd/subscribe"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultPeerRateLimit is the default number of onion messages per
+	// second that we'll accept from a single peer on average.
+	DefaultPeerRateLimit = 5
+
+	// DefaultPeerBurst is the default number of onion messages that we'll
+	// accept from a single peer in a burst, before its rate limit kicks
+	// in.
+	DefaultPeerBurst = 20
+
+	// pathIDSize is the size of the random path IDs that we include in
+	// our reply paths.
+	pathIDSize = 32
+)
+
+var (
+	// ErrRateLimited is returned when a peer sends us onion messages at a
+	// higher rate than we allow.
+	ErrRateLimited = errors.New("onion message rate limit exceeded")
+
+	// ErrNoDestination is returned when an onion message is sent without
+	// a destination.
+	ErrNoDestination = errors.New("onion message has no destination")
+
+	// ErrSendToSelf is returned when an onion message would have to be
+	// sent to ourselves.
+	ErrSendToSelf = errors.New("cannot send onion message to ourselves")
+
+	// ErrTooManyHops is returned when the path of an onion message is
+	// longer than what fits in the onion.
+	ErrTooManyHops = fmt.Errorf("onion message paths cannot be longer "+
+		"than %d hops", sphinx.NumMaxHops)
+)
+
+// Config houses the functions and parameters the Messenger needs to relay,
+// receive and send onion messages.
+type Config struct {
+	// NodeKey is our node key, which is used to peel the onion messages
+	// and decrypt the route blinding data that are addressed to us.
+	NodeKey sphinx.SingleKeyECDH
+
+	// ChainParams are the parameters of the chain that we're active on.
+	ChainParams *chaincfg.Params
+
+	// SendToPeer sends an onion message to one of our connected peers. An
+	// error is returned if we're not connected to the peer.
+	SendToPeer func(peer route.Vertex, msg *lnwire.OnionMessage) error
+
+	// FetchChannelPeer returns the remote party of the channel with the
+	// given short channel ID, which is used to forward onion messages
+	// that are addressed to the next hop by channel rather than by node.
+	FetchChannelPeer func(scid lnwire.ShortChannelID) (route.Vertex,
+		error)
+
+	// PeerRateLimit is the number of onion messages per second that we
+	// accept from a single peer on average.
+	PeerRateLimit rate.Limit
+
+	// PeerBurst is the number of onion messages that we accept from a
+	// single peer in a burst.
+	PeerBurst int
+}
+
+// ReceivedMessage is an onion message that was addressed to us.
+type ReceivedMessage struct {
+	// Peer is the peer that handed the message to us. Unless the message
+	// was sent over a single hop, this isn't the sender of the message.
+	Peer route.Vertex
+
+	// PathID is the path ID that we left for ourselves in the blinded path
+	// that the message was sent over, if any. It can be used to match
+	// replies to the messages that we sent.
+	PathID []byte
+
+	// ReplyPath is the blinded path that the sender included for us to
+	// reply over, if any.
+	ReplyPath *sphinx.BlindedPath
+
+	// CustomRecords is the set of application-defined records that the
+	// message carries.
+	CustomRecords map[uint64][]byte
+}
+
+// SendRequest describes an onion message that should be sent.
+type SendRequest struct {
+	// Destination is the node that the message should be delivered to.
+	// Either Destination or BlindedDestination must be set.
+	Destination *btcec.PublicKey
+
+	// BlindedDestination is a blinded path that the message should be
+	// delivered over, such as the reply path of a message that we
+	// received.
+	BlindedDestination *sphinx.BlindedPath
+
+	// IntermediateHops is the list of nodes that the message should be
+	// relayed through before it reaches the destination, or the
+	// introduction node of the blinded destination. The first node of
+	// the path, which is either the first intermediate hop or the
+	// destination itself, must be one of our connected peers.
+	IntermediateHops []*btcec.PublicKey
+
+	// IncludeReplyPath indicates that a blinded path back to us should be
+	// included in the message, so that the recipient can reply to it.
+	IncludeReplyPath bool
+
+	// ReplyPathID is the path ID to use for the reply path, which implies
+	// IncludeReplyPath. If it isn't set, a random path ID is used. Callers
+	// that pick their own path ID can start waiting for replies before the
+	// message is sent.
+	ReplyPathID []byte
+
+	// CustomRecords is the set of application-defined records that the
+	// message carries to its destination. All record types must be at
+	// least MinCustomRecordType.
+	CustomRecords map[uint64][]byte
+}
+
+// Messenger relays the onion messages that our peers send us, delivers the
+// ones that are addressed to us to its subscribers and sends onion messages
+// on behalf of the user. Each peer is subject to a rate limit, so that it
+// can't use us to flood the network with messages.
+type Messenger struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// router is used to peel the onion messages that we receive. As
+	// onion messages don't move any funds, there is no need for replay
+	// protection, so its replay log is never used.
+	router *sphinx.Router
+
+	// ourPub is our own node's public key.
+	ourPub route.Vertex
+
+	// limiters holds the rate limiter of each peer that sent us an onion
+	// message.
+	limiters   map[route.Vertex]*rate.Limiter
+	limiterMtx sync.Mutex
+
+	ntfnServer *subscribe.Server
+}
+
+// New creates a new onion Messenger from the given config.
+func New(cfg *Config) *Messenger {
+	return &Messenger{
+		cfg: cfg,
+		router: sphinx.NewRouter(
+			cfg.NodeKey, cfg.ChainParams,
+			sphinx.NewMemoryReplayLog(),
+		),
+		ourPub:     route.NewVertex(cfg.NodeKey.PubKey()),
+		limiters:   make(map[route.Vertex]*rate.Limiter),
+		ntfnServer: subscribe.NewServer(),
+	}
+}
+
+// Start starts the Messenger's subscription server.
+func (m *Messenger) Start() error {
+	var err error
+	m.started.Do(func() {
+		log.Info("Onion messenger starting")
+		err = m.ntfnServer.Start()
+	})
+
+	return err
+}
+
+// Stop signals the Messenger for a graceful shutdown.
+func (m *Messenger) Stop() error {
+	var err error
+	m.stopped.Do(func() {
+		log.Info("Onion messenger shutting down")
+		err = m.ntfnServer.Stop()
+	})
+
+	return err
+}
+
+// SubscribeMessages returns a subscribe.Client that will receive a
+// *ReceivedMessage for every onion message that is addressed to us.
+func (m *Messenger) SubscribeMessages() (*subscribe.Client, error) {
+	return m.ntfnServer.Subscribe()
+}
+
+// RemovePeer removes the rate limiter of the given peer, which should be
+// called once we're no longer connected to it.
+func (m *Messenger) RemovePeer(peer route.Vertex) {
+	m.limiterMtx.Lock()
+	defer m.limiterMtx.Unlock()
+
+	delete(m.limiters, peer)
+}
+
+// allow returns true if the given peer is still within its rate limit.
+func (m *Messenger) allow(peer route.Vertex) bool {
+	m.limiterMtx.Lock()
+	defer m.limiterMtx.Unlock()
+
+	limiter, ok := m.limiters[peer]
+	if !ok {
+		limiter = rate.NewLimiter(m.cfg.PeerRateLimit, m.cfg.PeerBurst)
+		m.limiters[peer] = limiter
+	}
+
+	return limiter.Allow()
+}
+
+// HandleMessage processes an onion message that we received from the given
+// peer. Depending on the route blinding data that is addressed to us, the
+// message is either relayed to the next hop or delivered to our subscribers.
+func (m *Messenger) HandleMessage(peer [33]byte,
+	msg *lnwire.OnionMessage) error {
+
+	peerVertex := route.Vertex(peer)
+	if !m.allow(peerVertex) {
+		return ErrRateLimited
+	}
+
+	if len(msg.OnionBlob) != lnwire.OnionPacketSize {
+		return fmt.Errorf("unsupported onion message packet size: %d",
+			len(msg.OnionBlob))
+	}
+
+	var onionPkt sphinx.OnionPacket
+	err := onionPkt.Decode(bytes.NewReader(msg.OnionBlob))
+	if err != nil {
+		return fmt.Errorf("unable to decode onion packet: %w", err)
+	}
+
+	// Onion messages don't commit to any associated data, and the sender
+	// encrypted the onion to our blinded node ID, so we need the path key
+	// to derive our shared secret.
+	processed, err := m.router.ReconstructOnionPacket(
+		&onionPkt, nil, sphinx.WithBlindingPoint(msg.PathKey),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to process onion packet: %w", err)
+	}
+
+	payload, err := DecodePayload(
+		bytes.NewReader(processed.Payload.Payload),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if len(payload.EncryptedData) == 0 {
+		return ErrNoEncryptedData
+	}
+
+	plainText, err := m.router.DecryptBlindedHopData(
+		msg.PathKey, payload.EncryptedData,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to decrypt route blinding data: %w",
+			err)
+	}
+
+	routeData, err := record.DecodeBlindedRouteData(
+		bytes.NewReader(plainText),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to decode route blinding data: %w",
+			err)
+	}
+
+	switch processed.Action {
+	case sphinx.MoreHops:
+		return m.forward(
+			peerVertex, msg.PathKey, routeData,
+			processed.NextPacket,
+		)
+
+	case sphinx.ExitNode:
+		return m.deliver(peerVertex, routeData, payload)
+
+	default:
+		return fmt.Errorf("unexpected onion action: %v",
+			processed.Action)
+	}
+}
+
+// forward relays an onion message to the next hop as instructed by the route
+// blinding data that was addressed to us.
+func (m *Messenger) forward(peer route.Vertex, pathKey *btcec.PublicKey,
+	routeData *record.BlindedRouteData,
+	nextPacket *sphinx.OnionPacket) error {
+
+	nextNode, err := m.nextNode(routeData)
+	if err != nil {
+		return err
+	}
+
+	if nextNode == m.ourPub {
+		return ErrSendToSelf
+	}
+
+	// If the sender concatenated two blinded paths, the path key of the
+	// next hop is given to us. Otherwise, we derive it from our own.
+	nextPathKey := routeData.NextBlindingOverride
+	if nextPathKey == nil {
+		nextPathKey, err = m.router.NextEphemeral(pathKey)
+		if err != nil {
+			return fmt.Errorf("unable to derive next path key: %w",
+				err)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := nextPacket.Encode(&b); err != nil {
+		return err
+	}
+
+	log.Debugf("Relaying onion message from %v to %v", peer, nextNode)
+
+	return m.cfg.SendToPeer(
+		nextNode, lnwire.NewOnionMessage(nextPathKey, b.Bytes()),
+	)
+}
+
+// nextNode returns the node that an onion message should be relayed to.
+func (m *Messenger) nextNode(
+	routeData *record.BlindedRouteData) (route.Vertex, error) {
+
+	switch {
+	case routeData.NextNodeID != nil:
+		return route.NewVertex(routeData.NextNodeID), nil
+
+	case routeData.ShortChannelID != nil:
+		return m.cfg.FetchChannelPeer(*routeData.ShortChannelID)
+
+	default:
+		return route.Vertex{}, errors.New("route blinding data has " +
+			"no next hop")
+	}
+}
+
+// deliver hands an onion message that is addressed to us to our subscribers.
+func (m *Messenger) deliver(peer route.Vertex,
+	routeData *record.BlindedRouteData, payload *Payload) error {
+
+	log.Debugf("Received onion message via %v with %d custom records",
+		peer, len(payload.CustomRecords))
+
+	return m.ntfnServer.SendUpdate(&ReceivedMessage{
+		Peer:          peer,
+		PathID:        routeData.PathID,
+		ReplyPath:     payload.ReplyPath,
+		CustomRecords: payload.CustomRecords,
+	})
+}
+
+// SendMessage sends an onion message as described by the request. If a reply
+// path is included, the path ID of the reply path is returned, which will be
+// reported for any reply that we receive over it.
+func (m *Messenger) SendMessage(req *SendRequest) ([]byte, error) {
+	if err := ValidateCustomRecords(req.CustomRecords); err != nil {
+		return nil, err
+	}
+
+	// If we're the introduction node of the blinded destination, we
+	// process our own hop to find out where to send the message.
+	dest := req.BlindedDestination
+	if dest != nil && route.NewVertex(dest.IntroductionPoint) == m.ourPub {
+		var err error
+		dest, err = m.advancePath(dest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodes := make([]*btcec.PublicKey, 0, len(req.IntermediateHops)+1)
+	nodes = append(nodes, req.IntermediateHops...)
+	if req.Destination != nil {
+		if dest != nil {
+			return nil, errors.New("only one of destination and " +
+				"blinded destination may be set")
+		}
+
+		nodes = append(nodes, req.Destination)
+	}
+
+	// We blind the part of the path that we pick ourselves, so that only
+	// the first hop learns that we are the sender. The last of our hops
+	// is told to hand the message off to the blinded destination, if
+	// there is one.
+	hopInfos := make([]*sphinx.HopInfo, len(nodes))
+	for i, node := range nodes {
+		if route.NewVertex(node) == m.ourPub {
+			return nil, ErrSendToSelf
+		}
+
+		routeData := &record.BlindedRouteData{}
+		switch {
+		case i < len(nodes)-1:
+			routeData.NextNodeID = nodes[i+1]
+
+		case dest != nil:
+			routeData.NextNodeID = dest.IntroductionPoint
+			routeData.NextBlindingOverride = dest.BlindingPoint
+		}
+
+		plainText, err := record.EncodeBlindedRouteData(routeData)
+		if err != nil {
+			return nil, err
+		}
+
+		hopInfos[i] = &sphinx.HopInfo{
+			NodePub:   node,
+			PlainText: plainText,
+		}
+	}
+
+	var (
+		firstHop    *btcec.PublicKey
+		pathKey     *btcec.PublicKey
+		blindedHops []*sphinx.BlindedHopInfo
+	)
+	if len(hopInfos) > 0 {
+		blindingKey, err := btcec.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := sphinx.BuildBlindedPath(blindingKey, hopInfos)
+		if err != nil {
+			return nil, err
+		}
+
+		firstHop = nodes[0]
+		pathKey = path.BlindingPoint
+		blindedHops = path.BlindedHops
+	}
+
+	if dest != nil {
+		if firstHop == nil {
+			firstHop = dest.IntroductionPoint
+			pathKey = dest.BlindingPoint
+		}
+
+		blindedHops = append(blindedHops, dest.BlindedHops...)
+	}
+
+	switch {
+	case len(blindedHops) == 0:
+		return nil, ErrNoDestination
+
+	case len(blindedHops) > sphinx.NumMaxHops:
+		return nil, ErrTooManyHops
+	}
+
+	var (
+		replyPath *sphinx.BlindedPath
+		pathID    []byte
+	)
+	switch {
+	case len(req.ReplyPathID) > 0:
+		pathID = req.ReplyPathID
+
+	case req.IncludeReplyPath:
+		pathID = make([]byte, pathIDSize)
+		if _, err := rand.Read(pathID); err != nil {
+			return nil, err
+		}
+	}
+
+	if pathID != nil {
+
+		var err error
+		replyPath, err = m.buildReplyPath(firstHop, pathID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	onionBlob, err := buildOnion(blindedHops, &Payload{
+		ReplyPath:     replyPath,
+		CustomRecords: req.CustomRecords,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	firstVertex := route.NewVertex(firstHop)
+	log.Debugf("Sending onion message over %d hops via %v",
+		len(blindedHops), firstVertex)
+
+	err = m.cfg.SendToPeer(
+		firstVertex, lnwire.NewOnionMessage(pathKey, onionBlob),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pathID, nil
+}
+
+// advancePath processes the first hop of a blinded path that we're the
+// introduction node of, returning the remainder of the path.
+func (m *Messenger) advancePath(
+	path *sphinx.BlindedPath) (*sphinx.BlindedPath, error) {
+
+	if len(path.BlindedHops) < 2 {
+		return nil, ErrSendToSelf
+	}
+
+	plainText, err := m.router.DecryptBlindedHopData(
+		path.BlindingPoint, path.BlindedHops[0].CipherText,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt route blinding "+
+			"data: %w", err)
+	}
+
+	routeData, err := record.DecodeBlindedRouteData(
+		bytes.NewReader(plainText),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	nextNode, err := m.nextNode(routeData)
+	if err != nil {
+		return nil, err
+	}
+
+	introPoint, err := btcec.ParsePubKey(nextNode[:])
+	if err != nil {
+		return nil, err
+	}
+
+	blindingPoint := routeData.NextBlindingOverride
+	if blindingPoint == nil {
+		blindingPoint, err = m.router.NextEphemeral(path.BlindingPoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &sphinx.BlindedPath{
+		IntroductionPoint: introPoint,
+		BlindingPoint:     blindingPoint,
+		BlindedHops:       path.BlindedHops[1:],
+	}, nil
+}
+
+// buildReplyPath constructs a blinded path to us with the given path ID, that
+// is introduced by the given node. If the introduction node is our peer, it
+// only learns that the recipient of the path is one of its peers.
+func (m *Messenger) buildReplyPath(introNode *btcec.PublicKey,
+	pathID []byte) (*sphinx.BlindedPath, error) {
+
+	ourKey := m.cfg.NodeKey.PubKey()
+
+	var hops []*record.BlindedRouteData
+	if route.NewVertex(introNode) != m.ourPub {
+		hops = append(hops, &record.BlindedRouteData{
+			NextNodeID: ourKey,
+		})
+	}
+	hops = append(hops, &record.BlindedRouteData{
+		PathID: pathID,
+	})
+
+	hopInfos := make([]*sphinx.HopInfo, len(hops))
+	for i, hop := range hops {
+		plainText, err := record.EncodeBlindedRouteData(hop)
+		if err != nil {
+			return nil, err
+		}
+
+		nodePub := ourKey
+		if i < len(hops)-1 {
+			nodePub = introNode
+		}
+
+		hopInfos[i] = &sphinx.HopInfo{
+			NodePub:   nodePub,
+			PlainText: plainText,
+		}
+	}
+
+	blindingKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return sphinx.BuildBlindedPath(blindingKey, hopInfos)
+}
+
+// buildOnion constructs the serialized onion packet of an onion message over
+// the given blinded hops. The final payload is merged with the route
+// blinding data of the last hop.
+func buildOnion(blindedHops []*sphinx.BlindedHopInfo,
+	finalPayload *Payload) ([]byte, error) {
+
+	var paymentPath sphinx.PaymentPath
+	for i, hop := range blindedHops {
+		payload := &Payload{
+			EncryptedData: hop.CipherText,
+		}
+		if i == len(blindedHops)-1 {
+			payload.ReplyPath = finalPayload.ReplyPath
+			payload.CustomRecords = finalPayload.CustomRecords
+		}
+
+		payloadBytes, err := EncodePayload(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		hopPayload, err := sphinx.NewTLVHopPayload(payloadBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		paymentPath[i] = sphinx.OnionHop{
+			NodePub:    *hop.BlindedNodePub,
+			HopPayload: hopPayload,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	onionPkt, err := sphinx.NewOnionPacket(
+		&paymentPath, sessionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build onion: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := onionPkt.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,257 @@
+package onionmessage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/lnd/subscribe"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+var errNotConnected = errors.New("peer not connected")
+
+// testNetwork is a set of nodes that hand onion messages to each other
+// synchronously over the connections between them.
+type testNetwork struct {
+	t *testing.T
+
+	nodes map[route.Vertex]*testNode
+	conns map[[2]route.Vertex]struct{}
+}
+
+// testNode is a single node of a test network.
+type testNode struct {
+	pubKey    *btcec.PublicKey
+	messenger *Messenger
+	client    *subscribe.Client
+}
+
+func newTestNetwork(t *testing.T) *testNetwork {
+	return &testNetwork{
+		t:     t,
+		nodes: make(map[route.Vertex]*testNode),
+		conns: make(map[[2]route.Vertex]struct{}),
+	}
+}
+
+// addNode adds a node with the given rate limit to the network.
+func (n *testNetwork) addNode(limit rate.Limit, burst int) *testNode {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(n.t, err)
+
+	self := route.NewVertex(privKey.PubKey())
+	messenger := New(&Config{
+		NodeKey:     &sphinx.PrivKeyECDH{PrivKey: privKey},
+		ChainParams: &chaincfg.MainNetParams,
+		SendToPeer: func(peer route.Vertex,
+			msg *lnwire.OnionMessage) error {
+
+			if _, ok := n.conns[[2]route.Vertex{self, peer}]; !ok {
+				return errNotConnected
+			}
+
+			return n.nodes[peer].messenger.HandleMessage(
+				self, msg,
+			)
+		},
+		FetchChannelPeer: func(lnwire.ShortChannelID) (route.Vertex,
+			error) {
+
+			return route.Vertex{}, errors.New("no channels")
+		},
+		PeerRateLimit: limit,
+		PeerBurst:     burst,
+	})
+	require.NoError(n.t, messenger.Start())
+	n.t.Cleanup(func() {
+		require.NoError(n.t, messenger.Stop())
+	})
+
+	client, err := messenger.SubscribeMessages()
+	require.NoError(n.t, err)
+
+	node := &testNode{
+		pubKey:    privKey.PubKey(),
+		messenger: messenger,
+		client:    client,
+	}
+	n.nodes[self] = node
+
+	return node
+}
+
+// connect connects the two given nodes.
+func (n *testNetwork) connect(a, b *testNode) {
+	aPub, bPub := route.NewVertex(a.pubKey), route.NewVertex(b.pubKey)
+	n.conns[[2]route.Vertex{aPub, bPub}] = struct{}{}
+	n.conns[[2]route.Vertex{bPub, aPub}] = struct{}{}
+}
+
+// receive waits for the node to receive an onion message.
+func (n *testNode) receive(t *testing.T) *ReceivedMessage {
+	t.Helper()
+
+	select {
+	case update := <-n.client.Updates():
+		msg, ok := update.(*ReceivedMessage)
+		require.True(t, ok)
+
+		return msg
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no onion message received")
+		return nil
+	}
+}
+
+// TestRelayAndReply tests that an onion message is relayed to its recipient,
+// and that the recipient can reply over the included reply path.
+func TestRelayAndReply(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t)
+	alice := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	bob := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	carol := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	network.connect(alice, bob)
+	network.connect(bob, carol)
+
+	// Alice can't send to Carol directly, as they aren't connected.
+	_, err := alice.messenger.SendMessage(&SendRequest{
+		Destination: carol.pubKey,
+	})
+	require.ErrorIs(t, err, errNotConnected)
+
+	records := map[uint64][]byte{64: []byte("ping")}
+	pathID, err := alice.messenger.SendMessage(&SendRequest{
+		Destination:      carol.pubKey,
+		IntermediateHops: []*btcec.PublicKey{bob.pubKey},
+		IncludeReplyPath: true,
+		CustomRecords:    records,
+	})
+	require.NoError(t, err)
+	require.Len(t, pathID, pathIDSize)
+
+	// Carol only learns that the message was relayed by Bob.
+	msg := carol.receive(t)
+	require.Equal(t, route.NewVertex(bob.pubKey), msg.Peer)
+	require.Equal(t, records, msg.CustomRecords)
+	require.Nil(t, msg.PathID)
+	require.NotNil(t, msg.ReplyPath)
+	require.Equal(t, bob.pubKey, msg.ReplyPath.IntroductionPoint)
+
+	// Carol replies over the reply path, which Alice recognizes by its
+	// path ID.
+	replyRecords := map[uint64][]byte{65: []byte("pong")}
+	_, err = carol.messenger.SendMessage(&SendRequest{
+		BlindedDestination: msg.ReplyPath,
+		CustomRecords:      replyRecords,
+	})
+	require.NoError(t, err)
+
+	reply := alice.receive(t)
+	require.Equal(t, route.NewVertex(bob.pubKey), reply.Peer)
+	require.Equal(t, replyRecords, reply.CustomRecords)
+	require.Equal(t, pathID, reply.PathID)
+	require.Nil(t, reply.ReplyPath)
+}
+
+// TestSendOverOwnIntroduction tests that we can send to a blinded path that we
+// are the introduction node of.
+func TestSendOverOwnIntroduction(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t)
+	alice := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	bob := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	network.connect(alice, bob)
+
+	// When Bob sends a message directly to Alice, Alice is the
+	// introduction node of the reply path.
+	pathID, err := bob.messenger.SendMessage(&SendRequest{
+		Destination:      alice.pubKey,
+		IncludeReplyPath: true,
+	})
+	require.NoError(t, err)
+
+	msg := alice.receive(t)
+	require.Equal(t, alice.pubKey, msg.ReplyPath.IntroductionPoint)
+
+	_, err = alice.messenger.SendMessage(&SendRequest{
+		BlindedDestination: msg.ReplyPath,
+	})
+	require.NoError(t, err)
+
+	reply := bob.receive(t)
+	require.Equal(t, route.NewVertex(alice.pubKey), reply.Peer)
+	require.Equal(t, pathID, reply.PathID)
+
+	// Sending to ourselves is rejected.
+	_, err = alice.messenger.SendMessage(&SendRequest{
+		Destination: alice.pubKey,
+	})
+	require.ErrorIs(t, err, ErrSendToSelf)
+}
+
+// TestPeerRateLimit tests that onion messages are dropped once a peer exceeds
+// its rate limit.
+func TestPeerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t)
+	alice := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	bob := network.addNode(rate.Every(time.Hour), 2)
+	network.connect(alice, bob)
+
+	send := func() error {
+		_, err := alice.messenger.SendMessage(&SendRequest{
+			Destination: bob.pubKey,
+		})
+
+		return err
+	}
+
+	require.NoError(t, send())
+	require.NoError(t, send())
+	require.ErrorIs(t, send(), ErrRateLimited)
+
+	// Once Alice reconnects, she starts with a fresh burst.
+	bob.messenger.RemovePeer(route.NewVertex(alice.pubKey))
+	require.NoError(t, send())
+}
+
+// TestSendValidation tests that invalid send requests are rejected.
+func TestSendValidation(t *testing.T) {
+	t.Parallel()
+
+	network := newTestNetwork(t)
+	alice := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	bob := network.addNode(DefaultPeerRateLimit, DefaultPeerBurst)
+	network.connect(alice, bob)
+
+	_, err := alice.messenger.SendMessage(&SendRequest{})
+	require.ErrorIs(t, err, ErrNoDestination)
+
+	_, err = alice.messenger.SendMessage(&SendRequest{
+		Destination:   bob.pubKey,
+		CustomRecords: map[uint64][]byte{4: {1}},
+	})
+	require.ErrorIs(t, err, ErrInvalidCustomRecord(4))
+
+	hops := make([]*btcec.PublicKey, sphinx.NumMaxHops)
+	for i := range hops {
+		hops[i] = bob.pubKey
+	}
+	_, err = alice.messenger.SendMessage(&SendRequest{
+		Destination:      bob.pubKey,
+		IntermediateHops: hops,
+	})
+	require.ErrorIs(t, err, ErrTooManyHops)
+}
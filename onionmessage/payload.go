@@ -0,0 +1,331 @@
+package onionmessage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+)
+
+const (
+	// replyPathType is the type of the blinded path that the recipient of
+	// an onion message can use to reply to the sender.
+	replyPathType tlv.Type = 2
+
+	// encryptedDataType is the type of the route blinding data that is
+	// encrypted to the node processing the onion message.
+	encryptedDataType tlv.Type = 4
+
+	// MinCustomRecordType is the lowest type that may be used for the
+	// application-defined records that are delivered to the final hop of
+	// an onion message. All types below it are reserved for the onion
+	// message protocol itself.
+	MinCustomRecordType = 64
+
+	// MaxBlindedPathHops is the largest number of hops that we'll accept
+	// in a blinded path, as a path that long wouldn't fit in the onion
+	// anyway.
+	MaxBlindedPathHops = sphinx.NumMaxHops
+)
+
+var (
+	// ErrNoEncryptedData is returned when an onion message payload does
+	// not carry any route blinding data, which every hop of an onion
+	// message needs.
+	ErrNoEncryptedData = errors.New("onion message payload has no " +
+		"encrypted data")
+)
+
+// ErrInvalidCustomRecord is returned when an application-defined record of
+// an onion message uses a type that is reserved for the protocol.
+type ErrInvalidCustomRecord uint64
+
+// Error returns a human-readable description of the error.
+func (e ErrInvalidCustomRecord) Error() string {
+	return fmt.Sprintf("custom record type %d is below the minimum of %d",
+		uint64(e), MinCustomRecordType)
+}
+
+// Payload is the payload of a single hop of an onion message.
+type Payload struct {
+	// ReplyPath is an optional blinded path that the final hop can use to
+	// reply to the sender of the message.
+	ReplyPath *sphinx.BlindedPath
+
+	// EncryptedData is the route blinding data that is encrypted to the
+	// hop, telling it where to send the message next.
+	EncryptedData []byte
+
+	// CustomRecords is the set of application-defined records that are
+	// delivered to the final hop.
+	CustomRecords map[uint64][]byte
+}
+
+// ValidateCustomRecords asserts that all the given records are of a type that
+// may be used by applications.
+func ValidateCustomRecords(records map[uint64][]byte) error {
+	for typ := range records {
+		if typ < MinCustomRecordType {
+			return ErrInvalidCustomRecord(typ)
+		}
+	}
+
+	return nil
+}
+
+// EncodePayload serializes the payload of a single hop of an onion message.
+func EncodePayload(payload *Payload) ([]byte, error) {
+	if err := ValidateCustomRecords(payload.CustomRecords); err != nil {
+		return nil, err
+	}
+
+	var records []tlv.Record
+	if payload.ReplyPath != nil {
+		records = append(records, newBlindedPathRecord(
+			replyPathType, &payload.ReplyPath,
+		))
+	}
+
+	if len(payload.EncryptedData) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			encryptedDataType, &payload.EncryptedData,
+		))
+	}
+
+	records = append(records, tlv.MapToRecords(payload.CustomRecords)...)
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodePayload parses the payload of a single hop of an onion message. Any
+// records of an unknown type at or above MinCustomRecordType are returned as
+// custom records, while unknown even types below it are rejected.
+func DecodePayload(r io.Reader) (*Payload, error) {
+	var (
+		payload       Payload
+		replyPath     *sphinx.BlindedPath
+		encryptedData []byte
+	)
+
+	stream, err := tlv.NewStream(
+		newBlindedPathRecord(replyPathType, &replyPath),
+		tlv.MakePrimitiveRecord(encryptedDataType, &encryptedData),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for typ, value := range parsedTypes {
+		switch {
+		case typ == replyPathType:
+			payload.ReplyPath = replyPath
+
+		case typ == encryptedDataType:
+			payload.EncryptedData = encryptedData
+
+		case typ >= MinCustomRecordType:
+			if payload.CustomRecords == nil {
+				payload.CustomRecords = make(map[uint64][]byte)
+			}
+			payload.CustomRecords[uint64(typ)] = value
+
+		case typ%2 == 0:
+			return nil, fmt.Errorf("unknown required onion "+
+				"message payload type: %d", typ)
+		}
+	}
+
+	return &payload, nil
+}
+
+// newBlindedPathRecord creates a tlv.Record that encodes a blinded path as
+// specified for the reply path of an onion message.
+func newBlindedPathRecord(typ tlv.Type,
+	path **sphinx.BlindedPath) tlv.Record {
+
+	sizeFunc := func() uint64 {
+		var b bytes.Buffer
+		if err := encodeBlindedPath(&b, path, nil); err != nil {
+			panic(err)
+		}
+
+		return uint64(b.Len())
+	}
+
+	return tlv.MakeDynamicRecord(
+		typ, path, sizeFunc, encodeBlindedPath, decodeBlindedPath,
+	)
+}
+
+// encodeBlindedPath is a tlv.Encoder for a blinded path.
+func encodeBlindedPath(w io.Writer, val interface{}, _ *[8]byte) error {
+	v, ok := val.(**sphinx.BlindedPath)
+	if !ok {
+		return tlv.NewTypeForEncodingErr(val, "*sphinx.BlindedPath")
+	}
+
+	return WriteBlindedPath(w, *v)
+}
+
+// decodeBlindedPath is a tlv.Decoder for a blinded path.
+func decodeBlindedPath(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	v, ok := val.(**sphinx.BlindedPath)
+	if !ok {
+		return tlv.NewTypeForDecodingErr(
+			val, "*sphinx.BlindedPath", l, l,
+		)
+	}
+
+	lr := io.LimitReader(r, int64(l))
+
+	path, err := ReadBlindedPath(lr)
+	if err != nil {
+		return err
+	}
+
+	// The encoding of the path must consume the full record.
+	var trailing [1]byte
+	if n, _ := lr.Read(trailing[:]); n != 0 {
+		return errors.New("trailing bytes after blinded path")
+	}
+
+	*v = path
+
+	return nil
+}
+
+// WriteBlindedPath serializes a blinded path in the format that is used by
+// onion messages and BOLT 12 offers.
+func WriteBlindedPath(w io.Writer, path *sphinx.BlindedPath) error {
+	if len(path.BlindedHops) == 0 ||
+		len(path.BlindedHops) > MaxBlindedPathHops {
+
+		return fmt.Errorf("invalid number of blinded hops: %d",
+			len(path.BlindedHops))
+	}
+
+	_, err := w.Write(path.IntroductionPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(path.BlindingPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{uint8(len(path.BlindedHops))}); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	for _, hop := range path.BlindedHops {
+		_, err := w.Write(hop.BlindedNodePub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		if len(hop.CipherText) > sphinx.MaxPayloadSize {
+			return fmt.Errorf("encrypted data of %d bytes is too "+
+				"large", len(hop.CipherText))
+		}
+
+		dataLen := uint16(len(hop.CipherText))
+		if err := tlv.EUint16(w, &dataLen, &scratch); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(hop.CipherText); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadBlindedPath reads a single blinded path that was serialized with
+// WriteBlindedPath from the given reader.
+func ReadBlindedPath(r io.Reader) (*sphinx.BlindedPath, error) {
+	var path sphinx.BlindedPath
+
+	introPoint, err := readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+	path.IntroductionPoint = introPoint
+
+	blindingPoint, err := readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+	path.BlindingPoint = blindingPoint
+
+	var numHops [1]byte
+	if _, err := io.ReadFull(r, numHops[:]); err != nil {
+		return nil, err
+	}
+
+	if numHops[0] == 0 || int(numHops[0]) > MaxBlindedPathHops {
+		return nil, fmt.Errorf("invalid number of blinded hops: %d",
+			numHops[0])
+	}
+
+	var scratch [8]byte
+	path.BlindedHops = make([]*sphinx.BlindedHopInfo, numHops[0])
+	for i := range path.BlindedHops {
+		nodePub, err := readPubKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var dataLen uint16
+		err = tlv.DUint16(r, &dataLen, &scratch, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		cipherText := make([]byte, dataLen)
+		if _, err := io.ReadFull(r, cipherText); err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &sphinx.BlindedHopInfo{
+			BlindedNodePub: nodePub,
+			CipherText:     cipherText,
+		}
+	}
+
+	return &path, nil
+}
+
+// readPubKey reads a compressed public key from the given reader.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var keyBytes [btcec.PubKeyBytesLenCompressed]byte
+	if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(keyBytes[:])
+}
@@ -0,0 +1,92 @@
+package onionmessage
+
+import (
+	"bytes"
+	"testing"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPayloadEncoding tests that onion message payloads round trip through
+// their encoding.
+func TestPayloadEncoding(t *testing.T) {
+	t.Parallel()
+
+	pubKey := func() *btcec.PublicKey {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return priv.PubKey()
+	}
+
+	payload := &Payload{
+		ReplyPath: &sphinx.BlindedPath{
+			IntroductionPoint: pubKey(),
+			BlindingPoint:     pubKey(),
+			BlindedHops: []*sphinx.BlindedHopInfo{
+				{
+					BlindedNodePub: pubKey(),
+					CipherText:     []byte{1, 2, 3},
+				},
+				{
+					BlindedNodePub: pubKey(),
+					CipherText:     []byte{4, 5, 6, 7},
+				},
+			},
+		},
+		EncryptedData: []byte{8, 9},
+		CustomRecords: map[uint64][]byte{
+			64:  {10},
+			101: {11, 12},
+		},
+	}
+
+	encoded, err := EncodePayload(payload)
+	require.NoError(t, err)
+
+	decoded, err := DecodePayload(bytes.NewReader(encoded))
+	require.NoError(t, err)
+	require.Equal(t, payload, decoded)
+}
+
+// TestPayloadValidation tests that payloads with records of reserved types
+// are rejected.
+func TestPayloadValidation(t *testing.T) {
+	t.Parallel()
+
+	// Applications can't use the types that are reserved for the
+	// protocol.
+	_, err := EncodePayload(&Payload{
+		CustomRecords: map[uint64][]byte{63: {1}},
+	})
+	require.ErrorIs(t, err, ErrInvalidCustomRecord(63))
+
+	encode := func(typ uint64) []byte {
+		records := tlv.MapToRecords(map[uint64][]byte{typ: {1}})
+		stream, err := tlv.NewStream(records...)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, stream.Encode(&b))
+
+		return b.Bytes()
+	}
+
+	// Unknown even types that are reserved for the protocol must be
+	// understood, while unknown odd ones are ignored.
+	_, err = DecodePayload(bytes.NewReader(encode(6)))
+	require.Error(t, err)
+
+	payload, err := DecodePayload(bytes.NewReader(encode(7)))
+	require.NoError(t, err)
+	require.Empty(t, payload.CustomRecords)
+
+	// Both even and odd types from the custom range are handed to the
+	// application.
+	payload, err = DecodePayload(bytes.NewReader(encode(64)))
+	require.NoError(t, err)
+	require.Equal(t, map[uint64][]byte{64: {1}}, payload.CustomRecords)
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
 	"github.com/ltcsuite/lnd/lnwallet/chancloser"
+	"github.com/ltcsuite/lnd/lnwallet/chansplicer"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/netann"
 	"github.com/ltcsuite/lnd/pool"
@@ -264,6 +265,10 @@ type Config struct {
 	// states.
 	AnchorTowerClient wtclient.Client
 
+	// TaprootTowerClient is used by simple taproot channels to backup
+	// revoked states.
+	TaprootTowerClient wtclient.Client
+
 	// DisconnectPeer is used to disconnect this peer if the cooperative close
 	// process fails.
 	DisconnectPeer func(*btcec.PublicKey) error
@@ -285,6 +290,12 @@ type Config struct {
 	// FundingManager is an implementation of the funding.Controller interface.
 	FundingManager funding.Controller
 
+	// AnnounceSplicedChannel adds a channel whose splice has confirmed to
+	// the channel graph under its new short channel ID, using the passed
+	// policy, and announces it once it is sufficiently confirmed.
+	AnnounceSplicedChannel func(*channeldb.OpenChannel,
+		*channeldb.ChannelEdgePolicy) error
+
 	// Hodl is used when creating ChannelLinks to specify HodlFlags as
 	// breakpoints in dev builds.
 	Hodl *hodl.Config
@@ -338,6 +349,10 @@ type Config struct {
 	// from the peer.
 	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
 
+	// HandleOnionMessage is called whenever an onion message is received
+	// from the peer. If nil, onion messages from the peer are ignored.
+	HandleOnionMessage func(peer [33]byte, msg *lnwire.OnionMessage) error
+
 	// GetAliases is passed to created links so the Switch and link can be
 	// aware of the channel's aliases.
 	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
@@ -464,6 +479,24 @@ type Brontide struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// activeSplices keeps track of all channels that are being spliced,
+	// keyed by their channel ID before the splice. Any splice messages
+	// are directed to the state machine of the splice, which is removed
+	// once both peers locked the splice.
+	activeSplices *lnutils.SyncMap[lnwire.ChannelID, *activeSplice]
+
+	// localSpliceReqs is a channel in which any local requests to splice
+	// a particular channel are sent over.
+	localSpliceReqs chan *chansplicer.SpliceRequest
+
+	// chanSpliceMsgs is a channel that any message related to the splice
+	// of a channel is sent over.
+	chanSpliceMsgs chan *spliceMsg
+
+	// splicedChans is sent upon once the splice of a channel has
+	// confirmed.
+	splicedChans chan *splicedChannel
+
 	// remoteFeatures is the feature vector received from the peer during
 	// the connection handshake.
 	remoteFeatures *lnwire.FeatureVector
@@ -519,6 +552,13 @@ func NewBrontide(cfg Config) *Brontide {
 		startReady:         make(chan struct{}),
 		quit:               make(chan struct{}),
 		log:                build.NewPrefixLog(logPrefix, peerLog),
+
+		activeSplices: &lnutils.SyncMap[
+			lnwire.ChannelID, *activeSplice,
+		]{},
+		localSpliceReqs: make(chan *chansplicer.SpliceRequest),
+		chanSpliceMsgs:  make(chan *spliceMsg),
+		splicedChans:    make(chan *splicedChannel),
 	}
 
 	return p
@@ -805,6 +845,17 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 		p.log.Infof("Loading ChannelPoint(%v), isPending=%v",
 			chanPoint, lnChan.IsPending())
 
+		// Channels that are being spliced won't be added to the
+		// htlcswitch until both peers locked the splice.
+		resumed, spliceMsgs, err := p.resumeSplice(lnChan)
+		if err != nil {
+			return nil, err
+		}
+		if resumed {
+			msgs = append(msgs, spliceMsgs...)
+			continue
+		}
+
 		// Skip adding any permanently irreconcilable channels to the
 		// htlcswitch.
 		if !dbChan.HasChanStatus(channeldb.ChanStatusDefault) &&
@@ -859,47 +910,11 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 		// Before we register this new link with the HTLC Switch, we'll
 		// need to fetch its current link-layer forwarding policy from
 		// the database.
-		graph := p.cfg.ChannelGraph
-		info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
-		if err != nil && err != channeldb.ErrEdgeNotFound {
+		_, forwardingPolicy, err := p.fetchForwardingPolicy(chanPoint)
+		if err != nil {
 			return nil, err
 		}
 
-		// We'll filter out our policy from the directional channel
-		// edges based whom the edge connects to. If it doesn't connect
-		// to us, then we know that we were the one that advertised the
-		// policy.
-		//
-		// TODO(roasbeef): can add helper method to get policy for
-		// particular channel.
-		var selfPolicy *channeldb.ChannelEdgePolicy
-		if info != nil && bytes.Equal(info.NodeKey1Bytes[:],
-			p.cfg.ServerPubKey[:]) {
-
-			selfPolicy = p1
-		} else {
-			selfPolicy = p2
-		}
-
-		// If we don't yet have an advertised routing policy, then
-		// we'll use the current default, otherwise we'll translate the
-		// routing policy into a forwarding policy.
-		var forwardingPolicy *models.ForwardingPolicy
-		if selfPolicy != nil {
-			forwardingPolicy = &models.ForwardingPolicy{
-				MinHTLCOut:    selfPolicy.MinHTLC,
-				MaxHTLC:       selfPolicy.MaxHTLC,
-				BaseFee:       selfPolicy.FeeBaseMSat,
-				FeeRate:       selfPolicy.FeeProportionalMillionths,
-				TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
-			}
-		} else {
-			p.log.Warnf("Unable to find our forwarding policy "+
-				"for channel %v, using default values",
-				chanPoint)
-			forwardingPolicy = &p.cfg.RoutingPolicy
-		}
-
 		p.log.Tracef("Using link policy of: %v",
 			spew.Sdump(forwardingPolicy))
 
@@ -938,6 +953,60 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 	return msgs, nil
 }
 
+// fetchForwardingPolicy returns our policy of the channel from the channel
+// graph, along with the forwarding policy translated from it. If we don't yet
+// have an advertised routing policy, the default forwarding policy is
+// returned instead.
+func (p *Brontide) fetchForwardingPolicy(chanPoint *wire.OutPoint) (
+	*channeldb.ChannelEdgePolicy, *models.ForwardingPolicy, error) {
+
+	graph := p.cfg.ChannelGraph
+	info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
+	if err != nil && err != channeldb.ErrEdgeNotFound {
+		return nil, nil, err
+	}
+
+	// We'll filter out our policy from the directional channel edges based
+	// whom the edge connects to. If it doesn't connect to us, then we know
+	// that we were the one that advertised the policy.
+	//
+	// TODO(roasbeef): can add helper method to get policy for particular
+	// channel.
+	var selfPolicy *channeldb.ChannelEdgePolicy
+	if info != nil && bytes.Equal(info.NodeKey1Bytes[:],
+		p.cfg.ServerPubKey[:]) {
+
+		selfPolicy = p1
+	} else {
+		selfPolicy = p2
+	}
+
+	// If we don't yet have an advertised routing policy, then we'll use
+	// the current default, otherwise we'll translate the routing policy
+	// into a forwarding policy.
+	if selfPolicy == nil {
+		p.log.Warnf("Unable to find our forwarding policy for channel "+
+			"%v, using default values", chanPoint)
+
+		return nil, &p.cfg.RoutingPolicy, nil
+	}
+
+	inboundFee, err := selfPolicy.InboundFee()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse inbound fee: %w",
+			err)
+	}
+
+	return selfPolicy, &models.ForwardingPolicy{
+		MinHTLCOut:    selfPolicy.MinHTLC,
+		MaxHTLC:       selfPolicy.MaxHTLC,
+		BaseFee:       selfPolicy.FeeBaseMSat,
+		FeeRate:       selfPolicy.FeeProportionalMillionths,
+		TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
+		InboundFee:    inboundFee,
+	}, nil
+}
+
 // addLink creates and adds a new ChannelLink from the specified channel.
 func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 	lnChan *lnwallet.LightningChannel,
@@ -973,31 +1042,15 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		return p.cfg.ChainArb.NotifyContractUpdate(*chanPoint, update)
 	}
 
-	chanType := lnChan.State().ChanType
-
-	// Select the appropriate tower client based on the channel type. It's
-	// okay if the clients are disabled altogether and these values are nil,
-	// as the link will check for nilness before using either.
-	var towerClient htlcswitch.TowerClient
-	switch {
-	case chanType.IsTaproot():
-		// Leave the tower client as nil for now until the tower client
-		// has support for taproot channels.
-		//
-		// If the user has activated the tower client, then add a log
-		// to explain that any taproot channel updates wil not be
-		// backed up to a tower.
-		if p.cfg.TowerClient != nil {
-			p.log.Debugf("Updates for channel %s will not be "+
-				"backed up to a watchtower as watchtowers "+
-				"are not yet taproot channel compatible",
-				chanPoint)
-		}
-	case chanType.HasAnchors():
-		towerClient = p.cfg.AnchorTowerClient
-	default:
-		towerClient = p.cfg.TowerClient
-	}
+	// Select the appropriate tower client based on the type of the remote
+	// commitment, which is the next state we'll back up. This may still
+	// be the old type if the link restarts during an upgrade of the
+	// commitment type, in which case the link switches to the tower
+	// client of the new type once the upgrade is complete.
+	chanState := lnChan.State()
+	towerClient := p.towerClientForType(chanState.ChanTypeAtHeight(
+		false, chanState.RemoteCommitment.CommitHeight,
+	))
 
 	//nolint:lll
 	linkCfg := htlcswitch.ChannelLinkConfig{
@@ -1029,6 +1082,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		MaxFeeUpdateTimeout:     htlcswitch.DefaultMaxLinkFeeUpdateTimeout,
 		OutgoingCltvRejectDelta: p.cfg.OutgoingCltvRejectDelta,
 		TowerClient:             towerClient,
+		GetTowerClient:          p.towerClientForType,
 		MaxOutgoingCltvExpiry:   p.cfg.MaxOutgoingCltvExpiry,
 		MaxFeeAllocation:        p.cfg.MaxChannelFeeAllocation,
 		MaxAnchorsCommitFeeRate: p.cfg.MaxAnchorsCommitFeeRate,
@@ -1053,6 +1107,23 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 	return p.cfg.Switch.CreateAndAddLink(linkCfg, lnChan)
 }
 
+// towerClientForType returns the tower client that backs up the revoked states
+// of channels of the given type. It's okay if the clients are disabled
+// altogether and nil is returned, as the link will check for nilness before
+// using it.
+func (p *Brontide) towerClientForType(
+	chanType channeldb.ChannelType) htlcswitch.TowerClient {
+
+	switch {
+	case chanType.IsTaproot():
+		return p.cfg.TaprootTowerClient
+	case chanType.HasAnchors():
+		return p.cfg.AnchorTowerClient
+	default:
+		return p.cfg.TowerClient
+	}
+}
+
 // maybeSendNodeAnn sends our node announcement to the remote peer if at least
 // one confirmed public channel exists with them.
 func (p *Brontide) maybeSendNodeAnn(channels []*channeldb.OpenChannel) {
@@ -1599,6 +1670,10 @@ out:
 
 		case *lnwire.OpenChannel,
 			*lnwire.AcceptChannel,
+			*lnwire.OpenChannel2,
+			*lnwire.AcceptChannel2,
+			*lnwire.TxInitRbf,
+			*lnwire.TxAckRbf,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
 			*lnwire.ChannelReady:
@@ -1618,6 +1693,33 @@ out:
 				break out
 			}
 
+		case *lnwire.SpliceInit,
+			*lnwire.SpliceAck,
+			*lnwire.SpliceLocked,
+			*lnwire.TxAddInput,
+			*lnwire.TxAddOutput,
+			*lnwire.TxRemoveInput,
+			*lnwire.TxRemoveOutput,
+			*lnwire.TxComplete,
+			*lnwire.TxSignatures,
+			*lnwire.TxAbort:
+
+			// The funding transaction of a dual funded channel is
+			// constructed with the same messages as a splice, so
+			// we'll hand them to the funding manager if they
+			// belong to a pending channel.
+			cid := msg.(LinkUpdater).TargetChanID()
+			if p.cfg.FundingManager.IsPendingChannel(cid, p) {
+				p.cfg.FundingManager.ProcessFundingMsg(msg, p)
+				break
+			}
+
+			select {
+			case p.chanSpliceMsgs <- &spliceMsg{cid, msg}:
+			case <-p.quit:
+				break out
+			}
+
 		case *lnwire.Warning:
 			targetChan = msg.ChanID
 			isLinkUpdate = p.handleWarningOrError(targetChan, msg)
@@ -1651,6 +1753,36 @@ out:
 		// if the channel is not active yet.
 		case LinkUpdater:
 			targetChan = msg.TargetChanID()
+
+			// While a channel is spliced, the commitment signatures
+			// for its new funding output are handled by the splice
+			// state machine instead of the link.
+			_, isCommitSig := msg.(*lnwire.CommitSig)
+
+			// The initial commitment signatures of a dual funded
+			// channel are handled by the funding manager.
+			if isCommitSig && p.cfg.FundingManager.IsPendingChannel(
+				targetChan, p,
+			) {
+
+				p.cfg.FundingManager.ProcessFundingMsg(nextMsg, p)
+				break
+			}
+
+			if _, ok := p.activeSplices.Load(targetChan); ok &&
+				isCommitSig {
+
+				select {
+				case p.chanSpliceMsgs <- &spliceMsg{
+					targetChan, nextMsg,
+				}:
+				case <-p.quit:
+					break out
+				}
+
+				break
+			}
+
 			isLinkUpdate = p.hasChannel(targetChan)
 
 			// Log an error if we don't have this channel. This
@@ -1681,6 +1813,9 @@ out:
 				p.log.Errorf("%v", err)
 			}
 
+		case *lnwire.OnionMessage:
+			p.handleOnionMessage(msg)
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -1716,6 +1851,20 @@ func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
 	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
 }
 
+// handleOnionMessage hands the given onion message to the onion message
+// handler, if one is registered. As onion messages are unreliable by design,
+// failures to relay them are not reported as peer errors.
+func (p *Brontide) handleOnionMessage(msg *lnwire.OnionMessage) {
+	if p.cfg.HandleOnionMessage == nil {
+		p.log.Tracef("Ignoring onion message, no handler registered")
+		return
+	}
+
+	if err := p.cfg.HandleOnionMessage(p.PubKey(), msg); err != nil {
+		p.log.Debugf("Unable to handle onion message: %v", err)
+	}
+}
+
 // isLoadedFromDisk returns true if the provided channel ID is loaded from
 // disk.
 //
@@ -2463,6 +2612,20 @@ out:
 		case closeMsg := <-p.chanCloseMsgs:
 			p.handleCloseMsg(closeMsg)
 
+		// We've just received a local request to splice an active
+		// channel, which will kick off the splice negotiation.
+		case req := <-p.localSpliceReqs:
+			p.handleLocalSpliceReq(req)
+
+		// We've received a splice related message from the remote
+		// peer, which advances the splice state machine.
+		case spliceMsg := <-p.chanSpliceMsgs:
+			p.handleSpliceMsg(spliceMsg)
+
+		// The splice of a channel has confirmed, so we'll lock it.
+		case spliced := <-p.splicedChans:
+			p.handleSplicedChannel(spliced)
+
 		// The channel reannounce delay has elapsed, broadcast the
 		// reenabled channel updates to the network. This should only
 		// fire once, so we set the reenableTimeout channel to nil to
@@ -0,0 +1,512 @@
+package peer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/contractcourt"
+	"github.com/ltcsuite/lnd/lnpeer"
+	"github.com/ltcsuite/lnd/lnwallet"
+	"github.com/ltcsuite/lnd/lnwallet/chansplicer"
+	"github.com/ltcsuite/lnd/lnwire"
+)
+
+// spliceMsg is a wrapper struct around a message related to the splice of a
+// channel, along with the ID of the channel it was sent for.
+type spliceMsg struct {
+	cid lnwire.ChannelID
+	msg lnwire.Message
+}
+
+// splicedChannel is sent once the splice of a channel has confirmed, and the
+// channel was moved over to its new funding output.
+type splicedChannel struct {
+	cid     lnwire.ChannelID
+	channel *channeldb.OpenChannel
+}
+
+// activeSplice tracks the splice of a channel with the peer.
+type activeSplice struct {
+	// splicer is the state machine of the splice.
+	splicer *chansplicer.ChanSplicer
+
+	// channel is the channel that is spliced.
+	channel *lnwallet.LightningChannel
+
+	// selfPolicy is our routing policy of the channel before the splice,
+	// which is carried over to the spliced channel.
+	selfPolicy *channeldb.ChannelEdgePolicy
+
+	// quit is closed once the splice is aborted or completed.
+	quit chan struct{}
+}
+
+// HandleLocalSpliceReq accepts a request to splice a channel, and passes it
+// onto the channelManager goroutine, which will shut down the link and start
+// the splice negotiation.
+func (p *Brontide) HandleLocalSpliceReq(req *chansplicer.SpliceRequest) {
+	select {
+	case p.localSpliceReqs <- req:
+	case <-p.quit:
+		req.Err <- lnpeer.ErrPeerExiting
+	}
+}
+
+// newSpliceCfg returns the configuration of a splice of the given channel.
+func (p *Brontide) newSpliceCfg(
+	channel *lnwallet.LightningChannel) (chansplicer.ChanSpliceCfg, error) {
+
+	_, height, err := p.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return chansplicer.ChanSpliceCfg{}, err
+	}
+
+	return chansplicer.ChanSpliceCfg{
+		Channel:     channel,
+		FundSplice:  p.cfg.Wallet.FundSplice,
+		FetchTx:     p.cfg.Wallet.FetchTx,
+		Signer:      p.cfg.Wallet.Cfg.Signer,
+		BroadcastTx: p.cfg.Wallet.PublishTransaction,
+		Height:      uint32(height),
+		DustLimit:   channel.State().LocalChanCfg.DustLimit,
+	}, nil
+}
+
+// startSplice tracks the passed splice, and subscribes to the chain events of
+// the channel to be notified once the splice has confirmed.
+func (p *Brontide) startSplice(splice *activeSplice) error {
+	cid := splice.splicer.ChanID()
+	chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(
+		splice.splicer.PrevOutpoint(),
+	)
+	if err != nil {
+		return err
+	}
+
+	p.activeSplices.Store(cid, splice)
+
+	p.wg.Add(1)
+	go p.waitForSplice(cid, splice, chainEvents)
+
+	return nil
+}
+
+// waitForSplice waits for the splice of the channel to confirm, and passes
+// the spliced channel onto the channelManager goroutine.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (p *Brontide) waitForSplice(cid lnwire.ChannelID, splice *activeSplice,
+	chainEvents *contractcourt.ChainEventSubscription) {
+
+	defer p.wg.Done()
+	defer chainEvents.Cancel()
+
+	select {
+	case channel := <-chainEvents.Spliced:
+		select {
+		case p.splicedChans <- &splicedChannel{cid, channel}:
+		case <-p.quit:
+		}
+
+	case <-splice.quit:
+	case <-p.quit:
+	}
+}
+
+// stopSplice stops tracking the passed splice.
+func (p *Brontide) stopSplice(splice *activeSplice) {
+	p.activeSplices.Delete(splice.splicer.ChanID())
+	close(splice.quit)
+}
+
+// handleLocalSpliceReq starts the splice of a channel requested by the local
+// user.
+func (p *Brontide) handleLocalSpliceReq(req *chansplicer.SpliceRequest) {
+	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
+
+	channel, ok := p.activeChannels.Load(chanID)
+	switch {
+	case !ok || channel == nil:
+		req.Err <- fmt.Errorf("unable to splice channel, ChannelID(%v) "+
+			"is unknown", chanID)
+		return
+
+	case !p.remoteFeatures.HasFeature(lnwire.SpliceOptional):
+		req.Err <- fmt.Errorf("peer doesn't support splicing")
+		return
+	}
+
+	if _, ok := p.activeSplices.Load(chanID); ok {
+		req.Err <- fmt.Errorf("ChannelID(%v) is already being spliced",
+			chanID)
+		return
+	}
+	if _, ok := p.activeChanCloses[chanID]; ok {
+		req.Err <- fmt.Errorf("ChannelID(%v) is being closed", chanID)
+		return
+	}
+
+	splice, err := p.newSplice(channel, req)
+	if err != nil {
+		p.log.Errorf("Unable to splice ChannelPoint(%v): %v",
+			req.ChanPoint, err)
+		req.Err <- err
+		return
+	}
+
+	spliceInit, err := splice.splicer.InitSplice()
+	if err != nil {
+		p.log.Errorf("Unable to splice ChannelPoint(%v): %v",
+			req.ChanPoint, err)
+		p.failSplice(splice, err)
+		return
+	}
+
+	p.queueMsg(spliceInit, nil)
+}
+
+// newSplice shuts down the link of the channel and starts a new splice of it.
+// The request is nil if the remote peer initiated the splice.
+func (p *Brontide) newSplice(channel *lnwallet.LightningChannel,
+	req *chansplicer.SpliceRequest) (*activeSplice, error) {
+
+	chanPoint := channel.ChannelPoint()
+	selfPolicy, _, err := p.fetchForwardingPolicy(chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := p.newSpliceCfg(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// The channel must be quiesced for the entire splice, so we shut
+	// down its link before the negotiation starts. It is only restarted
+	// once the splice is locked, as we don't sign commitments for both
+	// the old and the new funding output while the splice is pending.
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	if err := p.tryLinkShutdown(chanID); err != nil {
+		return nil, fmt.Errorf("unable to shut down link: %w", err)
+	}
+
+	splice := &activeSplice{
+		splicer:    chansplicer.NewChanSplicer(cfg, req),
+		channel:    channel,
+		selfPolicy: selfPolicy,
+		quit:       make(chan struct{}),
+	}
+	if err := p.startSplice(splice); err != nil {
+		p.restartLink(channel)
+		return nil, err
+	}
+
+	return splice, nil
+}
+
+// handleSpliceMsg advances the splice state machine of a channel with a
+// message sent by the remote peer.
+func (p *Brontide) handleSpliceMsg(msg *spliceMsg) {
+	splice, ok := p.activeSplices.Load(msg.cid)
+	if !ok {
+		switch m := msg.msg.(type) {
+		case *lnwire.SpliceInit:
+			var err error
+			splice, err = p.acceptSplice(msg.cid)
+			if err != nil {
+				p.log.Errorf("Unable to accept splice of "+
+					"ChannelID(%v): %v", msg.cid, err)
+				p.queueMsg(&lnwire.TxAbort{
+					ChanID: msg.cid,
+					Data:   []byte(err.Error()),
+				}, nil)
+
+				return
+			}
+
+		case *lnwire.SpliceLocked:
+			p.handleCompletedSpliceLocked(m)
+			return
+
+		default:
+			p.log.Debugf("Ignoring %v for ChannelID(%v) that "+
+				"isn't being spliced", msg.msg.MsgType(),
+				msg.cid)
+			return
+		}
+	}
+
+	msgs, err := splice.splicer.ProcessSpliceMsg(msg.msg)
+	if err != nil {
+		p.log.Errorf("Unable to process splice msg %v for "+
+			"ChannelID(%v): %v", msg.msg.MsgType(), msg.cid, err)
+		p.failSplice(splice, err)
+		return
+	}
+
+	for _, msg := range msgs {
+		p.queueMsg(msg, nil)
+	}
+
+	if splice.splicer.IsLocked() {
+		p.finalizeSplice(splice)
+	}
+}
+
+// acceptSplice starts a splice of a channel that the remote peer initiated.
+func (p *Brontide) acceptSplice(cid lnwire.ChannelID) (*activeSplice, error) {
+	if !p.cfg.Features.HasFeature(lnwire.SpliceOptional) {
+		return nil, fmt.Errorf("splicing is not enabled")
+	}
+
+	channel, ok := p.activeChannels.Load(cid)
+	if !ok || channel == nil {
+		return nil, ErrChannelNotFound
+	}
+	if _, ok := p.activeChanCloses[cid]; ok {
+		return nil, fmt.Errorf("channel is being closed")
+	}
+
+	return p.newSplice(channel, nil)
+}
+
+// failSplice handles a failed splice. A splice that wasn't persisted yet is
+// aborted, and the link of the channel is restarted. Otherwise, the splice
+// transaction may still confirm, so we keep waiting for it.
+func (p *Brontide) failSplice(splice *activeSplice, spliceErr error) {
+	cid := splice.splicer.ChanID()
+
+	if !splice.splicer.CanAbort() {
+		p.queueMsg(&lnwire.Warning{
+			ChanID: cid,
+			Data:   lnwire.WarningData(spliceErr.Error()),
+		}, nil)
+
+		return
+	}
+
+	// Either abort the splice, or acknowledge the abort of the remote
+	// peer.
+	p.queueMsg(&lnwire.TxAbort{
+		ChanID: cid,
+		Data:   []byte(spliceErr.Error()),
+	}, nil)
+
+	splice.splicer.Abort()
+	p.stopSplice(splice)
+
+	if req := splice.splicer.Request(); req != nil {
+		req.Err <- spliceErr
+	}
+
+	p.restartLink(splice.channel)
+}
+
+// restartLink adds the link of a channel back to the switch after an aborted
+// splice.
+func (p *Brontide) restartLink(channel *lnwallet.LightningChannel) {
+	chanPoint := channel.ChannelPoint()
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+
+	lnChan, err := lnwallet.NewLightningChannel(
+		p.cfg.Signer, channel.State(), p.cfg.SigPool,
+	)
+	if err != nil {
+		p.log.Errorf("Unable to restart ChannelPoint(%v): %v",
+			chanPoint, err)
+		return
+	}
+
+	if err := p.addChannelLink(lnChan); err != nil {
+		p.log.Errorf("Unable to restart ChannelPoint(%v): %v",
+			chanPoint, err)
+		return
+	}
+
+	p.activeChannels.Store(chanID, lnChan)
+}
+
+// addChannelLink adds a link for the passed channel to the switch, using our
+// forwarding policy of the channel.
+func (p *Brontide) addChannelLink(lnChan *lnwallet.LightningChannel) error {
+	chanPoint := lnChan.ChannelPoint()
+
+	_, forwardingPolicy, err := p.fetchForwardingPolicy(chanPoint)
+	if err != nil {
+		return err
+	}
+
+	chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(*chanPoint)
+	if err != nil {
+		return err
+	}
+
+	return p.addLink(chanPoint, lnChan, forwardingPolicy, chainEvents, true)
+}
+
+// handleSplicedChannel locks a splice once it has confirmed.
+func (p *Brontide) handleSplicedChannel(spliced *splicedChannel) {
+	splice, ok := p.activeSplices.Load(spliced.cid)
+	if !ok {
+		return
+	}
+
+	spliceLocked, err := splice.splicer.SpliceConfirmed(spliced.channel)
+	if err != nil {
+		p.log.Errorf("Unable to lock splice of ChannelID(%v): %v",
+			spliced.cid, err)
+		return
+	}
+
+	// The remote peer reestablishes the spliced channel under its new
+	// channel ID, so we'll register it as pending until its link is
+	// added.
+	newPoint := spliced.channel.FundingOutpoint
+	p.activeChannels.Store(lnwire.NewChanIDFromOutPoint(&newPoint), nil)
+
+	p.queueMsg(spliceLocked, nil)
+
+	if splice.splicer.IsLocked() {
+		p.finalizeSplice(splice)
+	}
+}
+
+// finalizeSplice adds the spliced channel to the channel graph and the switch
+// once both peers locked the splice.
+func (p *Brontide) finalizeSplice(splice *activeSplice) {
+	p.stopSplice(splice)
+
+	channel := splice.splicer.SplicedChannel()
+	newPoint := channel.FundingOutpoint
+	newID := lnwire.NewChanIDFromOutPoint(&newPoint)
+
+	p.log.Infof("Splice of ChannelPoint(%v) locked, channel moved to "+
+		"ChannelPoint(%v)", splice.splicer.PrevOutpoint(), newPoint)
+
+	p.activeChannels.Delete(splice.splicer.ChanID())
+
+	if p.cfg.AnnounceSplicedChannel != nil {
+		err := p.cfg.AnnounceSplicedChannel(channel, splice.selfPolicy)
+		if err != nil {
+			p.log.Errorf("Unable to announce spliced "+
+				"ChannelPoint(%v): %v", newPoint, err)
+		}
+	}
+
+	lnChan, err := lnwallet.NewLightningChannel(
+		p.cfg.Signer, channel, p.cfg.SigPool,
+	)
+	if err != nil {
+		p.log.Errorf("Unable to load spliced ChannelPoint(%v): %v",
+			newPoint, err)
+		return
+	}
+
+	if err := p.addChannelLink(lnChan); err != nil {
+		p.log.Errorf("Unable to add link for spliced "+
+			"ChannelPoint(%v): %v", newPoint, err)
+		return
+	}
+
+	p.activeChannels.Store(newID, lnChan)
+}
+
+// handleCompletedSpliceLocked responds to a splice_locked message of the
+// remote peer for a splice that we already completed, so the remote peer is
+// able to complete the splice as well after a restart.
+func (p *Brontide) handleCompletedSpliceLocked(msg *lnwire.SpliceLocked) {
+	var found bool
+	p.activeChannels.Range(func(_ lnwire.ChannelID,
+		lnChan *lnwallet.LightningChannel) bool {
+
+		if lnChan != nil &&
+			lnChan.ChannelPoint().Hash == msg.SpliceTxid {
+
+			found = true
+			return false
+		}
+
+		return true
+	})
+	if !found {
+		p.log.Debugf("Ignoring splice_locked for unknown splice tx %v",
+			msg.SpliceTxid)
+		return
+	}
+
+	p.queueMsg(&lnwire.SpliceLocked{
+		ChanID:     msg.ChanID,
+		SpliceTxid: msg.SpliceTxid,
+	}, nil)
+}
+
+// resumeSplice resumes the splice of a channel after a restart, and returns
+// the messages that must be retransmitted to the remote peer. False is
+// returned if the channel isn't being spliced.
+func (p *Brontide) resumeSplice(lnChan *lnwallet.LightningChannel) (bool,
+	[]lnwire.Message, error) {
+
+	dbChan := lnChan.State()
+	splice, err := dbChan.FetchSplice()
+	switch {
+	case errors.Is(err, channeldb.ErrNoSplice):
+		return false, nil, nil
+
+	case err != nil:
+		return false, nil, err
+	}
+
+	// A splice that was locked by both peers is complete.
+	isSplicing := dbChan.HasChanStatus(channeldb.ChanStatusSplicing)
+	if !isSplicing && (!splice.LocalLocked || splice.RemoteLocked) {
+		return false, nil, nil
+	}
+
+	p.log.Infof("Resuming splice of ChannelPoint(%v)",
+		splice.PrevOutpoint)
+
+	cfg, err := p.newSpliceCfg(lnChan)
+	if err != nil {
+		return false, nil, err
+	}
+
+	splicer, msgs, err := chansplicer.ResumeChanSplicer(cfg, splice)
+	if err != nil {
+		return false, nil, err
+	}
+
+	active := &activeSplice{
+		splicer: splicer,
+		channel: lnChan,
+		quit:    make(chan struct{}),
+	}
+
+	// Until the splice has confirmed, the channel is known under its
+	// current channel ID, and we'll wait for the confirmation.
+	if isSplicing {
+		p.activeChannels.Store(splicer.ChanID(), lnChan)
+
+		selfPolicy, _, err := p.fetchForwardingPolicy(
+			&splice.PrevOutpoint,
+		)
+		if err != nil {
+			return false, nil, err
+		}
+		active.selfPolicy = selfPolicy
+
+		if err := p.startSplice(active); err != nil {
+			return false, nil, err
+		}
+
+		return true, msgs, nil
+	}
+
+	// Otherwise, the channel was moved over to the new funding output
+	// already, and we wait for the splice_locked of the remote peer.
+	newPoint := splice.FundingOutpoint()
+	p.activeChannels.Store(lnwire.NewChanIDFromOutPoint(&newPoint), nil)
+	p.activeSplices.Store(splicer.ChanID(), active)
+
+	return true, msgs, nil
+}
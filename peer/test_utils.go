@@ -484,6 +484,13 @@ func (m *mockUpdateHandler) MayAddOutgoingHtlc(lnwire.MilliSatoshi) error { retu
 // ShutdownIfChannelClean currently returns nil.
 func (m *mockUpdateHandler) ShutdownIfChannelClean() error { return nil }
 
+// UpgradeCommitType currently returns a channel that is never written to.
+func (m *mockUpdateHandler) UpgradeCommitType(
+	lnwallet.CommitmentType) <-chan error {
+
+	return make(chan error)
+}
+
 type mockMessageConn struct {
 	t *testing.T
 
@@ -0,0 +1,413 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/tlv"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+)
+
+const (
+	// blindedPaddingType is the type of the padding that may be included
+	// in route blinding data to make all hops the same size.
+	blindedPaddingType tlv.Type = 1
+
+	// blindedShortChanIDType is the type of the short channel id that a
+	// forwarding node should use for the outgoing HTLC.
+	blindedShortChanIDType tlv.Type = 2
+
+	// blindedNextNodeIDType is the type of the node id of the next node
+	// in the blinded route.
+	blindedNextNodeIDType tlv.Type = 4
+
+	// blindedPathIDType is the type of the path id that the recipient of
+	// a blinded payment left for itself.
+	blindedPathIDType tlv.Type = 6
+
+	// blindedNextBlindingOverrideType is the type of the blinding point
+	// that replaces the next ephemeral key when two blinded routes are
+	// concatenated.
+	blindedNextBlindingOverrideType tlv.Type = 8
+
+	// blindedPaymentRelayType is the type of the fee and cltv parameters
+	// that a forwarding node should apply.
+	blindedPaymentRelayType tlv.Type = 10
+
+	// blindedPaymentConstraintsType is the type of the constraints that
+	// the incoming HTLC of a node in the blinded route must satisfy.
+	blindedPaymentConstraintsType tlv.Type = 12
+
+	// blindedFeaturesType is the type of the features that a node in the
+	// blinded route is expected to use.
+	blindedFeaturesType tlv.Type = 14
+)
+
+// PaymentRelayInfo describes the relay policy of a node inside a blinded
+// route.
+type PaymentRelayInfo struct {
+	// CltvExpiryDelta is the expiry delta for the payment.
+	CltvExpiryDelta uint16
+
+	// FeeRate is the fee rate that will be charged per millionth of a
+	// satoshi.
+	FeeRate uint32
+
+	// BaseFee is the per-htlc fee charged.
+	BaseFee uint32
+}
+
+// PaymentConstraints is a set of restrictions on a payment that are set by
+// the recipient to protect the privacy of its blinded route.
+type PaymentConstraints struct {
+	// MaxCltvExpiry is the maximum expiry height for the payment.
+	MaxCltvExpiry uint32
+
+	// HtlcMinimumMsat is the minimum htlc size for the payment.
+	HtlcMinimumMsat lnwire.MilliSatoshi
+}
+
+// BlindedRouteData contains the information that is included in a blinded
+// route's encrypted data blob that is created by the recipient to provide
+// forwarding information.
+type BlindedRouteData struct {
+	// ShortChannelID is the channel ID of the next hop.
+	ShortChannelID *lnwire.ShortChannelID
+
+	// NextNodeID is the node ID of the next hop, which may be used instead
+	// of the short channel ID.
+	NextNodeID *btcec.PublicKey
+
+	// PathID is a secret set of bytes that the recipient sets for itself
+	// to recognize payments to its blinded route. It is only set for the
+	// final hop.
+	PathID []byte
+
+	// NextBlindingOverride is a blinding point that should be switched
+	// in for the next hop. This is used to combine two blinded paths into
+	// one (which primarily is used in onion messaging, but in theory
+	// could be used for payments as well).
+	NextBlindingOverride *btcec.PublicKey
+
+	// RelayInfo provides the relay parameters for the hop.
+	RelayInfo *PaymentRelayInfo
+
+	// Constraints provides the payment relay constraints for the hop.
+	Constraints *PaymentConstraints
+
+	// Features is the set of features the payment requires.
+	Features *lnwire.FeatureVector
+}
+
+// records returns the TLV records of the blinded route data that are set,
+// sorted by type.
+func (b *BlindedRouteData) records() []tlv.Record {
+	var (
+		records []tlv.Record
+		scid    uint64
+	)
+
+	if b.ShortChannelID != nil {
+		scid = b.ShortChannelID.ToUint64()
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedShortChanIDType, &scid,
+		))
+	}
+
+	if b.NextNodeID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedNextNodeIDType, &b.NextNodeID,
+		))
+	}
+
+	if len(b.PathID) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedPathIDType, &b.PathID,
+		))
+	}
+
+	if b.NextBlindingOverride != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedNextBlindingOverrideType,
+			&b.NextBlindingOverride,
+		))
+	}
+
+	if b.RelayInfo != nil {
+		records = append(records, newPaymentRelayRecord(b.RelayInfo))
+	}
+
+	if b.Constraints != nil {
+		records = append(
+			records, newPaymentConstraintsRecord(b.Constraints),
+		)
+	}
+
+	if b.Features != nil {
+		records = append(records, newBlindedFeaturesRecord(b.Features))
+	}
+
+	return records
+}
+
+// EncodeBlindedRouteData encodes the blinded route data provided.
+func EncodeBlindedRouteData(data *BlindedRouteData) ([]byte, error) {
+	stream, err := tlv.NewStream(data.records()...)
+	if err != nil {
+		return nil, err
+	}
+
+	var w bytes.Buffer
+	if err := stream.Encode(&w); err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
+
+// DecodeBlindedRouteData decodes the data provided within a blinded route.
+func DecodeBlindedRouteData(r io.Reader) (*BlindedRouteData, error) {
+	var (
+		data        BlindedRouteData
+		padding     []byte
+		scid        uint64
+		pathID      []byte
+		nextNode    *btcec.PublicKey
+		override    *btcec.PublicKey
+		relayInfo   PaymentRelayInfo
+		constraints PaymentConstraints
+		features    = lnwire.EmptyFeatureVector()
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(blindedPaddingType, &padding),
+		tlv.MakePrimitiveRecord(blindedShortChanIDType, &scid),
+		tlv.MakePrimitiveRecord(blindedNextNodeIDType, &nextNode),
+		tlv.MakePrimitiveRecord(blindedPathIDType, &pathID),
+		tlv.MakePrimitiveRecord(
+			blindedNextBlindingOverrideType, &override,
+		),
+		newPaymentRelayRecord(&relayInfo),
+		newPaymentConstraintsRecord(&constraints),
+		newBlindedFeaturesRecord(features),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parsedTypes[blindedShortChanIDType]; ok {
+		shortID := lnwire.NewShortChanIDFromInt(scid)
+		data.ShortChannelID = &shortID
+	}
+
+	if _, ok := parsedTypes[blindedNextNodeIDType]; ok {
+		data.NextNodeID = nextNode
+	}
+
+	if _, ok := parsedTypes[blindedPathIDType]; ok {
+		data.PathID = pathID
+	}
+
+	if _, ok := parsedTypes[blindedNextBlindingOverrideType]; ok {
+		data.NextBlindingOverride = override
+	}
+
+	if _, ok := parsedTypes[blindedPaymentRelayType]; ok {
+		data.RelayInfo = &relayInfo
+	}
+
+	if _, ok := parsedTypes[blindedPaymentConstraintsType]; ok {
+		data.Constraints = &constraints
+	}
+
+	if _, ok := parsedTypes[blindedFeaturesType]; ok {
+		data.Features = features
+	}
+
+	return &data, nil
+}
+
+// newPaymentRelayRecord creates a tlv.Record that encodes the payment_relay
+// (type 10) of a blinded route data blob.
+func newPaymentRelayRecord(info *PaymentRelayInfo) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		blindedPaymentRelayType, info, func() uint64 {
+			// 2 bytes for the cltv expiry delta, 4 bytes for the
+			// fee rate and the truncated base fee.
+			return 2 + 4 + tlv.SizeTUint32(info.BaseFee)
+		},
+		encodePaymentRelay, decodePaymentRelay,
+	)
+}
+
+// encodePaymentRelay encodes the payment relay info of a blinded hop.
+func encodePaymentRelay(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*PaymentRelayInfo); ok {
+		err := tlv.EUint16T(w, v.CltvExpiryDelta, buf)
+		if err != nil {
+			return err
+		}
+
+		if err := tlv.EUint32T(w, v.FeeRate, buf); err != nil {
+			return err
+		}
+
+		return tlv.ETUint32T(w, v.BaseFee, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "*record.PaymentRelayInfo")
+}
+
+// decodePaymentRelay decodes the payment relay info of a blinded hop.
+func decodePaymentRelay(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*PaymentRelayInfo); ok && l >= 6 && l <= 10 {
+		var cltvDelta uint16
+		if err := tlv.DUint16(r, &cltvDelta, buf, 2); err != nil {
+			return err
+		}
+
+		var feeRate uint32
+		if err := tlv.DUint32(r, &feeRate, buf, 4); err != nil {
+			return err
+		}
+
+		var baseFee uint32
+		if err := tlv.DTUint32(r, &baseFee, buf, l-6); err != nil {
+			return err
+		}
+
+		v.CltvExpiryDelta = cltvDelta
+		v.FeeRate = feeRate
+		v.BaseFee = baseFee
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "*record.PaymentRelayInfo", l, 10)
+}
+
+// newPaymentConstraintsRecord creates a tlv.Record that encodes the
+// payment_constraints (type 12) of a blinded route data blob.
+func newPaymentConstraintsRecord(constraints *PaymentConstraints) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		blindedPaymentConstraintsType, constraints, func() uint64 {
+			// 4 bytes for the max cltv expiry and the truncated
+			// htlc minimum.
+			return 4 + tlv.SizeTUint64(
+				uint64(constraints.HtlcMinimumMsat),
+			)
+		},
+		encodePaymentConstraints, decodePaymentConstraints,
+	)
+}
+
+// encodePaymentConstraints encodes the payment constraints of a blinded hop.
+func encodePaymentConstraints(w io.Writer, val interface{},
+	buf *[8]byte) error {
+
+	if v, ok := val.(*PaymentConstraints); ok {
+		if err := tlv.EUint32T(w, v.MaxCltvExpiry, buf); err != nil {
+			return err
+		}
+
+		return tlv.ETUint64T(w, uint64(v.HtlcMinimumMsat), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "*record.PaymentConstraints")
+}
+
+// decodePaymentConstraints decodes the payment constraints of a blinded hop.
+func decodePaymentConstraints(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*PaymentConstraints); ok && l >= 4 && l <= 12 {
+		var maxCltv uint32
+		if err := tlv.DUint32(r, &maxCltv, buf, 4); err != nil {
+			return err
+		}
+
+		var htlcMin uint64
+		if err := tlv.DTUint64(r, &htlcMin, buf, l-4); err != nil {
+			return err
+		}
+
+		v.MaxCltvExpiry = maxCltv
+		v.HtlcMinimumMsat = lnwire.MilliSatoshi(htlcMin)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "*record.PaymentConstraints", l, 12,
+	)
+}
+
+// newBlindedFeaturesRecord creates a tlv.Record that encodes the
+// allowed_features (type 14) of a blinded route data blob.
+func newBlindedFeaturesRecord(features *lnwire.FeatureVector) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		blindedFeaturesType, features, func() uint64 {
+			return uint64(features.SerializeSize())
+		},
+		encodeBlindedFeatures, decodeBlindedFeatures,
+	)
+}
+
+// encodeBlindedFeatures encodes the features of a blinded hop.
+func encodeBlindedFeatures(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*lnwire.FeatureVector); ok {
+		return v.EncodeBase256(w)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "*lnwire.FeatureVector")
+}
+
+// decodeBlindedFeatures decodes the features of a blinded hop.
+func decodeBlindedFeatures(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*lnwire.FeatureVector); ok {
+		return v.DecodeBase256(r, int(l))
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "*lnwire.FeatureVector", l, l)
+}
+
+// String returns a human-readable representation of the blinded route data.
+func (b *BlindedRouteData) String() string {
+	var (
+		next       string
+		relayInfo  = "<nil>"
+		constraint = "<nil>"
+	)
+	switch {
+	case b.ShortChannelID != nil:
+		next = fmt.Sprintf("chan_id=%v", *b.ShortChannelID)
+
+	case b.NextNodeID != nil:
+		next = fmt.Sprintf("node_id=%x",
+			b.NextNodeID.SerializeCompressed())
+
+	default:
+		next = fmt.Sprintf("path_id=%x", b.PathID)
+	}
+
+	if b.RelayInfo != nil {
+		relayInfo = fmt.Sprintf("%+v", *b.RelayInfo)
+	}
+	if b.Constraints != nil {
+		constraint = fmt.Sprintf("%+v", *b.Constraints)
+	}
+
+	return fmt.Sprintf("%v, relay=%v, constraints=%v", next, relayInfo,
+		constraint)
+}
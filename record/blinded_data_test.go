@@ -0,0 +1,78 @@
+package record_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/record"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlindedDataEncoding tests encoding and decoding of the route blinding
+// data of forwarding and final hops.
+func TestBlindedDataEncoding(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	scid := lnwire.NewShortChanIDFromInt(1234)
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.RouteBlindingOptional),
+		lnwire.Features,
+	)
+
+	testCases := []struct {
+		name string
+		data *record.BlindedRouteData
+	}{{
+		name: "forwarding hop",
+		data: &record.BlindedRouteData{
+			ShortChannelID: &scid,
+			RelayInfo: &record.PaymentRelayInfo{
+				CltvExpiryDelta: 40,
+				FeeRate:         1000,
+				BaseFee:         1,
+			},
+			Constraints: &record.PaymentConstraints{
+				MaxCltvExpiry:   1_000_000,
+				HtlcMinimumMsat: 1,
+			},
+			Features: features,
+		},
+	}, {
+		name: "forwarding hop with override",
+		data: &record.BlindedRouteData{
+			NextNodeID:           privKey.PubKey(),
+			NextBlindingOverride: privKey.PubKey(),
+			RelayInfo:            &record.PaymentRelayInfo{},
+		},
+	}, {
+		name: "final hop",
+		data: &record.BlindedRouteData{
+			PathID: []byte{1, 2, 3},
+			Constraints: &record.PaymentConstraints{
+				MaxCltvExpiry: 500,
+			},
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := record.EncodeBlindedRouteData(tc.data)
+			require.NoError(t, err)
+
+			decoded, err := record.DecodeBlindedRouteData(
+				bytes.NewReader(encoded),
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.data, decoded)
+		})
+	}
+}
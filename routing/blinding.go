@@ -0,0 +1,174 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
+)
+
+var (
+	// ErrNoBlindedPath is returned when the blinded path in a blinded
+	// payment is missing.
+	ErrNoBlindedPath = errors.New("blinded path required")
+
+	// ErrInsufficientBlindedHops is returned when a blinded path does
+	// not have enough blinded hops.
+	ErrInsufficientBlindedHops = errors.New("blinded path requires " +
+		"at least one hop")
+
+	// ErrHTLCRestrictions is returned when a blinded path has invalid
+	// HTLC maximum and minimum values.
+	ErrHTLCRestrictions = errors.New("invalid htlc minimum and maximum")
+
+	// ErrSelfIntroduction is returned when we are the introduction node
+	// of the blinded path we're asked to pay to.
+	ErrSelfIntroduction = errors.New("cannot pay to a blinded path " +
+		"that we're the introduction node of")
+)
+
+// BlindedPayment provides the path and payment parameters required to send a
+// payment along a blinded path.
+type BlindedPayment struct {
+	// BlindedPath contains the unblinded introduction point and blinded
+	// hops for the blinded section of the payment. The first blinded hop
+	// is the introduction node itself.
+	BlindedPath *sphinx.BlindedPath
+
+	// BaseFee is the total base fee to be paid for payments made over
+	// the blinded path.
+	BaseFee uint32
+
+	// ProportionalFeeRate is the aggregated proportional fee rate for
+	// payments made over the blinded path.
+	ProportionalFeeRate uint32
+
+	// CltvExpiryDelta is the total expiry delta for the blinded path,
+	// including the final expiry delta of the recipient.
+	CltvExpiryDelta uint16
+
+	// HtlcMinimum is the highest HLTC minimum supported along the blinded
+	// path (while some hops may have lower values, we're effectively
+	// bounded by the highest minimum).
+	HtlcMinimum uint64
+
+	// HtlcMaximum is the lowest HTLC maximum supported along the blinded
+	// path (while some hops may have higher values, we're effectively
+	// bounded by the lowest maximum).
+	HtlcMaximum uint64
+
+	// Features is the set of relay features available for the payment.
+	Features *lnwire.FeatureVector
+}
+
+// Validate performs validation on a blinded payment.
+func (b *BlindedPayment) Validate() error {
+	if b.BlindedPath == nil {
+		return ErrNoBlindedPath
+	}
+
+	// The sphinx library inserts the introduction node as the first hop,
+	// so we expect at least one hop.
+	if len(b.BlindedPath.BlindedHops) < 1 {
+		return fmt.Errorf("%w got: %v", ErrInsufficientBlindedHops,
+			len(b.BlindedPath.BlindedHops))
+	}
+
+	if b.HtlcMaximum < b.HtlcMinimum {
+		return fmt.Errorf("%w: %v < %v", ErrHTLCRestrictions,
+			b.HtlcMaximum, b.HtlcMinimum)
+	}
+
+	return nil
+}
+
+// Target returns the vertex that path finding should find a route to. This is
+// the blinded public key of the last hop in the blinded path, or the
+// introduction node if it is the recipient itself.
+func (b *BlindedPayment) Target() route.Vertex {
+	hops := b.BlindedPath.BlindedHops
+	if len(hops) == 1 {
+		return route.NewVertex(b.BlindedPath.IntroductionPoint)
+	}
+
+	return route.NewVertex(hops[len(hops)-1].BlindedNodePub)
+}
+
+// FinalCltvDelta returns the final expiry delta that needs to be applied on
+// top of the route to the blinded path. For paths that contain more than the
+// introduction node, the recipient's expiry delta is already included in the
+// aggregated expiry delta of the path's first edge.
+func (b *BlindedPayment) FinalCltvDelta() uint16 {
+	if len(b.BlindedPath.BlindedHops) == 1 {
+		return b.CltvExpiryDelta
+	}
+
+	return 0
+}
+
+// toRouteHints produces a set of chained route hints that represent a
+// blinded path. The aggregated fees and expiry delta of the whole path are
+// charged on the edge leaving the introduction node, while the remaining
+// edges between blinded hops are free, as their policies are included in the
+// aggregate.
+func (b *BlindedPayment) toRouteHints() (
+	hints map[route.Vertex][]*channeldb.CachedEdgePolicy) {
+
+	hints = make(map[route.Vertex][]*channeldb.CachedEdgePolicy)
+
+	// If the introduction node is the recipient, there are no edges to
+	// add to the graph.
+	hops := b.BlindedPath.BlindedHops
+	if len(hops) == 1 {
+		return hints
+	}
+
+	features := b.Features
+	if features == nil {
+		features = lnwire.EmptyFeatureVector()
+	}
+
+	// All hops in the blinded path are at least bounded by the aggregate
+	// HTLC minimum and maximum of the path, if the latter is known.
+	var msgFlags lnwire.ChanUpdateMsgFlags
+	if b.HtlcMaximum != 0 {
+		msgFlags |= lnwire.ChanUpdateRequiredMaxHtlc
+	}
+	minHTLC := lnwire.MilliSatoshi(b.HtlcMinimum)
+	maxHTLC := lnwire.MilliSatoshi(b.HtlcMaximum)
+
+	for i := 1; i < len(hops); i++ {
+		fromNode := route.NewVertex(hops[i-1].BlindedNodePub)
+		if i == 1 {
+			fromNode = route.NewVertex(
+				b.BlindedPath.IntroductionPoint,
+			)
+		}
+		toNode := route.NewVertex(hops[i].BlindedNodePub)
+
+		edge := &channeldb.CachedEdgePolicy{
+			ToNodePubKey: func() route.Vertex {
+				return toNode
+			},
+			ToNodeFeatures: features,
+			MessageFlags:   msgFlags,
+			MinHTLC:        minHTLC,
+			MaxHTLC:        maxHTLC,
+		}
+
+		if i == 1 {
+			edge.FeeBaseMSat = lnwire.MilliSatoshi(b.BaseFee)
+			edge.FeeProportionalMillionths = lnwire.MilliSatoshi(
+				b.ProportionalFeeRate,
+			)
+			edge.TimeLockDelta = b.CltvExpiryDelta
+		}
+
+		hints[fromNode] = append(hints[fromNode], edge)
+	}
+
+	return hints
+}
@@ -0,0 +1,248 @@
+package routing
+
+import (
+	"testing"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/routing/route"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBlindedPath creates a blinded path with the given number of blinded
+// hops, using random keys for all of its nodes.
+func newTestBlindedPath(t *testing.T, numHops int) *sphinx.BlindedPath {
+	t.Helper()
+
+	newKey := func() *btcec.PublicKey {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return privKey.PubKey()
+	}
+
+	path := &sphinx.BlindedPath{
+		IntroductionPoint: newKey(),
+		BlindingPoint:     newKey(),
+	}
+	for i := 0; i < numHops; i++ {
+		path.BlindedHops = append(path.BlindedHops,
+			&sphinx.BlindedHopInfo{
+				BlindedNodePub: newKey(),
+				CipherText:     []byte{byte(i + 1)},
+			},
+		)
+	}
+
+	return path
+}
+
+// TestBlindedPathValidation tests validation of blinded paths.
+func TestBlindedPathValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payment *BlindedPayment
+		err     error
+	}{
+		{
+			name:    "no path",
+			payment: &BlindedPayment{},
+			err:     ErrNoBlindedPath,
+		},
+		{
+			name: "insufficient hops",
+			payment: &BlindedPayment{
+				BlindedPath: newTestBlindedPath(t, 0),
+			},
+			err: ErrInsufficientBlindedHops,
+		},
+		{
+			name: "maximum < minimum",
+			payment: &BlindedPayment{
+				BlindedPath: newTestBlindedPath(t, 2),
+				HtlcMaximum: 10,
+				HtlcMinimum: 20,
+			},
+			err: ErrHTLCRestrictions,
+		},
+		{
+			name: "valid",
+			payment: &BlindedPayment{
+				BlindedPath: newTestBlindedPath(t, 2),
+				HtlcMaximum: 15,
+				HtlcMinimum: 5,
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.payment.Validate()
+			require.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
+
+// TestBlindedPaymentToHints tests conversion of a blinded path to a chain of
+// route hints, with the aggregate policy of the path charged by the
+// introduction node.
+func TestBlindedPaymentToHints(t *testing.T) {
+	t.Parallel()
+
+	// A path that only contains the introduction node has no edges.
+	payment := &BlindedPayment{
+		BlindedPath:     newTestBlindedPath(t, 1),
+		CltvExpiryDelta: 40,
+	}
+	require.Empty(t, payment.toRouteHints())
+	require.Equal(
+		t, route.NewVertex(payment.BlindedPath.IntroductionPoint),
+		payment.Target(),
+	)
+	require.EqualValues(t, 40, payment.FinalCltvDelta())
+
+	payment = &BlindedPayment{
+		BlindedPath:         newTestBlindedPath(t, 3),
+		BaseFee:             1000,
+		ProportionalFeeRate: 10,
+		CltvExpiryDelta:     144,
+		HtlcMinimum:         100,
+		HtlcMaximum:         10_000,
+	}
+	require.NoError(t, payment.Validate())
+
+	path := payment.BlindedPath
+	hops := path.BlindedHops
+	require.Equal(
+		t, route.NewVertex(hops[2].BlindedNodePub), payment.Target(),
+	)
+	require.Zero(t, payment.FinalCltvDelta())
+
+	hints := payment.toRouteHints()
+	require.Len(t, hints, 2)
+
+	checkEdge := func(from, to *btcec.PublicKey,
+		expected channeldb.CachedEdgePolicy) {
+
+		edges := hints[route.NewVertex(from)]
+		require.Len(t, edges, 1)
+
+		edge := edges[0]
+		require.Equal(t, route.NewVertex(to), edge.ToNodePubKey())
+		require.Equal(t, expected.FeeBaseMSat, edge.FeeBaseMSat)
+		require.Equal(
+			t, expected.FeeProportionalMillionths,
+			edge.FeeProportionalMillionths,
+		)
+		require.Equal(t, expected.TimeLockDelta, edge.TimeLockDelta)
+		require.True(t, edge.MessageFlags.HasMaxHtlc())
+		require.EqualValues(t, 100, edge.MinHTLC)
+		require.EqualValues(t, 10_000, edge.MaxHTLC)
+	}
+
+	checkEdge(
+		path.IntroductionPoint, hops[1].BlindedNodePub,
+		channeldb.CachedEdgePolicy{
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 10,
+			TimeLockDelta:             144,
+		},
+	)
+	checkEdge(
+		hops[1].BlindedNodePub, hops[2].BlindedNodePub,
+		channeldb.CachedEdgePolicy{},
+	)
+}
+
+// TestNewRouteBlinded tests that routes to a blinded path carry the route
+// blinding data of the path's hops.
+func TestNewRouteBlinded(t *testing.T) {
+	t.Parallel()
+
+	payment := &BlindedPayment{
+		BlindedPath:         newTestBlindedPath(t, 3),
+		BaseFee:             100,
+		ProportionalFeeRate: 0,
+		CltvExpiryDelta:     100,
+	}
+	path := payment.BlindedPath
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	source := route.NewVertex(privKey.PubKey())
+
+	// The route consists of a public channel to the introduction node,
+	// followed by the edges of the blinded path.
+	introVertex := route.NewVertex(path.IntroductionPoint)
+	pathEdges := []*channeldb.CachedEdgePolicy{{
+		ChannelID: 1,
+		ToNodePubKey: func() route.Vertex {
+			return introVertex
+		},
+		ToNodeFeatures: lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(
+				lnwire.TLVOnionPayloadOptional,
+			), lnwire.Features,
+		),
+	}}
+	pathEdges = append(
+		pathEdges, payment.toRouteHints()[introVertex]...,
+	)
+	pathEdges = append(pathEdges, payment.toRouteHints()[route.NewVertex(
+		path.BlindedHops[1].BlindedNodePub,
+	)]...)
+
+	rt, err := newRoute(
+		source, pathEdges, 1000, finalHopParams{
+			amt:         1000,
+			totalAmt:    1000,
+			cltvDelta:   payment.FinalCltvDelta(),
+			blindedPath: path,
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, rt.Hops, 3)
+
+	// The sender pays the aggregate fee of the path to the introduction
+	// node, and locks the funds up for the aggregate expiry delta.
+	require.EqualValues(t, 1100, rt.TotalAmount)
+	require.EqualValues(t, 1100, rt.TotalTimeLock)
+
+	introHop := rt.Hops[0]
+	require.Equal(t, introVertex, introHop.PubKeyBytes)
+	require.Equal(t, path.BlindingPoint, introHop.BlindingPoint)
+
+	for i, hop := range rt.Hops {
+		require.False(t, hop.LegacyPayload)
+		require.Equal(
+			t, path.BlindedHops[i].CipherText, hop.EncryptedData,
+		)
+	}
+
+	finalHop := rt.Hops[2]
+	require.EqualValues(t, 1000, finalHop.TotalAmtMsat)
+	require.EqualValues(t, 1000, finalHop.AmtToForward)
+	require.Nil(t, finalHop.MPP)
+
+	// The route can be encoded into an onion.
+	_, err = rt.ToSphinxPath()
+	require.NoError(t, err)
+
+	// If we are the introduction node, we can't pay to the path.
+	_, err = newRoute(
+		introVertex, pathEdges[1:], 1000, finalHopParams{
+			amt:         1000,
+			totalAmt:    1000,
+			blindedPath: path,
+		},
+	)
+	require.ErrorIs(t, err, ErrSelfIntroduction)
+}
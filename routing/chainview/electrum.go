@@ -30,6 +30,11 @@ type ElectrumClient interface {
 	GetBlockHeader(ctx context.Context, height uint32) (*wire.BlockHeader,
 		error)
 
+	// GetBlockHeaders retrieves count consecutive block headers starting
+	// at the given height in a single request.
+	GetBlockHeaders(ctx context.Context, startHeight,
+		count uint32) ([]*wire.BlockHeader, error)
+
 	// GetHistory retrieves the transaction history for a scripthash.
 	GetHistory(ctx context.Context,
 		scripthash string) ([]*HistoryResult, error)
@@ -38,8 +43,30 @@ type ElectrumClient interface {
 	// wire.MsgTx.
 	GetTransactionMsgTx(ctx context.Context,
 		txHash *chainhash.Hash) (*wire.MsgTx, error)
+
+	// VerifyTxInclusion checks that the transaction is committed to by a
+	// valid block header at the given height, using the server's merkle
+	// proof. An error is returned if the proof or header is invalid.
+	VerifyTxInclusion(ctx context.Context, txHash *chainhash.Hash,
+		height uint32) error
 }
 
+const (
+	// maxReorgDepth is the number of recent block hashes the Electrum
+	// chain view keeps around to find the fork point of a reorg.
+	maxReorgDepth = 144
+
+	// forkSearchDepth is the number of headers at or below our best
+	// height that are requested along with the ones above it when looking
+	// for a fork point. It covers the reorgs seen in practice with a
+	// single request.
+	forkSearchDepth = 16
+
+	// maxHeaderBatch is the maximum number of headers an Electrum server
+	// returns for a single blockchain.block.headers request.
+	maxHeaderBatch = 2016
+)
+
 // HeaderResult represents a block header notification from an Electrum server.
 type HeaderResult struct {
 	Height int32
@@ -64,6 +91,11 @@ type ElectrumFilteredChainView struct {
 	bestHeightMtx sync.Mutex
 	bestHeight    uint32
 
+	// recentHashes holds the hashes of the blocks we've dispatched within
+	// the last maxReorgDepth heights, keyed by height. It's used to find
+	// the fork point when the server switches to a different branch.
+	recentHashes map[uint32]chainhash.Hash
+
 	// client is the Electrum client used for all RPC operations.
 	client ElectrumClient
 
@@ -115,6 +147,7 @@ func NewElectrumFilteredChainView(
 
 	return &ElectrumFilteredChainView{
 		client:               client,
+		recentHashes:         make(map[uint32]chainhash.Hash),
 		blockQueue:           newBlockEventQueue(),
 		filterUpdates:        make(chan electrumFilterUpdate),
 		chainFilter:          make(map[wire.OutPoint][]byte),
@@ -157,6 +190,20 @@ func (e *ElectrumFilteredChainView) Start() error {
 		e.bestHeight = uint32(header.Height)
 		e.bestHeightMtx.Unlock()
 
+		// Remember the hash of our starting block so a reorg of it
+		// can be detected.
+		blockHeader, err := e.client.GetBlockHeader(
+			ctx, uint32(header.Height),
+		)
+		if err != nil {
+			log.Warnf("Unable to fetch initial header at height "+
+				"%d: %v", header.Height, err)
+		} else {
+			e.recordBlock(
+				uint32(header.Height), blockHeader.BlockHash(),
+			)
+		}
+
 		log.Debugf("ElectrumFilteredChainView initial height: %d",
 			header.Height)
 
@@ -221,91 +268,209 @@ func (e *ElectrumFilteredChainView) blockSubscriptionHandler(
 	}
 }
 
-// handleBlockConnected processes a new block header notification, filters
-// for relevant transactions, and dispatches the filtered block event.
+// handleBlockConnected processes a new block header notification. The server
+// only announces its latest tip, so we first find where its branch forks off
+// from the blocks we've dispatched. The stale blocks above the fork point are
+// disconnected from the tip downwards, after which every block of the new
+// branch is filtered for relevant transactions and connected in order.
 func (e *ElectrumFilteredChainView) handleBlockConnected(
 	header *HeaderResult) {
 
-	blockHeight := uint32(header.Height)
+	tipHeight := uint32(header.Height)
+
+	forkHeight, branch, err := e.findForkPoint(tipHeight)
+	if err != nil {
+		log.Errorf("Unable to find fork point for tip at height %d: "+
+			"%v", tipHeight, err)
+		return
+	}
 
 	e.bestHeightMtx.Lock()
 	prevBestHeight := e.bestHeight
 	e.bestHeightMtx.Unlock()
 
-	// Check for reorg - if the new height is less than or equal to what
-	// we've seen, we may have a reorg situation.
-	if blockHeight <= prevBestHeight && blockHeight > 0 {
-		e.handlePotentialReorg(blockHeight, prevBestHeight)
+	if forkHeight < prevBestHeight {
+		log.Infof("Reorg detected: fork at height %d, disconnecting "+
+			"%d block(s)", forkHeight, prevBestHeight-forkHeight)
+
+		e.disconnectBlocks(forkHeight)
 	}
 
-	// Get the block header to retrieve the hash.
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	for _, block := range branch {
+		// Filter the block for transactions that spend our watched
+		// outputs.
+		block.Transactions = e.filterBlockTransactions(block.Height)
 
-	blockHeader, err := e.client.GetBlockHeader(ctx, blockHeight)
-	if err != nil {
-		log.Errorf("Failed to get block header at height %d: %v",
-			blockHeight, err)
-		return
-	}
+		e.recordBlock(block.Height, block.Hash)
 
-	blockHash := blockHeader.BlockHash()
+		e.blockQueue.Add(&blockEvent{
+			eventType: connected,
+			block:     block,
+		})
+	}
+}
 
-	// Filter the block for transactions that spend our watched outputs.
-	filteredTxns := e.filterBlockTransactions(blockHeight)
+// findForkPoint walks the server's chain back from the given tip height until
+// it reaches a block we've dispatched ourselves. It returns the height of that
+// common ancestor and the server's blocks above it, ordered by height. If the
+// walk goes below the window of hashes we remember, the block just above the
+// window is assumed to be shared. The server's headers are fetched in batches,
+// so the walk usually takes a single request.
+func (e *ElectrumFilteredChainView) findForkPoint(
+	tipHeight uint32) (uint32, []*FilteredBlock, error) {
 
-	// Update best height.
 	e.bestHeightMtx.Lock()
-	e.bestHeight = blockHeight
+	bestHeight := e.bestHeight
 	e.bestHeightMtx.Unlock()
 
-	// Create and dispatch the filtered block.
-	filteredBlock := &FilteredBlock{
-		Hash:         blockHash,
-		Height:       blockHeight,
-		Transactions: filteredTxns,
+	var (
+		branch     []*FilteredBlock
+		headers    []*wire.BlockHeader
+		batchStart uint32
+	)
+	height := tipHeight
+	for {
+		if len(headers) == 0 || height < batchStart {
+			var err error
+			batchStart, headers, err = e.fetchForkBatch(
+				height, bestHeight,
+			)
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+
+		hash := headers[height-batchStart].BlockHash()
+		if height <= bestHeight {
+			ours, ok := e.recentHash(height)
+			if !ok {
+				log.Warnf("Reorg deeper than %d blocks, "+
+					"assuming common ancestor at height %d",
+					maxReorgDepth, height)
+
+				break
+			}
+
+			if ours == hash {
+				break
+			}
+		}
+
+		branch = append(branch, &FilteredBlock{
+			Hash:   hash,
+			Height: height,
+		})
+
+		if height == 0 {
+			break
+		}
+		height--
+	}
+
+	// The branch was collected from the tip downwards, so reverse it to
+	// connect the blocks in order.
+	for i, j := 0, len(branch)-1; i < j; i, j = i+1, j-1 {
+		branch[i], branch[j] = branch[j], branch[i]
 	}
 
-	e.blockQueue.Add(&blockEvent{
-		eventType: connected,
-		block:     filteredBlock,
-	})
+	if len(branch) == 0 {
+		return tipHeight, nil, nil
+	}
+
+	return branch[0].Height - 1, branch, nil
 }
 
-// handlePotentialReorg handles potential chain reorganizations by sending
-// disconnected block events for blocks that are no longer on the main chain.
-func (e *ElectrumFilteredChainView) handlePotentialReorg(newHeight,
-	prevHeight uint32) {
+// fetchForkBatch fetches a batch of the server's headers that ends at the
+// given height, for the fork point search. The batch covers the headers above
+// our best height along with a few below it, but never more than the server
+// returns at once. The height of the first header is returned along with the
+// batch.
+func (e *ElectrumFilteredChainView) fetchForkBatch(height,
+	bestHeight uint32) (uint32, []*wire.BlockHeader, error) {
+
+	count := uint32(forkSearchDepth)
+	if height > bestHeight {
+		count += height - bestHeight
+	}
+	if count > maxHeaderBatch {
+		count = maxHeaderBatch
+	}
+	if count > height+1 {
+		count = height + 1
+	}
+	start := height - count + 1
 
-	log.Debugf("Potential reorg detected: new height %d, prev height %d",
-		newHeight, prevHeight)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// Send disconnected events for blocks from prevHeight down to
-	// newHeight.
-	for h := prevHeight; h >= newHeight; h-- {
-		ctx, cancel := context.WithTimeout(
-			context.Background(), 10*time.Second,
-		)
-		blockHeader, err := e.client.GetBlockHeader(ctx, h)
-		cancel()
+	headers, err := e.client.GetBlockHeaders(ctx, start, count)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to fetch headers at "+
+			"height %d: %w", start, err)
+	}
 
-		if err != nil {
-			log.Warnf("Failed to get header for disconnected "+
-				"block %d: %v", h, err)
-			continue
-		}
+	if uint32(len(headers)) != count {
+		return 0, nil, fmt.Errorf("expected %d headers starting at "+
+			"height %d, got %d", count, start, len(headers))
+	}
 
-		blockHash := blockHeader.BlockHash()
-		disconnectedBlock := &FilteredBlock{
-			Hash:   blockHash,
-			Height: h,
+	return start, headers, nil
+}
+
+// disconnectBlocks sends disconnected block events for all blocks above the
+// fork height, starting at our best block, and rewinds our best height to the
+// fork point.
+func (e *ElectrumFilteredChainView) disconnectBlocks(forkHeight uint32) {
+	e.bestHeightMtx.Lock()
+	defer e.bestHeightMtx.Unlock()
+
+	for height := e.bestHeight; height > forkHeight; height-- {
+		hash, ok := e.recentHashes[height]
+		if !ok {
+			log.Warnf("Unknown hash for disconnected block at "+
+				"height %d", height)
 		}
+		delete(e.recentHashes, height)
 
 		e.blockQueue.Add(&blockEvent{
 			eventType: disconnected,
-			block:     disconnectedBlock,
+			block: &FilteredBlock{
+				Hash:   hash,
+				Height: height,
+			},
 		})
 	}
+
+	e.bestHeight = forkHeight
+}
+
+// recordBlock makes the given block our best block and remembers its hash,
+// forgetting hashes that have fallen out of the reorg window.
+func (e *ElectrumFilteredChainView) recordBlock(height uint32,
+	hash chainhash.Hash) {
+
+	e.bestHeightMtx.Lock()
+	defer e.bestHeightMtx.Unlock()
+
+	e.bestHeight = height
+	e.recentHashes[height] = hash
+
+	if height >= maxReorgDepth {
+		delete(e.recentHashes, height-maxReorgDepth)
+	}
+}
+
+// recentHash returns the hash of the block we dispatched at the given height,
+// if it's still within the reorg window.
+func (e *ElectrumFilteredChainView) recentHash(
+	height uint32) (chainhash.Hash, bool) {
+
+	e.bestHeightMtx.Lock()
+	defer e.bestHeightMtx.Unlock()
+
+	hash, ok := e.recentHashes[height]
+
+	return hash, ok
 }
 
 // scripthashFromScript converts a pkScript (output script) to an Electrum
@@ -386,15 +551,25 @@ func (e *ElectrumFilteredChainView) filterBlockTransactions(
 
 			// Check if this transaction spends our outpoint.
 			for _, txIn := range tx.TxIn {
-				if txIn.PreviousOutPoint == outpoint {
-					filteredTxns = append(
-						filteredTxns, tx.Copy(),
-					)
-					spentOutpoints = append(
-						spentOutpoints, outpoint,
-					)
+				if txIn.PreviousOutPoint != outpoint {
+					continue
+				}
+
+				// Don't trust the server's claim that the
+				// spend confirmed until it proves it.
+				err := e.client.VerifyTxInclusion(
+					ctx, txHash, uint32(histItem.Height),
+				)
+				if err != nil {
+					log.Warnf("Unable to verify spend of "+
+						"%v by %v: %v", outpoint,
+						txHash, err)
 					break
 				}
+
+				filteredTxns = append(filteredTxns, tx.Copy())
+				spentOutpoints = append(spentOutpoints, outpoint)
+				break
 			}
 		}
 	}
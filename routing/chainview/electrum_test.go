@@ -23,6 +23,9 @@ type mockElectrumClient struct {
 
 	headerChan chan *HeaderResult
 
+	// headerRequests counts the batch header requests.
+	headerRequests int
+
 	mu sync.RWMutex
 }
 
@@ -69,6 +72,35 @@ func (m *mockElectrumClient) GetBlockHeader(ctx context.Context,
 	}, nil
 }
 
+// GetBlockHeaders returns count headers starting at the given height.
+func (m *mockElectrumClient) GetBlockHeaders(ctx context.Context, startHeight,
+	count uint32) ([]*wire.BlockHeader, error) {
+
+	m.mu.Lock()
+	m.headerRequests++
+	m.mu.Unlock()
+
+	headers := make([]*wire.BlockHeader, 0, count)
+	for height := startHeight; height < startHeight+count; height++ {
+		header, err := m.GetBlockHeader(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, header)
+	}
+
+	return headers, nil
+}
+
+// numHeaderRequests returns the number of batch header requests made so far.
+func (m *mockElectrumClient) numHeaderRequests() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.headerRequests
+}
+
 // GetHistory returns the transaction history for a scripthash.
 func (m *mockElectrumClient) GetHistory(ctx context.Context,
 	scripthash string) ([]*HistoryResult, error) {
@@ -97,6 +129,14 @@ func (m *mockElectrumClient) GetTransactionMsgTx(ctx context.Context,
 	return wire.NewMsgTx(wire.TxVersion), nil
 }
 
+// VerifyTxInclusion accepts every transaction as the mock serves no merkle
+// proofs.
+func (m *mockElectrumClient) VerifyTxInclusion(ctx context.Context,
+	txHash *chainhash.Hash, height uint32) error {
+
+	return nil
+}
+
 // setConnected sets the connection status of the mock client.
 func (m *mockElectrumClient) setConnected(connected bool) {
 	m.mu.Lock()
@@ -358,3 +398,133 @@ func TestElectrumFilteredChainViewBlockConnected(t *testing.T) {
 		t.Fatal("timeout waiting for filtered block")
 	}
 }
+
+// addTestBranch adds a branch of headers on top of the header at forkHeight
+// up to and including tipHeight, and returns their hashes keyed by height. The
+// branch byte makes the headers differ from those of other branches.
+func (m *mockElectrumClient) addTestBranch(forkHeight, tipHeight uint32,
+	branch byte) map[uint32]chainhash.Hash {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hashes := make(map[uint32]chainhash.Hash)
+	for height := forkHeight + 1; height <= tipHeight; height++ {
+		var prevHash chainhash.Hash
+		if prev, ok := m.headers[height-1]; ok {
+			prevHash = prev.BlockHash()
+		}
+
+		header := &wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  prevHash,
+			MerkleRoot: chainhash.Hash{branch},
+			Timestamp:  time.Unix(int64(height)*150, 0),
+		}
+		m.headers[height] = header
+		hashes[height] = header.BlockHash()
+	}
+
+	return hashes
+}
+
+// TestElectrumFilteredChainViewReorg tests that reorgs announced through a
+// single tip notification disconnect exactly the stale blocks, from the tip
+// downwards, and connect the new branch in order.
+func TestElectrumFilteredChainViewReorg(t *testing.T) {
+	t.Parallel()
+
+	mockClient := newMockElectrumClient()
+	chainA := mockClient.addTestBranch(0, 105, 0)
+
+	chainView, err := NewElectrumFilteredChainView(mockClient)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		mockClient.sendHeader(100)
+	}()
+
+	require.NoError(t, chainView.Start())
+	defer func() {
+		require.NoError(t, chainView.Stop())
+	}()
+
+	expectConnected := func(height uint32, hash chainhash.Hash) {
+		t.Helper()
+
+		select {
+		case block := <-chainView.FilteredBlocks():
+			require.Equal(t, height, block.Height)
+			require.Equal(t, hash, block.Hash)
+
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for block %d", height)
+		}
+	}
+
+	expectDisconnected := func(height uint32, hash chainhash.Hash) {
+		t.Helper()
+
+		select {
+		case block := <-chainView.DisconnectedBlocks():
+			require.Equal(t, height, block.Height)
+			require.Equal(t, hash, block.Hash)
+
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for disconnect of block %d",
+				height)
+		}
+	}
+
+	// expectRequests asserts that the fork point of the last notification
+	// was found with a single batch of headers.
+	requests := mockClient.numHeaderRequests()
+	expectRequests := func() {
+		t.Helper()
+
+		require.Equal(t, requests+1, mockClient.numHeaderRequests())
+		requests++
+	}
+
+	// A notification that skips blocks connects every missed block.
+	mockClient.sendHeader(105)
+	for height := uint32(101); height <= 105; height++ {
+		expectConnected(height, chainA[height])
+	}
+	expectRequests()
+
+	// Reorg out the last three blocks in favour of a longer branch that
+	// forks off at height 102.
+	chainB := mockClient.addTestBranch(102, 107, 1)
+	mockClient.sendHeader(107)
+
+	for height := uint32(105); height >= 103; height-- {
+		expectDisconnected(height, chainA[height])
+	}
+	for height := uint32(103); height <= 107; height++ {
+		expectConnected(height, chainB[height])
+	}
+	expectRequests()
+
+	// A reorg that replaces the tip with a block at the same height only
+	// swaps out that block.
+	chainC := mockClient.addTestBranch(106, 107, 2)
+	mockClient.sendHeader(107)
+
+	expectDisconnected(107, chainB[107])
+	expectConnected(107, chainC[107])
+	expectRequests()
+
+	// Repeating the current tip doesn't produce any events.
+	mockClient.sendHeader(107)
+	select {
+	case block := <-chainView.FilteredBlocks():
+		t.Fatalf("unexpected block %d", block.Height)
+
+	case block := <-chainView.DisconnectedBlocks():
+		t.Fatalf("unexpected disconnect of block %d", block.Height)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -112,6 +112,7 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 			TimeLockDelta: uint32(edge.TimeLockDelta),
 			MinHTLCOut:    edge.MinHTLC,
 			MaxHTLC:       edge.MaxHTLC,
+			InboundFee:    newSchema.InboundFee,
 		}
 
 		return nil
@@ -182,6 +183,17 @@ func (r *Manager) updateEdge(tx kvdb.RTx, chanPoint wire.OutPoint,
 	)
 	edge.TimeLockDelta = uint16(newSchema.TimeLockDelta)
 
+	// The inbound fee is carried in the extra opaque data of the channel
+	// update. A zero inbound fee isn't advertised at all.
+	var extraData lnwire.ExtraOpaqueData
+	if newSchema.InboundFee != (models.InboundFee{}) {
+		inboundFee := newSchema.InboundFee.ToWire()
+		if err := extraData.PackRecords(&inboundFee); err != nil {
+			return err
+		}
+	}
+	edge.ExtraOpaqueData = extraData
+
 	// Retrieve negotiated channel htlc amt limits.
 	amtMin, amtMax, err := r.getHtlcAmtLimits(tx, chanPoint)
 	if err != nil {
@@ -103,6 +103,10 @@ type finalHopParams struct {
 	// metadata is additional data that is sent along with the payment to
 	// the payee.
 	metadata []byte
+
+	// blindedPath is set if the payment is sent to a blinded path. The
+	// last hops of the route are then the hops of the blinded path.
+	blindedPath *sphinx.BlindedPath
 }
 
 // newRoute constructs a route using the provided path and final hop constraints.
@@ -229,7 +233,20 @@ func newRoute(sourceVertex route.Vertex,
 			// and its policy for the outgoing channel. This policy
 			// is stored as part of the incoming channel of
 			// the next hop.
-			fee = pathEdges[i+1].ComputeFee(amtToForward)
+			outboundFee := pathEdges[i+1].ComputeFee(amtToForward)
+
+			// The current hop may additionally charge an inbound
+			// fee for the channel over which it receives the htlc.
+			// A discount can at most cancel out the outbound fee.
+			inboundFee := edge.InboundFee.CalcFee(
+				amtToForward + outboundFee,
+			)
+			if inboundFee < -int64(outboundFee) {
+				inboundFee = -int64(outboundFee)
+			}
+			fee = lnwire.MilliSatoshi(
+				int64(outboundFee) + inboundFee,
+			)
 
 			// We'll take the total timelock of the preceding hop as
 			// the outgoing timelock or this hop. Then we'll
@@ -260,6 +277,15 @@ func newRoute(sourceVertex route.Vertex,
 		nextIncomingAmount = amtToForward + fee
 	}
 
+	// If we're paying to a blinded path, the hops of the path need the
+	// data that the recipient encrypted for them.
+	if finalHop.blindedPath != nil {
+		err := addBlindedPath(hops, finalHop.blindedPath, finalHop.totalAmt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// With the base routing data expressed as hops, build the full route
 	newRoute, err := route.NewRouteFromHops(
 		nextIncomingAmount, totalTimeLock, route.Vertex(sourceVertex),
@@ -272,6 +298,41 @@ func newRoute(sourceVertex route.Vertex,
 	return newRoute, nil
 }
 
+// addBlindedPath attaches the route blinding data of a blinded path to the
+// hops that make up its tail. The introduction node additionally receives the
+// blinding point, and the recipient the total amount of the payment.
+func addBlindedPath(hops []*route.Hop, blindedPath *sphinx.BlindedPath,
+	totalAmt lnwire.MilliSatoshi) error {
+
+	blindedHops := blindedPath.BlindedHops
+
+	// The introduction node is the first hop of the blinded path. If the
+	// route is shorter than the blinded path, we are the introduction
+	// node ourselves.
+	introIdx := len(hops) - len(blindedHops)
+	if introIdx < 0 {
+		return ErrSelfIntroduction
+	}
+
+	introHop := hops[introIdx]
+	introVertex := route.NewVertex(blindedPath.IntroductionPoint)
+	if introHop.PubKeyBytes != introVertex {
+		return fmt.Errorf("route does not reach introduction node "+
+			"%v of blinded path", introVertex)
+	}
+	introHop.BlindingPoint = blindedPath.BlindingPoint
+
+	for i, blindedHop := range blindedHops {
+		hop := hops[introIdx+i]
+		hop.EncryptedData = blindedHop.CipherText
+		hop.LegacyPayload = false
+	}
+
+	hops[len(hops)-1].TotalAmtMsat = totalAmt
+
+	return nil
+}
+
 // edgeWeight computes the weight of an edge. This value is used when searching
 // for the shortest path within the channel graph between two nodes. Weight is
 // is the fee itself plus a time lock penalty added to it. This benefits
@@ -642,9 +703,18 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 		edgesExpanded++
 
+		// Calculate the inbound fee that the to node charges for
+		// receiving over this edge. The final destination doesn't
+		// charge an inbound fee, which is already reflected in the
+		// edge by the unifier.
+		inboundFee := edge.calcInboundFee(
+			toNodeDist.amountToReceive, toNodeDist.outboundFee,
+		)
+
 		// Calculate amount that the candidate node would have to send
 		// out.
-		amountToSend := toNodeDist.amountToReceive
+		amountToSend := toNodeDist.amountToReceive +
+			lnwire.MilliSatoshi(inboundFee)
 
 		// Request the success probability for this edge.
 		edgeProbability := r.ProbabilitySource(
@@ -718,7 +788,18 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// weight composed of the fee that this node will charge and
 		// the amount that will be locked for timeLockDelta blocks in
 		// the HTLC that is handed out to fromVertex.
-		weight := edgeWeight(amountToReceive, fee, timeLockDelta)
+		// The inbound fee of the to node is accounted for here as well,
+		// because it only became known with the choice of this edge.
+		// Dijkstra doesn't support negative edge weights, so a net
+		// discount doesn't lower the weight below zero.
+		hopFee := int64(fee) + inboundFee
+		if hopFee < 0 {
+			hopFee = 0
+		}
+		weight := edgeWeight(
+			amountToReceive, lnwire.MilliSatoshi(hopFee),
+			timeLockDelta,
+		)
 
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
@@ -797,6 +878,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			weight:          tempWeight,
 			node:            fromVertex,
 			amountToReceive: amountToReceive,
+			outboundFee:     fee,
 			incomingCltv:    incomingCltv,
 			probability:     probability,
 			nextHop:         edge.policy,
@@ -861,7 +943,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		pivot := partialPath.node
 
 		// Create unified edges for all incoming connections.
-		u := newNodeEdgeUnifier(self, pivot, outgoingChanMap)
+		// Inbound fees are charged by all nodes except the final
+		// destination.
+		u := newNodeEdgeUnifier(
+			self, pivot, pivot != target, outgoingChanMap,
+		)
 
 		err := u.addGraphPolicies(g.graph)
 		if err != nil {
@@ -904,6 +990,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 			edge := edgeUnifier.getEdge(
 				amtToSend, g.bandwidthHints,
+				partialPath.outboundFee,
 			)
 
 			if edge == nil {
@@ -167,7 +167,7 @@ func makeTestGraph(t *testing.T, useCache bool) (*channeldb.ChannelGraph,
 	graph, err := channeldb.NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		useCache, false,
+		useCache, false, nil,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -436,6 +436,31 @@ type testChannelPolicy struct {
 	LastUpdate  time.Time
 	Disabled    bool
 	Features    *lnwire.FeatureVector
+
+	// InboundFeeBaseMsat and InboundFeeRate define the inbound fee that
+	// the node charges for htlcs that it receives over the channel.
+	InboundFeeBaseMsat int32
+	InboundFeeRate     int32
+}
+
+// extraOpaqueData returns the extra opaque data of the channel update that
+// corresponds to the test policy.
+func (p *testChannelPolicy) extraOpaqueData() ([]byte, error) {
+	if p.InboundFeeBaseMsat == 0 && p.InboundFeeRate == 0 {
+		return nil, nil
+	}
+
+	inboundFee := lnwire.Fee{
+		BaseFee: p.InboundFeeBaseMsat,
+		FeeRate: p.InboundFeeRate,
+	}
+
+	var extraData lnwire.ExtraOpaqueData
+	if err := extraData.PackRecords(&inboundFee); err != nil {
+		return nil, err
+	}
+
+	return extraData, nil
 }
 
 type testChannelEnd struct {
@@ -688,6 +713,11 @@ func createTestGraphFromChannels(t *testing.T, useCache bool,
 				node2Features = node2.Features
 			}
 
+			extraData, err := node1.extraOpaqueData()
+			if err != nil {
+				return nil, err
+			}
+
 			edgePolicy := &channeldb.ChannelEdgePolicy{
 				SigBytes:                  testSig.Serialize(),
 				MessageFlags:              msgFlags,
@@ -704,6 +734,7 @@ func createTestGraphFromChannels(t *testing.T, useCache bool,
 					PubKeyBytes: node2Vertex,
 					Features:    node2Features,
 				},
+				ExtraOpaqueData: extraData,
 			}
 			if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 				return nil, err
@@ -726,6 +757,11 @@ func createTestGraphFromChannels(t *testing.T, useCache bool,
 				node1Features = node1.Features
 			}
 
+			extraData, err := node2.extraOpaqueData()
+			if err != nil {
+				return nil, err
+			}
+
 			edgePolicy := &channeldb.ChannelEdgePolicy{
 				SigBytes:                  testSig.Serialize(),
 				MessageFlags:              msgFlags,
@@ -742,6 +778,7 @@ func createTestGraphFromChannels(t *testing.T, useCache bool,
 					PubKeyBytes: node1Vertex,
 					Features:    node1Features,
 				},
+				ExtraOpaqueData: extraData,
 			}
 			if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 				return nil, err
@@ -835,6 +872,9 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "with metadata",
 		fn:   runFindPathWithMetadata,
+	}, {
+		name: "inbound fees",
+		fn:   runInboundFees,
 	}}
 
 	// Run with graph cache enabled.
@@ -970,6 +1010,122 @@ func runFindLowestFeePath(t *testing.T, useCache bool) {
 	}
 }
 
+// runInboundFees tests that inbound fee discounts are taken into account when
+// selecting a path and building the route, and that a discount never results
+// in a negative fee for the node that offers it.
+func runInboundFees(t *testing.T, useCache bool) {
+	const (
+		startingHeight = 100
+		finalHopCLTV   = 1
+	)
+
+	testCases := []struct {
+		name             string
+		inboundFee       int32
+		expectedHop      string
+		expectedHopFee   lnwire.MilliSatoshi
+		expectedFeeTotal lnwire.MilliSatoshi
+	}{{
+		name:             "no discount",
+		expectedHop:      "b",
+		expectedHopFee:   6000,
+		expectedFeeTotal: 11000,
+	}, {
+		name:             "discount",
+		inboundFee:       -4000,
+		expectedHop:      "a",
+		expectedHopFee:   4000,
+		expectedFeeTotal: 9000,
+	}, {
+		name:             "discount capped",
+		inboundFee:       -10000,
+		expectedHop:      "a",
+		expectedHopFee:   0,
+		expectedFeeTotal: 5000,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Set up a test graph with two paths from hub to
+			// target. The path through a has higher outbound fees,
+			// but a offers a discount for htlcs that it receives
+			// from hub.
+			hubPolicy := &testChannelPolicy{
+				Expiry:      144,
+				FeeBaseMsat: 5000,
+				MinHTLC:     1,
+			}
+			aPolicy := &testChannelPolicy{
+				Expiry:             144,
+				MinHTLC:            1,
+				InboundFeeBaseMsat: tc.inboundFee,
+			}
+			testChannels := []*testChannel{
+				symmetricTestChannel(
+					"source", "hub", 100000,
+					&testChannelPolicy{
+						Expiry:  144,
+						MinHTLC: 1,
+					}, 1,
+				),
+				asymmetricTestChannel(
+					"hub", "a", 100000, hubPolicy,
+					aPolicy, 2,
+				),
+				symmetricTestChannel(
+					"hub", "b", 100000, hubPolicy, 3,
+				),
+				symmetricTestChannel(
+					"a", "target", 100000,
+					&testChannelPolicy{
+						Expiry:      144,
+						FeeBaseMsat: 8000,
+						MinHTLC:     1,
+					}, 4,
+				),
+				symmetricTestChannel(
+					"b", "target", 100000,
+					&testChannelPolicy{
+						Expiry:      144,
+						FeeBaseMsat: 6000,
+						MinHTLC:     1,
+					}, 5,
+				),
+			}
+
+			ctx := newPathFindingTestContext(
+				t, useCache, testChannels, "source",
+			)
+
+			paymentAmt := lnwire.NewMSatFromSatoshis(100)
+			target := ctx.keyFromAlias("target")
+			path, err := ctx.findPath(target, paymentAmt)
+			require.NoError(t, err, "unable to find path")
+
+			route, err := newRoute(
+				ctx.source, path, startingHeight,
+				finalHopParams{
+					amt:       paymentAmt,
+					cltvDelta: finalHopCLTV,
+				},
+			)
+			require.NoError(t, err, "unable to create route")
+
+			require.Equal(
+				t, ctx.keyFromAlias(tc.expectedHop),
+				route.Hops[1].PubKeyBytes,
+			)
+			require.Equal(t, tc.expectedHopFee, route.HopFee(1))
+			require.Equal(
+				t, paymentAmt+tc.expectedFeeTotal,
+				route.TotalAmount,
+			)
+		})
+	}
+}
+
 func getAliasFromPubKey(pubKey route.Vertex,
 	aliases map[string]route.Vertex) string {
 
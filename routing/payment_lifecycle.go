@@ -1,6 +1,7 @@
 package routing
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -14,10 +15,21 @@ import (
 	"github.com/ltcsuite/lnd/routing/route"
 	"github.com/ltcsuite/lnd/routing/shards"
 	"github.com/ltcsuite/ltcd/btcec/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// errShardHandlerExiting is returned from the shardHandler when it exits.
-var errShardHandlerExiting = fmt.Errorf("shard handler exiting")
+var (
+	// errShardHandlerExiting is returned from the shardHandler when it
+	// exits.
+	errShardHandlerExiting = fmt.Errorf("shard handler exiting")
+
+	// tracer records the spans of the payment lifecycle. Spans are only
+	// exported if tracing is enabled.
+	tracer = otel.Tracer("github.com/ltcsuite/lnd/routing")
+)
 
 // paymentLifecycle holds all information about the current state of a payment
 // needed to resume if from any point.
@@ -117,8 +129,12 @@ func (p *paymentLifecycle) fetchPaymentState() (*channeldb.MPPayment,
 	return payment, state, nil
 }
 
-// resumePayment resumes the paymentLifecycle from the current state.
-func (p *paymentLifecycle) resumePayment() ([32]byte, *route.Route, error) {
+// resumePayment resumes the paymentLifecycle from the current state. The spans
+// of path finding and of the shards are recorded as part of the trace carried
+// by the passed context.
+func (p *paymentLifecycle) resumePayment(
+	ctx context.Context) ([32]byte, *route.Route, error) {
+
 	shardHandler := &shardHandler{
 		router:       p.router,
 		identifier:   p.identifier,
@@ -147,7 +163,7 @@ func (p *paymentLifecycle) resumePayment() ([32]byte, *route.Route, error) {
 		log.Infof("Resuming payment shard %v for payment %v",
 			a.AttemptID, p.identifier)
 
-		shardHandler.collectResultAsync(&a.HTLCAttemptInfo)
+		shardHandler.collectResultAsync(ctx, &a.HTLCAttemptInfo)
 	}
 
 	// We'll continue until either our payment succeeds, or we encounter a
@@ -250,11 +266,7 @@ lifecycle:
 		}
 
 		// Create a new payment attempt from the given payment session.
-		rt, err := p.paySession.RequestRoute(
-			currentState.remainingAmt, currentState.remainingFees,
-			uint32(currentState.numShardsInFlight),
-			uint32(p.currentHeight),
-		)
+		rt, err := p.requestRoute(ctx, currentState)
 		if err != nil {
 			log.Warnf("Failed to find route for payment %v: %v",
 				p.identifier, err)
@@ -298,7 +310,9 @@ lifecycle:
 		lastShard := rt.ReceiverAmt() == currentState.remainingAmt
 
 		// We found a route to try, launch a new shard.
-		attempt, outcome, err := shardHandler.launchShard(rt, lastShard)
+		attempt, outcome, err := shardHandler.launchShard(
+			ctx, rt, lastShard,
+		)
 		switch {
 		// We may get a terminal error if we've processed a shard with
 		// a terminal state (settled or permanent failure), while we
@@ -338,10 +352,48 @@ lifecycle:
 
 		// Now that the shard was successfully sent, launch a go
 		// routine that will handle its result when its back.
-		shardHandler.collectResultAsync(attempt)
+		shardHandler.collectResultAsync(ctx, attempt)
 	}
 }
 
+// requestRoute finds a route for the remaining amount of the payment, recording
+// a span of the path finding.
+func (p *paymentLifecycle) requestRoute(ctx context.Context,
+	state *paymentState) (*route.Route, error) {
+
+	_, span := tracer.Start(
+		ctx, "routing.PaymentSession.RequestRoute",
+		trace.WithAttributes(
+			attribute.Int64(
+				"remaining_amt_msat", int64(state.remainingAmt),
+			),
+			attribute.Int64(
+				"remaining_fees_msat",
+				int64(state.remainingFees),
+			),
+			attribute.Int(
+				"active_shards", state.numShardsInFlight,
+			),
+		),
+	)
+	defer span.End()
+
+	rt, err := p.paySession.RequestRoute(
+		state.remainingAmt, state.remainingFees,
+		uint32(state.numShardsInFlight), uint32(p.currentHeight),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("num_hops", len(rt.Hops)))
+
+	return rt, nil
+}
+
 // shardHandler holds what is necessary to send and collect the result of
 // shards.
 type shardHandler struct {
@@ -426,18 +478,31 @@ type launchOutcome struct {
 // whether the attempt was successfully sent. If the launchOutcome wraps a
 // non-nil error, it means that the attempt was not sent onto the network, so
 // no result will be available in the future for it.
-func (p *shardHandler) launchShard(rt *route.Route,
+func (p *shardHandler) launchShard(ctx context.Context, rt *route.Route,
 	lastShard bool) (*channeldb.HTLCAttemptInfo, *launchOutcome, error) {
 
+	ctx, span := tracer.Start(ctx, "routing.shardHandler.launchShard")
+	defer span.End()
+
 	// Using the route received from the payment session, create a new
 	// shard to send.
 	firstHop, htlcAdd, attempt, err := p.createNewPaymentAttempt(
 		rt, lastShard,
 	)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
 		return nil, nil, err
 	}
 
+	span.SetAttributes(
+		attribute.Int64("attempt_id", int64(attempt.AttemptID)),
+		attribute.Int64("amt_msat", int64(rt.TotalAmount)),
+		attribute.Int64("fee_msat", int64(rt.TotalFees())),
+		attribute.String("first_hop", firstHop.String()),
+	)
+
 	// Before sending this HTLC to the switch, we checkpoint the fresh
 	// paymentID and route to the DB. This lets us know on startup the ID
 	// of the payment that we attempted to send, such that we can query the
@@ -445,13 +510,19 @@ func (p *shardHandler) launchShard(rt *route.Route,
 	// when it eventually comes back.
 	err = p.router.cfg.Control.RegisterAttempt(p.identifier, attempt)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
 		return nil, nil, err
 	}
 
 	// Now that the attempt is created and checkpointed to the DB, we send
 	// it.
-	sendErr := p.sendPaymentAttempt(attempt, firstHop, htlcAdd)
+	sendErr := p.sendPaymentAttempt(ctx, attempt, firstHop, htlcAdd)
 	if sendErr != nil {
+		span.RecordError(sendErr)
+		span.SetStatus(codes.Error, sendErr.Error())
+
 		// TODO(joostjager): Distinguish unexpected internal errors
 		// from real send errors.
 		htlcAttempt, err := p.failAttempt(attempt, sendErr)
@@ -481,7 +552,9 @@ type shardResult struct {
 // collectResultAsync launches a goroutine that will wait for the result of the
 // given HTLC attempt to be available then handle its result. It will fail the
 // payment with the control tower if a terminal error is encountered.
-func (p *shardHandler) collectResultAsync(attempt *channeldb.HTLCAttemptInfo) {
+func (p *shardHandler) collectResultAsync(ctx context.Context,
+	attempt *channeldb.HTLCAttemptInfo) {
+
 	// errToSend is the error to be sent to sh.shardErrors.
 	var errToSend error
 
@@ -504,7 +577,7 @@ func (p *shardHandler) collectResultAsync(attempt *channeldb.HTLCAttemptInfo) {
 		defer handleResultErr()
 
 		// Block until the result is available.
-		result, err := p.collectResult(attempt)
+		result, err := p.collectResult(ctx, attempt)
 		if err != nil {
 			if err != ErrRouterShuttingDown &&
 				err != htlcswitch.ErrSwitchExiting &&
@@ -536,7 +609,40 @@ func (p *shardHandler) collectResultAsync(attempt *channeldb.HTLCAttemptInfo) {
 // collectResult waits for the result for the given attempt to be available
 // from the Switch, then records the attempt outcome with the control tower. A
 // shardResult is returned, indicating the final outcome of this HTLC attempt.
-func (p *shardHandler) collectResult(attempt *channeldb.HTLCAttemptInfo) (
+// The wait for the result is recorded as a span that ends with the settle or
+// fail of the attempt.
+func (p *shardHandler) collectResult(ctx context.Context,
+	attempt *channeldb.HTLCAttemptInfo) (*shardResult, error) {
+
+	_, span := tracer.Start(
+		ctx, "routing.shardHandler.collectResult",
+		trace.WithAttributes(
+			attribute.Int64("attempt_id", int64(attempt.AttemptID)),
+		),
+	)
+	defer span.End()
+
+	result, err := p.waitForResult(attempt)
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+	case result.err != nil:
+		span.SetAttributes(attribute.String("outcome", "failed"))
+		span.RecordError(result.err)
+		span.SetStatus(codes.Error, result.err.Error())
+
+	default:
+		span.SetAttributes(attribute.String("outcome", "settled"))
+	}
+
+	return result, err
+}
+
+// waitForResult waits for the result for the given attempt to be available
+// from the Switch, then records the attempt outcome with the control tower.
+func (p *shardHandler) waitForResult(attempt *channeldb.HTLCAttemptInfo) (
 	*shardResult, error) {
 
 	// We'll retrieve the hash specific to this shard from the
@@ -733,7 +839,7 @@ func (p *shardHandler) createNewPaymentAttempt(rt *route.Route, lastShard bool)
 }
 
 // sendPaymentAttempt attempts to send the current attempt to the switch.
-func (p *shardHandler) sendPaymentAttempt(
+func (p *shardHandler) sendPaymentAttempt(ctx context.Context,
 	attempt *channeldb.HTLCAttemptInfo, firstHop lnwire.ShortChannelID,
 	htlcAdd *lnwire.UpdateAddHTLC) error {
 
@@ -749,7 +855,7 @@ func (p *shardHandler) sendPaymentAttempt(
 	// such that we can resume waiting for the result after a
 	// restart.
 	err := p.router.cfg.Payer.SendHTLC(
-		firstHop, attempt.AttemptID, htlcAdd,
+		ctx, firstHop, attempt.AttemptID, htlcAdd,
 	)
 	if err != nil {
 		log.Errorf("Failed sending attempt %d for payment "+
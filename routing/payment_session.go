@@ -1,6 +1,7 @@
 package routing
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/btcsuite/btclog"
@@ -164,6 +165,11 @@ type PaymentSession interface {
 type paymentSession struct {
 	additionalEdges map[route.Vertex][]*channeldb.CachedEdgePolicy
 
+	// blindedTargets are the blinded paths the payment can be sent to. If
+	// set, a path is searched for to each of them instead of to the
+	// payment's target.
+	blindedTargets []*blindedTarget
+
 	getBandwidthHints func(routingGraph) (bandwidthHints, error)
 
 	payment *LightningPayment
@@ -190,6 +196,14 @@ type paymentSession struct {
 	log btclog.Logger
 }
 
+// blindedTarget is a blinded path that a payment can be sent to, along with
+// the chain of additional edges that leads through it to the blinded
+// recipient.
+type blindedTarget struct {
+	payment *BlindedPayment
+	edges   map[route.Vertex][]*channeldb.CachedEdgePolicy
+}
+
 // newPaymentSession instantiates a new payment session.
 func newPaymentSession(p *LightningPayment,
 	getBandwidthHints func(routingGraph) (bandwidthHints, error),
@@ -202,10 +216,42 @@ func newPaymentSession(p *LightningPayment,
 		return nil, err
 	}
 
+	// If we're paying to blinded paths, each of them is added to the
+	// graph as a chain of additional edges leading to its blinded
+	// recipient when we search for a path to it.
+	var (
+		blindedTargets []*blindedTarget
+		targetFound    bool
+	)
+	for _, blindedPayment := range p.BlindedPayments {
+		if len(p.RouteHints) != 0 {
+			return nil, errors.New("cannot combine route hints " +
+				"with a blinded payment")
+		}
+
+		if err := blindedPayment.Validate(); err != nil {
+			return nil, err
+		}
+
+		if p.Target == blindedPayment.Target() {
+			targetFound = true
+		}
+
+		blindedTargets = append(blindedTargets, &blindedTarget{
+			payment: blindedPayment,
+			edges:   blindedPayment.toRouteHints(),
+		})
+	}
+	if len(blindedTargets) > 0 && !targetFound {
+		return nil, errors.New("payment target does not match " +
+			"blinded payment target")
+	}
+
 	logPrefix := fmt.Sprintf("PaymentSession(%x):", p.Identifier())
 
 	return &paymentSession{
 		additionalEdges:   edges,
+		blindedTargets:    blindedTargets,
 		getBandwidthHints: getBandwidthHints,
 		payment:           p,
 		pathFinder:        findPath,
@@ -235,8 +281,9 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 
 	// Add BlockPadding to the finalCltvDelta so that the receiving node
 	// does not reject the HTLC if some blocks are mined while it's in-flight.
-	finalCltvDelta := p.payment.FinalCLTVDelta
-	finalCltvDelta += BlockPadding
+	// Payments to blinded paths apply the final delta of the path that is
+	// found.
+	finalCltvDelta := p.payment.FinalCLTVDelta + BlockPadding
 
 	// We need to subtract the final delta before passing it into path
 	// finding. The optimal path is independent of the final cltv delta and
@@ -297,16 +344,27 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		sourceVertex := routingGraph.sourceNode()
 
 		// Find a route for the current amount.
-		path, _, err := p.pathFinder(
-			&graphParams{
-				additionalEdges: p.additionalEdges,
-				bandwidthHints:  bandwidthHints,
-				graph:           routingGraph,
-			},
-			restrictions, &p.pathFindingConfig,
-			sourceVertex, p.payment.Target,
-			maxAmt, p.payment.TimePref, finalHtlcExpiry,
+		var (
+			path           []*channeldb.CachedEdgePolicy
+			blindedPayment *BlindedPayment
 		)
+		if len(p.blindedTargets) > 0 {
+			path, blindedPayment, err = p.findBlindedPath(
+				routingGraph, bandwidthHints, restrictions,
+				sourceVertex, maxAmt, height,
+			)
+		} else {
+			path, _, err = p.pathFinder(
+				&graphParams{
+					additionalEdges: p.additionalEdges,
+					bandwidthHints:  bandwidthHints,
+					graph:           routingGraph,
+				},
+				restrictions, &p.pathFindingConfig,
+				sourceVertex, p.payment.Target,
+				maxAmt, p.payment.TimePref, finalHtlcExpiry,
+			)
+		}
 
 		// Close routing graph.
 		cleanup()
@@ -314,8 +372,11 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		switch {
 		case err == errNoPathFound:
 			// Don't split if this is a legacy payment without mpp
-			// record.
-			if p.payment.PaymentAddr == nil {
+			// record. Payments to blinded paths carry the total
+			// amount in the payload of the final hop instead.
+			if p.payment.PaymentAddr == nil &&
+				len(p.blindedTargets) == 0 {
+
 				p.log.Debugf("not splitting because payment " +
 					"address is unspecified")
 
@@ -381,17 +442,21 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		// With the next candidate path found, we'll attempt to turn
 		// this into a route by applying the time-lock and fee
 		// requirements.
-		route, err := newRoute(
-			sourceVertex, path, height,
-			finalHopParams{
-				amt:         maxAmt,
-				totalAmt:    p.payment.Amount,
-				cltvDelta:   finalCltvDelta,
-				records:     p.payment.DestCustomRecords,
-				paymentAddr: p.payment.PaymentAddr,
-				metadata:    p.payment.Metadata,
-			},
-		)
+		finalHop := finalHopParams{
+			amt:         maxAmt,
+			totalAmt:    p.payment.Amount,
+			cltvDelta:   finalCltvDelta,
+			records:     p.payment.DestCustomRecords,
+			paymentAddr: p.payment.PaymentAddr,
+			metadata:    p.payment.Metadata,
+		}
+		if blindedPayment != nil {
+			finalHop.cltvDelta = blindedPayment.FinalCltvDelta() +
+				BlockPadding
+			finalHop.blindedPath = blindedPayment.BlindedPath
+		}
+
+		route, err := newRoute(sourceVertex, path, height, finalHop)
 		if err != nil {
 			return nil, err
 		}
@@ -400,6 +465,68 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 	}
 }
 
+// findBlindedPath searches for a path to each of the blinded paths of the
+// payment and returns the one with the highest success probability, along
+// with the blinded path it leads through. As mission control penalizes the
+// introduction node of a blinded path that failed, later attempts move on to
+// the other blinded paths.
+func (p *paymentSession) findBlindedPath(routingGraph routingGraph,
+	bandwidthHints bandwidthHints, restrictions *RestrictParams,
+	source route.Vertex, amt lnwire.MilliSatoshi, height uint32) (
+	[]*channeldb.CachedEdgePolicy, *BlindedPayment, error) {
+
+	var (
+		bestPath        []*channeldb.CachedEdgePolicy
+		bestProbability float64
+		bestPayment     *BlindedPayment
+	)
+	for _, target := range p.blindedTargets {
+		// The final expiry delta differs between the blinded paths, so
+		// the cltv limit is adjusted for each of them.
+		finalCltvDelta := target.payment.FinalCltvDelta() +
+			BlockPadding
+		pathRestrictions := *restrictions
+		pathRestrictions.CltvLimit = p.payment.CltvLimit -
+			uint32(finalCltvDelta)
+		finalHtlcExpiry := int32(height) + int32(finalCltvDelta)
+
+		path, probability, err := p.pathFinder(
+			&graphParams{
+				additionalEdges: target.edges,
+				bandwidthHints:  bandwidthHints,
+				graph:           routingGraph,
+			},
+			&pathRestrictions, &p.pathFindingConfig,
+			source, target.payment.Target(),
+			amt, p.payment.TimePref, finalHtlcExpiry,
+		)
+		switch {
+		case err == errNoPathFound:
+			p.log.Debugf("no path found to blinded path with "+
+				"introduction node %v", route.NewVertex(
+				target.payment.BlindedPath.IntroductionPoint,
+			))
+
+			continue
+
+		case err != nil:
+			return nil, nil, err
+		}
+
+		if bestPath == nil || probability > bestProbability {
+			bestPath = path
+			bestProbability = probability
+			bestPayment = target.payment
+		}
+	}
+
+	if bestPath == nil {
+		return nil, nil, errNoPathFound
+	}
+
+	return bestPath, bestPayment, nil
+}
+
 // UpdateAdditionalEdge updates the channel edge policy for a private edge. It
 // validates the message signature and checks it's up to date, then applies the
 // updates to the supplied policy. It returns a boolean to indicate whether
@@ -249,6 +249,112 @@ func TestRequestRoute(t *testing.T) {
 	}
 }
 
+// TestRequestRouteBlindedPaths tests that a route is requested to each of the
+// blinded paths of a payment, and that the most probable one is used.
+func TestRequestRouteBlindedPaths(t *testing.T) {
+	t.Parallel()
+
+	const height = 10
+
+	// The first blinded path can't be reached, the second and third ones
+	// can with different success probabilities.
+	blindedPayments := []*BlindedPayment{
+		{
+			BlindedPath:     newTestBlindedPath(t, 1),
+			CltvExpiryDelta: 10,
+		},
+		{
+			BlindedPath:     newTestBlindedPath(t, 1),
+			CltvExpiryDelta: 20,
+		},
+		{
+			BlindedPath:     newTestBlindedPath(t, 1),
+			CltvExpiryDelta: 30,
+		},
+	}
+	probabilities := map[route.Vertex]float64{
+		blindedPayments[1].Target(): 0.5,
+		blindedPayments[2].Target(): 0.8,
+	}
+
+	payment := &LightningPayment{
+		Target:          blindedPayments[0].Target(),
+		CltvLimit:       100,
+		Amount:          1000,
+		FeeLimit:        1000,
+		BlindedPayments: blindedPayments,
+	}
+	require.NoError(t, payment.SetPaymentHash(lntypes.Hash{}))
+
+	session, err := newPaymentSession(
+		payment,
+		func(routingGraph) (bandwidthHints, error) {
+			return &mockBandwidthHints{}, nil
+		},
+		func() (routingGraph, func(), error) {
+			return &sessionGraph{}, func() {}, nil
+		},
+		&MissionControl{},
+		PathFindingConfig{},
+	)
+	require.NoError(t, err)
+
+	session.pathFinder = func(_ *graphParams, r *RestrictParams,
+		_ *PathFindingConfig, _, target route.Vertex,
+		_ lnwire.MilliSatoshi, _ float64, _ int32) (
+		[]*channeldb.CachedEdgePolicy, float64, error) {
+
+		probability, ok := probabilities[target]
+		if !ok {
+			return nil, 0, errNoPathFound
+		}
+
+		// The cltv limit excludes the final delta of the blinded path
+		// that is searched for.
+		var finalCltvDelta uint16
+		for _, blindedPayment := range blindedPayments {
+			if blindedPayment.Target() == target {
+				finalCltvDelta = blindedPayment.FinalCltvDelta()
+			}
+		}
+		require.Equal(
+			t, 100-uint32(finalCltvDelta+BlockPadding), r.CltvLimit,
+		)
+
+		path := []*channeldb.CachedEdgePolicy{
+			{
+				ToNodePubKey: func() route.Vertex {
+					return target
+				},
+				ToNodeFeatures: lnwire.NewFeatureVector(
+					nil, nil,
+				),
+			},
+		}
+
+		return path, probability, nil
+	}
+
+	route, err := session.RequestRoute(
+		payment.Amount, payment.FeeLimit, 0, height,
+	)
+	require.NoError(t, err)
+
+	// The route leads to the most probable blinded path, using its final
+	// delta.
+	require.Len(t, route.Hops, 1)
+	finalHop := route.Hops[0]
+	require.Equal(t, blindedPayments[2].Target(), finalHop.PubKeyBytes)
+	require.Equal(
+		t, blindedPayments[2].BlindedPath.BlindingPoint,
+		finalHop.BlindingPoint,
+	)
+	require.EqualValues(t, payment.Amount, finalHop.TotalAmtMsat)
+	require.EqualValues(
+		t, height+30+uint32(BlockPadding), route.TotalTimeLock,
+	)
+}
+
 type sessionGraph struct {
 	routingGraph
 }
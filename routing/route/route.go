@@ -131,6 +131,23 @@ type Hop struct {
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// EncryptedData is an encrypted data blob that is included for hops
+	// that are part of a blinded route.
+	EncryptedData []byte
+
+	// BlindingPoint is an ephemeral public key used by the introduction
+	// node of a blinded route to decrypt its encrypted data.
+	BlindingPoint *btcec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route.
+	// This field should only be set for the final hop of a blinded route.
+	TotalAmtMsat lnwire.MilliSatoshi
+}
+
+// IsBlinded returns true if the hop is part of a blinded route.
+func (h *Hop) IsBlinded() bool {
+	return h.EncryptedData != nil
 }
 
 // Copy returns a deep copy of the Hop.
@@ -169,23 +186,52 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 	// required routing fields, as well as these optional values.
 	var records []tlv.Record
 
-	// Every hop must have an amount to forward and CLTV expiry.
+	// Every hop must have an amount to forward and CLTV expiry, except
+	// for the intermediate hops of a blinded route that take them from
+	// their encrypted data.
 	amt := uint64(h.AmtToForward)
-	records = append(records,
-		record.NewAmtToFwdRecord(&amt),
-		record.NewLockTimeRecord(&h.OutgoingTimeLock),
-	)
+	if !h.IsBlinded() || h.TotalAmtMsat != 0 {
+		records = append(records,
+			record.NewAmtToFwdRecord(&amt),
+			record.NewLockTimeRecord(&h.OutgoingTimeLock),
+		)
+	}
 
 	// BOLT 04 says the next_hop_id should be omitted for the final hop,
-	// but present for all others.
+	// but present for all others. Hops of a blinded route find the next
+	// hop in their encrypted data instead.
 	//
 	// TODO(conner): test using hop.Exit once available
-	if nextChanID != 0 {
+	if nextChanID != 0 && !h.IsBlinded() {
 		records = append(records,
 			record.NewNextHopIDRecord(&nextChanID),
 		)
 	}
 
+	// Hops that are part of a blinded route carry the data that the
+	// recipient encrypted for them. The introduction node additionally
+	// needs the blinding point to decrypt it.
+	if h.IsBlinded() {
+		records = append(records,
+			record.NewEncryptedDataRecord(&h.EncryptedData),
+		)
+	}
+
+	if h.BlindingPoint != nil {
+		records = append(records,
+			record.NewBlindingPointRecord(&h.BlindingPoint),
+		)
+	}
+
+	// The final hop of a blinded route also includes the total amount
+	// of the payment.
+	if h.TotalAmtMsat != 0 {
+		totalAmt := uint64(h.TotalAmtMsat)
+		records = append(records,
+			record.NewTotalAmtMsatBlinded(&totalAmt),
+		)
+	}
+
 	// If an MPP record is destined for this hop, ensure that we only ever
 	// attach it to the final hop. Otherwise the route was constructed
 	// incorrectly.
@@ -246,20 +292,46 @@ func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
 			tlv.VarIntSize(length) + length
 	}
 
-	// Add amount size.
-	addRecord(record.AmtOnionType, tlv.SizeTUint64(uint64(h.AmtToForward)))
-
-	// Add lock time size.
-	addRecord(
-		record.LockTimeOnionType,
-		tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
-	)
+	// Add amount and lock time size, unless this is an intermediate hop
+	// of a blinded route.
+	if !h.IsBlinded() || h.TotalAmtMsat != 0 {
+		addRecord(
+			record.AmtOnionType,
+			tlv.SizeTUint64(uint64(h.AmtToForward)),
+		)
+		addRecord(
+			record.LockTimeOnionType,
+			tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
+		)
+	}
 
 	// Add next hop if present.
-	if nextChanID != 0 {
+	if nextChanID != 0 && !h.IsBlinded() {
 		addRecord(record.NextHopOnionType, 8)
 	}
 
+	// Add the route blinding records if present.
+	if h.IsBlinded() {
+		addRecord(
+			record.EncryptedDataOnionType,
+			uint64(len(h.EncryptedData)),
+		)
+	}
+
+	if h.BlindingPoint != nil {
+		addRecord(
+			record.BlindingPointOnionType,
+			btcec.PubKeyBytesLenCompressed,
+		)
+	}
+
+	if h.TotalAmtMsat != 0 {
+		addRecord(
+			record.TotalAmtMsatBlindedType,
+			tlv.SizeTUint64(uint64(h.TotalAmtMsat)),
+		)
+	}
+
 	// Add mpp if present.
 	if h.MPP != nil {
 		addRecord(record.MPPOnionType, h.MPP.PayloadSize())
@@ -8,6 +8,7 @@ import (
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/record"
 	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -214,3 +215,51 @@ func TestPayloadSize(t *testing.T) {
 		}
 	}
 }
+
+// TestBlindedPayloadSize tests the payload size calculation of hops that are
+// part of a blinded route.
+func TestBlindedPayloadSize(t *testing.T) {
+	hops := []*Hop{
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1000,
+			OutgoingTimeLock: 600000,
+			ChannelID:        3432483437438,
+		},
+		{
+			PubKeyBytes:   testPubKeyBytes,
+			ChannelID:     63584534844,
+			EncryptedData: []byte{1, 2, 3},
+			BlindingPoint: testPubKey,
+		},
+		{
+			PubKeyBytes:   testPubKeyBytes,
+			EncryptedData: []byte{4, 5, 6, 7},
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700000,
+			EncryptedData:    []byte{8, 9},
+			TotalAmtMsat:     1200,
+		},
+	}
+
+	rt := Route{
+		Hops: hops,
+	}
+	path, err := rt.ToSphinxPath()
+	require.NoError(t, err)
+
+	for i, onionHop := range path[:path.TrueRouteLength()] {
+		hop := hops[i]
+		var nextChan uint64
+		if i < len(hops)-1 {
+			nextChan = hops[i+1].ChannelID
+		}
+
+		expected := uint64(onionHop.HopPayload.NumBytes())
+		actual := hop.PayloadSize(nextChan)
+		require.Equal(t, expected, actual, "hop %v", i)
+	}
+}
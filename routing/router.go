@@ -2,6 +2,7 @@ package routing
 
 import (
 	"bytes"
+	"context"
 	goErrors "errors"
 	"fmt"
 	"math"
@@ -18,6 +19,7 @@ import (
 	"github.com/ltcsuite/lnd/batch"
 	"github.com/ltcsuite/lnd/chainntnfs"
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/clock"
 	"github.com/ltcsuite/lnd/htlcswitch"
 	"github.com/ltcsuite/lnd/input"
@@ -38,6 +40,9 @@ import (
 	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/wire"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -170,8 +175,9 @@ type PaymentAttemptDispatcher interface {
 	// SendHTLC is a function that directs a link-layer switch to
 	// forward a fully encoded payment to the first hop in the route
 	// denoted by its public key. A non-nil error is to be returned if the
-	// payment was unsuccessful.
-	SendHTLC(firstHop lnwire.ShortChannelID,
+	// payment was unsuccessful. The passed context carries the trace of
+	// the payment attempt, if any.
+	SendHTLC(ctx context.Context, firstHop lnwire.ShortChannelID,
 		attemptID uint64,
 		htlcAdd *lnwire.UpdateAddHTLC) error
 
@@ -250,6 +256,11 @@ type FeeSchema struct {
 	// the effective fee rate charged per mSAT will be: (amount *
 	// FeeRate/1,000,000).
 	FeeRate uint32
+
+	// InboundFee is the fee that is charged for payments that are received
+	// over the channel, in addition to the fee of the outgoing channel. It
+	// is typically negative to express a discount.
+	InboundFee models.InboundFee
 }
 
 // ChannelPolicy holds the parameters that determine the policy we enforce
@@ -671,8 +682,9 @@ func (r *ChannelRouter) Start() error {
 			// also set a zero fee limit, as no more routes should
 			// be tried.
 			_, _, err := r.sendPayment(
-				0, payment.Info.PaymentIdentifier, 0,
-				paySession, shardTracker,
+				context.Background(), 0,
+				payment.Info.PaymentIdentifier, 0, paySession,
+				shardTracker,
 			)
 			if err != nil {
 				log.Errorf("Resuming payment %v failed: %v.",
@@ -1842,6 +1854,48 @@ func (r *ChannelRouter) FindRoute(source, target route.Vertex,
 	routeHints map[route.Vertex][]*channeldb.CachedEdgePolicy,
 	finalExpiry uint16) (*route.Route, float64, error) {
 
+	return r.findRoute(
+		source, target, amt, timePref, restrictions, routeHints,
+		finalHopParams{
+			amt:       amt,
+			totalAmt:  amt,
+			cltvDelta: finalExpiry,
+			records:   destCustomRecords,
+		},
+	)
+}
+
+// FindBlindedRoute attempts to query the ChannelRouter for the optimum path
+// to the recipient of a blinded payment, through the blinded path it provided.
+func (r *ChannelRouter) FindBlindedRoute(source route.Vertex,
+	amt lnwire.MilliSatoshi, timePref float64, restrictions *RestrictParams,
+	destCustomRecords record.CustomSet,
+	blindedPayment *BlindedPayment) (*route.Route, float64, error) {
+
+	if err := blindedPayment.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	return r.findRoute(
+		source, blindedPayment.Target(), amt, timePref, restrictions,
+		blindedPayment.toRouteHints(),
+		finalHopParams{
+			amt:         amt,
+			totalAmt:    amt,
+			cltvDelta:   blindedPayment.FinalCltvDelta(),
+			records:     destCustomRecords,
+			blindedPath: blindedPayment.BlindedPath,
+		},
+	)
+}
+
+// findRoute finds the optimum path to the given target and turns it into a
+// route using the passed final hop parameters.
+func (r *ChannelRouter) findRoute(source, target route.Vertex,
+	amt lnwire.MilliSatoshi, timePref float64, restrictions *RestrictParams,
+	routeHints map[route.Vertex][]*channeldb.CachedEdgePolicy,
+	finalHop finalHopParams) (*route.Route, float64, error) {
+
 	log.Debugf("Searching for path to %v, sending %v", target, amt)
 
 	// We'll attempt to obtain a set of bandwidth hints that can help us
@@ -1862,7 +1916,7 @@ func (r *ChannelRouter) FindRoute(source, target route.Vertex,
 
 	// Now that we know the destination is reachable within the graph, we'll
 	// execute our path finding algorithm.
-	finalHtlcExpiry := currentHeight + int32(finalExpiry)
+	finalHtlcExpiry := currentHeight + int32(finalHop.cltvDelta)
 
 	// Validate time preference.
 	if timePref < -1 || timePref > 1 {
@@ -1884,15 +1938,7 @@ func (r *ChannelRouter) FindRoute(source, target route.Vertex,
 	}
 
 	// Create the route with absolute time lock values.
-	route, err := newRoute(
-		source, path, uint32(currentHeight),
-		finalHopParams{
-			amt:       amt,
-			totalAmt:  amt,
-			cltvDelta: finalExpiry,
-			records:   destCustomRecords,
-		},
-	)
+	route, err := newRoute(source, path, uint32(currentHeight), finalHop)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2079,6 +2125,19 @@ type LightningPayment struct {
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// BlindedPayments is set if the payment is sent to blinded paths. Path
+	// finding then searches for a route to each of them and uses the one
+	// with the highest success probability, so that the payment moves on
+	// to the other paths if one of them fails. The target of the payment
+	// must be the target of one of the blinded paths, and no route hints
+	// can be provided.
+	BlindedPayments []*BlindedPayment
+
+	// SpanContext is the tracing span of the request that initiated the
+	// payment. If it is valid, the spans recorded over the lifecycle of
+	// the payment become part of its trace.
+	SpanContext trace.SpanContext
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -2142,7 +2201,7 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte,
 	// Since this is the first time this payment is being made, we pass nil
 	// for the existing attempt.
 	return r.sendPayment(
-		payment.FeeLimit, payment.Identifier(),
+		payment.traceContext(), payment.FeeLimit, payment.Identifier(),
 		payment.PayAttemptTimeout, paySession, shardTracker,
 	)
 }
@@ -2162,8 +2221,9 @@ func (r *ChannelRouter) SendPaymentAsync(payment *LightningPayment,
 			spewPayment(payment))
 
 		_, _, err := r.sendPayment(
-			payment.FeeLimit, payment.Identifier(),
-			payment.PayAttemptTimeout, ps, st,
+			payment.traceContext(), payment.FeeLimit,
+			payment.Identifier(), payment.PayAttemptTimeout, ps,
+			st,
 		)
 		if err != nil {
 			log.Errorf("Payment %x failed: %v",
@@ -2174,6 +2234,14 @@ func (r *ChannelRouter) SendPaymentAsync(payment *LightningPayment,
 	return nil
 }
 
+// traceContext returns a context that carries the span of the request that
+// initiated the payment, if any.
+func (l *LightningPayment) traceContext() context.Context {
+	return trace.ContextWithSpanContext(
+		context.Background(), l.SpanContext,
+	)
+}
+
 // spewPayment returns a log closures that provides a spewed string
 // representation of the passed payment.
 func spewPayment(payment *LightningPayment) logClosure {
@@ -2247,28 +2315,56 @@ func (r *ChannelRouter) PreparePayment(payment *LightningPayment) (
 }
 
 // SendToRoute sends a payment using the provided route and fails the payment
-// when an error is returned from the attempt.
-func (r *ChannelRouter) SendToRoute(htlcHash lntypes.Hash,
-	rt *route.Route) (*channeldb.HTLCAttempt, error) {
+// when an error is returned from the attempt. The spans of the payment are
+// recorded as children of the given span context, if it is valid.
+func (r *ChannelRouter) SendToRoute(spanContext trace.SpanContext,
+	htlcHash lntypes.Hash, rt *route.Route) (*channeldb.HTLCAttempt, error) {
 
-	return r.sendToRoute(htlcHash, rt, false)
+	return r.sendToRoute(spanContext, htlcHash, rt, false)
 }
 
 // SendToRouteSkipTempErr sends a payment using the provided route and fails
-// the payment ONLY when a terminal error is returned from the attempt.
-func (r *ChannelRouter) SendToRouteSkipTempErr(htlcHash lntypes.Hash,
-	rt *route.Route) (*channeldb.HTLCAttempt, error) {
+// the payment ONLY when a terminal error is returned from the attempt. The
+// spans of the payment are recorded as children of the given span context, if
+// it is valid.
+func (r *ChannelRouter) SendToRouteSkipTempErr(spanContext trace.SpanContext,
+	htlcHash lntypes.Hash, rt *route.Route) (*channeldb.HTLCAttempt, error) {
+
+	return r.sendToRoute(spanContext, htlcHash, rt, true)
+}
+
+// sendToRoute records a span for sending a payment with the given hash through
+// the provided route, and dispatches the payment as part of it.
+func (r *ChannelRouter) sendToRoute(spanContext trace.SpanContext,
+	htlcHash lntypes.Hash, rt *route.Route,
+	skipTempErr bool) (*channeldb.HTLCAttempt, error) {
+
+	ctx, span := tracer.Start(
+		trace.ContextWithSpanContext(context.Background(), spanContext),
+		"routing.ChannelRouter.sendToRoute",
+		trace.WithAttributes(
+			attribute.String("payment_hash", htlcHash.String()),
+		),
+	)
+	defer span.End()
 
-	return r.sendToRoute(htlcHash, rt, true)
+	attempt, err := r.dispatchToRoute(ctx, htlcHash, rt, skipTempErr)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return attempt, err
 }
 
-// sendToRoute attempts to send a payment with the given hash through the
+// dispatchToRoute attempts to send a payment with the given hash through the
 // provided route. This function is blocking and will return the attempt
 // information as it is stored in the database. For a successful htlc, this
 // information will contain the preimage. If an error occurs after the attempt
 // was initiated, both return values will be non-nil. If skipTempErr is true,
 // the payment won't be failed unless a terminal error has occurred.
-func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
+func (r *ChannelRouter) dispatchToRoute(ctx context.Context,
+	htlcHash lntypes.Hash, rt *route.Route,
 	skipTempErr bool) (*channeldb.HTLCAttempt, error) {
 
 	// Calculate amount paid to receiver.
@@ -2334,7 +2430,7 @@ func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 	}
 
 	var shardError error
-	attempt, outcome, err := sh.launchShard(rt, false)
+	attempt, outcome, err := sh.launchShard(ctx, rt, false)
 
 	// With SendToRoute, it can happen that the route exceeds protocol
 	// constraints. Mark the payment as failed with an internal error.
@@ -2366,7 +2462,7 @@ func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 
 	// Shard successfully launched, wait for the result to be available.
 	default:
-		result, err := sh.collectResult(attempt)
+		result, err := sh.collectResult(ctx, attempt)
 		if err != nil {
 			return nil, err
 		}
@@ -2426,11 +2522,19 @@ func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 // carry out its execution. After restarts it is safe, and assumed, that the
 // router will call this method for every payment still in-flight according to
 // the ControlTower.
-func (r *ChannelRouter) sendPayment(feeLimit lnwire.MilliSatoshi,
-	identifier lntypes.Hash, timeout time.Duration,
-	paySession PaymentSession,
+func (r *ChannelRouter) sendPayment(ctx context.Context,
+	feeLimit lnwire.MilliSatoshi, identifier lntypes.Hash,
+	timeout time.Duration, paySession PaymentSession,
 	shardTracker shards.ShardTracker) ([32]byte, *route.Route, error) {
 
+	ctx, span := tracer.Start(
+		ctx, "routing.ChannelRouter.sendPayment",
+		trace.WithAttributes(
+			attribute.String("payment_hash", identifier.String()),
+		),
+	)
+	defer span.End()
+
 	// We'll also fetch the current block height so we can properly
 	// calculate the required HTLC time locks within the route.
 	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
@@ -2456,8 +2560,13 @@ func (r *ChannelRouter) sendPayment(feeLimit lnwire.MilliSatoshi,
 		p.timeoutChan = time.After(timeout)
 	}
 
-	return p.resumePayment()
+	preimage, rt, err := p.resumePayment(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
 
+	return preimage, rt, err
 }
 
 // extractChannelUpdate examines the error and extracts the channel update.
@@ -2898,6 +3007,10 @@ func getRouteUnifiers(source route.Vertex, hops []route.Vertex,
 	// Allocate a list that will contain the edge unifiers for this route.
 	unifiers := make([]*edgeUnifier, len(hops))
 
+	// nextOutFee is the outbound fee that the to node of the current hop
+	// charges for forwarding to the next hop.
+	var nextOutFee lnwire.MilliSatoshi
+
 	// Traverse hops backwards to accumulate fees in the running amounts.
 	for i := len(hops) - 1; i >= 0; i-- {
 		toNode := hops[i]
@@ -2912,8 +3025,12 @@ func getRouteUnifiers(source route.Vertex, hops []route.Vertex,
 		localChan := i == 0
 
 		// Build unified edges for this hop based on the channels known
-		// in the graph.
-		u := newNodeEdgeUnifier(source, toNode, outgoingChans)
+		// in the graph. Inbound fees are charged by all nodes except
+		// the final destination.
+		useInboundFees := i < len(hops)-1
+		u := newNodeEdgeUnifier(
+			source, toNode, useInboundFees, outgoingChans,
+		)
 
 		err := u.addGraphPolicies(graph)
 		if err != nil {
@@ -2939,7 +3056,9 @@ func getRouteUnifiers(source route.Vertex, hops []route.Vertex,
 		}
 
 		// Get an edge for the specific amount that we want to forward.
-		edge := edgeUnifier.getEdge(runningAmt, bandwidthHints)
+		edge := edgeUnifier.getEdge(
+			runningAmt, bandwidthHints, nextOutFee,
+		)
 		if edge == nil {
 			log.Errorf("Cannot find policy with amt=%v for node %v",
 				runningAmt, fromNode)
@@ -2950,9 +3069,15 @@ func getRouteUnifiers(source route.Vertex, hops []route.Vertex,
 			}
 		}
 
-		// Add fee for this hop.
+		// Add the inbound fee of the to node and the outbound fee of
+		// the from node for this hop.
+		inboundFee := edge.calcInboundFee(runningAmt, nextOutFee)
+		runningAmt += lnwire.MilliSatoshi(inboundFee)
+
+		nextOutFee = 0
 		if !localChan {
-			runningAmt += edge.policy.ComputeFee(runningAmt)
+			nextOutFee = edge.policy.ComputeFee(runningAmt)
+			runningAmt += nextOutFee
 		}
 
 		log.Tracef("Select channel %v at position %v",
@@ -2977,7 +3102,10 @@ func getPathEdges(source route.Vertex, receiverAmt lnwire.MilliSatoshi,
 	// amount ranges re-checked.
 	var pathEdges []*channeldb.CachedEdgePolicy
 	for i, unifier := range unifiers {
-		edge := unifier.getEdge(receiverAmt, bandwidthHints)
+		// The amount already includes the inbound fee of the to node.
+		// Passing a zero outbound fee caps any inbound discount at
+		// zero, so that it doesn't distort the amount range checks.
+		edge := unifier.getEdge(receiverAmt, bandwidthHints, 0)
 		if edge == nil {
 			fromNode := source
 			if i > 0 {
@@ -2991,10 +3119,19 @@ func getPathEdges(source route.Vertex, receiverAmt lnwire.MilliSatoshi,
 		}
 
 		if i > 0 {
-			// Decrease the amount to send while going forward.
-			receiverAmt -= edge.policy.ComputeFeeFromIncoming(
+			// Decrease the amount to send while going forward. The
+			// forwarding node deducts its inbound fee for the
+			// incoming channel and its outbound fee for this one,
+			// but never a negative fee in total.
+			outboundFee := edge.policy.ComputeFeeFromIncoming(
 				receiverAmt,
 			)
+			inboundFee := pathEdges[i-1].InboundFee.CalcFee(
+				receiverAmt,
+			)
+			if fee := int64(outboundFee) + inboundFee; fee > 0 {
+				receiverAmt -= lnwire.MilliSatoshi(fee)
+			}
 		}
 
 		pathEdges = append(pathEdges, edge.policy)
@@ -29,6 +29,7 @@ import (
 	"github.com/ltcsuite/ltcd/wire"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var uniquePaymentID uint64 = 1 // to be used atomically
@@ -537,7 +538,7 @@ func TestChannelUpdateValidation(t *testing.T) {
 	// Send off the payment request to the router. The specified route
 	// should be attempted and the channel update should be received by
 	// router and ignored because it is missing a valid signature.
-	_, err = ctx.router.SendToRoute(payment, rt)
+	_, err = ctx.router.SendToRoute(trace.SpanContext{}, payment, rt)
 	require.Error(t, err, "expected route to fail with channel update")
 
 	_, e1, e2, err = ctx.router.GetChannelByID(
@@ -554,7 +555,7 @@ func TestChannelUpdateValidation(t *testing.T) {
 	signErrChanUpdate(t, testGraph.privKeyMap["b"], &errChanUpdate)
 
 	// Retry the payment using the same route as before.
-	_, err = ctx.router.SendToRoute(payment, rt)
+	_, err = ctx.router.SendToRoute(trace.SpanContext{}, payment, rt)
 	require.Error(t, err, "expected route to fail with channel update")
 
 	// This time a valid signature was supplied and the policy change should
@@ -2944,7 +2945,9 @@ func TestSendToRouteStructuredError(t *testing.T) {
 			// update should be received by router and ignored
 			// because it is missing a valid
 			// signature.
-			_, err = ctx.router.SendToRoute(payment, rt)
+			_, err = ctx.router.SendToRoute(
+				trace.SpanContext{}, payment, rt,
+			)
 
 			fErr, ok := err.(*htlcswitch.ForwardingError)
 			require.True(
@@ -3025,7 +3028,7 @@ func TestSendToRouteMaxHops(t *testing.T) {
 	// Send off the payment request to the router. We expect an error back
 	// indicating that the route is too long.
 	var payment lntypes.Hash
-	_, err = ctx.router.SendToRoute(payment, rt)
+	_, err = ctx.router.SendToRoute(trace.SpanContext{}, payment, rt)
 	if err != route.ErrMaxRouteHopsExceeded {
 		t.Fatalf("expected ErrMaxRouteHopsExceeded, but got %v", err)
 	}
@@ -4328,7 +4331,9 @@ func TestSendToRouteSkipTempErrSuccess(t *testing.T) {
 	).Return(nil)
 
 	// Expect a successful send to route.
-	attempt, err := router.SendToRouteSkipTempErr(payHash, rt)
+	attempt, err := router.SendToRouteSkipTempErr(
+		trace.SpanContext{}, payHash, rt,
+	)
 	require.NoError(t, err)
 	require.Equal(t, testAttempt, attempt)
 
@@ -4414,7 +4419,9 @@ func TestSendToRouteSkipTempErrTempFailure(t *testing.T) {
 	).Return(nil, nil)
 
 	// Expect a failed send to route.
-	attempt, err := router.SendToRouteSkipTempErr(payHash, rt)
+	attempt, err := router.SendToRouteSkipTempErr(
+		trace.SpanContext{}, payHash, rt,
+	)
 	require.Equal(t, tempErr, err)
 	require.Equal(t, testAttempt, attempt)
 
@@ -4503,7 +4510,9 @@ func TestSendToRouteSkipTempErrPermanentFailure(t *testing.T) {
 	).Return(&failureReason, nil)
 
 	// Expect a failed send to route.
-	attempt, err := router.SendToRouteSkipTempErr(payHash, rt)
+	attempt, err := router.SendToRouteSkipTempErr(
+		trace.SpanContext{}, payHash, rt,
+	)
 	require.Equal(t, permErr, err)
 	require.Equal(t, testAttempt, attempt)
 
@@ -4592,7 +4601,7 @@ func TestSendToRouteTempFailure(t *testing.T) {
 	).Return(nil, nil)
 
 	// Expect a failed send to route.
-	attempt, err := router.SendToRoute(payHash, rt)
+	attempt, err := router.SendToRoute(trace.SpanContext{}, payHash, rt)
 	require.Equal(t, tempErr, err)
 	require.Equal(t, testAttempt, attempt)
 
@@ -2,6 +2,7 @@ package routing
 
 import (
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/routing/route"
@@ -20,6 +21,10 @@ type nodeEdgeUnifier struct {
 	// toNode is the node for which the edge unifiers are instantiated.
 	toNode route.Vertex
 
+	// useInboundFees indicates whether to take inbound fees into account.
+	// Inbound fees are not charged by the final destination of a payment.
+	useInboundFees bool
+
 	// outChanRestr is an optional outgoing channel restriction for the
 	// local channel to use.
 	outChanRestr map[uint64]struct{}
@@ -27,14 +32,15 @@ type nodeEdgeUnifier struct {
 
 // newNodeEdgeUnifier instantiates a new nodeEdgeUnifier object. Channel
 // policies can be added to this object.
-func newNodeEdgeUnifier(sourceNode, toNode route.Vertex,
+func newNodeEdgeUnifier(sourceNode, toNode route.Vertex, useInboundFees bool,
 	outChanRestr map[uint64]struct{}) *nodeEdgeUnifier {
 
 	return &nodeEdgeUnifier{
-		edgeUnifiers: make(map[route.Vertex]*edgeUnifier),
-		toNode:       toNode,
-		sourceNode:   sourceNode,
-		outChanRestr: outChanRestr,
+		edgeUnifiers:   make(map[route.Vertex]*edgeUnifier),
+		toNode:         toNode,
+		useInboundFees: useInboundFees,
+		sourceNode:     sourceNode,
+		outChanRestr:   outChanRestr,
 	}
 }
 
@@ -61,9 +67,17 @@ func (u *nodeEdgeUnifier) addPolicy(fromNode route.Vertex,
 		u.edgeUnifiers[fromNode] = unifier
 	}
 
+	// The inbound fee is charged by the to node. Hop hints don't carry an
+	// inbound fee, so it is zero for them.
+	inboundFee := edge.InboundFee
+	if !u.useInboundFees {
+		inboundFee = models.InboundFee{}
+	}
+
 	unifier.edges = append(unifier.edges, &unifiedEdge{
-		policy:   edge,
-		capacity: capacity,
+		policy:     edge,
+		capacity:   capacity,
+		inboundFee: inboundFee,
 	})
 }
 
@@ -95,6 +109,25 @@ func (u *nodeEdgeUnifier) addGraphPolicies(g routingGraph) error {
 type unifiedEdge struct {
 	policy   *channeldb.CachedEdgePolicy
 	capacity ltcutil.Amount
+
+	// inboundFee is the inbound fee that the to node charges for htlcs
+	// that it receives over this channel.
+	inboundFee models.InboundFee
+}
+
+// calcInboundFee returns the inbound fee that the to node charges for
+// receiving the given net amount over this edge. The net amount is what the to
+// node forwards plus its outbound fee nextOutFee. A discount is capped so that
+// the total fee of the to node never becomes negative.
+func (u *unifiedEdge) calcInboundFee(netAmtReceived,
+	nextOutFee lnwire.MilliSatoshi) int64 {
+
+	inboundFee := u.inboundFee.CalcFee(netAmtReceived)
+	if inboundFee < -int64(nextOutFee) {
+		inboundFee = -int64(nextOutFee)
+	}
+
+	return inboundFee
 }
 
 // amtInRange checks whether an amount falls within the valid range for a
@@ -136,22 +169,28 @@ type edgeUnifier struct {
 }
 
 // getEdge returns the optimal unified edge to use for this connection given a
-// specific amount to send. It differentiates between local and network
-// channels.
-func (u *edgeUnifier) getEdge(amt lnwire.MilliSatoshi,
-	bandwidthHints bandwidthHints) *unifiedEdge {
+// specific net amount that the to node needs to receive. The amount sent over
+// the edge additionally includes the inbound fee of the to node, which is
+// capped by the to node's outbound fee nextOutFee. It differentiates between
+// local and network channels.
+func (u *edgeUnifier) getEdge(netAmtReceived lnwire.MilliSatoshi,
+	bandwidthHints bandwidthHints,
+	nextOutFee lnwire.MilliSatoshi) *unifiedEdge {
 
 	if u.localChan {
-		return u.getEdgeLocal(amt, bandwidthHints)
+		return u.getEdgeLocal(
+			netAmtReceived, bandwidthHints, nextOutFee,
+		)
 	}
 
-	return u.getEdgeNetwork(amt)
+	return u.getEdgeNetwork(netAmtReceived, nextOutFee)
 }
 
 // getEdgeLocal returns the optimal unified edge to use for this local
-// connection given a specific amount to send.
-func (u *edgeUnifier) getEdgeLocal(amt lnwire.MilliSatoshi,
-	bandwidthHints bandwidthHints) *unifiedEdge {
+// connection given a specific net amount that the to node needs to receive.
+func (u *edgeUnifier) getEdgeLocal(netAmtReceived lnwire.MilliSatoshi,
+	bandwidthHints bandwidthHints,
+	nextOutFee lnwire.MilliSatoshi) *unifiedEdge {
 
 	var (
 		bestEdge     *unifiedEdge
@@ -159,6 +198,12 @@ func (u *edgeUnifier) getEdgeLocal(amt lnwire.MilliSatoshi,
 	)
 
 	for _, edge := range u.edges {
+		// Calculate the amount that is sent over this channel,
+		// including the inbound fee that our peer charges.
+		amt := netAmtReceived + lnwire.MilliSatoshi(
+			edge.calcInboundFee(netAmtReceived, nextOutFee),
+		)
+
 		// Check valid amount range for the channel.
 		if !edge.amtInRange(amt) {
 			log.Debugf("Amount %v not in range for edge %v",
@@ -219,8 +264,9 @@ func (u *edgeUnifier) getEdgeLocal(amt lnwire.MilliSatoshi,
 
 		// Update best edge.
 		bestEdge = &unifiedEdge{
-			policy:   edge.policy,
-			capacity: edge.capacity,
+			policy:     edge.policy,
+			capacity:   edge.capacity,
+			inboundFee: edge.inboundFee,
 		}
 	}
 
@@ -228,18 +274,25 @@ func (u *edgeUnifier) getEdgeLocal(amt lnwire.MilliSatoshi,
 }
 
 // getEdgeNetwork returns the optimal unified edge to use for this connection
-// given a specific amount to send. The goal is to return a unified edge with a
-// policy that maximizes the probability of a successful forward in a non-strict
-// forwarding context.
-func (u *edgeUnifier) getEdgeNetwork(amt lnwire.MilliSatoshi) *unifiedEdge {
+// given a specific net amount that the to node needs to receive. The goal is to
+// return a unified edge with a policy that maximizes the probability of a
+// successful forward in a non-strict forwarding context.
+func (u *edgeUnifier) getEdgeNetwork(netAmtReceived,
+	nextOutFee lnwire.MilliSatoshi) *unifiedEdge {
+
 	var (
-		bestPolicy  *channeldb.CachedEdgePolicy
-		maxFee      lnwire.MilliSatoshi
+		bestEdge    *unifiedEdge
+		maxFee      int64
 		maxTimelock uint16
 		maxCapMsat  lnwire.MilliSatoshi
 	)
 
 	for _, edge := range u.edges {
+		// Calculate the amount that is sent over this channel,
+		// including the inbound fee that the to node charges.
+		inboundFee := edge.calcInboundFee(netAmtReceived, nextOutFee)
+		amt := netAmtReceived + lnwire.MilliSatoshi(inboundFee)
+
 		// Check valid amount range for the channel.
 		if !edge.amtInRange(amt) {
 			log.Debugf("Amount %v not in range for edge %v",
@@ -275,9 +328,10 @@ func (u *edgeUnifier) getEdgeNetwork(amt lnwire.MilliSatoshi) *unifiedEdge {
 		)
 
 		// Use the policy that results in the highest fee for this
-		// specific amount.
-		fee := edge.policy.ComputeFee(amt)
-		if fee < maxFee {
+		// specific amount. Both the outbound fee of the from node and
+		// the inbound fee of the to node are taken into account.
+		fee := int64(edge.policy.ComputeFee(amt)) + inboundFee
+		if bestEdge != nil && fee < maxFee {
 			log.Debugf("Skipped edge %v due to it produces less "+
 				"fee: fee=%v, maxFee=%v",
 				edge.policy.ChannelID, fee, maxFee)
@@ -285,11 +339,11 @@ func (u *edgeUnifier) getEdgeNetwork(amt lnwire.MilliSatoshi) *unifiedEdge {
 		}
 		maxFee = fee
 
-		bestPolicy = edge.policy
+		bestEdge = edge
 	}
 
 	// Return early if no channel matches.
-	if bestPolicy == nil {
+	if bestEdge == nil {
 		return nil
 	}
 
@@ -303,8 +357,11 @@ func (u *edgeUnifier) getEdgeNetwork(amt lnwire.MilliSatoshi) *unifiedEdge {
 	// get forwarded. Because we penalize pair-wise, there won't be a second
 	// chance for this node pair. But this is all only needed for nodes that
 	// have distinct policies for channels to the same peer.
-	policyCopy := *bestPolicy
-	modifiedEdge := unifiedEdge{policy: &policyCopy}
+	policyCopy := *bestEdge.policy
+	modifiedEdge := unifiedEdge{
+		policy:     &policyCopy,
+		inboundFee: bestEdge.inboundFee,
+	}
 	modifiedEdge.policy.TimeLockDelta = maxTimelock
 	modifiedEdge.capacity = maxCapMsat.ToSatoshis()
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/routing/route"
 	"github.com/ltcsuite/ltcd/ltcutil"
@@ -40,21 +41,35 @@ func TestNodeEdgeUnifier(t *testing.T) {
 	c1 := ltcutil.Amount(7)
 	c2 := ltcutil.Amount(8)
 
-	unifierFilled := newNodeEdgeUnifier(source, toNode, nil)
+	unifierFilled := newNodeEdgeUnifier(source, toNode, false, nil)
 	unifierFilled.addPolicy(fromNode, &p1, c1)
 	unifierFilled.addPolicy(fromNode, &p2, c2)
 
-	unifierNoCapacity := newNodeEdgeUnifier(source, toNode, nil)
+	unifierNoCapacity := newNodeEdgeUnifier(source, toNode, false, nil)
 	unifierNoCapacity.addPolicy(fromNode, &p1, 0)
 	unifierNoCapacity.addPolicy(fromNode, &p2, 0)
 
-	unifierNoInfo := newNodeEdgeUnifier(source, toNode, nil)
+	unifierNoInfo := newNodeEdgeUnifier(source, toNode, false, nil)
 	unifierNoInfo.addPolicy(fromNode, &channeldb.CachedEdgePolicy{}, 0)
 
+	// Give p1 an inbound discount of the to node.
+	p1Inbound := p1
+	p1Inbound.InboundFee = models.InboundFee{Base: -10}
+
+	unifierInbound := newNodeEdgeUnifier(source, toNode, true, nil)
+	unifierInbound.addPolicy(fromNode, &p1Inbound, c1)
+	unifierInbound.addPolicy(fromNode, &p2, c2)
+
+	// The final destination doesn't charge inbound fees.
+	unifierInboundExit := newNodeEdgeUnifier(source, toNode, false, nil)
+	unifierInboundExit.addPolicy(fromNode, &p1Inbound, c1)
+	unifierInboundExit.addPolicy(fromNode, &p2, c2)
+
 	tests := []struct {
 		name             string
 		unifier          *nodeEdgeUnifier
 		amount           lnwire.MilliSatoshi
+		nextOutFee       lnwire.MilliSatoshi
 		expectedFeeBase  lnwire.MilliSatoshi
 		expectedFeeRate  lnwire.MilliSatoshi
 		expectedTimeLock uint16
@@ -114,6 +129,39 @@ func TestNodeEdgeUnifier(t *testing.T) {
 			unifier:          unifierNoInfo,
 			expectedCapacity: 0,
 		},
+		// For 200 msat, the inbound discount of p1 makes p2 the policy
+		// with the highest total fee.
+		{
+			name:             "use p2 due to inbound discount",
+			unifier:          unifierInbound,
+			amount:           200,
+			nextOutFee:       100,
+			expectedFeeBase:  p2.FeeBaseMSat,
+			expectedFeeRate:  p2.FeeProportionalMillionths,
+			expectedTimeLock: p1.TimeLockDelta,
+			expectedCapacity: c2,
+		},
+		// Without an outbound fee of the to node, the discount is
+		// capped at zero and p1 yields the highest fee again.
+		{
+			name:             "inbound discount capped",
+			unifier:          unifierInbound,
+			amount:           200,
+			expectedFeeBase:  p1.FeeBaseMSat,
+			expectedFeeRate:  p1.FeeProportionalMillionths,
+			expectedTimeLock: p1.TimeLockDelta,
+			expectedCapacity: c2,
+		},
+		{
+			name:             "no inbound fee for exit hop",
+			unifier:          unifierInboundExit,
+			amount:           200,
+			nextOutFee:       100,
+			expectedFeeBase:  p1.FeeBaseMSat,
+			expectedFeeRate:  p1.FeeProportionalMillionths,
+			expectedTimeLock: p1.TimeLockDelta,
+			expectedCapacity: c2,
+		},
 	}
 
 	for _, test := range tests {
@@ -123,7 +171,7 @@ func TestNodeEdgeUnifier(t *testing.T) {
 			t.Parallel()
 
 			edge := test.unifier.edgeUnifiers[fromNode].getEdge(
-				test.amount, bandwidthHints,
+				test.amount, bandwidthHints, test.nextOutFee,
 			)
 
 			if test.expectNoPolicy {
@@ -21,12 +21,14 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/ltcsuite/lnd/autopilot"
+	"github.com/ltcsuite/lnd/bolt12"
 	"github.com/ltcsuite/lnd/build"
 	"github.com/ltcsuite/lnd/chainreg"
 	"github.com/ltcsuite/lnd/chanacceptor"
 	"github.com/ltcsuite/lnd/chanbackup"
 	"github.com/ltcsuite/lnd/chanfitness"
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/channeldb/models"
 	"github.com/ltcsuite/lnd/channelnotifier"
 	"github.com/ltcsuite/lnd/contractcourt"
 	"github.com/ltcsuite/lnd/discovery"
@@ -51,8 +53,11 @@ import (
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
 	"github.com/ltcsuite/lnd/lnwallet/chancloser"
 	"github.com/ltcsuite/lnd/lnwallet/chanfunding"
+	"github.com/ltcsuite/lnd/lnwallet/chansplicer"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/lnd/macaroons"
+	"github.com/ltcsuite/lnd/offers"
+	"github.com/ltcsuite/lnd/onionmessage"
 	"github.com/ltcsuite/lnd/peer"
 	"github.com/ltcsuite/lnd/peernotifier"
 	"github.com/ltcsuite/lnd/record"
@@ -76,6 +81,7 @@ import (
 	"github.com/ltcsuite/ltcwallet/waddrmgr"
 	"github.com/ltcsuite/ltcwallet/wallet/txauthor"
 	"github.com/tv42/zbase32"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -322,6 +328,17 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/SpliceChannel": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/UpgradeChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/AbandonChannel": {{
 			Entity: "offchain",
 			Action: "write",
@@ -528,6 +545,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/BumpChannelOpen": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		lnrpc.RegisterRPCMiddlewareURI: {{
 			Entity: "macaroon",
 			Action: "write",
@@ -540,6 +564,22 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SendOnionMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeOnionMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AddOffer": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/DecodeOffer": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/LookupHtlcResolution": {{
 			Entity: "offchain",
 			Action: "read",
@@ -702,6 +742,7 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			return info.NodeKey1Bytes, info.NodeKey2Bytes, nil
 		},
 		FindRoute:              s.chanRouter.FindRoute,
+		FindBlindedRoute:       s.chanRouter.FindBlindedRoute,
 		MissionControl:         s.missionControl,
 		ActiveNetParams:        r.cfg.ActiveNetParams.Params,
 		Tower:                  s.controlTower,
@@ -717,6 +758,9 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		},
 		SetChannelAuto: s.chanStatusMgr.RequestAuto,
 	}
+	if s.offersMgr != nil {
+		routerBackend.FetchOfferInvoice = s.offersMgr.FetchInvoice
+	}
 
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
 		return s.featureMgr.Get(feature.SetInvoice)
@@ -744,10 +788,10 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		s.htlcSwitch, r.cfg.ActiveNetParams.Params, s.chanRouter,
 		routerBackend, s.nodeSigner, s.graphDB, s.chanStateDB,
 		s.sweeper, tower, s.towerClient, s.anchorTowerClient,
-		r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
-		genAmpInvoiceFeatures, s.getNodeAnnouncement,
-		s.updateAndBrodcastSelfNode, parseAddr, rpcsLog,
-		s.aliasMgr.GetPeerAlias,
+		s.taprootTowerClient, r.cfg.net.ResolveTCPAddr,
+		genInvoiceFeatures, genAmpInvoiceFeatures,
+		s.getNodeAnnouncement, s.updateAndBrodcastSelfNode, parseAddr,
+		rpcsLog, s.aliasMgr.GetPeerAlias,
 	)
 	if err != nil {
 		return err
@@ -1131,6 +1175,7 @@ func (r *rpcServer) ListUnspent(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	lnrpc.RedactMwebUtxos(rpcUtxos)
 
 	maxStr := ""
 	if maxConfs != math.MaxInt32 {
@@ -2256,6 +2301,7 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		MinFundAmt:        minFundAmt,
 		Memo:              []byte(in.Memo),
 		Outpoints:         outpoints,
+		DualFunded:        in.DualFunded,
 	}, nil
 }
 
@@ -2336,15 +2382,36 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 
 	updateChan, errChan := r.server.OpenChannel(req)
 
-	var outpoint wire.OutPoint
+	var (
+		outpoint wire.OutPoint
+		pegOut   *mwebPegOut
+	)
 out:
 	for {
 		select {
 		case err := <-errChan:
+			// If the wallet can only afford the channel with its
+			// MWEB coins, we might peg them out and try again once.
+			if pegOut == nil {
+				pegOut, err = r.pegOutForFunding(
+					updateStream.Context(), err,
+					req.MinConfs,
+				)
+				if err == nil {
+					pegOut.applyTo(req)
+					updateChan, errChan =
+						r.server.OpenChannel(req)
+
+					continue
+				}
+			}
+
 			rpcsLog.Errorf("unable to open channel to NodeKey(%x): %v",
 				req.TargetPubkey.SerializeCompressed(), err)
 			return err
 		case fundingUpdate := <-updateChan:
+			pegOut.markPending(fundingUpdate.GetChanPending())
+
 			rpcsLog.Tracef("[openchannel] sending update: %v",
 				fundingUpdate)
 			if err := updateStream.Send(fundingUpdate); err != nil {
@@ -2395,33 +2462,53 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 	}
 
 	updateChan, errChan := r.server.OpenChannel(req)
-	select {
-	// If an error occurs them immediately return the error to the client.
-	case err := <-errChan:
-		rpcsLog.Errorf("unable to open channel to NodeKey(%x): %v",
-			req.TargetPubkey.SerializeCompressed(), err)
-		return nil, err
 
-	// Otherwise, wait for the first channel update. The first update sent
-	// is when the funding transaction is broadcast to the network.
-	case fundingUpdate := <-updateChan:
-		rpcsLog.Tracef("[openchannel] sending update: %v",
-			fundingUpdate)
+	var pegOut *mwebPegOut
+	for {
+		select {
+		// If the wallet can only afford the channel with its MWEB
+		// coins, we might peg them out and try again once.
+		case err := <-errChan:
+			if pegOut == nil {
+				pegOut, err = r.pegOutForFunding(
+					ctx, err, req.MinConfs,
+				)
+				if err == nil {
+					pegOut.applyTo(req)
+					updateChan, errChan =
+						r.server.OpenChannel(req)
 
-		// Parse out the txid of the pending funding transaction. The
-		// sync client can use this to poll against the list of
-		// PendingChannels.
-		openUpdate := fundingUpdate.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
-		chanUpdate := openUpdate.ChanPending
+					continue
+				}
+			}
 
-		return &lnrpc.ChannelPoint{
-			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			rpcsLog.Errorf("unable to open channel to "+
+				"NodeKey(%x): %v",
+				req.TargetPubkey.SerializeCompressed(), err)
+			return nil, err
+
+		// Otherwise, wait for the first channel update. The first
+		// update sent is when the funding transaction is broadcast to
+		// the network.
+		case fundingUpdate := <-updateChan:
+			rpcsLog.Tracef("[openchannel] sending update: %v",
+				fundingUpdate)
+
+			// Parse out the txid of the pending funding
+			// transaction. The sync client can use this to poll
+			// against the list of PendingChannels.
+			chanUpdate := fundingUpdate.GetChanPending()
+
+			txid := &lnrpc.ChannelPoint_FundingTxidBytes{
 				FundingTxidBytes: chanUpdate.Txid,
-			},
-			OutputIndex: chanUpdate.OutputIndex,
-		}, nil
-	case <-r.quit:
-		return nil, nil
+			}
+			return &lnrpc.ChannelPoint{
+				FundingTxid: txid,
+				OutputIndex: chanUpdate.OutputIndex,
+			}, nil
+		case <-r.quit:
+			return nil, nil
+		}
 	}
 }
 
@@ -2465,6 +2552,13 @@ func (r *rpcServer) BatchOpenChannel(ctx context.Context,
 		return nil, fmt.Errorf("specify at least one channel")
 	}
 
+	// Check, if manually selected outpoints are present to fund the batch.
+	if len(in.Outpoints) > 0 {
+		if _, err := toWireOutpoints(in.Outpoints); err != nil {
+			return nil, fmt.Errorf("can't create outpoints %w", err)
+		}
+	}
+
 	// In case we remove a pending channel from the database, we need to set
 	// a close height, so we'll just use the current best known height.
 	_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
@@ -2482,7 +2576,7 @@ func (r *rpcServer) BatchOpenChannel(ctx context.Context,
 	channelAbandoner := func(point *wire.OutPoint) error {
 		return r.abandonChan(point, uint32(bestHeight))
 	}
-	batcher := funding.NewBatcher(&funding.BatchConfig{
+	batchCfg := &funding.BatchConfig{
 		RequestParser:    requestParser,
 		ChannelAbandoner: channelAbandoner,
 		ChannelOpener:    r.server.OpenChannel,
@@ -2490,10 +2584,34 @@ func (r *rpcServer) BatchOpenChannel(ctx context.Context,
 		Wallet:           r.server.cc.Wallet,
 		NetParams:        &r.server.cc.Wallet.Cfg.NetParams,
 		Quit:             r.quit,
-	})
+	}
+	batcher := funding.NewBatcher(batchCfg)
 	rpcPoints, err := batcher.BatchFund(ctx, in)
+
+	// If the wallet can only afford the batch with its MWEB coins, we
+	// might peg them out and try again once with a fresh batcher, as the
+	// failed one has cleaned up after itself.
+	var pegOut *mwebPegOut
+	if err != nil {
+		minConfs, confErr := lnrpc.ExtractMinConfs(
+			in.MinConfs, in.SpendUnconfirmed,
+		)
+		if confErr != nil {
+			return nil, confErr
+		}
+
+		pegOut, err = r.pegOutForFunding(ctx, err, minConfs)
+		if err == nil {
+			pegOut.applyToBatch(in)
+			batcher = funding.NewBatcher(batchCfg)
+			rpcPoints, err = batcher.BatchFund(ctx, in)
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("batch funding failed: %v", err)
+		return nil, fmt.Errorf("batch funding failed: %w", err)
+	}
+	for _, rpcPoint := range rpcPoints {
+		pegOut.markPending(rpcPoint)
 	}
 
 	// Now all that's left to do is send back the response with the channel
@@ -2935,6 +3053,256 @@ func (r *rpcServer) AbandonChannel(_ context.Context,
 	return &lnrpc.AbandonChannelResponse{}, nil
 }
 
+// SpliceChannel splices funds into or out of an active channel identified by
+// its channel point, and returns once the splice transaction was broadcast.
+func (r *rpcServer) SpliceChannel(ctx context.Context,
+	in *lnrpc.SpliceChannelRequest) (*lnrpc.SpliceChannelResponse, error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point in splice " +
+			"channel")
+	}
+
+	switch {
+	case in.SpliceInSat < 0 || in.SpliceOutSat < 0:
+		return nil, fmt.Errorf("splice amounts must not be negative")
+
+	case in.SpliceInSat == 0 && in.SpliceOutSat == 0:
+		return nil, fmt.Errorf("either splice_in_sat or " +
+			"splice_out_sat must be set")
+
+	case in.SpliceInSat != 0 && in.SpliceOutSat != 0:
+		return nil, fmt.Errorf("cannot splice funds in and out of a " +
+			"channel at the same time")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to splice channel while peer "+
+			"is offline: %w", err)
+	}
+
+	// Spliced out funds are sent to a new address of the wallet, unless
+	// the caller specified an address.
+	var spliceOutScript []byte
+	if in.SpliceOutSat > 0 {
+		var addr ltcutil.Address
+		if in.SpliceOutAddr != "" {
+			addr, err = ltcutil.DecodeAddress(
+				in.SpliceOutAddr, r.cfg.ActiveNetParams.Params,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("invalid splice out "+
+					"address: %w", err)
+			}
+
+			if !addr.IsForNet(r.cfg.ActiveNetParams.Params) {
+				return nil, fmt.Errorf("splice out address is "+
+					"not for %s",
+					r.cfg.ActiveNetParams.Params.Name)
+			}
+		} else {
+			addr, err = r.server.cc.Wallet.NewAddress(
+				lnwallet.TaprootPubkey, false,
+				lnwallet.DefaultAccountName,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		spliceOutScript, err = txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	feeRate, err := lnrpc.CalculateFeeRate(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+		r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[splicechannel] request for ChannelPoint(%v), "+
+		"splice_in=%v, splice_out=%v, sat/kw=%v", chanPoint,
+		in.SpliceInSat, in.SpliceOutSat, int64(feeRate))
+
+	req := &chansplicer.SpliceRequest{
+		ChanPoint:       chanPoint,
+		SpliceIn:        ltcutil.Amount(in.SpliceInSat),
+		SpliceOut:       ltcutil.Amount(in.SpliceOutSat),
+		SpliceOutScript: spliceOutScript,
+		FeeRate:         feeRate,
+		Txid:            make(chan chainhash.Hash, 1),
+		Err:             make(chan error, 1),
+	}
+	peer.HandleLocalSpliceReq(req)
+
+	select {
+	case spliceTxid := <-req.Txid:
+		return &lnrpc.SpliceChannelResponse{
+			SpliceTxid: spliceTxid[:],
+		}, nil
+
+	case err := <-req.Err:
+		return nil, err
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-r.quit:
+		return nil, ErrServerShuttingDown
+	}
+}
+
+// UpgradeChannel upgrades the commitment type of an active channel identified
+// by its channel point in place, and returns once both peers signed
+// commitments of the new type.
+func (r *rpcServer) UpgradeChannel(ctx context.Context,
+	in *lnrpc.UpgradeChannelRequest) (*lnrpc.UpgradeChannelResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point in upgrade " +
+			"channel")
+	}
+
+	var commitType lnwallet.CommitmentType
+	switch in.CommitmentType {
+	case lnrpc.CommitmentType_STATIC_REMOTE_KEY:
+		commitType = lnwallet.CommitmentTypeTweakless
+
+	case lnrpc.CommitmentType_ANCHORS:
+		commitType = lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx
+
+	default:
+		return nil, fmt.Errorf("unsupported commitment type %v for "+
+			"channel upgrade", in.CommitmentType)
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	link, err := r.server.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to upgrade inactive channel: "+
+			"%w", err)
+	}
+
+	rpcsLog.Debugf("[upgradechannel] request for ChannelPoint(%v), "+
+		"commitment_type=%v", chanPoint, commitType)
+
+	select {
+	case err := <-link.UpgradeCommitType(commitType):
+		if err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.UpgradeChannelResponse{}, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-r.quit:
+		return nil, ErrServerShuttingDown
+	}
+}
+
+// BumpChannelOpen replaces the funding transaction of a pending dual funded
+// channel with one paying a higher fee rate, and returns once the
+// replacement was broadcast.
+func (r *rpcServer) BumpChannelOpen(ctx context.Context,
+	in *lnrpc.BumpChannelOpenRequest) (*lnrpc.BumpChannelOpenResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point in bump " +
+			"channel open")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to bump channel open while "+
+			"peer is offline: %w", err)
+	}
+
+	feeRate, err := lnrpc.CalculateFeeRate(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+		r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[bumpchannelopen] request for ChannelPoint(%v), "+
+		"sat/kw=%v", chanPoint, int64(feeRate))
+
+	req := &funding.BumpFundingMsg{
+		Peer:      peer,
+		ChanPoint: *chanPoint,
+		FeeRate:   feeRate,
+		Txid:      make(chan chainhash.Hash, 1),
+		Err:       make(chan error, 1),
+	}
+	go r.server.fundingMgr.BumpFundingFee(req)
+
+	select {
+	case fundingTxid := <-req.Txid:
+		return &lnrpc.BumpChannelOpenResponse{
+			FundingTxid: fundingTxid[:],
+		}, nil
+
+	case err := <-req.Err:
+		return nil, err
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-r.quit:
+		return nil, ErrServerShuttingDown
+	}
+}
+
 // GetInfo returns general information concerning the lightning node including
 // its identity pubkey, alias, the chains it is connected to, and information
 // concerning the number of open+pending channels.
@@ -3284,6 +3652,8 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 	}
 
 	var totalBalance, confirmedBalance, unconfirmedBalance ltcutil.Amount
+	var canonicalBalance, mwebConfirmedBalance ltcutil.Amount
+	var mwebUnconfirmedBalance ltcutil.Amount
 	rpcAccountBalances := make(
 		map[string]*lnrpc.WalletAccountBalance, len(accounts),
 	)
@@ -3342,6 +3712,30 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 		unconfirmedBal := totalBal - confirmedBal
 		unconfirmedBalance += unconfirmedBal
 
+		// Funds held in the MWEB extension block can't fund channels
+		// directly, so we report them separately from the canonical
+		// balance.
+		utxos, err := r.server.cc.Wallet.ListUnspentWitness(
+			0, math.MaxInt32, account.AccountName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		var mwebBal ltcutil.Amount
+		for _, utxo := range utxos {
+			if utxo.AddressType != lnwallet.Mweb {
+				continue
+			}
+
+			mwebBal += utxo.Value
+			if utxo.Confirmations > 0 {
+				mwebConfirmedBalance += utxo.Value
+			} else {
+				mwebUnconfirmedBalance += utxo.Value
+			}
+		}
+		canonicalBalance += totalBal - mwebBal
+
 		rpcAccountBalances[account.AccountName] = &lnrpc.WalletAccountBalance{
 			ConfirmedBalance:   int64(confirmedBal),
 			UnconfirmedBalance: int64(unconfirmedBal),
@@ -3381,8 +3775,10 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 	)
 
 	rpcsLog.Debugf("[walletbalance] Total balance=%v (confirmed=%v, "+
-		"unconfirmed=%v)", totalBalance, confirmedBalance,
-		unconfirmedBalance)
+		"unconfirmed=%v, canonical=%v, mweb_confirmed=%v, "+
+		"mweb_unconfirmed=%v)", totalBalance, confirmedBalance,
+		unconfirmedBalance, canonicalBalance, mwebConfirmedBalance,
+		mwebUnconfirmedBalance)
 
 	return &lnrpc.WalletBalanceResponse{
 		TotalBalance:              int64(totalBalance),
@@ -3391,6 +3787,9 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 		LockedBalance:             int64(lockedBalance),
 		ReservedBalanceAnchorChan: int64(requiredReserve),
 		AccountBalance:            rpcAccountBalances,
+		CanonicalBalance:          int64(canonicalBalance),
+		MwebConfirmedBalance:      int64(mwebConfirmedBalance),
+		MwebUnconfirmedBalance:    int64(mwebUnconfirmedBalance),
 	}, nil
 }
 
@@ -4888,7 +5287,7 @@ type rpcPaymentRequest struct {
 func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error {
 	var lock sync.Mutex
 
-	return r.sendPayment(&paymentStream{
+	return r.sendPayment(stream.Context(), &paymentStream{
 		recv: func() (*rpcPaymentRequest, error) {
 			req, err := stream.Recv()
 			if err != nil {
@@ -4916,7 +5315,7 @@ func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error
 func (r *rpcServer) SendToRoute(stream lnrpc.Lightning_SendToRouteServer) error {
 	var lock sync.Mutex
 
-	return r.sendPayment(&paymentStream{
+	return r.sendPayment(stream.Context(), &paymentStream{
 		recv: func() (*rpcPaymentRequest, error) {
 			req, err := stream.Recv()
 			if err != nil {
@@ -5233,17 +5632,19 @@ type paymentIntentResponse struct {
 // We'll either pass the payment as a whole to the channel router, or give it a
 // pre-built route. The first error this method returns denotes if we were
 // unable to save the payment. The second error returned denotes if the payment
-// didn't succeed.
-func (r *rpcServer) dispatchPaymentIntent(
+// didn't succeed. The spans of the payment are recorded as part of the trace of
+// the given context, if tracing is enabled.
+func (r *rpcServer) dispatchPaymentIntent(ctx context.Context,
 	payIntent *rpcPaymentIntent) (*paymentIntentResponse, error) {
 
 	// Construct a payment request to send to the channel router. If the
 	// payment is successful, the route chosen will be returned. Otherwise,
 	// we'll get a non-nil error.
 	var (
-		preImage  [32]byte
-		route     *route.Route
-		routerErr error
+		preImage    [32]byte
+		route       *route.Route
+		routerErr   error
+		spanContext = trace.SpanContextFromContext(ctx)
 	)
 
 	// If a route was specified, then we'll pass the route directly to the
@@ -5264,6 +5665,7 @@ func (r *rpcServer) dispatchPaymentIntent(
 			DestFeatures:       payIntent.destFeatures,
 			PaymentAddr:        payIntent.paymentAddr,
 			Metadata:           payIntent.metadata,
+			SpanContext:        spanContext,
 
 			// Don't enable multi-part payments on the main rpc.
 			// Users need to use routerrpc for that.
@@ -5280,7 +5682,7 @@ func (r *rpcServer) dispatchPaymentIntent(
 	} else {
 		var attempt *channeldb.HTLCAttempt
 		attempt, routerErr = r.server.chanRouter.SendToRoute(
-			payIntent.rHash, payIntent.route,
+			spanContext, payIntent.rHash, payIntent.route,
 		)
 
 		if routerErr == nil {
@@ -5310,8 +5712,11 @@ func (r *rpcServer) dispatchPaymentIntent(
 // requests) and continually attempt to dispatch payment requests written to
 // the write end of the stream. Responses will also be streamed back to the
 // client via the write end of the stream. This method is by both SendToRoute
-// and SendPayment as the logic is virtually identical.
-func (r *rpcServer) sendPayment(stream *paymentStream) error {
+// and SendPayment as the logic is virtually identical. The spans of the
+// payments are recorded as part of the trace of the given stream context.
+func (r *rpcServer) sendPayment(ctx context.Context,
+	stream *paymentStream) error {
+
 	payChan := make(chan *rpcPaymentIntent)
 	errChan := make(chan error, 1)
 
@@ -5453,7 +5858,7 @@ sendLoop:
 				}()
 
 				resp, saveErr := r.dispatchPaymentIntent(
-					payIntent,
+					ctx, payIntent,
 				)
 
 				switch {
@@ -5576,7 +5981,7 @@ func (r *rpcServer) sendPaymentSync(ctx context.Context,
 
 	// With the payment validated, we'll now attempt to dispatch the
 	// payment.
-	resp, saveErr := r.dispatchPaymentIntent(&payIntent)
+	resp, saveErr := r.dispatchPaymentIntent(ctx, &payIntent)
 	switch {
 	case saveErr != nil:
 		return nil, saveErr
@@ -5606,24 +6011,7 @@ func (r *rpcServer) sendPaymentSync(ctx context.Context,
 func (r *rpcServer) AddInvoice(ctx context.Context,
 	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
 
-	defaultDelta := r.cfg.Litecoin.TimeLockDelta
-
-	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
-		AddInvoice:        r.server.invoices.AddInvoice,
-		IsChannelActive:   r.server.htlcSwitch.HasActiveLink,
-		ChainParams:       r.cfg.ActiveNetParams.Params,
-		NodeSigner:        r.server.nodeSigner,
-		DefaultCLTVExpiry: defaultDelta,
-		ChanDB:            r.server.chanStateDB,
-		Graph:             r.server.graphDB,
-		GenInvoiceFeatures: func() *lnwire.FeatureVector {
-			return r.server.featureMgr.Get(feature.SetInvoice)
-		},
-		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
-			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
-		},
-		GetAlias: r.server.aliasMgr.GetPeerAlias,
-	}
+	addInvoiceCfg := r.server.newAddInvoiceConfig()
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
 	if err != nil {
@@ -5645,6 +6033,7 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		Private:         invoice.Private,
 		RouteHints:      routeHints,
 		Amp:             invoice.IsAmp,
+		Blind:           invoice.IsBlinded,
 	}
 
 	if invoice.RPreimage != nil {
@@ -6066,7 +6455,7 @@ func marshalDBRoutingPolicy(
 
 	customRecords := marshalExtraOpaqueData(policy.ExtraOpaqueData)
 
-	return &lnrpc.RoutingPolicy{
+	rpcPolicy := &lnrpc.RoutingPolicy{
 		TimeLockDelta:    uint32(policy.TimeLockDelta),
 		MinHtlc:          int64(policy.MinHTLC),
 		MaxHtlcMsat:      uint64(policy.MaxHTLC),
@@ -6076,6 +6465,21 @@ func marshalDBRoutingPolicy(
 		LastUpdate:       uint32(policy.LastUpdate.Unix()),
 		CustomRecords:    customRecords,
 	}
+
+	// A malformed inbound fee is still visible in the custom records, so
+	// we just report a zero fee in that case.
+	inboundFee, err := policy.InboundFee()
+	if err != nil {
+		rpcsLog.Debugf("Unable to parse inbound fee of channel %v: %v",
+			policy.ChannelID, err)
+
+		return rpcPolicy
+	}
+
+	rpcPolicy.InboundFeeBaseMsat = inboundFee.Base
+	rpcPolicy.InboundFeeRateMilliMsat = inboundFee.Rate
+
+	return rpcPolicy
 }
 
 // GetNodeMetrics returns all available node metrics calculated from the
@@ -6602,7 +7006,7 @@ func (r *rpcServer) ListPayments(ctx context.Context,
 		query.MaxPayments = math.MaxUint64
 	}
 
-	paymentsQuerySlice, err := r.server.miscDB.QueryPayments(query)
+	paymentsQuerySlice, err := r.server.paymentDB.QueryPayments(query)
 	if err != nil {
 		return nil, err
 	}
@@ -6644,7 +7048,7 @@ func (r *rpcServer) DeletePayment(ctx context.Context,
 	rpcsLog.Infof("[DeletePayment] payment_identifier=%v, "+
 		"failed_htlcs_only=%v", hash, req.FailedHtlcsOnly)
 
-	err = r.server.miscDB.DeletePayment(hash, req.FailedHtlcsOnly)
+	err = r.server.paymentDB.DeletePayment(hash, req.FailedHtlcsOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -6661,7 +7065,7 @@ func (r *rpcServer) DeleteAllPayments(ctx context.Context,
 		"failed_htlcs_only=%v", req.FailedPaymentsOnly,
 		req.FailedHtlcsOnly)
 
-	err := r.server.miscDB.DeletePayments(
+	err := r.server.paymentDB.DeletePayments(
 		req.FailedPaymentsOnly, req.FailedHtlcsOnly,
 	)
 	if err != nil {
@@ -6766,6 +7170,9 @@ func (r *rpcServer) DecodePayReq(ctx context.Context,
 		RouteHints:      routeHints,
 		PaymentAddr:     paymentAddr,
 		Features:        invoicesrpc.CreateRPCFeatures(payReq.Features),
+		BlindedPaths: invoicesrpc.CreateRPCBlindedPayments(
+			payReq.BlindedPaymentPaths,
+		),
 	}, nil
 }
 
@@ -6804,13 +7211,21 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 		feeRateFixedPoint := edgePolicy.FeeProportionalMillionths
 		feeRate := float64(feeRateFixedPoint) / feeBase
 
+		inboundFee, err := edgePolicy.InboundFee()
+		if err != nil {
+			return fmt.Errorf("unable to parse inbound fee of "+
+				"channel %v: %w", chanInfo.ChannelID, err)
+		}
+
 		// TODO(roasbeef): also add stats for revenue for each channel
 		feeReports = append(feeReports, &lnrpc.ChannelFeeReport{
-			ChanId:       chanInfo.ChannelID,
-			ChannelPoint: chanInfo.ChannelPoint.String(),
-			BaseFeeMsat:  int64(edgePolicy.FeeBaseMSat),
-			FeePerMil:    int64(feeRateFixedPoint),
-			FeeRate:      feeRate,
+			ChanId:             chanInfo.ChannelID,
+			ChannelPoint:       chanInfo.ChannelPoint.String(),
+			BaseFeeMsat:        int64(edgePolicy.FeeBaseMSat),
+			FeePerMil:          int64(feeRateFixedPoint),
+			FeeRate:            feeRate,
+			InboundBaseFeeMsat: inboundFee.Base,
+			InboundFeePerMil:   inboundFee.Rate,
 		})
 
 		return nil
@@ -6992,10 +7407,22 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 			MaxTimeLockDelta)
 	}
 
+	// Inbound fees are only meant to steer incoming liquidity with
+	// discounts, which keeps the outbound fee the upper bound of what a
+	// forward through our node costs.
+	if req.InboundBaseFeeMsat > 0 || req.InboundFeeRatePpm > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "positive "+
+			"inbound fees are not supported")
+	}
+
 	baseFeeMsat := lnwire.MilliSatoshi(req.BaseFeeMsat)
 	feeSchema := routing.FeeSchema{
 		BaseFee: baseFeeMsat,
 		FeeRate: feeRateFixed,
+		InboundFee: models.InboundFee{
+			Base: req.InboundBaseFeeMsat,
+			Rate: req.InboundFeeRatePpm,
+		},
 	}
 
 	maxHtlc := lnwire.MilliSatoshi(req.MaxHtlcMsat)
@@ -7013,9 +7440,11 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	}
 
 	rpcsLog.Debugf("[updatechanpolicy] updating channel policy "+
-		"base_fee=%v, rate_fixed=%v, time_lock_delta: %v, "+
+		"base_fee=%v, rate_fixed=%v, inbound_base_fee=%v, "+
+		"inbound_rate_fixed=%v, time_lock_delta: %v, "+
 		"min_htlc=%v, max_htlc=%v, targets=%v",
-		req.BaseFeeMsat, feeRateFixed, req.TimeLockDelta,
+		req.BaseFeeMsat, feeRateFixed, req.InboundBaseFeeMsat,
+		req.InboundFeeRatePpm, req.TimeLockDelta,
 		minHtlc, maxHtlc,
 		spew.Sdump(targetChans))
 
@@ -7083,11 +7512,40 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 	// Next, we'll map the proto request into a format that is understood by
 	// the forwarding log.
 	eventQuery := channeldb.ForwardingEventQuery{
-		StartTime:    startTime,
-		EndTime:      endTime,
-		IndexOffset:  req.IndexOffset,
-		NumMaxEvents: numEvents,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		IndexOffset:     req.IndexOffset,
+		NumMaxEvents:    numEvents,
+		IncomingChanIDs: fn.NewSet[lnwire.ShortChannelID](),
+		OutgoingChanIDs: fn.NewSet[lnwire.ShortChannelID](),
+		Peers:           fn.NewSet[route.Vertex](),
+		Outcomes:        fn.NewSet[channeldb.ForwardingEventOutcome](),
+	}
+	for _, chanID := range req.IncomingChanIds {
+		eventQuery.IncomingChanIDs.Add(
+			lnwire.NewShortChanIDFromInt(chanID),
+		)
+	}
+	for _, chanID := range req.OutgoingChanIds {
+		eventQuery.OutgoingChanIDs.Add(
+			lnwire.NewShortChanIDFromInt(chanID),
+		)
+	}
+	for _, peer := range req.Peers {
+		vertex, err := route.NewVertexFromBytes(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer %x: %w", peer, err)
+		}
+		eventQuery.Peers.Add(vertex)
+	}
+	for _, outcome := range req.Outcomes {
+		fwdOutcome, err := unmarshallForwardingOutcome(outcome)
+		if err != nil {
+			return nil, err
+		}
+		eventQuery.Outcomes.Add(fwdOutcome)
 	}
+
 	timeSlice, err := r.server.miscDB.ForwardingLog().Query(eventQuery)
 	if err != nil {
 		return nil, fmt.Errorf("unable to query forwarding log: %v",
@@ -7134,9 +7592,6 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 		return peer.Alias, nil
 	}
 
-	// TODO(roasbeef): add settlement latency?
-	//  * use FPE on all records?
-
 	// With the events retrieved, we'll now map them into the proper proto
 	// response.
 	//
@@ -7153,29 +7608,77 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 		amtOutMsat := event.AmtOut
 		feeMsat := event.AmtIn - event.AmtOut
 
-		resp.ForwardingEvents[i] = &lnrpc.ForwardingEvent{
-			Timestamp:   uint64(event.Timestamp.Unix()),
-			TimestampNs: uint64(event.Timestamp.UnixNano()),
-			ChanIdIn:    event.IncomingChanID.ToUint64(),
-			ChanIdOut:   event.OutgoingChanID.ToUint64(),
-			AmtIn:       uint64(amtInMsat.ToSatoshis()),
-			AmtOut:      uint64(amtOutMsat.ToSatoshis()),
-			Fee:         uint64(feeMsat.ToSatoshis()),
-			FeeMsat:     uint64(feeMsat),
-			AmtInMsat:   uint64(amtInMsat),
-			AmtOutMsat:  uint64(amtOutMsat),
+		// Failed and rejected forwards didn't earn us any fees.
+		if event.Outcome != channeldb.ForwardingEventSettled {
+			feeMsat = 0
+		}
+
+		var addedAt uint64
+		if !event.AddedTime.IsZero() {
+			addedAt = uint64(event.AddedTime.UnixNano())
 		}
+		latency := event.ResolutionLatency()
+		outcome := marshallForwardingOutcome(event.Outcome)
+		failureSource := marshallForwardingFailureSource(
+			event.FailureSource,
+		)
+
+		// Fall back to the name of the failure code if the failure
+		// wasn't recorded in more detail.
+		failureReason := event.FailureReason
+		if failureReason == "" && event.FailureCode != 0 {
+			failureReason = event.FailureCode.String()
+		}
+
+		resp.ForwardingEvents[i] = &lnrpc.ForwardingEvent{
+			Timestamp:           uint64(event.Timestamp.Unix()),
+			TimestampNs:         uint64(event.Timestamp.UnixNano()),
+			ChanIdIn:            event.IncomingChanID.ToUint64(),
+			ChanIdOut:           event.OutgoingChanID.ToUint64(),
+			AmtIn:               uint64(amtInMsat.ToSatoshis()),
+			AmtOut:              uint64(amtOutMsat.ToSatoshis()),
+			Fee:                 uint64(feeMsat.ToSatoshis()),
+			FeeMsat:             uint64(feeMsat),
+			AmtInMsat:           uint64(amtInMsat),
+			AmtOutMsat:          uint64(amtOutMsat),
+			AddedAtNs:           addedAt,
+			ResolutionLatencyNs: uint64(latency.Nanoseconds()),
+			Outcome:             outcome,
+			FailureSource:       failureSource,
+			FailureCode:         uint32(event.FailureCode),
+			FailureReason:       failureReason,
+		}
+		event.IncomingHtlcID.WhenSome(func(id uint64) {
+			resp.ForwardingEvents[i].IncomingHtlcId = id
+			resp.ForwardingEvents[i].HasIncomingHtlcId = true
+		})
+		event.OutgoingHtlcID.WhenSome(func(id uint64) {
+			resp.ForwardingEvents[i].OutgoingHtlcId = id
+			resp.ForwardingEvents[i].HasOutgoingHtlcId = true
+		})
 
 		if req.PeerAliasLookup {
-			aliasIn, err := getRemoteAlias(event.IncomingChanID)
-			if err != nil {
-				aliasIn = fmt.Sprintf("unable to lookup peer "+
-					"alias: %v", err)
+			// We'll prefer the aliases that were recorded along
+			// with the event, and only look them up otherwise.
+			aliasIn := event.IncomingPeerAlias
+			if aliasIn == "" {
+				aliasIn, err = getRemoteAlias(
+					event.IncomingChanID,
+				)
+				if err != nil {
+					aliasIn = fmt.Sprintf("unable to "+
+						"lookup peer alias: %v", err)
+				}
 			}
-			aliasOut, err := getRemoteAlias(event.OutgoingChanID)
-			if err != nil {
-				aliasOut = fmt.Sprintf("unable to lookup peer"+
-					"alias: %v", err)
+			aliasOut := event.OutgoingPeerAlias
+			if aliasOut == "" {
+				aliasOut, err = getRemoteAlias(
+					event.OutgoingChanID,
+				)
+				if err != nil {
+					aliasOut = fmt.Sprintf("unable to "+
+						"lookup peer alias: %v", err)
+				}
 			}
 			resp.ForwardingEvents[i].PeerAliasIn = aliasIn
 			resp.ForwardingEvents[i].PeerAliasOut = aliasOut
@@ -7185,6 +7688,61 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 	return resp, nil
 }
 
+// unmarshallForwardingOutcome converts an RPC forwarding outcome into the
+// outcome used by the forwarding log.
+func unmarshallForwardingOutcome(
+	outcome lnrpc.ForwardingOutcome) (channeldb.ForwardingEventOutcome,
+	error) {
+
+	switch outcome {
+	case lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_SETTLED:
+		return channeldb.ForwardingEventSettled, nil
+
+	case lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_FAILED:
+		return channeldb.ForwardingEventFailed, nil
+
+	case lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_REJECTED:
+		return channeldb.ForwardingEventRejected, nil
+
+	default:
+		return 0, fmt.Errorf("unknown forwarding outcome: %v", outcome)
+	}
+}
+
+// marshallForwardingOutcome converts a forwarding log outcome into its RPC
+// counterpart.
+func marshallForwardingOutcome(
+	outcome channeldb.ForwardingEventOutcome) lnrpc.ForwardingOutcome {
+
+	switch outcome {
+	case channeldb.ForwardingEventFailed:
+		return lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_FAILED
+
+	case channeldb.ForwardingEventRejected:
+		return lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_REJECTED
+
+	default:
+		return lnrpc.ForwardingOutcome_FORWARDING_OUTCOME_SETTLED
+	}
+}
+
+// marshallForwardingFailureSource converts a forwarding log failure source
+// into its RPC counterpart.
+func marshallForwardingFailureSource(
+	source channeldb.ForwardingFailureSource) lnrpc.ForwardingFailureSource {
+
+	switch source {
+	case channeldb.ForwardingFailureSourceLocal:
+		return lnrpc.ForwardingFailureSource_FORWARDING_FAILURE_SOURCE_LOCAL
+
+	case channeldb.ForwardingFailureSourceRemote:
+		return lnrpc.ForwardingFailureSource_FORWARDING_FAILURE_SOURCE_REMOTE
+
+	default:
+		return lnrpc.ForwardingFailureSource_FORWARDING_FAILURE_SOURCE_NONE
+	}
+}
+
 // ExportChannelBackup attempts to return an encrypted static channel backup
 // for the target channel identified by it channel point. The backup is
 // encrypted with a key generated from the aezeed seed of the user. The
@@ -8117,6 +8675,215 @@ func (r *rpcServer) SubscribeCustomMessages(req *lnrpc.SubscribeCustomMessagesRe
 	}
 }
 
+// errOnionMessagesDisabled is returned by the onion message RPCs if onion
+// messages were disabled in the protocol options.
+var errOnionMessagesDisabled = errors.New("onion messages are disabled")
+
+// SendOnionMessage sends an onion message to a node or over a blinded path.
+func (r *rpcServer) SendOnionMessage(ctx context.Context,
+	req *lnrpc.SendOnionMessageRequest) (*lnrpc.SendOnionMessageResponse,
+	error) {
+
+	if r.server.onionMessenger == nil {
+		return nil, errOnionMessagesDisabled
+	}
+
+	sendReq := &onionmessage.SendRequest{
+		IncludeReplyPath: req.IncludeReplyPath,
+		CustomRecords:    req.CustomRecords,
+	}
+
+	switch {
+	case len(req.Destination) > 0 && req.BlindedPath != nil:
+		return nil, errors.New("only one of destination and " +
+			"blinded_path may be set")
+
+	case len(req.Destination) > 0:
+		dest, err := btcec.ParsePubKey(req.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination: %w", err)
+		}
+		sendReq.Destination = dest
+
+	case req.BlindedPath != nil:
+		path, err := routerrpc.UnmarshalBlindedPath(req.BlindedPath)
+		if err != nil {
+			return nil, err
+		}
+		sendReq.BlindedDestination = path
+
+	default:
+		return nil, errors.New("destination or blinded_path required")
+	}
+
+	for _, rpcHop := range req.IntermediateHops {
+		hop, err := btcec.ParsePubKey(rpcHop)
+		if err != nil {
+			return nil, fmt.Errorf("invalid intermediate hop: %w",
+				err)
+		}
+
+		sendReq.IntermediateHops = append(sendReq.IntermediateHops, hop)
+	}
+
+	pathID, err := r.server.onionMessenger.SendMessage(sendReq)
+	switch {
+	case err == ErrPeerNotConnected:
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.SendOnionMessageResponse{
+		ReplyPathId: pathID,
+	}, nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+// that are addressed to us.
+func (r *rpcServer) SubscribeOnionMessages(
+	_ *lnrpc.SubscribeOnionMessagesRequest,
+	server lnrpc.Lightning_SubscribeOnionMessagesServer) error {
+
+	if r.server.onionMessenger == nil {
+		return errOnionMessagesDisabled
+	}
+
+	client, err := r.server.onionMessenger.SubscribeMessages()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			msg := update.(*onionmessage.ReceivedMessage)
+
+			rpcMsg := &lnrpc.OnionMessage{
+				Peer:          msg.Peer[:],
+				PathId:        msg.PathID,
+				CustomRecords: msg.CustomRecords,
+			}
+			if msg.ReplyPath != nil {
+				rpcMsg.ReplyPath = routerrpc.MarshalBlindedPath(
+					msg.ReplyPath,
+				)
+			}
+
+			if err := server.Send(rpcMsg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AddOffer creates a BOLT 12 offer that can be paid several times, each
+// payment requesting a fresh invoice from us over onion messages.
+func (r *rpcServer) AddOffer(_ context.Context,
+	req *lnrpc.AddOfferRequest) (*lnrpc.AddOfferResponse, error) {
+
+	if r.server.offersMgr == nil {
+		return nil, errOnionMessagesDisabled
+	}
+
+	switch {
+	case req.AmountMsat < 0:
+		return nil, errors.New("amount cannot be negative")
+
+	case req.AbsoluteExpiry < 0:
+		return nil, errors.New("absolute expiry cannot be negative")
+
+	case req.QuantityMax != 0 && !req.AllowQuantity:
+		return nil, errors.New("quantity_max requires allow_quantity")
+	}
+
+	params := &offers.OfferParams{
+		Amount:      lnwire.MilliSatoshi(req.AmountMsat),
+		Description: req.Description,
+		Issuer:      req.Issuer,
+	}
+	if req.AllowQuantity {
+		quantityMax := req.QuantityMax
+		params.QuantityMax = &quantityMax
+	}
+	if req.AbsoluteExpiry != 0 {
+		params.AbsoluteExpiry = time.Unix(req.AbsoluteExpiry, 0)
+	}
+
+	offer, err := r.server.offersMgr.CreateOffer(params)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := offer.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.AddOfferResponse{
+		Offer: encoded,
+	}, nil
+}
+
+// DecodeOffer decodes a BOLT 12 offer.
+func (r *rpcServer) DecodeOffer(_ context.Context,
+	req *lnrpc.DecodeOfferRequest) (*lnrpc.Offer, error) {
+
+	offer, err := bolt12.DecodeOffer(req.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcOffer := &lnrpc.Offer{
+		Metadata:    offer.Metadata,
+		AmountMsat:  int64(offer.Amount),
+		Description: offer.Description,
+		Issuer:      offer.Issuer,
+	}
+
+	for _, chain := range offer.Chains {
+		rpcOffer.Chains = append(rpcOffer.Chains, chain.String())
+	}
+
+	if offer.Features != nil {
+		rpcOffer.Features = invoicesrpc.CreateRPCFeatures(
+			lnwire.NewFeatureVector(
+				offer.Features, lnwire.Features,
+			),
+		)
+	}
+
+	if !offer.AbsoluteExpiry.IsZero() {
+		rpcOffer.AbsoluteExpiry = offer.AbsoluteExpiry.Unix()
+	}
+
+	for _, path := range offer.Paths {
+		rpcOffer.Paths = append(
+			rpcOffer.Paths, routerrpc.MarshalBlindedPath(path),
+		)
+	}
+
+	if offer.QuantityMax != nil {
+		rpcOffer.AllowQuantity = true
+		rpcOffer.QuantityMax = *offer.QuantityMax
+	}
+
+	if offer.IssuerID != nil {
+		rpcOffer.IssuerId = hex.EncodeToString(
+			offer.IssuerID.SerializeCompressed(),
+		)
+	}
+
+	return rpcOffer, nil
+}
+
 // ListAliases returns the set of all aliases we have ever allocated along with
 // their base SCID's and possibly a separate confirmed SCID in the case of
 // zero-conf.
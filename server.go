@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -29,6 +30,7 @@ import (
 	"github.com/ltcsuite/lnd/clock"
 	"github.com/ltcsuite/lnd/contractcourt"
 	"github.com/ltcsuite/lnd/discovery"
+	"github.com/ltcsuite/lnd/electrum"
 	"github.com/ltcsuite/lnd/feature"
 	"github.com/ltcsuite/lnd/funding"
 	"github.com/ltcsuite/lnd/healthcheck"
@@ -42,13 +44,18 @@ import (
 	"github.com/ltcsuite/lnd/lnencrypt"
 	"github.com/ltcsuite/lnd/lnpeer"
 	"github.com/ltcsuite/lnd/lnrpc"
+	"github.com/ltcsuite/lnd/lnrpc/invoicesrpc"
 	"github.com/ltcsuite/lnd/lnrpc/routerrpc"
+	"github.com/ltcsuite/lnd/lntypes"
 	"github.com/ltcsuite/lnd/lnwallet"
 	"github.com/ltcsuite/lnd/lnwallet/chainfee"
 	"github.com/ltcsuite/lnd/lnwallet/rpcwallet"
 	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/lnd/monitoring"
 	"github.com/ltcsuite/lnd/nat"
 	"github.com/ltcsuite/lnd/netann"
+	"github.com/ltcsuite/lnd/offers"
+	"github.com/ltcsuite/lnd/onionmessage"
 	"github.com/ltcsuite/lnd/peer"
 	"github.com/ltcsuite/lnd/peernotifier"
 	"github.com/ltcsuite/lnd/pool"
@@ -65,13 +72,16 @@ import (
 	"github.com/ltcsuite/lnd/watchtower/wtclient"
 	"github.com/ltcsuite/lnd/watchtower/wtpolicy"
 	"github.com/ltcsuite/lnd/watchtower/wtserver"
+	"github.com/ltcsuite/lnd/zpay32"
 	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/connmgr"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/ltcsuite/ltcd/wire"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -242,6 +252,10 @@ type server struct {
 	// channel DB that haven't been separated out yet.
 	miscDB *channeldb.DB
 
+	// paymentDB is the DB that stores the payments we send and their HTLC
+	// attempts.
+	paymentDB channeldb.PaymentStore
+
 	aliasMgr *aliasmgr.Manager
 
 	htlcSwitch *htlcswitch.Switch
@@ -282,6 +296,8 @@ type server struct {
 
 	anchorTowerClient wtclient.Client
 
+	taprootTowerClient wtclient.Client
+
 	connMgr *connmgr.ConnManager
 
 	sigPool *lnwallet.SigPool
@@ -321,6 +337,15 @@ type server struct {
 
 	customMessageServer *subscribe.Server
 
+	// onionMessenger relays, sends and receives onion messages. It is nil
+	// if onion messages are disabled.
+	onionMessenger *onionmessage.Messenger
+
+	// offersMgr creates BOLT 12 offers, answers invoice requests for them
+	// and fetches invoices for the offers that we pay. It is nil if onion
+	// messages are disabled.
+	offersMgr *offers.Manager
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -540,6 +565,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoAnySegwit:              cfg.ProtocolOptions.NoAnySegwit(),
 		CustomFeatures:           cfg.ProtocolOptions.ExperimentalProtocol.CustomFeatures(),
 		NoTaprootChans:           !cfg.ProtocolOptions.TaprootChans,
+		NoRouteBlinding:          cfg.ProtocolOptions.NoRouteBlinding(),
+		NoOnionMessages:          cfg.ProtocolOptions.NoOnionMessages(),
+		NoSplicing:               !cfg.ProtocolOptions.Splicing(),
+		NoDualFunding:            !cfg.ProtocolOptions.DualFunding(),
+		NoQuiescence:             !cfg.ProtocolOptions.DynamicCommitments(),
+		NoDynamicCommitments:     !cfg.ProtocolOptions.DynamicCommitments(),
 	})
 	if err != nil {
 		return nil, err
@@ -562,6 +593,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		chanStateDB:    dbs.ChanStateDB.ChannelStateDB(),
 		addrSource:     dbs.ChanStateDB,
 		miscDB:         dbs.ChanStateDB,
+		paymentDB:      dbs.PaymentDB,
 		cc:             cc,
 		sigPool:        lnwallet.NewSigPool(cfg.Workers.Sig, cc.Signer),
 		writePool:      writePool,
@@ -631,6 +663,49 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	if !cfg.ProtocolOptions.NoOnionMessages() {
+		s.onionMessenger = onionmessage.New(&onionmessage.Config{
+			NodeKey:          nodeKeyECDH,
+			ChainParams:      cfg.ActiveNetParams.Params,
+			SendToPeer:       s.sendOnionMessage,
+			FetchChannelPeer: s.fetchChannelPeer,
+			PeerRateLimit: rate.Limit(
+				cfg.OnionMessages.PeerRateLimit,
+			),
+			PeerBurst: cfg.OnionMessages.PeerBurst,
+		})
+
+		// The metadata of our offers is authenticated with a key that
+		// only we can derive from our node key, so that we recognize
+		// them across restarts without storing them.
+		sharedSecret, err := nodeKeyECDH.ECDH(nodeKeyDesc.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		metadataKey := sha256.Sum256(append(
+			[]byte("bolt12 offer metadata"), sharedSecret[:]...,
+		))
+
+		s.offersMgr = offers.NewManager(&offers.Config{
+			ChainParams: cfg.ActiveNetParams.Params,
+			NodeKey:     nodeKeyDesc.PubKey,
+			MetadataKey: metadataKey,
+			SignMessage: func(msg []byte) (*schnorr.Signature,
+				error) {
+
+				return cc.KeyRing.SignMessageSchnorr(
+					nodeKeyDesc.KeyLocator, msg, false, nil,
+				)
+			},
+			AddInvoice:        s.addOfferInvoice,
+			SendMessage:       s.onionMessenger.SendMessage,
+			SubscribeMessages: s.onionMessenger.SubscribeMessages,
+			FindPath:          s.findOnionMessagePath,
+			InvoiceExpiry:     offers.DefaultInvoiceExpiry,
+			Clock:             clock.NewDefaultClock(),
+		})
+	}
+
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
 		DB:                   dbs.ChanStateDB,
 		FetchAllOpenChannels: s.chanStateDB.FetchAllOpenChannels,
@@ -651,6 +726,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			peer.HandleLocalCloseChanReqs(request)
 		},
 		FwdingLog:              dbs.ChanStateDB.ForwardingLog(),
+		FetchNodeAlias:         s.fetchNodeAlias,
 		SwitchPackager:         channeldb.NewSwitchPackager(),
 		ExtractErrorEncrypter:  s.sphinx.ExtractErrorEncrypter,
 		FetchLastChannelUpdate: s.fetchLastChanUpdate(),
@@ -663,6 +739,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RejectHTLC:             cfg.RejectHTLC,
 		Clock:                  clock.NewDefaultClock(),
 		MailboxDeliveryTimeout: cfg.Htlcswitch.MailboxDeliveryTimeout,
+		LogFailedForwards:      cfg.Htlcswitch.LogFailedForwards,
 		DustThreshold:          thresholdMSats,
 		SignAliasUpdate:        s.signAliasUpdate,
 		IsAlias:                aliasmgr.IsAlias,
@@ -952,9 +1029,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PathFindingConfig: pathFindingConfig,
 	}
 
-	paymentControl := channeldb.NewPaymentControl(dbs.ChanStateDB)
-
-	s.controlTower = routing.NewControlTower(paymentControl)
+	s.controlTower = routing.NewControlTower(dbs.PaymentDB)
 
 	strictPruning := (cfg.Litecoin.Node == "neutrino" ||
 		cfg.Routing.StrictZombiePruning)
@@ -1081,6 +1156,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PublishTransaction:  cc.Wallet.PublishTransaction,
 		Store:               utxnStore,
 		SweepInput:          s.sweeper.SweepInput,
+		Budget:              *cfg.Sweeper.Budget,
 	})
 
 	// Construct a closure that wraps the htlcswitch's CloseLink method.
@@ -1211,6 +1287,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		OnionProcessor:                s.sphinx,
 		PaymentsExpirationGracePeriod: cfg.PaymentsExpirationGracePeriod,
 		IsForwardedHTLC:               s.htlcSwitch.IsForwardedHTLC,
+		QueryIncomingCircuit:          s.htlcSwitch.QueryIncomingCircuit,
+		ForwardingTimeLockDelta:       cfg.Litecoin.TimeLockDelta,
+		Budget:                        *cfg.Sweeper.Budget,
 		Clock:                         clock.NewDefaultClock(),
 		SubscribeBreachComplete:       s.breachArbiter.SubscribeBreachComplete,
 		PutFinalHtlcOutcome:           s.chanStateDB.PutOnchainFinalHtlcOutcome, //nolint: lll
@@ -1541,7 +1620,14 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 				return nil, 0, err
 			}
 
-			return br, channel.ChanType, nil
+			// The revoked state may predate an upgrade of the
+			// commitment type, so we return the type it was
+			// created with.
+			chanType := channel.ChanTypeAtHeight(
+				false, commitHeight,
+			)
+
+			return br, chanType, nil
 		}
 
 		fetchClosedChannel := s.chanStateDB.FetchClosedChannelForID
@@ -1605,6 +1691,39 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		if err != nil {
 			return nil, err
 		}
+
+		// Copy the policy for legacy channels and set the blob flag
+		// signalling support for simple taproot channels.
+		taprootPolicy := policy
+		taprootPolicy.TxPolicy.BlobType |=
+			blob.Type(blob.FlagTaprootChannel)
+
+		s.taprootTowerClient, err = wtclient.New(&wtclient.Config{
+			FetchClosedChannel:     fetchClosedChannel,
+			BuildBreachRetribution: buildBreachRetribution,
+			SessionCloseRange:      cfg.WtClient.SessionCloseRange,
+			ChainNotifier:          s.cc.ChainNotifier,
+			SubscribeChannelEvents: func() (subscribe.Subscription,
+				error) {
+
+				return s.channelNotifier.
+					SubscribeChannelEvents()
+			},
+			Signer:             cc.Wallet.Cfg.Signer,
+			NewAddress:         newSweepPkScriptGen(cc.Wallet),
+			SecretKeyRing:      s.cc.KeyRing,
+			Dial:               cfg.net.Dial,
+			AuthDial:           authDial,
+			DB:                 dbs.TowerClientDB,
+			Policy:             taprootPolicy,
+			ChainHash:          *s.cfg.ActiveNetParams.GenesisHash,
+			MinBackoff:         10 * time.Second,
+			MaxBackoff:         5 * time.Minute,
+			MaxTasksInMemQueue: cfg.WtClient.MaxTasksInMemQueue,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if len(cfg.ExternalHosts) != 0 {
@@ -1679,6 +1798,8 @@ func (s *server) signAliasUpdate(u *lnwire.ChannelUpdate) (*ecdsa.Signature,
 //   - diskCheck
 //   - tlsHealthCheck
 //   - torController, only created when tor is enabled.
+//   - electrumTipCheck, electrumLatencyCheck and mwebPeersCheck, only
+//     created when the Electrum backend is used.
 //
 // If a health check has been disabled by setting attempts to 0, our monitor
 // will not run it.
@@ -1772,6 +1893,15 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 		checks = append(checks, torConnectionCheck)
 	}
 
+	// If the Electrum backend is used, add the healthchecks for the
+	// freshness of the tip it serves, the latency of its servers and the
+	// MWEB P2P peers it fetches blocks from.
+	if chainClient, ok := cc.ChainSource.(*electrum.ChainClient); ok {
+		checks = append(checks, s.electrumHealthChecks(
+			cfg, chainClient,
+		)...)
+	}
+
 	// If remote signing is enabled, add the healthcheck for the remote
 	// signing RPC interface.
 	if s.cfg.RemoteSigner != nil && s.cfg.RemoteSigner.Enable {
@@ -1808,6 +1938,58 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 	)
 }
 
+// electrumHealthChecks creates the health checks that are specific to the
+// Electrum backend.
+func (s *server) electrumHealthChecks(cfg *Config,
+	chainClient *electrum.ChainClient) []*healthcheck.Observation {
+
+	// Blocks aren't mined at regular intervals on the test networks that
+	// are mined on demand, so the tip can't be expected to keep up with
+	// the clock there.
+	tipAttempts := cfg.HealthChecks.ElectrumTip.Attempts
+	if cfg.Litecoin.RegTest || cfg.Litecoin.SimNet {
+		srvrLog.Info("Disabling electrum tip check for regtest and " +
+			"simnet")
+
+		tipAttempts = 0
+	}
+
+	tipCheck := healthcheck.NewObservation(
+		"electrum tip",
+		chainClient.TipStalenessCheck(
+			cfg.HealthChecks.ElectrumTip.MaxBlocks,
+		),
+		cfg.HealthChecks.ElectrumTip.Interval,
+		cfg.HealthChecks.ElectrumTip.Timeout,
+		cfg.HealthChecks.ElectrumTip.Backoff,
+		tipAttempts,
+	)
+
+	latencyCheck := healthcheck.NewObservation(
+		"electrum latency",
+		chainClient.Client().LatencyCheck(
+			cfg.HealthChecks.ElectrumLatency.MaxLatency,
+		),
+		cfg.HealthChecks.ElectrumLatency.Interval,
+		cfg.HealthChecks.ElectrumLatency.Timeout,
+		cfg.HealthChecks.ElectrumLatency.Backoff,
+		cfg.HealthChecks.ElectrumLatency.Attempts,
+	)
+
+	mwebPeersCheck := healthcheck.NewObservation(
+		"mweb peers",
+		chainClient.MwebPeerCheck(cfg.HealthChecks.MwebPeers.MinPeers),
+		cfg.HealthChecks.MwebPeers.Interval,
+		cfg.HealthChecks.MwebPeers.Timeout,
+		cfg.HealthChecks.MwebPeers.Backoff,
+		cfg.HealthChecks.MwebPeers.Attempts,
+	)
+
+	return []*healthcheck.Observation{
+		tipCheck, latencyCheck, mwebPeersCheck,
+	}
+}
+
 // Started returns true if the server has been started, and false otherwise.
 // NOTE: This function is safe for concurrent access.
 func (s *server) Started() bool {
@@ -1853,6 +2035,20 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.customMessageServer.Stop)
 
+		if s.onionMessenger != nil {
+			if err := s.onionMessenger.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.onionMessenger.Stop)
+
+			if err := s.offersMgr.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.offersMgr.Stop)
+		}
+
 		if s.hostAnn != nil {
 			if err := s.hostAnn.Start(); err != nil {
 				startErr = err
@@ -1931,6 +2127,13 @@ func (s *server) Start() error {
 			}
 			cleanup = cleanup.add(s.anchorTowerClient.Stop)
 		}
+		if s.taprootTowerClient != nil {
+			if err := s.taprootTowerClient.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.taprootTowerClient.Stop)
+		}
 
 		if err := s.sweeper.Start(); err != nil {
 			startErr = err
@@ -2306,6 +2509,13 @@ func (s *server) Stop() error {
 					"tower client: %v", err)
 			}
 		}
+		if s.taprootTowerClient != nil {
+			err := s.taprootTowerClient.Stop()
+			if err != nil {
+				srvrLog.Warnf("Unable to shut down taproot "+
+					"tower client: %v", err)
+			}
+		}
 
 		if s.hostAnn != nil {
 			if err := s.hostAnn.Stop(); err != nil {
@@ -2321,6 +2531,20 @@ func (s *server) Stop() error {
 			}
 		}
 
+		if s.offersMgr != nil {
+			if err := s.offersMgr.Stop(); err != nil {
+				srvrLog.Warnf("unable to stop offers "+
+					"manager: %v", err)
+			}
+		}
+
+		if s.onionMessenger != nil {
+			if err := s.onionMessenger.Stop(); err != nil {
+				srvrLog.Warnf("unable to stop onion "+
+					"messenger: %v", err)
+			}
+		}
+
 		// Wait for all lingering goroutines to quit.
 		s.wg.Wait()
 
@@ -3826,6 +4050,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		HtlcNotifier:            s.htlcNotifier,
 		TowerClient:             s.towerClient,
 		AnchorTowerClient:       s.anchorTowerClient,
+		TaprootTowerClient:      s.taprootTowerClient,
 		DisconnectPeer:          s.DisconnectPeer,
 		GenNodeAnnouncement: func(...netann.NodeAnnModifier) (
 			lnwire.NodeAnnouncement, error) {
@@ -3841,6 +4066,8 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 
 		FundingManager: s.fundingMgr,
 
+		AnnounceSplicedChannel: s.fundingMgr.AnnounceSplicedChannel,
+
 		Hodl:                    s.cfg.Hodl,
 		UnsafeReplay:            s.cfg.UnsafeReplay,
 		MaxOutgoingCltvExpiry:   s.cfg.MaxOutgoingCltvExpiry,
@@ -3861,6 +4088,10 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 	copy(pCfg.PubKeyBytes[:], peerAddr.IdentityKey.SerializeCompressed())
 	copy(pCfg.ServerPubKey[:], s.identityECDH.PubKey().SerializeCompressed())
 
+	if s.onionMessenger != nil {
+		pCfg.HandleOnionMessage = s.onionMessenger.HandleMessage
+	}
+
 	p := peer.NewBrontide(pCfg)
 
 	// TODO(roasbeef): update IP address for link-node
@@ -4013,6 +4244,11 @@ func (s *server) peerTerminationWatcher(p *peer.Brontide, ready chan struct{}) {
 	// so we don't need to maintain sync state for it any longer.
 	s.authGossiper.PruneSyncState(p.PubKey())
 
+	// The peer's onion message rate limit no longer needs to be tracked.
+	if s.onionMessenger != nil {
+		s.onionMessenger.RemovePeer(p.PubKey())
+	}
+
 	// Tell the switch to remove all links associated with this peer.
 	// Passing nil as the target link indicates that all links associated
 	// with this interface should be closed.
@@ -4585,6 +4821,53 @@ func (s *server) fetchNodeAdvertisedAddrs(pub *btcec.PublicKey) ([]net.Addr, err
 	return node.Addresses, nil
 }
 
+// nodeMetricsConfig returns the subsystems of the server that the node
+// metrics are gathered from.
+func (s *server) nodeMetricsConfig() *monitoring.NodeMetricsConfig {
+	towerClients := make(map[string]wtclient.Client)
+	if s.towerClient != nil {
+		towerClients["legacy"] = s.towerClient
+	}
+	if s.anchorTowerClient != nil {
+		towerClients["anchor"] = s.anchorTowerClient
+	}
+	if s.taprootTowerClient != nil {
+		towerClients["taproot"] = s.taprootTowerClient
+	}
+
+	chanDB := s.chanStateDB
+	chanNotifier := s.channelNotifier
+
+	return &monitoring.NodeMetricsConfig{
+		FetchOpenChannels:         chanDB.FetchAllOpenChannels,
+		FetchPendingChannels:      chanDB.FetchPendingChannels,
+		FetchWaitingCloseChannels: chanDB.FetchWaitingCloseChannels,
+		IsChannelActive:           s.htlcSwitch.HasActiveLink,
+		SubscribeChannelEvents:    chanNotifier.SubscribeChannelEvents,
+		SubscribeHtlcEvents:       s.htlcNotifier.SubscribeHtlcEvents,
+		SubscribePayments:         s.controlTower.SubscribeAllPayments,
+		PendingSweeps:             s.sweeper.PendingInputs,
+		TowerClients:              towerClients,
+		NumPeers: func() int {
+			return len(s.Peers())
+		},
+		SubscribePeerEvents: s.peerNotifier.SubscribePeerEvents,
+		ChainIO:             s.cc.ChainIO,
+		ChainNotifier:       s.cc.ChainNotifier,
+	}
+}
+
+// fetchNodeAlias returns the alias of the node with the given public key, as
+// found in the channel graph.
+func (s *server) fetchNodeAlias(pub route.Vertex) (string, error) {
+	node, err := s.graphDB.FetchLightningNode(pub)
+	if err != nil {
+		return "", err
+	}
+
+	return node.Alias, nil
+}
+
 // fetchLastChanUpdate returns a function which is able to retrieve our latest
 // channel update for a target channel.
 func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
@@ -4665,6 +4948,186 @@ func (s *server) SendCustomMessage(peerPub [33]byte, msgType lnwire.MessageType,
 	return peer.SendMessageLazy(true, msg)
 }
 
+// sendOnionMessage sends an onion message to the connected peer with the
+// specified pubkey.
+func (s *server) sendOnionMessage(peerPub route.Vertex,
+	msg *lnwire.OnionMessage) error {
+
+	peer, err := s.FindPeerByPubStr(string(peerPub[:]))
+	if err != nil {
+		return err
+	}
+
+	// Onion messages are best effort, so we don't wait for the peer to
+	// become active and send them as low-priority.
+	return peer.SendMessageLazy(false, msg)
+}
+
+// fetchChannelPeer returns the remote party of the channel with the given
+// short channel ID, which may also be an alias of one of our channels.
+func (s *server) fetchChannelPeer(
+	scid lnwire.ShortChannelID) (route.Vertex, error) {
+
+	info, _, _, err := s.graphDB.FetchChannelEdgesByID(scid.ToUint64())
+	if err == channeldb.ErrEdgeNotFound {
+		baseScid, aliasErr := s.aliasMgr.FindBaseSCID(scid)
+		if aliasErr != nil {
+			return route.Vertex{}, err
+		}
+
+		info, _, _, err = s.graphDB.FetchChannelEdgesByID(
+			baseScid.ToUint64(),
+		)
+	}
+	if err != nil {
+		return route.Vertex{}, err
+	}
+
+	ourPub := route.NewVertex(s.identityECDH.PubKey())
+	switch ourPub {
+	case info.NodeKey1Bytes:
+		return info.NodeKey2Bytes, nil
+
+	case info.NodeKey2Bytes:
+		return info.NodeKey1Bytes, nil
+
+	default:
+		return route.Vertex{}, fmt.Errorf("channel %v is not one of "+
+			"ours", scid)
+	}
+}
+
+// maxOnionMessagePathHops is the maximum number of intermediate hops of the
+// onion message paths that we search for.
+const maxOnionMessagePathHops = 6
+
+// findOnionMessagePath returns the intermediate hops of the shortest path to
+// the given node that onion messages can be sent over, whose first hop is one
+// of our peers. No hops are needed if the node is ourselves or a peer.
+func (s *server) findOnionMessagePath(
+	dest *btcec.PublicKey) ([]*btcec.PublicKey, error) {
+
+	ourPub := route.NewVertex(s.identityECDH.PubKey())
+	isPeer := func(node route.Vertex) bool {
+		_, err := s.FindPeerByPubStr(string(node[:]))
+		return err == nil
+	}
+
+	destVertex := route.NewVertex(dest)
+	if destVertex == ourPub || isPeer(destVertex) {
+		return nil, nil
+	}
+
+	// We search backwards from the destination through the nodes that
+	// relay onion messages, until we reach one of our peers.
+	relaysMessages := func(node route.Vertex) bool {
+		features, err := s.graphDB.FetchNodeFeatures(node)
+		return err == nil &&
+			features.HasFeature(lnwire.OnionMessagesOptional)
+	}
+
+	next := map[route.Vertex]route.Vertex{destVertex: destVertex}
+	queue := []route.Vertex{destVertex}
+	for depth := 0; depth < maxOnionMessagePathHops; depth++ {
+		var nextQueue []route.Vertex
+		for _, node := range queue {
+			cb := func(c *channeldb.DirectedChannel) error {
+				other := c.OtherNode
+				if _, ok := next[other]; ok || other == ourPub ||
+					!relaysMessages(other) {
+
+					return nil
+				}
+
+				next[other] = node
+				nextQueue = append(nextQueue, other)
+
+				return nil
+			}
+
+			err := s.graphDB.ForEachNodeChannel(nil, node, cb)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, node := range nextQueue {
+			if !isPeer(node) {
+				continue
+			}
+
+			var hops []*btcec.PublicKey
+			for hop := node; hop != destVertex; hop = next[hop] {
+				pubKey, err := btcec.ParsePubKey(hop[:])
+				if err != nil {
+					return nil, err
+				}
+				hops = append(hops, pubKey)
+			}
+
+			return hops, nil
+		}
+
+		queue = nextQueue
+	}
+
+	return nil, fmt.Errorf("no onion message path to %v", destVertex)
+}
+
+// newAddInvoiceConfig returns the config that invoices are added to the
+// invoice registry with.
+func (s *server) newAddInvoiceConfig() *invoicesrpc.AddInvoiceConfig {
+	return &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		ChainParams:       s.cfg.ActiveNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		DefaultCLTVExpiry: s.cfg.Litecoin.TimeLockDelta,
+		ChanDB:            s.chanStateDB,
+		Graph:             s.graphDB,
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoice)
+		},
+		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoiceAmp)
+		},
+		GetAlias: s.aliasMgr.GetPeerAlias,
+		BestHeight: func() (uint32, error) {
+			_, height, err := s.cc.ChainIO.GetBestBlock()
+			return uint32(height), err
+		},
+	}
+}
+
+// addOfferInvoice adds an invoice for an invoice request for one of our
+// offers, and returns the blinded paths that it can be paid over.
+func (s *server) addOfferInvoice(amt lnwire.MilliSatoshi, memo string,
+	expiry time.Duration) (lntypes.Hash, []*zpay32.BlindedPaymentPath,
+	error) {
+
+	hash, invoice, err := invoicesrpc.AddInvoice(
+		context.Background(), s.newAddInvoiceConfig(),
+		&invoicesrpc.AddInvoiceData{
+			Memo:   memo,
+			Value:  amt,
+			Expiry: int64(expiry / time.Second),
+			Blind:  true,
+		},
+	)
+	if err != nil {
+		return lntypes.Hash{}, nil, err
+	}
+
+	payReq, err := zpay32.Decode(
+		string(invoice.PaymentRequest), s.cfg.ActiveNetParams.Params,
+	)
+	if err != nil {
+		return lntypes.Hash{}, nil, err
+	}
+
+	return *hash, payReq.BlindedPaymentPaths, nil
+}
+
 // newSweepPkScriptGen creates closure that generates a new public key script
 // which should be used to sweep any funds into the on-chain wallet.
 // Specifically, the script generated is a version 0, pay-to-witness-pubkey-hash
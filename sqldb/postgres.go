@@ -3,6 +3,7 @@ package sqldb
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"testing"
 	"time"
 
@@ -37,10 +38,19 @@ type PostgresConfig struct {
 	DBName             string `long:"dbname" description:"Database name to use."`
 	MaxOpenConnections int    `long:"maxconnections" description:"Max open connections to keep alive to the database server."`
 	RequireSSL         bool   `long:"requiressl" description:"Whether to require using SSL (mode: require) when connecting to the server."`
+
+	// Dsn is a complete connection string to use instead of the one
+	// built from the fields above. It allows using any connection
+	// parameter supported by the driver.
+	Dsn string
 }
 
 // DSN returns the dns to connect to the database.
 func (s *PostgresConfig) DSN(hidePassword bool) string {
+	if s.Dsn != "" {
+		return s.rawDSN(hidePassword)
+	}
+
 	var sslMode = "disable"
 	if s.RequireSSL {
 		sslMode = "require"
@@ -56,6 +66,22 @@ func (s *PostgresConfig) DSN(hidePassword bool) string {
 		s.DBName, sslMode)
 }
 
+// rawDSN returns the configured connection string. Only the password of URL
+// connection strings can be hidden, so key/value connection strings are
+// replaced by a placeholder entirely if the password should be hidden.
+func (s *PostgresConfig) rawDSN(hidePassword bool) string {
+	if !hidePassword {
+		return s.Dsn
+	}
+
+	dsnURL, err := url.Parse(s.Dsn)
+	if err != nil || dsnURL.Scheme == "" {
+		return "****"
+	}
+
+	return dsnURL.Redacted()
+}
+
 // PostgresStore is a database store implementation that uses a Postgres
 // backend.
 type PostgresStore struct {
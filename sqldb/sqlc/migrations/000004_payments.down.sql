@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS payment_htlc_attempts_first_hop_chan_id_idx;
+DROP INDEX IF EXISTS payment_htlc_attempts_payment_id_idx;
+DROP TABLE IF EXISTS payment_htlc_attempts;
+
+DROP INDEX IF EXISTS payments_created_at_idx;
+DROP INDEX IF EXISTS payments_status_idx;
+DROP INDEX IF EXISTS payments_hash_idx;
+DROP TABLE IF EXISTS payments;
@@ -0,0 +1,95 @@
+-- payments contains the information shared by all the payments sent by the
+-- node.
+CREATE TABLE IF NOT EXISTS payments (
+    -- The id of the payment. Only used to reference the payment from its
+    -- htlc attempts.
+    id INTEGER PRIMARY KEY,
+
+    -- The sequence number of the payment. Translates to the payment index
+    -- which is used to paginate the payments in order of creation. This is
+    -- kept separate from the id so that the sequence numbers of payments
+    -- migrated from the key-value store are preserved.
+    sequence_num BIGINT NOT NULL UNIQUE,
+
+    -- The payment identifier. This is the payment hash for regular payments
+    -- and the set id for AMP payments.
+    hash BLOB NOT NULL UNIQUE,
+
+    -- The amount of the payment in millisatoshis.
+    amount_msat BIGINT NOT NULL,
+
+    -- The encoded payment request of the payment, if any.
+    payment_request BLOB,
+
+    -- The payment status. This is derived from the state of the htlc
+    -- attempts and the failure reason, but stored so that payments can be
+    -- efficiently filtered by their status.
+    status SMALLINT NOT NULL,
+
+    -- The reason the payment failed. Only set once we've given up on the
+    -- payment altogether.
+    fail_reason SMALLINT,
+
+    -- Timestamp of when this payment was created.
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS payments_hash_idx ON payments(hash);
+CREATE INDEX IF NOT EXISTS payments_status_idx ON payments(status);
+CREATE INDEX IF NOT EXISTS payments_created_at_idx ON payments(created_at);
+
+-- payment_htlc_attempts contains the htlcs that were sent out in an attempt to
+-- complete a payment.
+CREATE TABLE IF NOT EXISTS payment_htlc_attempts (
+    -- The id for this htlc attempt. Used in foreign keys instead of the
+    -- attempt_id/payment_id combination.
+    id INTEGER PRIMARY KEY,
+
+    -- The uint64 attempt id. This field is a counter so it is safe to store
+    -- it as int64 in the database.
+    attempt_id BIGINT NOT NULL,
+
+    -- The ephemeral session key used for the onion of this attempt.
+    session_key BLOB NOT NULL,
+
+    -- The serialized route this attempt was sent along.
+    route BLOB NOT NULL,
+
+    -- Short chan id of the first hop of the route. uint64 stored as text.
+    first_hop_chan_id TEXT NOT NULL,
+
+    -- The hash used for this attempt. For AMP payments this differs across
+    -- attempts. Attempts made by older versions may not have this set, in
+    -- which case the payment hash is used.
+    hash BLOB,
+
+    -- The timestamp at which this htlc was attempted.
+    attempt_time TIMESTAMP NOT NULL,
+
+    -- The preimage that settled this htlc.
+    settle_preimage BLOB,
+
+    -- The timestamp at which this htlc was settled.
+    settle_time TIMESTAMP,
+
+    -- The reason this htlc failed.
+    fail_reason SMALLINT,
+
+    -- The encoded wire message that failed this htlc, if any.
+    fail_message BLOB,
+
+    -- The position in the route of the node that generated the failure.
+    fail_source_index INTEGER,
+
+    -- The timestamp at which this htlc was failed.
+    fail_time TIMESTAMP,
+
+    -- The id of the payment this htlc attempt belongs to.
+    payment_id INTEGER NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+
+    -- The attempt_id is unique per payment.
+    UNIQUE (attempt_id, payment_id)
+);
+
+CREATE INDEX IF NOT EXISTS payment_htlc_attempts_payment_id_idx ON payment_htlc_attempts(payment_id);
+CREATE INDEX IF NOT EXISTS payment_htlc_attempts_first_hop_chan_id_idx ON payment_htlc_attempts(first_hop_chan_id);
@@ -0,0 +1,228 @@
+-- graph_nodes contains the nodes of the channel graph. Nodes we only know of
+-- through a channel announcement are stored as shell nodes which only have
+-- their public key set.
+CREATE TABLE IF NOT EXISTS graph_nodes (
+    -- The id of the node. Only used to reference the node from the other
+    -- graph tables.
+    id INTEGER PRIMARY KEY,
+
+    -- The 33 byte compressed identity public key of the node.
+    pub_key BLOB NOT NULL UNIQUE,
+
+    -- Whether we received a node announcement for this node. If not set,
+    -- the node is a shell node and none of the announcement fields below
+    -- are populated.
+    have_announcement BOOLEAN NOT NULL,
+
+    -- The unix timestamp in seconds of the last node announcement.
+    last_update BIGINT NOT NULL,
+
+    -- The color of the node as a hex string in the #rrggbb format.
+    color TEXT,
+
+    -- The alias of the node.
+    alias TEXT,
+
+    -- The signature of the last node announcement.
+    signature BLOB
+);
+
+CREATE INDEX IF NOT EXISTS graph_nodes_last_update_idx ON graph_nodes(last_update);
+
+-- graph_node_features contains the feature bits advertised by a node.
+CREATE TABLE IF NOT EXISTS graph_node_features (
+    -- The node that advertised the feature.
+    node_id INTEGER NOT NULL REFERENCES graph_nodes(id) ON DELETE CASCADE,
+
+    -- The feature bit that is set.
+    feature_bit INTEGER NOT NULL,
+
+    UNIQUE (node_id, feature_bit)
+);
+
+-- graph_node_addresses contains the network addresses advertised by a node.
+CREATE TABLE IF NOT EXISTS graph_node_addresses (
+    -- The node that advertised the address.
+    node_id INTEGER NOT NULL REFERENCES graph_nodes(id) ON DELETE CASCADE,
+
+    -- The position of the address within the node announcement.
+    position INTEGER NOT NULL,
+
+    -- The type of the address. This is one of: 0 (ipv4), 1 (ipv6),
+    -- 2 (tor v2) or 3 (tor v3).
+    type SMALLINT NOT NULL,
+
+    -- The address in the host:port format.
+    address TEXT NOT NULL,
+
+    UNIQUE (node_id, position)
+);
+
+-- graph_node_extra_types contains the TLV records found in the extra opaque
+-- data of a node announcement.
+CREATE TABLE IF NOT EXISTS graph_node_extra_types (
+    -- The node the record belongs to.
+    node_id INTEGER NOT NULL REFERENCES graph_nodes(id) ON DELETE CASCADE,
+
+    -- The uint64 TLV type, stored as int64.
+    type BIGINT NOT NULL,
+
+    -- The value of the TLV record.
+    value BLOB,
+
+    UNIQUE (node_id, type)
+);
+
+-- graph_source_nodes points to our own node within the graph.
+CREATE TABLE IF NOT EXISTS graph_source_nodes (
+    node_id INTEGER NOT NULL UNIQUE REFERENCES graph_nodes(id) ON DELETE CASCADE
+);
+
+-- graph_channels contains the static information of the channels within the
+-- graph.
+CREATE TABLE IF NOT EXISTS graph_channels (
+    -- The id of the channel. Only used to reference the channel from the
+    -- other graph tables.
+    id INTEGER PRIMARY KEY,
+
+    -- The short channel id of the channel, encoded as 8 byte big endian so
+    -- that channels can be range queried by block height. The full uint64
+    -- range is used by alias SCIDs which rules out storing it as BIGINT.
+    scid BLOB NOT NULL UNIQUE,
+
+    -- The hash of the genesis block of the chain the channel lives on.
+    chain_hash BLOB NOT NULL,
+
+    -- The funding outpoint of the channel in the txid:index format.
+    outpoint TEXT NOT NULL,
+
+    -- The capacity of the channel in satoshis.
+    capacity BIGINT NOT NULL,
+
+    -- The node with the lexicographically smaller public key.
+    node_id_1 INTEGER NOT NULL REFERENCES graph_nodes(id),
+
+    -- The node with the lexicographically larger public key.
+    node_id_2 INTEGER NOT NULL REFERENCES graph_nodes(id),
+
+    -- The funding public keys of node 1 and node 2.
+    bitcoin_key_1 BLOB NOT NULL,
+    bitcoin_key_2 BLOB NOT NULL,
+
+    -- The signatures of the channel announcement proof. These are only set
+    -- for announced channels.
+    node_1_signature BLOB,
+    node_2_signature BLOB,
+    bitcoin_1_signature BLOB,
+    bitcoin_2_signature BLOB
+);
+
+CREATE INDEX IF NOT EXISTS graph_channels_outpoint_idx ON graph_channels(outpoint);
+CREATE INDEX IF NOT EXISTS graph_channels_node_id_1_idx ON graph_channels(node_id_1);
+CREATE INDEX IF NOT EXISTS graph_channels_node_id_2_idx ON graph_channels(node_id_2);
+
+-- graph_channel_features contains the feature bits of a channel announcement.
+CREATE TABLE IF NOT EXISTS graph_channel_features (
+    -- The channel the feature belongs to.
+    channel_id INTEGER NOT NULL REFERENCES graph_channels(id) ON DELETE CASCADE,
+
+    -- The feature bit that is set.
+    feature_bit INTEGER NOT NULL,
+
+    UNIQUE (channel_id, feature_bit)
+);
+
+-- graph_channel_extra_types contains the TLV records found in the extra
+-- opaque data of a channel announcement.
+CREATE TABLE IF NOT EXISTS graph_channel_extra_types (
+    -- The channel the record belongs to.
+    channel_id INTEGER NOT NULL REFERENCES graph_channels(id) ON DELETE CASCADE,
+
+    -- The uint64 TLV type, stored as int64.
+    type BIGINT NOT NULL,
+
+    -- The value of the TLV record.
+    value BLOB,
+
+    UNIQUE (channel_id, type)
+);
+
+-- graph_channel_policies contains the routing policies of the channels within
+-- the graph. Each channel has at most one policy per direction.
+CREATE TABLE IF NOT EXISTS graph_channel_policies (
+    -- The id of the policy. Only used to reference the policy from its extra
+    -- types.
+    id INTEGER PRIMARY KEY,
+
+    -- The channel the policy belongs to.
+    channel_id INTEGER NOT NULL REFERENCES graph_channels(id) ON DELETE CASCADE,
+
+    -- The node that advertised the policy.
+    node_id INTEGER NOT NULL REFERENCES graph_nodes(id),
+
+    -- The unix timestamp in seconds of the channel update.
+    last_update BIGINT NOT NULL,
+
+    -- The message and channel flags of the channel update.
+    message_flags SMALLINT NOT NULL,
+    channel_flags SMALLINT NOT NULL,
+
+    -- The time lock delta in blocks.
+    timelock INTEGER NOT NULL,
+
+    -- The minimum htlc value in millisatoshis.
+    min_htlc_msat BIGINT NOT NULL,
+
+    -- The maximum htlc value in millisatoshis. Only set if the message flags
+    -- signal its presence.
+    max_htlc_msat BIGINT,
+
+    -- The base fee in millisatoshis.
+    base_fee_msat BIGINT NOT NULL,
+
+    -- The proportional fee in parts per million.
+    fee_ppm BIGINT NOT NULL,
+
+    -- The signature of the channel update.
+    signature BLOB NOT NULL,
+
+    UNIQUE (channel_id, node_id)
+);
+
+CREATE INDEX IF NOT EXISTS graph_channel_policies_last_update_idx ON graph_channel_policies(last_update);
+
+-- graph_channel_policy_extra_types contains the TLV records found in the
+-- extra opaque data of a channel update.
+CREATE TABLE IF NOT EXISTS graph_channel_policy_extra_types (
+    -- The policy the record belongs to.
+    channel_policy_id INTEGER NOT NULL REFERENCES graph_channel_policies(id) ON DELETE CASCADE,
+
+    -- The uint64 TLV type, stored as int64.
+    type BIGINT NOT NULL,
+
+    -- The value of the TLV record.
+    value BLOB,
+
+    UNIQUE (channel_policy_id, type)
+);
+
+-- graph_zombie_channels contains the channels that were pruned from the graph
+-- as zombies, along with the keys of the nodes that may resurrect them.
+CREATE TABLE IF NOT EXISTS graph_zombie_channels (
+    -- The short channel id of the zombie channel, 8 byte big endian.
+    scid BLOB PRIMARY KEY,
+
+    -- The public keys of the nodes that can resurrect the channel. A key of
+    -- all zeroes means that node can't resurrect the channel on its own.
+    node_key_1 BLOB NOT NULL,
+    node_key_2 BLOB NOT NULL
+);
+
+-- graph_prune_log contains the blocks that were used to prune the graph.
+CREATE TABLE IF NOT EXISTS graph_prune_log (
+    -- The height of the block.
+    block_height BIGINT PRIMARY KEY,
+
+    -- The hash of the block.
+    block_hash BLOB NOT NULL
+);
@@ -27,6 +27,97 @@ type AmpInvoicePayment struct {
 	InvoiceID    int32
 }
 
+type GraphChannel struct {
+	ID                int32
+	Scid              []byte
+	ChainHash         []byte
+	Outpoint          string
+	Capacity          int64
+	NodeID1           int32
+	NodeID2           int32
+	BitcoinKey1       []byte
+	BitcoinKey2       []byte
+	Node1Signature    []byte
+	Node2Signature    []byte
+	Bitcoin1Signature []byte
+	Bitcoin2Signature []byte
+}
+
+type GraphChannelExtraType struct {
+	ChannelID int32
+	Type      int64
+	Value     []byte
+}
+
+type GraphChannelFeature struct {
+	ChannelID  int32
+	FeatureBit int32
+}
+
+type GraphChannelPolicy struct {
+	ID           int32
+	ChannelID    int32
+	NodeID       int32
+	LastUpdate   int64
+	MessageFlags int16
+	ChannelFlags int16
+	Timelock     int32
+	MinHtlcMsat  int64
+	MaxHtlcMsat  sql.NullInt64
+	BaseFeeMsat  int64
+	FeePpm       int64
+	Signature    []byte
+}
+
+type GraphChannelPolicyExtraType struct {
+	ChannelPolicyID int32
+	Type            int64
+	Value           []byte
+}
+
+type GraphNode struct {
+	ID               int32
+	PubKey           []byte
+	HaveAnnouncement bool
+	LastUpdate       int64
+	Color            sql.NullString
+	Alias            sql.NullString
+	Signature        []byte
+}
+
+type GraphNodeAddress struct {
+	NodeID   int32
+	Position int32
+	Type     int16
+	Address  string
+}
+
+type GraphNodeExtraType struct {
+	NodeID int32
+	Type   int64
+	Value  []byte
+}
+
+type GraphNodeFeature struct {
+	NodeID     int32
+	FeatureBit int32
+}
+
+type GraphPruneLog struct {
+	BlockHeight int64
+	BlockHash   []byte
+}
+
+type GraphSourceNode struct {
+	NodeID int32
+}
+
+type GraphZombieChannel struct {
+	Scid     []byte
+	NodeKey1 []byte
+	NodeKey2 []byte
+}
+
 type Invoice struct {
 	ID             int32
 	Hash           []byte
@@ -91,3 +182,31 @@ type InvoicePayment struct {
 	AmountPaidMsat int64
 	InvoiceID      int32
 }
+
+type Payment struct {
+	ID             int32
+	SequenceNum    int64
+	Hash           []byte
+	AmountMsat     int64
+	PaymentRequest []byte
+	Status         int16
+	FailReason     sql.NullInt16
+	CreatedAt      time.Time
+}
+
+type PaymentHtlcAttempt struct {
+	ID              int32
+	AttemptID       int64
+	SessionKey      []byte
+	Route           []byte
+	FirstHopChanID  string
+	Hash            []byte
+	AttemptTime     time.Time
+	SettlePreimage  []byte
+	SettleTime      sql.NullTime
+	FailReason      sql.NullInt16
+	FailMessage     []byte
+	FailSourceIndex sql.NullInt32
+	FailTime        sql.NullTime
+	PaymentID       int32
+}
@@ -9,19 +9,54 @@ import (
 )
 
 type Querier interface {
+	CountNodes(ctx context.Context) (int64, error)
+	CountPayments(ctx context.Context) (int64, error)
+	CountZombieChannels(ctx context.Context) (int64, error)
 	DeleteAMPHTLCCustomRecords(ctx context.Context, invoiceID int32) error
 	DeleteAMPHTLCs(ctx context.Context, invoiceID int32) error
 	DeleteAMPInvoiceHTLC(ctx context.Context, setID []byte) error
+	DeleteAllChannels(ctx context.Context) error
+	DeleteAllNodes(ctx context.Context) error
+	DeleteAllZombieChannels(ctx context.Context) error
+	DeleteChannel(ctx context.Context, id int32) error
+	DeleteChannelExtraTypes(ctx context.Context, channelID int32) error
+	DeleteChannelFeatures(ctx context.Context, channelID int32) error
+	DeleteChannelPolicyExtraTypes(ctx context.Context, channelPolicyID int32) error
+	DeleteFailedHTLCAttemptsByPaymentStatus(ctx context.Context, status int16) error
+	DeleteFailedPaymentHTLCAttempts(ctx context.Context, paymentID int32) error
 	DeleteInvoice(ctx context.Context, arg DeleteInvoiceParams) error
 	DeleteInvoiceEvents(ctx context.Context, invoiceID int32) error
 	DeleteInvoiceFeatures(ctx context.Context, invoiceID int32) error
 	DeleteInvoiceHTLC(ctx context.Context, htlcID int64) error
 	DeleteInvoiceHTLCCustomRecords(ctx context.Context, invoiceID int32) error
 	DeleteInvoiceHTLCs(ctx context.Context, invoiceID int32) error
+	DeleteNodeAddresses(ctx context.Context, nodeID int32) error
+	DeleteNodeByPubKey(ctx context.Context, pubKey []byte) (int64, error)
+	DeleteNodeExtraTypes(ctx context.Context, nodeID int32) error
+	DeleteNodeFeatures(ctx context.Context, nodeID int32) error
+	DeletePayment(ctx context.Context, id int32) error
+	DeletePaymentsByStatus(ctx context.Context, status int16) error
+	DeletePruneLogEntriesFrom(ctx context.Context, blockHeight int64) error
+	DeleteSourceNodes(ctx context.Context) error
+	DeleteUnconnectedNodes(ctx context.Context) ([][]byte, error)
+	DeleteZombieChannel(ctx context.Context, scid []byte) error
+	FailPaymentHTLCAttempt(ctx context.Context, arg FailPaymentHTLCAttemptParams) error
+	FetchPaymentsByStatus(ctx context.Context, status int16) ([]Payment, error)
 	FilterInvoicePayments(ctx context.Context, arg FilterInvoicePaymentsParams) ([]FilterInvoicePaymentsRow, error)
 	FilterInvoices(ctx context.Context, arg FilterInvoicesParams) ([]Invoice, error)
+	FilterPayments(ctx context.Context, arg FilterPaymentsParams) ([]Payment, error)
 	GetAMPInvoiceHTLCsByInvoiceID(ctx context.Context, invoiceID int32) ([]AmpInvoiceHtlc, error)
 	GetAMPInvoiceHTLCsBySetID(ctx context.Context, setID []byte) ([]AmpInvoiceHtlc, error)
+	GetChannelByOutpoint(ctx context.Context, outpoint string) (GraphChannel, error)
+	GetChannelBySCID(ctx context.Context, scid []byte) (GraphChannel, error)
+	GetChannelExtraTypes(ctx context.Context, channelID int32) ([]GraphChannelExtraType, error)
+	GetChannelFeatures(ctx context.Context, channelID int32) ([]int32, error)
+	GetChannelPolicy(ctx context.Context, arg GetChannelPolicyParams) (GraphChannelPolicy, error)
+	GetChannelPolicyExtraTypes(ctx context.Context, channelPolicyID int32) ([]GraphChannelPolicyExtraType, error)
+	GetChannelsByNodeID(ctx context.Context, nodeID1 int32) ([]GraphChannel, error)
+	GetChannelsBySCIDRange(ctx context.Context, arg GetChannelsBySCIDRangeParams) ([]GraphChannel, error)
+	GetDisabledSCIDs(ctx context.Context) ([][]byte, error)
+	GetHighestSCID(ctx context.Context) ([]byte, error)
 	// This method may return more than one invoice if filter using multiple fields
 	// from different invoices. It is the caller's responsibility to ensure that
 	// we bubble up an error in those cases.
@@ -30,22 +65,55 @@ type Querier interface {
 	GetInvoiceHTLCCustomRecords(ctx context.Context, invoiceID int32) ([]GetInvoiceHTLCCustomRecordsRow, error)
 	GetInvoiceHTLCs(ctx context.Context, invoiceID int32) ([]InvoiceHtlc, error)
 	GetInvoicePayments(ctx context.Context, invoiceID int32) ([]InvoicePayment, error)
+	GetMaxPaymentSequenceNum(ctx context.Context) (int64, error)
+	GetNodeAddresses(ctx context.Context, nodeID int32) ([]GetNodeAddressesRow, error)
+	GetNodeByID(ctx context.Context, id int32) (GraphNode, error)
+	GetNodeByPubKey(ctx context.Context, pubKey []byte) (GraphNode, error)
+	GetNodeExtraTypes(ctx context.Context, nodeID int32) ([]GraphNodeExtraType, error)
+	GetNodeFeatures(ctx context.Context, nodeID int32) ([]int32, error)
+	GetNodesByLastUpdateRange(ctx context.Context, arg GetNodesByLastUpdateRangeParams) ([]GraphNode, error)
+	GetPayment(ctx context.Context, hash []byte) (Payment, error)
+	GetPaymentHTLCAttempts(ctx context.Context, paymentID int32) ([]PaymentHtlcAttempt, error)
+	GetPruneTip(ctx context.Context) (GraphPruneLog, error)
+	GetSCIDsByPolicyLastUpdateRange(ctx context.Context, arg GetSCIDsByPolicyLastUpdateRangeParams) ([][]byte, error)
 	GetSetIDHTLCsCustomRecords(ctx context.Context, setID []byte) ([]GetSetIDHTLCsCustomRecordsRow, error)
+	GetSourceNodeID(ctx context.Context) (int32, error)
+	GetZombieChannel(ctx context.Context, scid []byte) (GraphZombieChannel, error)
 	InsertAMPInvoiceHTLC(ctx context.Context, arg InsertAMPInvoiceHTLCParams) error
 	InsertAMPInvoicePayment(ctx context.Context, arg InsertAMPInvoicePaymentParams) error
+	InsertChannel(ctx context.Context, arg InsertChannelParams) (int32, error)
+	InsertChannelExtraType(ctx context.Context, arg InsertChannelExtraTypeParams) error
+	InsertChannelFeature(ctx context.Context, arg InsertChannelFeatureParams) error
+	InsertChannelPolicyExtraType(ctx context.Context, arg InsertChannelPolicyExtraTypeParams) error
 	InsertInvoice(ctx context.Context, arg InsertInvoiceParams) (int32, error)
 	InsertInvoiceEvent(ctx context.Context, arg InsertInvoiceEventParams) error
 	InsertInvoiceFeature(ctx context.Context, arg InsertInvoiceFeatureParams) error
 	InsertInvoiceHTLC(ctx context.Context, arg InsertInvoiceHTLCParams) error
 	InsertInvoiceHTLCCustomRecord(ctx context.Context, arg InsertInvoiceHTLCCustomRecordParams) error
 	InsertInvoicePayment(ctx context.Context, arg InsertInvoicePaymentParams) (int32, error)
+	InsertNodeAddress(ctx context.Context, arg InsertNodeAddressParams) error
+	InsertNodeExtraType(ctx context.Context, arg InsertNodeExtraTypeParams) error
+	InsertNodeFeature(ctx context.Context, arg InsertNodeFeatureParams) error
+	InsertPayment(ctx context.Context, arg InsertPaymentParams) (int32, error)
+	InsertPaymentHTLCAttempt(ctx context.Context, arg InsertPaymentHTLCAttemptParams) error
+	InsertShellNode(ctx context.Context, pubKey []byte) (int32, error)
+	InsertSourceNode(ctx context.Context, nodeID int32) error
+	ListChannelsPaginated(ctx context.Context, arg ListChannelsPaginatedParams) ([]GraphChannel, error)
+	ListNodesPaginated(ctx context.Context, arg ListNodesPaginatedParams) ([]GraphNode, error)
 	SelectAMPInvoicePayments(ctx context.Context, arg SelectAMPInvoicePaymentsParams) ([]SelectAMPInvoicePaymentsRow, error)
 	SelectInvoiceEvents(ctx context.Context, arg SelectInvoiceEventsParams) ([]InvoiceEvent, error)
+	SettlePaymentHTLCAttempt(ctx context.Context, arg SettlePaymentHTLCAttemptParams) error
 	UpdateAMPInvoiceHTLC(ctx context.Context, arg UpdateAMPInvoiceHTLCParams) error
 	UpdateAMPPayment(ctx context.Context, arg UpdateAMPPaymentParams) error
+	UpdateChannel(ctx context.Context, arg UpdateChannelParams) error
 	UpdateInvoice(ctx context.Context, arg UpdateInvoiceParams) error
 	UpdateInvoiceHTLC(ctx context.Context, arg UpdateInvoiceHTLCParams) error
 	UpdateInvoiceHTLCs(ctx context.Context, arg UpdateInvoiceHTLCsParams) error
+	UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStatusParams) error
+	UpsertChannelPolicy(ctx context.Context, arg UpsertChannelPolicyParams) (int32, error)
+	UpsertNode(ctx context.Context, arg UpsertNodeParams) (int32, error)
+	UpsertPruneLogEntry(ctx context.Context, arg UpsertPruneLogEntryParams) error
+	UpsertZombieChannel(ctx context.Context, arg UpsertZombieChannelParams) error
 }
 
 var _ Querier = (*Queries)(nil)
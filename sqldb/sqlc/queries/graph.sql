@@ -0,0 +1,348 @@
+-- name: UpsertNode :one
+INSERT INTO graph_nodes (
+    pub_key, have_announcement, last_update, color, alias, signature
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (pub_key) DO UPDATE
+SET have_announcement = EXCLUDED.have_announcement,
+    last_update = EXCLUDED.last_update,
+    color = EXCLUDED.color,
+    alias = EXCLUDED.alias,
+    signature = EXCLUDED.signature
+RETURNING id;
+
+-- name: InsertShellNode :one
+INSERT INTO graph_nodes (
+    pub_key, have_announcement, last_update
+) VALUES (
+    $1, FALSE, 0
+) RETURNING id;
+
+-- name: GetNodeByPubKey :one
+SELECT *
+FROM graph_nodes
+WHERE pub_key = $1;
+
+-- name: GetNodeByID :one
+SELECT *
+FROM graph_nodes
+WHERE id = $1;
+
+-- name: GetNodesByLastUpdateRange :many
+SELECT *
+FROM graph_nodes
+WHERE have_announcement = TRUE AND last_update >= @start_time AND
+    last_update < @end_time
+ORDER BY last_update, pub_key;
+
+-- name: ListNodesPaginated :many
+SELECT *
+FROM graph_nodes
+WHERE id > @id_after
+ORDER BY id
+LIMIT @num_limit;
+
+-- name: CountNodes :one
+SELECT COUNT(*)
+FROM graph_nodes;
+
+-- name: DeleteNodeByPubKey :execrows
+DELETE
+FROM graph_nodes
+WHERE pub_key = $1;
+
+-- name: DeleteAllNodes :exec
+DELETE
+FROM graph_nodes;
+
+-- name: DeleteUnconnectedNodes :many
+DELETE
+FROM graph_nodes
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM graph_channels c
+    WHERE c.node_id_1 = graph_nodes.id OR c.node_id_2 = graph_nodes.id
+) AND NOT EXISTS (
+    SELECT 1
+    FROM graph_source_nodes s
+    WHERE s.node_id = graph_nodes.id
+)
+RETURNING pub_key;
+
+-- name: InsertNodeFeature :exec
+INSERT INTO graph_node_features (
+    node_id, feature_bit
+) VALUES (
+    $1, $2
+);
+
+-- name: GetNodeFeatures :many
+SELECT feature_bit
+FROM graph_node_features
+WHERE node_id = $1
+ORDER BY feature_bit;
+
+-- name: DeleteNodeFeatures :exec
+DELETE
+FROM graph_node_features
+WHERE node_id = $1;
+
+-- name: InsertNodeAddress :exec
+INSERT INTO graph_node_addresses (
+    node_id, position, type, address
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: GetNodeAddresses :many
+SELECT type, address
+FROM graph_node_addresses
+WHERE node_id = $1
+ORDER BY position;
+
+-- name: DeleteNodeAddresses :exec
+DELETE
+FROM graph_node_addresses
+WHERE node_id = $1;
+
+-- name: InsertNodeExtraType :exec
+INSERT INTO graph_node_extra_types (
+    node_id, type, value
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetNodeExtraTypes :many
+SELECT *
+FROM graph_node_extra_types
+WHERE node_id = $1
+ORDER BY type;
+
+-- name: DeleteNodeExtraTypes :exec
+DELETE
+FROM graph_node_extra_types
+WHERE node_id = $1;
+
+-- name: InsertSourceNode :exec
+INSERT INTO graph_source_nodes (
+    node_id
+) VALUES (
+    $1
+);
+
+-- name: GetSourceNodeID :one
+SELECT node_id
+FROM graph_source_nodes;
+
+-- name: DeleteSourceNodes :exec
+DELETE
+FROM graph_source_nodes;
+
+-- name: InsertChannel :one
+INSERT INTO graph_channels (
+    scid, chain_hash, outpoint, capacity, node_id_1, node_id_2,
+    bitcoin_key_1, bitcoin_key_2, node_1_signature, node_2_signature,
+    bitcoin_1_signature, bitcoin_2_signature
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id;
+
+-- name: UpdateChannel :exec
+UPDATE graph_channels
+SET chain_hash = $2, outpoint = $3, capacity = $4, node_id_1 = $5,
+    node_id_2 = $6, bitcoin_key_1 = $7, bitcoin_key_2 = $8,
+    node_1_signature = $9, node_2_signature = $10,
+    bitcoin_1_signature = $11, bitcoin_2_signature = $12
+WHERE id = $1;
+
+-- name: GetChannelBySCID :one
+SELECT *
+FROM graph_channels
+WHERE scid = $1;
+
+-- name: GetChannelByOutpoint :one
+SELECT *
+FROM graph_channels
+WHERE outpoint = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetChannelsBySCIDRange :many
+SELECT *
+FROM graph_channels
+WHERE scid >= @start_scid AND scid <= @end_scid
+ORDER BY scid;
+
+-- name: GetChannelsByNodeID :many
+SELECT *
+FROM graph_channels
+WHERE node_id_1 = $1 OR node_id_2 = $1
+ORDER BY scid;
+
+-- name: ListChannelsPaginated :many
+SELECT *
+FROM graph_channels
+WHERE id > @id_after
+ORDER BY id
+LIMIT @num_limit;
+
+-- name: GetHighestSCID :one
+SELECT scid
+FROM graph_channels
+ORDER BY scid DESC
+LIMIT 1;
+
+-- name: DeleteChannel :exec
+DELETE
+FROM graph_channels
+WHERE id = $1;
+
+-- name: DeleteAllChannels :exec
+DELETE
+FROM graph_channels;
+
+-- name: InsertChannelFeature :exec
+INSERT INTO graph_channel_features (
+    channel_id, feature_bit
+) VALUES (
+    $1, $2
+);
+
+-- name: GetChannelFeatures :many
+SELECT feature_bit
+FROM graph_channel_features
+WHERE channel_id = $1
+ORDER BY feature_bit;
+
+-- name: DeleteChannelFeatures :exec
+DELETE
+FROM graph_channel_features
+WHERE channel_id = $1;
+
+-- name: InsertChannelExtraType :exec
+INSERT INTO graph_channel_extra_types (
+    channel_id, type, value
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetChannelExtraTypes :many
+SELECT *
+FROM graph_channel_extra_types
+WHERE channel_id = $1
+ORDER BY type;
+
+-- name: DeleteChannelExtraTypes :exec
+DELETE
+FROM graph_channel_extra_types
+WHERE channel_id = $1;
+
+-- name: UpsertChannelPolicy :one
+INSERT INTO graph_channel_policies (
+    channel_id, node_id, last_update, message_flags, channel_flags,
+    timelock, min_htlc_msat, max_htlc_msat, base_fee_msat, fee_ppm,
+    signature
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+ON CONFLICT (channel_id, node_id) DO UPDATE
+SET last_update = EXCLUDED.last_update,
+    message_flags = EXCLUDED.message_flags,
+    channel_flags = EXCLUDED.channel_flags,
+    timelock = EXCLUDED.timelock,
+    min_htlc_msat = EXCLUDED.min_htlc_msat,
+    max_htlc_msat = EXCLUDED.max_htlc_msat,
+    base_fee_msat = EXCLUDED.base_fee_msat,
+    fee_ppm = EXCLUDED.fee_ppm,
+    signature = EXCLUDED.signature
+RETURNING id;
+
+-- name: GetChannelPolicy :one
+SELECT *
+FROM graph_channel_policies
+WHERE channel_id = $1 AND node_id = $2;
+
+-- name: GetSCIDsByPolicyLastUpdateRange :many
+SELECT c.scid
+FROM graph_channels c
+JOIN graph_channel_policies p ON p.channel_id = c.id
+WHERE p.last_update >= @start_time AND p.last_update < @end_time
+GROUP BY c.scid
+ORDER BY MIN(p.last_update), c.scid;
+
+-- name: GetDisabledSCIDs :many
+SELECT c.scid
+FROM graph_channels c
+JOIN graph_channel_policies p1
+    ON p1.channel_id = c.id AND p1.node_id = c.node_id_1
+JOIN graph_channel_policies p2
+    ON p2.channel_id = c.id AND p2.node_id = c.node_id_2
+WHERE (p1.channel_flags & 2) != 0 AND (p2.channel_flags & 2) != 0
+ORDER BY c.scid;
+
+-- name: InsertChannelPolicyExtraType :exec
+INSERT INTO graph_channel_policy_extra_types (
+    channel_policy_id, type, value
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetChannelPolicyExtraTypes :many
+SELECT *
+FROM graph_channel_policy_extra_types
+WHERE channel_policy_id = $1
+ORDER BY type;
+
+-- name: DeleteChannelPolicyExtraTypes :exec
+DELETE
+FROM graph_channel_policy_extra_types
+WHERE channel_policy_id = $1;
+
+-- name: UpsertZombieChannel :exec
+INSERT INTO graph_zombie_channels (
+    scid, node_key_1, node_key_2
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (scid) DO UPDATE
+SET node_key_1 = EXCLUDED.node_key_1,
+    node_key_2 = EXCLUDED.node_key_2;
+
+-- name: GetZombieChannel :one
+SELECT *
+FROM graph_zombie_channels
+WHERE scid = $1;
+
+-- name: DeleteZombieChannel :exec
+DELETE
+FROM graph_zombie_channels
+WHERE scid = $1;
+
+-- name: DeleteAllZombieChannels :exec
+DELETE
+FROM graph_zombie_channels;
+
+-- name: CountZombieChannels :one
+SELECT COUNT(*)
+FROM graph_zombie_channels;
+
+-- name: UpsertPruneLogEntry :exec
+INSERT INTO graph_prune_log (
+    block_height, block_hash
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (block_height) DO UPDATE
+SET block_hash = EXCLUDED.block_hash;
+
+-- name: GetPruneTip :one
+SELECT *
+FROM graph_prune_log
+ORDER BY block_height DESC
+LIMIT 1;
+
+-- name: DeletePruneLogEntriesFrom :exec
+DELETE
+FROM graph_prune_log
+WHERE block_height >= $1;
@@ -0,0 +1,110 @@
+-- name: InsertPayment :one
+INSERT INTO payments (
+    sequence_num, hash, amount_msat, payment_request, status, fail_reason,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id;
+
+-- name: GetPayment :one
+SELECT *
+FROM payments
+WHERE hash = $1;
+
+-- name: GetMaxPaymentSequenceNum :one
+SELECT CAST(COALESCE(MAX(sequence_num), 0) AS BIGINT) AS max_sequence_num
+FROM payments;
+
+-- name: CountPayments :one
+SELECT COUNT(*)
+FROM payments;
+
+-- name: FetchPaymentsByStatus :many
+SELECT *
+FROM payments
+WHERE status = $1
+ORDER BY sequence_num;
+
+-- name: FilterPayments :many
+SELECT *
+FROM payments
+WHERE (
+    sequence_num > sqlc.narg('sequence_num_after') OR
+    sqlc.narg('sequence_num_after') IS NULL
+) AND (
+    sequence_num < sqlc.narg('sequence_num_before') OR
+    sqlc.narg('sequence_num_before') IS NULL
+) AND (
+    status = sqlc.narg('status') OR
+    sqlc.narg('status') IS NULL
+) AND (
+    created_at >= sqlc.narg('created_after') OR
+    sqlc.narg('created_after') IS NULL
+) AND (
+    created_at <= sqlc.narg('created_before') OR
+    sqlc.narg('created_before') IS NULL
+)
+ORDER BY
+    CASE
+        WHEN sqlc.narg('reverse') = FALSE THEN sequence_num
+        ELSE NULL
+    END ASC,
+    CASE
+        WHEN sqlc.narg('reverse') = TRUE THEN sequence_num
+        ELSE NULL
+    END DESC
+LIMIT @num_limit;
+
+-- name: UpdatePaymentStatus :exec
+UPDATE payments
+SET status = $2, fail_reason = $3
+WHERE id = $1;
+
+-- name: DeletePayment :exec
+DELETE
+FROM payments
+WHERE id = $1;
+
+-- name: DeletePaymentsByStatus :exec
+DELETE
+FROM payments
+WHERE status = $1;
+
+-- name: InsertPaymentHTLCAttempt :exec
+INSERT INTO payment_htlc_attempts (
+    attempt_id, session_key, route, first_hop_chan_id, hash, attempt_time,
+    payment_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: GetPaymentHTLCAttempts :many
+SELECT *
+FROM payment_htlc_attempts
+WHERE payment_id = $1
+ORDER BY attempt_id;
+
+-- name: SettlePaymentHTLCAttempt :exec
+UPDATE payment_htlc_attempts
+SET settle_preimage = $3, settle_time = $4
+WHERE payment_id = $1 AND attempt_id = $2;
+
+-- name: FailPaymentHTLCAttempt :exec
+UPDATE payment_htlc_attempts
+SET fail_reason = $3, fail_message = $4, fail_source_index = $5,
+    fail_time = $6
+WHERE payment_id = $1 AND attempt_id = $2;
+
+-- name: DeleteFailedPaymentHTLCAttempts :exec
+DELETE
+FROM payment_htlc_attempts
+WHERE payment_id = $1 AND fail_time IS NOT NULL;
+
+-- name: DeleteFailedHTLCAttemptsByPaymentStatus :exec
+DELETE
+FROM payment_htlc_attempts
+WHERE fail_time IS NOT NULL AND payment_id IN (
+    SELECT id
+    FROM payments
+    WHERE status = $1
+);
@@ -9,12 +9,14 @@ import (
 	"github.com/ltcsuite/lnd/autopilot"
 	"github.com/ltcsuite/lnd/chainreg"
 	"github.com/ltcsuite/lnd/channeldb"
+	"github.com/ltcsuite/lnd/electrum"
 	"github.com/ltcsuite/lnd/htlcswitch"
 	"github.com/ltcsuite/lnd/invoices"
 	"github.com/ltcsuite/lnd/lncfg"
 	"github.com/ltcsuite/lnd/lnrpc/autopilotrpc"
 	"github.com/ltcsuite/lnd/lnrpc/chainrpc"
 	"github.com/ltcsuite/lnd/lnrpc/devrpc"
+	"github.com/ltcsuite/lnd/lnrpc/electrumrpc"
 	"github.com/ltcsuite/lnd/lnrpc/invoicesrpc"
 	"github.com/ltcsuite/lnd/lnrpc/neutrinorpc"
 	"github.com/ltcsuite/lnd/lnrpc/peersrpc"
@@ -71,6 +73,10 @@ type subRPCServerConfigs struct {
 	// a client to interact with a running neutrino node.
 	NeutrinoKitRPC *neutrinorpc.Config `group:"neutrinorpc" namespace:"neutrinorpc"`
 
+	// ElectrumKitRPC is a sub-RPC server that exposes the status of the
+	// Electrum chain backend.
+	ElectrumKitRPC *electrumrpc.Config `group:"electrumrpc" namespace:"electrumrpc"`
+
 	// RouterRPC is a sub-RPC server the exposes functionality that allows
 	// clients to send payments on the network, and perform Lightning
 	// payment related queries such as requests for estimates of off-chain
@@ -115,6 +121,7 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	tower *watchtower.Standalone,
 	towerClient wtclient.Client,
 	anchorTowerClient wtclient.Client,
+	taprootTowerClient wtclient.Client,
 	tcpResolver lncfg.TCPResolver,
 	genInvoiceFeatures func() *lnwire.FeatureVector,
 	genAmpInvoiceFeatures func() *lnwire.FeatureVector,
@@ -186,6 +193,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("KeyRing").Set(
 				reflect.ValueOf(cc.KeyRing),
 			)
+			subCfgValue.FieldByName("Signer").Set(
+				reflect.ValueOf(cc.Signer),
+			)
 			subCfgValue.FieldByName("Sweeper").Set(
 				reflect.ValueOf(sweeper),
 			)
@@ -270,6 +280,17 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(cc.Cfg.NeutrinoCS),
 			)
 
+		case *electrumrpc.Config:
+			subCfgValue := extractReflectValue(subCfg)
+
+			// The chain source is only an Electrum chain client
+			// when running with the Electrum backend, otherwise
+			// the sub-server reports it isn't active.
+			chainClient, _ := cc.ChainSource.(*electrum.ChainClient)
+			subCfgValue.FieldByName("ChainClient").Set(
+				reflect.ValueOf(chainClient),
+			)
+
 		// RouterRPC isn't conditionally compiled and doesn't need to be
 		// populated using reflection.
 		case *routerrpc.Config:
@@ -287,7 +308,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 		case *wtclientrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
 
-			if towerClient != nil && anchorTowerClient != nil {
+			if towerClient != nil && anchorTowerClient != nil &&
+				taprootTowerClient != nil {
+
 				subCfgValue.FieldByName("Active").Set(
 					reflect.ValueOf(towerClient != nil),
 				)
@@ -297,6 +320,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				subCfgValue.FieldByName("AnchorClient").Set(
 					reflect.ValueOf(anchorTowerClient),
 				)
+				subCfgValue.FieldByName("TaprootClient").Set(
+					reflect.ValueOf(taprootTowerClient),
+				)
 			}
 			subCfgValue.FieldByName("Resolver").Set(
 				reflect.ValueOf(tcpResolver),
@@ -2,6 +2,8 @@ package sweep
 
 import (
 	"time"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
 )
 
 var (
@@ -9,4 +11,20 @@ var (
 	// window. The sweep is held back during the batch window to allow more
 	// inputs to be added and thereby lower the fee per input.
 	DefaultBatchWindowDuration = 30 * time.Second
+
+	// DefaultDeadlineDelta is the number of blocks, counted from the
+	// height at which the sweeper first attempts to sweep an input, over
+	// which the fee function of a budgeted input without an explicit
+	// deadline raises its fee rate towards the budget.
+	DefaultDeadlineDelta int32 = 1008
+
+	// DefaultBudgetRatio is the default fraction of an output's value that
+	// we're willing to spend on fees to get it swept before its deadline.
+	DefaultBudgetRatio = 0.5
 )
+
+// BudgetFromRatio returns the fee budget for an output of the given value
+// when we're willing to spend the given fraction of it on fees.
+func BudgetFromRatio(value ltcutil.Amount, ratio float64) ltcutil.Amount {
+	return ltcutil.Amount(float64(value) * ratio)
+}
@@ -0,0 +1,101 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+)
+
+// FeeFunction determines the fee rate a sweep transaction should pay at a
+// given block height. It is used to gradually raise the fee rate of inputs
+// that carry a budget, so that they are confirmed before their deadline
+// without overpaying when the mempool is quiet.
+type FeeFunction interface {
+	// FeeRate returns the fee rate to use for a sweep transaction that is
+	// published at the given height.
+	FeeRate(height int32) chainfee.SatPerKWeight
+
+	// MaxFeeRate returns the highest fee rate the function will ever
+	// return. It corresponds to spending the full budget.
+	MaxFeeRate() chainfee.SatPerKWeight
+}
+
+// LinearFeeFunction is a FeeFunction that raises the fee rate linearly from
+// a starting fee rate to an ending fee rate as the chain moves from the
+// starting height towards the deadline height. Once the deadline is reached,
+// the ending fee rate is used.
+type LinearFeeFunction struct {
+	// startingFeeRate is the fee rate used at or below the starting
+	// height.
+	startingFeeRate chainfee.SatPerKWeight
+
+	// endingFeeRate is the fee rate used at or above the deadline height.
+	endingFeeRate chainfee.SatPerKWeight
+
+	// startHeight is the height at which the fee function was created.
+	startHeight int32
+
+	// deadlineHeight is the height by which the sweep should be confirmed.
+	deadlineHeight int32
+}
+
+// A compile-time constraint to ensure LinearFeeFunction implements
+// FeeFunction.
+var _ FeeFunction = (*LinearFeeFunction)(nil)
+
+// NewLinearFeeFunction creates a new linear fee function. The starting fee
+// rate is capped at the ending fee rate, so the returned function never
+// decreases.
+func NewLinearFeeFunction(startingFeeRate, endingFeeRate chainfee.SatPerKWeight,
+	startHeight, deadlineHeight int32) (*LinearFeeFunction, error) {
+
+	if endingFeeRate <= 0 {
+		return nil, fmt.Errorf("invalid ending fee rate %v",
+			endingFeeRate)
+	}
+
+	if startingFeeRate > endingFeeRate {
+		startingFeeRate = endingFeeRate
+	}
+
+	return &LinearFeeFunction{
+		startingFeeRate: startingFeeRate,
+		endingFeeRate:   endingFeeRate,
+		startHeight:     startHeight,
+		deadlineHeight:  deadlineHeight,
+	}, nil
+}
+
+// FeeRate returns the fee rate to use for a sweep transaction that is
+// published at the given height.
+//
+// NOTE: Part of the FeeFunction interface.
+func (l *LinearFeeFunction) FeeRate(height int32) chainfee.SatPerKWeight {
+	switch {
+	case height >= l.deadlineHeight:
+		return l.endingFeeRate
+
+	case height <= l.startHeight:
+		return l.startingFeeRate
+	}
+
+	// The deadline lies strictly after the start height here, so the
+	// division below is safe.
+	delta := l.endingFeeRate - l.startingFeeRate
+	elapsed := chainfee.SatPerKWeight(height - l.startHeight)
+	total := chainfee.SatPerKWeight(l.deadlineHeight - l.startHeight)
+
+	return l.startingFeeRate + delta*elapsed/total
+}
+
+// MaxFeeRate returns the highest fee rate the function will ever return.
+//
+// NOTE: Part of the FeeFunction interface.
+func (l *LinearFeeFunction) MaxFeeRate() chainfee.SatPerKWeight {
+	return l.endingFeeRate
+}
+
+// DeadlineHeight returns the height at which the ending fee rate is reached.
+func (l *LinearFeeFunction) DeadlineHeight() int32 {
+	return l.deadlineHeight
+}
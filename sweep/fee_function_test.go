@@ -0,0 +1,60 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLinearFeeFunction asserts that the linear fee function raises the fee
+// rate from the starting to the ending fee rate between the starting and the
+// deadline height.
+func TestLinearFeeFunction(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewLinearFeeFunction(1000, 2000, 100, 110)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		height  int32
+		feeRate chainfee.SatPerKWeight
+	}{
+		{height: 90, feeRate: 1000},
+		{height: 100, feeRate: 1000},
+		{height: 101, feeRate: 1100},
+		{height: 105, feeRate: 1500},
+		{height: 109, feeRate: 1900},
+		{height: 110, feeRate: 2000},
+		{height: 200, feeRate: 2000},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.feeRate, f.FeeRate(tc.height),
+			"height %v", tc.height)
+	}
+
+	require.Equal(t, chainfee.SatPerKWeight(2000), f.MaxFeeRate())
+	require.Equal(t, int32(110), f.DeadlineHeight())
+}
+
+// TestLinearFeeFunctionBounds asserts that the linear fee function handles a
+// starting fee rate above the ending fee rate, a deadline in the past and an
+// invalid ending fee rate.
+func TestLinearFeeFunctionBounds(t *testing.T) {
+	t.Parallel()
+
+	// The starting fee rate is capped at the ending fee rate.
+	f, err := NewLinearFeeFunction(3000, 2000, 100, 110)
+	require.NoError(t, err)
+	require.Equal(t, chainfee.SatPerKWeight(2000), f.FeeRate(100))
+	require.Equal(t, chainfee.SatPerKWeight(2000), f.FeeRate(105))
+
+	// A deadline that already passed results in the ending fee rate right
+	// away.
+	f, err = NewLinearFeeFunction(1000, 2000, 100, 90)
+	require.NoError(t, err)
+	require.Equal(t, chainfee.SatPerKWeight(2000), f.FeeRate(100))
+
+	_, err = NewLinearFeeFunction(1000, 0, 100, 110)
+	require.Error(t, err)
+}
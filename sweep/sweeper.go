@@ -11,6 +11,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ltcsuite/lnd/chainntnfs"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/labels"
 	"github.com/ltcsuite/lnd/lnwallet"
@@ -62,6 +63,10 @@ var (
 	// it is/has already been stopped.
 	ErrSweeperShuttingDown = errors.New("utxo sweeper shutting down")
 
+	// ErrBudgetTooLow is returned in case the budget of an input doesn't
+	// cover the minimum relay fee rate of a transaction sweeping it.
+	ErrBudgetTooLow = errors.New("budget below minimum relay fee")
+
 	// DefaultMaxSweepAttempts specifies the default maximum number of times
 	// an input is included in a publish attempt before giving up and
 	// returning an error to the caller.
@@ -82,6 +87,18 @@ type Params struct {
 	// ExclusiveGroup is an identifier that, if set, prevents other inputs
 	// with the same identifier from being batched together.
 	ExclusiveGroup *uint64
+
+	// Budget is the maximum amount of fees the client is willing to pay
+	// to get the input swept. If set, the sweeper raises the fee rate of
+	// the input block by block until the budget is exhausted at the
+	// deadline height. The fee preference is then optional and only used
+	// to determine the starting fee rate.
+	Budget ltcutil.Amount
+
+	// DeadlineHeight is the block height by which the input should be
+	// swept. It is only used for inputs that have a budget. If not set,
+	// DefaultDeadlineDelta blocks after the first sweep attempt is used.
+	DeadlineHeight fn.Option[int32]
 }
 
 // ParamsUpdate contains a new set of parameters to update a pending sweep with.
@@ -94,17 +111,37 @@ type ParamsUpdate struct {
 	// Force indicates whether the input should be swept regardless of
 	// whether it is economical to do so.
 	Force bool
+
+	// Budget is the maximum amount of fees the client is willing to pay
+	// to get the input swept. If zero, the current budget of the input is
+	// kept.
+	Budget ltcutil.Amount
+
+	// DeadlineHeight is the block height by which the input should be
+	// swept. If none, the current deadline of the input is kept.
+	DeadlineHeight fn.Option[int32]
 }
 
 // String returns a human readable interpretation of the sweep parameters.
 func (p Params) String() string {
+	exclusiveGroup := "nil"
 	if p.ExclusiveGroup != nil {
-		return fmt.Sprintf("fee=%v, force=%v, exclusive_group=%v",
-			p.Fee, p.Force, *p.ExclusiveGroup)
+		exclusiveGroup = fmt.Sprintf("%v", *p.ExclusiveGroup)
 	}
 
-	return fmt.Sprintf("fee=%v, force=%v, exclusive_group=nil",
-		p.Fee, p.Force)
+	deadline := "nil"
+	p.DeadlineHeight.WhenSome(func(h int32) {
+		deadline = fmt.Sprintf("%v", h)
+	})
+
+	return fmt.Sprintf("fee=%v, force=%v, exclusive_group=%v, "+
+		"budget=%v, deadline=%v", p.Fee, p.Force, exclusiveGroup,
+		p.Budget, deadline)
+}
+
+// hasFeePreference returns true if the client specified a fee preference.
+func (p Params) hasFeePreference() bool {
+	return p.Fee.FeeRate != 0 || p.Fee.ConfTarget != 0
 }
 
 // pendingInput is created when an input reaches the main loop for the first
@@ -135,6 +172,16 @@ type pendingInput struct {
 	// lastFeeRate is the most recent fee rate used for this input within a
 	// transaction broadcast to the network.
 	lastFeeRate chainfee.SatPerKWeight
+
+	// feeFunc raises the fee rate of an input that carries a budget as
+	// its deadline approaches. It is created the first time the input is
+	// clustered and reset whenever the budget or deadline changes.
+	feeFunc *LinearFeeFunction
+
+	// publishedFeeRate is the fee rate of the last transaction spending
+	// this input that was accepted by the wallet. Any replacement must
+	// pay a higher fee rate for it to propagate.
+	publishedFeeRate chainfee.SatPerKWeight
 }
 
 // parameters returns the sweep parameters for this input.
@@ -189,6 +236,11 @@ type PendingInput struct {
 
 	// Params contains the sweep parameters for this pending request.
 	Params Params
+
+	// DeadlineHeight is the height by which the budget of the input will
+	// be fully spent. It is only set for inputs with a budget once the
+	// sweeper has started sweeping them.
+	DeadlineHeight fn.Option[int32]
 }
 
 // updateReq is an internal message we'll use to represent an external caller's
@@ -426,6 +478,10 @@ func (s *UtxoSweeper) Stop() error {
 // it to be batched under the same transaction with other similar fee rate
 // inputs.
 //
+// If a budget is provided, the fee rate of the input is instead raised block
+// by block until the full budget is spent at the deadline height, and the
+// input is retried until it is spent.
+//
 // NOTE: Extreme care needs to be taken that input isn't changed externally.
 // Because it is an interface and we don't know what is exactly behind it, we
 // cannot make a local copy in sweeper.
@@ -437,7 +493,7 @@ func (s *UtxoSweeper) SweepInput(input input.Input,
 	}
 
 	// Ensure the client provided a sane fee preference.
-	if _, err := s.feeRateForPreference(params.Fee); err != nil {
+	if err := s.validateFeePreference(params); err != nil {
 		return nil, err
 	}
 
@@ -492,6 +548,109 @@ func (s *UtxoSweeper) feeRateForPreference(
 	return feeRate, nil
 }
 
+// validateFeePreference ensures the fee preference of the given sweep
+// parameters respects the bounds of the UtxoSweeper. Inputs that carry a
+// budget may leave out the fee preference, in which case their fee function
+// determines the starting fee rate on its own.
+func (s *UtxoSweeper) validateFeePreference(params Params) error {
+	if params.Budget != 0 && !params.hasFeePreference() {
+		return nil
+	}
+
+	_, err := s.feeRateForPreference(params.Fee)
+
+	return err
+}
+
+// feeRateForInput returns the fee rate the given input should be swept with
+// at the given height. Inputs with a budget are swept at the rate returned by
+// their fee function, all other inputs use their fee preference.
+func (s *UtxoSweeper) feeRateForInput(input *pendingInput,
+	currentHeight int32) (chainfee.SatPerKWeight, error) {
+
+	if input.params.Budget == 0 {
+		return s.feeRateForPreference(input.params.Fee)
+	}
+
+	if input.feeFunc == nil {
+		feeFunc, err := s.newFeeFunction(input, currentHeight)
+		if err != nil {
+			return 0, err
+		}
+
+		log.Debugf("Created fee function for input %v: start=%v, "+
+			"end=%v, deadline=%v", input.OutPoint(),
+			feeFunc.FeeRate(currentHeight), feeFunc.MaxFeeRate(),
+			feeFunc.DeadlineHeight())
+
+		input.feeFunc = feeFunc
+	}
+
+	return input.feeFunc.FeeRate(currentHeight), nil
+}
+
+// newFeeFunction creates the fee function for an input that carries a budget.
+// The ending fee rate is the rate at which the budget is fully spent on a
+// transaction sweeping just this input, including any unconfirmed parent that
+// is paid for through cpfp. The starting fee rate is derived from the fee
+// preference, or from the fee estimate for the blocks left until the deadline
+// if no preference was given.
+func (s *UtxoSweeper) newFeeFunction(input *pendingInput,
+	currentHeight int32) (*LinearFeeFunction, error) {
+
+	deadline := input.params.DeadlineHeight.UnwrapOr(
+		currentHeight + DefaultDeadlineDelta,
+	)
+
+	// Estimate the weight of a transaction that only sweeps this input.
+	estimator := newWeightEstimator(0)
+	if err := estimator.add(input); err != nil {
+		return nil, err
+	}
+	estimator.addP2WKHOutput()
+
+	weight := int64(estimator.weight())
+	budget := input.params.Budget
+	if parent := input.UnconfParent(); parent != nil {
+		weight += parent.Weight
+		budget += parent.Fee
+	}
+
+	endingFeeRate := chainfee.SatPerKWeight(budget * 1000 /
+		ltcutil.Amount(weight))
+	if endingFeeRate > s.cfg.MaxFeeRate {
+		endingFeeRate = s.cfg.MaxFeeRate
+	}
+	if endingFeeRate < s.relayFeeRate {
+		return nil, fmt.Errorf("%w: budget %v results in fee rate "+
+			"%v, minimum is %v", ErrBudgetTooLow,
+			input.params.Budget, endingFeeRate, s.relayFeeRate)
+	}
+
+	// Determine the starting fee rate. Without an explicit preference we
+	// target confirmation within the blocks left until the deadline.
+	feePref := input.params.Fee
+	if !input.params.hasFeePreference() {
+		confTarget := deadline - currentHeight
+		if confTarget < 1 {
+			confTarget = 1
+		}
+		feePref = FeePreference{ConfTarget: uint32(confTarget)}
+	}
+
+	startingFeeRate, err := DetermineFeePerKw(s.cfg.FeeEstimator, feePref)
+	if err != nil {
+		return nil, err
+	}
+	if startingFeeRate < s.relayFeeRate {
+		startingFeeRate = s.relayFeeRate
+	}
+
+	return NewLinearFeeFunction(
+		startingFeeRate, endingFeeRate, currentHeight, deadline,
+	)
+}
+
 // removeLastSweepDescendants removes any transactions from the wallet that
 // spend outputs produced by the passed spendingTx. This needs to be done in
 // cases where we're not the only ones that can sweep an output, but there may
@@ -613,6 +772,17 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 					*prevExclGroup = *pendInput.params.ExclusiveGroup
 				}
 
+				// A changed budget or deadline requires a new
+				// fee function, which will be created at the
+				// next clustering.
+				oldParams := pendInput.params
+				if oldParams.Budget != input.params.Budget ||
+					oldParams.DeadlineHeight !=
+						input.params.DeadlineHeight {
+
+					pendInput.feeFunc = nil
+				}
+
 				// Update input details and sweep parameters.
 				// The re-offered input details may contain a
 				// change to the unconfirmed parent tx info.
@@ -779,7 +949,7 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 			// this to ensure any inputs which have had their fee
 			// rate bumped are broadcast first in order enforce the
 			// RBF policy.
-			inputClusters := s.createInputClusters()
+			inputClusters := s.createInputClusters(bestHeight)
 			sort.Slice(inputClusters, func(i, j int) bool {
 				return inputClusters[i].sweepFeeRate >
 					inputClusters[j].sweepFeeRate
@@ -885,22 +1055,44 @@ func (s *UtxoSweeper) bucketForFeeRate(
 // createInputClusters creates a list of input clusters from the set of pending
 // inputs known by the UtxoSweeper. It clusters inputs by
 // 1) Required tx locktime
-// 2) Similar fee rates.
-func (s *UtxoSweeper) createInputClusters() []inputCluster {
+// 2) Deadline height, for inputs that carry a budget
+// 3) Similar fee rates.
+func (s *UtxoSweeper) createInputClusters(
+	currentHeight int32) []inputCluster {
+
 	inputs := s.pendingInputs
 
 	// We start by getting the inputs clusters by locktime. Since the
 	// inputs commit to the locktime, they can only be clustered together
 	// if the locktime is equal.
-	lockTimeClusters, nonLockTimeInputs := s.clusterByLockTime(inputs)
+	lockTimeClusters, nonLockTimeInputs := s.clusterByLockTime(
+		inputs, currentHeight,
+	)
+
+	// Split off the inputs that carry a budget. Their fee rate is driven
+	// by their deadline, so they are only batched with inputs that share
+	// the same deadline.
+	budgetInputs := make(pendingInputs)
+	feeInputs := make(pendingInputs)
+	for op, input := range nonLockTimeInputs {
+		if input.params.Budget != 0 {
+			budgetInputs[op] = input
+		} else {
+			feeInputs[op] = input
+		}
+	}
+
+	deadlineClusters := s.clusterByDeadline(budgetInputs, currentHeight)
 
 	// Cluster the the remaining inputs by sweep fee rate.
-	feeClusters := s.clusterBySweepFeeRate(nonLockTimeInputs)
+	feeClusters := s.clusterBySweepFeeRate(feeInputs)
 
-	// Since the inputs that we clustered by fee rate don't commit to a
-	// specific locktime, we can try to merge a locktime cluster with a fee
-	// cluster.
-	return zipClusters(lockTimeClusters, feeClusters)
+	// Since the inputs that we clustered by deadline or fee rate don't
+	// commit to a specific locktime, we can try to merge a locktime
+	// cluster with one of them.
+	return zipClusters(
+		lockTimeClusters, append(deadlineClusters, feeClusters...),
+	)
 }
 
 // clusterByLockTime takes the given set of pending inputs and clusters those
@@ -908,8 +1100,8 @@ func (s *UtxoSweeper) createInputClusters() []inputCluster {
 // is determined by calculating the average fee rate of all inputs within that
 // cluster. In addition to the created clusters, inputs that did not specify a
 // required lock time are returned.
-func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
-	pendingInputs) {
+func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs,
+	currentHeight int32) ([]inputCluster, pendingInputs) {
 
 	locktimes := make(map[uint32]pendingInputs)
 	inputFeeRates := make(map[wire.OutPoint]chainfee.SatPerKWeight)
@@ -923,6 +1115,13 @@ func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
 			continue
 		}
 
+		// We also get the preferred fee rate for this input.
+		feeRate, err := s.feeRateForInput(input, currentHeight)
+		if err != nil {
+			s.skipInput(op, err)
+			continue
+		}
+
 		// Check if we already have inputs with this locktime.
 		p, ok := locktimes[lt]
 		if !ok {
@@ -932,13 +1131,6 @@ func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
 		p[op] = input
 		locktimes[lt] = p
 
-		// We also get the preferred fee rate for this input.
-		feeRate, err := s.feeRateForPreference(input.params.Fee)
-		if err != nil {
-			log.Warnf("Skipping input %v: %v", op, err)
-			continue
-		}
-
 		input.lastFeeRate = feeRate
 		inputFeeRates[op] = feeRate
 	}
@@ -982,23 +1174,8 @@ func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster
 			continue
 		}
 
-		// Only try to sweep inputs with an unconfirmed parent if the
-		// current sweep fee rate exceeds the parent tx fee rate. This
-		// assumes that such inputs are offered to the sweeper solely
-		// for the purpose of anchoring down the parent tx using cpfp.
-		parentTx := input.UnconfParent()
-		if parentTx != nil {
-			parentFeeRate :=
-				chainfee.SatPerKWeight(parentTx.Fee*1000) /
-					chainfee.SatPerKWeight(parentTx.Weight)
-
-			if parentFeeRate >= feeRate {
-				log.Debugf("Skipping cpfp input %v: fee_rate=%v, "+
-					"parent_fee_rate=%v", op, feeRate,
-					parentFeeRate)
-
-				continue
-			}
+		if parentPaysFeeRate(input, feeRate) {
+			continue
 		}
 
 		feeGroup := s.bucketForFeeRate(feeRate)
@@ -1039,6 +1216,110 @@ func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster
 	return inputClusters
 }
 
+// clusterByDeadline takes a set of pending inputs that carry a budget and
+// clusters those with the same deadline height together. The sweep fee rate of
+// each cluster is the average of the rates returned by the fee functions of
+// its inputs at the current height, capped at the lowest maximum fee rate of
+// the inputs so that none of them pays more than its budget allows.
+func (s *UtxoSweeper) clusterByDeadline(inputs pendingInputs,
+	currentHeight int32) []inputCluster {
+
+	deadlineInputs := make(map[int32]*bucketList)
+	inputFeeRates := make(map[wire.OutPoint]chainfee.SatPerKWeight)
+
+	for op, input := range inputs {
+		feeRate, err := s.feeRateForInput(input, currentHeight)
+		if err != nil {
+			s.skipInput(op, err)
+			continue
+		}
+
+		if parentPaysFeeRate(input, feeRate) {
+			continue
+		}
+
+		// Create a bucket list for this deadline if there isn't one
+		// yet. The bucket list will take into account exclusive group
+		// constraints.
+		deadline := input.feeFunc.DeadlineHeight()
+		buckets, ok := deadlineInputs[deadline]
+		if !ok {
+			buckets = &bucketList{}
+			deadlineInputs[deadline] = buckets
+		}
+		buckets.add(input)
+
+		input.lastFeeRate = feeRate
+		inputFeeRates[op] = feeRate
+	}
+
+	inputClusters := make([]inputCluster, 0, len(deadlineInputs))
+	for _, buckets := range deadlineInputs {
+		for _, inputs := range buckets.buckets {
+			var sweepFeeRate chainfee.SatPerKWeight
+			maxFeeRate := s.cfg.MaxFeeRate
+			for op, input := range inputs {
+				sweepFeeRate += inputFeeRates[op]
+
+				if input.feeFunc.MaxFeeRate() < maxFeeRate {
+					maxFeeRate = input.feeFunc.MaxFeeRate()
+				}
+			}
+			sweepFeeRate /= chainfee.SatPerKWeight(len(inputs))
+			if sweepFeeRate > maxFeeRate {
+				sweepFeeRate = maxFeeRate
+			}
+			inputClusters = append(inputClusters, inputCluster{
+				sweepFeeRate: sweepFeeRate,
+				inputs:       inputs,
+			})
+		}
+	}
+
+	return inputClusters
+}
+
+// skipInput leaves the given input out of the current sweep because its fee
+// rate can't be determined. Inputs whose budget can't even pay the minimum
+// relay fee will never be swept, so they are removed and their listeners are
+// notified instead of skipping them forever.
+func (s *UtxoSweeper) skipInput(op wire.OutPoint, err error) {
+	if errors.Is(err, ErrBudgetTooLow) {
+		log.Errorf("Removing input %v: %v", op, err)
+		s.signalAndRemove(&op, Result{Err: ErrBudgetTooLow})
+
+		return
+	}
+
+	log.Warnf("Skipping input %v: %v", op, err)
+}
+
+// parentPaysFeeRate returns true if the input has an unconfirmed parent that
+// already pays at least the given fee rate. Inputs with an unconfirmed parent
+// are only swept if the sweep fee rate exceeds the parent tx fee rate. This
+// assumes that such inputs are offered to the sweeper solely for the purpose
+// of anchoring down the parent tx using cpfp.
+func parentPaysFeeRate(input *pendingInput,
+	feeRate chainfee.SatPerKWeight) bool {
+
+	parentTx := input.UnconfParent()
+	if parentTx == nil {
+		return false
+	}
+
+	parentFeeRate := chainfee.SatPerKWeight(parentTx.Fee*1000) /
+		chainfee.SatPerKWeight(parentTx.Weight)
+
+	if parentFeeRate < feeRate {
+		return false
+	}
+
+	log.Debugf("Skipping cpfp input %v: fee_rate=%v, parent_fee_rate=%v",
+		input.OutPoint(), feeRate, parentFeeRate)
+
+	return true
+}
+
 // zipClusters merges pairwise clusters from as and bs such that cluster a from
 // as is merged with a cluster from bs that has at least the fee rate of a.
 // This to ensure we don't delay confirmation by decreasing the fee rate (the
@@ -1142,7 +1423,7 @@ func (s *UtxoSweeper) scheduleSweep(currentHeight int32) error {
 
 	// We'll only start our timer once we have inputs we're able to sweep.
 	startTimer := false
-	for _, cluster := range s.createInputClusters() {
+	for _, cluster := range s.createInputClusters(currentHeight) {
 		// Examine pending inputs and try to construct lists of inputs.
 		// We don't need to obtain the coin selection lock, because we
 		// just need an indication as to whether we can sweep. More
@@ -1288,6 +1569,21 @@ func (s *UtxoSweeper) getInputLists(cluster inputCluster,
 func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 	currentHeight int32) error {
 
+	// Make sure the fee rate is high enough to replace any transaction we
+	// published earlier for one of the inputs, without exceeding the
+	// budget of any of them.
+	feeRate, ok := s.replacementFeeRate(inputs, feeRate)
+	if !ok {
+		for _, inp := range inputs {
+			pi, ok := s.pendingInputs[*inp.OutPoint()]
+			if ok && pi.params.Budget != 0 {
+				pi.minPublishHeight = currentHeight + 1
+			}
+		}
+
+		return nil
+	}
+
 	// Generate an output script if there isn't an unused script available.
 	if s.currentOutputScript == nil {
 		pkScript, err := s.cfg.GenSweepScript()
@@ -1361,6 +1657,23 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 		// Record another publish attempt.
 		pi.publishAttempts++
 
+		// Inputs with a budget are retried every block, so that their
+		// fee function can raise the fee rate until the deadline. They
+		// are only given up on once they are spent.
+		if pi.params.Budget != 0 {
+			if err == nil {
+				pi.publishedFeeRate = feeRate
+			}
+			pi.minPublishHeight = currentHeight + 1
+
+			log.Debugf("Rescheduling input %v with budget %v "+
+				"after %v attempts at height %v",
+				input.PreviousOutPoint, pi.params.Budget,
+				pi.publishAttempts, pi.minPublishHeight)
+
+			continue
+		}
+
 		// We don't care what the result of the publish call was. Even
 		// if it is published successfully, it can still be that it
 		// needs to be retried. Call NextAttemptDeltaFunc to calculate
@@ -1392,6 +1705,62 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 	return nil
 }
 
+// replacementFeeRate returns the fee rate a sweep of the given inputs should
+// be published at. The fee rate is capped at the lowest budget fee rate of the
+// inputs. If one of the inputs has been published at the same or a higher fee
+// rate before, the fee rate is raised by at least the relay fee rate so that
+// the new transaction can replace the old one. False is returned if the
+// resulting fee rate exceeds the budget of any of the inputs, in which case
+// the previous transaction is left in place.
+func (s *UtxoSweeper) replacementFeeRate(inputs inputSet,
+	feeRate chainfee.SatPerKWeight) (chainfee.SatPerKWeight, bool) {
+
+	var (
+		minFeeRate chainfee.SatPerKWeight
+		maxFeeRate = s.cfg.MaxFeeRate
+	)
+	for _, inp := range inputs {
+		pi, ok := s.pendingInputs[*inp.OutPoint()]
+		if !ok {
+			continue
+		}
+
+		if pi.publishedFeeRate != 0 {
+			rate := pi.publishedFeeRate + s.relayFeeRate
+			if rate > minFeeRate {
+				minFeeRate = rate
+			}
+		}
+
+		if pi.feeFunc != nil && pi.feeFunc.MaxFeeRate() < maxFeeRate {
+			maxFeeRate = pi.feeFunc.MaxFeeRate()
+		}
+	}
+
+	if feeRate > maxFeeRate {
+		log.Debugf("Capping sweep fee rate %v at budget fee rate %v",
+			feeRate, maxFeeRate)
+
+		feeRate = maxFeeRate
+	}
+
+	if feeRate >= minFeeRate {
+		return feeRate, true
+	}
+
+	if minFeeRate > maxFeeRate {
+		log.Debugf("Not replacing sweep: required fee rate %v "+
+			"exceeds budget fee rate %v", minFeeRate, maxFeeRate)
+
+		return 0, false
+	}
+
+	log.Debugf("Raising sweep fee rate from %v to %v to replace "+
+		"previous sweep", feeRate, minFeeRate)
+
+	return minFeeRate, true
+}
+
 // waitForSpend registers a spend notification with the chain notifier. It
 // returns a cancel function that can be used to cancel the registration.
 func (s *UtxoSweeper) waitForSpend(outpoint wire.OutPoint,
@@ -1480,16 +1849,25 @@ func (s *UtxoSweeper) handlePendingSweepsReq(
 			NextBroadcastHeight: uint32(pendingInput.minPublishHeight),
 			Params:              pendingInput.params,
 		}
+
+		// Report the deadline the fee function is working towards,
+		// which may be a default if the client didn't specify one.
+		if pendingInput.feeFunc != nil {
+			pendingInputs[op].DeadlineHeight = fn.Some(
+				pendingInput.feeFunc.DeadlineHeight(),
+			)
+		}
 	}
 
 	return pendingInputs
 }
 
 // UpdateParams allows updating the sweep parameters of a pending input in the
-// UtxoSweeper. This function can be used to provide an updated fee preference
-// and force flag that will be used for a new sweep transaction of the input
-// that will act as a replacement transaction (RBF) of the original sweeping
-// transaction, if any. The exclusive group is left unchanged.
+// UtxoSweeper. This function can be used to provide an updated fee preference,
+// force flag, budget and deadline that will be used for a new sweep
+// transaction of the input that will act as a replacement transaction (RBF) of
+// the original sweeping transaction, if any. The exclusive group is left
+// unchanged.
 //
 // NOTE: This currently doesn't do any fee rate validation to ensure that a bump
 // is actually successful. The responsibility of doing so should be handled by
@@ -1498,7 +1876,11 @@ func (s *UtxoSweeper) UpdateParams(input wire.OutPoint,
 	params ParamsUpdate) (chan Result, error) {
 
 	// Ensure the client provided a sane fee preference.
-	if _, err := s.feeRateForPreference(params.Fee); err != nil {
+	err := s.validateFeePreference(Params{
+		Fee:    params.Fee,
+		Budget: params.Budget,
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -1546,16 +1928,28 @@ func (s *UtxoSweeper) handleUpdateReq(req *updateReq, bestHeight int32) (
 	}
 
 	// Create the updated parameters struct. Leave the exclusive group
-	// unchanged.
+	// unchanged, and only replace the budget and deadline if the request
+	// specifies them, so that a plain fee bump doesn't erase the ones set
+	// by the client that offered the input.
 	newParams := pendingInput.params
 	newParams.Fee = req.params.Fee
 	newParams.Force = req.params.Force
+	if req.params.Budget != 0 {
+		newParams.Budget = req.params.Budget
+	}
+	if req.params.DeadlineHeight.IsSome() {
+		newParams.DeadlineHeight = req.params.DeadlineHeight
+	}
 
 	log.Debugf("Updating sweep parameters for %v from %v to %v", req.input,
 		pendingInput.params, newParams)
 
 	pendingInput.params = newParams
 
+	// Start a new fee function from the current height, so the updated
+	// fee preference, budget and deadline take effect immediately.
+	pendingInput.feeFunc = nil
+
 	// We'll reset the input's publish height to the current so that a new
 	// transaction can be created that replaces the transaction currently
 	// spending the input. We only do this for inputs that have been
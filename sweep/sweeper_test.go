@@ -10,6 +10,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ltcsuite/lnd/build"
+	"github.com/ltcsuite/lnd/fn"
 	"github.com/ltcsuite/lnd/input"
 	"github.com/ltcsuite/lnd/keychain"
 	"github.com/ltcsuite/lnd/lntest/mock"
@@ -1213,6 +1214,210 @@ func TestBumpFeeRBF(t *testing.T) {
 	ctx.finish(1)
 }
 
+// TestBumpFeeKeepsBudget asserts that a fee bump that only specifies a fee
+// preference keeps the budget and deadline of the input.
+func TestBumpFeeKeepsBudget(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	inp := createTestInput(
+		ltcutil.SatoshiPerBitcoin, input.CommitmentTimeLock,
+	)
+	budget := ltcutil.Amount(ltcutil.SatoshiPerBitcoin / 10)
+	deadline := fn.Some(mockChainHeight + 10)
+	sweepResult, err := ctx.sweeper.SweepInput(&inp, Params{
+		Fee:            FeePreference{ConfTarget: 6},
+		Budget:         budget,
+		DeadlineHeight: deadline,
+	})
+	require.NoError(t, err)
+
+	ctx.tick()
+	ctx.receiveTx()
+
+	bumpResult, err := ctx.sweeper.UpdateParams(
+		*inp.OutPoint(), ParamsUpdate{
+			Fee: FeePreference{ConfTarget: 2},
+		},
+	)
+	require.NoError(t, err)
+
+	pendingInputs, err := ctx.sweeper.PendingInputs()
+	require.NoError(t, err)
+	require.Contains(t, pendingInputs, *inp.OutPoint())
+
+	params := pendingInputs[*inp.OutPoint()].Params
+	require.Equal(t, FeePreference{ConfTarget: 2}, params.Fee)
+	require.Equal(t, budget, params.Budget)
+	require.Equal(t, deadline, params.DeadlineHeight)
+
+	// The bump is swept with the next batch, which we'll mine.
+	ctx.tick()
+	ctx.receiveTx()
+
+	ctx.backend.mine()
+	ctx.expectResult(sweepResult, nil)
+	ctx.expectResult(bumpResult, nil)
+
+	ctx.finish(1)
+}
+
+// TestBudgetFeeBumping asserts that the fee rate of an input with a budget is
+// raised block by block towards its deadline.
+func TestBudgetFeeBumping(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	changePk, err := ctx.sweeper.cfg.GenSweepScript()
+	require.NoError(t, err)
+
+	// Offer an input with a budget large enough to reach the maximum fee
+	// rate of the sweeper at the deadline, ten blocks from now.
+	inp := createTestInput(
+		ltcutil.SatoshiPerBitcoin, input.CommitmentTimeLock,
+	)
+	startFeeRate := chainfee.SatPerKWeight(1000)
+	sweepResult, err := ctx.sweeper.SweepInput(&inp, Params{
+		Fee:            FeePreference{FeeRate: startFeeRate},
+		Budget:         ltcutil.SatoshiPerBitcoin / 10,
+		DeadlineHeight: fn.Some(mockChainHeight + 10),
+	})
+	require.NoError(t, err)
+
+	// The first sweep uses the starting fee rate.
+	ctx.tick()
+	tx := ctx.receiveTx()
+	assertTxFeeRate(t, &tx, startFeeRate, changePk, &inp)
+
+	// Halfway to the deadline, the fee rate is halfway to the maximum.
+	ctx.notifier.NotifyEpoch(mockChainHeight + 5)
+	ctx.tick()
+	tx = ctx.receiveTx()
+	midFeeRate := startFeeRate + (DefaultMaxFeeRate-startFeeRate)/2
+	assertTxFeeRate(t, &tx, midFeeRate, changePk, &inp)
+
+	// At the deadline, the maximum fee rate is used.
+	ctx.notifier.NotifyEpoch(mockChainHeight + 10)
+	ctx.tick()
+	tx = ctx.receiveTx()
+	assertTxFeeRate(t, &tx, DefaultMaxFeeRate, changePk, &inp)
+
+	// The input is never given up on, even though the number of publish
+	// attempts exceeds the configured maximum.
+	ctx.assertPendingInputs(&inp)
+
+	ctx.backend.mine()
+	ctx.expectResult(sweepResult, nil)
+
+	ctx.finish(1)
+}
+
+// TestBudgetReplacement asserts that an input with a budget isn't republished
+// if the replacement can't pay a higher fee rate within the budget.
+func TestBudgetReplacement(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	changePk, err := ctx.sweeper.cfg.GenSweepScript()
+	require.NoError(t, err)
+
+	// Offer an input whose deadline already passed, so the fee rate that
+	// spends the full budget is used right away.
+	inp := createTestInput(1_000_000, input.CommitmentTimeLock)
+	budget := ltcutil.Amount(1000)
+	sweepResult, err := ctx.sweeper.SweepInput(&inp, Params{
+		Budget:         budget,
+		DeadlineHeight: fn.Some(mockChainHeight - 1),
+	})
+	require.NoError(t, err)
+
+	_, estimator, err := getWeightEstimate(
+		[]input.Input{&inp}, nil, 0, changePk,
+	)
+	require.NoError(t, err)
+	budgetFeeRate := chainfee.SatPerKWeight(
+		budget * 1000 / ltcutil.Amount(estimator.weight()),
+	)
+
+	ctx.tick()
+	tx := ctx.receiveTx()
+	assertTxFeeRate(t, &tx, budgetFeeRate, changePk, &inp)
+
+	// In the next block, a replacement would need to pay more than the
+	// budget allows, so nothing is published.
+	ctx.notifier.NotifyEpoch(mockChainHeight + 1)
+	ctx.tick()
+	ctx.assertNoTx()
+
+	ctx.backend.mine()
+	ctx.expectResult(sweepResult, nil)
+
+	ctx.finish(1)
+}
+
+// TestBudgetMixedBatch asserts that inputs with different budgets that are
+// swept together don't pay more than the lowest of their budgets allows.
+func TestBudgetMixedBatch(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	changePk, err := ctx.sweeper.cfg.GenSweepScript()
+	require.NoError(t, err)
+
+	// Offer two inputs with the same deadline, which already passed, so
+	// both of them ask for the fee rate that spends their full budget.
+	// The budget of the second input is much larger than that of the
+	// first.
+	lowInp := createTestInput(1_000_000, input.CommitmentTimeLock)
+	lowBudget := ltcutil.Amount(1000)
+	lowResult, err := ctx.sweeper.SweepInput(&lowInp, Params{
+		Budget:         lowBudget,
+		DeadlineHeight: fn.Some(mockChainHeight - 1),
+	})
+	require.NoError(t, err)
+
+	highInp := createTestInput(1_000_000, input.CommitmentTimeLock)
+	highResult, err := ctx.sweeper.SweepInput(&highInp, Params{
+		Budget:         100_000,
+		DeadlineHeight: fn.Some(mockChainHeight - 1),
+	})
+	require.NoError(t, err)
+
+	_, estimator, err := getWeightEstimate(
+		[]input.Input{&lowInp}, nil, 0, changePk,
+	)
+	require.NoError(t, err)
+	lowFeeRate := chainfee.SatPerKWeight(
+		lowBudget * 1000 / ltcutil.Amount(estimator.weight()),
+	)
+
+	// The inputs are batched at the budget fee rate of the first input
+	// rather than at the average of both.
+	ctx.tick()
+	tx := ctx.receiveTx()
+	assertTxFeeRate(t, &tx, lowFeeRate, changePk, &lowInp, &highInp)
+
+	ctx.backend.mine()
+	ctx.expectResult(lowResult, nil)
+	ctx.expectResult(highResult, nil)
+
+	ctx.finish(1)
+}
+
+// TestBudgetTooLow asserts that an input whose budget can't pay the minimum
+// relay fee is removed with an error instead of being skipped forever.
+func TestBudgetTooLow(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	inp := createTestInput(1_000_000, input.CommitmentTimeLock)
+	sweepResult, err := ctx.sweeper.SweepInput(&inp, Params{
+		Budget:         1,
+		DeadlineHeight: fn.Some(mockChainHeight + 10),
+	})
+	require.NoError(t, err)
+
+	ctx.expectResult(sweepResult, ErrBudgetTooLow)
+	ctx.assertPendingInputs()
+
+	ctx.finish(1)
+}
+
 // TestExclusiveGroup tests the sweeper exclusive group functionality.
 func TestExclusiveGroup(t *testing.T) {
 	ctx := createSweeperTestContext(t)
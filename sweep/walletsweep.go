@@ -186,6 +186,13 @@ type WalletSweepPackage struct {
 	// this closure MUST be called, otherwise all selected utxos will be
 	// unable to be used.
 	CancelSweepAttempt func()
+
+	// Fee is the total fee paid by SweepTx.
+	Fee ltcutil.Amount
+
+	// MwebFee is the part of Fee paid by the MWEB kernels of SweepTx. It
+	// is zero if SweepTx doesn't touch the MWEB extension block.
+	MwebFee ltcutil.Amount
 }
 
 // DeliveryAddr is a pair of (address, amount) used to craft a transaction
@@ -330,7 +337,7 @@ func CraftSweepAllTx(feeRate chainfee.SatPerKWeight, blockHeight uint32,
 
 			// Fall through to canonical-only path below.
 		} else {
-			sweepTx, err := craftMwebSweepTx(
+			sweepTx, fee, err := craftMwebSweepTx(
 				canonicalOutputs, mwebOutputs, txOuts,
 				changePkScript, feeRate, mwebSweeper,
 			)
@@ -339,9 +346,18 @@ func CraftSweepAllTx(feeRate chainfee.SatPerKWeight, blockHeight uint32,
 				return nil, err
 			}
 
+			// The kernels of the extension block commit to the fee
+			// they pay, the rest is paid on the canonical chain.
+			mwebFee := lnwallet.ClassifyMwebTx(sweepTx).Fee
+			if mwebFee > fee {
+				mwebFee = fee
+			}
+
 			return &WalletSweepPackage{
 				SweepTx:            sweepTx,
 				CancelSweepAttempt: unlockOutputs,
+				Fee:                fee,
+				MwebFee:            mwebFee,
 			}, nil
 		}
 	}
@@ -392,20 +408,30 @@ func CraftSweepAllTx(feeRate chainfee.SatPerKWeight, blockHeight uint32,
 		return nil, err
 	}
 
+	var fee ltcutil.Amount
+	for _, output := range canonicalOutputs {
+		fee += output.Value
+	}
+	for _, txOut := range sweepTx.TxOut {
+		fee -= ltcutil.Amount(txOut.Value)
+	}
+
 	return &WalletSweepPackage{
 		SweepTx:            sweepTx,
 		CancelSweepAttempt: unlockOutputs,
+		Fee:                fee,
 	}, nil
 }
 
 // craftMwebSweepTx builds a sweep transaction that handles MWEB inputs and/or
 // MWEB outputs. It constructs an AuthoredTx with all inputs (canonical + MWEB),
 // calculates fees for both canonical and MWEB portions, and delegates signing
-// to the MwebSweeper.
+// to the MwebSweeper. The signed transaction is returned along with the total
+// fee it pays.
 func craftMwebSweepTx(canonicalUtxos, mwebUtxos []*lnwallet.Utxo,
 	deliveryTxOuts []*wire.TxOut, changePkScript []byte,
 	feeRate chainfee.SatPerKWeight,
-	mwebSweeper MwebSweeper) (*wire.MsgTx, error) {
+	mwebSweeper MwebSweeper) (*wire.MsgTx, ltcutil.Amount, error) {
 
 	feeRatePerKb := ltcutil.Amount(feeRate.FeePerKVByte())
 
@@ -518,7 +544,7 @@ func craftMwebSweepTx(canonicalUtxos, mwebUtxos []*lnwallet.Utxo,
 	changeAmt := totalInput - deliveryTotal - totalFee
 
 	if changeAmt < 0 {
-		return nil, fmt.Errorf("insufficient input to create sweep "+
+		return nil, 0, fmt.Errorf("insufficient input to create sweep "+
 			"tx: input_sum=%v, output_sum=%v, fee=%v",
 			totalInput, deliveryTotal, totalFee)
 	}
@@ -546,7 +572,8 @@ func craftMwebSweepTx(canonicalUtxos, mwebUtxos []*lnwallet.Utxo,
 	// Sign the transaction: AddMweb handles the MWEB extension block,
 	// then AddAllInputScripts signs the canonical inputs.
 	if err := mwebSweeper.SignMwebSweepTx(authoredTx, feeRate); err != nil {
-		return nil, fmt.Errorf("unable to sign mweb sweep tx: %w", err)
+		return nil, 0, fmt.Errorf("unable to sign mweb sweep tx: %w",
+			err)
 	}
 
 	// After signing, verify that the total fee meets the minimum relay
@@ -591,12 +618,14 @@ func craftMwebSweepTx(canonicalUtxos, mwebUtxos []*lnwallet.Utxo,
 		}
 
 		if err := mwebSweeper.SignMwebSweepTx(authoredTx, feeRate); err != nil {
-			return nil, fmt.Errorf("unable to sign mweb sweep "+
+			return nil, 0, fmt.Errorf("unable to sign mweb sweep "+
 				"tx (relay fee adjustment): %w", err)
 		}
+
+		totalFee += deficit
 	}
 
-	return authoredTx.Tx, nil
+	return authoredTx.Tx, totalFee, nil
 }
 
 // fetchUtxosFromOutpoints returns UTXOs for given outpoints. Errors if any
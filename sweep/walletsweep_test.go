@@ -396,6 +396,10 @@ func TestCraftSweepAllTx(t *testing.T) {
 			output.PkScript)
 	}
 
+	// As no fees are paid, the reported fees should be zero too.
+	require.Zero(t, sweepPkg.Fee)
+	require.Zero(t, sweepPkg.MwebFee)
+
 	// If we cancel the sweep attempt, then we should find that all the
 	// UTXOs within the sweep transaction are now unlocked.
 	sweepPkg.CancelSweepAttempt()
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ltcsuite/lnd/build"
+	"github.com/ltcsuite/lnd/lncfg"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Start installs a global OpenTelemetry tracer provider that exports the spans
+// recorded by lnd to the OTLP collector of the given config. The subsystems of
+// lnd record their spans through the global provider, so no spans are recorded
+// unless Start is called. The returned function flushes any spans that
+// haven't been exported yet and shuts the exporter down.
+func Start(ctx context.Context,
+	cfg *lncfg.Tracing) (func(context.Context) error, error) {
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		// Use the system's root certificates to verify the collector.
+		creds := credentials.NewClientTLSFromCert(nil, "")
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(creds))
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP exporter: %w",
+			err)
+	}
+
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+		semconv.ServiceVersionKey.String(build.Version()),
+	)
+
+	// New traces are sampled with the configured ratio, while traces that
+	// were started by the caller of an RPC follow the caller's decision.
+	sampler := sdktrace.ParentBased(
+		sdktrace.TraceIDRatioBased(cfg.SampleRatio),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Errorf("Unable to export spans: %v", err)
+	}))
+
+	log.Infof("Exporting traces to OTLP collector at %v", cfg.Endpoint)
+
+	return provider.Shutdown, nil
+}
+
+// ServerOptions returns the gRPC server options that record a span for every
+// RPC call. If the caller propagated the context of its own trace, the span is
+// recorded as part of it. The context of the span is passed on to the RPC
+// handler, so that any spans recorded while handling the call become its
+// children.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(otelgrpc.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(otelgrpc.StreamServerInterceptor()),
+	}
+}
@@ -0,0 +1,190 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// mockCollector is a stand-in for an OTLP collector that stores all spans it
+// receives.
+type mockCollector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu          sync.Mutex
+	spans       []*tracepb.Span
+	serviceName string
+}
+
+// Export stores the spans of the given request.
+//
+// NOTE: This is part of the collectortrace.TraceServiceServer interface.
+func (m *mockCollector) Export(_ context.Context,
+	req *collectortrace.ExportTraceServiceRequest) (
+	*collectortrace.ExportTraceServiceResponse, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, resourceSpans := range req.ResourceSpans {
+		for _, attr := range resourceSpans.Resource.Attributes {
+			if attr.Key == "service.name" {
+				m.serviceName = attr.Value.GetStringValue()
+			}
+		}
+
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			m.spans = append(m.spans, scopeSpans.Spans...)
+		}
+	}
+
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+// span returns the exported span with the given name.
+func (m *mockCollector) span(t *testing.T, name string) *tracepb.Span {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, span := range m.spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	t.Fatalf("span %v not exported", name)
+
+	return nil
+}
+
+// startCollector starts a mock collector that listens on a local port.
+func startCollector(t *testing.T) (*mockCollector, string) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	collector := &mockCollector{}
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, collector)
+
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return collector, lis.Addr().String()
+}
+
+// TestTracing asserts that the spans of RPC calls and of the subsystems that
+// handle them are exported to the collector as part of the caller's trace.
+func TestTracing(t *testing.T) {
+	collector, endpoint := startCollector(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	shutdown, err := Start(ctx, &lncfg.Tracing{
+		Active:      true,
+		Endpoint:    endpoint,
+		Insecure:    true,
+		ServiceName: "lnd-test",
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	// Serve an RPC with the tracing server options, and record a span of
+	// a subsystem while handling it.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(ServerOptions()...)
+	healthpb.RegisterHealthServer(server, &tracedHealthServer{
+		Server: health.NewServer(),
+	})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The caller propagates the context of its own trace with the call.
+	traceID := trace.TraceID{1, 2, 3}
+	callerSpan := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(
+		trace.ContextWithRemoteSpanContext(ctx, callerSpan), carrier,
+	)
+	callCtx := metadata.NewOutgoingContext(ctx, metadata.New(carrier))
+
+	client := healthpb.NewHealthClient(conn)
+	_, err = client.Check(callCtx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	// Shutting down flushes all spans to the collector.
+	require.NoError(t, shutdown(ctx))
+
+	require.Equal(t, "lnd-test", collector.serviceName)
+
+	rpcSpan := collector.span(t, "grpc.health.v1.Health/Check")
+	require.Equal(t, traceID[:], rpcSpan.TraceId)
+	require.Equal(
+		t, callerSpan.SpanID().String(), spanID(rpcSpan.ParentSpanId),
+	)
+
+	handlerSpan := collector.span(t, "health.Check")
+	require.Equal(t, traceID[:], handlerSpan.TraceId)
+	require.Equal(t, rpcSpan.SpanId, handlerSpan.ParentSpanId)
+}
+
+// tracedHealthServer is a health server that records a span for every check,
+// standing in for the subsystems of lnd that handle an RPC call.
+type tracedHealthServer struct {
+	*health.Server
+}
+
+// Check records a span as part of the trace of the RPC call.
+func (s *tracedHealthServer) Check(ctx context.Context,
+	req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse,
+	error) {
+
+	_, span := otel.Tracer("tracing_test").Start(ctx, "health.Check")
+	defer span.End()
+
+	return s.Server.Check(ctx, req)
+}
+
+// spanID returns the string representation of the given raw span ID.
+func spanID(raw []byte) string {
+	var id trace.SpanID
+	copy(id[:], raw)
+
+	return id.String()
+}
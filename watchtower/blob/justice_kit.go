@@ -81,6 +81,12 @@ var (
 		"cannot obtain commit to-remote p2wkh output script from blob",
 	)
 
+	// ErrNotTaprootChannel is returned when trying to retrieve a tapscript
+	// tree from a blob that isn't meant for a taproot channel.
+	ErrNotTaprootChannel = errors.New(
+		"blob type is not for a taproot channel",
+	)
+
 	// ErrSweepAddressToLong is returned when trying to encode or decode a
 	// sweep address with length greater than the maximum length of 42
 	// bytes, which supports p2wkh and p2sh addresses.
@@ -133,7 +139,7 @@ type JusticeKit struct {
 	CSVDelay uint32
 
 	// CommitToLocalSig is a signature under RevocationPubKey using
-	// SIGHASH_ALL.
+	// SIGHASH_ALL, or SIGHASH_DEFAULT for taproot channels.
 	CommitToLocalSig lnwire.Sig
 
 	// CommitToRemotePubKey is the public key in the to-remote output of the revoked
@@ -143,7 +149,8 @@ type JusticeKit struct {
 	// public key.
 	CommitToRemotePubKey PubKey
 
-	// CommitToRemoteSig is a signature under CommitToRemotePubKey using SIGHASH_ALL.
+	// CommitToRemoteSig is a signature under CommitToRemotePubKey using
+	// SIGHASH_ALL, or SIGHASH_DEFAULT for taproot channels.
 	//
 	// NOTE: This value is only used if CommitToRemotePubKey contains a valid
 	// compressed public key.
@@ -151,8 +158,18 @@ type JusticeKit struct {
 }
 
 // CommitToLocalWitnessScript returns the serialized witness script for the
-// commitment to-local output.
+// commitment to-local output. For taproot channels, this is the revocation
+// leaf of the to-local tapscript tree.
 func (b *JusticeKit) CommitToLocalWitnessScript() ([]byte, error) {
+	if b.BlobType.IsTaprootChannel() {
+		scriptTree, err := b.CommitToLocalScriptTree()
+		if err != nil {
+			return nil, err
+		}
+
+		return scriptTree.RevocationLeaf.Script, nil
+	}
+
 	revocationPubKey, err := btcec.ParsePubKey(
 		b.RevocationPubKey[:],
 	)
@@ -172,16 +189,55 @@ func (b *JusticeKit) CommitToLocalWitnessScript() ([]byte, error) {
 	)
 }
 
+// CommitToLocalScriptTree returns the tapscript tree of the commitment
+// to-local output of a taproot channel. An error is returned if the blob is
+// not meant for a taproot channel.
+func (b *JusticeKit) CommitToLocalScriptTree() (*input.CommitScriptTree,
+	error) {
+
+	if !b.BlobType.IsTaprootChannel() {
+		return nil, ErrNotTaprootChannel
+	}
+
+	revocationPubKey, err := btcec.ParsePubKey(
+		b.RevocationPubKey[:],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	localDelayedPubKey, err := btcec.ParsePubKey(
+		b.LocalDelayPubKey[:],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return input.NewLocalCommitScriptTree(
+		b.CSVDelay, localDelayedPubKey, revocationPubKey,
+	)
+}
+
 // CommitToLocalRevokeWitnessStack constructs a witness stack spending the
-// revocation clause of the commitment to-local output.
+// revocation clause of the commitment to-local output. For segwit v0
+// channels the stack is:
 //
 //	<revocation-sig> 1
+//
+// For taproot channels the revocation leaf only requires a schnorr signature
+// using SIGHASH_DEFAULT, so the stack is:
+//
+//	<revocation-sig>
 func (b *JusticeKit) CommitToLocalRevokeWitnessStack() ([][]byte, error) {
 	toLocalSig, err := b.CommitToLocalSig.ToSignature()
 	if err != nil {
 		return nil, err
 	}
 
+	if b.BlobType.IsTaprootChannel() {
+		return [][]byte{toLocalSig.Serialize()}, nil
+	}
+
 	witnessStack := make([][]byte, 2)
 	witnessStack[0] = append(toLocalSig.Serialize(),
 		byte(txscript.SigHashAll))
@@ -198,16 +254,28 @@ func (b *JusticeKit) HasCommitToRemoteOutput() bool {
 
 // CommitToRemoteWitnessScript returns the witness script for the commitment
 // to-remote output given the blob type. The script returned will either be for
-// a p2wpkh to-remote output or an p2wsh anchor to-remote output which includes
-// a CSV delay.
+// a p2wpkh to-remote output, an p2wsh anchor to-remote output which includes
+// a CSV delay, or the CSV delayed tapscript leaf of a taproot to-remote
+// output.
 func (b *JusticeKit) CommitToRemoteWitnessScript() ([]byte, error) {
 	if !btcec.IsCompressedPubKey(b.CommitToRemotePubKey[:]) {
 		return nil, ErrNoCommitToRemoteOutput
 	}
 
+	switch {
+	// If this is a blob for a taproot channel, we'll return the settle
+	// leaf of the to-remote tapscript tree.
+	case b.BlobType.IsTaprootChannel():
+		scriptTree, err := b.CommitToRemoteScriptTree()
+		if err != nil {
+			return nil, err
+		}
+
+		return scriptTree.SettleLeaf.Script, nil
+
 	// If this is a blob for an anchor channel, we'll return the p2wsh
 	// output containing a CSV delay of 1.
-	if b.BlobType.IsAnchorChannel() {
+	case b.BlobType.IsAnchorChannel():
 		pk, err := btcec.ParsePubKey(b.CommitToRemotePubKey[:])
 		if err != nil {
 			return nil, err
@@ -219,17 +287,46 @@ func (b *JusticeKit) CommitToRemoteWitnessScript() ([]byte, error) {
 	return b.CommitToRemotePubKey[:], nil
 }
 
+// CommitToRemoteScriptTree returns the tapscript tree of the commitment
+// to-remote output of a taproot channel. An error is returned if the blob is
+// not meant for a taproot channel, or doesn't contain a to-remote pubkey.
+func (b *JusticeKit) CommitToRemoteScriptTree() (*input.CommitScriptTree,
+	error) {
+
+	if !b.BlobType.IsTaprootChannel() {
+		return nil, ErrNotTaprootChannel
+	}
+
+	if !btcec.IsCompressedPubKey(b.CommitToRemotePubKey[:]) {
+		return nil, ErrNoCommitToRemoteOutput
+	}
+
+	toRemotePubKey, err := btcec.ParsePubKey(b.CommitToRemotePubKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return input.NewRemoteCommitScriptTree(toRemotePubKey)
+}
+
 // CommitToRemoteWitnessStack returns a witness stack spending the commitment
 // to-remote output, which consists of a single signature satisfying either the
-// legacy or anchor witness scripts.
+// legacy, anchor or taproot witness scripts.
 //
 //	<to-remote-sig>
+//
+// Signatures for taproot channels use SIGHASH_DEFAULT, so no sighash flag is
+// appended to them.
 func (b *JusticeKit) CommitToRemoteWitnessStack() ([][]byte, error) {
 	toRemoteSig, err := b.CommitToRemoteSig.ToSignature()
 	if err != nil {
 		return nil, err
 	}
 
+	if b.BlobType.IsTaprootChannel() {
+		return [][]byte{toRemoteSig.Serialize()}, nil
+	}
+
 	witnessStack := make([][]byte, 1)
 	witnessStack[0] = append(toRemoteSig.Serialize(),
 		byte(txscript.SigHashAll))
@@ -478,7 +575,7 @@ func (b *JusticeKit) decodeV0(r io.Reader) error {
 		return err
 	}
 
-	b.CommitToLocalSig, err = lnwire.NewSigFromWireECDSA(localSig[:])
+	b.CommitToLocalSig, err = b.parseSig(localSig[:])
 	if err != nil {
 		return err
 	}
@@ -504,9 +601,7 @@ func (b *JusticeKit) decodeV0(r io.Reader) error {
 	// valid compressed public key was read from the reader.
 	if btcec.IsCompressedPubKey(commitToRemotePubkey[:]) {
 		b.CommitToRemotePubKey = commitToRemotePubkey
-		b.CommitToRemoteSig, err = lnwire.NewSigFromWireECDSA(
-			commitToRemoteSig[:],
-		)
+		b.CommitToRemoteSig, err = b.parseSig(commitToRemoteSig[:])
 		if err != nil {
 			return err
 		}
@@ -514,3 +609,14 @@ func (b *JusticeKit) decodeV0(r io.Reader) error {
 
 	return nil
 }
+
+// parseSig parses a 64-byte signature read from an encoded blob. Taproot
+// channels are spent using schnorr signatures, while all other channel types
+// use ECDSA signatures in their fixed-size wire encoding.
+func (b *JusticeKit) parseSig(sig []byte) (lnwire.Sig, error) {
+	if b.BlobType.IsTaprootChannel() {
+		return lnwire.NewSigFromSchnorrRawSignature(sig)
+	}
+
+	return lnwire.NewSigFromWireECDSA(sig)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ltcsuite/lnd/watchtower/blob"
 	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
 	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/stretchr/testify/require"
 )
@@ -35,6 +36,14 @@ func makeSig(i int) lnwire.Sig {
 	return sig
 }
 
+func makeSchnorrSig(i int) lnwire.Sig {
+	var sigBytes [64]byte
+	binary.BigEndian.PutUint64(sigBytes[:8], uint64(i))
+
+	sig, _ := lnwire.NewSigFromSchnorrRawSignature(sigBytes[:])
+	return sig
+}
+
 func makeAddr(size int) []byte {
 	addr := make([]byte, size)
 	if _, err := io.ReadFull(rand.Reader, addr); err != nil {
@@ -84,6 +93,19 @@ var descriptorTests = []descriptorTest{
 		commitToRemotePubKey: makePubKey(2),
 		commitToRemoteSig:    makeSig(2),
 	},
+	{
+		name:                 "taproot to-local and to-remote",
+		encVersion:           blob.TypeAltruistTaprootCommit,
+		decVersion:           blob.TypeAltruistTaprootCommit,
+		sweepAddr:            makeAddr(34),
+		revPubKey:            makePubKey(0),
+		delayPubKey:          makePubKey(1),
+		csvDelay:             144,
+		commitToLocalSig:     makeSchnorrSig(1),
+		hasCommitToRemote:    true,
+		commitToRemotePubKey: makePubKey(2),
+		commitToRemoteSig:    makeSchnorrSig(2),
+	},
 	{
 		name:             "unknown encrypt version",
 		encVersion:       0,
@@ -385,3 +407,105 @@ func TestJusticeKitToLocalWitnessConstruction(t *testing.T) {
 	}
 	require.Equal(t, expWitnessStack, toLocalWitnessStack)
 }
+
+// TestJusticeKitTaprootWitnessConstruction tests that a JusticeKit for a
+// taproot channel returns the tapscript leaves and witness stacks required to
+// spend the revocation path of the to-local output and the to-remote output.
+func TestJusticeKitTaprootWitnessConstruction(t *testing.T) {
+	csvDelay := uint32(144)
+
+	// Generate the revocation, delay and to-remote private keys.
+	revPrivKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	delayPrivKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	toRemotePrivKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// Sign a message using the revocation and to-remote private keys. The
+	// exact message doesn't matter as we won't be validating the
+	// signatures.
+	digest := bytes.Repeat([]byte("a"), 32)
+	rawRevSig, err := schnorr.Sign(revPrivKey, digest)
+	require.NoError(t, err)
+
+	rawToRemoteSig, err := schnorr.Sign(toRemotePrivKey, digest)
+	require.NoError(t, err)
+
+	commitToLocalSig, err := lnwire.NewSigFromSignature(rawRevSig)
+	require.NoError(t, err)
+
+	commitToRemoteSig, err := lnwire.NewSigFromSignature(rawToRemoteSig)
+	require.NoError(t, err)
+
+	var revPubKey, delayPubKey, toRemotePubKey blob.PubKey
+	copy(revPubKey[:], revPrivKey.PubKey().SerializeCompressed())
+	copy(delayPubKey[:], delayPrivKey.PubKey().SerializeCompressed())
+	copy(toRemotePubKey[:], toRemotePrivKey.PubKey().SerializeCompressed())
+
+	justiceKit := &blob.JusticeKit{
+		BlobType:             blob.TypeAltruistTaprootCommit,
+		CSVDelay:             csvDelay,
+		RevocationPubKey:     revPubKey,
+		LocalDelayPubKey:     delayPubKey,
+		CommitToLocalSig:     commitToLocalSig,
+		CommitToRemotePubKey: toRemotePubKey,
+		CommitToRemoteSig:    commitToRemoteSig,
+	}
+
+	// The to-local witness script should be the revocation leaf of the
+	// to-local script tree.
+	expToLocalTree, err := input.NewLocalCommitScriptTree(
+		csvDelay, delayPrivKey.PubKey(), revPrivKey.PubKey(),
+	)
+	require.NoError(t, err)
+
+	toLocalTree, err := justiceKit.CommitToLocalScriptTree()
+	require.NoError(t, err)
+	require.True(t, expToLocalTree.TaprootKey.IsEqual(
+		toLocalTree.TaprootKey,
+	))
+
+	toLocalScript, err := justiceKit.CommitToLocalWitnessScript()
+	require.NoError(t, err)
+	require.Equal(t, expToLocalTree.RevocationLeaf.Script, toLocalScript)
+
+	// The to-local witness stack should consist solely of the schnorr
+	// signature, without any sighash flag.
+	toLocalWitnessStack, err := justiceKit.CommitToLocalRevokeWitnessStack()
+	require.NoError(t, err)
+	require.Equal(
+		t, [][]byte{rawRevSig.Serialize()}, toLocalWitnessStack,
+	)
+
+	// The to-remote witness script should be the settle leaf of the
+	// to-remote script tree.
+	expToRemoteTree, err := input.NewRemoteCommitScriptTree(
+		toRemotePrivKey.PubKey(),
+	)
+	require.NoError(t, err)
+
+	toRemoteTree, err := justiceKit.CommitToRemoteScriptTree()
+	require.NoError(t, err)
+	require.True(t, expToRemoteTree.TaprootKey.IsEqual(
+		toRemoteTree.TaprootKey,
+	))
+
+	toRemoteScript, err := justiceKit.CommitToRemoteWitnessScript()
+	require.NoError(t, err)
+	require.Equal(t, expToRemoteTree.SettleLeaf.Script, toRemoteScript)
+
+	toRemoteWitnessStack, err := justiceKit.CommitToRemoteWitnessStack()
+	require.NoError(t, err)
+	require.Equal(
+		t, [][]byte{rawToRemoteSig.Serialize()}, toRemoteWitnessStack,
+	)
+
+	// Finally, a blob for a segwit v0 channel should refuse to return a
+	// tapscript tree.
+	justiceKit.BlobType = blob.TypeAltruistAnchorCommit
+	_, err = justiceKit.CommitToLocalScriptTree()
+	require.ErrorIs(t, err, blob.ErrNotTaprootChannel)
+}
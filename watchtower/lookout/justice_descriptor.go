@@ -56,6 +56,10 @@ type breachedInput struct {
 // commitToLocalInput extracts the information required to spend the commit
 // to-local output.
 func (p *JusticeDescriptor) commitToLocalInput() (*breachedInput, error) {
+	if p.JusticeKit.BlobType.IsTaprootChannel() {
+		return p.taprootCommitToLocalInput()
+	}
+
 	// Retrieve the to-local witness script from the justice kit.
 	toLocalScript, err := p.JusticeKit.CommitToLocalWitnessScript()
 	if err != nil {
@@ -101,6 +105,10 @@ func (p *JusticeDescriptor) commitToLocalInput() (*breachedInput, error) {
 // commitToRemoteInput extracts the information required to spend the commit
 // to-remote output.
 func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
+	if p.JusticeKit.BlobType.IsTaprootChannel() {
+		return p.taprootCommitToRemoteInput()
+	}
+
 	// Retrieve the to-remote witness script from the justice kit.
 	toRemoteScript, err := p.JusticeKit.CommitToRemoteWitnessScript()
 	if err != nil {
@@ -168,6 +176,120 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 	}, nil
 }
 
+// taprootCommitToLocalInput extracts the information required to spend the
+// commit to-local output of a taproot channel via its revocation leaf.
+func (p *JusticeDescriptor) taprootCommitToLocalInput() (*breachedInput,
+	error) {
+
+	// Reconstruct the to-local tapscript tree from the justice kit, which
+	// gives us both the output key and the revocation leaf.
+	scriptTree, err := p.JusticeKit.CommitToLocalScriptTree()
+	if err != nil {
+		return nil, err
+	}
+
+	toLocalPkScript, err := input.PayToTaprootScript(scriptTree.TaprootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Locate the to-local output on the breaching commitment transaction.
+	toLocalIndex, toLocalTxOut, err := findTxOutByPkScript(
+		p.BreachedCommitTx, toLocalPkScript,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The revocation leaf is revealed along with the control block proving
+	// its inclusion in the output key.
+	ctrlBlock, err := scriptTree.CtrlBlockForPath(
+		input.ScriptPathRevocation,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ctrlBlockBytes, err := ctrlBlock.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve to-local witness stack, which is just a schnorr signature
+	// under the revocation pubkey.
+	witnessStack, err := p.JusticeKit.CommitToLocalRevokeWitnessStack()
+	if err != nil {
+		return nil, err
+	}
+
+	return &breachedInput{
+		txOut: toLocalTxOut,
+		outPoint: wire.OutPoint{
+			Hash:  p.BreachedCommitTx.TxHash(),
+			Index: toLocalIndex,
+		},
+		witness: buildTaprootWitness(
+			witnessStack, scriptTree.RevocationLeaf.Script,
+			ctrlBlockBytes,
+		),
+	}, nil
+}
+
+// taprootCommitToRemoteInput extracts the information required to spend the
+// commit to-remote output of a taproot channel via its CSV delayed leaf.
+func (p *JusticeDescriptor) taprootCommitToRemoteInput() (*breachedInput,
+	error) {
+
+	// Reconstruct the to-remote tapscript tree from the justice kit.
+	scriptTree, err := p.JusticeKit.CommitToRemoteScriptTree()
+	if err != nil {
+		return nil, err
+	}
+
+	toRemotePkScript, err := input.PayToTaprootScript(
+		scriptTree.TaprootKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Locate the to-remote output on the breaching commitment transaction.
+	toRemoteIndex, toRemoteTxOut, err := findTxOutByPkScript(
+		p.BreachedCommitTx, toRemotePkScript,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ctrlBlock, err := scriptTree.CtrlBlockForPath(input.ScriptPathSuccess)
+	if err != nil {
+		return nil, err
+	}
+	ctrlBlockBytes, err := ctrlBlock.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve the to-remote witness stack, which is just a schnorr
+	// signature under the to-remote pubkey.
+	witnessStack, err := p.JusticeKit.CommitToRemoteWitnessStack()
+	if err != nil {
+		return nil, err
+	}
+
+	return &breachedInput{
+		txOut: toRemoteTxOut,
+		outPoint: wire.OutPoint{
+			Hash:  p.BreachedCommitTx.TxHash(),
+			Index: toRemoteIndex,
+		},
+		witness: buildTaprootWitness(
+			witnessStack, scriptTree.SettleLeaf.Script,
+			ctrlBlockBytes,
+		),
+		sequence: 1,
+	}, nil
+}
+
 // assembleJusticeTxn accepts the breached inputs recovered from state update
 // and attempts to construct the justice transaction that sweeps the victims
 // funds to their wallet and claims the watchtower's reward.
@@ -224,6 +346,11 @@ func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
 		return nil, fmt.Errorf("error creating previous output "+
 			"fetcher: %v", err)
 	}
+
+	// Taproot inputs commit to all previous outputs in their sighash, so
+	// the sighash midstate must be computed up front for the validation
+	// below.
+	hashCache := txscript.NewTxSigHashes(justiceTxn, prevOutFetcher)
 	for _, inp := range inputs {
 		// Lookup the input's new post-sort position.
 		i := inputIndex[inp.outPoint]
@@ -234,7 +361,7 @@ func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
 		vm, err := txscript.NewEngine(
 			inp.txOut.PkScript, justiceTxn, i,
 			txscript.StandardVerifyFlags,
-			nil, nil, inp.txOut.Value, prevOutFetcher,
+			nil, hashCache, inp.txOut.Value, prevOutFetcher,
 		)
 		if err != nil {
 			return nil, err
@@ -295,9 +422,16 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 	// values on the sweep transaction, so we mimic the original bug to
 	// avoid invalidating signatures by older clients. For anchor channels
 	// we correct this and use the correct witness size.
-	if p.JusticeKit.BlobType.IsAnchorChannel() {
+	switch {
+	case p.JusticeKit.BlobType.IsTaprootChannel():
+		weightEstimate.AddWitnessInput(
+			input.TaprootToLocalRevokeWitnessSize,
+		)
+
+	case p.JusticeKit.BlobType.IsAnchorChannel():
 		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
-	} else {
+
+	default:
 		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize - 1)
 	}
 
@@ -319,9 +453,16 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 		log.Debugf("Found to remote witness output=%#v, stack=%v",
 			toRemoteInput.txOut, toRemoteInput.witness)
 
-		if p.JusticeKit.BlobType.IsAnchorChannel() {
+		switch {
+		case p.JusticeKit.BlobType.IsTaprootChannel():
+			weightEstimate.AddWitnessInput(
+				input.TaprootToRemoteWitnessSize,
+			)
+
+		case p.JusticeKit.BlobType.IsAnchorChannel():
 			weightEstimate.AddWitnessInput(input.ToRemoteConfirmedWitnessSize)
-		} else {
+
+		default:
 			weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
 		}
 	}
@@ -358,6 +499,17 @@ func buildWitness(witnessStack [][]byte, witnessScript []byte) [][]byte {
 	return witness
 }
 
+// buildTaprootWitness appends the revealed tapscript leaf and its control
+// block to a given witness stack.
+func buildTaprootWitness(witnessStack [][]byte, leafScript,
+	ctrlBlock []byte) [][]byte {
+
+	witness := make([][]byte, 0, len(witnessStack)+2)
+	witness = append(witness, witnessStack...)
+
+	return append(witness, leafScript, ctrlBlock)
+}
+
 // prevOutFetcher returns a txscript.MultiPrevOutFetcher for the given set
 // of inputs.
 func prevOutFetcher(inputs []*breachedInput) (*txscript.MultiPrevOutFetcher,
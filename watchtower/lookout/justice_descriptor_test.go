@@ -52,6 +52,8 @@ var (
 	altruistCommitType = blob.FlagCommitOutputs.Type()
 
 	altruistAnchorCommitType = blob.TypeAltruistAnchorCommit
+
+	altruistTaprootCommitType = blob.TypeAltruistTaprootCommit
 )
 
 // TestJusticeDescriptor asserts that a JusticeDescriptor is able to produce the
@@ -73,6 +75,10 @@ func TestJusticeDescriptor(t *testing.T) {
 			name:     "altruist anchor commit type",
 			blobType: altruistAnchorCommitType,
 		},
+		{
+			name:     "altruist taproot commit type",
+			blobType: altruistTaprootCommitType,
+		},
 	}
 
 	for _, test := range tests {
@@ -84,6 +90,7 @@ func TestJusticeDescriptor(t *testing.T) {
 
 func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	isAnchorChannel := blobType.IsAnchorChannel()
+	isTaprootChannel := blobType.IsTaprootChannel()
 
 	const (
 		localAmount  = ltcutil.Amount(100000)
@@ -109,15 +116,51 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		toRemoteKeyLoc = signer.AddPrivKey(toRemoteSK)
 	)
 
-	// Construct the to-local witness script.
-	toLocalScript, err := input.CommitScriptToSelf(
-		csvDelay, toLocalPK, revPK,
+	// Construct the to-local witness script and compute the to-local
+	// pkscript. For taproot channels, the witness script is the revocation
+	// leaf, which must be revealed along with its control block.
+	var (
+		toLocalScript      []byte
+		toLocalScriptHash  []byte
+		toLocalCtrlBlock   []byte
+		toRemoteCtrlBlock  []byte
+		toLocalSignMethod  input.SignMethod
+		toRemoteSignMethod input.SignMethod
+		sigHashType        = txscript.SigHashAll
+		err                error
 	)
-	require.Nil(t, err)
+	if isTaprootChannel {
+		toLocalTree, err := input.NewLocalCommitScriptTree(
+			csvDelay, toLocalPK, revPK,
+		)
+		require.NoError(t, err)
 
-	// Compute the to-local witness script hash.
-	toLocalScriptHash, err := input.WitnessScriptHash(toLocalScript)
-	require.Nil(t, err)
+		toLocalScript = toLocalTree.RevocationLeaf.Script
+		toLocalScriptHash, err = input.PayToTaprootScript(
+			toLocalTree.TaprootKey,
+		)
+		require.NoError(t, err)
+
+		ctrlBlock, err := toLocalTree.CtrlBlockForPath(
+			input.ScriptPathRevocation,
+		)
+		require.NoError(t, err)
+		toLocalCtrlBlock, err = ctrlBlock.ToBytes()
+		require.NoError(t, err)
+
+		toLocalSignMethod = input.TaprootScriptSpendSignMethod
+		toRemoteSignMethod = input.TaprootScriptSpendSignMethod
+		sigHashType = txscript.SigHashDefault
+	} else {
+		toLocalScript, err = input.CommitScriptToSelf(
+			csvDelay, toLocalPK, revPK,
+		)
+		require.Nil(t, err)
+
+		// Compute the to-local witness script hash.
+		toLocalScriptHash, err = input.WitnessScriptHash(toLocalScript)
+		require.Nil(t, err)
+	}
 
 	// Compute the to-remote redeem script, witness script hash, and
 	// sequence numbers.
@@ -142,7 +185,30 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		toRemoteScriptHash    []byte
 		toRemoteSigningScript []byte
 	)
-	if isAnchorChannel {
+	switch {
+	case isTaprootChannel:
+		toRemoteSequence = 1
+		toRemoteTree, err := input.NewRemoteCommitScriptTree(
+			toRemotePK,
+		)
+		require.NoError(t, err)
+
+		toRemoteRedeemScript = toRemoteTree.SettleLeaf.Script
+		toRemoteScriptHash, err = input.PayToTaprootScript(
+			toRemoteTree.TaprootKey,
+		)
+		require.NoError(t, err)
+
+		ctrlBlock, err := toRemoteTree.CtrlBlockForPath(
+			input.ScriptPathSuccess,
+		)
+		require.NoError(t, err)
+		toRemoteCtrlBlock, err = ctrlBlock.ToBytes()
+		require.NoError(t, err)
+
+		toRemoteSigningScript = toRemoteRedeemScript
+
+	case isAnchorChannel:
 		toRemoteSequence = 1
 		toRemoteRedeemScript, err = input.CommitScriptToRemoteConfirmed(
 			toRemotePK,
@@ -156,7 +222,8 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 
 		// As it should be.
 		toRemoteSigningScript = toRemoteRedeemScript
-	} else {
+
+	default:
 		toRemoteRedeemScript = toRemotePK.SerializeCompressed()
 		toRemoteScriptHash, err = input.CommitScriptUnencumbered(
 			toRemotePK,
@@ -193,15 +260,19 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	// values on the sweep transaction, so we mimic the original bug and
 	// create signatures using the original weight estimate. For anchor
 	// channels we fix this and use the correct witness size.
-	if isAnchorChannel {
-		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
-	} else {
-		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize - 1)
-	}
+	switch {
+	case isTaprootChannel:
+		weightEstimate.AddWitnessInput(
+			input.TaprootToLocalRevokeWitnessSize,
+		)
+		weightEstimate.AddWitnessInput(input.TaprootToRemoteWitnessSize)
 
-	if isAnchorChannel {
+	case isAnchorChannel:
+		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize)
 		weightEstimate.AddWitnessInput(input.ToRemoteConfirmedWitnessSize)
-	} else {
+
+	default:
+		weightEstimate.AddWitnessInput(input.ToLocalPenaltyWitnessSize - 1)
 		weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
 	}
 	weightEstimate.AddP2WKHOutput()
@@ -271,16 +342,26 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 
 	hashCache := input.NewTxSigHashesV0Only(justiceTxn)
 
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for i, txOut := range breachTxn.TxOut {
+		prevOutFetcher.AddPrevOut(wire.OutPoint{
+			Hash:  breachTxID,
+			Index: uint32(i),
+		}, txOut)
+	}
+
 	// Create the sign descriptor used to sign for the to-local input.
 	toLocalSignDesc := &input.SignDescriptor{
 		KeyDesc: keychain.KeyDescriptor{
 			KeyLocator: revKeyLoc,
 		},
-		WitnessScript: toLocalScript,
-		Output:        breachTxn.TxOut[0],
-		SigHashes:     hashCache,
-		InputIndex:    0,
-		HashType:      txscript.SigHashAll,
+		WitnessScript:     toLocalScript,
+		Output:            breachTxn.TxOut[0],
+		SigHashes:         hashCache,
+		InputIndex:        0,
+		HashType:          sigHashType,
+		SignMethod:        toLocalSignMethod,
+		PrevOutputFetcher: prevOutFetcher,
 	}
 
 	// Create the sign descriptor used to sign for the to-remote input.
@@ -289,11 +370,13 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 			KeyLocator: toRemoteKeyLoc,
 			PubKey:     toRemotePK,
 		},
-		WitnessScript: toRemoteSigningScript,
-		Output:        breachTxn.TxOut[1],
-		SigHashes:     hashCache,
-		InputIndex:    1,
-		HashType:      txscript.SigHashAll,
+		WitnessScript:     toRemoteSigningScript,
+		Output:            breachTxn.TxOut[1],
+		SigHashes:         hashCache,
+		InputIndex:        1,
+		HashType:          sigHashType,
+		SignMethod:        toRemoteSignMethod,
+		PrevOutputFetcher: prevOutFetcher,
 	}
 
 	// Verify that our test justice transaction is sane.
@@ -352,18 +435,33 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		t.Fatalf("punisher did not publish justice txn")
 	}
 
-	// Construct the test's to-local witness.
-	justiceTxn.TxIn[0].Witness = make([][]byte, 3)
-	justiceTxn.TxIn[0].Witness[0] = append(toLocalSigRaw.Serialize(),
-		byte(txscript.SigHashAll))
-	justiceTxn.TxIn[0].Witness[1] = []byte{1}
-	justiceTxn.TxIn[0].Witness[2] = toLocalScript
-
-	// Construct the test's to-remote witness.
-	justiceTxn.TxIn[1].Witness = make([][]byte, 2)
-	justiceTxn.TxIn[1].Witness[0] = append(toRemoteSigRaw.Serialize(),
-		byte(txscript.SigHashAll))
-	justiceTxn.TxIn[1].Witness[1] = toRemoteRedeemScript
+	// Construct the test's witnesses. Taproot channels reveal the leaf
+	// script and its control block, and use SIGHASH_DEFAULT signatures.
+	if isTaprootChannel {
+		justiceTxn.TxIn[0].Witness = [][]byte{
+			toLocalSigRaw.Serialize(), toLocalScript,
+			toLocalCtrlBlock,
+		}
+		justiceTxn.TxIn[1].Witness = [][]byte{
+			toRemoteSigRaw.Serialize(), toRemoteRedeemScript,
+			toRemoteCtrlBlock,
+		}
+	} else {
+		// Construct the test's to-local witness.
+		justiceTxn.TxIn[0].Witness = make([][]byte, 3)
+		justiceTxn.TxIn[0].Witness[0] = append(
+			toLocalSigRaw.Serialize(), byte(txscript.SigHashAll),
+		)
+		justiceTxn.TxIn[0].Witness[1] = []byte{1}
+		justiceTxn.TxIn[0].Witness[2] = toLocalScript
+
+		// Construct the test's to-remote witness.
+		justiceTxn.TxIn[1].Witness = make([][]byte, 2)
+		justiceTxn.TxIn[1].Witness[0] = append(
+			toRemoteSigRaw.Serialize(), byte(txscript.SigHashAll),
+		)
+		justiceTxn.TxIn[1].Witness[1] = toRemoteRedeemScript
+	}
 
 	// Assert that the watchtower derives the same justice txn.
 	require.Equal(t, justiceTxn, wtJusticeTxn)
@@ -147,9 +147,16 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody,
 	// to that output as local, though relative to their commitment, it is
 	// paying to-the-remote party (which is us).
 	if breachInfo.RemoteOutputSignDesc != nil {
+		// Taproot channels sweep the revoked output through the
+		// revocation leaf of its tapscript tree.
+		witnessType := input.WitnessType(input.CommitmentRevoke)
+		if chanType.IsTaproot() {
+			witnessType = input.TaprootCommitmentRevoke
+		}
+
 		toLocalInput = input.NewBaseInput(
 			&breachInfo.RemoteOutpoint,
-			input.CommitmentRevoke,
+			witnessType,
 			breachInfo.RemoteOutputSignDesc,
 			0,
 		)
@@ -158,6 +165,8 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody,
 	if breachInfo.LocalOutputSignDesc != nil {
 		var witnessType input.WitnessType
 		switch {
+		case chanType.IsTaproot():
+			witnessType = input.TaprootRemoteCommitSpend
 		case chanType.HasAnchors():
 			witnessType = input.CommitmentToRemoteConfirmed
 		case chanType.IsTweakless():
@@ -166,10 +175,11 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody,
 			witnessType = input.CommitmentNoDelay
 		}
 
-		// Anchor channels have a CSV-encumbered to-remote output. We'll
-		// construct a CSV input in that case and assign the proper CSV
-		// delay of 1, otherwise we fallback to the a regular P2WKH
-		// to-remote output for tweaked or tweakless channels.
+		// Anchor and taproot channels have a CSV-encumbered to-remote
+		// output. We'll construct a CSV input in that case and assign
+		// the proper CSV delay of 1, otherwise we fallback to the a
+		// regular P2WKH to-remote output for tweaked or tweakless
+		// channels.
 		if chanType.HasAnchors() {
 			toRemoteInput = input.NewCsvInput(
 				&breachInfo.LocalOutpoint,
@@ -208,12 +218,18 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody,
 		// so we mimic the original bug and create signatures using the
 		// original weight estimate. For anchor channels we'll go ahead
 		// an use the correct penalty witness when signing our justice
-		// transactions.
-		if chanType.HasAnchors() {
+		// transactions. Taproot channels spend the revocation leaf of
+		// the to-local tapscript tree.
+		switch {
+		case chanType.IsTaproot():
+			weightEstimate.AddWitnessInput(
+				input.TaprootToLocalRevokeWitnessSize,
+			)
+		case chanType.HasAnchors():
 			weightEstimate.AddWitnessInput(
 				input.ToLocalPenaltyWitnessSize,
 			)
-		} else {
+		default:
 			weightEstimate.AddWitnessInput(
 				input.ToLocalPenaltyWitnessSize - 1,
 			)
@@ -222,12 +238,18 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody,
 	if t.toRemoteInput != nil {
 		// Legacy channels (both tweaked and non-tweaked) spend from
 		// P2WKH output. Anchor channels spend a to-remote confirmed
-		// P2WSH  output.
-		if chanType.HasAnchors() {
+		// P2WSH  output, while taproot channels spend the CSV delayed
+		// leaf of a P2TR output.
+		switch {
+		case chanType.IsTaproot():
+			weightEstimate.AddWitnessInput(
+				input.TaprootToRemoteWitnessSize,
+			)
+		case chanType.HasAnchors():
 			weightEstimate.AddWitnessInput(
 				input.ToRemoteConfirmedWitnessSize,
 			)
-		} else {
+		default:
 			weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
 		}
 	}
@@ -248,10 +270,23 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody,
 		}
 	}
 
-	if chanType.HasAnchors() != session.Policy.IsAnchorChannel() {
-		log.Criticalf("Invalid task (has_anchors=%t) for session "+
-			"(has_anchors=%t)", chanType.HasAnchors(),
-			session.Policy.IsAnchorChannel())
+	// Taproot channels also have anchors, but are backed up using their
+	// own blob type rather than the anchor one. A state can't be backed
+	// up to a session of a different channel type, which could happen if
+	// the state was handed to the wrong client after the commitment type
+	// of the channel was upgraded.
+	isTaproot := chanType.IsTaproot()
+	isAnchor := chanType.HasAnchors() && !isTaproot
+	switch {
+	case isTaproot != session.Policy.IsTaprootChannel():
+		return fmt.Errorf("%w: task (is_taproot=%t), session "+
+			"(is_taproot=%t)", ErrSessionChanTypeMismatch,
+			isTaproot, session.Policy.IsTaprootChannel())
+
+	case isAnchor != session.Policy.IsAnchorChannel():
+		return fmt.Errorf("%w: task (has_anchors=%t), session "+
+			"(has_anchors=%t)", ErrSessionChanTypeMismatch,
+			isAnchor, session.Policy.IsAnchorChannel())
 	}
 
 	// Now, compute the output values depending on whether FlagReward is set
@@ -361,16 +396,10 @@ func (t *backupTask) craftSessionPayload(
 			return hint, nil, err
 		}
 
-		// Parse the DER-encoded signature from the first position of
-		// the resulting witness. We trim an extra byte to remove the
-		// sighash flag.
-		witness := inputScript.Witness
-		rawSignature := witness[0][:len(witness[0])-1]
-
-		// Re-encode the DER signature into a fixed-size 64 byte
-		// signature.
-		signature, err := lnwire.NewSigFromECDSARawSignature(
-			rawSignature,
+		// Parse the signature from the first position of the
+		// resulting witness.
+		signature, err := parseWitnessSig(
+			inputScript.Witness, t.blobType,
 		)
 		if err != nil {
 			return hint, nil, err
@@ -380,7 +409,7 @@ func (t *backupTask) craftSessionPayload(
 		// using the input's witness type to select the appropriate
 		// field
 		switch inp.WitnessType() {
-		case input.CommitmentRevoke:
+		case input.CommitmentRevoke, input.TaprootCommitmentRevoke:
 			justiceKit.CommitToLocalSig = signature
 
 		case input.CommitSpendNoDelayTweakless:
@@ -388,6 +417,8 @@ func (t *backupTask) craftSessionPayload(
 		case input.CommitmentNoDelay:
 			fallthrough
 		case input.CommitmentToRemoteConfirmed:
+			fallthrough
+		case input.TaprootRemoteCommitSpend:
 			justiceKit.CommitToRemoteSig = signature
 		default:
 			return hint, nil, fmt.Errorf("invalid witness type: %v",
@@ -411,6 +442,27 @@ func (t *backupTask) craftSessionPayload(
 	return hint, encBlob, nil
 }
 
+// parseWitnessSig parses the signature from the first position of a witness
+// crafted for one of the justice transaction's inputs. Taproot channels sign
+// using SIGHASH_DEFAULT, so the witness holds a raw 64 byte schnorr signature.
+// All other channel types hold a DER-encoded signature followed by a sighash
+// flag, which is trimmed and re-encoded into a fixed-size 64 byte signature.
+func parseWitnessSig(witness wire.TxWitness,
+	blobType blob.Type) (lnwire.Sig, error) {
+
+	if len(witness) == 0 {
+		return lnwire.Sig{}, fmt.Errorf("empty witness")
+	}
+
+	if blobType.IsTaprootChannel() {
+		return lnwire.NewSigFromSchnorrRawSignature(witness[0])
+	}
+
+	rawSignature := witness[0][:len(witness[0])-1]
+
+	return lnwire.NewSigFromECDSARawSignature(rawSignature)
+}
+
 // toBlobPubKey serializes the given pubkey into a blob.PubKey that can be set
 // as a field on a blob.JusticeKit.
 func toBlobPubKey(pubKey *btcec.PublicKey) blob.PubKey {
@@ -84,9 +84,12 @@ func genTaskTest(
 	bindErr error,
 	chanType channeldb.ChannelType) backupTaskTest {
 
-	// Set the anchor flag in the blob type if the session needs to support
-	// anchor channels.
-	if chanType.HasAnchors() {
+	// Set the anchor or taproot flag in the blob type if the session needs
+	// to support anchor or taproot channels.
+	switch {
+	case chanType.IsTaproot():
+		blobType |= blob.Type(blob.FlagTaprootChannel)
+	case chanType.HasAnchors():
 		blobType |= blob.Type(blob.FlagAnchorChannel)
 	}
 
@@ -141,6 +144,18 @@ func genTaskTest(
 			},
 			HashType: txscript.SigHashAll,
 		}
+		if chanType.IsTaproot() {
+			scriptTree, err := input.NewLocalCommitScriptTree(
+				csvDelay, toLocalPK, revPK,
+			)
+			if err != nil {
+				panic(err)
+			}
+			setTaprootSignDesc(
+				toLocalSignDesc, scriptTree,
+				input.ScriptPathRevocation,
+			)
+		}
 		breachInfo.RemoteOutputSignDesc = toLocalSignDesc
 		breachTxn.AddTxOut(toLocalSignDesc.Output)
 	}
@@ -155,6 +170,18 @@ func genTaskTest(
 			},
 			HashType: txscript.SigHashAll,
 		}
+		if chanType.IsTaproot() {
+			scriptTree, err := input.NewRemoteCommitScriptTree(
+				toRemotePK,
+			)
+			if err != nil {
+				panic(err)
+			}
+			setTaprootSignDesc(
+				toRemoteSignDesc, scriptTree,
+				input.ScriptPathSuccess,
+			)
+		}
 		breachInfo.LocalOutputSignDesc = toRemoteSignDesc
 		breachTxn.AddTxOut(toRemoteSignDesc.Output)
 	}
@@ -174,9 +201,13 @@ func genTaskTest(
 			Hash:  txid,
 			Index: index,
 		}
+		witnessType := input.WitnessType(input.CommitmentRevoke)
+		if chanType.IsTaproot() {
+			witnessType = input.TaprootCommitmentRevoke
+		}
 		toLocalInput = input.NewBaseInput(
 			&breachInfo.RemoteOutpoint,
-			input.CommitmentRevoke,
+			witnessType,
 			breachInfo.RemoteOutputSignDesc,
 			0,
 		)
@@ -190,6 +221,8 @@ func genTaskTest(
 
 		var witnessType input.WitnessType
 		switch {
+		case chanType.IsTaproot():
+			witnessType = input.TaprootRemoteCommitSpend
 		case chanType.HasAnchors():
 			witnessType = input.CommitmentToRemoteConfirmed
 		case chanType.IsTweakless():
@@ -241,6 +274,38 @@ func genTaskTest(
 	}
 }
 
+// setTaprootSignDesc populates the taproot specific fields of a sign
+// descriptor spending the given path of a commitment script tree.
+func setTaprootSignDesc(signDesc *input.SignDescriptor,
+	scriptTree *input.CommitScriptTree, path input.ScriptPath) {
+
+	pkScript, err := input.PayToTaprootScript(scriptTree.TaprootKey)
+	if err != nil {
+		panic(err)
+	}
+
+	witnessScript, err := scriptTree.WitnessScriptForPath(path)
+	if err != nil {
+		panic(err)
+	}
+
+	ctrlBlock, err := scriptTree.CtrlBlockForPath(path)
+	if err != nil {
+		panic(err)
+	}
+
+	ctrlBlockBytes, err := ctrlBlock.ToBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	signDesc.Output.PkScript = pkScript
+	signDesc.WitnessScript = witnessScript
+	signDesc.ControlBlock = ctrlBlockBytes
+	signDesc.SignMethod = input.TaprootScriptSpendSignMethod
+	signDesc.HashType = txscript.SigHashDefault
+}
+
 var (
 	blobTypeCommitNoReward = blob.FlagCommitOutputs.Type()
 
@@ -275,6 +340,7 @@ func TestBackupTask(t *testing.T) {
 		channeldb.SingleFunderBit,
 		channeldb.SingleFunderTweaklessBit,
 		channeldb.AnchorOutputsBit,
+		channeldb.AnchorOutputsBit | channeldb.SimpleTaprootFeatureBit,
 	}
 
 	var backupTaskTests []backupTaskTest
@@ -286,6 +352,8 @@ func TestBackupTask(t *testing.T) {
 		//   - anchor to-remote outputs require a P2WSH sweep rather
 		//     than a P2WKH sweep.
 		//   - the to-local weight estimate fixes an off-by-one.
+		//   - taproot outputs are swept via their tapscript leaves,
+		//     which requires revealing the leaf and a control block.
 		// In tests related to the dust threshold, the size difference
 		// between the channel types makes it so that the threshold fee
 		// rate is slightly lower (since the transactions are heavier).
@@ -299,7 +367,18 @@ func TestBackupTask(t *testing.T) {
 			sweepFeeRateNoRewardRemoteDust chainfee.SatPerKWeight = 227500
 			sweepFeeRateRewardRemoteDust   chainfee.SatPerKWeight = 175350
 		)
-		if chanType.HasAnchors() {
+		switch {
+		case chanType.IsTaproot():
+			expSweepCommitNoRewardBoth = 299165
+			expSweepCommitNoRewardLocal = 199468
+			expSweepCommitNoRewardRemote = 99531
+			expSweepCommitRewardBoth = 295993
+			expSweepCommitRewardLocal = 197296
+			expSweepCommitRewardRemote = 98359
+			sweepFeeRateNoRewardRemoteDust = 212900
+			sweepFeeRateRewardRemoteDust = 167000
+
+		case chanType.HasAnchors():
 			expSweepCommitNoRewardBoth = 299236
 			expSweepCommitNoRewardLocal = 199513
 			expSweepCommitNoRewardRemote = 99557
@@ -481,6 +560,33 @@ func TestBackupTask(t *testing.T) {
 	}
 }
 
+// TestBackupTaskChanTypeMismatch asserts that a state can't be bound to a
+// session that was negotiated for a different channel type, which happens if
+// a state of an upgraded channel is handed to the client of its old type.
+func TestBackupTaskChanTypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	test := genTaskTest(
+		"anchor state, legacy session",
+		100,                        // stateNum
+		200000,                     // toLocalAmt
+		100000,                     // toRemoteAmt
+		blobTypeCommitNoReward,     // blobType
+		1000,                       // sweepFeeRate
+		nil,                        // rewardScript
+		0,                          // expSweepAmt
+		0,                          // expRewardAmt
+		ErrSessionChanTypeMismatch, // bindErr
+		channeldb.AnchorOutputsBit,
+	)
+
+	// The session was negotiated for legacy channels, while the state
+	// belongs to an anchor channel.
+	test.session.Policy.BlobType = blobTypeCommitNoReward
+
+	testBackupTask(t, test)
+}
+
 func testBackupTask(t *testing.T, test backupTaskTest) {
 	// Create a new backupTask from the channel id and breach info.
 	id := wtdb.BackupID{
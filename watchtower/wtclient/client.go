@@ -53,8 +53,8 @@ const (
 )
 
 // genSessionFilter constructs a filter that can be used to select sessions only
-// if they match the policy of the client (namely anchor vs legacy). If
-// activeOnly is set, then only active sessions will be returned.
+// if they match the policy of the client (namely legacy vs anchor vs taproot).
+// If activeOnly is set, then only active sessions will be returned.
 func (c *TowerClient) genSessionFilter(
 	activeOnly bool) wtdb.ClientSessionFilterFn {
 
@@ -65,6 +65,12 @@ func (c *TowerClient) genSessionFilter(
 			return false
 		}
 
+		if c.cfg.Policy.IsTaprootChannel() !=
+			session.Policy.IsTaprootChannel() {
+
+			return false
+		}
+
 		if !activeOnly {
 			return true
 		}
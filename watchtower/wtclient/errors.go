@@ -35,6 +35,12 @@ var (
 	// with the client.
 	ErrUnregisteredChannel = errors.New("channel is not registered")
 
+	// ErrSessionChanTypeMismatch signals that a revoked state can't be
+	// backed up to a session, because the session was negotiated for a
+	// different channel type than the one of the state.
+	ErrSessionChanTypeMismatch = errors.New("channel type of state " +
+		"doesn't match session")
+
 	// ErrSessionKeyAlreadyUsed indicates that the client attempted to
 	// create a new session with a tower with a session key that has already
 	// been used in the past.
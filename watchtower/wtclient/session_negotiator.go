@@ -120,14 +120,18 @@ var _ SessionNegotiator = (*sessionNegotiator)(nil)
 // newSessionNegotiator initializes a fresh sessionNegotiator instance.
 func newSessionNegotiator(cfg *NegotiatorConfig) *sessionNegotiator {
 	// Generate the set of features the negotiator will present to the tower
-	// upon connection. For anchor channels, we'll conditionally signal that
-	// we require support for anchor channels depending on the requested
-	// policy.
+	// upon connection. For anchor and taproot channels, we'll conditionally
+	// signal that we require support for the channel type depending on the
+	// requested policy.
 	features := []lnwire.FeatureBit{
 		wtwire.AltruistSessionsRequired,
 	}
-	if cfg.Policy.IsAnchorChannel() {
+	switch {
+	case cfg.Policy.IsAnchorChannel():
 		features = append(features, wtwire.AnchorCommitRequired)
+
+	case cfg.Policy.IsTaprootChannel():
+		features = append(features, wtwire.TaprootCommitRequired)
 	}
 
 	localInit := wtwire.NewInitMessage(
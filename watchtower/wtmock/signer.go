@@ -47,6 +47,27 @@ func (s *MockSigner) SignOutputRaw(tx *wire.MsgTx,
 		panic("cannot sign w/ unknown key")
 	}
 
+	// Taproot outputs are spent via one of their tapscript leaves, which
+	// requires a schnorr signature over the tapscript sighash.
+	if signDesc.SignMethod == input.TaprootScriptSpendSignMethod {
+		sigHashes := txscript.NewTxSigHashes(
+			tx, signDesc.PrevOutputFetcher,
+		)
+		leaf := txscript.NewBaseTapLeaf(witnessScript)
+
+		rawSig, err := txscript.RawTxInTapscriptSignature(
+			tx, sigHashes, signDesc.InputIndex, amt,
+			signDesc.Output.PkScript, leaf, signDesc.HashType,
+			privKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Slice off the sighash flag, if any, before parsing.
+		return schnorr.ParseSignature(rawSig[:schnorr.SignatureSize])
+	}
+
 	sig, err := txscript.RawTxInWitnessSignature(
 		tx, signDesc.SigHashes, signDesc.InputIndex, amt,
 		witnessScript, signDesc.HashType, privKey,
@@ -181,6 +181,30 @@ var createSessionTests = []createSessionTestCase{
 			Data: []byte{},
 		},
 	},
+	{
+		name: "duplicate session create altruist taproot commit",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(
+				wtwire.TaprootCommitRequired,
+			),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeAltruistTaprootCommit,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 10000,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: []byte{},
+		},
+		expDupReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: []byte{},
+		},
+	},
 	{
 		name: "duplicate session create",
 		initMsg: wtwire.NewInitMessage(
@@ -0,0 +1,289 @@
+package zpay32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	sphinx "github.com/ltcsuite/lightning-onion"
+	"github.com/ltcsuite/lnd/lnwire"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/ltcutil/bech32"
+)
+
+const (
+	// maxNumHopsPerPath is the maximum number of blinded hops that we
+	// allow in a single blinded payment path.
+	maxNumHopsPerPath = 7
+
+	// maxCipherTextLength is the maximum length of the encrypted data of
+	// a single blinded hop.
+	maxCipherTextLength = 1300
+)
+
+// BlindedPaymentPath holds all the information a payer needs to know about a
+// blinded path to a receiver of a payment.
+type BlindedPaymentPath struct {
+	// FeeBaseMsat is the total base fee for the path in milli-satoshis.
+	FeeBaseMsat uint32
+
+	// FeeRate is the total fee rate for the path in parts per million.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the total CLTV delta to apply to the path, which
+	// includes the final expiry delta of the receiver.
+	CltvExpiryDelta uint16
+
+	// HTLCMinMsat is the minimum number of milli-satoshis that any hop in
+	// the path will route.
+	HTLCMinMsat uint64
+
+	// HTLCMaxMsat is the maximum number of milli-satoshis that a hop in
+	// the path will route. A value of zero means that the maximum is not
+	// known.
+	HTLCMaxMsat uint64
+
+	// Features is the feature bit vector for the path.
+	Features *lnwire.FeatureVector
+
+	// FirstEphemeralBlindingPoint is the blinding point that the
+	// introduction node uses to derive its shared secret with the
+	// receiver.
+	FirstEphemeralBlindingPoint *btcec.PublicKey
+
+	// IntroductionNode is the real public key of the first node of the
+	// path.
+	IntroductionNode *btcec.PublicKey
+
+	// Hops is the blinded path. The first hop is the introduction node.
+	Hops []*sphinx.BlindedHopInfo
+}
+
+// Copy returns a deep copy of the blinded payment path.
+func (p *BlindedPaymentPath) Copy() *BlindedPaymentPath {
+	c := *p
+	c.Hops = make([]*sphinx.BlindedHopInfo, len(p.Hops))
+	for i, hop := range p.Hops {
+		hopCopy := *hop
+		c.Hops[i] = &hopCopy
+	}
+
+	return &c
+}
+
+// SphinxPath returns the blinded path in the form expected by the sphinx
+// package.
+func (p *BlindedPaymentPath) SphinxPath() *sphinx.BlindedPath {
+	return &sphinx.BlindedPath{
+		IntroductionPoint: p.IntroductionNode,
+		BlindingPoint:     p.FirstEphemeralBlindingPoint,
+		BlindedHops:       p.Hops,
+	}
+}
+
+// BlindedPath is a functional option that allows callers of NewInvoice to
+// attach a blinded payment path to the invoice.
+func BlindedPath(path *BlindedPaymentPath) func(*Invoice) {
+	return func(i *Invoice) {
+		i.BlindedPaymentPaths = append(i.BlindedPaymentPaths, path)
+	}
+}
+
+// encodeBlindedPaymentPath serializes a blinded payment path to the passed
+// writer.
+func encodeBlindedPaymentPath(w io.Writer, p *BlindedPaymentPath) error {
+	if len(p.Hops) == 0 || len(p.Hops) > maxNumHopsPerPath {
+		return fmt.Errorf("blinded path must have between 1 and %d "+
+			"hops, got %d", maxNumHopsPerPath, len(p.Hops))
+	}
+
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[:4], p.FeeBaseMsat)
+	if _, err := w.Write(b[:4]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint32(b[:4], p.FeeRate)
+	if _, err := w.Write(b[:4]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint16(b[:2], p.CltvExpiryDelta)
+	if _, err := w.Write(b[:2]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint64(b[:], p.HTLCMinMsat)
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint64(b[:], p.HTLCMaxMsat)
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+
+	var features bytes.Buffer
+	if p.Features != nil {
+		err := p.Features.RawFeatureVector.Encode(&features)
+		if err != nil {
+			return err
+		}
+	} else {
+		binary.BigEndian.PutUint16(b[:2], 0)
+		features.Write(b[:2])
+	}
+	if _, err := w.Write(features.Bytes()); err != nil {
+		return err
+	}
+
+	_, err := w.Write(p.IntroductionNode.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(p.FirstEphemeralBlindingPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{uint8(len(p.Hops))}); err != nil {
+		return err
+	}
+
+	for _, hop := range p.Hops {
+		_, err := w.Write(hop.BlindedNodePub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		if len(hop.CipherText) > maxCipherTextLength {
+			return fmt.Errorf("encrypted data of %d bytes exceeds "+
+				"maximum of %d", len(hop.CipherText),
+				maxCipherTextLength)
+		}
+
+		binary.BigEndian.PutUint16(b[:2], uint16(len(hop.CipherText)))
+		if _, err := w.Write(b[:2]); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(hop.CipherText); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeBlindedPaymentPath deserializes a blinded payment path from the
+// passed reader.
+func decodeBlindedPaymentPath(r io.Reader) (*BlindedPaymentPath, error) {
+	var (
+		p BlindedPaymentPath
+		b [8]byte
+	)
+
+	if _, err := io.ReadFull(r, b[:4]); err != nil {
+		return nil, err
+	}
+	p.FeeBaseMsat = binary.BigEndian.Uint32(b[:4])
+
+	if _, err := io.ReadFull(r, b[:4]); err != nil {
+		return nil, err
+	}
+	p.FeeRate = binary.BigEndian.Uint32(b[:4])
+
+	if _, err := io.ReadFull(r, b[:2]); err != nil {
+		return nil, err
+	}
+	p.CltvExpiryDelta = binary.BigEndian.Uint16(b[:2])
+
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+	p.HTLCMinMsat = binary.BigEndian.Uint64(b[:])
+
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+	p.HTLCMaxMsat = binary.BigEndian.Uint64(b[:])
+
+	rawFeatures := lnwire.NewRawFeatureVector()
+	if err := rawFeatures.Decode(r); err != nil {
+		return nil, err
+	}
+	p.Features = lnwire.NewFeatureVector(rawFeatures, lnwire.Features)
+
+	var err error
+	p.IntroductionNode, err = readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p.FirstEphemeralBlindingPoint, err = readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(r, b[:1]); err != nil {
+		return nil, err
+	}
+	numHops := int(b[0])
+	if numHops == 0 || numHops > maxNumHopsPerPath {
+		return nil, fmt.Errorf("blinded path must have between 1 and "+
+			"%d hops, got %d", maxNumHopsPerPath, numHops)
+	}
+
+	p.Hops = make([]*sphinx.BlindedHopInfo, 0, numHops)
+	for i := 0; i < numHops; i++ {
+		blindedNodePub, err := readPubKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := io.ReadFull(r, b[:2]); err != nil {
+			return nil, err
+		}
+		cipherTextLen := binary.BigEndian.Uint16(b[:2])
+		if cipherTextLen > maxCipherTextLength {
+			return nil, fmt.Errorf("encrypted data of %d bytes "+
+				"exceeds maximum of %d", cipherTextLen,
+				maxCipherTextLength)
+		}
+
+		cipherText := make([]byte, cipherTextLen)
+		if _, err := io.ReadFull(r, cipherText); err != nil {
+			return nil, err
+		}
+
+		p.Hops = append(p.Hops, &sphinx.BlindedHopInfo{
+			BlindedNodePub: blindedNodePub,
+			CipherText:     cipherText,
+		})
+	}
+
+	return &p, nil
+}
+
+// readPubKey reads a compressed public key from the passed reader.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var keyBytes [btcec.PubKeyBytesLenCompressed]byte
+	if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(keyBytes[:])
+}
+
+// parseBlindedPaymentPath converts the data (encoded in base32) into a
+// blinded payment path.
+func parseBlindedPaymentPath(data []byte) (*BlindedPaymentPath, error) {
+	base256Data, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBlindedPaymentPath(bytes.NewReader(base256Data))
+}
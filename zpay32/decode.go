@@ -290,6 +290,17 @@ func parseTaggedFields(invoice *Invoice, fields []byte, net *chaincfg.Params) er
 			}
 
 			invoice.RouteHints = append(invoice.RouteHints, routeHint)
+		case fieldTypeB:
+			// A `b` field can also be included multiple times, once
+			// for each blinded path to the destination.
+			blindedPath, err := parseBlindedPaymentPath(base32Data)
+			if err != nil {
+				return err
+			}
+
+			invoice.BlindedPaymentPaths = append(
+				invoice.BlindedPaymentPaths, blindedPath,
+			)
 		case fieldType9:
 			if invoice.Features != nil {
 				// We skip the field if we have already seen a
@@ -260,6 +260,26 @@ func writeTaggedFields(bufferBase32 *bytes.Buffer, invoice *Invoice) error {
 		}
 	}
 
+	for _, path := range invoice.BlindedPaymentPaths {
+		var pathBase256 bytes.Buffer
+		err := encodeBlindedPaymentPath(&pathBase256, path)
+		if err != nil {
+			return err
+		}
+
+		pathBase32, err := bech32.ConvertBits(
+			pathBase256.Bytes(), 8, 5, true,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = writeTaggedField(bufferBase32, fieldTypeB, pathBase32)
+		if err != nil {
+			return err
+		}
+	}
+
 	if invoice.Destination != nil {
 		// Convert 33 byte pubkey to 53 5-bit groups.
 		pubKeyBase32, err := bech32.ConvertBits(
@@ -76,6 +76,9 @@ const (
 	// probing the recipient.
 	fieldTypeS = 16
 
+	// fieldTypeB contains a blinded payment path to the target node.
+	fieldTypeB = 20
+
 	// maxInvoiceLength is the maximum total length an invoice can have.
 	// This is chosen to be the maximum number of bytes that can fit into a
 	// single QR code: https://en.wikipedia.org/wiki/QR_code#Storage
@@ -183,6 +186,13 @@ type Invoice struct {
 	// NOTE: This is optional.
 	RouteHints [][]HopHint
 
+	// BlindedPaymentPaths represents one or more blinded paths that can be
+	// used to reach the destination without learning its identity. An
+	// invoice can't carry both route hints and blinded paths.
+	//
+	// NOTE: This is optional.
+	BlindedPaymentPaths []*BlindedPaymentPath
+
 	// Features represents an optional field used to signal optional or
 	// required support for features by the receiver.
 	Features *lnwire.FeatureVector
@@ -385,5 +395,14 @@ func validateInvoice(invoice *Invoice) error {
 		return fmt.Errorf("missing feature vector")
 	}
 
+	// Route hints reveal the location of the destination, so they can't
+	// be combined with blinded paths.
+	if len(invoice.RouteHints) != 0 &&
+		len(invoice.BlindedPaymentPaths) != 0 {
+
+		return fmt.Errorf("cannot have both route hints and blinded " +
+			"payment paths")
+	}
+
 	return nil
 }
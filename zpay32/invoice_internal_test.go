@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	sphinx "github.com/ltcsuite/lightning-onion"
 	"github.com/ltcsuite/lnd/lnwire"
 	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/chaincfg"
@@ -799,3 +800,71 @@ func TestParseTaggedFields(t *testing.T) {
 		})
 	}
 }
+
+// TestBlindedPaymentPathEncoding tests that blinded payment paths survive an
+// encoding round trip, and that invoices can't carry both route hints and
+// blinded paths.
+func TestBlindedPaymentPathEncoding(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() *btcec.PublicKey {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return privKey.PubKey()
+	}
+
+	path := &BlindedPaymentPath{
+		FeeBaseMsat:     1000,
+		FeeRate:         10,
+		CltvExpiryDelta: 144,
+		HTLCMinMsat:     100,
+		HTLCMaxMsat:     1_000_000,
+		Features: lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(lnwire.MPPOptional),
+			lnwire.Features,
+		),
+		FirstEphemeralBlindingPoint: newKey(),
+		IntroductionNode:            newKey(),
+		Hops: []*sphinx.BlindedHopInfo{
+			{
+				BlindedNodePub: newKey(),
+				CipherText:     []byte{1, 2, 3},
+			},
+			{
+				BlindedNodePub: newKey(),
+				CipherText:     []byte{4, 5, 6, 7},
+			},
+		},
+	}
+
+	invoice, err := NewInvoice(
+		&chaincfg.MainNetParams, testPaymentHash, time.Unix(1, 0),
+		Description(testCupOfCoffee), BlindedPath(path),
+	)
+	require.NoError(t, err)
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	require.NoError(t, err)
+
+	decoded, err := Decode(encoded, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.Len(t, decoded.BlindedPaymentPaths, 1)
+	require.Equal(t, path, decoded.BlindedPaymentPaths[0])
+
+	// A path without any hops can't be encoded.
+	invoice.BlindedPaymentPaths[0] = &BlindedPaymentPath{
+		FirstEphemeralBlindingPoint: newKey(),
+		IntroductionNode:            newKey(),
+	}
+	_, err = invoice.Encode(testMessageSigner)
+	require.Error(t, err)
+
+	// Route hints and blinded paths are mutually exclusive.
+	_, err = NewInvoice(
+		&chaincfg.MainNetParams, testPaymentHash, time.Unix(1, 0),
+		Description(testCupOfCoffee), BlindedPath(path),
+		RouteHint(testSingleHop),
+	)
+	require.Error(t, err)
+}